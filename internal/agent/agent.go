@@ -85,6 +85,16 @@ type SessionAgentCall struct {
 	PresencePenalty  *float64
 	NonInteractive   bool
 	SubmittedAt      int64
+
+	// Model, when set, runs this call on the given model instead of the
+	// agent's large model. RoutingReason explains the choice and is
+	// recorded on the assistant messages of the turn.
+	Model         *Model
+	RoutingReason string
+
+	// verification marks a follow-up prompt queued by the verification
+	// pass, which continues the same user turn.
+	verification bool
 }
 
 type SessionAgent interface {
@@ -108,6 +118,9 @@ type Model struct {
 	CatwalkCfg catwalk.Model
 	ModelCfg   config.SelectedModel
 	FlatRate   bool
+	// XRUSH: ProviderType selects the reasoning adapter of the model's
+	// steps.
+	ProviderType catwalk.Type
 }
 
 type sessionAgent struct {
@@ -131,6 +144,16 @@ type sessionAgent struct {
 
 	extHost *ext.ExtensionHost // XRUSH: extension host
 	hooks   agentHookMediator  // XRUSH: hook mediator for extension lifecycle
+
+	contextAttribution bool   // XRUSH: context attribution
+	workingDir         string // XRUSH: context attribution
+
+	verifier     *Verifier                       // XRUSH: self-verification pass
+	verifyStates *csync.Map[string, verifyState] // XRUSH: self-verification pass
+
+	sampler *Sampler // XRUSH: multi-candidate sampling
+
+	reasoning *config.ReasoningOptions // XRUSH: reasoning capture and storage
 }
 
 type SessionAgentOptions struct {
@@ -147,6 +170,22 @@ type SessionAgentOptions struct {
 	Notify               pubsub.Publisher[notify.Notification]
 	StreamTimeout        time.Duration
 	ExtHost              *ext.ExtensionHost // XRUSH: extension host option
+
+	// XRUSH: annotate replies with their context sources; WorkingDir
+	// resolves the files they cite.
+	ContextAttribution bool
+	WorkingDir         string
+
+	// XRUSH: checks run before a turn that edited files completes; nil
+	// disables the verification pass.
+	Verifier *Verifier
+
+	// XRUSH: samples extra final answers on high-stakes turns; nil
+	// disables sampling.
+	Sampler *Sampler
+
+	// XRUSH: reasoning capture and storage; nil uses the defaults.
+	Reasoning *config.ReasoningOptions
 }
 
 func NewSessionAgent(
@@ -167,6 +206,12 @@ func NewSessionAgent(
 		streamTimeout:        opts.StreamTimeout,
 		extHost:              opts.ExtHost,                          // XRUSH: extension host
 		hooks:                agentHookMediator{host: opts.ExtHost}, // XRUSH: hook mediator init
+		contextAttribution:   opts.ContextAttribution,               // XRUSH: context attribution
+		workingDir:           opts.WorkingDir,                       // XRUSH: context attribution
+		verifier:             opts.Verifier,                         // XRUSH: self-verification pass
+		verifyStates:         csync.NewMap[string, verifyState](),
+		sampler:              opts.Sampler,   // XRUSH: multi-candidate sampling
+		reasoning:            opts.Reasoning, // XRUSH: reasoning capture and storage
 		messageQueue:         csync.NewMap[string, []SessionAgentCall](),
 		activeRequests:       csync.NewMap[string, context.CancelFunc](),
 	}
@@ -194,6 +239,10 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 	// Copy mutable fields under lock to avoid races with SetTools/SetModels.
 	agentTools := a.tools.Copy()
 	largeModel := a.largeModel.Get()
+	if call.Model != nil && call.Model.Model != nil {
+		// XRUSH: the coordinator routed this turn to another model.
+		largeModel = *call.Model
+	}
 	systemPrompt := a.systemPrompt.Get()
 	promptPrefix := a.systemPromptPrefix.Get()
 	var instructions strings.Builder
@@ -237,7 +286,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 	var wg sync.WaitGroup
 	// Generate title if first message.
-	if len(msgs) == 0 {
+	if len(msgs) == 0 || len(msgs) == 1 && isTemplatePreamble(msgs[0]) {
 		titleCtx := ctx // Copy to avoid race with ctx reassignment below.
 		wg.Go(func() {
 			a.generateTitle(titleCtx, call.SessionID, call.Prompt)
@@ -302,6 +351,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 	startTime := time.Now()
 	sentToLLMAt := startTime.Unix()
+	defer a.stripTurnReasoning(context.WithoutCancel(ctx), call.SessionID, startTime) // XRUSH: reasoning storage
 	a.eventPromptSent(call.SessionID)
 
 	if a.hooks.host != nil {
@@ -311,6 +361,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 	a.hooks.invokeRunStart(ctx, call.SessionID, call.Prompt)
 
 	var currentAssistant *message.Message
+	var inlineReasoning reasoningAdapter // XRUSH: inline reasoning of the current step
 	var stepMessages []fantasy.Message
 	var firstTokenAt int64
 	var firstTokenOnce sync.Once
@@ -402,10 +453,11 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 			var assistantMsg message.Message
 			assistantMsg, err = a.messages.Create(callContext, call.SessionID, message.CreateMessageParams{
-				Role:     message.Assistant,
-				Parts:    []message.ContentPart{},
-				Model:    routedModel.ModelCfg.Model,
-				Provider: routedModel.ModelCfg.Provider,
+				Role:          message.Assistant,
+				Parts:         []message.ContentPart{},
+				Model:         routedModel.ModelCfg.Model,
+				Provider:      routedModel.ModelCfg.Provider,
+				RoutingReason: call.RoutingReason,
 			})
 			if err != nil {
 				return callContext, prepared, err
@@ -414,6 +466,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 			callContext = context.WithValue(callContext, tools.SupportsImagesContextKey, routedModel.CatwalkCfg.SupportsImages)
 			callContext = context.WithValue(callContext, tools.ModelNameContextKey, routedModel.CatwalkCfg.Name)
 			currentAssistant = &assistantMsg
+			inlineReasoning = newReasoningAdapter(routedModel.ProviderType, a.reasoning.Tags())
 			return callContext, prepared, err
 		},
 		OnReasoningStart: func(id string, reasoning fantasy.ReasoningContent) error {
@@ -450,6 +503,15 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 		OnTextDelta: func(id string, text string) error {
 			resetIdle()
 			setFirstToken()
+			// XRUSH: reasoning the model wrote inline in the reply.
+			if inlineReasoning != nil {
+				var reasoning string
+				reasoning, text = inlineReasoning.Text(text)
+				appendInlineReasoning(currentAssistant, reasoning, inlineReasoning.Thinking())
+				if text == "" {
+					return a.messages.Update(genCtx, *currentAssistant)
+				}
+			}
 			if len(currentAssistant.Parts) == 0 {
 				text = strings.TrimPrefix(text, "\n")
 			}
@@ -457,6 +519,17 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 			currentAssistant.AppendContent(text)
 			return a.messages.Update(genCtx, *currentAssistant)
 		},
+		OnTextEnd: func(id string) error {
+			if inlineReasoning == nil {
+				return nil
+			}
+			reasoning, text := inlineReasoning.Flush()
+			appendInlineReasoning(currentAssistant, reasoning, false)
+			if text != "" {
+				currentAssistant.AppendContent(text)
+			}
+			return a.messages.Update(genCtx, *currentAssistant)
+		},
 		OnToolInputStart: func(id string, toolName string) error {
 			resetIdle()
 			toolCall := message.ToolCall{
@@ -486,12 +559,14 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 				Finished:         true,
 			}
 			currentAssistant.AddToolCall(toolCall)
+			a.publishToolEvent(call.SessionID, notify.TypeToolStarted, tc.ToolCallID, tc.ToolName, false)
 			// Use parent ctx instead of genCtx to ensure the update succeeds
 			// even if the request is canceled mid-stream
 			return a.messages.Update(ctx, *currentAssistant)
 		},
 		OnToolResult: func(result fantasy.ToolResultContent) error {
 			toolResult := a.convertToToolResult(result)
+			a.publishToolEvent(call.SessionID, notify.TypeToolFinished, toolResult.ToolCallID, toolResult.Name, toolResult.IsError)
 			// Use parent ctx instead of genCtx to ensure the message is created
 			// even if the request is canceled mid-stream
 			_, createMsgErr := a.messages.Create(ctx, currentAssistant.SessionID, message.CreateMessageParams{
@@ -542,12 +617,23 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 				return getSessionErr
 			}
 			usage, estimated := fallbackStepUsage(stepMessages, stepResult)
-			a.updateSessionUsage(largeModel, &updatedSession, usage, a.openrouterCost(stepResult.ProviderMetadata), estimated)
+			stepCost := a.updateSessionUsage(largeModel, &updatedSession, usage, a.openrouterCost(stepResult.ProviderMetadata), estimated)
+			if !estimated {
+				currentAssistant.SetUsage(usage.InputTokens+usage.CacheReadTokens, usage.OutputTokens, stepCost)
+			}
+			// XRUSH: reasoning tokens are counted apart from the reply.
+			if thinking := currentAssistant.ReasoningContent().Thinking; thinking != "" || usage.ReasoningTokens > 0 {
+				currentAssistant.SetReasoningTokens(reasoningTokens(usage.ReasoningTokens, thinking))
+			}
 			_, sessionErr := a.sessions.Save(ctx, updatedSession)
 			if sessionErr != nil {
 				return sessionErr
 			}
 			currentSession = updatedSession
+			// XRUSH: context attribution
+			if reply := currentAssistant.Content().Text; a.contextAttribution && reply != "" {
+				currentAssistant.SetContextAttribution(attributeContext(a.workingDir, stepMessages, reply))
+			}
 			a.hooks.invokeStepFinish(genCtx, call.SessionID, stepResult) // XRUSH: hook lifecycle - step finish
 			return a.messages.Update(genCtx, *currentAssistant)
 		},
@@ -594,6 +680,12 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 	a.hooks.invokeRunEnd(ctx, call.SessionID, result, err) // XRUSH: hook lifecycle - run end
 
+	// XRUSH: multi-candidate sampling of the turn's final answer.
+	if err == nil && !shouldSummarize && a.sampler != nil && pendingFinishReason == message.FinishReasonEndTurn && currentAssistant != nil {
+		stopIdle()
+		a.sampleTurn(genCtx, call, SamplerModel{Model: largeModel, ProviderOptions: call.ProviderOptions}, stepMessages, agentTools, currentAssistant)
+	}
+
 	if pendingFinishReason != "" && currentAssistant != nil {
 		currentAssistant.AddFinish(pendingFinishReason, "", "")
 		currentAssistant.CompletedAt = time.Now().Unix()
@@ -726,6 +818,10 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 		}
 	}
 
+	// XRUSH: self-verification pass. A queued follow-up continues the
+	// turn, so it is not reported finished yet.
+	verifying := !shouldSummarize && !ext.StoppedByCondition(ctx) && a.verifyTurn(genCtx, call, startTime)
+
 	// Release active request before publishing the notification.
 	// TUI handlers poll IsSessionBusy() and only re-evaluate when a
 	// tea.Msg arrives, so the cleanup must precede the notify or
@@ -735,11 +831,12 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 	// Send notification that agent has finished its turn (skip for
 	// nested/non-interactive sessions).
-	if !call.NonInteractive && a.notify != nil {
+	if !call.NonInteractive && a.notify != nil && !verifying {
 		a.notify.Publish(pubsub.CreatedEvent, notify.Notification{
 			SessionID:    call.SessionID,
 			SessionTitle: currentSession.Title,
 			Type:         notify.TypeAgentFinished,
+			Duration:     time.Since(startTime),
 		})
 	}
 
@@ -850,12 +947,6 @@ func (a *sessionAgent) Summarize(ctx context.Context, sessionID string, opts fan
 		return err
 	}
 
-	summaryMessage.AddFinish(message.FinishReasonEndTurn, "", "")
-	err = a.messages.Update(genCtx, summaryMessage)
-	if err != nil {
-		return err
-	}
-
 	var openrouterCost *float64
 	for _, step := range resp.Steps {
 		stepCost := a.openrouterCost(step.ProviderMetadata)
@@ -868,7 +959,13 @@ func (a *sessionAgent) Summarize(ctx context.Context, sessionID string, opts fan
 		}
 	}
 
-	a.updateSessionUsage(largeModel, &currentSession, resp.TotalUsage, openrouterCost, false)
+	summaryCost := a.updateSessionUsage(largeModel, &currentSession, resp.TotalUsage, openrouterCost, false)
+	summaryMessage.SetUsage(resp.TotalUsage.InputTokens+resp.TotalUsage.CacheReadTokens, resp.TotalUsage.OutputTokens, summaryCost)
+	summaryMessage.AddFinish(message.FinishReasonEndTurn, "", "")
+	err = a.messages.Update(genCtx, summaryMessage)
+	if err != nil {
+		return err
+	}
 
 	// Just in case, get just the last usage info.
 	usage := resp.Response.Usage
@@ -1106,13 +1203,26 @@ func (a *sessionAgent) getSessionMessages(ctx context.Context, session session.S
 			}
 		}
 		if summaryMsgIndex != -1 {
+			// XRUSH: the notes of a session started from a template stay
+			// in context once it is summarized.
+			var pinned []message.Message
+			if summaryMsgIndex > 0 && isTemplatePreamble(msgs[0]) {
+				pinned = append(pinned, msgs[0])
+			}
 			msgs = msgs[summaryMsgIndex:]
 			msgs[0].Role = message.User
+			msgs = append(pinned, msgs...)
 		}
 	}
 	return msgs, nil
 }
 
+// isTemplatePreamble reports whether msg holds the notes and working set
+// of the template the session was started from.
+func isTemplatePreamble(msg message.Message) bool {
+	return msg.Role == message.User && config.IsPreamble(msg.Content().Text)
+}
+
 // generateTitle generates a session titled based on the initial prompt.
 func (a *sessionAgent) generateTitle(ctx context.Context, sessionID string, userPrompt string) {
 	if userPrompt == "" {
@@ -1253,7 +1363,9 @@ func (a *sessionAgent) openrouterCost(metadata fantasy.ProviderMetadata) *float6
 	return &opts.Usage.Cost
 }
 
-func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session, usage fantasy.Usage, overrideCost *float64, estimated bool) {
+// updateSessionUsage accumulates usage into session and returns the cost
+// charged for it, which is zero for estimated usage and flat-rate models.
+func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session, usage fantasy.Usage, overrideCost *float64, estimated bool) float64 {
 	if !usageIsZero(usage) {
 		session.EstimatedUsage = estimated
 	}
@@ -1284,6 +1396,7 @@ func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session,
 
 	session.Cost += cost
 	updateSessionTokenCounters(session, usage)
+	return cost
 }
 
 func updateSessionTokenCounters(session *session.Session, usage fantasy.Usage) {
@@ -1322,6 +1435,7 @@ func (a *sessionAgent) Cancel(sessionID string) {
 		slog.Debug("Clearing queued prompts", "session_id", sessionID)
 		a.messageQueue.Del(sessionID)
 	}
+	a.verifyStates.Del(sessionID) // XRUSH: self-verification pass
 }
 
 func (a *sessionAgent) ClearQueue(sessionID string) {
@@ -1403,6 +1517,21 @@ func (a *sessionAgent) Model() Model {
 	return a.largeModel.Get()
 }
 
+// publishToolEvent emits a tool lifecycle notification. Tool events are
+// high-frequency and purely observational, so they use lossy delivery.
+func (a *sessionAgent) publishToolEvent(sessionID string, typ notify.Type, toolCallID, toolName string, isError bool) {
+	if a.notify == nil {
+		return
+	}
+	a.notify.Publish(pubsub.CreatedEvent, notify.Notification{
+		SessionID:  sessionID,
+		Type:       typ,
+		ToolCallID: toolCallID,
+		ToolName:   toolName,
+		IsError:    isError,
+	})
+}
+
 // convertToToolResult converts a fantasy tool result to a message tool result.
 func (a *sessionAgent) convertToToolResult(result fantasy.ToolResultContent) message.ToolResult {
 	baseResult := message.ToolResult{
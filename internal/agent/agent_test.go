@@ -870,3 +870,43 @@ func TestProviderRetryLogFields(t *testing.T) {
 		}, fields)
 	})
 }
+
+func TestGetSessionMessages_KeepsTemplatePreamble(t *testing.T) {
+	env := testEnv(t)
+	agent := testSessionAgent(env, nil, nil, "test prompt").(*sessionAgent)
+
+	ctx := t.Context()
+	sess, err := env.sessions.Create(ctx, "test")
+	require.NoError(t, err)
+
+	create := func(role message.MessageRole, text string, summary bool) message.Message {
+		msg, err := env.messages.Create(ctx, sess.ID, message.CreateMessageParams{
+			Role:             role,
+			Parts:            []message.ContentPart{message.TextContent{Text: text}},
+			IsSummaryMessage: summary,
+		})
+		require.NoError(t, err)
+		return msg
+	}
+	preamble := create(message.User, "<pinned_notes>\n- Reproduce before fixing\n</pinned_notes>\n", false)
+
+	// A session holding only the preamble has not been prompted yet.
+	msgs, err := agent.getSessionMessages(ctx, sess)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.True(t, isTemplatePreamble(msgs[0]))
+
+	create(message.User, "fix the health check", false)
+	create(message.Assistant, "done", false)
+	summary := create(message.Assistant, "summary of the fix", true)
+	sess.SummaryMessageID = summary.ID
+
+	msgs, err = agent.getSessionMessages(ctx, sess)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	require.Equal(t, preamble.ID, msgs[0].ID)
+	require.Equal(t, summary.ID, msgs[1].ID)
+	require.Equal(t, message.User, msgs[1].Role)
+
+	require.False(t, isTemplatePreamble(msgs[1]))
+}
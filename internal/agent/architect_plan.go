@@ -40,6 +40,8 @@ type ArchitectPlan struct {
 	Steps []PlanStep `json:"steps"`
 	// Rationale explains why this plan was chosen over alternatives.
 	Rationale string `json:"rationale"`
+	// Risks lists what could go wrong while executing the plan.
+	Risks []string `json:"risks,omitempty"`
 	// ApprovalRequired indicates whether the plan needs explicit user
 	// approval before execution proceeds.
 	ApprovalRequired bool `json:"approval_required"`
@@ -129,6 +131,12 @@ func (p ArchitectPlan) String() string {
 			fmt.Fprintf(&b, " (files: %s)", strings.Join(step.TargetFiles, ", "))
 		}
 	}
+	if len(p.Risks) > 0 {
+		b.WriteString("\nRisks:")
+		for _, risk := range p.Risks {
+			fmt.Fprintf(&b, "\n  - %s", risk)
+		}
+	}
 	return b.String()
 }
 
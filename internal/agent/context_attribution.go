@@ -0,0 +1,235 @@
+package agent
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/message"
+)
+
+// maxAttributedCitations caps the citations checked per reply.
+const maxAttributedCitations = 20
+
+var (
+	// contextBlockPattern matches the named blocks prompt assembly adds to
+	// the system prompt.
+	contextBlockPattern = regexp.MustCompile(`<context name="([^"]+)">`)
+	// summaryIDPattern matches the header of LCM summary messages.
+	summaryIDPattern = regexp.MustCompile(`\[Summary ID: ([^\]\s]+)\]`)
+	// fileTagPattern matches files inlined into the prompt: memory files,
+	// attachments and batch view results.
+	fileTagPattern = regexp.MustCompile(`<file path=['"]([^'"]+)['"]>`)
+	// urlPattern matches URLs, which are removed before looking for cited
+	// files.
+	urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://\S+`)
+	// citedPathPattern matches file references such as internal/app/app.go,
+	// ./main.go or README.md:12.
+	citedPathPattern = regexp.MustCompile(`(?:^|[\s(\[{"'` + "`" + `])((?:\.{1,2}/|/)?(?:[\w.@+-]+/)*[\w@+-][\w.@+-]*\.([A-Za-z][A-Za-z0-9]{0,9}))(?::\d+(?:[:-]\d+)?)?`)
+)
+
+// contentTools are the tools whose file_path argument puts the file content
+// into context.
+var contentTools = []string{
+	tools.ViewToolName,
+	tools.EditToolName,
+	tools.MultiEditToolName,
+	tools.WriteToolName,
+	tools.BatchEditToolName,
+}
+
+// citableExtensions are the extensions a bare file name needs to count as a
+// citation; without one, identifiers like fmt.Println would match.
+var citableExtensions = map[string]bool{
+	"c": true, "cc": true, "cfg": true, "conf": true, "cpp": true, "cs": true, "css": true,
+	"dart": true, "env": true, "ex": true, "exs": true, "go": true, "gradle": true, "h": true,
+	"hpp": true, "hs": true, "html": true, "ini": true, "java": true, "js": true, "json": true,
+	"jsonl": true, "jsx": true, "kt": true, "lock": true, "lua": true, "md": true, "mdx": true,
+	"mod": true, "php": true, "proto": true, "py": true, "rb": true, "rs": true, "scala": true,
+	"scss": true, "sh": true, "sql": true, "sum": true, "svelte": true, "swift": true, "tf": true,
+	"toml": true, "tpl": true, "ts": true, "tsx": true, "txt": true, "vue": true, "xml": true,
+	"yaml": true, "yml": true, "zig": true,
+}
+
+// contextInventory is what the prompt of a step contained.
+type contextInventory struct {
+	workingDir string
+	sources    []message.ContextSource
+	seen       map[message.ContextSource]bool
+	// files holds the working-directory relative paths of files whose
+	// content was in context.
+	files map[string]bool
+	// text is the prompt text, used to tell whether a file was at least
+	// named.
+	text strings.Builder
+}
+
+// attributeContext lists the context sources of a step's prompt and checks
+// the files reply cites against it.
+func attributeContext(workingDir string, prompt []fantasy.Message, reply string) message.ContextAttribution {
+	inv := collectContextInventory(workingDir, prompt)
+	attribution := message.ContextAttribution{Sources: inv.sources}
+	text := inv.text.String()
+	for _, cited := range citedFiles(reply) {
+		path := relativePath(workingDir, cited)
+		citation := message.FileCitation{Path: path, Status: message.CitationAbsent}
+		if match, ok := inv.lookup(path); ok {
+			citation.Status = message.CitationRead
+			path = match
+		} else if strings.Contains(text, path) || strings.Contains(text, cited) {
+			citation.Status = message.CitationMentioned
+		}
+		// A bare name may refer to a file in any directory, so only paths
+		// can be reported missing.
+		if _, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(path))); err != nil && strings.Contains(path, "/") {
+			citation.Missing = true
+		}
+		attribution.Citations = append(attribution.Citations, citation)
+	}
+	return attribution
+}
+
+func collectContextInventory(workingDir string, prompt []fantasy.Message) *contextInventory {
+	inv := &contextInventory{
+		workingDir: workingDir,
+		seen:       make(map[message.ContextSource]bool),
+		files:      make(map[string]bool),
+	}
+	for _, msg := range prompt {
+		for _, part := range msg.Content {
+			switch p := part.(type) {
+			case fantasy.TextPart:
+				inv.scanText(msg.Role, p.Text)
+			case fantasy.ToolCallPart:
+				if slices.Contains(contentTools, p.ToolName) {
+					for _, path := range toolCallPaths(p.Input) {
+						inv.addFile(message.ContextSourceFile, path)
+					}
+				}
+			case fantasy.ToolResultPart:
+				if out, ok := p.Output.(fantasy.ToolResultOutputContentText); ok {
+					inv.scanText(msg.Role, out.Text)
+				}
+			case fantasy.FilePart:
+				if p.Filename != "" {
+					inv.addFile(message.ContextSourceFile, p.Filename)
+				}
+			}
+		}
+	}
+	return inv
+}
+
+// scanText records the sources found in a prompt text.
+func (inv *contextInventory) scanText(role fantasy.MessageRole, text string) {
+	inv.text.WriteString(text)
+	inv.text.WriteByte('\n')
+
+	for _, m := range contextBlockPattern.FindAllStringSubmatch(text, -1) {
+		switch m[1] {
+		case "repo-map":
+			inv.add(message.ContextSourceRepoMap, "")
+		case "observations":
+			inv.add(message.ContextSourceObservations, "")
+		default:
+			inv.add(message.ContextSourceContextFile, m[1])
+		}
+	}
+	for _, m := range summaryIDPattern.FindAllStringSubmatch(text, -1) {
+		inv.add(message.ContextSourceSummary, m[1])
+	}
+	if strings.Contains(text, "<pinned_notes>") {
+		inv.add(message.ContextSourcePinnedNotes, "")
+	}
+	// Files in the system prompt are memory files such as AGENTS.md.
+	kind := message.ContextSourceFile
+	if role == fantasy.MessageRoleSystem {
+		kind = message.ContextSourceContextFile
+	}
+	for _, m := range fileTagPattern.FindAllStringSubmatch(text, -1) {
+		inv.addFile(kind, m[1])
+	}
+}
+
+func (inv *contextInventory) add(kind message.ContextSourceKind, name string) {
+	src := message.ContextSource{Kind: kind, Name: name}
+	if !inv.seen[src] {
+		inv.seen[src] = true
+		inv.sources = append(inv.sources, src)
+	}
+}
+
+// addFile records a file whose content was in context.
+func (inv *contextInventory) addFile(kind message.ContextSourceKind, path string) {
+	path = relativePath(inv.workingDir, path)
+	inv.files[path] = true
+	inv.add(kind, path)
+}
+
+// lookup finds a file whose content was in context. A bare or partial
+// path matches a context file with the same trailing path elements.
+func (inv *contextInventory) lookup(path string) (string, bool) {
+	if inv.files[path] {
+		return path, true
+	}
+	for _, file := range slices.Sorted(maps.Keys(inv.files)) {
+		if strings.HasSuffix(file, "/"+path) {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+// toolCallPaths returns the file paths in a tool call input.
+func toolCallPaths(input string) []string {
+	var args struct {
+		FilePath  string   `json:"file_path"`
+		FilePaths []string `json:"file_paths"`
+	}
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		return nil
+	}
+	paths := args.FilePaths
+	if args.FilePath != "" {
+		paths = append(paths, args.FilePath)
+	}
+	return paths
+}
+
+// citedFiles returns the distinct file paths a reply refers to, in order
+// of first appearance.
+func citedFiles(reply string) []string {
+	reply = urlPattern.ReplaceAllString(reply, " ")
+	var cited []string
+	for _, m := range citedPathPattern.FindAllStringSubmatch(reply, -1) {
+		path, ext := m[1], strings.ToLower(m[2])
+		if !citableExtensions[ext] {
+			continue
+		}
+		if !slices.Contains(cited, path) {
+			cited = append(cited, path)
+		}
+		if len(cited) == maxAttributedCitations {
+			break
+		}
+	}
+	return cited
+}
+
+// relativePath returns path relative to workingDir in slash form when it
+// lies inside it, and the cleaned path otherwise.
+func relativePath(workingDir, path string) string {
+	path = filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(workingDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCitedFiles(t *testing.T) {
+	t.Parallel()
+
+	reply := "The handler lives in `internal/app/app.go:42` and calls ./main.go. " +
+		"Use fmt.Println, see https://example.com/docs/index.html and README.md. " +
+		"Version v1.2 changed internal/app/app.go again."
+	require.Equal(t, []string{"internal/app/app.go", "./main.go", "README.md"}, citedFiles(reply))
+}
+
+func TestAttributeContext(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"main.go", "README.md", filepath.Join("internal", "app", "app.go")} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o644))
+	}
+
+	prompt := []fantasy.Message{
+		fantasy.NewSystemMessage("You are a coder.\n<memory>\n<file path=\"AGENTS.md\">\nrules\n</file>\n</memory>\n\n" +
+			"<context name=\"repo-map\">\ninternal/app/app.go:\n  func New\nREADME.md\n</context>\n"),
+		fantasy.NewUserMessage("[Summary ID: sum_1]\nEarlier we discussed the build."),
+		fantasy.NewUserMessage("<pinned_notes>\n- prefer small diffs\n</pinned_notes>\nFix the bug"),
+		{
+			Role: fantasy.MessageRoleAssistant,
+			Content: []fantasy.MessagePart{
+				fantasy.ToolCallPart{ToolCallID: "1", ToolName: "view", Input: `{"file_path":"` + filepath.ToSlash(filepath.Join(dir, "main.go")) + `"}`},
+			},
+		},
+	}
+	reply := "I changed main.go, which is wired from app.go (see README.md), and removed util/helpers.go."
+
+	got := attributeContext(dir, prompt, reply)
+	require.Equal(t, []message.ContextSource{
+		{Kind: message.ContextSourceRepoMap},
+		{Kind: message.ContextSourceContextFile, Name: "AGENTS.md"},
+		{Kind: message.ContextSourceSummary, Name: "sum_1"},
+		{Kind: message.ContextSourcePinnedNotes},
+		{Kind: message.ContextSourceFile, Name: "main.go"},
+	}, got.Sources)
+	require.Equal(t, []message.FileCitation{
+		{Path: "main.go", Status: message.CitationRead},
+		{Path: "app.go", Status: message.CitationMentioned},
+		{Path: "README.md", Status: message.CitationMentioned},
+		{Path: "util/helpers.go", Status: message.CitationAbsent, Missing: true},
+	}, got.Citations)
+}
@@ -23,7 +23,9 @@ import (
 	"github.com/charmbracelet/crush/internal/agent/notify"
 	"github.com/charmbracelet/crush/internal/agent/prompt"
 	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/csync"
 	"github.com/charmbracelet/crush/internal/event"
 	"github.com/charmbracelet/crush/internal/ext" // XRUSH: extension host import
 	"github.com/charmbracelet/crush/internal/filetracker"
@@ -37,6 +39,7 @@ import (
 	"github.com/charmbracelet/crush/internal/pubsub"
 	"github.com/charmbracelet/crush/internal/session"
 	"github.com/charmbracelet/crush/internal/skills"
+	"github.com/charmbracelet/crush/internal/staging"
 	"golang.org/x/sync/errgroup"
 
 	"charm.land/fantasy/providers/anthropic"
@@ -112,6 +115,14 @@ type Coordinator interface {
 	StructuredSubagentFactory() StructuredSubagentFactory
 	// ResolveLCMModel resolves a LanguageModel for LCM summarization.
 	ResolveLCMModel(ctx context.Context, selected config.SelectedModel, providerCfg config.ProviderConfig) (Model, error)
+	// AutoDowngradeEnabled reports whether trivial turns of the session
+	// are routed to the small model.
+	AutoDowngradeEnabled(sessionID string) bool
+	// SetAutoDowngrade overrides the auto_downgrade option for the session.
+	SetAutoDowngrade(sessionID string, enabled bool)
+	// EstimateTurn returns the input tokens by component and the projected
+	// cost of sending prompt to the session, without sending it.
+	EstimateTurn(ctx context.Context, sessionID, prompt string, attachments ...message.Attachment) (TurnEstimate, error)
 }
 
 type coordinator struct {
@@ -158,6 +169,28 @@ type coordinator struct {
 	// the coordinator uses the single-model path (backward compatible).
 	tieredProvider *TieredModelProvider
 
+	// plans tracks architect plans awaiting approval or being executed
+	// per session.
+	plans *planTracker
+
+	// downgrades holds per-session overrides of automatic downgrades to
+	// smallModel, the small model cached by UpdateModels.
+	downgrades *downgradeTracker
+	smallModel *csync.Value[Model]
+
+	// compressor rewrites static prompt text when compress_system_prompt
+	// is set; coderPrompt is rebuilt once rewrites are available.
+	compressor  *promptCompressor
+	coderPrompt *prompt.Prompt
+
+	// staging receives file edits for review when Options.ReviewEdits is
+	// set. When nil, edits are always written directly.
+	staging staging.Service
+
+	// locks guards sessions against concurrent runs from other crush
+	// processes. When nil, runs are not locked.
+	locks *session.Locks
+
 	readyWg errgroup.Group
 }
 
@@ -207,6 +240,10 @@ func NewCoordinator(
 		skillTracker:   skillTracker,
 		extHost:        extHost,
 		rateLimitCoord: NewRateLimitCoordinator(),
+		plans:          newPlanTracker(),
+		downgrades:     newDowngradeTracker(),
+		smallModel:     csync.NewValue(Model{}),
+		compressor:     newPromptCompressor(filepath.Join(cfg.Config().Options.DataDirectory, promptCompressionFile)),
 	}
 
 	if extHost != nil {
@@ -238,10 +275,14 @@ func NewCoordinator(
 	}
 
 	// TODO: make this dynamic when we support multiple agents
-	prompt, err := coderPrompt(prompt.WithWorkingDir(c.cfg.WorkingDir()))
+	prompt, err := coderPrompt(
+		prompt.WithWorkingDir(c.cfg.WorkingDir()),
+		prompt.WithStaticRewriter(c.rewriteStaticSection), // XRUSH: system prompt compression
+	)
 	if err != nil {
 		return nil, err
 	}
+	c.coderPrompt = prompt
 
 	agent, err := c.buildAgent(ctx, prompt, agentCfg, false)
 	if err != nil {
@@ -258,16 +299,48 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 		return nil, err
 	}
 
+	// XRUSH: hold the session lock for the turn so another crush process
+	// sharing the data directory cannot write to the session meanwhile.
+	lock, err := c.locks.Acquire(ctx, sessionID, session.TakeoverRequested(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	result, err := c.run(ctx, sessionID, prompt, attachments...)
+	if err != nil && lock.Lost() {
+		return result, session.ErrSessionTakenOver
+	}
+	return result, err
+}
+
+func (c *coordinator) run(ctx context.Context, sessionID string, prompt string, attachments ...message.Attachment) (*fantasy.AgentResult, error) {
+	// XRUSH: connect MCP servers deferred by lazy_mcp so their tools are
+	// in the tool list built below.
+	mcp.StartPending(ctx, c.cfg)
+
 	// refresh models before each run
 	if err := c.UpdateModels(ctx); err != nil {
 		return nil, fmt.Errorf("failed to update models: %w", err)
 	}
+	c.startPromptCompression() // XRUSH: system prompt compression
+
+	// A plan awaiting approval consumes the next prompt as the reply.
+	if ps, ok := c.plans.Pending(sessionID); ok {
+		return c.handlePlanReply(ctx, sessionID, ps, prompt, attachments...)
+	}
 
 	// Two-phase architect→editor routing for feature tasks.
 	if c.shouldUseTwoPhase(prompt) {
 		return c.executeWithArchitectEditor(ctx, sessionID, prompt, attachments...)
 	}
 
+	return c.runDirect(ctx, sessionID, prompt, attachments...)
+}
+
+// runDirect runs the prompt on the current agent, without planning it
+// first.
+func (c *coordinator) runDirect(ctx context.Context, sessionID string, prompt string, attachments ...message.Attachment) (*fantasy.AgentResult, error) {
 	model := c.currentAgent.Model()
 
 	// Tiered model resolution: uses TieredModelProvider when configured,
@@ -279,6 +352,14 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 		}
 	}
 
+	// XRUSH: trivial turns run on the small model when auto_downgrade is on.
+	runAgent := c.currentAgent
+	if small, reason, ok := c.downgradeTurn(sessionID, prompt, attachments, model); ok {
+		slog.Debug("Downgrading turn to the small model", "session_id", sessionID, "model", small.ModelCfg.Model, "reason", reason)
+		model = small
+		runAgent = routedAgent{SessionAgent: c.currentAgent, model: small, reason: reason}
+	}
+
 	maxTokens := model.CatwalkCfg.DefaultMaxTokens
 	if model.ModelCfg.MaxTokens != 0 {
 		maxTokens = model.ModelCfg.MaxTokens
@@ -306,14 +387,14 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 	}
 
 	beforeLoaded := c.skillTracker.LoadedNames()
-	result, originalErr := c.runWithFallback(ctx, c.currentAgent, model, providerCfg, sessionID, prompt, attachments, mergedOptions, maxTokens, temp, topP, topK, freqPenalty, presPenalty)
+	result, originalErr := c.runWithFallback(ctx, runAgent, model, providerCfg, sessionID, prompt, attachments, mergedOptions, maxTokens, temp, topP, topK, freqPenalty, presPenalty)
 	logTurnSkillUsage(sessionID, prompt, c.activeSkills, c.skillTracker, beforeLoaded)
 
 	c.recordCostFromResult(model, result, originalErr)
 
 	if c.isUnauthorized(originalErr) {
 		if err := c.retryAfterUnauthorized(ctx, providerCfg); err == nil {
-			retryResult, retryErr := c.runWithFallback(ctx, c.currentAgent, model, providerCfg, sessionID, prompt, attachments, mergedOptions, maxTokens, temp, topP, topK, freqPenalty, presPenalty)
+			retryResult, retryErr := c.runWithFallback(ctx, runAgent, model, providerCfg, sessionID, prompt, attachments, mergedOptions, maxTokens, temp, topP, topK, freqPenalty, presPenalty)
 			c.recordCostFromResult(model, retryResult, retryErr)
 			return retryResult, retryErr
 		}
@@ -421,15 +502,37 @@ func (c *coordinator) shouldUseTwoPhase(prompt string) bool {
 // executeWithArchitectEditor runs the two-phase architect→editor flow.
 // Phase 1 uses the ArchitectModel to produce a structured plan. Phase 2
 // feeds that plan to the current agent (using EditorModel or large model)
-// for execution.
+// for execution. When the approval gate blocks the plan it is proposed to
+// the user instead, and execution waits for their reply.
 func (c *coordinator) executeWithArchitectEditor(
 	ctx context.Context,
 	sessionID string,
 	prompt string,
 	attachments ...message.Attachment,
+) (*fantasy.AgentResult, error) {
+	return c.runArchitectPhase(ctx, sessionID, prompt, prompt, false, attachments...)
+}
+
+// runArchitectPhase produces a plan for task by sending archPrompt to the
+// architect model, then either proposes it for approval or executes it.
+// forceApproval is set when re-planning from user feedback so the revised
+// plan is always reviewed again. When no plan can be produced, the task
+// itself runs on the current agent and any plan being revised is dropped.
+func (c *coordinator) runArchitectPhase(
+	ctx context.Context,
+	sessionID string,
+	task string,
+	prompt string,
+	forceApproval bool,
+	attachments ...message.Attachment,
 ) (*fantasy.AgentResult, error) {
 	cfg := c.cfg.Config()
 
+	fallback := func() (*fantasy.AgentResult, error) {
+		c.plans.Drop(sessionID)
+		return c.runDirect(ctx, sessionID, task, attachments...)
+	}
+
 	var archModelCfg config.SelectedModel
 	if cfg.Options.ArchitectModel != nil {
 		archModelCfg = *cfg.Options.ArchitectModel
@@ -441,40 +544,41 @@ func (c *coordinator) executeWithArchitectEditor(
 	archProviderCfg, ok := cfg.Providers.Get(archModelCfg.Provider)
 	if !ok {
 		slog.Warn("ArchitectModel provider not configured, falling back to single model", "provider", archModelCfg.Provider)
-		return c.Run(ctx, sessionID, prompt, attachments...)
+		return fallback()
 	}
 
 	archProvider, err := c.buildProvider(archProviderCfg, archModelCfg, false)
 	if err != nil {
 		slog.Warn("Failed to build architect provider, falling back to single model", "error", err)
-		return c.Run(ctx, sessionID, prompt, attachments...)
+		return fallback()
 	}
 
 	archCatwalk := cfg.GetModel(archModelCfg.Provider, archModelCfg.Model)
 	if archCatwalk == nil {
 		slog.Warn("ArchitectModel not found in provider, falling back to single model", "model", archModelCfg.Model)
-		return c.Run(ctx, sessionID, prompt, attachments...)
+		return fallback()
 	}
 
 	archLM, err := archProvider.LanguageModel(ctx, archModelCfg.Model)
 	if err != nil {
 		slog.Warn("Failed to create architect language model, falling back to single model", "error", err)
-		return c.Run(ctx, sessionID, prompt, attachments...)
+		return fallback()
 	}
 	archLM = newRateLimitedModel(archLM, c.rateLimitCoord, archModelCfg.Provider)
 
 	archModel := Model{
-		Model:      archLM,
-		CatwalkCfg: *archCatwalk,
-		ModelCfg:   archModelCfg,
-		FlatRate:   archProviderCfg.FlatRate,
+		Model:        archLM,
+		CatwalkCfg:   *archCatwalk,
+		ModelCfg:     archModelCfg,
+		FlatRate:     archProviderCfg.FlatRate,
+		ProviderType: archProviderCfg.Type,
 	}
 
 	// Phase 1: Architect generates a plan.
 	archAgent, err := c.buildArchitectAgent(ctx, archModel, archProviderCfg)
 	if err != nil {
 		slog.Warn("Failed to build architect agent, falling back to single model", "error", err)
-		return c.Run(ctx, sessionID, prompt, attachments...)
+		return fallback()
 	}
 
 	maxArchTokens := archCatwalk.DefaultMaxTokens
@@ -489,18 +593,21 @@ func (c *coordinator) executeWithArchitectEditor(
 	c.recordCostFromResult(archModel, archResult, err)
 	if err != nil {
 		slog.Warn("Architect phase failed, falling back to single model", "error", err)
-		return c.Run(ctx, sessionID, prompt, attachments...)
+		return fallback()
 	}
 
 	plan, err := ParseArchitectPlan(archResult.Response.Content.Text())
 	if err != nil {
 		slog.Warn("Failed to parse architect plan, using raw output", "error", err)
 		plan = ArchitectPlan{
-			Steps:     []PlanStep{{Description: prompt, Status: PlanStepPending}},
+			Steps:     []PlanStep{{Description: task, Status: PlanStepPending}},
 			Rationale: "Fallback: architect output was not valid JSON",
 		}
 	}
 	plan.ModelID = archModelCfg.Model
+	if forceApproval {
+		plan.ApprovalRequired = true
+	}
 
 	slog.Info("Architect plan generated",
 		"steps", len(plan.Steps),
@@ -512,9 +619,24 @@ func (c *coordinator) executeWithArchitectEditor(
 	gate := NewApprovalGate(approvalRequired)
 	if err := gate.Check(plan); err != nil {
 		slog.Info("Architect plan blocked by approval gate", "steps", len(plan.Steps))
-		return nil, fmt.Errorf("plan approval gate: %w", err)
+		return c.proposePlan(ctx, sessionID, task, plan)
 	}
 
+	return c.executeArchitectPlan(ctx, sessionID, task, &plan, attachments...)
+}
+
+// executeArchitectPlan runs phase 2 of the architect→editor flow: the plan
+// is handed to the Operator for large plans, or to the editor model as a
+// single prompt otherwise. Step statuses are updated in place.
+func (c *coordinator) executeArchitectPlan(
+	ctx context.Context,
+	sessionID string,
+	prompt string,
+	plan *ArchitectPlan,
+	attachments ...message.Attachment,
+) (*fantasy.AgentResult, error) {
+	cfg := c.cfg.Config()
+
 	// XRUSH: For complex multi-step plans (>3 steps), use Operator for
 	// recursive DAG decomposition with fresh subagents per subtask.
 	if len(plan.Steps) > 3 && c.structuredSubagentFactory != nil {
@@ -571,10 +693,11 @@ func (c *coordinator) executeWithArchitectEditor(
 					if err == nil {
 						editLM = newRateLimitedModel(editLM, c.rateLimitCoord, editCfg.Provider)
 						editorModel = Model{
-							Model:      editLM,
-							CatwalkCfg: *editCatwalk,
-							ModelCfg:   editCfg,
-							FlatRate:   editProviderCfg.FlatRate,
+							Model:        editLM,
+							CatwalkCfg:   *editCatwalk,
+							ModelCfg:     editCfg,
+							FlatRate:     editProviderCfg.FlatRate,
+							ProviderType: editProviderCfg.Type,
 						}
 					}
 				}
@@ -608,7 +731,13 @@ func (c *coordinator) executeWithArchitectEditor(
 			)
 		}
 	} else {
+		// Steps checked off (or left pending) via the todos tool keep
+		// that status; the rest are considered done.
+		c.syncPlanFromTodos(ctx, sessionID, plan)
 		for i := range plan.Steps {
+			if plan.Steps[i].Status != PlanStepRunning {
+				continue
+			}
 			plan.MarkStepCompleted(i)
 			slog.Info("Plan step marked as completed",
 				"step", i+1,
@@ -717,6 +846,7 @@ func getProviderOptions(model Model, providerCfg config.ProviderConfig) fantasy.
 				"effort":  model.ModelCfg.ReasoningEffort,
 			}
 		}
+		setStopSequences(mergedOptions, model.ModelCfg.Stop) // XRUSH: custom stop sequences
 		parsed, err := openrouter.ParseOptions(mergedOptions)
 		if err == nil {
 			options[openrouter.Name] = parsed
@@ -729,6 +859,7 @@ func getProviderOptions(model Model, providerCfg config.ProviderConfig) fantasy.
 				"effort":  model.ModelCfg.ReasoningEffort,
 			}
 		}
+		setStopSequences(mergedOptions, model.ModelCfg.Stop) // XRUSH: custom stop sequences
 		parsed, err := vercel.ParseOptions(mergedOptions)
 		if err == nil {
 			options[vercel.Name] = parsed
@@ -796,6 +927,10 @@ func getProviderOptions(model Model, providerCfg config.ProviderConfig) fantasy.
 			}
 		}
 
+		if len(model.ModelCfg.Stop) > 0 { // XRUSH: custom stop sequences
+			extraBody["stop"] = model.ModelCfg.Stop
+		}
+
 		mergedOptions["extra_body"] = extraBody
 
 		parsed, err := openaicompat.ParseOptions(mergedOptions)
@@ -807,6 +942,20 @@ func getProviderOptions(model Model, providerCfg config.ProviderConfig) fantasy.
 	return options
 }
 
+// setStopSequences adds stop sequences to the extra body of merged
+// provider options, keeping the fields already there.
+func setStopSequences(mergedOptions map[string]any, stop []string) {
+	if len(stop) == 0 {
+		return
+	}
+	extraBody, _ := mergedOptions["extra_body"].(map[string]any)
+	if extraBody == nil {
+		extraBody = make(map[string]any)
+	}
+	extraBody["stop"] = stop
+	mergedOptions["extra_body"] = extraBody
+}
+
 func mergeCallOptions(model Model, cfg config.ProviderConfig) (fantasy.ProviderOptions, *float64, *float64, *int64, *float64, *float64) {
 	modelOptions := getProviderOptions(model, cfg)
 	temp := cmp.Or(model.ModelCfg.Temperature, model.CatwalkCfg.Options.Temperature)
@@ -888,6 +1037,34 @@ func (c *coordinator) buildAgent(ctx context.Context, prompt *prompt.Prompt, age
 			}
 			return c.extHost
 		}(),
+		ContextAttribution: c.cfg.Config().Options.ContextAttribution && !isSubAgent, // XRUSH: context attribution
+		WorkingDir:         c.cfg.WorkingDir(),
+		// XRUSH: self-verification pass; sub-agents report back to the
+		// main agent, which verifies the turn as a whole.
+		Verifier: func() *Verifier {
+			if isSubAgent {
+				return nil
+			}
+			v := NewVerifier(c.cfg.Config().Options.Verification, c.cfg.WorkingDir(), c.filetracker)
+			if v != nil {
+				// An invalid target fails buildTools, so the agent
+				// never runs with local checks by mistake.
+				v.Remote, _ = c.remoteTarget()
+				if env, _ := c.devEnv(v.Remote); env.Active() {
+					v.DevEnv = env
+				}
+			}
+			return v
+		}(),
+		// XRUSH: multi-candidate sampling, for the main agent's answers
+		// only.
+		Sampler: func() *Sampler {
+			if isSubAgent {
+				return nil
+			}
+			return c.buildSampler(ctx)
+		}(),
+		Reasoning: c.cfg.Config().Options.Reasoning, // XRUSH: reasoning capture and storage
 	})
 
 	c.readyWg.Go(func() error {
@@ -911,6 +1088,36 @@ func (c *coordinator) buildAgent(ctx context.Context, prompt *prompt.Prompt, age
 	return result, nil
 }
 
+// buildSampler returns the configured multi-candidate sampler, or nil when
+// sampling is disabled. Sampling models that cannot be resolved are
+// skipped.
+func (c *coordinator) buildSampler(ctx context.Context) *Sampler {
+	opts := c.cfg.Config().Options.Sampling
+	if opts == nil || !opts.Enabled {
+		return nil
+	}
+	var models []SamplerModel
+	for _, selected := range opts.Models {
+		providerCfg, ok := c.cfg.Config().Providers.Get(selected.Provider)
+		if !ok {
+			slog.Warn("Sampling model provider not configured", "provider", selected.Provider, "model", selected.Model)
+			continue
+		}
+		model, err := c.ResolveLCMModel(ctx, selected, providerCfg)
+		if err != nil {
+			slog.Warn("Failed to resolve sampling model", "provider", selected.Provider, "model", selected.Model, "error", err)
+			continue
+		}
+		models = append(models, SamplerModel{Model: model, ProviderOptions: getProviderOptions(model, providerCfg)})
+	}
+	sampler := NewSampler(opts, models)
+	sampler.RecordCost = func(model string, tokensIn, tokensOut int, cost float64) {
+		c.costTracker.RecordCost(model, tokensIn, tokensOut, cost)
+		c.metricsStore.Record(model, 0, true, tokensIn, tokensOut, cost)
+	}
+	return sampler
+}
+
 func (c *coordinator) buildTools(ctx context.Context, agent config.Agent, isSubAgent bool) ([]fantasy.AgentTool, error) {
 	var allTools []fantasy.AgentTool
 	if slices.Contains(agent.AllowedTools, AgentToolName) {
@@ -929,6 +1136,19 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent, isSubA
 		allTools = append(allTools, agenticFetchTool)
 	}
 
+	// XRUSH: bash and the file tools run against the remote target, when
+	// one is configured.
+	target, err := c.remoteTarget()
+	if err != nil {
+		return nil, err
+	}
+	// XRUSH: otherwise they run inside the project's Nix or direnv
+	// environment, when one is enabled.
+	env, err := c.devEnv(target)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the model name for the agent
 	modelID := ""
 	if modelCfg, ok := c.cfg.Config().Models[agent.Model]; ok {
@@ -951,16 +1171,33 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent, isSubA
 		postToolRunner = hooks.NewRunner(postHooks, c.cfg.WorkingDir(), c.cfg.WorkingDir())
 	}
 
+	// In review mode, edits are staged for per-hunk review instead of
+	// being written immediately.
+	var stager staging.Service
+	if c.cfg.Config().Options.ReviewEdits {
+		stager = c.staging
+	}
+
 	allTools = append(
 		allTools,
-		tools.NewBashTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Config().Options.Attribution, modelID),
+		tools.NewBashTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Config().Options.Attribution, modelID, target, env),
 		tools.NewCrushInfoTool(c.cfg, c.lspManager, c.allSkills, c.activeSkills, c.skillTracker),
 		tools.NewCrushLogsTool(logFile),
 		tools.NewJobOutputTool(),
 		tools.NewJobKillTool(),
+		tools.NewKnowledgeLookupTool(c.knowledgeBase()), // XRUSH: project knowledge base
+		tools.NewSchemaLookupTool(c.cfg.WorkingDir()),   // XRUSH: database schema from migrations
+		tools.NewFeatureFlagsTool(c.cfg.WorkingDir(), c.cfg.Config().Options.FeatureFlags.CustomPatterns()), // XRUSH: feature flag usages
+		tools.NewTechDebtTool(c.cfg.WorkingDir(), c.cfg.Config().Options.DataDirectory),                     // XRUSH: TODO/FIXME inventory
+		tools.NewLicensesTool(c.cfg.WorkingDir()),                                                           // XRUSH: license compliance
+		tools.NewDuplicateCodeTool(c.cfg.WorkingDir()),                                                      // XRUSH: duplicate code detection
+		tools.NewTailLogsTool(c.permissions, c.cfg.WorkingDir()),                                            // XRUSH: live log tailing
+		tools.NewProcessesTool(),                                                                            // XRUSH: process inspection
+		tools.NewPortsTool(),                                                                                // XRUSH: port usage
+		tools.NewSystemResourcesTool(c.cfg.WorkingDir()),                                                    // XRUSH: resource snapshot
 		tools.NewDownloadTool(c.permissions, c.cfg.WorkingDir(), nil),
-		tools.NewEditTool(c.lspManager, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir()),
-		tools.NewMultiEditTool(c.lspManager, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir()),
+		tools.NewEditTool(c.lspManager, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir(), stager, target),
+		tools.NewMultiEditTool(c.lspManager, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir(), stager, target),
 		tools.NewFetchTool(c.permissions, c.cfg.WorkingDir(), nil),
 		tools.NewGlobTool(c.cfg.WorkingDir()),
 		tools.NewGrepTool(c.cfg.WorkingDir(), c.cfg.Config().Tools.Grep),
@@ -968,7 +1205,7 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent, isSubA
 		tools.NewSourcegraphTool(nil),
 		tools.NewTodosTool(c.sessions),
 		tools.NewViewTool(c.lspManager, c.permissions, c.filetracker, c.skillTracker, c.cfg.WorkingDir(), c.fileScoreProvider, c.cfg.Config().Options.SkillsPaths...),
-		tools.NewWriteTool(c.lspManager, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir()),
+		tools.NewWriteTool(c.lspManager, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir(), stager, target),
 	)
 
 	// Add LSP tools if user has configured LSPs or auto_lsp is enabled (nil or true).
@@ -1050,6 +1287,11 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent, isSubA
 	// itself is still wrapped from the coder's side.
 	filteredTools = wrapToolsWithHooks(filteredTools, preToolRunner, postToolRunner, isSubAgent)
 
+	// XRUSH: swap long tool descriptions for their compressed rewrites.
+	if !isSubAgent {
+		filteredTools = c.compressToolDescriptions(filteredTools)
+	}
+
 	return filteredTools, nil
 }
 
@@ -1152,16 +1394,18 @@ func (c *coordinator) buildAgentModels(ctx context.Context, isSubAgent bool) (Mo
 	smallModel = newRateLimitedModel(smallModel, c.rateLimitCoord, smallModelCfg.Provider)
 
 	return Model{
-			Model:      largeModel,
-			CatwalkCfg: *largeCatwalkModel,
-			ModelCfg:   largeModelCfg,
-			FlatRate:   largeProviderCfg.FlatRate,
-		}, Model{
-			Model:      smallModel,
-			CatwalkCfg: *smallCatwalkModel,
-			ModelCfg:   smallModelCfg,
-			FlatRate:   smallProviderCfg.FlatRate,
-		}, nil
+		Model:        largeModel,
+		CatwalkCfg:   *largeCatwalkModel,
+		ModelCfg:     largeModelCfg,
+		FlatRate:     largeProviderCfg.FlatRate,
+		ProviderType: largeProviderCfg.Type,
+	}, Model{
+		Model:        smallModel,
+		CatwalkCfg:   *smallCatwalkModel,
+		ModelCfg:     smallModelCfg,
+		FlatRate:     smallProviderCfg.FlatRate,
+		ProviderType: smallProviderCfg.Type,
+	}, nil
 }
 
 func (c *coordinator) buildAnthropicProvider(baseURL, apiKey string, headers map[string]string, providerID string) (fantasy.Provider, error) {
@@ -1464,6 +1708,7 @@ func (c *coordinator) UpdateModels(ctx context.Context) error {
 		return err
 	}
 	c.currentAgent.SetModels(large, small)
+	c.smallModel.Set(small)
 
 	agentCfg, ok := c.cfg.Config().Agents[config.AgentCoder]
 	if !ok {
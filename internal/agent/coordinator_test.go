@@ -9,6 +9,8 @@ import (
 	"charm.land/fantasy"
 	"charm.land/fantasy/providers/anthropic"
 	"charm.land/fantasy/providers/bedrock"
+	"charm.land/fantasy/providers/openaicompat"
+	"charm.land/fantasy/providers/openrouter"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -428,3 +430,52 @@ func TestGetProviderOptionsReasoningEffort(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProviderOptionsStopSequences(t *testing.T) {
+	t.Parallel()
+
+	model := Model{
+		CatwalkCfg: catwalk.Model{ID: "qwen3-coder"},
+		ModelCfg: config.SelectedModel{
+			Provider: "local",
+			Stop:     []string{"<|im_end|>", "<|endoftext|>"},
+		},
+	}
+
+	opts := getProviderOptions(model, config.ProviderConfig{ID: "local", Type: catwalk.TypeOpenAICompat})
+	compat, ok := opts[openaicompat.Name].(*openaicompat.ProviderOptions)
+	require.True(t, ok)
+	assert.Equal(t, []string{"<|im_end|>", "<|endoftext|>"}, compat.ExtraBody["stop"])
+
+	opts = getProviderOptions(model, config.ProviderConfig{
+		ID:              "openrouter",
+		Type:            catwalk.TypeOpenRouter,
+		ProviderOptions: map[string]any{"extra_body": map[string]any{"seed": 7}},
+	})
+	router, ok := opts[openrouter.Name].(*openrouter.ProviderOptions)
+	require.True(t, ok)
+	assert.Equal(t, []string{"<|im_end|>", "<|endoftext|>"}, router.ExtraBody["stop"])
+	assert.InDelta(t, 7, router.ExtraBody["seed"], 0)
+}
+
+func TestCoordinatorTemplateToolAllowList(t *testing.T) {
+	ctx := context.Background()
+	env := testEnv(t)
+	cfg, err := config.Init(env.workingDir, "", false)
+	require.NoError(t, err)
+	config.SessionTemplate{Tools: []string{"view", "grep"}}.ApplyTools(cfg.Config())
+
+	c := &coordinator{
+		cfg:         cfg,
+		sessions:    env.sessions,
+		permissions: env.permissions,
+	}
+
+	tools, err := c.buildTools(ctx, cfg.Config().Agents[config.AgentCoder], false)
+	require.NoError(t, err)
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Info().Name)
+	}
+	require.Equal(t, []string{"grep", "view"}, names)
+}
@@ -0,0 +1,36 @@
+package agent
+
+import (
+	"cmp"
+	"log/slog"
+
+	"github.com/charmbracelet/crush/internal/knowledge"
+)
+
+// defaultEmbeddingsURL is used when the embedding provider has no base
+// URL of its own, as is the case for the built-in OpenAI provider.
+const defaultEmbeddingsURL = "https://api.openai.com/v1"
+
+// knowledgeBase returns the project knowledge base for the
+// knowledge_lookup tool. Semantic retrieval is enabled only when an
+// embedding model is configured; otherwise lookups match keywords.
+func (c *coordinator) knowledgeBase() *knowledge.Base {
+	cfg := c.cfg.Config()
+	opts := cfg.Options.Knowledge
+	if opts == nil {
+		return knowledge.New(cfg.Options.DataDirectory, 0, nil)
+	}
+
+	var embedder knowledge.Embedder
+	if e := opts.Embeddings; e != nil && e.Model != "" {
+		providerCfg, ok := cfg.Providers.Get(e.Provider)
+		if ok {
+			apiKey, _ := c.cfg.Resolve(providerCfg.APIKey)
+			baseURL, _ := c.cfg.Resolve(providerCfg.BaseURL)
+			embedder = knowledge.NewOpenAIEmbedder(cmp.Or(baseURL, defaultEmbeddingsURL), apiKey, e.Model, providerCfg.ExtraHeaders)
+		} else {
+			slog.Warn("Knowledge embedding provider not configured, using keyword matching", "provider", e.Provider)
+		}
+	}
+	return knowledge.New(cfg.Options.DataDirectory, opts.MaxTokens, embedder)
+}
@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/crush/internal/devenv"
+	"github.com/charmbracelet/crush/internal/remote"
+)
+
+// remoteTarget returns the configured remote execution target, or nil when
+// commands run locally.
+func (c *coordinator) remoteTarget() (*remote.Target, error) {
+	target, err := remote.New(c.cfg.Config().Options.Remote, c.cfg.WorkingDir())
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote target: %w", err)
+	}
+	return target, nil
+}
+
+// devEnv returns the project's Nix or direnv environment, or nil when it
+// declares none or dev_env is off. Commands on a remote target run in
+// that target's environment instead.
+func (c *coordinator) devEnv(target *remote.Target) (*devenv.Env, error) {
+	if target != nil {
+		return nil, nil
+	}
+	env, err := devenv.New(c.cfg.Config().Options.DevEnv, c.cfg.WorkingDir())
+	if err != nil {
+		return nil, fmt.Errorf("invalid development environment: %w", err)
+	}
+	return env, nil
+}
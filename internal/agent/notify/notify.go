@@ -3,6 +3,8 @@
 // events without importing UI packages.
 package notify
 
+import "time"
+
 // Type identifies the kind of agent notification.
 type Type string
 
@@ -15,6 +17,11 @@ const (
 	// TypeReAuthenticate indicates the agent encountered an
 	// authentication error and the user needs to re-authenticate.
 	TypeReAuthenticate Type = "re_authenticate"
+	// TypeToolStarted indicates the model emitted a complete tool call
+	// and the tool is about to run.
+	TypeToolStarted Type = "tool_started"
+	// TypeToolFinished indicates a tool call produced its result.
+	TypeToolFinished Type = "tool_finished"
 )
 
 // Notification represents a domain event published by the agent.
@@ -23,4 +30,19 @@ type Notification struct {
 	SessionTitle string
 	Type         Type
 	ProviderID   string
+
+	// Duration is how long the turn ran. Populated for agent finished
+	// notifications only.
+	Duration time.Duration
+
+	// Populated for tool lifecycle notifications only.
+	ToolCallID string
+	ToolName   string
+	IsError    bool
+}
+
+// IsToolEvent reports whether the notification describes a tool
+// lifecycle transition rather than a turn-level agent event.
+func (n Notification) IsToolEvent() bool {
+	return n.Type == TypeToolStarted || n.Type == TypeToolFinished
 }
@@ -0,0 +1,417 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/history"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+)
+
+// PlanPhase is the state of a plan in the plan/execute state machine.
+//
+//	proposed ──approve──▶ executing ──▶ completed
+//	proposed ──reject───▶ rejected
+//	proposed ──feedback─▶ revising ──▶ proposed
+type PlanPhase string
+
+const (
+	PlanPhaseProposed  PlanPhase = "proposed"
+	PlanPhaseRevising  PlanPhase = "revising"
+	PlanPhaseExecuting PlanPhase = "executing"
+	PlanPhaseCompleted PlanPhase = "completed"
+	PlanPhaseRejected  PlanPhase = "rejected"
+)
+
+// PlanReply is the user's response to a proposed plan.
+type PlanReply int
+
+const (
+	// PlanReplyRevise treats the reply as feedback to re-plan with.
+	PlanReplyRevise PlanReply = iota
+	PlanReplyApprove
+	PlanReplyReject
+)
+
+// planApproveWords and planRejectWords are the replies accepted as an
+// explicit approval or rejection of a proposed plan. Anything else is
+// treated as feedback for a revised plan.
+var (
+	planApproveWords = []string{
+		"approve", "approved", "yes", "y", "ok", "okay", "lgtm",
+		"go", "go ahead", "proceed", "execute", "run it", "do it",
+	}
+	planRejectWords = []string{
+		"reject", "rejected", "no", "n", "cancel", "abort", "stop", "discard",
+	}
+)
+
+// ClassifyPlanReply maps a user reply to a proposed plan onto a PlanReply.
+func ClassifyPlanReply(reply string) PlanReply {
+	normalized := strings.ToLower(strings.TrimSpace(reply))
+	normalized = strings.TrimRight(normalized, ".!")
+	switch {
+	case slices.Contains(planApproveWords, normalized):
+		return PlanReplyApprove
+	case slices.Contains(planRejectWords, normalized):
+		return PlanReplyReject
+	default:
+		return PlanReplyRevise
+	}
+}
+
+// PlanDeviation records a file touched during execution that no plan step
+// declared in its TargetFiles.
+type PlanDeviation struct {
+	Path       string    `json:"path"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// PlanSession is the plan state tracked for a single session.
+type PlanSession struct {
+	// Task is the original user prompt the plan was produced for.
+	Task string
+	// Plan is the current (possibly revised) plan.
+	Plan ArchitectPlan
+	// Phase is the current state of the plan.
+	Phase PlanPhase
+	// Revisions counts how many times the plan was re-generated from
+	// user feedback.
+	Revisions int
+	// StartedAt is when execution of the approved plan began.
+	StartedAt time.Time
+	// Deviations lists files touched outside the plan during execution.
+	Deviations []PlanDeviation
+}
+
+// planTracker holds the plan state machine for every session. The state
+// lives only in memory: after a restart no plan is pending, and a reply to
+// a plan proposed before it is handled as a new prompt.
+type planTracker struct {
+	sessions *csync.Map[string, *PlanSession]
+}
+
+func newPlanTracker() *planTracker {
+	return &planTracker{sessions: csync.NewMap[string, *PlanSession]()}
+}
+
+// Propose records a plan awaiting approval for the session, replacing any
+// previous one. When the previous plan is being revised, the original task
+// is kept and the revision counter is bumped.
+func (t *planTracker) Propose(sessionID, task string, plan ArchitectPlan) *PlanSession {
+	ps := &PlanSession{Task: task, Plan: plan, Phase: PlanPhaseProposed}
+	if prev, ok := t.sessions.Get(sessionID); ok && prev.Phase == PlanPhaseRevising {
+		ps.Task = prev.Task
+		ps.Revisions = prev.Revisions + 1
+	}
+	t.sessions.Set(sessionID, ps)
+	return ps
+}
+
+// Pending returns the plan awaiting approval for the session, if any.
+func (t *planTracker) Pending(sessionID string) (*PlanSession, bool) {
+	ps, ok := t.sessions.Get(sessionID)
+	if !ok || ps.Phase != PlanPhaseProposed {
+		return nil, false
+	}
+	return ps, true
+}
+
+// Get returns the plan state for the session regardless of phase.
+func (t *planTracker) Get(sessionID string) (*PlanSession, bool) {
+	return t.sessions.Get(sessionID)
+}
+
+// Approve moves a proposed plan to the executing phase.
+func (t *planTracker) Approve(sessionID string) (*PlanSession, error) {
+	ps, ok := t.Pending(sessionID)
+	if !ok {
+		return nil, fmt.Errorf("no plan awaiting approval for session %s", sessionID)
+	}
+	ps.Phase = PlanPhaseExecuting
+	ps.StartedAt = time.Now()
+	return ps, nil
+}
+
+// Revise moves a proposed plan to the revising phase while a new plan is
+// generated from the user's feedback.
+func (t *planTracker) Revise(sessionID string) (*PlanSession, error) {
+	ps, ok := t.Pending(sessionID)
+	if !ok {
+		return nil, fmt.Errorf("no plan awaiting approval for session %s", sessionID)
+	}
+	ps.Phase = PlanPhaseRevising
+	return ps, nil
+}
+
+// Reject discards a proposed plan.
+func (t *planTracker) Reject(sessionID string) error {
+	ps, ok := t.Pending(sessionID)
+	if !ok {
+		return fmt.Errorf("no plan awaiting approval for session %s", sessionID)
+	}
+	ps.Phase = PlanPhaseRejected
+	return nil
+}
+
+// Drop forgets the session's plan, whatever its phase.
+func (t *planTracker) Drop(sessionID string) {
+	t.sessions.Del(sessionID)
+}
+
+// Finish marks an executing plan as completed.
+func (t *planTracker) Finish(sessionID string) {
+	if ps, ok := t.sessions.Get(sessionID); ok && ps.Phase == PlanPhaseExecuting {
+		ps.Phase = PlanPhaseCompleted
+	}
+}
+
+// RecordDeviations stores the touched paths that fall outside the plan.
+func (t *planTracker) RecordDeviations(sessionID string, paths []string) []PlanDeviation {
+	ps, ok := t.sessions.Get(sessionID)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	var added []PlanDeviation
+	for _, p := range paths {
+		if ps.Plan.CoversFile(p) {
+			continue
+		}
+		d := PlanDeviation{Path: p, DetectedAt: now}
+		ps.Deviations = append(ps.Deviations, d)
+		added = append(added, d)
+	}
+	return added
+}
+
+// CoversFile reports whether any step declares path in its TargetFiles.
+// Paths are compared after cleaning, and a relative target matches any
+// path that ends with it. Plans without any target files cover nothing.
+func (p ArchitectPlan) CoversFile(path string) bool {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for _, step := range p.Steps {
+		for _, target := range step.TargetFiles {
+			target = filepath.ToSlash(filepath.Clean(target))
+			if path == target || strings.HasSuffix(path, "/"+strings.TrimPrefix(target, "./")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PlanTodos converts the plan steps into session todos so the agent can
+// check them off with the todos tool as it works.
+func (p ArchitectPlan) PlanTodos() []session.Todo {
+	todos := make([]session.Todo, 0, len(p.Steps))
+	for i, step := range p.Steps {
+		todos = append(todos, session.Todo{
+			Content:    planTodoContent(i, step),
+			Status:     session.TodoStatusPending,
+			ActiveForm: step.Description,
+		})
+	}
+	return todos
+}
+
+// SyncFromTodos updates step statuses from the session todos produced by
+// PlanTodos and returns how many steps had a matching todo. Only running
+// steps are updated: steps whose todo was left pending are marked skipped,
+// and steps without a todo keep their current status.
+func (p *ArchitectPlan) SyncFromTodos(todos []session.Todo) int {
+	matched := 0
+	for i, step := range p.Steps {
+		content := planTodoContent(i, step)
+		idx := slices.IndexFunc(todos, func(t session.Todo) bool { return t.Content == content })
+		if idx < 0 || step.Status != PlanStepRunning {
+			continue
+		}
+		matched++
+		switch todos[idx].Status {
+		case session.TodoStatusCompleted:
+			p.MarkStepCompleted(i)
+		case session.TodoStatusPending:
+			p.MarkStepSkipped(i)
+		}
+	}
+	return matched
+}
+
+func planTodoContent(idx int, step PlanStep) string {
+	return fmt.Sprintf("Step %d: %s", idx+1, step.Description)
+}
+
+// approvalMessage renders the proposed plan for the user together with the
+// replies that drive the state machine.
+func (ps *PlanSession) approvalMessage() string {
+	var b strings.Builder
+	if ps.Revisions > 0 {
+		fmt.Fprintf(&b, "Revised plan (revision %d) awaiting approval.\n\n", ps.Revisions)
+	} else {
+		b.WriteString("Plan awaiting approval.\n\n")
+	}
+	b.WriteString(ps.Plan.String())
+	b.WriteString("\n\nReply \"approve\" to execute it, \"reject\" to discard it, or describe the changes you want to revise it.")
+	return b.String()
+}
+
+// proposePlan stores the plan as awaiting approval and shows it to the
+// user. The next prompt in the session is handled by handlePlanReply.
+func (c *coordinator) proposePlan(ctx context.Context, sessionID, task string, plan ArchitectPlan) (*fantasy.AgentResult, error) {
+	ps := c.plans.Propose(sessionID, task, plan)
+	text := ps.approvalMessage()
+	if err := c.createPlanMessage(ctx, sessionID, message.Assistant, text); err != nil {
+		return nil, err
+	}
+	return planResult(text), nil
+}
+
+// handlePlanReply advances the plan state machine with the user's reply
+// to a proposed plan: approval executes it, rejection discards it, and
+// anything else is used as feedback to produce a revised plan. The reply
+// is kept in the session history whichever way it goes.
+func (c *coordinator) handlePlanReply(
+	ctx context.Context,
+	sessionID string,
+	ps *PlanSession,
+	reply string,
+	attachments ...message.Attachment,
+) (*fantasy.AgentResult, error) {
+	if err := c.createPlanMessage(ctx, sessionID, message.User, reply); err != nil {
+		return nil, err
+	}
+
+	switch ClassifyPlanReply(reply) {
+	case PlanReplyApprove:
+		if _, err := c.plans.Approve(sessionID); err != nil {
+			return nil, err
+		}
+		slog.Info("Architect plan approved", "session_id", sessionID, "steps", len(ps.Plan.Steps))
+		return c.executeApprovedPlan(ctx, sessionID, ps, attachments...)
+	case PlanReplyReject:
+		if err := c.plans.Reject(sessionID); err != nil {
+			return nil, err
+		}
+		slog.Info("Architect plan rejected", "session_id", sessionID)
+		const text = "Plan discarded. Nothing was executed."
+		if err := c.createPlanMessage(ctx, sessionID, message.Assistant, text); err != nil {
+			return nil, err
+		}
+		return planResult(text), nil
+	default:
+		if _, err := c.plans.Revise(sessionID); err != nil {
+			return nil, err
+		}
+		slog.Info("Revising architect plan from feedback", "session_id", sessionID, "revision", ps.Revisions+1)
+		revisePrompt := fmt.Sprintf(
+			"Revise the plan for the task below using the user's feedback.\n\nOriginal task: %s\n\nCurrent plan:\n%s\n\nFeedback: %s",
+			ps.Task, ps.Plan.String(), reply,
+		)
+		return c.runArchitectPhase(ctx, sessionID, ps.Task, revisePrompt, true, attachments...)
+	}
+}
+
+// executeApprovedPlan seeds the session todos from the plan so the agent
+// checks steps off as it goes, executes the plan, and flags any file the
+// run touched that no step declared.
+func (c *coordinator) executeApprovedPlan(
+	ctx context.Context,
+	sessionID string,
+	ps *PlanSession,
+	attachments ...message.Attachment,
+) (*fantasy.AgentResult, error) {
+	if sess, err := c.sessions.Get(ctx, sessionID); err == nil {
+		sess.Todos = ps.Plan.PlanTodos()
+		if _, err := c.sessions.Save(ctx, sess); err != nil {
+			slog.Warn("Failed to seed todos from plan", "session_id", sessionID, "error", err)
+		}
+	}
+
+	result, err := c.executeArchitectPlan(ctx, sessionID, ps.Task, &ps.Plan, attachments...)
+	c.plans.Finish(sessionID)
+
+	deviations := c.plans.RecordDeviations(sessionID, c.filesTouchedSince(ctx, sessionID, ps.StartedAt))
+	if len(deviations) > 0 {
+		paths := make([]string, 0, len(deviations))
+		for _, d := range deviations {
+			paths = append(paths, d.Path)
+		}
+		slog.Warn("Plan execution touched files outside the plan", "session_id", sessionID, "files", paths)
+		text := "Files changed outside the approved plan:\n- " + strings.Join(paths, "\n- ")
+		if msgErr := c.createPlanMessage(ctx, sessionID, message.Assistant, text); msgErr != nil {
+			slog.Warn("Failed to report plan deviations", "session_id", sessionID, "error", msgErr)
+		}
+	}
+
+	slog.Info("Architect plan finished", "session_id", sessionID, "plan", ps.Plan.String())
+	return result, err
+}
+
+// syncPlanFromTodos updates running plan steps from the session todos.
+func (c *coordinator) syncPlanFromTodos(ctx context.Context, sessionID string, plan *ArchitectPlan) {
+	if c.sessions == nil {
+		return
+	}
+	sess, err := c.sessions.Get(ctx, sessionID)
+	if err != nil {
+		return
+	}
+	plan.SyncFromTodos(sess.Todos)
+}
+
+// filesTouchedSince returns the sorted paths, relative to the working
+// directory when possible, of files modified in the session since t.
+func (c *coordinator) filesTouchedSince(ctx context.Context, sessionID string, t time.Time) []string {
+	if c.history == nil {
+		return nil
+	}
+	files, err := c.history.ListBySession(ctx, sessionID)
+	if err != nil {
+		slog.Warn("Failed to list session files for plan tracking", "session_id", sessionID, "error", err)
+		return nil
+	}
+	seen := make(map[string]struct{})
+	var paths []string
+	for _, f := range files {
+		if f.Version == history.InitialVersion || f.CreatedAt < t.Unix() {
+			continue
+		}
+		path := f.Path
+		if rel, err := filepath.Rel(c.cfg.WorkingDir(), path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func (c *coordinator) createPlanMessage(ctx context.Context, sessionID string, role message.MessageRole, text string) error {
+	parts := []message.ContentPart{message.TextContent{Text: text}}
+	if role == message.Assistant {
+		parts = append(parts, message.Finish{Reason: message.FinishReasonEndTurn, Time: time.Now().Unix()})
+	}
+	if _, err := c.messages.Create(ctx, sessionID, message.CreateMessageParams{Role: role, Parts: parts}); err != nil {
+		return fmt.Errorf("failed to create plan message: %w", err)
+	}
+	return nil
+}
+
+func planResult(text string) *fantasy.AgentResult {
+	return &fantasy.AgentResult{
+		Response: fantasy.Response{Content: fantasy.ResponseContent{fantasy.TextContent{Text: text}}},
+	}
+}
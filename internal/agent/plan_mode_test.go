@@ -0,0 +1,207 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyPlanReply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		reply string
+		want  PlanReply
+	}{
+		{"approve", PlanReplyApprove},
+		{"  LGTM! ", PlanReplyApprove},
+		{"go ahead.", PlanReplyApprove},
+		{"reject", PlanReplyReject},
+		{"No", PlanReplyReject},
+		{"also update the README in step 2", PlanReplyRevise},
+		{"yes but skip step 3", PlanReplyRevise},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, ClassifyPlanReply(tt.reply), tt.reply)
+	}
+}
+
+func TestPlanTrackerLifecycle(t *testing.T) {
+	t.Parallel()
+
+	tracker := newPlanTracker()
+	plan := ArchitectPlan{Steps: []PlanStep{{Description: "one", Status: PlanStepPending}}}
+
+	_, ok := tracker.Pending("s1")
+	require.False(t, ok)
+	_, err := tracker.Approve("s1")
+	require.Error(t, err)
+
+	tracker.Propose("s1", "task", plan)
+	ps, ok := tracker.Pending("s1")
+	require.True(t, ok)
+	require.Equal(t, PlanPhaseProposed, ps.Phase)
+	require.Zero(t, ps.Revisions)
+
+	_, err = tracker.Revise("s1")
+	require.NoError(t, err)
+	_, ok = tracker.Pending("s1")
+	require.False(t, ok, "a plan being revised is not awaiting approval")
+
+	ps = tracker.Propose("s1", "feedback prompt", plan)
+	require.Equal(t, "task", ps.Task, "revisions keep the original task")
+	require.Equal(t, 1, ps.Revisions)
+
+	ps, err = tracker.Approve("s1")
+	require.NoError(t, err)
+	require.Equal(t, PlanPhaseExecuting, ps.Phase)
+	require.False(t, ps.StartedAt.IsZero())
+
+	tracker.Finish("s1")
+	ps, ok = tracker.Get("s1")
+	require.True(t, ok)
+	require.Equal(t, PlanPhaseCompleted, ps.Phase)
+}
+
+func TestPlanTrackerReject(t *testing.T) {
+	t.Parallel()
+
+	tracker := newPlanTracker()
+	tracker.Propose("s1", "task", ArchitectPlan{})
+	require.NoError(t, tracker.Reject("s1"))
+
+	_, ok := tracker.Pending("s1")
+	require.False(t, ok)
+	require.Error(t, tracker.Reject("s1"))
+}
+
+func TestPlanTrackerDrop(t *testing.T) {
+	t.Parallel()
+
+	tracker := newPlanTracker()
+	tracker.Propose("s1", "task", ArchitectPlan{})
+	tracker.Drop("s1")
+
+	_, ok := tracker.Get("s1")
+	require.False(t, ok)
+	// Plans are kept in memory only, so a new tracker, as after a
+	// restart, has nothing awaiting approval.
+	tracker.Propose("s2", "task", ArchitectPlan{})
+	_, ok = newPlanTracker().Pending("s2")
+	require.False(t, ok)
+}
+
+func TestHandlePlanReply_RecordsReply(t *testing.T) {
+	const providerID = "test-provider"
+
+	tests := []struct {
+		name  string
+		reply string
+	}{
+		{"reject", "reject"},
+		{"revise", "also update the README"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testEnv(t)
+			coord := newTestCoordinator(t, env, providerID, config.ProviderConfig{ID: providerID})
+			coord.messages = env.messages
+			coord.plans = newPlanTracker()
+			coord.downgrades = newDowngradeTracker()
+			// The architect provider is missing, so revising falls back to
+			// running the task on the current agent.
+			coord.cfg.Config().Options.ArchitectModel = &config.SelectedModel{Provider: "missing", Model: "missing"}
+
+			var prompts []string
+			coord.currentAgent = newMockAgent(providerID, 4096, func(_ context.Context, call SessionAgentCall) (*fantasy.AgentResult, error) {
+				prompts = append(prompts, call.Prompt)
+				return agentResultWithText("done"), nil
+			})
+
+			sess, err := env.sessions.Create(t.Context(), "Plan")
+			require.NoError(t, err)
+			ps := coord.plans.Propose(sess.ID, "add a flag", ArchitectPlan{
+				Steps: []PlanStep{{Description: "add the flag", Status: PlanStepPending}},
+			})
+
+			_, err = coord.handlePlanReply(t.Context(), sess.ID, ps, tt.reply)
+			require.NoError(t, err)
+
+			msgs, err := env.messages.List(t.Context(), sess.ID)
+			require.NoError(t, err)
+			var replies int
+			for _, msg := range msgs {
+				if msg.Role == message.User && msg.Content().Text == tt.reply {
+					replies++
+				}
+			}
+			require.Equal(t, 1, replies, "the reply is recorded once")
+
+			_, ok := coord.plans.Get(sess.ID)
+			if tt.name == "revise" {
+				require.Equal(t, []string{"add a flag"}, prompts, "the fallback runs the original task")
+				require.False(t, ok, "the fallback drops the plan being revised")
+			} else {
+				require.Empty(t, prompts)
+			}
+		})
+	}
+}
+
+func TestPlanTrackerRecordDeviations(t *testing.T) {
+	t.Parallel()
+
+	tracker := newPlanTracker()
+	tracker.Propose("s1", "task", ArchitectPlan{Steps: []PlanStep{
+		{Description: "edit", TargetFiles: []string{"internal/agent/coordinator.go", "./README.md"}},
+	}})
+
+	got := tracker.RecordDeviations("s1", []string{
+		"internal/agent/coordinator.go",
+		"/repo/README.md",
+		"internal/agent/agent.go",
+	})
+	require.Len(t, got, 1)
+	require.Equal(t, "internal/agent/agent.go", got[0].Path)
+
+	ps, _ := tracker.Get("s1")
+	require.Len(t, ps.Deviations, 1)
+}
+
+func TestArchitectPlanSyncFromTodos(t *testing.T) {
+	t.Parallel()
+
+	plan := ArchitectPlan{Steps: []PlanStep{
+		{Description: "first", Status: PlanStepPending},
+		{Description: "second", Status: PlanStepPending},
+		{Description: "third", Status: PlanStepPending},
+	}}
+	todos := plan.PlanTodos()
+	require.Len(t, todos, 3)
+	require.Equal(t, "Step 1: first", todos[0].Content)
+
+	plan.MarkAllRunning()
+	todos[0].Status = session.TodoStatusCompleted
+	todos[1].Status = session.TodoStatusPending
+	todos = todos[:2]
+
+	require.Equal(t, 2, plan.SyncFromTodos(todos))
+	require.Equal(t, PlanStepCompleted, plan.Steps[0].Status)
+	require.Equal(t, PlanStepSkipped, plan.Steps[1].Status)
+	require.Equal(t, PlanStepRunning, plan.Steps[2].Status)
+}
+
+func TestArchitectPlanStringRisks(t *testing.T) {
+	t.Parallel()
+
+	plan := ArchitectPlan{
+		Steps: []PlanStep{{Description: "do it", Status: PlanStepPending}},
+		Risks: []string{"breaks the API"},
+	}
+	require.Contains(t, plan.String(), "Risks:\n  - breaks the API")
+}
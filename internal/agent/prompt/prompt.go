@@ -28,6 +28,8 @@ type Prompt struct {
 	workingDir string
 
 	cache *ContextCache // XRUSH: context caching
+
+	rewrite func(string) string // XRUSH: static section rewriting
 }
 
 type PromptDat struct {
@@ -81,7 +83,11 @@ func NewPrompt(name, promptTemplate string, opts ...Option) (*Prompt, error) {
 }
 
 func (p *Prompt) Build(ctx context.Context, provider, model string, store *config.ConfigStore) (string, error) {
-	t, err := template.New(p.name).Parse(p.template)
+	tmpl := p.template
+	if p.rewrite != nil {
+		tmpl = rewriteStaticSections(tmpl, p.rewrite)
+	}
+	t, err := template.New(p.name).Parse(tmpl)
 	if err != nil {
 		return "", fmt.Errorf("parsing template: %w", err)
 	}
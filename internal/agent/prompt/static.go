@@ -0,0 +1,53 @@
+package prompt
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sectionOpenRe matches a line that opens a top-level XML-style section,
+// such as "<critical_rules>".
+var sectionOpenRe = regexp.MustCompile(`^<([a-z_]+)>$`)
+
+// WithStaticRewriter rewrites the static sections of the template before it
+// is rendered. A section is static when it is a top-level "<tag>...</tag>"
+// block without template actions, so its text is the same for every
+// session. fn receives the whole block, tags included, and returns its
+// replacement.
+func WithStaticRewriter(fn func(section string) string) Option {
+	return func(p *Prompt) {
+		p.rewrite = fn
+	}
+}
+
+// rewriteStaticSections replaces every static section of tmpl with
+// fn(section). Sections containing template actions are left untouched.
+func rewriteStaticSections(tmpl string, fn func(string) string) string {
+	lines := strings.Split(tmpl, "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		m := sectionOpenRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, lines[i])
+			continue
+		}
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			if lines[j] == "</"+m[1]+">" {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			out = append(out, lines[i])
+			continue
+		}
+		block := strings.Join(lines[i:end+1], "\n")
+		if !strings.Contains(block, "{{") {
+			block = fn(block)
+		}
+		out = append(out, block)
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
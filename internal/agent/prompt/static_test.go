@@ -0,0 +1,39 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteStaticSections(t *testing.T) {
+	t.Parallel()
+
+	tmpl := `You are an assistant.
+
+<rules>
+Always be nice.
+Never be rude.
+</rules>
+
+<env>
+Working directory: {{.WorkingDir}}
+</env>
+
+<unclosed>
+trailing`
+
+	var seen []string
+	got := rewriteStaticSections(tmpl, func(s string) string {
+		seen = append(seen, s)
+		return strings.ToUpper(s)
+	})
+	require.Equal(t, []string{"<rules>\nAlways be nice.\nNever be rude.\n</rules>"}, seen)
+	require.Contains(t, got, "<RULES>\nALWAYS BE NICE.")
+	require.Contains(t, got, "Working directory: {{.WorkingDir}}")
+	require.True(t, strings.HasPrefix(got, "You are an assistant.\n\n"))
+	require.True(t, strings.HasSuffix(got, "<unclosed>\ntrailing"))
+
+	require.Equal(t, tmpl, rewriteStaticSections(tmpl, func(s string) string { return s }))
+}
@@ -0,0 +1,275 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/version"
+)
+
+//go:embed templates/compress.md
+var compressPrompt []byte
+
+// promptCompressionFile is the name of the compression cache in the data
+// directory.
+const promptCompressionFile = "prompt_compression.json"
+
+// minCompressChars is the shortest text worth compressing; shorter tool
+// descriptions are sent unchanged.
+const minCompressChars = 400
+
+// compressTimeout bounds a whole background compression pass.
+const compressTimeout = 10 * time.Minute
+
+// promptCompressor rewrites static prompt text into terser equivalents
+// with the small model. Rewrites are cached on disk keyed by the crush
+// version and the original text, so each text is compressed once per
+// version. Texts without a cached rewrite are sent unchanged and queued
+// for the next background pass.
+type promptCompressor struct {
+	path    string
+	running atomic.Bool
+
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string]string
+	pending map[string]string
+}
+
+func newPromptCompressor(path string) *promptCompressor {
+	return &promptCompressor{
+		path:    path,
+		entries: map[string]string{},
+		pending: map[string]string{},
+	}
+}
+
+func compressionKey(text string) string {
+	sum := sha256.Sum256([]byte(version.Version + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Rewrite returns the cached rewrite of text, or text itself when it has
+// not been compressed yet.
+func (p *promptCompressor) Rewrite(text string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.load()
+	key := compressionKey(text)
+	if got, ok := p.entries[key]; ok {
+		return got
+	}
+	p.pending[key] = text
+	return text
+}
+
+// Pending reports whether texts are waiting to be compressed.
+func (p *promptCompressor) Pending() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pending) > 0
+}
+
+// Compress rewrites every pending text with complete and persists the
+// results. Rewrites that are not shorter or drop the wrapping tags are
+// discarded in favour of the original, which is cached as well so it is not
+// retried. It returns the number of texts compressed.
+func (p *promptCompressor) Compress(ctx context.Context, complete func(ctx context.Context, system, text string) (string, error)) (int, error) {
+	p.mu.Lock()
+	pending := p.pending
+	p.pending = map[string]string{}
+	p.mu.Unlock()
+
+	done := 0
+	var firstErr error
+	for key, text := range pending {
+		if ctx.Err() != nil {
+			firstErr = ctx.Err()
+			break
+		}
+		got, err := complete(ctx, string(compressPrompt), text)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		got = cleanRewrite(got)
+		if !validRewrite(text, got) {
+			slog.Debug("Discarding prompt compression", "original_chars", len(text), "rewrite_chars", len(got))
+			got = text
+		} else {
+			done++
+		}
+		p.mu.Lock()
+		p.entries[key] = got
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.save()
+	return done, firstErr
+}
+
+// cleanRewrite strips surrounding whitespace and a code fence the model
+// may have wrapped its answer in.
+func cleanRewrite(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") && strings.HasSuffix(s, "```") {
+		lines := strings.Split(s, "\n")
+		if len(lines) >= 2 {
+			s = strings.TrimSpace(strings.Join(lines[1:len(lines)-1], "\n"))
+		}
+	}
+	return s
+}
+
+// validRewrite reports whether rewrite can replace orig: it must be shorter
+// and keep the first and last lines of a tagged section.
+func validRewrite(orig, rewrite string) bool {
+	if rewrite == "" || len(rewrite) >= len(orig) {
+		return false
+	}
+	if !strings.HasPrefix(orig, "<") {
+		return true
+	}
+	first, last := orig, orig
+	if i := strings.IndexByte(orig, '\n'); i >= 0 {
+		first = orig[:i]
+	}
+	if i := strings.LastIndexByte(orig, '\n'); i >= 0 {
+		last = orig[i+1:]
+	}
+	return strings.HasPrefix(rewrite, first+"\n") && strings.HasSuffix(rewrite, "\n"+last)
+}
+
+func (p *promptCompressor) load() {
+	if p.loaded || p.path == "" {
+		return
+	}
+	p.loaded = true
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, &p.entries)
+}
+
+func (p *promptCompressor) save() {
+	if p.path == "" {
+		return
+	}
+	data, err := json.Marshal(p.entries)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o700); err != nil {
+		return
+	}
+	if err := fsext.WriteFileAtomic(p.path, data, 0o600); err != nil {
+		slog.Warn("Failed to save prompt compression cache", "error", err)
+	}
+}
+
+// compressedTool replaces the description of a tool with its compressed
+// rewrite.
+type compressedTool struct {
+	fantasy.AgentTool
+	description string
+}
+
+func (t compressedTool) Info() fantasy.ToolInfo {
+	info := t.AgentTool.Info()
+	info.Description = t.description
+	return info
+}
+
+// promptCompressionEnabled reports whether compression applies. It is
+// re-evaluated on every use so toggling the option takes effect without a
+// restart.
+func (c *coordinator) promptCompressionEnabled() bool {
+	return c.cfg.Config().Options.PromptCompressionEnabled()
+}
+
+// rewriteStaticSection is the prompt.WithStaticRewriter hook of the coder
+// prompt.
+func (c *coordinator) rewriteStaticSection(section string) string {
+	if !c.promptCompressionEnabled() {
+		return section
+	}
+	return c.compressor.Rewrite(section)
+}
+
+// compressToolDescriptions swaps long tool descriptions for their cached
+// rewrites.
+func (c *coordinator) compressToolDescriptions(agentTools []fantasy.AgentTool) []fantasy.AgentTool {
+	if !c.promptCompressionEnabled() {
+		return agentTools
+	}
+	out := make([]fantasy.AgentTool, len(agentTools))
+	for i, tool := range agentTools {
+		out[i] = tool
+		desc := tool.Info().Description
+		if len(desc) < minCompressChars {
+			continue
+		}
+		if rewritten := c.compressor.Rewrite(desc); rewritten != desc {
+			out[i] = compressedTool{AgentTool: tool, description: rewritten}
+		}
+	}
+	return out
+}
+
+// startPromptCompression compresses pending texts with the small model in
+// the background, then rebuilds the system prompt so the next turn uses
+// them. Tool descriptions pick up the rewrites when tools are rebuilt at
+// the start of the next turn.
+func (c *coordinator) startPromptCompression() {
+	if !c.promptCompressionEnabled() || !c.compressor.Pending() || c.coderPrompt == nil {
+		return
+	}
+	small := c.smallModel.Get()
+	if small.Model == nil {
+		return
+	}
+	providerCfg, ok := c.cfg.Config().Providers.Get(small.ModelCfg.Provider)
+	if !ok {
+		return
+	}
+	if !c.compressor.running.CompareAndSwap(false, true) {
+		return
+	}
+	client := NewLCMLLMClient(small, providerCfg)
+	go func() {
+		defer c.compressor.running.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), compressTimeout)
+		defer cancel()
+
+		n, err := c.compressor.Compress(ctx, client.Complete)
+		if err != nil {
+			slog.Warn("Prompt compression incomplete", "error", err)
+		}
+		slog.Info("Compressed static prompt text", "count", n, "model", small.ModelCfg.Model)
+
+		large := c.currentAgent.Model()
+		systemPrompt, err := c.coderPrompt.Build(ctx, large.Model.Provider(), large.Model.Model(), c.cfg)
+		if err != nil {
+			slog.Warn("Failed to rebuild compressed system prompt", "error", err)
+			return
+		}
+		c.currentAgent.SetSystemPrompt(systemPrompt)
+	}()
+}
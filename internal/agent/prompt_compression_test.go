@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rulesSection = "<rules>\nPlease always make sure that you are being very concise in every answer.\n</rules>"
+
+func TestPromptCompressor(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), promptCompressionFile)
+	p := newPromptCompressor(path)
+	require.Equal(t, rulesSection, p.Rewrite(rulesSection), "unknown texts are sent unchanged")
+	require.Equal(t, "<env>\nshort\n</env>", p.Rewrite("<env>\nshort\n</env>"))
+	require.True(t, p.Pending())
+
+	calls := 0
+	n, err := p.Compress(t.Context(), func(_ context.Context, system, text string) (string, error) {
+		calls++
+		require.Contains(t, system, "as few tokens as possible")
+		if strings.HasPrefix(text, "<rules>") {
+			return "```\n<rules>\nBe concise.\n</rules>\n```", nil
+		}
+		// Longer than the original: discarded.
+		return text + " and more", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, 2, calls)
+	require.False(t, p.Pending())
+	require.Equal(t, "<rules>\nBe concise.\n</rules>", p.Rewrite(rulesSection))
+	require.Equal(t, "<env>\nshort\n</env>", p.Rewrite("<env>\nshort\n</env>"))
+	require.False(t, p.Pending(), "discarded rewrites are not retried")
+
+	// Rewrites survive a restart.
+	reloaded := newPromptCompressor(path)
+	require.Equal(t, "<rules>\nBe concise.\n</rules>", reloaded.Rewrite(rulesSection))
+	require.False(t, reloaded.Pending())
+}
+
+func TestPromptCompressorFailureRetries(t *testing.T) {
+	t.Parallel()
+
+	p := newPromptCompressor("")
+	p.Rewrite(rulesSection)
+	_, err := p.Compress(t.Context(), func(context.Context, string, string) (string, error) {
+		return "", errors.New("offline")
+	})
+	require.ErrorContains(t, err, "offline")
+	require.Equal(t, rulesSection, p.Rewrite(rulesSection))
+	require.True(t, p.Pending(), "failed texts are queued again on next use")
+}
+
+func TestValidRewrite(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, validRewrite("a long tool description", "short"))
+	require.False(t, validRewrite("short", "much longer text"))
+	require.False(t, validRewrite("short", ""))
+	require.True(t, validRewrite(rulesSection, "<rules>\nBe concise.\n</rules>"))
+	require.False(t, validRewrite(rulesSection, "Be concise."), "tags must be kept")
+	require.False(t, validRewrite(rulesSection, "<style>\nBe concise.\n</style>"))
+}
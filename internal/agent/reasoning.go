@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/crush/internal/agent/hyper"
+	"github.com/charmbracelet/crush/internal/message"
+)
+
+// inlineReasoningProviders are the provider types whose models may put
+// their reasoning in the reply text, wrapped in a tag such as <think>.
+// Other providers stream reasoning as its own content (Anthropic thinking
+// blocks, OpenAI reasoning summaries, Gemini thoughts) and fantasy reports
+// it through the reasoning callbacks; so does a reasoning_content field
+// on an OpenAI-compatible response.
+var inlineReasoningProviders = []catwalk.Type{
+	catwalk.TypeOpenAICompat,
+	catwalk.TypeOpenRouter,
+	catwalk.TypeVercel,
+	hyper.Name,
+}
+
+// reasoningAdapter moves reasoning out of the reply text of one step, for
+// providers that encode it inline.
+type reasoningAdapter interface {
+	// Text splits a text delta into the reasoning and reply text it
+	// carries. Text that may still turn out to be reasoning is held back.
+	Text(delta string) (reasoning, text string)
+	// Flush returns what is held back at the end of the text.
+	Flush() (reasoning, text string)
+	// Thinking reports whether the adapter is inside a reasoning block.
+	Thinking() bool
+}
+
+// newReasoningAdapter returns the reasoning adapter of a step on a
+// provider of providerType, or nil when the provider needs none.
+func newReasoningAdapter(providerType catwalk.Type, tags []string) reasoningAdapter {
+	if !slices.Contains(inlineReasoningProviders, providerType) || len(tags) == 0 {
+		return nil
+	}
+	return &inlineTagAdapter{tags: tags}
+}
+
+// inlineTagState is where an inlineTagAdapter is in the reply.
+type inlineTagState uint8
+
+const (
+	inlineTagOpening inlineTagState = iota // before the first non-space text
+	inlineTagInside                        // inside the reasoning block
+	inlineTagClosed                        // after the block, before the reply
+	inlineTagReply                         // in the reply
+)
+
+// inlineTagAdapter treats a reply that opens with <tag> as reasoning up to
+// the matching </tag>. Only a tag at the very start counts, so a reply
+// that merely mentions one is left alone.
+type inlineTagAdapter struct {
+	tags    []string
+	state   inlineTagState
+	closing string
+	pending string
+}
+
+func (t *inlineTagAdapter) Thinking() bool {
+	return t.state == inlineTagInside
+}
+
+func (t *inlineTagAdapter) Text(delta string) (reasoning, text string) {
+	t.pending += delta
+	for {
+		switch t.state {
+		case inlineTagOpening:
+			lead := strings.TrimLeft(t.pending, " \t\r\n")
+			if lead == "" {
+				return reasoning, text
+			}
+			undecided := false
+			for _, tag := range t.tags {
+				open := "<" + tag + ">"
+				if rest, ok := strings.CutPrefix(lead, open); ok {
+					t.state, t.closing, t.pending = inlineTagInside, "</"+tag+">", rest
+					break
+				}
+				if strings.HasPrefix(open, lead) {
+					undecided = true
+				}
+			}
+			if t.state == inlineTagOpening {
+				if undecided {
+					return reasoning, text
+				}
+				t.state = inlineTagReply
+			}
+		case inlineTagInside:
+			if before, after, ok := strings.Cut(t.pending, t.closing); ok {
+				reasoning += before
+				t.state, t.pending = inlineTagClosed, after
+				continue
+			}
+			// Hold back a tail that may be the start of the closing tag.
+			keep := 0
+			for n := min(len(t.closing)-1, len(t.pending)); n > 0; n-- {
+				if strings.HasSuffix(t.pending, t.closing[:n]) {
+					keep = n
+					break
+				}
+			}
+			reasoning += t.pending[:len(t.pending)-keep]
+			t.pending = t.pending[len(t.pending)-keep:]
+			return reasoning, text
+		case inlineTagClosed:
+			t.pending = strings.TrimLeft(t.pending, " \t\r\n")
+			if t.pending == "" {
+				return reasoning, text
+			}
+			t.state = inlineTagReply
+		case inlineTagReply:
+			text += t.pending
+			t.pending = ""
+			return reasoning, text
+		}
+	}
+}
+
+func (t *inlineTagAdapter) Flush() (reasoning, text string) {
+	pending := t.pending
+	t.pending = ""
+	switch t.state {
+	case inlineTagInside:
+		return pending, ""
+	case inlineTagClosed:
+		return "", ""
+	default:
+		return "", pending
+	}
+}
+
+// appendInlineReasoning adds reasoning an adapter took from the reply text
+// to msg, and closes the reasoning part once the adapter has left the
+// block.
+func appendInlineReasoning(msg *message.Message, reasoning string, thinking bool) {
+	if reasoning != "" {
+		msg.AppendReasoningContent(reasoning)
+	}
+	if !thinking {
+		msg.FinishThinking()
+	}
+}
+
+// reasoningTokens returns the reasoning tokens of a step: what the
+// provider reports, or an estimate from the reasoning text for providers
+// that do not count them separately.
+func reasoningTokens(reported int64, thinking string) int64 {
+	if reported > 0 {
+		return reported
+	}
+	return approxTokenCount(thinking)
+}
+
+// stripTurnReasoning drops the reasoning text of the assistant messages a
+// turn started at started created, when transcripts are configured to go
+// without it.
+func (a *sessionAgent) stripTurnReasoning(ctx context.Context, sessionID string, started time.Time) {
+	if a.reasoning == nil || !a.reasoning.StripFromTranscripts {
+		return
+	}
+	msgs, err := a.messages.List(ctx, sessionID)
+	if err != nil {
+		slog.Error("Failed to strip reasoning from the transcript", "error", err)
+		return
+	}
+	for _, msg := range msgs {
+		if msg.Role != message.Assistant || msg.CreatedAt < started.Unix() || !msg.StripReasoning() {
+			continue
+		}
+		if err := a.messages.Update(ctx, msg); err != nil {
+			slog.Error("Failed to strip reasoning from the transcript", "error", err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"testing"
+
+	"charm.land/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// feedAdapter streams deltas through a and returns the reasoning and reply
+// text it produced, flush included.
+func feedAdapter(a reasoningAdapter, deltas ...string) (reasoning, text string) {
+	for _, d := range deltas {
+		r, t := a.Text(d)
+		reasoning += r
+		text += t
+	}
+	r, t := a.Flush()
+	return reasoning + r, text + t
+}
+
+func TestNewReasoningAdapter(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newReasoningAdapter(catwalk.TypeAnthropic, config.DefaultReasoningTags))
+	require.Nil(t, newReasoningAdapter(catwalk.TypeOpenAICompat, nil))
+	require.NotNil(t, newReasoningAdapter(catwalk.TypeOpenAICompat, config.DefaultReasoningTags))
+}
+
+func TestInlineTagAdapter(t *testing.T) {
+	t.Parallel()
+
+	tags := config.DefaultReasoningTags
+	tests := []struct {
+		name      string
+		deltas    []string
+		reasoning string
+		text      string
+	}{
+		{
+			name:      "whole block",
+			deltas:    []string{"<think>plan it</think>\n\nThe answer."},
+			reasoning: "plan it",
+			text:      "The answer.",
+		},
+		{
+			name:      "tags split across deltas",
+			deltas:    []string{"\n<th", "ink>plan", " it</th", "ink", ">", "\n", "The answer."},
+			reasoning: "plan it",
+			text:      "The answer.",
+		},
+		{
+			name:      "second tag",
+			deltas:    []string{"<thinking>a < b</thinking>ok"},
+			reasoning: "a < b",
+			text:      "ok",
+		},
+		{
+			name:   "tag later in the reply is kept",
+			deltas: []string{"Use ", "<think> tags"},
+			text:   "Use <think> tags",
+		},
+		{
+			name:   "lookalike opening",
+			deltas: []string{"<th", "is>"},
+			text:   "<this>",
+		},
+		{
+			name:      "unclosed block at the end",
+			deltas:    []string{"<think>still going </thi"},
+			reasoning: "still going </thi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			reasoning, text := feedAdapter(newReasoningAdapter(catwalk.TypeOpenAICompat, tags), tt.deltas...)
+			require.Equal(t, tt.reasoning, reasoning)
+			require.Equal(t, tt.text, text)
+		})
+	}
+}
+
+func TestInlineTagAdapterThinking(t *testing.T) {
+	t.Parallel()
+
+	a := newReasoningAdapter(catwalk.TypeOpenAICompat, []string{"think"})
+	require.False(t, a.Thinking())
+	_, _ = a.Text("<think>hmm")
+	require.True(t, a.Thinking())
+	_, _ = a.Text("</think>done")
+	require.False(t, a.Thinking())
+}
+
+func TestReasoningTokens(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, int64(120), reasoningTokens(120, "short"))
+	require.Equal(t, int64(3), reasoningTokens(0, "twelve chars"))
+	require.Zero(t, reasoningTokens(0, ""))
+}
@@ -0,0 +1,300 @@
+package agent
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"charm.land/fantasy"
+	"charm.land/fantasy/schema"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/message"
+)
+
+//go:embed templates/sampling_judge.md
+var samplingJudgePrompt []byte
+
+// samplingJudgeMaxTokens caps the judge's reply, which is a short JSON
+// object.
+const samplingJudgeMaxTokens = 1024
+
+// SamplerModel is a model extra candidates can be drawn from, with the
+// provider options of its provider.
+type SamplerModel struct {
+	Model           Model
+	ProviderOptions fantasy.ProviderOptions
+}
+
+// Sampler draws extra final answers on high-stakes turns and lets the
+// small model pick the best one. Tool calls of the turn run once; only
+// the final answer is sampled, from the prompt of the turn's last step.
+type Sampler struct {
+	// Candidates is the number of candidates, the turn's own answer
+	// included.
+	Candidates int
+	// Temperatures are used in turn by the extra candidates; without any
+	// they use the turn's temperature.
+	Temperatures []float64
+	// Models are used in order by the extra candidates; the rest use the
+	// turn's model.
+	Models        []SamplerModel
+	Criteria      string
+	MinComplexity ComplexityLevel
+	Keywords      []string
+	// RecordCost, when set, is told about every sampling request.
+	RecordCost func(model string, tokensIn, tokensOut int, cost float64)
+}
+
+// NewSampler returns the sampler configured by opts, or nil when sampling
+// is disabled. models are the resolved opts.Models; the ones that could
+// not be resolved are left out.
+func NewSampler(opts *config.SamplingOptions, models []SamplerModel) *Sampler {
+	if opts == nil || !opts.Enabled {
+		return nil
+	}
+	s := &Sampler{
+		Candidates:    opts.CandidateCount(),
+		Temperatures:  opts.Temperatures,
+		Models:        models,
+		Criteria:      opts.Criteria,
+		MinComplexity: parseComplexity(opts.MinComplexity),
+		Keywords:      opts.Keywords,
+	}
+	if len(s.Temperatures) == 0 && len(s.Models) == 0 {
+		s.Temperatures = config.DefaultSamplingTemperatures
+	}
+	return s
+}
+
+func parseComplexity(s string) ComplexityLevel {
+	switch strings.ToLower(s) {
+	case "simple":
+		return ComplexitySimple
+	case "medium":
+		return ComplexityMedium
+	default:
+		return ComplexityComplex
+	}
+}
+
+// HighStakes reports whether a turn is worth sampling: its prompt names
+// one of the keywords or the session is at least MinComplexity.
+func (s *Sampler) HighStakes(prompt string, msgs []message.Message) bool {
+	lower := strings.ToLower(prompt)
+	for _, kw := range s.Keywords {
+		if kw != "" && strings.Contains(lower, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	return ClassifyComplexity(msgs) >= s.MinComplexity
+}
+
+// samplingSpec is how one extra candidate is drawn.
+type samplingSpec struct {
+	model       SamplerModel
+	temperature *float64
+}
+
+// specs returns how the extra candidates of a turn on model are drawn.
+func (s *Sampler) specs(model SamplerModel, temperature *float64) []samplingSpec {
+	specs := make([]samplingSpec, 0, s.Candidates-1)
+	for i := range s.Candidates - 1 {
+		spec := samplingSpec{model: model, temperature: temperature}
+		if i < len(s.Models) {
+			spec.model = s.Models[i]
+		}
+		if len(s.Temperatures) > 0 {
+			t := s.Temperatures[i%len(s.Temperatures)]
+			spec.temperature = &t
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// judgeVerdict is the judge's reply.
+type judgeVerdict struct {
+	Best   int                `json:"best"`
+	Scores map[string]float64 `json:"scores"`
+	Reason string             `json:"reason"`
+}
+
+// judgePrompt asks the judge to rank the candidates for request.
+func (s *Sampler) judgePrompt(request string, candidates []message.ResponseCandidate) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<request>\n%s\n</request>\n\n", request)
+	if s.Criteria != "" {
+		fmt.Fprintf(&b, "<criteria>\n%s\n</criteria>\n\n", s.Criteria)
+	}
+	for i, c := range candidates {
+		fmt.Fprintf(&b, "<candidate number=\"%d\">\n%s\n</candidate>\n\n", i+1, c.Text)
+	}
+	fmt.Fprintf(&b, "Which of the %d candidates answers the request best?", len(candidates))
+	return b.String()
+}
+
+// parseVerdict reads the judge's reply and scores the candidates. It
+// returns the chosen index, or false when the reply is unusable.
+func parseVerdict(reply string, candidates []message.ResponseCandidate) (int, string, bool) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return 0, "", false
+	}
+	var v judgeVerdict
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &v); err != nil {
+		return 0, "", false
+	}
+	if v.Best < 1 || v.Best > len(candidates) {
+		return 0, "", false
+	}
+	for k, score := range v.Scores {
+		if n, err := strconv.Atoi(k); err == nil && n >= 1 && n <= len(candidates) {
+			candidates[n-1].Score = score
+		}
+	}
+	return v.Best - 1, v.Reason, true
+}
+
+// toolDefinitions describes tools to the model without offering them;
+// some providers reject a history with tool calls but no tools.
+func toolDefinitions(agentTools []fantasy.AgentTool) []fantasy.Tool {
+	defs := make([]fantasy.Tool, 0, len(agentTools))
+	for _, tool := range agentTools {
+		info := tool.Info()
+		inputSchema := map[string]any{
+			"type":       "object",
+			"properties": info.Parameters,
+			"required":   info.Required,
+		}
+		schema.Normalize(inputSchema)
+		defs = append(defs, fantasy.FunctionTool{
+			Name:        info.Name,
+			Description: info.Description,
+			InputSchema: inputSchema,
+		})
+	}
+	return defs
+}
+
+// usageCharge returns what usage on model cost, zero for flat-rate models.
+func usageCharge(model Model, usage fantasy.Usage) float64 {
+	if model.FlatRate {
+		return 0
+	}
+	return computeUsageCost(model, usage)
+}
+
+// sampleTurn draws the extra candidates of a finished turn, has the small
+// model rank them and makes the best one the reply. prompt is the last
+// step's prompt. Sampling failures keep the turn's own answer. Its cost
+// is added to the session.
+func (a *sessionAgent) sampleTurn(ctx context.Context, call SessionAgentCall, turn SamplerModel, prompt []fantasy.Message, agentTools []fantasy.AgentTool, assistant *message.Message) {
+	s := a.sampler
+	answer := assistant.Content().Text
+	if s == nil || answer == "" || len(assistant.ToolCalls()) > 0 {
+		return
+	}
+	msgs, err := a.messages.List(ctx, call.SessionID)
+	if err != nil || !s.HighStakes(call.Prompt, msgs) {
+		return
+	}
+
+	candidates := []message.ResponseCandidate{{
+		Text:        answer,
+		Model:       turn.Model.ModelCfg.Model,
+		Provider:    turn.Model.ModelCfg.Provider,
+		Temperature: call.Temperature,
+	}}
+	var maxOutputTokens *int64
+	if call.MaxOutputTokens > 0 {
+		maxOutputTokens = &call.MaxOutputTokens
+	}
+	toolChoice := fantasy.ToolChoiceNone
+	defs := toolDefinitions(agentTools)
+	for _, spec := range s.specs(turn, call.Temperature) {
+		fc := fantasy.Call{
+			Prompt:          prompt,
+			MaxOutputTokens: maxOutputTokens,
+			Temperature:     spec.temperature,
+			UserAgent:       userAgent,
+			ProviderOptions: spec.model.ProviderOptions,
+		}
+		if len(defs) > 0 {
+			fc.Tools = defs
+			fc.ToolChoice = &toolChoice
+		}
+		resp, genErr := spec.model.Model.Model.Generate(ctx, fc)
+		if genErr != nil {
+			slog.Warn("Failed to sample candidate", "model", spec.model.Model.ModelCfg.Model, "error", genErr)
+			continue
+		}
+		text := strings.TrimSpace(resp.Content.Text())
+		cost := a.chargeSampling(spec.model.Model, resp.Usage)
+		if text == "" {
+			continue
+		}
+		candidates = append(candidates, message.ResponseCandidate{
+			Text:        text,
+			Model:       spec.model.Model.ModelCfg.Model,
+			Provider:    spec.model.Model.ModelCfg.Provider,
+			Temperature: spec.temperature,
+			Cost:        cost,
+		})
+	}
+	if len(candidates) < 2 {
+		return
+	}
+
+	result := message.ResponseCandidates{Candidates: candidates}
+	judge := a.smallModel.Get()
+	if judge.Model != nil {
+		maxTokens := int64(samplingJudgeMaxTokens)
+		resp, judgeErr := judge.Model.Generate(ctx, fantasy.Call{
+			Prompt: fantasy.Prompt{
+				fantasy.NewSystemMessage(string(samplingJudgePrompt)),
+				fantasy.NewUserMessage(s.judgePrompt(call.Prompt, candidates)),
+			},
+			MaxOutputTokens: &maxTokens,
+			UserAgent:       userAgent,
+		})
+		if judgeErr != nil {
+			slog.Warn("Failed to judge sampled candidates", "model", judge.ModelCfg.Model, "error", judgeErr)
+		} else {
+			result.JudgeCost = a.chargeSampling(judge, resp.Usage)
+			if chosen, reason, ok := parseVerdict(resp.Content.Text(), result.Candidates); ok {
+				result.Chosen = chosen
+				result.Judge = judge.ModelCfg.Model
+				result.Reason = reason
+			} else {
+				slog.Warn("Judge reply could not be parsed, keeping the turn's answer", "model", judge.ModelCfg.Model)
+			}
+		}
+	}
+	assistant.SetResponseCandidates(result)
+
+	if cost := result.Cost(); cost > 0 {
+		sess, getErr := a.sessions.Get(ctx, call.SessionID)
+		if getErr != nil {
+			slog.Error("Failed to account sampling cost", "error", getErr)
+			return
+		}
+		sess.Cost += cost
+		if _, saveErr := a.sessions.Save(ctx, sess); saveErr != nil {
+			slog.Error("Failed to account sampling cost", "error", saveErr)
+		}
+	}
+}
+
+// chargeSampling reports a sampling request to the cost tracker and
+// returns its cost.
+func (a *sessionAgent) chargeSampling(model Model, usage fantasy.Usage) float64 {
+	cost := usageCharge(model, usage)
+	if a.sampler.RecordCost != nil {
+		a.sampler.RecordCost(model.ModelCfg.Model, int(usage.InputTokens), int(usage.OutputTokens), cost)
+	}
+	return cost
+}
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSampler(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewSampler(nil, nil))
+	require.Nil(t, NewSampler(&config.SamplingOptions{Candidates: 4}, nil))
+
+	s := NewSampler(&config.SamplingOptions{Enabled: true, MinComplexity: "medium"}, nil)
+	require.NotNil(t, s)
+	require.Equal(t, config.DefaultSamplingCandidates, s.Candidates)
+	require.Equal(t, config.DefaultSamplingTemperatures, s.Temperatures)
+	require.Equal(t, ComplexityMedium, s.MinComplexity)
+
+	s = NewSampler(&config.SamplingOptions{Enabled: true, Candidates: 20}, []SamplerModel{{}})
+	require.Equal(t, 8, s.Candidates)
+	require.Empty(t, s.Temperatures, "models alone spread the candidates")
+	require.Equal(t, ComplexityComplex, s.MinComplexity)
+}
+
+func TestSamplerSpecs(t *testing.T) {
+	t.Parallel()
+
+	turn := SamplerModel{Model: Model{ModelCfg: config.SelectedModel{Model: "large"}}}
+	other := SamplerModel{Model: Model{ModelCfg: config.SelectedModel{Model: "other"}}}
+	s := &Sampler{Candidates: 4, Temperatures: []float64{0.2, 1}, Models: []SamplerModel{other}}
+
+	specs := s.specs(turn, nil)
+	require.Len(t, specs, 3)
+	require.Equal(t, "other", specs[0].model.Model.ModelCfg.Model)
+	require.Equal(t, "large", specs[1].model.Model.ModelCfg.Model)
+	require.Equal(t, "large", specs[2].model.Model.ModelCfg.Model)
+	require.InDelta(t, 0.2, *specs[0].temperature, 1e-9)
+	require.InDelta(t, 1, *specs[1].temperature, 1e-9)
+	require.InDelta(t, 0.2, *specs[2].temperature, 1e-9)
+
+	turnTemp := 0.5
+	s = &Sampler{Candidates: 2}
+	specs = s.specs(turn, &turnTemp)
+	require.Len(t, specs, 1)
+	require.Equal(t, &turnTemp, specs[0].temperature)
+}
+
+func TestSamplerHighStakes(t *testing.T) {
+	t.Parallel()
+
+	s := &Sampler{MinComplexity: ComplexityComplex, Keywords: []string{"Production"}}
+	require.False(t, s.HighStakes("rename this variable", nil))
+	require.True(t, s.HighStakes("fix the production outage", nil))
+
+	s.MinComplexity = ComplexitySimple
+	require.True(t, s.HighStakes("rename this variable", nil))
+}
+
+func TestParseVerdict(t *testing.T) {
+	t.Parallel()
+
+	candidates := func() []message.ResponseCandidate {
+		return []message.ResponseCandidate{{Text: "a"}, {Text: "b"}, {Text: "c"}}
+	}
+
+	got := candidates()
+	chosen, reason, ok := parseVerdict("Here you go:\n```json\n{\"best\": 2, \"scores\": {\"1\": 4, \"2\": 9, \"7\": 3}, \"reason\": \"handles the edge case\"}\n```", got)
+	require.True(t, ok)
+	require.Equal(t, 1, chosen)
+	require.Equal(t, "handles the edge case", reason)
+	require.InDelta(t, 4, got[0].Score, 1e-9)
+	require.InDelta(t, 9, got[1].Score, 1e-9)
+	require.Zero(t, got[2].Score)
+
+	for _, reply := range []string{"", "candidate 2", `{"best": 4}`, `{"best": 0}`, `{"best": "two"}`} {
+		_, _, ok := parseVerdict(reply, candidates())
+		require.False(t, ok, reply)
+	}
+}
+
+func TestSamplerJudgePrompt(t *testing.T) {
+	t.Parallel()
+
+	s := &Sampler{Criteria: "minimal diffs"}
+	prompt := s.judgePrompt("fix the bug", []message.ResponseCandidate{{Text: "first"}, {Text: "second"}})
+	require.Contains(t, prompt, "<request>\nfix the bug\n</request>")
+	require.Contains(t, prompt, "<criteria>\nminimal diffs\n</criteria>")
+	require.Contains(t, prompt, "<candidate number=\"2\">\nsecond\n</candidate>")
+	require.Contains(t, prompt, "Which of the 2 candidates")
+}
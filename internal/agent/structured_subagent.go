@@ -9,6 +9,8 @@ import (
 	"charm.land/fantasy"
 	"github.com/charmbracelet/crush/internal/agent/prompt"
 	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/staging"
 )
 
 const (
@@ -42,6 +44,23 @@ func WithMetricsStore(store *MetricsStore) CoordinatorOption {
 	}
 }
 
+// WithStaging wires the staging area that receives file edits for review
+// when Options.ReviewEdits is enabled.
+func WithStaging(svc staging.Service) CoordinatorOption {
+	return func(c *coordinator) {
+		c.staging = svc
+	}
+}
+
+// WithSessionLocks wires the session locks held for the duration of each
+// run. A run whose lock is taken over by another process is cancelled.
+func WithSessionLocks(locks *session.Locks) CoordinatorOption {
+	return func(c *coordinator) {
+		c.locks = locks
+		locks.OnLost(c.Cancel)
+	}
+}
+
 // WithTierRouter wires a TierRouter for fallback-chain resolution during
 // LLM retries. When set, Run and runSubAgent wrap their calls with
 // ExecuteWithFallback.
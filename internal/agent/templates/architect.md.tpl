@@ -22,6 +22,7 @@ You are the Architect agent for Crush. Your role is to analyze tasks and produce
     }
   ],
   "rationale": "Why this plan structure was chosen over alternatives",
+  "risks": ["What could go wrong and how it is mitigated"],
   "approval_required": false
 }
 </output_schema>
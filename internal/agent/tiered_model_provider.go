@@ -177,10 +177,11 @@ func (b coordinatorModelBuilder) BuildModel(ctx context.Context, providerCfg con
 	}
 
 	return Model{
-		Model:      lm,
-		CatwalkCfg: *catwalkModel,
-		ModelCfg:   sel,
-		FlatRate:   providerCfg.FlatRate,
+		Model:        lm,
+		CatwalkCfg:   *catwalkModel,
+		ModelCfg:     sel,
+		FlatRate:     providerCfg.FlatRate,
+		ProviderType: providerCfg.Type,
 	}, nil
 }
 
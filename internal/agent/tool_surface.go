@@ -174,6 +174,16 @@ func (s *ToolSurface) registerDefaults() {
 
 	s.Register("crush_info", CapabilityObservation)
 	s.Register("crush_logs", CapabilityObservation)
+	s.Register("duplicate_code", CapabilityObservation)
+	s.Register("feature_flags", CapabilityObservation)
+	s.Register("knowledge_lookup", CapabilityObservation)
+	s.Register("licenses", CapabilityObservation)
+	s.Register("ports", CapabilityObservation)
+	s.Register("processes", CapabilityObservation)
+	s.Register("schema_lookup", CapabilityObservation)
+	s.Register("system_resources", CapabilityObservation)
+	s.Register("tail_logs", CapabilityObservation)
+	s.Register("tech_debt", CapabilityObservation)
 	s.Register("todos", CapabilityObservation)
 	s.Register("list_mcp_resources", CapabilityNetwork|CapabilityObservation)
 	s.Register("read_mcp_resource", CapabilityNetwork|CapabilityObservation)
@@ -14,8 +14,10 @@ import (
 
 	"charm.land/fantasy"
 	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/devenv"
 	"github.com/charmbracelet/crush/internal/fsext"
 	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/remote"
 	"github.com/charmbracelet/crush/internal/shell"
 )
 
@@ -67,6 +69,12 @@ type bashDescriptionData struct {
 	Attribution     config.Attribution
 	ModelID         string
 	RgAvailable     bool
+	Remote          string
+	RemoteSync      bool
+	DevEnv          string
+	DevEnvFile      string
+	DevEnvActive    bool
+	DevEnvExample   string
 }
 
 var bannedCommands = []string{
@@ -142,16 +150,27 @@ var bannedCommands = []string{
 	"ufw",
 }
 
-func bashDescription(attribution *config.Attribution, modelID string) string {
+func bashDescription(attribution *config.Attribution, modelID string, target *remote.Target, env *devenv.Env) string {
 	bannedCommandsStr := strings.Join(bannedCommands, ", ")
 	var out bytes.Buffer
-	if err := bashDescriptionTpl.Execute(&out, bashDescriptionData{
+	data := bashDescriptionData{
 		BannedCommands:  bannedCommandsStr,
 		MaxOutputLength: MaxOutputLength,
 		Attribution:     *attribution,
 		ModelID:         modelID,
 		RgAvailable:     getRg() != "",
-	}); err != nil {
+		RemoteSync:      target.Sync(),
+	}
+	if target != nil {
+		data.Remote = target.String()
+	}
+	if env != nil && target == nil {
+		data.DevEnv = env.String()
+		data.DevEnvFile = env.File()
+		data.DevEnvActive = env.Active()
+		data.DevEnvExample = env.Example("make test")
+	}
+	if err := bashDescriptionTpl.Execute(&out, data); err != nil {
 		// this should never happen.
 		panic("failed to execute bash description template: " + err.Error())
 	}
@@ -190,10 +209,13 @@ func blockFuncs() []shell.BlockFunc {
 	}
 }
 
-func NewBashTool(permissions permission.Service, workingDir string, attribution *config.Attribution, modelID string) fantasy.AgentTool {
+// NewBashTool returns the bash tool. Commands run on target when it is
+// non-nil, and otherwise inside env when it is active. An inactive env is
+// only described to the agent.
+func NewBashTool(permissions permission.Service, workingDir string, attribution *config.Attribution, modelID string, target *remote.Target, env *devenv.Env) fantasy.AgentTool {
 	return fantasy.NewAgentTool(
 		BashToolName,
-		string(bashDescription(attribution, modelID)),
+		string(bashDescription(attribution, modelID, target, env)),
 		func(ctx context.Context, params BashParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
 			if params.Command == "" {
 				return fantasy.NewTextErrorResponse("missing command"), nil
@@ -201,6 +223,9 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 
 			// Determine working directory
 			execWorkingDir := cmp.Or(params.WorkingDir, workingDir)
+			if target != nil {
+				execWorkingDir = target.LocalPath(execWorkingDir)
+			}
 
 			isSafeReadOnly := false
 			cmdLower := strings.ToLower(params.Command)
@@ -241,13 +266,23 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 				}
 			}
 
+			// On a remote target the command runs in the matching remote
+			// directory.
+			var runner shell.Remote
+			if target != nil {
+				runner = target
+				execWorkingDir = target.RemotePath(execWorkingDir)
+			} else if env.Active() {
+				runner = env
+			}
+
 			// If explicitly requested as background, start immediately with detached context
 			if params.RunInBackground {
 				startTime := time.Now()
 				bgManager := shell.GetBackgroundShellManager()
 				bgManager.Cleanup()
 				// Use background context so it continues after tool returns
-				bgShell, err := bgManager.Start(context.Background(), execWorkingDir, blockFuncs(), params.Command, params.Description)
+				bgShell, err := bgManager.StartRemote(context.Background(), runner, execWorkingDir, blockFuncs(), params.Command, params.Description)
 				if err != nil {
 					return fantasy.ToolResponse{}, fmt.Errorf("error starting background shell: %w", err)
 				}
@@ -302,7 +337,7 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 			// Start with detached context so it can survive if moved to background
 			bgManager := shell.GetBackgroundShellManager()
 			bgManager.Cleanup()
-			bgShell, err := bgManager.Start(context.Background(), execWorkingDir, blockFuncs(), params.Command, params.Description)
+			bgShell, err := bgManager.StartRemote(context.Background(), runner, execWorkingDir, blockFuncs(), params.Command, params.Description)
 			if err != nil {
 				return fantasy.ToolResponse{}, fmt.Errorf("error starting shell: %w", err)
 			}
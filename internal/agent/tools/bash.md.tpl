@@ -5,6 +5,24 @@ Uses mvdan/sh interpreter (Bash-compatible on all platforms including Windows).
 Use forward slashes for paths: "ls C:/foo/bar" not "ls C:\foo\bar".
 Common shell builtins and core utils available on Windows.
 </cross_platform>
+{{- if .Remote }}
+
+<remote_target>
+Commands run with bash on {{ .Remote }}, not on this machine. Write command names out: a command named by a variable or substitution, such as $CC, is refused.
+Paths under the working directory map to the project root there, and working_dir may be given either way.
+View, edit and write work on the local files{{ if .RemoteSync }}; files written by edit and write are copied to the target{{ end }}.
+</remote_target>
+{{- end }}
+{{- if .DevEnv }}
+
+<dev_env>
+{{- if .DevEnvActive }}
+Commands run inside the project's development environment ({{ .DevEnv }}), declared in {{ .DevEnvFile }}, so they use the project's toolchain. Do not wrap them in direnv, nix develop or nix-shell yourself. Write command names out: a command named by a variable or substitution, such as $CC, is refused.
+{{- else }}
+The project declares a development environment in {{ .DevEnvFile }}, but commands run outside it. When a build or test fails for lack of a tool or for the wrong tool version, run it inside the environment, for example `{{ .DevEnvExample }}` from the project root, and suggest setting options.dev_env to "auto" so every command does.
+{{- end }}
+</dev_env>
+{{- end }}
 
 <execution_steps>
 1. Directory Verification: If creating directories/files, use LS tool to verify parent exists
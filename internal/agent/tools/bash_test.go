@@ -3,12 +3,17 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"charm.land/fantasy"
 	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/devenv"
 	"github.com/charmbracelet/crush/internal/permission"
 	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/remote"
 	"github.com/charmbracelet/crush/internal/shell"
 	"github.com/stretchr/testify/require"
 )
@@ -115,7 +120,7 @@ func (m *recordingPermissionService) SubscribeNotifications(ctx context.Context)
 func newBashToolForTest(workingDir string) fantasy.AgentTool {
 	permissions := &mockBashPermissionService{Broker: pubsub.NewBroker[permission.PermissionRequest]()}
 	attribution := &config.Attribution{TrailerStyle: config.TrailerStyleNone}
-	return NewBashTool(permissions, workingDir, attribution, "test-model")
+	return NewBashTool(permissions, workingDir, attribution, "test-model", nil, nil)
 }
 
 func newBashToolWithRecordingPerms(workingDir string, allow bool) (fantasy.AgentTool, *recordingPermissionService) {
@@ -124,7 +129,7 @@ func newBashToolWithRecordingPerms(workingDir string, allow bool) (fantasy.Agent
 		allow:  allow,
 	}
 	attribution := &config.Attribution{TrailerStyle: config.TrailerStyleNone}
-	return NewBashTool(perms, workingDir, attribution, "test-model"), perms
+	return NewBashTool(perms, workingDir, attribution, "test-model", nil, nil), perms
 }
 
 func TestBashTool_ChainedCommandsRequirePermission(t *testing.T) {
@@ -182,3 +187,65 @@ func runBashTool(t *testing.T, tool fantasy.AgentTool, ctx context.Context, para
 	require.NoError(t, err)
 	return resp
 }
+
+func TestBashTool_RemoteTarget(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh is a shell script")
+	}
+	// The fake ssh runs its command locally, the way the remote login
+	// shell would.
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "ssh"), []byte("#!/bin/sh\nfor last; do :; done\nexec sh -c \"$last\"\n"), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workingDir, remoteRoot := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(remoteRoot, "marker"), []byte("remote\n"), 0o644))
+	target, err := remote.New(&config.RemoteOptions{Type: config.RemoteTypeSSH, Host: "devbox", Path: remoteRoot}, workingDir)
+	require.NoError(t, err)
+
+	permissions := &mockBashPermissionService{Broker: pubsub.NewBroker[permission.PermissionRequest]()}
+	tool := NewBashTool(permissions, workingDir, &config.Attribution{TrailerStyle: config.TrailerStyleNone}, "test-model", target, nil)
+	require.Contains(t, tool.Info().Description, "Commands run with bash on ssh devbox:"+remoteRoot)
+
+	ctx := context.WithValue(context.Background(), SessionIDContextKey, "test-session")
+	resp := runBashTool(t, tool, ctx, BashParams{Description: "remote", Command: "cat marker && echo $AGENT"})
+	require.False(t, resp.IsError)
+	require.Contains(t, resp.Content, "remote\ncrush")
+	require.Contains(t, resp.Content, "<cwd>"+remoteRoot+"</cwd>")
+
+	resp = runBashTool(t, tool, ctx, BashParams{Description: "blocked", Command: "curl https://example.com"})
+	require.Contains(t, resp.Content, "not allowed for security reasons")
+}
+
+func TestBashTool_DevEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake direnv is a shell script")
+	}
+	// The fake direnv runs its command with a marker of the environment.
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "direnv"), []byte("#!/bin/sh\nshift 2\nIN_DEVENV=1 exec \"$@\"\n"), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	workingDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, ".envrc"), []byte("use flake\n"), 0o644))
+	attribution := &config.Attribution{TrailerStyle: config.TrailerStyleNone}
+	permissions := &mockBashPermissionService{Broker: pubsub.NewBroker[permission.PermissionRequest]()}
+	ctx := context.WithValue(context.Background(), SessionIDContextKey, "test-session")
+
+	// Unset, the environment is only offered.
+	env, err := devenv.New("", workingDir)
+	require.NoError(t, err)
+	tool := NewBashTool(permissions, workingDir, attribution, "test-model", nil, env)
+	require.Contains(t, tool.Info().Description, "but commands run outside it")
+	require.Contains(t, tool.Info().Description, "direnv exec . make test")
+	resp := runBashTool(t, tool, ctx, BashParams{Description: "outside", Command: "echo in=$IN_DEVENV"})
+	require.Contains(t, resp.Content, "in=\n")
+
+	env, err = devenv.New(config.DevEnvAuto, workingDir)
+	require.NoError(t, err)
+	tool = NewBashTool(permissions, workingDir, attribution, "test-model", nil, env)
+	require.Contains(t, tool.Info().Description, "Commands run inside the project's development environment (direnv exec "+workingDir+")")
+	resp = runBashTool(t, tool, ctx, BashParams{Description: "inside", Command: "echo in=$IN_DEVENV"})
+	require.False(t, resp.IsError)
+	require.Contains(t, resp.Content, "in=1\n")
+}
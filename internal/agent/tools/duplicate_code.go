@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/dupes"
+)
+
+const DuplicateCodeToolName = "duplicate_code"
+
+// defaultDuplicateCodeLimit is the number of clusters listed when the call
+// sets no limit.
+const defaultDuplicateCodeLimit = 20
+
+//go:embed duplicate_code.md
+var duplicateCodeDescription string
+
+type DuplicateCodeParams struct {
+	Path              string `json:"path,omitempty" description:"Directory or part of a path to restrict the comparison to"`
+	MinTokens         int    `json:"min_tokens,omitempty" description:"Shortest duplicated run to report, in tokens (default 50)"`
+	IgnoreIdentifiers bool   `json:"ignore_identifiers,omitempty" description:"Also match copies that renamed variables and functions"`
+	Limit             int    `json:"limit,omitempty" description:"Maximum clusters listed (default 20)"`
+}
+
+func NewDuplicateCodeTool(workingDir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		DuplicateCodeToolName,
+		duplicateCodeDescription,
+		func(ctx context.Context, params DuplicateCodeParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			res, err := dupes.Detect(ctx, workingDir, dupes.Options{
+				Path:              params.Path,
+				MinTokens:         params.MinTokens,
+				IgnoreIdentifiers: params.IgnoreIdentifiers,
+			})
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			return fantasy.NewTextResponse(res.Report(cmp.Or(params.Limit, defaultDuplicateCodeLimit))), nil
+		},
+	)
+}
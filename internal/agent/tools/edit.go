@@ -19,6 +19,8 @@ import (
 
 	"github.com/charmbracelet/crush/internal/lsp"
 	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/remote"
+	"github.com/charmbracelet/crush/internal/staging"
 )
 
 type EditParams struct {
@@ -57,6 +59,9 @@ type editContext struct {
 	files       history.Service
 	filetracker filetracker.Service
 	workingDir  string
+	// staging, when non-nil, receives edits for review instead of the
+	// file being written directly.
+	staging staging.Service
 }
 
 func NewEditTool(
@@ -65,6 +70,8 @@ func NewEditTool(
 	files history.Service,
 	filetracker filetracker.Service,
 	workingDir string,
+	stager staging.Service,
+	target *remote.Target,
 ) fantasy.AgentTool {
 	return fantasy.NewAgentTool(
 		EditToolName,
@@ -74,12 +81,12 @@ func NewEditTool(
 				return fantasy.NewTextErrorResponse("file_path is required"), nil
 			}
 
-			params.FilePath = filepathext.SmartJoin(workingDir, params.FilePath)
+			params.FilePath = filepathext.SmartJoin(workingDir, target.LocalPath(params.FilePath))
 
 			var response fantasy.ToolResponse
 			var err error
 
-			editCtx := editContext{ctx, permissions, files, filetracker, workingDir}
+			editCtx := editContext{ctx, permissions, files, filetracker, workingDir, stager}
 
 			if params.OldString == "" {
 				response, err = createNewFile(editCtx, params.FilePath, params.NewString, call)
@@ -102,6 +109,9 @@ func NewEditTool(
 
 			text := fmt.Sprintf("<result>\n%s\n</result>\n", response.Content)
 			text += getDiagnostics(params.FilePath, lspManager)
+			if stager == nil {
+				text += pushToRemote(ctx, target, params.FilePath)
+			}
 			text += runDiagnosticCascade(ctx, lspManager, params.FilePath)
 			response.Content = text
 			return response, nil
@@ -165,6 +175,14 @@ func createNewFile(edit editContext, filePath, content string, call fantasy.Tool
 		return resp, nil
 	}
 
+	if edit.staging != nil {
+		return stageEdit(edit.staging, sessionID, filePath, "", content, true, EditResponseMetadata{
+			NewContent: content,
+			Additions:  additions,
+			Removals:   removals,
+		})
+	}
+
 	err = os.WriteFile(filePath, []byte(content), 0o644)
 	if err != nil {
 		return fantasy.ToolResponse{}, fmt.Errorf("failed to write file: %w", err)
@@ -232,7 +250,7 @@ func deleteContent(edit editContext, filePath, oldString string, replaceAll bool
 		), nil
 	}
 
-	content, err := os.ReadFile(filePath)
+	content, err := readEditTarget(edit.staging, sessionID, filePath)
 	if err != nil {
 		return fantasy.ToolResponse{}, fmt.Errorf("failed to read file: %w", err)
 	}
@@ -296,6 +314,15 @@ func deleteContent(edit editContext, filePath, oldString string, replaceAll bool
 		return resp, nil
 	}
 
+	if edit.staging != nil {
+		return stageEdit(edit.staging, sessionID, filePath, oldContent, newContent, false, EditResponseMetadata{
+			OldContent: oldContent,
+			NewContent: newContent,
+			Additions:  additions,
+			Removals:   removals,
+		})
+	}
+
 	if isCrlf {
 		newContent, _ = fsext.ToWindowsLineEndings(newContent)
 	}
@@ -375,7 +402,7 @@ func replaceContent(edit editContext, filePath, oldString, newString string, rep
 		), nil
 	}
 
-	content, err := os.ReadFile(filePath)
+	content, err := readEditTarget(edit.staging, sessionID, filePath)
 	if err != nil {
 		return fantasy.ToolResponse{}, fmt.Errorf("failed to read file: %w", err)
 	}
@@ -442,6 +469,15 @@ func replaceContent(edit editContext, filePath, oldString, newString string, rep
 		return resp, nil
 	}
 
+	if edit.staging != nil {
+		return stageEdit(edit.staging, sessionID, filePath, oldContent, newContent, false, EditResponseMetadata{
+			OldContent: oldContent,
+			NewContent: newContent,
+			Additions:  additions,
+			Removals:   removals,
+		})
+	}
+
 	if isCrlf {
 		newContent, _ = fsext.ToWindowsLineEndings(newContent)
 	}
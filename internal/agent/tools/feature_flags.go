@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/featureflags"
+)
+
+const FeatureFlagsToolName = "feature_flags"
+
+//go:embed feature_flags.md
+var featureFlagsDescription string
+
+type FeatureFlagsParams struct {
+	Flag string `json:"flag,omitempty" description:"Flag name or part of one to list the checks of; omit to list every flag"`
+}
+
+func NewFeatureFlagsTool(workingDir string, patterns []string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		FeatureFlagsToolName,
+		featureFlagsDescription,
+		func(ctx context.Context, params FeatureFlagsParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			ix, err := featureflags.Load(ctx, workingDir, patterns)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			var out string
+			if params.Flag == "" {
+				out, err = ix.List()
+			} else {
+				out, err = ix.WhereUsed(params.Flag)
+			}
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			return fantasy.NewTextResponse(out), nil
+		},
+	)
+}
@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/knowledge"
+)
+
+const KnowledgeLookupToolName = "knowledge_lookup"
+
+//go:embed knowledge_lookup.md
+var knowledgeLookupDescription string
+
+type KnowledgeLookupParams struct {
+	Query string `json:"query,omitempty" description:"What to look for in the knowledge base"`
+	Path  string `json:"path,omitempty" description:"Path of a knowledge document to read, relative to the knowledge folder"`
+}
+
+func NewKnowledgeLookupTool(base *knowledge.Base) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		KnowledgeLookupToolName,
+		knowledgeLookupDescription,
+		func(ctx context.Context, params KnowledgeLookupParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			out, err := base.Lookup(ctx, params.Query, params.Path)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			return fantasy.NewTextResponse(out), nil
+		},
+	)
+}
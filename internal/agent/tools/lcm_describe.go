@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
 )
 
 var errLCMAccessDenied = fmt.Errorf("lcm access denied")
@@ -14,11 +16,62 @@ var errLCMAccessDenied = fmt.Errorf("lcm access denied")
 const (
 	LcmDescribeToolName       = "lcm_describe"
 	maxDescribeContentPreview = 2000
+	defaultDescribeLogLimit   = 50
 	lcmMissingSessionIDError  = "Session ID not found in context"
 )
 
 type LcmDescribeParams struct {
-	ID string `json:"id" description:"A file_xxx or sum_xxx identifier to describe"`
+	ID      string `json:"id" description:"A file_xxx or sum_xxx identifier to describe"`
+	Level   string `json:"level,omitempty" description:"Optional, log files: comma-separated levels to keep (ERROR, WARN, INFO, DEBUG, TRACE)"`
+	Since   string `json:"since,omitempty" description:"Optional, log files: keep entries at or after this time (RFC 3339 or YYYY-MM-DD HH:MM)"`
+	Until   string `json:"until,omitempty" description:"Optional, log files: keep entries at or before this time (RFC 3339 or YYYY-MM-DD HH:MM)"`
+	Pattern string `json:"pattern,omitempty" description:"Optional, log files: regex an entry or its stack trace must match"`
+	GroupBy string `json:"group_by,omitempty" description:"Optional, log files: count matching entries by level, signature, minute or hour"`
+	Format  string `json:"format,omitempty" description:"Optional, log files: text (default), csv or json"`
+	Limit   int    `json:"limit,omitempty" description:"Optional, log files: maximum entries to return (default: 50)"`
+}
+
+// logQuery returns the log query the params ask for, and false when they
+// ask for none.
+func (p LcmDescribeParams) logQuery() (explorer.LogQuery, bool, error) {
+	if p.Level == "" && p.Since == "" && p.Until == "" && p.Pattern == "" && p.GroupBy == "" && p.Format == "" && p.Limit == 0 {
+		return explorer.LogQuery{}, false, nil
+	}
+
+	q := explorer.LogQuery{GroupBy: p.GroupBy, Limit: p.Limit}
+	if q.Limit <= 0 {
+		q.Limit = defaultDescribeLogLimit
+	}
+	if !explorer.ValidLogGroupBy(q.GroupBy) {
+		return q, true, fmt.Errorf("invalid group_by %q: must be level, signature, minute or hour", q.GroupBy)
+	}
+	switch p.Format {
+	case "", "text", "csv", "json":
+	default:
+		return q, true, fmt.Errorf("invalid format %q: must be text, csv or json", p.Format)
+	}
+	for level := range strings.SplitSeq(p.Level, ",") {
+		if level = strings.TrimSpace(level); level != "" {
+			q.Levels = append(q.Levels, level)
+		}
+	}
+	var err error
+	if p.Since != "" {
+		if q.Since, err = explorer.ParseLogQueryTime(p.Since); err != nil {
+			return q, true, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if p.Until != "" {
+		if q.Until, err = explorer.ParseLogQueryTime(p.Until); err != nil {
+			return q, true, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	if p.Pattern != "" {
+		if q.Pattern, err = regexp.Compile(p.Pattern); err != nil {
+			return q, true, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	return q, true, nil
 }
 
 var lcmDescribeDescription = `Describe a file or summary by its ID.
@@ -32,6 +85,14 @@ For files (file_xxx):
 - Shows the original path, size in tokens, and content preview
 - Shows exploration summary if the file was explored by an explorer tool
 
+For log files, query parameters replace the content preview with the matching entries:
+- level: Comma-separated levels to keep, e.g. "ERROR,WARN"
+- since, until: Time bounds, e.g. "2024-01-15 10:30" or RFC 3339
+- pattern: Regex an entry must match; stack traces are part of their entry
+- group_by: Count matching entries by level, signature, minute or hour
+- format: text (default), csv or json
+- limit: Maximum entries to return (default: 50)
+
 For summaries (sum_xxx):
 - Shows the summary kind (leaf or condensed)
 - Shows the full summary content and token count
@@ -53,7 +114,14 @@ func NewLcmDescribeTool(sqlDB *sql.DB) fantasy.AgentTool {
 
 			// Dispatch based on prefix
 			if strings.HasPrefix(params.ID, "file_") {
-				return describeFile(ctx, sqlDB, sessionID, params.ID)
+				logQuery, ok, err := params.logQuery()
+				if err != nil {
+					return fantasy.NewTextErrorResponse(err.Error()), nil
+				}
+				if !ok {
+					return describeFile(ctx, sqlDB, sessionID, params.ID, nil, "")
+				}
+				return describeFile(ctx, sqlDB, sessionID, params.ID, &logQuery, params.Format)
 			} else if strings.HasPrefix(params.ID, "sum_") {
 				return describeSummary(ctx, sqlDB, sessionID, params.ID)
 			} else {
@@ -62,7 +130,9 @@ func NewLcmDescribeTool(sqlDB *sql.DB) fantasy.AgentTool {
 		})
 }
 
-func describeFile(ctx context.Context, db *sql.DB, callerSessionID, fileID string) (fantasy.ToolResponse, error) {
+// describeFile describes a large file. With a log query, the matching log
+// entries in format take the place of the content preview.
+func describeFile(ctx context.Context, db *sql.DB, callerSessionID, fileID string, logQuery *explorer.LogQuery, format string) (fantasy.ToolResponse, error) {
 	query := `SELECT lf.original_path, lf.content, lf.token_count, lf.exploration_summary, lf.explorer_used
 	          FROM lcm_large_files lf
 	          WHERE lf.file_id = ?
@@ -114,10 +184,33 @@ func describeFile(ctx context.Context, db *sql.DB, callerSessionID, fileID strin
 		fmt.Fprintf(&output, "Explorer: %s\n", explorerUsed.String)
 	}
 
-	if explorationSummary.Valid && explorationSummary.String != "" {
+	if logQuery == nil && explorationSummary.Valid && explorationSummary.String != "" {
 		fmt.Fprintf(&output, "Exploration summary:\n%s\n", explorationSummary.String)
 	}
 
+	if logQuery != nil {
+		if !content.Valid || content.String == "" {
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("No stored content to query in %s", fileID)), nil
+		}
+		result := explorer.QueryLogs([]byte(content.String), *logQuery)
+		output.WriteString("\nLog query:\n")
+		switch format {
+		case "csv":
+			fmt.Fprintf(&output, "Matched %d of %d entries\n", result.Matched, result.Total)
+			output.WriteString(result.ExportCSV())
+		case "json":
+			data, err := result.ExportJSON()
+			if err != nil {
+				return fantasy.ToolResponse{}, err
+			}
+			output.WriteString(data)
+			output.WriteString("\n")
+		default:
+			output.WriteString(result.Format())
+		}
+		return fantasy.NewTextResponse(output.String()), nil
+	}
+
 	if content.Valid && content.String != "" {
 		fmt.Fprintf(&output, "\nContent preview:\n")
 		preview := content.String
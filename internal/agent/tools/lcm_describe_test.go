@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/stretchr/testify/require"
+)
+
+const describeTestLog = `2024-01-15 10:00:01 INFO server started
+2024-01-15 10:01:05 ERROR request 1017 failed
+java.lang.IllegalStateException: pool closed
+	at com.example.Pool.get(Pool.java:42)
+2024-01-15 10:02:10 WARN retrying request 1017
+2024-01-15 11:15:00 ERROR request 1023 failed`
+
+func runLcmDescribe(t *testing.T, params LcmDescribeParams) fantasy.ToolResponse {
+	t.Helper()
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+
+	sess, err := session.NewService(q, conn).Create(t.Context(), "Logs")
+	require.NoError(t, err)
+	require.NoError(t, q.InsertLcmLargeFile(t.Context(), db.InsertLcmLargeFileParams{
+		FileID:       "file_0123456789abcdef",
+		SessionID:    sess.ID,
+		OriginalPath: "app.log",
+		Content:      sql.NullString{String: describeTestLog, Valid: true},
+		TokenCount:   60,
+	}))
+
+	input, err := json.Marshal(params)
+	require.NoError(t, err)
+	ctx := context.WithValue(t.Context(), SessionIDContextKey, sess.ID)
+	resp, err := NewLcmDescribeTool(conn).Run(ctx, fantasy.ToolCall{ID: "call", Name: LcmDescribeToolName, Input: string(input)})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestLcmDescribeLogQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("preview without a query", func(t *testing.T) {
+		t.Parallel()
+		resp := runLcmDescribe(t, LcmDescribeParams{ID: "file_0123456789abcdef"})
+		require.False(t, resp.IsError)
+		require.Contains(t, resp.Content, "Content preview:")
+	})
+
+	t.Run("level and pattern", func(t *testing.T) {
+		t.Parallel()
+		resp := runLcmDescribe(t, LcmDescribeParams{ID: "file_0123456789abcdef", Level: "error", Pattern: `Pool\.get`})
+		require.False(t, resp.IsError, resp.Content)
+		require.NotContains(t, resp.Content, "Content preview:")
+		require.Contains(t, resp.Content, "Matched 1 of 4 entries")
+		require.Contains(t, resp.Content, "2: 2024-01-15 10:01:05 ERROR request 1017 failed\njava.lang.IllegalStateException")
+	})
+
+	t.Run("group by hour as csv", func(t *testing.T) {
+		t.Parallel()
+		resp := runLcmDescribe(t, LcmDescribeParams{ID: "file_0123456789abcdef", Until: "2024-01-15 12:00", GroupBy: "hour", Format: "csv"})
+		require.False(t, resp.IsError, resp.Content)
+		require.Contains(t, resp.Content, "key,count,first_line\n2024-01-15 10:00,3,1\n2024-01-15 11:00,1,6\n")
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		t.Parallel()
+		resp := runLcmDescribe(t, LcmDescribeParams{ID: "file_0123456789abcdef", Since: "last week"})
+		require.True(t, resp.IsError)
+		require.Contains(t, resp.Content, "invalid since")
+	})
+}
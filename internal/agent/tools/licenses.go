@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/licenses"
+)
+
+const LicensesToolName = "licenses"
+
+// defaultLicensesLimit is the number of entries listed per section when
+// the call sets no limit.
+const defaultLicensesLimit = 50
+
+//go:embed licenses.md
+var licensesDescription string
+
+type LicensesParams struct {
+	Package string `json:"package,omitempty" description:"Dependency to look up, by name; partial names list the matching packages"`
+	Path    string `json:"path,omitempty" description:"Directory or part of a path whose source file license headers to list"`
+	Limit   int    `json:"limit,omitempty" description:"Maximum entries listed per section (default 50)"`
+}
+
+func NewLicensesTool(workingDir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		LicensesToolName,
+		licensesDescription,
+		func(ctx context.Context, params LicensesParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			report, err := licenses.Scan(ctx, workingDir)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			limit := cmp.Or(params.Limit, defaultLicensesLimit)
+			switch {
+			case params.Package != "":
+				return fantasy.NewTextResponse(report.Lookup(params.Package)), nil
+			case params.Path != "":
+				return fantasy.NewTextResponse(report.FileHeaders(params.Path, limit)), nil
+			}
+			return fantasy.NewTextResponse(report.Summary(limit)), nil
+		},
+	)
+}
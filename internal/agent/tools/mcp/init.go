@@ -68,6 +68,9 @@ const (
 	StateStarting
 	StateConnected
 	StateError
+	// StateIdle is a server that is not connected but starts on next use:
+	// deferred by lazy startup or closed after idling.
+	StateIdle
 )
 
 func (s State) String() string {
@@ -80,6 +83,8 @@ func (s State) String() string {
 		return "connected"
 	case StateError:
 		return "error"
+	case StateIdle:
+		return "idle"
 	default:
 		return "unknown"
 	}
@@ -163,8 +168,14 @@ func Close(ctx context.Context) error {
 }
 
 // Initialize initializes MCP clients based on the provided configuration.
+// With lazy_mcp set, servers not listed in eager_mcp are left in StateIdle
+// until StartPending or their first use.
 func Initialize(ctx context.Context, permissions permission.Service, cfg *config.ConfigStore) {
 	slog.Info("Initializing MCP clients")
+	var startup *config.StartupOptions
+	if opts := cfg.Config().Options; opts != nil {
+		startup = opts.Startup
+	}
 	var wg sync.WaitGroup
 	// Initialize states for all configured MCPs
 	for name, m := range cfg.Config().MCP {
@@ -173,36 +184,44 @@ func Initialize(ctx context.Context, permissions permission.Service, cfg *config
 			slog.Debug("Skipping disabled MCP", "name", name)
 			continue
 		}
+		if !startup.EagerMCPServer(name) {
+			pending.Set(name, m)
+			updateState(name, StateIdle, nil, nil, Counts{})
+			slog.Debug("Deferring MCP startup", "name", name)
+			continue
+		}
 
-		// Set initial starting state
-		wg.Add(1)
-		go func(name string, m config.MCPConfig) {
-			defer func() {
-				wg.Done()
-				if r := recover(); r != nil {
-					var err error
-					switch v := r.(type) {
-					case error:
-						err = v
-					case string:
-						err = fmt.Errorf("panic: %s", v)
-					default:
-						err = fmt.Errorf("panic: %v", v)
-					}
-					updateState(name, StateError, err, nil, Counts{})
-					slog.Error("Panic in MCP client initialization", "error", err, "name", name)
-				}
-			}()
-
-			if err := initClient(ctx, cfg, name, m, cfg.Resolver()); err != nil {
-				slog.Debug("Failed to initialize MCP client", "name", name, "error", err)
-			}
-		}(name, m)
+		wg.Go(func() {
+			startClient(ctx, cfg, name, m)
+		})
 	}
 	wg.Wait()
 	initOnce.Do(func() { close(initDone) })
 }
 
+// startClient runs initClient, turning a panic into StateError.
+func startClient(ctx context.Context, cfg *config.ConfigStore, name string, m config.MCPConfig) {
+	defer func() {
+		if r := recover(); r != nil {
+			var err error
+			switch v := r.(type) {
+			case error:
+				err = v
+			case string:
+				err = fmt.Errorf("panic: %s", v)
+			default:
+				err = fmt.Errorf("panic: %v", v)
+			}
+			updateState(name, StateError, err, nil, Counts{})
+			slog.Error("Panic in MCP client initialization", "error", err, "name", name)
+		}
+	}()
+
+	if err := initClient(ctx, cfg, name, m, cfg.Resolver()); err != nil {
+		slog.Debug("Failed to initialize MCP client", "name", name, "error", err)
+	}
+}
+
 // WaitForInit blocks until MCP initialization is complete.
 // If Initialize was never called, this returns immediately.
 func WaitForInit(ctx context.Context) error {
@@ -227,6 +246,7 @@ func InitializeSingle(ctx context.Context, name string, cfg *config.ConfigStore)
 		return nil
 	}
 
+	pending.Del(name)
 	return initClient(ctx, cfg, name, m, cfg.Resolver())
 }
 
@@ -260,6 +280,7 @@ func initClient(ctx context.Context, cfg *config.ConfigStore, name string, m con
 	toolCount := updateTools(cfg, name, tools)
 	updatePrompts(name, prompts)
 	sessions.Set(name, session)
+	markUsed(name)
 
 	updateState(name, StateConnected, nil, session, Counts{
 		Tools:   toolCount,
@@ -271,6 +292,8 @@ func initClient(ctx context.Context, cfg *config.ConfigStore, name string, m con
 
 // DisableSingle disables and closes a single MCP client by name.
 func DisableSingle(cfg *config.ConfigStore, name string) error {
+	pending.Del(name)
+	lastUsed.Del(name)
 	session, ok := sessions.Get(name)
 	if ok {
 		if err := session.Close(); err != nil &&
@@ -296,8 +319,12 @@ func DisableSingle(cfg *config.ConfigStore, name string) error {
 func getOrRenewClient(ctx context.Context, cfg *config.ConfigStore, name string) (*ClientSession, error) {
 	sess, ok := sessions.Get(name)
 	if !ok {
+		if state, _ := states.Get(name); state.State == StateIdle {
+			return resume(ctx, cfg, name)
+		}
 		return nil, fmt.Errorf("mcp '%s' not available", name)
 	}
+	markUsed(name)
 
 	m := cfg.Config().MCP[name]
 	state, _ := states.Get(name)
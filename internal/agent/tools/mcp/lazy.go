@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/csync"
+)
+
+var (
+	// pending holds servers whose startup was deferred by lazy_mcp.
+	pending = csync.NewMap[string, config.MCPConfig]()
+	// lastUsed records when each connected server last served a request.
+	lastUsed = csync.NewMap[string, time.Time]()
+	timeNow  = time.Now
+)
+
+func markUsed(name string) {
+	lastUsed.Set(name, timeNow())
+}
+
+// StartPending connects the servers deferred by lazy startup and waits for
+// them, so their tools are registered before the agent builds its tool
+// list. Once they have started it is a no-op.
+func StartPending(ctx context.Context, cfg *config.ConfigStore) {
+	var wg sync.WaitGroup
+	for name := range pending.Copy() {
+		m, ok := pending.Take(name)
+		if !ok {
+			continue
+		}
+		wg.Go(func() {
+			startClient(ctx, cfg, name, m)
+		})
+	}
+	wg.Wait()
+}
+
+// resume reconnects a server in StateIdle: one deferred by lazy startup,
+// or one closed by CloseIdle whose tools are still registered.
+func resume(ctx context.Context, cfg *config.ConfigStore, name string) (*ClientSession, error) {
+	if m, ok := pending.Take(name); ok {
+		if err := initClient(ctx, cfg, name, m, cfg.Resolver()); err != nil {
+			return nil, err
+		}
+		sess, ok := sessions.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("mcp '%s' not available", name)
+		}
+		return sess, nil
+	}
+
+	m := cfg.Config().MCP[name]
+	state, _ := states.Get(name)
+	updateState(name, StateStarting, nil, nil, state.Counts)
+	sess, err := createSession(ctx, name, m, cfg.Resolver())
+	if err != nil {
+		return nil, err
+	}
+	sessions.Set(name, sess)
+	markUsed(name)
+	updateState(name, StateConnected, nil, sess, state.Counts)
+	slog.Debug("Resumed idle MCP client", "name", name)
+	return sess, nil
+}
+
+// CloseIdle closes the sessions that have not served a request for longer
+// than idle and moves them to StateIdle. Their tools stay registered, and
+// the next call reconnects them. It returns the names it closed.
+func CloseIdle(idle time.Duration) []string {
+	if idle <= 0 {
+		return nil
+	}
+	var closed []string
+	cutoff := timeNow().Add(-idle)
+	for name, sess := range sessions.Seq2() {
+		used, ok := lastUsed.Get(name)
+		if !ok {
+			markUsed(name)
+			continue
+		}
+		if used.After(cutoff) {
+			continue
+		}
+		sessions.Del(name)
+		lastUsed.Del(name)
+		if err := sess.Close(); err != nil &&
+			!errors.Is(err, io.EOF) &&
+			!errors.Is(err, context.Canceled) &&
+			err.Error() != "signal: killed" {
+			slog.Warn("Error closing idle MCP session", "name", name, "error", err)
+		}
+		state, _ := states.Get(name)
+		updateState(name, StateIdle, nil, nil, state.Counts)
+		slog.Debug("Closed idle MCP client", "name", name, "idle", idle)
+		closed = append(closed, name)
+	}
+	return closed
+}
+
+// WatchIdle runs CloseIdle periodically until ctx is done.
+func WatchIdle(ctx context.Context, idle time.Duration) {
+	if idle <= 0 {
+		return
+	}
+	ticker := time.NewTicker(max(idle/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			CloseIdle(idle)
+		}
+	}
+}
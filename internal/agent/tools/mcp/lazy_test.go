@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitializeDefersLazyMCP(t *testing.T) {
+	const name = "lazy-deferred"
+	t.Cleanup(func() {
+		pending.Del(name)
+		states.Del(name)
+	})
+
+	store := config.NewTestStore(&config.Config{
+		Options: &config.Options{Startup: &config.StartupOptions{LazyMCP: true}},
+		MCP: map[string]config.MCPConfig{
+			name: {Type: config.MCPStdio, Command: "crush-test-missing-mcp"},
+		},
+	})
+	Initialize(t.Context(), nil, store)
+
+	state, ok := GetState(name)
+	require.True(t, ok)
+	require.Equal(t, StateIdle, state.State)
+	_, ok = pending.Get(name)
+	require.True(t, ok, "lazy server should wait for StartPending")
+	_, ok = sessions.Get(name)
+	require.False(t, ok)
+}
+
+func TestEagerMCPServer(t *testing.T) {
+	t.Parallel()
+
+	var unset *config.StartupOptions
+	require.True(t, unset.EagerMCPServer("any"))
+	require.True(t, (&config.StartupOptions{}).EagerMCPServer("any"))
+
+	lazy := &config.StartupOptions{LazyMCP: true, EagerMCP: []string{"github"}}
+	require.True(t, lazy.EagerMCPServer("github"))
+	require.False(t, lazy.EagerMCPServer("docs"))
+}
+
+func TestCloseIdle(t *testing.T) {
+	const (
+		idleName   = "idle-close"
+		activeName = "idle-keep"
+	)
+	connect := func(name string) {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		server := mcp.NewServer(&mcp.Implementation{Name: "test-server"}, nil)
+		serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = serverSession.Close() })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		clientSession, err := mcp.NewClient(&mcp.Implementation{Name: "crush-test"}, nil).Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		sess := &ClientSession{clientSession, cancel}
+		sessions.Set(name, sess)
+		updateState(name, StateConnected, nil, sess, Counts{Tools: 2})
+	}
+	t.Cleanup(func() {
+		for _, name := range []string{idleName, activeName} {
+			if sess, ok := sessions.Take(name); ok {
+				_ = sess.Close()
+			}
+			states.Del(name)
+			lastUsed.Del(name)
+		}
+	})
+
+	connect(idleName)
+	connect(activeName)
+	lastUsed.Set(idleName, time.Now().Add(-time.Hour))
+	markUsed(activeName)
+
+	require.Contains(t, CloseIdle(time.Minute), idleName)
+
+	state, _ := GetState(idleName)
+	require.Equal(t, StateIdle, state.State)
+	require.Equal(t, 2, state.Counts.Tools, "idle servers keep their counts")
+	_, ok := sessions.Get(idleName)
+	require.False(t, ok)
+
+	state, _ = GetState(activeName)
+	require.Equal(t, StateConnected, state.State)
+	_, ok = sessions.Get(activeName)
+	require.True(t, ok)
+
+	require.Nil(t, CloseIdle(0))
+}
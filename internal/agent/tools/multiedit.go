@@ -18,6 +18,8 @@ import (
 	"github.com/charmbracelet/crush/internal/history"
 	"github.com/charmbracelet/crush/internal/lsp"
 	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/remote"
+	"github.com/charmbracelet/crush/internal/staging"
 )
 
 type MultiEditOperation struct {
@@ -63,6 +65,8 @@ func NewMultiEditTool(
 	files history.Service,
 	filetracker filetracker.Service,
 	workingDir string,
+	stager staging.Service,
+	target *remote.Target,
 ) fantasy.AgentTool {
 	return fantasy.NewAgentTool(
 		MultiEditToolName,
@@ -76,7 +80,7 @@ func NewMultiEditTool(
 				return fantasy.NewTextErrorResponse("at least one edit operation is required"), nil
 			}
 
-			params.FilePath = filepathext.SmartJoin(workingDir, params.FilePath)
+			params.FilePath = filepathext.SmartJoin(workingDir, target.LocalPath(params.FilePath))
 
 			// Validate all edits before applying any
 			if err := validateEdits(params.Edits); err != nil {
@@ -86,7 +90,7 @@ func NewMultiEditTool(
 			var response fantasy.ToolResponse
 			var err error
 
-			editCtx := editContext{ctx, permissions, files, filetracker, workingDir}
+			editCtx := editContext{ctx, permissions, files, filetracker, workingDir, stager}
 			// Handle file creation case (first edit has empty old_string)
 			if len(params.Edits) > 0 && params.Edits[0].OldString == "" {
 				response, err = processMultiEditWithCreation(editCtx, params, call)
@@ -108,6 +112,9 @@ func NewMultiEditTool(
 			// Wait for LSP diagnostics and add them to the response
 			text := fmt.Sprintf("<result>\n%s\n</result>\n", response.Content)
 			text += getDiagnostics(params.FilePath, lspManager)
+			if stager == nil {
+				text += pushToRemote(ctx, target, params.FilePath)
+			}
 			response.Content = text
 			return response, nil
 		},
@@ -208,6 +215,16 @@ func processMultiEditWithCreation(edit editContext, params MultiEditParams, call
 		return resp, nil
 	}
 
+	if edit.staging != nil {
+		return stageEdit(edit.staging, sessionID, params.FilePath, "", currentContent, true, MultiEditResponseMetadata{
+			NewContent:   currentContent,
+			Additions:    additions,
+			Removals:     removals,
+			EditsApplied: editsApplied,
+			EditsFailed:  failedEdits,
+		})
+	}
+
 	// Write the file
 	err = os.WriteFile(params.FilePath, []byte(currentContent), 0o644)
 	if err != nil {
@@ -285,7 +302,7 @@ func processMultiEditExistingFile(edit editContext, params MultiEditParams, call
 	}
 
 	// Read current file content
-	content, err := os.ReadFile(params.FilePath)
+	content, err := readEditTarget(edit.staging, sessionID, params.FilePath)
 	if err != nil {
 		return fantasy.ToolResponse{}, fmt.Errorf("failed to read file: %w", err)
 	}
@@ -362,6 +379,17 @@ func processMultiEditExistingFile(edit editContext, params MultiEditParams, call
 		return resp, nil
 	}
 
+	if edit.staging != nil {
+		return stageEdit(edit.staging, sessionID, params.FilePath, oldContent, currentContent, false, MultiEditResponseMetadata{
+			OldContent:   oldContent,
+			NewContent:   currentContent,
+			Additions:    additions,
+			Removals:     removals,
+			EditsApplied: editsApplied,
+			EditsFailed:  failedEdits,
+		})
+	}
+
 	if isCrlf {
 		currentContent, _ = fsext.ToWindowsLineEndings(currentContent)
 	}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/sysinspect"
+)
+
+const PortsToolName = "ports"
+
+// defaultPortsLimit is the number of sockets listed when the call sets no
+// limit.
+const defaultPortsLimit = 100
+
+//go:embed ports.md
+var portsDescription string
+
+type PortsParams struct {
+	Port        int  `json:"port,omitempty" description:"Only list the sockets on this local port, connections included"`
+	Connections bool `json:"connections,omitempty" description:"Also list established and closing connections, not only listening sockets"`
+	Limit       int  `json:"limit,omitempty" description:"Maximum sockets listed (default 100)"`
+}
+
+type PortsResponseMetadata struct {
+	Total int               `json:"total"`
+	Ports []sysinspect.Port `json:"ports"`
+}
+
+func NewPortsTool() fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		PortsToolName,
+		portsDescription,
+		func(ctx context.Context, params PortsParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			all, err := sysinspect.Ports(ctx)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("error listing ports: %v", err)), nil
+			}
+			var ports []sysinspect.Port
+			for _, p := range all {
+				switch {
+				case params.Port > 0 && p.Port != params.Port:
+				case params.Port > 0, params.Connections, p.Listening():
+					ports = append(ports, p)
+				}
+			}
+			if len(ports) == 0 {
+				if params.Port > 0 {
+					return fantasy.NewTextResponse(fmt.Sprintf("Nothing uses port %d.", params.Port)), nil
+				}
+				return fantasy.NewTextResponse("No listening sockets."), nil
+			}
+
+			total := len(ports)
+			if limit := cmp.Or(params.Limit, defaultPortsLimit); total > limit {
+				ports = ports[:limit]
+			}
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "Sockets: %d", total)
+			if len(ports) < total {
+				fmt.Fprintf(&sb, ", listing the first %d", len(ports))
+			}
+			sb.WriteString("\n\n")
+			sb.WriteString(sysinspect.FormatPorts(ports))
+			if hidden := countUnowned(ports); hidden > 0 {
+				fmt.Fprintf(&sb, "\n%d sockets belong to processes of other users and show no owner.\n", hidden)
+			}
+
+			metadata := PortsResponseMetadata{Total: total, Ports: ports}
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(sb.String()), metadata), nil
+		},
+	)
+}
+
+func countUnowned(ports []sysinspect.Port) int {
+	n := 0
+	for _, p := range ports {
+		if p.PID == 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/sysinspect"
+)
+
+const ProcessesToolName = "processes"
+
+// defaultProcessesLimit is the number of processes listed when the call
+// sets no limit.
+const defaultProcessesLimit = 30
+
+//go:embed processes.md
+var processesDescription string
+
+type ProcessesParams struct {
+	Filter string `json:"filter,omitempty" description:"Only list processes whose name or command line contains this text, case-insensitively"`
+	PID    int    `json:"pid,omitempty" description:"Only list this process and its descendants"`
+	Sort   string `json:"sort,omitempty" description:"Order by cpu, memory or pid (default cpu)"`
+	Limit  int    `json:"limit,omitempty" description:"Maximum processes listed (default 30)"`
+}
+
+type ProcessesResponseMetadata struct {
+	Total     int                  `json:"total"`
+	Processes []sysinspect.Process `json:"processes"`
+}
+
+func NewProcessesTool() fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		ProcessesToolName,
+		processesDescription,
+		func(ctx context.Context, params ProcessesParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			key := sysinspect.SortKey(cmp.Or(strings.ToLower(params.Sort), string(sysinspect.SortByCPU)))
+			switch key {
+			case sysinspect.SortByCPU, sysinspect.SortByMemory, sysinspect.SortByPID:
+			default:
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("unknown sort %q: use cpu, memory or pid", params.Sort)), nil
+			}
+
+			procs, err := sysinspect.Processes(ctx)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("error listing processes: %v", err)), nil
+			}
+			if params.PID > 0 {
+				procs = sysinspect.ProcessTree(procs, params.PID)
+				if len(procs) == 0 {
+					return fantasy.NewTextErrorResponse(fmt.Sprintf("no process with PID %d", params.PID)), nil
+				}
+			}
+			if params.Filter != "" {
+				procs = sysinspect.FilterProcesses(procs, params.Filter)
+			}
+			if len(procs) == 0 {
+				return fantasy.NewTextResponse("No matching processes."), nil
+			}
+
+			total := len(procs)
+			procs = sysinspect.TopProcesses(procs, key, cmp.Or(params.Limit, defaultProcessesLimit))
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "Processes: %d", total)
+			if len(procs) < total {
+				fmt.Fprintf(&sb, ", listing the first %d by %s", len(procs), key)
+			}
+			sb.WriteString("\n\n")
+			sb.WriteString(sysinspect.FormatProcesses(procs))
+
+			metadata := ProcessesResponseMetadata{Total: total, Processes: procs}
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(sb.String()), metadata), nil
+		},
+	)
+}
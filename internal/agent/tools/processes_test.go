@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
+)
+
+func runInspectionTool(t *testing.T, tool fantasy.AgentTool, params any) fantasy.ToolResponse {
+	t.Helper()
+	input, err := json.Marshal(params)
+	require.NoError(t, err)
+	resp, err := tool.Run(t.Context(), fantasy.ToolCall{ID: "call", Name: tool.Info().Name, Input: string(input)})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestInspectionTools(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("inspects /proc")
+	}
+
+	resp := runInspectionTool(t, NewProcessesTool(), ProcessesParams{PID: os.Getpid()})
+	require.False(t, resp.IsError, resp.Content)
+	require.Contains(t, resp.Content, "Processes: 1\n")
+	var procs ProcessesResponseMetadata
+	require.NoError(t, json.Unmarshal([]byte(resp.Metadata), &procs))
+	require.Equal(t, os.Getpid(), procs.Processes[0].PID)
+
+	resp = runInspectionTool(t, NewProcessesTool(), ProcessesParams{Sort: "name"})
+	require.True(t, resp.IsError)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	port := ln.Addr().(*net.TCPAddr).Port
+	resp = runInspectionTool(t, NewPortsTool(), PortsParams{Port: port})
+	require.False(t, resp.IsError, resp.Content)
+	require.Contains(t, resp.Content, "127.0.0.1:"+strconv.Itoa(port))
+	require.Contains(t, resp.Content, "LISTEN")
+	require.Contains(t, resp.Content, fmt.Sprint(os.Getpid()))
+
+	resp = runInspectionTool(t, NewSystemResourcesTool(t.TempDir()), SystemResourcesParams{})
+	require.False(t, resp.IsError, resp.Content)
+	require.Contains(t, resp.Content, "Memory:")
+	require.Contains(t, resp.Content, "Disk (")
+}
@@ -0,0 +1,20 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/charmbracelet/crush/internal/remote"
+)
+
+// pushToRemote copies a written file to the remote target when it syncs
+// files. A failed copy does not undo the local write; it is reported to
+// the agent instead, so it can retry or tell the user.
+func pushToRemote(ctx context.Context, target *remote.Target, filePath string) string {
+	if err := target.Push(ctx, filePath); err != nil {
+		slog.Warn("Failed to sync file to remote target", "path", filePath, "error", err)
+		return fmt.Sprintf("\n<remote_sync_error>\nThe file was written locally but not copied to %s: %v\n</remote_sync_error>\n", target, err)
+	}
+	return ""
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/dbschema"
+)
+
+const SchemaLookupToolName = "schema_lookup"
+
+//go:embed schema_lookup.md
+var schemaLookupDescription string
+
+type SchemaLookupParams struct {
+	Table  string `json:"table,omitempty" description:"Table name or part of one to describe"`
+	Column string `json:"column,omitempty" description:"Column name or part of one to find across tables"`
+}
+
+func NewSchemaLookupTool(workingDir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		SchemaLookupToolName,
+		schemaLookupDescription,
+		func(ctx context.Context, params SchemaLookupParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			schema, err := dbschema.Load(ctx, workingDir)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			out, err := schema.Lookup(params.Table, params.Column)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			return fantasy.NewTextResponse(out), nil
+		},
+	)
+}
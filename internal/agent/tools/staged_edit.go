@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"os"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/staging"
+)
+
+// readEditTarget returns the content an edit applies to: the staged
+// content when the file already has an edit awaiting review, otherwise the
+// file on disk.
+func readEditTarget(stager staging.Service, sessionID, filePath string) ([]byte, error) {
+	if stager != nil {
+		if content, ok := stager.Content(sessionID, filePath); ok {
+			return []byte(content), nil
+		}
+	}
+	return os.ReadFile(filePath)
+}
+
+// stageEdit records the edit in the staging area for per-hunk review
+// instead of writing it. original is only used the first time the file is
+// staged. The metadata is attached to the response so the UI can still
+// render the diff.
+func stageEdit(stager staging.Service, sessionID, filePath, original, proposed string, created bool, metadata any) (fantasy.ToolResponse, error) {
+	change, err := stager.Stage(sessionID, filePath, original, proposed, created)
+	if err != nil {
+		return fantasy.ToolResponse{}, err
+	}
+	return fantasy.WithResponseMetadata(fantasy.NewTextResponse(change.Summary()), metadata), nil
+}
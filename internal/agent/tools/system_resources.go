@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/sysinspect"
+)
+
+const SystemResourcesToolName = "system_resources"
+
+//go:embed system_resources.md
+var systemResourcesDescription string
+
+type SystemResourcesParams struct {
+	Path string `json:"path,omitempty" description:"Directory whose filesystem's disk usage to report (default the working directory)"`
+}
+
+func NewSystemResourcesTool(workingDir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		SystemResourcesToolName,
+		systemResourcesDescription,
+		func(ctx context.Context, params SystemResourcesParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			snap, err := sysinspect.TakeSnapshot(ctx, filepathext.SmartJoin(workingDir, params.Path))
+			if err != nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("error reading system resources: %v", err)), nil
+			}
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(snap.Format()), snap), nil
+		},
+	)
+}
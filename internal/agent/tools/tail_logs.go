@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+	"github.com/charmbracelet/crush/internal/permission"
+)
+
+const TailLogsToolName = "tail_logs"
+
+//go:embed tail_logs.md
+var tailLogsDescription string
+
+const (
+	// maxTailFollow caps how long one call follows the log.
+	maxTailFollow = 60 * time.Second
+	// tailPollInterval is how often a followed log is checked for growth.
+	tailPollInterval = 250 * time.Millisecond
+	// maxTailChunk caps the bytes analyzed per call; older growth is
+	// skipped, as is the start of a larger log on the first call.
+	maxTailChunk = 1024 * 1024
+)
+
+type TailLogsParams struct {
+	Path          string `json:"path" description:"The log file to tail, absolute or relative to the working directory"`
+	FollowSeconds int    `json:"follow_seconds,omitempty" description:"Seconds to keep following the log for new lines before reporting (default 0, max 60)"`
+}
+
+type TailLogsPermissionsParams struct {
+	Path          string `json:"path"`
+	FollowSeconds int    `json:"follow_seconds,omitempty"`
+}
+
+type TailLogsResponseMetadata struct {
+	Path      string `json:"path"`
+	Offset    int64  `json:"offset"`
+	NewLines  int    `json:"new_lines"`
+	NewErrors int    `json:"new_errors"`
+}
+
+// logTailState is where a session left off in a log.
+type logTailState struct {
+	mu     sync.Mutex
+	offset int64
+	tail   *explorer.LogTail
+}
+
+type tailLogsTool struct {
+	permissions permission.Service
+	workingDir  string
+
+	mu    sync.Mutex
+	state map[string]*logTailState
+}
+
+func NewTailLogsTool(permissions permission.Service, workingDir string) fantasy.AgentTool {
+	t := &tailLogsTool{
+		permissions: permissions,
+		workingDir:  workingDir,
+		state:       make(map[string]*logTailState),
+	}
+	return fantasy.NewAgentTool(TailLogsToolName, tailLogsDescription, t.run)
+}
+
+func (t *tailLogsTool) run(ctx context.Context, params TailLogsParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+	if params.Path == "" {
+		return fantasy.NewTextErrorResponse("path is required"), nil
+	}
+	sessionID := GetSessionFromContext(ctx)
+	if sessionID == "" {
+		return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for tailing logs")
+	}
+
+	absPath, err := filepath.Abs(filepathext.SmartJoin(t.workingDir, params.Path))
+	if err != nil {
+		return fantasy.ToolResponse{}, fmt.Errorf("error resolving log path: %w", err)
+	}
+	absWorkingDir, err := filepath.Abs(t.workingDir)
+	if err != nil {
+		return fantasy.ToolResponse{}, fmt.Errorf("error resolving working directory: %w", err)
+	}
+	if rel, err := filepath.Rel(absWorkingDir, absPath); err != nil || strings.HasPrefix(rel, "..") {
+		granted, err := t.permissions.Request(ctx, permission.CreatePermissionRequest{
+			SessionID:   sessionID,
+			Path:        absPath,
+			ToolCallID:  call.ID,
+			ToolName:    TailLogsToolName,
+			Action:      "read",
+			Description: fmt.Sprintf("Tail log file outside working directory: %s", absPath),
+			Params: TailLogsPermissionsParams{
+				Path:          params.Path,
+				FollowSeconds: params.FollowSeconds,
+			},
+		})
+		if err != nil {
+			return fantasy.ToolResponse{}, err
+		}
+		if !granted {
+			return NewPermissionDeniedResponse(), nil
+		}
+	}
+
+	if st, err := os.Stat(absPath); err != nil {
+		if os.IsNotExist(err) {
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("log file not found: %s", absPath)), nil
+		}
+		return fantasy.NewTextErrorResponse(fmt.Sprintf("error accessing log file: %v", err)), nil
+	} else if st.IsDir() {
+		return fantasy.NewTextErrorResponse(fmt.Sprintf("path is a directory, not a log file: %s", absPath)), nil
+	}
+
+	// Calls for one log take turns, or they would read the same bytes
+	// twice.
+	key := sessionID + "\x00" + absPath
+	t.mu.Lock()
+	state, ok := t.state[key]
+	if !ok {
+		state = &logTailState{tail: explorer.NewLogTail()}
+		t.state[key] = state
+	}
+	t.mu.Unlock()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	follow := min(time.Duration(max(params.FollowSeconds, 0))*time.Second, maxTailFollow)
+	read, err := state.follow(ctx, absPath, follow)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fantasy.ToolResponse{}, ctx.Err()
+		}
+		return fantasy.NewTextErrorResponse(fmt.Sprintf("error reading log file: %v", err)), nil
+	}
+	chunk := state.tail.Add(read.content)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Log: %s\n", absPath)
+	switch {
+	case !ok:
+		sb.WriteString("First call: analyzed the log so far; later calls report only what is appended after this one.\n")
+	case read.truncated:
+		sb.WriteString("The log was truncated or rotated since the last call; reading it from the start.\n")
+	}
+	if read.skipped > 0 {
+		fmt.Fprintf(&sb, "Skipped %d bytes of a burst larger than %d.\n", read.skipped, maxTailChunk)
+	}
+	if follow > 0 {
+		fmt.Fprintf(&sb, "Followed for %s.\n", follow)
+	}
+	if len(read.content) == 0 {
+		sb.WriteString("No new lines.\n")
+	} else {
+		sb.WriteString(chunk.Format())
+	}
+
+	metadata := TailLogsResponseMetadata{
+		Path:      absPath,
+		Offset:    state.offset,
+		NewLines:  chunk.Lines,
+		NewErrors: len(chunk.New),
+	}
+	return fantasy.WithResponseMetadata(fantasy.NewTextResponse(sb.String()), metadata), nil
+}
+
+// follow reads what is appended to path for d, polling so a log that is
+// rotated meanwhile loses no lines, and returns it all. With d zero it
+// reads once.
+func (s *logTailState) follow(ctx context.Context, path string, d time.Duration) (logGrowth, error) {
+	var all logGrowth
+	read := func() error {
+		g, err := readLogGrowth(path, s.offset)
+		if err != nil {
+			return err
+		}
+		s.offset = g.offset
+		all.content = append(all.content, g.content...)
+		if n := len(all.content) - maxTailChunk; n > 0 {
+			// Keep the newest lines, starting at a line boundary.
+			n += bytes.IndexByte(all.content[n:], '\n') + 1
+			all.content = all.content[n:]
+			all.skipped += int64(n)
+		}
+		all.truncated = all.truncated || g.truncated
+		all.skipped += g.skipped
+		return nil
+	}
+	if err := read(); err != nil || d <= 0 {
+		return all, err
+	}
+
+	deadline := time.NewTimer(d)
+	defer deadline.Stop()
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return all, ctx.Err()
+		case <-deadline.C:
+			if err := read(); err != nil && !os.IsNotExist(err) {
+				return all, err
+			}
+			return all, nil
+		case <-ticker.C:
+			// A rotated log may be missing for a moment.
+			if err := read(); err != nil && !os.IsNotExist(err) {
+				return all, err
+			}
+		}
+	}
+}
+
+// logGrowth is what was appended to a log since an offset.
+type logGrowth struct {
+	content []byte
+	// offset is where the next read starts: after the last complete line.
+	offset int64
+	// truncated is set when the log shrank below the offset, skipped
+	// counts the bytes left unread past maxTailChunk.
+	truncated bool
+	skipped   int64
+}
+
+// readLogGrowth reads the complete lines appended to path after offset,
+// at most maxTailChunk bytes of them. A trailing partial line is left for
+// the next read.
+func readLogGrowth(path string, offset int64) (logGrowth, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return logGrowth{}, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return logGrowth{}, err
+	}
+
+	var g logGrowth
+	size := st.Size()
+	if size < offset {
+		g.truncated = true
+		offset = 0
+	}
+	start := offset
+	if size-start > maxTailChunk {
+		start = size - maxTailChunk
+		g.skipped = start - offset
+	}
+	buf := make([]byte, size-start)
+	if _, err := f.ReadAt(buf, start); err != nil && err != io.EOF {
+		return logGrowth{}, err
+	}
+	if g.skipped > 0 {
+		// Drop the partial line the skip landed in.
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			buf = buf[i+1:]
+			g.skipped += int64(i + 1)
+		}
+	}
+	end := bytes.LastIndexByte(buf, '\n') + 1
+	g.content = buf[:end]
+	g.offset = size - int64(len(buf)-end)
+	return g, nil
+}
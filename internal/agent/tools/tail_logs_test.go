@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
+)
+
+func runTailLogs(t *testing.T, tool fantasy.AgentTool, ctx context.Context, params TailLogsParams) string {
+	t.Helper()
+	input, err := json.Marshal(params)
+	require.NoError(t, err)
+	resp, err := tool.Run(ctx, fantasy.ToolCall{ID: "call", Name: TailLogsToolName, Input: string(input)})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content)
+	return resp.Content
+}
+
+func appendLog(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestTailLogsTool(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	logPath := filepath.Join(workingDir, "server.log")
+	appendLog(t, logPath, "2024-01-15 10:00:00 INFO starting\n2024-01-15 10:00:01 ERROR db timeout after 3000 ms\n")
+
+	tool := NewTailLogsTool(&mockPermissionService{}, workingDir)
+	ctx := context.WithValue(context.Background(), SessionIDContextKey, "test-session")
+
+	out := runTailLogs(t, tool, ctx, TailLogsParams{Path: "server.log"})
+	require.Contains(t, out, "First call")
+	require.Contains(t, out, "New error signatures:")
+	require.Contains(t, out, "ERROR db timeout after <num> ms")
+
+	out = runTailLogs(t, tool, ctx, TailLogsParams{Path: "server.log"})
+	require.Contains(t, out, "No new lines.")
+
+	// A repeat of a known error is recurring; a partial line waits.
+	appendLog(t, logPath, "2024-01-15 10:00:02 ERROR db timeout after 5000 ms\n2024-01-15 10:00:03 ERROR cache miss storm\n2024-01-15 10:00:04 WARN slo")
+	out = runTailLogs(t, tool, ctx, TailLogsParams{Path: "server.log"})
+	require.Contains(t, out, "New lines: 2")
+	require.Contains(t, out, "ERROR cache miss storm")
+	require.Contains(t, out, "Recurring signatures:")
+	require.Contains(t, out, "1 new (2 total)")
+	require.NotContains(t, out, "WARN slo")
+
+	// Following picks up lines written during the call.
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		appendLog(t, logPath, "w\n")
+	}()
+	out = runTailLogs(t, tool, ctx, TailLogsParams{Path: logPath, FollowSeconds: 1})
+	require.Contains(t, out, "Followed for 1s.")
+	require.Contains(t, out, "WARN slow")
+
+	// Other sessions keep their own place in the log.
+	other := context.WithValue(context.Background(), SessionIDContextKey, "other-session")
+	require.Contains(t, runTailLogs(t, tool, other, TailLogsParams{Path: "server.log"}), "First call")
+
+	require.NoError(t, os.WriteFile(logPath, []byte("ERROR fresh start\n"), 0o644))
+	out = runTailLogs(t, tool, ctx, TailLogsParams{Path: "server.log"})
+	require.Contains(t, out, "truncated or rotated")
+	require.Contains(t, out, "ERROR fresh start")
+}
+
+func TestTailLogsToolErrors(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	tool := NewTailLogsTool(&mockPermissionService{}, workingDir)
+	ctx := context.WithValue(context.Background(), SessionIDContextKey, "test-session")
+
+	for _, path := range []string{"", "missing.log", "."} {
+		input, err := json.Marshal(TailLogsParams{Path: path})
+		require.NoError(t, err)
+		resp, err := tool.Run(ctx, fantasy.ToolCall{ID: "call", Name: TailLogsToolName, Input: string(input)})
+		require.NoError(t, err)
+		require.True(t, resp.IsError, path)
+	}
+}
+
+func TestReadLogGrowthSkipsLargeBursts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "big.log")
+	line := "INFO padding line that is long enough to matter\n"
+	var content []byte
+	for len(content) < maxTailChunk+10*len(line) {
+		content = append(content, line...)
+	}
+	content = append(content, "ERROR last\npartial"...)
+	require.NoError(t, os.WriteFile(path, content, 0o644))
+
+	g, err := readLogGrowth(path, 0)
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(g.content), maxTailChunk)
+	require.Equal(t, int64(len(content)-len(g.content)-len("partial")), g.skipped)
+	require.Equal(t, int64(len(content)-len("partial")), g.offset)
+	require.Equal(t, line, string(g.content[:len(line)]))
+	require.Contains(t, string(g.content), "ERROR last\n")
+}
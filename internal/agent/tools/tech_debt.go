@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"strings"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/techdebt"
+)
+
+const TechDebtToolName = "tech_debt"
+
+// defaultTechDebtLimit is the number of comments listed when the call
+// sets no limit.
+const defaultTechDebtLimit = 100
+
+//go:embed tech_debt.md
+var techDebtDescription string
+
+type TechDebtParams struct {
+	Tags          string `json:"tags,omitempty" description:"Comma-separated tags to keep: TODO, FIXME, HACK, XXX"`
+	Path          string `json:"path,omitempty" description:"Directory or part of a path to restrict the inventory to"`
+	Author        string `json:"author,omitempty" description:"Part of the blamed author or the TODO(owner) name"`
+	Query         string `json:"query,omitempty" description:"Text the comment must contain"`
+	OlderThanDays int    `json:"older_than_days,omitempty" description:"Keep comments last changed more than this many days ago"`
+	Sort          string `json:"sort,omitempty" description:"Order: path (default) or age, oldest first"`
+	Limit         int    `json:"limit,omitempty" description:"Maximum comments to list (default 100)"`
+}
+
+func NewTechDebtTool(workingDir, dataDir string) fantasy.AgentTool {
+	inv := techdebt.New(workingDir, dataDir)
+	return fantasy.NewAgentTool(
+		TechDebtToolName,
+		techDebtDescription,
+		func(ctx context.Context, params TechDebtParams, _ fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			if err := inv.Update(ctx); err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			filter := techdebt.Filter{
+				Path:      params.Path,
+				Author:    params.Author,
+				Text:      params.Query,
+				OlderThan: time.Duration(params.OlderThanDays) * 24 * time.Hour,
+				Sort:      params.Sort,
+			}
+			for tag := range strings.SplitSeq(params.Tags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					filter.Tags = append(filter.Tags, tag)
+				}
+			}
+			now := time.Now()
+			items := inv.Items(filter, now)
+			return fantasy.NewTextResponse(techdebt.Report(items, inv.Total(), cmp.Or(params.Limit, defaultTechDebtLimit), now)), nil
+		},
+	)
+}
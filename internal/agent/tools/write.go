@@ -19,6 +19,8 @@ import (
 
 	"github.com/charmbracelet/crush/internal/lsp"
 	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/remote"
+	"github.com/charmbracelet/crush/internal/staging"
 )
 
 //go:embed write.md
@@ -49,6 +51,8 @@ func NewWriteTool(
 	files history.Service,
 	filetracker filetracker.Service,
 	workingDir string,
+	stager staging.Service,
+	target *remote.Target,
 ) fantasy.AgentTool {
 	return fantasy.NewAgentTool(
 		WriteToolName,
@@ -63,7 +67,7 @@ func NewWriteTool(
 				return fantasy.ToolResponse{}, fmt.Errorf("session_id is required")
 			}
 
-			filePath := filepathext.SmartJoin(workingDir, params.FilePath)
+			filePath := filepathext.SmartJoin(workingDir, target.LocalPath(params.FilePath))
 
 			fileInfo, err := os.Stat(filePath)
 			if err == nil {
@@ -93,7 +97,7 @@ func NewWriteTool(
 
 			oldContent := ""
 			if fileInfo != nil && !fileInfo.IsDir() {
-				oldBytes, readErr := os.ReadFile(filePath)
+				oldBytes, readErr := readEditTarget(stager, sessionID, filePath)
 				if readErr == nil {
 					oldContent = string(oldBytes)
 				}
@@ -134,6 +138,14 @@ func NewWriteTool(
 				return resp, nil
 			}
 
+			if stager != nil {
+				return stageEdit(stager, sessionID, filePath, oldContent, params.Content, fileInfo == nil, WriteResponseMetadata{
+					Diff:      diff,
+					Additions: additions,
+					Removals:  removals,
+				})
+			}
+
 			err = os.WriteFile(filePath, []byte(params.Content), 0o644)
 			if err != nil {
 				return fantasy.ToolResponse{}, fmt.Errorf("error writing file: %w", err)
@@ -170,6 +182,7 @@ func NewWriteTool(
 			result = fmt.Sprintf("<result>\n%s\n</result>", result)
 			result += getDiagnostics(filePath, lspManager)
 			result += runDiagnosticCascade(ctx, lspManager, filePath)
+			result += pushToRemote(ctx, target, filePath)
 			return fantasy.WithResponseMetadata(
 				fantasy.NewTextResponse(result),
 				WriteResponseMetadata{
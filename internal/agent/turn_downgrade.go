@@ -0,0 +1,159 @@
+package agent
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/message"
+)
+
+// Reasons recorded on assistant messages of downgraded turns.
+const (
+	DowngradeSummaryFollowUp = "summary follow-up"
+	DowngradeShortPrompt     = "short prompt"
+	DowngradeQuestion        = "question without tool use"
+)
+
+// shortPromptWords is the longest prompt, in words, treated as a short
+// conversational reply ("thanks", "ok, go on").
+const shortPromptWords = 6
+
+var (
+	// filePathPattern matches tokens that look like file paths or names
+	// with an extension (internal/agent, main.go, ./run.sh).
+	filePathPattern = regexp.MustCompile(`(?:^|\s)(?:\.{0,2}/)?[\w.-]+(?:/[\w.-]+)+|\b[\w-]+\.[a-z]{1,5}\b`)
+
+	// summaryPattern matches follow-ups asking to restate the previous
+	// answer.
+	summaryPattern = regexp.MustCompile(`\b(summari[sz]e|summary|tl;?dr|recap|rephrase|reword|in short|shorter|simpler|eli5|in one sentence|bullet points?)\b`)
+
+	// questionPattern matches prompts opening with an interrogative.
+	questionPattern = regexp.MustCompile(`^(what|why|how|when|who|which|where|is|are|can|could|should|would|does|do|did|explain|define)\b`)
+)
+
+// toolWords are words implying the turn needs tools: changes to make,
+// commands to run or things in the workspace to look at.
+var toolWords = map[string]bool{
+	"add": true, "build": true, "bug": true, "change": true, "check": true,
+	"class": true, "code": true, "codebase": true, "commit": true,
+	"compile": true, "create": true, "debug": true, "delete": true,
+	"deploy": true, "diff": true, "directory": true, "edit": true,
+	"error": true, "file": true, "files": true, "find": true, "fix": true,
+	"folder": true, "function": true, "generate": true, "grep": true,
+	"implement": true, "install": true, "lint": true, "log": true,
+	"logs": true, "method": true, "migrate": true, "modify": true,
+	"module": true, "move": true, "open": true, "package": true,
+	"read": true, "refactor": true, "remove": true, "rename": true,
+	"replace": true, "repo": true, "repository": true, "review": true,
+	"run": true, "search": true, "test": true, "tests": true,
+	"update": true, "write": true,
+}
+
+// classifyTrivialTurn returns why the turn may run on the small model, or
+// an empty string when it must stay on the large one. Turns with
+// attachments, code, file references or words implying tool use are never
+// downgraded.
+func classifyTrivialTurn(prompt string, attachments int, maxChars int) string {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" || attachments > 0 || len([]rune(prompt)) > maxChars {
+		return ""
+	}
+	if strings.Contains(prompt, "```") || strings.Contains(prompt, "`") ||
+		strings.Count(prompt, "\n") > 2 || filePathPattern.MatchString(prompt) {
+		return ""
+	}
+
+	lower := strings.ToLower(prompt)
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '\''
+	})
+	for _, w := range words {
+		if toolWords[w] {
+			return ""
+		}
+	}
+
+	switch {
+	case summaryPattern.MatchString(lower):
+		return DowngradeSummaryFollowUp
+	case len(words) <= shortPromptWords:
+		return DowngradeShortPrompt
+	case strings.HasSuffix(lower, "?") || questionPattern.MatchString(lower):
+		return DowngradeQuestion
+	}
+	return ""
+}
+
+// downgradeTracker holds per-session overrides of the auto_downgrade
+// option.
+type downgradeTracker struct {
+	overrides *csync.Map[string, bool]
+}
+
+func newDowngradeTracker() *downgradeTracker {
+	return &downgradeTracker{overrides: csync.NewMap[string, bool]()}
+}
+
+// Enabled reports whether downgrades apply to the session, falling back to
+// def when the session has no override.
+func (t *downgradeTracker) Enabled(sessionID string, def bool) bool {
+	if enabled, ok := t.overrides.Get(sessionID); ok {
+		return enabled
+	}
+	return def
+}
+
+// Set overrides the option for the session.
+func (t *downgradeTracker) Set(sessionID string, enabled bool) {
+	t.overrides.Set(sessionID, enabled)
+}
+
+// routedAgent runs every call of a turn on a specific model and records
+// why it was chosen.
+type routedAgent struct {
+	SessionAgent
+	model  Model
+	reason string
+}
+
+func (r routedAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy.AgentResult, error) {
+	call.Model = &r.model
+	call.RoutingReason = r.reason
+	return r.SessionAgent.Run(ctx, call)
+}
+
+// AutoDowngradeEnabled implements Coordinator.
+func (c *coordinator) AutoDowngradeEnabled(sessionID string) bool {
+	opts := c.cfg.Config().Options
+	return c.downgrades.Enabled(sessionID, opts != nil && opts.AutoDowngrade != nil && opts.AutoDowngrade.Enabled)
+}
+
+// SetAutoDowngrade implements Coordinator.
+func (c *coordinator) SetAutoDowngrade(sessionID string, enabled bool) {
+	c.downgrades.Set(sessionID, enabled)
+}
+
+// downgradeTurn returns the small model and the reason when the turn is
+// trivial enough to skip the large model.
+func (c *coordinator) downgradeTurn(sessionID, prompt string, attachments []message.Attachment, current Model) (Model, string, bool) {
+	if !c.AutoDowngradeEnabled(sessionID) {
+		return Model{}, "", false
+	}
+	small := c.smallModel.Get()
+	if small.Model == nil || (small.ModelCfg.Provider == current.ModelCfg.Provider && small.ModelCfg.Model == current.ModelCfg.Model) {
+		return Model{}, "", false
+	}
+	var downgrade *config.AutoDowngradeOptions
+	if opts := c.cfg.Config().Options; opts != nil {
+		downgrade = opts.AutoDowngrade
+	}
+	reason := classifyTrivialTurn(prompt, len(attachments), downgrade.PromptLimit())
+	if reason == "" {
+		return Model{}, "", false
+	}
+	return small, reason, true
+}
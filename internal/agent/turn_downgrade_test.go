@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyTrivialTurn(t *testing.T) {
+	t.Parallel()
+
+	for prompt, want := range map[string]string{
+		"thanks!":         DowngradeShortPrompt,
+		"ok, sounds good": DowngradeShortPrompt,
+		"can you summarize that in a few sentences": DowngradeSummaryFollowUp,
+		"tl;dr please": DowngradeSummaryFollowUp,
+		"what is the difference between a mutex and a channel?": DowngradeQuestion,
+		"how does garbage collection work in go":                DowngradeQuestion,
+
+		"fix the failing test":                        "",
+		"what does internal/agent/agent.go do?":       "",
+		"why is `make` slow here?":                    "",
+		"please look at main.go":                      "",
+		"where is the error coming from in the repo?": "",
+		"I would like you to think about the overall approach we took earlier and tell me your thoughts": "",
+		"": "",
+	} {
+		require.Equal(t, want, classifyTrivialTurn(prompt, 0, 280), prompt)
+	}
+
+	require.Empty(t, classifyTrivialTurn("thanks!", 1, 280), "attachments are never downgraded")
+	require.Empty(t, classifyTrivialTurn("what is a monad?", 0, 10), "long prompts are never downgraded")
+}
+
+func TestDowngradeTracker(t *testing.T) {
+	t.Parallel()
+
+	tr := newDowngradeTracker()
+	require.True(t, tr.Enabled("s1", true))
+	require.False(t, tr.Enabled("s1", false))
+
+	tr.Set("s1", false)
+	require.False(t, tr.Enabled("s1", true))
+	require.True(t, tr.Enabled("s2", true), "overrides are per session")
+}
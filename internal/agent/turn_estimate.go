@@ -0,0 +1,330 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/repomap"
+)
+
+// ErrEstimateUnavailable is returned when the agent cannot estimate turns.
+var ErrEstimateUnavailable = errors.New("turn estimates are not available")
+
+// Names of the components of a turn estimate.
+const (
+	EstimateSystemPrompt = "System prompt"
+	EstimateTools        = "Tool definitions"
+	EstimateHistory      = "Conversation history"
+	EstimateRepoMap      = "Repository map"
+	EstimatePrompt       = "Prompt"
+	EstimateAttachments  = "Attachments"
+)
+
+// imageTokens is the input charged for an attached image. Providers bill
+// images by their pixel size, which is not known here; this is the cost of
+// a typical screenshot on the major providers.
+const imageTokens = 1500
+
+// EstimateComponent is the input tokens of one part of a prompt.
+type EstimateComponent struct {
+	Name   string
+	Tokens int
+}
+
+// TurnEstimate is a dry-run estimate of a turn: the input tokens of the
+// prompt it would send, by component, and its projected cost on the model
+// it would run on. Prompt caching discounts are not applied, so the cost
+// is an upper bound for the input.
+type TurnEstimate struct {
+	Provider string
+	Model    string
+	// Components are the input tokens by part of the prompt, in the order
+	// the parts are sent.
+	Components []EstimateComponent
+	// OutputTokens is the projected output: the size of the previous
+	// response, or the configured default for a new session.
+	OutputTokens int
+	// Exact reports whether the tokens were counted with the model's
+	// tokenizer rather than estimated from the text length.
+	Exact bool
+	// FlatRate reports whether the model is billed by subscription, in
+	// which case the turn costs nothing.
+	FlatRate bool
+	// CostPer1MIn and CostPer1MOut are the model prices in USD per million
+	// tokens.
+	CostPer1MIn  float64
+	CostPer1MOut float64
+}
+
+// Add appends a component, skipping empty ones.
+func (e *TurnEstimate) Add(name string, tokens int) {
+	if tokens > 0 {
+		e.Components = append(e.Components, EstimateComponent{Name: name, Tokens: tokens})
+	}
+}
+
+// InputTokens returns the total input tokens.
+func (e TurnEstimate) InputTokens() int {
+	var total int
+	for _, c := range e.Components {
+		total += c.Tokens
+	}
+	return total
+}
+
+// InputCost returns the projected cost of the input in USD.
+func (e TurnEstimate) InputCost() float64 {
+	if e.FlatRate {
+		return 0
+	}
+	return e.CostPer1MIn / 1e6 * float64(e.InputTokens())
+}
+
+// OutputCost returns the projected cost of the output in USD.
+func (e TurnEstimate) OutputCost() float64 {
+	if e.FlatRate {
+		return 0
+	}
+	return e.CostPer1MOut / 1e6 * float64(e.OutputTokens)
+}
+
+// Cost returns the projected cost of the turn in USD.
+func (e TurnEstimate) Cost() float64 {
+	return e.InputCost() + e.OutputCost()
+}
+
+// Priced reports whether the model has known prices, without which the
+// cost of a pay-per-token model cannot be projected.
+func (e TurnEstimate) Priced() bool {
+	return e.FlatRate || e.CostPer1MIn > 0 || e.CostPer1MOut > 0
+}
+
+// turnEstimator is implemented by session agents that can estimate a turn
+// without running it.
+type turnEstimator interface {
+	EstimateTurn(ctx context.Context, call SessionAgentCall) (TurnEstimate, error)
+}
+
+// tokenizers is the tokenizer registry used for estimates, or nil when its
+// model families could not be loaded.
+var tokenizers = sync.OnceValue(func() *repomap.DefaultTokenCounterProvider {
+	repomap.InitTiktokenLoader(repomap.TiktokenCacheDir())
+	p, err := repomap.NewDefaultTokenCounterProvider(repomap.DefaultSupportJSON())
+	if err != nil {
+		slog.Warn("Failed to load the tokenizer registry", "error", err)
+		return nil
+	}
+	return p
+})
+
+// estimateCounter counts tokens with the tokenizer of a model, falling
+// back to a length heuristic for models the registry does not know.
+type estimateCounter struct {
+	ctx     context.Context
+	model   string
+	counter repomap.TokenCounter
+}
+
+func newEstimateCounter(ctx context.Context, model string) estimateCounter {
+	c := estimateCounter{ctx: ctx, model: model}
+	if p := tokenizers(); p != nil {
+		c.counter, _ = p.CounterForModel(model)
+	}
+	return c
+}
+
+// exact reports whether counts come from the model's tokenizer.
+func (c estimateCounter) exact() bool {
+	return c.counter != nil
+}
+
+func (c estimateCounter) count(text string) int {
+	if text == "" {
+		return 0
+	}
+	if c.counter != nil {
+		if n, err := c.counter.Count(c.ctx, c.model, text); err == nil {
+			return n
+		}
+	}
+	return repomap.EstimateTokens(text, "default")
+}
+
+// countMessages returns the tokens of the text and files of msgs. Media is
+// counted as images.
+func (c estimateCounter) countMessages(msgs []fantasy.Message) int {
+	var total int
+	for _, msg := range msgs {
+		for _, part := range msg.Content {
+			if p, ok := fantasy.AsMessagePart[fantasy.TextPart](part); ok {
+				total += c.count(p.Text)
+			} else if p, ok := fantasy.AsMessagePart[fantasy.ReasoningPart](part); ok {
+				total += c.count(p.Text)
+			} else if p, ok := fantasy.AsMessagePart[fantasy.ToolCallPart](part); ok {
+				total += c.count(p.ToolName + p.Input)
+			} else if p, ok := fantasy.AsMessagePart[fantasy.ToolResultPart](part); ok {
+				total += c.countToolResult(p.Output)
+			} else if p, ok := fantasy.AsMessagePart[fantasy.FilePart](part); ok {
+				total += c.countFile(p)
+			}
+		}
+	}
+	return total
+}
+
+func (c estimateCounter) countToolResult(output fantasy.ToolResultOutputContent) int {
+	if o, ok := fantasy.AsToolResultOutputType[fantasy.ToolResultOutputContentText](output); ok {
+		return c.count(o.Text)
+	}
+	if o, ok := fantasy.AsToolResultOutputType[fantasy.ToolResultOutputContentError](output); ok && o.Error != nil {
+		return c.count(o.Error.Error())
+	}
+	if o, ok := fantasy.AsToolResultOutputType[fantasy.ToolResultOutputContentMedia](output); ok {
+		return c.count(o.Text) + imageTokens
+	}
+	return 0
+}
+
+func (c estimateCounter) countFile(f fantasy.FilePart) int {
+	if strings.HasPrefix(f.MediaType, "image/") {
+		return imageTokens
+	}
+	return c.count(string(f.Data))
+}
+
+// countTools returns the tokens of the tool definitions sent to the model.
+func (c estimateCounter) countTools(tools []fantasy.AgentTool) int {
+	var total int
+	for _, tool := range tools {
+		info, err := json.Marshal(tool.Info())
+		if err != nil {
+			continue
+		}
+		total += c.count(string(info))
+	}
+	return total
+}
+
+// EstimateTurn counts the input of the turn call would run, without
+// running it. The repository map is injected by an extension and is not
+// counted here.
+func (a *sessionAgent) EstimateTurn(ctx context.Context, call SessionAgentCall) (TurnEstimate, error) {
+	model := a.largeModel.Get()
+	if call.Model != nil && call.Model.Model != nil {
+		model = *call.Model
+	}
+	counter := newEstimateCounter(ctx, model.ModelCfg.Model)
+	est := TurnEstimate{
+		Provider:     model.ModelCfg.Provider,
+		Model:        model.ModelCfg.Model,
+		Exact:        counter.exact(),
+		FlatRate:     model.FlatRate,
+		CostPer1MIn:  model.CatwalkCfg.CostPer1MIn,
+		CostPer1MOut: model.CatwalkCfg.CostPer1MOut,
+	}
+
+	system := a.systemPrompt.Get()
+	if s := connectedMCPInstructions(); s != "" {
+		system += "\n\n<mcp-instructions>\n" + s + "\n</mcp-instructions>"
+	}
+	est.Add(EstimateSystemPrompt, counter.count(a.systemPromptPrefix.Get())+counter.count(system))
+	est.Add(EstimateTools, counter.countTools(applyPhaseFilter(a.tools.Copy(), call.Prompt)))
+
+	var msgs []message.Message
+	if call.SessionID != "" {
+		sess, err := a.sessions.Get(ctx, call.SessionID)
+		if err != nil {
+			return TurnEstimate{}, fmt.Errorf("failed to get session: %w", err)
+		}
+		if msgs, err = a.getSessionMessages(ctx, sess); err != nil {
+			return TurnEstimate{}, fmt.Errorf("failed to get session messages: %w", err)
+		}
+		est.OutputTokens = int(sess.CompletionTokens)
+	}
+	history, files := a.preparePrompt(msgs, model.CatwalkCfg.SupportsImages, call.Attachments...)
+	est.Add(EstimateHistory, counter.countMessages(history))
+
+	est.Add(EstimatePrompt, counter.count(message.PromptWithTextAttachments(call.Prompt, call.Attachments)))
+	var attached int
+	for _, f := range files {
+		attached += counter.countFile(f)
+	}
+	est.Add(EstimateAttachments, attached)
+	return est, nil
+}
+
+// connectedMCPInstructions returns the instructions of the connected MCP
+// servers, which are appended to the system prompt.
+func connectedMCPInstructions() string {
+	var instructions strings.Builder
+	for _, server := range mcp.GetStates() {
+		if server.State != mcp.StateConnected {
+			continue
+		}
+		if s := server.Client.InitializeResult().Instructions; s != "" {
+			instructions.WriteString(s)
+			instructions.WriteString("\n\n")
+		}
+	}
+	return instructions.String()
+}
+
+// EstimateTurn implements Coordinator.
+func (c *coordinator) EstimateTurn(ctx context.Context, sessionID, prompt string, attachments ...message.Attachment) (TurnEstimate, error) {
+	if err := c.readyWg.Wait(); err != nil {
+		return TurnEstimate{}, err
+	}
+	estimator, ok := c.currentAgent.(turnEstimator)
+	if !ok {
+		return TurnEstimate{}, ErrEstimateUnavailable
+	}
+
+	model := c.currentAgent.Model()
+	if small, _, ok := c.downgradeTurn(sessionID, prompt, attachments, model); ok {
+		model = small
+	}
+	if !model.CatwalkCfg.SupportsImages {
+		var text []message.Attachment
+		for _, att := range attachments {
+			if att.IsText() {
+				text = append(text, att)
+			}
+		}
+		attachments = text
+	}
+
+	est, err := estimator.EstimateTurn(ctx, SessionAgentCall{
+		SessionID:   sessionID,
+		Prompt:      prompt,
+		Attachments: attachments,
+		Model:       &model,
+	})
+	if err != nil {
+		return TurnEstimate{}, err
+	}
+
+	var opts *config.CostEstimateOptions
+	if o := c.cfg.Config().Options; o != nil {
+		opts = o.CostEstimate
+	}
+	if est.OutputTokens <= 0 {
+		est.OutputTokens = opts.ProjectedOutputTokens()
+	}
+	maxTokens := model.CatwalkCfg.DefaultMaxTokens
+	if model.ModelCfg.MaxTokens != 0 {
+		maxTokens = model.ModelCfg.MaxTokens
+	}
+	if maxTokens > 0 {
+		est.OutputTokens = min(est.OutputTokens, int(maxTokens))
+	}
+	return est, nil
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTurnEstimateCost(t *testing.T) {
+	t.Parallel()
+
+	var est TurnEstimate
+	est.Add(EstimateSystemPrompt, 600_000)
+	est.Add(EstimateRepoMap, 0)
+	est.Add(EstimatePrompt, 400_000)
+	est.OutputTokens = 100_000
+	require.Len(t, est.Components, 2, "empty components are skipped")
+	require.Equal(t, 1_000_000, est.InputTokens())
+	require.False(t, est.Priced())
+
+	est.CostPer1MIn = 3
+	est.CostPer1MOut = 15
+	require.True(t, est.Priced())
+	require.InDelta(t, 3.0, est.InputCost(), 1e-9)
+	require.InDelta(t, 1.5, est.OutputCost(), 1e-9)
+	require.InDelta(t, 4.5, est.Cost(), 1e-9)
+
+	est.FlatRate = true
+	require.Zero(t, est.Cost())
+}
+
+func TestEstimateCounter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	text := "The quick brown fox jumps over the lazy dog."
+
+	heuristic := newEstimateCounter(ctx, "no-such-model")
+	require.False(t, heuristic.exact())
+	require.Positive(t, heuristic.count(text))
+	require.Zero(t, heuristic.count(""))
+
+	exact := newEstimateCounter(ctx, "gpt-4")
+	require.True(t, exact.exact())
+	require.Equal(t, 10, exact.count(text))
+
+	msgs := []fantasy.Message{
+		fantasy.NewUserMessage(text),
+		{
+			Role: fantasy.MessageRoleAssistant,
+			Content: []fantasy.MessagePart{
+				fantasy.ToolCallPart{ToolName: "view", Input: `{"file_path":"main.go"}`},
+			},
+		},
+		{
+			Role: fantasy.MessageRoleTool,
+			Content: []fantasy.MessagePart{
+				fantasy.ToolResultPart{Output: fantasy.ToolResultOutputContentMedia{MediaType: "image/png"}},
+			},
+		},
+		{
+			Role: fantasy.MessageRoleUser,
+			Content: []fantasy.MessagePart{
+				fantasy.FilePart{MediaType: "image/png", Data: []byte("png")},
+			},
+		},
+	}
+	total := exact.countMessages(msgs)
+	require.Greater(t, total, 10+2*imageTokens)
+	require.Less(t, total, 10+2*imageTokens+30)
+}
@@ -0,0 +1,262 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/devenv"
+	"github.com/charmbracelet/crush/internal/filetracker"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/remote"
+	"github.com/charmbracelet/crush/internal/shell"
+)
+
+const (
+	defaultVerifyIterations = 2
+	defaultVerifyTimeout    = 5 * time.Minute
+	// verifyOutputLimit caps the output of a failed check sent back to the
+	// agent; the tail is kept since that is where failures are reported.
+	verifyOutputLimit = 4000
+)
+
+// VerificationMarker starts every transcript entry of the verification
+// pass, so its follow-up prompts and results are easy to tell apart from
+// the user's own messages.
+const VerificationMarker = "[verification]"
+
+// CheckResult is the outcome of one verification check.
+type CheckResult struct {
+	Name     string
+	Command  string
+	Passed   bool
+	Output   string
+	Duration time.Duration
+}
+
+// Verifier runs the configured checks after a turn that wrote files. A
+// failing run is sent back to the agent as a follow-up prompt, up to
+// MaxIterations times, before the turn is reported complete.
+type Verifier struct {
+	Checks        []config.VerificationCheck
+	MaxIterations int
+	Timeout       time.Duration
+	WorkingDir    string
+	FileTracker   filetracker.Service
+	// Remote, when set, runs the checks on the remote target, in the
+	// directory matching WorkingDir.
+	Remote *remote.Target
+	// DevEnv, when set and there is no Remote, runs the checks inside the
+	// project's development environment.
+	DevEnv *devenv.Env
+
+	// run executes one check; tests replace it.
+	run func(ctx context.Context, dir, command string) (string, error)
+}
+
+// NewVerifier returns the verifier configured by opts, or nil when
+// verification is disabled or has no checks.
+func NewVerifier(opts *config.VerificationOptions, workingDir string, ft filetracker.Service) *Verifier {
+	if opts == nil || !opts.Enabled || len(opts.Checks) == 0 {
+		return nil
+	}
+	v := &Verifier{
+		Checks:        opts.Checks,
+		MaxIterations: defaultVerifyIterations,
+		Timeout:       defaultVerifyTimeout,
+		WorkingDir:    workingDir,
+		FileTracker:   ft,
+	}
+	if opts.MaxIterations > 0 {
+		v.MaxIterations = opts.MaxIterations
+	}
+	if opts.TimeoutSeconds > 0 {
+		v.Timeout = time.Duration(opts.TimeoutSeconds) * time.Second
+	}
+	return v
+}
+
+// HasChanges reports whether the session wrote files since the given time.
+// Without a file tracker every turn is verified.
+func (v *Verifier) HasChanges(ctx context.Context, sessionID string, since time.Time) bool {
+	if v.FileTracker == nil {
+		return true
+	}
+	return v.FileTracker.HasWritesSince(ctx, sessionID, since)
+}
+
+// Run runs every check in order and returns their results.
+func (v *Verifier) Run(ctx context.Context) []CheckResult {
+	run := v.run
+	if run == nil {
+		run = runCheck
+		if v.Remote != nil {
+			run = v.runRemoteCheck
+		} else if v.DevEnv != nil {
+			run = v.runDevEnvCheck
+		}
+	}
+	results := make([]CheckResult, 0, len(v.Checks))
+	for _, check := range v.Checks {
+		if ctx.Err() != nil {
+			break
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, v.Timeout)
+		start := time.Now()
+		out, err := run(checkCtx, v.WorkingDir, check.Command)
+		if checkCtx.Err() == context.DeadlineExceeded {
+			out = strings.TrimRight(out, "\n") + fmt.Sprintf("\ntimed out after %s", v.Timeout)
+		}
+		cancel()
+		results = append(results, CheckResult{
+			Name:     checkName(check),
+			Command:  check.Command,
+			Passed:   err == nil,
+			Output:   out,
+			Duration: time.Since(start),
+		})
+	}
+	return results
+}
+
+func runCheck(ctx context.Context, dir, command string) (string, error) {
+	var out bytes.Buffer
+	err := shell.Run(ctx, shell.RunOptions{
+		Command: command,
+		Cwd:     dir,
+		Env:     os.Environ(),
+		Stdout:  &out,
+		Stderr:  &out,
+	})
+	return out.String(), err
+}
+
+func (v *Verifier) runRemoteCheck(ctx context.Context, dir, command string) (string, error) {
+	var out bytes.Buffer
+	err := v.Remote.Exec(ctx, v.Remote.RemotePath(dir), command, &out, &out)
+	return out.String(), err
+}
+
+func (v *Verifier) runDevEnvCheck(ctx context.Context, dir, command string) (string, error) {
+	var out bytes.Buffer
+	err := v.DevEnv.Exec(ctx, dir, command, &out, &out)
+	return out.String(), err
+}
+
+func checkName(check config.VerificationCheck) string {
+	if check.Name != "" {
+		return check.Name
+	}
+	return check.Command
+}
+
+// failedChecks returns the results that did not pass.
+func failedChecks(results []CheckResult) []CheckResult {
+	var failed []CheckResult
+	for _, r := range results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+func checkNames(results []CheckResult) string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// followUpPrompt asks the agent to fix the failed checks.
+func (v *Verifier) followUpPrompt(failed []CheckResult, attempt int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s Checks failed after your changes (attempt %d of %d): %s.\n\n",
+		VerificationMarker, attempt, v.MaxIterations, checkNames(failed))
+	for _, r := range failed {
+		out := strings.TrimSpace(r.Output)
+		if len(out) > verifyOutputLimit {
+			out = "…" + out[len(out)-verifyOutputLimit:]
+		}
+		fmt.Fprintf(&b, "## %s\n$ %s\n```\n%s\n```\n\n", r.Name, r.Command, out)
+	}
+	b.WriteString("Fix the failures, then finish your answer. Do not report the task as complete while a check fails.")
+	return b.String()
+}
+
+// verifyState tracks the verification of one user turn across its
+// follow-up prompts.
+type verifyState struct {
+	turnStart time.Time
+	attempts  int
+}
+
+// verifyTurn runs the verification pass at the end of a successful run. It
+// returns true when it queued a follow-up prompt, in which case the turn is
+// not complete yet.
+func (a *sessionAgent) verifyTurn(ctx context.Context, call SessionAgentCall, runStart time.Time) bool {
+	v := a.verifier
+	if v == nil || ctx.Err() != nil {
+		return false
+	}
+	state, ok := a.verifyStates.Get(call.SessionID)
+	if !call.verification || !ok {
+		state = verifyState{turnStart: runStart}
+	}
+	if !v.HasChanges(ctx, call.SessionID, state.turnStart) {
+		a.verifyStates.Del(call.SessionID)
+		return false
+	}
+
+	results := v.Run(ctx)
+	if ctx.Err() != nil {
+		a.verifyStates.Del(call.SessionID)
+		return false
+	}
+	failed := failedChecks(results)
+	if len(failed) == 0 {
+		a.verifyStates.Del(call.SessionID)
+		a.recordVerification(ctx, call.SessionID, fmt.Sprintf("%s All checks passed: %s.", VerificationMarker, checkNames(results)))
+		return false
+	}
+	if state.attempts >= v.MaxIterations {
+		a.verifyStates.Del(call.SessionID)
+		a.recordVerification(ctx, call.SessionID, fmt.Sprintf("%s Checks still failing after %d attempts: %s. Stopping here; the failures need a closer look.",
+			VerificationMarker, state.attempts, checkNames(failed)))
+		return false
+	}
+
+	state.attempts++
+	a.verifyStates.Set(call.SessionID, state)
+	followUp := call
+	followUp.Prompt = v.followUpPrompt(failed, state.attempts)
+	followUp.Attachments = nil
+	followUp.SubmittedAt = time.Now().Unix()
+	followUp.verification = true
+	queued, _ := a.messageQueue.Get(call.SessionID)
+	a.messageQueue.Set(call.SessionID, append([]SessionAgentCall{followUp}, queued...))
+	return true
+}
+
+// recordVerification adds the outcome of the pass to the transcript.
+func (a *sessionAgent) recordVerification(ctx context.Context, sessionID, text string) {
+	model := a.largeModel.Get()
+	_, err := a.messages.Create(ctx, sessionID, message.CreateMessageParams{
+		Role: message.Assistant,
+		Parts: []message.ContentPart{
+			message.TextContent{Text: text},
+			message.Finish{Reason: message.FinishReasonEndTurn, Time: time.Now().Unix()},
+		},
+		Model:    model.ModelCfg.Model,
+		Provider: model.ModelCfg.Provider,
+	})
+	if err != nil {
+		slog.Error("Failed to record verification result", "error", err)
+	}
+}
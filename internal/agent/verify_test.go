@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVerifier(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewVerifier(nil, "", nil))
+	require.Nil(t, NewVerifier(&config.VerificationOptions{Enabled: true}, "", nil))
+	require.Nil(t, NewVerifier(&config.VerificationOptions{
+		Checks: []config.VerificationCheck{{Command: "true"}},
+	}, "", nil))
+
+	v := NewVerifier(&config.VerificationOptions{
+		Enabled:        true,
+		Checks:         []config.VerificationCheck{{Command: "true"}},
+		TimeoutSeconds: 10,
+	}, "/repo", nil)
+	require.NotNil(t, v)
+	require.Equal(t, defaultVerifyIterations, v.MaxIterations)
+	require.Equal(t, 10*time.Second, v.Timeout)
+	require.Equal(t, "/repo", v.WorkingDir)
+}
+
+func TestVerifierRun(t *testing.T) {
+	t.Parallel()
+
+	v := &Verifier{
+		Checks: []config.VerificationCheck{
+			{Name: "build", Command: "go build ./..."},
+			{Command: "go test ./internal/..."},
+		},
+		MaxIterations: 2,
+		Timeout:       time.Minute,
+		run: func(_ context.Context, _, command string) (string, error) {
+			if strings.HasPrefix(command, "go test") {
+				return "--- FAIL: TestThing", errors.New("exit status 1")
+			}
+			return "", nil
+		},
+	}
+
+	results := v.Run(t.Context())
+	require.Len(t, results, 2)
+	require.True(t, results[0].Passed)
+	require.Equal(t, "build", results[0].Name)
+	require.False(t, results[1].Passed)
+	require.Equal(t, "go test ./internal/...", results[1].Name)
+
+	failed := failedChecks(results)
+	require.Len(t, failed, 1)
+
+	prompt := v.followUpPrompt(failed, 1)
+	require.True(t, strings.HasPrefix(prompt, VerificationMarker))
+	require.Contains(t, prompt, "attempt 1 of 2")
+	require.Contains(t, prompt, "$ go test ./internal/...")
+	require.Contains(t, prompt, "--- FAIL: TestThing")
+}
+
+func TestVerifierFollowUpTruncatesOutput(t *testing.T) {
+	t.Parallel()
+
+	v := &Verifier{MaxIterations: 1}
+	out := strings.Repeat("a", verifyOutputLimit) + "FAIL at the end"
+	prompt := v.followUpPrompt([]CheckResult{{Name: "test", Command: "make test", Output: out}}, 1)
+	require.Contains(t, prompt, "FAIL at the end")
+	require.Contains(t, prompt, "…")
+	require.Less(t, len(prompt), len(out)+500)
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/charmbracelet/crush/internal/session"
 	"github.com/charmbracelet/crush/internal/shell"
 	"github.com/charmbracelet/crush/internal/skills"
+	"github.com/charmbracelet/crush/internal/staging"
 	"github.com/charmbracelet/crush/internal/ui/anim"
 	"github.com/charmbracelet/crush/internal/ui/styles"
 	"github.com/charmbracelet/crush/internal/update"
@@ -71,8 +72,17 @@ type App struct {
 
 	RewindService rewind.Service // XRUSH: rewind service
 
+	Staging staging.Service // XRUSH: per-hunk edit review staging area
+
+	SessionLocks *session.Locks // XRUSH: advisory per-session run locks
+
 	ExtHost *ext.ExtensionHost // XRUSH: extension host
 
+	Completer ext.TextCompleter // XRUSH: small-model text completer, nil if unavailable
+
+	// XRUSH: sessions whose interrupted responses were recovered at startup
+	RecoveredSessions []string
+
 	config *config.ConfigStore
 	DB     *sql.DB
 
@@ -112,6 +122,9 @@ func New(ctx context.Context, conn *sql.DB, store *config.ConfigStore, skillsMgr
 		FileTracker: filetracker.NewService(q),
 		LSPManager:  lsp.NewManager(store),
 		Skills:      skillsMgr,
+		Staging:     staging.NewService(files),
+
+		SessionLocks: newSessionLocks(q), // XRUSH: session locks
 
 		globalCtx: ctx,
 
@@ -125,6 +138,7 @@ func New(ctx context.Context, conn *sql.DB, store *config.ConfigStore, skillsMgr
 	}
 
 	app.setupEvents()
+	app.recoverInterruptedResponses(ctx) // XRUSH: crash recovery
 
 	// Check for updates in the background.
 	if !cfg.Options.DisableUpdateCheck {
@@ -169,6 +183,7 @@ func New(ctx context.Context, conn *sql.DB, store *config.ConfigStore, skillsMgr
 		updateLSPState(name, client.GetServerState(), nil, client, 0)
 	})
 	go app.LSPManager.TrackConfigured()
+	wireServerLifecycle(ctx, app, store) // XRUSH: eager LSP and idle shutdown
 
 	// Initialize the diagnostic watcher for background file monitoring.
 	dw, err := tools.NewDiagnosticWatcher(app.LSPManager, store.WorkingDir())
@@ -257,6 +272,12 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt,
 		}
 	}
 
+	// XRUSH: a configured permission policy replaces blanket auto-approval.
+	policy, err := app.loadPermissionPolicy()
+	if err != nil {
+		return err
+	}
+
 	var (
 		spinner   *format.Spinner
 		stdoutTTY bool
@@ -324,9 +345,17 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt,
 		slog.Info("Created session for non-interactive run", "session_id", sess.ID)
 	}
 
-	// Automatically approve all permission requests for this non-interactive
-	// session.
-	app.Permissions.AutoApproveSession(sess.ID)
+	if policy != nil { // XRUSH: permission policy
+		closeAudit, err := app.applyPermissionPolicy(sess.ID, policy)
+		if err != nil {
+			return err
+		}
+		defer closeAudit()
+	} else {
+		// Automatically approve all permission requests for this
+		// non-interactive session.
+		app.Permissions.AutoApproveSession(sess.ID)
+	}
 
 	type response struct {
 		result *fantasy.AgentResult
@@ -525,6 +554,7 @@ func (app *App) setupEvents() {
 	if app.Skills != nil {
 		setupSubscriber(ctx, app.serviceEventsWG, "skills", app.Skills.SubscribeEvents, app.events)
 	}
+	wireSessionCleanup(ctx, app) // XRUSH: drop in-memory state of deleted sessions
 	cleanupFunc := func(context.Context) error {
 		cancel()
 		app.serviceEventsWG.Wait()
@@ -577,6 +607,8 @@ func (app *App) InitCoderAgent(ctx context.Context) error {
 		app.agentNotifications,
 		app.Skills,
 		app.ExtHost, // XRUSH: pass extension host to coordinator
+		agent.WithStaging(app.Staging),
+		agent.WithSessionLocks(app.SessionLocks), // XRUSH: session locks
 	)
 	if err != nil {
 		slog.Error("Failed to create coder agent", "err", err)
@@ -6,12 +6,15 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"time"
 
 	"charm.land/catwalk/pkg/catwalk"
 	"charm.land/fantasy"
 
 	"github.com/charmbracelet/crush/internal/agent"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/db"
 	"github.com/charmbracelet/crush/internal/ext"
@@ -20,6 +23,7 @@ import (
 	"github.com/charmbracelet/crush/internal/lcm"
 	"github.com/charmbracelet/crush/internal/lcm/explorer"
 	"github.com/charmbracelet/crush/internal/lcm/nudge"
+	"github.com/charmbracelet/crush/internal/pubsub"
 	"github.com/charmbracelet/crush/internal/rewind"
 	"github.com/charmbracelet/crush/internal/session"
 )
@@ -714,12 +718,134 @@ func wireMessageDecorator(app *App, q db.Querier, conn *sql.DB, store *config.Co
 		if cfg.Options.LCM.ExplorerOutputProfile != "" {
 			decoratorCfg.ExplorerOutputProfile = explorer.OutputProfile(cfg.Options.LCM.ExplorerOutputProfile)
 		}
+		for path, profile := range cfg.Options.LCM.ExplorerPathProfiles {
+			if decoratorCfg.ExplorerPathProfiles == nil {
+				decoratorCfg.ExplorerPathProfiles = make(map[string]explorer.OutputProfile)
+			}
+			decoratorCfg.ExplorerPathProfiles[path] = explorer.OutputProfile(profile)
+		}
+		if s := cfg.Options.LCM.SQLiteSampling; s != nil {
+			decoratorCfg.SQLiteSampling = &explorer.SQLiteSampling{
+				SampleRows:    s.SampleRows,
+				MaxTables:     s.MaxTables,
+				MaxCellLength: s.MaxCellLength,
+				SkipRowCounts: s.SkipRowCounts,
+			}
+		}
+		if n := cfg.Options.LCM.ArchiveNesting; n != nil {
+			decoratorCfg.ArchiveNesting = &explorer.ArchiveNesting{
+				MaxDepth:      n.MaxDepth,
+				MaxEntryBytes: n.MaxEntryBytes,
+			}
+		}
+		if l := cfg.Options.LCM.ExplorerOutputLimits; l != nil {
+			decoratorCfg.OutputLimits = map[explorer.OutputProfile]*explorer.OutputLimits{
+				explorer.OutputProfileParity:      outputLimits(l.Parity),
+				explorer.OutputProfileEnhancement: outputLimits(l.Enhancement),
+			}
+		}
+		decoratorCfg.RejectUnsafeArchives = cfg.Options.LCM.RejectUnsafeArchives
+	}
+	if cfg.Options.RemoteFetchEnabled() {
+		decoratorCfg.RemoteFetch = remoteFetchOptions(cfg.Options)
+	}
+	if cfg.Options != nil && cfg.Options.LCM != nil && cfg.Options.LCM.ExploreCache != nil {
+		decoratorCfg.ExploreCache = exploreCache(cfg.Options.LCM.ExploreCache, queries)
 	}
 
 	app.Messages = lcm.NewMessageDecorator(app.Messages, mgr, queries, conn, decoratorCfg)
 	slog.Info("Message decorator wired with LCM support")
 }
 
+// outputLimits converts the output limits of one explorer profile. A nil
+// config keeps the defaults.
+func outputLimits(l *config.OutputLimitsOptions) *explorer.OutputLimits {
+	if l == nil {
+		return nil
+	}
+	return &explorer.OutputLimits{
+		SectionItems: l.SectionItems,
+		SectionLines: l.SectionLines,
+		TotalLines:   l.TotalLines,
+		SampleSize:   l.SampleSize,
+	}
+}
+
+// DatabaseSettings converts the database config into connection settings
+// for db.Connect. A nil config keeps the defaults.
+func DatabaseSettings(opts *config.Options) db.Settings {
+	if opts == nil || opts.Database == nil {
+		return db.Settings{}
+	}
+	d := opts.Database
+	return db.Settings{
+		JournalMode:     d.JournalMode,
+		BusyTimeout:     time.Duration(d.BusyTimeoutMs) * time.Millisecond,
+		CacheSizeKiB:    d.CacheSizeKiB,
+		MmapSize:        d.MmapSizeBytes,
+		ConnMaxIdleTime: time.Duration(d.ConnMaxIdleSeconds) * time.Second,
+	}
+}
+
+// wireServerLifecycle starts the eager LSP servers and the idle shutdown
+// watchers configured under options.startup. MCP servers deferred by
+// lazy_mcp are started by the coordinator before the first turn.
+func wireServerLifecycle(ctx context.Context, app *App, store *config.ConfigStore) {
+	opts := store.Config().Options
+	if opts == nil || opts.Startup == nil {
+		return
+	}
+	if len(opts.Startup.EagerLSP) > 0 {
+		go app.LSPManager.StartEager(ctx, opts.Startup.EagerLSP)
+	}
+	if idle := opts.Startup.IdleShutdown; idle > 0 {
+		go app.LSPManager.WatchIdle(ctx, idle)
+		go mcp.WatchIdle(ctx, idle)
+		slog.Info("Idle shutdown enabled for LSP and MCP servers", "idle", idle)
+	}
+}
+
+// exploreCache builds the exploration cache selected by opts.
+func exploreCache(opts *config.ExploreCacheOptions, q db.Querier) explorer.ExploreCache {
+	switch opts.Backend {
+	case "", "memory":
+		return explorer.NewMemoryCache(opts.MaxEntries)
+	case "sqlite":
+		return lcm.NewSQLiteExploreCache(q, opts.MaxEntries)
+	default:
+		slog.Warn("Unknown explore cache backend, using memory", "backend", opts.Backend)
+		return explorer.NewMemoryCache(opts.MaxEntries)
+	}
+}
+
+// remoteFetchOptions builds explorer fetch options from the LCM remote
+// fetch config. Header values are expanded from the environment so tokens
+// stay out of the config file.
+func remoteFetchOptions(opts *config.Options) *explorer.RemoteOptions {
+	rf := opts.LCM.RemoteFetch
+	headers := make(map[string]map[string]string, len(rf.Headers))
+	for host, values := range rf.Headers {
+		expanded := make(map[string]string, len(values))
+		for name, value := range values {
+			expanded[name] = os.ExpandEnv(value)
+		}
+		headers[host] = expanded
+	}
+	fetchers := explorer.DefaultFetchers(headers)
+	fetchers["s3"] = &explorer.S3Fetcher{Endpoint: rf.S3Endpoint}
+
+	ttl := time.Hour
+	if rf.CacheTTLSeconds != 0 {
+		ttl = time.Duration(max(rf.CacheTTLSeconds, 0)) * time.Second
+	}
+	return &explorer.RemoteOptions{
+		Fetchers: fetchers,
+		MaxBytes: rf.MaxBytes,
+		CacheDir: filepath.Join(opts.DataDirectory, "explorer-cache"),
+		CacheTTL: ttl,
+	}
+}
+
 // [XRUSH: end]
 
 // [XRUSH: begin: wireLCMModelOutputLimit]
@@ -830,3 +956,33 @@ var (
 
 // [XRUSH: end]
 // [XRUSH: end: rewind service and agent config restoration]
+
+// newSessionLocks returns the session locks of this process. A session
+// taken over by another process drops its repo map injection records,
+// which only track this process's runs.
+func newSessionLocks(q db.Querier) *session.Locks {
+	locks := session.NewLocks(q)
+	locks.OnLost(extensions.TheRepomapExtension.ForgetInjections)
+	return locks
+}
+
+// wireSessionCleanup drops the in-memory state kept for a session once it
+// is deleted, so a long-lived process does not hold on to it.
+func wireSessionCleanup(ctx context.Context, app *App) {
+	app.serviceEventsWG.Go(func() {
+		events := app.Sessions.Subscribe(ctx)
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event.Type == pubsub.DeletedEvent {
+					extensions.TheRepomapExtension.ForgetSession(event.Payload.ID)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
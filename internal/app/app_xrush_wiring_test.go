@@ -64,6 +64,11 @@ func (s *stubCoordinator) ResolveLCMModel(_ context.Context, selected config.Sel
 	return s.model, nil
 }
 
+func (s *stubCoordinator) AutoDowngradeEnabled(string) bool { return false }
+func (s *stubCoordinator) SetAutoDowngrade(string, bool)    {}
+func (s *stubCoordinator) EstimateTurn(context.Context, string, string, ...message.Attachment) (agent.TurnEstimate, error) {
+	return agent.TurnEstimate{}, agent.ErrEstimateUnavailable
+}
 
 func TestResolveLCMModelReturnsRealProvider(t *testing.T) {
 	t.Parallel()
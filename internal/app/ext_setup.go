@@ -18,6 +18,7 @@ import (
 
 func setupExtensions(ctx context.Context, app *App, conn *sql.DB, q db.Querier, sessions session.Service, messages message.Service, store *config.ConfigStore) {
 	completer := newTextCompleter(store)
+	app.Completer = completer
 
 	// [XRUSH: begin: create rewind service before bootstrap]
 	// Create the rewind service before bootstrap so that the RewindExtension
@@ -139,6 +140,10 @@ func setupExtensions(ctx context.Context, app *App, conn *sql.DB, q db.Querier,
 	}
 	// [XRUSH: end]
 
+	// [XRUSH: begin: wire repo map refresh events]
+	setupSubscriber(app.eventsCtx, app.serviceEventsWG, "repomap-refresh", extensions.TheRepomapExtension.SubscribeRefresh, app.events)
+	// [XRUSH: end]
+
 	app.cleanupFuncs = append(app.cleanupFuncs, func(_ context.Context) error {
 		if app.ExtHost != nil {
 			return app.ExtHost.Shutdown(context.Background())
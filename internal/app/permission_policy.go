@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/crush/internal/permission"
+)
+
+// permissionAuditFile is the JSON lines file, under the data directory's
+// logs folder, that policy decisions are appended to.
+const permissionAuditFile = "permission-audit.jsonl"
+
+// loadPermissionPolicy loads the configured permission policy. It returns
+// nil when none is configured.
+func (app *App) loadPermissionPolicy() (*permission.Policy, error) {
+	path := app.config.Config().Options.PermissionPolicy
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(app.config.WorkingDir(), path)
+	}
+	return permission.LoadPolicy(path)
+}
+
+// applyPermissionPolicy makes policy decide the session's permission
+// requests, auditing every decision. The returned function closes the audit
+// log.
+func (app *App) applyPermissionPolicy(sessionID string, policy *permission.Policy) (func(), error) {
+	svc, ok := app.Permissions.(permission.PolicyService)
+	if !ok {
+		return nil, fmt.Errorf("permission service does not support policies")
+	}
+
+	closeAudit := func() {}
+	audit := permission.NewAuditLog(nil)
+	logsDir := filepath.Join(app.config.Config().Options.DataDirectory, "logs")
+	f, err := openAuditFile(filepath.Join(logsDir, permissionAuditFile))
+	if err != nil {
+		slog.Warn("Permission decisions will only be logged", "error", err)
+	} else {
+		audit = permission.NewAuditLog(f)
+		closeAudit = func() { _ = f.Close() }
+	}
+
+	svc.ApplyPolicy(sessionID, policy, audit)
+	slog.Info("Permission policy applied to non-interactive session", "session_id", sessionID, "rules", len(policy.Rules))
+	return closeAudit, nil
+}
+
+func openAuditFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return f, nil
+}
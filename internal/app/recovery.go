@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// interruptedGrace is how long an unfinished assistant message must have
+// been idle before it is treated as interrupted. Streaming responses are
+// written many times a second, so this leaves responses still streaming in
+// another crush process that shares the database alone.
+const interruptedGrace = time.Minute
+
+// recoverInterruptedResponses finishes assistant responses cut off by a
+// crash or terminal disconnect, keeping their partial content, and records
+// the sessions they belong to.
+func (app *App) recoverInterruptedResponses(ctx context.Context) {
+	recovered, err := app.Messages.RecoverInterrupted(ctx, time.Now().Add(-interruptedGrace))
+	if err != nil {
+		slog.Warn("Failed to recover interrupted responses", "error", err)
+	}
+	for _, msg := range recovered {
+		slog.Info("Recovered interrupted response", "session_id", msg.SessionID, "message_id", msg.ID)
+		if !slices.Contains(app.RecoveredSessions, msg.SessionID) {
+			app.RecoveredSessions = append(app.RecoveredSessions, msg.SessionID)
+		}
+	}
+}
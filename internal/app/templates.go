@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/crush/internal/agent"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+)
+
+// StartTemplateSession switches to the template's models, if it names
+// any, and creates a session seeded from it. The template's notes and
+// working set are stored as the first message of the session, which the
+// agent keeps in context when the session is summarized. The template's
+// tools must already have been applied to the config.
+func (app *App) StartTemplateSession(ctx context.Context, tmpl config.SessionTemplate) (session.Session, error) {
+	if tmpl.Model != "" || tmpl.SmallModel != "" {
+		if err := app.overrideModelsForNonInteractive(ctx, tmpl.Model, tmpl.SmallModel); err != nil {
+			return session.Session{}, fmt.Errorf("failed to select template models: %w", err)
+		}
+	}
+
+	preamble, err := tmpl.Preamble(app.config.WorkingDir())
+	if err != nil {
+		return session.Session{}, err
+	}
+
+	sess, err := app.Sessions.Create(ctx, agent.DefaultSessionName)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("failed to create session: %w", err)
+	}
+	if preamble == "" {
+		return sess, nil
+	}
+	if _, err := app.Messages.Create(ctx, sess.ID, message.CreateMessageParams{
+		Role:  message.User,
+		Parts: []message.ContentPart{message.TextContent{Text: preamble}},
+	}); err != nil {
+		return session.Session{}, fmt.Errorf("failed to pin template notes: %w", err)
+	}
+	return sess, nil
+}
@@ -220,12 +220,19 @@ func (b *Backend) CreateWorkspace(args proto.Workspace) (*Workspace, proto.Works
 	}
 
 	cfg.Overrides().SkipPermissionRequests = args.YOLO
+	// XRUSH: skip repository-only work outside a repository.
+	if !config.InRepository(args.Path) {
+		cfg.ApplyBareMode()
+	}
 
 	if err := createDotCrushDir(cfg.Config().Options.DataDirectory); err != nil {
 		return nil, proto.Workspace{}, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	conn, err := db.Connect(b.ctx, cfg.Config().Options.DataDirectory, db.WithDataDirLock(true))
+	conn, err := db.Connect(b.ctx, cfg.Config().Options.DataDirectory,
+		db.WithDataDirLock(true),
+		db.WithSettings(app.DatabaseSettings(cfg.Config().Options)),
+	)
 	if err != nil {
 		return nil, proto.Workspace{}, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -613,6 +620,20 @@ func (b *Backend) VersionInfo() proto.VersionInfo {
 	}
 }
 
+// CheckDatabases checks the database of every running workspace and
+// returns the first failure.
+func (b *Backend) CheckDatabases(ctx context.Context) error {
+	for id, ws := range b.workspaces.Seq2() {
+		if ws.App == nil || ws.DB == nil {
+			continue
+		}
+		if _, err := db.CheckHealth(ctx, ws.DB); err != nil {
+			return fmt.Errorf("workspace %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
 // Config returns the server-level configuration.
 func (b *Backend) Config() *config.ConfigStore {
 	return b.cfg
@@ -0,0 +1,106 @@
+package bugreport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Summary returns the report as Markdown, to paste into an issue.
+func (r *Report) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Crush bug report\n\n")
+	fmt.Fprintf(&b, "- Version: %s (%s)\n", r.Info.Version, r.Info.GoVersion)
+	fmt.Fprintf(&b, "- Platform: %s/%s\n", r.Info.OS, r.Info.Arch)
+	if r.Info.Term != "" || r.Info.TermProgram != "" {
+		fmt.Fprintf(&b, "- Terminal: %s\n", strings.TrimSpace(r.Info.TermProgram+" "+r.Info.Term))
+	}
+	if r.Info.Shell != "" {
+		fmt.Fprintf(&b, "- Shell: %s\n", r.Info.Shell)
+	}
+	fmt.Fprintf(&b, "- Created: %s\n", r.Info.Created.Format("2006-01-02 15:04:05 MST"))
+
+	b.WriteString("\n## Checks\n\n| Check | Status | Detail |\n|---|---|---|\n")
+	for _, c := range r.Checks {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", c.Name, c.Status, tableCell(c.Detail))
+	}
+
+	b.WriteString("\n## Recent failures\n\n")
+	if len(r.Failures) == 0 {
+		b.WriteString("None recorded.\n")
+	}
+	for _, f := range r.Failures {
+		what := f.Tool
+		if f.Kind == FailureProvider {
+			what = f.Provider + "/" + f.Model
+		}
+		first, _, _ := strings.Cut(f.Message, "\n")
+		fmt.Fprintf(&b, "- %s %s `%s`: %s\n", f.Time.Format("2006-01-02 15:04:05"), f.Kind, what, first)
+	}
+
+	fmt.Fprintf(&b, "\n## Files\n\n")
+	b.WriteString("- `info.json`: version and platform\n")
+	if r.Config != nil {
+		b.WriteString("- `config.json`: effective configuration, secrets redacted\n")
+	}
+	b.WriteString("- `checks.json`: the checks above\n")
+	b.WriteString("- `failures.json`: the failures above, in full\n")
+	if len(r.Logs) > 0 {
+		fmt.Fprintf(&b, "- `logs/crush.log`: the last %d log lines\n", len(r.Logs))
+	}
+	return b.String()
+}
+
+func tableCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// WriteZip writes the report as a zip archive.
+func (r *Report) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data func() ([]byte, error)
+	}{
+		{"summary.md", func() ([]byte, error) { return []byte(r.Summary()), nil }},
+		{"info.json", func() ([]byte, error) { return indent(r.Info) }},
+		{"config.json", func() ([]byte, error) { return r.Config, nil }},
+		{"checks.json", func() ([]byte, error) { return indent(r.Checks) }},
+		{"failures.json", func() ([]byte, error) { return indent(r.Failures) }},
+		{"logs/crush.log", r.logFile},
+	}
+	for _, f := range files {
+		data, err := f.data()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: r.Info.Created,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func indent(v any) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+func (r *Report) logFile() ([]byte, error) {
+	if len(r.Logs) == 0 {
+		return nil, nil
+	}
+	return []byte(strings.Join(r.Logs, "\n") + "\n"), nil
+}
@@ -0,0 +1,224 @@
+// Package bugreport collects what is needed to triage a bug, the version
+// and platform, the effective configuration, recent logs, the last failing
+// provider and tool interactions and a set of environment checks, into a
+// single archive to attach to an issue. Secrets are masked throughout.
+package bugreport
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/crush/internal/cast"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/version"
+)
+
+const (
+	// DefaultLogLines is how many of the last log lines are included.
+	DefaultLogLines = 500
+	// maxFailures is how many of the most recent failures are included.
+	maxFailures = 20
+	// maxFailureMessage caps the text of a failure.
+	maxFailureMessage = 500
+)
+
+// Info identifies the build and platform.
+type Info struct {
+	Version     string    `json:"version"`
+	GoVersion   string    `json:"go_version"`
+	OS          string    `json:"os"`
+	Arch        string    `json:"arch"`
+	Term        string    `json:"term,omitempty"`
+	TermProgram string    `json:"term_program,omitempty"`
+	Shell       string    `json:"shell,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
+// Failure kinds.
+const (
+	// FailureProvider is a response that finished with an error.
+	FailureProvider = "provider"
+	// FailureTool is a tool call whose result is an error.
+	FailureTool = "tool"
+)
+
+// Failure is the metadata of a failed provider or tool interaction. Prompts
+// and tool inputs are left out.
+type Failure struct {
+	Kind      string    `json:"kind"`
+	Time      time.Time `json:"time"`
+	SessionID string    `json:"session_id"`
+	Provider  string    `json:"provider,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Tool      string    `json:"tool,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// Report is a collected bug report.
+type Report struct {
+	Info Info
+	// Config is the effective configuration, redacted; nil when it could
+	// not be loaded.
+	Config   json.RawMessage
+	Checks   []Check
+	Failures []Failure
+	// Logs are the last lines of crush.log, redacted.
+	Logs []string
+}
+
+// Options configures Collect.
+type Options struct {
+	// Config is the effective configuration; nil when loading it failed
+	// with ConfigErr.
+	Config     *config.Config
+	ConfigErr  error
+	WorkingDir string
+	// Messages are searched for failures; HistoryErr is why they could not
+	// be read, if so.
+	Messages   []message.Message
+	HistoryErr error
+	// LogLines is how many of the last log lines to include; zero uses
+	// DefaultLogLines, negative includes none.
+	LogLines int
+	// Environ is the environment, in KEY=VALUE form, whose secret values
+	// are masked; os.Environ when nil.
+	Environ []string
+	// Now is the time of the report; the current time when zero.
+	Now time.Time
+}
+
+// Collect gathers a report. It does not fail on a broken setup, which is
+// what a report is for: problems are recorded as failed checks.
+func Collect(opts Options) *Report {
+	if opts.Environ == nil {
+		opts.Environ = os.Environ()
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	redactor := cast.NewRedactor(append(cast.EnvSecrets(opts.Environ), configSecrets(opts.Config)...)...)
+
+	r := &Report{
+		Info: Info{
+			Version:     version.Version,
+			GoVersion:   runtime.Version(),
+			OS:          runtime.GOOS,
+			Arch:        runtime.GOARCH,
+			Term:        os.Getenv("TERM"),
+			TermProgram: os.Getenv("TERM_PROGRAM"),
+			Shell:       filepath.Base(os.Getenv("SHELL")),
+			Created:     opts.Now.UTC(),
+		},
+		Checks:   runChecks(opts, exec.LookPath),
+		Failures: failures(opts.Messages, redactor),
+	}
+	if r.Info.Shell == "." {
+		r.Info.Shell = ""
+	}
+	if opts.Config != nil {
+		if data, err := redactConfig(opts.Config, redactor); err == nil {
+			r.Config = data
+		} else {
+			r.Checks = append(r.Checks, Check{Name: "config_dump", Status: StatusFail, Detail: err.Error()})
+		}
+
+		n := cmp.Or(opts.LogLines, DefaultLogLines)
+		if n > 0 {
+			logs, err := tail(filepath.Join(opts.Config.Options.DataDirectory, "logs", "crush.log"), n)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				r.Checks = append(r.Checks, Check{Name: "logs", Status: StatusWarn, Detail: err.Error()})
+			}
+			for _, line := range logs {
+				r.Logs = append(r.Logs, redactor.Redact(line))
+			}
+		}
+	}
+	return r
+}
+
+// failures returns the most recent failed interactions of msgs, oldest
+// first.
+func failures(msgs []message.Message, redactor *cast.Redactor) []Failure {
+	out := []Failure{}
+	for _, m := range msgs {
+		if m.Role == message.Assistant {
+			if f := m.FinishPart(); f != nil && f.Reason == message.FinishReasonError {
+				text := f.Message
+				if f.Details != "" {
+					text = cmp.Or(text, "error") + ": " + f.Details
+				}
+				out = append(out, Failure{
+					Kind:      FailureProvider,
+					Time:      time.Unix(cmp.Or(f.Time, m.CreatedAt), 0).UTC(),
+					SessionID: m.SessionID,
+					Provider:  m.Provider,
+					Model:     m.Model,
+					Message:   truncate(redactor.Redact(text)),
+				})
+			}
+		}
+		for _, tr := range m.ToolResults() {
+			if !tr.IsError {
+				continue
+			}
+			out = append(out, Failure{
+				Kind:      FailureTool,
+				Time:      time.Unix(m.CreatedAt, 0).UTC(),
+				SessionID: m.SessionID,
+				Tool:      tr.Name,
+				Message:   truncate(redactor.Redact(tr.Content)),
+			})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b Failure) int {
+		return a.Time.Compare(b.Time)
+	})
+	return out[max(len(out)-maxFailures, 0):]
+}
+
+func truncate(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= maxFailureMessage {
+		return s
+	}
+	cut := maxFailureMessage
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
+
+// tail returns the last n lines of the file at path.
+func tail(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ring := make([]string, 0, n)
+	next := 0
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64<<10), 4<<20)
+	for sc.Scan() {
+		if len(ring) < n {
+			ring = append(ring, sc.Text())
+			continue
+		}
+		ring[next] = sc.Text()
+		next = (next + 1) % n
+	}
+	return append(ring[next:], ring[:next]...), sc.Err()
+}
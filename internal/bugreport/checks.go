@@ -0,0 +1,156 @@
+package bugreport
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/permission"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is the outcome of one environment check.
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// runChecks checks the setup described by opts. lookPath finds commands,
+// as exec.LookPath does.
+func runChecks(opts Options, lookPath func(string) (string, error)) []Check {
+	if opts.ConfigErr != nil || opts.Config == nil {
+		detail := "not loaded"
+		if opts.ConfigErr != nil {
+			detail = opts.ConfigErr.Error()
+		}
+		return []Check{{Name: "config", Status: StatusFail, Detail: detail}}
+	}
+	cfg := opts.Config
+	checks := []Check{{Name: "config", Status: StatusOK, Detail: "loaded"}}
+	checks = append(checks, checkDataDir(cfg.Options.DataDirectory))
+
+	if opts.HistoryErr != nil {
+		checks = append(checks, Check{Name: "history", Status: StatusWarn, Detail: opts.HistoryErr.Error()})
+	}
+
+	var enabled []string
+	if cfg.Providers != nil {
+		for id, p := range cfg.Providers.Seq2() {
+			if !p.Disable {
+				enabled = append(enabled, id)
+			}
+		}
+	}
+	slices.Sort(enabled)
+	if len(enabled) == 0 {
+		checks = append(checks, Check{Name: "providers", Status: StatusFail, Detail: "no provider is configured"})
+	} else {
+		checks = append(checks, Check{Name: "providers", Status: StatusOK, Detail: strings.Join(enabled, ", ")})
+	}
+
+	for _, t := range []config.SelectedModelType{config.SelectedModelTypeLarge, config.SelectedModelTypeSmall} {
+		checks = append(checks, checkModel(cfg, t, enabled))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.LSP)) {
+		l := cfg.LSP[name]
+		if l.Disabled || l.Command == "" {
+			continue
+		}
+		checks = append(checks, checkCommand("lsp."+name, l.Command, lookPath))
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.MCP)) {
+		m := cfg.MCP[name]
+		if m.Disabled || (m.Type != "" && m.Type != config.MCPStdio) {
+			continue
+		}
+		checks = append(checks, checkCommand("mcp."+name, m.Command, lookPath))
+	}
+
+	parity := "off"
+	if cfg.Options.ParityMode() {
+		parity = "on: lcm.explorer_output_profile is parity"
+	}
+	checks = append(checks, Check{Name: "parity_mode", Status: StatusOK, Detail: parity})
+
+	if path := cfg.Options.PermissionPolicy; path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(opts.WorkingDir, path)
+		}
+		if _, err := permission.LoadPolicy(path); err != nil {
+			checks = append(checks, Check{Name: "permission_policy", Status: StatusFail, Detail: err.Error()})
+		} else {
+			checks = append(checks, Check{Name: "permission_policy", Status: StatusOK, Detail: "valid"})
+		}
+	}
+	return checks
+}
+
+// checkDataDir checks that the data directory can be written to.
+func checkDataDir(dir string) Check {
+	c := Check{Name: "data_directory"}
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		c.Status, c.Detail = StatusWarn, "does not exist yet"
+		return c
+	case err != nil:
+		c.Status, c.Detail = StatusFail, err.Error()
+		return c
+	case !info.IsDir():
+		c.Status, c.Detail = StatusFail, "not a directory"
+		return c
+	}
+	f, err := os.CreateTemp(dir, ".bugreport-*")
+	if err != nil {
+		c.Status, c.Detail = StatusFail, "not writable: "+err.Error()
+		return c
+	}
+	f.Close()
+	os.Remove(f.Name())
+	c.Status, c.Detail = StatusOK, "writable"
+	return c
+}
+
+// checkModel checks that the selected model of type t uses an enabled
+// provider.
+func checkModel(cfg *config.Config, t config.SelectedModelType, enabled []string) Check {
+	c := Check{Name: "model." + string(t)}
+	m, ok := cfg.Models[t]
+	switch {
+	case !ok || m.Model == "":
+		c.Status, c.Detail = StatusWarn, "not selected"
+	case !slices.Contains(enabled, m.Provider):
+		c.Status, c.Detail = StatusFail, fmt.Sprintf("%s/%s: provider %q is not configured", m.Provider, m.Model, m.Provider)
+	default:
+		c.Status, c.Detail = StatusOK, m.Provider+"/"+m.Model
+	}
+	return c
+}
+
+// checkCommand checks that command is installed.
+func checkCommand(name, command string, lookPath func(string) (string, error)) Check {
+	if command == "" {
+		return Check{Name: name, Status: StatusFail, Detail: "no command"}
+	}
+	path, err := lookPath(command)
+	if err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: fmt.Sprintf("%s: not found in PATH", command)}
+	}
+	return Check{Name: name, Status: StatusOK, Detail: path}
+}
@@ -0,0 +1,117 @@
+package bugreport
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/cast"
+	"github.com/charmbracelet/crush/internal/config"
+)
+
+// redacted replaces a secret value of the configuration.
+const redacted = "[REDACTED]"
+
+// secretKeySuffixes mark configuration keys whose values are secrets, once
+// lowercased and stripped of separators: api_key, access_token, apiKey.
+var secretKeySuffixes = []string{"key", "token", "secret", "password", "passwd", "credential", "credentials", "authorization", "cookie"}
+
+// secretMaps are configuration keys whose values are maps of secrets, such
+// as the environment of an MCP server or the headers sent to a provider.
+var secretMaps = map[string]bool{"env": true, "headers": true, "extra_headers": true}
+
+// configSecrets returns the literal secrets of cfg, so they are masked
+// wherever they show up, in logs and error messages too.
+func configSecrets(cfg *config.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	var out []string
+	if cfg.Providers != nil {
+		for _, p := range cfg.Providers.Seq2() {
+			out = append(out, p.APIKey)
+			if p.OAuthToken != nil {
+				out = append(out, p.OAuthToken.AccessToken, p.OAuthToken.RefreshToken)
+			}
+			out = appendValues(out, p.ExtraHeaders)
+		}
+	}
+	for _, m := range cfg.MCP {
+		out = appendValues(out, m.Env)
+		out = appendValues(out, m.Headers)
+	}
+	for _, l := range cfg.LSP {
+		out = appendValues(out, l.Env)
+	}
+	return out
+}
+
+func appendValues(out []string, m map[string]string) []string {
+	for _, v := range m {
+		if !isReference(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// referencePattern matches a whole value that is a $VAR, ${VAR} or
+// $(command) reference.
+var referencePattern = regexp.MustCompile(`^\$(\{?[A-Za-z_]\w*\}?|\(.*\))$`)
+
+// isReference reports whether v names where a value comes from, $VAR or
+// $(command), rather than being the value; references are kept as they
+// show how the configuration is meant to work. Anything else starting
+// with $, such as a password, is a literal secret.
+func isReference(v string) bool {
+	return referencePattern.MatchString(v)
+}
+
+// redactConfig returns cfg as indented JSON with secrets masked.
+func redactConfig(cfg *config.Config, redactor *cast.Redactor) (json.RawMessage, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(redactValue(v, "", redactor), "", "  ")
+}
+
+// redactValue masks the secrets of v, the value of key.
+func redactValue(v any, key string, redactor *cast.Redactor) any {
+	switch v := v.(type) {
+	case map[string]any:
+		secrets := secretMaps[strings.ToLower(key)]
+		for k, item := range v {
+			if s, ok := item.(string); ok && (secrets || isSecretKey(k)) {
+				if s != "" && !isReference(s) {
+					v[k] = redacted
+				}
+				continue
+			}
+			v[k] = redactValue(item, k, redactor)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, key, redactor)
+		}
+		return v
+	case string:
+		return redactor.Redact(v)
+	}
+	return v
+}
+
+func isSecretKey(key string) bool {
+	k := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(k, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+// Package cast records terminal sessions as asciicast v2 files, the format
+// of asciinema, with secrets masked, and renders recordings into
+// self-contained HTML replays for demos and bug reports.
+package cast
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Version is the asciicast version written and read.
+const Version = 2
+
+// Event kinds.
+const (
+	// KindOutput is data written to the terminal.
+	KindOutput = "o"
+	// KindResize is a terminal resize; its data is "COLSxROWS".
+	KindResize = "r"
+)
+
+// Header is the first line of a cast.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is one line after the header: the seconds since the start, the
+// kind and the data.
+type Event struct {
+	Time float64
+	Kind string
+	Data string
+}
+
+// MarshalJSON encodes e as the [time, kind, data] array of the format.
+func (e Event) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode([]any{e.Time, e.Kind, e.Data}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// UnmarshalJSON decodes the [time, kind, data] array of the format.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("event has %d fields, want 3", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &e.Time); err != nil {
+		return fmt.Errorf("event time: %w", err)
+	}
+	if err := json.Unmarshal(raw[1], &e.Kind); err != nil {
+		return fmt.Errorf("event kind: %w", err)
+	}
+	if err := json.Unmarshal(raw[2], &e.Data); err != nil {
+		return fmt.Errorf("event data: %w", err)
+	}
+	return nil
+}
+
+// Recording is a decoded cast.
+type Recording struct {
+	Header Header
+	Events []Event
+}
+
+// Duration returns the time of the last event.
+func (r Recording) Duration() time.Duration {
+	if len(r.Events) == 0 {
+		return 0
+	}
+	return time.Duration(r.Events[len(r.Events)-1].Time * float64(time.Second))
+}
+
+// Read decodes a cast. A truncated last line, left by a session that did
+// not exit cleanly, is ignored.
+func Read(r io.Reader) (Recording, error) {
+	var rec Recording
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return rec, err
+		}
+		return rec, errors.New("empty cast")
+	}
+	if err := json.Unmarshal(sc.Bytes(), &rec.Header); err != nil {
+		return rec, fmt.Errorf("cast header: %w", err)
+	}
+	if rec.Header.Version != Version {
+		return rec, fmt.Errorf("unsupported asciicast version %d", rec.Header.Version)
+	}
+
+	for line := 2; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			if !sc.Scan() {
+				break
+			}
+			return rec, fmt.Errorf("cast line %d: %w", line, err)
+		}
+		rec.Events = append(rec.Events, e)
+	}
+	return rec, sc.Err()
+}
+
+// Recorder writes a cast as the session runs. It is safe for concurrent
+// use.
+type Recorder struct {
+	mu       sync.Mutex
+	w        *bufio.Writer
+	enc      *json.Encoder
+	redactor *Redactor
+	start    time.Time
+	now      func() time.Time
+	err      error
+}
+
+// NewRecorder writes h to w and returns a recorder of the events that
+// follow. Output is masked by redactor, when not nil.
+func NewRecorder(w io.Writer, h Header, redactor *Redactor) (*Recorder, error) {
+	r := &Recorder{
+		w:        bufio.NewWriter(w),
+		redactor: redactor,
+		now:      time.Now,
+	}
+	r.start = r.now()
+	r.enc = json.NewEncoder(r.w)
+	r.enc.SetEscapeHTML(false)
+
+	h.Version = Version
+	if h.Timestamp == 0 {
+		h.Timestamp = r.start.Unix()
+	}
+	if err := r.enc.Encode(h); err != nil {
+		return nil, err
+	}
+	return r, r.w.Flush()
+}
+
+// Output records data written to the terminal.
+func (r *Recorder) Output(data []byte) error {
+	s := string(data)
+	if r.redactor != nil {
+		s = r.redactor.Redact(s)
+	}
+	return r.event(KindOutput, s)
+}
+
+// Resize records a new terminal size.
+func (r *Recorder) Resize(width, height int) error {
+	return r.event(KindResize, strconv.Itoa(width)+"x"+strconv.Itoa(height))
+}
+
+func (r *Recorder) event(kind, data string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	elapsed := r.now().Sub(r.start).Seconds()
+	// Microseconds are all players use; they keep the file small.
+	elapsed = float64(int64(elapsed*1e6)) / 1e6
+	if err := r.enc.Encode(Event{Time: elapsed, Kind: kind, Data: data}); err != nil {
+		r.err = err
+		return err
+	}
+	// Flushed on every event so a crash keeps the session up to it.
+	r.err = r.w.Flush()
+	return r.err
+}
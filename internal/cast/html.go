@@ -0,0 +1,59 @@
+package cast
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// DefaultIdleLimit caps the pauses of a replay, so time spent waiting on
+// the model or the user is not replayed in full.
+const DefaultIdleLimit = 2 * time.Second
+
+//go:embed player.html
+var playerHTML string
+
+var playerTemplate = template.Must(template.New("player").Parse(playerHTML))
+
+// HTMLOptions configures WriteHTML.
+type HTMLOptions struct {
+	// Title heads the page; the cast's title or "Crush session" when
+	// empty.
+	Title string
+	// IdleLimit caps the pauses of the replay. Zero uses
+	// DefaultIdleLimit; negative replays pauses in full.
+	IdleLimit time.Duration
+}
+
+// WriteHTML writes rec as a single HTML page that replays it, with the
+// recording and a small terminal emulator inline, so the file can be
+// attached to a bug report or opened offline.
+func WriteHTML(w io.Writer, rec Recording, opts HTMLOptions) error {
+	title := opts.Title
+	if title == "" {
+		title = rec.Header.Title
+	}
+	if title == "" {
+		title = "Crush session"
+	}
+	idle := opts.IdleLimit
+	if idle == 0 {
+		idle = DefaultIdleLimit
+	}
+
+	meta := fmt.Sprintf("%dx%d, %s", rec.Header.Width, rec.Header.Height, rec.Duration().Round(time.Second))
+	if rec.Header.Timestamp > 0 {
+		meta += ", recorded " + time.Unix(rec.Header.Timestamp, 0).Format("2006-01-02 15:04")
+	}
+	if rec.Events == nil {
+		rec.Events = []Event{}
+	}
+	return playerTemplate.Execute(w, struct {
+		Title     string
+		Meta      string
+		Recording Recording
+		IdleLimit float64
+	}{title, meta, rec, max(idle.Seconds(), 0)})
+}
@@ -0,0 +1,91 @@
+package cast
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// minSecretLength is the shortest secret value masked verbatim; shorter
+// values would mask ordinary words.
+const minSecretLength = 8
+
+// secretPatterns find credentials by their well-known formats.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_\w{20,})`),
+	regexp.MustCompile(`\bglpat-[\w-]{20,}`),
+	regexp.MustCompile(`\bxox[abprs]-[\w-]{10,}`),
+	regexp.MustCompile(`\bsk-(?:ant-|proj-|live_|test_)?[\w-]{20,}`),
+	regexp.MustCompile(`\bAIza[\w-]{35}\b`),
+	regexp.MustCompile(`\beyJ[\w-]{8,}\.eyJ[\w-]{8,}\.[\w-]+`),
+	regexp.MustCompile(`(?i)\bbearer\s+[\w.~+/-]{20,}=*`),
+}
+
+// secretEnvWords mark environment variables holding secrets.
+var secretEnvWords = []string{"KEY", "TOKEN", "SECRET", "PASSWORD", "PASSWD", "CREDENTIAL", "AUTH"}
+
+// Redactor masks secrets in recorded output. Each masked character is
+// replaced by '*', so the layout of the terminal is kept.
+type Redactor struct {
+	// secrets are the values masked verbatim, longest first.
+	secrets []string
+}
+
+// NewRedactor returns a redactor of the well-known credential formats and
+// of secrets, such as API keys from the configuration. Values shorter than
+// 8 bytes are ignored.
+func NewRedactor(secrets ...string) *Redactor {
+	r := &Redactor{}
+	for _, s := range secrets {
+		s = strings.TrimSpace(s)
+		if len(s) >= minSecretLength && !slices.Contains(r.secrets, s) {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	slices.SortFunc(r.secrets, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	return r
+}
+
+// EnvSecrets returns the values of the variables of environ, in KEY=VALUE
+// form, whose names mark them as secrets, such as OPENAI_API_KEY or
+// GITHUB_TOKEN.
+func EnvSecrets(environ []string) []string {
+	var out []string
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || len(value) < minSecretLength {
+			continue
+		}
+		upper := strings.ToUpper(name)
+		for _, w := range secretEnvWords {
+			if strings.Contains(upper, w) {
+				out = append(out, value)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Redact returns s with every secret masked.
+func (r *Redactor) Redact(s string) string {
+	for _, secret := range r.secrets {
+		if strings.Contains(s, secret) {
+			s = strings.ReplaceAll(s, secret, mask(secret))
+		}
+	}
+	for _, p := range secretPatterns {
+		s = p.ReplaceAllStringFunc(s, mask)
+	}
+	return s
+}
+
+func mask(s string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(s))
+}
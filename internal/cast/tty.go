@@ -0,0 +1,56 @@
+package cast
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// TTY is a terminal whose output is also recorded. It can stand in for
+// the program's output file: reads, mode changes and size queries go to
+// the file itself.
+type TTY struct {
+	*os.File
+	rec *Recorder
+
+	mu            sync.Mutex
+	width, height int
+	failed        bool
+}
+
+// NewTTY records what is written to f, a terminal of width by height
+// cells, into rec.
+func NewTTY(f *os.File, rec *Recorder, width, height int) *TTY {
+	return &TTY{File: f, rec: rec, width: width, height: height}
+}
+
+// Write writes p to the terminal and records it. A size change since the
+// last write is recorded first, so a replay draws p at the new size.
+// Failing to record never fails the write.
+func (t *TTY) Write(p []byte) (int, error) {
+	n, err := t.File.Write(p)
+	if n == 0 {
+		return n, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failed {
+		return n, err
+	}
+	if w, h, sizeErr := term.GetSize(t.Fd()); sizeErr == nil && (w != t.width || h != t.height) {
+		t.width, t.height = w, h
+		t.record(t.rec.Resize(w, h))
+	}
+	t.record(t.rec.Output(p[:n]))
+	return n, err
+}
+
+func (t *TTY) record(err error) {
+	if err != nil && !t.failed {
+		t.failed = true
+		slog.Warn("Session recording stopped", "error", err)
+	}
+}
@@ -203,6 +203,18 @@ func (c *Client) SubscribeEvents(ctx context.Context, id string) (<-chan any, er
 				var e pubsub.Event[proto.SkillsEvent]
 				_ = json.Unmarshal(p.Payload, &e)
 				sendEvent(ctx, events, e)
+			case pubsub.PayloadTypeToolEvent:
+				var e pubsub.Event[proto.ToolEvent]
+				_ = json.Unmarshal(p.Payload, &e)
+				sendEvent(ctx, events, e)
+			case pubsub.PayloadTypeLCMEvent:
+				var e pubsub.Event[proto.LCMEvent]
+				_ = json.Unmarshal(p.Payload, &e)
+				sendEvent(ctx, events, e)
+			case pubsub.PayloadTypeRepoMapEvent:
+				var e pubsub.Event[proto.RepoMapEvent]
+				_ = json.Unmarshal(p.Payload, &e)
+				sendEvent(ctx, events, e)
 			default:
 				slog.Warn("Unknown event type", "type", p.Type)
 				continue
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/dupes"
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+	"github.com/spf13/cobra"
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze the project's code",
+	Long:  "Static analyses of the project that need no model.",
+}
+
+var (
+	dupesPath              string
+	dupesMinTokens         int
+	dupesMaxFiles          int
+	dupesMaxFileSize       int64
+	dupesIgnoreIdentifiers bool
+	dupesLimit             int
+	dupesJSON              bool
+)
+
+var analyzeDupesCmd = &cobra.Command{
+	Use:   "dupes",
+	Short: "Find probable copy-paste code",
+	Long: `Find code duplicated across the project's source files. Files are compared
+by token, ignoring whitespace, comments and literal values, and the shared
+runs are grouped into clusters of copies ranked by how many tokens they
+duplicate. Generated files are skipped.`,
+	Example: `
+# The most duplicated code of the project
+crush analyze dupes
+
+# Copies under internal/ that may have renamed variables, 100 tokens or longer
+crush analyze dupes --path internal --ignore-identifiers --min-tokens 100
+  `,
+	Args: cobra.NoArgs,
+	RunE: runAnalyzeDupes,
+}
+
+func init() {
+	analyzeDupesCmd.Flags().StringVar(&dupesPath, "path", "", "directory or part of a path to restrict to")
+	analyzeDupesCmd.Flags().IntVar(&dupesMinTokens, "min-tokens", dupes.DefaultMinTokens, "shortest duplicated run to report, in tokens")
+	analyzeDupesCmd.Flags().IntVar(&dupesMaxFiles, "max-files", dupes.DefaultMaxFiles, "maximum files to compare")
+	analyzeDupesCmd.Flags().Int64Var(&dupesMaxFileSize, "max-file-size", dupes.DefaultMaxFileSize, "skip files larger than this many bytes")
+	analyzeDupesCmd.Flags().BoolVar(&dupesIgnoreIdentifiers, "ignore-identifiers", false, "match copies that renamed identifiers")
+	analyzeDupesCmd.Flags().IntVar(&dupesLimit, "limit", 20, "maximum clusters to list (0 for all)")
+	analyzeDupesCmd.Flags().BoolVar(&dupesJSON, "json", false, "output in JSON format")
+	analyzeCmd.AddCommand(analyzeDupesCmd)
+
+	analyzeBinaryCmd.Flags().StringVar(&binaryPackage, "package", ".", "package to build when no binary is given")
+	analyzeBinaryCmd.Flags().StringVar(&binaryBaseline, "baseline", "", "earlier build of the binary to compare with")
+	analyzeBinaryCmd.Flags().IntVar(&binaryLimit, "limit", 20, "maximum sections, modules and packages to list (0 for all)")
+	analyzeBinaryCmd.Flags().BoolVar(&binaryJSON, "json", false, "output in JSON format")
+	analyzeCmd.AddCommand(analyzeBinaryCmd)
+}
+
+var (
+	binaryPackage  string
+	binaryBaseline string
+	binaryLimit    int
+	binaryJSON     bool
+)
+
+var analyzeBinaryCmd = &cobra.Command{
+	Use:   "binary [path]",
+	Short: "Break down the size of a Go binary",
+	Long: `Report what a Go binary's size is made of: its sections and, from its symbol
+table, the modules and packages that take the most space. Without a path,
+the package given by --package is built first. With --baseline, report how
+the size changed from an earlier build instead.
+
+Binaries linked with -ldflags=-s have no symbol table and cannot be broken
+down by package.`,
+	Example: `
+# Build the project and show where its size goes
+crush analyze binary
+
+# Inspect an existing binary
+crush analyze binary ./bin/server
+
+# Which dependencies grew since the last release
+crush analyze binary --baseline ./dist/server-v1.2.0
+  `,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAnalyzeBinary,
+}
+
+func runAnalyzeBinary(cmd *cobra.Command, args []string) error {
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return err
+	}
+
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	} else {
+		tmp, err := os.MkdirTemp("", "crush-analyze-*")
+		if err != nil {
+			return fmt.Errorf("failed to create a build directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		path = filepath.Join(tmp, "binary")
+		build := exec.CommandContext(cmd.Context(), "go", "build", "-o", path, binaryPackage)
+		build.Dir = cwd
+		if out, err := build.CombinedOutput(); err != nil {
+			return fmt.Errorf("go build %s failed: %w\n%s", binaryPackage, err, out)
+		}
+	}
+
+	size, err := readBinarySize(path)
+	if err != nil {
+		return err
+	}
+	var base *explorer.BinarySize
+	if binaryBaseline != "" {
+		if base, err = readBinarySize(binaryBaseline); err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if binaryJSON {
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		if base != nil {
+			return enc.Encode(map[string]*explorer.BinarySize{"baseline": base, "binary": size})
+		}
+		return enc.Encode(size)
+	}
+	limit := binaryLimit
+	if limit <= 0 {
+		limit = len(size.Packages) + len(size.Sections)
+	}
+	if base != nil {
+		fmt.Fprint(out, size.Compare(base, limit))
+		return nil
+	}
+	fmt.Fprint(out, size.Report(limit))
+	return nil
+}
+
+func readBinarySize(path string) (*explorer.BinarySize, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size, err := explorer.ReadBinarySize(f, info.Size())
+	if errors.Is(err, explorer.ErrNoSymbols) {
+		return nil, fmt.Errorf("%s: %w; rebuild it without -ldflags=-s", path, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return size, nil
+}
+
+func runAnalyzeDupes(cmd *cobra.Command, _ []string) error {
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return err
+	}
+	dataDir, err := cmd.Flags().GetString("data-dir")
+	if err != nil {
+		return fmt.Errorf("failed to get data directory: %v", err)
+	}
+	cfg, err := config.Load(cwd, dataDir, false)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %v", err)
+	}
+
+	res, err := dupes.Detect(cmd.Context(), cfg.WorkingDir(), dupes.Options{
+		Path:              dupesPath,
+		MinTokens:         dupesMinTokens,
+		MaxFiles:          dupesMaxFiles,
+		MaxFileSize:       dupesMaxFileSize,
+		IgnoreIdentifiers: dupesIgnoreIdentifiers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detect duplicate code: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if dupesJSON {
+		if dupesLimit > 0 && len(res.Clusters) > dupesLimit {
+			res.Clusters = res.Clusters[:dupesLimit]
+		}
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(res)
+	}
+	limit := dupesLimit
+	if limit <= 0 {
+		limit = len(res.Clusters)
+	}
+	fmt.Fprint(out, res.Report(limit))
+	return nil
+}
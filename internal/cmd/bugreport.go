@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/bugreport"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// bugReportSessions is how many of the most recent sessions are searched
+// for failures.
+const bugReportSessions = 5
+
+var bugReportCmd = &cobra.Command{
+	Use:   "bugreport",
+	Short: "Collect a bug report archive to attach to an issue",
+	Long: `Collect what is needed to triage a bug into a single zip archive: the
+version and platform, the effective configuration, the last lines of the
+log, the last failing provider and tool interactions of recent sessions and
+the results of environment checks, such as whether the configured models,
+language servers and MCP servers can be used.
+
+API keys, tokens and the values of secret environment variables are
+masked, but prompts and tool output in the logs are not: review the
+archive before attaching it.`,
+	Example: `
+# Write crush-bugreport-<time>.zip in the current directory
+crush bugreport
+
+# Choose the archive and include more of the log
+crush bugreport -o report.zip --log-lines 2000
+  `,
+	Args: cobra.NoArgs,
+	RunE: runBugReport,
+}
+
+func init() {
+	bugReportCmd.Flags().StringP("output", "o", "", "Archive to write (default: crush-bugreport-<time>.zip)")
+	bugReportCmd.Flags().Int("log-lines", bugreport.DefaultLogLines, "Number of log lines to include (0 for none)")
+}
+
+func runBugReport(cmd *cobra.Command, _ []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	logLines, _ := cmd.Flags().GetInt("log-lines")
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	if logLines == 0 {
+		logLines = -1
+	}
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := bugreport.Options{WorkingDir: cwd, LogLines: logLines}
+	// A configuration that does not load is reported, not returned: it is
+	// often the bug.
+	if store, err := config.Load(cwd, dataDir, false); err != nil {
+		opts.ConfigErr = err
+	} else {
+		opts.Config = store.Config()
+		opts.WorkingDir = store.WorkingDir()
+		opts.Messages, opts.HistoryErr = recentMessages(cmd, opts.Config.Options.DataDirectory)
+	}
+
+	now := time.Now()
+	opts.Now = now
+	r := bugreport.Collect(opts)
+
+	out := cmp.Or(output, "crush-bugreport-"+now.Format("20060102-150405")+".zip")
+	f, err := os.OpenFile(out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := r.WriteZip(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	counts := map[bugreport.Status]int{}
+	for _, c := range r.Checks {
+		counts[c.Status]++
+		if c.Status != bugreport.StatusOK {
+			fmt.Fprintf(cmd.OutOrStdout(), "%-4s %s: %s\n", c.Status, c.Name, c.Detail)
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%d checks failed, %d warnings, %d recent failures, %d log lines)\n",
+		out, counts[bugreport.StatusFail], counts[bugreport.StatusWarn], len(r.Failures), len(r.Logs))
+	fmt.Fprintln(cmd.OutOrStdout(), "Review it before attaching it to an issue: prompts and tool output in the log are not masked.")
+	return nil
+}
+
+// recentMessages returns the messages of the most recent sessions of the
+// project, or none when it has no database yet.
+func recentMessages(cmd *cobra.Command, dataDir string) ([]message.Message, error) {
+	if _, err := os.Stat(filepath.Join(dataDir, "crush.db")); err != nil {
+		return nil, nil
+	}
+	ctx := cmd.Context()
+	conn, err := db.Connect(ctx, dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Release(dataDir)
+
+	q := db.New(conn)
+	sessions, err := session.NewService(q, conn).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := message.NewService(q)
+	var out []message.Message
+	for _, s := range sessions[:min(len(sessions), bugReportSessions)] {
+		msgs, err := messages.List(ctx, s.ID)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, msgs...)
+	}
+	return out, nil
+}
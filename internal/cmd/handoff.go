@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/event"
+	"github.com/charmbracelet/crush/internal/handoff"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+// handoffPassphraseEnv holds the bundle passphrase for non-interactive use.
+const handoffPassphraseEnv = "CRUSH_HANDOFF_PASSPHRASE"
+
+var (
+	handoffOutput      string
+	handoffTurns       int
+	handoffApplyConfig bool
+)
+
+var handoffCmd = &cobra.Command{
+	Use:   "handoff",
+	Short: "Move a session to another machine",
+	Long: `Export a session to a single encrypted bundle and import it on another
+machine to continue working. A bundle holds the transcript, the template's
+pinned notes and working set, the LCM large files referenced in the last
+turns and the workspace config overrides (.crush/crush.json).
+
+The passphrase is read from $` + handoffPassphraseEnv + ` or prompted for.`,
+}
+
+var handoffExportCmd = &cobra.Command{
+	Use:   "export [id]",
+	Short: "Export a session to an encrypted bundle",
+	Long:  "Export a session to an encrypted bundle. Without an ID the most recent session is exported. ID can be a UUID, full hash, or hash prefix.",
+	Example: `
+# Export the most recent session
+crush handoff export -o work.crush
+
+# Export a session and the LCM files of its last 5 turns
+crush handoff export 3f2a -o work.crush --turns 5
+  `,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHandoffExport,
+}
+
+var handoffImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a session from an encrypted bundle",
+	Long: `Import a session from an encrypted bundle. The session keeps its ID;
+continue it with crush --session <id>. The bundled workspace config can
+name MCP and LSP commands and hooks to run, so it is only copied when
+--apply-config is given, and then only the keys not set locally.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHandoffImport,
+}
+
+func init() {
+	handoffExportCmd.Flags().StringVarP(&handoffOutput, "output", "o", "", "bundle file to write (required)")
+	handoffExportCmd.Flags().IntVar(&handoffTurns, "turns", handoff.DefaultTurns, "recent turns whose LCM files are bundled")
+	_ = handoffExportCmd.MarkFlagRequired("output")
+	handoffImportCmd.Flags().BoolVar(&handoffApplyConfig, "apply-config", false, "copy the bundled workspace config keys not set locally")
+	handoffCmd.AddCommand(handoffExportCmd)
+	handoffCmd.AddCommand(handoffImportCmd)
+}
+
+func runHandoffExport(cmd *cobra.Command, args []string) error {
+	event.SetNonInteractive(true)
+
+	ctx, svc, cleanup, err := sessionSetup(cmd)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var sess session.Session
+	if len(args) == 1 {
+		sess, err = resolveSessionID(ctx, svc.sessions, args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		list, err := svc.sessions.List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list sessions: %w", err)
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("no sessions found")
+		}
+		sess = list[0]
+	}
+
+	var cfgData []byte
+	if path, err := svc.cfg.ConfigPath(config.ScopeWorkspace); err == nil {
+		cfgData, err = os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read workspace config: %w", err)
+		}
+	}
+
+	bundle, err := handoff.Export(ctx, svc.queries, sess.ID, handoff.ExportOptions{
+		Turns:  handoffTurns,
+		Config: bytes.TrimSpace(cfgData),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export session: %w", err)
+	}
+
+	passphrase, err := handoffPassphrase(true)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := handoff.Write(&buf, bundle, passphrase); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := os.WriteFile(handoffOutput, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported %s (%q): %d messages, %d LCM files to %s\n",
+		session.HashID(sess.ID)[:7], sess.Title, len(bundle.Messages), len(bundle.LargeFiles), handoffOutput)
+	return nil
+}
+
+func runHandoffImport(cmd *cobra.Command, args []string) error {
+	event.SetNonInteractive(true)
+
+	ctx, svc, cleanup, err := sessionSetup(cmd)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	passphrase, err := handoffPassphrase(false)
+	if err != nil {
+		return err
+	}
+	bundle, err := handoff.Read(f, passphrase)
+	if err != nil {
+		return err
+	}
+	queries, ok := svc.queries.(*db.Queries)
+	if !ok {
+		return errors.New("session database does not support transactions")
+	}
+	if err := handoff.Import(ctx, svc.conn, queries, bundle); err != nil {
+		return fmt.Errorf("failed to import session: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Imported %s (%q): %d messages, %d LCM files\n",
+		session.HashID(bundle.Session.ID)[:7], bundle.Session.Title, len(bundle.Messages), len(bundle.LargeFiles))
+	if len(bundle.PinnedNotes) > 0 {
+		fmt.Fprintf(out, "Pinned notes:\n  %s\n", strings.Join(bundle.PinnedNotes, "\n  "))
+	}
+	if len(bundle.WorkingSet) > 0 {
+		fmt.Fprintf(out, "Working set:\n  %s\n", strings.Join(bundle.WorkingSet, "\n  "))
+	}
+	if handoffApplyConfig {
+		keys, err := handoff.ApplyConfig(svc.cfg, bundle.Config)
+		if err != nil && !errors.Is(err, config.ErrNoWorkspaceConfig) {
+			return fmt.Errorf("failed to apply workspace config: %w", err)
+		}
+		if len(keys) > 0 {
+			fmt.Fprintf(out, "Workspace config keys applied: %s\n", strings.Join(keys, ", "))
+		}
+	} else if keys, err := handoff.ConfigKeys(bundle.Config); err == nil && len(keys) > 0 {
+		fmt.Fprintf(out, "Workspace config keys skipped: %s\n", strings.Join(keys, ", "))
+		fmt.Fprintln(out, "Review them and import again with --apply-config to copy them.")
+	}
+	fmt.Fprintf(out, "Continue with: crush --session %s\n", bundle.Session.ID)
+	return nil
+}
+
+// handoffPassphrase returns the bundle passphrase from the environment or,
+// on a terminal, from a prompt; confirm asks for it twice.
+func handoffPassphrase(confirm bool) (string, error) {
+	if p := os.Getenv(handoffPassphraseEnv); p != "" {
+		return p, nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("no passphrase: set $%s", handoffPassphraseEnv)
+	}
+	read := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		p, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		return string(p), err
+	}
+	p, err := read("Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", errors.New("a passphrase is required")
+	}
+	if confirm {
+		again, err := read("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != p {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return p, nil
+}
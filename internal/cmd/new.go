@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/event"
+	"github.com/charmbracelet/crush/internal/ui/common"
+	ui "github.com/charmbracelet/crush/internal/ui/model"
+	"github.com/charmbracelet/crush/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Start a new session from a template",
+	Long: `Start a new session seeded from a named template.
+Templates live in crush.json under "templates" or as one JSON file per
+template in .crush/templates/<name>.json. A template may define pinned
+notes, a working set of files or globs, the models to use, the tools to
+enable, and a default first prompt.
+
+The session opens in the interactive UI with the template's default
+prompt ready to edit and send. With --prompt, the prompt is run
+non-interactively instead.`,
+	Example: `
+# List available templates
+crush new --list
+
+# Open a bugfix session with the template's default prompt in the editor
+crush new --template backend-bugfix
+
+# Run a bugfix session non-interactively with a custom first prompt
+crush new --template backend-bugfix --prompt "The /v1/health endpoint returns 500"
+  `,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			list, _    = cmd.Flags().GetBool("list")
+			name, _    = cmd.Flags().GetString("template")
+			prompt, _  = cmd.Flags().GetString("prompt")
+			quiet, _   = cmd.Flags().GetBool("quiet")
+			dataDir, _ = cmd.Flags().GetString("data-dir")
+			debug, _   = cmd.Flags().GetBool("debug")
+		)
+
+		if list {
+			cwd, err := ResolveCwd(cmd)
+			if err != nil {
+				return err
+			}
+			store, err := config.Init(cwd, dataDir, debug)
+			if err != nil {
+				return err
+			}
+			cfg := store.Config()
+			templates, err := config.LoadTemplates(cfg, cfg.Options.DataDirectory)
+			if err != nil {
+				return err
+			}
+			return printTemplates(cmd.OutOrStdout(), templates)
+		}
+
+		if name == "" {
+			return fmt.Errorf("a template is required; use --template <name> or --list")
+		}
+
+		interactive := !cmd.Flags().Changed("prompt")
+		if !interactive {
+			var err error
+			if prompt, err = MaybePrependStdin(prompt); err != nil {
+				return err
+			}
+			if strings.TrimSpace(prompt) == "" {
+				return fmt.Errorf("--prompt is empty")
+			}
+		}
+
+		// The template's tool allow-list is applied before the app
+		// builds the agent's tools from the config.
+		var tmpl config.SessionTemplate
+		ws, cleanup, err := setupLocalWorkspace(cmd, func(store *config.ConfigStore) error {
+			cfg := store.Config()
+			templates, err := config.LoadTemplates(cfg, cfg.Options.DataDirectory)
+			if err != nil {
+				return err
+			}
+			t, ok := templates[name]
+			if !ok {
+				return fmt.Errorf("template %q not found; available: %s", name, strings.Join(config.TemplateNames(templates), ", "))
+			}
+			tmpl = t
+			tmpl.ApplyTools(cfg)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		if !ws.Config().IsConfigured() {
+			return fmt.Errorf("no providers configured - please run 'crush' to set up a provider interactively")
+		}
+
+		app := ws.(*workspace.AppWorkspace).App()
+		sess, err := app.StartTemplateSession(cmd.Context(), tmpl)
+		if err != nil {
+			return fmt.Errorf("failed to start session from template %q: %w", name, err)
+		}
+
+		if interactive {
+			event.AppInitialized()
+			model := ui.New(common.DefaultCommon(ws), sess.ID, false)
+			model.SetInitialPrompt(tmpl.Prompt)
+			return runUI(cmd.Context(), ws, model, "")
+		}
+
+		ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, os.Kill)
+		defer cancel()
+
+		event.SetNonInteractive(true)
+		event.AppInitialized()
+
+		return app.RunNonInteractive(ctx, os.Stdout, prompt, "", "", quiet, sess.ID, false)
+	},
+}
+
+func init() {
+	newCmd.Flags().StringP("template", "t", "", "Name of the session template to use")
+	newCmd.Flags().StringP("prompt", "p", "", "Run this first prompt non-interactively instead of opening the UI")
+	newCmd.Flags().BoolP("list", "l", false, "List available session templates")
+	newCmd.Flags().BoolP("quiet", "q", false, "Hide spinner when running --prompt")
+}
+
+func printTemplates(w io.Writer, templates map[string]config.SessionTemplate) error {
+	if len(templates) == 0 {
+		fmt.Fprintln(w, "No session templates defined.")
+		return nil
+	}
+	for _, name := range config.TemplateNames(templates) {
+		if desc := templates[name].Description; desc != "" {
+			fmt.Fprintf(w, "%s\t%s\n", name, desc)
+			continue
+		}
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintTemplates(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	require.NoError(t, printTemplates(&b, nil))
+	require.Equal(t, "No session templates defined.\n", b.String())
+
+	b.Reset()
+	require.NoError(t, printTemplates(&b, map[string]config.SessionTemplate{
+		"release":        {},
+		"backend-bugfix": {Description: "Fix a backend bug"},
+	}))
+	require.Equal(t, "backend-bugfix\tFix a backend bug\nrelease\n", b.String())
+}
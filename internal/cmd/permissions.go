@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/spf13/cobra"
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Manage permission policies",
+	Long:  "Validate and dry-run the permission policies that decide tool permission prompts in non-interactive runs.",
+}
+
+var permissionsValidateCmd = &cobra.Command{
+	Use:   "validate <policy>",
+	Short: "Validate a permission policy",
+	Long: `Check a permission policy file for unknown fields, unknown decisions,
+invalid globs and unreachable rules. With --tool, also report the decision
+the policy would make for that request without running anything.`,
+	Example: `
+# Check a policy file
+crush permissions validate policy.json
+
+# See what the policy decides for a shell command
+crush permissions validate policy.json --tool bash --command "go test ./..."
+
+# See what the policy decides for an edit
+crush permissions validate policy.json --tool edit --action write --path internal/app/app.go
+  `,
+	Args: cobra.ExactArgs(1),
+	RunE: runPermissionsValidate,
+}
+
+func init() {
+	permissionsValidateCmd.Flags().String("tool", "", "Tool of the request to evaluate")
+	permissionsValidateCmd.Flags().String("action", "", "Action of the request to evaluate")
+	permissionsValidateCmd.Flags().String("path", "", "Path of the request to evaluate")
+	permissionsValidateCmd.Flags().String("command", "", "Shell command of the request to evaluate")
+	permissionsCmd.AddCommand(permissionsValidateCmd)
+}
+
+func runPermissionsValidate(cmd *cobra.Command, args []string) error {
+	var (
+		tool, _    = cmd.Flags().GetString("tool")
+		action, _  = cmd.Flags().GetString("action")
+		path, _    = cmd.Flags().GetString("path")
+		command, _ = cmd.Flags().GetString("command")
+	)
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read policy: %w", err)
+	}
+	policy, err := permission.ParsePolicy(data)
+	if err != nil {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, e := range joined.Unwrap() {
+				cmd.PrintErrf("%s: %v\n", args[0], e)
+			}
+			return fmt.Errorf("invalid policy: %d problems found", len(joined.Unwrap()))
+		}
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	cmd.Printf("%s: ok (%d rules, default %s)\n", args[0], len(policy.Rules), policyDefault(policy))
+
+	if tool == "" {
+		if action != "" || path != "" || command != "" {
+			return fmt.Errorf("--tool is required to evaluate a request")
+		}
+		return nil
+	}
+
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return err
+	}
+	req := permission.CreatePermissionRequest{
+		ToolName: tool,
+		Action:   action,
+		Path:     path,
+	}
+	if command != "" {
+		req.Params = tools.BashPermissionsParams{Command: command}
+	}
+	eval := policy.Evaluate(cwd, req)
+	rule := "default"
+	if eval.Rule > 0 {
+		rule = fmt.Sprintf("rule %d", eval.Rule)
+	}
+	if eval.Reason != "" {
+		rule += ": " + eval.Reason
+	}
+	cmd.Printf("decision: %s (%s)\n", eval.Decision, rule)
+	return nil
+}
+
+func policyDefault(p *permission.Policy) permission.PolicyDecision {
+	if p.Default == "" {
+		return permission.PolicyAsk
+	}
+	return p.Default
+}
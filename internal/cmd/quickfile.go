@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/common"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// quickFileInlineBytes is the largest text file attached verbatim;
+	// larger files are summarized by the explorers.
+	quickFileInlineBytes = 32 * 1024
+	// quickFileExploreBytes caps what is read of a file whose explorer
+	// cannot stream it.
+	quickFileExploreBytes = 8 * 1024 * 1024
+	// quickFileExploreTimeout bounds summarizing the file at startup.
+	quickFileExploreTimeout = 15 * time.Second
+)
+
+// quickFileResult is the outcome of preparing the file attachment.
+type quickFileResult struct {
+	att message.Attachment
+	err error
+}
+
+// prepareQuickFile prepares the attachment for path in the background.
+func prepareQuickFile(ctx context.Context, path string) <-chan quickFileResult {
+	ch := make(chan quickFileResult, 1)
+	go func() {
+		att, err := quickFileAttachment(ctx, path)
+		ch <- quickFileResult{att: att, err: err}
+	}()
+	return ch
+}
+
+// quickFileArg returns the absolute path of the file given to the root
+// command, or "" when there is none.
+func quickFileArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot open %s: %w", args[0], err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory; use --cwd to start crush in it", args[0])
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", args[0])
+	}
+	return path, nil
+}
+
+// quickFileDir returns the directory to start in for path: its own
+// directory when it lies outside the working directory, or "" to keep the
+// working directory. --cwd always wins.
+func quickFileDir(cmd *cobra.Command, path string) string {
+	if cwd, _ := cmd.Flags().GetString("cwd"); cwd != "" {
+		return ""
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.Dir(path)
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && filepath.IsLocal(rel) {
+		return ""
+	}
+	return filepath.Dir(path)
+}
+
+// quickFileAttachment prepares the file given to the root command for the
+// first prompt. Images and small text files are attached as they are;
+// anything else is replaced by its explorer summary, so large logs,
+// databases, archives and binaries fit in the context.
+func quickFileAttachment(ctx context.Context, path string) (message.Attachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return message.Attachment{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return message.Attachment{}, err
+	}
+	size := info.Size()
+
+	head := make([]byte, min(size, 512))
+	if _, err := io.ReadFull(f, head); err != nil {
+		return message.Attachment{}, err
+	}
+	att := message.Attachment{
+		FilePath: path,
+		FileName: filepath.Base(path),
+		MimeType: http.DetectContentType(head),
+	}
+	if (att.IsImage() && size <= common.MaxAttachmentSize) || (att.IsText() && size <= quickFileInlineBytes) {
+		att.Content, err = os.ReadFile(path)
+		return att, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, quickFileExploreTimeout)
+	defer cancel()
+	result, err := exploreQuickFile(ctx, path, f, size)
+	if err != nil {
+		return message.Attachment{}, fmt.Errorf("summarize %s: %w", att.FileName, err)
+	}
+	att.MimeType = "text/plain"
+	att.Content = fmt.Appendf(nil, "Summary of %s (%s) by the %s explorer, not its full content; read the file for details.\n\n%s",
+		att.FileName, humanize.IBytes(uint64(size)), result.ExplorerUsed, result.Summary)
+	return att, nil
+}
+
+// exploreQuickFile summarizes f, streaming it when its explorer can and
+// reading at most quickFileExploreBytes otherwise.
+func exploreQuickFile(ctx context.Context, path string, f *os.File, size int64) (explorer.ExploreResult, error) {
+	registry := explorer.NewRegistry()
+	result, err := registry.ExploreStream(ctx, path, f, size)
+	if !errors.Is(err, explorer.ErrStreamUnsupported) {
+		return result, err
+	}
+	content := make([]byte, min(size, quickFileExploreBytes))
+	n, err := f.ReadAt(content, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return explorer.ExploreResult{}, err
+	}
+	return registry.Explore(ctx, explorer.ExploreInput{Path: path, Content: content[:n]})
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickFileArg(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(file, []byte("hello"), 0o644))
+
+	path, err := quickFileArg(nil)
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	path, err = quickFileArg([]string{file})
+	require.NoError(t, err)
+	require.Equal(t, file, path)
+
+	_, err = quickFileArg([]string{dir})
+	require.ErrorContains(t, err, "is a directory")
+	_, err = quickFileArg([]string{filepath.Join(dir, "missing")})
+	require.ErrorContains(t, err, "cannot open")
+}
+
+func TestQuickFileAttachment(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	t.Run("small text is attached verbatim", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(dir, "notes.txt")
+		require.NoError(t, os.WriteFile(path, []byte("remember the milk\n"), 0o644))
+
+		att, err := quickFileAttachment(context.Background(), path)
+		require.NoError(t, err)
+		require.Equal(t, "notes.txt", att.FileName)
+		require.True(t, att.IsText())
+		require.Equal(t, "remember the milk\n", string(att.Content))
+	})
+
+	t.Run("large text is summarized", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(dir, "server.log")
+		var b strings.Builder
+		for i := range 2000 {
+			b.WriteString("2024-01-02T03:04:05Z INFO request served path=/api/items id=")
+			b.WriteString(strings.Repeat("7", i%5+1))
+			b.WriteString("\n")
+		}
+		require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o644))
+
+		att, err := quickFileAttachment(context.Background(), path)
+		require.NoError(t, err)
+		require.Equal(t, path, att.FilePath)
+		require.Equal(t, "text/plain", att.MimeType)
+		require.True(t, strings.HasPrefix(string(att.Content), "Summary of server.log ("))
+		require.Less(t, len(att.Content), b.Len())
+	})
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/home"
+	"github.com/charmbracelet/crush/internal/projects"
+	"github.com/charmbracelet/crush/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a usage report",
+	Long: `Generate a usage report covering sessions, models, token spend, top
+repositories and tool mix across every known project. The report is
+computed entirely from local databases; nothing is sent over the network.`,
+	Example: `
+# Markdown report for the last 30 days
+crush report
+
+# HTML report for the last two weeks, written to a file
+crush report --since 2w --format html -o report.html
+  `,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().String("since", "30d", "Look-back period (e.g. 30d, 2w, 12h)")
+	reportCmd.Flags().String("format", "markdown", "Output format: markdown or html")
+	reportCmd.Flags().StringP("output", "o", "", "Write the report to a file instead of stdout")
+}
+
+func runReport(cmd *cobra.Command, _ []string) error {
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+
+	if format != "markdown" && format != "html" {
+		return fmt.Errorf("unknown format %q: use markdown or html", format)
+	}
+	period, err := report.ParseSince(sinceFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	var sources []report.Source
+	for _, target := range reportTargets(dataDir) {
+		if _, err := os.Stat(filepath.Join(target.DataDir, "crush.db")); err != nil {
+			continue
+		}
+		conn, err := db.Connect(ctx, target.DataDir)
+		if err != nil {
+			return fmt.Errorf("failed to open database for %s: %w", target.Path, err)
+		}
+		defer db.Release(target.DataDir)
+		sources = append(sources, report.Source{
+			Path:    home.Short(target.Path),
+			Querier: db.New(conn),
+		})
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no data available: no project databases found")
+	}
+
+	r, err := report.Build(ctx, sources, time.Now().Add(-period))
+	if err != nil {
+		return fmt.Errorf("failed to build report: %w", err)
+	}
+
+	out := r.Markdown()
+	if format == "html" {
+		if out, err = r.HTML(); err != nil {
+			return fmt.Errorf("failed to render report: %w", err)
+		}
+	}
+
+	if output == "" {
+		_, err = fmt.Fprint(cmd.OutOrStdout(), out)
+		return err
+	}
+	if err := os.WriteFile(output, []byte(out), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	cmd.PrintErrf("Report written to %s\n", output)
+	return nil
+}
+
+// reportTargets returns the projects to include in a report: the
+// current project first, followed by every registered project. Entries
+// sharing a data directory are only listed once. Configuration is not
+// loaded so that generating a report never reaches out to the network.
+func reportTargets(dataDir string) []projects.Project {
+	var targets []projects.Project
+	seen := map[string]bool{}
+	add := func(p projects.Project) {
+		key := filepath.Clean(p.DataDir)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		targets = append(targets, p)
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		if dataDir == "" {
+			dataDir = filepath.Join(cwd, ".crush")
+		}
+		if abs, err := filepath.Abs(dataDir); err == nil {
+			dataDir = abs
+		}
+		add(projects.Project{Path: cwd, DataDir: dataDir})
+	}
+
+	list, err := projects.List()
+	if err != nil {
+		return targets
+	}
+	for _, p := range list {
+		add(p)
+	}
+	return targets
+}
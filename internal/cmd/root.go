@@ -59,6 +59,9 @@ func init() {
 	rootCmd.Flags().StringP("session", "s", "", "Continue a previous session by ID")
 	rootCmd.Flags().BoolP("continue", "C", false, "Continue the most recent session")
 	rootCmd.MarkFlagsMutuallyExclusive("session", "continue")
+	// XRUSH: --record writes the session as an asciicast for crush share.
+	rootCmd.Flags().String("record", "", "Record the session as an asciinema cast, to the given file or the data directory's recordings")
+	rootCmd.Flags().Lookup("record").NoOptDefVal = recordAuto
 
 	rootCmd.AddCommand(
 		runCmd,
@@ -71,12 +74,20 @@ func init() {
 		loginCmd,
 		statsCmd,
 		sessionCmd,
-		evalCmd, // XRUSH: eval sub-command
+		evalCmd,        // XRUSH: eval sub-command
+		reportCmd,      // XRUSH: report sub-command
+		permissionsCmd, // XRUSH: permission policy sub-command
+		newCmd,
+		handoffCmd,   // XRUSH: session handoff sub-command
+		todosCmd,     // XRUSH: tech-debt inventory sub-command
+		analyzeCmd,   // XRUSH: code analysis sub-command
+		shareCmd,     // XRUSH: session replay sub-command
+		bugReportCmd, // XRUSH: bug report sub-command
 	)
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "crush",
+	Use:   "crush [file]",
 	Short: "A terminal-first AI assistant for software development",
 	Long:  "A glamorous, terminal-first AI assistant for software development and adjacent tasks",
 	Example: `
@@ -103,11 +114,33 @@ crush --session {session-id}
 
 # Continue the most recent session
 crush --continue
+
+# Ask about a file; it is attached to the first prompt, summarized if large
+crush server.log
+
+# Record the session, then turn it into an HTML replay
+crush --record
+crush share
   `,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sessionID, _ := cmd.Flags().GetString("session")
 		continueLast, _ := cmd.Flags().GetBool("continue")
 
+		// XRUSH: crush <file> attaches the file to the first prompt. It is
+		// summarized while the workspace is set up.
+		quickFile, err := quickFileArg(args)
+		if err != nil {
+			return err
+		}
+		var quickAttachment <-chan quickFileResult
+		if quickFile != "" {
+			if dir := quickFileDir(cmd, quickFile); dir != "" {
+				_ = cmd.Flags().Set("cwd", dir)
+			}
+			quickAttachment = prepareQuickFile(cmd.Context(), quickFile)
+		}
+
 		ws, cleanup, err := setupWorkspaceWithProgressBar(cmd)
 		if err != nil {
 			return err
@@ -126,25 +159,47 @@ crush --continue
 
 		com := common.DefaultCommon(ws)
 		model := ui.New(com, sessionID, continueLast)
-
-		var env uv.Environ = os.Environ()
-		program := tea.NewProgram(
-			model,
-			tea.WithEnvironment(env),
-			tea.WithContext(cmd.Context()),
-			tea.WithFilter(ui.MouseEventFilter),
-		)
-		go ws.Subscribe(program)
-
-		if _, err := program.Run(); err != nil {
-			event.Error(err)
-			slog.Error("TUI run error", "error", err)
-			return errors.New("Crush crashed. If metrics are enabled, we were notified about it. If you'd like to report it, please copy the stacktrace above and open an issue at https://github.com/charmbracelet/crush/issues/new?template=bug.yml") //nolint:staticcheck
+		if quickAttachment != nil {
+			if res := <-quickAttachment; res.err != nil {
+				slog.Warn("Failed to attach file", "path", quickFile, "error", res.err)
+			} else {
+				model.SetInitialAttachment(res.att)
+			}
 		}
-		return nil
+
+		record, _ := cmd.Flags().GetString("record")
+		return runUI(cmd.Context(), ws, model, record)
 	},
 }
 
+// runUI runs the TUI on ws until it exits, recording it when record is
+// set.
+func runUI(ctx context.Context, ws workspace.Workspace, model *ui.UI, record string) error {
+	var env uv.Environ = os.Environ()
+	opts := []tea.ProgramOption{
+		tea.WithEnvironment(env),
+		tea.WithContext(ctx),
+		tea.WithFilter(ui.MouseEventFilter),
+	}
+	if record != "" {
+		tty, stop, err := startRecording(record, ws)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		opts = append(opts, tea.WithOutput(tty))
+	}
+	program := tea.NewProgram(model, opts...)
+	go ws.Subscribe(program)
+
+	if _, err := program.Run(); err != nil {
+		event.Error(err)
+		slog.Error("TUI run error", "error", err)
+		return errors.New("Crush crashed. If metrics are enabled, we were notified about it. If you'd like to report it, please copy the stacktrace above and open an issue at https://github.com/charmbracelet/crush/issues/new?template=bug.yml") //nolint:staticcheck
+	}
+	return nil
+}
+
 var heartbit = lipgloss.NewStyle().Foreground(charmtone.Dolly).SetString(`
     ▄▄▄▄▄▄▄▄    ▄▄▄▄▄▄▄▄
   ███████████  ███████████
@@ -246,7 +301,7 @@ func setupWorkspace(cmd *cobra.Command) (workspace.Workspace, func(), error) {
 
 // setupLocalWorkspace creates an in-process app.App and wraps it in an
 // AppWorkspace.
-func setupLocalWorkspace(cmd *cobra.Command) (workspace.Workspace, func(), error) {
+func setupLocalWorkspace(cmd *cobra.Command, configure ...func(*config.ConfigStore) error) (workspace.Workspace, func(), error) {
 	debug, _ := cmd.Flags().GetBool("debug")
 	yolo, _ := cmd.Flags().GetBool("yolo")
 	dataDir, _ := cmd.Flags().GetString("data-dir")
@@ -264,23 +319,28 @@ func setupLocalWorkspace(cmd *cobra.Command) (workspace.Workspace, func(), error
 
 	cfg := store.Config()
 	store.Overrides().SkipPermissionRequests = yolo
-
-	if err := os.MkdirAll(cfg.Options.DataDirectory, 0o700); err != nil {
-		return nil, nil, fmt.Errorf("failed to create data directory: %q %w", cfg.Options.DataDirectory, err)
+	// XRUSH: skip repository-only work outside a repository.
+	if !config.InRepository(cwd) {
+		store.ApplyBareMode()
+	}
+	// XRUSH: let the command adjust the config before the app builds the
+	// agent and its tools from it.
+	for _, fn := range configure {
+		if err := fn(store); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	gitIgnorePath := filepath.Join(cfg.Options.DataDirectory, ".gitignore")
-	if _, err := os.Stat(gitIgnorePath); os.IsNotExist(err) {
-		if err := os.WriteFile(gitIgnorePath, []byte("*\n"), 0o644); err != nil {
-			return nil, nil, fmt.Errorf("failed to create .gitignore file: %q %w", gitIgnorePath, err)
-		}
+	// XRUSH: keep skills/ and knowledge/ versioned with the repository.
+	if err := createDotCrushDir(cfg.Options.DataDirectory); err != nil {
+		return nil, nil, err
 	}
 
 	if err := projects.Register(cwd, cfg.Options.DataDirectory); err != nil {
 		slog.Warn("Failed to register project", "error", err)
 	}
 
-	conn, err := db.Connect(ctx, cfg.Options.DataDirectory)
+	conn, err := db.Connect(ctx, cfg.Options.DataDirectory, db.WithSettings(app.DatabaseSettings(cfg.Options)))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -58,6 +58,9 @@ crush run --session {session-id} "Follow up on your last response"
 # Continue the most recent session
 crush run --continue "Follow up on your last response"
 
+# Decide permission prompts with a policy instead of approving everything
+crush run --policy policy.json "Fix the failing tests"
+
   `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var (
@@ -67,6 +70,7 @@ crush run --continue "Follow up on your last response"
 			smallModel, _ = cmd.Flags().GetString("small-model")
 			sessionID, _  = cmd.Flags().GetString("session")
 			useLast, _    = cmd.Flags().GetBool("continue")
+			policy, _     = cmd.Flags().GetString("policy") // XRUSH: permission policy
 		)
 
 		// Cancel on SIGINT or SIGTERM.
@@ -95,6 +99,9 @@ crush run --continue "Follow up on your last response"
 		}
 
 		if useClientServer() {
+			if policy != "" { // XRUSH: permission policy
+				return fmt.Errorf("--policy is not supported in client/server mode")
+			}
 			c, ws, cleanup, err := connectToServer(cmd)
 			if err != nil {
 				return err
@@ -138,6 +145,10 @@ crush run --continue "Follow up on your last response"
 			slog.SetDefault(slog.New(log.New(os.Stderr)))
 		}
 
+		if policy != "" { // XRUSH: permission policy
+			ws.Config().Options.PermissionPolicy = policy
+		}
+
 		appWs := ws.(*workspace.AppWorkspace)
 		return appWs.App().RunNonInteractive(ctx, os.Stdout, prompt, largeModel, smallModel, quiet || verbose, sessionID, useLast)
 	},
@@ -150,6 +161,7 @@ func init() {
 	runCmd.Flags().String("small-model", "", "Small model to use. If not provided, uses the default small model for the provider")
 	runCmd.Flags().StringP("session", "s", "", "Continue a previous session by ID")
 	runCmd.Flags().BoolP("continue", "C", false, "Continue the most recent session")
+	runCmd.Flags().String("policy", "", "Permission policy file deciding tool permission prompts") // XRUSH: permission policy
 	runCmd.MarkFlagsMutuallyExclusive("session", "continue")
 }
 
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -102,6 +103,8 @@ type sessionServices struct {
 	sessions session.Service
 	messages message.Service
 	cfg      *config.ConfigStore
+	queries  db.Querier // XRUSH: file history for session diff
+	conn     *sql.DB    // XRUSH: transaction for handoff import
 }
 
 func sessionSetup(cmd *cobra.Command) (context.Context, *sessionServices, func(), error) {
@@ -129,6 +132,8 @@ func sessionSetup(cmd *cobra.Command) (context.Context, *sessionServices, func()
 		sessions: session.NewService(queries, conn),
 		messages: message.NewService(queries),
 		cfg:      cfg,
+		queries:  queries, // XRUSH: file history for session diff
+		conn:     conn,    // XRUSH: transaction for handoff import
 	}
 	return ctx, svc, func() { conn.Close() }, nil
 }
@@ -425,12 +430,15 @@ func outputSessionJSON(w io.Writer, sess session.Session, msgs []*message.Messag
 
 	for i, msg := range msgs {
 		output.Messages[i] = sessionShowMessage{
-			ID:       msg.ID,
-			Role:     string(msg.Role),
-			Created:  time.Unix(msg.CreatedAt, 0).Format(time.RFC3339),
-			Model:    msg.Model,
-			Provider: msg.Provider,
-			Parts:    convertParts(msg.Parts),
+			ID:               msg.ID,
+			Role:             string(msg.Role),
+			Created:          time.Unix(msg.CreatedAt, 0).Format(time.RFC3339),
+			Model:            msg.Model,
+			Provider:         msg.Provider,
+			PromptTokens:     msg.PromptTokens,
+			CompletionTokens: msg.CompletionTokens,
+			Cost:             msg.Cost,
+			Parts:            convertParts(msg.Parts),
 		}
 	}
 
@@ -588,12 +596,15 @@ type sessionShowSkill struct {
 }
 
 type sessionShowMessage struct {
-	ID       string            `json:"id"`
-	Role     string            `json:"role"`
-	Created  string            `json:"created"`
-	Model    string            `json:"model,omitempty"`
-	Provider string            `json:"provider,omitempty"`
-	Parts    []sessionShowPart `json:"parts"`
+	ID               string            `json:"id"`
+	Role             string            `json:"role"`
+	Created          string            `json:"created"`
+	Model            string            `json:"model,omitempty"`
+	Provider         string            `json:"provider,omitempty"`
+	PromptTokens     int64             `json:"prompt_tokens,omitempty"`
+	CompletionTokens int64             `json:"completion_tokens,omitempty"`
+	Cost             float64           `json:"cost,omitempty"`
+	Parts            []sessionShowPart `json:"parts"`
 }
 
 type sessionShowPart struct {
@@ -603,9 +614,10 @@ type sessionShowPart struct {
 	Text string `json:"text,omitempty"`
 
 	// Reasoning
-	Thinking   string `json:"thinking,omitempty"`
-	StartedAt  int64  `json:"started_at,omitempty"`
-	FinishedAt int64  `json:"finished_at,omitempty"`
+	Thinking        string `json:"thinking,omitempty"`
+	StartedAt       int64  `json:"started_at,omitempty"`
+	FinishedAt      int64  `json:"finished_at,omitempty"`
+	ReasoningTokens int64  `json:"reasoning_tokens,omitempty"`
 
 	// Tool call
 	ToolCallID string `json:"tool_call_id,omitempty"`
@@ -627,6 +639,12 @@ type sessionShowPart struct {
 	// Finish
 	Reason string `json:"reason,omitempty"`
 	Time   int64  `json:"time,omitempty"`
+
+	// Context attribution
+	Attribution *message.ContextAttribution `json:"attribution,omitempty"` // XRUSH: context attribution
+
+	// Sampled candidates
+	Candidates *message.ResponseCandidates `json:"candidates,omitempty"` // XRUSH: multi-candidate sampling
 }
 
 func extractSkillsFromMessages(msgs []*message.Message) []sessionShowSkill {
@@ -674,10 +692,11 @@ func convertParts(parts []message.ContentPart) []sessionShowPart {
 			})
 		case message.ReasoningContent:
 			result = append(result, sessionShowPart{
-				Type:       "reasoning",
-				Thinking:   p.Thinking,
-				StartedAt:  p.StartedAt,
-				FinishedAt: p.FinishedAt,
+				Type:            "reasoning",
+				Thinking:        p.Thinking,
+				StartedAt:       p.StartedAt,
+				FinishedAt:      p.FinishedAt,
+				ReasoningTokens: p.Tokens,
 			})
 		case message.ToolCall:
 			result = append(result, sessionShowPart{
@@ -713,6 +732,16 @@ func convertParts(parts []message.ContentPart) []sessionShowPart {
 				Reason: string(p.Reason),
 				Time:   p.Time,
 			})
+		case message.ContextAttribution: // XRUSH: context attribution
+			result = append(result, sessionShowPart{
+				Type:        "context_attribution",
+				Attribution: &p,
+			})
+		case message.ResponseCandidates: // XRUSH: multi-candidate sampling
+			result = append(result, sessionShowPart{
+				Type:       "response_candidates",
+				Candidates: &p,
+			})
 		default:
 			result = append(result, sessionShowPart{
 				Type: "unknown",
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/event"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionCandidatesJSON   bool
+	sessionCandidatesChoose int
+)
+
+var sessionCandidatesCmd = &cobra.Command{
+	Use:   "candidates <id>",
+	Short: "Show the sampled answers of a session",
+	Long: `Show the candidate answers sampled for the turns of a session when
+multi-candidate sampling is enabled, with the judge's scores and choice.
+Use --choose to make another candidate of the last sampled turn its reply.
+IDs can be a UUID, full hash, or hash prefix.`,
+	Example: `
+# Read the alternatives of every sampled turn
+crush session candidates 3f2a
+
+# Keep the second candidate of the last sampled turn instead
+crush session candidates 3f2a --choose 2
+  `,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionCandidates,
+}
+
+func init() {
+	sessionCandidatesCmd.Flags().BoolVar(&sessionCandidatesJSON, "json", false, "output in JSON format")
+	sessionCandidatesCmd.Flags().IntVar(&sessionCandidatesChoose, "choose", 0, "candidate number to make the reply of the last sampled turn")
+	sessionCmd.AddCommand(sessionCandidatesCmd)
+}
+
+type sessionCandidatesTurn struct {
+	MessageID  string                     `json:"message_id"`
+	Candidates message.ResponseCandidates `json:"candidates"`
+}
+
+func runSessionCandidates(cmd *cobra.Command, args []string) error {
+	event.SetNonInteractive(true)
+
+	ctx, svc, cleanup, err := sessionSetup(cmd)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	sess, err := resolveSessionID(ctx, svc.sessions, args[0])
+	if err != nil {
+		return err
+	}
+	msgs, err := svc.messages.List(ctx, sess.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	var sampled []message.Message
+	for _, msg := range msgs {
+		if msg.ResponseCandidates() != nil {
+			sampled = append(sampled, msg)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if sessionCandidatesChoose != 0 {
+		if len(sampled) == 0 {
+			return fmt.Errorf("session %s has no sampled turns", session.HashID(sess.ID)[:7])
+		}
+		last := sampled[len(sampled)-1]
+		if !last.ChooseCandidate(sessionCandidatesChoose - 1) {
+			return fmt.Errorf("no candidate %d; the last sampled turn has %d", sessionCandidatesChoose, len(last.ResponseCandidates().Candidates))
+		}
+		if err := svc.messages.Update(ctx, last); err != nil {
+			return fmt.Errorf("failed to update message: %w", err)
+		}
+		if err := svc.messages.FlushAll(ctx); err != nil {
+			return fmt.Errorf("failed to update message: %w", err)
+		}
+		fmt.Fprintf(out, "Candidate %d is now the reply\n", sessionCandidatesChoose)
+		return nil
+	}
+
+	if sessionCandidatesJSON {
+		turns := make([]sessionCandidatesTurn, 0, len(sampled))
+		for _, msg := range sampled {
+			turns = append(turns, sessionCandidatesTurn{MessageID: msg.ID, Candidates: *msg.ResponseCandidates()})
+		}
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(turns)
+	}
+
+	if len(sampled) == 0 {
+		fmt.Fprintln(out, "No sampled turns")
+		return nil
+	}
+	for i, msg := range sampled {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprint(out, formatCandidates(msg.ID, *msg.ResponseCandidates()))
+	}
+	return nil
+}
+
+// formatCandidates renders the candidates of one sampled turn.
+func formatCandidates(messageID string, c message.ResponseCandidates) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Turn %s: %d candidates", messageID, len(c.Candidates))
+	if c.Judge != "" {
+		fmt.Fprintf(&b, ", judged by %s", c.Judge)
+	}
+	if cost := c.Cost(); cost > 0 {
+		fmt.Fprintf(&b, ", +$%.4f", cost)
+	}
+	b.WriteString("\n")
+	if c.Reason != "" {
+		fmt.Fprintf(&b, "Reason: %s\n", c.Reason)
+	}
+	for i, cand := range c.Candidates {
+		marker := " "
+		if i == c.Chosen {
+			marker = "*"
+		}
+		fmt.Fprintf(&b, "\n%s #%d %s", marker, i+1, cand.Model)
+		if cand.Temperature != nil {
+			fmt.Fprintf(&b, " t=%g", *cand.Temperature)
+		}
+		if cand.Score > 0 {
+			fmt.Fprintf(&b, " score %g", cand.Score)
+		}
+		b.WriteString("\n")
+		for line := range strings.SplitSeq(strings.TrimRight(cand.Text, "\n"), "\n") {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+	}
+	return b.String()
+}
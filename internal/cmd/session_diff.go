@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/charmbracelet/crush/internal/event"
+	"github.com/charmbracelet/crush/internal/rewind"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionDiffJSON  bool
+	sessionDiffPatch bool
+)
+
+var sessionDiffCmd = &cobra.Command{
+	Use:   "diff <id> [<id>]",
+	Short: "Compare the working tree of sessions",
+	Long: `Compare the files touched by sessions. With one ID, compare the files
+as they were when that session started with their current content on disk.
+With two IDs, compare the state left by the first session with the state
+left by the second. Use --patch for the full unified diff and --json for
+machine-readable output. IDs can be a UUID, full hash, or hash prefix.`,
+	Example: `
+# What changed on disk since a session started
+crush session diff 3f2a
+
+# Compare the results of two sessions
+crush session diff 3f2a 9c41 --patch
+  `,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runSessionDiff,
+}
+
+func init() {
+	sessionDiffCmd.Flags().BoolVar(&sessionDiffJSON, "json", false, "output in JSON format")
+	sessionDiffCmd.Flags().BoolVar(&sessionDiffPatch, "patch", false, "print the full unified diff")
+	sessionCmd.AddCommand(sessionDiffCmd)
+}
+
+type sessionDiffFile struct {
+	Path      string `json:"path"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+type sessionDiffResult struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Files   []sessionDiffFile `json:"files"`
+	Summary string            `json:"summary,omitempty"`
+	Patch   string            `json:"patch,omitempty"`
+}
+
+func runSessionDiff(cmd *cobra.Command, args []string) error {
+	event.SetNonInteractive(true)
+
+	ctx, svc, cleanup, err := sessionSetup(cmd)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	from, err := resolveSessionID(ctx, svc.sessions, args[0])
+	if err != nil {
+		return err
+	}
+
+	differ := rewind.NewDiffer(svc.queries, svc.cfg.WorkingDir())
+	var result *rewind.WorkspaceDiff
+	if len(args) == 1 {
+		result, err = differ.DiffSinceStart(ctx, from.ID)
+	} else {
+		to, resolveErr := resolveSessionID(ctx, svc.sessions, args[1])
+		if resolveErr != nil {
+			return resolveErr
+		}
+		result, err = differ.DiffSessions(ctx, from.ID, to.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to diff sessions: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if sessionDiffJSON {
+		files := make([]sessionDiffFile, 0, len(result.Files))
+		for _, f := range result.Files {
+			files = append(files, sessionDiffFile(f))
+		}
+		payload := sessionDiffResult{From: result.From, To: result.To, Files: files, Summary: result.Summary}
+		if sessionDiffPatch {
+			payload.Patch = result.Patch
+		}
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(payload)
+	}
+
+	if len(result.Files) == 0 {
+		fmt.Fprintf(out, "No differences between %s and %s\n", result.From, result.To)
+		return nil
+	}
+	if sessionDiffPatch {
+		fmt.Fprint(out, result.Patch)
+		return nil
+	}
+	fmt.Fprintf(out, "Comparing %s with %s\n\n", result.From, result.To)
+	fmt.Fprint(out, result.Summary)
+	return nil
+}
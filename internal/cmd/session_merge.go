@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/event"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/sessionmerge"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionMergeMessages []string
+	sessionMergeYes      bool
+	sessionMergeDryRun   bool
+)
+
+var sessionMergeCmd = &cobra.Command{
+	Use:   "merge <source> <target>",
+	Short: "Merge the messages of one session into another",
+	Long: `Copy the messages of the source session to the end of the target
+session, for instance to carry an investigation into the session
+implementing it. Use --messages to merge some messages only; a selected
+tool call brings its result along, and the other way around.
+
+Pinned notes the target already has are dropped from the merged messages,
+and the LCM large files they reference are copied into the target under
+new IDs. The merge is previewed and confirmed before anything is written;
+the source session is left as it was. IDs can be a UUID, full hash, or
+hash prefix; message IDs can be prefixes.`,
+	Example: `
+# Preview merging a whole session
+crush session merge 3f2a 9c41 --dry-run
+
+# Merge two messages without asking
+crush session merge 3f2a 9c41 --messages 5d0e,7b21 --yes
+  `,
+	Args: cobra.ExactArgs(2),
+	RunE: runSessionMerge,
+}
+
+func init() {
+	sessionMergeCmd.Flags().StringSliceVar(&sessionMergeMessages, "messages", nil, "IDs of the source messages to merge (default: all)")
+	sessionMergeCmd.Flags().BoolVarP(&sessionMergeYes, "yes", "y", false, "merge without asking for confirmation")
+	sessionMergeCmd.Flags().BoolVar(&sessionMergeDryRun, "dry-run", false, "preview the merge without writing it")
+	sessionCmd.AddCommand(sessionMergeCmd)
+}
+
+func runSessionMerge(cmd *cobra.Command, args []string) error {
+	event.SetNonInteractive(true)
+
+	ctx, svc, cleanup, err := sessionSetup(cmd)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	source, err := resolveSessionID(ctx, svc.sessions, args[0])
+	if err != nil {
+		return err
+	}
+	target, err := resolveSessionID(ctx, svc.sessions, args[1])
+	if err != nil {
+		return err
+	}
+	plan, err := sessionmerge.Preview(ctx, svc.queries, sessionmerge.Options{
+		SourceID:   source.ID,
+		TargetID:   target.ID,
+		MessageIDs: sessionMergeMessages,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	writeMergePlan(out, plan)
+	if sessionMergeDryRun {
+		return nil
+	}
+	if !sessionMergeYes {
+		if !term.IsTerminal(os.Stdin.Fd()) {
+			return errors.New("not merged: confirm with --yes")
+		}
+		fmt.Fprint(out, "\nMerge? (y/N) ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.TrimSpace(answer); !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			fmt.Fprintln(out, "Merge cancelled.")
+			return nil
+		}
+	}
+
+	queries, ok := svc.queries.(*db.Queries)
+	if !ok {
+		return errors.New("session database does not support transactions")
+	}
+	if err := sessionmerge.Apply(ctx, svc.conn, queries, plan); err != nil {
+		return fmt.Errorf("failed to merge sessions: %w", err)
+	}
+	fmt.Fprintf(out, "Merged %d messages into %s\n", len(plan.Messages), session.HashID(target.ID)[:7])
+	return nil
+}
+
+// writeMergePlan prints the preview of a merge.
+func writeMergePlan(w io.Writer, p *sessionmerge.Plan) {
+	fmt.Fprintf(w, "Merge %s (%q) into %s (%q):\n",
+		session.HashID(p.Source.ID)[:7], p.Source.Title, session.HashID(p.Target.ID)[:7], p.Target.Title)
+	fmt.Fprintf(w, "  %d messages", len(p.Messages))
+	if p.Widened > 0 {
+		fmt.Fprintf(w, " (%d added to keep tool calls with their results)", p.Widened)
+	}
+	fmt.Fprintln(w)
+	for _, m := range p.Messages {
+		fmt.Fprintf(w, "    %-9s %s\n", m.Role, ansi.Truncate(sessionmerge.Excerpt(m), 70, "…"))
+	}
+	if len(p.PinnedNotes) > 0 {
+		fmt.Fprintf(w, "Pinned notes merged:\n  %s\n", strings.Join(p.PinnedNotes, "\n  "))
+	}
+	if len(p.DuplicateNotes) > 0 {
+		fmt.Fprintf(w, "Pinned notes already in the target, dropped:\n  %s\n", strings.Join(p.DuplicateNotes, "\n  "))
+	}
+	if len(p.Files) > 0 {
+		fmt.Fprintln(w, "LCM files:")
+		for _, f := range p.Files {
+			note := "copied"
+			if f.Existing {
+				note = "already in the target"
+			}
+			fmt.Fprintf(w, "  %s -> %s  %s (%s)\n", f.From, f.To, f.Path, note)
+		}
+	}
+	if len(p.MissingFiles) > 0 {
+		fmt.Fprintf(w, "LCM files not found, references kept: %s\n", strings.Join(p.MissingFiles, ", "))
+	}
+}
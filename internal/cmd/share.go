@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/cast"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/workspace"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+// recordAuto is the value of a bare --record: the recording is named after
+// its start time in the recordings directory.
+const recordAuto = "auto"
+
+// recordingsDir is where recordings are kept, under the data directory.
+const recordingsDir = "recordings"
+
+var (
+	shareOutput    string
+	shareTitle     string
+	shareIdleLimit time.Duration
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share [cast]",
+	Short: "Turn a session recording into an HTML replay",
+	Long: `Write a session recording, made with crush --record, as a single HTML file
+that replays it in any browser without network access, for demos and bug
+reports. Without a path the most recent recording of the project is used.
+
+Recordings mask well-known credential formats and the values of secret
+environment variables as they are written, but a secret typed into the
+prompt one key at a time is drawn in pieces and cannot be recognized:
+review a replay before sharing it.`,
+	Example: `
+# Record a session, then share it
+crush --record
+crush share -o bug.html
+
+# Record to a given file, an asciinema cast
+crush --record=demo.cast
+crush share demo.cast --title "Refactoring with crush" --idle-limit 1s
+  `,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShare,
+}
+
+func init() {
+	shareCmd.Flags().StringVarP(&shareOutput, "output", "o", "", "HTML file to write (default: the recording's name with .html)")
+	shareCmd.Flags().StringVar(&shareTitle, "title", "", "title of the replay")
+	shareCmd.Flags().DurationVar(&shareIdleLimit, "idle-limit", cast.DefaultIdleLimit, "longest pause replayed (negative for no limit)")
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	} else {
+		dir, err := shareRecordingsDir(cmd)
+		if err != nil {
+			return err
+		}
+		if path, err = latestRecording(dir); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	rec, err := cast.Read(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	out := cmp.Or(shareOutput, strings.TrimSuffix(path, filepath.Ext(path))+".html")
+	w, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := cast.WriteHTML(w, rec, cast.HTMLOptions{Title: shareTitle, IdleLimit: shareIdleLimit}); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%s, %d events)\n", out, rec.Duration().Round(time.Second), len(rec.Events))
+	return nil
+}
+
+// shareRecordingsDir returns the recordings directory of the project.
+func shareRecordingsDir(cmd *cobra.Command) (string, error) {
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return "", err
+	}
+	dataDir, err := cmd.Flags().GetString("data-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get data directory: %v", err)
+	}
+	cfg, err := config.Load(cwd, dataDir, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to load configuration: %v", err)
+	}
+	return filepath.Join(cfg.Config().Options.DataDirectory, recordingsDir), nil
+}
+
+// latestRecording returns the most recently modified cast in dir.
+func latestRecording(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	type recording struct {
+		path    string
+		modTime time.Time
+	}
+	var recs []recording
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".cast" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		recs = append(recs, recording{filepath.Join(dir, e.Name()), info.ModTime()})
+	}
+	if len(recs) == 0 {
+		return "", fmt.Errorf("no recordings in %s; record a session with crush --record", dir)
+	}
+	return slices.MaxFunc(recs, func(a, b recording) int {
+		return a.modTime.Compare(b.modTime)
+	}).path, nil
+}
+
+// startRecording records the TUI's output to path, or to a new file in
+// the recordings directory when path is recordAuto. The returned TTY
+// replaces stdout as the program's output; stop closes the recording.
+func startRecording(path string, ws workspace.Workspace) (*cast.TTY, func(), error) {
+	cfg := ws.Config()
+	if path == recordAuto {
+		dir := filepath.Join(cfg.Options.DataDirectory, recordingsDir)
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, nil, fmt.Errorf("failed to create recordings directory: %w", err)
+		}
+		path = filepath.Join(dir, time.Now().Format("20060102-150405")+".cast")
+	}
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		return nil, nil, errors.New("--record needs a terminal on stdout")
+	}
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get terminal size: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets := cast.EnvSecrets(os.Environ())
+	for _, p := range cfg.Providers.Seq2() {
+		if !strings.HasPrefix(p.APIKey, "$") {
+			secrets = append(secrets, p.APIKey)
+		}
+	}
+	rec, err := cast.NewRecorder(f, cast.Header{
+		Width:  width,
+		Height: height,
+		Title:  "crush " + filepath.Base(ws.WorkingDir()),
+		Env:    map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+	}, cast.NewRedactor(secrets...))
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return cast.NewTTY(os.Stdout, rec, width, height), func() {
+		if err := f.Close(); err == nil {
+			fmt.Fprintf(os.Stderr, "Session recorded to %s; share it with crush share %s\n", path, path)
+		}
+	}, nil
+}
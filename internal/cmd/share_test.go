@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestRecording(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := latestRecording(filepath.Join(dir, "missing"))
+	require.ErrorContains(t, err, "no recordings in")
+
+	old := time.Now().Add(-time.Hour)
+	for name, mod := range map[string]time.Time{
+		"20260101-100000.cast": old,
+		"20260101-110000.cast": old.Add(time.Minute),
+		"notes.html":           time.Now(),
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0o600))
+		require.NoError(t, os.Chtimes(path, mod, mod))
+	}
+	path, err := latestRecording(dir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "20260101-110000.cast"), path)
+}
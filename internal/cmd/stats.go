@@ -60,6 +60,7 @@ type Stats struct {
 	RecentActivity    []DailyActivity    `json:"recent_activity"`
 	AvgResponseTimeMs float64            `json:"avg_response_time_ms"`
 	ToolUsage         []ToolUsage        `json:"tool_usage"`
+	ExplorerUsage     []ExplorerUsage    `json:"explorer_usage"`
 	HourDayHeatmap    []HourDayHeatmapPt `json:"hour_day_heatmap"`
 }
 
@@ -84,9 +85,12 @@ type DailyUsage struct {
 }
 
 type ModelUsage struct {
-	Model        string `json:"model"`
-	Provider     string `json:"provider"`
-	MessageCount int64  `json:"message_count"`
+	Model            string  `json:"model"`
+	Provider         string  `json:"provider"`
+	MessageCount     int64   `json:"message_count"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
 }
 
 type HourlyUsage struct {
@@ -114,6 +118,15 @@ type ToolUsage struct {
 	CallCount int64  `json:"call_count"`
 }
 
+type ExplorerUsage struct {
+	Explorer      string  `json:"explorer"`
+	Explorations  int64   `json:"explorations"`
+	Fallbacks     int64   `json:"fallbacks"`
+	ParseFailures int64   `json:"parse_failures"`
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+	Bytes         int64   `json:"bytes"`
+}
+
 type HourDayHeatmapPt struct {
 	DayOfWeek    int   `json:"day_of_week"`
 	Hour         int   `json:"hour"`
@@ -226,9 +239,12 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 	}
 	for _, m := range modelUsage {
 		stats.UsageByModel = append(stats.UsageByModel, ModelUsage{
-			Model:        m.Model,
-			Provider:     m.Provider,
-			MessageCount: m.MessageCount,
+			Model:            m.Model,
+			Provider:         m.Provider,
+			MessageCount:     m.MessageCount,
+			PromptTokens:     m.PromptTokens,
+			CompletionTokens: m.CompletionTokens,
+			Cost:             m.Cost,
 		})
 	}
 
@@ -294,6 +310,26 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 		}
 	}
 
+	// Explorer usage.
+	explorerMetrics, err := queries.ListExplorerMetrics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get explorer usage: %w", err)
+	}
+	for _, e := range explorerMetrics {
+		var avg float64
+		if e.Explorations > 0 {
+			avg = float64(e.DurationUs) / float64(e.Explorations) / 1000
+		}
+		stats.ExplorerUsage = append(stats.ExplorerUsage, ExplorerUsage{
+			Explorer:      e.Explorer,
+			Explorations:  e.Explorations,
+			Fallbacks:     e.Fallbacks,
+			ParseFailures: e.ParseFailures,
+			AvgDurationMs: avg,
+			Bytes:         e.Bytes,
+		})
+	}
+
 	// Hour/day heatmap.
 	heatmap, err := queries.GetHourDayHeatmap(ctx)
 	if err != nil {
@@ -33,6 +33,13 @@ function formatCost(n) {
   return "$" + n.toFixed(2);
 }
 
+function formatBytes(n) {
+  if (n >= 1073741824) return (n / 1073741824).toFixed(1) + " GiB";
+  if (n >= 1048576) return (n / 1048576).toFixed(1) + " MiB";
+  if (n >= 1024) return (n / 1024).toFixed(1) + " KiB";
+  return Math.round(n) + " B";
+}
+
 function formatTime(ms) {
   if (ms < 1000) return Math.round(ms) + "ms";
   return (ms / 1000).toFixed(1) + "s";
@@ -236,6 +243,52 @@ if (stats.tool_usage?.length > 0) {
   });
 }
 
+if (stats.explorer_usage?.length > 0) {
+  const displayExplorers = stats.explorer_usage.slice(0, 15);
+  new Chart(document.getElementById("explorerChart"), {
+    type: "bar",
+    data: {
+      labels: displayExplorers.map((e) => e.explorer),
+      datasets: [
+        {
+          label: "Explorations",
+          data: displayExplorers.map((e) => e.explorations),
+          backgroundColor: colors.charple,
+          borderRadius: 4,
+        },
+        {
+          label: "Parse failures",
+          data: displayExplorers.map((e) => e.parse_failures),
+          backgroundColor: colors.cherry,
+          borderRadius: 4,
+        },
+      ],
+    },
+    options: {
+      indexAxis: "y",
+      responsive: true,
+      maintainAspectRatio: false,
+      animation: { duration: easeDuration, easing: easeType },
+      plugins: {
+        legend: { position: "bottom" },
+        tooltip: {
+          callbacks: {
+            afterLabel: (ctx) => {
+              if (ctx.datasetIndex !== 0) return "";
+              const e = displayExplorers[ctx.dataIndex];
+              return [
+                `Fallbacks: ${formatNumber(e.fallbacks)}`,
+                `Avg time: ${formatTime(e.avg_duration_ms)}`,
+                `Processed: ${formatBytes(e.bytes)}`,
+              ];
+            },
+          },
+        },
+      },
+    },
+  });
+}
+
 // Token Distribution Pie
 new Chart(document.getElementById("tokenPieChart"), {
   type: "doughnut",
@@ -294,7 +347,21 @@ if (stats.usage_by_model?.length > 0) {
       responsive: true,
       maintainAspectRatio: false,
       animation: { duration: easeDuration, easing: easeType },
-      plugins: { legend: { display: false } },
+      plugins: {
+        legend: { display: false },
+        tooltip: {
+          callbacks: {
+            afterLabel: (ctx) => {
+              const m = displayModels[ctx.dataIndex];
+              if (!m.prompt_tokens && !m.completion_tokens) return "";
+              return [
+                `Tokens: ${formatNumber(m.prompt_tokens)} in / ${formatNumber(m.completion_tokens)} out`,
+                `Cost: ${formatCost(m.cost)}`,
+              ];
+            },
+          },
+        },
+      },
     },
   });
 }
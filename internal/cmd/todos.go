@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/techdebt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	todosTags          []string
+	todosPath          string
+	todosAuthor        string
+	todosGrep          string
+	todosOlderThanDays int
+	todosSort          string
+	todosLimit         int
+	todosJSON          bool
+)
+
+var todosCmd = &cobra.Command{
+	Use:   "todos",
+	Short: "List TODO, FIXME, HACK and XXX comments",
+	Long: `List the tech-debt comments of the project with their file, line, owner,
+and the author and age of the line from git blame. The inventory is cached
+in the data directory and only changed files are re-read.`,
+	Example: `
+# Everything, counted by tag
+crush todos
+
+# The oldest FIXMEs and HACKs under internal/, untouched for a year
+crush todos --tag fixme,hack --path internal --older-than-days 365 --sort age
+  `,
+	Args: cobra.NoArgs,
+	RunE: runTodos,
+}
+
+func init() {
+	todosCmd.Flags().StringSliceVar(&todosTags, "tag", nil, "tags to keep: TODO, FIXME, HACK, XXX")
+	todosCmd.Flags().StringVar(&todosPath, "path", "", "directory or part of a path to restrict to")
+	todosCmd.Flags().StringVar(&todosAuthor, "author", "", "part of the blamed author or TODO(owner) name")
+	todosCmd.Flags().StringVar(&todosGrep, "grep", "", "text the comment must contain")
+	todosCmd.Flags().IntVar(&todosOlderThanDays, "older-than-days", 0, "keep comments last changed more than this many days ago")
+	todosCmd.Flags().StringVar(&todosSort, "sort", techdebt.SortPath, "order: path or age (oldest first)")
+	todosCmd.Flags().IntVar(&todosLimit, "limit", 0, "maximum comments to list (0 for all)")
+	todosCmd.Flags().BoolVar(&todosJSON, "json", false, "output in JSON format")
+}
+
+func runTodos(cmd *cobra.Command, _ []string) error {
+	if todosSort != techdebt.SortPath && todosSort != techdebt.SortAge {
+		return fmt.Errorf("invalid --sort %q: use %s or %s", todosSort, techdebt.SortPath, techdebt.SortAge)
+	}
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return err
+	}
+	dataDir, err := cmd.Flags().GetString("data-dir")
+	if err != nil {
+		return fmt.Errorf("failed to get data directory: %v", err)
+	}
+	cfg, err := config.Load(cwd, dataDir, false)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %v", err)
+	}
+
+	inv, err := techdebt.Load(cmd.Context(), cfg.WorkingDir(), cfg.Config().Options.DataDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to build the inventory: %w", err)
+	}
+	now := time.Now()
+	items := inv.Items(techdebt.Filter{
+		Tags:      todosTags,
+		Path:      todosPath,
+		Author:    todosAuthor,
+		Text:      todosGrep,
+		OlderThan: time.Duration(todosOlderThanDays) * 24 * time.Hour,
+		Sort:      todosSort,
+	}, now)
+
+	out := cmd.OutOrStdout()
+	if todosJSON {
+		if todosLimit > 0 && len(items) > todosLimit {
+			items = items[:todosLimit]
+		}
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(items)
+	}
+	fmt.Fprint(out, techdebt.Report(items, inv.Total(), todosLimit, now))
+	return nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// vcsMarkers are the entries whose presence marks the root of a version
+// controlled tree.
+var vcsMarkers = []string{".git", ".hg", ".svn", ".jj", ".bzr", "_darcs", ".fossil"}
+
+// InRepository reports whether dir or one of its parents is the root of a
+// version controlled tree. It only looks for marker entries, so it is
+// cheap enough to run before anything else at startup.
+func InRepository(dir string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for {
+		for _, marker := range vcsMarkers {
+			if _, err := os.Lstat(filepath.Join(dir, marker)); err == nil {
+				return true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// ApplyBareMode tunes the store for a working directory outside any
+// repository, where crush is typically asked about a single file: repo map
+// generation, automatic LSP setup and the project initialization prompt
+// are skipped. LSPs configured explicitly still start, and an explicit
+// auto_lsp setting is kept. Only the in-memory config changes.
+func (s *ConfigStore) ApplyBareMode() {
+	s.overrides.Bare = true
+	opts := s.config.Options
+	if opts == nil {
+		return
+	}
+	if opts.RepoMap == nil {
+		opts.RepoMap = &RepoMapOptions{}
+	}
+	opts.RepoMap.Disabled = true
+	if opts.AutoLSP == nil {
+		autoLSP := false
+		opts.AutoLSP = &autoLSP
+	}
+}
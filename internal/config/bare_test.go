@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInRepository(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	if InRepository(root) {
+		t.Skip("temp directory is inside a repository")
+	}
+	require.False(t, InRepository(nested))
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".hg"), 0o755))
+	require.True(t, InRepository(root))
+	require.True(t, InRepository(nested))
+}
+
+func TestApplyBareMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disables repo map and auto LSP", func(t *testing.T) {
+		t.Parallel()
+		dataDir := t.TempDir()
+		workDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "server.log"), []byte("x"), 0o644))
+		store := testStore(&Config{Options: &Options{DataDirectory: dataDir}})
+		store.workingDir = workDir
+
+		store.ApplyBareMode()
+		require.True(t, store.Overrides().Bare)
+		require.True(t, store.Config().Options.RepoMap.Disabled)
+		require.False(t, *store.Config().Options.AutoLSP)
+		needsInit, err := ProjectNeedsInitialization(store)
+		require.NoError(t, err)
+		require.False(t, needsInit)
+	})
+
+	t.Run("keeps explicit auto LSP", func(t *testing.T) {
+		t.Parallel()
+		autoLSP := true
+		store := testStore(&Config{Options: &Options{AutoLSP: &autoLSP, RepoMap: &RepoMapOptions{MaxTokens: 10}}})
+		store.ApplyBareMode()
+		require.True(t, *store.Config().Options.AutoLSP)
+		require.Equal(t, 10, store.Config().Options.RepoMap.MaxTokens)
+	})
+}
@@ -83,6 +83,9 @@ type SelectedModel struct {
 	TopK             *int64   `json:"top_k,omitempty" jsonschema:"description=Top-k sampling parameter"`
 	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty" jsonschema:"description=Frequency penalty to reduce repetition"`
 	PresencePenalty  *float64 `json:"presence_penalty,omitempty" jsonschema:"description=Presence penalty to increase topic diversity"`
+	// XRUSH: stop sequences, sent by providers that accept them in the
+	// request body (mostly local openai-compat servers).
+	Stop []string `json:"stop,omitempty" jsonschema:"description=Sequences that end generation; only sent to openai-compat\\, openrouter\\, vercel and hyper providers,example=<|im_end|>"`
 
 	// Override provider specific options.
 	ProviderOptions map[string]any `json:"provider_options,omitempty" jsonschema:"description=Additional provider-specific options for the model"`
@@ -225,6 +228,19 @@ type TUIOptions struct {
 
 	Completions Completions `json:"completions,omitzero" jsonschema:"description=Completions UI options"`
 	Transparent *bool       `json:"transparent,omitempty" jsonschema:"description=Enable transparent background for the TUI interface,default=false"`
+	// ShowMessageUsage annotates assistant messages with their token usage
+	// and cost. It can be toggled at runtime from the chat.
+	ShowMessageUsage bool `json:"show_message_usage,omitempty" jsonschema:"description=Show per-message token usage and cost in the chat,default=false"`
+	// Accessibility switches the TUI to a screen reader friendly mode:
+	// no animations, linearized chat output with role prefixes and a
+	// high-contrast theme. The ACCESSIBLE environment variable enables
+	// it too.
+	Accessibility bool `json:"accessibility,omitempty" jsonschema:"description=Enable screen reader friendly accessibility mode,default=false"`
+	// EditorLinks turns file:line references in tool output into links
+	// that open the file in an editor: an editor preset (vscode, cursor,
+	// zed, idea, sublime, ...), a URL template, or a command template run
+	// on double click. Templates use {path}, {line} and {col}.
+	EditorLinks string `json:"editor_links,omitempty" jsonschema:"description=Open file:line references in an editor: a preset (vscode cursor zed idea sublime file ...) or a URL or command template with {path} {line} and {col},example=vscode,example=code -g {path}:{line}:{col}"` // XRUSH: editor links
 }
 
 // Completions defines options for the completions UI.
@@ -324,12 +340,95 @@ type Options struct {
 	// (default), beta tools are hidden from the tool surface.
 	BetaTools bool `json:"beta_tools,omitempty" jsonschema:"description=Enable beta tools that are hidden by default,default=false"`
 
+	// ReviewEdits stages file edits made by the edit, multiedit and write
+	// tools for per-hunk review instead of writing them immediately.
+	ReviewEdits bool `json:"review_edits,omitempty" jsonschema:"description=Stage file edits for per-hunk review before they are written to disk,default=false"`
+
+	// CompressSystemPrompt rewrites the static system prompt sections and
+	// tool descriptions into terser equivalents with the small model. The
+	// rewrite runs once per version and is cached. It is ignored in parity
+	// mode.
+	CompressSystemPrompt bool `json:"compress_system_prompt,omitempty" jsonschema:"description=Compress static system prompt sections and tool descriptions with the small model (ignored in parity mode),default=false"`
+
+	// Offline turns off optional features that reach the network on their
+	// own, such as fetching remote URIs for exploration. Model provider
+	// traffic is unaffected.
+	Offline bool `json:"offline,omitempty" jsonschema:"description=Disable optional network features such as remote artifact exploration,default=false"`
+
+	// Voice configures push-to-talk voice input.
+	Voice *VoiceOptions `json:"voice,omitempty" jsonschema:"description=Push-to-talk voice input configuration"`
+
+	// Notifications gates desktop notifications for long-running turns and
+	// permission prompts. They are off unless explicitly enabled.
+	Notifications *NotificationOptions `json:"notifications,omitempty" jsonschema:"description=When to send desktop notifications (off by default)"`
+
+	// OutputPane configures opening tool output, diffs and expanded
+	// content in a tmux pane or iTerm2 split.
+	OutputPane *OutputPaneOptions `json:"output_pane,omitempty" jsonschema:"description=Open chat items in a tmux pane or iTerm2 split"`
+
+	// Translation configures translating assistant messages into another
+	// language with the small model.
+	Translation *TranslationOptions `json:"translation,omitempty" jsonschema:"description=Translate assistant messages into another language"`
+
+	// Knowledge configures the shared project knowledge base in the
+	// knowledge/ folder of the data directory.
+	Knowledge *KnowledgeOptions `json:"knowledge,omitempty" jsonschema:"description=Project knowledge base retrieved with the knowledge_lookup tool"`
+
+	// FeatureFlags configures the feature flag index behind the
+	// feature_flags tool and the repo map's flag section.
+	FeatureFlags *FeatureFlagOptions `json:"feature_flags,omitempty" jsonschema:"description=Feature flag index for the feature_flags tool and repo map"`
+
+	// AutoDowngrade routes trivial turns (short prompts, plain questions,
+	// summary follow-ups) to the small model.
+	AutoDowngrade *AutoDowngradeOptions `json:"auto_downgrade,omitempty" jsonschema:"description=Route trivial turns to the small model automatically"`
+
+	// CostEstimate shows the prompt tokens and projected cost of a turn
+	// before it is sent, optionally asking for confirmation above a cost.
+	CostEstimate *CostEstimateOptions `json:"cost_estimate,omitempty" jsonschema:"description=Pre-send token and cost estimate of a turn with an optional confirmation threshold"`
+
+	// PermissionPolicy is a policy file that decides permission prompts in
+	// non-interactive runs instead of approving every request. Relative
+	// paths are resolved against the working directory.
+	PermissionPolicy string `json:"permission_policy,omitempty" jsonschema:"description=Policy file of allow/deny/ask rules that decides permission prompts in non-interactive runs instead of approving every request,example=.crush/permission-policy.json"`
+
+	// ContextAttribution annotates assistant replies with the context
+	// sources present for the turn and flags cited files whose content was
+	// not in context.
+	ContextAttribution bool `json:"context_attribution,omitempty" jsonschema:"description=Annotate replies with the context sources present for the turn and flag cited files that were not in context,default=false"`
+
 	// StreamTimeout is the maximum idle time waiting for an LLM response
 	// before the stream is cancelled. Tool execution time is excluded —
 	// the timer only ticks while waiting for the LLM. When zero, a
 	// default of 10 minutes is used.
 	StreamTimeout time.Duration `json:"stream_timeout,omitempty" jsonschema:"description=Maximum idle time waiting for an LLM response (tool execution excluded). Default: 10m,example=10m,example=15m"`
 
+	// Database tunes the SQLite pragmas and connection pool.
+	Database *DatabaseOptions `json:"database,omitempty" jsonschema:"description=SQLite pragma and connection pool tuning for the data directory database"`
+
+	// Startup controls lazy LSP and MCP startup and idle shutdown.
+	Startup *StartupOptions `json:"startup,omitempty" jsonschema:"description=Lazy LSP and MCP startup and idle shutdown"`
+
+	// Verification runs checks after a turn that edited files and sends
+	// failures back to the agent before the turn completes.
+	Verification *VerificationOptions `json:"verification,omitempty" jsonschema:"description=Checks run before a turn that edited files completes"`
+
+	// Sampling draws several candidate final answers on high-stakes turns
+	// and keeps the one a judge model ranks best. Experimental.
+	Sampling *SamplingOptions `json:"sampling,omitempty" jsonschema:"description=Experimental: sample several final answers on high-stakes turns and keep the one a judge model ranks best"`
+
+	// Reasoning controls how reasoning (thinking) traces are captured,
+	// shown and stored.
+	Reasoning *ReasoningOptions `json:"reasoning,omitempty" jsonschema:"description=Capture\\, display and storage of model reasoning traces"`
+
+	// Remote runs the bash tool and the verification checks on a remote
+	// host or in a container instead of locally.
+	Remote *RemoteOptions `json:"remote,omitempty" jsonschema:"description=Run the bash tool and verification checks over SSH\\, docker exec or in the project's devcontainer"`
+
+	// DevEnv runs the bash tool and the verification checks inside the
+	// project's Nix or direnv environment. When unset, a detected
+	// environment is only mentioned to the agent.
+	DevEnv string `json:"dev_env,omitempty" jsonschema:"description=Run the bash tool and verification checks in the project's Nix or direnv environment: auto picks .envrc\\, flake.nix or shell.nix; off does not mention it to the agent either,enum=auto,enum=direnv,enum=flake,enum=nix-shell,enum=off"`
+
 	AutofixTimeout time.Duration `json:"autofix_timeout,omitempty" jsonschema:"description=Timeout for autofix lint/format cycle. Default: 60s,example=30s,example=2m"`
 	// [XRUSH: end]
 }
@@ -639,6 +738,8 @@ type Config struct {
 
 	Hooks map[string][]HookConfig `json:"hooks,omitempty" jsonschema:"description=User-defined shell commands that fire on hook events (e.g. PreToolUse)"`
 
+	Templates map[string]SessionTemplate `json:"templates,omitempty" jsonschema:"description=Named session templates used with crush new --template"`
+
 	Agents map[string]Agent `json:"-"`
 }
 
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"charm.land/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/crush/internal/agent/hyper"
+)
+
+// stopSequenceProviders are the provider types that can send stop
+// sequences; they pass them in the request body.
+var stopSequenceProviders = []catwalk.Type{
+	catwalk.TypeOpenAICompat,
+	catwalk.TypeOpenRouter,
+	catwalk.TypeVercel,
+	hyper.Name,
+}
+
+// validateGenerationParams drops the generation parameters of m that its
+// provider type or model cannot take, and returns a warning for each.
+// model may be nil when the model is unknown.
+func validateGenerationParams(m SelectedModel, providerType catwalk.Type, model *catwalk.Model) (SelectedModel, []string) {
+	var warnings []string
+	drop := func(param, reason string) {
+		warnings = append(warnings, fmt.Sprintf("%s: %s ignored, %s", m.Model, param, reason))
+	}
+
+	noPenalties := providerType == catwalk.TypeAnthropic || providerType == catwalk.TypeBedrock
+	maxTemperature := 2.0
+	if noPenalties {
+		maxTemperature = 1
+	}
+
+	if t := m.Temperature; t != nil && (*t < 0 || *t > maxTemperature) {
+		drop("temperature", fmt.Sprintf("must be between 0 and %g", maxTemperature))
+		m.Temperature = nil
+	}
+	if p := m.TopP; p != nil && (*p <= 0 || *p > 1) {
+		drop("top_p", "must be above 0 and at most 1")
+		m.TopP = nil
+	}
+	if k := m.TopK; k != nil {
+		switch {
+		case providerType == catwalk.TypeOpenAI || providerType == catwalk.TypeAzure:
+			drop("top_k", fmt.Sprintf("not supported by %s providers", providerType))
+			m.TopK = nil
+		case *k <= 0:
+			drop("top_k", "must be positive")
+			m.TopK = nil
+		}
+	}
+	for _, penalty := range []struct {
+		name  string
+		value **float64
+	}{
+		{"frequency_penalty", &m.FrequencyPenalty},
+		{"presence_penalty", &m.PresencePenalty},
+	} {
+		switch v := *penalty.value; {
+		case v == nil:
+		case noPenalties:
+			drop(penalty.name, fmt.Sprintf("not supported by %s providers", providerType))
+			*penalty.value = nil
+		case *v < -2 || *v > 2:
+			drop(penalty.name, "must be between -2 and 2")
+			*penalty.value = nil
+		}
+	}
+	if len(m.Stop) > 0 && !slices.Contains(stopSequenceProviders, providerType) {
+		drop("stop", fmt.Sprintf("not supported by %s providers", providerType))
+		m.Stop = nil
+	}
+	if m.ReasoningEffort != "" && model != nil {
+		switch {
+		case !model.CanReason:
+			drop("reasoning_effort", "the model does not reason")
+			m.ReasoningEffort = ""
+		case len(model.ReasoningLevels) > 0 && !slices.Contains(model.ReasoningLevels, m.ReasoningEffort):
+			drop("reasoning_effort", fmt.Sprintf("must be one of %v", model.ReasoningLevels))
+			m.ReasoningEffort = ""
+		}
+	}
+	return m, warnings
+}
+
+// checkGenerationParams validates the generation parameters of a selected
+// model against its provider and logs the ones it drops.
+func (c *Config) checkGenerationParams(m SelectedModel) SelectedModel {
+	providerCfg, ok := c.Providers.Get(m.Provider)
+	if !ok {
+		return m
+	}
+	checked, warnings := validateGenerationParams(m, cmp.Or(providerCfg.Type, catwalk.TypeOpenAI), c.GetModel(m.Provider, m.Model))
+	for _, w := range warnings {
+		slog.Warn("Ignoring generation parameter", "detail", w)
+	}
+	return checked
+}
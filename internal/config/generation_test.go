@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"charm.land/catwalk/pkg/catwalk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGenerationParams(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(v float64) *float64 { return &v }
+	topK := int64(40)
+	m := SelectedModel{
+		Model:            "local-model",
+		Temperature:      ptr(1.5),
+		TopP:             ptr(0.9),
+		TopK:             &topK,
+		FrequencyPenalty: ptr(0.5),
+		PresencePenalty:  ptr(3),
+		Stop:             []string{"<|im_end|>"},
+		ReasoningEffort:  "high",
+	}
+
+	t.Run("openai-compat keeps what it can send", func(t *testing.T) {
+		t.Parallel()
+		got, warnings := validateGenerationParams(m, catwalk.TypeOpenAICompat, nil)
+		require.Equal(t, m.Temperature, got.Temperature)
+		require.Equal(t, m.TopK, got.TopK)
+		require.Equal(t, m.Stop, got.Stop)
+		require.Equal(t, "high", got.ReasoningEffort, "unknown models keep their effort")
+		require.Nil(t, got.PresencePenalty)
+		require.Equal(t, []string{"local-model: presence_penalty ignored, must be between -2 and 2"}, warnings)
+	})
+
+	t.Run("anthropic drops penalties, stop and hot temperatures", func(t *testing.T) {
+		t.Parallel()
+		got, warnings := validateGenerationParams(m, catwalk.TypeAnthropic, &catwalk.Model{
+			CanReason:       true,
+			ReasoningLevels: []string{"low", "medium", "high"},
+		})
+		require.Nil(t, got.Temperature)
+		require.Nil(t, got.FrequencyPenalty)
+		require.Nil(t, got.PresencePenalty)
+		require.Nil(t, got.Stop)
+		require.Equal(t, m.TopK, got.TopK)
+		require.Equal(t, "high", got.ReasoningEffort)
+		require.Len(t, warnings, 4)
+	})
+
+	t.Run("openai drops top_k and unsupported effort", func(t *testing.T) {
+		t.Parallel()
+		got, warnings := validateGenerationParams(m, catwalk.TypeOpenAI, &catwalk.Model{})
+		require.Nil(t, got.TopK)
+		require.Nil(t, got.Stop)
+		require.Empty(t, got.ReasoningEffort)
+		require.Equal(t, m.FrequencyPenalty, got.FrequencyPenalty)
+		require.Contains(t, warnings, "local-model: reasoning_effort ignored, the model does not reason")
+		require.Contains(t, warnings, "local-model: top_k ignored, not supported by openai providers")
+	})
+}
@@ -30,6 +30,11 @@ func ProjectNeedsInitialization(store *ConfigStore) (bool, error) {
 		return false, fmt.Errorf("config not loaded")
 	}
 
+	// XRUSH: there is no project to initialize outside a repository.
+	if store.Overrides().Bare {
+		return false, nil
+	}
+
 	cfg := store.Config()
 	flagFilePath := filepath.Join(cfg.Options.DataDirectory, InitFlagFilename)
 
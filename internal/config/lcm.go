@@ -26,6 +26,12 @@ type LCMOptions struct {
 	// "parity".
 	ExplorerOutputProfile string `json:"explorer_output_profile,omitempty"`
 
+	// ExplorerPathProfiles overrides ExplorerOutputProfile per runtime
+	// ingestion path, keyed by runtime inventory path ID (for example
+	// "lcm.tool_output.create"). Unknown paths or profiles put every path in
+	// the parity profile.
+	ExplorerPathProfiles map[string]string `json:"explorer_path_profiles,omitempty" jsonschema:"description=Explorer output profile per runtime ingestion path ID; invalid entries fall back to the parity profile"`
+
 	// SessionBudget is the maximum total auto-memory content per session in
 	// characters. When set to 0 (default), the hardcoded constant (60 KB) is
 	// used.
@@ -73,6 +79,127 @@ type LCMOptions struct {
 	// summarizer LLM call during compaction. When the timeout fires, the
 	// compaction layer is skipped and the pipeline continues. Default: 60.
 	SummarizerTimeoutSeconds int `json:"summarizer_timeout,omitempty" jsonschema:"description=Timeout in seconds for LCM summarizer LLM calls during compaction,default=60"`
+
+	// RemoteFetch lets the explorer fetch s3://, https:// and file:// URIs.
+	// When nil, only local content is explored.
+	RemoteFetch *RemoteFetchOptions `json:"remote_fetch,omitempty" jsonschema:"description=Fetching of remote URIs for exploration"`
+
+	// ExploreCache caches exploration results by content, so identical tool
+	// outputs are not parsed again. When nil, nothing is cached.
+	ExploreCache *ExploreCacheOptions `json:"explore_cache,omitempty" jsonschema:"description=Content-addressed cache of exploration results"`
+
+	// SQLiteSampling bounds the row counts and sample rows the explorer
+	// reports for SQLite databases. When nil, the defaults are used.
+	SQLiteSampling *SQLiteSamplingOptions `json:"sqlite_sampling,omitempty" jsonschema:"description=Row counts and sample rows in SQLite database summaries"`
+
+	// ArchiveNesting bounds the archives the explorer opens inside
+	// archives. When nil, the defaults are used.
+	ArchiveNesting *ArchiveNestingOptions `json:"archive_nesting,omitempty" jsonschema:"description=Exploration of archives nested in archives"`
+
+	// ExplorerOutputLimits caps how much of each exploration summary is
+	// kept, per output profile. When nil, the defaults are used.
+	ExplorerOutputLimits *ExplorerOutputLimitsOptions `json:"explorer_output_limits,omitempty" jsonschema:"description=Items, lines and samples kept in exploration summaries per output profile"`
+
+	// RejectUnsafeArchives fails closed on archives in large tool output
+	// whose entries would be unsafe to extract: path traversal, absolute
+	// paths, links escaping the root or archive bombs. Their exploration is
+	// dropped instead of persisted.
+	RejectUnsafeArchives bool `json:"reject_unsafe_archives,omitempty" jsonschema:"description=Drop the exploration of archives with unsafe entries instead of persisting it,default=false"`
+}
+
+// SQLiteSamplingOptions bounds the data shape the explorer reports for
+// SQLite databases under the enhancement profile.
+type SQLiteSamplingOptions struct {
+	// SampleRows is how many rows are sampled per table, in rowid or
+	// primary key order. Default: 3. Negative disables sampling.
+	SampleRows int `json:"sample_rows,omitempty" jsonschema:"description=Rows sampled per table; negative disables sampling,default=3"`
+
+	// MaxTables is how many tables, in name order, are counted and
+	// sampled. Default: 8.
+	MaxTables int `json:"max_tables,omitempty" jsonschema:"description=Maximum number of tables counted and sampled,default=8"`
+
+	// MaxCellLength truncates longer sampled values. Default: 100.
+	MaxCellLength int `json:"max_cell_length,omitempty" jsonschema:"description=Maximum length of a sampled value before truncation,default=100"`
+
+	// SkipRowCounts omits the per-table row counts, which scan every
+	// table.
+	SkipRowCounts bool `json:"skip_row_counts,omitempty" jsonschema:"description=Omit per-table row counts,default=false"`
+}
+
+// ArchiveNestingOptions bounds the exploration of archives inside archives,
+// such as jars inside a ZIP, under the enhancement profile.
+type ArchiveNestingOptions struct {
+	// MaxDepth is how many levels of nested archives are opened. Default:
+	// 2. Negative disables nested exploration.
+	MaxDepth int `json:"max_depth,omitempty" jsonschema:"description=Levels of nested archives opened; negative disables,default=2"`
+
+	// MaxEntryBytes is the largest nested archive opened, in uncompressed
+	// bytes. Larger ones are named but not opened. Default: 33554432
+	// (32 MiB).
+	MaxEntryBytes int64 `json:"max_entry_bytes,omitempty" jsonschema:"description=Largest nested archive opened in uncompressed bytes,default=33554432"`
+}
+
+// ExplorerOutputLimitsOptions sets the output limits of each explorer
+// output profile. The compact and standard aliases use the limits of
+// parity and enhancement; verbose output is never truncated.
+type ExplorerOutputLimitsOptions struct {
+	// Parity holds the limits of the parity profile.
+	Parity *OutputLimitsOptions `json:"parity,omitempty" jsonschema:"description=Output limits of the parity profile"`
+
+	// Enhancement holds the limits of the enhancement profile.
+	Enhancement *OutputLimitsOptions `json:"enhancement,omitempty" jsonschema:"description=Output limits of the enhancement profile"`
+}
+
+// OutputLimitsOptions caps an exploration summary. Users with large
+// context windows can raise them to keep more of each file.
+type OutputLimitsOptions struct {
+	// SectionItems is how many items a summary section lists. Default: 8.
+	SectionItems int `json:"section_items,omitempty" jsonschema:"description=Items listed per summary section,default=8"`
+
+	// SectionLines is how many lines of raw content, such as a text
+	// file's sampled content, a section keeps. Default: 16.
+	SectionLines int `json:"section_lines,omitempty" jsonschema:"description=Lines of raw content kept per summary section,default=16"`
+
+	// TotalLines caps the lines of the whole summary. Default: 0, no cap.
+	TotalLines int `json:"total_lines,omitempty" jsonschema:"description=Maximum lines of a whole summary; 0 for no cap,default=0"`
+
+	// SampleSize is how many error and warning lines are sampled from
+	// log files. Default: 10.
+	SampleSize int `json:"sample_size,omitempty" jsonschema:"description=Error and warning lines sampled from log files,default=10"`
+}
+
+// ExploreCacheOptions configures the exploration cache.
+type ExploreCacheOptions struct {
+	// Backend selects the storage: "memory" keeps results for the process,
+	// "sqlite" keeps them in the data directory database across runs.
+	// Default: "memory".
+	Backend string `json:"backend,omitempty" jsonschema:"description=Storage of cached exploration results,enum=memory,enum=sqlite,default=memory"`
+
+	// MaxEntries caps the cached results; the least recently used are
+	// evicted first. Default: 1024.
+	MaxEntries int `json:"max_entries,omitempty" jsonschema:"description=Maximum number of cached exploration results,default=1024"`
+}
+
+// RemoteFetchOptions configures how the explorer fetches remote URIs. It
+// has no effect in offline or parity mode.
+type RemoteFetchOptions struct {
+	// Enabled turns remote fetching on.
+	Enabled bool `json:"enabled,omitempty" jsonschema:"description=Allow the explorer to fetch remote URIs,default=false"`
+
+	// MaxBytes caps the size of a fetched artifact. Default: 50 MB.
+	MaxBytes int64 `json:"max_bytes,omitempty" jsonschema:"description=Maximum size in bytes of a fetched artifact,default=52428800"`
+
+	// CacheTTLSeconds is how long fetched artifacts are cached on disk.
+	// Default: 3600. Negative disables the cache.
+	CacheTTLSeconds int `json:"cache_ttl_seconds,omitempty" jsonschema:"description=Seconds a fetched artifact stays cached on disk; negative disables the cache,default=3600"`
+
+	// Headers maps a host name to headers sent with HTTPS requests to it.
+	// Values support $VAR environment expansion.
+	Headers map[string]map[string]string `json:"headers,omitempty" jsonschema:"description=Per-host HTTP headers for remote fetches; values support $VAR expansion"`
+
+	// S3Endpoint overrides the AWS S3 endpoint for S3-compatible stores.
+	// Credentials always come from the standard AWS chain.
+	S3Endpoint string `json:"s3_endpoint,omitempty" jsonschema:"description=Endpoint for S3-compatible stores; credentials come from the standard AWS chain"`
 }
 
 // NudgeOptions configures the nudge injection system.
@@ -665,6 +665,9 @@ func configureSelectedModels(store *ConfigStore, knownProviders []catwalk.Provid
 			if largeModelSelected.PresencePenalty != nil {
 				large.PresencePenalty = largeModelSelected.PresencePenalty
 			}
+			if len(largeModelSelected.Stop) > 0 { // XRUSH: custom stop sequences
+				large.Stop = largeModelSelected.Stop
+			}
 		}
 	}
 	smallModelSelected, smallModelConfigured := c.Models[SelectedModelTypeSmall]
@@ -708,6 +711,9 @@ func configureSelectedModels(store *ConfigStore, knownProviders []catwalk.Provid
 			if smallModelSelected.PresencePenalty != nil {
 				small.PresencePenalty = smallModelSelected.PresencePenalty
 			}
+			if len(smallModelSelected.Stop) > 0 { // XRUSH: custom stop sequences
+				small.Stop = smallModelSelected.Stop
+			}
 			small.Think = smallModelSelected.Think
 		}
 	}
@@ -730,6 +736,10 @@ func configureSelectedModels(store *ConfigStore, knownProviders []catwalk.Provid
 		}
 	}
 
+	// XRUSH: drop generation parameters the provider or model cannot take.
+	large = c.checkGenerationParams(large)
+	small = c.checkGenerationParams(small)
+
 	c.Models[SelectedModelTypeLarge] = large
 	c.Models[SelectedModelTypeSmall] = small
 	return nil
@@ -70,6 +70,23 @@ func (o TUIOptions) merge(t TUIOptions) TUIOptions {
 	o.Completions.MaxDepth = cmp.Or(t.Completions.MaxDepth, o.Completions.MaxDepth)
 	o.Completions.MaxItems = cmp.Or(t.Completions.MaxItems, o.Completions.MaxItems)
 	o.Transparent = cmp.Or(t.Transparent, o.Transparent)
+	o.ShowMessageUsage = o.ShowMessageUsage || t.ShowMessageUsage
+	o.Accessibility = o.Accessibility || t.Accessibility
+	o.EditorLinks = cmp.Or(t.EditorLinks, o.EditorLinks)
+	return o
+}
+
+func (o *OutputLimitsOptions) merge(t *OutputLimitsOptions) *OutputLimitsOptions {
+	if t == nil {
+		return o
+	}
+	if o == nil {
+		o = &OutputLimitsOptions{}
+	}
+	o.SectionItems = cmp.Or(t.SectionItems, o.SectionItems)
+	o.SectionLines = cmp.Or(t.SectionLines, o.SectionLines)
+	o.TotalLines = cmp.Or(t.TotalLines, o.TotalLines)
+	o.SampleSize = cmp.Or(t.SampleSize, o.SampleSize)
 	return o
 }
 
@@ -114,6 +131,12 @@ func (o Options) merge(t Options) Options {
 		o.LCM.DisableLargeToolOutput = o.LCM.DisableLargeToolOutput || t.LCM.DisableLargeToolOutput
 		o.LCM.LargeToolOutputTokenThreshold = cmp.Or(t.LCM.LargeToolOutputTokenThreshold, o.LCM.LargeToolOutputTokenThreshold)
 		o.LCM.ExplorerOutputProfile = cmp.Or(t.LCM.ExplorerOutputProfile, o.LCM.ExplorerOutputProfile)
+		for path, profile := range t.LCM.ExplorerPathProfiles {
+			if o.LCM.ExplorerPathProfiles == nil {
+				o.LCM.ExplorerPathProfiles = make(map[string]string)
+			}
+			o.LCM.ExplorerPathProfiles[path] = profile
+		}
 		o.LCM.OperationalMemoryEnabled = o.LCM.OperationalMemoryEnabled || t.LCM.OperationalMemoryEnabled
 		o.LCM.PostCompactMaxFiles = cmp.Or(t.LCM.PostCompactMaxFiles, o.LCM.PostCompactMaxFiles)
 		o.LCM.PostCompactTokenBudget = cmp.Or(t.LCM.PostCompactTokenBudget, o.LCM.PostCompactTokenBudget)
@@ -144,6 +167,52 @@ func (o Options) merge(t Options) Options {
 			o.LCM.Nudge.NudgeFrequency = cmp.Or(t.LCM.Nudge.NudgeFrequency, o.LCM.Nudge.NudgeFrequency)
 			o.LCM.Nudge.NudgeForce = cmp.Or(t.LCM.Nudge.NudgeForce, o.LCM.Nudge.NudgeForce)
 		}
+		if t.LCM.RemoteFetch != nil {
+			if o.LCM.RemoteFetch == nil {
+				o.LCM.RemoteFetch = &RemoteFetchOptions{}
+			}
+			o.LCM.RemoteFetch.Enabled = o.LCM.RemoteFetch.Enabled || t.LCM.RemoteFetch.Enabled
+			o.LCM.RemoteFetch.MaxBytes = cmp.Or(t.LCM.RemoteFetch.MaxBytes, o.LCM.RemoteFetch.MaxBytes)
+			o.LCM.RemoteFetch.CacheTTLSeconds = cmp.Or(t.LCM.RemoteFetch.CacheTTLSeconds, o.LCM.RemoteFetch.CacheTTLSeconds)
+			o.LCM.RemoteFetch.S3Endpoint = cmp.Or(t.LCM.RemoteFetch.S3Endpoint, o.LCM.RemoteFetch.S3Endpoint)
+			for host, headers := range t.LCM.RemoteFetch.Headers {
+				if o.LCM.RemoteFetch.Headers == nil {
+					o.LCM.RemoteFetch.Headers = make(map[string]map[string]string)
+				}
+				o.LCM.RemoteFetch.Headers[host] = headers
+			}
+		}
+		if t.LCM.ExploreCache != nil {
+			if o.LCM.ExploreCache == nil {
+				o.LCM.ExploreCache = &ExploreCacheOptions{}
+			}
+			o.LCM.ExploreCache.Backend = cmp.Or(t.LCM.ExploreCache.Backend, o.LCM.ExploreCache.Backend)
+			o.LCM.ExploreCache.MaxEntries = cmp.Or(t.LCM.ExploreCache.MaxEntries, o.LCM.ExploreCache.MaxEntries)
+		}
+		if t.LCM.SQLiteSampling != nil {
+			if o.LCM.SQLiteSampling == nil {
+				o.LCM.SQLiteSampling = &SQLiteSamplingOptions{}
+			}
+			o.LCM.SQLiteSampling.SampleRows = cmp.Or(t.LCM.SQLiteSampling.SampleRows, o.LCM.SQLiteSampling.SampleRows)
+			o.LCM.SQLiteSampling.MaxTables = cmp.Or(t.LCM.SQLiteSampling.MaxTables, o.LCM.SQLiteSampling.MaxTables)
+			o.LCM.SQLiteSampling.MaxCellLength = cmp.Or(t.LCM.SQLiteSampling.MaxCellLength, o.LCM.SQLiteSampling.MaxCellLength)
+			o.LCM.SQLiteSampling.SkipRowCounts = o.LCM.SQLiteSampling.SkipRowCounts || t.LCM.SQLiteSampling.SkipRowCounts
+		}
+		if t.LCM.ArchiveNesting != nil {
+			if o.LCM.ArchiveNesting == nil {
+				o.LCM.ArchiveNesting = &ArchiveNestingOptions{}
+			}
+			o.LCM.ArchiveNesting.MaxDepth = cmp.Or(t.LCM.ArchiveNesting.MaxDepth, o.LCM.ArchiveNesting.MaxDepth)
+			o.LCM.ArchiveNesting.MaxEntryBytes = cmp.Or(t.LCM.ArchiveNesting.MaxEntryBytes, o.LCM.ArchiveNesting.MaxEntryBytes)
+		}
+		if t.LCM.ExplorerOutputLimits != nil {
+			if o.LCM.ExplorerOutputLimits == nil {
+				o.LCM.ExplorerOutputLimits = &ExplorerOutputLimitsOptions{}
+			}
+			o.LCM.ExplorerOutputLimits.Parity = o.LCM.ExplorerOutputLimits.Parity.merge(t.LCM.ExplorerOutputLimits.Parity)
+			o.LCM.ExplorerOutputLimits.Enhancement = o.LCM.ExplorerOutputLimits.Enhancement.merge(t.LCM.ExplorerOutputLimits.Enhancement)
+		}
+		o.LCM.RejectUnsafeArchives = o.LCM.RejectUnsafeArchives || t.LCM.RejectUnsafeArchives
 	}
 	if t.RepoMap != nil {
 		if o.RepoMap == nil {
@@ -187,6 +256,11 @@ func (o Options) merge(t Options) Options {
 	o.DoomLoopIntervention = cmp.Or(t.DoomLoopIntervention, o.DoomLoopIntervention)
 	o.DisableNotifications = o.DisableNotifications || t.DisableNotifications
 	o.BetaTools = o.BetaTools || t.BetaTools
+	o.ReviewEdits = o.ReviewEdits || t.ReviewEdits
+	o.CompressSystemPrompt = o.CompressSystemPrompt || t.CompressSystemPrompt
+	o.Offline = o.Offline || t.Offline
+	o.PermissionPolicy = cmp.Or(t.PermissionPolicy, o.PermissionPolicy)
+	o.ContextAttribution = o.ContextAttribution || t.ContextAttribution
 	o.DisabledSkills = append(o.DisabledSkills, t.DisabledSkills...)
 
 	if t.Snapshot != nil {
@@ -195,6 +269,134 @@ func (o Options) merge(t Options) Options {
 		}
 		o.Snapshot.MaxPerSession = cmp.Or(t.Snapshot.MaxPerSession, o.Snapshot.MaxPerSession)
 	}
+	if t.Notifications != nil {
+		if o.Notifications == nil {
+			o.Notifications = &NotificationOptions{}
+		}
+		o.Notifications.Enabled = o.Notifications.Enabled || t.Notifications.Enabled
+		o.Notifications.MinTurnSeconds = cmp.Or(t.Notifications.MinTurnSeconds, o.Notifications.MinTurnSeconds)
+		o.Notifications.SkipPermissionPrompts = o.Notifications.SkipPermissionPrompts || t.Notifications.SkipPermissionPrompts
+	}
+	if t.OutputPane != nil {
+		if o.OutputPane == nil {
+			o.OutputPane = &OutputPaneOptions{}
+		}
+		o.OutputPane.Multiplexer = cmp.Or(t.OutputPane.Multiplexer, o.OutputPane.Multiplexer)
+		o.OutputPane.Pager = cmp.Or(t.OutputPane.Pager, o.OutputPane.Pager)
+		o.OutputPane.Vertical = o.OutputPane.Vertical || t.OutputPane.Vertical
+	}
+	if t.Translation != nil {
+		if o.Translation == nil {
+			o.Translation = &TranslationOptions{}
+		}
+		o.Translation.Language = cmp.Or(t.Translation.Language, o.Translation.Language)
+		o.Translation.Display = cmp.Or(t.Translation.Display, o.Translation.Display)
+		o.Translation.Auto = o.Translation.Auto || t.Translation.Auto
+	}
+	if t.Knowledge != nil {
+		if o.Knowledge == nil {
+			o.Knowledge = &KnowledgeOptions{}
+		}
+		o.Knowledge.MaxTokens = cmp.Or(t.Knowledge.MaxTokens, o.Knowledge.MaxTokens)
+		if t.Knowledge.Embeddings != nil {
+			o.Knowledge.Embeddings = t.Knowledge.Embeddings
+		}
+	}
+	if t.FeatureFlags != nil {
+		if o.FeatureFlags == nil {
+			o.FeatureFlags = &FeatureFlagOptions{}
+		}
+		o.FeatureFlags.Patterns = append(o.FeatureFlags.Patterns, t.FeatureFlags.Patterns...)
+		o.FeatureFlags.RepoMap = o.FeatureFlags.RepoMap || t.FeatureFlags.RepoMap
+	}
+	if t.AutoDowngrade != nil {
+		if o.AutoDowngrade == nil {
+			o.AutoDowngrade = &AutoDowngradeOptions{}
+		}
+		o.AutoDowngrade.Enabled = o.AutoDowngrade.Enabled || t.AutoDowngrade.Enabled
+		o.AutoDowngrade.MaxPromptChars = cmp.Or(t.AutoDowngrade.MaxPromptChars, o.AutoDowngrade.MaxPromptChars)
+	}
+	if t.CostEstimate != nil {
+		if o.CostEstimate == nil {
+			o.CostEstimate = &CostEstimateOptions{}
+		}
+		o.CostEstimate.ConfirmAbove = cmp.Or(t.CostEstimate.ConfirmAbove, o.CostEstimate.ConfirmAbove)
+		o.CostEstimate.OutputTokens = cmp.Or(t.CostEstimate.OutputTokens, o.CostEstimate.OutputTokens)
+	}
+	if t.Database != nil {
+		if o.Database == nil {
+			o.Database = &DatabaseOptions{}
+		}
+		o.Database.JournalMode = cmp.Or(t.Database.JournalMode, o.Database.JournalMode)
+		o.Database.BusyTimeoutMs = cmp.Or(t.Database.BusyTimeoutMs, o.Database.BusyTimeoutMs)
+		o.Database.CacheSizeKiB = cmp.Or(t.Database.CacheSizeKiB, o.Database.CacheSizeKiB)
+		o.Database.MmapSizeBytes = cmp.Or(t.Database.MmapSizeBytes, o.Database.MmapSizeBytes)
+		o.Database.ConnMaxIdleSeconds = cmp.Or(t.Database.ConnMaxIdleSeconds, o.Database.ConnMaxIdleSeconds)
+	}
+	if t.Startup != nil {
+		if o.Startup == nil {
+			o.Startup = &StartupOptions{}
+		}
+		o.Startup.LazyMCP = o.Startup.LazyMCP || t.Startup.LazyMCP
+		o.Startup.EagerMCP = sortedCompact(append(o.Startup.EagerMCP, t.Startup.EagerMCP...))
+		o.Startup.EagerLSP = sortedCompact(append(o.Startup.EagerLSP, t.Startup.EagerLSP...))
+		o.Startup.IdleShutdown = cmp.Or(t.Startup.IdleShutdown, o.Startup.IdleShutdown)
+	}
+	if t.Verification != nil {
+		if o.Verification == nil {
+			o.Verification = &VerificationOptions{}
+		}
+		o.Verification.Enabled = o.Verification.Enabled || t.Verification.Enabled
+		if len(t.Verification.Checks) > 0 {
+			o.Verification.Checks = slices.Clone(t.Verification.Checks)
+		}
+		o.Verification.MaxIterations = cmp.Or(t.Verification.MaxIterations, o.Verification.MaxIterations)
+		o.Verification.TimeoutSeconds = cmp.Or(t.Verification.TimeoutSeconds, o.Verification.TimeoutSeconds)
+	}
+	if t.Sampling != nil {
+		if o.Sampling == nil {
+			o.Sampling = &SamplingOptions{}
+		}
+		o.Sampling.Enabled = o.Sampling.Enabled || t.Sampling.Enabled
+		o.Sampling.Candidates = cmp.Or(t.Sampling.Candidates, o.Sampling.Candidates)
+		if len(t.Sampling.Temperatures) > 0 {
+			o.Sampling.Temperatures = slices.Clone(t.Sampling.Temperatures)
+		}
+		if len(t.Sampling.Models) > 0 {
+			o.Sampling.Models = slices.Clone(t.Sampling.Models)
+		}
+		o.Sampling.Criteria = cmp.Or(t.Sampling.Criteria, o.Sampling.Criteria)
+		o.Sampling.MinComplexity = cmp.Or(t.Sampling.MinComplexity, o.Sampling.MinComplexity)
+		o.Sampling.Keywords = sortedCompact(append(o.Sampling.Keywords, t.Sampling.Keywords...))
+	}
+	if t.Reasoning != nil {
+		if o.Reasoning == nil {
+			o.Reasoning = &ReasoningOptions{}
+		}
+		o.Reasoning.Display = cmp.Or(t.Reasoning.Display, o.Reasoning.Display)
+		o.Reasoning.StripFromTranscripts = o.Reasoning.StripFromTranscripts || t.Reasoning.StripFromTranscripts
+		if len(t.Reasoning.InlineTags) > 0 {
+			o.Reasoning.InlineTags = slices.Clone(t.Reasoning.InlineTags)
+		}
+	}
+	// A remote target is only meaningful as a whole, so a later one
+	// replaces an earlier one.
+	if t.Remote != nil {
+		r := *t.Remote
+		r.Args = slices.Clone(t.Remote.Args)
+		o.Remote = &r
+	}
+	o.DevEnv = cmp.Or(t.DevEnv, o.DevEnv)
+	if t.Voice != nil {
+		if o.Voice == nil {
+			o.Voice = &VoiceOptions{}
+		}
+		o.Voice.Enabled = o.Voice.Enabled || t.Voice.Enabled
+		o.Voice.RecordCommand = cmp.Or(t.Voice.RecordCommand, o.Voice.RecordCommand)
+		o.Voice.TranscribeCommand = cmp.Or(t.Voice.TranscribeCommand, o.Voice.TranscribeCommand)
+		o.Voice.Model = cmp.Or(t.Voice.Model, o.Voice.Model)
+		o.Voice.Language = cmp.Or(t.Voice.Language, o.Voice.Language)
+	}
 	return o
 }
 
@@ -275,6 +477,11 @@ func (c Config) merge(t Config) Config {
 		c.Hooks[event] = append(c.Hooks[event], hooks...)
 	}
 
+	// Templates: later definitions replace earlier ones by name.
+	if len(t.Templates) > 0 {
+		c.Templates = mergeMaps(c.Templates, t.Templates)
+	}
+
 	return c
 }
 
@@ -140,6 +140,7 @@ func TestConfigMerging(t *testing.T) {
 						MaxDepth: &maxDepth,
 						MaxItems: &maxItems,
 					},
+					EditorLinks: "vscode",
 				},
 			},
 		}, Config{
@@ -159,6 +160,7 @@ func TestConfigMerging(t *testing.T) {
 		require.True(t, c.Options.TUI.CompactMode)
 		require.Equal(t, "split", c.Options.TUI.DiffMode)
 		require.Equal(t, newMaxDepth, *c.Options.TUI.Completions.MaxDepth)
+		require.Equal(t, "vscode", c.Options.TUI.EditorLinks)
 	})
 
 	t.Run("options", func(t *testing.T) {
@@ -301,6 +303,45 @@ func TestConfigMerging(t *testing.T) {
 		require.Equal(t, 8, c.Tools.RepoMap.ParserPoolSize)
 	})
 
+	t.Run("repo_map_roots_replaced_as_a_whole", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Tools: Tools{
+				RepoMap: RepoMapOptions{Roots: []RepoMapRoot{{Path: "api"}, {Path: "web"}}},
+			},
+		}, Config{
+			Tools: Tools{
+				RepoMap: RepoMapOptions{ExcludeGlobs: []string{"*.log"}},
+			},
+		}, Config{
+			Tools: Tools{
+				RepoMap: RepoMapOptions{Roots: []RepoMapRoot{{Path: "../shared", Name: "shared", ExcludeGlobs: []string{"gen/**"}}}},
+			},
+		})
+
+		require.Equal(t, []RepoMapRoot{{Path: "../shared", Name: "shared", ExcludeGlobs: []string{"gen/**"}}}, c.Tools.RepoMap.Roots)
+	})
+
+	t.Run("repo_map_weights_merged_by_key", func(t *testing.T) {
+		first := RepoMapOptions{
+			LanguageWeights: map[string]float64{"go": 2, "python": 0.5},
+			GlobWeights:     map[string]float64{"**/*_test.go": 0.3},
+		}
+		c := exerciseMerge(t, Config{
+			Tools: Tools{RepoMap: first},
+		}, Config{
+			Tools: Tools{RepoMap: RepoMapOptions{MaxTokens: 1024}},
+		}, Config{
+			Tools: Tools{RepoMap: RepoMapOptions{
+				LanguageWeights: map[string]float64{"python": 1},
+				GlobWeights:     map[string]float64{"**/*.pb.go": 0},
+			}},
+		})
+
+		require.Equal(t, map[string]float64{"go": 2, "python": 1}, c.Tools.RepoMap.LanguageWeights)
+		require.Equal(t, map[string]float64{"**/*_test.go": 0.3, "**/*.pb.go": 0}, c.Tools.RepoMap.GlobWeights)
+		require.Equal(t, 0.5, first.LanguageWeights["python"], "earlier config is left untouched")
+	})
+
 	t.Run("repo_map_second_wins_nonzero", func(t *testing.T) {
 		c := exerciseMerge(t, Config{
 			Tools: Tools{
@@ -866,6 +907,25 @@ func TestConfigMerging(t *testing.T) {
 		require.True(t, c.Options.BetaTools)
 	})
 
+	t.Run("options_notifications_merge", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				Notifications: &NotificationOptions{Enabled: true, MinTurnSeconds: 10},
+				TUI:           &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				Notifications: &NotificationOptions{MinTurnSeconds: 60},
+				TUI:           &TUIOptions{},
+			},
+		})
+
+		require.NotNil(t, c)
+		require.True(t, c.Options.Notifications.Enabled)
+		require.Equal(t, time.Minute, c.Options.Notifications.MinTurnDuration())
+		require.Equal(t, DefaultMinTurnSeconds*time.Second, (*NotificationOptions)(nil).MinTurnDuration())
+	})
+
 	t.Run("provider_config_merge_preserves_fields", func(t *testing.T) {
 		// Tests that merging a later provider config with empty fields
 		// does not overwrite earlier non-empty fields.
@@ -998,6 +1058,306 @@ func TestConfigMerging(t *testing.T) {
 		require.Equal(t, "parity", c.Options.LCM.ExplorerOutputProfile)
 	})
 
+	t.Run("database_last_non_empty", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				Database: &DatabaseOptions{JournalMode: "wal", BusyTimeoutMs: 1000},
+				TUI:      &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				Database: &DatabaseOptions{BusyTimeoutMs: 60000, CacheSizeKiB: 32000},
+				TUI:      &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &DatabaseOptions{JournalMode: "wal", BusyTimeoutMs: 60000, CacheSizeKiB: 32000}, c.Options.Database)
+	})
+
+	t.Run("startup_lists_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				Startup: &StartupOptions{EagerMCP: []string{"github"}, EagerLSP: []string{"gopls"}, IdleShutdown: time.Minute},
+				TUI:     &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				Startup: &StartupOptions{LazyMCP: true, EagerMCP: []string{"docs", "github"}},
+				TUI:     &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &StartupOptions{
+			LazyMCP:      true,
+			EagerMCP:     []string{"docs", "github"},
+			EagerLSP:     []string{"gopls"},
+			IdleShutdown: time.Minute,
+		}, c.Options.Startup)
+	})
+
+	t.Run("verification_checks_replaced", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				Verification: &VerificationOptions{
+					Enabled:       true,
+					Checks:        []VerificationCheck{{Name: "build", Command: "go build ./..."}},
+					MaxIterations: 3,
+				},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				Verification: &VerificationOptions{
+					Checks: []VerificationCheck{{Name: "test", Command: "go test ./internal/..."}},
+				},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &VerificationOptions{
+			Enabled:       true,
+			Checks:        []VerificationCheck{{Name: "test", Command: "go test ./internal/..."}},
+			MaxIterations: 3,
+		}, c.Options.Verification)
+	})
+
+	t.Run("sampling_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				Sampling: &SamplingOptions{
+					Enabled:      true,
+					Temperatures: []float64{0.2, 1.0},
+					Keywords:     []string{"security"},
+				},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				Sampling: &SamplingOptions{
+					Candidates: 4,
+					Models:     []SelectedModel{{Provider: "openai", Model: "gpt-4o"}},
+					Keywords:   []string{"production", "security"},
+				},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &SamplingOptions{
+			Enabled:      true,
+			Candidates:   4,
+			Temperatures: []float64{0.2, 1.0},
+			Models:       []SelectedModel{{Provider: "openai", Model: "gpt-4o"}},
+			Keywords:     []string{"production", "security"},
+		}, c.Options.Sampling)
+		require.Equal(t, 4, c.Options.Sampling.CandidateCount())
+	})
+
+	t.Run("reasoning_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				Reasoning: &ReasoningOptions{
+					Display:    "expanded",
+					InlineTags: []string{"think"},
+				},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				Reasoning: &ReasoningOptions{
+					Display:              "hidden",
+					StripFromTranscripts: true,
+				},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &ReasoningOptions{
+			Display:              "hidden",
+			StripFromTranscripts: true,
+			InlineTags:           []string{"think"},
+		}, c.Options.Reasoning)
+		require.Equal(t, ReasoningDisplayHidden, c.Options.Reasoning.DisplayMode())
+		require.Equal(t, ReasoningDisplayCollapsed, (*ReasoningOptions)(nil).DisplayMode())
+		require.Equal(t, DefaultReasoningTags, (*ReasoningOptions)(nil).Tags())
+	})
+
+	t.Run("cost_estimate_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				CostEstimate: &CostEstimateOptions{ConfirmAbove: 0.5, OutputTokens: 2000},
+				TUI:          &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				CostEstimate: &CostEstimateOptions{ConfirmAbove: 1.25},
+				TUI:          &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &CostEstimateOptions{ConfirmAbove: 1.25, OutputTokens: 2000}, c.Options.CostEstimate)
+		require.Equal(t, 1.25, c.Options.CostEstimate.Threshold())
+		require.Equal(t, 2000, c.Options.CostEstimate.ProjectedOutputTokens())
+		require.Zero(t, (*CostEstimateOptions)(nil).Threshold())
+		require.Equal(t, DefaultCostEstimateOutputTokens, (*CostEstimateOptions)(nil).ProjectedOutputTokens())
+	})
+
+	t.Run("lcm_explore_cache_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ExploreCache: &ExploreCacheOptions{MaxEntries: 4096}},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ExploreCache: &ExploreCacheOptions{Backend: "sqlite"}},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &ExploreCacheOptions{Backend: "sqlite", MaxEntries: 4096}, c.Options.LCM.ExploreCache)
+	})
+
+	t.Run("lcm_sqlite_sampling_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				LCM: &LCMOptions{SQLiteSampling: &SQLiteSamplingOptions{SampleRows: 5, SkipRowCounts: true}},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				LCM: &LCMOptions{SQLiteSampling: &SQLiteSamplingOptions{MaxTables: 2}},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &SQLiteSamplingOptions{SampleRows: 5, MaxTables: 2, SkipRowCounts: true}, c.Options.LCM.SQLiteSampling)
+	})
+
+	t.Run("lcm_archive_nesting_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ArchiveNesting: &ArchiveNestingOptions{MaxDepth: 3, MaxEntryBytes: 1 << 20}},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ArchiveNesting: &ArchiveNestingOptions{MaxDepth: -1}},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &ArchiveNestingOptions{MaxDepth: -1, MaxEntryBytes: 1 << 20}, c.Options.LCM.ArchiveNesting)
+	})
+
+	t.Run("lcm_explorer_output_limits_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ExplorerOutputLimits: &ExplorerOutputLimitsOptions{
+					Enhancement: &OutputLimitsOptions{SectionItems: 50, SampleSize: 20},
+				}},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ExplorerOutputLimits: &ExplorerOutputLimitsOptions{
+					Parity:      &OutputLimitsOptions{TotalLines: 40},
+					Enhancement: &OutputLimitsOptions{SectionItems: 100},
+				}},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &ExplorerOutputLimitsOptions{
+			Parity:      &OutputLimitsOptions{TotalLines: 40},
+			Enhancement: &OutputLimitsOptions{SectionItems: 100, SampleSize: 20},
+		}, c.Options.LCM.ExplorerOutputLimits)
+	})
+
+	t.Run("lcm_reject_unsafe_archives_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				LCM: &LCMOptions{RejectUnsafeArchives: true},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				LCM: &LCMOptions{},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.True(t, c.Options.LCM.RejectUnsafeArchives)
+	})
+
+	t.Run("feature_flags_merged", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				FeatureFlags: &FeatureFlagOptions{Patterns: []string{`flags\.On\("([^"]+)"\)`}, RepoMap: true},
+				TUI:          &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				FeatureFlags: &FeatureFlagOptions{Patterns: []string{`isEnabled\((\w+)\)`}},
+				TUI:          &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, []string{`flags\.On\("([^"]+)"\)`, `isEnabled\((\w+)\)`}, c.Options.FeatureFlags.CustomPatterns())
+		require.True(t, c.Options.FeatureFlags.AnnotateRepoMap())
+	})
+
+	t.Run("remote_replaced_as_a_whole", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				Remote: &RemoteOptions{Type: RemoteTypeSSH, Host: "devbox", Path: "/srv/app", Sync: true},
+				TUI:    &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				Remote: &RemoteOptions{Type: RemoteTypeDocker, Container: "app-dev"},
+				TUI:    &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, &RemoteOptions{Type: RemoteTypeDocker, Container: "app-dev"}, c.Options.Remote)
+	})
+
+	t.Run("dev_env_overridden", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{DevEnv: DevEnvAuto, TUI: &TUIOptions{}},
+		}, Config{
+			Options: &Options{TUI: &TUIOptions{}},
+		}, Config{
+			Options: &Options{DevEnv: DevEnvOff, TUI: &TUIOptions{}},
+		})
+
+		require.Equal(t, DevEnvOff, c.Options.DevEnv)
+	})
+
+	t.Run("lcm_explorer_path_profiles_merged_by_path", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ExplorerPathProfiles: map[string]string{
+					"lcm.tool_output.create":   "enhancement",
+					"volt.prompt.file.persist": "enhancement",
+				}},
+				TUI: &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				LCM: &LCMOptions{ExplorerPathProfiles: map[string]string{
+					"lcm.tool_output.create": "parity",
+				}},
+				TUI: &TUIOptions{},
+			},
+		})
+
+		require.Equal(t, map[string]string{
+			"lcm.tool_output.create":   "parity",
+			"volt.prompt.file.persist": "enhancement",
+		}, c.Options.LCM.ExplorerPathProfiles)
+	})
+
 	t.Run("lcm_disable_large_tool_output_true_if_any", func(t *testing.T) {
 		c := exerciseMerge(t, Config{
 			Options: &Options{
@@ -1175,6 +1535,24 @@ func TestConfigMerging(t *testing.T) {
 		require.Equal(t, 6, c.Options.RepoMap.ParserPoolSize)
 	})
 
+	t.Run("repo_map_watch_or_latch", func(t *testing.T) {
+		c := exerciseMerge(t, Config{
+			Options: &Options{
+				RepoMap: &RepoMapOptions{Watch: true},
+				TUI:     &TUIOptions{},
+			},
+		}, Config{
+			Options: &Options{
+				RepoMap: &RepoMapOptions{},
+				TUI:     &TUIOptions{},
+			},
+		})
+
+		require.NotNil(t, c)
+		require.NotNil(t, c.Options.RepoMap)
+		require.True(t, c.Options.RepoMap.Watch)
+	})
+
 	t.Run("repo_map_disabled_or_latch", func(t *testing.T) {
 		c := exerciseMerge(t, Config{
 			Options: &Options{
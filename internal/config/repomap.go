@@ -1,6 +1,10 @@
 package config
 
-import "cmp"
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
 
 // RepoMapOptions configures repository map generation.
 type RepoMapOptions struct {
@@ -18,6 +22,32 @@ type RepoMapOptions struct {
 	// ParserPoolSize sets tree-sitter parser pool capacity.
 	// Zero uses the runtime default.
 	ParserPoolSize int `json:"parser_pool_size,omitempty" jsonschema:"description=Tree-sitter parser pool size (0 = runtime default)"`
+	// Watch re-indexes files as they change on disk, using filesystem
+	// notifications, instead of waiting for a forced refresh.
+	Watch bool `json:"watch,omitempty" jsonschema:"description=Watch the repository and re-index changed files incrementally"`
+	// Roots turn the working directory into a multi-root workspace: the
+	// files of every root are ranked together and listed under the root's
+	// name. When empty the working directory is the only root.
+	Roots []RepoMapRoot `json:"roots,omitempty" jsonschema:"description=Project roots of a multi-root workspace ranked in one repo map"`
+	// LanguageWeights multiply the rank of files by language, keyed by
+	// tree-sitter language name: {"go": 2} lifts Go definitions.
+	LanguageWeights map[string]float64 `json:"language_weights,omitempty" jsonschema:"description=Rank multipliers by language name (above 1 boosts; below 1 demotes; 0 leaves the files' definitions unranked)"`
+	// GlobWeights multiply the rank of files matching doublestar globs:
+	// {"**/*_test.go": 0.3} demotes tests. Every matching glob applies,
+	// on top of the language weight.
+	GlobWeights map[string]float64 `json:"glob_weights,omitempty" jsonschema:"description=Rank multipliers by doublestar glob over repo map paths; every matching glob applies"`
+}
+
+// RepoMapRoot is one project root of a multi-root repo map workspace.
+type RepoMapRoot struct {
+	// Path is the root's directory, absolute or relative to the working
+	// directory.
+	Path string `json:"path" jsonschema:"description=Root directory absolute or relative to the working directory,example=services/api"`
+	// Name prefixes the root's paths in the map; the base name of Path
+	// when empty.
+	Name string `json:"name,omitempty" jsonschema:"description=Prefix of the root's paths in the map (defaults to the directory name)"`
+	// ExcludeGlobs are excluded from this root only, relative to Path.
+	ExcludeGlobs []string `json:"exclude_globs,omitempty" jsonschema:"description=Glob patterns relative to the root excluded from this root only"`
 }
 
 func (o RepoMapOptions) merge(t RepoMapOptions) RepoMapOptions {
@@ -29,9 +59,28 @@ func (o RepoMapOptions) merge(t RepoMapOptions) RepoMapOptions {
 		o.MapMulNoFiles = t.MapMulNoFiles
 	}
 	o.ParserPoolSize = cmp.Or(t.ParserPoolSize, o.ParserPoolSize)
+	o.Watch = o.Watch || t.Watch
+	// A workspace is defined as a whole; a later config replaces it.
+	if len(t.Roots) > 0 {
+		o.Roots = slices.Clone(t.Roots)
+	}
+	o.LanguageWeights = mergeWeights(o.LanguageWeights, t.LanguageWeights)
+	o.GlobWeights = mergeWeights(o.GlobWeights, t.GlobWeights)
 	return o
 }
 
+// mergeWeights returns the weights of o overridden key by key by those of
+// t, without modifying either.
+func mergeWeights(o, t map[string]float64) map[string]float64 {
+	if len(t) == 0 {
+		return o
+	}
+	merged := make(map[string]float64, len(o)+len(t))
+	maps.Copy(merged, o)
+	maps.Copy(merged, t)
+	return merged
+}
+
 // DefaultRepoMapMaxTokens computes the dynamic token budget based on model context
 // window size: min(max(contextWindow/8, 1024), 4096).
 func DefaultRepoMapMaxTokens(modelContextWindow int) int {
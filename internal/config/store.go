@@ -32,6 +32,9 @@ type fileSnapshot struct {
 // the lifetime of the process (or workspace).
 type RuntimeOverrides struct {
 	SkipPermissionRequests bool
+	// Bare is set when the working directory is outside any repository;
+	// see [ConfigStore.ApplyBareMode].
+	Bare bool // XRUSH: quick use outside a repository
 }
 
 // ConfigStore is the single entry point for all config access. It owns the
@@ -108,6 +111,12 @@ func (s *ConfigStore) configPath(scope Scope) (string, error) {
 	}
 }
 
+// ConfigPath returns the config file path for the given scope. It
+// returns ErrNoWorkspaceConfig when the workspace has no config path.
+func (s *ConfigStore) ConfigPath(scope Scope) (string, error) {
+	return s.configPath(scope)
+}
+
 // HasConfigField checks whether a key exists in the config file for the given
 // scope.
 func (s *ConfigStore) HasConfigField(scope Scope, key string) bool {
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// templatesDirName is the directory under the data directory (usually
+// .crush) that holds one JSON file per session template.
+const templatesDirName = "templates"
+
+// maxTemplateWorkingSetFiles caps how many files a template's working set
+// may expand to, so a careless "**/*" glob cannot flood the first prompt.
+const maxTemplateWorkingSetFiles = 200
+
+// SessionTemplate pre-seeds a new session for a recurring workflow.
+type SessionTemplate struct {
+	// Description is a short human-readable summary shown in listings.
+	Description string `json:"description,omitempty" jsonschema:"description=Short description shown when listing templates"`
+	// Notes are pinned notes kept in the context of the session, even
+	// once it is summarized.
+	Notes []string `json:"notes,omitempty" jsonschema:"description=Pinned notes injected at the start of the session"`
+	// Files are paths or doublestar globs, relative to the working
+	// directory, that make up the session's initial working set.
+	Files []string `json:"files,omitempty" jsonschema:"description=Working-set files or globs relative to the working directory,example=internal/server/**/*.go"`
+	// Model is the large model to use. Accepts 'model' or 'provider/model'.
+	Model string `json:"model,omitempty" jsonschema:"description=Large model to use (model or provider/model)"`
+	// SmallModel is the small model to use. Accepts 'model' or
+	// 'provider/model'.
+	SmallModel string `json:"small_model,omitempty" jsonschema:"description=Small model to use (model or provider/model)"`
+	// Tools is an allow-list of built-in tools. When empty every tool
+	// that is not otherwise disabled stays enabled.
+	Tools []string `json:"tools,omitempty" jsonschema:"description=Allow list of built-in tools enabled for the session,example=view,example=edit"`
+	// Prompt is the default first prompt used when none is given.
+	Prompt string `json:"prompt,omitempty" jsonschema:"description=Default first prompt when none is provided"`
+}
+
+// TemplatesDir returns the directory holding per-project template files.
+func TemplatesDir(dataDir string) string {
+	return filepath.Join(dataDir, templatesDirName)
+}
+
+// LoadTemplates returns the session templates defined in the config
+// merged with the ones found in <dataDir>/templates/*.json. Each file
+// defines a single template named after the file. Templates defined in
+// crush.json take precedence over files with the same name.
+func LoadTemplates(cfg *Config, dataDir string) (map[string]SessionTemplate, error) {
+	out := make(map[string]SessionTemplate)
+	if dataDir != "" {
+		entries, err := os.ReadDir(TemplatesDir(dataDir))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading templates directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			path := filepath.Join(TemplatesDir(dataDir), entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("reading template %s: %w", path, err)
+			}
+			var tmpl SessionTemplate
+			if err := json.Unmarshal(data, &tmpl); err != nil {
+				return nil, fmt.Errorf("invalid template %s: %w", path, err)
+			}
+			out[strings.TrimSuffix(entry.Name(), ".json")] = tmpl
+		}
+	}
+	if cfg != nil {
+		maps.Copy(out, cfg.Templates)
+	}
+	return out, nil
+}
+
+// TemplateNames returns the sorted names of the given templates.
+func TemplateNames(templates map[string]SessionTemplate) []string {
+	return slices.Sorted(maps.Keys(templates))
+}
+
+// WorkingSet expands the template's file globs against workingDir and
+// returns the matching regular files as sorted, slash-separated paths
+// relative to workingDir.
+func (t SessionTemplate) WorkingSet(workingDir string) ([]string, error) {
+	seen := make(map[string]struct{})
+	fsys := os.DirFS(workingDir)
+	for _, pattern := range t.Files {
+		pattern = filepath.ToSlash(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		matches, err := doublestar.Glob(fsys, pattern, doublestar.WithFilesOnly())
+		if err != nil {
+			return nil, fmt.Errorf("invalid working-set pattern %q: %w", pattern, err)
+		}
+		for _, m := range matches {
+			seen[m] = struct{}{}
+			if len(seen) > maxTemplateWorkingSetFiles {
+				return nil, fmt.Errorf("working set exceeds %d files; narrow the template globs", maxTemplateWorkingSetFiles)
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(seen)), nil
+}
+
+// Preamble renders the template's pinned notes and working set as the
+// block stored as the first message of the session. It returns an empty
+// string when the template has neither.
+func (t SessionTemplate) Preamble(workingDir string) (string, error) {
+	files, err := t.WorkingSet(workingDir)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if len(t.Notes) > 0 {
+		b.WriteString("<pinned_notes>\n")
+		for _, note := range t.Notes {
+			fmt.Fprintf(&b, "- %s\n", strings.TrimSpace(note))
+		}
+		b.WriteString("</pinned_notes>\n")
+	}
+	if len(files) > 0 {
+		b.WriteString("<working_set>\n")
+		for _, f := range files {
+			b.WriteString(f)
+			b.WriteByte('\n')
+		}
+		b.WriteString("</working_set>\n")
+	}
+	return b.String(), nil
+}
+
+// IsPreamble reports whether text is a template preamble, the pinned
+// first message of a session started from a template.
+func IsPreamble(text string) bool {
+	return strings.HasPrefix(text, "<pinned_notes>\n") || strings.HasPrefix(text, "<working_set>\n")
+}
+
+// ApplyTools restricts the built-in tools to the template's allow-list by
+// extending Options.DisabledTools, then rebuilds the agent definitions.
+// It is a no-op when the template does not restrict tools. The agent's
+// tools are built from the config, so it must run before the app is
+// created.
+func (t SessionTemplate) ApplyTools(c *Config) {
+	if len(t.Tools) == 0 || c == nil {
+		return
+	}
+	if c.Options == nil {
+		c.Options = &Options{}
+	}
+	for _, name := range allToolNames() {
+		if !slices.Contains(t.Tools, name) && !slices.Contains(c.Options.DisabledTools, name) {
+			c.Options.DisabledTools = append(c.Options.DisabledTools, name)
+		}
+	}
+	c.SetupAgents()
+}
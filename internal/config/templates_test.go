@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	dir := TemplatesDir(dataDir)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "backend-bugfix.json"),
+		[]byte(`{"description":"from file","notes":["run go test"]}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs.json"),
+		[]byte(`{"description":"docs"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o644))
+
+	cfg := &Config{Templates: map[string]SessionTemplate{
+		"backend-bugfix": {Description: "from config"},
+	}}
+
+	templates, err := LoadTemplates(cfg, dataDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend-bugfix", "docs"}, TemplateNames(templates))
+	require.Equal(t, "from config", templates["backend-bugfix"].Description)
+	require.Equal(t, "docs", templates["docs"].Description)
+}
+
+func TestLoadTemplatesInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	dir := TemplatesDir(dataDir)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{`), 0o644))
+
+	_, err := LoadTemplates(nil, dataDir)
+	require.Error(t, err)
+}
+
+func TestSessionTemplatePreamble(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workingDir, "internal", "server"), 0o755))
+	for _, name := range []string{"internal/server/a.go", "internal/server/b.go", "main.go"} {
+		require.NoError(t, os.WriteFile(filepath.Join(workingDir, name), nil, 0o644))
+	}
+
+	tmpl := SessionTemplate{
+		Notes: []string{"Reproduce before fixing"},
+		Files: []string{"internal/server/*.go", "main.go", "internal/server/a.go"},
+	}
+	got, err := tmpl.Preamble(workingDir)
+	require.NoError(t, err)
+	require.Equal(t, "<pinned_notes>\n- Reproduce before fixing\n</pinned_notes>\n"+
+		"<working_set>\ninternal/server/a.go\ninternal/server/b.go\nmain.go\n</working_set>\n", got)
+	require.True(t, IsPreamble(got))
+
+	files, err := SessionTemplate{Files: []string{"main.go"}}.Preamble(workingDir)
+	require.NoError(t, err)
+	require.True(t, IsPreamble(files))
+	require.False(t, IsPreamble("Fix <pinned_notes>\n"))
+
+	empty, err := SessionTemplate{}.Preamble(workingDir)
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
+
+func TestSessionTemplateApplyTools(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{Options: &Options{}}
+	SessionTemplate{Tools: []string{"view", "grep"}}.ApplyTools(cfg)
+
+	require.ElementsMatch(t, []string{"view", "grep"}, cfg.Agents[AgentCoder].AllowedTools)
+	require.NotContains(t, cfg.Options.DisabledTools, "view")
+	require.Contains(t, cfg.Options.DisabledTools, "bash")
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"slices"
+	"time"
+)
+
 // RoutingTier defines a single tier in the multi-tier model router. Each tier
 // specifies a token threshold and the model type to use for prompts at or
 // below that threshold. Tiers are sorted ascending by UpToTokens before use.
@@ -42,6 +47,336 @@ type ValidationOptions struct {
 	SeverityFilter     string `json:"severity_filter,omitempty" jsonschema:"description=Minimum diagnostic severity to report: error, warning (default), info, or hint,enum=error,enum=warning,enum=info,enum=hint"`
 }
 
+// VerificationOptions configures the self-verification pass. After a turn
+// that edited files, the checks run in order; when one fails, its output is
+// sent back to the agent as a follow-up prompt, up to MaxIterations times.
+type VerificationOptions struct {
+	Enabled        bool                `json:"enabled,omitempty" jsonschema:"description=Run the verification checks before a turn that edited files completes,default=false"`
+	Checks         []VerificationCheck `json:"checks,omitempty" jsonschema:"description=Commands run in order from the working directory; a non-zero exit fails the check"`
+	MaxIterations  int                 `json:"max_iterations,omitempty" jsonschema:"description=Follow-up prompts sent for failing checks before giving up,default=2"`
+	TimeoutSeconds int                 `json:"timeout_seconds,omitempty" jsonschema:"description=Timeout of each check in seconds,default=300"`
+}
+
+// VerificationCheck is a named shell command run by the verification pass.
+type VerificationCheck struct {
+	Name    string `json:"name" jsonschema:"description=Short name shown in the transcript,example=test"`
+	Command string `json:"command" jsonschema:"description=Shell command to run,example=go test ./..."`
+}
+
+// Defaults of the sampling options.
+const (
+	DefaultSamplingCandidates    = 3
+	DefaultSamplingMinComplexity = "complex"
+)
+
+// DefaultSamplingTemperatures spread the extra candidates around the
+// turn's own temperature.
+var DefaultSamplingTemperatures = []float64{0.3, 0.9}
+
+// SamplingOptions configures multi-candidate sampling. On high-stakes turns
+// the final answer is drawn again, at other temperatures or on other
+// models, and a judge (the small model) ranks the candidates against the
+// user's request and Criteria. The best one becomes the reply; the others
+// stay on the message. Tool calls of the turn run once; only the final
+// answer is sampled.
+type SamplingOptions struct {
+	Enabled       bool            `json:"enabled,omitempty" jsonschema:"description=Sample several final answers on high-stakes turns,default=false"`
+	Candidates    int             `json:"candidates,omitempty" jsonschema:"description=Candidates per sampled turn, the turn's own answer included,default=3,minimum=2,maximum=8"`
+	Temperatures  []float64       `json:"temperatures,omitempty" jsonschema:"description=Temperatures of the extra candidates, used in turn,example=0.3,example=0.9"`
+	Models        []SelectedModel `json:"models,omitempty" jsonschema:"description=Models of the extra candidates, in order; the rest use the turn's model"`
+	Criteria      string          `json:"criteria,omitempty" jsonschema:"description=What the judge should favor beyond answering the request,example=correct and minimal changes"`
+	MinComplexity string          `json:"min_complexity,omitempty" jsonschema:"description=Lowest session complexity sampled,enum=simple,enum=medium,enum=complex,default=complex"`
+	Keywords      []string        `json:"keywords,omitempty" jsonschema:"description=Prompts containing one of these words are always sampled,example=production,example=security"`
+}
+
+// CandidateCount returns the number of candidates per sampled turn.
+func (s *SamplingOptions) CandidateCount() int {
+	if s == nil || s.Candidates <= 0 {
+		return DefaultSamplingCandidates
+	}
+	return min(max(s.Candidates, 2), 8)
+}
+
+// Reasoning display modes.
+const (
+	ReasoningDisplayCollapsed = "collapsed"
+	ReasoningDisplayExpanded  = "expanded"
+	ReasoningDisplayHidden    = "hidden"
+)
+
+// DefaultReasoningTags are the tags models without a separate reasoning
+// channel wrap their reasoning in.
+var DefaultReasoningTags = []string{"think", "thinking"}
+
+// ReasoningOptions controls reasoning traces. Providers that stream
+// reasoning separately are captured as is; for OpenAI-compatible providers
+// a reply that opens with one of InlineTags has the tagged block moved
+// into the reasoning part.
+type ReasoningOptions struct {
+	Display              string   `json:"display,omitempty" jsonschema:"description=How thinking blocks start out in the chat,enum=collapsed,enum=expanded,enum=hidden,default=collapsed"`
+	StripFromTranscripts bool     `json:"strip_from_transcripts,omitempty" jsonschema:"description=Drop reasoning text from stored messages once the turn ends; token counts and durations are kept,default=false"`
+	InlineTags           []string `json:"inline_tags,omitempty" jsonschema:"description=Tags that wrap inline reasoning in replies of OpenAI-compatible providers,example=think,example=reasoning"`
+}
+
+// DisplayMode returns the configured display mode, collapsed by default.
+func (r *ReasoningOptions) DisplayMode() string {
+	if r == nil {
+		return ReasoningDisplayCollapsed
+	}
+	switch r.Display {
+	case ReasoningDisplayExpanded, ReasoningDisplayHidden:
+		return r.Display
+	default:
+		return ReasoningDisplayCollapsed
+	}
+}
+
+// Tags returns the inline reasoning tags, DefaultReasoningTags unless
+// configured.
+func (r *ReasoningOptions) Tags() []string {
+	if r == nil || len(r.InlineTags) == 0 {
+		return DefaultReasoningTags
+	}
+	return r.InlineTags
+}
+
+// VoiceOptions configures push-to-talk voice input. Audio is recorded and
+// transcribed by external commands; {output}, {input}, {model} and
+// {language} placeholders in the commands are substituted at run time.
+type VoiceOptions struct {
+	Enabled           bool   `json:"enabled,omitempty" jsonschema:"description=Enable push-to-talk voice input in the prompt editor,default=false"`
+	RecordCommand     string `json:"record_command,omitempty" jsonschema:"description=Command that records the microphone to {output} until interrupted (defaults to sox or arecord),example=sox -q -d -r 16000 -c 1 -b 16 {output}"`
+	TranscribeCommand string `json:"transcribe_command,omitempty" jsonschema:"description=Command that prints the transcript of {input} to stdout (defaults to whisper.cpp's whisper-cli),example=whisper-cli -m {model} -f {input} -nt -np"`
+	Model             string `json:"model,omitempty" jsonschema:"description=Path to the whisper.cpp ggml model used by the default transcribe command"`
+	Language          string `json:"language,omitempty" jsonschema:"description=Spoken language passed to the transcriber,default=auto,example=en"`
+}
+
+// Remote target types.
+const (
+	RemoteTypeSSH          = "ssh"
+	RemoteTypeDocker       = "docker"
+	RemoteTypeDevcontainer = "devcontainer"
+)
+
+// RemoteOptions points the bash tool and the verification checks at a
+// remote host over SSH or at a container through docker exec. Path is the
+// project root on the target; the local working directory maps onto it.
+// With Sync, files written by the edit tools are copied to the target;
+// otherwise both sides are assumed to see the same files, as with a bind
+// mount or a network file system.
+//
+// The devcontainer type starts a container for the project itself, from
+// its .devcontainer configuration or Image, with the working directory
+// mounted at Path.
+type RemoteOptions struct {
+	Type      string   `json:"type" jsonschema:"required,description=How commands reach the target,enum=ssh,enum=docker,enum=devcontainer"`
+	Host      string   `json:"host,omitempty" jsonschema:"description=SSH destination for the ssh type,example=dev@devbox.local,example=devbox"`
+	Container string   `json:"container,omitempty" jsonschema:"description=Container name or ID for the docker type; name of the started container for the devcontainer type,example=app-dev"`
+	Image     string   `json:"image,omitempty" jsonschema:"description=Image for the devcontainer type (overrides the project's devcontainer.json),example=golang:1.25"`
+	Path      string   `json:"path,omitempty" jsonschema:"description=Project root on the target (defaults to the local working directory; for devcontainers the workspace folder),example=/srv/app"`
+	Sync      bool     `json:"sync,omitempty" jsonschema:"description=Copy files written by the edit and write tools to the target,default=false"`
+	Args      []string `json:"args,omitempty" jsonschema:"description=Extra arguments passed to ssh or docker exec before the destination,example=-p,example=2222"`
+}
+
+// Development environment modes for Options.DevEnv.
+const (
+	DevEnvAuto     = "auto"
+	DevEnvDirenv   = "direnv"
+	DevEnvFlake    = "flake"
+	DevEnvNixShell = "nix-shell"
+	DevEnvOff      = "off"
+)
+
+// NotificationOptions controls when desktop notifications are sent while
+// the terminal is unfocused. They are off by default; the delivery backend
+// is chosen by notification_style.
+type NotificationOptions struct {
+	Enabled               bool `json:"enabled,omitempty" jsonschema:"description=Send desktop notifications for long-running turns and permission prompts while the terminal is unfocused,default=false"`
+	MinTurnSeconds        int  `json:"min_turn_seconds,omitempty" jsonschema:"description=Only notify about finished turns that ran at least this many seconds,default=30"`
+	SkipPermissionPrompts bool `json:"skip_permission_prompts,omitempty" jsonschema:"description=Do not notify when a permission prompt is waiting,default=false"`
+}
+
+// DefaultMinTurnSeconds is the default minimum turn duration that triggers
+// a turn finished notification.
+const DefaultMinTurnSeconds = 30
+
+// MinTurnDuration returns the minimum duration a turn must run before its
+// completion is notified.
+func (n *NotificationOptions) MinTurnDuration() time.Duration {
+	if n == nil || n.MinTurnSeconds <= 0 {
+		return DefaultMinTurnSeconds * time.Second
+	}
+	return time.Duration(n.MinTurnSeconds) * time.Second
+}
+
+// OutputPaneOptions controls opening chat items in a separate tmux pane or
+// iTerm2 split.
+type OutputPaneOptions struct {
+	Multiplexer string `json:"multiplexer,omitempty" jsonschema:"description=Multiplexer to open output panes in; auto detects tmux or iTerm2,enum=auto,enum=tmux,enum=iterm2,enum=disabled,default=auto"`
+	Pager       string `json:"pager,omitempty" jsonschema:"description=Command used to display pane content,default=less -R,example=bat --paging=always"`
+	Vertical    bool   `json:"vertical,omitempty" jsonschema:"description=Open panes below the chat instead of beside it,default=false"`
+}
+
+// Translation display modes.
+const (
+	TranslationDisplayInline     = "inline"
+	TranslationDisplaySideBySide = "side_by_side"
+)
+
+// TranslationOptions controls translating assistant messages with the
+// small model. Code blocks are never translated.
+type TranslationOptions struct {
+	Language string `json:"language,omitempty" jsonschema:"description=Language to translate assistant messages into,example=Spanish,example=ja"`
+	Display  string `json:"display,omitempty" jsonschema:"description=Show the translation below the original or side by side with it,enum=inline,enum=side_by_side,default=inline"`
+	Auto     bool   `json:"auto,omitempty" jsonschema:"description=Translate every finished assistant reply automatically,default=false"`
+}
+
+// SideBySide reports whether translations are shown next to the original.
+func (t *TranslationOptions) SideBySide() bool {
+	return t != nil && t.Display == TranslationDisplaySideBySide
+}
+
+// KnowledgeOptions configures the knowledge_lookup tool. Markdown files in
+// the knowledge/ folder of the data directory are searched by keyword and,
+// when embeddings are configured, by semantic similarity.
+type KnowledgeOptions struct {
+	MaxTokens  int                        `json:"max_tokens,omitempty" jsonschema:"description=Token budget for a single knowledge_lookup result,default=4000"`
+	Embeddings *KnowledgeEmbeddingOptions `json:"embeddings,omitempty" jsonschema:"description=Embed knowledge sections for semantic retrieval"`
+}
+
+// KnowledgeEmbeddingOptions selects the embedding model used for semantic
+// knowledge retrieval. The provider must expose an OpenAI-compatible
+// /embeddings endpoint.
+type KnowledgeEmbeddingOptions struct {
+	Provider string `json:"provider,omitempty" jsonschema:"description=ID of a configured provider with an OpenAI-compatible embeddings endpoint,example=openai"`
+	Model    string `json:"model,omitempty" jsonschema:"description=Embedding model ID,example=text-embedding-3-small"`
+}
+
+// FeatureFlagOptions configures the feature flag index. Checks made
+// through common flag SDKs are recognized out of the box; patterns add
+// in-house helpers.
+type FeatureFlagOptions struct {
+	Patterns []string `json:"patterns,omitempty" jsonschema:"description=Regular expressions matching in-house flag checks; the first capture group is the flag name"`
+	RepoMap  bool     `json:"repo_map,omitempty" jsonschema:"description=List the flags and the files checking them at the top of the repo map,default=false"`
+}
+
+// CustomPatterns returns the configured flag check patterns.
+func (f *FeatureFlagOptions) CustomPatterns() []string {
+	if f == nil {
+		return nil
+	}
+	return f.Patterns
+}
+
+// AnnotateRepoMap reports whether the repo map lists the feature flags.
+func (f *FeatureFlagOptions) AnnotateRepoMap() bool {
+	return f != nil && f.RepoMap
+}
+
+// DefaultAutoDowngradeMaxPromptChars is the longest prompt considered for
+// an automatic downgrade when no limit is configured.
+const DefaultAutoDowngradeMaxPromptChars = 280
+
+// AutoDowngradeOptions configures routing trivial turns to the small model.
+// Turns with attachments, code or file references, or requests that imply
+// tool use are never downgraded. The setting can be overridden per session.
+type AutoDowngradeOptions struct {
+	Enabled        bool `json:"enabled,omitempty" jsonschema:"description=Route short prompts, plain questions and summary follow-ups to the small model,default=false"`
+	MaxPromptChars int  `json:"max_prompt_chars,omitempty" jsonschema:"description=Longest prompt that may be downgraded,default=280"`
+}
+
+// PromptLimit returns the longest prompt that may be downgraded.
+func (a *AutoDowngradeOptions) PromptLimit() int {
+	if a == nil || a.MaxPromptChars <= 0 {
+		return DefaultAutoDowngradeMaxPromptChars
+	}
+	return a.MaxPromptChars
+}
+
+// DefaultCostEstimateOutputTokens is the output projected for a turn
+// when the session has no previous response to go by.
+const DefaultCostEstimateOutputTokens = 1000
+
+// CostEstimateOptions configures the dry-run estimate of a turn: the prompt
+// tokens by component and the projected cost on the model the turn would
+// use. The estimate is always available from the command palette;
+// ConfirmAbove also checks every prompt before it is sent.
+type CostEstimateOptions struct {
+	ConfirmAbove float64 `json:"confirm_above,omitempty" jsonschema:"description=Ask for confirmation before sending a prompt whose projected cost in USD exceeds this; 0 never asks,example=0.5"`
+	OutputTokens int     `json:"output_tokens,omitempty" jsonschema:"description=Output tokens projected for a turn when the session has no previous response,default=1000"`
+}
+
+// ProjectedOutputTokens returns the output projected for a session with
+// no previous response.
+func (c *CostEstimateOptions) ProjectedOutputTokens() int {
+	if c == nil || c.OutputTokens <= 0 {
+		return DefaultCostEstimateOutputTokens
+	}
+	return c.OutputTokens
+}
+
+// Threshold returns the projected cost above which a prompt needs
+// confirmation, or 0 when prompts are sent without one.
+func (c *CostEstimateOptions) Threshold() float64 {
+	if c == nil || c.ConfirmAbove <= 0 {
+		return 0
+	}
+	return c.ConfirmAbove
+}
+
+// DatabaseOptions tunes the SQLite pragmas and connection pool of the
+// data directory database. Unset fields keep the built-in defaults: WAL
+// journal, a 30 second busy timeout, an 8 MB page cache and no memory map.
+// The pool always holds a single connection: several have left the WAL and
+// the database header out of sync.
+type DatabaseOptions struct {
+	JournalMode        string `json:"journal_mode,omitempty" jsonschema:"description=SQLite journal mode,enum=wal,enum=delete,enum=truncate,enum=persist,enum=memory,enum=off,default=wal"`
+	BusyTimeoutMs      int    `json:"busy_timeout_ms,omitempty" jsonschema:"description=Milliseconds a statement waits on a locked database before failing with SQLITE_BUSY,default=30000"`
+	CacheSizeKiB       int    `json:"cache_size_kib,omitempty" jsonschema:"description=SQLite page cache size in KiB,default=8000"`
+	MmapSizeBytes      int64  `json:"mmap_size_bytes,omitempty" jsonschema:"description=Bytes of the database file to memory map; 0 disables memory mapping,default=0"`
+	ConnMaxIdleSeconds int    `json:"conn_max_idle_seconds,omitempty" jsonschema:"description=Seconds an idle pooled connection is kept open; 0 keeps it indefinitely,default=0"`
+}
+
+// StartupOptions controls when LSP and MCP servers start and stop. By
+// default MCP servers connect at launch and LSP servers start on the first
+// file they handle.
+type StartupOptions struct {
+	// LazyMCP defers connecting MCP servers to the first agent turn, except
+	// those listed in EagerMCP.
+	LazyMCP  bool     `json:"lazy_mcp,omitempty" jsonschema:"description=Connect MCP servers on the first agent turn instead of at launch,default=false"`
+	EagerMCP []string `json:"eager_mcp,omitempty" jsonschema:"description=MCP servers that still connect at launch when lazy_mcp is set,example=github"`
+	// EagerLSP lists LSP servers started at launch rather than on the
+	// first file they handle.
+	EagerLSP []string `json:"eager_lsp,omitempty" jsonschema:"description=LSP servers started at launch instead of on the first file they handle,example=gopls"`
+	// IdleShutdown stops LSP and MCP servers unused for this long. They
+	// start again on next use. Zero keeps them running.
+	IdleShutdown time.Duration `json:"idle_shutdown,omitempty" jsonschema:"description=Stop LSP and MCP servers unused for this long; they restart on next use. 0 keeps them running,example=15m"`
+}
+
+// EagerMCPServer reports whether the MCP server name connects at launch.
+func (s *StartupOptions) EagerMCPServer(name string) bool {
+	return s == nil || !s.LazyMCP || slices.Contains(s.EagerMCP, name)
+}
+
+// ParityMode reports whether the upstream-parity output profile is
+// selected. Optional rewrites of what is sent to the model are disabled in
+// parity mode.
+func (o *Options) ParityMode() bool {
+	return o != nil && o.LCM != nil && o.LCM.ExplorerOutputProfile == "parity"
+}
+
+// PromptCompressionEnabled reports whether static system prompt sections
+// and tool descriptions are compressed.
+func (o *Options) PromptCompressionEnabled() bool {
+	return o != nil && o.CompressSystemPrompt && !o.ParityMode()
+}
+
+// RemoteFetchEnabled reports whether the explorer may fetch remote URIs.
+func (o *Options) RemoteFetchEnabled() bool {
+	return o != nil && !o.Offline && !o.ParityMode() &&
+		o.LCM != nil && o.LCM.RemoteFetch != nil && o.LCM.RemoteFetch.Enabled
+}
+
 // SnapshotConfig configures snapshot retention for the rewind system.
 type SnapshotConfig struct {
 	MaxPerSession int `json:"max_per_session,omitempty" jsonschema:"description=Maximum snapshots to retain per session (older ones are cleaned up),default=50"`
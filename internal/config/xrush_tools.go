@@ -33,6 +33,9 @@ func xrushToolNames() []string {
 	return []string{
 		"agentic_map",
 		"batch_edit",
+		"duplicate_code",
+		"feature_flags",
+		"knowledge_lookup",
 		"lcm_active_context",
 		"lcm_ancestry",
 		"lcm_archive",
@@ -46,25 +49,35 @@ func xrushToolNames() []string {
 		"lcm_lineage",
 		"lcm_sprig",
 		"lcm_time_query",
+		"licenses",
 		"list_mcp_resources",
 		"llm_map",
 		"map_refresh",
 		"multiedit",
+		"ports",
+		"processes",
 		"productive_execute",
 		"read_mcp_resource",
+		"schema_lookup",
 		"send_message",
 		"sourcegraph",
 		"swarm_execute",
 		"synthetic_output",
+		"system_resources",
+		"tail_logs",
 		"task_stop",
 		"team_create",
 		"team_delete",
+		"tech_debt",
 	}
 }
 
 // xrushReadOnlyTools returns the list of xrush-only read-only tools.
 func xrushReadOnlyTools() []string {
 	return []string{
+		"duplicate_code",
+		"feature_flags",
+		"knowledge_lookup",
 		"lcm_grep",
 		"lcm_describe",
 		"lcm_expand",
@@ -78,6 +91,13 @@ func xrushReadOnlyTools() []string {
 		"lcm_file_search",
 		"lcm_active_context",
 		"lcm_lineage",
+		"licenses",
+		"ports",
+		"processes",
+		"schema_lookup",
+		"system_resources",
+		"tail_logs",
+		"tech_debt",
 	}
 }
 
@@ -93,27 +113,31 @@ func allToolNames() []string {
 		"crush_info",
 		"crush_logs",
 		"download",
+		fork[2], // duplicate_code
 		"edit",
+		fork[3], // feature_flags
 		"fetch",
 		"glob",
 		"grep",
 		"job_kill",
 		"job_output",
-		fork[2],  // lcm_active_context
-		fork[3],  // lcm_ancestry
-		fork[4],  // lcm_archive
-		fork[5],  // lcm_bindle
-		fork[6],  // lcm_compact
-		fork[7],  // lcm_describe
-		fork[8],  // lcm_dolt
-		fork[9],  // lcm_expand
-		fork[10], // lcm_file_search
-		fork[11], // lcm_grep
-		fork[12], // lcm_lineage
-		fork[13], // lcm_sprig
-		fork[14], // lcm_time_query
-		fork[15], // list_mcp_resources
-		fork[16], // llm_map
+		fork[4],  // knowledge_lookup
+		fork[5],  // lcm_active_context
+		fork[6],  // lcm_ancestry
+		fork[7],  // lcm_archive
+		fork[8],  // lcm_bindle
+		fork[9],  // lcm_compact
+		fork[10], // lcm_describe
+		fork[11], // lcm_dolt
+		fork[12], // lcm_expand
+		fork[13], // lcm_file_search
+		fork[14], // lcm_grep
+		fork[15], // lcm_lineage
+		fork[16], // lcm_sprig
+		fork[17], // lcm_time_query
+		fork[18], // licenses
+		fork[19], // list_mcp_resources
+		fork[20], // llm_map
 		"ls",
 		"lsp_diagnostics",
 		"lsp_document_symbols",
@@ -121,17 +145,23 @@ func allToolNames() []string {
 		"lsp_restart",
 		"lsp_symbols",
 		"lsp_workspace_symbols",
-		fork[17], // map_refresh
-		fork[18], // multiedit
-		fork[19], // productive_execute
-		fork[20], // read_mcp_resource
-		fork[21], // send_message
-		fork[22], // sourcegraph
-		fork[23], // swarm_execute
-		fork[24], // synthetic_output
-		fork[25], // task_stop
-		fork[26], // team_create
-		fork[27], // team_delete
+		fork[21], // map_refresh
+		fork[22], // multiedit
+		fork[23], // ports
+		fork[24], // processes
+		fork[25], // productive_execute
+		fork[26], // read_mcp_resource
+		fork[27], // schema_lookup
+		fork[28], // send_message
+		fork[29], // sourcegraph
+		fork[30], // swarm_execute
+		fork[31], // synthetic_output
+		fork[32], // system_resources
+		fork[33], // tail_logs
+		fork[34], // task_stop
+		fork[35], // team_create
+		fork[36], // team_delete
+		fork[37], // tech_debt
 		"todos",
 		"view",
 		"write",
@@ -64,6 +64,7 @@ type ConnectOption func(*connectOptions)
 // connectOptions holds the resolved configuration for a Connect call.
 type connectOptions struct {
 	lockDataDir bool
+	settings    Settings
 }
 
 // WithDataDirLock toggles acquisition of the per-data-directory lock
@@ -99,6 +100,11 @@ func Connect(ctx context.Context, dataDir string, opts ...ConnectOption) (*sql.D
 		absPath = dbPath
 	}
 
+	dbPragmas, err := cfg.settings.pragmas()
+	if err != nil {
+		return nil, err
+	}
+
 	poolMu.Lock()
 	defer poolMu.Unlock()
 
@@ -126,7 +132,7 @@ func Connect(ctx context.Context, dataDir string, opts ...ConnectOption) (*sql.D
 		}
 	}
 
-	conn, err := openDB(dbPath)
+	conn, err := openDB(dbPath, dbPragmas)
 	if err != nil {
 		if lock != nil {
 			lock.release()
@@ -140,6 +146,9 @@ func Connect(ctx context.Context, dataDir string, opts ...ConnectOption) (*sql.D
 	// under concurrent sub-agents) has caused WAL/header desync
 	// resulting in SQLITE_NOTADB (26) on the next open.
 	conn.SetMaxOpenConns(1)
+	if cfg.settings.ConnMaxIdleTime > 0 {
+		conn.SetConnMaxIdleTime(cfg.settings.ConnMaxIdleTime)
+	}
 
 	releaseLock := func() {
 		if lock != nil {
@@ -10,7 +10,7 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-func openDB(dbPath string) (*sql.DB, error) {
+func openDB(dbPath string, pragmas map[string]string) (*sql.DB, error) {
 	// Set pragmas for better performance via _pragma query params.
 	// Format: _pragma=name(value)
 	params := url.Values{}
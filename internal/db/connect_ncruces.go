@@ -10,7 +10,7 @@ import (
 	"github.com/ncruces/go-sqlite3/driver"
 )
 
-func openDB(dbPath string) (*sql.DB, error) {
+func openDB(dbPath string, pragmas map[string]string) (*sql.DB, error) {
 	// Use BEGIN IMMEDIATE so writers acquire the reserved lock up front,
 	// preventing deferred-to-writer upgrade deadlocks. The "file:" prefix
 	// is required for the ncruces driver to parse query parameters.
@@ -24,12 +24,18 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addExplorerMetricsStmt, err = db.PrepareContext(ctx, addExplorerMetrics); err != nil {
+		return nil, fmt.Errorf("error preparing query AddExplorerMetrics: %w", err)
+	}
 	if q.addSnapshotFileStmt, err = db.PrepareContext(ctx, addSnapshotFile); err != nil {
 		return nil, fmt.Errorf("error preparing query AddSnapshotFile: %w", err)
 	}
 	if q.appendLcmContextItemStmt, err = db.PrepareContext(ctx, appendLcmContextItem); err != nil {
 		return nil, fmt.Errorf("error preparing query AppendLcmContextItem: %w", err)
 	}
+	if q.claimSessionLockStmt, err = db.PrepareContext(ctx, claimSessionLock); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimSessionLock: %w", err)
+	}
 	if q.clearSessionSummaryMessageIDStmt, err = db.PrepareContext(ctx, clearSessionSummaryMessageID); err != nil {
 		return nil, fmt.Errorf("error preparing query ClearSessionSummaryMessageID: %w", err)
 	}
@@ -99,6 +105,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteSessionMessagesStmt, err = db.PrepareContext(ctx, deleteSessionMessages); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSessionMessages: %w", err)
 	}
+	if q.deleteSessionOverrideStmt, err = db.PrepareContext(ctx, deleteSessionOverride); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSessionOverride: %w", err)
+	}
+	if q.deleteSessionOverridesStmt, err = db.PrepareContext(ctx, deleteSessionOverrides); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSessionOverrides: %w", err)
+	}
 	if q.deleteSessionRankingsStmt, err = db.PrepareContext(ctx, deleteSessionRankings); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSessionRankings: %w", err)
 	}
@@ -129,6 +141,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getContentReplacementsBySessionPositionStmt, err = db.PrepareContext(ctx, getContentReplacementsBySessionPosition); err != nil {
 		return nil, fmt.Errorf("error preparing query GetContentReplacementsBySessionPosition: %w", err)
 	}
+	if q.getExplorerCacheEntryStmt, err = db.PrepareContext(ctx, getExplorerCacheEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetExplorerCacheEntry: %w", err)
+	}
 	if q.getFileStmt, err = db.PrepareContext(ctx, getFile); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFile: %w", err)
 	}
@@ -198,9 +213,24 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getRepoMapFileCacheByPathStmt, err = db.PrepareContext(ctx, getRepoMapFileCacheByPath); err != nil {
 		return nil, fmt.Errorf("error preparing query GetRepoMapFileCacheByPath: %w", err)
 	}
+	if q.getRepoMapTagCacheEntryStmt, err = db.PrepareContext(ctx, getRepoMapTagCacheEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRepoMapTagCacheEntry: %w", err)
+	}
+	if q.getReportToolUsageStmt, err = db.PrepareContext(ctx, getReportToolUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReportToolUsage: %w", err)
+	}
+	if q.getReportTotalsStmt, err = db.PrepareContext(ctx, getReportTotals); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReportTotals: %w", err)
+	}
+	if q.getReportUsageByModelStmt, err = db.PrepareContext(ctx, getReportUsageByModel); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReportUsageByModel: %w", err)
+	}
 	if q.getSessionByIDStmt, err = db.PrepareContext(ctx, getSessionByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSessionByID: %w", err)
 	}
+	if q.getSessionLockStmt, err = db.PrepareContext(ctx, getSessionLock); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSessionLock: %w", err)
+	}
 	if q.getToolUsageStmt, err = db.PrepareContext(ctx, getToolUsage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetToolUsage: %w", err)
 	}
@@ -228,6 +258,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getUsageByModelStmt, err = db.PrepareContext(ctx, getUsageByModel); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUsageByModel: %w", err)
 	}
+	if q.importMessageStmt, err = db.PrepareContext(ctx, importMessage); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportMessage: %w", err)
+	}
+	if q.importSessionStmt, err = db.PrepareContext(ctx, importSession); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportSession: %w", err)
+	}
 	if q.insertLcmContextItemStmt, err = db.PrepareContext(ctx, insertLcmContextItem); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertLcmContextItem: %w", err)
 	}
@@ -267,6 +303,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listContentReplacementsByStateStmt, err = db.PrepareContext(ctx, listContentReplacementsByState); err != nil {
 		return nil, fmt.Errorf("error preparing query ListContentReplacementsByState: %w", err)
 	}
+	if q.listExplorerMetricsStmt, err = db.PrepareContext(ctx, listExplorerMetrics); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExplorerMetrics: %w", err)
+	}
 	if q.listFilesByPathStmt, err = db.PrepareContext(ctx, listFilesByPath); err != nil {
 		return nil, fmt.Errorf("error preparing query ListFilesByPath: %w", err)
 	}
@@ -315,6 +354,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listRepoMapTagsStmt, err = db.PrepareContext(ctx, listRepoMapTags); err != nil {
 		return nil, fmt.Errorf("error preparing query ListRepoMapTags: %w", err)
 	}
+	if q.listSessionOverridesStmt, err = db.PrepareContext(ctx, listSessionOverrides); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSessionOverrides: %w", err)
+	}
 	if q.listSessionRankingsStmt, err = db.PrepareContext(ctx, listSessionRankings); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSessionRankings: %w", err)
 	}
@@ -336,9 +378,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listTurnSnapshotsBySessionStmt, err = db.PrepareContext(ctx, listTurnSnapshotsBySession); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTurnSnapshotsBySession: %w", err)
 	}
+	if q.listUnfinishedAssistantMessagesStmt, err = db.PrepareContext(ctx, listUnfinishedAssistantMessages); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUnfinishedAssistantMessages: %w", err)
+	}
 	if q.listUserMessagesBySessionStmt, err = db.PrepareContext(ctx, listUserMessagesBySession); err != nil {
 		return nil, fmt.Errorf("error preparing query ListUserMessagesBySession: %w", err)
 	}
+	if q.pruneExplorerCacheStmt, err = db.PrepareContext(ctx, pruneExplorerCache); err != nil {
+		return nil, fmt.Errorf("error preparing query PruneExplorerCache: %w", err)
+	}
+	if q.pruneRepoMapTagCacheStmt, err = db.PrepareContext(ctx, pruneRepoMapTagCache); err != nil {
+		return nil, fmt.Errorf("error preparing query PruneRepoMapTagCache: %w", err)
+	}
 	if q.recordContentReplacementStmt, err = db.PrepareContext(ctx, recordContentReplacement); err != nil {
 		return nil, fmt.Errorf("error preparing query RecordContentReplacement: %w", err)
 	}
@@ -348,12 +399,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.recordFileWriteStmt, err = db.PrepareContext(ctx, recordFileWrite); err != nil {
 		return nil, fmt.Errorf("error preparing query RecordFileWrite: %w", err)
 	}
+	if q.releaseSessionLockStmt, err = db.PrepareContext(ctx, releaseSessionLock); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseSessionLock: %w", err)
+	}
 	if q.renameSessionStmt, err = db.PrepareContext(ctx, renameSession); err != nil {
 		return nil, fmt.Errorf("error preparing query RenameSession: %w", err)
 	}
+	if q.renewSessionLockStmt, err = db.PrepareContext(ctx, renewSessionLock); err != nil {
+		return nil, fmt.Errorf("error preparing query RenewSessionLock: %w", err)
+	}
 	if q.searchLcmSummariesStmt, err = db.PrepareContext(ctx, searchLcmSummaries); err != nil {
 		return nil, fmt.Errorf("error preparing query SearchLcmSummaries: %w", err)
 	}
+	if q.touchRepoMapTagCacheEntryStmt, err = db.PrepareContext(ctx, touchRepoMapTagCacheEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchRepoMapTagCacheEntry: %w", err)
+	}
 	if q.updateContentReplacementStateStmt, err = db.PrepareContext(ctx, updateContentReplacementState); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateContentReplacementState: %w", err)
 	}
@@ -384,12 +444,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateSessionTitleAndUsageStmt, err = db.PrepareContext(ctx, updateSessionTitleAndUsage); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateSessionTitleAndUsage: %w", err)
 	}
+	if q.upsertExplorerCacheEntryStmt, err = db.PrepareContext(ctx, upsertExplorerCacheEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertExplorerCacheEntry: %w", err)
+	}
 	if q.upsertLcmSessionConfigStmt, err = db.PrepareContext(ctx, upsertLcmSessionConfig); err != nil {
 		return nil, fmt.Errorf("error preparing query UpsertLcmSessionConfig: %w", err)
 	}
 	if q.upsertRepoMapFileCacheStmt, err = db.PrepareContext(ctx, upsertRepoMapFileCache); err != nil {
 		return nil, fmt.Errorf("error preparing query UpsertRepoMapFileCache: %w", err)
 	}
+	if q.upsertRepoMapTagCacheEntryStmt, err = db.PrepareContext(ctx, upsertRepoMapTagCacheEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertRepoMapTagCacheEntry: %w", err)
+	}
+	if q.upsertSessionOverrideStmt, err = db.PrepareContext(ctx, upsertSessionOverride); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertSessionOverride: %w", err)
+	}
 	if q.upsertSessionRankingStmt, err = db.PrepareContext(ctx, upsertSessionRanking); err != nil {
 		return nil, fmt.Errorf("error preparing query UpsertSessionRanking: %w", err)
 	}
@@ -401,6 +470,11 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addExplorerMetricsStmt != nil {
+		if cerr := q.addExplorerMetricsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addExplorerMetricsStmt: %w", cerr)
+		}
+	}
 	if q.addSnapshotFileStmt != nil {
 		if cerr := q.addSnapshotFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addSnapshotFileStmt: %w", cerr)
@@ -411,6 +485,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing appendLcmContextItemStmt: %w", cerr)
 		}
 	}
+	if q.claimSessionLockStmt != nil {
+		if cerr := q.claimSessionLockStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimSessionLockStmt: %w", cerr)
+		}
+	}
 	if q.clearSessionSummaryMessageIDStmt != nil {
 		if cerr := q.clearSessionSummaryMessageIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing clearSessionSummaryMessageIDStmt: %w", cerr)
@@ -526,6 +605,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteSessionMessagesStmt: %w", cerr)
 		}
 	}
+	if q.deleteSessionOverrideStmt != nil {
+		if cerr := q.deleteSessionOverrideStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSessionOverrideStmt: %w", cerr)
+		}
+	}
+	if q.deleteSessionOverridesStmt != nil {
+		if cerr := q.deleteSessionOverridesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSessionOverridesStmt: %w", cerr)
+		}
+	}
 	if q.deleteSessionRankingsStmt != nil {
 		if cerr := q.deleteSessionRankingsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteSessionRankingsStmt: %w", cerr)
@@ -576,6 +665,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getContentReplacementsBySessionPositionStmt: %w", cerr)
 		}
 	}
+	if q.getExplorerCacheEntryStmt != nil {
+		if cerr := q.getExplorerCacheEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getExplorerCacheEntryStmt: %w", cerr)
+		}
+	}
 	if q.getFileStmt != nil {
 		if cerr := q.getFileStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getFileStmt: %w", cerr)
@@ -691,11 +785,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getRepoMapFileCacheByPathStmt: %w", cerr)
 		}
 	}
+	if q.getRepoMapTagCacheEntryStmt != nil {
+		if cerr := q.getRepoMapTagCacheEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRepoMapTagCacheEntryStmt: %w", cerr)
+		}
+	}
+	if q.getReportToolUsageStmt != nil {
+		if cerr := q.getReportToolUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReportToolUsageStmt: %w", cerr)
+		}
+	}
+	if q.getReportTotalsStmt != nil {
+		if cerr := q.getReportTotalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReportTotalsStmt: %w", cerr)
+		}
+	}
+	if q.getReportUsageByModelStmt != nil {
+		if cerr := q.getReportUsageByModelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReportUsageByModelStmt: %w", cerr)
+		}
+	}
 	if q.getSessionByIDStmt != nil {
 		if cerr := q.getSessionByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSessionByIDStmt: %w", cerr)
 		}
 	}
+	if q.getSessionLockStmt != nil {
+		if cerr := q.getSessionLockStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSessionLockStmt: %w", cerr)
+		}
+	}
 	if q.getToolUsageStmt != nil {
 		if cerr := q.getToolUsageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getToolUsageStmt: %w", cerr)
@@ -741,6 +860,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUsageByModelStmt: %w", cerr)
 		}
 	}
+	if q.importMessageStmt != nil {
+		if cerr := q.importMessageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importMessageStmt: %w", cerr)
+		}
+	}
+	if q.importSessionStmt != nil {
+		if cerr := q.importSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importSessionStmt: %w", cerr)
+		}
+	}
 	if q.insertLcmContextItemStmt != nil {
 		if cerr := q.insertLcmContextItemStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertLcmContextItemStmt: %w", cerr)
@@ -806,6 +935,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listContentReplacementsByStateStmt: %w", cerr)
 		}
 	}
+	if q.listExplorerMetricsStmt != nil {
+		if cerr := q.listExplorerMetricsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExplorerMetricsStmt: %w", cerr)
+		}
+	}
 	if q.listFilesByPathStmt != nil {
 		if cerr := q.listFilesByPathStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listFilesByPathStmt: %w", cerr)
@@ -886,6 +1020,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listRepoMapTagsStmt: %w", cerr)
 		}
 	}
+	if q.listSessionOverridesStmt != nil {
+		if cerr := q.listSessionOverridesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSessionOverridesStmt: %w", cerr)
+		}
+	}
 	if q.listSessionRankingsStmt != nil {
 		if cerr := q.listSessionRankingsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listSessionRankingsStmt: %w", cerr)
@@ -921,11 +1060,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listTurnSnapshotsBySessionStmt: %w", cerr)
 		}
 	}
+	if q.listUnfinishedAssistantMessagesStmt != nil {
+		if cerr := q.listUnfinishedAssistantMessagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUnfinishedAssistantMessagesStmt: %w", cerr)
+		}
+	}
 	if q.listUserMessagesBySessionStmt != nil {
 		if cerr := q.listUserMessagesBySessionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listUserMessagesBySessionStmt: %w", cerr)
 		}
 	}
+	if q.pruneExplorerCacheStmt != nil {
+		if cerr := q.pruneExplorerCacheStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing pruneExplorerCacheStmt: %w", cerr)
+		}
+	}
+	if q.pruneRepoMapTagCacheStmt != nil {
+		if cerr := q.pruneRepoMapTagCacheStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing pruneRepoMapTagCacheStmt: %w", cerr)
+		}
+	}
 	if q.recordContentReplacementStmt != nil {
 		if cerr := q.recordContentReplacementStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing recordContentReplacementStmt: %w", cerr)
@@ -941,16 +1095,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing recordFileWriteStmt: %w", cerr)
 		}
 	}
+	if q.releaseSessionLockStmt != nil {
+		if cerr := q.releaseSessionLockStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseSessionLockStmt: %w", cerr)
+		}
+	}
 	if q.renameSessionStmt != nil {
 		if cerr := q.renameSessionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing renameSessionStmt: %w", cerr)
 		}
 	}
+	if q.renewSessionLockStmt != nil {
+		if cerr := q.renewSessionLockStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renewSessionLockStmt: %w", cerr)
+		}
+	}
 	if q.searchLcmSummariesStmt != nil {
 		if cerr := q.searchLcmSummariesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing searchLcmSummariesStmt: %w", cerr)
 		}
 	}
+	if q.touchRepoMapTagCacheEntryStmt != nil {
+		if cerr := q.touchRepoMapTagCacheEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchRepoMapTagCacheEntryStmt: %w", cerr)
+		}
+	}
 	if q.updateContentReplacementStateStmt != nil {
 		if cerr := q.updateContentReplacementStateStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateContentReplacementStateStmt: %w", cerr)
@@ -1001,6 +1170,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateSessionTitleAndUsageStmt: %w", cerr)
 		}
 	}
+	if q.upsertExplorerCacheEntryStmt != nil {
+		if cerr := q.upsertExplorerCacheEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertExplorerCacheEntryStmt: %w", cerr)
+		}
+	}
 	if q.upsertLcmSessionConfigStmt != nil {
 		if cerr := q.upsertLcmSessionConfigStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing upsertLcmSessionConfigStmt: %w", cerr)
@@ -1011,6 +1185,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing upsertRepoMapFileCacheStmt: %w", cerr)
 		}
 	}
+	if q.upsertRepoMapTagCacheEntryStmt != nil {
+		if cerr := q.upsertRepoMapTagCacheEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertRepoMapTagCacheEntryStmt: %w", cerr)
+		}
+	}
+	if q.upsertSessionOverrideStmt != nil {
+		if cerr := q.upsertSessionOverrideStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertSessionOverrideStmt: %w", cerr)
+		}
+	}
 	if q.upsertSessionRankingStmt != nil {
 		if cerr := q.upsertSessionRankingStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing upsertSessionRankingStmt: %w", cerr)
@@ -1060,8 +1244,10 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                          DBTX
 	tx                                          *sql.Tx
+	addExplorerMetricsStmt                      *sql.Stmt
 	addSnapshotFileStmt                         *sql.Stmt
 	appendLcmContextItemStmt                    *sql.Stmt
+	claimSessionLockStmt                        *sql.Stmt
 	clearSessionSummaryMessageIDStmt            *sql.Stmt
 	cloneSessionFilesStmt                       *sql.Stmt
 	cloneSessionMessagesStmt                    *sql.Stmt
@@ -1085,6 +1271,8 @@ type Queries struct {
 	deleteSessionStmt                           *sql.Stmt
 	deleteSessionFilesStmt                      *sql.Stmt
 	deleteSessionMessagesStmt                   *sql.Stmt
+	deleteSessionOverrideStmt                   *sql.Stmt
+	deleteSessionOverridesStmt                  *sql.Stmt
 	deleteSessionRankingsStmt                   *sql.Stmt
 	deleteSessionReadOnlyPathsStmt              *sql.Stmt
 	deleteSessionTurnSnapshotsStmt              *sql.Stmt
@@ -1095,6 +1283,7 @@ type Queries struct {
 	getContentReplacementStmt                   *sql.Stmt
 	getContentReplacementsByFileIDStmt          *sql.Stmt
 	getContentReplacementsBySessionPositionStmt *sql.Stmt
+	getExplorerCacheEntryStmt                   *sql.Stmt
 	getFileStmt                                 *sql.Stmt
 	getFileByPathAndSessionStmt                 *sql.Stmt
 	getFileReadStmt                             *sql.Stmt
@@ -1118,7 +1307,12 @@ type Queries struct {
 	getRecentActivityStmt                       *sql.Stmt
 	getRepoMapFileCacheStmt                     *sql.Stmt
 	getRepoMapFileCacheByPathStmt               *sql.Stmt
+	getRepoMapTagCacheEntryStmt                 *sql.Stmt
+	getReportToolUsageStmt                      *sql.Stmt
+	getReportTotalsStmt                         *sql.Stmt
+	getReportUsageByModelStmt                   *sql.Stmt
 	getSessionByIDStmt                          *sql.Stmt
+	getSessionLockStmt                          *sql.Stmt
 	getToolUsageStmt                            *sql.Stmt
 	getTotalStatsStmt                           *sql.Stmt
 	getTurnSnapshotStmt                         *sql.Stmt
@@ -1128,6 +1322,8 @@ type Queries struct {
 	getUsageByDayOfWeekStmt                     *sql.Stmt
 	getUsageByHourStmt                          *sql.Stmt
 	getUsageByModelStmt                         *sql.Stmt
+	importMessageStmt                           *sql.Stmt
+	importSessionStmt                           *sql.Stmt
 	insertLcmContextItemStmt                    *sql.Stmt
 	insertLcmLargeFileStmt                      *sql.Stmt
 	insertLcmMapItemStmt                        *sql.Stmt
@@ -1141,6 +1337,7 @@ type Queries struct {
 	listAllUserMessagesStmt                     *sql.Stmt
 	listContentReplacementsByRoundStmt          *sql.Stmt
 	listContentReplacementsByStateStmt          *sql.Stmt
+	listExplorerMetricsStmt                     *sql.Stmt
 	listFilesByPathStmt                         *sql.Stmt
 	listFilesBySessionStmt                      *sql.Stmt
 	listLatestSessionFilesStmt                  *sql.Stmt
@@ -1157,6 +1354,7 @@ type Queries struct {
 	listRecentSessionReadFilesStmt              *sql.Stmt
 	listRepoMapDefsByNameStmt                   *sql.Stmt
 	listRepoMapTagsStmt                         *sql.Stmt
+	listSessionOverridesStmt                    *sql.Stmt
 	listSessionRankingsStmt                     *sql.Stmt
 	listSessionReadFilesStmt                    *sql.Stmt
 	listSessionReadOnlyPathsStmt                *sql.Stmt
@@ -1164,12 +1362,18 @@ type Queries struct {
 	listSessionsStmt                            *sql.Stmt
 	listSnapshotFilesStmt                       *sql.Stmt
 	listTurnSnapshotsBySessionStmt              *sql.Stmt
+	listUnfinishedAssistantMessagesStmt         *sql.Stmt
 	listUserMessagesBySessionStmt               *sql.Stmt
+	pruneExplorerCacheStmt                      *sql.Stmt
+	pruneRepoMapTagCacheStmt                    *sql.Stmt
 	recordContentReplacementStmt                *sql.Stmt
 	recordFileReadStmt                          *sql.Stmt
 	recordFileWriteStmt                         *sql.Stmt
+	releaseSessionLockStmt                      *sql.Stmt
 	renameSessionStmt                           *sql.Stmt
+	renewSessionLockStmt                        *sql.Stmt
 	searchLcmSummariesStmt                      *sql.Stmt
+	touchRepoMapTagCacheEntryStmt               *sql.Stmt
 	updateContentReplacementStateStmt           *sql.Stmt
 	updateLcmLargeFileExplorationStmt           *sql.Stmt
 	updateLcmMapItemStmt                        *sql.Stmt
@@ -1180,8 +1384,11 @@ type Queries struct {
 	updateMessageTokenCountStmt                 *sql.Stmt
 	updateSessionStmt                           *sql.Stmt
 	updateSessionTitleAndUsageStmt              *sql.Stmt
+	upsertExplorerCacheEntryStmt                *sql.Stmt
 	upsertLcmSessionConfigStmt                  *sql.Stmt
 	upsertRepoMapFileCacheStmt                  *sql.Stmt
+	upsertRepoMapTagCacheEntryStmt              *sql.Stmt
+	upsertSessionOverrideStmt                   *sql.Stmt
 	upsertSessionRankingStmt                    *sql.Stmt
 	upsertSessionReadOnlyPathStmt               *sql.Stmt
 }
@@ -1190,8 +1397,10 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                          tx,
 		tx:                                          tx,
+		addExplorerMetricsStmt:                      q.addExplorerMetricsStmt,
 		addSnapshotFileStmt:                         q.addSnapshotFileStmt,
 		appendLcmContextItemStmt:                    q.appendLcmContextItemStmt,
+		claimSessionLockStmt:                        q.claimSessionLockStmt,
 		clearSessionSummaryMessageIDStmt:            q.clearSessionSummaryMessageIDStmt,
 		cloneSessionFilesStmt:                       q.cloneSessionFilesStmt,
 		cloneSessionMessagesStmt:                    q.cloneSessionMessagesStmt,
@@ -1215,6 +1424,8 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		deleteSessionStmt:                           q.deleteSessionStmt,
 		deleteSessionFilesStmt:                      q.deleteSessionFilesStmt,
 		deleteSessionMessagesStmt:                   q.deleteSessionMessagesStmt,
+		deleteSessionOverrideStmt:                   q.deleteSessionOverrideStmt,
+		deleteSessionOverridesStmt:                  q.deleteSessionOverridesStmt,
 		deleteSessionRankingsStmt:                   q.deleteSessionRankingsStmt,
 		deleteSessionReadOnlyPathsStmt:              q.deleteSessionReadOnlyPathsStmt,
 		deleteSessionTurnSnapshotsStmt:              q.deleteSessionTurnSnapshotsStmt,
@@ -1225,6 +1436,7 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getContentReplacementStmt:                   q.getContentReplacementStmt,
 		getContentReplacementsByFileIDStmt:          q.getContentReplacementsByFileIDStmt,
 		getContentReplacementsBySessionPositionStmt: q.getContentReplacementsBySessionPositionStmt,
+		getExplorerCacheEntryStmt:                   q.getExplorerCacheEntryStmt,
 		getFileStmt:                                 q.getFileStmt,
 		getFileByPathAndSessionStmt:                 q.getFileByPathAndSessionStmt,
 		getFileReadStmt:                             q.getFileReadStmt,
@@ -1248,7 +1460,12 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getRecentActivityStmt:                       q.getRecentActivityStmt,
 		getRepoMapFileCacheStmt:                     q.getRepoMapFileCacheStmt,
 		getRepoMapFileCacheByPathStmt:               q.getRepoMapFileCacheByPathStmt,
+		getRepoMapTagCacheEntryStmt:                 q.getRepoMapTagCacheEntryStmt,
+		getReportToolUsageStmt:                      q.getReportToolUsageStmt,
+		getReportTotalsStmt:                         q.getReportTotalsStmt,
+		getReportUsageByModelStmt:                   q.getReportUsageByModelStmt,
 		getSessionByIDStmt:                          q.getSessionByIDStmt,
+		getSessionLockStmt:                          q.getSessionLockStmt,
 		getToolUsageStmt:                            q.getToolUsageStmt,
 		getTotalStatsStmt:                           q.getTotalStatsStmt,
 		getTurnSnapshotStmt:                         q.getTurnSnapshotStmt,
@@ -1258,6 +1475,8 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getUsageByDayOfWeekStmt:                     q.getUsageByDayOfWeekStmt,
 		getUsageByHourStmt:                          q.getUsageByHourStmt,
 		getUsageByModelStmt:                         q.getUsageByModelStmt,
+		importMessageStmt:                           q.importMessageStmt,
+		importSessionStmt:                           q.importSessionStmt,
 		insertLcmContextItemStmt:                    q.insertLcmContextItemStmt,
 		insertLcmLargeFileStmt:                      q.insertLcmLargeFileStmt,
 		insertLcmMapItemStmt:                        q.insertLcmMapItemStmt,
@@ -1271,6 +1490,7 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		listAllUserMessagesStmt:                     q.listAllUserMessagesStmt,
 		listContentReplacementsByRoundStmt:          q.listContentReplacementsByRoundStmt,
 		listContentReplacementsByStateStmt:          q.listContentReplacementsByStateStmt,
+		listExplorerMetricsStmt:                     q.listExplorerMetricsStmt,
 		listFilesByPathStmt:                         q.listFilesByPathStmt,
 		listFilesBySessionStmt:                      q.listFilesBySessionStmt,
 		listLatestSessionFilesStmt:                  q.listLatestSessionFilesStmt,
@@ -1287,6 +1507,7 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		listRecentSessionReadFilesStmt:              q.listRecentSessionReadFilesStmt,
 		listRepoMapDefsByNameStmt:                   q.listRepoMapDefsByNameStmt,
 		listRepoMapTagsStmt:                         q.listRepoMapTagsStmt,
+		listSessionOverridesStmt:                    q.listSessionOverridesStmt,
 		listSessionRankingsStmt:                     q.listSessionRankingsStmt,
 		listSessionReadFilesStmt:                    q.listSessionReadFilesStmt,
 		listSessionReadOnlyPathsStmt:                q.listSessionReadOnlyPathsStmt,
@@ -1294,12 +1515,18 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		listSessionsStmt:                            q.listSessionsStmt,
 		listSnapshotFilesStmt:                       q.listSnapshotFilesStmt,
 		listTurnSnapshotsBySessionStmt:              q.listTurnSnapshotsBySessionStmt,
+		listUnfinishedAssistantMessagesStmt:         q.listUnfinishedAssistantMessagesStmt,
 		listUserMessagesBySessionStmt:               q.listUserMessagesBySessionStmt,
+		pruneExplorerCacheStmt:                      q.pruneExplorerCacheStmt,
+		pruneRepoMapTagCacheStmt:                    q.pruneRepoMapTagCacheStmt,
 		recordContentReplacementStmt:                q.recordContentReplacementStmt,
 		recordFileReadStmt:                          q.recordFileReadStmt,
 		recordFileWriteStmt:                         q.recordFileWriteStmt,
+		releaseSessionLockStmt:                      q.releaseSessionLockStmt,
 		renameSessionStmt:                           q.renameSessionStmt,
+		renewSessionLockStmt:                        q.renewSessionLockStmt,
 		searchLcmSummariesStmt:                      q.searchLcmSummariesStmt,
+		touchRepoMapTagCacheEntryStmt:               q.touchRepoMapTagCacheEntryStmt,
 		updateContentReplacementStateStmt:           q.updateContentReplacementStateStmt,
 		updateLcmLargeFileExplorationStmt:           q.updateLcmLargeFileExplorationStmt,
 		updateLcmMapItemStmt:                        q.updateLcmMapItemStmt,
@@ -1310,8 +1537,11 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		updateMessageTokenCountStmt:                 q.updateMessageTokenCountStmt,
 		updateSessionStmt:                           q.updateSessionStmt,
 		updateSessionTitleAndUsageStmt:              q.updateSessionTitleAndUsageStmt,
+		upsertExplorerCacheEntryStmt:                q.upsertExplorerCacheEntryStmt,
 		upsertLcmSessionConfigStmt:                  q.upsertLcmSessionConfigStmt,
 		upsertRepoMapFileCacheStmt:                  q.upsertRepoMapFileCacheStmt,
+		upsertRepoMapTagCacheEntryStmt:              q.upsertRepoMapTagCacheEntryStmt,
+		upsertSessionOverrideStmt:                   q.upsertSessionOverrideStmt,
 		upsertSessionRankingStmt:                    q.upsertSessionRankingStmt,
 		upsertSessionReadOnlyPathStmt:               q.upsertSessionReadOnlyPathStmt,
 	}
@@ -285,7 +285,7 @@ func (q *Queries) GetMessageCountByTimeRange(ctx context.Context, arg GetMessage
 }
 
 const getMessagesByTimeRange = `-- name: GetMessagesByTimeRange :many
-SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at FROM messages WHERE session_id = ? AND created_at >= ? AND created_at <= ? ORDER BY created_at ASC
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason FROM messages WHERE session_id = ? AND created_at >= ? AND created_at <= ? ORDER BY created_at ASC
 `
 
 type GetMessagesByTimeRangeParams struct {
@@ -320,6 +320,10 @@ func (q *Queries) GetMessagesByTimeRange(ctx context.Context, arg GetMessagesByT
 			&i.SentToLlmAt,
 			&i.FirstTokenAt,
 			&i.CompletedAt,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+			&i.RoutingReason,
 		); err != nil {
 			return nil, err
 		}
@@ -748,7 +752,7 @@ func (q *Queries) ListLcmSummaryParents(ctx context.Context, summaryID string) (
 }
 
 const listMessagesBySessionSeq = `-- name: ListMessagesBySessionSeq :many
-SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at FROM messages WHERE session_id = ? ORDER BY seq ASC
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason FROM messages WHERE session_id = ? ORDER BY seq ASC
 `
 
 func (q *Queries) ListMessagesBySessionSeq(ctx context.Context, sessionID string) ([]Message, error) {
@@ -777,6 +781,10 @@ func (q *Queries) ListMessagesBySessionSeq(ctx context.Context, sessionID string
 			&i.SentToLlmAt,
 			&i.FirstTokenAt,
 			&i.CompletedAt,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+			&i.RoutingReason,
 		); err != nil {
 			return nil, err
 		}
@@ -792,7 +800,7 @@ func (q *Queries) ListMessagesBySessionSeq(ctx context.Context, sessionID string
 }
 
 const listMessagesInSeqRange = `-- name: ListMessagesInSeqRange :many
-SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at FROM messages WHERE session_id = ? AND seq >= ? AND seq <= ? ORDER BY seq ASC
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason FROM messages WHERE session_id = ? AND seq >= ? AND seq <= ? ORDER BY seq ASC
 `
 
 type ListMessagesInSeqRangeParams struct {
@@ -827,6 +835,10 @@ func (q *Queries) ListMessagesInSeqRange(ctx context.Context, arg ListMessagesIn
 			&i.SentToLlmAt,
 			&i.FirstTokenAt,
 			&i.CompletedAt,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+			&i.RoutingReason,
 		); err != nil {
 			return nil, err
 		}
@@ -22,14 +22,15 @@ INSERT INTO messages (
     seq,
     created_at,
     updated_at,
-    submitted_at
+    submitted_at,
+    routing_reason
 ) VALUES (
     ?, ?, ?, ?, ?, ?, ?,
     (SELECT COALESCE(MAX(m.seq) + 1, 0) FROM messages m WHERE m.session_id = ?),
     strftime('%s', 'now'), strftime('%s', 'now'),
-    ?
+    ?, ?
 )
-RETURNING id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at
+RETURNING id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason
 `
 
 type CreateMessageParams struct {
@@ -42,6 +43,7 @@ type CreateMessageParams struct {
 	IsSummaryMessage int64          `json:"is_summary_message"`
 	SessionID_2      string         `json:"session_id_2"`
 	SubmittedAt      int64          `json:"submitted_at"`
+	RoutingReason    string         `json:"routing_reason"`
 }
 
 func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error) {
@@ -55,6 +57,7 @@ func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (M
 		arg.IsSummaryMessage,
 		arg.SessionID_2,
 		arg.SubmittedAt,
+		arg.RoutingReason,
 	)
 	var i Message
 	err := row.Scan(
@@ -74,6 +77,10 @@ func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (M
 		&i.SentToLlmAt,
 		&i.FirstTokenAt,
 		&i.CompletedAt,
+		&i.PromptTokens,
+		&i.CompletionTokens,
+		&i.Cost,
+		&i.RoutingReason,
 	)
 	return i, err
 }
@@ -99,7 +106,7 @@ func (q *Queries) DeleteSessionMessages(ctx context.Context, sessionID string) e
 }
 
 const getMessage = `-- name: GetMessage :one
-SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason
 FROM messages
 WHERE id = ? LIMIT 1
 `
@@ -124,12 +131,16 @@ func (q *Queries) GetMessage(ctx context.Context, id string) (Message, error) {
 		&i.SentToLlmAt,
 		&i.FirstTokenAt,
 		&i.CompletedAt,
+		&i.PromptTokens,
+		&i.CompletionTokens,
+		&i.Cost,
+		&i.RoutingReason,
 	)
 	return i, err
 }
 
 const listAllUserMessages = `-- name: ListAllUserMessages :many
-SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason
 FROM messages
 WHERE role = 'user'
 ORDER BY created_at DESC
@@ -161,6 +172,10 @@ func (q *Queries) ListAllUserMessages(ctx context.Context) ([]Message, error) {
 			&i.SentToLlmAt,
 			&i.FirstTokenAt,
 			&i.CompletedAt,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+			&i.RoutingReason,
 		); err != nil {
 			return nil, err
 		}
@@ -176,7 +191,7 @@ func (q *Queries) ListAllUserMessages(ctx context.Context) ([]Message, error) {
 }
 
 const listMessagesBySession = `-- name: ListMessagesBySession :many
-SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason
 FROM messages
 WHERE session_id = ?
 ORDER BY created_at ASC
@@ -208,6 +223,61 @@ func (q *Queries) ListMessagesBySession(ctx context.Context, sessionID string) (
 			&i.SentToLlmAt,
 			&i.FirstTokenAt,
 			&i.CompletedAt,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+			&i.RoutingReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnfinishedAssistantMessages = `-- name: ListUnfinishedAssistantMessages :many
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason
+FROM messages
+WHERE role = 'assistant' AND finished_at IS NULL AND updated_at < ?
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListUnfinishedAssistantMessages(ctx context.Context, updatedAt int64) ([]Message, error) {
+	rows, err := q.query(ctx, q.listUnfinishedAssistantMessagesStmt, listUnfinishedAssistantMessages, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Message{}
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Role,
+			&i.Parts,
+			&i.Model,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FinishedAt,
+			&i.Provider,
+			&i.IsSummaryMessage,
+			&i.Seq,
+			&i.TokenCount,
+			&i.SubmittedAt,
+			&i.SentToLlmAt,
+			&i.FirstTokenAt,
+			&i.CompletedAt,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+			&i.RoutingReason,
 		); err != nil {
 			return nil, err
 		}
@@ -223,7 +293,7 @@ func (q *Queries) ListMessagesBySession(ctx context.Context, sessionID string) (
 }
 
 const listUserMessagesBySession = `-- name: ListUserMessagesBySession :many
-SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at
+SELECT id, session_id, role, parts, model, created_at, updated_at, finished_at, provider, is_summary_message, seq, token_count, submitted_at, sent_to_llm_at, first_token_at, completed_at, prompt_tokens, completion_tokens, cost, routing_reason
 FROM messages
 WHERE session_id = ? AND role = 'user'
 ORDER BY created_at DESC
@@ -255,6 +325,10 @@ func (q *Queries) ListUserMessagesBySession(ctx context.Context, sessionID strin
 			&i.SentToLlmAt,
 			&i.FirstTokenAt,
 			&i.CompletedAt,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+			&i.RoutingReason,
 		); err != nil {
 			return nil, err
 		}
@@ -277,17 +351,23 @@ SET
     sent_to_llm_at = ?,
     first_token_at = ?,
     completed_at = ?,
+    prompt_tokens = ?,
+    completion_tokens = ?,
+    cost = ?,
     updated_at = strftime('%s', 'now')
 WHERE id = ?
 `
 
 type UpdateMessageParams struct {
-	Parts        string        `json:"parts"`
-	FinishedAt   sql.NullInt64 `json:"finished_at"`
-	SentToLlmAt  int64         `json:"sent_to_llm_at"`
-	FirstTokenAt int64         `json:"first_token_at"`
-	CompletedAt  int64         `json:"completed_at"`
-	ID           string        `json:"id"`
+	Parts            string        `json:"parts"`
+	FinishedAt       sql.NullInt64 `json:"finished_at"`
+	SentToLlmAt      int64         `json:"sent_to_llm_at"`
+	FirstTokenAt     int64         `json:"first_token_at"`
+	CompletedAt      int64         `json:"completed_at"`
+	PromptTokens     int64         `json:"prompt_tokens"`
+	CompletionTokens int64         `json:"completion_tokens"`
+	Cost             float64       `json:"cost"`
+	ID               string        `json:"id"`
 }
 
 func (q *Queries) UpdateMessage(ctx context.Context, arg UpdateMessageParams) error {
@@ -297,6 +377,9 @@ func (q *Queries) UpdateMessage(ctx context.Context, arg UpdateMessageParams) er
 		arg.SentToLlmAt,
 		arg.FirstTokenAt,
 		arg.CompletedAt,
+		arg.PromptTokens,
+		arg.CompletionTokens,
+		arg.Cost,
 		arg.ID,
 	)
 	return err
@@ -446,7 +446,7 @@ func TestMessagePartsMigration(t *testing.T) {
 	require.Error(t, err, "invalid part_type should be rejected by CHECK constraint")
 
 	// Verify the Down migration works.
-	err = goose.Down(sqlDB, "migrations")
+	err = goose.DownTo(sqlDB, "migrations", 20260521000000)
 	require.NoError(t, err)
 
 	_, err = sqlDB.ExecContext(ctx, "SELECT * FROM message_parts LIMIT 0")
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE messages ADD COLUMN prompt_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN completion_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN cost REAL NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE messages DROP COLUMN cost;
+ALTER TABLE messages DROP COLUMN completion_tokens;
+ALTER TABLE messages DROP COLUMN prompt_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS repo_map_session_overrides (
+    repo_key TEXT NOT NULL,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    target TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('pin', 'blacklist')),
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (repo_key, session_id, target)
+);
+CREATE INDEX IF NOT EXISTS idx_rmso_repo_session ON repo_map_session_overrides(repo_key, session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_rmso_repo_session;
+DROP TABLE IF EXISTS repo_map_session_overrides;
+-- +goose StatementEnd
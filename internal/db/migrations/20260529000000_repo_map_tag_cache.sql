@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tags and imports extracted from one version of a file, kept across
+-- restarts and branch switches so unchanged content is not parsed again.
+CREATE TABLE IF NOT EXISTS repo_map_tag_cache (
+    repo_key TEXT NOT NULL,
+    rel_path TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    parser_version TEXT NOT NULL,
+    language TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL,
+    imports TEXT NOT NULL,
+    accessed_at INTEGER NOT NULL,
+    PRIMARY KEY (repo_key, rel_path, content_hash, parser_version)
+);
+CREATE INDEX IF NOT EXISTS idx_rmtc_repo_accessed ON repo_map_tag_cache(repo_key, accessed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_rmtc_repo_accessed;
+DROP TABLE IF EXISTS repo_map_tag_cache;
+-- +goose StatementEnd
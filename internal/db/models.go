@@ -19,6 +19,23 @@ type EvalRun struct {
 	CreatedAt     int64   `json:"created_at"`
 }
 
+type ExplorerCache struct {
+	CacheKey   string `json:"cache_key"`
+	Result     string `json:"result"`
+	CreatedAt  int64  `json:"created_at"`
+	AccessedAt int64  `json:"accessed_at"`
+}
+
+type ExplorerMetric struct {
+	Explorer      string `json:"explorer"`
+	Explorations  int64  `json:"explorations"`
+	Fallbacks     int64  `json:"fallbacks"`
+	ParseFailures int64  `json:"parse_failures"`
+	DurationUs    int64  `json:"duration_us"`
+	Bytes         int64  `json:"bytes"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
 type File struct {
 	ID        string `json:"id"`
 	SessionID string `json:"session_id"`
@@ -175,6 +192,10 @@ type Message struct {
 	SentToLlmAt      int64          `json:"sent_to_llm_at"`
 	FirstTokenAt     int64          `json:"first_token_at"`
 	CompletedAt      int64          `json:"completed_at"`
+	PromptTokens     int64          `json:"prompt_tokens"`
+	CompletionTokens int64          `json:"completion_tokens"`
+	Cost             float64        `json:"cost"`
+	RoutingReason    string         `json:"routing_reason"`
 }
 
 type MessagePart struct {
@@ -214,6 +235,14 @@ type RepoMapImport struct {
 	CreatedAt  sql.NullTime `json:"created_at"`
 }
 
+type RepoMapSessionOverride struct {
+	RepoKey   string `json:"repo_key"`
+	SessionID string `json:"session_id"`
+	Target    string `json:"target"`
+	Kind      string `json:"kind"`
+	CreatedAt int64  `json:"created_at"`
+}
+
 type RepoMapSessionRanking struct {
 	RepoKey   string  `json:"repo_key"`
 	SessionID string  `json:"session_id"`
@@ -238,6 +267,17 @@ type RepoMapTag struct {
 	Language string `json:"language"`
 }
 
+type RepoMapTagCache struct {
+	RepoKey       string `json:"repo_key"`
+	RelPath       string `json:"rel_path"`
+	ContentHash   string `json:"content_hash"`
+	ParserVersion string `json:"parser_version"`
+	Language      string `json:"language"`
+	Tags          string `json:"tags"`
+	Imports       string `json:"imports"`
+	AccessedAt    int64  `json:"accessed_at"`
+}
+
 type ScorerResult struct {
 	ID          string  `json:"id"`
 	RunID       string  `json:"run_id"`
@@ -267,6 +307,14 @@ type Session struct {
 	Todos            sql.NullString `json:"todos"`
 }
 
+type SessionLock struct {
+	SessionID   string `json:"session_id"`
+	Owner       string `json:"owner"`
+	Pid         int64  `json:"pid"`
+	AcquiredAt  int64  `json:"acquired_at"`
+	HeartbeatAt int64  `json:"heartbeat_at"`
+}
+
 type SessionOperationalMemory struct {
 	SessionID string `json:"session_id"`
 	ThreadID  string `json:"thread_id"`
@@ -9,9 +9,13 @@ import (
 )
 
 type Querier interface {
+	AddExplorerMetrics(ctx context.Context, arg AddExplorerMetricsParams) error
 	// Snapshot file bridge
 	AddSnapshotFile(ctx context.Context, arg AddSnapshotFileParams) error
 	AppendLcmContextItem(ctx context.Context, arg AppendLcmContextItemParams) error
+	// Takes or renews the lock of a session. A lock held by another owner is
+	// only replaced once its heartbeat is older than stale_before.
+	ClaimSessionLock(ctx context.Context, arg ClaimSessionLockParams) (int64, error)
 	ClearSessionSummaryMessageID(ctx context.Context, id string) error
 	CloneSessionFiles(ctx context.Context, arg CloneSessionFilesParams) error
 	// Fork operations
@@ -38,6 +42,8 @@ type Querier interface {
 	DeleteSession(ctx context.Context, id string) error
 	DeleteSessionFiles(ctx context.Context, sessionID string) error
 	DeleteSessionMessages(ctx context.Context, sessionID string) error
+	DeleteSessionOverride(ctx context.Context, arg DeleteSessionOverrideParams) error
+	DeleteSessionOverrides(ctx context.Context, arg DeleteSessionOverridesParams) error
 	DeleteSessionRankings(ctx context.Context, arg DeleteSessionRankingsParams) error
 	DeleteSessionReadOnlyPaths(ctx context.Context, arg DeleteSessionReadOnlyPathsParams) error
 	DeleteSessionTurnSnapshots(ctx context.Context, sessionID string) error
@@ -48,6 +54,8 @@ type Querier interface {
 	GetContentReplacement(ctx context.Context, id int64) (LcmContentReplacement, error)
 	GetContentReplacementsByFileID(ctx context.Context, arg GetContentReplacementsByFileIDParams) ([]LcmContentReplacement, error)
 	GetContentReplacementsBySessionPosition(ctx context.Context, arg GetContentReplacementsBySessionPositionParams) ([]LcmContentReplacement, error)
+	// Returns the cached result and marks it as recently used.
+	GetExplorerCacheEntry(ctx context.Context, arg GetExplorerCacheEntryParams) (string, error)
 	GetFile(ctx context.Context, id string) (File, error)
 	GetFileByPathAndSession(ctx context.Context, arg GetFileByPathAndSessionParams) (File, error)
 	GetFileRead(ctx context.Context, arg GetFileReadParams) (ReadFile, error)
@@ -71,7 +79,12 @@ type Querier interface {
 	GetRecentActivity(ctx context.Context) ([]GetRecentActivityRow, error)
 	GetRepoMapFileCache(ctx context.Context, repoKey string) ([]RepoMapFileCache, error)
 	GetRepoMapFileCacheByPath(ctx context.Context, arg GetRepoMapFileCacheByPathParams) (RepoMapFileCache, error)
+	GetRepoMapTagCacheEntry(ctx context.Context, arg GetRepoMapTagCacheEntryParams) (GetRepoMapTagCacheEntryRow, error)
+	GetReportToolUsage(ctx context.Context, createdAt int64) ([]GetReportToolUsageRow, error)
+	GetReportTotals(ctx context.Context, createdAt int64) (GetReportTotalsRow, error)
+	GetReportUsageByModel(ctx context.Context, createdAt int64) ([]GetReportUsageByModelRow, error)
 	GetSessionByID(ctx context.Context, id string) (Session, error)
+	GetSessionLock(ctx context.Context, sessionID string) (SessionLock, error)
 	GetToolUsage(ctx context.Context) ([]GetToolUsageRow, error)
 	GetTotalStats(ctx context.Context) (GetTotalStatsRow, error)
 	GetTurnSnapshot(ctx context.Context, id string) (TurnSnapshot, error)
@@ -81,6 +94,13 @@ type Querier interface {
 	GetUsageByDayOfWeek(ctx context.Context) ([]GetUsageByDayOfWeekRow, error)
 	GetUsageByHour(ctx context.Context) ([]GetUsageByHourRow, error)
 	GetUsageByModel(ctx context.Context) ([]GetUsageByModelRow, error)
+	// Inserts a message exported from another database, keeping its ID,
+	// sequence and timestamps.
+	ImportMessage(ctx context.Context, arg ImportMessageParams) error
+	// Inserts a session exported from another database, keeping its ID and
+	// timestamps. message_count starts at zero and is counted by the message
+	// insert trigger.
+	ImportSession(ctx context.Context, arg ImportSessionParams) error
 	// LCM Context Items
 	InsertLcmContextItem(ctx context.Context, arg InsertLcmContextItemParams) error
 	// LCM Large Files
@@ -101,6 +121,7 @@ type Querier interface {
 	ListAllUserMessages(ctx context.Context) ([]Message, error)
 	ListContentReplacementsByRound(ctx context.Context, arg ListContentReplacementsByRoundParams) ([]LcmContentReplacement, error)
 	ListContentReplacementsByState(ctx context.Context, arg ListContentReplacementsByStateParams) ([]LcmContentReplacement, error)
+	ListExplorerMetrics(ctx context.Context) ([]ExplorerMetric, error)
 	ListFilesByPath(ctx context.Context, path string) ([]File, error)
 	ListFilesBySession(ctx context.Context, sessionID string) ([]File, error)
 	ListLatestSessionFiles(ctx context.Context, sessionID string) ([]File, error)
@@ -117,6 +138,7 @@ type Querier interface {
 	ListRecentSessionReadFiles(ctx context.Context, arg ListRecentSessionReadFilesParams) ([]ReadFile, error)
 	ListRepoMapDefsByName(ctx context.Context, arg ListRepoMapDefsByNameParams) ([]ListRepoMapDefsByNameRow, error)
 	ListRepoMapTags(ctx context.Context, repoKey string) ([]ListRepoMapTagsRow, error)
+	ListSessionOverrides(ctx context.Context, arg ListSessionOverridesParams) ([]RepoMapSessionOverride, error)
 	ListSessionRankings(ctx context.Context, arg ListSessionRankingsParams) ([]RepoMapSessionRanking, error)
 	ListSessionReadFiles(ctx context.Context, sessionID string) ([]ReadFile, error)
 	ListSessionReadOnlyPaths(ctx context.Context, arg ListSessionReadOnlyPathsParams) ([]string, error)
@@ -124,13 +146,21 @@ type Querier interface {
 	ListSessions(ctx context.Context) ([]Session, error)
 	ListSnapshotFiles(ctx context.Context, snapshotID string) ([]ListSnapshotFilesRow, error)
 	ListTurnSnapshotsBySession(ctx context.Context, sessionID string) ([]TurnSnapshot, error)
+	ListUnfinishedAssistantMessages(ctx context.Context, updatedAt int64) ([]Message, error)
 	ListUserMessagesBySession(ctx context.Context, sessionID string) ([]Message, error)
+	// Keeps the most recently used entries.
+	PruneExplorerCache(ctx context.Context, limit int64) error
+	// Keeps the most recently used entries of a repository.
+	PruneRepoMapTagCache(ctx context.Context, arg PruneRepoMapTagCacheParams) error
 	// LCM Content Replacements
 	RecordContentReplacement(ctx context.Context, arg RecordContentReplacementParams) (int64, error)
 	RecordFileRead(ctx context.Context, arg RecordFileReadParams) error
 	RecordFileWrite(ctx context.Context, arg RecordFileWriteParams) error
+	ReleaseSessionLock(ctx context.Context, arg ReleaseSessionLockParams) error
 	RenameSession(ctx context.Context, arg RenameSessionParams) error
+	RenewSessionLock(ctx context.Context, arg RenewSessionLockParams) (int64, error)
 	SearchLcmSummaries(ctx context.Context, arg SearchLcmSummariesParams) ([]SearchLcmSummariesRow, error)
+	TouchRepoMapTagCacheEntry(ctx context.Context, arg TouchRepoMapTagCacheEntryParams) error
 	UpdateContentReplacementState(ctx context.Context, arg UpdateContentReplacementStateParams) error
 	UpdateLcmLargeFileExploration(ctx context.Context, arg UpdateLcmLargeFileExplorationParams) error
 	UpdateLcmMapItem(ctx context.Context, arg UpdateLcmMapItemParams) error
@@ -141,9 +171,12 @@ type Querier interface {
 	UpdateMessageTokenCount(ctx context.Context, arg UpdateMessageTokenCountParams) error
 	UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error)
 	UpdateSessionTitleAndUsage(ctx context.Context, arg UpdateSessionTitleAndUsageParams) error
+	UpsertExplorerCacheEntry(ctx context.Context, arg UpsertExplorerCacheEntryParams) error
 	// LCM Session Config
 	UpsertLcmSessionConfig(ctx context.Context, arg UpsertLcmSessionConfigParams) error
 	UpsertRepoMapFileCache(ctx context.Context, arg UpsertRepoMapFileCacheParams) error
+	UpsertRepoMapTagCacheEntry(ctx context.Context, arg UpsertRepoMapTagCacheEntryParams) error
+	UpsertSessionOverride(ctx context.Context, arg UpsertSessionOverrideParams) error
 	UpsertSessionRanking(ctx context.Context, arg UpsertSessionRankingParams) error
 	UpsertSessionReadOnlyPath(ctx context.Context, arg UpsertSessionReadOnlyPathParams) error
 }
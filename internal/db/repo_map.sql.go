@@ -39,6 +39,37 @@ func (q *Queries) DeleteRepoMapTagsByPath(ctx context.Context, arg DeleteRepoMap
 	return err
 }
 
+const deleteSessionOverride = `-- name: DeleteSessionOverride :exec
+DELETE FROM repo_map_session_overrides
+WHERE repo_key = ? AND session_id = ? AND target = ?
+`
+
+type DeleteSessionOverrideParams struct {
+	RepoKey   string `json:"repo_key"`
+	SessionID string `json:"session_id"`
+	Target    string `json:"target"`
+}
+
+func (q *Queries) DeleteSessionOverride(ctx context.Context, arg DeleteSessionOverrideParams) error {
+	_, err := q.exec(ctx, q.deleteSessionOverrideStmt, deleteSessionOverride, arg.RepoKey, arg.SessionID, arg.Target)
+	return err
+}
+
+const deleteSessionOverrides = `-- name: DeleteSessionOverrides :exec
+DELETE FROM repo_map_session_overrides
+WHERE repo_key = ? AND session_id = ?
+`
+
+type DeleteSessionOverridesParams struct {
+	RepoKey   string `json:"repo_key"`
+	SessionID string `json:"session_id"`
+}
+
+func (q *Queries) DeleteSessionOverrides(ctx context.Context, arg DeleteSessionOverridesParams) error {
+	_, err := q.exec(ctx, q.deleteSessionOverridesStmt, deleteSessionOverrides, arg.RepoKey, arg.SessionID)
+	return err
+}
+
 const deleteSessionRankings = `-- name: DeleteSessionRankings :exec
 DELETE FROM repo_map_session_rankings
 WHERE repo_key = ? AND session_id = ?
@@ -128,6 +159,37 @@ func (q *Queries) GetRepoMapFileCacheByPath(ctx context.Context, arg GetRepoMapF
 	return i, err
 }
 
+const getRepoMapTagCacheEntry = `-- name: GetRepoMapTagCacheEntry :one
+SELECT language, tags, imports
+FROM repo_map_tag_cache
+WHERE repo_key = ? AND rel_path = ? AND content_hash = ? AND parser_version = ?
+`
+
+type GetRepoMapTagCacheEntryParams struct {
+	RepoKey       string `json:"repo_key"`
+	RelPath       string `json:"rel_path"`
+	ContentHash   string `json:"content_hash"`
+	ParserVersion string `json:"parser_version"`
+}
+
+type GetRepoMapTagCacheEntryRow struct {
+	Language string `json:"language"`
+	Tags     string `json:"tags"`
+	Imports  string `json:"imports"`
+}
+
+func (q *Queries) GetRepoMapTagCacheEntry(ctx context.Context, arg GetRepoMapTagCacheEntryParams) (GetRepoMapTagCacheEntryRow, error) {
+	row := q.queryRow(ctx, q.getRepoMapTagCacheEntryStmt, getRepoMapTagCacheEntry,
+		arg.RepoKey,
+		arg.RelPath,
+		arg.ContentHash,
+		arg.ParserVersion,
+	)
+	var i GetRepoMapTagCacheEntryRow
+	err := row.Scan(&i.Language, &i.Tags, &i.Imports)
+	return i, err
+}
+
 const insertRepoMapTag = `-- name: InsertRepoMapTag :exec
 INSERT INTO repo_map_tags (repo_key, rel_path, name, kind, node_type, line, language)
 VALUES (?, ?, ?, ?, ?, ?, ?)
@@ -253,6 +315,47 @@ func (q *Queries) ListRepoMapTags(ctx context.Context, repoKey string) ([]ListRe
 	return items, nil
 }
 
+const listSessionOverrides = `-- name: ListSessionOverrides :many
+SELECT repo_key, session_id, target, kind, created_at
+FROM repo_map_session_overrides
+WHERE repo_key = ? AND session_id = ?
+ORDER BY created_at, target
+`
+
+type ListSessionOverridesParams struct {
+	RepoKey   string `json:"repo_key"`
+	SessionID string `json:"session_id"`
+}
+
+func (q *Queries) ListSessionOverrides(ctx context.Context, arg ListSessionOverridesParams) ([]RepoMapSessionOverride, error) {
+	rows, err := q.query(ctx, q.listSessionOverridesStmt, listSessionOverrides, arg.RepoKey, arg.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RepoMapSessionOverride{}
+	for rows.Next() {
+		var i RepoMapSessionOverride
+		if err := rows.Scan(
+			&i.RepoKey,
+			&i.SessionID,
+			&i.Target,
+			&i.Kind,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSessionRankings = `-- name: ListSessionRankings :many
 SELECT repo_key, session_id, rel_path, rank
 FROM repo_map_session_rankings
@@ -328,6 +431,52 @@ func (q *Queries) ListSessionReadOnlyPaths(ctx context.Context, arg ListSessionR
 	return items, nil
 }
 
+const pruneRepoMapTagCache = `-- name: PruneRepoMapTagCache :exec
+DELETE FROM repo_map_tag_cache
+WHERE rowid IN (
+    SELECT rowid FROM repo_map_tag_cache
+    WHERE repo_key = ?
+    ORDER BY accessed_at DESC
+    LIMIT -1 OFFSET ?
+)
+`
+
+type PruneRepoMapTagCacheParams struct {
+	RepoKey string `json:"repo_key"`
+	Offset  int64  `json:"offset"`
+}
+
+// Keeps the most recently used entries of a repository.
+func (q *Queries) PruneRepoMapTagCache(ctx context.Context, arg PruneRepoMapTagCacheParams) error {
+	_, err := q.exec(ctx, q.pruneRepoMapTagCacheStmt, pruneRepoMapTagCache, arg.RepoKey, arg.Offset)
+	return err
+}
+
+const touchRepoMapTagCacheEntry = `-- name: TouchRepoMapTagCacheEntry :exec
+UPDATE repo_map_tag_cache
+SET accessed_at = ?
+WHERE repo_key = ? AND rel_path = ? AND content_hash = ? AND parser_version = ?
+`
+
+type TouchRepoMapTagCacheEntryParams struct {
+	AccessedAt    int64  `json:"accessed_at"`
+	RepoKey       string `json:"repo_key"`
+	RelPath       string `json:"rel_path"`
+	ContentHash   string `json:"content_hash"`
+	ParserVersion string `json:"parser_version"`
+}
+
+func (q *Queries) TouchRepoMapTagCacheEntry(ctx context.Context, arg TouchRepoMapTagCacheEntryParams) error {
+	_, err := q.exec(ctx, q.touchRepoMapTagCacheEntryStmt, touchRepoMapTagCacheEntry,
+		arg.AccessedAt,
+		arg.RepoKey,
+		arg.RelPath,
+		arg.ContentHash,
+		arg.ParserVersion,
+	)
+	return err
+}
+
 const upsertRepoMapFileCache = `-- name: UpsertRepoMapFileCache :exec
 INSERT INTO repo_map_file_cache (repo_key, rel_path, mtime, language, tag_count)
 VALUES (?, ?, ?, ?, ?)
@@ -353,6 +502,66 @@ func (q *Queries) UpsertRepoMapFileCache(ctx context.Context, arg UpsertRepoMapF
 	return err
 }
 
+const upsertRepoMapTagCacheEntry = `-- name: UpsertRepoMapTagCacheEntry :exec
+INSERT INTO repo_map_tag_cache (repo_key, rel_path, content_hash, parser_version, language, tags, imports, accessed_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(repo_key, rel_path, content_hash, parser_version) DO UPDATE SET
+    language = excluded.language,
+    tags = excluded.tags,
+    imports = excluded.imports,
+    accessed_at = excluded.accessed_at
+`
+
+type UpsertRepoMapTagCacheEntryParams struct {
+	RepoKey       string `json:"repo_key"`
+	RelPath       string `json:"rel_path"`
+	ContentHash   string `json:"content_hash"`
+	ParserVersion string `json:"parser_version"`
+	Language      string `json:"language"`
+	Tags          string `json:"tags"`
+	Imports       string `json:"imports"`
+	AccessedAt    int64  `json:"accessed_at"`
+}
+
+func (q *Queries) UpsertRepoMapTagCacheEntry(ctx context.Context, arg UpsertRepoMapTagCacheEntryParams) error {
+	_, err := q.exec(ctx, q.upsertRepoMapTagCacheEntryStmt, upsertRepoMapTagCacheEntry,
+		arg.RepoKey,
+		arg.RelPath,
+		arg.ContentHash,
+		arg.ParserVersion,
+		arg.Language,
+		arg.Tags,
+		arg.Imports,
+		arg.AccessedAt,
+	)
+	return err
+}
+
+const upsertSessionOverride = `-- name: UpsertSessionOverride :exec
+INSERT INTO repo_map_session_overrides (repo_key, session_id, target, kind, created_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(repo_key, session_id, target) DO UPDATE SET kind = excluded.kind, created_at = excluded.created_at
+`
+
+type UpsertSessionOverrideParams struct {
+	RepoKey   string `json:"repo_key"`
+	SessionID string `json:"session_id"`
+	Target    string `json:"target"`
+	Kind      string `json:"kind"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func (q *Queries) UpsertSessionOverride(ctx context.Context, arg UpsertSessionOverrideParams) error {
+	_, err := q.exec(ctx, q.upsertSessionOverrideStmt, upsertSessionOverride,
+		arg.RepoKey,
+		arg.SessionID,
+		arg.Target,
+		arg.Kind,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const upsertSessionRanking = `-- name: UpsertSessionRanking :exec
 INSERT INTO repo_map_session_rankings (repo_key, session_id, rel_path, rank)
 VALUES (?, ?, ?, ?)
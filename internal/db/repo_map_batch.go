@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// This file is hand-written: sqlc has no batch form for SQLite, so these
+// helpers reuse the generated queries through one prepared statement each.
+// Call them on a Queries from WithTx so the batch commits atomically.
+
+// UpsertSessionRankings upserts every ranking with a single prepared
+// statement.
+func (q *Queries) UpsertSessionRankings(ctx context.Context, args []UpsertSessionRankingParams) error {
+	if len(args) == 0 {
+		return nil
+	}
+	stmt, err := q.db.PrepareContext(ctx, upsertSessionRanking)
+	if err != nil {
+		return fmt.Errorf("prepare upsert session ranking: %w", err)
+	}
+	defer stmt.Close()
+	for _, arg := range args {
+		if _, err := stmt.ExecContext(ctx, arg.RepoKey, arg.SessionID, arg.RelPath, arg.Rank); err != nil {
+			return fmt.Errorf("upsert session ranking %q: %w", arg.RelPath, err)
+		}
+	}
+	return nil
+}
+
+// UpsertSessionReadOnlyPaths upserts every read-only path with a single
+// prepared statement.
+func (q *Queries) UpsertSessionReadOnlyPaths(ctx context.Context, args []UpsertSessionReadOnlyPathParams) error {
+	if len(args) == 0 {
+		return nil
+	}
+	stmt, err := q.db.PrepareContext(ctx, upsertSessionReadOnlyPath)
+	if err != nil {
+		return fmt.Errorf("prepare upsert session read-only path: %w", err)
+	}
+	defer stmt.Close()
+	for _, arg := range args {
+		if _, err := stmt.ExecContext(ctx, arg.RepoKey, arg.SessionID, arg.RelPath); err != nil {
+			return fmt.Errorf("upsert session read-only path %q: %w", arg.RelPath, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Settings tunes the SQLite connection. Zero fields keep the defaults in
+// [pragmas] and a single pooled connection.
+type Settings struct {
+	// JournalMode is the journal_mode pragma: WAL, DELETE, TRUNCATE,
+	// PERSIST, MEMORY or OFF.
+	JournalMode string
+	// BusyTimeout is how long a statement waits on a locked database
+	// before failing with SQLITE_BUSY.
+	BusyTimeout time.Duration
+	// CacheSizeKiB is the page cache size in KiB.
+	CacheSizeKiB int
+	// MmapSize is the number of bytes of the database file to memory map.
+	MmapSize int64
+	// ConnMaxIdleTime closes pooled connections idle for longer.
+	ConnMaxIdleTime time.Duration
+}
+
+// WithSettings tunes the pragmas and pool of the connection. It only
+// applies to the Connect call that opens the database; later calls for
+// the same data directory share the open connection as is.
+func WithSettings(s Settings) ConnectOption {
+	return func(o *connectOptions) { o.settings = s }
+}
+
+var journalModes = map[string]bool{
+	"WAL": true, "DELETE": true, "TRUNCATE": true,
+	"PERSIST": true, "MEMORY": true, "OFF": true,
+}
+
+// pragmas returns the default pragmas overridden by s.
+func (s Settings) pragmas() (map[string]string, error) {
+	out := maps.Clone(pragmas)
+	if s.JournalMode != "" {
+		mode := strings.ToUpper(strings.TrimSpace(s.JournalMode))
+		if !journalModes[mode] {
+			return nil, fmt.Errorf("invalid journal mode %q", s.JournalMode)
+		}
+		out["journal_mode"] = mode
+	}
+	if s.BusyTimeout < 0 || s.CacheSizeKiB < 0 || s.MmapSize < 0 {
+		return nil, fmt.Errorf("database settings must not be negative")
+	}
+	if s.BusyTimeout > 0 {
+		out["busy_timeout"] = strconv.FormatInt(s.BusyTimeout.Milliseconds(), 10)
+	}
+	if s.CacheSizeKiB > 0 {
+		// A negative cache_size is a size in KiB rather than in pages.
+		out["cache_size"] = strconv.Itoa(-s.CacheSizeKiB)
+	}
+	if s.MmapSize > 0 {
+		out["mmap_size"] = strconv.FormatInt(s.MmapSize, 10)
+	}
+	return out, nil
+}
+
+// Health reports the effective settings and pool state of a connection.
+type Health struct {
+	JournalMode  string
+	BusyTimeout  time.Duration
+	CacheSize    int64
+	MmapSize     int64
+	Stats        sql.DBStats
+	PingDuration time.Duration
+}
+
+// CheckHealth pings conn and reads back its pragmas. An error means the
+// database did not answer; Stats.WaitCount and WaitDuration show whether
+// callers queue for the pool.
+func CheckHealth(ctx context.Context, conn *sql.DB) (Health, error) {
+	var h Health
+	start := time.Now()
+	if err := conn.PingContext(ctx); err != nil {
+		return h, fmt.Errorf("database ping failed: %w", err)
+	}
+	h.PingDuration = time.Since(start)
+
+	if err := conn.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&h.JournalMode); err != nil {
+		return h, fmt.Errorf("failed to read journal_mode: %w", err)
+	}
+	var busyMillis int64
+	if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyMillis); err != nil {
+		return h, fmt.Errorf("failed to read busy_timeout: %w", err)
+	}
+	h.BusyTimeout = time.Duration(busyMillis) * time.Millisecond
+	if err := conn.QueryRowContext(ctx, "PRAGMA cache_size").Scan(&h.CacheSize); err != nil {
+		return h, fmt.Errorf("failed to read cache_size: %w", err)
+	}
+	if err := conn.QueryRowContext(ctx, "PRAGMA mmap_size").Scan(&h.MmapSize); err != nil {
+		return h, fmt.Errorf("failed to read mmap_size: %w", err)
+	}
+	h.JournalMode = strings.ToUpper(h.JournalMode)
+	h.Stats = conn.Stats()
+	return h, nil
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnect_WithSettings(t *testing.T) {
+	t.Cleanup(ResetPool)
+
+	dataDir := t.TempDir()
+	conn, err := Connect(context.Background(), dataDir, WithSettings(Settings{
+		JournalMode:  "truncate",
+		BusyTimeout:  5 * time.Second,
+		CacheSizeKiB: 16000,
+		MmapSize:     1 << 20,
+	}))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = Release(dataDir) })
+
+	health, err := CheckHealth(context.Background(), conn)
+	require.NoError(t, err)
+	require.Equal(t, "TRUNCATE", health.JournalMode)
+	require.Equal(t, 5*time.Second, health.BusyTimeout)
+	require.Equal(t, int64(-16000), health.CacheSize)
+	require.Equal(t, int64(1<<20), health.MmapSize)
+	// Settings never lift the single connection guard.
+	require.Equal(t, 1, health.Stats.MaxOpenConnections)
+}
+
+func TestConnect_DefaultSettings(t *testing.T) {
+	t.Cleanup(ResetPool)
+
+	dataDir := t.TempDir()
+	conn, err := Connect(context.Background(), dataDir)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = Release(dataDir) })
+
+	health, err := CheckHealth(context.Background(), conn)
+	require.NoError(t, err)
+	require.Equal(t, "WAL", health.JournalMode)
+	require.Equal(t, 30*time.Second, health.BusyTimeout)
+	require.Equal(t, 1, health.Stats.MaxOpenConnections)
+}
+
+func TestConnect_InvalidSettings(t *testing.T) {
+	t.Cleanup(ResetPool)
+
+	_, err := Connect(context.Background(), t.TempDir(), WithSettings(Settings{JournalMode: "fast"}))
+	require.ErrorContains(t, err, "invalid journal mode")
+	_, err = Connect(context.Background(), t.TempDir(), WithSettings(Settings{BusyTimeout: -time.Second}))
+	require.Error(t, err)
+}
+
+func TestCheckHealth_ClosedConnection(t *testing.T) {
+	t.Cleanup(ResetPool)
+
+	dataDir := t.TempDir()
+	conn, err := Connect(context.Background(), dataDir)
+	require.NoError(t, err)
+	require.NoError(t, Release(dataDir))
+
+	_, err = CheckHealth(context.Background(), conn)
+	require.Error(t, err)
+}
@@ -0,0 +1,22 @@
+-- name: GetExplorerCacheEntry :one
+-- Returns the cached result and marks it as recently used.
+UPDATE explorer_cache
+SET accessed_at = ?
+WHERE cache_key = ?
+RETURNING result;
+
+-- name: UpsertExplorerCacheEntry :exec
+INSERT INTO explorer_cache (cache_key, result, created_at, accessed_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(cache_key) DO UPDATE SET
+    result = excluded.result,
+    accessed_at = excluded.accessed_at;
+
+-- name: PruneExplorerCache :exec
+-- Keeps the most recently used entries.
+DELETE FROM explorer_cache
+WHERE cache_key NOT IN (
+    SELECT cache_key FROM explorer_cache
+    ORDER BY accessed_at DESC
+    LIMIT ?
+);
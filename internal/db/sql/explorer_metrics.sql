@@ -0,0 +1,15 @@
+-- name: AddExplorerMetrics :exec
+INSERT INTO explorer_metrics (explorer, explorations, fallbacks, parse_failures, duration_us, bytes, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(explorer) DO UPDATE SET
+    explorations = explorations + excluded.explorations,
+    fallbacks = fallbacks + excluded.fallbacks,
+    parse_failures = parse_failures + excluded.parse_failures,
+    duration_us = duration_us + excluded.duration_us,
+    bytes = bytes + excluded.bytes,
+    updated_at = excluded.updated_at;
+
+-- name: ListExplorerMetrics :many
+SELECT explorer, explorations, fallbacks, parse_failures, duration_us, bytes, updated_at
+FROM explorer_metrics
+ORDER BY explorations DESC, explorer;
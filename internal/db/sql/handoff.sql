@@ -0,0 +1,43 @@
+-- name: ImportSession :exec
+-- Inserts a session exported from another database, keeping its ID and
+-- timestamps. message_count starts at zero and is counted by the message
+-- insert trigger.
+INSERT INTO sessions (
+    id,
+    parent_session_id,
+    title,
+    message_count,
+    prompt_tokens,
+    completion_tokens,
+    cost,
+    summary_message_id,
+    todos,
+    updated_at,
+    created_at
+) VALUES (?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportMessage :exec
+-- Inserts a message exported from another database, keeping its ID,
+-- sequence and timestamps.
+INSERT INTO messages (
+    id,
+    session_id,
+    role,
+    parts,
+    model,
+    provider,
+    is_summary_message,
+    seq,
+    token_count,
+    created_at,
+    updated_at,
+    finished_at,
+    submitted_at,
+    sent_to_llm_at,
+    first_token_at,
+    completed_at,
+    prompt_tokens,
+    completion_tokens,
+    cost,
+    routing_reason
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
@@ -21,12 +21,13 @@ INSERT INTO messages (
     seq,
     created_at,
     updated_at,
-    submitted_at
+    submitted_at,
+    routing_reason
 ) VALUES (
     ?, ?, ?, ?, ?, ?, ?,
     (SELECT COALESCE(MAX(m.seq) + 1, 0) FROM messages m WHERE m.session_id = ?),
     strftime('%s', 'now'), strftime('%s', 'now'),
-    ?
+    ?, ?
 )
 RETURNING *;
 
@@ -38,6 +39,9 @@ SET
     sent_to_llm_at = ?,
     first_token_at = ?,
     completed_at = ?,
+    prompt_tokens = ?,
+    completion_tokens = ?,
+    cost = ?,
     updated_at = strftime('%s', 'now')
 WHERE id = ?;
 
@@ -61,3 +65,9 @@ SELECT *
 FROM messages
 WHERE role = 'user'
 ORDER BY created_at DESC;
+
+-- name: ListUnfinishedAssistantMessages :many
+SELECT *
+FROM messages
+WHERE role = 'assistant' AND finished_at IS NULL AND updated_at < ?
+ORDER BY created_at ASC;
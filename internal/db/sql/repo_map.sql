@@ -61,6 +61,54 @@ ORDER BY rel_path;
 DELETE FROM repo_map_session_read_only
 WHERE repo_key = ? AND session_id = ?;
 
+-- name: UpsertSessionOverride :exec
+INSERT INTO repo_map_session_overrides (repo_key, session_id, target, kind, created_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(repo_key, session_id, target) DO UPDATE SET kind = excluded.kind, created_at = excluded.created_at;
+
+-- name: ListSessionOverrides :many
+SELECT repo_key, session_id, target, kind, created_at
+FROM repo_map_session_overrides
+WHERE repo_key = ? AND session_id = ?
+ORDER BY created_at, target;
+
+-- name: DeleteSessionOverride :exec
+DELETE FROM repo_map_session_overrides
+WHERE repo_key = ? AND session_id = ? AND target = ?;
+
+-- name: DeleteSessionOverrides :exec
+DELETE FROM repo_map_session_overrides
+WHERE repo_key = ? AND session_id = ?;
+
 -- name: DeleteRepoMapFileCache :exec
 DELETE FROM repo_map_file_cache
 WHERE repo_key = ? AND rel_path = ?;
+
+-- name: GetRepoMapTagCacheEntry :one
+SELECT language, tags, imports
+FROM repo_map_tag_cache
+WHERE repo_key = ? AND rel_path = ? AND content_hash = ? AND parser_version = ?;
+
+-- name: TouchRepoMapTagCacheEntry :exec
+UPDATE repo_map_tag_cache
+SET accessed_at = ?
+WHERE repo_key = ? AND rel_path = ? AND content_hash = ? AND parser_version = ?;
+
+-- name: UpsertRepoMapTagCacheEntry :exec
+INSERT INTO repo_map_tag_cache (repo_key, rel_path, content_hash, parser_version, language, tags, imports, accessed_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(repo_key, rel_path, content_hash, parser_version) DO UPDATE SET
+    language = excluded.language,
+    tags = excluded.tags,
+    imports = excluded.imports,
+    accessed_at = excluded.accessed_at;
+
+-- name: PruneRepoMapTagCache :exec
+-- Keeps the most recently used entries of a repository.
+DELETE FROM repo_map_tag_cache
+WHERE rowid IN (
+    SELECT rowid FROM repo_map_tag_cache
+    WHERE repo_key = ?
+    ORDER BY accessed_at DESC
+    LIMIT -1 OFFSET ?
+);
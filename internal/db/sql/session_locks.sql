@@ -0,0 +1,28 @@
+-- name: ClaimSessionLock :execrows
+-- Takes or renews the lock of a session. A lock held by another owner is
+-- only replaced once its heartbeat is older than stale_before.
+INSERT INTO session_locks (session_id, owner, pid, acquired_at, heartbeat_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(session_id) DO UPDATE SET
+    owner = excluded.owner,
+    pid = excluded.pid,
+    acquired_at = CASE
+        WHEN session_locks.owner = excluded.owner THEN session_locks.acquired_at
+        ELSE excluded.acquired_at
+    END,
+    heartbeat_at = excluded.heartbeat_at
+WHERE session_locks.owner = excluded.owner
+    OR session_locks.heartbeat_at < sqlc.arg(stale_before);
+
+-- name: GetSessionLock :one
+SELECT * FROM session_locks
+WHERE session_id = ?;
+
+-- name: ReleaseSessionLock :exec
+DELETE FROM session_locks
+WHERE session_id = ? AND owner = ?;
+
+-- name: RenewSessionLock :execrows
+UPDATE session_locks
+SET heartbeat_at = ?
+WHERE session_id = ? AND owner = ?;
@@ -14,7 +14,10 @@ ORDER BY day DESC;
 SELECT
     COALESCE(model, 'unknown') as model,
     COALESCE(provider, 'unknown') as provider,
-    COUNT(*) as message_count
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(prompt_tokens), 0) AS INTEGER) as prompt_tokens,
+    CAST(COALESCE(SUM(completion_tokens), 0) AS INTEGER) as completion_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
 FROM messages
 WHERE role = 'assistant'
 GROUP BY model, provider
@@ -91,3 +94,39 @@ FROM sessions
 WHERE parent_session_id IS NULL
 GROUP BY day_of_week, hour
 ORDER BY day_of_week, hour;
+
+-- name: GetReportTotals :one
+SELECT
+    COUNT(*) as session_count,
+    CAST(COALESCE(SUM(prompt_tokens), 0) AS INTEGER) as prompt_tokens,
+    CAST(COALESCE(SUM(completion_tokens), 0) AS INTEGER) as completion_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost,
+    CAST(COALESCE(SUM(message_count), 0) AS INTEGER) as message_count
+FROM sessions
+WHERE parent_session_id IS NULL
+  AND created_at >= ?;
+
+-- name: GetReportUsageByModel :many
+SELECT
+    COALESCE(model, 'unknown') as model,
+    COALESCE(provider, 'unknown') as provider,
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(prompt_tokens), 0) AS INTEGER) as prompt_tokens,
+    CAST(COALESCE(SUM(completion_tokens), 0) AS INTEGER) as completion_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
+FROM messages
+WHERE role = 'assistant'
+  AND created_at >= ?
+GROUP BY model, provider
+ORDER BY message_count DESC;
+
+-- name: GetReportToolUsage :many
+SELECT
+    CAST(json_extract(value, '$.data.name') AS TEXT) as tool_name,
+    COUNT(*) as call_count
+FROM messages, json_each(parts)
+WHERE json_extract(value, '$.type') = 'tool_call'
+  AND json_extract(value, '$.data.name') IS NOT NULL
+  AND messages.created_at >= ?
+GROUP BY tool_name
+ORDER BY call_count DESC;
@@ -330,7 +330,10 @@ const getUsageByModel = `-- name: GetUsageByModel :many
 SELECT
     COALESCE(model, 'unknown') as model,
     COALESCE(provider, 'unknown') as provider,
-    COUNT(*) as message_count
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(prompt_tokens), 0) AS INTEGER) as prompt_tokens,
+    CAST(COALESCE(SUM(completion_tokens), 0) AS INTEGER) as completion_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
 FROM messages
 WHERE role = 'assistant'
 GROUP BY model, provider
@@ -338,9 +341,12 @@ ORDER BY message_count DESC
 `
 
 type GetUsageByModelRow struct {
-	Model        string `json:"model"`
-	Provider     string `json:"provider"`
-	MessageCount int64  `json:"message_count"`
+	Model            string  `json:"model"`
+	Provider         string  `json:"provider"`
+	MessageCount     int64   `json:"message_count"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
 }
 
 func (q *Queries) GetUsageByModel(ctx context.Context) ([]GetUsageByModelRow, error) {
@@ -352,7 +358,141 @@ func (q *Queries) GetUsageByModel(ctx context.Context) ([]GetUsageByModelRow, er
 	items := []GetUsageByModelRow{}
 	for rows.Next() {
 		var i GetUsageByModelRow
-		if err := rows.Scan(&i.Model, &i.Provider, &i.MessageCount); err != nil {
+		if err := rows.Scan(
+			&i.Model,
+			&i.Provider,
+			&i.MessageCount,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReportTotals = `-- name: GetReportTotals :one
+SELECT
+    COUNT(*) as session_count,
+    CAST(COALESCE(SUM(prompt_tokens), 0) AS INTEGER) as prompt_tokens,
+    CAST(COALESCE(SUM(completion_tokens), 0) AS INTEGER) as completion_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost,
+    CAST(COALESCE(SUM(message_count), 0) AS INTEGER) as message_count
+FROM sessions
+WHERE parent_session_id IS NULL
+  AND created_at >= ?
+`
+
+type GetReportTotalsRow struct {
+	SessionCount     int64   `json:"session_count"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+	MessageCount     int64   `json:"message_count"`
+}
+
+func (q *Queries) GetReportTotals(ctx context.Context, createdAt int64) (GetReportTotalsRow, error) {
+	row := q.queryRow(ctx, q.getReportTotalsStmt, getReportTotals, createdAt)
+	var i GetReportTotalsRow
+	err := row.Scan(
+		&i.SessionCount,
+		&i.PromptTokens,
+		&i.CompletionTokens,
+		&i.Cost,
+		&i.MessageCount,
+	)
+	return i, err
+}
+
+const getReportUsageByModel = `-- name: GetReportUsageByModel :many
+SELECT
+    COALESCE(model, 'unknown') as model,
+    COALESCE(provider, 'unknown') as provider,
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(prompt_tokens), 0) AS INTEGER) as prompt_tokens,
+    CAST(COALESCE(SUM(completion_tokens), 0) AS INTEGER) as completion_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
+FROM messages
+WHERE role = 'assistant'
+  AND created_at >= ?
+GROUP BY model, provider
+ORDER BY message_count DESC
+`
+
+type GetReportUsageByModelRow struct {
+	Model            string  `json:"model"`
+	Provider         string  `json:"provider"`
+	MessageCount     int64   `json:"message_count"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+func (q *Queries) GetReportUsageByModel(ctx context.Context, createdAt int64) ([]GetReportUsageByModelRow, error) {
+	rows, err := q.query(ctx, q.getReportUsageByModelStmt, getReportUsageByModel, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetReportUsageByModelRow{}
+	for rows.Next() {
+		var i GetReportUsageByModelRow
+		if err := rows.Scan(
+			&i.Model,
+			&i.Provider,
+			&i.MessageCount,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReportToolUsage = `-- name: GetReportToolUsage :many
+SELECT
+    CAST(json_extract(value, '$.data.name') AS TEXT) as tool_name,
+    COUNT(*) as call_count
+FROM messages, json_each(parts)
+WHERE json_extract(value, '$.type') = 'tool_call'
+  AND json_extract(value, '$.data.name') IS NOT NULL
+  AND messages.created_at >= ?
+GROUP BY tool_name
+ORDER BY call_count DESC
+`
+
+type GetReportToolUsageRow struct {
+	ToolName  string `json:"tool_name"`
+	CallCount int64  `json:"call_count"`
+}
+
+func (q *Queries) GetReportToolUsage(ctx context.Context, createdAt int64) ([]GetReportToolUsageRow, error) {
+	rows, err := q.query(ctx, q.getReportToolUsageStmt, getReportToolUsage, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetReportToolUsageRow{}
+	for rows.Next() {
+		var i GetReportToolUsageRow
+		if err := rows.Scan(&i.ToolName, &i.CallCount); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -0,0 +1,296 @@
+package dbschema
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	alembicRevision     = regexp.MustCompile(`(?m)^revision\s*(?::[^=]*)?=\s*['"]([\w-]+)['"]`)
+	alembicDownRevision = regexp.MustCompile(`(?m)^down_revision\s*(?::[^=]*)?=\s*(.*)$`)
+	pyStringLiteral     = regexp.MustCompile(`['"]([^'"]*)['"]`)
+	// pyOpCall matches the start of an operation: op.add_column( or, in a
+	// batch, batch_op.add_column(.
+	pyOpCall = regexp.MustCompile(`\b(\w+)\.(\w+)\(`)
+	// pyBatch matches a batch operation and its alias:
+	// with op.batch_alter_table("users") as batch_op:.
+	pyBatch = regexp.MustCompile(`batch_alter_table\(\s*['"]([\w.]+)['"][^:]*\bas\s+(\w+)`)
+	// pyCall matches a call such as sa.Column( or ForeignKey(, capturing the
+	// function's name.
+	pyCall       = regexp.MustCompile(`^(?:\w+\.)*(\w+)\(`)
+	pyModulePath = regexp.MustCompile(`^(?:\w+\.)+`)
+	pyKeyword    = regexp.MustCompile(`^(\w+)\s*=\s*(.*)$`)
+)
+
+// alembicOrder orders revisions by their down_revision chain, breaking ties
+// and cycles by path.
+func alembicOrder(ms []migration) []migration {
+	slices.SortFunc(ms, func(a, b migration) int { return strings.Compare(a.path, b.path) })
+	revs := make(map[string]bool)
+	parents := make([][]string, len(ms))
+	for i, m := range ms {
+		if r := alembicRevision.FindStringSubmatch(m.content); r != nil {
+			revs[r[1]] = true
+		}
+		if d := alembicDownRevision.FindStringSubmatch(m.content); d != nil {
+			for _, p := range pyStringLiteral.FindAllStringSubmatch(d[1], -1) {
+				parents[i] = append(parents[i], p[1])
+			}
+		}
+	}
+
+	ordered := make([]migration, 0, len(ms))
+	applied := make(map[string]bool)
+	done := make([]bool, len(ms))
+	for len(ordered) < len(ms) {
+		progressed := false
+		for i, m := range ms {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for _, p := range parents[i] {
+				if revs[p] && !applied[p] {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+			done[i], progressed = true, true
+			ordered = append(ordered, m)
+			if r := alembicRevision.FindStringSubmatch(m.content); r != nil {
+				applied[r[1]] = true
+			}
+		}
+		if !progressed {
+			for i, m := range ms {
+				if !done[i] {
+					done[i] = true
+					ordered = append(ordered, m)
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+// applyAlembic replays the operations of an Alembic revision's upgrade
+// function. It reports false for Python files that are not revisions.
+func applyAlembic(b *builder, source, content string) bool {
+	if !alembicRevision.MatchString(content) {
+		return false
+	}
+	body := pythonFunctionBody(content, "upgrade")
+	batches := make(map[string]string)
+	for _, m := range pyBatch.FindAllStringSubmatch(body, -1) {
+		batches[m[2]] = m[1]
+	}
+
+	for _, loc := range pyOpCall.FindAllStringSubmatchIndex(body, -1) {
+		recv, method := body[loc[2]:loc[3]], body[loc[4]:loc[5]]
+		args := splitTopLevel(parenBody(body[loc[1]:]))
+		if recv != "op" {
+			table, ok := batches[recv]
+			if !ok {
+				continue
+			}
+			// Batch operations leave out the table, which constraint
+			// operations name after the constraint.
+			at := 0
+			if method == "create_foreign_key" || method == "create_primary_key" {
+				at = min(1, len(args))
+			}
+			args = slices.Insert(args, at, `"`+table+`"`)
+		}
+		applyAlembicOp(b, source, method, args)
+	}
+	return true
+}
+
+// applyAlembicOp applies one operation, with the table as its first
+// argument.
+func applyAlembicOp(b *builder, source, method string, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	name := pyString(args[0])
+	switch method {
+	case "create_table":
+		t := b.createTable(name, source, nil)
+		for _, arg := range args[1:] {
+			applyAlembicTableArg(t, arg)
+		}
+	case "drop_table":
+		b.dropTable(name)
+	case "rename_table":
+		if len(args) > 1 {
+			b.renameTable(name, pyString(args[1]), source)
+		}
+	case "add_column":
+		if len(args) > 1 {
+			applyAlembicTableArg(b.table(name, source), args[1])
+		}
+	case "drop_column":
+		if len(args) > 1 {
+			b.table(name, source).dropColumn(pyString(args[1]))
+		}
+	case "alter_column":
+		if len(args) < 2 {
+			return
+		}
+		t := b.table(name, source)
+		c := t.column(pyString(args[1]))
+		if c == nil {
+			return
+		}
+		for _, arg := range args[2:] {
+			kw := pyKeyword.FindStringSubmatch(arg)
+			if kw == nil {
+				continue
+			}
+			switch kw[1] {
+			case "new_column_name":
+				c.Name = pyString(kw[2])
+			case "type_":
+				c.Type = pyType(kw[2])
+			case "nullable":
+				c.NotNull = kw[2] == "False"
+			}
+		}
+	case "create_foreign_key":
+		// op.create_foreign_key(name, source, referent, local_cols,
+		// remote_cols)
+		if len(args) < 5 || pyString(args[1]) == "" {
+			return
+		}
+		referent := pyString(args[2])
+		t := b.table(pyString(args[1]), source)
+		locals, remotes := pyStrings(args[3]), pyStrings(args[4])
+		for i, col := range locals {
+			ref := referent
+			if i < len(remotes) {
+				ref += "." + remotes[i]
+			}
+			t.setReference(col, ref)
+		}
+	case "create_primary_key":
+		if len(args) > 2 {
+			b.table(pyString(args[1]), source).setPrimaryKey(pyStrings(args[2]))
+		}
+	}
+}
+
+// applyAlembicTableArg applies a Column or constraint argument of
+// create_table or add_column.
+func applyAlembicTableArg(t *Table, arg string) {
+	m := pyCall.FindStringSubmatch(arg)
+	if m == nil {
+		return
+	}
+	args := splitTopLevel(parenBody(arg[len(m[0]):]))
+	switch m[1] {
+	case "Column":
+		if c, ok := parseAlembicColumn(args); ok {
+			t.addColumn(c)
+		}
+	case "PrimaryKeyConstraint":
+		var cols []string
+		for _, a := range args {
+			if s := pyString(a); s != "" {
+				cols = append(cols, s)
+			}
+		}
+		t.setPrimaryKey(cols)
+	case "ForeignKeyConstraint":
+		if len(args) < 2 {
+			return
+		}
+		remotes := pyStrings(args[1])
+		for i, col := range pyStrings(args[0]) {
+			if i < len(remotes) {
+				t.setReference(col, remotes[i])
+			}
+		}
+	}
+}
+
+// parseAlembicColumn parses the arguments of sa.Column: its name, type,
+// foreign key and nullable and primary_key keywords.
+func parseAlembicColumn(args []string) (Column, bool) {
+	if len(args) == 0 {
+		return Column{}, false
+	}
+	c := Column{Name: pyString(args[0])}
+	if c.Name == "" {
+		return Column{}, false
+	}
+	nullable := ""
+	for _, arg := range args[1:] {
+		if kw := pyKeyword.FindStringSubmatch(arg); kw != nil {
+			switch kw[1] {
+			case "nullable":
+				nullable = kw[2]
+			case "primary_key":
+				c.PrimaryKey = kw[2] == "True"
+			case "type_":
+				c.Type = pyType(kw[2])
+			}
+			continue
+		}
+		if m := pyCall.FindStringSubmatch(arg); m != nil && m[1] == "ForeignKey" {
+			c.References = pyString(parenBody(arg[len(m[0]):]))
+			continue
+		}
+		if c.Type == "" {
+			c.Type = pyType(arg)
+		}
+	}
+	c.NotNull = nullable == "False" || (c.PrimaryKey && nullable != "True")
+	return c, true
+}
+
+// pythonFunctionBody returns the body of the top-level function name, or
+// "" when there is none.
+func pythonFunctionBody(content, name string) string {
+	start := strings.Index(content, "\ndef "+name+"(")
+	if start < 0 {
+		if !strings.HasPrefix(content, "def "+name+"(") {
+			return ""
+		}
+	} else {
+		start++
+	}
+	body := content[start:]
+	if end := strings.Index(body, "\ndef "); end >= 0 {
+		body = body[:end]
+	}
+	return body
+}
+
+// pyString returns the value of a string literal, or "".
+func pyString(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if len(arg) < 2 || (arg[0] != '"' && arg[0] != '\'') || arg[len(arg)-1] != arg[0] {
+		return ""
+	}
+	return arg[1 : len(arg)-1]
+}
+
+// pyStrings returns the string literals of a list literal.
+func pyStrings(arg string) []string {
+	var values []string
+	for _, m := range pyStringLiteral.FindAllStringSubmatch(arg, -1) {
+		values = append(values, m[1])
+	}
+	return values
+}
+
+// pyType shortens a SQLAlchemy type: sa.String(length=255) is
+// String(length=255).
+func pyType(arg string) string {
+	arg = pyModulePath.ReplaceAllString(strings.TrimSpace(arg), "")
+	return strings.TrimSuffix(arg, "()")
+}
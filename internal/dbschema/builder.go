@@ -0,0 +1,115 @@
+package dbschema
+
+import (
+	"slices"
+	"strings"
+)
+
+// builder accumulates the tables of a schema as migrations change them.
+// Tables and columns are matched ignoring case, like unquoted SQL
+// identifiers.
+type builder struct {
+	byName map[string]*Table
+}
+
+func newBuilder() *builder {
+	return &builder{byName: make(map[string]*Table)}
+}
+
+// tables returns the tables in name order.
+func (b *builder) tables() []*Table {
+	tables := make([]*Table, 0, len(b.byName))
+	for _, key := range sortedKeys(b.byName) {
+		tables = append(tables, b.byName[key])
+	}
+	return tables
+}
+
+// table returns the named table, creating it when a migration alters a
+// table the replayed migrations never created.
+func (b *builder) table(name, source string) *Table {
+	key := strings.ToLower(name)
+	t, ok := b.byName[key]
+	if !ok {
+		t = &Table{Name: name}
+		b.byName[key] = t
+	}
+	if !slices.Contains(t.Migrations, source) {
+		t.Migrations = append(t.Migrations, source)
+	}
+	return t
+}
+
+// createTable replaces any table of the same name.
+func (b *builder) createTable(name, source string, columns []Column) *Table {
+	delete(b.byName, strings.ToLower(name))
+	t := b.table(name, source)
+	for _, c := range columns {
+		t.addColumn(c)
+	}
+	return t
+}
+
+func (b *builder) dropTable(name string) {
+	delete(b.byName, strings.ToLower(name))
+}
+
+func (b *builder) renameTable(from, to, source string) {
+	t, ok := b.byName[strings.ToLower(from)]
+	if !ok {
+		return
+	}
+	delete(b.byName, strings.ToLower(from))
+	t.Name = to
+	b.byName[strings.ToLower(to)] = t
+	if !slices.Contains(t.Migrations, source) {
+		t.Migrations = append(t.Migrations, source)
+	}
+}
+
+// column returns the named column of t, or nil.
+func (t *Table) column(name string) *Column {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// addColumn adds c, replacing a column of the same name.
+func (t *Table) addColumn(c Column) {
+	if existing := t.column(c.Name); existing != nil {
+		*existing = c
+		return
+	}
+	t.Columns = append(t.Columns, c)
+}
+
+func (t *Table) dropColumn(name string) {
+	t.Columns = slices.DeleteFunc(t.Columns, func(c Column) bool {
+		return strings.EqualFold(c.Name, name)
+	})
+}
+
+func (t *Table) renameColumn(from, to string) {
+	if c := t.column(from); c != nil {
+		c.Name = to
+	}
+}
+
+// setPrimaryKey marks the named columns as the primary key.
+func (t *Table) setPrimaryKey(names []string) {
+	for _, name := range names {
+		if c := t.column(name); c != nil {
+			c.PrimaryKey, c.NotNull = true, true
+		}
+	}
+}
+
+// setReference points the named column at a table or "table.column".
+func (t *Table) setReference(name, ref string) {
+	if c := t.column(name); c != nil {
+		c.References = ref
+	}
+}
@@ -0,0 +1,269 @@
+package dbschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// rubyQuotedIdent matches quoted table and column names, which the
+	// parser rewrites as symbols.
+	rubyQuotedIdent = regexp.MustCompile(`["']([\w.]+)["']`)
+	// rubyCall matches a migration method call with its arguments.
+	rubyCall = regexp.MustCompile(`^\s*(\w+)(?:\s*\(|\s+)(.*)$`)
+	// rubyBlockCall matches a call on a create_table or change_table block
+	// variable: t.string :name, null: false.
+	rubyBlockCall = regexp.MustCompile(`^\s*(\w+)\.(\w+)\b\s*\(?(.*)$`)
+	// rubyBlockOpen matches the block a call opens: do |t|.
+	rubyBlockOpen = regexp.MustCompile(`\)?\s*do\s*\|\s*(\w+)\s*\|\s*$`)
+	rubySymbol    = regexp.MustCompile(`^:(\w+)$`)
+	rubyOption    = regexp.MustCompile(`^:?(\w+)(?::|\s*=>)\s*(.*)$`)
+	rubyDownDef   = regexp.MustCompile(`^(\s*)def\s+(?:self\.)?down\b`)
+	// rubyToTable matches the table a foreign_key option names.
+	rubyToTable = regexp.MustCompile(`to_table:\s*:(\w+)`)
+)
+
+// railsBlock is the create_table or change_table block being read.
+type railsBlock struct {
+	table   *Table
+	varName string
+	indent  int
+}
+
+// applyRails replays the schema statements of a Rails migration, skipping
+// its down method.
+func applyRails(b *builder, source, content string) bool {
+	var block *railsBlock
+	skipIndent := -1
+	for line := range strings.Lines(content) {
+		line = strings.TrimRight(line, "\r\n")
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if skipIndent >= 0 {
+			if trimmed == "end" && indent <= skipIndent {
+				skipIndent = -1
+			}
+			continue
+		}
+		if m := rubyDownDef.FindStringSubmatch(line); m != nil {
+			skipIndent = len(m[1])
+			continue
+		}
+		line = rubyQuotedIdent.ReplaceAllString(line, ":$1")
+
+		if block != nil {
+			if trimmed == "end" && indent <= block.indent {
+				block = nil
+				continue
+			}
+			if m := rubyBlockCall.FindStringSubmatch(line); m != nil && m[1] == block.varName {
+				pos, opts := rubyArgs(m[3])
+				applyRailsColumnCall(block.table, m[2], pos, opts)
+			}
+			continue
+		}
+
+		m := rubyCall.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		args := m[2]
+		var blockVar string
+		if bm := rubyBlockOpen.FindStringSubmatch(args); bm != nil {
+			blockVar = bm[1]
+			args = args[:len(args)-len(bm[0])]
+		}
+		pos, opts := rubyArgs(args)
+		if len(pos) == 0 {
+			continue
+		}
+		var table *Table
+		switch m[1] {
+		case "create_table":
+			table = b.createTable(pos[0], source, nil)
+			if opts["id"] != "false" {
+				typ := strings.TrimPrefix(opts["id"], ":")
+				if typ == "" {
+					typ = "bigint"
+				}
+				pk := strings.TrimPrefix(opts["primary_key"], ":")
+				if pk == "" {
+					pk = "id"
+				}
+				table.addColumn(Column{Name: pk, Type: typ, NotNull: true, PrimaryKey: true})
+			}
+		case "change_table":
+			table = b.table(pos[0], source)
+		case "drop_table":
+			b.dropTable(pos[0])
+		case "rename_table":
+			if len(pos) > 1 {
+				b.renameTable(pos[0], pos[1], source)
+			}
+		case "add_column", "add_reference", "add_belongs_to", "add_timestamps", "add_foreign_key",
+			"remove_column", "remove_columns", "remove_reference", "remove_belongs_to", "remove_timestamps",
+			"rename_column", "change_column", "change_column_null":
+			applyRailsTableCall(b.table(pos[0], source), m[1], pos[1:], opts)
+		}
+		if table != nil && blockVar != "" {
+			block = &railsBlock{table: table, varName: blockVar, indent: indent}
+		}
+	}
+	return strings.Contains(content, "Migration")
+}
+
+// applyRailsTableCall applies a statement naming its table, such as
+// add_column :users, :email, :string.
+func applyRailsTableCall(t *Table, method string, pos []string, opts map[string]string) {
+	switch method {
+	case "add_column":
+		if len(pos) > 1 {
+			applyRailsColumnCall(t, pos[1], pos[:1], opts)
+		}
+	case "add_reference", "add_belongs_to":
+		applyRailsColumnCall(t, "references", pos, opts)
+	case "add_timestamps":
+		applyRailsColumnCall(t, "timestamps", nil, opts)
+	case "add_foreign_key":
+		if len(pos) > 0 {
+			col := opts["column"]
+			if col == "" {
+				col = singularize(pos[0]) + "_id"
+			}
+			t.setReference(strings.TrimPrefix(col, ":"), pos[0])
+		}
+	case "remove_column", "remove_columns":
+		if method == "remove_column" && len(pos) > 1 {
+			// remove_column :users, :email, :string names the type.
+			pos = pos[:1]
+		}
+		applyRailsColumnCall(t, "remove", pos, opts)
+	case "remove_reference", "remove_belongs_to":
+		for _, name := range pos {
+			t.dropColumn(name + "_id")
+			t.dropColumn(name + "_type")
+		}
+	case "remove_timestamps":
+		t.dropColumn("created_at")
+		t.dropColumn("updated_at")
+	case "rename_column":
+		applyRailsColumnCall(t, "rename", pos, opts)
+	case "change_column":
+		applyRailsColumnCall(t, "change", pos, opts)
+	case "change_column_null":
+		if len(pos) > 1 {
+			if c := t.column(pos[0]); c != nil {
+				c.NotNull = pos[1] == "false"
+			}
+		}
+	}
+}
+
+// applyRailsColumnCall applies a column method of a create_table or
+// change_table block: t.string :name, t.references :org, t.timestamps,
+// t.remove :name and so on.
+func applyRailsColumnCall(t *Table, method string, pos []string, opts map[string]string) {
+	notNull := opts["null"] == "false"
+	switch method {
+	case "index", "check_constraint", "remove_index", "foreign_key", "rename_index":
+	case "timestamps":
+		t.addColumn(Column{Name: "created_at", Type: "datetime", NotNull: opts["null"] != "true"})
+		t.addColumn(Column{Name: "updated_at", Type: "datetime", NotNull: opts["null"] != "true"})
+	case "references", "belongs_to":
+		typ := strings.TrimPrefix(opts["type"], ":")
+		if typ == "" {
+			typ = "bigint"
+		}
+		for _, name := range pos {
+			c := Column{Name: name + "_id", Type: typ, NotNull: notNull}
+			if fk := opts["foreign_key"]; fk != "" && fk != "false" {
+				c.References = pluralize(name)
+				if m := rubyToTable.FindStringSubmatch(fk); m != nil {
+					c.References = m[1]
+				}
+			}
+			t.addColumn(c)
+			if opts["polymorphic"] == "true" {
+				t.addColumn(Column{Name: name + "_type", Type: "string", NotNull: notNull})
+			}
+		}
+	case "remove":
+		for _, name := range pos {
+			t.dropColumn(name)
+		}
+	case "rename":
+		if len(pos) > 1 {
+			t.renameColumn(pos[0], pos[1])
+		}
+	case "change":
+		if len(pos) > 1 {
+			if c := t.column(pos[0]); c != nil {
+				c.Type = pos[1]
+				if opts["null"] != "" {
+					c.NotNull = notNull
+				}
+			}
+		}
+	case "column":
+		if len(pos) > 1 {
+			t.addColumn(Column{Name: pos[0], Type: pos[1], NotNull: notNull, PrimaryKey: opts["primary_key"] == "true"})
+		}
+	default:
+		// t.string :first_name, :last_name
+		for _, name := range pos {
+			t.addColumn(Column{Name: name, Type: method, NotNull: notNull, PrimaryKey: opts["primary_key"] == "true"})
+		}
+	}
+}
+
+// rubyArgs splits the arguments of a call into its leading symbols and
+// literals and its keyword options.
+func rubyArgs(args string) ([]string, map[string]string) {
+	args = strings.TrimSpace(args)
+	args = strings.TrimSuffix(args, ")")
+	var pos []string
+	opts := make(map[string]string)
+	for _, arg := range splitTopLevel(args) {
+		if m := rubySymbol.FindStringSubmatch(arg); m != nil {
+			pos = append(pos, m[1])
+			continue
+		}
+		if m := rubyOption.FindStringSubmatch(arg); m != nil {
+			opts[m[1]] = strings.TrimSpace(m[2])
+			continue
+		}
+		if arg == "true" || arg == "false" {
+			pos = append(pos, arg)
+		}
+	}
+	return pos, opts
+}
+
+// pluralize returns the table name Rails infers for a reference.
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && !strings.HasSuffix(name, "ay") && !strings.HasSuffix(name, "ey") && !strings.HasSuffix(name, "oy"):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+// singularize reverses pluralize.
+func singularize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	}
+	return strings.TrimSuffix(name, "s")
+}
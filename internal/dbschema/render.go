@@ -0,0 +1,153 @@
+package dbschema
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// maxDescribedTables is the most tables Lookup describes column by column.
+const maxDescribedTables = 5
+
+// Prelude renders the schema compactly for the repository map: one line
+// per table listing its columns, primary keys and foreign keys. It shows
+// at most maxTables tables and returns "" for an empty schema.
+func (s *Schema) Prelude(maxTables int) string {
+	if s == nil || len(s.Tables) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Database schema (%d tables from %d migrations in %s):\n", len(s.Tables), s.Migrations, strings.Join(s.Dirs, ", "))
+	for i, t := range s.Tables {
+		if maxTables > 0 && i == maxTables {
+			fmt.Fprintf(&sb, "  ... and %d more tables (schema_lookup lists them)\n", len(s.Tables)-maxTables)
+			break
+		}
+		cols := make([]string, len(t.Columns))
+		for j, c := range t.Columns {
+			cols[j] = c.Name
+			if c.PrimaryKey {
+				cols[j] += " PK"
+			}
+			if c.References != "" {
+				cols[j] += " -> " + c.References
+			}
+		}
+		fmt.Fprintf(&sb, "  %s(%s)\n", t.Name, strings.Join(cols, ", "))
+	}
+	return sb.String()
+}
+
+// Lookup describes the tables named like table, or with columns named like
+// column, matching case-insensitive substrings. A table matched exactly is
+// described alone. With neither, it lists every table.
+func (s *Schema) Lookup(table, column string) (string, error) {
+	if len(s.Tables) == 0 {
+		return "", fmt.Errorf("no database migrations found (golang-migrate, goose, dbmate, Flyway, Rails or Alembic)")
+	}
+	table, column = strings.ToLower(strings.TrimSpace(table)), strings.ToLower(strings.TrimSpace(column))
+
+	var sb strings.Builder
+	if table == "" && column == "" {
+		fmt.Fprintf(&sb, "%d tables from %d migrations in %s:\n", len(s.Tables), s.Migrations, strings.Join(s.Dirs, ", "))
+		for _, t := range s.Tables {
+			fmt.Fprintf(&sb, "  %s (%d columns)\n", t.Name, len(t.Columns))
+		}
+		return sb.String(), nil
+	}
+
+	var tables []*Table
+	if t := s.Table(table); t != nil && table != "" {
+		tables = []*Table{t}
+	} else {
+		for _, t := range s.Tables {
+			if strings.Contains(strings.ToLower(t.Name), table) {
+				tables = append(tables, t)
+			}
+		}
+	}
+
+	if column == "" {
+		if len(tables) == 0 {
+			return "", fmt.Errorf("no table matches %q; tables: %s", table, s.tableNames())
+		}
+		if len(tables) > maxDescribedTables {
+			fmt.Fprintf(&sb, "%d tables match %q; name one to see its columns:\n", len(tables), table)
+			for _, t := range tables {
+				fmt.Fprintf(&sb, "  %s (%d columns)\n", t.Name, len(t.Columns))
+			}
+			return sb.String(), nil
+		}
+		for i, t := range tables {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			t.describe(&sb)
+		}
+		return sb.String(), nil
+	}
+
+	var rows []string
+	for _, t := range tables {
+		for _, c := range t.Columns {
+			if strings.Contains(strings.ToLower(c.Name), column) {
+				rows = append(rows, fmt.Sprintf("%s.%s\t%s\t%s", t.Name, c.Name, c.Type, c.constraints()))
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("no column matches %q", column)
+	}
+	writeAligned(&sb, "", rows)
+	return sb.String(), nil
+}
+
+// writeAligned writes tab-separated rows with aligned columns.
+func writeAligned(sb *strings.Builder, indent string, rows []string) {
+	var aligned strings.Builder
+	tw := tabwriter.NewWriter(&aligned, 0, 2, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s%s\n", indent, row)
+	}
+	tw.Flush()
+	for line := range strings.Lines(aligned.String()) {
+		sb.WriteString(strings.TrimRight(line, " \n"))
+		sb.WriteString("\n")
+	}
+}
+
+// describe writes the table's columns, one per line, and the migrations
+// that shaped it.
+func (t *Table) describe(sb *strings.Builder) {
+	fmt.Fprintf(sb, "Table %s\n", t.Name)
+	rows := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		rows[i] = c.Name + "\t" + c.Type + "\t" + c.constraints()
+	}
+	writeAligned(sb, "  ", rows)
+	fmt.Fprintf(sb, "Migrations: %s\n", strings.Join(t.Migrations, ", "))
+}
+
+// constraints describes the column's primary key, nullability and foreign
+// key.
+func (c Column) constraints() string {
+	var parts []string
+	switch {
+	case c.PrimaryKey:
+		parts = append(parts, "PRIMARY KEY")
+	case c.NotNull:
+		parts = append(parts, "NOT NULL")
+	}
+	if c.References != "" {
+		parts = append(parts, "-> "+c.References)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s *Schema) tableNames() string {
+	names := make([]string, len(s.Tables))
+	for i, t := range s.Tables {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
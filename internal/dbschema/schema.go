@@ -0,0 +1,261 @@
+// Package dbschema reconstructs a repository's database schema by replaying
+// its migrations, so that the agent can look tables and columns up instead
+// of guessing them. It understands SQL migrations (golang-migrate, goose,
+// dbmate and Flyway), Rails migrations and Alembic revisions.
+package dbschema
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+)
+
+// maxMigrationSize bounds the size of a single migration; larger files are
+// skipped.
+const maxMigrationSize = 1 << 20
+
+// Column is a table column as the migrations left it.
+type Column struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	PrimaryKey bool
+	// References is the "table.column", or just the table, a foreign key
+	// points at.
+	References string
+}
+
+// Table is a table as the migrations left it.
+type Table struct {
+	Name    string
+	Columns []Column
+	// Migrations are the migrations that changed the table, oldest first.
+	Migrations []string
+}
+
+// Schema is the tables the migrations of a repository create, in name
+// order.
+type Schema struct {
+	Tables []*Table
+	// Migrations is the number of migrations replayed.
+	Migrations int
+	// Dirs are the directories holding the migrations.
+	Dirs []string
+}
+
+// kind is a migration format.
+type kind int
+
+const (
+	kindNone kind = iota
+	kindSQL
+	kindRails
+	kindAlembic
+)
+
+// migrationKind returns the format of the migration at relPath, judged by
+// its path alone, or kindNone when it is not a migration.
+func migrationKind(relPath string) kind {
+	dir, name := path.Split(relPath)
+	dir = strings.TrimSuffix(dir, "/")
+	switch path.Ext(name) {
+	case ".sql":
+		if strings.HasSuffix(name, ".down.sql") {
+			return kindNone
+		}
+		if strings.HasSuffix(name, ".up.sql") || strings.Contains(strings.ToLower(path.Base(dir)), "migrat") {
+			return kindSQL
+		}
+	case ".rb":
+		if dir == "db/migrate" || strings.HasSuffix(dir, "/db/migrate") {
+			return kindRails
+		}
+	case ".py":
+		if path.Base(dir) == "versions" && name != "__init__.py" {
+			return kindAlembic
+		}
+	}
+	return kindNone
+}
+
+// Migrations returns the migrations among the slash-separated, repository
+// relative paths.
+func Migrations(relPaths []string) []string {
+	var migrations []string
+	for _, p := range relPaths {
+		if migrationKind(p) != kindNone {
+			migrations = append(migrations, p)
+		}
+	}
+	return migrations
+}
+
+// Stamp fingerprints the migrations' paths, sizes and modification times,
+// so that callers can tell when the schema needs rebuilding.
+func Stamp(root string, migrations []string) uint64 {
+	h := fnv.New64a()
+	for _, m := range migrations {
+		h.Write([]byte(m))
+		if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(m))); err == nil {
+			fmt.Fprintf(h, "\x00%d\x00%d\x00", info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return h.Sum64()
+}
+
+// Load finds the migrations below root, skipping the paths the repository
+// ignores, and replays them.
+func Load(ctx context.Context, root string) (*Schema, error) {
+	walker := fsext.NewFastGlobWalker(root)
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if p != root && walker.ShouldSkipDir(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		if rel = filepath.ToSlash(rel); migrationKind(rel) != kindNone && !walker.ShouldSkip(p) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Parse(root, files)
+}
+
+// migration is a migration read from disk.
+type migration struct {
+	path    string
+	kind    kind
+	content string
+	// version orders the migrations of a directory.
+	version string
+}
+
+// Parse replays the migrations, repository-relative paths below root, in
+// order: directory by directory, and within a directory by version, or by
+// revision chain for Alembic.
+func Parse(root string, migrations []string) (*Schema, error) {
+	byDir := make(map[string][]migration)
+	for _, p := range migrations {
+		k := migrationKind(p)
+		if k == kindNone {
+			continue
+		}
+		abs := filepath.Join(root, filepath.FromSlash(p))
+		info, err := os.Stat(abs)
+		if err != nil || info.Size() > maxMigrationSize {
+			continue
+		}
+		data, err := os.ReadFile(abs)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", p, err)
+		}
+		dir := path.Dir(p)
+		byDir[dir] = append(byDir[dir], migration{
+			path:    p,
+			kind:    k,
+			content: string(data),
+			version: migrationVersion(path.Base(p)),
+		})
+	}
+
+	b := newBuilder()
+	schema := &Schema{}
+	for _, dir := range sortedKeys(byDir) {
+		ms := byDir[dir]
+		if ms[0].kind == kindAlembic {
+			ms = alembicOrder(ms)
+		} else {
+			slices.SortFunc(ms, func(a, b migration) int {
+				return cmp.Or(compareVersions(a.version, b.version), strings.Compare(a.path, b.path))
+			})
+		}
+		applied := 0
+		for _, m := range ms {
+			var ok bool
+			switch m.kind {
+			case kindSQL:
+				ok = applySQL(b, m.path, upSection(m.content))
+			case kindRails:
+				ok = applyRails(b, m.path, m.content)
+			case kindAlembic:
+				ok = applyAlembic(b, m.path, m.content)
+			}
+			if ok {
+				applied++
+			}
+		}
+		if applied > 0 {
+			schema.Migrations += applied
+			schema.Dirs = append(schema.Dirs, dir)
+		}
+	}
+	schema.Tables = b.tables()
+	return schema, nil
+}
+
+// migrationVersion returns the first run of digits in a migration's file
+// name: its golang-migrate version, goose or Rails timestamp, or Flyway
+// version.
+func migrationVersion(name string) string {
+	start := strings.IndexFunc(name, isDigit)
+	if start < 0 {
+		return ""
+	}
+	end := start
+	for end < len(name) && isDigit(rune(name[end])) {
+		end++
+	}
+	return strings.TrimLeft(name[start:end], "0")
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// compareVersions compares numeric versions without parsing them, as
+// timestamps overflow integers.
+func compareVersions(a, b string) int {
+	return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+}
+
+// Table returns the table named name, ignoring case, or nil.
+func (s *Schema) Table(name string) *Table {
+	for _, t := range s.Tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
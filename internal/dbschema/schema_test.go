@@ -0,0 +1,279 @@
+package dbschema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
+func columnNames(t *Table) []string {
+	names := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestMigrationKind(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]kind{
+		"db/migrations/0001_init.up.sql":         kindSQL,
+		"db/migrations/0001_init.down.sql":       kindNone,
+		"internal/db/migrations/20250101_x.sql":  kindSQL,
+		"schema/0001_init.up.sql":                kindSQL,
+		"queries/users.sql":                      kindNone,
+		"db/migrate/20240101000000_users.rb":     kindRails,
+		"app/models/user.rb":                     kindNone,
+		"alembic/versions/ab12_create_users.py":  kindAlembic,
+		"alembic/versions/__init__.py":           kindNone,
+		"src/migrations/V2__add_email_index.sql": kindSQL,
+	}
+	for path, want := range tests {
+		require.Equal(t, want, migrationKind(path), path)
+	}
+}
+
+func TestParseSQLMigrations(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{
+		"migrations/1_create_users.up.sql": `
+CREATE TABLE orgs (id SERIAL PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE "users" (
+    id BIGSERIAL,
+    email VARCHAR(255) NOT NULL, -- login
+    key TEXT,
+    org_id INTEGER REFERENCES orgs(id) ON DELETE CASCADE,
+    price NUMERIC(10, 2) DEFAULT 0,
+    /* legacy */ nickname TEXT,
+    PRIMARY KEY (id),
+    UNIQUE (email)
+);
+CREATE TEMP TABLE scratch (x INT);
+CREATE INDEX users_email ON users (email);`,
+		"migrations/1_create_users.down.sql": `DROP TABLE users; DROP TABLE orgs;`,
+		"migrations/2_rework_users.up.sql": `
+ALTER TABLE users ADD COLUMN team_id INTEGER, DROP COLUMN nickname;
+ALTER TABLE users RENAME COLUMN email TO login;
+ALTER TABLE users ALTER COLUMN price TYPE NUMERIC(12, 2);
+ALTER TABLE users ADD CONSTRAINT users_team FOREIGN KEY (team_id) REFERENCES teams (id);
+ALTER TABLE orgs RENAME TO organizations;`,
+		"migrations/10_goose.sql": `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE teams (id INTEGER PRIMARY KEY, org_id INTEGER NOT NULL);
+CREATE TRIGGER touch AFTER UPDATE ON teams BEGIN UPDATE teams SET id = id; END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE teams;`,
+	})
+
+	schema, err := Load(t.Context(), root)
+	require.NoError(t, err)
+	require.Equal(t, 3, schema.Migrations)
+	require.Equal(t, []string{"migrations"}, schema.Dirs)
+	require.Len(t, schema.Tables, 3)
+
+	users := schema.Table("USERS")
+	require.NotNil(t, users)
+	require.Equal(t, []string{"id", "login", "key", "org_id", "price", "team_id"}, columnNames(users))
+	require.Equal(t, Column{Name: "id", Type: "BIGSERIAL", NotNull: true, PrimaryKey: true}, users.Columns[0])
+	require.Equal(t, Column{Name: "login", Type: "VARCHAR(255)", NotNull: true}, users.Columns[1])
+	require.Equal(t, "orgs.id", users.Columns[3].References)
+	require.Equal(t, "NUMERIC(12, 2)", users.Columns[4].Type)
+	require.Equal(t, "teams.id", users.Columns[5].References)
+	require.Equal(t, []string{"migrations/1_create_users.up.sql", "migrations/2_rework_users.up.sql"}, users.Migrations)
+
+	require.NotNil(t, schema.Table("organizations"))
+	require.Nil(t, schema.Table("orgs"))
+	require.Equal(t, []string{"id", "org_id"}, columnNames(schema.Table("teams")))
+}
+
+func TestParseRailsMigrations(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{
+		"db/migrate/20240101000000_create_accounts.rb": `class CreateAccounts < ActiveRecord::Migration[7.1]
+  def change
+    create_table :accounts do |t|
+      t.string :name, null: false
+      t.timestamps
+    end
+
+    create_table "users", id: :uuid do |t|
+      t.string "email", null: false # login
+      t.string :first_name, :last_name
+      t.references :account, foreign_key: true, type: :uuid
+      t.belongs_to :company, foreign_key: { to_table: :accounts }
+      t.index :email, unique: true
+    end
+  end
+end`,
+		"db/migrate/20240201000000_change_users.rb": `class ChangeUsers < ActiveRecord::Migration[7.1]
+  def up
+    add_column :users, :admin, :boolean, default: false, null: false
+    remove_column :users, :last_name, :string
+    rename_column :users, :first_name, :given_name
+    change_table :accounts do |t|
+      t.integer :plan
+      t.remove :updated_at
+    end
+    add_reference :accounts, :owner, polymorphic: true
+  end
+
+  def down
+    drop_table :users
+  end
+end`,
+	})
+
+	schema, err := Load(t.Context(), root)
+	require.NoError(t, err)
+	require.Equal(t, 2, schema.Migrations)
+
+	users := schema.Table("users")
+	require.NotNil(t, users)
+	require.Equal(t, []string{"id", "email", "given_name", "account_id", "company_id", "admin"}, columnNames(users))
+	require.Equal(t, Column{Name: "id", Type: "uuid", NotNull: true, PrimaryKey: true}, users.Columns[0])
+	require.Equal(t, Column{Name: "email", Type: "string", NotNull: true}, users.Columns[1])
+	require.Equal(t, Column{Name: "account_id", Type: "uuid", References: "accounts"}, users.Columns[3])
+	require.Equal(t, "accounts", users.Columns[4].References)
+	require.True(t, users.Columns[5].NotNull)
+
+	accounts := schema.Table("accounts")
+	require.Equal(t, []string{"id", "name", "created_at", "plan", "owner_id", "owner_type"}, columnNames(accounts))
+}
+
+func TestParseAlembicMigrations(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{
+		// The file names sort against the revision chain.
+		"alembic/versions/a_add_email.py": `"""add email"""
+from alembic import op
+import sqlalchemy as sa
+
+revision = "b2"
+down_revision = "a1"
+
+
+def upgrade():
+    op.add_column("users", sa.Column("email", sa.String(length=255), nullable=False))
+    with op.batch_alter_table("users") as batch_op:
+        batch_op.alter_column("name", new_column_name="full_name")
+        batch_op.create_foreign_key("fk_users_org", "orgs", ["org_id"], ["id"])
+
+
+def downgrade():
+    op.drop_column("users", "email")
+`,
+		"alembic/versions/b_create_users.py": `from alembic import op
+import sqlalchemy as sa
+
+revision: str = "a1"
+down_revision = None
+
+
+def upgrade() -> None:
+    op.create_table(
+        "users",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("name", sa.String(), nullable=True),
+        sa.Column("org_id", sa.Integer()),
+        sa.Column("team_id", sa.Integer(), sa.ForeignKey("teams.id")),
+        sa.PrimaryKeyConstraint("id"),
+    )
+    op.create_table("teams", sa.Column("id", sa.Integer(), primary_key=True))
+
+
+def downgrade() -> None:
+    op.drop_table("users")
+`,
+		"alembic/env.py": `from alembic import context`,
+	})
+
+	schema, err := Load(t.Context(), root)
+	require.NoError(t, err)
+	require.Equal(t, 2, schema.Migrations)
+
+	users := schema.Table("users")
+	require.NotNil(t, users)
+	require.Equal(t, []string{"id", "full_name", "org_id", "team_id", "email"}, columnNames(users))
+	require.Equal(t, Column{Name: "id", Type: "Integer", NotNull: true, PrimaryKey: true}, users.Columns[0])
+	require.Equal(t, "orgs.id", users.Columns[2].References)
+	require.Equal(t, "teams.id", users.Columns[3].References)
+	require.Equal(t, Column{Name: "email", Type: "String(length=255)", NotNull: true}, users.Columns[4])
+	require.True(t, schema.Table("teams").Columns[0].PrimaryKey)
+}
+
+func TestSchemaLookup(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{
+		"db/migrations/001_init.up.sql": `
+CREATE TABLE orgs (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE users (id INTEGER PRIMARY KEY, org_id INTEGER REFERENCES orgs (id), name TEXT);`,
+	})
+	migrations := Migrations([]string{"README.md", "db/migrations/001_init.up.sql"})
+	require.Equal(t, []string{"db/migrations/001_init.up.sql"}, migrations)
+	schema, err := Parse(root, migrations)
+	require.NoError(t, err)
+
+	require.Equal(t, `Database schema (2 tables from 1 migrations in db/migrations):
+  orgs(id PK, name)
+  users(id PK, org_id -> orgs.id, name)
+`, schema.Prelude(0))
+	require.Contains(t, schema.Prelude(1), "... and 1 more tables")
+
+	out, err := schema.Lookup("", "")
+	require.NoError(t, err)
+	require.Contains(t, out, "  users (3 columns)\n")
+
+	out, err = schema.Lookup("users", "")
+	require.NoError(t, err)
+	require.Equal(t, `Table users
+  id      INTEGER  PRIMARY KEY
+  org_id  INTEGER  -> orgs.id
+  name    TEXT
+Migrations: db/migrations/001_init.up.sql
+`, out)
+
+	out, err = schema.Lookup("", "NAME")
+	require.NoError(t, err)
+	require.Equal(t, "orgs.name   TEXT  NOT NULL\nusers.name  TEXT\n", out)
+
+	_, err = schema.Lookup("accounts", "")
+	require.ErrorContains(t, err, "tables: orgs, users")
+
+	empty, err := Parse(root, nil)
+	require.NoError(t, err)
+	require.Empty(t, empty.Prelude(0))
+	_, err = empty.Lookup("", "")
+	require.ErrorContains(t, err, "no database migrations found")
+}
+
+func TestStampChangesWithMigrations(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{"migrations/1_a.up.sql": "CREATE TABLE a (id INT);"})
+	migrations := []string{"migrations/1_a.up.sql"}
+	stamp := Stamp(root, migrations)
+	require.Equal(t, stamp, Stamp(root, migrations))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "migrations", "1_a.up.sql"), []byte("CREATE TABLE a (id INT, b INT);"), 0o644))
+	require.NotEqual(t, stamp, Stamp(root, migrations))
+}
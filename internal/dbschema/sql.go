@@ -0,0 +1,330 @@
+package dbschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// upMarker and downMarker delimit the up and down halves of goose and
+	// dbmate migrations.
+	upMarker   = regexp.MustCompile(`(?i)^--\s*(?:\+goose\s+up|migrate:up)\b`)
+	downMarker = regexp.MustCompile(`(?i)^--\s*(?:\+goose\s+down|migrate:down)\b`)
+
+	createTableStmt = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?(TEMP\s+|TEMPORARY\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)\s*\(`)
+	dropTableStmt   = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(.+?)(?:\s+(?:CASCADE|RESTRICT))?$`)
+	alterTableStmt  = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(\S+)\s+(.*)$`)
+	renameTableStmt = regexp.MustCompile(`(?is)^RENAME\s+TABLE\s+(\S+)\s+TO\s+(\S+)$`)
+
+	addAction          = regexp.MustCompile(`(?is)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(.*)$`)
+	dropAction         = regexp.MustCompile(`(?is)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?(\S+)`)
+	renameTableAction  = regexp.MustCompile(`(?is)^RENAME\s+TO\s+(\S+)$`)
+	renameColumnAction = regexp.MustCompile(`(?is)^RENAME\s+(?:COLUMN\s+)?(\S+)\s+TO\s+(\S+)$`)
+	alterTypeAction    = regexp.MustCompile(`(?is)^ALTER\s+(?:COLUMN\s+)?(\S+)\s+(?:SET\s+DATA\s+)?TYPE\s+(.+?)(?:\s+USING\s+.*)?$`)
+	alterNullAction    = regexp.MustCompile(`(?is)^ALTER\s+(?:COLUMN\s+)?(\S+)\s+(SET|DROP)\s+NOT\s+NULL$`)
+	modifyAction       = regexp.MustCompile(`(?is)^MODIFY\s+(?:COLUMN\s+)?(.*)$`)
+	changeAction       = regexp.MustCompile(`(?is)^CHANGE\s+(?:COLUMN\s+)?(\S+)\s+(.*)$`)
+
+	// constraintDef matches table constraints, which are not columns.
+	// MySQL index definitions are told from columns named key or index by
+	// the column list following the index name.
+	constraintDef  = regexp.MustCompile(`(?i)^(?:(?:CONSTRAINT|PRIMARY\s+KEY|FOREIGN\s+KEY|UNIQUE|CHECK|FULLTEXT|SPATIAL|EXCLUDE)\b|(?:INDEX|KEY)\s*\(|(?:INDEX|KEY)\s+[^\s(]+\s+\()`)
+	constraintName = regexp.MustCompile(`(?i)^CONSTRAINT\s+\S+\s+`)
+	primaryKeyDef  = regexp.MustCompile(`(?is)^PRIMARY\s+KEY\s*\(([^)]*)\)`)
+	foreignKeyDef  = regexp.MustCompile(`(?is)^FOREIGN\s+KEY\s*\(([^)]*)\)\s*REFERENCES\s+([^\s(]+)\s*(?:\(([^)]*)\))?`)
+
+	notNull    = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	primaryKey = regexp.MustCompile(`(?i)\bPRIMARY\s+KEY\b`)
+	references = regexp.MustCompile(`(?i)\bREFERENCES\s+([^\s(]+)\s*(?:\(\s*([^)\s]+)\s*\))?`)
+)
+
+// columnConstraintWords end the type of a column definition.
+var columnConstraintWords = map[string]bool{
+	"CONSTRAINT": true, "NOT": true, "NULL": true, "PRIMARY": true,
+	"REFERENCES": true, "DEFAULT": true, "UNIQUE": true, "CHECK": true,
+	"GENERATED": true, "COLLATE": true, "AUTO_INCREMENT": true,
+	"AUTOINCREMENT": true, "IDENTITY": true, "COMMENT": true, "ON": true,
+	"AS": true,
+}
+
+// upSection returns the up half of a goose or dbmate migration, or all of
+// any other SQL migration.
+func upSection(sql string) string {
+	lines := strings.Split(sql, "\n")
+	var up []string
+	marked, inUp := false, false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case upMarker.MatchString(trimmed):
+			marked, inUp = true, true
+		case downMarker.MatchString(trimmed):
+			marked, inUp = true, false
+		case inUp:
+			up = append(up, line)
+		}
+	}
+	if !marked {
+		return sql
+	}
+	return strings.Join(up, "\n")
+}
+
+// applySQL replays the table statements of a SQL migration: CREATE, DROP,
+// ALTER and RENAME TABLE. Other statements are ignored.
+func applySQL(b *builder, source, sql string) bool {
+	for _, stmt := range splitStatements(sql) {
+		if m := createTableStmt.FindStringSubmatch(stmt); m != nil {
+			if m[1] != "" {
+				continue
+			}
+			t := b.createTable(unquote(m[2]), source, nil)
+			for _, def := range splitTopLevel(parenBody(stmt[len(m[0]):])) {
+				applyTableDef(t, def)
+			}
+			continue
+		}
+		if m := dropTableStmt.FindStringSubmatch(stmt); m != nil {
+			for _, name := range splitTopLevel(m[1]) {
+				b.dropTable(unquote(name))
+			}
+			continue
+		}
+		if m := renameTableStmt.FindStringSubmatch(stmt); m != nil {
+			b.renameTable(unquote(m[1]), unquote(m[2]), source)
+			continue
+		}
+		if m := alterTableStmt.FindStringSubmatch(stmt); m != nil {
+			name := unquote(m[1])
+			for _, action := range splitTopLevel(m[2]) {
+				name = applyAlterAction(b, name, source, action)
+			}
+		}
+	}
+	return true
+}
+
+// applyTableDef applies a column or table constraint definition of a
+// CREATE TABLE statement or an ALTER TABLE ... ADD action.
+func applyTableDef(t *Table, def string) {
+	if !constraintDef.MatchString(def) {
+		if c, ok := parseColumnDef(def); ok {
+			t.addColumn(c)
+		}
+		return
+	}
+	def = constraintName.ReplaceAllString(def, "")
+	if m := primaryKeyDef.FindStringSubmatch(def); m != nil {
+		t.setPrimaryKey(splitIdents(m[1]))
+		return
+	}
+	if m := foreignKeyDef.FindStringSubmatch(def); m != nil {
+		cols, refCols := splitIdents(m[1]), splitIdents(m[3])
+		for i, col := range cols {
+			ref := unquote(m[2])
+			if i < len(refCols) {
+				ref += "." + refCols[i]
+			}
+			t.setReference(col, ref)
+		}
+	}
+}
+
+// applyAlterAction applies one action of an ALTER TABLE statement and
+// returns the table's name, which RENAME TO changes.
+func applyAlterAction(b *builder, name, source, action string) string {
+	if m := renameTableAction.FindStringSubmatch(action); m != nil {
+		to := unquote(m[1])
+		b.renameTable(name, to, source)
+		return to
+	}
+	t := b.table(name, source)
+	switch {
+	case addAction.MatchString(action):
+		applyTableDef(t, addAction.FindStringSubmatch(action)[1])
+	case renameColumnAction.MatchString(action):
+		m := renameColumnAction.FindStringSubmatch(action)
+		t.renameColumn(unquote(m[1]), unquote(m[2]))
+	case alterNullAction.MatchString(action):
+		m := alterNullAction.FindStringSubmatch(action)
+		if c := t.column(unquote(m[1])); c != nil {
+			c.NotNull = strings.EqualFold(m[2], "SET")
+		}
+	case alterTypeAction.MatchString(action):
+		m := alterTypeAction.FindStringSubmatch(action)
+		if c := t.column(unquote(m[1])); c != nil {
+			c.Type = strings.TrimSpace(m[2])
+		}
+	case modifyAction.MatchString(action):
+		if c, ok := parseColumnDef(modifyAction.FindStringSubmatch(action)[1]); ok {
+			t.addColumn(c)
+		}
+	case changeAction.MatchString(action):
+		m := changeAction.FindStringSubmatch(action)
+		if c, ok := parseColumnDef(m[2]); ok {
+			t.dropColumn(unquote(m[1]))
+			t.addColumn(c)
+		}
+	case dropAction.MatchString(action):
+		target := dropAction.FindStringSubmatch(action)[1]
+		switch strings.ToUpper(target) {
+		case "CONSTRAINT", "INDEX", "KEY", "PRIMARY", "FOREIGN", "CHECK", "DEFAULT":
+		default:
+			t.dropColumn(unquote(target))
+		}
+	}
+	return name
+}
+
+// parseColumnDef parses a column definition: its name, its type up to the
+// first constraint, and the constraints it knows.
+func parseColumnDef(def string) (Column, bool) {
+	fields := strings.Fields(def)
+	if len(fields) == 0 {
+		return Column{}, false
+	}
+	c := Column{Name: unquote(fields[0])}
+	var typ []string
+	for _, f := range fields[1:] {
+		if columnConstraintWords[strings.ToUpper(f)] {
+			break
+		}
+		typ = append(typ, f)
+	}
+	c.Type = strings.Join(typ, " ")
+	c.PrimaryKey = primaryKey.MatchString(def)
+	c.NotNull = c.PrimaryKey || notNull.MatchString(def)
+	if m := references.FindStringSubmatch(def); m != nil {
+		c.References = unquote(m[1])
+		if m[2] != "" {
+			c.References += "." + unquote(m[2])
+		}
+	}
+	return c, true
+}
+
+// splitStatements strips the comments of a SQL script and splits it into
+// statements, with whitespace collapsed.
+func splitStatements(sql string) []string {
+	var stmts []string
+	var sb strings.Builder
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case quote != 0:
+			sb.WriteByte(ch)
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+			sb.WriteByte(ch)
+		case ch == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			sb.WriteByte(' ')
+		case ch == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			sb.WriteByte(' ')
+		case ch == ';':
+			stmts = appendStatement(stmts, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return appendStatement(stmts, sb.String())
+}
+
+func appendStatement(stmts []string, stmt string) []string {
+	if stmt = strings.Join(strings.Fields(stmt), " "); stmt != "" {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+// splitTopLevel splits s at the commas outside parentheses and quotes.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			depth--
+		case ch == ',' && depth == 0:
+			parts = appendPart(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return appendPart(parts, s[start:])
+}
+
+func appendPart(parts []string, part string) []string {
+	if part = strings.TrimSpace(part); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// parenBody returns s up to the parenthesis closing one opened just before
+// it.
+func parenBody(s string) string {
+	depth := 1
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth--; depth == 0 {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
+
+// splitIdents splits a comma-separated list of identifiers.
+func splitIdents(s string) []string {
+	var idents []string
+	for _, part := range splitTopLevel(s) {
+		idents = append(idents, unquote(strings.Fields(part)[0]))
+	}
+	return idents
+}
+
+// unquote strips the identifier quotes of SQL dialects from a possibly
+// schema-qualified name.
+func unquote(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '"', '`', '[', ']', '\'':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(name))
+}
@@ -0,0 +1,170 @@
+// Package devenv detects the development environment a project declares,
+// a direnv .envrc, a Nix flake or a shell.nix, and runs commands inside it
+// with direnv exec, nix develop --command or nix-shell --run, so that they
+// see the project's toolchain rather than whatever is on the local PATH.
+package devenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/shell"
+)
+
+// kind is a supported environment: the file declaring it and the tool
+// that enters it.
+type kind struct {
+	name string
+	file string
+	tool string
+}
+
+// kinds are the supported environments in detection order. A .envrc
+// comes first, as it usually loads the flake or shell.nix itself and adds
+// the project's own variables.
+var kinds = []kind{
+	{name: config.DevEnvDirenv, file: ".envrc", tool: "direnv"},
+	{name: config.DevEnvFlake, file: "flake.nix", tool: "nix"},
+	{name: config.DevEnvNixShell, file: "shell.nix", tool: "nix-shell"},
+}
+
+// Env is the development environment of a project. A nil *Env stands for
+// none.
+type Env struct {
+	kind   kind
+	root   string
+	active bool
+}
+
+// New returns the environment of the project at root for the given
+// dev_env mode. With an empty mode a detected environment is returned
+// inactive, to be offered rather than used; off and a project declaring
+// no such environment yield nil. Naming an environment whose tool is not
+// installed is an error.
+func New(mode, root string) (*Env, error) {
+	root = filepath.Clean(root)
+	switch mode {
+	case config.DevEnvOff:
+		return nil, nil
+	case "", config.DevEnvAuto:
+		env := Detect(root)
+		if env != nil {
+			env.active = mode == config.DevEnvAuto
+		}
+		return env, nil
+	}
+	for _, k := range kinds {
+		if k.name != mode {
+			continue
+		}
+		if !exists(filepath.Join(root, k.file)) {
+			return nil, nil
+		}
+		if _, err := exec.LookPath(k.tool); err != nil {
+			return nil, fmt.Errorf("dev_env %s: %s is not installed", mode, k.tool)
+		}
+		return &Env{kind: k, root: root, active: true}, nil
+	}
+	return nil, fmt.Errorf("unknown dev_env %q: expected %q, %q, %q, %q or %q", mode,
+		config.DevEnvAuto, config.DevEnvDirenv, config.DevEnvFlake, config.DevEnvNixShell, config.DevEnvOff)
+}
+
+// Detect returns the first environment the project at root declares whose
+// tool is installed, inactive, or nil when there is none.
+func Detect(root string) *Env {
+	for _, k := range kinds {
+		if !exists(filepath.Join(root, k.file)) {
+			continue
+		}
+		if _, err := exec.LookPath(k.tool); err != nil {
+			continue
+		}
+		return &Env{kind: k, root: root}
+	}
+	return nil
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// Active reports whether commands are to run inside the environment.
+func (e *Env) Active() bool {
+	return e != nil && e.active
+}
+
+// Kind returns the dev_env mode naming the environment, such as "flake".
+func (e *Env) Kind() string {
+	return e.kind.name
+}
+
+// File returns the file declaring the environment, such as "flake.nix".
+func (e *Env) File() string {
+	return e.kind.file
+}
+
+// String describes how commands enter the environment, such as
+// "nix develop /src/app".
+func (e *Env) String() string {
+	switch e.kind.name {
+	case config.DevEnvDirenv:
+		return "direnv exec " + e.root
+	case config.DevEnvFlake:
+		return "nix develop " + e.root
+	}
+	return "nix-shell " + filepath.Join(e.root, e.kind.file)
+}
+
+// Example shows how to run command inside the environment by hand, from
+// the project root.
+func (e *Env) Example(command string) string {
+	switch e.kind.name {
+	case config.DevEnvDirenv:
+		return "direnv exec . " + command
+	case config.DevEnvFlake:
+		return "nix develop --command " + command
+	}
+	return "nix-shell --run " + quote(command)
+}
+
+// Exec runs command with bash in dir inside the environment, streaming its
+// output. It satisfies [shell.Remote]. A non-zero exit is reported as an
+// *exec.ExitError, including the failures of the tool entering the
+// environment, such as a .envrc that was not allowed.
+func (e *Env) Exec(ctx context.Context, dir, command string, stdout, stderr io.Writer) error {
+	if e == nil {
+		return errors.New("no development environment")
+	}
+	cmd := exec.CommandContext(ctx, e.kind.tool, e.args(command)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), shell.CrushEnvMarkers()...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// args returns the arguments of the tool running command inside the
+// environment.
+func (e *Env) args(command string) []string {
+	switch e.kind.name {
+	case config.DevEnvDirenv:
+		return []string{"exec", e.root, "bash", "-c", command}
+	case config.DevEnvFlake:
+		// Flakes may not be enabled in the user's nix.conf.
+		return []string{"--extra-experimental-features", "nix-command flakes", "develop", e.root, "--command", "bash", "-c", command}
+	}
+	return []string{filepath.Join(e.root, e.kind.file), "--run", command}
+}
+
+// quote quotes s as a single sh word.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,116 @@
+package devenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTools puts a direnv, nix and nix-shell on PATH that run their
+// command directly, with DEVENV set to the environment entered.
+func fakeTools(t *testing.T, names ...string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tools are shell scripts")
+	}
+	scripts := map[string]string{
+		"direnv":    "#!/bin/sh\nshift 2\nDEVENV=direnv exec \"$@\"\n",
+		"nix":       "#!/bin/sh\nwhile [ \"$1\" != --command ]; do shift; done\nshift\nDEVENV=flake exec \"$@\"\n",
+		"nix-shell": "#!/bin/sh\nDEVENV=nix-shell exec sh -c \"$3\"\n",
+	}
+	bin := t.TempDir()
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, name), []byte(scripts[name]), 0o755))
+	}
+	return bin
+}
+
+func project(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f), nil, 0o644))
+	}
+	return dir
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("PATH", fakeTools(t, "direnv", "nix"))
+
+	all := project(t, ".envrc", "flake.nix", "shell.nix")
+	env, err := New(config.DevEnvAuto, all)
+	require.NoError(t, err)
+	require.True(t, env.Active())
+	require.Equal(t, config.DevEnvDirenv, env.Kind())
+	require.Equal(t, "direnv exec "+all, env.String())
+
+	// Unset only offers the environment.
+	env, err = New("", all)
+	require.NoError(t, err)
+	require.NotNil(t, env)
+	require.False(t, env.Active())
+
+	env, err = New(config.DevEnvFlake, all)
+	require.NoError(t, err)
+	require.True(t, env.Active())
+	require.Equal(t, "flake.nix", env.File())
+
+	// Environments whose tool is missing are skipped by detection, but
+	// are an error when asked for.
+	env, err = New(config.DevEnvAuto, project(t, "shell.nix"))
+	require.NoError(t, err)
+	require.Nil(t, env)
+	_, err = New(config.DevEnvNixShell, project(t, "shell.nix"))
+	require.ErrorContains(t, err, "nix-shell is not installed")
+
+	for _, mode := range []string{config.DevEnvOff, config.DevEnvDirenv} {
+		env, err = New(mode, project(t, "flake.nix"))
+		require.NoError(t, err, mode)
+		require.Nil(t, env, mode)
+		require.False(t, env.Active(), mode)
+	}
+
+	_, err = New("conda", all)
+	require.ErrorContains(t, err, "unknown dev_env")
+}
+
+func TestExec(t *testing.T) {
+	t.Setenv("PATH", fakeTools(t, "direnv", "nix", "nix-shell")+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for file, mode := range map[string]string{
+		".envrc":    config.DevEnvDirenv,
+		"flake.nix": config.DevEnvFlake,
+		"shell.nix": config.DevEnvNixShell,
+	} {
+		dir := project(t, file)
+		sub := filepath.Join(dir, "sub")
+		require.NoError(t, os.Mkdir(sub, 0o755))
+		env, err := New(mode, dir)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, env.Exec(context.Background(), sub, `echo "$DEVENV $AGENT" && basename "$PWD"`, &out, &out), mode)
+		require.Equal(t, mode+" crush\nsub\n", out.String())
+
+		err = env.Exec(context.Background(), dir, "exit 3", &out, &out)
+		exitErr, ok := errors.AsType[*exec.ExitError](err)
+		require.True(t, ok, mode)
+		require.Equal(t, 3, exitErr.ExitCode())
+	}
+}
+
+func TestExample(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "direnv exec . go test ./...", (&Env{kind: kinds[0]}).Example("go test ./..."))
+	require.Equal(t, "nix develop --command go test ./...", (&Env{kind: kinds[1]}).Example("go test ./..."))
+	require.Equal(t, `nix-shell --run 'echo '\''hi'\'''`, (&Env{kind: kinds[2]}).Example("echo 'hi'"))
+}
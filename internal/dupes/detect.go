@@ -0,0 +1,434 @@
+// Package dupes finds probable copy-paste code in a repository. Files are
+// split into normalized tokens, fingerprinted by winnowing hashes of token
+// shingles, and files sharing fingerprints are compared token by token;
+// the shared runs long enough to matter are grouped into clusters of
+// identical code and ranked by how many tokens they duplicate.
+package dupes
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"hash/fnv"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+)
+
+// Defaults of Options.
+const (
+	DefaultMinTokens   = 50
+	DefaultMaxFiles    = 5000
+	DefaultMaxFileSize = 256 << 10
+)
+
+// window is the winnowing window, in shingles: any run shared by two files
+// that is at least MinTokens+window-1 tokens long shares a fingerprint.
+const window = 8
+
+// maxOccurrences skips fingerprints found more often than this, which are
+// boilerplate such as license headers or import blocks rather than
+// copy-paste.
+const maxOccurrences = 32
+
+// sourceExtensions are the extensions of the files compared.
+var sourceExtensions = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".ts": true, ".tsx": true, ".vue": true, ".svelte": true,
+	".py": true, ".rb": true, ".php": true, ".pl": true, ".lua": true,
+	".java": true, ".kt": true, ".kts": true, ".scala": true, ".groovy": true,
+	".cs": true, ".fs": true, ".swift": true, ".rs": true, ".dart": true,
+	".ex": true, ".exs": true, ".erl": true, ".hs": true, ".ml": true, ".clj": true,
+	".c": true, ".cc": true, ".cpp": true, ".h": true, ".hpp": true, ".m": true, ".mm": true,
+	".sh": true, ".bash": true, ".zsh": true, ".ps1": true, ".sql": true,
+}
+
+// generatedMarkers flag generated files, which are skipped.
+var generatedMarkers = [][]byte{
+	[]byte("Code generated"),
+	[]byte("DO NOT EDIT"),
+	[]byte("@generated"),
+	[]byte("<auto-generated"),
+}
+
+// Options bounds the detection.
+type Options struct {
+	// Path restricts the files compared to those under it or whose
+	// repository relative path contains it.
+	Path string
+	// MinTokens is the shortest shared run reported. Default: 50.
+	MinTokens int
+	// MaxFiles is how many files, in path order, are compared. Default:
+	// 5000.
+	MaxFiles int
+	// MaxFileSize skips larger files, in bytes. Default: 262144.
+	MaxFileSize int64
+	// IgnoreIdentifiers matches copies that renamed variables and
+	// functions, not only ones that changed literals.
+	IgnoreIdentifiers bool
+}
+
+func (o Options) withDefaults() Options {
+	o.MinTokens = cmp.Or(o.MinTokens, DefaultMinTokens)
+	o.MaxFiles = cmp.Or(o.MaxFiles, DefaultMaxFiles)
+	o.MaxFileSize = cmp.Or(o.MaxFileSize, DefaultMaxFileSize)
+	return o
+}
+
+// Location is a copy of a cluster's code.
+type Location struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// Cluster is code found identically, after normalization, in several
+// places.
+type Cluster struct {
+	// Tokens is the length of the shared code in tokens.
+	Tokens    int        `json:"tokens"`
+	Locations []Location `json:"locations"`
+	// Preview is the first non-blank source line of the first copy.
+	Preview string `json:"preview,omitempty"`
+}
+
+// Duplicated returns the tokens that would go away if the copies were one.
+func (c Cluster) Duplicated() int {
+	return c.Tokens * (len(c.Locations) - 1)
+}
+
+// Result is the duplicate code of a repository, with the clusters ranked
+// by duplicated tokens.
+type Result struct {
+	Clusters []Cluster `json:"clusters"`
+	// Files is how many files were compared.
+	Files int `json:"files"`
+	// SkippedLarge, SkippedGenerated and SkippedOverLimit count the files
+	// left out by size, as generated, and beyond MaxFiles.
+	SkippedLarge     int `json:"skipped_large,omitempty"`
+	SkippedGenerated int `json:"skipped_generated,omitempty"`
+	SkippedOverLimit int `json:"skipped_over_limit,omitempty"`
+}
+
+// sourceFile is a tokenized file.
+type sourceFile struct {
+	path  string
+	src   []byte
+	toks  []token
+	lines []int // byte offsets of line starts
+}
+
+// occurrence is a fingerprint position: a file and a token index.
+type occurrence struct {
+	file, pos int
+}
+
+// span is a run of tokens of a file.
+type span struct {
+	file, start, end int
+}
+
+// Detect finds the duplicate code among the source files below root,
+// skipping the paths the repository ignores.
+func Detect(ctx context.Context, root string, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	paths, err := walk(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{}
+	var files []*sourceFile
+	for _, rel := range paths {
+		if !sourceExtensions[strings.ToLower(path.Ext(rel))] || !matchesPath(rel, opts.Path) {
+			continue
+		}
+		if len(files) == opts.MaxFiles {
+			res.SkippedOverLimit++
+			continue
+		}
+		abs := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Stat(abs)
+		if err != nil {
+			continue
+		}
+		if info.Size() > opts.MaxFileSize {
+			res.SkippedLarge++
+			continue
+		}
+		src, err := os.ReadFile(abs)
+		if err != nil {
+			continue
+		}
+		if isGenerated(src) {
+			res.SkippedGenerated++
+			continue
+		}
+		files = append(files, &sourceFile{path: rel, src: src, toks: tokenize(string(src), opts.IgnoreIdentifiers)})
+	}
+	res.Files = len(files)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res.Clusters = cluster(files, matches(ctx, files, opts.MinTokens), opts.MinTokens)
+	return res, ctx.Err()
+}
+
+// matches returns the maximal shared token runs of files, as pairs of
+// spans, found from the files' shared fingerprints.
+func matches(ctx context.Context, files []*sourceFile, minTokens int) [][2]span {
+	index := make(map[uint64][]occurrence)
+	for fi, f := range files {
+		for _, pos := range fingerprints(f.toks, minTokens) {
+			h := shingleHash(f.toks[pos : pos+minTokens])
+			index[h] = append(index[h], occurrence{fi, pos})
+		}
+	}
+
+	seen := make(map[[4]int]bool)
+	var pairs [][2]span
+	for _, occs := range index {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if len(occs) < 2 || len(occs) > maxOccurrences {
+			continue
+		}
+		for i, a := range occs {
+			for _, b := range occs[i+1:] {
+				sa, sb, ok := extend(files, a, b)
+				if !ok || sa.end-sa.start < minTokens {
+					continue
+				}
+				key := [4]int{sa.file, sa.start, sb.file, sb.start}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				pairs = append(pairs, [2]span{sa, sb})
+			}
+		}
+	}
+	return pairs
+}
+
+// extend grows the run the two occurrences share backward and forward as
+// far as their tokens match. Runs within one file stop before they
+// overlap.
+func extend(files []*sourceFile, a, b occurrence) (span, span, bool) {
+	if a.file > b.file || a.file == b.file && a.pos > b.pos {
+		a, b = b, a
+	}
+	ta, tb := files[a.file].toks, files[b.file].toks
+	same := a.file == b.file
+	if same && a.pos == b.pos {
+		return span{}, span{}, false
+	}
+	start := 0
+	for a.pos-start > 0 && b.pos-start > 0 && ta[a.pos-start-1].hash == tb[b.pos-start-1].hash {
+		if same && b.pos-start-1 <= a.pos {
+			break
+		}
+		start++
+	}
+	end := 0
+	for a.pos+end < len(ta) && b.pos+end < len(tb) && ta[a.pos+end].hash == tb[b.pos+end].hash {
+		if same && a.pos+end >= b.pos-start {
+			break
+		}
+		end++
+	}
+	return span{a.file, a.pos - start, a.pos + end}, span{b.file, b.pos - start, b.pos + end}, end > 0
+}
+
+// fingerprints selects the positions of the shingles of toks kept by
+// winnowing: the smallest hash of every window of consecutive shingles.
+func fingerprints(toks []token, k int) []int {
+	n := len(toks) - k + 1
+	if n <= 0 {
+		return nil
+	}
+	hashes := make([]uint64, n)
+	for i := range hashes {
+		hashes[i] = shingleHash(toks[i : i+k])
+	}
+	var picked []int
+	last := -1
+	for w := 0; w+window <= max(n, window); w++ {
+		best := -1
+		for i := w; i < min(w+window, n); i++ {
+			if best < 0 || hashes[i] <= hashes[best] {
+				best = i
+			}
+		}
+		if best != last {
+			picked = append(picked, best)
+			last = best
+		}
+		if w+window >= n {
+			break
+		}
+	}
+	return picked
+}
+
+// shingleHash hashes a run of tokens.
+func shingleHash(toks []token) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, t := range toks {
+		for i := range buf {
+			buf[i] = byte(t.hash >> (8 * i))
+		}
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// cluster groups the matched spans by their token sequence, so that each
+// cluster holds the places one piece of code was copied to, and ranks the
+// clusters by duplicated tokens.
+func cluster(files []*sourceFile, pairs [][2]span, minTokens int) []Cluster {
+	groups := make(map[uint64][]span)
+	add := func(s span) {
+		h := shingleHash(files[s.file].toks[s.start:s.end])
+		if !slices.Contains(groups[h], s) {
+			groups[h] = append(groups[h], s)
+		}
+	}
+	for _, p := range pairs {
+		add(p[0])
+		add(p[1])
+	}
+
+	var candidates [][]span
+	for _, spans := range groups {
+		slices.SortFunc(spans, func(a, b span) int {
+			return cmp.Or(strings.Compare(files[a.file].path, files[b.file].path), cmp.Compare(a.start, b.start))
+		})
+		// Spans of one file that overlap are the same copy found from
+		// different pairs; keep the first.
+		var kept []span
+		for _, s := range spans {
+			if n := len(kept); n > 0 && kept[n-1].file == s.file && s.start < kept[n-1].end {
+				continue
+			}
+			kept = append(kept, s)
+		}
+		if len(kept) >= 2 && kept[0].end-kept[0].start >= minTokens {
+			candidates = append(candidates, kept)
+		}
+	}
+	duplicated := func(spans []span) int {
+		return (spans[0].end - spans[0].start) * (len(spans) - 1)
+	}
+	slices.SortFunc(candidates, func(a, b []span) int {
+		return cmp.Or(
+			cmp.Compare(duplicated(b), duplicated(a)),
+			strings.Compare(files[a[0].file].path, files[b[0].file].path),
+			cmp.Compare(a[0].start, b[0].start),
+		)
+	})
+
+	// A copy matched longer against some copies than against others shows
+	// up in several groups; report each copy once, in its best ranked
+	// cluster.
+	var reported []span
+	overlapsReported := func(s span) bool {
+		return slices.ContainsFunc(reported, func(r span) bool {
+			return r.file == s.file && r.start < s.end && s.start < r.end
+		})
+	}
+	var clusters []Cluster
+	for _, spans := range candidates {
+		if !slices.ContainsFunc(spans, func(s span) bool { return !overlapsReported(s) }) {
+			continue
+		}
+		reported = append(reported, spans...)
+		c := Cluster{Tokens: spans[0].end - spans[0].start}
+		for _, s := range spans {
+			f := files[s.file]
+			c.Locations = append(c.Locations, Location{
+				Path:      f.path,
+				StartLine: f.toks[s.start].line,
+				EndLine:   f.toks[s.end-1].line,
+			})
+		}
+		c.Preview = files[spans[0].file].line(c.Locations[0].StartLine)
+		clusters = append(clusters, c)
+	}
+	return clusters
+}
+
+// line returns the 1-based line n of the file, trimmed.
+func (f *sourceFile) line(n int) string {
+	if f.lines == nil {
+		f.lines = []int{0}
+		for i, c := range f.src {
+			if c == '\n' {
+				f.lines = append(f.lines, i+1)
+			}
+		}
+	}
+	if n < 1 || n > len(f.lines) {
+		return ""
+	}
+	end := len(f.src)
+	if n < len(f.lines) {
+		end = f.lines[n]
+	}
+	text := strings.TrimSpace(string(f.src[f.lines[n-1]:end]))
+	if len(text) > 120 {
+		text = strings.ToValidUTF8(text[:120], "") + "..."
+	}
+	return text
+}
+
+// matchesPath reports whether the relative path is under dir or contains
+// it.
+func matchesPath(rel, dir string) bool {
+	dir = strings.Trim(filepath.ToSlash(dir), "/")
+	return dir == "" || dir == "." || strings.HasPrefix(rel, dir+"/") || strings.Contains(rel, dir)
+}
+
+// isGenerated reports whether the head of src marks it as generated.
+func isGenerated(src []byte) bool {
+	head := src[:min(len(src), 1024)]
+	for _, m := range generatedMarkers {
+		if bytes.Contains(head, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func walk(ctx context.Context, root string) ([]string, error) {
+	walker := fsext.NewFastGlobWalker(root)
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if p != root && walker.ShouldSkipDir(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if walker.ShouldSkip(p) {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, p); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	slices.Sort(files)
+	return files, err
+}
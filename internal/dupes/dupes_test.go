@@ -0,0 +1,118 @@
+package dupes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+// copied is a function long enough to be reported, with its identifiers
+// and literals varying by copy.
+func copied(name, counts, msg string, limit int) string {
+	return strings.NewReplacer("NAME", name, "COUNTS", counts).Replace(fmt.Sprintf(`func NAME(items []string) (map[string]int, error) {
+	COUNTS := make(map[string]int)
+	for i, item := range items {
+		if item == "" {
+			return nil, fmt.Errorf(%q, i)
+		}
+		if len(COUNTS) > %d {
+			break
+		}
+		COUNTS[item]++
+	}
+	return COUNTS, nil
+}
+`, msg, limit))
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	a := tokenize("x := \"a\" + 1 // note\n/* block\n */ y", false)
+	b := tokenize("x := 'b' + 22\ny", false)
+	require.Len(t, a, 7)
+	require.Equal(t, hashes(a), hashes(b))
+	require.Equal(t, 3, a[6].line)
+	require.Equal(t, 2, b[6].line)
+
+	require.NotEqual(t, hashes(tokenize("foo(bar)", false)), hashes(tokenize("baz(qux)", false)))
+	require.Equal(t, hashes(tokenize("foo(bar)", true)), hashes(tokenize("baz(qux)", true)))
+	require.NotEqual(t, hashes(tokenize("if x", true)), hashes(tokenize("for x", true)))
+
+	// An apostrophe in prose does not swallow the rest of the file.
+	require.Len(t, tokenize("don't\nx", false), 4)
+}
+
+func hashes(toks []token) []uint64 {
+	out := make([]uint64, len(toks))
+	for i, t := range toks {
+		out[i] = t.hash
+	}
+	return out
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, root, "a/a.go", "package a\n\n"+copied("countA", "counts", "empty item %d", 10))
+	writeFile(t, root, "b/b.go", "package b\n\nimport \"fmt\"\n\n"+copied("countA", "counts", "blank at %d", 20))
+	writeFile(t, root, "c/c.go", "package c\n\n"+copied("countC", "seen", "empty item %d", 10))
+	writeFile(t, root, "d/d.go", "package d\n\nfunc unique() int { return 1 }\n")
+	writeFile(t, root, "gen/gen.go", "// Code generated by tool. DO NOT EDIT.\n\npackage gen\n\n"+copied("countA", "counts", "x", 1))
+
+	res, err := Detect(t.Context(), root, Options{MinTokens: 40})
+	require.NoError(t, err)
+	require.Equal(t, 4, res.Files)
+	require.Equal(t, 1, res.SkippedGenerated)
+	require.Len(t, res.Clusters, 1)
+	c := res.Clusters[0]
+	require.Len(t, c.Locations, 2)
+	require.Equal(t, "a/a.go", c.Locations[0].Path)
+	require.Equal(t, 3, c.Locations[0].StartLine)
+	require.Equal(t, "b/b.go", c.Locations[1].Path)
+	require.Equal(t, 5, c.Locations[1].StartLine)
+	require.Equal(t, "func countA(items []string) (map[string]int, error) {", c.Preview)
+
+	res, err = Detect(t.Context(), root, Options{MinTokens: 40, IgnoreIdentifiers: true})
+	require.NoError(t, err)
+	require.Len(t, res.Clusters, 1)
+	require.Len(t, res.Clusters[0].Locations, 3)
+
+	report := res.Report(10)
+	require.Contains(t, report, "Duplicate code: 1 clusters")
+	require.Contains(t, report, "1. 3 copies of")
+	require.Contains(t, report, "   - c/c.go:3-15")
+	require.Contains(t, report, "Skipped files: 1 generated")
+
+	res, err = Detect(t.Context(), root, Options{MinTokens: 40, Path: "a", MaxFiles: 1})
+	require.NoError(t, err)
+	require.Empty(t, res.Clusters)
+	require.True(t, strings.HasPrefix(res.Report(10), "Duplicate code: none found (scanned 1 files)"))
+}
+
+func TestDetect_SameFile(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, root, "a.go", "package a\n\n"+copied("one", "a", "x", 1)+"\n"+copied("two", "b", "y", 2))
+
+	res, err := Detect(t.Context(), root, Options{MinTokens: 40, IgnoreIdentifiers: true})
+	require.NoError(t, err)
+	require.Len(t, res.Clusters, 1)
+	require.Equal(t, []Location{
+		{Path: "a.go", StartLine: 3, EndLine: 15},
+		{Path: "a.go", StartLine: 17, EndLine: 29},
+	}, res.Clusters[0].Locations)
+}
@@ -0,0 +1,61 @@
+package dupes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxListedCopies bounds the copies listed per cluster.
+const maxListedCopies = 10
+
+// Report renders the result, listing at most limit clusters, most
+// duplicated first.
+func (r *Result) Report(limit int) string {
+	var sb strings.Builder
+	files := make(map[string]bool)
+	duplicated := 0
+	for _, c := range r.Clusters {
+		duplicated += c.Duplicated()
+		for _, l := range c.Locations {
+			files[l.Path] = true
+		}
+	}
+	if len(r.Clusters) == 0 {
+		fmt.Fprintf(&sb, "Duplicate code: none found (scanned %d files)\n", r.Files)
+	} else {
+		fmt.Fprintf(&sb, "Duplicate code: %d clusters, %d duplicated tokens across %d files (scanned %d files)\n",
+			len(r.Clusters), duplicated, len(files), r.Files)
+	}
+	var skipped []string
+	if r.SkippedLarge > 0 {
+		skipped = append(skipped, fmt.Sprintf("%d too large", r.SkippedLarge))
+	}
+	if r.SkippedGenerated > 0 {
+		skipped = append(skipped, fmt.Sprintf("%d generated", r.SkippedGenerated))
+	}
+	if r.SkippedOverLimit > 0 {
+		skipped = append(skipped, fmt.Sprintf("%d over the file limit", r.SkippedOverLimit))
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(&sb, "Skipped files: %s\n", strings.Join(skipped, ", "))
+	}
+	for i, c := range r.Clusters {
+		if i == limit {
+			fmt.Fprintf(&sb, "\n... and %d more clusters\n", len(r.Clusters)-i)
+			break
+		}
+		first := c.Locations[0]
+		fmt.Fprintf(&sb, "\n%d. %d copies of %d tokens (%d lines)\n", i+1, len(c.Locations), c.Tokens, first.EndLine-first.StartLine+1)
+		for j, l := range c.Locations {
+			if j == maxListedCopies {
+				fmt.Fprintf(&sb, "   - ... and %d more\n", len(c.Locations)-j)
+				break
+			}
+			fmt.Fprintf(&sb, "   - %s:%d-%d\n", l.Path, l.StartLine, l.EndLine)
+		}
+		if c.Preview != "" {
+			fmt.Fprintf(&sb, "   > %s\n", c.Preview)
+		}
+	}
+	return sb.String()
+}
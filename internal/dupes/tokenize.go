@@ -0,0 +1,135 @@
+package dupes
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// token is a normalized source token and the 1-based line it starts on.
+type token struct {
+	hash uint64
+	line int
+}
+
+// Placeholders that literals, and identifiers when ignored, are replaced
+// with, so copies that only change them still match.
+const (
+	stringPlaceholder     = `""`
+	numberPlaceholder     = "0"
+	identifierPlaceholder = "$id"
+)
+
+// keywords are kept as they are when identifiers are ignored, so that
+// control flow still has to match. The list is the union of the keywords
+// of the common languages; missing some only makes matching looser.
+var keywords = map[string]bool{
+	"if": true, "else": true, "for": true, "while": true, "do": true, "switch": true,
+	"case": true, "default": true, "break": true, "continue": true, "return": true,
+	"func": true, "function": true, "def": true, "fn": true, "class": true,
+	"struct": true, "interface": true, "type": true, "enum": true, "var": true,
+	"let": true, "const": true, "new": true, "try": true, "catch": true,
+	"finally": true, "throw": true, "raise": true, "except": true, "import": true,
+	"from": true, "package": true, "go": true, "defer": true, "select": true,
+	"range": true, "map": true, "chan": true, "in": true, "of": true, "not": true,
+	"and": true, "or": true, "is": true, "with": true, "as": true, "yield": true,
+	"async": true, "await": true, "match": true, "impl": true, "pub": true,
+	"static": true, "public": true, "private": true, "protected": true,
+	"void": true, "null": true, "nil": true, "None": true, "true": true,
+	"false": true, "True": true, "False": true, "this": true, "self": true,
+	"super": true, "lambda": true, "end": true, "then": true, "elif": true,
+}
+
+// tokenize splits source into normalized tokens, dropping whitespace and
+// comments. String and number literals are replaced with placeholders;
+// identifiers are too when ignoreIdentifiers is set.
+func tokenize(src string, ignoreIdentifiers bool) []token {
+	var toks []token
+	line := 1
+	emit := func(text string) {
+		h := fnv.New64a()
+		h.Write([]byte(text))
+		toks = append(toks, token{hash: h.Sum64(), line: line})
+	}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(src[i:], "//") || c == '#' && lineComment(src, i):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i = min(len(src), i+end+4)
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' && c != '`' {
+					j++
+				} else if src[j] == '\n' && c != '`' {
+					// An unterminated quote, such as an apostrophe in
+					// prose; keep it as punctuation.
+					break
+				}
+				j++
+			}
+			if j >= len(src) || src[j] != c {
+				emit(string(c))
+				i++
+				continue
+			}
+			emit(stringPlaceholder)
+			line += strings.Count(src[i:j], "\n")
+			i = j + 1
+		case isDigit(c):
+			j := i
+			for j < len(src) && (isIdentChar(src[j]) || src[j] == '.') {
+				j++
+			}
+			emit(numberPlaceholder)
+			i = j
+		case isIdentChar(c):
+			j := i
+			for j < len(src) && isIdentChar(src[j]) {
+				j++
+			}
+			word := src[i:j]
+			if ignoreIdentifiers && !keywords[word] {
+				word = identifierPlaceholder
+			}
+			emit(word)
+			i = j
+		default:
+			emit(src[i : i+1])
+			i++
+		}
+	}
+	return toks
+}
+
+// lineComment reports whether the '#' at i starts a comment rather than,
+// say, a C preprocessor directive or a map literal in some languages. It
+// does so when it opens the line or follows whitespace.
+func lineComment(src string, i int) bool {
+	if strings.HasPrefix(src[i:], "#include") || strings.HasPrefix(src[i:], "#define") ||
+		strings.HasPrefix(src[i:], "#if") || strings.HasPrefix(src[i:], "#endif") {
+		return false
+	}
+	return i == 0 || src[i-1] == ' ' || src[i-1] == '\t' || src[i-1] == '\n'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
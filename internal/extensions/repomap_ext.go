@@ -7,6 +7,8 @@ import (
 	"charm.land/fantasy"
 
 	"github.com/charmbracelet/crush/internal/ext"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/repomap"
 )
 
 // TheRepomapExtension is the singleton repomap extension instance registered
@@ -24,8 +26,14 @@ type RepomapExtension struct {
 	asyncRefresh    func(ctx context.Context, sessionID string) error
 	loadCachedMap   func(sessionID string) (string, int)
 	shouldInjectMap func(ctx context.Context, sessionID string) bool
+	forgetInjection func(sessionID string)
+	forgetSession   func(sessionID string)
 	fileScores      func(ctx context.Context, sessionID string) map[string]float64
+	overrides       func(ctx context.Context, sessionID string) []repomap.Override
+	setOverride     func(ctx context.Context, sessionID string, o repomap.Override) error
+	clearOverrides  func(ctx context.Context, sessionID, target string) error
 	closeSvc        func()
+	refreshEvents   *pubsub.Broker[repomap.RefreshEvent]
 }
 
 func (e *RepomapExtension) Name() string { return "repomap" }
@@ -60,6 +68,11 @@ func (e *RepomapExtension) Shutdown(_ context.Context) error {
 	e.active = false
 	e.loadCachedMap = nil
 	e.shouldInjectMap = nil
+	e.forgetInjection = nil
+	e.forgetSession = nil
+	e.overrides = nil
+	e.setOverride = nil
+	e.clearOverrides = nil
 	return nil
 }
 
@@ -135,6 +148,29 @@ func (e *RepomapExtension) ShouldInjectMap(ctx context.Context, sessionID string
 	return fn(ctx, sessionID)
 }
 
+// ForgetInjections drops the map injection records of a session, so that
+// the next run re-evaluates injection. No-op when the service is
+// unavailable.
+func (e *RepomapExtension) ForgetInjections(sessionID string) {
+	e.mu.RLock()
+	fn := e.forgetInjection
+	e.mu.RUnlock()
+	if fn != nil {
+		fn(sessionID)
+	}
+}
+
+// ForgetSession drops the in-memory repo map state of a session, e.g.
+// once it is deleted. No-op when the service is unavailable.
+func (e *RepomapExtension) ForgetSession(sessionID string) {
+	e.mu.RLock()
+	fn := e.forgetSession
+	e.mu.RUnlock()
+	if fn != nil {
+		fn(sessionID)
+	}
+}
+
 // FileScores returns PageRank-based file scores for the given session.
 func (e *RepomapExtension) FileScores(ctx context.Context, sessionID string) map[string]float64 {
 	e.mu.RLock()
@@ -146,6 +182,57 @@ func (e *RepomapExtension) FileScores(ctx context.Context, sessionID string) map
 	return fn(ctx, sessionID)
 }
 
+// Overrides returns the pin and blacklist overrides persisted for the
+// session.
+func (e *RepomapExtension) Overrides(ctx context.Context, sessionID string) []repomap.Override {
+	e.mu.RLock()
+	fn := e.overrides
+	e.mu.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, sessionID)
+}
+
+// SetOverride pins or blacklists a repo-map target for the session. Returns
+// repomap.ErrUnavailable when the service is not running.
+func (e *RepomapExtension) SetOverride(ctx context.Context, sessionID string, o repomap.Override) error {
+	e.mu.RLock()
+	fn := e.setOverride
+	e.mu.RUnlock()
+	if fn == nil {
+		return repomap.ErrUnavailable
+	}
+	return fn(ctx, sessionID, o)
+}
+
+// ClearOverrides removes one override target for the session, or all of
+// them when target is empty.
+func (e *RepomapExtension) ClearOverrides(ctx context.Context, sessionID, target string) error {
+	e.mu.RLock()
+	fn := e.clearOverrides
+	e.mu.RUnlock()
+	if fn == nil {
+		return repomap.ErrUnavailable
+	}
+	return fn(ctx, sessionID, target)
+}
+
+// SubscribeRefresh returns a channel of repo-map refresh events. Without
+// tree-sitter no events are ever published.
+func (e *RepomapExtension) SubscribeRefresh(ctx context.Context) <-chan pubsub.Event[repomap.RefreshEvent] {
+	return e.refreshBroker().Subscribe(ctx)
+}
+
+func (e *RepomapExtension) refreshBroker() *pubsub.Broker[repomap.RefreshEvent] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.refreshEvents == nil {
+		e.refreshEvents = pubsub.NewBroker[repomap.RefreshEvent]()
+	}
+	return e.refreshEvents
+}
+
 var (
 	_ ext.Extension       = (*RepomapExtension)(nil)
 	_ ext.ToolProvider    = (*RepomapExtension)(nil)
@@ -10,6 +10,7 @@ import (
 	"charm.land/fantasy"
 
 	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/db"
 	"github.com/charmbracelet/crush/internal/ext"
 	"github.com/charmbracelet/crush/internal/repomap"
@@ -38,9 +39,13 @@ func (e *RepomapExtension) buildRepomapTools(ctx context.Context, host ext.HostC
 	}
 
 	q := db.New(rawDB)
-	svc := repomap.NewService(cfg, q, rawDB, host.WorkingDir(), ctx)
+	svcOpts := []repomap.ServiceOption{repomap.WithRefreshPublisher(e.refreshBroker())}
+	if cfg.Options.RepoMap.Watch {
+		svcOpts = append(svcOpts, repomap.WithFileWatcher())
+	}
+	svc := newRepomapService(ctx, cfg, q, rawDB, host.WorkingDir(), svcOpts)
 
-	slog.Info("RepomapExtension: service created", "working_dir", host.WorkingDir())
+	slog.Info("RepomapExtension: service created", "working_dir", host.WorkingDir(), "roots", len(cfg.Options.RepoMap.Roots))
 
 	go svc.PreIndex()
 
@@ -95,9 +100,14 @@ func (e *RepomapExtension) buildRepomapTools(ctx context.Context, host ext.HostC
 		}
 		return svc.ShouldInject(sessionID, runKey)
 	}
+	e.forgetInjection = svc.ForgetInjections
+	e.forgetSession = svc.ForgetSession
 	e.fileScores = func(ctx context.Context, sessionID string) map[string]float64 {
 		return svc.FileScores(ctx, sessionID)
 	}
+	e.overrides = svc.SessionOverrides
+	e.setOverride = svc.SetOverride
+	e.clearOverrides = svc.ClearOverrides
 	e.mu.Unlock()
 
 	return baseRepomapTools(refreshSync, refreshAsync, rawDB)
@@ -119,3 +129,22 @@ func baseRepomapTools(syncFn, asyncFn tools.MapRefreshFn, sqlDB *sql.DB) []fanta
 		tools.NewMapRefreshTool(syncFn, asyncFn),
 	}
 }
+
+// newRepomapService creates the repo-map service over the configured
+// workspace roots, or the working directory alone when there are none or
+// they are invalid.
+func newRepomapService(ctx context.Context, cfg *config.Config, q *db.Queries, rawDB *sql.DB, workingDir string, opts []repomap.ServiceOption) *repomap.Service {
+	if len(cfg.Options.RepoMap.Roots) == 0 {
+		return repomap.NewService(cfg, q, rawDB, workingDir, ctx, opts...)
+	}
+	roots := make([]repomap.Root, 0, len(cfg.Options.RepoMap.Roots))
+	for _, r := range cfg.Options.RepoMap.Roots {
+		roots = append(roots, repomap.Root{Name: r.Name, Dir: r.Path, ExcludeGlobs: r.ExcludeGlobs})
+	}
+	svc, err := repomap.NewServiceMultiRoot(cfg, q, rawDB, workingDir, roots, ctx, opts...)
+	if err != nil {
+		slog.Warn("RepomapExtension: invalid repo map roots, mapping the working directory only", "error", err)
+		return repomap.NewService(cfg, q, rawDB, workingDir, ctx, opts...)
+	}
+	return svc
+}
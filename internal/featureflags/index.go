@@ -0,0 +1,266 @@
+// Package featureflags indexes the feature flags a repository checks, by
+// matching the call shapes of common flag SDKs and configured patterns, so
+// that flag cleanup and rollout sessions can find every use of a flag.
+package featureflags
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+)
+
+// maxSourceSize bounds the size of a scanned file; larger files are
+// skipped.
+const maxSourceSize = 1 << 20
+
+// maxUsageText bounds the source line kept for a usage.
+const maxUsageText = 160
+
+// flagLiteral matches a string literal naming a flag, capturing the name.
+const flagLiteral = `\s*["'\x60]([\w.:/-]+)["'\x60]`
+
+// leadingArg matches an optional first argument, such as the user or
+// context, that some SDKs take before the flag.
+const leadingArg = `(?:[^,()"'\x60]+,)?`
+
+// builtinPatterns are the flag checks of common SDKs. Each captures the
+// flag's name.
+var builtinPatterns = []struct{ sdk, expr string }{
+	{"LaunchDarkly", `\b(?:[bB]ool|[sS]tring|[iI]nt|[fF]loat|[dD]ouble|[nN]umber|[jJ]son|JSON)?[vV]ariation(?:Detail)?(?:Ctx)?\(` + flagLiteral},
+	{"Unleash", `\b(?:is_?[eE]nabled\??|IsEnabled)\(` + flagLiteral},
+	{"OpenFeature", `\b(?:get_?)?(?:[bB]oolean|[sS]tring|[nN]umber|[iI]nteger|[fF]loat|[oO]bject)_?[vV]alue(?:_?[dD]etails)?\(` + leadingArg + flagLiteral},
+	{"GrowthBook", `\b(?:isOn|is_on|IsOn|isOff|is_off|getFeatureValue|get_feature_value|evalFeature|eval_feature)\(` + flagLiteral},
+	{"Statsig", `\b(?:checkGate|check_gate|CheckGate|getFeatureGate|get_feature_gate)\(` + leadingArg + flagLiteral},
+	{"Split", `\b(?:getTreatment|get_treatment|getTreatmentWithConfig|get_treatment_with_config|Treatment)\(` + leadingArg + flagLiteral},
+	{"PostHog", `\b(?:isFeatureEnabled|feature_enabled|getFeatureFlag|get_feature_flag)\(` + flagLiteral},
+	{"Flagsmith", `\b(?:hasFeature|has_feature|is_feature_enabled)\(` + flagLiteral},
+	{"Flipper", `\b(?:Flipper|Feature)(?:\.enabled\?\(|\.disabled\?\(|\[)\s*(?::|["'])([\w.:/-]+)`},
+}
+
+// sourceExtensions are the extensions of the files scanned for flags.
+var sourceExtensions = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".ts": true, ".tsx": true, ".vue": true, ".svelte": true,
+	".py": true, ".rb": true, ".erb": true, ".php": true,
+	".java": true, ".kt": true, ".kts": true, ".scala": true,
+	".cs": true, ".swift": true, ".rs": true, ".dart": true,
+	".ex": true, ".exs": true, ".c": true, ".cc": true, ".cpp": true,
+	".h": true, ".hpp": true, ".m": true, ".mm": true,
+}
+
+// Pattern recognizes flag checks. Its first capture group is the flag's
+// name.
+type Pattern struct {
+	SDK string
+	re  *regexp.Regexp
+}
+
+// Patterns returns the built-in SDK patterns followed by the custom
+// regular expressions, each of which must have a capture group naming the
+// flag.
+func Patterns(custom []string) ([]Pattern, error) {
+	patterns := make([]Pattern, 0, len(builtinPatterns)+len(custom))
+	for _, p := range builtinPatterns {
+		patterns = append(patterns, Pattern{SDK: p.sdk, re: regexp.MustCompile(p.expr)})
+	}
+	for _, expr := range custom {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature flag pattern %q: %w", expr, err)
+		}
+		if re.NumSubexp() == 0 {
+			return nil, fmt.Errorf("feature flag pattern %q has no capture group for the flag name", expr)
+		}
+		patterns = append(patterns, Pattern{SDK: "custom", re: re})
+	}
+	return patterns, nil
+}
+
+// Usage is a place a flag is checked.
+type Usage struct {
+	Path string
+	Line int
+	SDK  string
+	// Text is the trimmed source line.
+	Text string
+}
+
+// Flag is a feature flag and the places it is checked, in path and line
+// order.
+type Flag struct {
+	Name   string
+	Usages []Usage
+}
+
+// Files returns the number of files the flag is checked in.
+func (f *Flag) Files() int {
+	files := 0
+	for i, u := range f.Usages {
+		if i == 0 || u.Path != f.Usages[i-1].Path {
+			files++
+		}
+	}
+	return files
+}
+
+// Index is the feature flags of a repository, in name order.
+type Index struct {
+	Flags []*Flag
+	// Scanned is the number of files scanned.
+	Scanned int
+}
+
+// SourceFiles returns the files scanned for flags among the
+// slash-separated, repository relative paths.
+func SourceFiles(relPaths []string) []string {
+	var files []string
+	for _, p := range relPaths {
+		if sourceExtensions[strings.ToLower(path.Ext(p))] {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// Stamp fingerprints the files' paths, sizes and modification times, so
+// that callers can tell when the index needs rebuilding.
+func Stamp(root string, files []string) uint64 {
+	h := fnv.New64a()
+	for _, f := range files {
+		h.Write([]byte(f))
+		if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(f))); err == nil {
+			fmt.Fprintf(h, "\x00%d\x00%d\x00", info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return h.Sum64()
+}
+
+// Load scans the source files below root, skipping the paths the
+// repository ignores, with the built-in and custom patterns.
+func Load(ctx context.Context, root string, custom []string) (*Index, error) {
+	patterns, err := Patterns(custom)
+	if err != nil {
+		return nil, err
+	}
+	walker := fsext.NewFastGlobWalker(root)
+	var files []string
+	err = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if p != root && walker.ShouldSkipDir(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		if rel = filepath.ToSlash(rel); sourceExtensions[strings.ToLower(path.Ext(rel))] && !walker.ShouldSkip(p) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Scan(ctx, root, files, patterns)
+}
+
+// Scan indexes the flags checked in files, repository-relative paths below
+// root.
+func Scan(ctx context.Context, root string, files []string, patterns []Pattern) (*Index, error) {
+	byName := make(map[string]*Flag)
+	ix := &Index{}
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		abs := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Stat(abs)
+		if err != nil || info.Size() > maxSourceSize {
+			continue
+		}
+		f, err := os.Open(abs)
+		if err != nil {
+			continue
+		}
+		ix.Scanned++
+		scanFile(f, rel, patterns, byName)
+		f.Close()
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(byName)) {
+		flag := byName[name]
+		slices.SortFunc(flag.Usages, func(a, b Usage) int {
+			return cmp.Or(strings.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line))
+		})
+		ix.Flags = append(ix.Flags, flag)
+	}
+	return ix, nil
+}
+
+// scanFile records the flags checked on each line of f. A flag matched by
+// several patterns on one line is recorded once, for the first pattern.
+func scanFile(f *os.File, rel string, patterns []Pattern, byName map[string]*Flag) {
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), maxSourceSize)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		var seen []string
+		for _, p := range patterns {
+			for _, m := range p.re.FindAllStringSubmatch(text, -1) {
+				name := m[1]
+				if name == "" || slices.Contains(seen, name) {
+					continue
+				}
+				seen = append(seen, name)
+				flag := byName[name]
+				if flag == nil {
+					flag = &Flag{Name: name}
+					byName[name] = flag
+				}
+				flag.Usages = append(flag.Usages, Usage{
+					Path: rel,
+					Line: line,
+					SDK:  p.SDK,
+					Text: truncate(strings.TrimSpace(text), maxUsageText),
+				})
+			}
+		}
+	}
+}
+
+// Flag returns the flag named name, or nil.
+func (ix *Index) Flag(name string) *Flag {
+	i, ok := slices.BinarySearchFunc(ix.Flags, name, func(f *Flag, name string) int {
+		return strings.Compare(f.Name, name)
+	})
+	if !ok {
+		return nil
+	}
+	return ix.Flags[i]
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "") + "..."
+}
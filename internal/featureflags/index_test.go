@@ -0,0 +1,140 @@
+package featureflags
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
+func usageLines(f *Flag) []string {
+	var lines []string
+	for _, u := range f.Usages {
+		lines = append(lines, fmt.Sprintf("%s %s:%d", u.SDK, u.Path, u.Line))
+	}
+	return lines
+}
+
+func TestLoadBuiltinPatterns(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{
+		"server/checkout.go": `package server
+
+func checkout() {
+	if ld.BoolVariation("new-checkout", user, false) {
+	}
+	on, _ := client.BooleanValue(ctx, "dark-mode", false, evalCtx)
+	if unleash.IsEnabled("new-checkout") {
+	}
+}`,
+		"web/app.tsx": `const on = ldClient.variation('new-checkout', ctx, false);
+if (statsig.checkGate(user, "beta_search")) {}
+posthog.isFeatureEnabled("onboarding-v2")
+gb.isOn(` + "`dark-mode`" + `)`,
+		"app/models/user.rb": `return unless Flipper.enabled?(:beta_search, user)
+Flipper[:legacy_export].enable`,
+		"svc/flags.py": `if flags.is_feature_enabled("onboarding-v2"):
+    treatment = split.get_treatment(key, "pricing_page")`,
+		"README.md":            `isEnabled("not-source")`,
+		"vendor/lib/vendor.go": `unleash.IsEnabled("vendored")`,
+		".gitignore":           "vendor/\n",
+	})
+
+	ix, err := Load(t.Context(), root, nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, ix.Scanned)
+
+	var names []string
+	for _, f := range ix.Flags {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"beta_search", "dark-mode", "legacy_export", "new-checkout", "onboarding-v2", "pricing_page"}, names)
+
+	require.Equal(t, []string{"Flipper app/models/user.rb:1", "Statsig web/app.tsx:2"}, usageLines(ix.Flag("beta_search")))
+	require.Equal(t, []string{"OpenFeature server/checkout.go:6", "GrowthBook web/app.tsx:4"}, usageLines(ix.Flag("dark-mode")))
+	require.Equal(t, []string{
+		"LaunchDarkly server/checkout.go:4",
+		"Unleash server/checkout.go:7",
+		"LaunchDarkly web/app.tsx:1",
+	}, usageLines(ix.Flag("new-checkout")))
+	require.Equal(t, 2, ix.Flag("new-checkout").Files())
+	require.Equal(t, []string{"Flagsmith svc/flags.py:1", "PostHog web/app.tsx:3"}, usageLines(ix.Flag("onboarding-v2")))
+	require.Equal(t, []string{"Split svc/flags.py:2"}, usageLines(ix.Flag("pricing_page")))
+	require.Nil(t, ix.Flag("vendored"))
+}
+
+func TestLoadCustomPatterns(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{
+		"main.go": `if features.On(FlagNewBilling) || flags.Enabled("new-billing") {}`,
+	})
+
+	ix, err := Load(t.Context(), root, []string{`features\.On\((Flag\w+)\)`, `flags\.Enabled\("([^"]+)"\)`})
+	require.NoError(t, err)
+	require.Len(t, ix.Flags, 2)
+	require.Equal(t, "FlagNewBilling", ix.Flags[0].Name)
+	require.Equal(t, "custom", ix.Flags[0].Usages[0].SDK)
+	require.Equal(t, "new-billing", ix.Flags[1].Name)
+
+	_, err = Load(t.Context(), root, []string{`flags\.Enabled`})
+	require.ErrorContains(t, err, "no capture group")
+	_, err = Load(t.Context(), root, []string{`(`})
+	require.ErrorContains(t, err, "invalid feature flag pattern")
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	root := writeFiles(t, map[string]string{
+		"a.go": "unleash.IsEnabled(\"new-checkout\")\n\nunleash.IsEnabled(\"checkout-v1\")\n",
+		"b.py": "if flags.is_feature_enabled(\"new-checkout\"):\n",
+	})
+	ix, err := Load(t.Context(), root, nil)
+	require.NoError(t, err)
+
+	out, err := ix.List()
+	require.NoError(t, err)
+	require.Equal(t, `2 feature flags in 2 source files:
+  checkout-v1 (Unleash): 1 uses in 1 files
+  new-checkout (Unleash, Flagsmith): 2 uses in 2 files
+`, out)
+
+	out, err = ix.WhereUsed("new-checkout")
+	require.NoError(t, err)
+	require.Equal(t, `Flag new-checkout: 2 uses in 2 files
+  a.go:1: unleash.IsEnabled("new-checkout")
+  b.py:1: if flags.is_feature_enabled("new-checkout"):
+`, out)
+
+	out, err = ix.WhereUsed("CHECKOUT")
+	require.NoError(t, err)
+	require.Contains(t, out, "Flag checkout-v1: 1 uses in 1 files\n  a.go:3: ")
+	require.Contains(t, out, "\n\nFlag new-checkout: ")
+
+	_, err = ix.WhereUsed("billing")
+	require.ErrorContains(t, err, `no feature flag matches "billing"`)
+
+	require.Equal(t, "Feature flags (2):\n  checkout-v1: a.go\n  new-checkout: a.go, b.py\n", ix.Prelude(0))
+	require.Contains(t, ix.Prelude(1), "  ... and 1 more flags (feature_flags lists them)\n")
+
+	empty, err := Scan(t.Context(), root, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, empty.Prelude(0))
+	_, err = empty.List()
+	require.ErrorContains(t, err, "no feature flag checks found")
+}
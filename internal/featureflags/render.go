@@ -0,0 +1,95 @@
+package featureflags
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxListedUsages is the most usages WhereUsed lists per flag.
+const maxListedUsages = 50
+
+// List writes one line per flag with how many times and in how many files
+// it is checked.
+func (ix *Index) List() (string, error) {
+	if len(ix.Flags) == 0 {
+		return "", fmt.Errorf("no feature flag checks found in %d source files; configure feature_flags.patterns for in-house flag helpers", ix.Scanned)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d feature flags in %d source files:\n", len(ix.Flags), ix.Scanned)
+	for _, f := range ix.Flags {
+		fmt.Fprintf(&sb, "  %s (%s): %d uses in %d files\n", f.Name, f.sdks(), len(f.Usages), f.Files())
+	}
+	return sb.String(), nil
+}
+
+// WhereUsed lists the usages of the flags named like name, matching a
+// case-insensitive substring. A flag matched exactly is listed alone.
+func (ix *Index) WhereUsed(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	flags := []*Flag{ix.Flag(name)}
+	if flags[0] == nil {
+		flags = flags[:0]
+		lower := strings.ToLower(name)
+		for _, f := range ix.Flags {
+			if strings.Contains(strings.ToLower(f.Name), lower) {
+				flags = append(flags, f)
+			}
+		}
+	}
+	if len(flags) == 0 {
+		return "", fmt.Errorf("no feature flag matches %q; call without a flag to list them", name)
+	}
+
+	var sb strings.Builder
+	for i, f := range flags {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "Flag %s: %d uses in %d files\n", f.Name, len(f.Usages), f.Files())
+		for j, u := range f.Usages {
+			if j == maxListedUsages {
+				fmt.Fprintf(&sb, "  ... and %d more\n", len(f.Usages)-maxListedUsages)
+				break
+			}
+			fmt.Fprintf(&sb, "  %s:%d: %s\n", u.Path, u.Line, u.Text)
+		}
+	}
+	return sb.String(), nil
+}
+
+// Prelude renders the flags compactly for the repository map: one line per
+// flag naming the files that check it. It shows at most maxFlags flags and
+// returns "" when there are none.
+func (ix *Index) Prelude(maxFlags int) string {
+	if ix == nil || len(ix.Flags) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Feature flags (%d):\n", len(ix.Flags))
+	for i, f := range ix.Flags {
+		if maxFlags > 0 && i == maxFlags {
+			fmt.Fprintf(&sb, "  ... and %d more flags (feature_flags lists them)\n", len(ix.Flags)-maxFlags)
+			break
+		}
+		var files []string
+		for j, u := range f.Usages {
+			if j == 0 || u.Path != f.Usages[j-1].Path {
+				files = append(files, u.Path)
+			}
+		}
+		fmt.Fprintf(&sb, "  %s: %s\n", f.Name, strings.Join(files, ", "))
+	}
+	return sb.String()
+}
+
+// sdks returns the SDKs the flag is checked with, in order of first use.
+func (f *Flag) sdks() string {
+	var sdks []string
+	for _, u := range f.Usages {
+		if !slices.Contains(sdks, u.SDK) {
+			sdks = append(sdks, u.SDK)
+		}
+	}
+	return strings.Join(sdks, ", ")
+}
@@ -0,0 +1,289 @@
+// Package handoff moves a session between machines as a single encrypted
+// bundle: the transcript, the template notes and working set, the LCM
+// large files referenced by recent turns and the workspace config
+// overrides.
+package handoff
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+)
+
+// FormatVersion is the bundle layout version. Import rejects bundles with
+// a newer version.
+const FormatVersion = 1
+
+// DefaultTurns is the number of recent turns whose LCM large files are
+// bundled when ExportOptions.Turns is not positive.
+const DefaultTurns = 20
+
+// ErrSessionExists is returned by Import when the bundled session is
+// already in the target database.
+var ErrSessionExists = errors.New("session already exists")
+
+// Bundle is the decrypted content of a handoff file.
+type Bundle struct {
+	Version   int   `json:"version"`
+	CreatedAt int64 `json:"created_at"`
+
+	Session  db.Session       `json:"session"`
+	Messages []db.Message     `json:"messages"`
+	Parts    []db.MessagePart `json:"parts,omitempty"`
+
+	// PinnedNotes and WorkingSet are the template notes and working-set
+	// paths found in the transcript, listed for the user on import.
+	PinnedNotes []string `json:"pinned_notes,omitempty"`
+	WorkingSet  []string `json:"working_set,omitempty"`
+
+	// LargeFiles are the LCM large files referenced by the last turns.
+	LargeFiles []db.LcmLargeFile `json:"large_files,omitempty"`
+
+	// Config is the raw workspace config (.crush/crush.json), if any.
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Turns is how many recent turns, counted by user messages, are
+	// scanned for LCM large file references. Default: DefaultTurns.
+	Turns int
+	// Config is the workspace config file to carry along.
+	Config []byte
+}
+
+// lcmFileRef matches LCM large file IDs (see lcm.GenerateFileID).
+var lcmFileRef = regexp.MustCompile(`file_[0-9a-f]{16}`)
+
+// Export collects the bundle of the session with the given ID.
+func Export(ctx context.Context, q db.Querier, sessionID string, opts ExportOptions) (*Bundle, error) {
+	sess, err := q.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("loading session: %w", err)
+	}
+	msgs, err := q.ListMessagesBySessionSeq(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("loading messages: %w", err)
+	}
+
+	b := &Bundle{
+		Version:   FormatVersion,
+		CreatedAt: time.Now().Unix(),
+		Session:   sess,
+		Messages:  msgs,
+	}
+	for _, msg := range msgs {
+		parts, err := q.GetMessagePartsByMessageID(ctx, msg.ID)
+		if err != nil {
+			return nil, fmt.Errorf("loading parts of message %s: %w", msg.ID, err)
+		}
+		b.Parts = append(b.Parts, parts...)
+	}
+	b.PinnedNotes, b.WorkingSet = templateContext(msgs)
+
+	turns := opts.Turns
+	if turns <= 0 {
+		turns = DefaultTurns
+	}
+	for _, id := range referencedFiles(recentTurns(msgs, turns)) {
+		file, err := q.GetLcmLargeFile(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading LCM file %s: %w", id, err)
+		}
+		b.LargeFiles = append(b.LargeFiles, file)
+	}
+
+	if len(opts.Config) > 0 {
+		if !json.Valid(opts.Config) {
+			return nil, errors.New("workspace config is not valid JSON")
+		}
+		b.Config = json.RawMessage(opts.Config)
+	}
+	return b, nil
+}
+
+// Import writes the bundled session into the database in one transaction.
+// The session keeps its ID, so LCM file references stay valid, and is
+// imported as a top-level session.
+func Import(ctx context.Context, conn *sql.DB, q *db.Queries, b *Bundle) error {
+	if b.Version > FormatVersion {
+		return fmt.Errorf("bundle version %d is newer than supported version %d", b.Version, FormatVersion)
+	}
+	if _, err := q.GetSessionByID(ctx, b.Session.ID); err == nil {
+		return fmt.Errorf("%w: %s", ErrSessionExists, b.Session.ID)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("checking session: %w", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+	qtx := q.WithTx(tx)
+
+	s := b.Session
+	if err := qtx.ImportSession(ctx, db.ImportSessionParams{
+		ID:               s.ID,
+		Title:            s.Title,
+		PromptTokens:     s.PromptTokens,
+		CompletionTokens: s.CompletionTokens,
+		Cost:             s.Cost,
+		SummaryMessageID: s.SummaryMessageID,
+		Todos:            s.Todos,
+		UpdatedAt:        s.UpdatedAt,
+		CreatedAt:        s.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("importing session: %w", err)
+	}
+	for _, m := range b.Messages {
+		if err := qtx.ImportMessage(ctx, db.ImportMessageParams{
+			ID:               m.ID,
+			SessionID:        s.ID,
+			Role:             m.Role,
+			Parts:            m.Parts,
+			Model:            m.Model,
+			Provider:         m.Provider,
+			IsSummaryMessage: m.IsSummaryMessage,
+			Seq:              m.Seq,
+			TokenCount:       m.TokenCount,
+			CreatedAt:        m.CreatedAt,
+			UpdatedAt:        m.UpdatedAt,
+			FinishedAt:       m.FinishedAt,
+			SubmittedAt:      m.SubmittedAt,
+			SentToLlmAt:      m.SentToLlmAt,
+			FirstTokenAt:     m.FirstTokenAt,
+			CompletedAt:      m.CompletedAt,
+			PromptTokens:     m.PromptTokens,
+			CompletionTokens: m.CompletionTokens,
+			Cost:             m.Cost,
+			RoutingReason:    m.RoutingReason,
+		}); err != nil {
+			return fmt.Errorf("importing message %s: %w", m.ID, err)
+		}
+	}
+	for _, p := range b.Parts {
+		if _, err := qtx.InsertMessagePart(ctx, db.InsertMessagePartParams{
+			PartID:      p.PartID,
+			MessageID:   p.MessageID,
+			SessionID:   s.ID,
+			PartType:    p.PartType,
+			PartIndex:   p.PartIndex,
+			ContentJson: p.ContentJson,
+		}); err != nil {
+			return fmt.Errorf("importing message part %s: %w", p.PartID, err)
+		}
+	}
+	for _, f := range b.LargeFiles {
+		if err := qtx.InsertLcmLargeFile(ctx, db.InsertLcmLargeFileParams{
+			FileID:             f.FileID,
+			SessionID:          s.ID,
+			OriginalPath:       f.OriginalPath,
+			Content:            f.Content,
+			TokenCount:         f.TokenCount,
+			ExplorationSummary: f.ExplorationSummary,
+			ExplorerUsed:       f.ExplorerUsed,
+		}); err != nil {
+			return fmt.Errorf("importing LCM file %s: %w", f.FileID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// recentTurns returns the messages of the last n turns, a turn starting at
+// a user message.
+func recentTurns(msgs []db.Message, n int) []db.Message {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role != "user" {
+			continue
+		}
+		if n--; n == 0 {
+			return msgs[i:]
+		}
+	}
+	return msgs
+}
+
+// referencedFiles returns the LCM file IDs mentioned in msgs, in order of
+// first mention.
+func referencedFiles(msgs []db.Message) []string {
+	var ids []string
+	for _, m := range msgs {
+		for _, id := range lcmFileRef.FindAllString(m.Parts, -1) {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// templateContext extracts the <pinned_notes> and <working_set> blocks that
+// session templates prepend to the first prompt.
+func templateContext(msgs []db.Message) (notes, files []string) {
+	for _, m := range msgs {
+		if m.Role != "user" {
+			continue
+		}
+		for _, text := range textParts(m.Parts) {
+			for _, line := range block(text, "pinned_notes") {
+				notes = append(notes, strings.TrimPrefix(line, "- "))
+			}
+			files = append(files, block(text, "working_set")...)
+		}
+		if len(notes) > 0 || len(files) > 0 {
+			break
+		}
+	}
+	return notes, files
+}
+
+// textParts returns the text of the text parts in the stored parts JSON.
+func textParts(raw string) []string {
+	var parts []struct {
+		Type string `json:"type"`
+		Data struct {
+			Text string `json:"text"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parts); err != nil {
+		return nil
+	}
+	var out []string
+	for _, p := range parts {
+		if p.Type == "text" {
+			out = append(out, p.Data.Text)
+		}
+	}
+	return out
+}
+
+// block returns the non-empty lines between <tag> and </tag> in text.
+func block(text, tag string) []string {
+	_, rest, ok := strings.Cut(text, "<"+tag+">")
+	if !ok {
+		return nil
+	}
+	body, _, ok := strings.Cut(rest, "</"+tag+">")
+	if !ok {
+		return nil
+	}
+	var lines []string
+	for line := range strings.SplitSeq(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
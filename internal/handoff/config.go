@@ -0,0 +1,49 @@
+package handoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/charmbracelet/crush/internal/config"
+)
+
+// ConfigKeys returns the top-level keys of the bundled workspace config.
+func ConfigKeys(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("decoding bundled config: %w", err)
+	}
+	return slices.Sorted(maps.Keys(fields)), nil
+}
+
+// ApplyConfig copies the top-level keys of the bundled workspace config
+// that the local workspace config does not set, so local settings win. It
+// returns the keys it applied. The config can name MCP and LSP commands
+// and hooks to run, so it is only applied when the user asks for it.
+func ApplyConfig(store *config.ConfigStore, raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("decoding bundled config: %w", err)
+	}
+	kv := make(map[string]any)
+	for key, value := range fields {
+		if !store.HasConfigField(config.ScopeWorkspace, key) {
+			kv[key] = value
+		}
+	}
+	if len(kv) == 0 {
+		return nil, nil
+	}
+	if err := store.SetConfigFields(config.ScopeWorkspace, kv); err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(kv)), nil
+}
@@ -0,0 +1,116 @@
+package handoff
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A handoff file is magic | salt | nonce | AES-256-GCM(gzip(JSON bundle)),
+// keyed by PBKDF2-SHA256 of the passphrase. The magic is the additional
+// data, so a file of another format never decrypts.
+const (
+	magic         = "CRUSHHO1"
+	saltSize      = 16
+	keySize       = 32
+	kdfIterations = 600_000
+)
+
+// ErrDecrypt is returned by Read when the passphrase is wrong or the file
+// was modified.
+var ErrDecrypt = errors.New("cannot decrypt handoff bundle: wrong passphrase or corrupted file")
+
+// Write encrypts b with passphrase and writes it to w.
+func Write(w io.Writer, b *Bundle, passphrase string) error {
+	if passphrase == "" {
+		return errors.New("a passphrase is required")
+	}
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return fmt.Errorf("encoding bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compressing bundle: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	out := make([]byte, 0, len(magic)+saltSize+len(nonce)+plain.Len()+aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = aead.Seal(out, nonce, plain.Bytes(), []byte(magic))
+	_, err = w.Write(out)
+	return err
+}
+
+// Read decrypts a bundle written by Write.
+func Read(r io.Reader, passphrase string) (*Bundle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		return nil, errors.New("not a crush handoff bundle")
+	}
+	data = data[len(magic):]
+	if len(data) < saltSize {
+		return nil, ErrDecrypt
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, data := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, data, []byte(magic))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing bundle: %w", err)
+	}
+	defer zr.Close()
+	var b Bundle
+	if err := json.NewDecoder(zr).Decode(&b); err != nil {
+		return nil, fmt.Errorf("decoding bundle: %w", err)
+	}
+	return &b, nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, kdfIterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,101 @@
+package handoff
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/stretchr/testify/require"
+)
+
+func openDB(t *testing.T) (*db.Queries, *sql.DB) {
+	t.Helper()
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return db.New(conn), conn
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	src, srcConn := openDB(t)
+	sess, err := session.NewService(src, srcConn).Create(ctx, "Fix health endpoint")
+	require.NoError(t, err)
+
+	const fileID = "file_0123456789abcdef"
+	require.NoError(t, src.InsertLcmLargeFile(ctx, db.InsertLcmLargeFileParams{
+		FileID:       fileID,
+		SessionID:    sess.ID,
+		OriginalPath: "build.log",
+		Content:      sql.NullString{String: "lots of output", Valid: true},
+		TokenCount:   4,
+	}))
+
+	msgs := message.NewService(src)
+	prompt := "<pinned_notes>\n- Keep the API stable\n</pinned_notes>\n<working_set>\ninternal/server/health.go\n</working_set>\nThe endpoint returns 500"
+	for _, p := range []message.CreateMessageParams{
+		{Role: message.User, Parts: []message.ContentPart{message.TextContent{Text: prompt}}},
+		{Role: message.Assistant, Parts: []message.ContentPart{message.TextContent{Text: "Stored the log as " + fileID}}},
+		{Role: message.User, Parts: []message.ContentPart{message.TextContent{Text: "Now add a test"}}},
+	} {
+		_, err := msgs.Create(ctx, sess.ID, p)
+		require.NoError(t, err)
+	}
+
+	bundle, err := Export(ctx, src, sess.ID, ExportOptions{Config: []byte(`{"options":{"debug":true}}`)})
+	require.NoError(t, err)
+	require.Len(t, bundle.Messages, 3)
+	require.Equal(t, []string{"Keep the API stable"}, bundle.PinnedNotes)
+	require.Equal(t, []string{"internal/server/health.go"}, bundle.WorkingSet)
+	require.Len(t, bundle.LargeFiles, 1)
+
+	lastTurn, err := Export(ctx, src, sess.ID, ExportOptions{Turns: 1})
+	require.NoError(t, err)
+	require.Empty(t, lastTurn.LargeFiles, "the file is referenced before the last turn")
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, bundle, "correct horse"))
+	_, err = Read(bytes.NewReader(buf.Bytes()), "wrong")
+	require.ErrorIs(t, err, ErrDecrypt)
+	got, err := Read(bytes.NewReader(buf.Bytes()), "correct horse")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"options":{"debug":true}}`, string(got.Config))
+
+	dst, dstConn := openDB(t)
+	require.NoError(t, Import(ctx, dstConn, dst, got))
+	require.ErrorIs(t, Import(ctx, dstConn, dst, got), ErrSessionExists)
+
+	imported, err := dst.GetSessionByID(ctx, sess.ID)
+	require.NoError(t, err)
+	require.Equal(t, sess.Title, imported.Title)
+	require.Equal(t, int64(3), imported.MessageCount)
+
+	importedMsgs, err := message.NewService(dst).List(ctx, sess.ID)
+	require.NoError(t, err)
+	require.Len(t, importedMsgs, 3)
+	require.Equal(t, "Now add a test", importedMsgs[2].Content().Text)
+
+	file, err := dst.GetLcmLargeFile(ctx, fileID)
+	require.NoError(t, err)
+	require.Equal(t, "lots of output", file.Content.String)
+}
+
+func TestConfigKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, err := ConfigKeys([]byte(`{"mcp":{"x":{"command":"run"}},"hooks":{},"options":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"hooks", "mcp", "options"}, keys)
+
+	keys, err = ConfigKeys(nil)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	_, err = ConfigKeys([]byte(`[`))
+	require.Error(t, err)
+}
@@ -162,6 +162,9 @@ func TestConfigFlags(t *testing.T) {
 			"lcm_bindle": true, "lcm_ancestry": true, "lcm_dolt": true,
 			"lcm_archive": true, "lcm_sprig": true, "lcm_time_query": true,
 			"lcm_file_search": true, "lcm_active_context": true, "lcm_lineage": true,
+			"lcm_compact": true, "knowledge_lookup": true, "schema_lookup": true,
+			"feature_flags": true, "tech_debt": true, "licenses": true, "duplicate_code": true, "tail_logs": true,
+			"processes": true, "ports": true, "system_resources": true,
 		}
 		for _, tool := range task.AllowedTools {
 			require.True(t, readOnly[tool],
@@ -219,6 +222,7 @@ func TestDBMigrations(t *testing.T) {
 	assertTableExists(t, ctx, conn, "repo_map_tags")
 	assertTableExists(t, ctx, conn, "repo_map_session_rankings")
 	assertTableExists(t, ctx, conn, "repo_map_session_read_only")
+	assertTableExists(t, ctx, conn, "repo_map_tag_cache")
 
 	queries := db.New(conn)
 
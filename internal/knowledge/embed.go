@@ -0,0 +1,212 @@
+package knowledge
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+)
+
+// Embedder turns texts into vectors for semantic retrieval.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// embedBatchSize is the number of texts sent in a single request.
+const embedBatchSize = 64
+
+type openAIEmbedder struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+	headers map[string]string
+}
+
+// NewOpenAIEmbedder returns an [Embedder] for an OpenAI-compatible
+// /embeddings endpoint.
+func NewOpenAIEmbedder(baseURL, apiKey, model string, headers map[string]string) Embedder {
+	return &openAIEmbedder{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		headers: headers,
+	}
+}
+
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embedBatchSize {
+		batch := texts[start:min(start+embedBatchSize, len(texts))]
+		got, err := e.embedBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, got...)
+	}
+	return vectors, nil
+}
+
+func (e *openAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]any{"model": e.model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("embedding request failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var out struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid embedding response: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(vectors) {
+			return nil, fmt.Errorf("invalid embedding response: index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("invalid embedding response: missing vector %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// vectorCache stores embeddings keyed by a hash of the embedded text so
+// unchanged sections are not re-embedded. It is persisted as JSON in the
+// data directory, outside the versioned knowledge folder.
+type vectorCache struct {
+	mu      sync.Mutex
+	path    string
+	loaded  bool
+	vectors map[string][]float32
+}
+
+func newVectorCache(path string) *vectorCache {
+	return &vectorCache{path: path, vectors: map[string][]float32{}}
+}
+
+func textKey(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookup returns an embedding for every text, embedding the missing ones
+// with e. Entries for texts that are no longer requested are dropped when
+// the cache is saved.
+func (c *vectorCache) lookup(ctx context.Context, e Embedder, texts []string) ([][]float32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	var missing []string
+	for _, t := range texts {
+		if _, ok := c.vectors[textKey(t)]; !ok {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) > 0 {
+		got, err := e.Embed(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for i, t := range missing {
+			c.vectors[textKey(t)] = got[i]
+		}
+	}
+
+	out := make([][]float32, len(texts))
+	keep := make(map[string][]float32, len(texts))
+	for i, t := range texts {
+		k := textKey(t)
+		out[i] = c.vectors[k]
+		keep[k] = out[i]
+	}
+	if len(missing) > 0 || len(keep) != len(c.vectors) {
+		c.vectors = keep
+		c.save()
+	}
+	return out, nil
+}
+
+func (c *vectorCache) load() {
+	if c.loaded || c.path == "" {
+		return
+	}
+	c.loaded = true
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, &c.vectors)
+}
+
+func (c *vectorCache) save() {
+	if c.path == "" {
+		return
+	}
+	data, err := json.Marshal(c.vectors)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return
+	}
+	_ = fsext.WriteFileAtomic(c.path, data, 0o600)
+}
+
+// cosine returns the cosine similarity of two vectors.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,160 @@
+// Package knowledge implements the project knowledge base: markdown files
+// kept in the knowledge/ folder of the data directory. The folder is
+// committed with the repository so a team can share conventions, runbooks
+// and background with every session.
+package knowledge
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+)
+
+// DirName is the name of the knowledge folder inside the data directory.
+const DirName = "knowledge"
+
+// maxFileSize bounds the size of a single knowledge file; larger files are
+// skipped.
+const maxFileSize = 1 << 20
+
+// Dir returns the knowledge folder of the given data directory.
+func Dir(dataDir string) string {
+	return filepath.Join(dataDir, DirName)
+}
+
+// Document is a markdown file in the knowledge base.
+type Document struct {
+	// Path is the slash-separated path relative to the knowledge folder.
+	Path string
+	// Title is the first level-one heading, or the file name without its
+	// extension.
+	Title string
+	// Summary is the structural summary produced by the markdown explorer.
+	Summary  string
+	Content  string
+	Sections []Section
+}
+
+// Section is a part of a document introduced by a heading. Text before
+// the first heading forms a section with an empty heading.
+type Section struct {
+	Heading string
+	// Line is the 1-based line the section starts on.
+	Line    int
+	Content string
+}
+
+// Load reads every markdown file below dir. A missing directory yields
+// no documents and no error.
+func Load(ctx context.Context, dir string) ([]Document, error) {
+	var docs []Document
+	md := &explorer.MarkdownExplorer{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !md.CanHandle(path, nil) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxFileSize {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		doc := parseDocument(filepath.ToSlash(rel), string(content))
+		if res, err := md.Explore(ctx, explorer.ExploreInput{Path: path, Content: content}); err == nil {
+			doc.Summary = res.Summary
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(docs, func(a, b Document) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return docs, nil
+}
+
+// parseDocument splits content into sections at ATX headings, ignoring
+// headings inside fenced code blocks.
+func parseDocument(path, content string) Document {
+	doc := Document{
+		Path:    path,
+		Title:   strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Content: content,
+	}
+	lines := strings.Split(content, "\n")
+	cur := Section{Line: 1}
+	var body []string
+	flush := func() {
+		cur.Content = strings.TrimSpace(strings.Join(body, "\n"))
+		if cur.Heading != "" || cur.Content != "" {
+			doc.Sections = append(doc.Sections, cur)
+		}
+		body = nil
+	}
+
+	inFence := false
+	titled := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if level, text := heading(trimmed); !inFence && level > 0 {
+			flush()
+			cur = Section{Heading: text, Line: i + 1}
+			if level == 1 && !titled {
+				doc.Title = text
+				titled = true
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return doc
+}
+
+// heading returns the level and text of an ATX heading line, or 0 when
+// the line is not a heading.
+func heading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ') {
+		return 0, ""
+	}
+	text := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+	if text == "" {
+		return 0, ""
+	}
+	return level, text
+}
@@ -0,0 +1,185 @@
+package knowledge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeKnowledge(t *testing.T, dataDir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(Dir(dataDir), name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+const deployDoc = `# Deploying
+
+Intro text.
+
+## Release checklist
+
+Bump the version, tag the commit and run the release workflow.
+
+` + "```sh\n# not a heading\ngit tag v1.2.3\n```" + `
+
+## Rollback
+
+Redeploy the previous tag.
+`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	docs, err := Load(t.Context(), Dir(dataDir))
+	require.NoError(t, err)
+	require.Empty(t, docs, "a missing knowledge folder is not an error")
+
+	writeKnowledge(t, dataDir, map[string]string{
+		"ops/deploy.md":  deployDoc,
+		"style.markdown": "Use tabs.",
+		"notes.txt":      "ignored",
+		".drafts/wip.md": "ignored",
+	})
+	docs, err = Load(t.Context(), Dir(dataDir))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	deploy := docs[0]
+	require.Equal(t, "ops/deploy.md", deploy.Path)
+	require.Equal(t, "Deploying", deploy.Title)
+	require.Contains(t, deploy.Summary, "Markdown file: deploy.md")
+	var headings []string
+	for _, s := range deploy.Sections {
+		headings = append(headings, s.Heading)
+	}
+	require.Equal(t, []string{"Deploying", "Release checklist", "Rollback"}, headings)
+	require.Equal(t, 5, deploy.Sections[1].Line)
+	require.Contains(t, deploy.Sections[1].Content, "# not a heading")
+
+	require.Equal(t, "style", docs[1].Title)
+	require.Equal(t, "Use tabs.", docs[1].Sections[0].Content)
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	base := New(dataDir, 0, nil)
+	out, err := base.Lookup(t.Context(), "", "")
+	require.NoError(t, err)
+	require.Contains(t, out, "knowledge base is empty")
+
+	writeKnowledge(t, dataDir, map[string]string{
+		"ops/deploy.md": deployDoc,
+		"style.md":      "# Style\n\nUse tabs for indentation.\n",
+	})
+
+	t.Run("index", func(t *testing.T) {
+		t.Parallel()
+		out, err := base.Lookup(t.Context(), "", "")
+		require.NoError(t, err)
+		require.Contains(t, out, "- ops/deploy.md: Deploying")
+		require.Contains(t, out, "  - Release checklist")
+	})
+
+	t.Run("query", func(t *testing.T) {
+		t.Parallel()
+		out, err := base.Lookup(t.Context(), "how do I roll back a release?", "")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(out, `<knowledge path="ops/deploy.md" line="14">`), out)
+		require.Contains(t, out, "## Release checklist")
+		require.NotContains(t, out, "Use tabs")
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		t.Parallel()
+		out, err := base.Lookup(t.Context(), "kubernetes", "")
+		require.NoError(t, err)
+		require.Contains(t, out, `No knowledge matched "kubernetes"`)
+	})
+
+	t.Run("path", func(t *testing.T) {
+		t.Parallel()
+		out, err := base.Lookup(t.Context(), "", "/style.md")
+		require.NoError(t, err)
+		require.Contains(t, out, "Use tabs for indentation.")
+
+		_, err = base.Lookup(t.Context(), "", "missing.md")
+		require.ErrorContains(t, err, `no knowledge document named "missing.md"`)
+	})
+}
+
+func TestLookupBudget(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	long := "# Big\n\n## Alpha\n\n" + strings.Repeat("alpha words ", 200) +
+		"\n\n## Beta\n\n" + strings.Repeat("beta words ", 200) + "\n"
+	writeKnowledge(t, dataDir, map[string]string{"big.md": long})
+	base := New(dataDir, 700, nil)
+
+	out, err := base.Lookup(t.Context(), "beta", "big.md")
+	require.NoError(t, err)
+	require.Contains(t, out, "exceeds the 700 token budget")
+	require.Contains(t, out, "Markdown file: big.md")
+	require.Contains(t, out, "- Alpha (line 3)")
+	require.Contains(t, out, "## Beta")
+	require.NotContains(t, out, "alpha words alpha")
+
+	out, err = base.Lookup(t.Context(), "words", "")
+	require.NoError(t, err)
+	require.Contains(t, out, "1 more matching section(s) omitted")
+}
+
+// fakeEmbedder maps texts to vectors by keyword so semantic matches can be
+// asserted without a model.
+type fakeEmbedder struct {
+	calls atomic.Int32
+}
+
+func (f *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	f.calls.Add(1)
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		t = strings.ToLower(t)
+		switch {
+		case strings.Contains(t, "rollback"), strings.Contains(t, "revert"):
+			out[i] = []float32{1, 0}
+		default:
+			out[i] = []float32{0, 1}
+		}
+	}
+	return out, nil
+}
+
+func TestLookupEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	writeKnowledge(t, dataDir, map[string]string{"ops/deploy.md": deployDoc})
+	emb := &fakeEmbedder{}
+	base := New(dataDir, 0, emb)
+
+	// "revert" shares no words with the rollback section.
+	out, err := base.Lookup(t.Context(), "revert", "")
+	require.NoError(t, err)
+	require.Contains(t, out, "## Rollback")
+	require.NotContains(t, out, "Release checklist")
+	require.Equal(t, int32(2), emb.calls.Load(), "sections and query are embedded")
+	require.FileExists(t, filepath.Join(dataDir, cacheFile))
+
+	// Unchanged sections are served from the persisted cache.
+	base = New(dataDir, 0, emb)
+	_, err = base.Lookup(t.Context(), "revert", "")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), emb.calls.Load(), "only the query is embedded again")
+}
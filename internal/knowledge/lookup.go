@@ -0,0 +1,304 @@
+package knowledge
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/crush/internal/lcm"
+)
+
+// DefaultMaxTokens is the default token budget of a single lookup.
+const DefaultMaxTokens = 4000
+
+// minSimilarity is the cosine similarity below which a section is not
+// considered relevant by semantic retrieval alone.
+const minSimilarity = 0.3
+
+// cacheFile is the name of the embedding cache in the data directory.
+const cacheFile = "knowledge_embeddings.json"
+
+// Base is the knowledge base of a project. Files are re-read on every
+// lookup so edits are picked up without a restart.
+type Base struct {
+	dir       string
+	maxTokens int
+	embedder  Embedder
+	cache     *vectorCache
+}
+
+// New returns the knowledge base stored in dataDir. A nil embedder
+// disables semantic retrieval; maxTokens <= 0 selects
+// [DefaultMaxTokens].
+func New(dataDir string, maxTokens int, embedder Embedder) *Base {
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTokens
+	}
+	return &Base{
+		dir:       Dir(dataDir),
+		maxTokens: maxTokens,
+		embedder:  embedder,
+		cache:     newVectorCache(filepath.Join(dataDir, cacheFile)),
+	}
+}
+
+// Match is a section matching a query.
+type Match struct {
+	Document *Document
+	Section  Section
+	Score    float64
+}
+
+// Lookup answers a knowledge_lookup call. With a path it returns that
+// document; with a query it returns the best matching sections; with
+// neither it returns an index of the knowledge base. Output never exceeds
+// the token budget.
+func (b *Base) Lookup(ctx context.Context, query, path string) (string, error) {
+	docs, err := Load(ctx, b.dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read knowledge base: %w", err)
+	}
+	if len(docs) == 0 {
+		return fmt.Sprintf("The knowledge base is empty. Add markdown files to %s to share project knowledge.", b.dir), nil
+	}
+
+	query = strings.TrimSpace(query)
+	if path != "" {
+		path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "/")
+		for i := range docs {
+			if docs[i].Path == path {
+				return b.renderDocument(ctx, &docs[i], query), nil
+			}
+		}
+		return "", fmt.Errorf("no knowledge document named %q; call knowledge_lookup without arguments to list documents", path)
+	}
+	if query == "" {
+		return b.renderIndex(docs), nil
+	}
+
+	matches := b.Search(ctx, docs, query)
+	if len(matches) == 0 {
+		return fmt.Sprintf("No knowledge matched %q.\n\n%s", query, b.renderIndex(docs)), nil
+	}
+	return b.renderMatches(matches), nil
+}
+
+// Search ranks the sections of docs against query. Lexical matching is
+// always used; when an embedder is configured, semantic similarity is
+// blended in and sections that are semantically close but share no words
+// with the query are also returned.
+func (b *Base) Search(ctx context.Context, docs []Document, query string) []Match {
+	terms := tokenize(query)
+	var matches []Match
+	for i := range docs {
+		for _, s := range docs[i].Sections {
+			matches = append(matches, Match{
+				Document: &docs[i],
+				Section:  s,
+				Score:    lexicalScore(terms, &docs[i], s),
+			})
+		}
+	}
+
+	if b.embedder != nil {
+		if err := b.addSimilarity(ctx, query, matches); err != nil {
+			slog.Warn("Knowledge embeddings unavailable, using keyword matching", "error", err)
+		}
+	}
+
+	matches = slices.DeleteFunc(matches, func(m Match) bool { return m.Score <= 0 })
+	slices.SortStableFunc(matches, func(a, c Match) int {
+		return cmp.Compare(c.Score, a.Score)
+	})
+	return matches
+}
+
+func (b *Base) addSimilarity(ctx context.Context, query string, matches []Match) error {
+	texts := make([]string, len(matches))
+	for i, m := range matches {
+		texts[i] = embeddingText(m)
+	}
+	vectors, err := b.cache.lookup(ctx, b.embedder, texts)
+	if err != nil {
+		return err
+	}
+	q, err := b.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return err
+	}
+	for i := range matches {
+		sim := cosine(q[0], vectors[i])
+		switch {
+		case matches[i].Score > 0:
+			matches[i].Score += sim * 2
+		case sim >= minSimilarity:
+			matches[i].Score = sim
+		}
+	}
+	return nil
+}
+
+func embeddingText(m Match) string {
+	return m.Document.Path + "\n" + m.Section.Heading + "\n" + m.Section.Content
+}
+
+// tokenize splits s into lowercase words of at least two characters.
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var terms []string
+	for _, f := range fields {
+		if len([]rune(f)) >= 2 && !slices.Contains(terms, f) {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// lexicalScore rewards sections containing many distinct query terms,
+// with extra weight for terms in the heading or document path.
+func lexicalScore(terms []string, doc *Document, s Section) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+	content := strings.ToLower(s.Content)
+	head := strings.ToLower(s.Heading)
+	where := strings.ToLower(doc.Path + " " + doc.Title)
+	var score float64
+	matched := 0
+	for _, t := range terms {
+		hits := strings.Count(content, t)
+		inHead := strings.Contains(head, t)
+		inDoc := strings.Contains(where, t)
+		if hits == 0 && !inHead && !inDoc {
+			continue
+		}
+		matched++
+		score += math.Log1p(float64(hits))
+		if inHead {
+			score += 2
+		}
+		if inDoc {
+			score++
+		}
+	}
+	// Favour sections covering more of the query over ones repeating a
+	// single term.
+	return score * float64(matched) / float64(len(terms))
+}
+
+// renderMatches writes matching sections in rank order until the budget
+// is spent.
+func (b *Base) renderMatches(matches []Match) string {
+	var sb strings.Builder
+	budget := int64(b.maxTokens)
+	shown := 0
+	for _, m := range matches {
+		block := fmt.Sprintf("<knowledge path=%q line=\"%d\">\n%s\n</knowledge>\n\n", m.Document.Path, m.Section.Line, sectionText(m.Section))
+		cost := lcm.EstimateTokens(block)
+		if cost > budget {
+			if shown == 0 {
+				block = truncateTokens(block, budget)
+				sb.WriteString(block)
+				shown++
+			}
+			break
+		}
+		sb.WriteString(block)
+		budget -= cost
+		shown++
+	}
+	if rest := len(matches) - shown; rest > 0 {
+		fmt.Fprintf(&sb, "%d more matching section(s) omitted to stay within the %d token budget. Narrow the query or pass a path to read a document.\n", rest, b.maxTokens)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// renderDocument returns the whole document when it fits the budget.
+// Larger documents are summarized by the markdown explorer and followed
+// by as many sections as fit, most relevant to query first.
+func (b *Base) renderDocument(ctx context.Context, doc *Document, query string) string {
+	full := fmt.Sprintf("<knowledge path=%q>\n%s\n</knowledge>", doc.Path, strings.TrimSpace(doc.Content))
+	if lcm.EstimateTokens(full) <= int64(b.maxTokens) {
+		return full
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s exceeds the %d token budget; showing its outline and the sections that fit.\n\n", doc.Path, b.maxTokens)
+	sb.WriteString(doc.Summary)
+	sb.WriteString("\nSections:\n")
+	for _, s := range doc.Sections {
+		if s.Heading != "" {
+			fmt.Fprintf(&sb, "- %s (line %d)\n", s.Heading, s.Line)
+		}
+	}
+	sb.WriteString("\n")
+
+	sections := doc.Sections
+	if query != "" {
+		var ranked []Section
+		for _, m := range b.Search(ctx, []Document{*doc}, query) {
+			ranked = append(ranked, m.Section)
+		}
+		sections = ranked
+	}
+	budget := int64(b.maxTokens) - lcm.EstimateTokens(sb.String())
+	for _, s := range sections {
+		block := fmt.Sprintf("<knowledge path=%q line=\"%d\">\n%s\n</knowledge>\n\n", doc.Path, s.Line, sectionText(s))
+		cost := lcm.EstimateTokens(block)
+		if cost > budget {
+			continue
+		}
+		sb.WriteString(block)
+		budget -= cost
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// renderIndex lists every document with its headings, within budget.
+func (b *Base) renderIndex(docs []Document) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Knowledge base: %d document(s). Call knowledge_lookup with a query to search or a path to read a document.\n\n", len(docs))
+	budget := int64(b.maxTokens) - lcm.EstimateTokens(sb.String())
+	for i, doc := range docs {
+		var entry strings.Builder
+		fmt.Fprintf(&entry, "- %s: %s\n", doc.Path, doc.Title)
+		for _, s := range doc.Sections {
+			if s.Heading != "" && s.Heading != doc.Title {
+				fmt.Fprintf(&entry, "  - %s\n", s.Heading)
+			}
+		}
+		cost := lcm.EstimateTokens(entry.String())
+		if cost > budget {
+			fmt.Fprintf(&sb, "%d more document(s) omitted to stay within the token budget.\n", len(docs)-i)
+			break
+		}
+		sb.WriteString(entry.String())
+		budget -= cost
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func sectionText(s Section) string {
+	if s.Heading == "" {
+		return s.Content
+	}
+	return "## " + s.Heading + "\n\n" + s.Content
+}
+
+// truncateTokens cuts s to roughly the given number of tokens.
+func truncateTokens(s string, tokens int64) string {
+	r := []rune(s)
+	limit := int(tokens * lcm.CharsPerToken)
+	if limit >= len(r) {
+		return s
+	}
+	return string(r[:limit]) + "\n[truncated]\n"
+}
@@ -0,0 +1,87 @@
+package lcm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+)
+
+// exploreCachePruneInterval is the number of writes between prunes of the
+// sqlite exploration cache.
+const exploreCachePruneInterval = 64
+
+// SQLiteExploreCache is an explorer.ExploreCache stored in the crush
+// database, so exploration results survive restarts. Entries past
+// maxEntries are pruned by last access.
+type SQLiteExploreCache struct {
+	q          db.Querier
+	maxEntries int64
+	writes     atomic.Int64
+}
+
+var _ explorer.ExploreCache = (*SQLiteExploreCache)(nil)
+
+// NewSQLiteExploreCache returns a cache over q holding up to maxEntries
+// results, or explorer.DefaultMemoryCacheEntries when maxEntries is not
+// positive.
+func NewSQLiteExploreCache(q db.Querier, maxEntries int) *SQLiteExploreCache {
+	if maxEntries <= 0 {
+		maxEntries = explorer.DefaultMemoryCacheEntries
+	}
+	return &SQLiteExploreCache{q: q, maxEntries: int64(maxEntries)}
+}
+
+// Get implements explorer.ExploreCache.
+func (c *SQLiteExploreCache) Get(ctx context.Context, key string) (explorer.ExploreResult, bool, error) {
+	raw, err := c.q.GetExplorerCacheEntry(ctx, db.GetExplorerCacheEntryParams{
+		AccessedAt: time.Now().Unix(),
+		CacheKey:   key,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return explorer.ExploreResult{}, false, nil
+	}
+	if err != nil {
+		return explorer.ExploreResult{}, false, fmt.Errorf("reading explore cache: %w", err)
+	}
+	var result explorer.ExploreResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return explorer.ExploreResult{}, false, fmt.Errorf("decoding explore cache entry: %w", err)
+	}
+	return result, true, nil
+}
+
+// Put implements explorer.ExploreCache.
+func (c *SQLiteExploreCache) Put(ctx context.Context, key string, result explorer.ExploreResult) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding explore cache entry: %w", err)
+	}
+	now := time.Now().Unix()
+	if err := c.q.UpsertExplorerCacheEntry(ctx, db.UpsertExplorerCacheEntryParams{
+		CacheKey:   key,
+		Result:     string(raw),
+		CreatedAt:  now,
+		AccessedAt: now,
+	}); err != nil {
+		return fmt.Errorf("writing explore cache: %w", err)
+	}
+	if c.writes.Add(1)%exploreCachePruneInterval == 0 {
+		return c.Prune(ctx)
+	}
+	return nil
+}
+
+// Prune drops the least recently used entries past the size limit.
+func (c *SQLiteExploreCache) Prune(ctx context.Context) error {
+	if err := c.q.PruneExplorerCache(ctx, c.maxEntries); err != nil {
+		return fmt.Errorf("pruning explore cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package lcm
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteExploreCache(t *testing.T) {
+	t.Parallel()
+
+	queries, _ := setupTestDB(t)
+	cache := NewSQLiteExploreCache(queries, 2)
+	ctx := t.Context()
+
+	_, ok, err := cache.Get(ctx, "missing")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	want := explorer.ExploreResult{
+		Summary:      "JSON object with 2 keys",
+		ExplorerUsed: "JSONExplorer",
+		Facts:        &explorer.Facts{Counts: map[string]int64{"keys": 2}},
+	}
+	require.NoError(t, cache.Put(ctx, "a", want))
+	got, ok, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, want, got)
+
+	require.NoError(t, cache.Put(ctx, "b", want))
+	require.NoError(t, cache.Put(ctx, "c", want))
+	require.NoError(t, cache.Prune(ctx))
+
+	var kept int
+	for _, key := range []string{"a", "b", "c"} {
+		if _, ok, _ := cache.Get(ctx, key); ok {
+			kept++
+		}
+	}
+	require.Equal(t, 2, kept)
+}
@@ -3,14 +3,18 @@ package explorer
 import (
 	"archive/tar"
 	"archive/zip"
+	"bufio"
 	"bytes"
 	"compress/bzip2"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,8 +24,11 @@ import (
 // ArchiveExplorer explores archive and compressed file formats.
 type ArchiveExplorer struct {
 	formatterProfile OutputProfile
+	nesting          ArchiveNesting
 }
 
+var _ StreamExplorer = (*ArchiveExplorer)(nil)
+
 // archiveExtensions maps extensions to archive family identifiers.
 var archiveExtensions = map[string]string{
 	"zip":   "zip",
@@ -34,6 +41,7 @@ var archiveExtensions = map[string]string{
 	"crx":   "zip",
 	"xpi":   "zip",
 	"vsix":  "zip",
+	"whl":   "zip",
 	"tar":   "tar",
 	"gz":    "gzip",
 	"tgz":   "tar.gz",
@@ -90,7 +98,7 @@ var magicSignatures = []magicSignature{
 func (e *ArchiveExplorer) CanHandle(path string, content []byte) bool {
 	// Check double extensions first (e.g., .tar.gz).
 	lower := strings.ToLower(path)
-	for ext := range doubleExtensions {
+	for _, ext := range keysLongestFirst(doubleExtensions) {
 		if strings.HasSuffix(lower, ext) {
 			return true
 		}
@@ -119,37 +127,116 @@ func detectArchiveMagic(content []byte) string {
 }
 
 func (e *ArchiveExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
-	family := e.resolveFamily(input.Path, input.Content)
+	return e.explore(ctx, archiveSource{
+		path: input.Path,
+		r:    bytes.NewReader(input.Content),
+		size: int64(len(input.Content)),
+	})
+}
+
+// ExploreStream summarizes the archive at path read from r, which holds
+// size bytes. Unlike Explore it never loads the archive into memory: ZIP
+// archives are listed from their central directory, tar entry bodies are
+// skipped, and compressed streams are decompressed on the fly. Listing
+// stops after maxArchiveEntries entries.
+func (e *ArchiveExplorer) ExploreStream(ctx context.Context, path string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	return e.explore(ctx, archiveSource{path: path, r: r, size: size})
+}
+
+// maxArchiveEntries bounds the tar entries read for one summary, so the
+// histograms stay small and huge compressed archives are not decompressed
+// to the end.
+const maxArchiveEntries = 100_000
+
+// archiveHeadBytes is how much of the archive is read up front for magic
+// byte detection.
+const archiveHeadBytes = 512
+
+// archiveSource is the archive being explored.
+type archiveSource struct {
+	path string
+	r    io.ReaderAt
+	size int64
+	// nested collects the archives found inside, or is nil when they are
+	// not opened.
+	nested *nestedArchives
+	// depth is how deeply the archive is nested; prefix is its path
+	// within the outermost archive.
+	depth  int
+	prefix string
+}
+
+// head returns up to n bytes from the start of the archive.
+func (s archiveSource) head(n int) []byte {
+	buf := make([]byte, min(int64(n), s.size))
+	read, _ := s.r.ReadAt(buf, 0)
+	return buf[:read]
+}
+
+// reader returns a reader over the whole archive.
+func (s archiveSource) reader() *io.SectionReader {
+	return io.NewSectionReader(s.r, 0, s.size)
+}
+
+func (e *ArchiveExplorer) explore(ctx context.Context, src archiveSource) (ExploreResult, error) {
+	// EXCEED MODE: what the archives inside the archive hold.
+	if src.depth == 0 && e.formatterProfile == OutputProfileEnhancement {
+		if limits := e.nesting.withDefaults(); limits.MaxDepth > 0 {
+			src.nested = &nestedArchives{limits: limits}
+		}
+	}
+	result, err := e.exploreFamily(ctx, src)
+	if err != nil || src.depth > 0 || src.nested == nil || len(src.nested.found) == 0 {
+		return result, err
+	}
+	// Unsafe entries of nested archives count against the outer one.
+	if unsafe := src.nested.unsafe(); unsafe > 0 && result.Facts != nil {
+		result.Facts.Counts["unsafe_entries"] += unsafe
+	}
+	var summary strings.Builder
+	summary.WriteString(result.Summary)
+	src.nested.write(&summary)
+	result.Summary = summary.String()
+	result.TokenEstimate = estimateTokens(result.Summary)
+	return result, nil
+}
+
+// exploreFamily explores the archive according to its family.
+func (e *ArchiveExplorer) exploreFamily(ctx context.Context, src archiveSource) (ExploreResult, error) {
+	family := e.resolveFamily(src.path, src.head(archiveHeadBytes))
 
 	switch family {
-	case "zip", "jar", "war", "ear", "apk", "ipa", "nupkg", "crx", "xpi", "vsix":
-		return e.exploreZIP(input, family)
+	case "zip", "jar", "war", "ear", "apk", "ipa", "nupkg", "crx", "xpi", "vsix", "whl":
+		return e.exploreZIP(ctx, src, family)
 	case "tar":
-		return e.exploreTAR(input, nil)
+		raw := src.reader()
+		return e.exploreTARReader(ctx, src, raw, raw, "tar")
 	case "tar.gz":
-		return e.exploreTARCompressed(input, "gzip")
+		return e.exploreTARCompressed(ctx, src, "gzip")
 	case "tar.bz2":
-		return e.exploreTARCompressed(input, "bzip2")
+		return e.exploreTARCompressed(ctx, src, "bzip2")
 	case "tar.zst":
-		return e.exploreTARCompressed(input, "zstd")
+		return e.exploreTARCompressed(ctx, src, "zstd")
 	case "gzip":
 		// Standalone gzip could be a tar.gz; try tar first.
-		return e.exploreGzip(input)
+		return e.exploreGzip(ctx, src)
 	case "bzip2":
 		// Standalone bzip2 could be a tar.bz2; try tar first.
-		return e.exploreBzip2(input)
+		return e.exploreBzip2(ctx, src)
 	case "zstd":
 		// Standalone zstd could be a tar.zst; try tar first.
-		return e.exploreZstd(input)
+		return e.exploreZstd(ctx, src)
 	case "deb":
-		return e.exploreDeb(input)
+		return e.exploreDeb(ctx, src)
 	case "ar":
-		return e.exploreDeb(input) // ar format same as deb
+		return e.exploreDeb(ctx, src) // ar format same as deb
 	case "rpm":
-		return e.exploreRPM(input)
+		return e.exploreRPM(src)
+	case "7z", "rar":
+		return e.exploreListed(ctx, src, family)
 	default:
-		// Opaque formats: 7z, rar, xz, lz, lz4, cab, cpio, iso, dmg, wim.
-		return e.exploreOpaque(input, family)
+		// Opaque formats: xz, lz, lz4, cab, cpio, iso, dmg, wim.
+		return e.exploreOpaque(src, family)
 	}
 }
 
@@ -158,9 +245,9 @@ func (e *ArchiveExplorer) resolveFamily(path string, content []byte) string {
 	lower := strings.ToLower(path)
 
 	// Check double extensions first.
-	for ext, family := range doubleExtensions {
+	for _, ext := range keysLongestFirst(doubleExtensions) {
 		if strings.HasSuffix(lower, ext) {
-			return family
+			return doubleExtensions[ext]
 		}
 	}
 
@@ -170,7 +257,7 @@ func (e *ArchiveExplorer) resolveFamily(path string, content []byte) string {
 		// For zip-family containers, return the specific extension so
 		// we know to look for manifests etc.
 		switch ext {
-		case "jar", "war", "ear", "apk", "ipa", "nupkg", "crx", "xpi", "vsix":
+		case "jar", "war", "ear", "apk", "ipa", "nupkg", "crx", "xpi", "vsix", "whl":
 			return ext
 		}
 		return family
@@ -181,15 +268,19 @@ func (e *ArchiveExplorer) resolveFamily(path string, content []byte) string {
 }
 
 // exploreZIP explores ZIP-family archives using pure Go archive/zip.
-func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (ExploreResult, error) {
-	reader, err := zip.NewReader(bytes.NewReader(input.Content), int64(len(input.Content)))
+func (e *ArchiveExplorer) exploreZIP(ctx context.Context, src archiveSource, family string) (ExploreResult, error) {
+	reader, err := zip.NewReader(src.r, src.size)
 	if err != nil {
-		summary := fmt.Sprintf("Archive file: %s\nFormat: %s\nSize: %d bytes\nError: could not read ZIP contents: %v",
-			filepath.Base(input.Path), family, len(input.Content), err)
+		var summary strings.Builder
+		fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
+		fmt.Fprintf(&summary, "Format: %s\n", family)
+		fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
+		zipDegradation(src.size, err).write(&summary)
+		result := summary.String()
 		return ExploreResult{
-			Summary:       summary,
+			Summary:       result,
 			ExplorerUsed:  "archive",
-			TokenEstimate: estimateTokens(summary),
+			TokenEstimate: estimateTokens(result),
 		}, nil
 	}
 
@@ -202,14 +293,18 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 		extHist         = make(map[string]int)
 		topLevel        = make(map[string]bool)
 		comprMethods    = make(map[string]int)
-		largest         []zipFileInfo
+		largest         topFiles
 		manifestContent string
+		app             *appMetadata
+		python          pythonPackage
 		minTime         time.Time
 		maxTime         time.Time
 		timeSet         bool
+		safety          archiveSafety
 	)
 
 	for _, f := range reader.File {
+		safety.checkPath(f.Name)
 		if f.FileInfo().IsDir() {
 			dirCount++
 			// Record top-level directory.
@@ -223,6 +318,10 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 		fileCount++
 		totalUncomp += f.UncompressedSize64
 		totalComp += f.CompressedSize64
+		safety.checkExpansion(f.Name, int64(f.CompressedSize64), int64(f.UncompressedSize64))
+		if f.Mode()&os.ModeSymlink != 0 {
+			safety.checkLink(f.Name, readLinkTarget(f.Open), false)
+		}
 
 		// Extension histogram.
 		ext := strings.ToLower(filepath.Ext(f.Name))
@@ -243,9 +342,9 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 		}
 
 		// Track largest files.
-		largest = append(largest, zipFileInfo{
-			name: f.Name,
-			size: f.UncompressedSize64,
+		largest.add(f.Name, int64(f.UncompressedSize64))
+		e.visitEntry(ctx, src, f.Name, int64(f.UncompressedSize64), func() (io.ReaderAt, error) {
+			return readZIPEntry(f)
 		})
 
 		// Encrypted detection.
@@ -284,21 +383,23 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 				}
 			}
 		}
-	}
 
-	// Sort largest descending, keep top 5.
-	sort.Slice(largest, func(i, j int) bool {
-		return largest[i].size > largest[j].size
-	})
-	if len(largest) > 5 {
-		largest = largest[:5]
+		// APK manifest and IPA Info.plist.
+		if app == nil && isAppMetadataEntry(family, f.Name) {
+			app = readAppMetadata(family, f.Name, f.Open)
+		}
+
+		// Wheel METADATA, or PKG-INFO of a zipped sdist.
+		if family == "whl" || family == "zip" {
+			python.visit(f.Name, f.Open)
+		}
 	}
 
 	// Build summary.
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
 	fmt.Fprintf(&summary, "Format: %s\n", family)
-	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
+	fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
 	fmt.Fprintf(&summary, "Files: %d, Directories: %d\n", fileCount, dirCount)
 	fmt.Fprintf(&summary, "Total uncompressed: %s\n", formatSize(totalUncomp))
 	if totalUncomp > 0 && totalComp > 0 {
@@ -321,30 +422,14 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 	// Extension histogram.
 	if len(extHist) > 0 {
 		summary.WriteString("\nExtension histogram:\n")
-		type extCount struct {
-			ext   string
-			count int
-		}
-		var sorted []extCount
-		for ext, count := range extHist {
-			sorted = append(sorted, extCount{ext, count})
-		}
-		sort.Slice(sorted, func(i, j int) bool {
-			if sorted[i].count != sorted[j].count {
-				return sorted[i].count > sorted[j].count
-			}
-			return sorted[i].ext < sorted[j].ext
-		})
-		for _, ec := range sorted {
-			fmt.Fprintf(&summary, "  - %s: %d\n", ec.ext, ec.count)
-		}
+		writeCounts(&summary, extHist, "")
 	}
 
 	// Largest files.
 	if len(largest) > 0 {
 		summary.WriteString("\nLargest files:\n")
 		for _, f := range largest {
-			fmt.Fprintf(&summary, "  - %s (%s)\n", f.name, formatSize(f.size))
+			fmt.Fprintf(&summary, "  - %s (%s)\n", f.name, formatSize(uint64(f.size)))
 		}
 	}
 
@@ -359,6 +444,15 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 			}
 		}
 	}
+	if app != nil {
+		app.write(&summary, e.formatterProfile)
+	}
+	pkg := python.result()
+	if pkg != nil {
+		pkg.write(&summary, e.formatterProfile)
+	}
+
+	safety.checkTotal(src.size, int64(totalUncomp))
 
 	// Enhancement mode extras.
 	if e.formatterProfile == OutputProfileEnhancement {
@@ -370,10 +464,10 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 
 		if len(comprMethods) > 0 {
 			summary.WriteString("\nCompression methods:\n")
-			for method, count := range comprMethods {
-				fmt.Fprintf(&summary, "  - %s: %d files\n", method, count)
-			}
+			writeCounts(&summary, comprMethods, " files")
 		}
+
+		safety.write(&summary, src.size, int64(totalUncomp), false)
 	}
 
 	result := summary.String()
@@ -381,20 +475,14 @@ func (e *ArchiveExplorer) exploreZIP(input ExploreInput, family string) (Explore
 		Summary:       result,
 		ExplorerUsed:  "archive",
 		TokenEstimate: estimateTokens(result),
+		Facts:         pkg.addFacts(app.addFacts(safety.addFacts(archiveFacts(fileCount, dirCount, int64(totalUncomp))))),
 	}, nil
 }
 
-// exploreTAR explores an uncompressed tar archive.
-func (e *ArchiveExplorer) exploreTAR(input ExploreInput, r io.Reader) (ExploreResult, error) {
-	if r == nil {
-		r = bytes.NewReader(input.Content)
-	}
-	return e.exploreTARReader(input, r, "tar")
-}
-
-// exploreTARCompressed explores a compressed tar archive.
-func (e *ArchiveExplorer) exploreTARCompressed(input ExploreInput, compression string) (ExploreResult, error) {
-	r := bytes.NewReader(input.Content)
+// exploreTARCompressed explores a compressed tar archive, decompressing it
+// as the headers are read.
+func (e *ArchiveExplorer) exploreTARCompressed(ctx context.Context, src archiveSource, compression string) (ExploreResult, error) {
+	r := src.reader()
 
 	var decompressed io.Reader
 	var err error
@@ -403,7 +491,7 @@ func (e *ArchiveExplorer) exploreTARCompressed(input ExploreInput, compression s
 	case "gzip":
 		decompressed, err = gzip.NewReader(r)
 		if err != nil {
-			return e.compressedFallback(input, "tar.gz", err)
+			return e.compressedFallback(src, "tar.gz", err)
 		}
 		defer decompressed.(*gzip.Reader).Close()
 	case "bzip2":
@@ -411,23 +499,26 @@ func (e *ArchiveExplorer) exploreTARCompressed(input ExploreInput, compression s
 	case "zstd":
 		dec, err := zstd.NewReader(r)
 		if err != nil {
-			return e.compressedFallback(input, "tar.zst", err)
+			return e.compressedFallback(src, "tar.zst", err)
 		}
 		defer dec.Close()
 		decompressed = dec
 	default:
-		return e.compressedFallback(input, compression, fmt.Errorf("unsupported compression: %s", compression))
+		return e.compressedFallback(src, compression, fmt.Errorf("unsupported compression: %s", compression))
 	}
 
 	format := "tar." + compression
 	if compression == "gzip" {
 		format = "tar.gz"
 	}
-	return e.exploreTARReader(input, decompressed, format)
+	return e.exploreTARReader(ctx, src, r, decompressed, format)
 }
 
-// exploreTARReader iterates tar headers and produces a summary.
-func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, format string) (ExploreResult, error) {
+// exploreTARReader iterates tar headers and produces a summary. Entry
+// bodies are skipped, by seeking when r supports it. raw is the reader over
+// the archive file r decompresses, used to report how far a damaged archive
+// was read.
+func (e *ArchiveExplorer) exploreTARReader(ctx context.Context, src archiveSource, raw io.Seeker, r io.Reader, format string) (ExploreResult, error) {
 	tr := tar.NewReader(r)
 
 	var (
@@ -439,21 +530,36 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 		topLevel     = make(map[string]bool)
 		owners       = make(map[string]int)
 		permissions  = make(map[string]int)
-		largest      []tarFileInfo
+		largest      topFiles
+		truncated    bool
+		readErr      error
+		entryCount   int
 		minTime      time.Time
 		maxTime      time.Time
 		timeSet      bool
+		safety       archiveSafety
+		python       pythonPackage
 	)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return ExploreResult{}, err
+		}
+		if entryCount == maxArchiveEntries {
+			truncated = true
+			break
+		}
 		hdr, err := tr.Next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
 			// Partial read is acceptable; report what we have.
+			readErr = err
 			break
 		}
+		entryCount++
+		safety.checkPath(hdr.Name)
 
 		// Top-level entry.
 		parts := strings.SplitN(hdr.Name, "/", 2)
@@ -472,6 +578,7 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 			dirCount++
 		case tar.TypeSymlink, tar.TypeLink:
 			symlinkCount++
+			safety.checkLink(hdr.Name, hdr.Linkname, hdr.Typeflag == tar.TypeLink)
 		default:
 			fileCount++
 			totalSize += hdr.Size
@@ -481,9 +588,14 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 				extHist[ext]++
 			}
 
-			largest = append(largest, tarFileInfo{
-				name: hdr.Name,
-				size: hdr.Size,
+			largest.add(hdr.Name, hdr.Size)
+			e.visitEntry(ctx, src, hdr.Name, hdr.Size, func() (io.ReaderAt, error) {
+				data, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+				return bytes.NewReader(data), err
+			})
+			// PKG-INFO of an sdist.
+			python.visit(hdr.Name, func() (io.ReadCloser, error) {
+				return io.NopCloser(tr), nil
 			})
 		}
 
@@ -515,32 +627,35 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 				}
 			}
 		}
-
-		// Discard the entry body to advance the reader.
-		if _, err := io.Copy(io.Discard, tr); err != nil {
-			break
-		}
-	}
-
-	// Sort largest descending, keep top 5.
-	sort.Slice(largest, func(i, j int) bool {
-		return largest[i].size > largest[j].size
-	})
-	if len(largest) > 5 {
-		largest = largest[:5]
 	}
 
 	// Build summary.
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
 	fmt.Fprintf(&summary, "Format: %s\n", format)
-	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
+	fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
 	fmt.Fprintf(&summary, "Files: %d, Directories: %d", fileCount, dirCount)
 	if symlinkCount > 0 {
 		fmt.Fprintf(&summary, ", Symlinks: %d", symlinkCount)
 	}
 	summary.WriteString("\n")
 	fmt.Fprintf(&summary, "Total uncompressed: %s\n", formatSize(uint64(totalSize)))
+	if truncated {
+		fmt.Fprintf(&summary, "Note: listing stopped after %d entries\n", maxArchiveEntries)
+	}
+	if readErr != nil {
+		examined, _ := raw.Seek(0, io.SeekCurrent)
+		degradedExploration{
+			Failed:   "tar header read: " + readErr.Error(),
+			Progress: fmt.Sprintf("%d entries read before the error", entryCount),
+			Examined: examined,
+			Size:     src.size,
+			NextSteps: []string{
+				"The archive is probably truncated or corrupted; the entries below were read before the damage",
+				"Re-download or re-create the archive to list the rest",
+			},
+		}.write(&summary)
+	}
 
 	// Top-level structure.
 	if len(topLevel) > 0 {
@@ -554,39 +669,19 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 	// Extension histogram.
 	if len(extHist) > 0 {
 		summary.WriteString("\nExtension histogram:\n")
-		type extCount struct {
-			ext   string
-			count int
-		}
-		var sorted []extCount
-		for ext, count := range extHist {
-			sorted = append(sorted, extCount{ext, count})
-		}
-		sort.Slice(sorted, func(i, j int) bool {
-			if sorted[i].count != sorted[j].count {
-				return sorted[i].count > sorted[j].count
-			}
-			return sorted[i].ext < sorted[j].ext
-		})
-		for _, ec := range sorted {
-			fmt.Fprintf(&summary, "  - %s: %d\n", ec.ext, ec.count)
-		}
+		writeCounts(&summary, extHist, "")
 	}
 
 	// Ownership summary.
 	if len(owners) > 0 {
 		summary.WriteString("\nOwnership:\n")
-		for owner, count := range owners {
-			fmt.Fprintf(&summary, "  - %s: %d entries\n", owner, count)
-		}
+		writeCounts(&summary, owners, " entries")
 	}
 
 	// Permissions summary.
 	if len(permissions) > 0 {
 		summary.WriteString("\nPermissions:\n")
-		for perm, count := range permissions {
-			fmt.Fprintf(&summary, "  - %s: %d entries\n", perm, count)
-		}
+		writeCounts(&summary, permissions, " entries")
 	}
 
 	// Largest files.
@@ -597,6 +692,13 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 		}
 	}
 
+	pkg := python.result()
+	if pkg != nil {
+		pkg.write(&summary, e.formatterProfile)
+	}
+
+	safety.checkTotal(src.size, totalSize)
+
 	// Enhancement mode extras.
 	if e.formatterProfile == OutputProfileEnhancement {
 		if timeSet && !minTime.Equal(maxTime) {
@@ -604,6 +706,8 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 			fmt.Fprintf(&summary, "  - Earliest: %s\n", minTime.Format(time.RFC3339))
 			fmt.Fprintf(&summary, "  - Latest: %s\n", maxTime.Format(time.RFC3339))
 		}
+
+		safety.write(&summary, src.size, totalSize, truncated || readErr != nil)
 	}
 
 	result := summary.String()
@@ -611,121 +715,106 @@ func (e *ArchiveExplorer) exploreTARReader(input ExploreInput, r io.Reader, form
 		Summary:       result,
 		ExplorerUsed:  "archive",
 		TokenEstimate: estimateTokens(result),
+		Facts:         pkg.addFacts(safety.addFacts(archiveFacts(fileCount, dirCount, totalSize))),
 	}, nil
 }
 
 // exploreGzip handles standalone .gz files. Tries tar first, falls back to
 // reporting the gzip container.
-func (e *ArchiveExplorer) exploreGzip(input ExploreInput) (ExploreResult, error) {
-	gr, err := gzip.NewReader(bytes.NewReader(input.Content))
+func (e *ArchiveExplorer) exploreGzip(ctx context.Context, src archiveSource) (ExploreResult, error) {
+	raw := src.reader()
+	gr, err := gzip.NewReader(raw)
 	if err != nil {
-		return e.exploreOpaque(input, "gzip")
+		return e.exploreOpaque(src, "gzip")
 	}
 	defer gr.Close()
-
-	// Peek to see if it contains a tar archive.
-	var buf bytes.Buffer
-	_, err = io.Copy(&buf, gr)
-	if err != nil {
-		return e.exploreOpaque(input, "gzip")
-	}
-
-	tarData := buf.Bytes()
-	if isTAR(tarData) {
-		return e.exploreTARReader(input, bytes.NewReader(tarData), "tar.gz")
-	}
-
-	// Plain gzip file.
-	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
-	summary.WriteString("Format: gzip\n")
-	fmt.Fprintf(&summary, "Compressed size: %d bytes\n", len(input.Content))
-	fmt.Fprintf(&summary, "Uncompressed size: %d bytes\n", len(tarData))
-	if len(input.Content) > 0 {
-		ratio := float64(len(input.Content)) / float64(len(tarData)) * 100
-		fmt.Fprintf(&summary, "Compression ratio: %.1f%%\n", ratio)
-	}
-
-	result := summary.String()
-	return ExploreResult{
-		Summary:       result,
-		ExplorerUsed:  "archive",
-		TokenEstimate: estimateTokens(result),
-	}, nil
+	return e.exploreCompressed(ctx, src, raw, gr, "gzip", true)
 }
 
 // exploreBzip2 handles standalone .bz2 files. Tries tar first, falls back
 // to reporting the bzip2 container.
-func (e *ArchiveExplorer) exploreBzip2(input ExploreInput) (ExploreResult, error) {
-	br := bzip2.NewReader(bytes.NewReader(input.Content))
-
-	var buf bytes.Buffer
-	_, err := io.Copy(&buf, br)
-	if err != nil {
-		return e.exploreOpaque(input, "bzip2")
-	}
-
-	tarData := buf.Bytes()
-	if isTAR(tarData) {
-		return e.exploreTARReader(input, bytes.NewReader(tarData), "tar.bz2")
-	}
-
-	// Plain bzip2 file.
-	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
-	summary.WriteString("Format: bzip2\n")
-	fmt.Fprintf(&summary, "Compressed size: %d bytes\n", len(input.Content))
-	fmt.Fprintf(&summary, "Uncompressed size: %d bytes\n", len(tarData))
-
-	result := summary.String()
-	return ExploreResult{
-		Summary:       result,
-		ExplorerUsed:  "archive",
-		TokenEstimate: estimateTokens(result),
-	}, nil
+func (e *ArchiveExplorer) exploreBzip2(ctx context.Context, src archiveSource) (ExploreResult, error) {
+	raw := src.reader()
+	return e.exploreCompressed(ctx, src, raw, bzip2.NewReader(raw), "bzip2", false)
 }
 
 // exploreZstd handles standalone .zst files. Tries tar first, falls back
 // to reporting the zstd container.
-func (e *ArchiveExplorer) exploreZstd(input ExploreInput) (ExploreResult, error) {
-	dec, err := zstd.NewReader(bytes.NewReader(input.Content))
+func (e *ArchiveExplorer) exploreZstd(ctx context.Context, src archiveSource) (ExploreResult, error) {
+	raw := src.reader()
+	dec, err := zstd.NewReader(raw)
 	if err != nil {
-		return e.exploreOpaque(input, "zstd")
+		return e.exploreOpaque(src, "zstd")
 	}
 	defer dec.Close()
+	return e.exploreCompressed(ctx, src, raw, dec, "zstd", false)
+}
 
-	var buf bytes.Buffer
-	_, err = io.Copy(&buf, dec)
-	if err != nil {
-		return e.exploreOpaque(input, "zstd")
+// exploreCompressed peeks at the decompressed stream of a standalone
+// compressed file and explores it as tar when it holds one. Otherwise the
+// stream is counted, not buffered, to report the uncompressed size.
+func (e *ArchiveExplorer) exploreCompressed(ctx context.Context, src archiveSource, raw io.Seeker, decompressed io.Reader, compression string, withRatio bool) (ExploreResult, error) {
+	br := bufio.NewReaderSize(decompressed, archiveHeadBytes)
+	if head, _ := br.Peek(archiveHeadBytes); isTAR(head) {
+		format := "tar." + compression
+		if compression == "gzip" {
+			format = "tar.gz"
+		}
+		return e.exploreTARReader(ctx, src, raw, br, format)
 	}
 
-	tarData := buf.Bytes()
-	if isTAR(tarData) {
-		return e.exploreTARReader(input, bytes.NewReader(tarData), "tar.zst")
-	}
+	uncompressed, err := io.Copy(io.Discard, br)
 
-	// Plain zstd file.
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
-	summary.WriteString("Format: zstd\n")
-	fmt.Fprintf(&summary, "Compressed size: %d bytes\n", len(input.Content))
-	fmt.Fprintf(&summary, "Uncompressed size: %d bytes\n", len(tarData))
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
+	fmt.Fprintf(&summary, "Format: %s\n", compression)
+	fmt.Fprintf(&summary, "Compressed size: %d bytes\n", src.size)
+	if err != nil {
+		examined, _ := raw.Seek(0, io.SeekCurrent)
+		degradedExploration{
+			Failed:   compression + " decompression: " + err.Error(),
+			Progress: fmt.Sprintf("%d bytes decompressed before the error", uncompressed),
+			Examined: examined,
+			Size:     src.size,
+			NextSteps: []string{
+				"The file is probably truncated or corrupted; re-download or re-create it",
+			},
+		}.write(&summary)
+		result := summary.String()
+		return ExploreResult{
+			Summary:       result,
+			ExplorerUsed:  "archive",
+			TokenEstimate: estimateTokens(result),
+		}, nil
+	}
+	fmt.Fprintf(&summary, "Uncompressed size: %d bytes\n", uncompressed)
+	if withRatio && src.size > 0 {
+		ratio := float64(src.size) / float64(uncompressed) * 100
+		fmt.Fprintf(&summary, "Compression ratio: %.1f%%\n", ratio)
+	}
+
+	var safety archiveSafety
+	safety.checkTotal(src.size, uncompressed)
+	// EXCEED MODE: expansion estimate and bomb check.
+	if e.formatterProfile == OutputProfileEnhancement {
+		safety.write(&summary, src.size, uncompressed, false)
+	}
 
 	result := summary.String()
 	return ExploreResult{
 		Summary:       result,
 		ExplorerUsed:  "archive",
 		TokenEstimate: estimateTokens(result),
+		Facts:         safety.addFacts(&Facts{Counts: map[string]int64{"uncompressed_bytes": uncompressed}}),
 	}, nil
 }
 
 // exploreDeb explores Debian .deb files (ar format).
-func (e *ArchiveExplorer) exploreDeb(input ExploreInput) (ExploreResult, error) {
+func (e *ArchiveExplorer) exploreDeb(ctx context.Context, src archiveSource) (ExploreResult, error) {
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
 	summary.WriteString("Format: deb (ar archive)\n")
-	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
+	fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
 
 	// Parse ar member headers. The ar format is:
 	//   "!<arch>\n" (8 bytes global header)
@@ -735,9 +824,16 @@ func (e *ArchiveExplorer) exploreDeb(input ExploreInput) (ExploreResult, error)
 	const arHeaderLen = 8
 	const memberHeaderLen = 60
 
-	data := input.Content
-	if len(data) < arHeaderLen || string(data[:arHeaderLen]) != "!<arch>\n" {
-		summary.WriteString("Warning: invalid ar header\n")
+	if string(src.head(arHeaderLen)) != "!<arch>\n" {
+		degradedExploration{
+			Failed:   `ar signature check: missing "!<arch>" magic`,
+			Progress: "no members read",
+			Examined: arHeaderLen,
+			Size:     src.size,
+			NextSteps: []string{
+				"Check the file type with the file command; the extension may be wrong",
+			},
+		}.write(&summary)
 		result := summary.String()
 		return ExploreResult{
 			Summary:       result,
@@ -747,17 +843,28 @@ func (e *ArchiveExplorer) exploreDeb(input ExploreInput) (ExploreResult, error)
 	}
 
 	summary.WriteString("\nMembers:\n")
-	pos := arHeaderLen
-	for pos+memberHeaderLen <= len(data) {
-		name := strings.TrimRight(string(data[pos:pos+16]), " ")
+	hdr := make([]byte, memberHeaderLen)
+	pos := int64(arHeaderLen)
+	members := 0
+	for pos+memberHeaderLen <= src.size {
+		if _, err := src.r.ReadAt(hdr, pos); err != nil {
+			break
+		}
+		name := strings.TrimRight(string(hdr[:16]), " ")
 		// Remove trailing "/" that ar uses.
 		name = strings.TrimRight(name, "/")
-		sizeStr := strings.TrimSpace(string(data[pos+48 : pos+58]))
+		sizeStr := strings.TrimSpace(string(hdr[48:58]))
 
-		var size int
+		var size int64
 		fmt.Sscanf(sizeStr, "%d", &size)
+		if size < 0 {
+			break
+		}
 
 		fmt.Fprintf(&summary, "  - %s (%s)\n", name, formatSize(uint64(size)))
+		members++
+		data := io.NewSectionReader(src.r, pos+memberHeaderLen, size)
+		e.visitEntry(ctx, src, name, size, func() (io.ReaderAt, error) { return data, nil })
 
 		// Advance past header + data (2-byte aligned).
 		pos += memberHeaderLen + size
@@ -766,26 +873,37 @@ func (e *ArchiveExplorer) exploreDeb(input ExploreInput) (ExploreResult, error)
 		}
 	}
 
+	facts := &Facts{}
+	facts.count("members", members)
 	result := summary.String()
 	return ExploreResult{
 		Summary:       result,
 		ExplorerUsed:  "archive",
 		TokenEstimate: estimateTokens(result),
+		Facts:         facts,
 	}, nil
 }
 
 // exploreRPM explores RPM package files by parsing the 96-byte lead.
-func (e *ArchiveExplorer) exploreRPM(input ExploreInput) (ExploreResult, error) {
+func (e *ArchiveExplorer) exploreRPM(src archiveSource) (ExploreResult, error) {
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
 	summary.WriteString("Format: RPM package\n")
-	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
+	fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
 
-	data := input.Content
 	// RPM lead is 96 bytes.
 	const rpmLeadLen = 96
+	data := src.head(rpmLeadLen)
 	if len(data) < rpmLeadLen {
-		summary.WriteString("Warning: file too small for RPM lead\n")
+		degradedExploration{
+			Failed:   fmt.Sprintf("RPM lead read: %d of %d bytes available", len(data), rpmLeadLen),
+			Progress: "lead incomplete, no header fields read",
+			Examined: int64(len(data)),
+			Size:     src.size,
+			NextSteps: []string{
+				"The file is probably truncated; re-download it",
+			},
+		}.write(&summary)
 		result := summary.String()
 		return ExploreResult{
 			Summary:       result,
@@ -796,7 +914,15 @@ func (e *ArchiveExplorer) exploreRPM(input ExploreInput) (ExploreResult, error)
 
 	// Verify magic: 0xedabeedb.
 	if data[0] != 0xed || data[1] != 0xab || data[2] != 0xee || data[3] != 0xdb {
-		summary.WriteString("Warning: invalid RPM magic\n")
+		degradedExploration{
+			Failed:   fmt.Sprintf("RPM magic check: got % x, want ed ab ee db", data[:4]),
+			Progress: "no header fields read",
+			Examined: 4,
+			Size:     src.size,
+			NextSteps: []string{
+				"Check the file type with the file command; the extension may be wrong",
+			},
+		}.write(&summary)
 		result := summary.String()
 		return ExploreResult{
 			Summary:       result,
@@ -840,8 +966,218 @@ func (e *ArchiveExplorer) exploreRPM(input ExploreInput) (ExploreResult, error)
 	}, nil
 }
 
+// archiveListers are the external tools that can list 7z and RAR archives,
+// in order of preference. The 7-Zip variants print a machine-readable
+// technical listing; bsdtar (libarchive) prints an ls-style one.
+var archiveListers = []struct {
+	name  string
+	args  []string
+	parse func(string) ([]listedEntry, bool)
+}{
+	{name: "7zz", args: []string{"l", "-slt"}, parse: parse7zListing},
+	{name: "7z", args: []string{"l", "-slt"}, parse: parse7zListing},
+	{name: "7za", args: []string{"l", "-slt"}, parse: parse7zListing},
+	{name: "bsdtar", args: []string{"-tvf"}, parse: parseBsdtarListing},
+}
+
+// listedEntry is an archive entry reported by an external lister.
+type listedEntry struct {
+	name string
+	size int64
+	dir  bool
+}
+
+// exploreListed lists 7z and RAR archives with the first available
+// external tool. Without one it falls back to exploreOpaque.
+func (e *ArchiveExplorer) exploreListed(ctx context.Context, src archiveSource, family string) (ExploreResult, error) {
+	var (
+		entries   []listedEntry
+		encrypted bool
+		lister    string
+	)
+	run := func(path string) error {
+		for _, l := range archiveListers {
+			output := runTool(ctx, l.name, append(slices.Clone(l.args), path)...)
+			if output == "" {
+				continue
+			}
+			if got, enc := l.parse(output); len(got) > 0 {
+				entries, encrypted, lister = got, enc, l.name
+				return nil
+			}
+		}
+		return nil
+	}
+	// Tools need a path; reuse the file when the source is one.
+	if f, ok := src.r.(*os.File); ok {
+		_ = run(f.Name())
+	} else {
+		_ = withTempFileFrom("crush-archive-*."+family, src.reader(), run)
+	}
+	if lister == "" {
+		return e.exploreOpaque(src, family)
+	}
+
+	var (
+		fileCount int
+		dirCount  int
+		totalSize int64
+		extHist   = make(map[string]int)
+		topLevel  = make(map[string]bool)
+		largest   topFiles
+		safety    archiveSafety
+	)
+	for _, entry := range entries {
+		safety.checkPath(entry.name)
+		name := strings.TrimSuffix(entry.name, "/")
+		parts := strings.SplitN(name, "/", 2)
+		if parts[0] != "" {
+			if entry.dir || len(parts) > 1 {
+				topLevel[parts[0]+"/"] = true
+			} else {
+				topLevel[parts[0]] = true
+			}
+		}
+		if entry.dir {
+			dirCount++
+			continue
+		}
+		fileCount++
+		totalSize += entry.size
+		if ext := strings.ToLower(filepath.Ext(name)); ext != "" {
+			extHist[ext]++
+		}
+		largest.add(name, entry.size)
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
+	fmt.Fprintf(&summary, "Format: %s\n", family)
+	fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
+	fmt.Fprintf(&summary, "Files: %d, Directories: %d\n", fileCount, dirCount)
+	fmt.Fprintf(&summary, "Total uncompressed: %s\n", formatSize(uint64(totalSize)))
+	if encrypted {
+		summary.WriteString("Encrypted: yes\n")
+	}
+	fmt.Fprintf(&summary, "Listed with: %s\n", lister)
+
+	if len(topLevel) > 0 {
+		summary.WriteString("\nTop-level structure:\n")
+		for _, entry := range sortedKeys(topLevel) {
+			fmt.Fprintf(&summary, "  - %s\n", entry)
+		}
+	}
+	if len(extHist) > 0 {
+		summary.WriteString("\nExtension histogram:\n")
+		writeCounts(&summary, extHist, "")
+	}
+	if len(largest) > 0 {
+		summary.WriteString("\nLargest files:\n")
+		for _, f := range largest {
+			fmt.Fprintf(&summary, "  - %s (%s)\n", f.name, formatSize(uint64(f.size)))
+		}
+	}
+
+	safety.checkTotal(src.size, totalSize)
+	// EXCEED MODE: extraction safety of the listed entries.
+	if e.formatterProfile == OutputProfileEnhancement {
+		safety.write(&summary, src.size, totalSize, false)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "archive",
+		TokenEstimate: estimateTokens(result),
+		Facts:         safety.addFacts(archiveFacts(fileCount, dirCount, totalSize)),
+	}, nil
+}
+
+// parse7zListing parses the output of "7z l -slt". Entries follow a line of
+// dashes as blank-line separated blocks of "Key = Value" properties.
+func parse7zListing(output string) ([]listedEntry, bool) {
+	var (
+		entries   []listedEntry
+		encrypted bool
+		inEntries bool
+		current   listedEntry
+		hasPath   bool
+	)
+	flush := func() {
+		if hasPath {
+			entries = append(entries, current)
+		}
+		current, hasPath = listedEntry{}, false
+	}
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !inEntries {
+			inEntries = strings.HasPrefix(line, "----------")
+			continue
+		}
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Path":
+			current.name, hasPath = filepath.ToSlash(value), true
+		case "Size":
+			current.size, _ = strconv.ParseInt(value, 10, 64)
+		case "Folder":
+			current.dir = value == "+"
+		case "Attributes":
+			current.dir = current.dir || strings.HasPrefix(value, "D")
+		case "Encrypted":
+			encrypted = encrypted || value == "+"
+		}
+	}
+	flush()
+	return entries, encrypted
+}
+
+// parseBsdtarListing parses the ls-style output of "bsdtar -tvf": mode,
+// links, owner, group, size and a three-field date precede the name.
+// bsdtar does not report encryption.
+func parseBsdtarListing(output string) ([]listedEntry, bool) {
+	const fieldsBeforeName = 8
+
+	var entries []listedEntry
+	for line := range strings.SplitSeq(output, "\n") {
+		rest := strings.TrimRight(line, "\r")
+		fields := make([]string, 0, fieldsBeforeName)
+		for len(fields) < fieldsBeforeName {
+			rest = strings.TrimLeft(rest, " ")
+			field, tail, ok := strings.Cut(rest, " ")
+			if !ok {
+				break
+			}
+			fields, rest = append(fields, field), tail
+		}
+		name := strings.TrimLeft(rest, " ")
+		if len(fields) < fieldsBeforeName || name == "" {
+			continue
+		}
+		mode := fields[0]
+		if strings.HasPrefix(mode, "l") {
+			name, _, _ = strings.Cut(name, " -> ")
+		}
+		size, _ := strconv.ParseInt(fields[4], 10, 64)
+		entries = append(entries, listedEntry{
+			name: name,
+			size: size,
+			dir:  strings.HasPrefix(mode, "d"),
+		})
+	}
+	return entries, false
+}
+
 // exploreOpaque handles formats we can only identify but not list.
-func (e *ArchiveExplorer) exploreOpaque(input ExploreInput, family string) (ExploreResult, error) {
+func (e *ArchiveExplorer) exploreOpaque(src archiveSource, family string) (ExploreResult, error) {
 	displayName := family
 	if displayName == "" {
 		displayName = "unknown archive"
@@ -849,10 +1185,11 @@ func (e *ArchiveExplorer) exploreOpaque(input ExploreInput, family string) (Expl
 
 	// Try magic byte identification for better naming.
 	if family == "" {
+		head := src.head(archiveHeadBytes)
 		for _, sig := range magicSignatures {
 			end := sig.offset + len(sig.magic)
-			if len(input.Content) >= end &&
-				bytes.Equal(input.Content[sig.offset:end], sig.magic) {
+			if len(head) >= end &&
+				bytes.Equal(head[sig.offset:end], sig.magic) {
 				displayName = sig.name
 				break
 			}
@@ -860,9 +1197,9 @@ func (e *ArchiveExplorer) exploreOpaque(input ExploreInput, family string) (Expl
 	}
 
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
 	fmt.Fprintf(&summary, "Format: %s\n", displayName)
-	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
+	fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
 	summary.WriteString("Note: contents cannot be listed without external tools\n")
 
 	result := summary.String()
@@ -875,12 +1212,20 @@ func (e *ArchiveExplorer) exploreOpaque(input ExploreInput, family string) (Expl
 
 // compressedFallback returns an error summary for a compressed archive we
 // cannot decompress.
-func (e *ArchiveExplorer) compressedFallback(input ExploreInput, format string, err error) (ExploreResult, error) {
+func (e *ArchiveExplorer) compressedFallback(src archiveSource, format string, err error) (ExploreResult, error) {
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(input.Path))
+	fmt.Fprintf(&summary, "Archive file: %s\n", filepath.Base(src.path))
 	fmt.Fprintf(&summary, "Format: %s\n", format)
-	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
-	fmt.Fprintf(&summary, "Error: could not decompress: %v\n", err)
+	fmt.Fprintf(&summary, "Size: %d bytes\n", src.size)
+	degradedExploration{
+		Failed:   "decompression: " + err.Error(),
+		Progress: "compressed stream header rejected, no entries read",
+		Examined: min(src.size, archiveHeadBytes),
+		Size:     src.size,
+		NextSteps: []string{
+			"Check the file type with the file command; the extension may not match the compression",
+		},
+	}.write(&summary)
 
 	result := summary.String()
 	return ExploreResult{
@@ -890,6 +1235,41 @@ func (e *ArchiveExplorer) compressedFallback(input ExploreInput, format string,
 	}, nil
 }
 
+// zipDegradation describes a ZIP archive whose central directory could not
+// be read. ZIP readers look for the end of central directory record in the
+// trailing 64 KiB of the file.
+func zipDegradation(size int64, err error) degradedExploration {
+	const directoryEndSearch = 65 * 1024
+	progress := "central directory unreadable, no entries read"
+	if errors.Is(err, zip.ErrFormat) {
+		progress = "end of central directory record not found, no entries read"
+	}
+	return degradedExploration{
+		Failed:   "ZIP central directory read: " + err.Error(),
+		Progress: progress,
+		Examined: min(size, directoryEndSearch),
+		Size:     size,
+		NextSteps: []string{
+			"The archive is probably truncated; an interrupted download loses the central directory",
+			"Re-download the archive, or recover entries from local headers with bsdtar -tvf",
+		},
+	}
+}
+
+// readZIPEntry reads the content of a ZIP entry.
+func readZIPEntry(f *zip.File) (io.ReaderAt, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, int64(f.UncompressedSize64)))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
 // isTAR checks whether data looks like a tar archive by checking the ustar
 // magic at offset 257.
 func isTAR(data []byte) bool {
@@ -932,24 +1312,30 @@ func formatSize(bytes uint64) string {
 	}
 }
 
-// sortedKeys returns the keys of a map sorted alphabetically.
-func sortedKeys(m map[string]bool) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	return keys
-}
+// largestFilesShown is how many of the largest files a summary lists.
+const largestFilesShown = 5
 
-// zipFileInfo holds name and size for sorting.
-type zipFileInfo struct {
+// archiveFileInfo holds name and size of an archive entry.
+type archiveFileInfo struct {
 	name string
-	size uint64
+	size int64
 }
 
-// tarFileInfo holds name and size for sorting.
-type tarFileInfo struct {
-	name string
-	size int64
+// topFiles keeps the largest files seen so far, largest first, without
+// holding every entry of the archive. Files of equal size keep the order
+// they were added in.
+type topFiles []archiveFileInfo
+
+func (t *topFiles) add(name string, size int64) {
+	i := len(*t)
+	for i > 0 && (*t)[i-1].size < size {
+		i--
+	}
+	if i >= largestFilesShown {
+		return
+	}
+	*t = slices.Insert(*t, i, archiveFileInfo{name: name, size: size})
+	if len(*t) > largestFilesShown {
+		*t = (*t)[:largestFilesShown]
+	}
 }
@@ -0,0 +1,674 @@
+package explorer
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxAppMetadataBytes bounds the AndroidManifest.xml or Info.plist read
+// from an app package.
+const maxAppMetadataBytes = 1 << 20
+
+// maxAppPermissions is how many permissions are listed.
+const maxAppPermissions = 40
+
+// appMetadata is what an app package declares about itself, from the
+// AndroidManifest.xml of an APK or the Info.plist of an IPA bundle.
+type appMetadata struct {
+	// source is the archive entry the metadata was read from.
+	source      string
+	id          string
+	name        string
+	version     string
+	build       string
+	minOS       string
+	targetOS    string
+	permissions []string
+	err         error
+}
+
+// isAppMetadataEntry reports whether name is the metadata entry of an app
+// package of family: AndroidManifest.xml at the root of an APK, or the
+// Info.plist of the bundle under Payload/ in an IPA.
+func isAppMetadataEntry(family, name string) bool {
+	switch family {
+	case "apk":
+		return name == "AndroidManifest.xml"
+	case "ipa":
+		dir, file := path.Split(name)
+		return file == "Info.plist" && strings.HasPrefix(dir, "Payload/") &&
+			strings.Count(dir, "/") == 2 && strings.HasSuffix(dir, ".app/")
+	}
+	return false
+}
+
+// readAppMetadata parses the metadata entry name of an app package.
+func readAppMetadata(family, name string, open func() (io.ReadCloser, error)) *appMetadata {
+	m := &appMetadata{source: name}
+	rc, err := open()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, maxAppMetadataBytes))
+	rc.Close()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	if family == "apk" {
+		m.err = m.parseAndroidManifest(data)
+	} else {
+		m.err = m.parseInfoPlist(data)
+	}
+	return m
+}
+
+// write appends the app metadata section.
+func (m *appMetadata) write(sb *strings.Builder, profile OutputProfile) {
+	fmt.Fprintf(sb, "\nApp metadata (%s):\n", m.source)
+	if m.err != nil {
+		fmt.Fprintf(sb, "  - Unreadable: %v\n", m.err)
+		return
+	}
+	if m.id != "" {
+		fmt.Fprintf(sb, "  - Package: %s\n", m.id)
+	}
+	if m.name != "" {
+		fmt.Fprintf(sb, "  - Name: %s\n", m.name)
+	}
+	switch {
+	case m.version != "" && m.build != "" && m.build != m.version:
+		fmt.Fprintf(sb, "  - Version: %s (build %s)\n", m.version, m.build)
+	case m.version != "":
+		fmt.Fprintf(sb, "  - Version: %s\n", m.version)
+	case m.build != "":
+		fmt.Fprintf(sb, "  - Version: build %s\n", m.build)
+	}
+	if m.minOS != "" {
+		fmt.Fprintf(sb, "  - Minimum OS: %s\n", m.minOS)
+	}
+	if m.targetOS != "" {
+		fmt.Fprintf(sb, "  - Target OS: %s\n", m.targetOS)
+	}
+	for i, p := range m.permissions {
+		if i == maxAppPermissions {
+			fmt.Fprintf(sb, "  - Permission: %s\n", overflowMarker(profile, len(m.permissions)-i, false))
+			break
+		}
+		fmt.Fprintf(sb, "  - Permission: %s\n", p)
+	}
+}
+
+// addFacts records the permission count in facts.
+func (m *appMetadata) addFacts(facts *Facts) *Facts {
+	if m == nil || m.err != nil {
+		return facts
+	}
+	if facts == nil {
+		facts = &Facts{}
+	}
+	facts.count("permissions", len(m.permissions))
+	return facts
+}
+
+// parseAndroidManifest reads the package, version, SDK levels and
+// permissions from an AndroidManifest.xml, compiled to binary XML as in
+// APKs or plain text as in sources.
+func (m *appMetadata) parseAndroidManifest(data []byte) error {
+	visit := func(el xmlElement) {
+		switch el.name {
+		case "manifest":
+			m.id = el.attrs["package"]
+			m.version = el.attrs["versionName"]
+			m.build = el.attrs["versionCode"]
+		case "uses-sdk":
+			m.minOS = androidVersion(el.attrs["minSdkVersion"])
+			m.targetOS = androidVersion(el.attrs["targetSdkVersion"])
+		case "uses-permission", "uses-permission-sdk-23":
+			if p := el.attrs["name"]; p != "" && !slices.Contains(m.permissions, p) {
+				m.permissions = append(m.permissions, p)
+			}
+		case "application":
+			if label := el.attrs["label"]; label != "" && !strings.HasPrefix(label, "@") {
+				m.name = label
+			}
+		}
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return walkTextXML(data, visit)
+	}
+	return walkBinaryXML(data, visit)
+}
+
+// androidVersionNames maps API levels to Android release versions.
+var androidVersionNames = map[int]string{
+	14: "4.0", 15: "4.0.3", 16: "4.1", 17: "4.2", 18: "4.3", 19: "4.4",
+	21: "5.0", 22: "5.1", 23: "6.0", 24: "7.0", 25: "7.1", 26: "8.0",
+	27: "8.1", 28: "9", 29: "10", 30: "11", 31: "12", 32: "12L", 33: "13",
+	34: "14", 35: "15", 36: "16",
+}
+
+// androidVersion describes an SDK level attribute, such as "Android 7.0
+// (API 24)". Codenames and unknown levels are kept as they are.
+func androidVersion(level string) string {
+	n, err := strconv.Atoi(level)
+	if err != nil {
+		return level
+	}
+	if v, ok := androidVersionNames[n]; ok {
+		return fmt.Sprintf("Android %s (API %d)", v, n)
+	}
+	return fmt.Sprintf("API %d", n)
+}
+
+// parseInfoPlist reads the bundle id, name, version, minimum OS and the
+// privacy usage descriptions, which name the permissions an iOS app asks
+// for, from an Info.plist in XML or binary form.
+func (m *appMetadata) parseInfoPlist(data []byte) error {
+	var (
+		dict map[string]any
+		err  error
+	)
+	if bytes.HasPrefix(data, []byte("bplist00")) {
+		dict, err = parseBinaryPlist(data)
+	} else {
+		dict, err = parseXMLPlist(data)
+	}
+	if err != nil {
+		return err
+	}
+	str := func(key string) string {
+		s, _ := dict[key].(string)
+		return s
+	}
+	m.id = str("CFBundleIdentifier")
+	m.name = cmp.Or(str("CFBundleDisplayName"), str("CFBundleName"))
+	m.version = str("CFBundleShortVersionString")
+	m.build = str("CFBundleVersion")
+	if v := str("MinimumOSVersion"); v != "" {
+		m.minOS = "iOS " + v
+	} else if v := str("LSMinimumSystemVersion"); v != "" {
+		m.minOS = "macOS " + v
+	}
+	for key := range dict {
+		if strings.HasSuffix(key, "UsageDescription") {
+			m.permissions = append(m.permissions, key)
+		}
+	}
+	slices.Sort(m.permissions)
+	return nil
+}
+
+// xmlElement is a start element with its attributes keyed by local name.
+type xmlElement struct {
+	name  string
+	attrs map[string]string
+}
+
+// walkTextXML calls visit for each start element of a text XML document.
+func walkTextXML(data []byte, visit func(xmlElement)) error {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			el := xmlElement{name: se.Name.Local, attrs: make(map[string]string, len(se.Attr))}
+			for _, a := range se.Attr {
+				el.attrs[a.Name.Local] = a.Value
+			}
+			visit(el)
+		}
+	}
+}
+
+// Android binary XML chunk types and value types.
+const (
+	axmlStringPool   = 0x0001
+	axmlDocument     = 0x0003
+	axmlResourceMap  = 0x0180
+	axmlStartElement = 0x0102
+	axmlUTF8         = 1 << 8
+	axmlNoString     = 0xffffffff
+
+	axmlTypeReference = 0x01
+	axmlTypeString    = 0x03
+	axmlTypeIntDec    = 0x10
+	axmlTypeIntHex    = 0x11
+	axmlTypeBoolean   = 0x12
+)
+
+// androidAttrNames names the framework attributes read from manifests, for
+// binary XML whose attribute names were stripped from the string pool.
+var androidAttrNames = map[uint32]string{
+	0x01010001: "label",
+	0x01010003: "name",
+	0x0101020c: "minSdkVersion",
+	0x0101021b: "versionCode",
+	0x0101021c: "versionName",
+	0x01010270: "targetSdkVersion",
+}
+
+// walkBinaryXML calls visit for each start element of an Android binary
+// XML document.
+func walkBinaryXML(data []byte, visit func(xmlElement)) error {
+	le := binary.LittleEndian
+	if len(data) < 8 || le.Uint16(data) != axmlDocument {
+		return errors.New("not an Android binary XML document")
+	}
+	var (
+		strs   []string
+		resIDs []uint32
+	)
+	for off := int(le.Uint16(data[2:])); off+8 <= len(data); {
+		typ := le.Uint16(data[off:])
+		hdr := int(le.Uint16(data[off+2:]))
+		size := int(le.Uint32(data[off+4:]))
+		if size < 8 || hdr > size || size > len(data)-off {
+			return fmt.Errorf("malformed chunk at offset %d", off)
+		}
+		chunk := data[off : off+size]
+		switch typ {
+		case axmlStringPool:
+			strs = axmlStrings(chunk, hdr)
+		case axmlResourceMap:
+			for i := hdr; i+4 <= size; i += 4 {
+				resIDs = append(resIDs, le.Uint32(chunk[i:]))
+			}
+		case axmlStartElement:
+			if el, ok := axmlElement(chunk, hdr, strs, resIDs); ok {
+				visit(el)
+			}
+		}
+		off += size
+	}
+	return nil
+}
+
+// axmlStrings decodes a binary XML string pool chunk.
+func axmlStrings(chunk []byte, hdr int) []string {
+	le := binary.LittleEndian
+	if hdr < 28 || len(chunk) < 28 {
+		return nil
+	}
+	count := int(le.Uint32(chunk[8:]))
+	utf8 := le.Uint32(chunk[16:])&axmlUTF8 != 0
+	start := int(le.Uint32(chunk[20:]))
+	if count > (len(chunk)-hdr)/4 || start > len(chunk) {
+		return nil
+	}
+	strs := make([]string, count)
+	for i := range strs {
+		off := start + int(le.Uint32(chunk[hdr+4*i:]))
+		if off < start || off >= len(chunk) {
+			continue
+		}
+		if utf8 {
+			strs[i] = axmlUTF8String(chunk[off:])
+		} else {
+			strs[i] = axmlUTF16String(chunk[off:])
+		}
+	}
+	return strs
+}
+
+// axmlUTF8String decodes a string pool entry: its UTF-16 and UTF-8
+// lengths, each in one or two bytes, then the UTF-8 bytes.
+func axmlUTF8String(b []byte) string {
+	length := func(b []byte) (int, int) {
+		if len(b) == 0 {
+			return 0, 0
+		}
+		if b[0]&0x80 == 0 {
+			return int(b[0]), 1
+		}
+		if len(b) < 2 {
+			return 0, 0
+		}
+		return int(b[0]&0x7f)<<8 | int(b[1]), 2
+	}
+	_, n := length(b)
+	size, m := length(b[n:])
+	b = b[n+m:]
+	if size > len(b) {
+		return ""
+	}
+	return string(b[:size])
+}
+
+// axmlUTF16String decodes a string pool entry: its length in code units,
+// in one or two words, then the UTF-16LE code units.
+func axmlUTF16String(b []byte) string {
+	le := binary.LittleEndian
+	if len(b) < 2 {
+		return ""
+	}
+	size, n := int(le.Uint16(b)), 2
+	if size&0x8000 != 0 {
+		if len(b) < 4 {
+			return ""
+		}
+		size, n = (size&0x7fff)<<16|int(le.Uint16(b[2:])), 4
+	}
+	b = b[n:]
+	if size > len(b)/2 {
+		return ""
+	}
+	units := make([]uint16, size)
+	for i := range units {
+		units[i] = le.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// axmlElement decodes a start element chunk.
+func axmlElement(chunk []byte, hdr int, strs []string, resIDs []uint32) (xmlElement, bool) {
+	le := binary.LittleEndian
+	str := func(i uint32) string {
+		if int64(i) < int64(len(strs)) {
+			return strs[i]
+		}
+		return ""
+	}
+	if hdr+20 > len(chunk) {
+		return xmlElement{}, false
+	}
+	body := chunk[hdr:]
+	el := xmlElement{name: str(le.Uint32(body[4:])), attrs: make(map[string]string)}
+	attrStart := int(le.Uint16(body[8:]))
+	attrSize := int(le.Uint16(body[10:]))
+	attrCount := int(le.Uint16(body[12:]))
+	if attrSize < 20 {
+		return el, true
+	}
+	for i := range attrCount {
+		off := attrStart + i*attrSize
+		if off+20 > len(body) {
+			break
+		}
+		a := body[off:]
+		nameIdx := le.Uint32(a[4:])
+		name := str(nameIdx)
+		if name == "" && int64(nameIdx) < int64(len(resIDs)) {
+			name = androidAttrNames[resIDs[nameIdx]]
+		}
+		if name == "" {
+			continue
+		}
+		raw, typ, val := le.Uint32(a[8:]), a[15], le.Uint32(a[16:])
+		switch {
+		case raw != axmlNoString:
+			el.attrs[name] = str(raw)
+		case typ == axmlTypeString:
+			el.attrs[name] = str(val)
+		case typ == axmlTypeIntDec:
+			el.attrs[name] = strconv.Itoa(int(int32(val)))
+		case typ == axmlTypeIntHex:
+			el.attrs[name] = fmt.Sprintf("0x%x", val)
+		case typ == axmlTypeBoolean:
+			el.attrs[name] = strconv.FormatBool(val != 0)
+		case typ == axmlTypeReference:
+			el.attrs[name] = fmt.Sprintf("@0x%08x", val)
+		default:
+			el.attrs[name] = fmt.Sprintf("0x%x", val)
+		}
+	}
+	return el, true
+}
+
+// parseXMLPlist decodes the top-level dictionary of an XML property list.
+func parseXMLPlist(data []byte) (map[string]any, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("no dictionary in property list: %w", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "dict" {
+			v, err := xmlPlistValue(d, se)
+			if err != nil {
+				return nil, err
+			}
+			dict, _ := v.(map[string]any)
+			return dict, nil
+		}
+	}
+}
+
+// xmlPlistValue decodes the property list value that start opens.
+func xmlPlistValue(d *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict", "array":
+		dict := make(map[string]any)
+		var (
+			list []any
+			key  string
+		)
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := d.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				v, err := xmlPlistValue(d, t)
+				if err != nil {
+					return nil, err
+				}
+				dict[key] = v
+				list = append(list, v)
+			case xml.EndElement:
+				if start.Name.Local == "array" {
+					return list, nil
+				}
+				return dict, nil
+			}
+		}
+	case "true", "false":
+		return start.Name.Local == "true", d.Skip()
+	case "integer":
+		var s string
+		if err := d.DecodeElement(&s, &start); err != nil {
+			return nil, err
+		}
+		n, _ := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		return n, nil
+	default:
+		var s string
+		err := d.DecodeElement(&s, &start)
+		return s, err
+	}
+}
+
+// maxPlistDepth bounds the nesting of binary property list containers,
+// which may reference each other in cycles.
+const maxPlistDepth = 16
+
+// binaryPlist decodes the objects of a binary property list.
+type binaryPlist struct {
+	data        []byte
+	offsetSize  int
+	refSize     int
+	numObjects  uint64
+	offsetTable int
+}
+
+// parseBinaryPlist decodes the top-level dictionary of a binary property
+// list.
+func parseBinaryPlist(data []byte) (map[string]any, error) {
+	if len(data) < 8+32 {
+		return nil, errors.New("binary property list too short")
+	}
+	be := binary.BigEndian
+	trailer := data[len(data)-32:]
+	p := &binaryPlist{
+		data:       data,
+		offsetSize: int(trailer[6]),
+		refSize:    int(trailer[7]),
+		numObjects: be.Uint64(trailer[8:]),
+	}
+	top := be.Uint64(trailer[16:])
+	table := be.Uint64(trailer[24:])
+	if p.offsetSize < 1 || p.offsetSize > 8 || p.refSize < 1 || p.refSize > 8 ||
+		p.numObjects > uint64(len(data)) || table > uint64(len(data)) ||
+		table+p.numObjects*uint64(p.offsetSize) > uint64(len(data)-32) {
+		return nil, errors.New("malformed binary property list trailer")
+	}
+	p.offsetTable = int(table)
+	v, err := p.object(top, 0)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.New("property list is not a dictionary")
+	}
+	return dict, nil
+}
+
+// object decodes the object ref refers to.
+func (p *binaryPlist) object(ref uint64, depth int) (any, error) {
+	if ref >= p.numObjects {
+		return nil, fmt.Errorf("object reference %d out of range", ref)
+	}
+	if depth > maxPlistDepth {
+		return nil, errors.New("property list nested too deeply")
+	}
+	at := p.offsetTable + int(ref)*p.offsetSize
+	off := int(readUintBE(p.data[at : at+p.offsetSize]))
+	if off >= len(p.data) {
+		return nil, fmt.Errorf("object offset %d out of range", off)
+	}
+	marker := p.data[off]
+	kind, info := marker>>4, int(marker&0x0f)
+	switch kind {
+	case 0x0:
+		switch marker {
+		case 0x08:
+			return false, nil
+		case 0x09:
+			return true, nil
+		}
+		return nil, nil
+	case 0x1:
+		n := 1 << info
+		if n > 8 || off+1+n > len(p.data) {
+			return nil, errors.New("malformed integer")
+		}
+		return int64(readUintBE(p.data[off+1 : off+1+n])), nil
+	case 0x5, 0x6, 0xa, 0xd:
+	default:
+		// Reals, dates, data and UIDs carry nothing read here.
+		return nil, nil
+	}
+	count, start, err := p.length(off, info)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case 0x5:
+		if start+count > len(p.data) {
+			return nil, errors.New("malformed string")
+		}
+		return string(p.data[start : start+count]), nil
+	case 0x6:
+		if start+2*count > len(p.data) {
+			return nil, errors.New("malformed string")
+		}
+		units := make([]uint16, count)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(p.data[start+2*i:])
+		}
+		return string(utf16.Decode(units)), nil
+	}
+	refs := count
+	if kind == 0xd {
+		refs *= 2
+	}
+	if start+refs*p.refSize > len(p.data) {
+		return nil, errors.New("malformed container")
+	}
+	refAt := func(i int) uint64 {
+		return readUintBE(p.data[start+i*p.refSize : start+(i+1)*p.refSize])
+	}
+	if kind == 0xa {
+		list := make([]any, 0, count)
+		for i := range count {
+			v, err := p.object(refAt(i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	}
+	dict := make(map[string]any, count)
+	for i := range count {
+		k, err := p.object(refAt(i), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			continue
+		}
+		v, err := p.object(refAt(count+i), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		dict[key] = v
+	}
+	return dict, nil
+}
+
+// length returns the element count of the object at off and where its
+// contents start. Counts of 15 or more follow the marker as an integer.
+func (p *binaryPlist) length(off, info int) (int, int, error) {
+	if info != 0x0f {
+		return info, off + 1, nil
+	}
+	if off+2 > len(p.data) || p.data[off+1]>>4 != 0x1 {
+		return 0, 0, errors.New("malformed length")
+	}
+	n := 1 << (p.data[off+1] & 0x0f)
+	if n > 8 || off+2+n > len(p.data) {
+		return 0, 0, errors.New("malformed length")
+	}
+	count := readUintBE(p.data[off+2 : off+2+n])
+	if count > uint64(len(p.data)) {
+		return 0, 0, errors.New("malformed length")
+	}
+	return int(count), off + 2 + n, nil
+}
+
+// readUintBE decodes a big-endian unsigned integer of up to 8 bytes.
+func readUintBE(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
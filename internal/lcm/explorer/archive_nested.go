@@ -0,0 +1,188 @@
+package explorer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	defaultNestingDepth       = 2
+	defaultNestedEntryBytes   = 32 << 20
+	maxNestedArchivesReported = 20
+)
+
+// ArchiveNesting bounds the exploration of archives inside archives, such
+// as jars inside a ZIP or wheels inside a tarball, under the enhancement
+// profile. Zero fields take their defaults.
+type ArchiveNesting struct {
+	// MaxDepth is how many levels of nested archives are opened. Defaults
+	// to 2; negative disables nested exploration.
+	MaxDepth int
+	// MaxEntryBytes is the largest nested archive opened, in uncompressed
+	// bytes. Larger ones are named but not opened. Defaults to 32 MiB.
+	MaxEntryBytes int64
+}
+
+func (n ArchiveNesting) withDefaults() ArchiveNesting {
+	n.MaxDepth = cmp.Or(n.MaxDepth, defaultNestingDepth)
+	if n.MaxEntryBytes <= 0 {
+		n.MaxEntryBytes = defaultNestedEntryBytes
+	}
+	return n
+}
+
+// WithArchiveNesting bounds how deep and how large the nested archives the
+// archive explorer opens are.
+func WithArchiveNesting(n ArchiveNesting) RegistryOption {
+	return func(r *Registry) {
+		r.archiveNesting = n
+	}
+}
+
+// nestedFamilies are the archive families opened when nested. The others
+// need external tools or cannot be listed, and are only named.
+var nestedFamilies = map[string]bool{
+	"zip": true, "jar": true, "war": true, "ear": true, "apk": true,
+	"ipa": true, "nupkg": true, "crx": true, "xpi": true, "vsix": true, "whl": true,
+	"tar": true, "tar.gz": true, "tar.bz2": true, "tar.zst": true,
+	"gzip": true, "bzip2": true, "zstd": true,
+	"deb": true, "ar": true, "rpm": true,
+}
+
+// nestedArchive is an archive found inside the archive being explored.
+type nestedArchive struct {
+	// path is the entry's path, joined with " > " across levels.
+	path   string
+	family string
+	size   int64
+	// counts are the Facts counts of its exploration; nil when it was not
+	// opened.
+	counts map[string]int64
+	// skip says why it was not opened.
+	skip string
+}
+
+// nestedArchives collects the nested archives of one exploration, at
+// every depth, in the order they were found.
+type nestedArchives struct {
+	limits ArchiveNesting
+	found  []nestedArchive
+	more   int
+}
+
+// visitEntry opens the entry name of src, of size uncompressed bytes, and
+// explores it when it is an archive within the nesting limits. open is
+// only called then.
+func (e *ArchiveExplorer) visitEntry(ctx context.Context, src archiveSource, name string, size int64, open func() (io.ReaderAt, error)) {
+	n := src.nested
+	if n == nil {
+		return
+	}
+	family := e.resolveFamily(name, nil)
+	if family == "" {
+		return
+	}
+	if len(n.found) == maxNestedArchivesReported {
+		n.more++
+		return
+	}
+
+	found := nestedArchive{path: name, family: family, size: size}
+	if src.prefix != "" {
+		found.path = src.prefix + " > " + name
+	}
+	switch {
+	case !nestedFamilies[family]:
+		found.skip = "format not opened when nested"
+	case src.depth >= n.limits.MaxDepth:
+		found.skip = fmt.Sprintf("nesting depth limit %d reached", n.limits.MaxDepth)
+	case size > n.limits.MaxEntryBytes:
+		found.skip = fmt.Sprintf("larger than the %s entry limit", formatSize(uint64(n.limits.MaxEntryBytes)))
+	}
+	i := len(n.found)
+	n.found = append(n.found, found)
+	if found.skip != "" {
+		return
+	}
+
+	r, err := open()
+	if err != nil {
+		n.found[i].skip = "unreadable: " + err.Error()
+		return
+	}
+	result, err := e.explore(ctx, archiveSource{
+		path:   name,
+		r:      r,
+		size:   size,
+		nested: n,
+		depth:  src.depth + 1,
+		prefix: found.path,
+	})
+	switch {
+	case err != nil:
+		n.found[i].skip = "exploration failed: " + err.Error()
+	case result.Facts != nil:
+		n.found[i].counts = result.Facts.Counts
+	}
+}
+
+// write appends the nested archives section to a summary.
+func (n *nestedArchives) write(sb *strings.Builder) {
+	sb.WriteString("\nNested archives:\n")
+	for _, a := range n.found {
+		fmt.Fprintf(sb, "  - %s (%s, %s): %s\n", a.path, a.family, formatSize(uint64(a.size)), a.describe())
+	}
+	if n.more > 0 {
+		fmt.Fprintf(sb, "  - %s\n", overflowMarker(OutputProfileEnhancement, n.more, false))
+	}
+}
+
+// unsafe returns the number of unsafe entries in the nested archives,
+// counted at the level they were found.
+func (n *nestedArchives) unsafe() int64 {
+	var total int64
+	for _, a := range n.found {
+		total += a.counts["unsafe_entries"]
+	}
+	return total
+}
+
+// describe summarizes what the nested archive holds, or why it was not
+// opened.
+func (a nestedArchive) describe() string {
+	if a.skip != "" {
+		return "not opened, " + a.skip
+	}
+	var parts []string
+	if n, ok := a.counts["files"]; ok {
+		parts = append(parts, fmt.Sprintf("%d files", n))
+	}
+	if n := a.counts["directories"]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d directories", n))
+	}
+	if n, ok := a.counts["members"]; ok {
+		parts = append(parts, fmt.Sprintf("%d members", n))
+	}
+	if n, ok := a.counts["uncompressed_bytes"]; ok {
+		parts = append(parts, formatSize(uint64(n))+" uncompressed")
+	}
+	if n := a.counts["unsafe_entries"]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d unsafe entries", n))
+	}
+	if len(parts) == 0 {
+		return "contents not listed"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// archiveFacts returns the Facts counts of a listed archive.
+func archiveFacts(files, dirs int, uncompressed int64) *Facts {
+	f := &Facts{}
+	f.count("files", files)
+	f.count("directories", dirs)
+	f.Counts["uncompressed_bytes"] = uncompressed
+	return f
+}
@@ -0,0 +1,246 @@
+package explorer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxPythonRequirements is how many Requires-Dist entries are listed.
+const maxPythonRequirements = 40
+
+// maxPythonEntryPoints is how many entry points are listed.
+const maxPythonEntryPoints = 20
+
+// pythonMetadata is what a Python distribution declares about itself, from
+// the METADATA of a wheel's .dist-info or the PKG-INFO of an sdist.
+type pythonMetadata struct {
+	// source is the archive entry the metadata was read from.
+	source         string
+	name           string
+	version        string
+	summary        string
+	license        string
+	requiresPython string
+	requires       []string
+	// entryPoints are "group: name = object" lines, from entry_points.txt
+	// or, for sdists without one, the scripts tables of pyproject.toml.
+	entryPoints []string
+	err         error
+}
+
+// pythonPackage collects the metadata entries of a wheel or sdist as the
+// archive is read, in whatever order they come.
+type pythonPackage struct {
+	metadataName    string
+	metadata        []byte
+	entryPoints     []byte
+	pyproject       []byte
+	metadataFailure error
+}
+
+// visit keeps the entry name when it is a metadata entry: the METADATA
+// and entry_points.txt of the top-level .dist-info of a wheel, or the
+// PKG-INFO, egg-info entry_points.txt and pyproject.toml of an sdist's
+// top-level directory.
+func (p *pythonPackage) visit(name string, open func() (io.ReadCloser, error)) {
+	dir, file := path.Split(name)
+	depth := strings.Count(dir, "/")
+	var dst *[]byte
+	switch {
+	case depth == 1 && strings.HasSuffix(dir, ".dist-info/") && file == "METADATA",
+		depth == 1 && file == "PKG-INFO":
+		if p.metadataName != "" {
+			return
+		}
+		p.metadataName = name
+		dst = &p.metadata
+	case file == "entry_points.txt" && (depth == 1 && strings.HasSuffix(dir, ".dist-info/") ||
+		(depth == 2 || depth == 3 && strings.Contains(dir, "/src/")) && strings.HasSuffix(dir, ".egg-info/")):
+		dst = &p.entryPoints
+	case depth == 1 && file == "pyproject.toml":
+		dst = &p.pyproject
+	default:
+		return
+	}
+	if *dst != nil {
+		return
+	}
+	rc, err := open()
+	if err == nil {
+		*dst, err = io.ReadAll(io.LimitReader(rc, maxAppMetadataBytes))
+		rc.Close()
+	}
+	if err != nil && dst == &p.metadata {
+		p.metadataFailure = err
+	}
+}
+
+// result parses the collected entries, or returns nil when the archive
+// has no Python metadata.
+func (p *pythonPackage) result() *pythonMetadata {
+	if p.metadataName == "" {
+		return nil
+	}
+	m := &pythonMetadata{source: p.metadataName, err: p.metadataFailure}
+	if m.err != nil {
+		return m
+	}
+	m.parseMetadata(p.metadata)
+	switch {
+	case p.entryPoints != nil:
+		m.entryPoints = parseEntryPoints(p.entryPoints, "")
+	case p.pyproject != nil:
+		m.entryPoints = parseEntryPoints(p.pyproject, "project.")
+	}
+	if m.name == "" && m.version == "" {
+		m.err = fmt.Errorf("no Name or Version field")
+	}
+	return m
+}
+
+// parseMetadata reads the core metadata fields, which are email-style
+// headers ending at the first blank line, where the description starts.
+func (m *pythonMetadata) parseMetadata(data []byte) {
+	var key, value string
+	flush := func() {
+		switch strings.ToLower(key) {
+		case "name":
+			m.name = value
+		case "version":
+			m.version = value
+		case "summary":
+			m.summary = value
+		case "license-expression":
+			m.license = value
+		case "license":
+			// Older metadata puts the whole license text here; keep it
+			// only when it is a name.
+			if m.license == "" && !strings.Contains(value, "\n") && len(value) <= 80 {
+				m.license = value
+			}
+		case "requires-python":
+			m.requiresPython = value
+		case "requires-dist":
+			m.requires = append(m.requires, value)
+		}
+		key, value = "", ""
+	}
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if key != "" {
+				value += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		flush()
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(k), strings.TrimSpace(v)
+	}
+	flush()
+}
+
+// parseEntryPoints reads the entry points of an entry_points.txt, or of
+// the [<prefix>scripts], [<prefix>gui-scripts] and
+// [<prefix>entry-points.<group>] tables of a pyproject.toml when prefix is
+// "project.".
+func parseEntryPoints(data []byte, prefix string) []string {
+	var points []string
+	group := ""
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			group = entryPointGroup(strings.Trim(line, "[] "), prefix)
+			continue
+		}
+		if group == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		k, v = strings.Trim(strings.TrimSpace(k), `"'`), strings.Trim(strings.TrimSpace(v), `"'`)
+		points = append(points, fmt.Sprintf("%s: %s = %s", group, k, v))
+	}
+	return points
+}
+
+// entryPointGroup maps a section name to its entry point group, or "" for
+// sections that hold none.
+func entryPointGroup(section, prefix string) string {
+	if prefix == "" {
+		return section
+	}
+	switch section {
+	case prefix + "scripts":
+		return "console_scripts"
+	case prefix + "gui-scripts":
+		return "gui_scripts"
+	}
+	if g, ok := strings.CutPrefix(section, prefix+"entry-points."); ok {
+		return strings.Trim(g, `"'`)
+	}
+	return ""
+}
+
+// write appends the Python package section.
+func (m *pythonMetadata) write(sb *strings.Builder, profile OutputProfile) {
+	fmt.Fprintf(sb, "\nPython package (%s):\n", m.source)
+	if m.err != nil {
+		fmt.Fprintf(sb, "  - Unreadable: %v\n", m.err)
+		return
+	}
+	for _, field := range []struct{ label, value string }{
+		{"Name", m.name},
+		{"Version", m.version},
+		{"Summary", m.summary},
+		{"License", m.license},
+		{"Requires Python", m.requiresPython},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(sb, "  - %s: %s\n", field.label, field.value)
+		}
+	}
+	for i, r := range m.requires {
+		if i == maxPythonRequirements {
+			fmt.Fprintf(sb, "  - Requires: %s\n", overflowMarker(profile, len(m.requires)-i, false))
+			break
+		}
+		fmt.Fprintf(sb, "  - Requires: %s\n", r)
+	}
+	for i, ep := range m.entryPoints {
+		if i == maxPythonEntryPoints {
+			fmt.Fprintf(sb, "  - Entry point: %s\n", overflowMarker(profile, len(m.entryPoints)-i, false))
+			break
+		}
+		fmt.Fprintf(sb, "  - Entry point: %s\n", ep)
+	}
+}
+
+// addFacts records the requirement and entry point counts in facts.
+func (m *pythonMetadata) addFacts(facts *Facts) *Facts {
+	if m == nil || m.err != nil {
+		return facts
+	}
+	if facts == nil {
+		facts = &Facts{}
+	}
+	facts.count("requirements", len(m.requires))
+	facts.count("entry_points", len(m.entryPoints))
+	return facts
+}
@@ -0,0 +1,163 @@
+package explorer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	// bombRatio is the expansion, uncompressed over compressed bytes, from
+	// which an entry or a whole archive is reported as a potential archive
+	// bomb.
+	bombRatio = 200
+	// bombMinBytes is the expanded size below which no expansion is
+	// reported, however high the ratio.
+	bombMinBytes = 8 << 20
+	// maxSafetyFindings is how many findings of one kind are listed.
+	maxSafetyFindings = 10
+	// maxLinkTarget bounds the symlink target read from a ZIP entry.
+	maxLinkTarget = 4096
+)
+
+// Kinds of unsafe archive entries.
+const (
+	unsafeTraversal   = "Path traversal"
+	unsafeAbsolute    = "Absolute path"
+	unsafeLink        = "Link escaping the root"
+	unsafeCompression = "Extreme compression"
+)
+
+// ErrUnsafeArchive is returned by ingestion paths that reject archives
+// with unsafe entries (see WithRuntimeRejectUnsafeArchives).
+var ErrUnsafeArchive = errors.New("archive has unsafe entries")
+
+// archiveSafety collects the entries of an archive that would be unsafe to
+// extract: paths escaping the extraction root, links pointing outside it,
+// and expansions typical of archive bombs.
+type archiveSafety struct {
+	findings []string
+	perKind  map[string]int
+	// unsafe counts every unsafe entry, listed or not.
+	unsafe int
+}
+
+func (s *archiveSafety) add(kind, detail string) {
+	if s.perKind == nil {
+		s.perKind = make(map[string]int)
+	}
+	s.unsafe++
+	s.perKind[kind]++
+	if s.perKind[kind] <= maxSafetyFindings {
+		s.findings = append(s.findings, kind+": "+detail)
+	}
+}
+
+// checkPath reports a name that is absolute or climbs out of the
+// extraction root.
+func (s *archiveSafety) checkPath(name string) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	switch {
+	case isAbsoluteEntry(name):
+		s.add(unsafeAbsolute, name)
+	case escapesRoot(path.Clean(name)):
+		s.add(unsafeTraversal, name)
+	}
+}
+
+// checkLink reports a symlink or hard link whose target lies outside the
+// extraction root. Symlink targets are relative to the link's directory,
+// hard link targets to the root.
+func (s *archiveSafety) checkLink(name, target string, hard bool) {
+	target = strings.ReplaceAll(target, `\`, "/")
+	resolved := path.Clean(target)
+	if !hard {
+		resolved = path.Join(path.Dir(strings.ReplaceAll(name, `\`, "/")), target)
+	}
+	if isAbsoluteEntry(target) || escapesRoot(resolved) {
+		s.add(unsafeLink, name+" -> "+target)
+	}
+}
+
+// checkExpansion reports an entry that expands from compressed to
+// uncompressed bytes like an archive bomb.
+func (s *archiveSafety) checkExpansion(name string, compressed, uncompressed int64) {
+	if uncompressed < bombMinBytes || compressed <= 0 || uncompressed/compressed < bombRatio {
+		return
+	}
+	s.add(unsafeCompression, fmt.Sprintf("%s expands %s to %s (%d:1)",
+		name, formatSize(uint64(compressed)), formatSize(uint64(uncompressed)), uncompressed/compressed))
+}
+
+// checkTotal reports the whole archive when it expands like an archive
+// bomb and no single entry was reported for it.
+func (s *archiveSafety) checkTotal(archiveSize, expanded int64) {
+	if s.perKind[unsafeCompression] == 0 {
+		s.checkExpansion("the archive", archiveSize, expanded)
+	}
+}
+
+// readLinkTarget returns the target of a ZIP symlink entry.
+func readLinkTarget(open func() (io.ReadCloser, error)) string {
+	rc, err := open()
+	if err != nil {
+		return ""
+	}
+	defer rc.Close()
+	data, _ := io.ReadAll(io.LimitReader(rc, maxLinkTarget))
+	return string(data)
+}
+
+// addFacts records the unsafe entry count in facts.
+func (s *archiveSafety) addFacts(facts *Facts) *Facts {
+	if facts == nil {
+		facts = &Facts{}
+	}
+	facts.count("unsafe_entries", s.unsafe)
+	return facts
+}
+
+// write appends the safety section: the expanded size estimate and the
+// unsafe entries. partial says the listing stopped early, so the expanded
+// size is a lower bound.
+func (s *archiveSafety) write(sb *strings.Builder, archiveSize, expanded int64, partial bool) {
+	sb.WriteString("\nSafety:\n")
+	estimate := formatSize(uint64(expanded))
+	if partial {
+		estimate = "at least " + estimate
+	}
+	if archiveSize > 0 {
+		fmt.Fprintf(sb, "  - Expanded size: %s, %.1fx the archive\n", estimate, float64(expanded)/float64(archiveSize))
+	} else {
+		fmt.Fprintf(sb, "  - Expanded size: %s\n", estimate)
+	}
+	for _, f := range s.findings {
+		fmt.Fprintf(sb, "  - %s\n", f)
+	}
+	for _, kind := range []string{unsafeTraversal, unsafeAbsolute, unsafeLink, unsafeCompression} {
+		if more := s.perKind[kind] - maxSafetyFindings; more > 0 {
+			fmt.Fprintf(sb, "  - %s: %s\n", kind, overflowMarker(OutputProfileEnhancement, more, false))
+		}
+	}
+	if s.unsafe == 0 {
+		sb.WriteString("  - No unsafe entries found\n")
+	}
+}
+
+// isAbsoluteEntry reports whether a slash-separated entry name is absolute
+// on Unix or Windows.
+func isAbsoluteEntry(name string) bool {
+	if strings.HasPrefix(name, "/") {
+		return true
+	}
+	return len(name) >= 2 && name[1] == ':' &&
+		(name[0] >= 'a' && name[0] <= 'z' || name[0] >= 'A' && name[0] <= 'Z')
+}
+
+// escapesRoot reports whether a cleaned, relative path climbs above its
+// root.
+func escapesRoot(cleaned string) bool {
+	return cleaned == ".." || strings.HasPrefix(cleaned, "../")
+}
@@ -6,7 +6,11 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -24,7 +28,7 @@ func TestArchiveExplorer_CanHandle_Extensions(t *testing.T) {
 		content  []byte
 		expected bool
 	}{
-		// All 28 archive extensions.
+		// All 29 archive extensions.
 		{name: "zip", path: "archive.zip", expected: true},
 		{name: "tar", path: "archive.tar", expected: true},
 		{name: "gz", path: "archive.gz", expected: true},
@@ -53,6 +57,7 @@ func TestArchiveExplorer_CanHandle_Extensions(t *testing.T) {
 		{name: "crx", path: "ext.crx", expected: true},
 		{name: "xpi", path: "ext.xpi", expected: true},
 		{name: "vsix", path: "ext.vsix", expected: true},
+		{name: "whl", path: "pkg-1.0-py3-none-any.whl", expected: true},
 
 		// Double extensions.
 		{name: "tar.gz", path: "archive.tar.gz", expected: true},
@@ -280,6 +285,213 @@ func TestArchiveExplorer_Explore_JAR_Manifest(t *testing.T) {
 	require.Contains(t, s, "Main-Class: com.example.Main")
 }
 
+func TestArchiveExplorer_Explore_APKManifest(t *testing.T) {
+	t.Parallel()
+
+	zipData := createTestZIP(t, map[string][]byte{
+		"AndroidManifest.xml": createTestAXML(),
+		"classes.dex":         []byte("dex\n035\x00"),
+	})
+	result, err := (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{Path: "app.apk", Content: zipData})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Format: apk")
+	require.Contains(t, s, "\nApp metadata (AndroidManifest.xml):\n")
+	require.Contains(t, s, "  - Package: com.example.app\n")
+	require.Contains(t, s, "  - Version: 1.2.3 (build 42)\n")
+	require.Contains(t, s, "  - Minimum OS: Android 7.0 (API 24)\n")
+	require.Contains(t, s, "  - Target OS: Android 14 (API 34)\n")
+	require.Contains(t, s, "  - Permission: android.permission.CAMERA\n")
+	require.Contains(t, s, "  - Permission: android.permission.INTERNET\n")
+	require.Equal(t, int64(2), result.Facts.Counts["permissions"])
+
+	// A text manifest, as in sources, reads the same.
+	text := `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="org.example.text"
+    android:versionCode="7" android:versionName="0.7">
+  <uses-sdk android:minSdkVersion="21"/>
+  <uses-permission android:name="android.permission.INTERNET"/>
+  <application android:label="Text App"/>
+</manifest>`
+	zipData = createTestZIP(t, map[string][]byte{"AndroidManifest.xml": []byte(text)})
+	result, err = (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{Path: "app.apk", Content: zipData})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "  - Package: org.example.text\n")
+	require.Contains(t, result.Summary, "  - Name: Text App\n")
+	require.Contains(t, result.Summary, "  - Version: 0.7 (build 7)\n")
+	require.Contains(t, result.Summary, "  - Minimum OS: Android 5.0 (API 21)\n")
+
+	zipData = createTestZIP(t, map[string][]byte{"AndroidManifest.xml": {0xff, 0xff}})
+	result, err = (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{Path: "app.apk", Content: zipData})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "  - Unreadable: not an Android binary XML document\n")
+}
+
+func TestArchiveExplorer_Explore_IPAInfoPlist(t *testing.T) {
+	t.Parallel()
+
+	plist := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>CFBundleIdentifier</key><string>com.example.ios</string>
+  <key>CFBundleDisplayName</key><string>Example</string>
+  <key>CFBundleShortVersionString</key><string>2.0</string>
+  <key>CFBundleVersion</key><string>200</string>
+  <key>MinimumOSVersion</key><string>15.0</string>
+  <key>NSCameraUsageDescription</key><string>Scan codes</string>
+  <key>NSLocationWhenInUseUsageDescription</key><string>Find stores</string>
+  <key>UIRequiredDeviceCapabilities</key><array><string>arm64</string></array>
+  <key>LSRequiresIPhoneOS</key><true/>
+</dict>
+</plist>`
+	zipData := createTestZIP(t, map[string][]byte{
+		"Payload/Example.app/Info.plist":                        []byte(plist),
+		"Payload/Example.app/Frameworks/X.framework/Info.plist": []byte("<plist/>"),
+		"Payload/Example.app/Example":                           {0xcf, 0xfa, 0xed, 0xfe},
+	})
+	result, err := (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{Path: "Example.ipa", Content: zipData})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "\nApp metadata (Payload/Example.app/Info.plist):\n")
+	require.Contains(t, s, "  - Package: com.example.ios\n")
+	require.Contains(t, s, "  - Name: Example\n")
+	require.Contains(t, s, "  - Version: 2.0 (build 200)\n")
+	require.Contains(t, s, "  - Minimum OS: iOS 15.0\n")
+	require.Contains(t, s, "  - Permission: NSCameraUsageDescription\n")
+	require.Contains(t, s, "  - Permission: NSLocationWhenInUseUsageDescription\n")
+	require.Equal(t, int64(2), result.Facts.Counts["permissions"])
+}
+
+func TestArchiveExplorer_Explore_PythonWheel(t *testing.T) {
+	t.Parallel()
+
+	metadata := `Metadata-Version: 2.3
+Name: example-pkg
+Version: 1.4.0
+Summary: An example package
+License-Expression: MIT
+Requires-Python: >=3.9
+Requires-Dist: requests>=2.28
+Requires-Dist: rich; extra == "cli"
+
+# example-pkg
+
+Requires-Dist: not-a-field
+`
+	zipData := createTestZIP(t, map[string][]byte{
+		"example_pkg/__init__.py":                      []byte("\n"),
+		"example_pkg-1.4.0.dist-info/METADATA":         []byte(metadata),
+		"example_pkg-1.4.0.dist-info/WHEEL":            []byte("Wheel-Version: 1.0\n"),
+		"example_pkg-1.4.0.dist-info/entry_points.txt": []byte("[console_scripts]\nexample = example_pkg.cli:main\n\n[pytest11]\nexample = example_pkg.plugin\n"),
+	})
+	result, err := (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{Path: "example_pkg-1.4.0-py3-none-any.whl", Content: zipData})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Format: whl")
+	require.Contains(t, s, "\nPython package (example_pkg-1.4.0.dist-info/METADATA):\n")
+	require.Contains(t, s, "  - Name: example-pkg\n")
+	require.Contains(t, s, "  - Version: 1.4.0\n")
+	require.Contains(t, s, "  - Summary: An example package\n")
+	require.Contains(t, s, "  - License: MIT\n")
+	require.Contains(t, s, "  - Requires Python: >=3.9\n")
+	require.Contains(t, s, "  - Requires: requests>=2.28\n")
+	require.Contains(t, s, "  - Requires: rich; extra == \"cli\"\n")
+	require.NotContains(t, s, "not-a-field")
+	require.Contains(t, s, "  - Entry point: console_scripts: example = example_pkg.cli:main\n")
+	require.Contains(t, s, "  - Entry point: pytest11: example = example_pkg.plugin\n")
+	require.Equal(t, int64(2), result.Facts.Counts["requirements"])
+	require.Equal(t, int64(2), result.Facts.Counts["entry_points"])
+}
+
+func TestArchiveExplorer_Explore_PythonSdist(t *testing.T) {
+	t.Parallel()
+
+	pkgInfo := "Metadata-Version: 2.1\nName: example-pkg\nVersion: 1.4.0\nRequires-Dist: click\n"
+	pyproject := `[project]
+name = "example-pkg"
+
+[project.scripts]
+example = "example_pkg.cli:main"
+
+[project.entry-points."example.plugins"]
+csv = "example_pkg.csv:Plugin"
+
+[tool.ruff]
+line-length = 100
+`
+	tarData := createTestTAR(t, map[string][]byte{
+		"example_pkg-1.4.0/PKG-INFO":               []byte(pkgInfo),
+		"example_pkg-1.4.0/pyproject.toml":         []byte(pyproject),
+		"example_pkg-1.4.0/src/example_pkg/cli.py": []byte("def main(): pass\n"),
+		"example_pkg-1.4.0/tests/fixture/PKG-INFO": []byte("Name: other\n"),
+	})
+	var gzBuf bytes.Buffer
+	gw := gzip.NewWriter(&gzBuf)
+	_, err := gw.Write(tarData)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	result, err := (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{Path: "example_pkg-1.4.0.tar.gz", Content: gzBuf.Bytes()})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "\nPython package (example_pkg-1.4.0/PKG-INFO):\n")
+	require.Contains(t, s, "  - Name: example-pkg\n")
+	require.Contains(t, s, "  - Requires: click\n")
+	require.Contains(t, s, "  - Entry point: console_scripts: example = example_pkg.cli:main\n")
+	require.Contains(t, s, "  - Entry point: example.plugins: csv = example_pkg.csv:Plugin\n")
+	require.NotContains(t, s, "line-length")
+	require.Equal(t, int64(1), result.Facts.Counts["requirements"])
+
+	// A plain tarball has no Python section.
+	result, err = (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{
+		Path:    "plain.tar",
+		Content: createTestTAR(t, map[string][]byte{"a/README.md": []byte("# a\n")}),
+	})
+	require.NoError(t, err)
+	require.NotContains(t, result.Summary, "Python package")
+}
+
+func TestParseBinaryPlist(t *testing.T) {
+	t.Parallel()
+
+	// {"CFBundleIdentifier": "com.example.ios", "MinimumOSVersion": "16.4"}
+	var buf bytes.Buffer
+	buf.WriteString("bplist00")
+	var offsets []int
+	object := func(b ...byte) {
+		offsets = append(offsets, buf.Len())
+		buf.Write(b)
+	}
+	str := func(s string) []byte {
+		return append([]byte{0x5f, 0x10, byte(len(s))}, s...)
+	}
+	object(0xd2, 1, 2, 3, 4)
+	object(str("CFBundleIdentifier")...)
+	object(str("MinimumOSVersion")...)
+	object(str("com.example.ios")...)
+	object(0x54, '1', '6', '.', '4')
+	table := buf.Len()
+	for _, off := range offsets {
+		buf.WriteByte(byte(off))
+	}
+	trailer := make([]byte, 32)
+	trailer[6], trailer[7] = 1, 1
+	trailer[15] = byte(len(offsets))
+	trailer[31] = byte(table)
+	buf.Write(trailer)
+
+	dict, err := parseBinaryPlist(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"CFBundleIdentifier": "com.example.ios", "MinimumOSVersion": "16.4"}, dict)
+
+	_, err = parseBinaryPlist(buf.Bytes()[:buf.Len()-8])
+	require.Error(t, err)
+}
+
 func TestArchiveExplorer_Explore_Deb(t *testing.T) {
 	t.Parallel()
 
@@ -300,6 +512,175 @@ func TestArchiveExplorer_Explore_Deb(t *testing.T) {
 	require.Contains(t, s, "debian-binary")
 }
 
+func TestArchiveExplorer_Explore_NestedArchives(t *testing.T) {
+	t.Parallel()
+
+	deepest := createTestZIP(t, map[string][]byte{"a.txt": []byte("a\n")})
+	inner := createTestZIP(t, map[string][]byte{
+		"lib/deepest.jar": deepest,
+		"Inner.class":     {0xCA, 0xFE, 0xBA, 0xBE},
+	})
+	wheel := createTestZIP(t, map[string][]byte{
+		"pkg/__init__.py":              []byte("\n"),
+		"pkg/core.py":                  []byte("def f(): pass\n"),
+		"pkg/vendor/inner.zip":         inner,
+		"pkg-1.0.dist-info/METADATA":   []byte("Name: pkg\n"),
+		"pkg-1.0.dist-info/RECORD":     []byte("\n"),
+		"pkg-1.0.dist-info/top_level":  []byte("pkg\n"),
+		"pkg-1.0.dist-info/WHEEL":      []byte("Wheel-Version: 1.0\n"),
+		"pkg-1.0.dist-info/entry.json": []byte("{}\n"),
+	})
+	tarData := createTestTAR(t, map[string][]byte{
+		"dist/pkg-1.0-py3-none-any.whl": wheel,
+		"dist/big.tar":                  bytes.Repeat([]byte{0}, 4096),
+		"dist/docs.7z":                  []byte("7z"),
+		"README.md":                     []byte("# dist\n"),
+	})
+
+	explorer := &ArchiveExplorer{
+		formatterProfile: OutputProfileEnhancement,
+		nesting:          ArchiveNesting{MaxEntryBytes: 2048},
+	}
+	result, err := explorer.Explore(context.Background(), ExploreInput{Path: "dist.tar", Content: tarData})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "\nNested archives:\n")
+	require.Contains(t, s, "  - dist/pkg-1.0-py3-none-any.whl (whl, ")
+	require.Contains(t, s, "): 8 files, ")
+	require.Contains(t, s, "  - dist/pkg-1.0-py3-none-any.whl > pkg/vendor/inner.zip (zip, ")
+	require.Contains(t, s, "): 2 files, ")
+	require.Contains(t, s, "  - dist/pkg-1.0-py3-none-any.whl > pkg/vendor/inner.zip > lib/deepest.jar (jar, ")
+	require.Contains(t, s, "not opened, nesting depth limit 2 reached")
+	require.Contains(t, s, "  - dist/big.tar (tar, 4.0 KB): not opened, larger than the 2.0 KB entry limit")
+	require.Contains(t, s, "  - dist/docs.7z (7z, 2 bytes): not opened, format not opened when nested")
+	require.Equal(t, int64(4), result.Facts.Counts["files"])
+
+	// The parity profile and a negative depth leave nested archives out.
+	for _, e := range []*ArchiveExplorer{{}, {formatterProfile: OutputProfileEnhancement, nesting: ArchiveNesting{MaxDepth: -1}}} {
+		result, err := e.Explore(context.Background(), ExploreInput{Path: "dist.tar", Content: tarData})
+		require.NoError(t, err)
+		require.NotContains(t, result.Summary, "Nested archives:")
+	}
+}
+
+func TestArchiveExplorer_Explore_NestedDebMembers(t *testing.T) {
+	t.Parallel()
+
+	control := createTestTAR(t, map[string][]byte{
+		"./control":   []byte("Package: demo\n"),
+		"./md5sums":   []byte("\n"),
+		"./postinst":  []byte("#!/bin/sh\n"),
+		"./conffiles": []byte("\n"),
+	})
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, err := gw.Write(control)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	var deb bytes.Buffer
+	deb.WriteString("!<arch>\n")
+	writeArMember(&deb, "debian-binary", []byte("2.0\n"))
+	writeArMember(&deb, "control.tar.gz", gz.Bytes())
+
+	explorer := &ArchiveExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := explorer.Explore(context.Background(), ExploreInput{Path: "demo.deb", Content: deb.Bytes()})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "  - control.tar.gz (tar.gz, ")
+	require.Contains(t, result.Summary, "): 4 files, ")
+	require.Equal(t, int64(2), result.Facts.Counts["members"])
+}
+
+func TestArchiveExplorer_Explore_Safety(t *testing.T) {
+	t.Parallel()
+
+	zipData := createTestZIP(t, map[string][]byte{
+		"../../etc/cron.d/job": []byte("* * * * * root sh\n"),
+		"/tmp/abs.txt":         []byte("abs\n"),
+		"ok/file.txt":          []byte("ok\n"),
+		"bomb.bin":             make([]byte, 16<<20),
+	})
+	explorer := &ArchiveExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := explorer.Explore(context.Background(), ExploreInput{Path: "evil.zip", Content: zipData})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "\nSafety:\n")
+	require.Contains(t, s, "  - Expanded size: 16.0 MB, ")
+	require.Contains(t, s, "  - Path traversal: ../../etc/cron.d/job\n")
+	require.Contains(t, s, "  - Absolute path: /tmp/abs.txt\n")
+	require.Contains(t, s, "  - Extreme compression: bomb.bin expands ")
+	require.NotContains(t, s, "ok/file.txt\n  - ")
+	require.Equal(t, int64(3), result.Facts.Counts["unsafe_entries"])
+
+	// The parity profile counts unsafe entries without the section.
+	result, err = (&ArchiveExplorer{}).Explore(context.Background(), ExploreInput{Path: "evil.zip", Content: zipData})
+	require.NoError(t, err)
+	require.NotContains(t, result.Summary, "Safety:")
+	require.Equal(t, int64(3), result.Facts.Counts["unsafe_entries"])
+
+	// Unsafe entries of a nested archive count against the outer one.
+	inner := createTestZIP(t, map[string][]byte{"../x.txt": []byte("x\n")})
+	tarData := createTestTAR(t, map[string][]byte{"lib/inner.zip": inner})
+	result, err = explorer.Explore(context.Background(), ExploreInput{Path: "outer.tar", Content: tarData})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "  - No unsafe entries found\n")
+	require.Contains(t, result.Summary, ", 1 unsafe entries")
+	require.Equal(t, int64(1), result.Facts.Counts["unsafe_entries"])
+}
+
+func TestArchiveExplorer_Explore_SafetyTARLinks(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "app/current", Typeflag: tar.TypeSymlink, Linkname: "releases/v2"},
+		{Name: "app/escape", Typeflag: tar.TypeSymlink, Linkname: "../../home/user/.ssh"},
+		{Name: "app/passwd", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"},
+		{Name: "app/hard", Typeflag: tar.TypeLink, Linkname: "../outside"},
+		{Name: "app/hard-ok", Typeflag: tar.TypeLink, Linkname: "app/current"},
+	} {
+		hdr.Mode = 0o777
+		require.NoError(t, tw.WriteHeader(hdr))
+	}
+	require.NoError(t, tw.Close())
+
+	explorer := &ArchiveExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := explorer.Explore(context.Background(), ExploreInput{Path: "links.tar", Content: buf.Bytes()})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "  - Link escaping the root: app/escape -> ../../home/user/.ssh\n")
+	require.Contains(t, s, "  - Link escaping the root: app/passwd -> /etc/passwd\n")
+	require.Contains(t, s, "  - Link escaping the root: app/hard -> ../outside\n")
+	require.NotContains(t, s, "app/current ->")
+	require.NotContains(t, s, "app/hard-ok ->")
+	require.Equal(t, int64(3), result.Facts.Counts["unsafe_entries"])
+
+	clean := createTestTAR(t, map[string][]byte{"a/b.txt": []byte("b\n")})
+	result, err = explorer.Explore(context.Background(), ExploreInput{Path: "clean.tar", Content: clean})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "  - No unsafe entries found\n")
+	require.Equal(t, int64(0), result.Facts.Counts["unsafe_entries"])
+}
+
+func TestRuntimeAdapter_Explore_RejectUnsafeArchives(t *testing.T) {
+	t.Parallel()
+
+	zipData := createTestZIP(t, map[string][]byte{"../escape.txt": []byte("x\n")})
+
+	_, _, _, err := NewRuntimeAdapter(WithRuntimeRejectUnsafeArchives(true)).
+		Explore(context.Background(), "session", "evil.zip", zipData)
+	require.ErrorIs(t, err, ErrUnsafeArchive)
+
+	summary, _, _, err := NewRuntimeAdapter().
+		Explore(context.Background(), "session", "evil.zip", zipData)
+	require.NoError(t, err)
+	require.NotEmpty(t, summary)
+}
+
 func TestArchiveExplorer_Explore_RPM(t *testing.T) {
 	t.Parallel()
 
@@ -359,6 +740,93 @@ func TestArchiveExplorer_Explore_Opaque(t *testing.T) {
 	}
 }
 
+func TestArchiveExplorer_Explore_7zListed(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("bsdtar"); err != nil {
+		t.Skip("bsdtar not available")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "sub", "notes file.txt"), bytes.Repeat([]byte("x"), 2048), 0o644))
+	archive := filepath.Join(t.TempDir(), "bundle.7z")
+	cmd := exec.Command("bsdtar", "--format", "7zip", "-cf", archive, "src")
+	cmd.Dir = dir
+	require.NoError(t, cmd.Run())
+	content, err := os.ReadFile(archive)
+	require.NoError(t, err)
+
+	explorer := &ArchiveExplorer{}
+	result, err := explorer.Explore(t.Context(), ExploreInput{Path: "bundle.7z", Content: content})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Format: 7z")
+	require.Contains(t, s, "Files: 2, Directories: 2")
+	require.Contains(t, s, "  - src/\n")
+	require.Contains(t, s, "  - .go: 1\n")
+	require.Contains(t, s, "  - src/sub/notes file.txt (2.0 KB)\n")
+	require.NotContains(t, s, "cannot be listed")
+}
+
+func TestParse7zListing(t *testing.T) {
+	t.Parallel()
+
+	output := `7-Zip [64] 17.05
+
+Listing archive: bundle.7z
+
+--
+Path = bundle.7z
+Type = 7z
+Physical Size = 300
+
+----------
+Path = src/main.go
+Folder = -
+Size = 13
+Packed Size = 9
+Attributes = A_ -rw-r--r--
+Encrypted = -
+
+Path = src
+Folder = +
+Size = 0
+Attributes = D_ drwxr-xr-x
+Encrypted = -
+
+Path = secret.txt
+Folder = -
+Size = 42
+Attributes = A
+Encrypted = +
+`
+	entries, encrypted := parse7zListing(output)
+	require.True(t, encrypted)
+	require.Equal(t, []listedEntry{
+		{name: "src/main.go", size: 13},
+		{name: "src", dir: true},
+		{name: "secret.txt", size: 42},
+	}, entries)
+}
+
+func TestParseBsdtarListing(t *testing.T) {
+	t.Parallel()
+
+	output := "-rw-r--r--  0 0      0          22 Oct 15 22:59 src/sub/my file.txt\n" +
+		"drwxr-xr-x  0 0      0           0 Oct 15  2023 src/\n" +
+		"lrwxrwxrwx  0 alice  staff       0 Oct 15 22:59 link -> src/sub\n" +
+		"garbage\n"
+	entries, encrypted := parseBsdtarListing(output)
+	require.False(t, encrypted)
+	require.Equal(t, []listedEntry{
+		{name: "src/sub/my file.txt", size: 22},
+		{name: "src/", dir: true},
+		{name: "link"},
+	}, entries)
+}
+
 func TestArchiveExplorer_Explore_ZIP_Encrypted(t *testing.T) {
 	t.Parallel()
 
@@ -600,6 +1068,81 @@ func createTestTAR(t *testing.T, files map[string][]byte) []byte {
 	return buf.Bytes()
 }
 
+// createTestAXML compiles a small AndroidManifest.xml to Android binary
+// XML. The versionCode attribute name is left empty in the string pool, as
+// shrinkers do, and resolved through the resource map.
+func createTestAXML() []byte {
+	strs := []string{
+		"", "manifest", "package", "com.example.app", "versionName", "1.2.3",
+		"uses-sdk", "minSdkVersion", "targetSdkVersion", "uses-permission", "name",
+		"android.permission.CAMERA", "android.permission.INTERNET",
+	}
+	le := binary.LittleEndian
+	chunk := func(typ uint16, hdr int, body []byte) []byte {
+		b := make([]byte, 8, 8+len(body))
+		le.PutUint16(b, typ)
+		le.PutUint16(b[2:], uint16(hdr))
+		le.PutUint32(b[4:], uint32(8+len(body)))
+		return append(b, body...)
+	}
+
+	var pool []byte
+	pool = le.AppendUint32(pool, uint32(len(strs)))
+	pool = le.AppendUint32(pool, 0)
+	pool = le.AppendUint32(pool, 0)
+	pool = le.AppendUint32(pool, uint32(28+4*len(strs)))
+	pool = le.AppendUint32(pool, 0)
+	var data []byte
+	for _, s := range strs {
+		pool = le.AppendUint32(pool, uint32(len(data)))
+		data = le.AppendUint16(data, uint16(len(s)))
+		for _, r := range s {
+			data = le.AppendUint16(data, uint16(r))
+		}
+		data = le.AppendUint16(data, 0)
+	}
+	doc := chunk(0x0001, 28, append(pool, data...))
+	doc = append(doc, chunk(0x0180, 8, le.AppendUint32(nil, 0x0101021b))...)
+
+	type attr struct {
+		name, str uint32
+		typ       byte
+		val       uint32
+	}
+	element := func(name uint32, attrs ...attr) []byte {
+		var b []byte
+		b = le.AppendUint32(b, 1)
+		b = le.AppendUint32(b, 0xffffffff)
+		b = le.AppendUint32(b, 0xffffffff)
+		b = le.AppendUint32(b, name)
+		b = le.AppendUint16(b, 20)
+		b = le.AppendUint16(b, 20)
+		b = le.AppendUint16(b, uint16(len(attrs)))
+		b = append(b, 0, 0, 0, 0, 0, 0)
+		for _, a := range attrs {
+			b = le.AppendUint32(b, 0xffffffff)
+			b = le.AppendUint32(b, a.name)
+			b = le.AppendUint32(b, a.str)
+			b = append(b, 8, 0, 0, a.typ)
+			b = le.AppendUint32(b, a.val)
+		}
+		return chunk(0x0102, 16, b)
+	}
+	const noString = 0xffffffff
+	doc = append(doc, element(1,
+		attr{name: 2, str: 3, typ: 0x03, val: 3},
+		attr{name: 0, str: noString, typ: 0x10, val: 42},
+		attr{name: 4, str: 5, typ: 0x03, val: 5},
+	)...)
+	doc = append(doc, element(6,
+		attr{name: 7, str: noString, typ: 0x10, val: 24},
+		attr{name: 8, str: noString, typ: 0x10, val: 34},
+	)...)
+	doc = append(doc, element(9, attr{name: 10, str: 11, typ: 0x03, val: 11})...)
+	doc = append(doc, element(9, attr{name: 10, str: 12, typ: 0x03, val: 12})...)
+	return chunk(0x0003, 8, doc)
+}
+
 // createTestDeb creates a synthetic .deb file (ar archive format) with
 // standard debian members.
 func createTestDeb(t *testing.T) []byte {
@@ -660,3 +1203,102 @@ func createTestRPM(t *testing.T) []byte {
 
 	return data
 }
+
+func TestArchiveExplorer_ExploreStream_MatchesExplore(t *testing.T) {
+	t.Parallel()
+
+	tarData := createTestTAR(t, map[string][]byte{
+		"app/main.py":   []byte("print('hello')\n"),
+		"app/utils.py":  []byte("def helper(): pass\n"),
+		"app/README.md": []byte("# App\n"),
+	})
+	var gzBuf bytes.Buffer
+	gw := gzip.NewWriter(&gzBuf)
+	_, err := gw.Write(tarData)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	tests := []struct {
+		name    string
+		path    string
+		content []byte
+	}{
+		{name: "zip", path: "archive.zip", content: createTestZIP(t, map[string][]byte{"a.go": []byte("package a\n")})},
+		{name: "tar", path: "archive.tar", content: tarData},
+		{name: "tar.gz", path: "archive.tar.gz", content: gzBuf.Bytes()},
+		{name: "gzip with tar", path: "archive.gz", content: gzBuf.Bytes()},
+		{name: "deb", path: "package.deb", content: createTestDeb(t)},
+		{name: "rpm", path: "package.rpm", content: createTestRPM(t)},
+	}
+
+	explorer := &ArchiveExplorer{formatterProfile: OutputProfileEnhancement}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want, err := explorer.Explore(t.Context(), ExploreInput{Path: tt.path, Content: tt.content})
+			require.NoError(t, err)
+			got, err := explorer.ExploreStream(t.Context(), tt.path, bytes.NewReader(tt.content), int64(len(tt.content)))
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestArchiveExplorer_ExploreStream_GzipStandalone(t *testing.T) {
+	t.Parallel()
+
+	var gzBuf bytes.Buffer
+	gw := gzip.NewWriter(&gzBuf)
+	_, err := gw.Write(bytes.Repeat([]byte("log line\n"), 10000))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	explorer := &ArchiveExplorer{}
+	result, err := explorer.ExploreStream(t.Context(), "app.log.gz", bytes.NewReader(gzBuf.Bytes()), int64(gzBuf.Len()))
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Format: gzip")
+	require.Contains(t, result.Summary, "Uncompressed size: 90000 bytes")
+}
+
+func TestArchiveExplorer_ExploreStream_Canceled(t *testing.T) {
+	t.Parallel()
+
+	tarData := createTestTAR(t, map[string][]byte{"a.txt": []byte("a")})
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	explorer := &ArchiveExplorer{}
+	_, err := explorer.ExploreStream(ctx, "archive.tar", bytes.NewReader(tarData), int64(len(tarData)))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestArchiveExplorer_TopFiles(t *testing.T) {
+	t.Parallel()
+
+	var top topFiles
+	for i, size := range []int64{3, 9, 1, 9, 7, 2, 8, 5} {
+		top.add(fmt.Sprintf("f%d", i), size)
+	}
+	require.Equal(t, topFiles{
+		{name: "f1", size: 9},
+		{name: "f3", size: 9},
+		{name: "f6", size: 8},
+		{name: "f4", size: 7},
+		{name: "f7", size: 5},
+	}, top)
+}
+
+func TestRegistry_ExploreStream(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	zipData := createTestZIP(t, map[string][]byte{"main.go": []byte("package main\n")})
+	result, err := registry.ExploreStream(t.Context(), "bundle.zip", bytes.NewReader(zipData), int64(len(zipData)))
+	require.NoError(t, err)
+	require.Equal(t, "archive", result.ExplorerUsed)
+	require.Contains(t, result.Summary, "Files: 1")
+
+	text := []byte("# Notes\n")
+	_, err = registry.ExploreStream(t.Context(), "notes.md", bytes.NewReader(text), int64(len(text)))
+	require.ErrorIs(t, err, ErrStreamUnsupported)
+}
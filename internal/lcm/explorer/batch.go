@@ -0,0 +1,111 @@
+package explorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// BatchOptions configures ExploreBatch.
+type BatchOptions struct {
+	// Concurrency bounds the files explored at once. Zero or less uses
+	// GOMAXPROCS.
+	Concurrency int
+	// StopOnError cancels the files not yet explored after the first
+	// failure. By default every file is explored and failures are only
+	// recorded.
+	StopOnError bool
+}
+
+// BatchItem is the outcome of exploring one input of a batch.
+type BatchItem struct {
+	Path     string
+	Result   ExploreResult
+	Err      error
+	Duration time.Duration
+}
+
+// BatchResult holds the outcome of ExploreBatch, one item per input in
+// input order.
+type BatchResult struct {
+	Items []BatchItem
+	// Failed counts the items with an error, including those canceled by
+	// StopOnError.
+	Failed int
+	// Duration is the wall time of the whole batch.
+	Duration time.Duration
+}
+
+// Err joins the errors of the failed items, each prefixed with its path,
+// or returns nil when every item succeeded.
+func (b BatchResult) Err() error {
+	var errs []error
+	for _, item := range b.Items {
+		if item.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Path, item.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ExplorersUsed counts the items explored by each explorer.
+func (b BatchResult) ExplorersUsed() map[string]int {
+	used := make(map[string]int)
+	for _, item := range b.Items {
+		if item.Err == nil {
+			used[item.Result.ExplorerUsed]++
+		}
+	}
+	return used
+}
+
+// ExploreBatch explores inputs concurrently with a bounded worker pool,
+// so directory snapshots and multi-file tool outputs are not explored one
+// file at a time. Each input goes through Explore, so the results match
+// exploring the files one by one. The returned error is only set when ctx
+// ends before the batch completes; per-file failures are in the items.
+func (r *Registry) ExploreBatch(ctx context.Context, inputs []ExploreInput, opts BatchOptions) (BatchResult, error) {
+	start := time.Now()
+	out := BatchResult{Items: make([]BatchItem, len(inputs))}
+	if len(inputs) == 0 {
+		return out, nil
+	}
+
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(min(workers, len(inputs)))
+	for i, input := range inputs {
+		g.Go(func() error {
+			item := &out.Items[i]
+			item.Path = input.Path
+			if err := gctx.Err(); err != nil {
+				item.Err = err
+				return nil
+			}
+			itemStart := time.Now()
+			item.Result, item.Err = r.Explore(gctx, input)
+			item.Duration = time.Since(itemStart)
+			if item.Err != nil && opts.StopOnError {
+				return item.Err
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for _, item := range out.Items {
+		if item.Err != nil {
+			out.Failed++
+		}
+	}
+	out.Duration = time.Since(start)
+	return out, ctx.Err()
+}
@@ -0,0 +1,112 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExploreBatch_MatchesSequential(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	var inputs []ExploreInput
+	for i := range 12 {
+		inputs = append(inputs,
+			ExploreInput{Path: fmt.Sprintf("c%d.json", i), Content: []byte(fmt.Sprintf(`{"id": %d, "tags": ["a", "b"]}`, i))},
+			ExploreInput{Path: fmt.Sprintf("c%d.yaml", i), Content: []byte(fmt.Sprintf("id: %d\nname: item\n", i))},
+			ExploreInput{Path: fmt.Sprintf("n%d.txt", i), Content: []byte("plain notes\nsecond line\n")},
+		)
+	}
+
+	batch, err := r.ExploreBatch(context.Background(), inputs, BatchOptions{Concurrency: 4})
+	require.NoError(t, err)
+	require.NoError(t, batch.Err())
+	require.Zero(t, batch.Failed)
+	require.Len(t, batch.Items, len(inputs))
+
+	for i, input := range inputs {
+		want, err := r.Explore(context.Background(), input)
+		require.NoError(t, err)
+		require.Equal(t, input.Path, batch.Items[i].Path)
+		require.Equal(t, want, batch.Items[i].Result, input.Path)
+	}
+	used := batch.ExplorersUsed()
+	require.Equal(t, 12, used["json"])
+	require.Equal(t, 12, used["yaml"])
+}
+
+func TestExploreBatch_BoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var running, peak atomic.Int32
+	slow := slowExplorerFunc(func() {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+	})
+	r := NewRegistry()
+	require.NoError(t, r.RegisterExplorer("SlowExplorer", slow, RegisterSpecificity(SpecificitySpecialized)))
+
+	inputs := make([]ExploreInput, 16)
+	for i := range inputs {
+		inputs[i] = ExploreInput{Path: fmt.Sprintf("f%d.slow", i), Content: []byte("x")}
+	}
+	batch, err := r.ExploreBatch(context.Background(), inputs, BatchOptions{Concurrency: 3})
+	require.NoError(t, err)
+	require.Zero(t, batch.Failed)
+	require.LessOrEqual(t, peak.Load(), int32(3))
+	require.Greater(t, peak.Load(), int32(1), "files should be explored concurrently")
+}
+
+func TestExploreBatch_Errors(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	inputs := []ExploreInput{
+		{Path: "a.json", Content: []byte(`{}`)},
+		{Path: "https://example.com/remote.json"},
+		{Path: "b.json", Content: []byte(`[]`)},
+	}
+
+	batch, err := r.ExploreBatch(context.Background(), inputs, BatchOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, batch.Failed)
+	require.ErrorIs(t, batch.Items[1].Err, ErrRemoteDisabled)
+	require.ErrorContains(t, batch.Err(), "https://example.com/remote.json")
+	require.NoError(t, batch.Items[0].Err)
+	require.NoError(t, batch.Items[2].Err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	batch, err = r.ExploreBatch(ctx, inputs, BatchOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, len(inputs), batch.Failed)
+
+	batch, err = r.ExploreBatch(context.Background(), nil, BatchOptions{})
+	require.NoError(t, err)
+	require.Empty(t, batch.Items)
+}
+
+// slowExplorerFunc handles *.slow files, calling fn on each exploration.
+type slowExplorerFunc func()
+
+func (slowExplorerFunc) CanHandle(path string, _ []byte) bool {
+	return strings.HasSuffix(path, ".slow")
+}
+
+func (f slowExplorerFunc) Explore(_ context.Context, input ExploreInput) (ExploreResult, error) {
+	f()
+	return ExploreResult{Summary: "slow " + input.Path}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 )
@@ -52,8 +53,11 @@ func hasBinarySignature(content []byte) bool {
 }
 
 // TextExplorer handles generic text files not matched by specific explorers.
+// Files too large to load are sampled instead: see sampleText.
 type TextExplorer struct{}
 
+var _ StreamExplorer = (*TextExplorer)(nil)
+
 func (e *TextExplorer) CanHandle(path string, content []byte) bool {
 	// Check if extension is in known text extensions
 	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
@@ -66,13 +70,7 @@ func (e *TextExplorer) CanHandle(path string, content []byte) bool {
 
 func (e *TextExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
 	if len(input.Content) > MaxFullLoadSize {
-		summary := fmt.Sprintf("Text file too large: %s (%d bytes)\nMaximum size for full load: %d bytes",
-			filepath.Base(input.Path), len(input.Content), MaxFullLoadSize)
-		return ExploreResult{
-			Summary:       summary,
-			ExplorerUsed:  "text",
-			TokenEstimate: estimateTokens(summary),
-		}, nil
+		return e.ExploreStream(ctx, input.Path, bytes.NewReader(input.Content), int64(len(input.Content)))
 	}
 
 	content, sampled := sampleContent(input.Content, 12000)
@@ -89,14 +87,34 @@ func (e *TextExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 	}
 	summary.WriteString(content)
 
+	facts := &Facts{}
+	facts.count("lines", lineCount)
+	facts.count("bytes", len(input.Content))
+
 	result := summary.String()
 	return ExploreResult{
 		Summary:       result,
 		ExplorerUsed:  "text",
 		TokenEstimate: estimateTokens(result),
+		Facts:         facts,
 	}, nil
 }
 
+// ExploreStream summarizes the text file at path read from r, which holds
+// size bytes. Files up to MaxFullLoadSize are read and explored as by
+// Explore; larger ones get streaming line and word counts and
+// deterministic head, middle and tail samples.
+func (e *TextExplorer) ExploreStream(ctx context.Context, path string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	if size <= MaxFullLoadSize {
+		content, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return ExploreResult{}, err
+		}
+		return e.Explore(ctx, ExploreInput{Path: path, Content: content})
+	}
+	return sampledTextResult(ctx, "Text file (sampled): "+filepath.Base(path), "text", r, size)
+}
+
 // FallbackExplorer is the last resort that handles everything.
 type FallbackExplorer struct{}
 
@@ -0,0 +1,131 @@
+package explorer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// CacheVersion is part of every cache key. Bump it whenever an explorer
+// changes its output, so results cached by older builds stop matching.
+const CacheVersion = 15
+
+// DefaultMemoryCacheEntries is the size of a MemoryCache created with a
+// non-positive size.
+const DefaultMemoryCacheEntries = 1024
+
+// ExploreCache stores static exploration results by content. Keys are
+// opaque strings built by the registry; implementations must be safe for
+// concurrent use.
+type ExploreCache interface {
+	// Get returns the result stored under key, if any.
+	Get(ctx context.Context, key string) (ExploreResult, bool, error)
+	// Put stores result under key.
+	Put(ctx context.Context, key string, result ExploreResult) error
+}
+
+// WithExploreCache makes Explore reuse the static result of identical
+// inputs from c. The key covers the content, the path, the output profile,
+// the explorer chain, the SQLite sampling, archive nesting and output
+// limits and CacheVersion, so a hit returns what exploring would. LLM and agent
+// enhancement still run on every call, and results shaped by a timeout or
+// an open circuit are not cached.
+func WithExploreCache(c ExploreCache) RegistryOption {
+	return func(r *Registry) {
+		r.cache = c
+	}
+}
+
+// cacheKey returns the key of input: the version, the output profile and
+// a SHA-256 of the explorer chain, sampling, nesting and output limits,
+// path and content.
+func (r *Registry) cacheKey(input ExploreInput) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "treesitter=%t\x00", r.tsParser != nil)
+	fmt.Fprintf(h, "sqlite=%+v\x00", r.sqliteSampling.withDefaults())
+	fmt.Fprintf(h, "nesting=%+v\x00", r.archiveNesting.withDefaults())
+	fmt.Fprintf(h, "output=%+v\x00", r.profileLimits())
+	for _, e := range r.explorers {
+		active := true
+		if p, ok := e.(*pluginExplorer); ok {
+			active = p.active()
+		}
+		fmt.Fprintf(h, "%s=%t\x00", explorerIdent(e), active)
+	}
+	fmt.Fprintf(h, "%s\x00", input.Path)
+	h.Write(input.Content)
+	return fmt.Sprintf("v%d:%s:%s", CacheVersion, normalizeProfile(r.formatterProfile), hex.EncodeToString(h.Sum(nil)))
+}
+
+// cachedStatic is exploreStatic behind the cache, when one is set.
+func (r *Registry) cachedStatic(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	if r.cache == nil {
+		return r.exploreStatic(ctx, input)
+	}
+
+	key := r.cacheKey(input)
+	result, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		slog.Debug("Explore cache read failed", "path", input.Path, "error", err)
+	} else if ok {
+		return result, nil
+	}
+
+	result, err = r.exploreStatic(ctx, input)
+	if err != nil || !cacheable(result) {
+		return result, err
+	}
+	if err := r.cache.Put(ctx, key, result); err != nil {
+		slog.Debug("Explore cache write failed", "path", input.Path, "error", err)
+	}
+	return result, nil
+}
+
+// cacheable reports whether result is what exploring the same input would
+// return again: skips caused by a timeout or an open circuit depend on
+// the moment, not on the content.
+func cacheable(result ExploreResult) bool {
+	for _, skip := range result.Skipped {
+		if skip.Reason == SkipTimeout || skip.Reason == SkipCircuitOpen {
+			return false
+		}
+	}
+	return true
+}
+
+// MemoryCache is an in-process ExploreCache that evicts the least recently
+// used results.
+type MemoryCache struct {
+	entries *lru.Cache[string, ExploreResult]
+}
+
+// NewMemoryCache returns a MemoryCache holding up to maxEntries results,
+// or DefaultMemoryCacheEntries when maxEntries is not positive.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheEntries
+	}
+	entries, _ := lru.New[string, ExploreResult](maxEntries)
+	return &MemoryCache{entries: entries}
+}
+
+// Get implements ExploreCache.
+func (c *MemoryCache) Get(_ context.Context, key string) (ExploreResult, bool, error) {
+	result, ok := c.entries.Get(key)
+	return result, ok, nil
+}
+
+// Put implements ExploreCache.
+func (c *MemoryCache) Put(_ context.Context, key string, result ExploreResult) error {
+	c.entries.Add(key, result)
+	return nil
+}
+
+// Len returns the number of cached results.
+func (c *MemoryCache) Len() int {
+	return c.entries.Len()
+}
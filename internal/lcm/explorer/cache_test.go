@@ -0,0 +1,68 @@
+package explorer
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExploreCache_ReusesIdenticalInput(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	cache := NewMemoryCache(0)
+	r := NewRegistry(WithExploreCache(cache))
+	require.NoError(t, r.RegisterExplorer("SlowExplorer", slowExplorerFunc(func() { calls.Add(1) })))
+
+	input := ExploreInput{Path: "a.slow", Content: []byte("payload")}
+	first, err := r.Explore(context.Background(), input)
+	require.NoError(t, err)
+	second, err := r.Explore(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, 1, cache.Len())
+
+	_, err = r.Explore(context.Background(), ExploreInput{Path: "a.slow", Content: []byte("changed")})
+	require.NoError(t, err)
+	_, err = r.Explore(context.Background(), ExploreInput{Path: "b.slow", Content: []byte("payload")})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestExploreCache_KeyCoversProfileAndExplorers(t *testing.T) {
+	t.Parallel()
+
+	input := ExploreInput{Path: "data.json", Content: []byte(`{"a": 1}`)}
+	base := NewRegistry().cacheKey(input)
+	require.Equal(t, base, NewRegistry().cacheKey(input))
+	require.NotEqual(t, base, NewRegistry(WithOutputProfile(OutputProfileParity)).cacheKey(input))
+	require.Equal(t, base, NewRegistry(WithSQLiteSampling(SQLiteSampling{SampleRows: 3})).cacheKey(input))
+	require.NotEqual(t, base, NewRegistry(WithSQLiteSampling(SQLiteSampling{SampleRows: 5})).cacheKey(input))
+
+	r := NewRegistry()
+	require.NoError(t, r.RegisterExplorer("SlowExplorer", slowExplorerFunc(func() {})))
+	require.NotEqual(t, base, r.cacheKey(input))
+}
+
+func TestExploreCache_SkipsTimedOutResults(t *testing.T) {
+	t.Parallel()
+
+	slow := &hangingExplorer{release: make(chan struct{})}
+	t.Cleanup(func() { close(slow.release) })
+
+	cache := NewMemoryCache(8)
+	r := NewRegistry(
+		WithExplorerLimits(ExplorerLimits{Timeout: 10 * time.Millisecond}, nil),
+		WithExploreCache(cache),
+	)
+	require.NoError(t, r.RegisterExplorer("SlowExplorer", slow))
+
+	result, err := r.Explore(context.Background(), ExploreInput{Path: "data.slow", Content: []byte("some text\n")})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Skipped)
+	require.Zero(t, cache.Len())
+}
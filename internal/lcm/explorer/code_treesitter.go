@@ -111,11 +111,20 @@ func (e *TreeSitterExplorer) Explore(ctx context.Context, input ExploreInput) (E
 				if kind == "" {
 					kind = "symbol"
 				}
+				details := []string{sym.Visibility}
+				// EXCEED MODE: export form, async flag and decorators
+				// beside the visibility, then the signature.
+				if e.formatterProfile == OutputProfileEnhancement {
+					details = append(details, sym.Qualifiers...)
+				}
 				if sym.Line > 0 {
-					fmt.Fprintf(&sb, "  - %s %s (%s, line %d)\n", kind, sym.Name, sym.Visibility, sym.Line)
-				} else {
-					fmt.Fprintf(&sb, "  - %s %s (%s)\n", kind, sym.Name, sym.Visibility)
+					details = append(details, fmt.Sprintf("line %d", sym.Line))
 				}
+				fmt.Fprintf(&sb, "  - %s %s (%s)", kind, sym.Name, strings.Join(details, ", "))
+				if e.formatterProfile == OutputProfileEnhancement && sym.Signature != "" {
+					fmt.Fprintf(&sb, ": %s", sym.Signature)
+				}
+				sb.WriteString("\n")
 			}
 		} else if len(analysis.Symbols) > 0 {
 			sb.WriteString("\nSymbols:\n")
@@ -146,6 +155,24 @@ func (e *TreeSitterExplorer) Explore(ctx context.Context, input ExploreInput) (E
 			}
 		}
 
+		// EXCEED MODE: first sentence of each exported symbol's doc
+		// comment, within a per-file token budget.
+		if e.formatterProfile == OutputProfileEnhancement {
+			docLang := strings.ToLower(lang)
+			var docs []symbolDoc
+			for _, sym := range analysis.Symbols {
+				if sym.DocComment == "" || inferVisibility(docLang, sym) != "public" {
+					continue
+				}
+				name := sym.Name
+				if sym.Parent != "" {
+					name = sym.Parent + "." + name
+				}
+				docs = append(docs, symbolDoc{Name: name, Raw: sym.DocComment})
+			}
+			writeDocComments(&sb, docLang, docs)
+		}
+
 		if len(analysis.Tags) > 0 {
 			sb.WriteString("\nTags:\n")
 			for _, tag := range analysis.Tags {
@@ -159,5 +186,32 @@ func (e *TreeSitterExplorer) Explore(ctx context.Context, input ExploreInput) (E
 	}
 
 	result := strings.TrimSpace(sb.String())
-	return ExploreResult{Summary: result, ExplorerUsed: "treesitter", TokenEstimate: estimateTokens(result)}, nil
+	return ExploreResult{Summary: result, ExplorerUsed: "treesitter", TokenEstimate: estimateTokens(result), Facts: treeSitterFacts(analysis)}, nil
+}
+
+// treeSitterFacts returns the parsed imports and symbols, with the line
+// span of each symbol as a section.
+func treeSitterFacts(analysis *treesitter.FileAnalysis) *Facts {
+	f := &Facts{}
+	if analysis == nil {
+		return f
+	}
+	for _, imp := range analysis.Imports {
+		f.Imports = append(f.Imports, imp.Path)
+	}
+	for _, sym := range analysis.Symbols {
+		name := sym.Name
+		if sym.Parent != "" {
+			name = sym.Parent + "." + name
+		}
+		kind := strings.TrimSpace(sym.Kind)
+		if kind == "" {
+			kind = "symbol"
+		}
+		f.Symbols = append(f.Symbols, SymbolInfo{Name: name, Kind: kind, StartLine: sym.Line, EndLine: sym.EndLine})
+		f.Sections = append(f.Sections, CodeSection{Name: name, Type: kind, StartLine: sym.Line, EndLine: sym.EndLine})
+	}
+	f.count("imports", len(analysis.Imports))
+	f.count("symbols", len(analysis.Symbols))
+	return f
 }
@@ -113,6 +113,59 @@ func TestTreeSitterExplorerExploreCallsAnalyze(t *testing.T) {
 	require.Contains(t, result.Summary, "def Main")
 }
 
+func TestTreeSitterExplorerExploreDocComments(t *testing.T) {
+	t.Parallel()
+
+	analysis := &treesitter.FileAnalysis{
+		Language: "go",
+		Symbols: []treesitter.SymbolInfo{
+			{Name: "Serve", Kind: "function", Line: 4, DocComment: "// Serve starts the server. It blocks until ctx is done."},
+			{Name: "Close", Kind: "method", Parent: "Server", Line: 9, DocComment: "// Close releases the listener."},
+			{Name: "helper", Kind: "function", Line: 12, DocComment: "// helper is unexported."},
+		},
+	}
+	content := []byte("package main")
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		p := &mockTreeSitterParser{analysis: analysis}
+		e := &TreeSitterExplorer{parser: p, formatterProfile: profile}
+		result, err := e.Explore(context.Background(), ExploreInput{Path: "main.go", Content: content})
+		require.NoError(t, err)
+		if profile == OutputProfileParity {
+			require.NotContains(t, result.Summary, "Doc comments:")
+			continue
+		}
+		require.Contains(t, result.Summary, "Doc comments:\n"+
+			"  - Serve: Serve starts the server.\n"+
+			"  - Server.Close: Close releases the listener.\n")
+		require.NotContains(t, result.Summary, "helper is unexported")
+	}
+}
+
+func TestTreeSitterExplorerExploreSignatures(t *testing.T) {
+	t.Parallel()
+
+	analysis := &treesitter.FileAnalysis{
+		Language: "python",
+		Symbols: []treesitter.SymbolInfo{
+			{Name: "fetch", Kind: "function", Line: 4, Params: "(url: str)", ReturnType: "bytes", Modifiers: []string{"async"}, Decorators: []string{"retry(3)"}},
+		},
+	}
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		p := &mockTreeSitterParser{analysis: analysis}
+		e := &TreeSitterExplorer{parser: p, formatterProfile: profile}
+		result, err := e.Explore(context.Background(), ExploreInput{Path: "client.py", Content: []byte("async def fetch(url: str) -> bytes: ...")})
+		require.NoError(t, err)
+		if profile == OutputProfileParity {
+			require.Contains(t, result.Summary, "  - function fetch (public, line 4)")
+			continue
+		}
+		require.Contains(t, result.Summary, "  - function fetch (public, async, @retry, line 4): (url: str) -> bytes")
+		require.Equal(t, "fetch", ParseFileStructure(result.Summary).Symbols[0].Name)
+	}
+}
+
 func TestTreeSitterExplorerExploreMaxFullLoadSizeGuard(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,246 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer/stdlib"
+)
+
+// CSharpExplorer explores C# source and script files without tree-sitter:
+// namespace, using directives, types, methods, properties, fields and
+// events with their accessibility. It is a family-tier explorer, so
+// TreeSitterExplorer wins when built in.
+type CSharpExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// csharpDeclRe matches the start of a declaration line: attribute lists,
+// modifiers and the rest of the line.
+var csharpDeclRe = regexp.MustCompile(`^\s*((?:\[(?:[^\[\]]|\[[^\]]*\])*\]\s*)*)((?:(?:public|private|protected|internal|static|abstract|sealed|virtual|override|readonly|const|extern|unsafe|volatile|new|partial|async|required|file|ref)\s+)*)`)
+
+var (
+	csharpNamespaceRe = regexp.MustCompile(`(?m)^\s*namespace\s+([\w.]+)`)
+	csharpUsingRe     = regexp.MustCompile(`(?m)^\s*(?:global\s+)?using\s+(?:static\s+)?(?:\w+\s*=\s*)?([\w.]+)(?:<[^;]*>)?\s*;`)
+	csharpKeywordRe   = regexp.MustCompile(`^(namespace|class|struct|interface|enum|record|delegate|event)\b\s*`)
+	csharpTypeNameRe  = regexp.MustCompile(`^(?:(?:struct|class)\s+)?(\w+)`)
+	csharpCtorRe      = regexp.MustCompile(`^(\w+)\s*\(`)
+	// csharpMemberRe matches a type, a name with optional type parameters
+	// and what follows: "(" for methods, "[" for indexers, "{" or "=>"
+	// for properties, "=", ";" or "," for fields.
+	csharpMemberRe = regexp.MustCompile(`^((?:[\w.:]+|\([^)]*\))(?:<[^;{}()=]*>)?(?:\?|\[[,\s]*\])*)\s+(\w+)\s*(?:<[^>]*>)?\s*(\(|\[|\{|=>|=|;|,|$)`)
+)
+
+// csharpStatementWords start statements that csharpMemberRe would take for
+// a type.
+var csharpStatementWords = []string{"return", "throw", "await", "new", "else", "yield", "using", "goto", "case", "var"}
+
+func (e *CSharpExplorer) CanHandle(path string, content []byte) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".cs" || ext == ".csx"
+}
+
+func (e *CSharpExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("C# file too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "csharp", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	orig := string(input.Content)
+	code, err := blankCodeLiterals(orig, csharpLiterals)
+	var decls []codeDecl
+	if err == nil {
+		decls, err = scanDecls(code, orig, matchCSharpDecl)
+	}
+	if err != nil {
+		return degradedTextResult("C# file: "+name, "csharp", input.Content, codeDegradation("csharp", input.Content, decls, err)), nil
+	}
+	var namespace string
+	if m := csharpNamespaceRe.FindStringSubmatch(code); m != nil {
+		namespace = m[1]
+	}
+	return codeExploration{
+		header:      "C# file: " + name,
+		lang:        "csharp",
+		pkg:         namespace,
+		pkgLabel:    "Namespace",
+		imports:     csharpImports(code, namespace),
+		decls:       csharpMembers(decls),
+		explorer:    "csharp",
+		returnArrow: ": ",
+	}.write(e.formatterProfile), nil
+}
+
+// csharpImports returns the namespaces and types of the using directives
+// of code. Those under the root of the file's own namespace are local.
+func csharpImports(code, namespace string) []codeImport {
+	var out []codeImport
+	seen := map[string]bool{}
+	root, _, _ := strings.Cut(namespace, ".")
+	for _, m := range csharpUsingRe.FindAllStringSubmatch(code, -1) {
+		if seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		category := "third_party"
+		switch first, _, _ := strings.Cut(m[1], "."); {
+		case stdlib.IsCSharpStdlib(m[1]):
+			category = "stdlib"
+		case root != "" && first == root:
+			category = "local"
+		}
+		out = append(out, codeImport{path: m[1], category: category})
+	}
+	return out
+}
+
+// matchCSharpDecl recognizes a C# declaration line.
+func matchCSharpDecl(code, orig string, start, end int) (codeDecl, bool) {
+	line := code[start:end]
+	m := csharpDeclRe.FindStringSubmatchIndex(line)
+	restAt := start + m[1]
+	rest := code[restAt:end]
+	if strings.TrimSpace(rest) == "" {
+		return codeDecl{}, false
+	}
+
+	var d codeDecl
+	d.attributes = bracketAttributes(line[m[2]:m[3]])
+	d.modifiers = strings.Fields(line[m[4]:m[5]])
+
+	if k := csharpKeywordRe.FindStringSubmatch(rest); k != nil {
+		keyword := k[1]
+		afterAt := restAt + len(k[0])
+		after := code[afterAt:end]
+		switch keyword {
+		case "namespace":
+			return codeDecl{kind: "namespace"}, true
+		case "delegate":
+			mm := csharpMemberRe.FindStringSubmatchIndex(after)
+			if mm == nil || after[mm[6]:mm[7]] != "(" {
+				return codeDecl{}, false
+			}
+			d.name, d.kind = after[mm[4]:mm[5]], "delegate"
+			d.returnType = collapseSpan(after[mm[2]:mm[3]])
+			d.params, _ = balancedSpan(code, orig, afterAt+mm[6], '(', ')')
+			return d, true
+		case "event":
+			mm := csharpMemberRe.FindStringSubmatch(after)
+			if mm == nil {
+				return codeDecl{}, false
+			}
+			d.name, d.kind = mm[2], "event"
+			return d, true
+		}
+		n := csharpTypeNameRe.FindStringSubmatchIndex(after)
+		if n == nil {
+			return codeDecl{}, false
+		}
+		d.name, d.kind, d.isType = after[n[2]:n[3]], keyword, true
+		d.supertypes = csharpSupertypes(code, orig, afterAt+n[1], end)
+		return d, true
+	}
+
+	if c := csharpCtorRe.FindStringSubmatchIndex(rest); c != nil && !slices.Contains(csharpStatementWords, rest[c[2]:c[3]]) {
+		d.name, d.kind = rest[c[2]:c[3]], "constructor"
+		d.params, _ = balancedSpan(code, orig, restAt+c[1]-1, '(', ')')
+		return d, true
+	}
+
+	mm := csharpMemberRe.FindStringSubmatchIndex(rest)
+	if mm == nil {
+		return codeDecl{}, false
+	}
+	typ := rest[mm[2]:mm[3]]
+	if slices.Contains(csharpStatementWords, typ) {
+		return codeDecl{}, false
+	}
+	d.name = rest[mm[4]:mm[5]]
+	switch follow := rest[mm[6]:mm[7]]; {
+	case follow == "(":
+		d.kind = "function"
+		d.returnType = collapseSpan(typ)
+		d.params, _ = balancedSpan(code, orig, restAt+mm[6], '(', ')')
+	case follow == "[" && d.name == "this":
+		d.kind = "indexer"
+		d.returnType = collapseSpan(typ)
+		d.params, _ = balancedSpan(code, orig, restAt+mm[6], '[', ']')
+	case follow == "{" || follow == "=>" || follow == "":
+		d.kind = "property"
+	case slices.Contains(d.modifiers, "const"):
+		d.kind = "constant"
+	default:
+		d.kind = "field"
+	}
+	return d, true
+}
+
+// csharpSupertypes returns the base type and interfaces of the type header
+// at code[at:end], which starts after the type name, skipping type
+// parameters and a primary constructor.
+func csharpSupertypes(code, orig string, at, end int) []string {
+	rest := code[at:end]
+	if strings.HasPrefix(strings.TrimSpace(rest), "<") {
+		if i := strings.IndexByte(rest, '>'); i >= 0 {
+			at += i + 1
+		}
+	}
+	rest = code[at:end]
+	if i := strings.IndexByte(rest, '('); i >= 0 && !strings.Contains(rest[:i], ":") {
+		_, next := balancedSpan(code, orig, at+i, '(', ')')
+		rest, _, _ = strings.Cut(code[next:], "\n")
+	}
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, ":") {
+		return nil
+	}
+	rest = rest[1:]
+	for _, stop := range []string{"{", ";", " where "} {
+		if i := strings.Index(rest, stop); i >= 0 {
+			rest = rest[:i]
+		}
+	}
+	var out []string
+	for _, s := range splitTopLevel(rest) {
+		// Drop base constructor arguments of records: Base(Name).
+		if i := strings.IndexByte(s, '('); i >= 0 {
+			s = s[:i]
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// csharpAccessModifiers are the C# accessibility keywords.
+var csharpAccessModifiers = []string{"public", "protected", "internal", "private"}
+
+// csharpMembers gives declarations without an access modifier their
+// default accessibility, internal for top-level types, public for
+// interface members and private otherwise, then drops top-level
+// statements and local functions, which are not members.
+func csharpMembers(decls []codeDecl) []codeDecl {
+	for i := range decls {
+		d := &decls[i]
+		if slices.ContainsFunc(d.modifiers, func(m string) bool { return slices.Contains(csharpAccessModifiers, m) }) {
+			continue
+		}
+		switch {
+		case d.owner < 0:
+			d.modifiers = append(d.modifiers, "internal")
+		case decls[d.owner].kind == "interface":
+			d.modifiers = append(d.modifiers, "public")
+		default:
+			d.modifiers = append(d.modifiers, "private")
+		}
+	}
+	return slices.DeleteFunc(decls, func(d codeDecl) bool {
+		return d.owner < 0 && !d.isType && d.kind != "delegate"
+	})
+}
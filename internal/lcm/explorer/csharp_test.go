@@ -0,0 +1,160 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testCSharp = `using System;
+using System.Collections.Generic;
+using static System.Math;
+using Json = Newtonsoft.Json.JsonConvert;
+using Acme.Core.Data;
+
+namespace Acme.Users
+{
+    /// <summary>Stores users.</summary>
+    public interface IUserStore
+    {
+        Task<User?> FindAsync(int id);
+        int Count { get; }
+    }
+
+    [Serializable]
+    public class UserStore : StoreBase, IUserStore, IDisposable
+    {
+        public const int MaxUsers = 100;
+        private readonly Dictionary<int, User> _users = new();
+        public event EventHandler<User>? Added;
+
+        public UserStore(string path) : base(path)
+        {
+            var brace = "{ not a brace";
+        }
+
+        public int Count => _users.Count;
+
+        [Obsolete("use FindAsync"), HttpGet("{id}")]
+        public virtual User Find(int id) => _users[id];
+
+        public async Task<User?> FindAsync(int id)
+        {
+            void Helper() { }
+            return await Task.FromResult<User?>(null);
+        }
+
+        protected internal static T Load<T>(string key) where T : class => default!;
+
+        public User this[int id] => _users[id];
+
+        internal void Clear() { _users.Clear(); }
+
+        private sealed class Cache
+        {
+            string name = @"verbatim ""}"" string";
+            public void Flush() { }
+        }
+
+        public void Dispose() { }
+    }
+
+    public record User(int Id, string Name) : Entity(Id);
+
+    enum Mode { Fast, Slow }
+
+    public delegate void UserHandler(User user);
+}
+`
+
+func TestCSharpExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &CSharpExplorer{}
+	require.True(t, e.CanHandle("src/Users/UserStore.cs", nil))
+	require.True(t, e.CanHandle("build.csx", nil))
+	require.False(t, e.CanHandle("App.csproj", nil))
+}
+
+func TestCSharpExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &CSharpExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "UserStore.cs", Content: []byte(testCSharp)})
+	require.NoError(t, err)
+	require.Equal(t, "csharp", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "C# file: UserStore.cs\nLanguage: csharp\nNamespace: Acme.Users\n"+
+		"Declarations: 21 (15 public, 1 protected, 2 internal, 3 private)\n")
+	require.Contains(t, s, "Imports:\n"+
+		"  - System (stdlib)\n"+
+		"  - System.Collections.Generic (stdlib)\n"+
+		"  - System.Math (stdlib)\n"+
+		"  - Newtonsoft.Json.JsonConvert (third_party)\n"+
+		"  - Acme.Core.Data (local)\n")
+	require.Contains(t, s, "  - interface IUserStore (public, line 10)\n"+
+		"  - method IUserStore.FindAsync (public, line 12)\n"+
+		"  - property IUserStore.Count (public, line 13)\n")
+	require.Contains(t, s, "  - constant UserStore.MaxUsers (public, line 19)\n"+
+		"  - field UserStore._users (private, line 20)\n"+
+		"  - event UserStore.Added (public, line 21)\n"+
+		"  - constructor UserStore.UserStore (public, line 23)\n"+
+		"  - property UserStore.Count (public, line 28)\n")
+	require.Contains(t, s, "  - method UserStore.Load (protected, line 39)\n")
+	require.Contains(t, s, "  - indexer UserStore.this (public, line 41)\n")
+	require.Contains(t, s, "  - method UserStore.Clear (internal, line 43)\n")
+	require.Contains(t, s, "  - class UserStore.Cache (private, line 45)\n"+
+		"  - field UserStore.Cache.name (private, line 47)\n"+
+		"  - method UserStore.Cache.Flush (public, line 48)\n")
+	require.Contains(t, s, "  - record User (public, line 54)\n")
+	require.Contains(t, s, "  - enum Mode (internal, line 56)\n")
+	require.True(t, strings.HasSuffix(s, "  - delegate UserHandler (public, line 58)"))
+	require.NotContains(t, s, "Helper")
+	require.NotContains(t, s, "brace")
+}
+
+func TestCSharpExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &CSharpExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "UserStore.cs", Content: []byte(testCSharp)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "  - class UserStore (public, [Serializable], line 17)\n")
+	require.Contains(t, s, "  - method UserStore.Find (public, virtual, [Obsolete], [HttpGet], line 31): (int id): User\n")
+	require.Contains(t, s, "  - method UserStore.FindAsync (public, async, line 33): (int id): Task<User?>\n")
+	require.Contains(t, s, "  - method UserStore.Load (protected, static, line 39): (string key): T\n")
+	require.Contains(t, s, "  - delegate UserHandler (public, line 58): (User user): void")
+	require.Contains(t, s, "Inheritance:\n"+
+		"  - UserStore: StoreBase, IUserStore, IDisposable\n"+
+		"  - User: Entity")
+}
+
+func TestCSharpExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&CSharpExplorer{}).Explore(context.Background(), ExploreInput{
+		Path:    "A.cs",
+		Content: []byte("class A\n{\n    void F() { }\n}\n}\n"),
+	})
+	require.NoError(t, err)
+	require.Regexp(t, degradedBlockPattern, result.Summary)
+	require.Contains(t, result.Summary, "Failed: csharp scanning at line 5, column 1: unmatched closing brace\n")
+	require.Contains(t, result.Summary, "Progress: found 2 declarations before the error\n")
+}
+
+func TestCSharpExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "UserStore.cs", Content: []byte(testCSharp)})
+		require.NoError(t, err)
+		require.Equal(t, "csharp", result.ExplorerUsed)
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Inheritance"))
+	}
+}
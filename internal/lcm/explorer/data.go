@@ -1,14 +1,16 @@
 package explorer
 
 import (
+	"bytes"
 	"context"
-	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -30,10 +32,12 @@ func (e *JSONExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 
 	var data any
 	if err := json.Unmarshal(input.Content, &data); err != nil {
-		// Invalid JSON, fallback to text
-		content, _ := sampleContent(input.Content, 12000)
-		summary := fmt.Sprintf("JSON file (parse error): %s\n%s", filepath.Base(input.Path), content)
-		return ExploreResult{Summary: summary, ExplorerUsed: "json", TokenEstimate: estimateTokens(summary)}, nil
+		if isJSONTruncation(input.Content, err) {
+			if r, ok := recoverTruncatedJSON(input.Content); ok {
+				return recoveredJSONResult(input.Path, input.Content, err, r), nil
+			}
+		}
+		return degradedTextResult(fmt.Sprintf("JSON file (parse error): %s", filepath.Base(input.Path)), "json", input.Content, jsonDegradation(input.Path, input.Content, err)), nil
 	}
 
 	var summary strings.Builder
@@ -66,8 +70,8 @@ func describeJSONValue(sb *strings.Builder, value any, depth int, maxDepth int)
 			fmt.Fprintf(sb, "%s{} (empty object)\n", indent)
 			return
 		}
-		for key, val := range v {
-			switch typed := val.(type) {
+		for _, key := range sortedKeys(v) {
+			switch typed := v[key].(type) {
 			case map[string]any:
 				fmt.Fprintf(sb, "%s%s: object (%d keys)\n", indent, key, len(typed))
 				describeJSONValue(sb, typed, depth+1, maxDepth)
@@ -111,62 +115,6 @@ func describeJSONValue(sb *strings.Builder, value any, depth int, maxDepth int)
 	}
 }
 
-// CSVExplorer explores CSV files.
-type CSVExplorer struct{}
-
-func (e *CSVExplorer) CanHandle(path string, content []byte) bool {
-	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
-	return ext == "csv" || ext == "tsv"
-}
-
-func (e *CSVExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
-	if len(input.Content) > MaxFullLoadSize {
-		summary := fmt.Sprintf("CSV file too large: %s (%d bytes)", filepath.Base(input.Path), len(input.Content))
-		return ExploreResult{Summary: summary, ExplorerUsed: "csv", TokenEstimate: estimateTokens(summary)}, nil
-	}
-
-	reader := csv.NewReader(strings.NewReader(string(input.Content)))
-	// Detect TSV
-	if strings.HasSuffix(strings.ToLower(input.Path), ".tsv") {
-		reader.Comma = '\t'
-	}
-
-	records, err := reader.ReadAll()
-	if err != nil {
-		// Fallback to text
-		content, _ := sampleContent(input.Content, 12000)
-		summary := fmt.Sprintf("CSV file (parse error): %s\n%s", filepath.Base(input.Path), content)
-		return ExploreResult{Summary: summary, ExplorerUsed: "csv", TokenEstimate: estimateTokens(summary)}, nil
-	}
-
-	var summary strings.Builder
-	fmt.Fprintf(&summary, "CSV file: %s\n", filepath.Base(input.Path))
-	fmt.Fprintf(&summary, "Rows: %d\n", len(records))
-
-	if len(records) > 0 {
-		fmt.Fprintf(&summary, "Columns: %d\n", len(records[0]))
-		summary.WriteString("\nColumn headers:\n")
-		for i, col := range records[0] {
-			fmt.Fprintf(&summary, "  %d. %s\n", i+1, col)
-		}
-
-		if len(records) > 1 {
-			summary.WriteString("\nSample rows (first 3):\n")
-			maxSample := min(len(records)-1, 3)
-			for i := 1; i <= maxSample; i++ {
-				fmt.Fprintf(&summary, "  Row %d: %v\n", i, records[i])
-			}
-		}
-	}
-
-	result := summary.String()
-	return ExploreResult{
-		Summary:       result,
-		ExplorerUsed:  "csv",
-		TokenEstimate: estimateTokens(result),
-	}, nil
-}
-
 // YAMLExplorer explores YAML files.
 type YAMLExplorer struct{}
 
@@ -183,10 +131,7 @@ func (e *YAMLExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 
 	var data any
 	if err := yaml.Unmarshal(input.Content, &data); err != nil {
-		// Fallback to text
-		content, _ := sampleContent(input.Content, 12000)
-		summary := fmt.Sprintf("YAML file (parse error): %s\n%s", filepath.Base(input.Path), content)
-		return ExploreResult{Summary: summary, ExplorerUsed: "yaml", TokenEstimate: estimateTokens(summary)}, nil
+		return degradedTextResult(fmt.Sprintf("YAML file (parse error): %s", filepath.Base(input.Path)), "yaml", input.Content, yamlDegradation(input.Content, err)), nil
 	}
 
 	var summary strings.Builder
@@ -218,8 +163,8 @@ func describeYAMLValue(sb *strings.Builder, value any, depth int, maxDepth int)
 			fmt.Fprintf(sb, "%s{} (empty map)\n", indent)
 			return
 		}
-		for key, val := range v {
-			switch typed := val.(type) {
+		for _, key := range sortedKeys(v) {
+			switch typed := v[key].(type) {
 			case map[string]any:
 				fmt.Fprintf(sb, "%s%s: map (%d keys)\n", indent, key, len(typed))
 				describeYAMLValue(sb, typed, depth+1, maxDepth)
@@ -356,8 +301,10 @@ func (e *INIExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 	}, nil
 }
 
-// XMLExplorer explores XML files.
-type XMLExplorer struct{}
+// XMLExplorer explores XML files, SVG documents included.
+type XMLExplorer struct {
+	formatterProfile OutputProfile
+}
 
 func (e *XMLExplorer) CanHandle(path string, content []byte) bool {
 	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
@@ -382,6 +329,7 @@ func (e *XMLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 	decoder := xml.NewDecoder(strings.NewReader(string(input.Content)))
 	elements := make(map[string]int)
 	var currentPath []string
+	var svg *svgStats
 
 	for {
 		tok, err := decoder.Token()
@@ -389,14 +337,29 @@ func (e *XMLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 			break
 		}
 		if err != nil {
-			// Parse error, fallback to text
-			content, _ := sampleContent(input.Content, 12000)
-			summary := fmt.Sprintf("XML file (parse error): %s\n%s", filepath.Base(input.Path), content)
-			return ExploreResult{Summary: summary, ExplorerUsed: "xml", TokenEstimate: estimateTokens(summary)}, nil
+			progress := fmt.Sprintf("%d elements read", sumCounts(elements))
+			if len(currentPath) > 0 {
+				progress += ", inside " + strings.Join(currentPath, "/")
+			}
+			return degradedTextResult(fmt.Sprintf("XML file (parse error): %s", filepath.Base(input.Path)), "xml", input.Content, degradedExploration{
+				Failed:   "XML tokenizing: " + err.Error(),
+				Progress: progress,
+				Examined: decoder.InputOffset(),
+				Size:     int64(len(input.Content)),
+				NextSteps: []string{
+					"Check for an unclosed tag or an unescaped '&' or '<' near the reported line",
+					"Read the raw lines around the error with the view tool",
+				},
+			}), nil
 		}
 
 		switch se := tok.(type) {
 		case xml.StartElement:
+			if len(currentPath) == 0 && len(elements) == 0 {
+				svg = newSVGStats(se)
+			} else if svg != nil {
+				svg.add(se)
+			}
 			currentPath = append(currentPath, se.Name.Local)
 			path := strings.Join(currentPath, "/")
 			elements[path]++
@@ -409,8 +372,8 @@ func (e *XMLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 
 	if len(elements) > 0 {
 		summary.WriteString("\nElement hierarchy:\n")
-		for path, count := range elements {
-			if count > 1 {
+		for _, path := range sortedKeys(elements) {
+			if count := elements[path]; count > 1 {
 				fmt.Fprintf(&summary, "  - %s (×%d)\n", path, count)
 			} else {
 				fmt.Fprintf(&summary, "  - %s\n", path)
@@ -418,6 +381,11 @@ func (e *XMLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 		}
 	}
 
+	// EXCEED MODE: SVG canvas and drawing statistics.
+	if svg != nil && e.formatterProfile == OutputProfileEnhancement {
+		svg.write(&summary)
+	}
+
 	result := summary.String()
 	return ExploreResult{
 		Summary:       result,
@@ -426,58 +394,82 @@ func (e *XMLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 	}, nil
 }
 
-// HTMLExplorer explores HTML files.
-type HTMLExplorer struct{}
-
-func (e *HTMLExplorer) CanHandle(path string, content []byte) bool {
-	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
-	if ext == "html" || ext == "htm" || ext == "xhtml" {
-		return true
+// jsonDegradation describes a JSON decoding failure, locating it from the
+// syntax error offset when there is one.
+func jsonDegradation(path string, content []byte, err error) degradedExploration {
+	size := int64(len(content))
+	d := degradedExploration{
+		Failed:   "JSON decoding: " + err.Error(),
+		Progress: "no value decoded",
+		Examined: size,
+		Size:     size,
+		NextSteps: []string{
+			"Check whether the file was truncated or is still being written",
+			"Read the raw content around the error with the view tool",
+		},
 	}
-	// Check if content looks like HTML
-	contentLower := strings.ToLower(string(content))
-	return strings.Contains(contentLower, "<!doctype html") ||
-		strings.Contains(contentLower, "<html")
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset counts the bytes read, including the offending one.
+		d.Examined = syntaxErr.Offset
+		if syntaxErr.Offset >= size {
+			line, col := lineColumn(content, size)
+			d.Progress = fmt.Sprintf("input ends inside an unterminated value at line %d, column %d", line, col)
+		} else {
+			line, col := lineColumn(content, syntaxErr.Offset-1)
+			d.Progress = fmt.Sprintf("valid up to the error at line %d, column %d", line, col)
+		}
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".jsonc" || ext == ".json5" {
+		d.NextSteps = append(d.NextSteps, "Comments and trailing commas are not supported; strip them to explore the structure")
+	}
+	return d
 }
 
-func (e *HTMLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
-	if len(input.Content) > MaxFullLoadSize {
-		summary := fmt.Sprintf("HTML file too large: %s (%d bytes)", filepath.Base(input.Path), len(input.Content))
-		return ExploreResult{Summary: summary, ExplorerUsed: "html", TokenEstimate: estimateTokens(summary)}, nil
+// yamlLinePattern extracts the line number from a yaml.v3 error.
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// yamlDegradation describes a YAML decoding failure, locating it from the
+// line number in the error message when there is one.
+func yamlDegradation(content []byte, err error) degradedExploration {
+	size := int64(len(content))
+	d := degradedExploration{
+		Failed:   "YAML decoding: " + err.Error(),
+		Progress: "no document decoded",
+		Examined: size,
+		Size:     size,
+		NextSteps: []string{
+			"Check indentation and mixed tabs/spaces near the reported line",
+			"Read the raw lines around the error with the view tool",
+		},
 	}
-
-	content := string(input.Content)
-	var summary strings.Builder
-	fmt.Fprintf(&summary, "HTML file: %s\n", filepath.Base(input.Path))
-	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
-
-	// Extract title
-	titleRe := regexp.MustCompile(`(?i)<title[^>]*>([^<]+)</title>`)
-	if match := titleRe.FindStringSubmatch(content); match != nil {
-		fmt.Fprintf(&summary, "Title: %s\n", strings.TrimSpace(match[1]))
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		d.Progress = fmt.Sprintf("parser stopped at line %d", line)
+		d.Examined = lineEndOffset(content, line)
 	}
+	return d
+}
 
-	// Count common elements
-	elemCounts := make(map[string]int)
-	for _, elem := range []string{"div", "span", "p", "a", "img", "script", "link", "style", "form", "input", "button"} {
-		re := regexp.MustCompile(fmt.Sprintf(`(?i)<%s[\s>]`, elem))
-		matches := re.FindAllString(content, -1)
-		if len(matches) > 0 {
-			elemCounts[elem] = len(matches)
+// lineEndOffset returns the byte offset just past the 1-based line in
+// content, or the content length when it has fewer lines.
+func lineEndOffset(content []byte, line int) int64 {
+	offset := 0
+	for range line {
+		i := bytes.IndexByte(content[offset:], '\n')
+		if i < 0 {
+			return int64(len(content))
 		}
+		offset += i + 1
 	}
+	return int64(offset)
+}
 
-	if len(elemCounts) > 0 {
-		summary.WriteString("\nElement counts:\n")
-		for elem, count := range elemCounts {
-			fmt.Fprintf(&summary, "  - <%s>: %d\n", elem, count)
-		}
+// sumCounts returns the total of a histogram.
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
 	}
-
-	result := summary.String()
-	return ExploreResult{
-		Summary:       result,
-		ExplorerUsed:  "html",
-		TokenEstimate: estimateTokens(result),
-	}, nil
+	return total
 }
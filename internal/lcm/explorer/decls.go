@@ -0,0 +1,617 @@
+package explorer
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxDeclSymbols caps the symbols listed by the regex-based code explorers.
+const maxDeclSymbols = 200
+
+// codeDecl is a declaration found by a regex-based code explorer.
+type codeDecl struct {
+	name, kind string
+	line       int
+	// parent is the dotted name of the enclosing types, if any, and owner
+	// the index of the innermost one among the declarations, or -1.
+	parent     string
+	owner      int
+	params     string
+	returnType string
+	modifiers  []string
+	// attributes are Swift attributes or Kotlin annotations, with "@",
+	// or C# [Name] and PHP #[Name] attributes.
+	attributes []string
+	// isType reports whether the declaration's body holds members.
+	isType bool
+	// supertypes are the declared superclass, protocols or interfaces.
+	supertypes []string
+}
+
+func (d codeDecl) qualifiedName() string {
+	if d.parent == "" {
+		return d.name
+	}
+	return d.parent + "." + d.name
+}
+
+// signature is the parameter list and return type, whitespace collapsed.
+func (d codeDecl) signature(returnArrow string) string {
+	if d.returnType == "" {
+		return d.params
+	}
+	return d.params + returnArrow + d.returnType
+}
+
+// declMatcher recognizes a declaration on the line code[start:end], which
+// has literals and comments blanked. orig is the unblanked source, at the
+// same offsets, for signatures.
+type declMatcher func(code, orig string, start, end int) (codeDecl, bool)
+
+// codeScanError is a brace or literal imbalance at offset.
+type codeScanError struct {
+	offset int
+	msg    string
+}
+
+func (e *codeScanError) Error() string { return e.msg }
+
+// literalSyntax describes the comment and string literal forms of a
+// language beyond // and /* */ comments and "double-quoted" strings.
+type literalSyntax struct {
+	// nestedComments makes block comments nest, as in Swift and Kotlin.
+	nestedComments bool
+	// charLiterals enables 'c' (or 'single-quoted string') literals.
+	charLiterals bool
+	// multilineStrings lets quoted strings span lines, as in PHP.
+	multilineStrings bool
+	// tripleQuotes enables """multi-line""" strings.
+	tripleQuotes bool
+	// verbatimStrings enables C# @"..." strings with "" escapes.
+	verbatimStrings bool
+	// php enables # comments (but not #[ attributes), heredocs and
+	// blanks text outside <?php ... ?> tags.
+	php bool
+}
+
+var (
+	swiftLiterals  = literalSyntax{nestedComments: true, tripleQuotes: true}
+	kotlinLiterals = literalSyntax{nestedComments: true, charLiterals: true, tripleQuotes: true}
+	csharpLiterals = literalSyntax{charLiterals: true, tripleQuotes: true, verbatimStrings: true}
+	phpLiterals    = literalSyntax{charLiterals: true, multilineStrings: true, php: true}
+)
+
+// phpHeredocRe matches the opening of a PHP heredoc or nowdoc.
+var phpHeredocRe = regexp.MustCompile(`^<<<[ \t]*(["']?)(\w+)["']?\r?\n`)
+
+// blankCodeLiterals replaces the content of comments and string literals
+// with spaces, keeping newlines and offsets, so braces and keywords inside
+// them are not taken for code.
+func blankCodeLiterals(src string, syntax literalSyntax) (string, *codeScanError) {
+	out := []byte(src)
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+	i := 0
+	if syntax.php {
+		// Inline HTML before the first tag is not code.
+		i = max(0, strings.Index(src, "<?"))
+		blank(0, i)
+	}
+	for i < len(src) {
+		switch {
+		case syntax.php && strings.HasPrefix(src[i:], "?>"):
+			next := strings.Index(src[i:], "<?")
+			if next < 0 {
+				next = len(src) - i
+			}
+			blank(i, i+next)
+			i += max(next, 2)
+		case strings.HasPrefix(src[i:], "//") || syntax.php && src[i] == '#' && !strings.HasPrefix(src[i:], "#["):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			if syntax.php {
+				// A line comment ends at ?> too.
+				if tag := strings.Index(src[i:i+end], "?>"); tag >= 0 {
+					end = tag
+				}
+			}
+			blank(i, i+end)
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			depth, j := 0, i
+			for j < len(src) {
+				if strings.HasPrefix(src[j:], "/*") && (syntax.nestedComments || depth == 0) {
+					depth++
+					j += 2
+				} else if strings.HasPrefix(src[j:], "*/") {
+					depth--
+					j += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					j++
+				}
+			}
+			if depth > 0 {
+				return string(out), &codeScanError{offset: i, msg: "unterminated block comment"}
+			}
+			blank(i, j)
+			i = j
+		case syntax.php && strings.HasPrefix(src[i:], "<<<"):
+			m := phpHeredocRe.FindStringSubmatch(src[i:])
+			if m == nil {
+				i += 3
+				continue
+			}
+			body := i + len(m[0])
+			end := regexp.MustCompile(`(?m)^[ \t]*` + m[2] + `\b`).FindStringIndex(src[body:])
+			if end == nil {
+				return string(out), &codeScanError{offset: i, msg: "unterminated heredoc"}
+			}
+			blank(body, body+end[0])
+			i = body + end[1]
+		case syntax.tripleQuotes && strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			if end < 0 {
+				return string(out), &codeScanError{offset: i, msg: "unterminated multi-line string"}
+			}
+			blank(i+3, i+3+end)
+			i += end + 6
+		case syntax.verbatimStrings && (strings.HasPrefix(src[i:], `@"`) || strings.HasPrefix(src[i:], `$@"`) || strings.HasPrefix(src[i:], `@$"`)):
+			j := i + strings.IndexByte(src[i:], '"') + 1
+			for j < len(src) && (src[j] != '"' || strings.HasPrefix(src[j:], `""`)) {
+				if src[j] == '"' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return string(out), &codeScanError{offset: i, msg: "unterminated verbatim string"}
+			}
+			blank(i+1, j)
+			i = j + 1
+		case src[i] == '"' || syntax.charLiterals && src[i] == '\'':
+			quote := src[i]
+			j := i + 1
+			for j < len(src) && src[j] != quote && (syntax.multilineStrings || src[j] != '\n') {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) || src[j] != quote {
+				return string(out), &codeScanError{offset: i, msg: "unterminated string literal"}
+			}
+			blank(i+1, j)
+			i = j + 1
+		default:
+			i++
+		}
+	}
+	return string(out), nil
+}
+
+// declScope is an open brace: a declaration's body or a plain block.
+type declScope struct {
+	name   string
+	isType bool
+	open   int
+	// decl is the index of the declaration owning the brace, or -1.
+	decl int
+}
+
+// scanDecls finds the declarations at the top level of code and in type
+// bodies, nested to any depth; function bodies and other blocks are
+// skipped. A declaration owns the next brace unless the line ends it.
+func scanDecls(code, orig string, match declMatcher) ([]codeDecl, *codeScanError) {
+	var (
+		decls   []codeDecl
+		stack   []declScope
+		pending *declScope
+		parens  int
+		// attrs are the attributes on the lines above a declaration.
+		attrs []string
+	)
+	lines := strings.SplitAfter(code, "\n")
+	start := 0
+	for i, line := range lines {
+		end := start + len(line)
+		// Parameters of a header spanning lines are not members.
+		inMembers := parens <= 0 && !slices.ContainsFunc(stack, func(s declScope) bool { return !s.isType })
+		if inMembers {
+			if d, ok := match(code, orig, start, end); ok && d.kind == "namespace" {
+				// A namespace holds members without being one.
+				pending = &declScope{isType: true, decl: -1}
+				parens = 0
+			} else if ok {
+				var parents []string
+				for _, s := range stack {
+					if s.name != "" {
+						parents = append(parents, s.name)
+					}
+				}
+				d.line = i + 1
+				d.parent = strings.Join(parents, ".")
+				d.owner = -1
+				if len(stack) > 0 {
+					d.owner = stack[len(stack)-1].decl
+				}
+				if d.parent != "" {
+					switch d.kind {
+					case "function":
+						d.kind = "method"
+					case "variable":
+						d.kind = "property"
+					}
+				}
+				d.attributes = append(attrs, d.attributes...)
+				decls = append(decls, d)
+				pending = &declScope{name: d.name, isType: d.isType, decl: len(decls) - 1}
+				parens = 0
+			}
+		}
+		switch trimmed := strings.TrimSpace(line); {
+		case strings.HasPrefix(trimmed, "@") && pending == nil:
+			attrs = append(attrs, declAttributes(trimmed)...)
+		case (strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "#[")) && pending == nil:
+			attrs = append(attrs, bracketAttributes(trimmed)...)
+		case trimmed != "":
+			attrs = nil
+		}
+		for j := start; j < end; j++ {
+			switch code[j] {
+			case '{':
+				scope := declScope{open: j, decl: -1}
+				if pending != nil {
+					scope.name, scope.isType, scope.decl = pending.name, pending.isType, pending.decl
+					pending = nil
+				}
+				stack = append(stack, scope)
+			case '}':
+				if len(stack) == 0 {
+					return decls, &codeScanError{offset: j, msg: "unmatched closing brace"}
+				}
+				stack = stack[:len(stack)-1]
+				pending = nil
+			case '(':
+				parens++
+			case ')':
+				parens--
+			}
+		}
+		if pending != nil && parens <= 0 && !declContinues(line, nextCodeLine(lines[i+1:])) {
+			pending = nil
+		}
+		start = end
+	}
+	if len(stack) > 0 {
+		return decls, &codeScanError{offset: stack[len(stack)-1].open, msg: "unclosed brace"}
+	}
+	return decls, nil
+}
+
+// declAttributes returns the names of the attributes or annotations
+// that start s, such as "@MainActor" of "@MainActor @objc(run) func".
+func declAttributes(s string) []string {
+	var out []string
+	for _, m := range declAttributeRe.FindAllStringSubmatch(s, -1) {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+var declAttributeRe = regexp.MustCompile(`(?:^|\s)(@[\w.:]+)(?:\([^)]*\))?`)
+
+// bracketAttributes returns the C# [Name] or PHP #[Name] attributes of
+// the attribute lists that start s, such as "[Obsolete]" and "[HttpGet]"
+// of "[Obsolete, HttpGet("{id}")] public User Get(int id)". Targets such
+// as "return:" and arguments are dropped.
+func bracketAttributes(s string) []string {
+	var out []string
+	for {
+		s = strings.TrimSpace(s)
+		prefix := "["
+		if strings.HasPrefix(s, "#[") {
+			prefix = "#["
+		} else if !strings.HasPrefix(s, "[") {
+			return out
+		}
+		depth, end := 0, -1
+		for i := len(prefix) - 1; i < len(s) && end < 0; i++ {
+			switch s[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			return out
+		}
+		for _, a := range splitTopLevel(s[len(prefix):end]) {
+			if m := bracketAttributeNameRe.FindStringSubmatch(a); m != nil {
+				out = append(out, prefix+m[1]+"]")
+			}
+		}
+		s = s[end+1:]
+	}
+}
+
+var bracketAttributeNameRe = regexp.MustCompile(`^(?:\w+\s*:\s*)?\\?([A-Za-z_][\w.\\]*)`)
+
+// nextCodeLine returns the first non-blank line of lines, trimmed.
+func nextCodeLine(lines []string) string {
+	for _, l := range lines {
+		if t := strings.TrimSpace(l); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// declContinues reports whether a declaration header goes on past line,
+// so the brace that opens its body may still follow.
+func declContinues(line, next string) bool {
+	line = strings.TrimSpace(line)
+	for _, suffix := range []string{",", "(", ":", "=", "->", "<", "&", "."} {
+		if strings.HasSuffix(line, suffix) {
+			return true
+		}
+	}
+	for _, prefix := range []string{"{", ":", ",", ".", ")", "->", "where", "throws", "rethrows", "async"} {
+		if strings.HasPrefix(next, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// balancedSpan returns the bracketed text of orig starting at the opening
+// bracket at code[i], collapsed to one line, and the offset past it.
+func balancedSpan(code, orig string, i int, open, close byte) (string, int) {
+	depth := 0
+	for j := i; j < len(code); j++ {
+		switch code[j] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return collapseSpan(orig[i : j+1]), j + 1
+			}
+		case '{', '}':
+			// A body started: the header is malformed.
+			return collapseSpan(orig[i:j]), j
+		}
+	}
+	return collapseSpan(orig[i:]), len(code)
+}
+
+// collapseSpan collapses whitespace in s to single spaces, dropping it
+// inside the brackets of a parameter list split over lines.
+func collapseSpan(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.ReplaceAll(s, "( ", "(")
+	s = strings.ReplaceAll(s, ", )", ")")
+	return strings.ReplaceAll(s, " )", ")")
+}
+
+// splitTopLevel splits s at commas outside brackets.
+func splitTopLevel(s string) []string {
+	var out []string
+	depth, from := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(', '<', '[':
+			depth++
+		case ')', '>', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				out = append(out, strings.TrimSpace(s[from:i]))
+				from = i + 1
+			}
+		}
+	}
+	if rest := strings.TrimSpace(s[from:]); rest != "" {
+		out = append(out, rest)
+	}
+	return out
+}
+
+// declVisibility classifies a declaration by its access modifiers.
+// Kotlin and PHP declarations are public by default, Swift ones internal
+// (visible module-wide); private(set) only restricts a setter. C#
+// defaults depend on the enclosing declaration, so the C# explorer adds
+// them before this is called; protected internal counts as protected.
+func declVisibility(lang string, modifiers []string) string {
+	has := func(want string) bool { return slices.Contains(modifiers, want) }
+	switch lang {
+	case "kotlin":
+		for _, v := range []string{"private", "protected", "internal"} {
+			if has(v) {
+				return v
+			}
+		}
+		return "public"
+	case "swift":
+		switch {
+		case has("public") || has("open"):
+			return "public"
+		case has("private") || has("fileprivate"):
+			return "private"
+		}
+		return "internal"
+	case "csharp":
+		for _, v := range []string{"public", "protected", "internal"} {
+			if has(v) {
+				return v
+			}
+		}
+		return "private"
+	case "php":
+		for _, v := range []string{"private", "protected"} {
+			if has(v) {
+				return v
+			}
+		}
+		return "public"
+	}
+	return "unknown"
+}
+
+// declQualifierModifiers are the modifiers listed beside a symbol's
+// visibility in enhancement output.
+var declQualifierModifiers = []string{"static", "abstract", "final", "open", "virtual", "override", "data", "sealed", "readonly", "partial", "suspend", "async", "inline"}
+
+// codeExploration is the output of a regex-based code explorer.
+type codeExploration struct {
+	header string
+	lang   string
+	pkg    string
+	// pkgLabel names pkg in the header; "Package" when empty.
+	pkgLabel string
+	imports  []codeImport
+	decls    []codeDecl
+	explorer string
+	// returnArrow separates parameters from the return type.
+	returnArrow string
+}
+
+// codeImport is an imported module or package and its category: stdlib,
+// third_party or local.
+type codeImport struct {
+	path, category string
+}
+
+// write renders the exploration the way TreeSitterExplorer renders a
+// parsed file: categorized imports, then symbols with visibility.
+func (c codeExploration) write(profile OutputProfile) ExploreResult {
+	var sb strings.Builder
+	sb.WriteString(c.header + "\n")
+	fmt.Fprintf(&sb, "Language: %s\n", c.lang)
+	if c.pkg != "" {
+		label := c.pkgLabel
+		if label == "" {
+			label = "Package"
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", label, c.pkg)
+	}
+	visibilities := map[string]int{}
+	for _, d := range c.decls {
+		visibilities[declVisibility(c.lang, d.modifiers)]++
+	}
+	if len(c.decls) > 0 {
+		var counts []string
+		for _, v := range []string{"public", "protected", "internal", "private"} {
+			if visibilities[v] > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", visibilities[v], v))
+			}
+		}
+		fmt.Fprintf(&sb, "Declarations: %d (%s)\n", len(c.decls), strings.Join(counts, ", "))
+	}
+
+	if len(c.imports) > 0 {
+		sb.WriteString("\nImports:\n")
+		for _, cat := range []string{"stdlib", "third_party", "local"} {
+			for _, imp := range c.imports {
+				if imp.category == cat {
+					fmt.Fprintf(&sb, "  - %s (%s)\n", imp.path, cat)
+				}
+			}
+		}
+	}
+
+	if len(c.decls) > 0 {
+		sb.WriteString("\nSymbols:\n")
+		for i, d := range c.decls {
+			if i == maxDeclSymbols {
+				fmt.Fprintf(&sb, "  - ... and %d more\n", len(c.decls)-maxDeclSymbols)
+				break
+			}
+			details := []string{declVisibility(c.lang, d.modifiers)}
+			// EXCEED MODE: modifiers and attributes beside the
+			// visibility, then the signature.
+			if profile == OutputProfileEnhancement {
+				for _, m := range declQualifierModifiers {
+					if slices.Contains(d.modifiers, m) {
+						details = append(details, m)
+					}
+				}
+				details = append(details, d.attributes...)
+			}
+			details = append(details, fmt.Sprintf("line %d", d.line))
+			fmt.Fprintf(&sb, "  - %s %s (%s)", d.kind, d.qualifiedName(), strings.Join(details, ", "))
+			if profile == OutputProfileEnhancement && d.params != "" {
+				sb.WriteString(": " + truncateRunes(d.signature(c.returnArrow), 120))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// EXCEED MODE: what each type extends or conforms to.
+	if profile == OutputProfileEnhancement {
+		var lines []string
+		for _, d := range c.decls {
+			if len(d.supertypes) > 0 {
+				lines = append(lines, fmt.Sprintf("%s: %s", d.qualifiedName(), strings.Join(d.supertypes, ", ")))
+			}
+		}
+		if len(lines) > 0 {
+			sb.WriteString("\nInheritance:\n")
+			for _, line := range lines[:min(len(lines), maxDeclSymbols)] {
+				fmt.Fprintf(&sb, "  - %s\n", line)
+			}
+		}
+	}
+
+	result := strings.TrimSpace(sb.String())
+	return ExploreResult{Summary: result, ExplorerUsed: c.explorer, TokenEstimate: estimateTokens(result), Facts: c.facts(visibilities)}
+}
+
+// facts returns every import and declaration, including those the summary
+// elides past maxDeclSymbols.
+func (c codeExploration) facts(visibilities map[string]int) *Facts {
+	f := &Facts{}
+	for _, imp := range c.imports {
+		f.Imports = append(f.Imports, imp.path)
+	}
+	for _, d := range c.decls {
+		f.Symbols = append(f.Symbols, SymbolInfo{Name: d.qualifiedName(), Kind: d.kind, StartLine: d.line})
+	}
+	f.count("declarations", len(c.decls))
+	f.count("imports", len(c.imports))
+	for v, n := range visibilities {
+		f.count(v, n)
+	}
+	return f
+}
+
+// codeDegradation describes a source file whose braces or literals do not
+// balance.
+func codeDegradation(lang string, content []byte, decls []codeDecl, err *codeScanError) degradedExploration {
+	line, col := lineColumn(content, int64(err.offset))
+	return degradedExploration{
+		Failed:   fmt.Sprintf("%s scanning at line %d, column %d: %s", lang, line, col, err.msg),
+		Progress: fmt.Sprintf("found %d declarations before the error", len(decls)),
+		Examined: int64(err.offset),
+		Size:     int64(len(content)),
+		NextSteps: []string{
+			"Compile the file to locate the syntax error",
+			"Read the raw content around the error with the view tool",
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package explorer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// degradedExploration describes an input an explorer could not parse.
+// Explorers render every parse failure through it, so summaries of broken
+// files share one shape regardless of format:
+//
+//	Degraded exploration:
+//	  Failed: <what failed>
+//	  Progress: <how far parsing got>
+//	  Bytes examined: <n> of <size>
+//	  Next steps:
+//	    - <suggestion>
+type degradedExploration struct {
+	// Failed names the step that failed, including the underlying error.
+	Failed string
+	// Progress tells how far parsing got before the failure.
+	Progress string
+	// Examined is the number of bytes read before giving up.
+	Examined int64
+	// Size is the size of the input in bytes.
+	Size int64
+	// NextSteps are suggestions for getting at the content anyway.
+	NextSteps []string
+}
+
+// write renders the block, preceded by a blank line, into sb.
+func (d degradedExploration) write(sb *strings.Builder) {
+	sb.WriteString("\nDegraded exploration:\n")
+	fmt.Fprintf(sb, "  Failed: %s\n", d.Failed)
+	fmt.Fprintf(sb, "  Progress: %s\n", d.Progress)
+	fmt.Fprintf(sb, "  Bytes examined: %d of %d\n", min(d.Examined, d.Size), d.Size)
+	if len(d.NextSteps) > 0 {
+		sb.WriteString("  Next steps:\n")
+		for _, step := range d.NextSteps {
+			fmt.Fprintf(sb, "    - %s\n", step)
+		}
+	}
+}
+
+// degradedTextResult is the result for a text format that failed to parse:
+// the header line, the degraded block and a sample of the raw content.
+func degradedTextResult(header, explorerUsed string, content []byte, d degradedExploration) ExploreResult {
+	var summary strings.Builder
+	summary.WriteString(header)
+	summary.WriteString("\n")
+	d.write(&summary)
+	sample, _ := sampleContent(content, 12000)
+	fmt.Fprintf(&summary, "\nContent (sampled):\n%s", sample)
+
+	result := summary.String()
+	return ExploreResult{Summary: result, ExplorerUsed: explorerUsed, TokenEstimate: estimateTokens(result)}
+}
+
+// lineColumn returns the 1-based line and column of byte offset in content.
+func lineColumn(content []byte, offset int64) (int, int) {
+	offset = max(0, min(offset, int64(len(content))))
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - (bytes.LastIndexByte(before, '\n') + 1) + 1
+	return line, col
+}
@@ -0,0 +1,143 @@
+package explorer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// degradedBlockPattern matches the degraded exploration block as an
+// explorer writes it, every field in schema order.
+var degradedBlockPattern = regexp.MustCompile(`\nDegraded exploration:\n  Failed: [^\n]+\n  Progress: [^\n]+\n  Bytes examined: \d+ of \d+\n  Next steps:\n(    - [^\n]+\n)+`)
+
+// formattedDegradedPattern matches the block after formatExploreResult,
+// which turns it into two sections and sorts the fields.
+var formattedDegradedPattern = regexp.MustCompile(`### Degraded exploration\n- Bytes examined: \d+ of \d+\n- Failed: [^\n]+\n- Progress: [^\n]+\n\n### Next steps\n(- [^\n]+\n)+`)
+
+// TestNegativePathGate is the negative-path gate: unparseable inputs of
+// every format with a parser must produce the degraded exploration block
+// instead of ad-hoc error text, in both output profiles.
+func TestNegativePathGate(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewDefaultParityFixtureConfig(".")
+	truncatedJSON, err := LoadFixtureFile(cfg, "negative_truncated.json")
+	require.NoError(t, err)
+
+	var truncatedTarGz bytes.Buffer
+	gw := gzip.NewWriter(&truncatedTarGz)
+	_, err = gw.Write(createTestTAR(t, map[string][]byte{"a.txt": bytes.Repeat([]byte("a"), 4096)}))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	tests := []struct {
+		name     string
+		path     string
+		content  []byte
+		explorer string
+	}{
+		{name: "truncated json fixture", path: "negative_truncated.json", content: truncatedJSON, explorer: "json"},
+		{name: "csv bare quote", path: "rows.csv", content: []byte("a,b\n1,\"2\n3,4\n"), explorer: "csv"},
+		{name: "yaml bad indent", path: "conf.yaml", content: []byte("a:\n  b: 1\n c: 2\n"), explorer: "yaml"},
+		{name: "xml unclosed", path: "feed.xml", content: []byte("<feed><entry><id>1</id></feed>"), explorer: "xml"},
+		{name: "zip without directory", path: "bundle.zip", content: []byte("PK\x03\x04truncated"), explorer: "archive"},
+		{name: "tar.gz truncated", path: "bundle.tar.gz", content: truncatedTarGz.Bytes()[:truncatedTarGz.Len()/2], explorer: "archive"},
+		{name: "tar.gz bad header", path: "bundle.tar.gz", content: []byte("not gzip at all"), explorer: "archive"},
+		{name: "deb bad magic", path: "pkg.deb", content: []byte("not an ar archive"), explorer: "archive"},
+		{name: "rpm short lead", path: "pkg.rpm", content: []byte{0xed, 0xab, 0xee, 0xdb}, explorer: "archive"},
+		{name: "docx not zip", path: "report.docx", content: []byte("not a zip"), explorer: "office"},
+		{name: "excalidraw truncated", path: "board.excalidraw", content: []byte(`{"elements":[{"type":`), explorer: "diagram"},
+		{name: "notebook truncated", path: "sales.ipynb", content: []byte(`{"cells": [{"cell_type": "code", "source": [`), explorer: "notebook"},
+		{name: "parquet without footer", path: "part.parquet", content: append([]byte("PAR1"), make([]byte, 32)...), explorer: "parquet"},
+		{name: "proto unterminated message", path: "api.proto", content: []byte("syntax = \"proto3\";\nmessage User {\n  string id = 1;\n"), explorer: "proto"},
+		{name: "graphql unterminated type", path: "schema.graphql", content: []byte("type Query {\n  user(id: ID!): User\n"), explorer: "graphql"},
+		{name: "kubernetes unterminated flow", path: "pod.yaml", content: []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: [broken\n"), explorer: "kubernetes"},
+		{name: "dockerfile unknown instruction", path: "Dockerfile", content: []byte("FROM alpine\nRUNN echo hi\n"), explorer: "dockerfile"},
+		{name: "package.json truncated", path: "package.json", content: []byte(`{"name": "web", "dependencies": {"react":`), explorer: "manifest"},
+		{name: "pom.xml unclosed", path: "pom.xml", content: []byte("<project>\n  <artifactId>app</artifactId>\n  <dependencies>\n</project>\n"), explorer: "manifest"},
+		{name: "build.gradle unclosed block", path: "build.gradle.kts", content: []byte("plugins {\n  java\n}\ndependencies {\n  implementation(\"a:b:1\")\n"), explorer: "manifest"},
+		{name: "go.sum missing hash", path: "go.sum", content: []byte("github.com/a/b v1.0.0\n"), explorer: "lockfile"},
+		{name: "Cargo.lock unterminated array", path: "Cargo.lock", content: []byte("[[package]]\nname = \"a\"\ndependencies = [\n \"b\",\n"), explorer: "lockfile"},
+		{name: "dotenv missing separator", path: ".env", content: []byte("API_URL=https://api.example.com\nexport PATH\n"), explorer: "dotenv"},
+		{name: "swift unclosed type", path: "Store.swift", content: []byte("public struct Store {\n  func load() {}\n"), explorer: "swift"},
+		{name: "kotlin unterminated string", path: "App.kt", content: []byte("fun main() {\n  println(\"hi)\n}\n"), explorer: "kotlin"},
+		{name: "php unterminated heredoc", path: "view.php", content: []byte("<?php\n$html = <<<HTML\n<p>hi</p>\n"), explorer: "php"},
+		{name: "csharp unclosed namespace", path: "App.cs", content: []byte("namespace App\n{\n    class A { }\n"), explorer: "csharp"},
+		{name: "git pack truncated", path: "pack-1.pack", content: []byte("PACK\x00\x00\x00\x02\x00\x00\x00\x03\x95\x0a\x78\x9c"), explorer: "git"},
+		{name: "sqlite garbage", path: "app.sqlite", content: []byte("not a database"), explorer: "sqlite"},
+	}
+
+	for _, profile := range []OutputProfile{OutputProfileEnhancement, OutputProfileParity} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		for _, tt := range tests {
+			t.Run(string(profile)+"/"+tt.name, func(t *testing.T) {
+				t.Parallel()
+				input := ExploreInput{Path: tt.path, Content: tt.content}
+				for _, e := range registry.explorers {
+					if !e.CanHandle(tt.path, tt.content) {
+						continue
+					}
+					raw, err := e.Explore(t.Context(), input)
+					require.NoError(t, err)
+					require.Regexp(t, degradedBlockPattern, raw.Summary)
+					break
+				}
+
+				result, err := registry.Explore(t.Context(), input)
+				require.NoError(t, err)
+				require.Equal(t, tt.explorer, result.ExplorerUsed)
+				require.Regexp(t, formattedDegradedPattern, result.Summary+"\n")
+				require.NotContains(t, result.Summary, "Error:")
+				require.NotContains(t, result.Summary, "Warning:")
+			})
+		}
+	}
+}
+
+func TestDegradedExplorationWrite(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	degradedExploration{
+		Failed:    "JSON decoding: unexpected end of JSON input",
+		Progress:  "input ends inside an unterminated value at line 1, column 31",
+		Examined:  40,
+		Size:      30,
+		NextSteps: []string{"Check whether the file was truncated"},
+	}.write(&sb)
+	require.Equal(t, `
+Degraded exploration:
+  Failed: JSON decoding: unexpected end of JSON input
+  Progress: input ends inside an unterminated value at line 1, column 31
+  Bytes examined: 30 of 30
+  Next steps:
+    - Check whether the file was truncated
+`, sb.String())
+}
+
+func TestJSONDegradationLocatesError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path     string
+		content  string
+		progress string
+		examined int64
+	}{
+		{path: "a.json", content: "{\n  \"a\": 1,\n  \"b\": ]\n}", progress: "valid up to the error at line 3, column 8", examined: 20},
+		{path: "a.jsonc", content: `{"key": "value", "incomplete":`, progress: "input ends inside an unterminated value at line 1, column 31", examined: 30},
+	}
+	for _, tt := range tests {
+		var v any
+		err := json.Unmarshal([]byte(tt.content), &v)
+		require.Error(t, err)
+		d := jsonDegradation(tt.path, []byte(tt.content), err)
+		require.Equal(t, tt.progress, d.Progress)
+		require.Equal(t, tt.examined, d.Examined)
+	}
+}
@@ -0,0 +1,180 @@
+package explorer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// determinismInput is a file explored by the determinism gate.
+type determinismInput struct {
+	path    string
+	content []byte
+}
+
+// determinismCorpus returns the parity fixtures plus synthetic files whose
+// summaries are built from maps with many keys, where random iteration
+// order would show up.
+func determinismCorpus(t *testing.T) []determinismInput {
+	t.Helper()
+
+	cfg := NewDefaultParityFixtureConfig(".")
+	index, err := LoadParityFixtureIndex(cfg)
+	require.NoError(t, err)
+
+	var corpus []determinismInput
+	for _, category := range []map[string]string{
+		index.Language, index.Format, index.Shell, index.Markdown, index.Binary, index.Negative,
+	} {
+		for _, name := range sortedKeys(category) {
+			content, err := LoadFixtureFile(cfg, category[name])
+			require.NoError(t, err)
+			corpus = append(corpus, determinismInput{path: category[name], content: content})
+		}
+	}
+
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	for i, name := range []string{"a.go", "b.md", "c.txt", "d.sh", "e.json", "f.yaml", "g.go", "h.txt"} {
+		content := []byte(strings.Repeat("x", i+1))
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:    "pkg/" + name,
+			Size:    int64(len(content)),
+			Mode:    int64(0o600 + i*0o11),
+			ModTime: time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC),
+			Uname:   fmt.Sprintf("user%d", i%5),
+		}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for i, name := range []string{"a.go", "b.md", "c.txt", "d.sh", "e.json", "f.yaml"} {
+		method := zip.Store
+		if i%2 == 0 {
+			method = zip.Deflate
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: "src/" + name, Method: method})
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(strings.Repeat(name, i+1)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	var jsonKeys, yamlKeys []string
+	for i := range 12 {
+		jsonKeys = append(jsonKeys, fmt.Sprintf(`"key%d": %d`, i, i))
+		yamlKeys = append(yamlKeys, fmt.Sprintf("key%d: %d", i, i))
+	}
+
+	return append(corpus,
+		determinismInput{path: "bundle.tar", content: tarBuf.Bytes()},
+		determinismInput{path: "bundle.zip", content: zipBuf.Bytes()},
+		determinismInput{path: "object.json", content: []byte("{" + strings.Join(jsonKeys, ", ") + "}")},
+		determinismInput{path: "values.yaml", content: []byte(strings.Join(yamlKeys, "\n") + "\n")},
+		determinismInput{path: "feed.xml", content: []byte(`<feed><title>t</title><entry><id>1</id><link/></entry><entry><id>2</id><author><name>n</name></author></entry><updated>u</updated></feed>`)},
+		determinismInput{path: "page.html", content: []byte(`<html><head><title>T</title><link rel="x"><style></style><script></script></head><body><div><p>a</p><span>b</span><a href="#">c</a><img src="x"><form><input><button>go</button></form></div></body></html>`)},
+		determinismInput{path: "board.excalidraw", content: []byte(`{"elements":[{"type":"rectangle"},{"type":"ellipse"},{"type":"arrow"},{"type":"text"},{"type":"line"},{"type":"diamond"},{"type":"freedraw"},{"type":"image"}]}`)},
+		determinismInput{path: "app.log", content: []byte("2024-01-01T00:00:00Z ERROR db down\n2024-01-01T00:00:01Z ERROR db down\n2024-01-01 00:00:02 WARN slow\nJan  1 00:00:03 host INFO ok\n2024-01-01T00:00:04Z DEBUG x\n2024-01-01T00:00:05Z FATAL boom\n2024-01-01T00:00:06Z TRACE y\n2024-01-01T00:00:07Z ERROR cache miss\n2024-01-01T00:00:08Z ERROR cache miss\n")},
+		determinismInput{path: "sales.ipynb", content: []byte(testNotebook)},
+		determinismInput{path: "change.diff", content: []byte(testGitDiff)},
+		determinismInput{path: "people.csv", content: []byte(testTable)},
+		determinismInput{path: "users.parquet", content: makeParquet()},
+		determinismInput{path: "events.arrow", content: makeArrowIPC()},
+		determinismInput{path: "billing.proto", content: []byte(testProto)},
+		determinismInput{path: "schema.graphql", content: []byte(testGraphQLSchema)},
+		determinismInput{path: "deploy.yaml", content: []byte(testKubernetesManifest)},
+		determinismInput{path: "Dockerfile", content: []byte(testDockerfile)},
+		determinismInput{path: "package.json", content: []byte(testPackageJSON)},
+		determinismInput{path: "pom.xml", content: []byte(testPOM)},
+		determinismInput{path: "build.gradle.kts", content: []byte(testGradleBuild)},
+		determinismInput{path: "package-lock.json", content: []byte(testPackageLock)},
+		determinismInput{path: "go.sum", content: []byte(testGoSum)},
+		determinismInput{path: "Cargo.lock", content: []byte(testCargoLock)},
+		determinismInput{path: "poetry.lock", content: []byte(testPoetryLock)},
+		determinismInput{path: ".env", content: []byte(testDotenv)},
+		determinismInput{path: "Sample.swift", content: []byte(testSwift)},
+		determinismInput{path: "Sample.kt", content: []byte(testKotlin)},
+		determinismInput{path: "User.php", content: []byte(testPHP)},
+		determinismInput{path: "UserStore.cs", content: []byte(testCSharp)},
+		determinismInput{path: "paper.tex", content: []byte("\\begin{figure}\\end{figure}\\begin{table}\\end{table}\\begin{equation}\\end{equation}\\begin{align}\\end{align}\\begin{itemize}\\end{itemize}\\begin{enumerate}\\end{enumerate}\\begin{theorem}\\end{theorem}\n")},
+		determinismInput{path: "notes.md", content: []byte("# Notes\n\n```go\nx\n```\n\n```python\ny\n```\n\n```sh\nz\n```\n\n```rust\nw\n```\n\n```ts\nv\n```\n")},
+		determinismInput{path: "script", content: []byte("#!/usr/bin/env ruby\nputs 1\n")},
+	)
+}
+
+// TestExplorerDeterminism is the determinism gate: every explorer runs
+// twice over the corpus in both output profiles and must produce identical
+// results. A failure means an explorer emits output while ranging over a
+// map; use the helpers in ordered.go instead.
+func TestExplorerDeterminism(t *testing.T) {
+	t.Parallel()
+
+	corpus := determinismCorpus(t)
+	for _, profile := range []OutputProfile{OutputProfileEnhancement, OutputProfileParity} {
+		t.Run(string(profile), func(t *testing.T) {
+			t.Parallel()
+
+			registry := NewRegistry(WithOutputProfile(profile))
+			covered := map[string]bool{}
+			for _, e := range registry.explorers {
+				name := fmt.Sprintf("%T", e)
+				for _, in := range corpus {
+					if !e.CanHandle(in.path, in.content) {
+						continue
+					}
+					covered[name] = true
+					input := ExploreInput{Path: in.path, Content: in.content}
+					first, err1 := e.Explore(t.Context(), input)
+					second, err2 := e.Explore(t.Context(), input)
+					require.Equal(t, err1, err2, "%s on %s", name, filepath.Base(in.path))
+					require.Equal(t, first, second, "%s on %s", name, filepath.Base(in.path))
+				}
+			}
+			for _, name := range []string{"*explorer.ArchiveExplorer", "*explorer.JSONExplorer", "*explorer.XMLExplorer", "*explorer.HTMLExplorer", "*explorer.DiagramExplorer", "*explorer.LogsExplorer", "*explorer.LatexExplorer", "*explorer.MarkdownExplorer"} {
+				require.True(t, covered[name], "%s is not exercised by the corpus", name)
+			}
+
+			for _, in := range corpus {
+				input := ExploreInput{Path: in.path, Content: in.content}
+				first, err1 := registry.Explore(t.Context(), input)
+				second, err2 := registry.Explore(t.Context(), input)
+				require.Equal(t, err1, err2, in.path)
+				require.Equal(t, first, second, in.path)
+			}
+		})
+	}
+}
+
+func TestOrderedHelpers(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"a", "b", "c"}, sortedKeys(map[string]int{"c": 1, "a": 2, "b": 3}))
+	require.Equal(t, []string{"python3", "python", "py", "r"}, keysLongestFirst(map[string]bool{"r": true, "py": true, "python": true, "python3": true}))
+	require.Equal(t, []countEntry{{"b", 3}, {"a", 1}, {"c", 1}}, byCount(map[string]int{"c": 1, "a": 1, "b": 3}))
+
+	var sb strings.Builder
+	writeCounts(&sb, map[string]int{"x": 1, "y": 2}, " files")
+	require.Equal(t, "  - y: 2 files\n  - x: 1 files\n", sb.String())
+
+	require.Equal(t, "ruby", detectShebang([]byte("#!/usr/bin/env ruby\n")))
+
+	depths := []int{0, 1, 1, 0}
+	for range 9 {
+		depths = append(depths, 0)
+	}
+	numbers := outlineNumbers(depths)
+	require.Equal(t, []string{"01", "01.1", "01.2", "02", "03"}, numbers[:5])
+	require.Equal(t, "11", numbers[len(numbers)-1])
+	require.True(t, slices.IsSorted(numbers))
+}
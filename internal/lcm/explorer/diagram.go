@@ -287,8 +287,11 @@ func exploreExcalidraw(summary *strings.Builder, content []byte) {
 	}
 
 	if err := json.Unmarshal(content, &data); err != nil {
+		d := jsonDegradation("", content, err)
+		d.NextSteps = append(d.NextSteps, "Re-export the drawing from Excalidraw")
+		d.write(summary)
 		text, _ := sampleContent(content, 2000)
-		fmt.Fprintf(summary, "Content (parse error, sampled):\n%s\n", text)
+		fmt.Fprintf(summary, "\nContent (sampled):\n%s\n", text)
 		return
 	}
 
@@ -301,9 +304,7 @@ func exploreExcalidraw(summary *strings.Builder, content []byte) {
 	fmt.Fprintf(summary, "Elements: %d\n", totalElements)
 	if len(typeCounts) > 0 {
 		summary.WriteString("\nElement types:\n")
-		for typ, count := range typeCounts {
-			fmt.Fprintf(summary, "  - %s: %d\n", typ, count)
-		}
+		writeCounts(summary, typeCounts, "")
 	}
 }
 
@@ -0,0 +1,271 @@
+package explorer
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DiffExplorer explores unified diffs and patches (git diff, diff -u,
+// format-patch mail) and the .rej files patch leaves for failed hunks.
+type DiffExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// diffLargestShown is the number of files listed by churn in enhancement
+// output.
+const diffLargestShown = 5
+
+// diffFile is the change to one file in a diff.
+type diffFile struct {
+	path      string
+	from      string // the old path of a rename or copy
+	status    string // added, deleted, modified, renamed, copied, binary
+	additions int
+	deletions int
+	hunks     int
+}
+
+func (e *DiffExplorer) CanHandle(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".diff", ".patch", ".rej":
+		return true
+	}
+	return looksLikeUnifiedDiff(content)
+}
+
+// looksLikeUnifiedDiff reports whether content starts like a diff: a git
+// header, a ---/+++ file pair, or a format-patch mail. Only the start is
+// checked so documents that quote a diff keep their own explorer.
+func looksLikeUnifiedDiff(content []byte) bool {
+	head := content[:min(len(content), SampleChunkSize)]
+	switch {
+	case bytes.HasPrefix(head, []byte("diff --git ")):
+		return true
+	case bytes.HasPrefix(head, []byte("--- ")):
+		return bytes.Contains(head, []byte("\n+++ ")) && bytes.Contains(head, []byte("\n@@ "))
+	case bytes.HasPrefix(head, []byte("From ")):
+		return bytes.Contains(head, []byte("\nSubject: ")) && bytes.Contains(head, []byte("\ndiff --git "))
+	}
+	return false
+}
+
+func (e *DiffExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	var summary strings.Builder
+	name := filepath.Base(input.Path)
+	// Reject files may hold bare hunks without file headers; they belong
+	// to the file the .rej is named after.
+	target := ""
+	if ext := filepath.Ext(name); strings.EqualFold(ext, ".rej") {
+		target = strings.TrimSuffix(name, ext)
+		fmt.Fprintf(&summary, "Rejected hunks: %s\n", name)
+	} else {
+		fmt.Fprintf(&summary, "Unified diff: %s\n", name)
+	}
+	files := parseUnifiedDiff(input.Content, target)
+
+	additions, deletions, hunks := 0, 0, 0
+	statuses := make(map[string]int)
+	exts := make(map[string]int)
+	for _, f := range files {
+		additions += f.additions
+		deletions += f.deletions
+		hunks += f.hunks
+		statuses[f.status]++
+		exts[cmp.Or(strings.ToLower(filepath.Ext(f.path)), "(none)")]++
+	}
+	fmt.Fprintf(&summary, "Files changed: %d\n", len(files))
+	fmt.Fprintf(&summary, "Lines added: %d\n", additions)
+	fmt.Fprintf(&summary, "Lines removed: %d\n", deletions)
+	fmt.Fprintf(&summary, "Hunks: %d\n", hunks)
+
+	if len(files) > 0 {
+		summary.WriteString("\nFiles:\n")
+		for _, f := range files {
+			path := f.path
+			if f.from != "" && f.from != f.path {
+				path = f.from + " -> " + f.path
+			}
+			fmt.Fprintf(&summary, "  - %s (%s, +%d -%d%s)\n", path, f.status, f.additions, f.deletions, hunkCount(f.hunks))
+		}
+		summary.WriteString("\nChange types:\n")
+		writeCounts(&summary, statuses, "")
+	}
+
+	// EXCEED MODE: where the churn is.
+	if e.formatterProfile == OutputProfileEnhancement && len(files) > 0 {
+		summary.WriteString("\nFile types:\n")
+		writeCounts(&summary, exts, "")
+
+		byChurn := slices.Clone(files)
+		slices.SortStableFunc(byChurn, func(a, b diffFile) int {
+			return cmp.Compare(b.additions+b.deletions, a.additions+a.deletions)
+		})
+		summary.WriteString("\nLargest changes:\n")
+		for _, f := range byChurn[:min(len(byChurn), diffLargestShown)] {
+			fmt.Fprintf(&summary, "  - %s: %s\n", f.path, lineCount(f.additions+f.deletions))
+		}
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "diff",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// parseUnifiedDiff splits a unified diff into per-file changes, in the
+// order they appear. Hunks before any file header are credited to
+// headerless, the file a reject file is named after, or skipped when it
+// is empty.
+func parseUnifiedDiff(content []byte, headerless string) []diffFile {
+	var files []diffFile
+	var cur *diffFile
+	// headerSeen is set once the current file has its --- line; another
+	// one starts the next file of a plain (non-git) diff.
+	headerSeen := false
+	start := func(path, status string) {
+		files = append(files, diffFile{path: path, status: status})
+		cur = &files[len(files)-1]
+		headerSeen = false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	// Lines left in the current hunk, from its @@ header.
+	oldLeft, newLeft := 0, 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "diff --git ") {
+			// A miscounted hunk must not swallow the next file.
+			oldLeft, newLeft = 0, 0
+		}
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				cur.additions++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				cur.deletions++
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			_, b, _ := strings.Cut(strings.TrimPrefix(line, "diff --git "), " b/")
+			start(b, "modified")
+		case strings.HasPrefix(line, "--- "):
+			old := diffPath(strings.TrimPrefix(line, "--- "))
+			if cur == nil || headerSeen {
+				start(old, "modified")
+			}
+			headerSeen = true
+			if old == "/dev/null" {
+				cur.status = "added"
+			}
+		case strings.HasPrefix(line, "@@ ") && cur == nil && headerless != "":
+			start(headerless, "modified")
+			cur.hunks++
+			oldLeft, newLeft = hunkLengths(line)
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "@@ "):
+			cur.hunks++
+			oldLeft, newLeft = hunkLengths(line)
+		case strings.HasPrefix(line, "+++ "):
+			if path := diffPath(strings.TrimPrefix(line, "+++ ")); path == "/dev/null" {
+				cur.status = "deleted"
+			} else {
+				cur.path = path
+			}
+		case strings.HasPrefix(line, "new file mode"):
+			cur.status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.status = "deleted"
+		case strings.HasPrefix(line, "rename from "):
+			cur.from = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			cur.status = "renamed"
+			cur.path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "copy from "):
+			cur.from = strings.TrimPrefix(line, "copy from ")
+		case strings.HasPrefix(line, "copy to "):
+			cur.status = "copied"
+			cur.path = strings.TrimPrefix(line, "copy to ")
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			cur.status = "binary"
+		}
+	}
+	return files
+}
+
+// hunkCount renders the hunk count of a file line; files without hunks
+// (binary or mode-only changes) get none.
+func hunkCount(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return ", 1 hunk"
+	}
+	return fmt.Sprintf(", %d hunks", n)
+}
+
+func lineCount(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
+
+// hunkLengths returns the old and new line counts of an
+// "@@ -l,s +l,s @@" header. An omitted count means one line.
+func hunkLengths(header string) (int, int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	length := func(r string) int {
+		_, n, ok := strings.Cut(r[1:], ",")
+		if !ok {
+			return 1
+		}
+		v, err := strconv.Atoi(n)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	return length(fields[1]), length(fields[2])
+}
+
+// diffPath strips the a/ or b/ prefix and any trailing timestamp from a
+// ---/+++ header path.
+func diffPath(s string) string {
+	if before, _, ok := strings.Cut(s, "\t"); ok {
+		s = before
+	}
+	if s == "/dev/null" {
+		return s
+	}
+	for _, prefix := range []string{"a/", "b/"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			return rest
+		}
+	}
+	return s
+}
@@ -0,0 +1,173 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGitDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,5 @@
+ package main
+-
+-func main() {}
++
++func main() {
++	run()
++}
+diff --git a/docs/old.md b/docs/new.md
+similarity index 90%
+rename from docs/old.md
+rename to docs/new.md
+--- a/docs/old.md
++++ b/docs/new.md
+@@ -1 +1 @@
+--- title
++-- heading
+diff --git a/added.txt b/added.txt
+new file mode 100644
+--- /dev/null
++++ b/added.txt
+@@ -0,0 +1,2 @@
++one
++two
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestDiffExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &DiffExplorer{}
+	require.True(t, e.CanHandle("fix.patch", nil))
+	require.True(t, e.CanHandle("changes.DIFF", nil))
+	require.True(t, e.CanHandle("main.go.rej", nil))
+	require.True(t, e.CanHandle("stdin", []byte(testGitDiff)))
+	require.True(t, e.CanHandle("out", []byte("--- a.txt\n+++ b.txt\n@@ -1 +1 @@\n-a\n+b\n")))
+	require.True(t, e.CanHandle("0001", []byte("From abc Mon Sep 17 00:00:00 2001\nSubject: [PATCH] fix\n\ndiff --git a/x b/x\n")))
+	require.False(t, e.CanHandle("notes.txt", []byte("Some prose.\n\ndiff --git a/x b/x\n")))
+	require.False(t, e.CanHandle("notes.txt", []byte("--- \ntitle: front matter\n---\n")))
+}
+
+func TestDiffExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &DiffExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "change.diff", Content: []byte(testGitDiff)})
+	require.NoError(t, err)
+	require.Equal(t, "diff", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Unified diff: change.diff\n")
+	require.Contains(t, s, "Files changed: 5\n")
+	require.Contains(t, s, "Lines added: 7\n")
+	require.Contains(t, s, "Lines removed: 4\n")
+	require.Contains(t, s, "Hunks: 4\n")
+	require.Contains(t, s, "  - main.go (modified, +4 -2, 1 hunk)\n")
+	require.Contains(t, s, "  - docs/old.md -> docs/new.md (renamed, +1 -1, 1 hunk)\n")
+	require.Contains(t, s, "  - added.txt (added, +2 -0, 1 hunk)\n")
+	require.Contains(t, s, "  - gone.txt (deleted, +0 -1, 1 hunk)\n")
+	require.Contains(t, s, "  - logo.png (binary, +0 -0)\n")
+	require.Contains(t, s, "Change types:\n"+
+		"  - added: 1\n"+
+		"  - binary: 1\n"+
+		"  - deleted: 1\n"+
+		"  - modified: 1\n"+
+		"  - renamed: 1\n")
+}
+
+func TestDiffExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	input := ExploreInput{Path: "change.diff", Content: []byte(testGitDiff)}
+	parity, err := (&DiffExplorer{formatterProfile: OutputProfileParity}).Explore(context.Background(), input)
+	require.NoError(t, err)
+	result, err := (&DiffExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), input)
+	require.NoError(t, err)
+
+	// Renamed files are typed and sized by their new path; the binary
+	// file has no changed lines.
+	extra, ok := strings.CutPrefix(result.Summary, parity.Summary)
+	require.True(t, ok, result.Summary)
+	require.Equal(t, "\nFile types:\n"+
+		"  - .txt: 2\n"+
+		"  - .go: 1\n"+
+		"  - .md: 1\n"+
+		"  - .png: 1\n"+
+		"\nLargest changes:\n"+
+		"  - main.go: 6 lines\n"+
+		"  - docs/new.md: 2 lines\n"+
+		"  - added.txt: 2 lines\n"+
+		"  - gone.txt: 1 line\n"+
+		"  - logo.png: 0 lines\n", extra)
+}
+
+func TestDiffExplorer_PlainDiff(t *testing.T) {
+	t.Parallel()
+
+	content := "--- a.txt\t2024-01-01 00:00:00\n+++ a.txt\t2024-01-02 00:00:00\n@@ -1,2 +1,2 @@\n-old\n+new\n same\n--- b.txt\n+++ b.txt\n@@ -1 +1,2 @@\n keep\n+more\n"
+	files := parseUnifiedDiff([]byte(content), "")
+	require.Equal(t, []diffFile{
+		{path: "a.txt", status: "modified", additions: 1, deletions: 1, hunks: 1},
+		{path: "b.txt", status: "modified", additions: 1, hunks: 1},
+	}, files)
+}
+
+func TestDiffExplorer_RenamesAndCopies(t *testing.T) {
+	t.Parallel()
+
+	content := "diff --git a/a.go b/b.go\nsimilarity index 100%\nrename from a.go\nrename to b.go\n" +
+		"diff --git a/c.go b/d.go\nsimilarity index 80%\ncopy from c.go\ncopy to d.go\n--- a/c.go\n+++ b/d.go\n@@ -1,2 +1,2 @@\n-x\n+y\n z\n@@ -9 +9 @@\n-p\n+q\n"
+	require.Equal(t, []diffFile{
+		{path: "b.go", from: "a.go", status: "renamed"},
+		{path: "d.go", from: "c.go", status: "copied", additions: 2, deletions: 2, hunks: 2},
+	}, parseUnifiedDiff([]byte(content), ""))
+
+	result, err := (&DiffExplorer{}).Explore(context.Background(), ExploreInput{Path: "x.diff", Content: []byte(content)})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "  - a.go -> b.go (renamed, +0 -0)\n")
+	require.Contains(t, result.Summary, "  - c.go -> d.go (copied, +2 -2, 2 hunks)\n")
+}
+
+func TestDiffExplorer_RejectFile(t *testing.T) {
+	t.Parallel()
+
+	// patch writes bare hunks when the target has no header of its own.
+	content := "@@ -3,2 +3,2 @@\n ctx\n-old\n+new\n@@ -20 +20,2 @@\n keep\n+added\n"
+	result, err := NewRegistry().Explore(context.Background(), ExploreInput{Path: "src/main.go.rej", Content: []byte(content)})
+	require.NoError(t, err)
+	require.Equal(t, "diff", result.ExplorerUsed)
+	require.Contains(t, result.Summary, "Rejected hunks: main.go.rej\n")
+	require.Contains(t, result.Summary, "Hunks: 2\n")
+	require.Contains(t, result.Summary, "- main.go (modified, +2 -1, 2 hunks)\n")
+
+	result, err = NewRegistry().Explore(context.Background(), ExploreInput{Path: "MAIN.GO.REJ", Content: []byte(content)})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "- MAIN.GO (modified, +2 -1, 2 hunks)\n")
+
+	// Outside reject files, hunks without a file header are skipped.
+	require.Empty(t, parseUnifiedDiff([]byte(content), ""))
+}
+
+func TestDiffExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "change.patch", Content: []byte(testGitDiff)})
+		require.NoError(t, err)
+		require.Equal(t, "diff", result.ExplorerUsed)
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "Largest changes"))
+	}
+}
@@ -0,0 +1,122 @@
+package explorer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// docCommentMaxTokens caps the summary of a single doc comment.
+	docCommentMaxTokens = 40
+	// docCommentBudgetTokens caps all doc comment summaries of one file.
+	docCommentBudgetTokens = 600
+)
+
+// symbolDoc pairs an exported symbol with its raw leading doc comment.
+type symbolDoc struct {
+	Name string
+	Raw  string
+}
+
+// docCommentLanguage reports whether doc comments of lang are summarized.
+func docCommentLanguage(lang string) bool {
+	switch lang {
+	case "go", "python", "typescript", "tsx":
+		return true
+	}
+	return false
+}
+
+// writeDocComments writes the first sentence of each symbol's doc comment.
+// Once the file's token budget is spent the remaining symbols are counted
+// instead of listed.
+func writeDocComments(summary *strings.Builder, lang string, docs []symbolDoc) {
+	if !docCommentLanguage(lang) {
+		return
+	}
+	var lines []string
+	used, omitted := 0, 0
+	for _, d := range docs {
+		text := docCommentSummary(lang, d.Raw)
+		if text == "" {
+			continue
+		}
+		line := d.Name + ": " + text
+		if used+estimateTokens(line) > docCommentBudgetTokens {
+			omitted++
+			continue
+		}
+		used += estimateTokens(line)
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	summary.WriteString("\nDoc comments:\n")
+	for _, line := range lines {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+	if omitted > 0 {
+		fmt.Fprintf(summary, "  - ... %d more omitted (doc budget %d tokens)\n", omitted, docCommentBudgetTokens)
+	}
+}
+
+// docCommentSummary strips comment markers from a raw doc comment and
+// returns its first sentence, capped at docCommentMaxTokens.
+func docCommentSummary(lang, raw string) string {
+	var words []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		line = strings.TrimSpace(line)
+		switch lang {
+		case "go":
+			line = strings.TrimPrefix(line, "//")
+			line = strings.TrimPrefix(line, "/*")
+			line = strings.TrimSuffix(line, "*/")
+		case "typescript", "tsx":
+			line = strings.TrimPrefix(line, "//")
+			line = strings.TrimPrefix(line, "/**")
+			line = strings.TrimPrefix(line, "/*")
+			line = strings.TrimSuffix(line, "*/")
+			line = strings.TrimPrefix(strings.TrimSpace(line), "*")
+		case "python":
+			for _, q := range []string{`"""`, `'''`} {
+				line = strings.TrimPrefix(line, q)
+				line = strings.TrimSuffix(line, q)
+			}
+		}
+		line = strings.TrimSpace(line)
+		// The summary ends at the first paragraph break or JSDoc tag.
+		if line == "" && len(words) > 0 {
+			break
+		}
+		if strings.HasPrefix(line, "@") && (lang == "typescript" || lang == "tsx") {
+			break
+		}
+		words = append(words, strings.Fields(line)...)
+	}
+
+	text := firstSentence(strings.Join(words, " "))
+	if estimateTokens(text) <= docCommentMaxTokens {
+		return text
+	}
+	runes := []rune(text)[:docCommentMaxTokens*4-3]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "..."
+}
+
+// firstSentence returns text up to and including the first sentence-ending
+// punctuation followed by a space.
+func firstSentence(text string) string {
+	for i := 0; i < len(text)-1; i++ {
+		switch text[i] {
+		case '.', '!', '?':
+			if text[i+1] == ' ' {
+				return text[:i+1]
+			}
+		}
+	}
+	return text
+}
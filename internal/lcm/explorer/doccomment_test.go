@@ -0,0 +1,53 @@
+package explorer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocCommentSummary(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		lang string
+		raw  string
+		want string
+	}{
+		{lang: "go", raw: "// Parse reads a config file. Unknown keys are ignored.", want: "Parse reads a config file."},
+		{lang: "go", raw: "// Parse reads a config\n// file from disk.\n//\n// Details follow.", want: "Parse reads a config file from disk."},
+		{lang: "go", raw: "/* Open opens the store */", want: "Open opens the store"},
+		{lang: "typescript", raw: "/**\n * Fetches a user by id.\n * @param id the user id\n */", want: "Fetches a user by id."},
+		{lang: "tsx", raw: "/** Renders the header! Used on every page. */", want: "Renders the header!"},
+		{lang: "python", raw: "Return the parsed rows.\n\n    Raises ValueError on bad input.\n    ", want: "Return the parsed rows."},
+		{lang: "python", raw: `"""Load settings."""`, want: "Load settings."},
+	} {
+		require.Equal(t, tt.want, docCommentSummary(tt.lang, tt.raw), tt.raw)
+	}
+
+	long := "// " + strings.Repeat("word ", 60)
+	got := docCommentSummary("go", long)
+	require.True(t, strings.HasSuffix(got, "..."))
+	require.LessOrEqual(t, estimateTokens(got), docCommentMaxTokens)
+}
+
+func TestWriteDocCommentsBudget(t *testing.T) {
+	t.Parallel()
+
+	var docs []symbolDoc
+	for i := range 100 {
+		docs = append(docs, symbolDoc{Name: fmt.Sprintf("Func%d", i), Raw: "// " + strings.Repeat("Does things ", 8) + "."})
+	}
+	var sb strings.Builder
+	writeDocComments(&sb, "go", docs)
+	out := sb.String()
+	require.True(t, strings.HasPrefix(out, "\nDoc comments:\n  - Func0: Does things"))
+	require.Regexp(t, `  - \.\.\. \d+ more omitted \(doc budget 600 tokens\)\n$`, out)
+	require.LessOrEqual(t, estimateTokens(out), docCommentBudgetTokens+20)
+
+	sb.Reset()
+	writeDocComments(&sb, "rust", docs)
+	require.Empty(t, sb.String())
+}
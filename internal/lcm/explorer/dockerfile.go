@@ -0,0 +1,573 @@
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DockerfileExplorer explores Dockerfiles and Containerfiles: build stages,
+// build args, exposed ports, COPY/ADD sources, RUN commands and the runtime
+// configuration of the final stage.
+type DockerfileExplorer struct {
+	formatterProfile OutputProfile
+}
+
+const (
+	// dockerfileMaxDetails caps the lines listed per section.
+	dockerfileMaxDetails = 200
+	// dockerfileRunMaxRunes caps a RUN command in the summary.
+	dockerfileRunMaxRunes = 100
+)
+
+// dockerfileInstructions are the instructions the Dockerfile frontend
+// accepts.
+var dockerfileInstructions = map[string]bool{
+	"FROM": true, "RUN": true, "CMD": true, "LABEL": true, "MAINTAINER": true,
+	"EXPOSE": true, "ENV": true, "ADD": true, "COPY": true, "ENTRYPOINT": true,
+	"VOLUME": true, "USER": true, "WORKDIR": true, "ARG": true, "ONBUILD": true,
+	"STOPSIGNAL": true, "HEALTHCHECK": true, "SHELL": true,
+}
+
+var (
+	dockerfileDirectivePattern = regexp.MustCompile(`^#\s*([a-zA-Z]+)\s*=\s*(\S+)\s*$`)
+	dockerfileHeredocPattern   = regexp.MustCompile(`<<(-?)["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+	dockerfileSecretPattern    = regexp.MustCompile(`(?i)(passw(or)?d|secret|token|api_?key|private_?key|credential)`)
+	// dockerfilePackagePattern matches package installs; the packages are
+	// the words up to the next shell separator.
+	dockerfilePackagePattern = regexp.MustCompile(`\b(apt-get|apt|apk|yum|dnf|microdnf|pip3?|npm|gem|go)\s+(?:(?:-\S+|--\S+)\s+)*(install|add|ci)\b([^;&|]*)`)
+)
+
+type dockerfile struct {
+	directives   []string
+	globalArgs   []string
+	stages       []*dockerStage
+	instructions int
+}
+
+type dockerStage struct {
+	index      int
+	name       string
+	base       string
+	platform   string
+	line       int
+	args       []string
+	env        []string
+	ports      []string
+	copies     []dockerCopy
+	runs       []dockerRun
+	volumes    []string
+	user       string
+	workdir    string
+	entrypoint string
+	cmd        string
+	healthchk  string
+}
+
+// label names the stage by its alias, or by its index when unnamed.
+func (s *dockerStage) label() string {
+	if s.name != "" {
+		return s.name
+	}
+	return fmt.Sprintf("stage %d", s.index)
+}
+
+type dockerCopy struct {
+	instruction string // COPY or ADD
+	from        string
+	sources     []string
+	dest        string
+	line        int
+}
+
+type dockerRun struct {
+	command string
+	line    int
+}
+
+// dockerfileSyntaxError is a parse error on a 1-based line.
+type dockerfileSyntaxError struct {
+	line int
+	msg  string
+}
+
+func (e *dockerfileSyntaxError) Error() string { return e.msg }
+
+func (e *DockerfileExplorer) CanHandle(path string, content []byte) bool {
+	base := strings.ToLower(filepath.Base(path))
+	for _, name := range []string{"dockerfile", "containerfile"} {
+		if base == name || strings.HasPrefix(base, name+".") || strings.HasSuffix(base, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *DockerfileExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Dockerfile too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "dockerfile", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	df, err := parseDockerfile(input.Content)
+	if err != nil {
+		return degradedTextResult("Dockerfile: "+name, "dockerfile", input.Content, dockerfileDegradation(input.Content, df, err)), nil
+	}
+	final := df.stages[len(df.stages)-1]
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Dockerfile: %s\n", name)
+	fmt.Fprintf(&summary, "Stages: %d (final: %s from %s)\n", len(df.stages), final.label(), final.base)
+	fmt.Fprintf(&summary, "Instructions: %d\n", df.instructions)
+	if len(df.directives) > 0 {
+		fmt.Fprintf(&summary, "Parser directives: %s\n", strings.Join(df.directives, ", "))
+	}
+
+	var lines []string
+	for _, s := range df.stages {
+		line := fmt.Sprintf("%s: %s (line %d", s.label(), s.base, s.line)
+		if s.platform != "" {
+			line += ", platform " + s.platform
+		}
+		if s == final {
+			line += ", final"
+		}
+		lines = append(lines, line+")")
+	}
+	writeDockerfileSection(&summary, "Build stages", lines)
+
+	lines = nil
+	for _, arg := range df.globalArgs {
+		lines = append(lines, arg+" (global)")
+	}
+	for _, s := range df.stages {
+		for _, arg := range s.args {
+			lines = append(lines, fmt.Sprintf("%s (%s)", arg, s.label()))
+		}
+	}
+	writeDockerfileSection(&summary, "Build args", lines)
+
+	lines = nil
+	for _, s := range df.stages {
+		for _, port := range s.ports {
+			lines = append(lines, fmt.Sprintf("%s (%s)", port, s.label()))
+		}
+	}
+	writeDockerfileSection(&summary, "Exposed ports", lines)
+
+	lines = nil
+	for _, s := range df.stages {
+		for _, c := range s.copies {
+			line := c.instruction
+			if c.from != "" {
+				line += " --from=" + c.from
+			}
+			lines = append(lines, fmt.Sprintf("%s %s -> %s (%s, line %d)", line, strings.Join(c.sources, ", "), c.dest, s.label(), c.line))
+		}
+	}
+	writeDockerfileSection(&summary, "Copied sources", lines)
+
+	lines = nil
+	for _, s := range df.stages {
+		for _, run := range s.runs {
+			lines = append(lines, fmt.Sprintf("%s: %s (line %d)", s.label(), truncateRunes(run.command, dockerfileRunMaxRunes), run.line))
+		}
+	}
+	writeDockerfileSection(&summary, "Run commands", lines)
+
+	// Only names are listed: ENV values are often credentials.
+	lines = nil
+	for _, s := range df.stages {
+		for _, key := range s.env {
+			lines = append(lines, fmt.Sprintf("%s (%s)", key, s.label()))
+		}
+	}
+	writeDockerfileSection(&summary, "Environment", lines)
+
+	lines = nil
+	if final.user != "" {
+		lines = append(lines, "user "+final.user)
+	} else {
+		lines = append(lines, "user root (no USER instruction)")
+	}
+	if final.workdir != "" {
+		lines = append(lines, "workdir "+final.workdir)
+	}
+	if final.entrypoint != "" {
+		lines = append(lines, "entrypoint "+final.entrypoint)
+	}
+	if final.cmd != "" {
+		lines = append(lines, "cmd "+final.cmd)
+	}
+	if final.healthchk != "" {
+		lines = append(lines, "healthcheck "+final.healthchk)
+	}
+	if len(final.volumes) > 0 {
+		lines = append(lines, "volumes "+strings.Join(final.volumes, ", "))
+	}
+	writeDockerfileSection(&summary, "Final stage", lines)
+
+	// EXCEED MODE: how stages feed each other, installed packages and
+	// build hygiene findings.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeDockerfileDependencies(&summary, df)
+		writeDockerfilePackages(&summary, df)
+		writeDockerfileFindings(&summary, df)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "dockerfile",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// parseDockerfile parses the instructions of a Dockerfile. On error the
+// partially parsed file is returned with it.
+func parseDockerfile(content []byte) (*dockerfile, error) {
+	df := &dockerfile{}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	escape := `\`
+
+	// Parser directives are only honoured before any other line.
+	i := 0
+	for ; i < len(lines); i++ {
+		m := dockerfileDirectivePattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			break
+		}
+		key := strings.ToLower(m[1])
+		df.directives = append(df.directives, key+"="+m[2])
+		if key == "escape" && m[2] == "`" {
+			escape = "`"
+		}
+	}
+
+	for ; i < len(lines); i++ {
+		start := i + 1
+		text := strings.TrimSpace(lines[i])
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		for strings.HasSuffix(text, escape) {
+			text = strings.TrimSuffix(text, escape)
+			i++
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+				i++
+			}
+			if i >= len(lines) {
+				return df, &dockerfileSyntaxError{line: start, msg: "line continuation runs past the end of the file"}
+			}
+			text += " " + strings.TrimSpace(lines[i])
+		}
+
+		keyword, args, _ := strings.Cut(text, " ")
+		keyword = strings.ToUpper(keyword)
+		args = strings.TrimSpace(args)
+		if !dockerfileInstructions[keyword] {
+			return df, &dockerfileSyntaxError{line: start, msg: fmt.Sprintf("unknown instruction %q", keyword)}
+		}
+
+		if m := dockerfileHeredocPattern.FindStringSubmatch(args); m != nil && (keyword == "RUN" || keyword == "COPY" || keyword == "ADD") {
+			var body []string
+			terminated := false
+			for i++; i < len(lines); i++ {
+				line := lines[i]
+				if m[1] == "-" {
+					line = strings.TrimLeft(line, "\t")
+				}
+				if line == m[2] {
+					terminated = true
+					break
+				}
+				if trimmed := strings.TrimSpace(line); trimmed != "" {
+					body = append(body, trimmed)
+				}
+			}
+			if !terminated {
+				return df, &dockerfileSyntaxError{line: start, msg: fmt.Sprintf("heredoc %s is never terminated", m[2])}
+			}
+			if keyword == "RUN" {
+				args = strings.TrimSpace(strings.Replace(args, m[0], "", 1))
+				args = strings.TrimSpace(args + " " + strings.Join(body, "; "))
+			}
+		}
+
+		df.instructions++
+		if keyword == "ARG" && len(df.stages) == 0 {
+			df.globalArgs = append(df.globalArgs, dockerWords(args)...)
+			continue
+		}
+		if keyword != "FROM" && len(df.stages) == 0 {
+			return df, &dockerfileSyntaxError{line: start, msg: keyword + " instruction before the first FROM"}
+		}
+		df.apply(keyword, args, start)
+	}
+
+	if len(df.stages) == 0 {
+		return df, &dockerfileSyntaxError{line: len(lines), msg: "no FROM instruction"}
+	}
+	return df, nil
+}
+
+// apply records one instruction on the current stage.
+func (df *dockerfile) apply(keyword, args string, line int) {
+	if keyword == "FROM" {
+		s := &dockerStage{index: len(df.stages), line: line}
+		words := dockerWords(args)
+		for j := 0; j < len(words); j++ {
+			switch {
+			case strings.HasPrefix(words[j], "--platform="):
+				s.platform = strings.TrimPrefix(words[j], "--platform=")
+			case strings.HasPrefix(words[j], "--"):
+			case s.base == "":
+				s.base = words[j]
+			case strings.EqualFold(words[j], "as") && j+1 < len(words):
+				s.name = words[j+1]
+				j++
+			}
+		}
+		df.stages = append(df.stages, s)
+		return
+	}
+
+	s := df.stages[len(df.stages)-1]
+	switch keyword {
+	case "ARG":
+		s.args = append(s.args, dockerWords(args)...)
+	case "ENV":
+		words := dockerWords(args)
+		if len(words) > 0 && !strings.Contains(words[0], "=") {
+			// Legacy "ENV KEY value" form.
+			s.env = append(s.env, words[0])
+			break
+		}
+		for _, w := range words {
+			if key, _, ok := strings.Cut(w, "="); ok {
+				s.env = append(s.env, key)
+			}
+		}
+	case "EXPOSE":
+		s.ports = append(s.ports, dockerWords(args)...)
+	case "COPY", "ADD":
+		c := dockerCopy{instruction: keyword, line: line}
+		var paths []string
+		for _, w := range dockerArgs(args) {
+			switch {
+			case strings.HasPrefix(w, "--from="):
+				c.from = strings.TrimPrefix(w, "--from=")
+			case strings.HasPrefix(w, "--") && len(paths) == 0:
+			default:
+				paths = append(paths, w)
+			}
+		}
+		if len(paths) > 0 {
+			c.dest = paths[len(paths)-1]
+			c.sources = paths[:len(paths)-1]
+		}
+		s.copies = append(s.copies, c)
+	case "RUN":
+		words := dockerArgs(args)
+		for len(words) > 0 && strings.HasPrefix(words[0], "--") {
+			words = words[1:]
+		}
+		s.runs = append(s.runs, dockerRun{command: strings.Join(words, " "), line: line})
+	case "USER":
+		s.user = args
+	case "WORKDIR":
+		if strings.HasPrefix(args, "/") || s.workdir == "" {
+			s.workdir = args
+		} else {
+			s.workdir = strings.TrimSuffix(s.workdir, "/") + "/" + args
+		}
+	case "ENTRYPOINT":
+		s.entrypoint = dockerCommandForm(args)
+	case "CMD":
+		s.cmd = dockerCommandForm(args)
+	case "HEALTHCHECK":
+		s.healthchk = strings.Join(strings.Fields(args), " ")
+	case "VOLUME":
+		s.volumes = append(s.volumes, dockerArgs(args)...)
+	}
+}
+
+// dockerArgs splits instruction arguments in JSON array (exec) form or in
+// whitespace-separated form.
+func dockerArgs(args string) []string {
+	if strings.HasPrefix(args, "[") {
+		var words []string
+		if json.Unmarshal([]byte(args), &words) == nil {
+			return words
+		}
+	}
+	return dockerWords(args)
+}
+
+// dockerCommandForm renders ENTRYPOINT and CMD arguments with their form.
+func dockerCommandForm(args string) string {
+	if strings.HasPrefix(args, "[") {
+		var words []string
+		if json.Unmarshal([]byte(args), &words) == nil {
+			return strings.Join(words, " ") + " (exec form)"
+		}
+	}
+	return strings.Join(strings.Fields(args), " ") + " (shell form)"
+}
+
+// dockerWords splits on whitespace outside double or single quotes and
+// removes the quotes.
+func dockerWords(s string) []string {
+	var words []string
+	var cur strings.Builder
+	var quote rune
+	inWord := false
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words
+}
+
+// dockerfileDegradation describes a Dockerfile that failed to parse.
+func dockerfileDegradation(content []byte, df *dockerfile, err error) degradedExploration {
+	line := 0
+	var syntaxErr *dockerfileSyntaxError
+	if errors.As(err, &syntaxErr) {
+		line = syntaxErr.line
+	}
+	return degradedExploration{
+		Failed:   fmt.Sprintf("Dockerfile parsing at line %d: %v", line, err),
+		Progress: fmt.Sprintf("parsed %d instructions in %d stages before the error", df.instructions, len(df.stages)),
+		Examined: lineEndOffset(content, line),
+		Size:     int64(len(content)),
+		NextSteps: []string{
+			"Run docker build --check or hadolint on the file to locate the error",
+			"Read the raw content around the error with the view tool",
+		},
+	}
+}
+
+func writeDockerfileDependencies(summary *strings.Builder, df *dockerfile) {
+	stages := make(map[string]bool)
+	var lines []string
+	for _, s := range df.stages {
+		if stages[strings.ToLower(s.base)] {
+			lines = append(lines, fmt.Sprintf("%s <- %s (FROM, line %d)", s.label(), s.base, s.line))
+		}
+		for _, c := range s.copies {
+			switch {
+			case c.from == "":
+			case stages[strings.ToLower(c.from)]:
+				lines = append(lines, fmt.Sprintf("%s <- %s (%s --from, line %d)", s.label(), c.from, c.instruction, c.line))
+			default:
+				lines = append(lines, fmt.Sprintf("%s <- external image %s (%s --from, line %d)", s.label(), c.from, c.instruction, c.line))
+			}
+		}
+		if s.name != "" {
+			stages[strings.ToLower(s.name)] = true
+		}
+		stages[fmt.Sprint(s.index)] = true
+	}
+	writeDockerfileSection(summary, "Stage dependencies", lines)
+}
+
+func writeDockerfilePackages(summary *strings.Builder, df *dockerfile) {
+	var lines []string
+	for _, s := range df.stages {
+		for _, run := range s.runs {
+			for _, m := range dockerfilePackagePattern.FindAllStringSubmatch(run.command, -1) {
+				var pkgs []string
+				for _, w := range strings.Fields(m[3]) {
+					if !strings.HasPrefix(w, "-") && !strings.ContainsAny(w, "\\$") {
+						pkgs = append(pkgs, w)
+					}
+				}
+				if len(pkgs) > 0 {
+					lines = append(lines, fmt.Sprintf("%s: %s (%s, line %d)", m[1], strings.Join(pkgs, ", "), s.label(), run.line))
+				}
+			}
+		}
+	}
+	writeDockerfileSection(summary, "Packages installed", lines)
+}
+
+func writeDockerfileFindings(summary *strings.Builder, df *dockerfile) {
+	stages := make(map[string]bool)
+	var lines []string
+	for _, s := range df.stages {
+		base := strings.ToLower(s.base)
+		if !stages[base] && base != "scratch" && !strings.Contains(base, "$") && !strings.Contains(base, "@") {
+			_, tag, tagged := strings.Cut(base[strings.LastIndex(base, "/")+1:], ":")
+			if !tagged || tag == "latest" {
+				lines = append(lines, fmt.Sprintf("base image %s is not pinned to a version (%s, line %d)", s.base, s.label(), s.line))
+			}
+		}
+		for _, c := range s.copies {
+			if c.instruction == "ADD" && slices.ContainsFunc(c.sources, func(src string) bool {
+				return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+			}) {
+				lines = append(lines, fmt.Sprintf("ADD downloads a remote URL without checksum verification (%s, line %d)", s.label(), c.line))
+			}
+		}
+		for _, name := range slices.Concat(s.args, s.env) {
+			key, _, _ := strings.Cut(name, "=")
+			if dockerfileSecretPattern.MatchString(key) {
+				lines = append(lines, fmt.Sprintf("%s looks like a secret baked into the image; prefer RUN --mount=type=secret (%s)", key, s.label()))
+			}
+		}
+		if s.name != "" {
+			stages[strings.ToLower(s.name)] = true
+		}
+	}
+	if final := df.stages[len(df.stages)-1]; final.user == "" || final.user == "root" || final.user == "0" {
+		lines = append(lines, "final stage runs as root")
+	}
+	writeDockerfileSection(summary, "Findings", lines)
+}
+
+func writeDockerfileSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), dockerfileMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with "...".
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
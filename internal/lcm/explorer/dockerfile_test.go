@@ -0,0 +1,149 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDockerfile = `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.22
+
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION} AS builder
+ARG TARGETOS
+WORKDIR /src
+COPY go.mod go.sum ./
+RUN --mount=type=cache,target=/go/pkg/mod \
+    go mod download
+COPY . .
+ENV CGO_ENABLED=0 API_TOKEN=dev
+RUN go build -o /out/server ./cmd/server
+
+FROM debian:bookworm-slim
+RUN apt-get update && apt-get install -y --no-install-recommends ca-certificates curl \
+    && rm -rf /var/lib/apt/lists/*
+COPY --from=builder /out/server /usr/local/bin/server
+ADD https://example.com/config.tar.gz /etc/app/
+EXPOSE 8080 9090/udp
+USER app
+WORKDIR /app
+HEALTHCHECK --interval=30s CMD curl -f http://localhost:8080/healthz
+ENTRYPOINT ["/usr/local/bin/server", "--port", "8080"]
+CMD ["--verbose"]
+`
+
+func TestDockerfileExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &DockerfileExplorer{}
+	require.True(t, e.CanHandle("Dockerfile", nil))
+	require.True(t, e.CanHandle("build/Dockerfile.dev", nil))
+	require.True(t, e.CanHandle("api.dockerfile", nil))
+	require.True(t, e.CanHandle("Containerfile", nil))
+	require.False(t, e.CanHandle("docker-compose.yml", nil))
+	require.False(t, e.CanHandle("Dockerfiles.md", nil))
+}
+
+func TestDockerfileExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &DockerfileExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "Dockerfile", Content: []byte(testDockerfile)})
+	require.NoError(t, err)
+	require.Equal(t, "dockerfile", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Dockerfile: Dockerfile\n")
+	require.Contains(t, s, "Stages: 2 (final: stage 1 from debian:bookworm-slim)\n")
+	require.Contains(t, s, "Instructions: 19\n")
+	require.Contains(t, s, "Parser directives: syntax=docker/dockerfile:1\n")
+	require.Contains(t, s, "Build stages:\n"+
+		"  - builder: golang:${GO_VERSION} (line 4, platform $BUILDPLATFORM)\n"+
+		"  - stage 1: debian:bookworm-slim (line 14, final)\n")
+	require.Contains(t, s, "Build args:\n  - GO_VERSION=1.22 (global)\n  - TARGETOS (builder)\n")
+	require.Contains(t, s, "Exposed ports:\n  - 8080 (stage 1)\n  - 9090/udp (stage 1)\n")
+	require.Contains(t, s, "Copied sources:\n"+
+		"  - COPY go.mod, go.sum -> ./ (builder, line 7)\n"+
+		"  - COPY . -> . (builder, line 10)\n"+
+		"  - COPY --from=builder /out/server -> /usr/local/bin/server (stage 1, line 17)\n"+
+		"  - ADD https://example.com/config.tar.gz -> /etc/app/ (stage 1, line 18)\n")
+	require.Contains(t, s, "Run commands:\n"+
+		"  - builder: go mod download (line 8)\n"+
+		"  - builder: go build -o /out/server ./cmd/server (line 12)\n"+
+		"  - stage 1: apt-get update && apt-get install -y --no-install-recommends ca-certificates curl && rm -rf /var/... (line 15)\n")
+	require.Contains(t, s, "Environment:\n  - CGO_ENABLED (builder)\n  - API_TOKEN (builder)\n")
+	require.NotContains(t, s, "=dev")
+	// The secret-looking variable is listed without being flagged.
+	require.Equal(t, 1, strings.Count(s, "API_TOKEN"))
+	require.True(t, strings.HasSuffix(s, "Final stage:\n"+
+		"  - user app\n"+
+		"  - workdir /app\n"+
+		"  - entrypoint /usr/local/bin/server --port 8080 (exec form)\n"+
+		"  - cmd --verbose (exec form)\n"+
+		"  - healthcheck --interval=30s CMD curl -f http://localhost:8080/healthz\n"), s)
+}
+
+func TestDockerfileExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &DockerfileExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "Dockerfile", Content: []byte(testDockerfile)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Stage dependencies:\n  - stage 1 <- builder (COPY --from, line 17)\n")
+	require.Contains(t, s, "Packages installed:\n  - apt-get: ca-certificates, curl (stage 1, line 15)\n")
+	require.Contains(t, s, "Findings:\n"+
+		"  - API_TOKEN looks like a secret baked into the image; prefer RUN --mount=type=secret (builder)\n"+
+		"  - ADD downloads a remote URL without checksum verification (stage 1, line 18)\n")
+	require.NotContains(t, s, "runs as root")
+	require.NotContains(t, s, "is not pinned")
+}
+
+func TestDockerfileExplorer_Explore_HeredocAndRoot(t *testing.T) {
+	t.Parallel()
+
+	content := "FROM alpine\nRUN <<EOF\napk add --no-cache git\necho done\nEOF\nCMD echo hi\n"
+	result, err := (&DockerfileExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), ExploreInput{Path: "Containerfile", Content: []byte(content)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Run commands:\n  - stage 0: apk add --no-cache git; echo done (line 2)\n")
+	require.Contains(t, s, "  - user root (no USER instruction)\n  - cmd echo hi (shell form)\n")
+	require.Contains(t, s, "  - apk: git (stage 0, line 2)\n")
+	require.Contains(t, s, "  - base image alpine is not pinned to a version (stage 0, line 1)\n")
+	require.Contains(t, s, "  - final stage runs as root\n")
+}
+
+func TestDockerfileExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		content string
+		failed  string
+	}{
+		{content: "FROM alpine\nRUNN echo hi\n", failed: "line 2: unknown instruction \"RUNN\""},
+		{content: "RUN echo hi\nFROM alpine\n", failed: "line 1: RUN instruction before the first FROM"},
+		{content: "FROM alpine\nRUN <<EOF\necho hi\n", failed: "line 2: heredoc EOF is never terminated"},
+		{content: "# just a comment\n", failed: "line 2: no FROM instruction"},
+	} {
+		result, err := (&DockerfileExplorer{}).Explore(context.Background(), ExploreInput{Path: "Dockerfile", Content: []byte(tt.content)})
+		require.NoError(t, err)
+		require.Regexp(t, degradedBlockPattern, result.Summary)
+		require.Contains(t, result.Summary, "Failed: Dockerfile parsing at "+tt.failed+"\n")
+	}
+}
+
+func TestDockerfileExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "Dockerfile", Content: []byte(testDockerfile)})
+		require.NoError(t, err)
+		require.Equal(t, "dockerfile", result.ExplorerUsed)
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Stage dependencies"))
+	}
+}
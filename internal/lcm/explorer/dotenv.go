@@ -0,0 +1,612 @@
+package explorer
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DotenvExplorer explores key/value configuration files (.env, .properties,
+// and .ini/.toml files that declare secret-looking keys): keys, counts,
+// duplicates and likely secrets, whose values are always redacted.
+type DotenvExplorer struct {
+	formatterProfile OutputProfile
+}
+
+const (
+	// dotenvMaxDetails caps the lines listed per section.
+	dotenvMaxDetails = 200
+	// dotenvValueMaxRunes caps a non-secret value in enhancement output.
+	dotenvValueMaxRunes = 60
+)
+
+// configEntry is one key/value assignment.
+type configEntry struct {
+	key   string // qualified by its INI section or TOML table
+	name  string // key without the section or table
+	value string
+	line  int
+	// literal marks values that are never interpolated (single-quoted
+	// dotenv and TOML literal strings).
+	literal bool
+}
+
+type configFile struct {
+	format   string // dotenv, properties, ini or toml
+	sections []string
+	entries  []configEntry
+}
+
+// configSyntaxError is a parse error on a 1-based line.
+type configSyntaxError struct {
+	line int
+	msg  string
+}
+
+func (e *configSyntaxError) Error() string { return e.msg }
+
+var (
+	// configSecretKeyPattern finds a secret-looking key in .ini and .toml
+	// content; only those files are taken over from INIExplorer and
+	// TOMLExplorer.
+	configSecretKeyPattern = regexp.MustCompile(`(?im)^\s*"?[\w.-]*(?:password|passwd|secret|token|api[_-]?key|private[_-]?key|credential|access[_-]?key)[\w.-]*"?\s*[=:]`)
+	dotenvKeyPattern       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	configReferencePattern = regexp.MustCompile(`\$\{([\w.-]+)(?:[:?-][^}]*)?\}|\$([A-Za-z_]\w*)`)
+	configNumberPattern    = regexp.MustCompile(`^[+-]?\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?$`)
+)
+
+// configSecretRules classify values that are secrets whatever their key.
+var configSecretRules = []struct {
+	pattern  *regexp.Regexp
+	category string
+}{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`), "private key"},
+	{regexp.MustCompile(`^AKIA[0-9A-Z]{16}$`), "AWS access key"},
+	{regexp.MustCompile(`^(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_\w{20,})$`), "GitHub token"},
+	{regexp.MustCompile(`^xox[abprs]-[\w-]+$`), "Slack token"},
+	{regexp.MustCompile(`^sk[-_](?:live|test|proj)[-_][\w-]{16,}$`), "API key"},
+	{regexp.MustCompile(`^eyJ[\w-]+\.eyJ[\w-]+\.[\w-]*$`), "JWT"},
+}
+
+// configSecretKeyWords classify keys by their underscore-separated words.
+var configSecretKeyWords = []struct {
+	words    []string
+	category string
+}{
+	{[]string{"_private_key_", "_privatekey_", "_ssh_key_"}, "private key"},
+	{[]string{"_password_", "_passwd_", "_pwd_", "_pass_", "_passphrase_"}, "password"},
+	{[]string{"_api_key_", "_apikey_"}, "API key"},
+	{[]string{"_access_key_", "_secret_key_"}, "access key"},
+	{[]string{"_signing_key_", "_encryption_key_", "_master_key_", "_salt_"}, "signing key"},
+	{[]string{"_secret_", "_secrets_"}, "secret"},
+	{[]string{"_token_", "_tokens_"}, "token"},
+	{[]string{"_credential_", "_credentials_", "_auth_"}, "credential"},
+}
+
+func (e *DotenvExplorer) CanHandle(path string, content []byte) bool {
+	switch configFormat(path) {
+	case "dotenv", "properties":
+		return true
+	case "ini", "toml":
+		return configSecretKeyPattern.Match(content)
+	}
+	return false
+}
+
+// configFormat names the key/value format of path, or "" for others.
+func configFormat(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env"):
+		return "dotenv"
+	case strings.HasSuffix(base, ".properties"):
+		return "properties"
+	case strings.HasSuffix(base, ".ini"):
+		return "ini"
+	case strings.HasSuffix(base, ".toml"):
+		return "toml"
+	}
+	return ""
+}
+
+func (e *DotenvExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Config file too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "dotenv", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	// Content handed over under an unknown name is read as dotenv.
+	format := cmp.Or(configFormat(input.Path), "dotenv")
+	cfg, err := parseConfigFile(format, strings.ReplaceAll(string(input.Content), "\r\n", "\n"))
+	if err != nil {
+		return redactedDegradedResult("Config file: "+name, input.Content, configDegradation(input.Content, cfg, err)), nil
+	}
+
+	lines := make(map[string][]int)
+	var order []string
+	defined := make(map[string]bool)
+	for _, entry := range cfg.entries {
+		if lines[entry.key] == nil {
+			order = append(order, entry.key)
+		}
+		lines[entry.key] = append(lines[entry.key], entry.line)
+		defined[entry.key] = true
+		defined[entry.name] = true
+	}
+
+	var keys, duplicates, secrets, values, empty, references []string
+	secretKeys := make(map[string]bool)
+	for _, key := range order {
+		keys = append(keys, fmt.Sprintf("%s (line %d)", key, lines[key][0]))
+		if len(lines[key]) > 1 {
+			nums := make([]string, len(lines[key]))
+			for i, n := range lines[key] {
+				nums[i] = strconv.Itoa(n)
+			}
+			duplicates = append(duplicates, fmt.Sprintf("%s (lines %s)", key, strings.Join(nums, ", ")))
+		}
+	}
+	for _, entry := range cfg.entries {
+		if category := classifyConfigSecret(entry.name, entry.value); category != "" {
+			secrets = append(secrets, fmt.Sprintf("%s = %s (%s, line %d)", entry.key, redactConfigValue(category, entry.value), category, entry.line))
+			secretKeys[entry.key] = true
+		} else if entry.value != "" {
+			value := truncateRunes(strings.Join(strings.Fields(entry.value), " "), dotenvValueMaxRunes)
+			values = append(values, fmt.Sprintf("%s = %s", entry.key, value))
+		}
+		if entry.value == "" {
+			empty = append(empty, fmt.Sprintf("%s (line %d)", entry.key, entry.line))
+		}
+		if entry.literal {
+			continue
+		}
+		var refs []string
+		for _, match := range configReferencePattern.FindAllStringSubmatch(entry.value, -1) {
+			ref := match[1] + match[2]
+			if !defined[ref] {
+				ref += " (undefined)"
+			}
+			refs = append(refs, ref)
+		}
+		if len(refs) > 0 {
+			references = append(references, entry.key+" -> "+strings.Join(refs, ", "))
+		}
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Config file: %s (%s)\n", name, cfg.format)
+	fmt.Fprintf(&summary, "Keys: %d (%d likely secrets, %d duplicated, %d empty)\n", len(order), len(secretKeys), len(duplicates), len(empty))
+	sections := make([]string, len(cfg.sections))
+	for i, s := range cfg.sections {
+		sections[i] = "[" + s + "]"
+	}
+	writeDotenvSection(&summary, "Sections", sections)
+	writeDotenvSection(&summary, "Keys", keys)
+	writeDotenvSection(&summary, "Duplicates", duplicates)
+	writeDotenvSection(&summary, "Likely secrets", secrets)
+
+	// EXCEED MODE: non-secret values, empty values and interpolation.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeDotenvSection(&summary, "Values", values)
+		writeDotenvSection(&summary, "Empty values", empty)
+		writeDotenvSection(&summary, "Variable references", references)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "dotenv",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+func writeDotenvSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), dotenvMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+}
+
+// classifyConfigSecret returns the kind of secret value holds, judged by
+// the value itself and then by the key name, or "" when it looks harmless.
+func classifyConfigSecret(key, value string) string {
+	for _, rule := range configSecretRules {
+		if rule.pattern.MatchString(value) {
+			return rule.category
+		}
+	}
+	if i := strings.Index(value, "://"); i > 0 {
+		if at := strings.IndexByte(value[i+3:], '@'); at > 0 && strings.Contains(value[i+3:i+3+at], ":") {
+			return "connection string"
+		}
+	}
+
+	// Numbers, booleans, plain URLs and bare references are settings such
+	// as TOKEN_TTL=3600 or AUTH_URL=https://..., not secrets.
+	lower := strings.ToLower(value)
+	switch {
+	case configNumberPattern.MatchString(value), lower == "true", lower == "false",
+		strings.Contains(value, "://"), configReferencePattern.ReplaceAllString(value, "") == "" && value != "":
+		return ""
+	}
+	words := "_" + configKeyWords(key) + "_"
+	for _, rule := range configSecretKeyWords {
+		for _, word := range rule.words {
+			if strings.Contains(words, word) {
+				return rule.category
+			}
+		}
+	}
+	if len(value) >= 24 && !strings.ContainsAny(value, " /\\") && byteEntropy([]byte(value)) >= 4.2 {
+		return "high-entropy value"
+	}
+	return ""
+}
+
+// configKeyWords lowercases key and separates its words with underscores,
+// splitting camelCase and dotted or dashed names.
+func configKeyWords(key string) string {
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range key {
+		switch {
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			b.WriteByte('_')
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteByte('_')
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// redactConfigValue hides a secret value, keeping only its length, or the
+// non-secret parts of a connection string.
+func redactConfigValue(category, value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	if category == "connection string" {
+		i := strings.Index(value, "://") + 3
+		at := i + strings.IndexByte(value[i:], '@')
+		user, _, _ := strings.Cut(value[i:at], ":")
+		return value[:i] + user + ":[redacted]" + value[at:]
+	}
+	return fmt.Sprintf("[redacted, %d chars]", len([]rune(value)))
+}
+
+func parseConfigFile(format, content string) (*configFile, error) {
+	cfg := &configFile{format: format}
+	lines := strings.Split(content, "\n")
+	var err error
+	switch format {
+	case "properties":
+		parseProperties(cfg, lines)
+	case "ini":
+		err = parseINI(cfg, lines)
+	case "toml":
+		err = parseTOMLKeys(cfg, lines)
+	default:
+		err = parseDotenv(cfg, lines)
+	}
+	return cfg, err
+}
+
+func parseDotenv(cfg *configFile, lines []string) error {
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return &configSyntaxError{line: i + 1, msg: "expected KEY=VALUE"}
+		}
+		if !dotenvKeyPattern.MatchString(key) {
+			return &configSyntaxError{line: i + 1, msg: "invalid variable name"}
+		}
+		entry := configEntry{key: key, name: key, line: i + 1}
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'"):
+			quote := value[:1]
+			// Quoted values may span lines until the closing quote.
+			body := value[1:]
+			for !dotenvClosed(body, quote) {
+				if i+1 >= len(lines) {
+					return &configSyntaxError{line: entry.line, msg: fmt.Sprintf("unterminated %s quoted value for %s", quote, key)}
+				}
+				i++
+				body += "\n" + lines[i]
+			}
+			end := dotenvQuoteEnd(body, quote)
+			entry.value = body[:end]
+			if quote == `"` {
+				entry.value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(entry.value)
+			}
+			entry.literal = quote == "'"
+		default:
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+			entry.value = value
+		}
+		cfg.entries = append(cfg.entries, entry)
+	}
+	return nil
+}
+
+// dotenvQuoteEnd returns the index of the closing quote in body, or -1.
+func dotenvQuoteEnd(body, quote string) int {
+	for i := 0; i < len(body); i++ {
+		switch {
+		case quote == `"` && body[i] == '\\':
+			i++
+		case body[i] == quote[0]:
+			return i
+		}
+	}
+	return -1
+}
+
+func dotenvClosed(body, quote string) bool { return dotenvQuoteEnd(body, quote) >= 0 }
+
+// parseProperties reads Java properties: "=", ":" or whitespace
+// separators, "#" and "!" comments and backslash continuations.
+func parseProperties(cfg *configFile, lines []string) {
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+		line := strings.TrimLeftFunc(lines[i], unicode.IsSpace)
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		for strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeftFunc(lines[i], unicode.IsSpace)
+		}
+		end := len(line)
+		for j := 0; j < len(line); j++ {
+			if line[j] == '\\' {
+				j++
+				continue
+			}
+			if line[j] == '=' || line[j] == ':' || line[j] == ' ' || line[j] == '\t' {
+				end = j
+				break
+			}
+		}
+		value := strings.TrimLeft(line[end:], " \t")
+		if value != "" && (value[0] == '=' || value[0] == ':') {
+			value = strings.TrimLeft(value[1:], " \t")
+		}
+		cfg.entries = append(cfg.entries, configEntry{key: line[:end], name: line[:end], value: strings.TrimSpace(value), line: start})
+	}
+}
+
+// parseINI reads [section] headers and "key = value" or "key: value"
+// lines; bare keys (as in my.cnf) have empty values.
+func parseINI(cfg *configFile, lines []string) error {
+	section := ""
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return &configSyntaxError{line: i + 1, msg: "unclosed section header"}
+			}
+			section = strings.TrimSpace(line[1:end])
+			cfg.sections = append(cfg.sections, section)
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		key, value := line, ""
+		if sep >= 0 {
+			key, value = strings.TrimSpace(line[:sep]), strings.TrimSpace(line[sep+1:])
+		}
+		entry := configEntry{key: key, name: key, value: strings.Trim(value, `"`), line: i + 1}
+		if section != "" {
+			entry.key = section + "." + key
+		}
+		cfg.entries = append(cfg.entries, entry)
+	}
+	return nil
+}
+
+// parseTOMLKeys reads TOML tables and key/value pairs line by line. It
+// tracks multi-line strings and arrays so their continuation lines are not
+// taken for keys, but does not validate values.
+func parseTOMLKeys(cfg *configFile, lines []string) error {
+	table := ""
+	arrays := make(map[string]int)
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			array := strings.HasPrefix(line, "[[")
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			end := strings.Index(line, closing)
+			if end < 0 {
+				return &configSyntaxError{line: i + 1, msg: "unclosed table header"}
+			}
+			table = strings.TrimSpace(line[len(closing):end])
+			if array {
+				// Each [[array]] element is its own table.
+				arrays[table]++
+				table = fmt.Sprintf("%s[%d]", table, arrays[table]-1)
+			}
+			cfg.sections = append(cfg.sections, table)
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return &configSyntaxError{line: i + 1, msg: "expected key = value"}
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		entry := configEntry{key: key, name: key, line: i + 1}
+		if table != "" {
+			entry.key = table + "." + entry.key
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, `"""`) || strings.HasPrefix(value, "'''"):
+			delim := value[:3]
+			body := value[3:]
+			for !strings.Contains(body, delim) {
+				if i+1 >= len(lines) {
+					return &configSyntaxError{line: entry.line, msg: "unterminated multi-line string for " + entry.key}
+				}
+				i++
+				body += "\n" + lines[i]
+			}
+			entry.value = strings.TrimPrefix(body[:strings.Index(body, delim)], "\n")
+			entry.literal = delim == "'''"
+		case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'"):
+			end := dotenvQuoteEnd(value[1:], value[:1])
+			if end < 0 {
+				return &configSyntaxError{line: entry.line, msg: "unterminated string for " + entry.key}
+			}
+			entry.value = value[1 : end+1]
+			entry.literal = value[0] == '\''
+		case strings.HasPrefix(value, "["):
+			// Arrays may span lines until their brackets balance.
+			value = tomlStripComment(value)
+			for tomlBracketDepth(value) > 0 {
+				if i+1 >= len(lines) {
+					return &configSyntaxError{line: entry.line, msg: "unterminated array for " + entry.key}
+				}
+				i++
+				value += " " + tomlStripComment(lines[i])
+			}
+			entry.value = value
+		default:
+			entry.value = tomlStripComment(value)
+		}
+		cfg.entries = append(cfg.entries, entry)
+	}
+	return nil
+}
+
+// tomlStripComment trims line and drops a trailing comment outside strings.
+func tomlStripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// tomlBracketDepth returns the unclosed bracket depth of a comment-free
+// value, ignoring brackets inside strings.
+func tomlBracketDepth(value string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return depth
+}
+
+// redactedDegradedResult is degradedTextResult for files that may hold
+// secrets: the content sample keeps comments, headers and keys, but not
+// values or lines that failed to parse.
+func redactedDegradedResult(header string, content []byte, d degradedExploration) ExploreResult {
+	var summary strings.Builder
+	summary.WriteString(header + "\n")
+	d.write(&summary)
+	summary.WriteString("\nContent (values redacted):\n")
+	lines := strings.Split(string(content), "\n")
+	for _, raw := range lines[:min(len(lines), dotenvMaxDetails)] {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "", strings.HasPrefix(line, "["), strings.ContainsAny(line[:1], "#;!"):
+			summary.WriteString(line + "\n")
+		case strings.ContainsAny(line, "=:"):
+			i := strings.IndexAny(line, "=:")
+			summary.WriteString(strings.TrimSpace(line[:i]) + " " + line[i:i+1] + " [redacted]\n")
+		default:
+			fmt.Fprintf(&summary, "[%d chars redacted]\n", len([]rune(line)))
+		}
+	}
+	if len(lines) > dotenvMaxDetails {
+		fmt.Fprintf(&summary, "... (%d more lines)\n", len(lines)-dotenvMaxDetails)
+	}
+
+	result := summary.String()
+	return ExploreResult{Summary: result, ExplorerUsed: "dotenv", TokenEstimate: estimateTokens(result)}
+}
+
+// configFormatNames are the display names of the configuration formats.
+var configFormatNames = map[string]string{
+	"dotenv":     ".env",
+	"properties": "Properties",
+	"ini":        "INI",
+	"toml":       "TOML",
+}
+
+// configDegradation describes a configuration file that failed to parse.
+func configDegradation(content []byte, cfg *configFile, err error) degradedExploration {
+	line := 0
+	var syntaxErr *configSyntaxError
+	if errors.As(err, &syntaxErr) {
+		line = syntaxErr.line
+	}
+	return degradedExploration{
+		Failed:   fmt.Sprintf("%s parsing at line %d: %v", configFormatNames[cfg.format], line, err),
+		Progress: fmt.Sprintf("parsed %d keys before the error", len(cfg.entries)),
+		Examined: lineEndOffset(content, line),
+		Size:     int64(len(content)),
+		NextSteps: []string{
+			"Read the raw content around the error with the view tool",
+			"Keep secret values out of the conversation when quoting the file",
+		},
+	}
+}
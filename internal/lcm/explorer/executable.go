@@ -14,15 +14,17 @@ import (
 
 // ExecutableExplorer explores executable and compiled binary formats.
 // It detects ELF, PE/COFF, Mach-O, WASM, Java class, and Python bytecode
-// files via both extension and magic byte matching. When available, it shells
-// out to platform tools (readelf, otool, objdump, nm, strings, file) to
-// extract dependencies, sections, symbols, and interesting strings.
+// files via both extension and magic byte matching. Dependencies, sections
+// and symbols of ELF, PE and Mach-O binaries are read with the Go debug
+// packages; in enhancement output it also shells out to platform tools
+// (file, readelf, ldd, otool, objdump, nm), when installed, for the file
+// type and for whatever the native readers could not list.
 type ExecutableExplorer struct {
 	formatterProfile OutputProfile
 }
 
 // executableExtensions maps extensions to format family identifiers.
-// These 17 extensions are claimed by ExecutableExplorer. Notably .deb, .rpm,
+// These 21 extensions are claimed by ExecutableExplorer. Notably .deb, .rpm,
 // .dmg, and .jar are NOT claimed here -- they go to ArchiveExplorer.
 var executableExtensions = map[string]string{
 	"exe":   "pe",
@@ -42,6 +44,10 @@ var executableExtensions = map[string]string{
 	"class": "java",
 	"pyc":   "pyc",
 	"pyo":   "pyc",
+	"fw":    "firmware",
+	"rom":   "firmware",
+	"img":   "firmware",
+	"trx":   "firmware",
 }
 
 // executableMagic describes a magic byte signature for executable formats.
@@ -85,6 +91,7 @@ const (
 	maxStrings            = 30
 	enhancedSymbols       = 100
 	enhancedStrings       = 50
+	maxSizeModules        = 15
 	maxStringLineLen      = 160
 	interestingStringsMin = 6
 )
@@ -111,13 +118,43 @@ func (e *ExecutableExplorer) Explore(ctx context.Context, input ExploreInput) (E
 	fmt.Fprintf(&summary, "Format: %s\n", format)
 	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
 
-	// Write content to temp file for tool invocation.
-	err := withTempFile("crush-exec-*", input.Content, func(tempPath string) error {
-		return e.exploreWithTools(ctx, &summary, tempPath, family, input.Content)
-	})
+	listing, err := readNativeBinary(input.Content)
 	if err != nil {
-		// Non-fatal: we already have basic header info.
-		fmt.Fprintf(&summary, "\nNote: tool analysis unavailable: %v\n", err)
+		fmt.Fprintf(&summary, "\nNote: %v\n", err)
+	}
+	if listing == nil {
+		listing = &binaryListing{}
+	}
+	if len(listing.arches) > 0 {
+		fmt.Fprintf(&summary, "Architectures: %s\n", strings.Join(listing.arches, ", "))
+	}
+
+	// EXCEED MODE: platform tools add the file type and fill in what the
+	// native readers could not list, such as static libraries.
+	var fileType string
+	if e.formatterProfile == OutputProfileEnhancement {
+		err := withTempFile("crush-exec-*", input.Content, func(tempPath string) error {
+			fileType = e.addToolAnalysis(ctx, listing, tempPath, input.Content)
+			return nil
+		})
+		if err != nil {
+			// Non-fatal: the native listing stands on its own.
+			fmt.Fprintf(&summary, "\nNote: tool analysis unavailable: %v\n", err)
+		}
+	}
+	e.writeListing(&summary, fileType, listing, input.Content)
+
+	// EXCEED MODE: what the image is made of, for firmware and packed
+	// binaries the tools above see as opaque data.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeBinaryProfile(&summary, input.Content)
+	}
+
+	// EXCEED MODE: where the bytes of a Go binary go, by module.
+	if e.formatterProfile == OutputProfileEnhancement {
+		if size, err := ReadBinarySize(bytes.NewReader(input.Content), int64(len(input.Content))); err == nil && size.GoVersion != "" {
+			size.writeSizes(&summary, "Size by module", size.Modules, size.Symbols, maxSizeModules)
+		}
 	}
 
 	result := summary.String()
@@ -168,6 +205,15 @@ func (e *ExecutableExplorer) resolveFormat(ext, family string, content []byte) s
 		return "Java class"
 	case "pyc":
 		return "Python bytecode"
+	case "firmware":
+		// Name the image by what it starts with, if anything known.
+		if magic := e.detectFormatFromMagic(content); magic != "" {
+			return magic
+		}
+		if hits := findEmbeddedData(content); len(hits) > 0 && hits[0].offset == 0 {
+			return fmt.Sprintf("Firmware image (%s)", hits[0].desc)
+		}
+		return "Firmware image"
 	case "":
 		// No extension match. Use magic bytes.
 		if magic := e.detectFormatFromMagic(content); magic != "" {
@@ -241,97 +287,104 @@ func disambiguateCafebabe(content []byte) string {
 	return "Mach-O Universal or Java class (ambiguous)"
 }
 
-// exploreWithTools runs external analysis tools against the temp file.
-// Each tool is independently optional; all failures are silently ignored.
-func (e *ExecutableExplorer) exploreWithTools(
-	ctx context.Context, summary *strings.Builder, tempPath, family string, content []byte,
-) error {
+// addToolAnalysis runs external analysis tools against the temp file and
+// returns the file type they report. Lists the native readers left empty
+// are filled from the tools. Each tool is independently optional; all
+// failures are silently ignored.
+func (e *ExecutableExplorer) addToolAnalysis(
+	ctx context.Context, listing *binaryListing, tempPath string, content []byte,
+) string {
 	// Determine format from magic bytes for tool selection.
 	formatHint := e.detectBinaryType(content)
 
-	// Step 1: file -b for format detection.
-	if fileDesc := runTool(ctx, "file", "-b", tempPath); fileDesc != "" {
-		trimmed := strings.TrimSpace(fileDesc)
-		if trimmed != "" && trimmed != "data" {
-			fmt.Fprintf(summary, "\nFile type: %s\n", trimmed)
-		}
+	if len(listing.deps) == 0 {
+		listing.deps = e.extractDependencies(ctx, tempPath, formatHint)
+	}
+	if len(listing.sections) == 0 {
+		listing.sections = e.extractSections(ctx, tempPath, formatHint)
+	}
+	if len(listing.exported) == 0 && len(listing.imported) == 0 {
+		listing.exported, listing.imported = e.extractSymbols(ctx, tempPath)
+	}
+
+	fileType := strings.TrimSpace(runTool(ctx, "file", "-b", tempPath))
+	if fileType == "data" {
+		return ""
+	}
+	return fileType
+}
+
+// writeListing writes the file type, dependencies, sections, symbols and
+// interesting strings of a binary.
+func (e *ExecutableExplorer) writeListing(summary *strings.Builder, fileType string, listing *binaryListing, content []byte) {
+	if fileType != "" {
+		fmt.Fprintf(summary, "\nFile type: %s\n", fileType)
 	}
 
-	// Step 2: Dependencies.
-	deps := e.extractDependencies(ctx, tempPath, formatHint)
-	if len(deps) > 0 {
+	if len(listing.deps) > 0 {
 		summary.WriteString("\nDependencies:\n")
 		limit := maxDeps
-		for i, dep := range deps {
+		for i, dep := range listing.deps {
 			if i >= limit {
-				fmt.Fprintf(summary, "  - ... and %d more\n", len(deps)-limit)
+				fmt.Fprintf(summary, "  - ... and %d more\n", len(listing.deps)-limit)
 				break
 			}
 			fmt.Fprintf(summary, "  - %s\n", dep)
 		}
 	}
 
-	// Step 3: Sections.
-	sections := e.extractSections(ctx, tempPath, formatHint)
-	if len(sections) > 0 {
+	if len(listing.sections) > 0 {
 		summary.WriteString("\nSections:\n")
 		limit := maxSections
 		if e.formatterProfile == OutputProfileEnhancement {
-			limit = len(sections) // No limit in enhancement mode.
+			limit = len(listing.sections) // No limit in enhancement mode.
 		}
-		for i, sec := range sections {
+		for i, sec := range listing.sections {
 			if i >= limit {
-				fmt.Fprintf(summary, "  - ... and %d more\n", len(sections)-limit)
+				fmt.Fprintf(summary, "  - ... and %d more\n", len(listing.sections)-limit)
 				break
 			}
 			fmt.Fprintf(summary, "  - %s\n", sec)
 		}
 	}
 
-	// Step 4: Symbols via nm -g.
 	exportLimit := maxExportedSymbols
 	importLimit := maxImportedSymbols
 	if e.formatterProfile == OutputProfileEnhancement {
 		exportLimit = enhancedSymbols
 		importLimit = enhancedSymbols
 	}
-
-	exported, imported := e.extractSymbols(ctx, tempPath)
-	if len(exported) > 0 {
+	if len(listing.exported) > 0 {
 		summary.WriteString("\nExported symbols:\n")
-		for i, sym := range exported {
+		for i, sym := range listing.exported {
 			if i >= exportLimit {
-				fmt.Fprintf(summary, "  - ... and %d more\n", len(exported)-exportLimit)
+				fmt.Fprintf(summary, "  - ... and %d more\n", len(listing.exported)-exportLimit)
 				break
 			}
 			fmt.Fprintf(summary, "  - %s\n", sym)
 		}
 	}
-	if len(imported) > 0 {
+	if len(listing.imported) > 0 {
 		summary.WriteString("\nImported symbols:\n")
-		for i, sym := range imported {
+		for i, sym := range listing.imported {
 			if i >= importLimit {
-				fmt.Fprintf(summary, "  - ... and %d more\n", len(imported)-importLimit)
+				fmt.Fprintf(summary, "  - ... and %d more\n", len(listing.imported)-importLimit)
 				break
 			}
 			fmt.Fprintf(summary, "  - %s\n", sym)
 		}
 	}
 
-	// Step 5: Interesting strings.
 	strLimit := maxStrings
 	if e.formatterProfile == OutputProfileEnhancement {
 		strLimit = enhancedStrings
 	}
-	interesting := e.extractStrings(ctx, tempPath, strLimit)
-	if len(interesting) > 0 {
+	if interesting := interestingStrings(content, strLimit); len(interesting) > 0 {
 		summary.WriteString("\nInteresting strings:\n")
 		for _, s := range interesting {
 			fmt.Fprintf(summary, "  - %s\n", s)
 		}
 	}
-
-	return nil
 }
 
 // detectBinaryType returns "elf", "pe", "macho", "wasm", "java", or ""
@@ -408,15 +461,6 @@ func (e *ExecutableExplorer) extractSymbols(ctx context.Context, tempPath string
 	return parseNmSymbols(output)
 }
 
-// extractStrings runs `strings -n 6` and filters for interesting patterns.
-func (e *ExecutableExplorer) extractStrings(ctx context.Context, tempPath string, limit int) []string {
-	output := runTool(ctx, "strings", fmt.Sprintf("-n%d", interestingStringsMin), tempPath)
-	if output == "" {
-		return nil
-	}
-	return filterInterestingStrings(output, limit)
-}
-
 // runTool runs an external tool with a 5-second timeout. Returns empty string
 // if the tool is not found or fails.
 func runTool(ctx context.Context, name string, args ...string) string {
@@ -613,14 +657,8 @@ func filterInterestingStrings(output string, limit int) []string {
 	var result []string
 	for line := range strings.SplitSeq(output, "\n") {
 		line = strings.TrimSpace(line)
-		if line == "" || len(line) > maxStringLineLen {
-			continue
-		}
-		for _, pat := range interestingStringPatterns {
-			if pat.MatchString(line) {
-				result = append(result, line)
-				break
-			}
+		if isInterestingString(line) {
+			result = append(result, line)
 		}
 		if len(result) >= limit {
 			break
@@ -628,3 +666,17 @@ func filterInterestingStrings(output string, limit int) []string {
 	}
 	return result
 }
+
+// isInterestingString reports whether a trimmed string is short enough to
+// show and matches one of interestingStringPatterns.
+func isInterestingString(line string) bool {
+	if line == "" || len(line) > maxStringLineLen {
+		return false
+	}
+	for _, pat := range interestingStringPatterns {
+		if pat.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package explorer
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxPEExports bounds the export name table read from a PE image, which a
+// corrupt header could otherwise make arbitrarily large.
+const maxPEExports = 1 << 16
+
+// binaryListing is what ExecutableExplorer reports about a binary's
+// structure.
+type binaryListing struct {
+	// arches are the architectures of a Mach-O universal binary; the rest
+	// of the listing describes the first of them.
+	arches   []string
+	deps     []string
+	sections []string
+	exported []string
+	imported []string
+}
+
+// readNativeBinary lists an ELF, PE or Mach-O binary with the Go debug
+// packages, so no external tool is needed. It returns nil for other
+// formats, and an error when the headers of a recognized format cannot be
+// read.
+func readNativeBinary(content []byte) (*binaryListing, error) {
+	r := bytes.NewReader(content)
+	switch {
+	case bytes.HasPrefix(content, []byte("\x7fELF")):
+		f, err := elf.NewFile(r)
+		if err != nil {
+			return nil, fmt.Errorf("ELF headers unreadable: %w", err)
+		}
+		return listELF(f), nil
+	case bytes.HasPrefix(content, []byte("MZ")):
+		f, err := pe.NewFile(r)
+		if err != nil {
+			return nil, fmt.Errorf("PE headers unreadable: %w", err)
+		}
+		return listPE(f), nil
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		return listMachO(f), nil
+	}
+	if disambiguateCafebabe(content) != "Mach-O Universal" {
+		return nil, nil
+	}
+	fat, err := macho.NewFatFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("Mach-O universal headers unreadable: %w", err)
+	}
+	listing := listMachO(fat.Arches[0].File)
+	for _, arch := range fat.Arches {
+		listing.arches = append(listing.arches, strings.ToLower(strings.TrimPrefix(arch.Cpu.String(), "Cpu")))
+	}
+	return listing, nil
+}
+
+// listELF lists the needed libraries, sections and global symbols of an
+// ELF file. Dynamic symbols are preferred; objects and static binaries
+// fall back to the full symbol table.
+func listELF(f *elf.File) *binaryListing {
+	listing := &binaryListing{}
+	listing.deps, _ = f.ImportedLibraries()
+	for _, s := range f.Sections {
+		if s.Type == elf.SHT_NULL || s.Name == "" {
+			continue
+		}
+		listing.sections = append(listing.sections, fmt.Sprintf("%s (%s)", s.Name, strings.TrimPrefix(s.Type.String(), "SHT_")))
+	}
+
+	syms, err := f.DynamicSymbols()
+	if err != nil || len(syms) == 0 {
+		syms, _ = f.Symbols()
+	}
+	for _, sym := range syms {
+		bind := elf.ST_BIND(sym.Info)
+		if sym.Name == "" || bind != elf.STB_GLOBAL && bind != elf.STB_WEAK {
+			continue
+		}
+		if sym.Section == elf.SHN_UNDEF {
+			listing.imported = append(listing.imported, sym.Name)
+		} else {
+			listing.exported = append(listing.exported, sym.Name)
+		}
+	}
+	listing.exported = sortedUnique(listing.exported)
+	listing.imported = sortedUnique(listing.imported)
+	return listing
+}
+
+// listPE lists the imported DLLs and functions, sections and exports of a
+// PE image. debug/pe does not list imported libraries, so they are taken
+// from the imported symbols in import order.
+func listPE(f *pe.File) *binaryListing {
+	listing := &binaryListing{}
+	for _, s := range f.Sections {
+		kind := "data"
+		switch {
+		case s.Characteristics&pe.IMAGE_SCN_CNT_CODE != 0:
+			kind = "code"
+		case s.Characteristics&pe.IMAGE_SCN_CNT_UNINITIALIZED_DATA != 0:
+			kind = "bss"
+		}
+		listing.sections = append(listing.sections, fmt.Sprintf("%s (%s)", s.Name, kind))
+	}
+
+	imports, _ := f.ImportedSymbols()
+	for _, imp := range imports {
+		name, dll, _ := strings.Cut(imp, ":")
+		if dll != "" && !slices.Contains(listing.deps, dll) {
+			listing.deps = append(listing.deps, dll)
+		}
+		listing.imported = append(listing.imported, fmt.Sprintf("%s (%s)", name, dll))
+	}
+	listing.imported = sortedUnique(listing.imported)
+	listing.exported = sortedUnique(peExports(f))
+	return listing
+}
+
+// peExports reads the name table of a PE export directory, which debug/pe
+// does not parse.
+func peExports(f *pe.File) []string {
+	var dirs []pe.DataDirectory
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dirs = oh.DataDirectory[:min(int(oh.NumberOfRvaAndSizes), len(oh.DataDirectory))]
+	case *pe.OptionalHeader64:
+		dirs = oh.DataDirectory[:min(int(oh.NumberOfRvaAndSizes), len(oh.DataDirectory))]
+	}
+	if len(dirs) <= pe.IMAGE_DIRECTORY_ENTRY_EXPORT || dirs[pe.IMAGE_DIRECTORY_ENTRY_EXPORT].VirtualAddress == 0 {
+		return nil
+	}
+
+	// at returns the image bytes from rva to the end of its section.
+	loaded := make(map[*pe.Section][]byte)
+	at := func(rva uint32) []byte {
+		for _, s := range f.Sections {
+			size := max(s.VirtualSize, s.Size)
+			if rva < s.VirtualAddress || rva-s.VirtualAddress >= size {
+				continue
+			}
+			data, ok := loaded[s]
+			if !ok {
+				data, _ = s.Data()
+				loaded[s] = data
+			}
+			if off := rva - s.VirtualAddress; int(off) < len(data) {
+				return data[off:]
+			}
+			return nil
+		}
+		return nil
+	}
+
+	dir := at(dirs[pe.IMAGE_DIRECTORY_ENTRY_EXPORT].VirtualAddress)
+	if len(dir) < 40 {
+		return nil
+	}
+	count := min(binary.LittleEndian.Uint32(dir[24:]), maxPEExports)
+	table := at(binary.LittleEndian.Uint32(dir[32:]))
+	var names []string
+	for i := uint32(0); i < count && int(4*i+4) <= len(table); i++ {
+		name := at(binary.LittleEndian.Uint32(table[4*i:]))
+		if end := bytes.IndexByte(name, 0); end > 0 {
+			names = append(names, string(name[:end]))
+		}
+	}
+	return names
+}
+
+// listMachO lists the linked dylibs, sections and external symbols of a
+// Mach-O file.
+func listMachO(f *macho.File) *binaryListing {
+	listing := &binaryListing{}
+	listing.deps, _ = f.ImportedLibraries()
+	for _, s := range f.Sections {
+		listing.sections = append(listing.sections, s.Seg+","+s.Name)
+	}
+	if f.Symtab != nil {
+		const (
+			nStab = 0xe0
+			nType = 0x0e
+			nExt  = 0x01
+		)
+		for _, sym := range f.Symtab.Syms {
+			if sym.Name == "" || sym.Type&nStab != 0 || sym.Type&nExt == 0 {
+				continue
+			}
+			if sym.Type&nType == 0 {
+				listing.imported = append(listing.imported, sym.Name)
+			} else {
+				listing.exported = append(listing.exported, sym.Name)
+			}
+		}
+	}
+	listing.exported = sortedUnique(listing.exported)
+	listing.imported = sortedUnique(listing.imported)
+	return listing
+}
+
+// sortedUnique sorts names and drops duplicates.
+func sortedUnique(names []string) []string {
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// interestingStrings scans content for printable ASCII runs, as
+// `strings -n 6` would, and returns at most limit interesting ones.
+func interestingStrings(content []byte, limit int) []string {
+	var result []string
+	start := -1
+	for i := 0; i <= len(content) && len(result) < limit; i++ {
+		if i < len(content) && (content[i] >= 0x20 && content[i] < 0x7f || content[i] == '\t') {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && i-start >= interestingStringsMin {
+			if line := strings.TrimSpace(string(content[start:i])); isInterestingString(line) {
+				result = append(result, line)
+			}
+		}
+		start = -1
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"debug/pe"
+	"encoding/binary"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// buildPEWithExports creates a PE32+ image with one .edata section whose
+// export directory names the given functions.
+//
+// Layout:
+//
+//	0x000: DOS header, e_lfanew = 0x40
+//	0x040: "PE\0\0", COFF file header, optional header, section header
+//	0x200: .edata raw data (RVA 0x1000): export directory, name pointer
+//	       table, NUL-terminated names
+func buildPEWithExports(t *testing.T, names ...string) []byte {
+	t.Helper()
+
+	const rva = 0x1000
+	edata := make([]byte, 40+4*len(names))
+	binary.LittleEndian.PutUint32(edata[24:], uint32(len(names)))
+	binary.LittleEndian.PutUint32(edata[32:], rva+40)
+	for i, name := range names {
+		binary.LittleEndian.PutUint32(edata[40+4*i:], uint32(rva+len(edata)))
+		edata = append(edata, name...)
+		edata = append(edata, 0)
+	}
+
+	var buf bytes.Buffer
+	dos := make([]byte, 0x40)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3C:], 0x40)
+	buf.Write(dos)
+	buf.WriteString("PE\x00\x00")
+
+	oh := pe.OptionalHeader64{
+		Magic:               0x20b,
+		SectionAlignment:    0x1000,
+		FileAlignment:       0x200,
+		SizeOfImage:         0x2000,
+		SizeOfHeaders:       0x200,
+		NumberOfRvaAndSizes: 16,
+	}
+	oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_EXPORT] = pe.DataDirectory{VirtualAddress: rva, Size: 40}
+	fh := pe.FileHeader{
+		Machine:              pe.IMAGE_FILE_MACHINE_AMD64,
+		NumberOfSections:     1,
+		SizeOfOptionalHeader: uint16(binary.Size(oh)),
+	}
+	sh := pe.SectionHeader32{
+		VirtualSize:      uint32(len(edata)),
+		VirtualAddress:   rva,
+		SizeOfRawData:    uint32(len(edata)),
+		PointerToRawData: 0x200,
+		Characteristics:  pe.IMAGE_SCN_CNT_INITIALIZED_DATA,
+	}
+	copy(sh.Name[:], ".edata")
+	for _, v := range []any{fh, oh, sh} {
+		require.NoError(t, binary.Write(&buf, binary.LittleEndian, v))
+	}
+	buf.Write(make([]byte, 0x200-buf.Len()))
+	buf.Write(edata)
+	return buf.Bytes()
+}
+
+func TestReadNativeBinary_PEExports(t *testing.T) {
+	t.Parallel()
+
+	listing, err := readNativeBinary(buildPEWithExports(t, "Zeta", "Alpha"))
+	require.NoError(t, err)
+	require.NotNil(t, listing)
+	require.Equal(t, []string{".edata (data)"}, listing.sections)
+	require.Equal(t, []string{"Alpha", "Zeta"}, listing.exported)
+	require.Empty(t, listing.imported)
+}
+
+// TestReadNativeBinary_TestExecutable lists the running test binary, which
+// is a real ELF, Mach-O or PE file depending on the platform.
+func TestReadNativeBinary_TestExecutable(t *testing.T) {
+	t.Parallel()
+
+	path, err := os.Executable()
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	listing, err := readNativeBinary(content)
+	require.NoError(t, err)
+	require.NotNil(t, listing)
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		require.Contains(t, listing.sections, "__TEXT,__text")
+	case "windows":
+		require.Contains(t, listing.sections, ".text (code)")
+	default:
+		require.Contains(t, listing.sections, ".text (PROGBITS)")
+	}
+
+	// The parity profile never runs external tools.
+	e := &ExecutableExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "explorer.test", Content: content})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "\nSections:\n")
+	require.NotContains(t, result.Summary, "File type:")
+}
+
+func TestReadNativeBinary_OtherFormats(t *testing.T) {
+	t.Parallel()
+
+	for name, content := range map[string][]byte{
+		"java class": buildSyntheticJavaClass(t),
+		"wasm":       buildSyntheticWASM(t),
+		"text":       []byte("not a binary at all"),
+	} {
+		listing, err := readNativeBinary(content)
+		require.NoError(t, err, name)
+		require.Nil(t, listing, name)
+	}
+
+	_, err := readNativeBinary([]byte("\x7fELF\x02"))
+	require.ErrorContains(t, err, "ELF headers unreadable")
+
+	// A truncated header is noted in the summary rather than failing.
+	result, err := (&ExecutableExplorer{}).Explore(context.Background(), ExploreInput{Path: "broken.so", Content: []byte("\x7fELF\x02")})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Note: ELF headers unreadable")
+}
+
+func TestInterestingStrings(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("\x00\x01https://example.com/api\x00short\x00\x7fnot interesting at all\x00/usr/lib/libfoo.so\x00error: disk full")
+	require.Equal(t, []string{
+		"https://example.com/api",
+		"/usr/lib/libfoo.so",
+		"error: disk full",
+	}, interestingStrings(content, 10))
+	require.Equal(t, []string{"https://example.com/api"}, interestingStrings(content, 1))
+}
@@ -0,0 +1,398 @@
+package explorer
+
+import (
+	"cmp"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ErrNoSymbols is returned by ReadBinarySize for a binary without a symbol
+// table, such as one linked with -ldflags=-s.
+var ErrNoSymbols = errors.New("no symbol table; the binary is stripped")
+
+// Buckets of symbols that belong to no Go package.
+const (
+	sizeLinkerData = "(linker data)"
+	sizeNonGo      = "(non-Go symbols)"
+	sizeStd        = "std"
+	sizeUnknownMod = "(unknown module)"
+)
+
+// goLinkerPrefixes are the prefixes of the symbols the Go linker generates,
+// before Go 1.20 named them with "go:".
+var goLinkerPrefixes = []string{
+	"go.string.", "go.func.", "go.buildid", "go.importpath.", "go.info.",
+	"go.range.", "go.loc.", "go.cuinfo.", "go.builtin.", "go.constinfo.",
+	"go.debuglines.", "go.debuginfo.",
+}
+
+// SizeEntry is the size of a section, package or module of a binary.
+type SizeEntry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// BinarySize is the size breakdown of an executable by section and, from
+// its symbol table, by Go package and module.
+type BinarySize struct {
+	Format   string `json:"format"`
+	FileSize int64  `json:"file_size"`
+	// GoVersion and MainModule come from the build info of Go binaries.
+	GoVersion  string `json:"go_version,omitempty"`
+	MainModule string `json:"main_module,omitempty"`
+	// Symbols is the total size of the symbols, which excludes headers,
+	// the pclntab in some formats and debug info.
+	Symbols  int64       `json:"symbols"`
+	Sections []SizeEntry `json:"sections"`
+	Packages []SizeEntry `json:"packages"`
+	Modules  []SizeEntry `json:"modules"`
+}
+
+// binarySymbol is a symbol of a binary with the section it is in.
+type binarySymbol struct {
+	name    string
+	section int
+	addr    uint64
+	size    uint64
+}
+
+// ReadBinarySize reads the size breakdown of the ELF, PE or Mach-O binary
+// r of size bytes. Symbol sizes are read from ELF symbol tables and, for
+// the other formats, are the distance to the next symbol of the section.
+func ReadBinarySize(r io.ReaderAt, size int64) (*BinarySize, error) {
+	b := &BinarySize{FileSize: size}
+	var syms []binarySymbol
+	if f, err := elf.NewFile(r); err == nil {
+		b.Format = "ELF"
+		syms = b.readELF(f)
+	} else if f, err := pe.NewFile(r); err == nil {
+		b.Format = "PE"
+		syms = b.readPE(f)
+	} else if f, err := macho.NewFile(r); err == nil {
+		b.Format = "Mach-O"
+		syms = b.readMachO(f)
+	} else if fat, err := macho.NewFatFile(r); err == nil {
+		b.Format = "Mach-O universal (" + strings.ToLower(strings.TrimPrefix(fat.Arches[0].Cpu.String(), "Cpu")) + ")"
+		syms = b.readMachO(fat.Arches[0].File)
+	} else {
+		return nil, errors.New("not an ELF, PE or Mach-O binary")
+	}
+	if len(syms) == 0 {
+		return nil, ErrNoSymbols
+	}
+
+	var modules []string
+	if info, err := buildinfo.Read(r); err == nil {
+		b.GoVersion = info.GoVersion
+		b.MainModule = info.Main.Path
+		modules = append(modules, info.Main.Path)
+		for _, dep := range info.Deps {
+			modules = append(modules, dep.Path)
+		}
+	}
+	packages := make(map[string]int64)
+	mods := make(map[string]int64)
+	for _, s := range syms {
+		pkg := goSymbolPackage(s.name)
+		packages[pkg] += int64(s.size)
+		mods[packageModule(pkg, modules)] += int64(s.size)
+		b.Symbols += int64(s.size)
+	}
+	b.Packages = sortedSizes(packages)
+	b.Modules = sortedSizes(mods)
+	slices.SortStableFunc(b.Sections, func(x, y SizeEntry) int { return cmp.Compare(y.Size, x.Size) })
+	return b, nil
+}
+
+func (b *BinarySize) readELF(f *elf.File) []binarySymbol {
+	for _, s := range f.Sections {
+		if s.Type != elf.SHT_NULL && s.Type != elf.SHT_NOBITS && s.Name != "" && s.Size > 0 {
+			b.Sections = append(b.Sections, SizeEntry{s.Name, int64(s.Size)})
+		}
+	}
+	elfSyms, _ := f.Symbols()
+	var syms []binarySymbol
+	for _, s := range elfSyms {
+		t := elf.ST_TYPE(s.Info)
+		if s.Size == 0 || s.Name == "" || t == elf.STT_SECTION || t == elf.STT_FILE ||
+			s.Section == elf.SHN_UNDEF || s.Section >= elf.SHN_LORESERVE || int(s.Section) >= len(f.Sections) ||
+			f.Sections[s.Section].Type == elf.SHT_NOBITS {
+			continue
+		}
+		syms = append(syms, binarySymbol{name: s.Name, section: int(s.Section), addr: s.Value, size: s.Size})
+	}
+	return syms
+}
+
+func (b *BinarySize) readPE(f *pe.File) []binarySymbol {
+	ends := make(map[int]uint64)
+	for i, s := range f.Sections {
+		if s.Size > 0 {
+			b.Sections = append(b.Sections, SizeEntry{s.Name, int64(s.Size)})
+		}
+		// COFF symbol values are offsets in their 1-based section.
+		ends[i+1] = uint64(min(s.VirtualSize, s.Size))
+	}
+	var syms []binarySymbol
+	for _, s := range f.Symbols {
+		if s.SectionNumber <= 0 || s.Name == "" || ends[int(s.SectionNumber)] == 0 {
+			continue
+		}
+		syms = append(syms, binarySymbol{name: s.Name, section: int(s.SectionNumber), addr: uint64(s.Value)})
+	}
+	return sizeByGaps(syms, ends)
+}
+
+func (b *BinarySize) readMachO(f *macho.File) []binarySymbol {
+	const (
+		typeMask   = 0x0e
+		typeSect   = 0x0e
+		stabMask   = 0xe0
+		zerofill   = 0x1
+		sectionTyp = 0xff
+	)
+	ends := make(map[int]uint64)
+	for i, s := range f.Sections {
+		if s.Flags&sectionTyp == zerofill {
+			continue
+		}
+		if s.Size > 0 {
+			b.Sections = append(b.Sections, SizeEntry{s.Seg + "," + s.Name, int64(s.Size)})
+		}
+		// Mach-O section numbers are 1-based.
+		ends[i+1] = s.Addr + s.Size
+	}
+	if f.Symtab == nil {
+		return nil
+	}
+	var syms []binarySymbol
+	for _, s := range f.Symtab.Syms {
+		if s.Type&stabMask != 0 || s.Type&typeMask != typeSect || s.Name == "" || ends[int(s.Sect)] == 0 {
+			continue
+		}
+		// Mach-O prefixes C symbol names with an underscore; Go symbols
+		// keep theirs as is.
+		name := s.Name
+		if strings.HasPrefix(name, "_") && strings.ContainsAny(name, "./") {
+			name = name[1:]
+		}
+		syms = append(syms, binarySymbol{name: name, section: int(s.Sect), addr: s.Value})
+	}
+	return sizeByGaps(syms, ends)
+}
+
+// sizeByGaps sizes each symbol as the distance to the next symbol of its
+// section, or to the section end for the last one.
+func sizeByGaps(syms []binarySymbol, ends map[int]uint64) []binarySymbol {
+	slices.SortFunc(syms, func(a, b binarySymbol) int {
+		return cmp.Or(cmp.Compare(a.section, b.section), cmp.Compare(a.addr, b.addr))
+	})
+	sized := syms[:0]
+	for i, s := range syms {
+		end := ends[s.section]
+		if i+1 < len(syms) && syms[i+1].section == s.section {
+			end = syms[i+1].addr
+		}
+		if end > s.addr {
+			s.size = end - s.addr
+			sized = append(sized, s)
+		}
+	}
+	return sized
+}
+
+// goSymbolPackage returns the import path of the package a Go symbol
+// belongs to, such as "net/http" for "net/http.(*Server).Serve" or
+// "type:*net/http.Request", or a bucket for symbols of no package.
+func goSymbolPackage(name string) string {
+	if strings.HasPrefix(name, "go:itab.") || strings.HasPrefix(name, "go.itab.") {
+		// An itab belongs to the package of its concrete type.
+		name = name[len("go:itab."):]
+	} else if strings.HasPrefix(name, "go:") {
+		return sizeLinkerData
+	}
+	for _, p := range goLinkerPrefixes {
+		if strings.HasPrefix(name, p) {
+			return sizeLinkerData
+		}
+	}
+	name = strings.TrimPrefix(name, "type:")
+	name = strings.TrimPrefix(name, "type.")
+	if strings.HasPrefix(name, ".namedata.") || strings.HasPrefix(name, ".importpath.") {
+		return sizeLinkerData
+	}
+	// Equality functions are named for the type they compare.
+	name = strings.TrimPrefix(name, ".eq.")
+	name = strings.TrimLeft(name, "*[]0123456789")
+	if i := strings.IndexAny(name, "[(,"); i >= 0 {
+		// Generic instantiations carry their type arguments in brackets,
+		// and methods promoted from embedded fields the import path of
+		// their origin after the receiver.
+		name = name[:i]
+	}
+	// Symbols escape the dots of the last element of the import path, so
+	// the package ends at the first dot after the path, which starts with
+	// a domain for packages outside the standard library.
+	start := 0
+	if rest, ok := strings.CutPrefix(name, "vendor/"); ok {
+		start = len(name) - len(rest)
+	}
+	if slash := strings.IndexByte(name[start:], '/'); slash > 0 && isPathElement(name[start:start+slash]) {
+		start += slash
+	}
+	dot := strings.IndexByte(name[start:], '.')
+	if dot < 0 || start+dot == 0 {
+		return sizeNonGo
+	}
+	return strings.ReplaceAll(name[:start+dot], "%2e", ".")
+}
+
+// isPathElement reports whether s can be the first element of an import
+// path rather than a symbol name.
+func isPathElement(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_' || c == '~') {
+			return false
+		}
+	}
+	return true
+}
+
+// packageModule returns the module of pkg among modules, "std" for the
+// standard library, or a bucket name for symbols of no package.
+func packageModule(pkg string, modules []string) string {
+	if strings.HasPrefix(pkg, "(") {
+		return pkg
+	}
+	best := ""
+	for _, m := range modules {
+		if (pkg == m || strings.HasPrefix(pkg, m+"/")) && len(m) > len(best) {
+			best = m
+		}
+	}
+	if best != "" {
+		return best
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	if !strings.Contains(first, ".") || first == "vendor" {
+		return sizeStd
+	}
+	return sizeUnknownMod
+}
+
+func sortedSizes(sizes map[string]int64) []SizeEntry {
+	entries := make([]SizeEntry, 0, len(sizes))
+	for name, size := range sizes {
+		entries = append(entries, SizeEntry{name, size})
+	}
+	slices.SortFunc(entries, func(a, b SizeEntry) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Name, b.Name))
+	})
+	return entries
+}
+
+// Report renders the size breakdown, listing at most limit sections,
+// modules and packages.
+func (b *BinarySize) Report(limit int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Format: %s\n", b.Format)
+	fmt.Fprintf(&sb, "File size: %s\n", formatSize(uint64(b.FileSize)))
+	if b.GoVersion != "" {
+		fmt.Fprintf(&sb, "Go: %s, main module %s\n", b.GoVersion, b.MainModule)
+	}
+	fmt.Fprintf(&sb, "Symbols: %s (%s)\n", formatSize(uint64(b.Symbols)), percentOf(b.Symbols, b.FileSize))
+	b.writeSizes(&sb, "Sections", b.Sections, b.FileSize, limit)
+	b.writeSizes(&sb, "Size by module", b.Modules, b.Symbols, limit)
+	b.writeSizes(&sb, "Size by package", b.Packages, b.Symbols, limit)
+	return sb.String()
+}
+
+// Compare renders how the binary changed from base, the same program built
+// earlier: the file size and the modules and packages that grew or shrank
+// most, at most limit of each.
+func (b *BinarySize) Compare(base *BinarySize, limit int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "File size: %s -> %s (%s)\n", formatSize(uint64(base.FileSize)), formatSize(uint64(b.FileSize)), signedSize(b.FileSize-base.FileSize))
+	fmt.Fprintf(&sb, "Symbols: %s -> %s (%s)\n", formatSize(uint64(base.Symbols)), formatSize(uint64(b.Symbols)), signedSize(b.Symbols-base.Symbols))
+	writeSizeChanges(&sb, "Module changes", base.Modules, b.Modules, limit)
+	writeSizeChanges(&sb, "Package changes", base.Packages, b.Packages, limit)
+	return sb.String()
+}
+
+func (b *BinarySize) writeSizes(sb *strings.Builder, title string, entries []SizeEntry, total int64, limit int) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n%s:\n", title)
+	for i, e := range entries {
+		if i == limit {
+			fmt.Fprintf(sb, "  - ... and %d more\n", len(entries)-i)
+			break
+		}
+		fmt.Fprintf(sb, "  - %s: %s (%s)\n", e.Name, formatSize(uint64(e.Size)), percentOf(e.Size, total))
+	}
+}
+
+func writeSizeChanges(sb *strings.Builder, title string, before, after []SizeEntry, limit int) {
+	sizes := make(map[string][2]int64)
+	for _, e := range before {
+		s := sizes[e.Name]
+		s[0] = e.Size
+		sizes[e.Name] = s
+	}
+	for _, e := range after {
+		s := sizes[e.Name]
+		s[1] = e.Size
+		sizes[e.Name] = s
+	}
+	type change struct {
+		name          string
+		before, after int64
+	}
+	var changes []change
+	for name, s := range sizes {
+		if s[0] != s[1] {
+			changes = append(changes, change{name, s[0], s[1]})
+		}
+	}
+	abs := func(n int64) int64 { return max(n, -n) }
+	slices.SortFunc(changes, func(a, b change) int {
+		return cmp.Or(cmp.Compare(abs(b.after-b.before), abs(a.after-a.before)), strings.Compare(a.name, b.name))
+	})
+	fmt.Fprintf(sb, "\n%s: %d\n", title, len(changes))
+	for i, c := range changes {
+		if i == limit {
+			fmt.Fprintf(sb, "  - ... and %d more\n", len(changes)-i)
+			break
+		}
+		note := ""
+		switch {
+		case c.before == 0:
+			note = ", new"
+		case c.after == 0:
+			note = ", removed"
+		}
+		fmt.Fprintf(sb, "  - %s: %s (%s -> %s%s)\n", c.name, signedSize(c.after-c.before), formatSize(uint64(c.before)), formatSize(uint64(c.after)), note)
+	}
+}
+
+func signedSize(n int64) string {
+	if n < 0 {
+		return "-" + formatSize(uint64(-n))
+	}
+	return "+" + formatSize(uint64(n))
+}
+
+func percentOf(n, total int64) string {
+	if total <= 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)/float64(total)*100)
+}
@@ -0,0 +1,160 @@
+package explorer
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoSymbolPackage(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]string{
+		"runtime.mallocgc":                                      "runtime",
+		"net/http.(*Server).Serve":                              "net/http",
+		"net/http.(*Server).Serve.func1":                        "net/http",
+		"github.com/charmbracelet/crush/internal/app.New":       "github.com/charmbracelet/crush/internal/app",
+		"type:*github.com/charmbracelet/crush/internal/app.App": "github.com/charmbracelet/crush/internal/app",
+		"type:.eq.github.com/a/b.T":                             "github.com/a/b",
+		"slices.SortFunc[go.shape.[]string,go.shape.string]":    "slices",
+		"gopkg.in/yaml%2ev3.Marshal":                            "gopkg.in/yaml.v3",
+		"vendor/golang.org/x/net/http2/hpack.NewEncoder":        "vendor/golang.org/x/net/http2/hpack",
+		"go:itab.*os.File,io.Reader":                            "os",
+		"github.com/a/sdk.T.github.com/a/sdk/param.IsNull":      "github.com/a/sdk",
+		"github.com/a/sdk.(*T).github.com/a/sdk/param.IsNull":   "github.com/a/sdk",
+		"go:func.*":             sizeLinkerData,
+		"go.string.\"hello\"":   sizeLinkerData,
+		"type:.namedata.*app.T": sizeLinkerData,
+		"_cgo_init":             sizeNonGo,
+		"x_cgo_thread_start":    sizeNonGo,
+	} {
+		require.Equal(t, want, goSymbolPackage(name), name)
+	}
+
+	modules := []string{"github.com/a/sdk", "github.com/a/sdk/v2", "example.com/main"}
+	require.Equal(t, "github.com/a/sdk/v2", packageModule("github.com/a/sdk/v2/param", modules))
+	require.Equal(t, "github.com/a/sdk", packageModule("github.com/a/sdk/param", modules))
+	require.Equal(t, sizeStd, packageModule("net/http", modules))
+	require.Equal(t, sizeStd, packageModule("vendor/golang.org/x/net/http2/hpack", modules))
+	require.Equal(t, sizeUnknownMod, packageModule("github.com/other/x", modules))
+	require.Equal(t, sizeLinkerData, packageModule(sizeLinkerData, modules))
+}
+
+func TestSizeByGaps(t *testing.T) {
+	t.Parallel()
+
+	syms := sizeByGaps([]binarySymbol{
+		{name: "b", section: 1, addr: 0x1010},
+		{name: "a", section: 1, addr: 0x1000},
+		{name: "alias", section: 1, addr: 0x1010},
+		{name: "c", section: 2, addr: 0x2000},
+	}, map[int]uint64{1: 0x1040, 2: 0x2008})
+	sizes := make(map[string]uint64)
+	for _, s := range syms {
+		sizes[s.name] += s.size
+	}
+	require.Equal(t, map[string]uint64{"a": 0x10, "alias": 0x30, "c": 0x8}, sizes)
+}
+
+// TestReadBinarySize_GoBinary sizes a small Go program built for the
+// platform, an ELF, Mach-O or PE file with a symbol table.
+func TestReadBinarySize_GoBinary(t *testing.T) {
+	t.Parallel()
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not installed")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/hello\n\ngo 1.21\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+import (
+	"fmt"
+	"net/url"
+)
+
+func main() { fmt.Println(url.QueryEscape("hello world")) }
+`), 0o644))
+	cmd := exec.CommandContext(t.Context(), goTool, "build", "-o", "hello.bin", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0", "GOFLAGS=")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	content, err := os.ReadFile(filepath.Join(dir, "hello.bin"))
+	require.NoError(t, err)
+
+	size, err := ReadBinarySize(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	require.NotEmpty(t, size.GoVersion)
+	require.Equal(t, "example.com/hello", size.MainModule)
+	require.Positive(t, size.Symbols)
+	require.LessOrEqual(t, size.Symbols, size.FileSize)
+
+	var packages, modules []string
+	for _, p := range size.Packages {
+		packages = append(packages, p.Name)
+	}
+	for _, m := range size.Modules {
+		modules = append(modules, m.Name)
+	}
+	require.Contains(t, packages, "runtime")
+	require.Contains(t, packages, "net/url")
+	require.Contains(t, packages, "main")
+	require.Contains(t, modules, sizeStd)
+
+	report := size.Report(5)
+	require.Contains(t, report, "Go: "+size.GoVersion+", main module example.com/hello\n")
+	require.Contains(t, report, "\nSize by module:\n  - ")
+	require.Contains(t, report, "\nSize by package:\n  - ")
+
+	// The enhancement profile lists the modules of Go binaries.
+	e := &ExecutableExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(t.Context(), ExploreInput{Path: "hello.bin", Content: content})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "\nSize by module:\n")
+
+	// Stripped binaries have nothing to attribute sizes to.
+	cmd = exec.CommandContext(t.Context(), goTool, "build", "-ldflags=-s", "-o", "stripped.bin", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0", "GOFLAGS=")
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	f, err := os.Open(filepath.Join(dir, "stripped.bin"))
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = ReadBinarySize(f, 0)
+	if runtime.GOOS != "darwin" {
+		// The Mach-O linker keeps a symbol table for dyld.
+		require.ErrorIs(t, err, ErrNoSymbols)
+	}
+
+	_, err = ReadBinarySize(bytes.NewReader([]byte("not a binary")), 12)
+	require.Error(t, err)
+}
+
+func TestBinarySize_Compare(t *testing.T) {
+	t.Parallel()
+
+	base := &BinarySize{
+		FileSize: 10 << 20,
+		Symbols:  6 << 20,
+		Modules:  []SizeEntry{{"std", 3 << 20}, {"example.com/app", 2 << 20}, {"example.com/old", 1 << 20}},
+		Packages: []SizeEntry{{"runtime", 1 << 20}},
+	}
+	head := &BinarySize{
+		FileSize: 12 << 20,
+		Symbols:  8 << 20,
+		Modules:  []SizeEntry{{"std", 3 << 20}, {"example.com/app", 2 << 20}, {"example.com/big", 3 << 20}},
+		Packages: []SizeEntry{{"runtime", 1 << 20}},
+	}
+	s := head.Compare(base, 10)
+	require.Contains(t, s, "File size: 10.0 MB -> 12.0 MB (+2.0 MB)\n")
+	require.Contains(t, s, "\nModule changes: 2\n  - example.com/big: +3.0 MB (0 bytes -> 3.0 MB, new)\n  - example.com/old: -1.0 MB (1.0 MB -> 0 bytes, removed)\n")
+	require.Contains(t, s, "\nPackage changes: 0\n")
+}
@@ -155,7 +155,7 @@ func TestExecutableExplorer_CanHandle_Extensions(t *testing.T) {
 		content  []byte
 		expected bool
 	}{
-		// All 17 executable extensions.
+		// All 21 executable extensions.
 		{name: "exe", path: "program.exe", expected: true},
 		{name: "dll", path: "library.dll", expected: true},
 		{name: "so", path: "libfoo.so", expected: true},
@@ -173,6 +173,10 @@ func TestExecutableExplorer_CanHandle_Extensions(t *testing.T) {
 		{name: "class", path: "Main.class", expected: true},
 		{name: "pyc", path: "module.pyc", expected: true},
 		{name: "pyo", path: "module.pyo", expected: true},
+		{name: "fw", path: "router.fw", expected: true},
+		{name: "rom", path: "bios.rom", expected: true},
+		{name: "img", path: "rootfs.img", expected: true},
+		{name: "trx", path: "openwrt.trx", expected: true},
 
 		// Case insensitivity.
 		{name: "EXE uppercase", path: "PROGRAM.EXE", expected: true},
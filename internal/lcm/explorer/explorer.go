@@ -2,9 +2,12 @@ package explorer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 const (
@@ -15,6 +18,9 @@ const (
 
 // ExploreInput is the input to the explorer registry.
 type ExploreInput struct {
+	// Path is a file path, or an s3://, https:// or file:// URI whose
+	// content is fetched when Content is nil and the registry was built
+	// WithRemoteFetch.
 	Path    string
 	Content []byte
 	// Model is reserved for future model-specific strategies.
@@ -59,6 +65,13 @@ type ExploreResult struct {
 	ExplorerUsed    string
 	TokenEstimate   int
 	SpecificityTier SpecificityTier
+	// Facts is the structured content of Summary; nil for explorers that
+	// report text only.
+	Facts *Facts
+	// Skipped lists the explorers that matched the file before the one
+	// that produced the result but failed, timed out or were over their
+	// limits (see WithExplorerLimits).
+	Skipped []ExplorerSkip
 }
 
 // Explorer is the interface all file explorers implement.
@@ -69,6 +82,20 @@ type Explorer interface {
 	Explore(ctx context.Context, input ExploreInput) (ExploreResult, error)
 }
 
+// StreamExplorer is implemented by explorers that can summarize a file
+// without loading it into memory, so arbitrarily large files can be
+// explored with bounded memory.
+type StreamExplorer interface {
+	Explorer
+	// ExploreStream returns a structured summary of the size bytes read
+	// from r.
+	ExploreStream(ctx context.Context, path string, r io.ReaderAt, size int64) (ExploreResult, error)
+}
+
+// ErrStreamUnsupported is returned by Registry.ExploreStream when the
+// explorer selected for the file needs the whole content in memory.
+var ErrStreamUnsupported = errors.New("explorer does not support streaming")
+
 // explorerWithKind is an optional interface that explorers can implement to
 // declare their runtime kind (e.g. "code_format_enhanced" for tree-sitter).
 type explorerWithKind interface{ explorerKind() string }
@@ -119,6 +146,17 @@ type Registry struct {
 	agentFn          AgentFunc // nil when agent-based exploration is unavailable
 	tsParser         any
 	formatterProfile OutputProfile
+	remote           *RemoteOptions // nil when remote URIs are not accepted
+	metrics          *Metrics       // nil when explorations are not counted
+	limits           *explorerLimits
+	breaker          *circuitBreaker
+	cache            ExploreCache // nil when results are not cached
+	tokenCounter     TokenCounter // nil when TokenEstimate is heuristic
+	tokenModel       string
+	sqliteSampling   SQLiteSampling
+	archiveNesting   ArchiveNesting
+	outputLimits     map[OutputProfile]OutputLimits
+	observer         Observer // nil when explorations are not observed
 }
 
 // NewRegistry creates a registry with all built-in explorers.
@@ -145,12 +183,25 @@ func NewRegistry(opts ...RegistryOption) *Registry {
 		&VideoExplorer{},
 		// Phase 0i: Diagram files (text-based formats)
 		&DiagramExplorer{},
+		// Phase 0j: Columnar data files (before generic binary for footer metadata)
+		&ParquetExplorer{},
+		// Phase 0k: Git packfiles, indexes and bundles (before generic binary)
+		&GitObjectExplorer{},
 		// Phase 1: Generic binary catch-all
 		&BinaryExplorer{},
 		// Phase 2: Data/document explorers (checked before code)
+		&NotebookExplorer{},
+		&DiffExplorer{},
+		&ProtoExplorer{},
+		&GraphQLExplorer{},
+		&DockerfileExplorer{},
+		&LockfileExplorer{},
+		&BuildManifestExplorer{},
 		&JSONExplorer{},
-		&CSVExplorer{},
+		&TabularExplorer{},
+		&KubernetesExplorer{},
 		&YAMLExplorer{},
+		&DotenvExplorer{},
 		&TOMLExplorer{},
 		&INIExplorer{},
 		&XMLExplorer{},
@@ -159,6 +210,11 @@ func NewRegistry(opts ...RegistryOption) *Registry {
 		&LatexExplorer{},
 		&SQLiteExplorer{},
 		&LogsExplorer{},
+		// Phase 2b: Code without tree-sitter (specialized tree-sitter wins)
+		&SwiftExplorer{},
+		&KotlinExplorer{},
+		&PHPExplorer{},
+		&CSharpExplorer{},
 		// Phase 3: Shell scripts (checked before generic text)
 		&ShellExplorer{},
 		// Phase 4: Generic text fallback
@@ -176,6 +232,7 @@ func NewRegistry(opts ...RegistryOption) *Registry {
 		switch exp := e.(type) {
 		case *ArchiveExplorer:
 			exp.formatterProfile = r.formatterProfile
+			exp.nesting = r.archiveNesting
 			r.explorers[i] = exp
 		case *PDFExplorer:
 			exp.formatterProfile = r.formatterProfile
@@ -187,12 +244,68 @@ func NewRegistry(opts ...RegistryOption) *Registry {
 			exp.formatterProfile = r.formatterProfile
 			r.explorers[i] = exp
 		case *SQLiteExplorer:
+			exp.formatterProfile = r.formatterProfile
+			exp.sampling = r.sqliteSampling
+			r.explorers[i] = exp
+		case *XMLExplorer:
 			exp.formatterProfile = r.formatterProfile
 			r.explorers[i] = exp
 		case *LatexExplorer:
 			exp.formatterProfile = r.formatterProfile
 			r.explorers[i] = exp
 		case *LogsExplorer:
+			exp.formatterProfile = r.formatterProfile
+			exp.samples = r.profileLimits().SampleSize
+			r.explorers[i] = exp
+		case *NotebookExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *TabularExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *ParquetExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *GitObjectExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *DiffExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *ProtoExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *GraphQLExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *KubernetesExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *DockerfileExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *LockfileExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *BuildManifestExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *DotenvExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *HTMLExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *SwiftExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *KotlinExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *PHPExplorer:
+			exp.formatterProfile = r.formatterProfile
+			r.explorers[i] = exp
+		case *CSharpExplorer:
 			exp.formatterProfile = r.formatterProfile
 			r.explorers[i] = exp
 		}
@@ -230,26 +343,44 @@ func NewRegistry(opts ...RegistryOption) *Registry {
 // Python exception: Python files skip tier 2 and go directly from tier 1 to
 // tier 3 when an agent is available.
 func (r *Registry) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	return r.observe(ctx, input.Path, false, func() (ExploreResult, error) {
+		return r.explore(ctx, input)
+	})
+}
+
+func (r *Registry) explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	// Remote URIs without content are fetched first (see WithRemoteFetch).
+	if input.Content == nil && IsRemoteURI(input.Path) {
+		resolved, err := r.resolveRemote(ctx, input)
+		if err != nil {
+			return ExploreResult{}, err
+		}
+		input = resolved
+	}
+
 	// Step 1: always run the static explorer to get a baseline result.
-	staticResult, err := r.exploreStatic(ctx, input)
+	staticResult, err := r.cachedStatic(ctx, input)
 	if err != nil {
 		return staticResult, err
 	}
 
 	// If no LLM capability is configured, return the static result (tier 1).
 	if r.llm == nil && r.agentFn == nil {
-		return staticResult, nil
+		return r.countTokens(ctx, staticResult), nil
 	}
 
 	// Attempt LLM-enhanced exploration (tiers 2 and 3).
 	enhanced := exploreLLMEnhanced(ctx, r.llm, r.agentFn, input, staticResult)
 	enhanced.SpecificityTier = staticResult.SpecificityTier
-	return formatExploreResult(enhanced, r.formatterProfile), nil
+	enhanced.Facts = staticResult.Facts
+	enhanced.Skipped = staticResult.Skipped
+	return r.countTokens(ctx, formatExploreResult(enhanced, r.formatterProfile, r.profileLimits())), nil
 }
 
 // exploreStatic runs the static (template-based) explorer chain using
 // three-tier specificity dispatch: specialized → family → generic.
 func (r *Registry) exploreStatic(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	var skipped []ExplorerSkip
 	for _, tier := range []SpecificityTier{SpecificitySpecialized, SpecificityFamily, SpecificityGeneric} {
 		for _, e := range r.explorers {
 			if explorerSpecificity(e) != tier {
@@ -258,17 +389,62 @@ func (r *Registry) exploreStatic(ctx context.Context, input ExploreInput) (Explo
 			if !e.CanHandle(input.Path, input.Content) {
 				continue
 			}
-			result, err := e.Explore(ctx, input)
-			if err != nil {
+			start := time.Now()
+			result, skip := r.runExplorer(ctx, e, input)
+			if skip != nil {
+				skipped = append(skipped, *skip)
 				continue
 			}
 			result.SpecificityTier = tier
-			return formatExploreResult(result, r.formatterProfile), nil
+			result.Skipped = skipped
+			r.metrics.record(result, input.Path, int64(len(input.Content)), time.Since(start))
+			return formatExploreResult(result, r.formatterProfile, r.profileLimits()), nil
 		}
 	}
 	// Should never reach here since FallbackExplorer handles everything.
-	result := ExploreResult{Summary: "Unknown file type", ExplorerUsed: "fallback", SpecificityTier: SpecificityGeneric}
-	return formatExploreResult(result, r.formatterProfile), nil
+	result := ExploreResult{Summary: "Unknown file type", ExplorerUsed: "fallback", SpecificityTier: SpecificityGeneric, Skipped: skipped}
+	return formatExploreResult(result, r.formatterProfile, r.profileLimits()), nil
+}
+
+// ExploreStream explores a file without loading it into memory. The
+// explorer is selected as in Explore, from the path and the first
+// SampleChunkSize bytes; when it is not a StreamExplorer,
+// ErrStreamUnsupported is returned and the caller should fall back to
+// Explore with the full content. Only the static tier runs.
+func (r *Registry) ExploreStream(ctx context.Context, path string, src io.ReaderAt, size int64) (ExploreResult, error) {
+	return r.observe(ctx, path, true, func() (ExploreResult, error) {
+		return r.exploreStream(ctx, path, src, size)
+	})
+}
+
+func (r *Registry) exploreStream(ctx context.Context, path string, src io.ReaderAt, size int64) (ExploreResult, error) {
+	head := make([]byte, min(size, SampleChunkSize))
+	n, err := src.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return ExploreResult{}, err
+	}
+	head = head[:n]
+
+	for _, tier := range []SpecificityTier{SpecificitySpecialized, SpecificityFamily, SpecificityGeneric} {
+		for _, e := range r.explorers {
+			if explorerSpecificity(e) != tier || !e.CanHandle(path, head) {
+				continue
+			}
+			se, ok := e.(StreamExplorer)
+			if !ok {
+				return ExploreResult{}, ErrStreamUnsupported
+			}
+			start := time.Now()
+			result, err := se.ExploreStream(ctx, path, src, size)
+			if err != nil {
+				return ExploreResult{}, err
+			}
+			result.SpecificityTier = tier
+			r.metrics.record(result, path, size, time.Since(start))
+			return r.countTokens(ctx, formatExploreResult(result, r.formatterProfile, r.profileLimits())), nil
+		}
+	}
+	return ExploreResult{}, ErrStreamUnsupported
 }
 
 // looksLikeText returns true if content appears to be text (not binary).
@@ -379,10 +555,11 @@ func detectShebang(content []byte) string {
 	parts := strings.FieldsSeq(line)
 	for part := range parts {
 		base := filepath.Base(part)
-		// Strip version suffix (python3.11 -> python3).
-		for lang, result := range shebangs {
-			if base == lang || strings.HasPrefix(base, lang) {
-				return result
+		// Strip version suffix (python3.11 -> python3). Longer names are
+		// tried first so "ruby" is not taken for "r".
+		for _, lang := range keysLongestFirst(shebangs) {
+			if strings.HasPrefix(base, lang) {
+				return shebangs[lang]
 			}
 		}
 	}
@@ -488,3 +488,16 @@ func explorerTypeName(e Explorer) string {
 		return "Unknown"
 	}
 }
+
+func TestFacts_KeptThroughLLM(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistryWithLLM(&mockLLM{response: "A deploy script."}, nil)
+	result, err := registry.Explore(context.Background(), ExploreInput{
+		Path:    "deploy.sh",
+		Content: []byte("deploy() {\n  echo hi\n}\n"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "shell+llm", result.ExplorerUsed)
+	require.Equal(t, []SymbolInfo{{Name: "deploy", Kind: "function", StartLine: 1}}, result.Facts.Symbols)
+}
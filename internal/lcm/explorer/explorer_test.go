@@ -26,7 +26,7 @@ func TestJSONExplorer(t *testing.T) {
 
 	reg := NewRegistry()
 	result, err := reg.Explore(context.Background(), ExploreInput{
-		Path:    "package.json",
+		Path:    "app.json",
 		Content: content,
 	})
 	require.NoError(t, err, "Explore failed")
@@ -44,7 +44,7 @@ var TEXT_EXTENSIONS = map[string]string{
 	"yaml": "yaml", "yml": "yaml",
 	"toml": "toml",
 	"xml":  "xml", "xsd": "xml", "xsl": "xml", "xslt": "xml",
-	"csv": "csv", "tsv": "csv",
+	"csv": "csv", "tsv": "csv", "psv": "csv",
 	"ini": "ini", "cfg": "ini", "conf": "ini", "config": "ini", "properties": "ini",
 	// Markdown/docs
 	"md": "markdown", "markdown": "markdown",
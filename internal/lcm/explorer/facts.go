@@ -0,0 +1,48 @@
+package explorer
+
+// Facts is the structured payload behind an exploration summary, so
+// consumers such as the repo map and the TUI can use what an explorer found
+// without scraping the text. Explorers fill the fields they know; a nil
+// Facts means the explorer reports text only.
+type Facts struct {
+	// Symbols are the declarations found, in file order, with names
+	// qualified by their enclosing types.
+	Symbols []SymbolInfo
+	// Imports are the imported modules, packages or paths, in file order.
+	Imports []string
+	// Counts are named quantities, such as "lines" or "heading_total". Keys
+	// are lower snake case and stable across output profiles.
+	Counts map[string]int64
+	// Sections are the document or code regions, in file order.
+	Sections []CodeSection
+}
+
+// count sets the named count, allocating Counts on first use.
+func (f *Facts) count(name string, n int) {
+	if f.Counts == nil {
+		f.Counts = make(map[string]int64)
+	}
+	f.Counts[name] = int64(n)
+}
+
+// FileStructure returns the symbols, imports and sections of f as a
+// FileStructure. Symbols without a section of their own get one spanning
+// their lines, as ParseFileStructure does.
+func (f *Facts) FileStructure() *FileStructure {
+	fs := &FileStructure{
+		Symbols: f.Symbols,
+		Imports: f.Imports,
+	}
+	fs.Sections = append(fs.Sections, f.Sections...)
+	if len(fs.Sections) == 0 {
+		for _, sym := range f.Symbols {
+			fs.Sections = append(fs.Sections, CodeSection{
+				Name:      sym.Name,
+				Type:      sym.Kind,
+				StartLine: sym.StartLine,
+				EndLine:   sym.EndLine,
+			})
+		}
+	}
+	return fs
+}
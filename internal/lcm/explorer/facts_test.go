@@ -0,0 +1,75 @@
+package explorer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacts_Markdown(t *testing.T) {
+	t.Parallel()
+
+	content := "---\ntitle: Guide\ntags: [a]\n---\n# Guide\n\nSee [docs](https://x.dev).\n\n## Install\n\n```go\nx := 1\n```\n\nUsage\n-----\n"
+	result, err := (&MarkdownExplorer{}).Explore(context.Background(), ExploreInput{Path: "guide.md", Content: []byte(content)})
+	require.NoError(t, err)
+	require.NotNil(t, result.Facts)
+
+	f := result.Facts
+	require.Equal(t, int64(2), f.Counts["frontmatter_keys"])
+	require.Equal(t, int64(3), f.Counts["heading_total"])
+	require.Equal(t, int64(1), f.Counts["h1"])
+	require.Equal(t, int64(2), f.Counts["h2"])
+	require.Equal(t, int64(1), f.Counts["code_blocks"])
+	require.Equal(t, int64(1), f.Counts["inline_links"])
+	require.Equal(t, []CodeSection{
+		{Name: "Guide", Type: "h1", StartLine: 5},
+		{Name: "Install", Type: "h2", StartLine: 9},
+		{Name: "Usage", Type: "h2", StartLine: 15},
+	}, f.Sections)
+}
+
+func TestFacts_Code(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&CSharpExplorer{}).Explore(context.Background(), ExploreInput{Path: "UserStore.cs", Content: []byte(testCSharp)})
+	require.NoError(t, err)
+
+	f := result.Facts
+	require.NotNil(t, f)
+	require.Equal(t, []string{"System", "System.Collections.Generic", "System.Math", "Newtonsoft.Json.JsonConvert", "Acme.Core.Data"}, f.Imports)
+	require.Len(t, f.Symbols, 21)
+	require.Equal(t, SymbolInfo{Name: "IUserStore.FindAsync", Kind: "method", StartLine: 12}, f.Symbols[1])
+	require.Equal(t, int64(21), f.Counts["declarations"])
+	require.Equal(t, int64(15), f.Counts["public"])
+	require.Equal(t, int64(3), f.Counts["private"])
+}
+
+func TestFacts_LatexAndShell(t *testing.T) {
+	t.Parallel()
+
+	latex := "\\usepackage{amsmath}\n\\section{Intro}\ntext \\cite{knuth}\n\\subsection{Scope}\n\\begin{figure}\n\\end{figure}\n"
+	result, err := (&LatexExplorer{}).Explore(context.Background(), ExploreInput{Path: "paper.tex", Content: []byte(latex)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"amsmath"}, result.Facts.Imports)
+	require.Equal(t, []CodeSection{
+		{Name: "Intro", Type: "section", StartLine: 2},
+		{Name: "Scope", Type: "subsection", StartLine: 4},
+	}, result.Facts.Sections)
+	require.Equal(t, int64(1), result.Facts.Counts["citations"])
+	require.Equal(t, int64(1), result.Facts.Counts["env_figure"])
+
+	// Shell functions are symbols, which the summary-scraping fallback
+	// would read as imports.
+	fs, err := NewRegistry().ExploreStructured(context.Background(), ExploreInput{
+		Path:    "deploy.sh",
+		Content: []byte("#!/bin/bash\nsource ./env.sh\n\nfunction deploy() {\n  echo hi\n}\nREGION=eu\n"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"./env.sh"}, fs.Imports)
+	require.Equal(t, []SymbolInfo{
+		{Name: "deploy", Kind: "function", StartLine: 4},
+		{Name: "REGION", Kind: "variable"},
+	}, fs.Symbols)
+	require.Equal(t, CodeSection{Name: "deploy", Type: "function", StartLine: 4}, fs.Sections[0])
+}
@@ -53,9 +53,10 @@ func ParseFileStructure(summary string) *FileStructure {
 	fs := &FileStructure{}
 
 	// Pattern: "  - kind name (visibility, line N)" or "  - kind name (line N)"
-	// or "  - kind name (visibility)" or "  - kind name"
+	// or "  - kind name (visibility)" or "  - kind name"; qualifiers such as
+	// "export, async" may sit between the visibility and the line.
 	symbolRe := regexp.MustCompile(
-		`^\s+-\s+(\S+)\s+(\S+)\s+(?:\((\S+),\s+line\s+(\d+)\)|\((\S+)\)|\(line\s+(\d+)\))?$`,
+		`^\s+-\s+(\S+)\s+(\S+)\s+(?:\((\S+),\s+(?:[^()]*,\s+)?line\s+(\d+)\)|\((\S+)\)|\(line\s+(\d+)\))?$`,
 	)
 	// Simplified pattern that captures "kind name ..." lines.
 	symbolSimpleRe := regexp.MustCompile(
@@ -65,6 +66,12 @@ func ParseFileStructure(summary string) *FileStructure {
 	scanner := bufio.NewScanner(strings.NewReader(summary))
 	for scanner.Scan() {
 		line := scanner.Text()
+		// Enhancement output appends "): signature" to symbol lines.
+		if strings.HasPrefix(line, "  - ") {
+			if idx := strings.Index(line, "): "); idx > 0 {
+				line = line[:idx+1]
+			}
+		}
 
 		// Detect imports: "  - path (category)" or "  - path".
 		if strings.HasPrefix(line, "  - ") && !symbolRe.MatchString(line) {
@@ -141,10 +148,10 @@ func (fs *FileStructure) String() string {
 }
 
 // ExploreStructured returns a structured representation of a file. It uses
-// the first explorer that can handle the file and parses the text result into
-// a FileStructure. When the underlying explorer is a TreeSitterExplorer it
-// extracts symbols directly; otherwise it falls back to ParseFileStructure on
-// the text summary.
+// the first explorer that can handle the file and builds the FileStructure
+// from the result's Facts, falling back to ParseFileStructure on the text
+// summary when the explorer reports none. A TreeSitterExplorer is preferred
+// when a parser is configured.
 func (r *Registry) ExploreStructured(ctx context.Context, input ExploreInput) (*FileStructure, error) {
 	// Use tree-sitter explorer directly if available for richer data.
 	if r.tsParser != nil {
@@ -154,7 +161,7 @@ func (r *Registry) ExploreStructured(ctx context.Context, input ExploreInput) (*
 			if err != nil {
 				return nil, fmt.Errorf("structured explore failed: %w", err)
 			}
-			return ParseFileStructure(result.Summary), nil
+			return resultFileStructure(result), nil
 		}
 	}
 
@@ -163,5 +170,14 @@ func (r *Registry) ExploreStructured(ctx context.Context, input ExploreInput) (*
 	if err != nil {
 		return nil, fmt.Errorf("structured explore failed: %w", err)
 	}
-	return ParseFileStructure(result.Summary), nil
+	return resultFileStructure(result), nil
+}
+
+// resultFileStructure prefers the explorer's Facts and parses the summary
+// only for explorers that report text alone.
+func resultFileStructure(result ExploreResult) *FileStructure {
+	if result.Facts != nil {
+		return result.Facts.FileStructure()
+	}
+	return ParseFileStructure(result.Summary)
 }
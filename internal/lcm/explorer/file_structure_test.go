@@ -30,6 +30,28 @@ Symbols:
 	require.Equal(t, "function", fs.Sections[0].Type)
 }
 
+func TestParseFileStructure_SymbolsWithSignatures(t *testing.T) {
+	t.Parallel()
+
+	summary := `Tree-sitter file: app.ts
+Language: typescript
+
+Symbols:
+  - function load (public, export, async, line 3): (id: string): Promise<void>
+  - class App (public, export default, line 1)
+  - method run (public, async, @cached, line 9): (self, retries: int = 3) -> None`
+
+	fs := ParseFileStructure(summary)
+	require.Len(t, fs.Symbols, 3)
+	require.Equal(t, "load", fs.Symbols[0].Name)
+	require.Equal(t, 3, fs.Symbols[0].StartLine)
+	require.Equal(t, "App", fs.Symbols[1].Name)
+	require.Equal(t, 1, fs.Symbols[1].StartLine)
+	require.Equal(t, "run", fs.Symbols[2].Name)
+	require.Equal(t, 9, fs.Symbols[2].StartLine)
+	require.Empty(t, fs.Imports)
+}
+
 func TestParseFileStructure_SymbolsWithLineOnly(t *testing.T) {
 	t.Parallel()
 
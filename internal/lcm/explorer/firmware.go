@@ -0,0 +1,475 @@
+package explorer
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Binary profile limits.
+const (
+	// entropyRegions is the number of regions an entropy profile splits
+	// the content into, each at least entropyMinRegion bytes.
+	entropyRegions   = 32
+	entropyMinRegion = 4096
+	// stringClusterGap is the largest gap between two printable runs of
+	// the same cluster. String tables are dense; chance printable runs in
+	// compressed data are about 600 bytes apart.
+	stringClusterGap        = 16
+	stringClusterMinLen     = 6
+	stringClusterMinStrings = 4
+	maxStringClusters       = 8
+	stringClusterSamples    = 3
+	maxEmbeddedData         = 30
+	// compareBlockSize is the block size used to compare binaries that
+	// have no section table.
+	compareBlockSize   = 64 * 1024
+	maxComparedDetails = 50
+)
+
+type entropyRegion struct {
+	start, end int
+	entropy    float64
+}
+
+// entropyClass names what data of the given Shannon entropy (bits per
+// byte) usually is.
+func entropyClass(h float64) string {
+	switch {
+	case h < 1:
+		return "padding"
+	case h < 4.5:
+		return "sparse data"
+	case h < 6.5:
+		return "code or text"
+	case h < 7.5:
+		return "packed data"
+	default:
+		return "compressed or encrypted"
+	}
+}
+
+// byteEntropy returns the Shannon entropy of b in bits per byte.
+func byteEntropy(b []byte) float64 {
+	if len(b) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, c := range b {
+		counts[c]++
+	}
+	var h float64
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(b))
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// entropyProfile splits content into regions and merges neighbours of the
+// same entropy class.
+func entropyProfile(content []byte) []entropyRegion {
+	size := max(entropyMinRegion, (len(content)+entropyRegions-1)/entropyRegions)
+	var regions []entropyRegion
+	for start := 0; start < len(content); start += size {
+		end := min(start+size, len(content))
+		h := byteEntropy(content[start:end])
+		if n := len(regions); n > 0 && entropyClass(regions[n-1].entropy) == entropyClass(h) {
+			prev := &regions[n-1]
+			// Size-weighted mean of the merged regions.
+			prev.entropy = (prev.entropy*float64(prev.end-prev.start) + h*float64(end-start)) / float64(end-prev.start)
+			prev.end = end
+			continue
+		}
+		regions = append(regions, entropyRegion{start: start, end: end, entropy: h})
+	}
+	return regions
+}
+
+type stringCluster struct {
+	start, end int
+	strings    []string
+}
+
+// stringClusters groups printable ASCII runs that lie close together, such
+// as a string table or an embedded script, largest first.
+func stringClusters(content []byte) []stringCluster {
+	var clusters []stringCluster
+	runStart := -1
+	flush := func(end int) {
+		if runStart < 0 {
+			return
+		}
+		if end-runStart >= stringClusterMinLen {
+			s := string(content[runStart:end])
+			if n := len(clusters); n > 0 && runStart-clusters[n-1].end <= stringClusterGap {
+				clusters[n-1].end = end
+				clusters[n-1].strings = append(clusters[n-1].strings, s)
+			} else {
+				clusters = append(clusters, stringCluster{start: runStart, end: end, strings: []string{s}})
+			}
+		}
+		runStart = -1
+	}
+	for i, c := range content {
+		if c >= 0x20 && c < 0x7f || c == '\t' {
+			if runStart < 0 {
+				runStart = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(content))
+
+	// A few strings are noise in compressed data, not a cluster.
+	clusters = slices.DeleteFunc(clusters, func(c stringCluster) bool { return len(c.strings) < stringClusterMinStrings })
+	slices.SortStableFunc(clusters, func(a, b stringCluster) int {
+		return cmp.Or(cmp.Compare(len(b.strings), len(a.strings)), cmp.Compare(a.start, b.start))
+	})
+	return clusters[:min(len(clusters), maxStringClusters)]
+}
+
+// embeddedData is a format signature found inside a binary.
+type embeddedData struct {
+	offset int
+	desc   string
+	// size is the length the header declares, or 0 when unknown; hits
+	// inside it are not reported separately.
+	size int
+}
+
+// embeddedSignature recognizes one format by its magic bytes. check
+// validates the header at the match and describes it, returning ok=false
+// for chance matches.
+type embeddedSignature struct {
+	magic []byte
+	check func(b []byte) (desc string, size int, ok bool)
+}
+
+var embeddedSignatures = []embeddedSignature{
+	{[]byte("hsqs"), checkSquashfs},
+	{[]byte("sqsh"), func(b []byte) (string, int, bool) {
+		return "squashfs filesystem (big-endian, pre-4.0)", 0, len(b) >= 32
+	}},
+	{[]byte("070701"), checkCpio},
+	{[]byte("070702"), checkCpio},
+	{[]byte("070707"), checkCpio},
+	{[]byte("UBI#"), func(b []byte) (string, int, bool) {
+		return "UBI erase block", 0, len(b) > 4 && b[4] == 1
+	}},
+	{[]byte{0x45, 0x3d, 0xcd, 0x28}, func(b []byte) (string, int, bool) {
+		if len(b) < 32 || string(b[16:32]) != "Compressed ROMFS" {
+			return "", 0, false
+		}
+		size := int(binary.LittleEndian.Uint32(b[4:8]))
+		return fmt.Sprintf("cramfs filesystem (%d bytes)", size), size, true
+	}},
+	{[]byte{0x27, 0x05, 0x19, 0x56}, checkUImage},
+	{[]byte{0xd0, 0x0d, 0xfe, 0xed}, func(b []byte) (string, int, bool) {
+		if len(b) < 24 {
+			return "", 0, false
+		}
+		size := int(binary.BigEndian.Uint32(b[4:8]))
+		version := binary.BigEndian.Uint32(b[20:24])
+		if version < 16 || version > 17 || size < 40 {
+			return "", 0, false
+		}
+		return fmt.Sprintf("device tree blob (%d bytes)", size), size, true
+	}},
+	{[]byte{0x1f, 0x8b, 0x08}, func(b []byte) (string, int, bool) {
+		// Reserved flag bits must be clear.
+		return "gzip stream", 0, len(b) > 3 && b[3]&0xe0 == 0
+	}},
+	{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, func(b []byte) (string, int, bool) { return "xz stream", 0, true }},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, func(b []byte) (string, int, bool) { return "zstd frame", 0, true }},
+	{[]byte("PK\x03\x04"), func(b []byte) (string, int, bool) {
+		return "ZIP archive", 0, len(b) >= 30 && b[5] == 0
+	}},
+	{[]byte("\x7fELF"), func(b []byte) (string, int, bool) {
+		if len(b) < 6 || b[4] < 1 || b[4] > 2 || b[5] < 1 || b[5] > 2 {
+			return "", 0, false
+		}
+		return fmt.Sprintf("ELF %d-bit executable", 32*int(b[4])), 0, true
+	}},
+}
+
+// squashfsCompressors are the compression ids of the squashfs 4.0 superblock.
+var squashfsCompressors = map[uint16]string{1: "gzip", 2: "lzma", 3: "lzo", 4: "xz", 5: "lz4", 6: "zstd"}
+
+func checkSquashfs(b []byte) (string, int, bool) {
+	if len(b) < 48 || binary.LittleEndian.Uint16(b[28:30]) != 4 {
+		return "", 0, false
+	}
+	size := int(binary.LittleEndian.Uint64(b[40:48]))
+	comp := cmp.Or(squashfsCompressors[binary.LittleEndian.Uint16(b[20:22])], "unknown")
+	inodes := binary.LittleEndian.Uint32(b[4:8])
+	return fmt.Sprintf("squashfs 4.%d filesystem (%s, %d inodes, %d bytes)", binary.LittleEndian.Uint16(b[30:32]), comp, inodes, size), size, true
+}
+
+func checkCpio(b []byte) (string, int, bool) {
+	if bytes.HasPrefix(b, []byte("070707")) {
+		// Portable ASCII (odc): 76-byte header of octal fields.
+		if len(b) < 76 || !isDigits(b[6:76], 8) {
+			return "", 0, false
+		}
+		return "cpio archive (odc)", 0, true
+	}
+	// newc/crc: 110-byte header of hex fields, name size at offset 94.
+	if len(b) < 110 || !isDigits(b[6:110], 16) {
+		return "", 0, false
+	}
+	format := "newc"
+	if b[5] == '2' {
+		format = "crc"
+	}
+	var nameSize int
+	fmt.Sscanf(string(b[94:102]), "%08x", &nameSize)
+	if nameSize > 1 && 110+nameSize-1 <= len(b) {
+		return fmt.Sprintf("cpio archive (%s, first entry %s)", format, b[110:110+nameSize-1]), 0, true
+	}
+	return fmt.Sprintf("cpio archive (%s)", format), 0, true
+}
+
+func isDigits(b []byte, base int) bool {
+	for _, c := range b {
+		switch {
+		case c >= '0' && c <= '7':
+		case base == 16 && (c >= '8' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func checkUImage(b []byte) (string, int, bool) {
+	if len(b) < 64 {
+		return "", 0, false
+	}
+	size := int(binary.BigEndian.Uint32(b[12:16])) + 64
+	name := strings.TrimRight(string(b[32:64]), "\x00")
+	if name == "" {
+		return fmt.Sprintf("U-Boot uImage (%d bytes)", size), size, true
+	}
+	return fmt.Sprintf("U-Boot uImage %q (%d bytes)", name, size), size, true
+}
+
+// findEmbeddedData scans content for known format signatures, skipping
+// those inside an earlier hit of known size and a signature at offset 0
+// that only repeats the file's own format.
+func findEmbeddedData(content []byte) []embeddedData {
+	var hits []embeddedData
+	for _, sig := range embeddedSignatures {
+		for off := 0; ; {
+			i := bytes.Index(content[off:], sig.magic)
+			if i < 0 {
+				break
+			}
+			at := off + i
+			if desc, size, ok := sig.check(content[at:]); ok {
+				hits = append(hits, embeddedData{offset: at, desc: desc, size: size})
+			}
+			off = at + 1
+		}
+	}
+	slices.SortFunc(hits, func(a, b embeddedData) int { return cmp.Compare(a.offset, b.offset) })
+
+	var out []embeddedData
+	end := 0
+	for _, h := range hits {
+		if h.offset < end {
+			continue
+		}
+		out = append(out, h)
+		if h.size > 0 {
+			end = h.offset + h.size
+		}
+		// A cpio archive is a run of headers; report its first only.
+		if strings.HasPrefix(h.desc, "cpio") {
+			if trailer := bytes.Index(content[h.offset:], []byte("TRAILER!!!")); trailer >= 0 {
+				end = h.offset + trailer
+			}
+		}
+	}
+	return out
+}
+
+// writeBinaryProfile writes the entropy profile, string clusters and
+// embedded data of content: what a firmware image or packed artifact is
+// made of when no tool understands it.
+func writeBinaryProfile(summary *strings.Builder, content []byte) {
+	if len(content) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\nEntropy profile (%.2f bits/byte overall):\n", byteEntropy(content))
+	for _, r := range entropyProfile(content) {
+		fmt.Fprintf(summary, "  - 0x%08x-0x%08x: %.2f (%s)\n", r.start, r.end, r.entropy, entropyClass(r.entropy))
+	}
+
+	if clusters := stringClusters(content); len(clusters) > 0 {
+		summary.WriteString("\nString clusters:\n")
+		for _, c := range clusters {
+			samples := make([]string, 0, stringClusterSamples)
+			for _, s := range c.strings[:min(len(c.strings), stringClusterSamples)] {
+				samples = append(samples, fmt.Sprintf("%q", truncateRunes(strings.TrimSpace(s), 40)))
+			}
+			fmt.Fprintf(summary, "  - 0x%08x-0x%08x: %d strings, e.g. %s\n", c.start, c.end, len(c.strings), strings.Join(samples, ", "))
+		}
+	}
+
+	if hits := findEmbeddedData(content); len(hits) > 0 {
+		summary.WriteString("\nEmbedded data:\n")
+		for _, h := range hits[:min(len(hits), maxEmbeddedData)] {
+			fmt.Fprintf(summary, "  - 0x%08x: %s\n", h.offset, h.desc)
+		}
+		if len(hits) > maxEmbeddedData {
+			fmt.Fprintf(summary, "  - ... and %d more\n", len(hits)-maxEmbeddedData)
+		}
+	}
+}
+
+// binarySection is a named byte range of a binary and its hash.
+type binarySection struct {
+	name string
+	size int
+	hash string
+}
+
+// binarySections returns the section table of an ELF, PE or Mach-O binary
+// with a SHA-256 per section, or fixed-size blocks for anything else. The
+// string names the layout used.
+func binarySections(content []byte) (string, []binarySection) {
+	r := bytes.NewReader(content)
+	if f, err := elf.NewFile(r); err == nil {
+		var out []binarySection
+		for _, s := range f.Sections {
+			if s.Name == "" {
+				continue
+			}
+			data, _ := s.Data() // SHT_NOBITS sections have no data.
+			out = append(out, binarySection{name: s.Name, size: int(s.Size), hash: shortHash(data)})
+		}
+		return "ELF sections", out
+	}
+	if f, err := pe.NewFile(r); err == nil {
+		var out []binarySection
+		for _, s := range f.Sections {
+			data, _ := s.Data()
+			out = append(out, binarySection{name: s.Name, size: int(s.Size), hash: shortHash(data)})
+		}
+		return "PE sections", out
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		var out []binarySection
+		for _, s := range f.Sections {
+			data, _ := s.Data()
+			out = append(out, binarySection{name: s.Seg + "," + s.Name, size: int(s.Size), hash: shortHash(data)})
+		}
+		return "Mach-O sections", out
+	}
+	return blockSections(content)
+}
+
+// blockSections splits content into compareBlockSize blocks named by
+// offset.
+func blockSections(content []byte) (string, []binarySection) {
+	var out []binarySection
+	for start := 0; start < len(content); start += compareBlockSize {
+		end := min(start+compareBlockSize, len(content))
+		out = append(out, binarySection{name: fmt.Sprintf("0x%08x", start), size: end - start, hash: shortHash(content[start:end])})
+	}
+	return fmt.Sprintf("%d KiB blocks", compareBlockSize/1024), out
+}
+
+// shortHash is the first 12 hex digits of the SHA-256 of data.
+func shortHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// CompareBinaries compares two builds of a binary section by section:
+// sections are matched by name and compared by SHA-256, so a changed
+// .text with an unchanged .rodata shows up as exactly that. Binaries
+// without a section table are compared in fixed-size blocks.
+func CompareBinaries(oldInput, newInput ExploreInput) ExploreResult {
+	oldLayout, oldSections := binarySections(oldInput.Content)
+	newLayout, newSections := binarySections(newInput.Content)
+	layout := newLayout
+	if oldLayout != newLayout {
+		// Section names only match within one format; compare blocks.
+		layout, oldSections = blockSections(oldInput.Content)
+		_, newSections = blockSections(newInput.Content)
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Binary comparison: %s -> %s\n", filepath.Base(oldInput.Path), filepath.Base(newInput.Path))
+	fmt.Fprintf(&summary, "Size: %d -> %d bytes (%+d)\n", len(oldInput.Content), len(newInput.Content), len(newInput.Content)-len(oldInput.Content))
+	oldSum, newSum := sha256.Sum256(oldInput.Content), sha256.Sum256(newInput.Content)
+	if oldSum == newSum {
+		fmt.Fprintf(&summary, "Identical: sha256 %s\n", hex.EncodeToString(oldSum[:]))
+		result := summary.String()
+		return ExploreResult{Summary: result, ExplorerUsed: "binary-diff", TokenEstimate: estimateTokens(result)}
+	}
+	fmt.Fprintf(&summary, "SHA-256: %s -> %s\n", hex.EncodeToString(oldSum[:6]), hex.EncodeToString(newSum[:6]))
+	fmt.Fprintf(&summary, "Compared by: %s\n", layout)
+	if oldLayout != newLayout {
+		fmt.Fprintf(&summary, "Layout changed: %s -> %s\n", oldLayout, newLayout)
+	}
+
+	oldByName := make(map[string]binarySection, len(oldSections))
+	for _, s := range oldSections {
+		oldByName[s.name] = s
+	}
+	var changed, added, removed []string
+	unchanged := 0
+	seen := make(map[string]bool, len(newSections))
+	for _, s := range newSections {
+		seen[s.name] = true
+		old, ok := oldByName[s.name]
+		switch {
+		case !ok:
+			added = append(added, fmt.Sprintf("%s (%d bytes)", s.name, s.size))
+		case old.hash == s.hash:
+			unchanged++
+		default:
+			changed = append(changed, fmt.Sprintf("%s: %d -> %d bytes (%s -> %s)", s.name, old.size, s.size, old.hash, s.hash))
+		}
+	}
+	for _, s := range oldSections {
+		if !seen[s.name] {
+			removed = append(removed, fmt.Sprintf("%s (%d bytes)", s.name, s.size))
+		}
+	}
+	fmt.Fprintf(&summary, "Sections: %d unchanged, %d changed, %d added, %d removed\n", unchanged, len(changed), len(added), len(removed))
+	for _, section := range []struct {
+		title string
+		lines []string
+	}{{"Changed sections", changed}, {"Added sections", added}, {"Removed sections", removed}} {
+		if len(section.lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&summary, "\n%s:\n", section.title)
+		for _, line := range section.lines[:min(len(section.lines), maxComparedDetails)] {
+			fmt.Fprintf(&summary, "  - %s\n", line)
+		}
+		if len(section.lines) > maxComparedDetails {
+			fmt.Fprintf(&summary, "  - ... and %d more\n", len(section.lines)-maxComparedDetails)
+		}
+	}
+
+	result := summary.String()
+	return ExploreResult{Summary: result, ExplorerUsed: "binary-diff", TokenEstimate: estimateTokens(result)}
+}
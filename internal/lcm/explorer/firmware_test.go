@@ -0,0 +1,200 @@
+package explorer
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randomBytes returns n bytes from a fixed-seed generator: entropy close to
+// 8 bits/byte, like compressed or encrypted data.
+func randomBytes(n int) []byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.Uint32())
+	}
+	return b
+}
+
+// buildSquashfsSuperblock returns a squashfs 4.0 superblock declaring a
+// size-byte, xz-compressed filesystem of 42 inodes.
+func buildSquashfsSuperblock(size int) []byte {
+	b := make([]byte, 96)
+	copy(b, "hsqs")
+	binary.LittleEndian.PutUint32(b[4:8], 42)
+	binary.LittleEndian.PutUint16(b[20:22], 4)
+	binary.LittleEndian.PutUint16(b[28:30], 4)
+	binary.LittleEndian.PutUint64(b[40:48], uint64(size))
+	return b
+}
+
+// buildCpioEntry returns a newc cpio header for name, followed by the name.
+// Every field but the name size is zero.
+func buildCpioEntry(name string) []byte {
+	header := fmt.Sprintf("070701%s%08X%08X", strings.Repeat("0", 88), len(name)+1, 0)
+	return append([]byte(header), append([]byte(name), 0)...)
+}
+
+// buildUImageHeader returns a U-Boot legacy image header for a
+// payloadSize-byte payload.
+func buildUImageHeader(name string, payloadSize int) []byte {
+	b := make([]byte, 64)
+	binary.BigEndian.PutUint32(b[0:4], 0x27051956)
+	binary.BigEndian.PutUint32(b[12:16], uint32(payloadSize))
+	copy(b[32:], name)
+	return b
+}
+
+func TestEntropyProfile(t *testing.T) {
+	t.Parallel()
+
+	require.Zero(t, byteEntropy(make([]byte, 100)))
+	require.InDelta(t, 8, byteEntropy(randomBytes(1<<16)), 0.01)
+	require.Equal(t, "padding", entropyClass(0))
+	require.Equal(t, "code or text", entropyClass(5))
+	require.Equal(t, "compressed or encrypted", entropyClass(7.9))
+
+	// Padding, then compressed data: two regions, each merged.
+	content := append(make([]byte, 64*1024), randomBytes(64*1024)...)
+	regions := entropyProfile(content)
+	require.Len(t, regions, 2)
+	require.Equal(t, entropyRegion{start: 0, end: 64 * 1024}, regions[0])
+	require.Equal(t, 64*1024, regions[1].start)
+	require.Equal(t, len(content), regions[1].end)
+	require.Equal(t, "compressed or encrypted", entropyClass(regions[1].entropy))
+}
+
+func TestStringClusters(t *testing.T) {
+	t.Parallel()
+
+	content := randomBytes(8192)
+	table := []byte("usage: fwtool\x00--flash\x00--verify-image\x00/dev/mtdblock3\x00")
+	copy(content[4096:], table)
+	require.Empty(t, stringClusters(randomBytes(1<<20)))
+
+	clusters := stringClusters(content)
+	require.Len(t, clusters, 1)
+	require.Equal(t, 4096, clusters[0].start)
+	require.Equal(t, []string{"usage: fwtool", "--flash", "--verify-image", "/dev/mtdblock3"}, clusters[0].strings)
+}
+
+func TestFindEmbeddedData(t *testing.T) {
+	t.Parallel()
+
+	content := buildUImageHeader("Linux-5.15", 1024)
+	content = append(content, make([]byte, 1024)...)
+	// A squashfs magic inside the uImage payload is not reported.
+	copy(content[200:], "hsqs")
+
+	content = append(content, buildSquashfsSuperblock(4096)...)
+	content = append(content, make([]byte, 4096-96)...)
+
+	cpioAt := len(content)
+	content = append(content, buildCpioEntry("init")...)
+	content = append(content, buildCpioEntry("bin/busybox")...)
+	content = append(content, buildCpioEntry("TRAILER!!!")...)
+
+	hits := findEmbeddedData(content)
+	require.Len(t, hits, 3)
+	require.Equal(t, embeddedData{offset: 0, desc: `U-Boot uImage "Linux-5.15" (1088 bytes)`, size: 1088}, hits[0])
+	require.Equal(t, 1088, hits[1].offset)
+	require.Equal(t, "squashfs 4.0 filesystem (xz, 42 inodes, 4096 bytes)", hits[1].desc)
+	require.Equal(t, cpioAt, hits[2].offset)
+	require.Equal(t, "cpio archive (newc, first entry init)", hits[2].desc)
+}
+
+func TestCompareBinaries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("identical", func(t *testing.T) {
+		t.Parallel()
+		content := randomBytes(1000)
+		result := CompareBinaries(
+			ExploreInput{Path: "a/fw.bin", Content: content},
+			ExploreInput{Path: "b/fw.bin", Content: content},
+		)
+		require.Equal(t, "binary-diff", result.ExplorerUsed)
+		require.Contains(t, result.Summary, "Identical: sha256 ")
+		require.NotContains(t, result.Summary, "Sections:")
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		t.Parallel()
+		oldContent := randomBytes(3 * compareBlockSize)
+		newContent := append([]byte(nil), oldContent...)
+		newContent[compareBlockSize+10] ^= 0xff
+		newContent = append(newContent, 1, 2, 3)
+
+		s := CompareBinaries(
+			ExploreInput{Path: "fw-1.0.bin", Content: oldContent},
+			ExploreInput{Path: "fw-1.1.bin", Content: newContent},
+		).Summary
+		require.Contains(t, s, "Binary comparison: fw-1.0.bin -> fw-1.1.bin")
+		require.Contains(t, s, "Size: 196608 -> 196611 bytes (+3)")
+		require.Contains(t, s, "Compared by: 64 KiB blocks")
+		require.Contains(t, s, "Sections: 2 unchanged, 1 changed, 1 added, 0 removed")
+		require.Contains(t, s, "  - 0x00010000: 65536 -> 65536 bytes")
+		require.Contains(t, s, "  - 0x00030000 (3 bytes)")
+	})
+
+	t.Run("elf", func(t *testing.T) {
+		t.Parallel()
+		path, err := os.Executable()
+		require.NoError(t, err)
+		oldContent, err := os.ReadFile(path)
+		require.NoError(t, err)
+		layout, sections := binarySections(oldContent)
+		if layout != "ELF sections" {
+			t.Skipf("test binary is not ELF: %s", layout)
+		}
+
+		// Flip one byte inside .rodata only.
+		var rodata binarySection
+		for _, s := range sections {
+			if s.name == ".rodata" {
+				rodata = s
+			}
+		}
+		require.NotZero(t, rodata.size)
+		idx := strings.Index(string(oldContent), "binary-diff")
+		require.Positive(t, idx)
+		newContent := append([]byte(nil), oldContent...)
+		newContent[idx] = 'B'
+
+		s := CompareBinaries(
+			ExploreInput{Path: "old", Content: oldContent},
+			ExploreInput{Path: "new", Content: newContent},
+		).Summary
+		require.Contains(t, s, "Compared by: ELF sections")
+		require.Contains(t, s, ", 1 changed, 0 added, 0 removed")
+		require.Contains(t, s, "  - .rodata: ")
+	})
+}
+
+func TestExecutableExplorer_FirmwareProfile(t *testing.T) {
+	t.Parallel()
+
+	content := buildSquashfsSuperblock(8192)
+	content = append(content, randomBytes(8192-96)...)
+	content = append(content, make([]byte, 8192)...)
+	input := ExploreInput{Path: "rootfs.img", Content: content}
+
+	parity, err := (&ExecutableExplorer{formatterProfile: OutputProfileParity}).Explore(context.Background(), input)
+	require.NoError(t, err)
+	require.Contains(t, parity.Summary, "Format: Firmware image (squashfs 4.0 filesystem (xz, 42 inodes, 8192 bytes))")
+	require.NotContains(t, parity.Summary, "Entropy profile")
+
+	enhanced, err := (&ExecutableExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), input)
+	require.NoError(t, err)
+	s := enhanced.Summary
+	require.Contains(t, s, "Entropy profile (")
+	require.Contains(t, s, "  - 0x00002000-0x00004000: 0.00 (padding)")
+	require.Contains(t, s, "Embedded data:\n  - 0x00000000: squashfs 4.0 filesystem")
+}
@@ -1,6 +1,7 @@
 package explorer
 
 import (
+	"cmp"
 	"fmt"
 	"sort"
 	"strings"
@@ -11,6 +12,46 @@ const (
 	defaultSectionLineLimit = 16
 )
 
+// OutputLimits caps how much of a summary the formatter keeps under the
+// parity and enhancement profiles; the verbose profile keeps everything.
+// Zero fields take their defaults.
+type OutputLimits struct {
+	// SectionItems is how many items a section lists. Defaults to 8.
+	SectionItems int
+	// SectionLines is how many lines of raw content, such as a text
+	// file's sampled content, a section keeps. Defaults to 16.
+	SectionLines int
+	// TotalLines caps the lines of the whole summary. Defaults to no cap.
+	TotalLines int
+	// SampleSize is how many error and warning lines are sampled from a
+	// log file. Defaults to 10.
+	SampleSize int
+}
+
+func (l OutputLimits) withDefaults() OutputLimits {
+	l.SectionItems = cmp.Or(max(l.SectionItems, 0), defaultSectionItemLimit)
+	l.SectionLines = cmp.Or(max(l.SectionLines, 0), defaultSectionLineLimit)
+	l.TotalLines = max(l.TotalLines, 0)
+	l.SampleSize = cmp.Or(max(l.SampleSize, 0), maxSampleSize)
+	return l
+}
+
+// WithOutputLimits sets the limits of summaries formatted under profile.
+// Aliases share the limits of their profile, so compact sets parity's.
+func WithOutputLimits(profile OutputProfile, l OutputLimits) RegistryOption {
+	return func(r *Registry) {
+		if r.outputLimits == nil {
+			r.outputLimits = make(map[OutputProfile]OutputLimits)
+		}
+		r.outputLimits[normalizeProfile(profile)] = l
+	}
+}
+
+// profileLimits returns the output limits of the registry's profile.
+func (r *Registry) profileLimits() OutputLimits {
+	return r.outputLimits[normalizeProfile(r.formatterProfile)].withDefaults()
+}
+
 // OutputProfile controls formatter behavior for truncation/overflow markers.
 type OutputProfile string
 
@@ -33,14 +74,14 @@ type summarySection struct {
 	raw   bool
 }
 
-func formatExploreResult(result ExploreResult, profile OutputProfile) ExploreResult {
+func formatExploreResult(result ExploreResult, profile OutputProfile, limits OutputLimits) ExploreResult {
 	summary := strings.TrimSpace(result.Summary)
 	if summary == "" {
 		return result
 	}
 
 	normalized := normalizeProfile(profile)
-	formatted := formatSummary(summary, normalized)
+	formatted := formatSummary(summary, normalized, limits)
 	result.Summary = formatted
 	result.TokenEstimate = estimateTokens(formatted)
 	return result
@@ -57,7 +98,8 @@ func normalizeProfile(profile OutputProfile) OutputProfile {
 	}
 }
 
-func formatSummary(summary string, profile OutputProfile) string {
+func formatSummary(summary string, profile OutputProfile, limits OutputLimits) string {
+	limits = limits.withDefaults()
 	lines := strings.Split(strings.ReplaceAll(summary, "\r\n", "\n"), "\n")
 	header := "File summary"
 	for _, line := range lines {
@@ -75,10 +117,25 @@ func formatSummary(summary string, profile OutputProfile) string {
 	var out strings.Builder
 	fmt.Fprintf(&out, "## %s\n", header)
 	for _, section := range sections {
-		renderSection(&out, section, profile)
+		renderSection(&out, section, profile, limits)
 	}
 
-	return strings.TrimSpace(out.String())
+	formatted := strings.TrimSpace(out.String())
+	if profile == OutputProfileVerbose || limits.TotalLines == 0 {
+		return formatted
+	}
+	return capLines(formatted, limits.TotalLines, profile)
+}
+
+// capLines keeps the first n lines of summary and marks the rest as
+// truncated.
+func capLines(summary string, n int, profile OutputProfile) string {
+	lines := strings.Split(summary, "\n")
+	if len(lines) <= n {
+		return summary
+	}
+	extra := len(lines) - n
+	return strings.Join(lines[:n], "\n") + "\n- " + overflowMarker(profile, extra, true)
 }
 
 func parseSummarySections(lines []string) []summarySection {
@@ -135,7 +192,7 @@ func normalizeSummaryLine(line string) string {
 	return strings.TrimSpace(trimmed)
 }
 
-func renderSection(out *strings.Builder, section summarySection, profile OutputProfile) {
+func renderSection(out *strings.Builder, section summarySection, profile OutputProfile, limits OutputLimits) {
 	fmt.Fprintf(out, "\n### %s\n", section.title)
 	if profile == OutputProfileVerbose {
 		if section.raw {
@@ -148,12 +205,12 @@ func renderSection(out *strings.Builder, section summarySection, profile OutputP
 		return
 	}
 	if section.raw {
-		writeSectionLines(out, section.lines, defaultSectionLineLimit, profile, true)
+		writeSectionLines(out, section.lines, limits.SectionLines, profile, true)
 		return
 	}
 	items := dedupe(section.lines)
 	sort.Strings(items)
-	writeSectionLines(out, items, defaultSectionItemLimit, profile, false)
+	writeSectionLines(out, items, limits.SectionItems, profile, false)
 }
 
 func writeSectionLines(out *strings.Builder, lines []string, cap int, profile OutputProfile, raw bool) {
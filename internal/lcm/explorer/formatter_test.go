@@ -2,6 +2,7 @@ package explorer
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -31,8 +32,8 @@ Constants:
   - ALPHA
 `
 
-	formattedA := formatSummary(raw, OutputProfileEnhancement)
-	formattedB := formatSummary(raw, OutputProfileEnhancement)
+	formattedA := formatSummary(raw, OutputProfileEnhancement, OutputLimits{})
+	formattedB := formatSummary(raw, OutputProfileEnhancement, OutputLimits{})
 	require.Equal(t, formattedA, formattedB)
 
 	require.Contains(t, formattedA, "## Go file: main.go")
@@ -63,8 +64,8 @@ Functions:
   - ten
 `
 
-	enhancement := formatSummary(raw, OutputProfileEnhancement)
-	parity := formatSummary(raw, OutputProfileParity)
+	enhancement := formatSummary(raw, OutputProfileEnhancement, OutputLimits{})
+	parity := formatSummary(raw, OutputProfileParity, OutputLimits{})
 
 	require.Contains(t, enhancement, "... and 2 more")
 	require.Contains(t, parity, "(+2 more)")
@@ -95,8 +96,8 @@ line 17
 line 18
 `
 
-	enhancement := formatSummary(raw, OutputProfileEnhancement)
-	parity := formatSummary(raw, OutputProfileParity)
+	enhancement := formatSummary(raw, OutputProfileEnhancement, OutputLimits{})
+	parity := formatSummary(raw, OutputProfileParity, OutputLimits{})
 
 	require.Contains(t, enhancement, "[TRUNCATED] ... and 2 more lines")
 	require.Contains(t, parity, "[TRUNCATED] (+2 more lines)")
@@ -162,7 +163,7 @@ Functions:
   - main()
   - helper()
 `
-	golden.RequireEqual(t, []byte(formatSummary(raw, OutputProfileEnhancement)))
+	golden.RequireEqual(t, []byte(formatSummary(raw, OutputProfileEnhancement, OutputLimits{})))
 }
 
 func TestFormatSummary_GoldenParity(t *testing.T) {
@@ -188,7 +189,7 @@ Functions:
   - main()
   - helper()
 `
-	golden.RequireEqual(t, []byte(formatSummary(raw, OutputProfileParity)))
+	golden.RequireEqual(t, []byte(formatSummary(raw, OutputProfileParity, OutputLimits{})))
 }
 
 // TestFormatSummary_OverflowMarkerNormalization verifies that overflow markers
@@ -293,7 +294,7 @@ line 17
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			formatted := formatSummary(tt.raw, tt.profile)
+			formatted := formatSummary(tt.raw, tt.profile, OutputLimits{})
 			require.Contains(t, formatted, tt.expectedMarker)
 		})
 	}
@@ -369,11 +370,11 @@ Imports:
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			if tt.wantParity != "" {
-				parity := formatSummary(tt.raw, OutputProfileParity)
+				parity := formatSummary(tt.raw, OutputProfileParity, OutputLimits{})
 				require.Contains(t, parity, tt.wantParity)
 			}
 			if tt.wantEnhance != "" {
-				enhance := formatSummary(tt.raw, OutputProfileEnhancement)
+				enhance := formatSummary(tt.raw, OutputProfileEnhancement, OutputLimits{})
 				require.Contains(t, enhance, tt.wantEnhance)
 			}
 		})
@@ -485,7 +486,7 @@ Functions:
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			formatted := formatSummary(tt.raw, OutputProfileEnhancement)
+			formatted := formatSummary(tt.raw, OutputProfileEnhancement, OutputLimits{})
 
 			for _, must := range tt.mustPreserve {
 				require.Contains(t, formatted, must,
@@ -518,7 +519,7 @@ Constants:
   - VERSION
 `
 
-	formatted := formatSummary(raw, OutputProfileEnhancement)
+	formatted := formatSummary(raw, OutputProfileEnhancement, OutputLimits{})
 
 	// Verify h2 level for file header
 	require.Contains(t, formatted, "## Python file: app.py")
@@ -540,3 +541,63 @@ Constants:
 		lastIdx = idx
 	}
 }
+
+func TestFormatSummary_OutputLimits(t *testing.T) {
+	t.Parallel()
+
+	var raw strings.Builder
+	raw.WriteString("Go file: main.go\nFunctions:\n")
+	for i := range 20 {
+		fmt.Fprintf(&raw, "  - fn%02d\n", i)
+	}
+	raw.WriteString("Content:\n")
+	for i := range 30 {
+		fmt.Fprintf(&raw, "line %d\n", i)
+	}
+
+	defaults := formatSummary(raw.String(), OutputProfileEnhancement, OutputLimits{})
+	require.Contains(t, defaults, "- fn07\n- ... and 12 more")
+	require.Contains(t, defaults, "- line 15\n- [TRUNCATED] ... and 14 more lines")
+
+	raised := formatSummary(raw.String(), OutputProfileEnhancement, OutputLimits{SectionItems: 50, SectionLines: 50})
+	require.Contains(t, raised, "- fn19\n")
+	require.Contains(t, raised, "- line 29")
+	require.NotContains(t, raised, "more")
+
+	capped := formatSummary(raw.String(), OutputProfileParity, OutputLimits{TotalLines: 4})
+	require.Equal(t, "## Go file: main.go\n\n### Functions\n- fn00\n- [TRUNCATED] (+27 more lines)", capped)
+
+	// The verbose profile keeps everything whatever the limits.
+	verbose := formatSummary(raw.String(), OutputProfileVerbose, OutputLimits{SectionItems: 1, TotalLines: 4})
+	require.Contains(t, verbose, "- fn19\n")
+	require.Contains(t, verbose, "- line 29")
+}
+
+func TestRegistry_OutputLimitsPerProfile(t *testing.T) {
+	t.Parallel()
+
+	var raw strings.Builder
+	raw.WriteString("2024-01-01 10:00:00 INFO start\n")
+	for i := range 40 {
+		fmt.Fprintf(&raw, "2024-01-01 10:00:%02d ERROR failure %d\n", i, i)
+		fmt.Fprintf(&raw, "2024-01-01 10:00:%02d WARN slow %d\n", i, i)
+	}
+	input := ExploreInput{Path: "app.log", Content: []byte(raw.String())}
+
+	limits := OutputLimits{SectionItems: 100, SampleSize: 30}
+	r := NewRegistry(
+		WithOutputProfile(OutputProfileStandard),
+		WithOutputLimits(OutputProfileEnhancement, limits),
+		WithOutputLimits(OutputProfileParity, OutputLimits{SectionItems: 1}),
+	)
+	require.Equal(t, limits.withDefaults(), r.profileLimits())
+	result, err := r.Explore(context.Background(), input)
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "\n- 30. ")
+	require.NotContains(t, result.Summary, "\n- 31. ")
+	require.NotContains(t, result.Summary, "more")
+
+	parity := NewRegistry(WithOutputProfile(OutputProfileCompact), WithOutputLimits(OutputProfileParity, OutputLimits{SectionItems: 1}))
+	require.Equal(t, 1, parity.profileLimits().SectionItems)
+	require.NotEqual(t, r.cacheKey(input), parity.cacheKey(input))
+}
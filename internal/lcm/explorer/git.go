@@ -0,0 +1,856 @@
+package explorer
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/zlib"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GitObjectExplorer explores git internals that end up in front of the
+// agent: packfiles, pack indexes, the .git/index staging file, bundles and
+// loose objects. It reports object counts, pack statistics and the refs a
+// bundle carries instead of compressed noise. Packs and bundles are walked
+// object by object with bounded memory, so ExploreStream handles them at
+// any size. Object IDs are assumed to be SHA-1 unless a bundle declares
+// SHA-256.
+type GitObjectExplorer struct {
+	formatterProfile OutputProfile
+}
+
+var (
+	gitPackMagic      = []byte("PACK")
+	gitPackIndexMagic = []byte("\xfftOc")
+	gitIndexMagic     = []byte("DIRC")
+	gitBundleV2       = []byte("# v2 git bundle\n")
+	gitBundleV3       = []byte("# v3 git bundle\n")
+
+	// gitLooseObjectPath matches the path of a loose object:
+	// objects/<2 hex>/<38 or 62 hex>.
+	gitLooseObjectPath = regexp.MustCompile(`(?:^|/)objects/([0-9a-f]{2})/([0-9a-f]{38}|[0-9a-f]{62})$`)
+)
+
+const (
+	// gitPackMaxObjects caps the objects walked in one pack; statistics
+	// of larger packs cover the first objects only.
+	gitPackMaxObjects = 200_000
+	// gitMaxLargest is the number of largest objects listed in
+	// enhancement output.
+	gitMaxLargest = 10
+	// gitMaxListed caps the refs, paths and prerequisites listed.
+	gitMaxListed = 50
+	// gitBundleMaxHeader caps the bundle header read before the pack.
+	gitBundleMaxHeader = 16 << 20
+)
+
+// gitObjectTypes names the object types of pack entry headers, by type
+// number.
+var gitObjectTypes = map[byte]string{
+	1: "commit",
+	2: "tree",
+	3: "blob",
+	4: "tag",
+	6: "ofs-delta",
+	7: "ref-delta",
+}
+
+// gitIndexExtensions describes the known .git/index extensions.
+var gitIndexExtensions = map[string]string{
+	"TREE": "cache tree",
+	"REUC": "resolve undo",
+	"link": "split index",
+	"UNTR": "untracked cache",
+	"FSMN": "fsmonitor",
+	"EOIE": "end of index entries",
+	"IEOT": "index entry offsets",
+	"sdir": "sparse directories",
+}
+
+func (e *GitObjectExplorer) CanHandle(path string, content []byte) bool {
+	return gitFormat(path, content) != ""
+}
+
+// gitFormat returns which git file content is, or "" when it is none.
+func gitFormat(path string, content []byte) string {
+	version := func(magic []byte) uint32 {
+		if len(content) < len(magic)+4 || !bytes.HasPrefix(content, magic) {
+			return 0
+		}
+		return binary.BigEndian.Uint32(content[len(magic):])
+	}
+	switch {
+	case bytes.HasPrefix(content, gitBundleV2), bytes.HasPrefix(content, gitBundleV3):
+		return "bundle"
+	}
+	switch v := version(gitPackMagic); {
+	case v == 2 || v == 3:
+		return "pack"
+	}
+	switch v := version(gitPackIndexMagic); {
+	case v == 2:
+		return "pack-index"
+	}
+	switch v := version(gitIndexMagic); {
+	case v >= 2 && v <= 4:
+		return "index"
+	}
+	if gitLooseObjectPath.MatchString(filepath.ToSlash(path)) && len(content) >= 2 &&
+		content[0]&0x0f == 8 && (uint16(content[0])<<8|uint16(content[1]))%31 == 0 {
+		return "loose"
+	}
+	return ""
+}
+
+func (e *GitObjectExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	return e.explore(ctx, input.Path, bytes.NewReader(input.Content), int64(len(input.Content)))
+}
+
+// ExploreStream summarizes the git file at path read from r, which holds
+// size bytes.
+func (e *GitObjectExplorer) ExploreStream(ctx context.Context, path string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	return e.explore(ctx, path, r, size)
+}
+
+func (e *GitObjectExplorer) explore(ctx context.Context, path string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	head := make([]byte, min(size, 64))
+	if _, err := r.ReadAt(head, 0); err != nil && !errors.Is(err, io.EOF) {
+		return ExploreResult{}, err
+	}
+
+	var summary strings.Builder
+	name := filepath.Base(path)
+	switch gitFormat(path, head) {
+	case "bundle":
+		fmt.Fprintf(&summary, "Git bundle: %s\n", name)
+		fmt.Fprintf(&summary, "Size: %s\n", formatSize(uint64(size)))
+		e.exploreBundle(ctx, &summary, r, size)
+	case "pack-index":
+		fmt.Fprintf(&summary, "Git pack index: %s\n", name)
+		fmt.Fprintf(&summary, "Size: %s\n", formatSize(uint64(size)))
+		e.explorePackIndex(&summary, r, size)
+	case "index":
+		fmt.Fprintf(&summary, "Git index: %s\n", name)
+		fmt.Fprintf(&summary, "Size: %s\n", formatSize(uint64(size)))
+		e.exploreIndex(&summary, r, size)
+	case "loose":
+		fmt.Fprintf(&summary, "Git loose object: %s\n", gitLooseObjectID(path))
+		e.exploreLoose(&summary, r, size)
+	default:
+		fmt.Fprintf(&summary, "Git packfile: %s\n", name)
+		fmt.Fprintf(&summary, "Size: %s\n", formatSize(uint64(size)))
+		e.explorePack(ctx, &summary, io.NewSectionReader(r, 0, size), sha1.New)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "git",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// --- packfiles ---
+
+// gitPackObject is one object of interest of a pack.
+type gitPackObject struct {
+	typ    string
+	size   int64
+	offset int64
+	id     string // hex object ID, only computed in enhancement output
+}
+
+// gitPackStats is what walking a pack tells about it.
+type gitPackStats struct {
+	version  uint32
+	declared uint32
+	walked   int
+	types    map[string]int
+	// sizes are the uncompressed sizes by type; deltas count at their
+	// delta size, not the size of the object they rebuild.
+	sizes    map[string]int64
+	objBytes int64 // compressed bytes of the objects walked
+	maxChain int
+	largest  []gitPackObject
+	checksum string
+	// stopped is set when the walk ended before the declared count: the
+	// object limit was reached or the pack is truncated or corrupt.
+	stopped error
+}
+
+// countingReader counts the bytes read through it. It is an
+// io.ByteReader, so that zlib reads no further than each object.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// errPackLimit stops a walk at gitPackMaxObjects.
+var errPackLimit = errors.New("object limit reached")
+
+// walkPack reads the pack in r: its header, then each object header and
+// compressed body, up to the declared count or gitPackMaxObjects. Object
+// IDs of non-delta objects are hashed with newHash when withIDs is set.
+func walkPack(ctx context.Context, r *io.SectionReader, newHash func() hash.Hash, withIDs bool) (*gitPackStats, error) {
+	size := r.Size()
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("pack header: %w", err)
+	}
+	if !bytes.HasPrefix(header, gitPackMagic) {
+		return nil, errors.New("pack header: missing PACK signature")
+	}
+	st := &gitPackStats{
+		version:  binary.BigEndian.Uint32(header[4:8]),
+		declared: binary.BigEndian.Uint32(header[8:12]),
+		types:    make(map[string]int),
+		sizes:    make(map[string]int64),
+	}
+	hashSize := int64(newHash().Size())
+	if size >= 12+hashSize {
+		sum := make([]byte, hashSize)
+		if _, err := r.ReadAt(sum, size-hashSize); err == nil {
+			st.checksum = hex.EncodeToString(sum)
+		}
+	}
+
+	cr := &countingReader{r: bufio.NewReaderSize(io.NewSectionReader(r, 12, max(size-12-hashSize, 0)), 64<<10)}
+	cr.n = 12
+	depth := make(map[int64]int)
+	var zr io.ReadCloser
+	for st.walked < int(st.declared) {
+		if st.walked == gitPackMaxObjects {
+			st.stopped = errPackLimit
+			break
+		}
+		if st.walked%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		offset := cr.n
+		typ, objSize, err := readPackObjectHeader(cr)
+		if err != nil {
+			st.stopped = fmt.Errorf("object %d at offset %d: %w", st.walked+1, offset, err)
+			break
+		}
+		typeName, known := gitObjectTypes[typ]
+		if !known {
+			st.stopped = fmt.Errorf("object %d at offset %d: unknown type %d", st.walked+1, offset, typ)
+			break
+		}
+
+		chain := 0
+		switch typ {
+		case 6:
+			rel, err := readOffsetVarint(cr)
+			if err != nil || rel <= 0 || rel > offset {
+				st.stopped = fmt.Errorf("object %d at offset %d: bad delta base offset", st.walked+1, offset)
+				break
+			}
+			chain = depth[offset-rel] + 1
+		case 7:
+			if _, err := io.CopyN(io.Discard, cr, hashSize); err != nil {
+				st.stopped = fmt.Errorf("object %d at offset %d: %w", st.walked+1, offset, err)
+				break
+			}
+			chain = 1
+		}
+		if st.stopped != nil {
+			break
+		}
+
+		if zr == nil {
+			zr, err = zlib.NewReader(cr)
+		} else {
+			err = zr.(zlib.Resetter).Reset(cr, nil)
+		}
+		if err != nil {
+			st.stopped = fmt.Errorf("object %d at offset %d: %w", st.walked+1, offset, err)
+			break
+		}
+		var h hash.Hash
+		var sink io.Writer = io.Discard
+		if withIDs && typ <= 4 {
+			h = newHash()
+			fmt.Fprintf(h, "%s %d\x00", typeName, objSize)
+			sink = h
+		}
+		if _, err := io.Copy(sink, zr); err != nil {
+			st.stopped = fmt.Errorf("object %d at offset %d: %w", st.walked+1, offset, err)
+			break
+		}
+
+		st.walked++
+		st.types[typeName]++
+		st.sizes[typeName] += objSize
+		st.objBytes += cr.n - offset
+		depth[offset] = chain
+		st.maxChain = max(st.maxChain, chain)
+		if typ <= 4 {
+			obj := gitPackObject{typ: typeName, size: objSize, offset: offset}
+			if h != nil {
+				obj.id = hex.EncodeToString(h.Sum(nil))
+			}
+			st.largest = gitKeepLargest(st.largest, obj)
+		}
+	}
+	return st, nil
+}
+
+// gitKeepLargest adds obj to the largest objects when it is among them.
+func gitKeepLargest(largest []gitPackObject, obj gitPackObject) []gitPackObject {
+	if len(largest) == gitMaxLargest && obj.size <= largest[len(largest)-1].size {
+		return largest
+	}
+	i, _ := slices.BinarySearchFunc(largest, obj, func(a, b gitPackObject) int {
+		return cmp.Or(cmp.Compare(b.size, a.size), cmp.Compare(a.offset, b.offset))
+	})
+	largest = slices.Insert(largest, i, obj)
+	return largest[:min(len(largest), gitMaxLargest)]
+}
+
+// readPackObjectHeader reads the type and uncompressed size of a pack
+// entry.
+func readPackObjectHeader(r io.ByteReader) (byte, int64, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	typ := (b >> 4) & 7
+	size := int64(b & 0x0f)
+	for shift := 4; b&0x80 != 0; shift += 7 {
+		if shift > 56 {
+			return 0, 0, errors.New("object size overflows")
+		}
+		if b, err = r.ReadByte(); err != nil {
+			return 0, 0, err
+		}
+		size |= int64(b&0x7f) << shift
+	}
+	return typ, size, nil
+}
+
+// readOffsetVarint reads the offset encoding git uses for delta base
+// offsets and v4 index name prefixes.
+func readOffsetVarint(r io.ByteReader) (int64, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	v := int64(b & 0x7f)
+	for b&0x80 != 0 {
+		if v > 1<<55 {
+			return 0, errors.New("offset overflows")
+		}
+		if b, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+		v = (v+1)<<7 | int64(b&0x7f)
+	}
+	return v, nil
+}
+
+// explorePack writes the statistics of the pack in r.
+func (e *GitObjectExplorer) explorePack(ctx context.Context, summary *strings.Builder, r *io.SectionReader, newHash func() hash.Hash) {
+	st, err := walkPack(ctx, r, newHash, e.formatterProfile == OutputProfileEnhancement)
+	if err != nil {
+		degradedExploration{
+			Failed:   err.Error(),
+			Progress: "no objects read",
+			Examined: min(r.Size(), 12),
+			Size:     r.Size(),
+			NextSteps: []string{
+				"Check the file is complete; a pack still being received is truncated",
+				"Run git verify-pack -v on the pack with its .idx for per-object details",
+			},
+		}.write(summary)
+		return
+	}
+	e.writePackStats(summary, st, r.Size())
+}
+
+func (e *GitObjectExplorer) writePackStats(summary *strings.Builder, st *gitPackStats, size int64) {
+	fmt.Fprintf(summary, "Pack version: %d\n", st.version)
+	fmt.Fprintf(summary, "Objects: %d\n", st.declared)
+	if st.walked > 0 {
+		deltas := st.types["ofs-delta"] + st.types["ref-delta"]
+		fmt.Fprintf(summary, "Deltas: %d of %d objects", deltas, st.walked)
+		if deltas > 0 {
+			fmt.Fprintf(summary, " (longest chain %d)", st.maxChain)
+		}
+		summary.WriteString("\n")
+		var uncompressed int64
+		for _, n := range st.sizes {
+			uncompressed += n
+		}
+		fmt.Fprintf(summary, "Uncompressed: %s in %s compressed (deltas at their delta size)\n",
+			formatSize(uint64(uncompressed)), formatSize(uint64(st.objBytes)))
+	}
+	// The trailer of a truncated pack is object data, not a checksum.
+	if st.checksum != "" && (st.stopped == nil || errors.Is(st.stopped, errPackLimit)) {
+		fmt.Fprintf(summary, "Checksum: %s\n", st.checksum)
+	}
+	if errors.Is(st.stopped, errPackLimit) {
+		fmt.Fprintf(summary, "Statistics cover the first %d of %d objects.\n", st.walked, st.declared)
+	}
+
+	if st.walked > 0 {
+		summary.WriteString("\nObject types:\n")
+		writeCounts(summary, st.types, "")
+	}
+	if st.stopped != nil && !errors.Is(st.stopped, errPackLimit) {
+		degradedExploration{
+			Failed:   "pack object walk: " + st.stopped.Error(),
+			Progress: fmt.Sprintf("walked %d of %d objects", st.walked, st.declared),
+			Examined: 12 + st.objBytes,
+			Size:     size,
+			NextSteps: []string{
+				"Check the file is complete; a pack still being received is truncated",
+				"Run git verify-pack -v on the pack with its .idx for per-object details",
+			},
+		}.write(summary)
+	}
+
+	// EXCEED MODE: the largest objects with their IDs, and the
+	// uncompressed size by type.
+	if e.formatterProfile == OutputProfileEnhancement && st.walked > 0 {
+		summary.WriteString("\nUncompressed size by type:\n")
+		for _, typ := range sortedKeys(st.sizes) {
+			fmt.Fprintf(summary, "  - %s: %s\n", typ, formatSize(uint64(st.sizes[typ])))
+		}
+		if len(st.largest) > 0 {
+			summary.WriteString("\nLargest objects:\n")
+			for _, obj := range st.largest {
+				id := obj.id
+				if len(id) > 12 {
+					id = id[:12]
+				}
+				fmt.Fprintf(summary, "  - %s %s: %s at offset %d\n", obj.typ, id, formatSize(uint64(obj.size)), obj.offset)
+			}
+		}
+	}
+}
+
+// --- pack indexes ---
+
+// explorePackIndex writes the object count of a version 2 pack index.
+func (e *GitObjectExplorer) explorePackIndex(summary *strings.Builder, r io.ReaderAt, size int64) {
+	const fanoutEnd = 8 + 256*4
+	header := make([]byte, fanoutEnd)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		degradedExploration{
+			Failed:    "pack index fan-out table: " + err.Error(),
+			Progress:  "header read",
+			Examined:  min(size, fanoutEnd),
+			Size:      size,
+			NextSteps: []string{"Rebuild the index with git index-pack on the .pack file"},
+		}.write(summary)
+		return
+	}
+	objects := binary.BigEndian.Uint32(header[fanoutEnd-4:])
+	fmt.Fprintf(summary, "Index version: %d\n", binary.BigEndian.Uint32(header[4:8]))
+	fmt.Fprintf(summary, "Objects: %d\n", objects)
+
+	// Names, CRCs and 4-byte offsets, the large offsets, then the pack
+	// and index checksums. The ID size follows from the file size.
+	rest := size - fanoutEnd
+	for _, hashSize := range []int64{20, 32} {
+		fixed := int64(objects) * (hashSize + 8)
+		if large := rest - fixed - 2*hashSize; large >= 0 && large%8 == 0 {
+			fmt.Fprintf(summary, "Object format: %s\n", map[int64]string{20: "sha1", 32: "sha256"}[hashSize])
+			if large > 0 {
+				fmt.Fprintf(summary, "Large offsets: %d (pack over 2 GB)\n", large/8)
+			}
+			sum := make([]byte, hashSize)
+			if _, err := r.ReadAt(sum, size-2*hashSize); err == nil {
+				fmt.Fprintf(summary, "Pack checksum: %s\n", hex.EncodeToString(sum))
+			}
+			return
+		}
+	}
+}
+
+// --- .git/index ---
+
+// gitIndexEntry is the part of an index entry the summary uses.
+type gitIndexEntry struct {
+	path  string
+	mode  uint32
+	stage int
+}
+
+// errIndexTooLarge reports an index too large to read.
+var errIndexTooLarge = errors.New("index too large to read")
+
+// parseGitIndex parses the entries and extension names of a .git/index.
+// On error, the entries read so far are returned with the offset reached.
+func parseGitIndex(data []byte, hashSize int) ([]gitIndexEntry, []string, int64, error) {
+	if len(data) < 12 {
+		return nil, nil, 0, errors.New("header truncated")
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	count := binary.BigEndian.Uint32(data[8:12])
+	entries := make([]gitIndexEntry, 0, min(count, 1<<16))
+	off := 12
+	var prev string
+	for i := uint32(0); i < count; i++ {
+		fixed := 40 + hashSize + 2
+		if off+fixed > len(data) {
+			return entries, nil, int64(off), fmt.Errorf("entry %d truncated", i+1)
+		}
+		mode := binary.BigEndian.Uint32(data[off+24:])
+		flags := binary.BigEndian.Uint16(data[off+40+hashSize:])
+		if flags&0x4000 != 0 {
+			if version < 3 {
+				return entries, nil, int64(off), fmt.Errorf("entry %d: extended flags in a version %d index", i+1, version)
+			}
+			fixed += 2
+		}
+		nameStart := off + fixed
+		var path string
+		if version == 4 {
+			br := bytes.NewReader(data[nameStart:])
+			strip, err := readOffsetVarint(br)
+			if err != nil || strip > int64(len(prev)) {
+				return entries, nil, int64(off), fmt.Errorf("entry %d: bad path prefix", i+1)
+			}
+			suffixStart := len(data) - br.Len()
+			end := bytes.IndexByte(data[suffixStart:], 0)
+			if end < 0 {
+				return entries, nil, int64(off), fmt.Errorf("entry %d: path not terminated", i+1)
+			}
+			path = prev[:len(prev)-int(strip)] + string(data[suffixStart:suffixStart+end])
+			off = suffixStart + end + 1
+		} else {
+			end := bytes.IndexByte(data[nameStart:], 0)
+			if end < 0 {
+				return entries, nil, int64(off), fmt.Errorf("entry %d: path not terminated", i+1)
+			}
+			path = string(data[nameStart : nameStart+end])
+			// Entries are padded with 1 to 8 NULs to a multiple of 8.
+			off += (fixed + end + 8) &^ 7
+		}
+		prev = path
+		entries = append(entries, gitIndexEntry{path: path, mode: mode, stage: int(flags>>12) & 3})
+	}
+
+	var extensions []string
+	for off+8 <= len(data)-hashSize {
+		sig := string(data[off : off+4])
+		n := int(binary.BigEndian.Uint32(data[off+4:]))
+		if n < 0 || off+8+n > len(data)-hashSize {
+			return entries, extensions, int64(off), fmt.Errorf("extension %q truncated", sig)
+		}
+		extensions = append(extensions, sig)
+		off += 8 + n
+	}
+	return entries, extensions, int64(off), nil
+}
+
+// exploreIndex writes the entries, conflicts and extensions of a
+// .git/index.
+func (e *GitObjectExplorer) exploreIndex(summary *strings.Builder, r io.ReaderAt, size int64) {
+	if size > MaxFullLoadSize {
+		header := make([]byte, 12)
+		if _, err := r.ReadAt(header, 0); err == nil {
+			fmt.Fprintf(summary, "Index version: %d\n", binary.BigEndian.Uint32(header[4:8]))
+			fmt.Fprintf(summary, "Entries: %d\n", binary.BigEndian.Uint32(header[8:12]))
+		}
+		fmt.Fprintf(summary, "Entries not listed: %v (over %s)\n", errIndexTooLarge, formatSize(MaxFullLoadSize))
+		return
+	}
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 0); err != nil && !errors.Is(err, io.EOF) {
+		fmt.Fprintf(summary, "Read error: %v\n", err)
+		return
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	declared := binary.BigEndian.Uint32(data[8:12])
+	fmt.Fprintf(summary, "Index version: %d\n", version)
+	fmt.Fprintf(summary, "Entries: %d\n", declared)
+
+	entries, extensions, off, err := parseGitIndex(data, sha1.Size)
+	if err != nil {
+		// A SHA-256 repository has longer IDs in every entry.
+		if entries256, ext256, off256, err256 := parseGitIndex(data, sha256.Size); err256 == nil {
+			entries, extensions, off, err = entries256, ext256, off256, nil
+			summary.WriteString("Object format: sha256\n")
+		}
+	}
+
+	modes := make(map[string]int)
+	dirs := make(map[string]int)
+	conflicts := make(map[string]bool)
+	var conflicted []string
+	for _, entry := range entries {
+		modes[gitModeName(entry.mode)]++
+		dir, _, found := strings.Cut(entry.path, "/")
+		if !found {
+			dir = "(root)"
+		}
+		dirs[dir]++
+		if entry.stage > 0 && !conflicts[entry.path] {
+			conflicts[entry.path] = true
+			conflicted = append(conflicted, entry.path)
+		}
+	}
+	fmt.Fprintf(summary, "Conflicted paths: %d\n", len(conflicted))
+	if len(modes) > 0 {
+		summary.WriteString("\nModes:\n")
+		writeCounts(summary, modes, " entries")
+	}
+	if len(dirs) > 0 {
+		summary.WriteString("\nTop-level directories:\n")
+		for i, entry := range byCount(dirs) {
+			if i == gitMaxListed {
+				fmt.Fprintf(summary, "  - ... and %d more\n", len(dirs)-gitMaxListed)
+				break
+			}
+			fmt.Fprintf(summary, "  - %s: %d entries\n", entry.Key, entry.Count)
+		}
+	}
+	if len(extensions) > 0 {
+		summary.WriteString("\nExtensions:\n")
+		for _, sig := range extensions {
+			desc := gitIndexExtensions[sig]
+			if desc == "" {
+				desc = "unknown"
+			}
+			fmt.Fprintf(summary, "  - %s: %s\n", sig, desc)
+		}
+	}
+	if err != nil {
+		degradedExploration{
+			Failed:   "index entries: " + err.Error(),
+			Progress: fmt.Sprintf("read %d of %d entries", len(entries), declared),
+			Examined: off,
+			Size:     size,
+			NextSteps: []string{
+				"Run git status to have git report the corruption",
+				"Rebuild the index with rm .git/index && git reset",
+			},
+		}.write(summary)
+	}
+
+	// EXCEED MODE: the conflicted paths and a sample of the tracked paths.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeGitList(summary, "Conflicts", conflicted)
+		paths := make([]string, 0, min(len(entries), gitMaxListed+1))
+		for _, entry := range entries {
+			if entry.stage == 0 {
+				paths = append(paths, entry.path)
+			}
+			if len(paths) > gitMaxListed {
+				break
+			}
+		}
+		writeGitList(summary, "Tracked paths (first)", paths)
+	}
+}
+
+// gitModeName names an index entry mode.
+func gitModeName(mode uint32) string {
+	switch mode >> 12 {
+	case 0o12:
+		return "symlink"
+	case 0o16:
+		return "submodule"
+	}
+	if mode&0o111 != 0 {
+		return "executable"
+	}
+	return "regular"
+}
+
+// writeGitList writes a titled list capped at gitMaxListed.
+func writeGitList(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), gitMaxListed)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+	if len(lines) > gitMaxListed {
+		summary.WriteString("  - ... and more\n")
+	}
+}
+
+// --- bundles ---
+
+// gitBundleHeader is the text header of a bundle.
+type gitBundleHeader struct {
+	version       int
+	capabilities  []string
+	prerequisites []string
+	refs          []string // "<refname> <id>"
+	packOffset    int64
+}
+
+// parseGitBundleHeader reads the header of a bundle, up to the blank line
+// the pack follows.
+func parseGitBundleHeader(r io.Reader) (*gitBundleHeader, error) {
+	br := bufio.NewReader(io.LimitReader(r, gitBundleMaxHeader))
+	h := &gitBundleHeader{}
+	var off int64
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadString('\n')
+		off += int64(len(line))
+		if err != nil {
+			return h, fmt.Errorf("line %d: header not terminated by a blank line", lineNo)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case lineNo == 1:
+			h.version, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "# v"), " git bundle"))
+		case line == "":
+			h.packOffset = off
+			return h, nil
+		case strings.HasPrefix(line, "@"):
+			h.capabilities = append(h.capabilities, line[1:])
+		case strings.HasPrefix(line, "-"):
+			h.prerequisites = append(h.prerequisites, line[1:])
+		default:
+			id, ref, ok := strings.Cut(line, " ")
+			if !ok {
+				return h, fmt.Errorf("line %d: malformed ref line", lineNo)
+			}
+			h.refs = append(h.refs, ref+" "+id)
+		}
+	}
+}
+
+// exploreBundle writes the refs and prerequisites of a bundle and the
+// statistics of its pack.
+func (e *GitObjectExplorer) exploreBundle(ctx context.Context, summary *strings.Builder, r io.ReaderAt, size int64) {
+	h, err := parseGitBundleHeader(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		degradedExploration{
+			Failed:   "bundle header " + err.Error(),
+			Progress: fmt.Sprintf("read %d refs and %d prerequisites", len(h.refs), len(h.prerequisites)),
+			Examined: min(size, gitBundleMaxHeader),
+			Size:     size,
+			NextSteps: []string{
+				"Check the file is complete",
+				"Run git bundle list-heads on the file",
+			},
+		}.write(summary)
+		return
+	}
+
+	newHash := sha1.New
+	format := "sha1"
+	for _, capability := range h.capabilities {
+		if capability == "object-format=sha256" {
+			newHash, format = sha256.New, "sha256"
+		}
+	}
+	fmt.Fprintf(summary, "Bundle version: %d\n", h.version)
+	fmt.Fprintf(summary, "Object format: %s\n", format)
+	if len(h.capabilities) > 0 {
+		fmt.Fprintf(summary, "Capabilities: %s\n", strings.Join(h.capabilities, ", "))
+	}
+	if len(h.prerequisites) == 0 {
+		summary.WriteString("Prerequisites: none (complete history)\n")
+	} else {
+		fmt.Fprintf(summary, "Prerequisites: %d (the receiving repository must have these commits)\n", len(h.prerequisites))
+	}
+	fmt.Fprintf(summary, "Refs: %d\n", len(h.refs))
+	writeGitList(summary, "Refs", h.refs)
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeGitList(summary, "Prerequisite commits", h.prerequisites)
+	}
+
+	summary.WriteString("\nPack:\n")
+	e.explorePack(ctx, summary, io.NewSectionReader(r, h.packOffset, size-h.packOffset), newHash)
+}
+
+// --- loose objects ---
+
+// gitLooseObjectID returns the object ID encoded in a loose object path.
+func gitLooseObjectID(path string) string {
+	m := gitLooseObjectPath.FindStringSubmatch(filepath.ToSlash(path))
+	if m == nil {
+		return filepath.Base(path)
+	}
+	return m[1] + m[2]
+}
+
+// gitLooseMaxRead caps the inflated bytes of a loose object read for its
+// summary.
+const gitLooseMaxRead = 64 << 10
+
+// exploreLoose writes the type and size of a loose object, and the
+// subject of commits and tags.
+func (e *GitObjectExplorer) exploreLoose(summary *strings.Builder, r io.ReaderAt, size int64) {
+	zr, err := zlib.NewReader(io.NewSectionReader(r, 0, size))
+	var data []byte
+	if err == nil {
+		data, err = io.ReadAll(io.LimitReader(zr, gitLooseMaxRead))
+	}
+	header, body, found := bytes.Cut(data, []byte{0})
+	typ, objSize, _ := strings.Cut(string(header), " ")
+	if !found || (err != nil && len(data) == 0) {
+		if err == nil {
+			err = errors.New("no object header")
+		}
+		degradedExploration{
+			Failed:    "loose object: " + err.Error(),
+			Progress:  fmt.Sprintf("inflated %d bytes", len(data)),
+			Examined:  size,
+			Size:      size,
+			NextSteps: []string{"Run git cat-file -p on the object ID"},
+		}.write(summary)
+		return
+	}
+	fmt.Fprintf(summary, "Type: %s\n", typ)
+	if n, err := strconv.ParseInt(objSize, 10, 64); err == nil {
+		fmt.Fprintf(summary, "Object size: %s\n", formatSize(uint64(n)))
+	}
+	fmt.Fprintf(summary, "Stored size: %s\n", formatSize(uint64(size)))
+
+	switch typ {
+	case "commit", "tag":
+		headers, message, _ := strings.Cut(string(body), "\n\n")
+		for line := range strings.SplitSeq(headers, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "tree", "object", "tag", "author", "tagger":
+				fmt.Fprintf(summary, "%s: %s\n", strings.ToUpper(key[:1])+key[1:], value)
+			case "parent":
+				fmt.Fprintf(summary, "Parent: %s\n", value)
+			}
+		}
+		if subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n"); subject != "" {
+			fmt.Fprintf(summary, "Subject: %s\n", subject)
+		}
+	}
+}
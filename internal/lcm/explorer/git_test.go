@@ -0,0 +1,286 @@
+package explorer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test fixtures are encoded by hand, so no git binary is needed.
+
+func gitZlib(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// gitPackEntry is an object to encode in a test pack. base is the index
+// of the delta base for ofs-delta entries.
+type gitPackEntry struct {
+	typ  byte
+	data []byte
+	base int
+}
+
+func makeGitPack(t *testing.T, entries []gitPackEntry) []byte {
+	t.Helper()
+	pack := []byte("PACK")
+	pack = binary.BigEndian.AppendUint32(pack, 2)
+	pack = binary.BigEndian.AppendUint32(pack, uint32(len(entries)))
+	offsets := make([]int, len(entries))
+	for i, entry := range entries {
+		offsets[i] = len(pack)
+		size := len(entry.data)
+		b := entry.typ<<4 | byte(size&0x0f)
+		for size >>= 4; size > 0; size >>= 7 {
+			pack = append(pack, b|0x80)
+			b = byte(size & 0x7f)
+		}
+		pack = append(pack, b)
+		if entry.typ == 6 {
+			rel := offsets[i] - offsets[entry.base]
+			enc := []byte{byte(rel & 0x7f)}
+			for rel >>= 7; rel > 0; rel >>= 7 {
+				rel--
+				enc = append([]byte{0x80 | byte(rel&0x7f)}, enc...)
+			}
+			pack = append(pack, enc...)
+		}
+		pack = append(pack, gitZlib(t, entry.data)...)
+	}
+	sum := sha1.Sum(pack)
+	return append(pack, sum[:]...)
+}
+
+func testGitPack(t *testing.T) []byte {
+	t.Helper()
+	blob := []byte("hello\n")
+	tree := append([]byte("100644 hello.txt\x00"), make([]byte, 20)...)
+	commit := []byte("tree 0000000000000000000000000000000000000000\nauthor A <a@example.com> 0 +0000\n\nInitial commit\n")
+	// A delta rebuilding "bye" from the blob: base size, result size and
+	// one insert instruction.
+	delta := []byte{6, 3, 3, 'b', 'y', 'e'}
+	return makeGitPack(t, []gitPackEntry{
+		{typ: 1, data: commit},
+		{typ: 2, data: tree},
+		{typ: 3, data: blob},
+		{typ: 6, data: delta, base: 2},
+		{typ: 6, data: delta, base: 3},
+	})
+}
+
+// gitIndexFixture is an entry of a test index.
+type gitIndexFixture struct {
+	path  string
+	mode  uint32
+	stage uint16
+}
+
+func makeGitIndex(version uint32, entries []gitIndexFixture) []byte {
+	data := []byte("DIRC")
+	data = binary.BigEndian.AppendUint32(data, version)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(entries)))
+	var prev string
+	for _, entry := range entries {
+		start := len(data)
+		data = append(data, make([]byte, 24)...)
+		data = binary.BigEndian.AppendUint32(data, entry.mode)
+		data = append(data, make([]byte, 12+20)...)
+		data = binary.BigEndian.AppendUint16(data, entry.stage<<12|uint16(len(entry.path)))
+		if version == 4 {
+			common := 0
+			for common < min(len(prev), len(entry.path)) && prev[common] == entry.path[common] {
+				common++
+			}
+			data = append(data, byte(len(prev)-common))
+			data = append(data, entry.path[common:]...)
+			data = append(data, 0)
+		} else {
+			data = append(data, entry.path...)
+			for pad := (len(data)-start+8)&^7 - (len(data) - start); pad > 0; pad-- {
+				data = append(data, 0)
+			}
+		}
+		prev = entry.path
+	}
+	data = append(data, "TREE"...)
+	data = binary.BigEndian.AppendUint32(data, 4)
+	data = append(data, "0 1\n"...)
+	sum := sha1.Sum(data)
+	return append(data, sum[:]...)
+}
+
+var testGitIndexEntries = []gitIndexFixture{
+	{path: "README.md", mode: 0o100644},
+	{path: "cmd/app/main.go", mode: 0o100644},
+	{path: "cmd/app/run.sh", mode: 0o100755},
+	{path: "docs/latest", mode: 0o120000},
+	{path: "merge.go", mode: 0o100644, stage: 1},
+	{path: "merge.go", mode: 0o100644, stage: 2},
+	{path: "merge.go", mode: 0o100644, stage: 3},
+	{path: "vendor/lib", mode: 0o160000},
+}
+
+func TestGitObjectExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &GitObjectExplorer{}
+	require.True(t, e.CanHandle("pack-1.pack", []byte("PACK\x00\x00\x00\x02\x00\x00\x00\x01")))
+	require.True(t, e.CanHandle("pack-1.idx", []byte("\xfftOc\x00\x00\x00\x02")))
+	require.True(t, e.CanHandle("index", []byte("DIRC\x00\x00\x00\x04\x00\x00\x00\x00")))
+	require.True(t, e.CanHandle("repo.bundle", []byte("# v3 git bundle\n@object-format=sha1\n")))
+	require.True(t, e.CanHandle(".git/objects/ce/013625030ba8dba906f756967f9e9ca394464a", []byte{0x78, 0x01}))
+	require.False(t, e.CanHandle("notes.txt", []byte("PACK my bags")))
+	require.False(t, e.CanHandle("data.bin", []byte{0x78, 0x9c}))
+	require.False(t, e.CanHandle("index", []byte("DIRC\x00\x00\x00\x09")))
+}
+
+func TestGitObjectExplorer_Pack(t *testing.T) {
+	t.Parallel()
+
+	pack := testGitPack(t)
+	e := &GitObjectExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "pack-1.pack", Content: pack})
+	require.NoError(t, err)
+	require.Equal(t, "git", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Git packfile: pack-1.pack\n")
+	require.Contains(t, s, "Pack version: 2\nObjects: 5\n")
+	require.Contains(t, s, "Object types:\n  - ofs-delta: 2\n  - blob: 1\n  - commit: 1\n  - tree: 1\n")
+	require.Contains(t, s, "Objects: 5\nDeltas: 2 of 5 objects (longest chain 2)\n")
+	require.Contains(t, s, "Checksum: "+hex.EncodeToString(pack[len(pack)-20:])+"\n")
+	require.NotContains(t, s, "Degraded")
+	require.NotContains(t, s, "Largest objects:")
+
+	e = &GitObjectExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err = e.Explore(context.Background(), ExploreInput{Path: "pack-1.pack", Content: pack})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Uncompressed size by type:\n")
+	// The blob is "hello\n", whose ID git computes as ce01362503...
+	require.Contains(t, result.Summary, "  - blob ce013625030b: 6 bytes at offset ")
+}
+
+func TestGitObjectExplorer_PackIndex(t *testing.T) {
+	t.Parallel()
+
+	idx := []byte("\xfftOc")
+	idx = binary.BigEndian.AppendUint32(idx, 2)
+	for i := range 256 {
+		idx = binary.BigEndian.AppendUint32(idx, uint32(min(i, 3)))
+	}
+	idx = append(idx, make([]byte, 3*(20+8))...)
+	idx = append(idx, bytes.Repeat([]byte{0xab}, 20)...)
+	idx = append(idx, make([]byte, 20)...)
+
+	result, err := (&GitObjectExplorer{}).Explore(context.Background(), ExploreInput{Path: "pack-1.idx", Content: idx})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Git pack index: pack-1.idx\n")
+	require.Contains(t, result.Summary, "Index version: 2\nObjects: 3\nObject format: sha1\n")
+	require.Contains(t, result.Summary, "Pack checksum: "+hex.EncodeToString(bytes.Repeat([]byte{0xab}, 20))+"\n")
+}
+
+func TestGitObjectExplorer_Index(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []uint32{2, 4} {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			t.Parallel()
+
+			data := makeGitIndex(version, testGitIndexEntries)
+			e := &GitObjectExplorer{formatterProfile: OutputProfileEnhancement}
+			result, err := e.Explore(context.Background(), ExploreInput{Path: ".git/index", Content: data})
+			require.NoError(t, err)
+
+			s := result.Summary
+			require.Contains(t, s, "Git index: index\n")
+			require.Contains(t, s, fmt.Sprintf("Index version: %d\nEntries: 8\n", version))
+			require.Contains(t, s, "Modes:\n  - regular: 5 entries\n  - executable: 1 entries\n  - submodule: 1 entries\n  - symlink: 1 entries\n")
+			require.Contains(t, s, "Conflicted paths: 1\n")
+			require.Contains(t, s, "Top-level directories:\n  - (root): 4 entries\n  - cmd: 2 entries\n")
+			require.Contains(t, s, "Extensions:\n  - TREE: cache tree\n")
+			require.Contains(t, s, "Conflicts:\n  - merge.go\n")
+			require.Contains(t, s, "  - cmd/app/run.sh\n")
+			require.NotContains(t, s, "Degraded")
+		})
+	}
+}
+
+func TestGitObjectExplorer_Bundle(t *testing.T) {
+	t.Parallel()
+
+	pack := testGitPack(t)
+	id := "ce013625030ba8dba906f756967f9e9ca394464a"
+	bundle := []byte("# v3 git bundle\n@object-format=sha1\n-" + id + " Earlier commit\n" +
+		id + " refs/heads/main\n" + id + " refs/tags/v1.0.0\n\n")
+	bundle = append(bundle, pack...)
+
+	result, err := (&GitObjectExplorer{}).Explore(context.Background(), ExploreInput{Path: "repo.bundle", Content: bundle})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Git bundle: repo.bundle\n")
+	require.Contains(t, s, "Bundle version: 3\nObject format: sha1\nCapabilities: object-format=sha1\n")
+	require.Contains(t, s, "Prerequisites: 1 (the receiving repository must have these commits)\n")
+	require.Contains(t, s, "Refs:\n  - refs/heads/main "+id+"\n  - refs/tags/v1.0.0 "+id+"\n")
+	require.Contains(t, s, "Pack version: 2\nObjects: 5\n")
+	require.NotContains(t, s, "Degraded")
+}
+
+func TestGitObjectExplorer_Loose(t *testing.T) {
+	t.Parallel()
+
+	body := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor A <a@example.com> 0 +0000\ncommitter A <a@example.com> 0 +0000\n\nFix the parser\n\nDetails.\n"
+	content := gitZlib(t, []byte(fmt.Sprintf("commit %d\x00%s", len(body), body)))
+	path := ".git/objects/ab/cdef0123456789abcdef0123456789abcdef01"
+
+	result, err := NewRegistry().Explore(context.Background(), ExploreInput{Path: path, Content: content})
+	require.NoError(t, err)
+	require.Equal(t, "git", result.ExplorerUsed)
+	require.Contains(t, result.Summary, "Git loose object: abcdef0123456789abcdef0123456789abcdef01")
+	require.Contains(t, result.Summary, "Type: commit")
+	require.Contains(t, result.Summary, "Subject: Fix the parser")
+}
+
+func TestGitObjectExplorer_Degraded(t *testing.T) {
+	t.Parallel()
+
+	pack := testGitPack(t)
+	truncated := pack[:len(pack)-25]
+	result, err := (&GitObjectExplorer{}).Explore(context.Background(), ExploreInput{Path: "pack-1.pack", Content: truncated})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Objects: 5\n")
+	require.Contains(t, result.Summary, "Degraded exploration:\n  Failed: pack object walk: object ")
+	require.Contains(t, result.Summary, "Progress: walked 3 of 5 objects\n")
+	require.NotContains(t, result.Summary, "Checksum:")
+
+	index := makeGitIndex(2, testGitIndexEntries)
+	result, err = (&GitObjectExplorer{}).Explore(context.Background(), ExploreInput{Path: "index", Content: index[:100]})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Failed: index entries: entry 2 truncated\n")
+	require.Contains(t, result.Summary, "Progress: read 1 of 8 entries\n")
+}
+
+func TestGitObjectExplorer_Stream(t *testing.T) {
+	t.Parallel()
+
+	content := testGitPack(t)
+	registry := NewRegistry(WithOutputProfile(OutputProfileEnhancement))
+	streamed, err := registry.ExploreStream(context.Background(), "pack-1.pack", bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	loaded, err := registry.Explore(context.Background(), ExploreInput{Path: "pack-1.pack", Content: content})
+	require.NoError(t, err)
+	require.Equal(t, "git", streamed.ExplorerUsed)
+	require.Equal(t, loaded.Summary, streamed.Summary)
+}
@@ -0,0 +1,926 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GraphQLExplorer explores GraphQL documents (.graphql, .gql): schema
+// types, root operation fields and directives, plus the operations and
+// fragments of executable documents.
+type GraphQLExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// graphqlMaxDetails caps the entries listed per section.
+const graphqlMaxDetails = 200
+
+// graphqlPunctuators are the characters that form tokens on their own.
+const graphqlPunctuators = "!$&():=@[]{}|"
+
+// graphqlBuiltinScalars are the scalars every schema has.
+var graphqlBuiltinScalars = map[string]bool{"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true}
+
+// graphqlRootKinds are the root operation types, in display order.
+var graphqlRootKinds = []struct{ operation, defaultType, title string }{
+	{"query", "Query", "Queries"},
+	{"mutation", "Mutation", "Mutations"},
+	{"subscription", "Subscription", "Subscriptions"},
+}
+
+// graphqlDefinitionKinds are the type definition keywords with their
+// plural for the declaration counts, in display order.
+var graphqlDefinitionKinds = []struct{ keyword, plural, title string }{
+	{"type", "types", "Types"},
+	{"interface", "interfaces", "Interfaces"},
+	{"union", "unions", "Unions"},
+	{"enum", "enums", "Enums"},
+	{"input", "inputs", "Inputs"},
+	{"scalar", "scalars", "Scalars"},
+}
+
+type graphqlField struct {
+	name string
+	args []string
+	// typ is empty for enum values.
+	typ        string
+	deprecated bool
+	reason     string
+}
+
+type graphqlType struct {
+	kind       string
+	name       string
+	line       int
+	extension  bool
+	implements []string
+	members    []string
+	fields     []graphqlField
+}
+
+type graphqlDirective struct {
+	name       string
+	args       []string
+	repeatable bool
+	locations  []string
+}
+
+type graphqlOperation struct {
+	kind string // query, mutation, subscription or fragment
+	name string
+	on   string
+}
+
+// graphqlDocument is the parsed content of a document.
+type graphqlDocument struct {
+	types      []*graphqlType
+	directives []graphqlDirective
+	operations []graphqlOperation
+	// roots maps operations to root type names set by a schema definition.
+	roots map[string]string
+	// directiveUses counts applied directives by name.
+	directiveUses map[string]int
+}
+
+type graphqlToken struct {
+	text   string
+	offset int
+	quoted bool
+}
+
+// graphqlSyntaxError is a tokenizer or parser error at a byte offset.
+type graphqlSyntaxError struct {
+	offset int
+	msg    string
+}
+
+func (e *graphqlSyntaxError) Error() string { return e.msg }
+
+func (e *GraphQLExplorer) CanHandle(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".graphql", ".graphqls", ".gql":
+		return true
+	}
+	return false
+}
+
+func (e *GraphQLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("GraphQL document too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "graphql", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	p := &graphqlParser{content: input.Content}
+	p.doc.roots = make(map[string]string)
+	p.doc.directiveUses = make(map[string]int)
+	toks, err := tokenizeGraphQL(input.Content)
+	if err == nil {
+		p.toks = toks
+		err = p.parse()
+	}
+	if err != nil {
+		return degradedTextResult("GraphQL document: "+name, "graphql", input.Content, graphqlDegradation(input.Content, &p.doc, err)), nil
+	}
+	doc := &p.doc
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "GraphQL document: %s\n", name)
+	counts := make(map[string]int)
+	for _, typ := range doc.types {
+		if !typ.extension {
+			counts[typ.kind]++
+		}
+	}
+	var declared []string
+	for _, kind := range graphqlDefinitionKinds {
+		if counts[kind.keyword] > 0 {
+			declared = append(declared, fmt.Sprintf("%d %s", counts[kind.keyword], kind.plural))
+		}
+	}
+	if len(doc.directives) > 0 {
+		declared = append(declared, fmt.Sprintf("%d directives", len(doc.directives)))
+	}
+	if len(declared) > 0 {
+		fmt.Fprintf(&summary, "Declarations: %s\n", strings.Join(declared, ", "))
+	}
+	if len(doc.operations) > 0 {
+		fmt.Fprintf(&summary, "Operations: %d\n", len(doc.operations))
+	}
+	var roots []string
+	for _, root := range graphqlRootKinds {
+		if typ := doc.rootType(root.operation, root.defaultType); typ != "" {
+			roots = append(roots, root.operation+" "+typ)
+		}
+	}
+	if len(roots) > 0 {
+		fmt.Fprintf(&summary, "Root types: %s\n", strings.Join(roots, ", "))
+	}
+
+	for _, kind := range graphqlDefinitionKinds {
+		var lines []string
+		for _, typ := range doc.types {
+			if typ.kind == kind.keyword && !typ.extension {
+				lines = append(lines, graphqlTypeLine(typ))
+			}
+		}
+		writeGraphQLSection(&summary, kind.title, lines)
+	}
+	for _, root := range graphqlRootKinds {
+		typ := doc.rootType(root.operation, root.defaultType)
+		var lines []string
+		for _, t := range doc.types {
+			if t.kind == "type" && t.name == typ {
+				for _, f := range t.fields {
+					lines = append(lines, graphqlFieldSignature(f))
+				}
+			}
+		}
+		writeGraphQLSection(&summary, root.title, lines)
+	}
+	if len(doc.directives) > 0 {
+		summary.WriteString("\nDirectives:\n")
+		for _, d := range doc.directives {
+			args := ""
+			if len(d.args) > 0 {
+				args = "(" + strings.Join(d.args, ", ") + ")"
+			}
+			repeatable := ""
+			if d.repeatable {
+				repeatable = " repeatable"
+			}
+			fmt.Fprintf(&summary, "  - @%s%s%s on %s\n", d.name, args, repeatable, strings.Join(d.locations, " | "))
+		}
+	}
+	var extensions []string
+	for _, typ := range doc.types {
+		if typ.extension {
+			extensions = append(extensions, fmt.Sprintf("extend %s %s (%d fields, line %d)", typ.kind, typ.name, len(typ.fields), typ.line))
+		}
+	}
+	writeGraphQLSection(&summary, "Extensions", extensions)
+	if len(doc.operations) > 0 {
+		summary.WriteString("\nOperation definitions:\n")
+		for _, op := range doc.operations {
+			line := op.kind + " " + op.name
+			if op.on != "" {
+				line += " on " + op.on
+			}
+			fmt.Fprintf(&summary, "  - %s\n", line)
+		}
+	}
+
+	// EXCEED MODE: field layouts, enum values, deprecations, directive usage
+	// and the type dependency graph.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeGraphQLFields(&summary, doc)
+		if len(doc.directiveUses) > 0 {
+			summary.WriteString("\nDirective usage:\n")
+			uses := make(map[string]int, len(doc.directiveUses))
+			for name, n := range doc.directiveUses {
+				uses["@"+name] = n
+			}
+			writeCounts(&summary, uses, "")
+		}
+		writeGraphQLDependencies(&summary, doc)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "graphql",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// rootType returns the type name of a root operation: the one set by a
+// schema definition, or the conventional name when such a type exists.
+func (doc *graphqlDocument) rootType(operation, defaultType string) string {
+	if len(doc.roots) > 0 {
+		return doc.roots[operation]
+	}
+	if slices.ContainsFunc(doc.types, func(t *graphqlType) bool { return t.kind == "type" && t.name == defaultType }) {
+		return defaultType
+	}
+	return ""
+}
+
+// graphqlDegradation describes a document that failed to tokenize or
+// parse.
+func graphqlDegradation(content []byte, doc *graphqlDocument, err error) degradedExploration {
+	size := int64(len(content))
+	offset := len(content)
+	var syntaxErr *graphqlSyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.offset
+	}
+	line, col := lineColumn(content, int64(offset))
+	return degradedExploration{
+		Failed: fmt.Sprintf("GraphQL parsing at line %d, column %d: %v", line, col, err),
+		Progress: fmt.Sprintf("parsed %d type definitions and %d operations before the error",
+			len(doc.types), len(doc.operations)),
+		Examined: int64(offset),
+		Size:     size,
+		NextSteps: []string{
+			"Validate the document with graphql-schema-linter or the GraphQL server's schema loader",
+			"Read the raw content around the error with the view tool",
+		},
+	}
+}
+
+func graphqlTypeLine(typ *graphqlType) string {
+	var line strings.Builder
+	line.WriteString(typ.name)
+	if len(typ.implements) > 0 {
+		line.WriteString(" implements " + strings.Join(typ.implements, " & "))
+	}
+	switch typ.kind {
+	case "union":
+		fmt.Fprintf(&line, " = %s (line %d)", strings.Join(typ.members, " | "), typ.line)
+	case "enum":
+		fmt.Fprintf(&line, " (%d values, line %d)", len(typ.fields), typ.line)
+	case "scalar":
+		fmt.Fprintf(&line, " (line %d)", typ.line)
+	default:
+		fmt.Fprintf(&line, " (%d fields, line %d)", len(typ.fields), typ.line)
+	}
+	return line.String()
+}
+
+// graphqlFieldSignature renders a field as "name(arg: Type): Type".
+func graphqlFieldSignature(f graphqlField) string {
+	sig := f.name
+	if len(f.args) > 0 {
+		sig += "(" + strings.Join(f.args, ", ") + ")"
+	}
+	return sig + ": " + f.typ
+}
+
+func writeGraphQLSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), graphqlMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+}
+
+func writeGraphQLFields(summary *strings.Builder, doc *graphqlDocument) {
+	var fields, values, deprecated []string
+	for _, typ := range doc.types {
+		for _, f := range typ.fields {
+			qualified := typ.name + "." + f.name
+			if f.typ == "" {
+				values = append(values, qualified)
+			} else {
+				fields = append(fields, typ.name+"."+graphqlFieldSignature(f))
+			}
+			if f.deprecated {
+				deprecated = append(deprecated, strings.TrimSuffix(qualified+": "+f.reason, ": "))
+			}
+		}
+	}
+	writeGraphQLSection(summary, "Fields", fields)
+	writeGraphQLSection(summary, "Enum values", values)
+	writeGraphQLSection(summary, "Deprecated", deprecated)
+}
+
+// writeGraphQLDependencies lists the named types each type refers to
+// through fields, arguments, interfaces and union members. Built-in
+// scalars are left out.
+func writeGraphQLDependencies(summary *strings.Builder, doc *graphqlDocument) {
+	var order []string
+	deps := make(map[string][]string)
+	for _, typ := range doc.types {
+		if _, ok := deps[typ.name]; !ok {
+			order = append(order, typ.name)
+			deps[typ.name] = nil
+		}
+		refs := append(slices.Clone(typ.implements), typ.members...)
+		for _, f := range typ.fields {
+			if f.typ != "" {
+				refs = append(refs, graphqlNamedType(f.typ))
+			}
+			for _, arg := range f.args {
+				_, argType, _ := strings.Cut(arg, ": ")
+				argType, _, _ = strings.Cut(argType, " ")
+				refs = append(refs, graphqlNamedType(argType))
+			}
+		}
+		for _, ref := range refs {
+			if !graphqlBuiltinScalars[ref] && ref != typ.name {
+				deps[typ.name] = append(deps[typ.name], ref)
+			}
+		}
+	}
+	var lines []string
+	for _, name := range order {
+		refs := deps[name]
+		slices.Sort(refs)
+		refs = slices.Compact(refs)
+		if len(refs) > 0 {
+			lines = append(lines, name+" -> "+strings.Join(refs, ", "))
+		}
+	}
+	writeGraphQLSection(summary, "Type dependencies", lines)
+}
+
+// graphqlNamedType strips list and non-null wrappers from a type reference.
+func graphqlNamedType(typ string) string {
+	return strings.Trim(typ, "[]!")
+}
+
+// tokenizeGraphQL splits a document into names, numbers, strings and
+// punctuators. Commas, whitespace and comments are insignificant.
+func tokenizeGraphQL(content []byte) ([]graphqlToken, error) {
+	var toks []graphqlToken
+	start := 0
+	if bytes.HasPrefix(content, []byte("\ufeff")) {
+		start = 3
+	}
+	for i := start; i < len(content); {
+		c := content[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case bytes.HasPrefix(content[i:], []byte(`"""`)):
+			start := i
+			i += 3
+			for i < len(content) && !bytes.HasPrefix(content[i:], []byte(`"""`)) {
+				if bytes.HasPrefix(content[i:], []byte(`\"""`)) {
+					i += 3
+				}
+				i++
+			}
+			if i >= len(content) {
+				return toks, &graphqlSyntaxError{offset: start, msg: "unterminated block string"}
+			}
+			i += 3
+			toks = append(toks, graphqlToken{text: string(content[start+3 : i-3]), offset: start, quoted: true})
+		case c == '"':
+			start := i
+			var text strings.Builder
+			for i++; i < len(content) && content[i] != '"' && content[i] != '\n'; i++ {
+				if content[i] == '\\' && i+1 < len(content) {
+					i++
+				}
+				text.WriteByte(content[i])
+			}
+			if i >= len(content) || content[i] != '"' {
+				return toks, &graphqlSyntaxError{offset: start, msg: "unterminated string"}
+			}
+			i++
+			toks = append(toks, graphqlToken{text: text.String(), offset: start, quoted: true})
+		case bytes.HasPrefix(content[i:], []byte("...")):
+			toks = append(toks, graphqlToken{text: "...", offset: i})
+			i += 3
+		case strings.IndexByte(graphqlPunctuators, c) >= 0:
+			toks = append(toks, graphqlToken{text: string(c), offset: i})
+			i++
+		case c == '_' || c == '-' || c == '.' || c == '+' || isGraphQLNameByte(c):
+			start := i
+			for i < len(content) && (isGraphQLNameByte(content[i]) || strings.IndexByte("_-.+", content[i]) >= 0) {
+				i++
+			}
+			toks = append(toks, graphqlToken{text: string(content[start:i]), offset: start})
+		default:
+			return toks, &graphqlSyntaxError{offset: i, msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return toks, nil
+}
+
+func isGraphQLNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// graphqlParser is a recursive-descent parser for type system and
+// executable definitions. Selection sets and argument values are skipped
+// or rendered rather than validated.
+type graphqlParser struct {
+	content []byte
+	toks    []graphqlToken
+	pos     int
+	doc     graphqlDocument
+}
+
+func (p *graphqlParser) parse() error {
+	for p.pos < len(p.toks) {
+		p.description()
+		if p.pos >= len(p.toks) {
+			return p.errorf("description without a definition")
+		}
+		tok := p.toks[p.pos]
+		var err error
+		switch {
+		case p.is("{"):
+			p.doc.operations = append(p.doc.operations, graphqlOperation{kind: "query", name: "(anonymous)"})
+			err = p.skipGroup("{", "}")
+		case p.is("query"), p.is("mutation"), p.is("subscription"):
+			p.pos++
+			err = p.parseOperation(tok.text)
+		case p.is("fragment"):
+			p.pos++
+			err = p.parseFragment()
+		case p.is("extend"):
+			p.pos++
+			err = p.parseDefinition(true)
+		default:
+			err = p.parseDefinition(false)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *graphqlParser) parseDefinition(extension bool) error {
+	if p.pos >= len(p.toks) || p.toks[p.pos].quoted {
+		return p.unexpected("expected a definition")
+	}
+	keyword := p.toks[p.pos]
+	p.pos++
+	if keyword.text == "schema" {
+		return p.parseSchema()
+	}
+	if keyword.text == "directive" && !extension {
+		return p.parseDirectiveDefinition()
+	}
+	known := false
+	for _, kind := range graphqlDefinitionKinds {
+		known = known || kind.keyword == keyword.text
+	}
+	if !known {
+		return p.errorAt(keyword, "unexpected %q at top level", keyword.text)
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	typ := &graphqlType{kind: keyword.text, name: name, line: p.line(keyword), extension: extension}
+	p.doc.types = append(p.doc.types, typ)
+	if p.is("implements") {
+		p.pos++
+		p.accept("&")
+		for {
+			iface, err := p.name()
+			if err != nil {
+				return err
+			}
+			typ.implements = append(typ.implements, iface)
+			if !p.accept("&") {
+				break
+			}
+		}
+	}
+	if _, _, err := p.directives(); err != nil {
+		return err
+	}
+	switch typ.kind {
+	case "union":
+		if p.accept("=") {
+			p.accept("|")
+			for {
+				member, err := p.name()
+				if err != nil {
+					return err
+				}
+				typ.members = append(typ.members, member)
+				if !p.accept("|") {
+					break
+				}
+			}
+		}
+	case "enum":
+		if p.accept("{") {
+			return p.parseEnumValues(typ)
+		}
+	case "type", "interface", "input":
+		if p.accept("{") {
+			return p.parseFields(typ)
+		}
+	}
+	return nil
+}
+
+func (p *graphqlParser) parseSchema() error {
+	if _, _, err := p.directives(); err != nil {
+		return err
+	}
+	if !p.accept("{") {
+		return nil
+	}
+	for !p.accept("}") {
+		operation, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		typ, err := p.name()
+		if err != nil {
+			return err
+		}
+		p.doc.roots[operation] = typ
+	}
+	return nil
+}
+
+func (p *graphqlParser) parseDirectiveDefinition() error {
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	d := graphqlDirective{name: name}
+	if p.accept("(") {
+		if d.args, err = p.argumentDefinitions(); err != nil {
+			return err
+		}
+	}
+	d.repeatable = p.accept("repeatable")
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+	p.accept("|")
+	for {
+		location, err := p.name()
+		if err != nil {
+			return err
+		}
+		d.locations = append(d.locations, location)
+		if !p.accept("|") {
+			break
+		}
+	}
+	p.doc.directives = append(p.doc.directives, d)
+	return nil
+}
+
+// parseFields parses field definitions up to and including the closing
+// brace.
+func (p *graphqlParser) parseFields(typ *graphqlType) error {
+	for !p.accept("}") {
+		p.description()
+		var f graphqlField
+		var err error
+		if f.name, err = p.name(); err != nil {
+			return err
+		}
+		if p.accept("(") {
+			if f.args, err = p.argumentDefinitions(); err != nil {
+				return err
+			}
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if f.typ, err = p.typeRef(); err != nil {
+			return err
+		}
+		if p.accept("=") {
+			if _, err := p.value(); err != nil {
+				return err
+			}
+		}
+		if f.deprecated, f.reason, err = p.directives(); err != nil {
+			return err
+		}
+		typ.fields = append(typ.fields, f)
+	}
+	return nil
+}
+
+func (p *graphqlParser) parseEnumValues(typ *graphqlType) error {
+	for !p.accept("}") {
+		p.description()
+		var f graphqlField
+		var err error
+		if f.name, err = p.name(); err != nil {
+			return err
+		}
+		if f.deprecated, f.reason, err = p.directives(); err != nil {
+			return err
+		}
+		typ.fields = append(typ.fields, f)
+	}
+	return nil
+}
+
+// argumentDefinitions parses the arguments after an opening parenthesis,
+// rendering each as "name: Type" with its default value.
+func (p *graphqlParser) argumentDefinitions() ([]string, error) {
+	var args []string
+	for !p.accept(")") {
+		p.description()
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		arg := name + ": " + typ
+		if p.accept("=") {
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			arg += " = " + value
+		}
+		if _, _, err := p.directives(); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func (p *graphqlParser) parseOperation(kind string) error {
+	op := graphqlOperation{kind: kind, name: "(anonymous)"}
+	if p.pos < len(p.toks) && !p.toks[p.pos].quoted && isGraphQLNameByte(p.toks[p.pos].text[0]) {
+		op.name = p.toks[p.pos].text
+		p.pos++
+	}
+	p.doc.operations = append(p.doc.operations, op)
+	if p.is("(") {
+		if err := p.skipGroup("(", ")"); err != nil {
+			return err
+		}
+	}
+	if _, _, err := p.directives(); err != nil {
+		return err
+	}
+	if !p.is("{") {
+		return p.unexpected("expected a selection set")
+	}
+	return p.skipGroup("{", "}")
+}
+
+func (p *graphqlParser) parseFragment() error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+	on, err := p.name()
+	if err != nil {
+		return err
+	}
+	p.doc.operations = append(p.doc.operations, graphqlOperation{kind: "fragment", name: name, on: on})
+	if _, _, err := p.directives(); err != nil {
+		return err
+	}
+	if !p.is("{") {
+		return p.unexpected("expected a selection set")
+	}
+	return p.skipGroup("{", "}")
+}
+
+// directives parses applied directives, counting their uses, and reports
+// a @deprecated among them with its reason.
+func (p *graphqlParser) directives() (bool, string, error) {
+	deprecated, reason := false, ""
+	for p.accept("@") {
+		name, err := p.name()
+		if err != nil {
+			return false, "", err
+		}
+		p.doc.directiveUses[name]++
+		if name == "deprecated" {
+			deprecated = true
+		}
+		if !p.is("(") {
+			continue
+		}
+		start := p.pos
+		if err := p.skipGroup("(", ")"); err != nil {
+			return false, "", err
+		}
+		if name != "deprecated" {
+			continue
+		}
+		for i := start; i+2 < p.pos; i++ {
+			if p.toks[i].text == "reason" && !p.toks[i].quoted && p.toks[i+1].text == ":" && p.toks[i+2].quoted {
+				reason = p.toks[i+2].text
+			}
+		}
+	}
+	return deprecated, reason, nil
+}
+
+// typeRef parses a type reference such as [User!]!.
+func (p *graphqlParser) typeRef() (string, error) {
+	var typ string
+	if p.accept("[") {
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		typ = name
+	}
+	if p.accept("!") {
+		typ += "!"
+	}
+	return typ, nil
+}
+
+// value parses and renders a constant value.
+func (p *graphqlParser) value() (string, error) {
+	if p.pos >= len(p.toks) {
+		return "", p.errorf("unexpected end of input")
+	}
+	tok := p.toks[p.pos]
+	switch {
+	case tok.quoted:
+		p.pos++
+		return strconv.Quote(tok.text), nil
+	case p.accept("["):
+		var items []string
+		for !p.accept("]") {
+			item, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case p.accept("{"):
+		var items []string
+		for !p.accept("}") {
+			name, err := p.name()
+			if err != nil {
+				return "", err
+			}
+			if err := p.expect(":"); err != nil {
+				return "", err
+			}
+			item, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, name+": "+item)
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	case p.accept("$"):
+		name, err := p.name()
+		return "$" + name, err
+	}
+	return p.name()
+}
+
+// description skips a description string.
+func (p *graphqlParser) description() {
+	if p.pos < len(p.toks) && p.toks[p.pos].quoted {
+		p.pos++
+	}
+}
+
+// skipGroup consumes a bracketed group starting at the current token.
+func (p *graphqlParser) skipGroup(open, close string) error {
+	start := p.toks[p.pos]
+	depth := 0
+	for ; p.pos < len(p.toks); p.pos++ {
+		switch {
+		case p.is(open):
+			depth++
+		case p.is(close):
+			depth--
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+		}
+	}
+	return p.errorAt(start, "unterminated %s", open)
+}
+
+// is reports whether the current token is the unquoted text.
+func (p *graphqlParser) is(text string) bool {
+	return p.pos < len(p.toks) && !p.toks[p.pos].quoted && p.toks[p.pos].text == text
+}
+
+// accept consumes the current token if it is the unquoted text.
+func (p *graphqlParser) accept(text string) bool {
+	if p.is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *graphqlParser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected("expected %q", text)
+	}
+	return nil
+}
+
+// name consumes a name or other bare word.
+func (p *graphqlParser) name() (string, error) {
+	if p.pos >= len(p.toks) {
+		return "", p.errorf("unexpected end of input")
+	}
+	tok := p.toks[p.pos]
+	if tok.quoted || tok.text == "..." || (len(tok.text) == 1 && strings.Contains(graphqlPunctuators, tok.text)) {
+		return "", p.unexpected("expected a name")
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+func (p *graphqlParser) unexpected(format string, args ...any) error {
+	if p.pos >= len(p.toks) {
+		return p.errorf(format+", got end of input", args...)
+	}
+	tok := p.toks[p.pos]
+	return p.errorAt(tok, format+", got %q", append(args, tok.text)...)
+}
+
+// errorf reports an error at the current token, or at the end of input.
+func (p *graphqlParser) errorf(format string, args ...any) error {
+	if p.pos < len(p.toks) {
+		return p.errorAt(p.toks[p.pos], format, args...)
+	}
+	return &graphqlSyntaxError{offset: len(p.content), msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *graphqlParser) errorAt(tok graphqlToken, format string, args ...any) error {
+	return &graphqlSyntaxError{offset: tok.offset, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *graphqlParser) line(tok graphqlToken) int {
+	line, _ := lineColumn(p.content, int64(tok.offset))
+	return line
+}
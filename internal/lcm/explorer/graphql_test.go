@@ -0,0 +1,197 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGraphQLSchema = `# Blog API
+schema {
+  query: Query
+  mutation: Mutation
+}
+
+"""
+Directives are checked by the gateway.
+"""
+directive @auth(requires: Role = ADMIN) repeatable on OBJECT | FIELD_DEFINITION
+
+scalar DateTime
+
+interface Node {
+  id: ID!
+}
+
+"A registered user."
+type User implements Node & Timestamped @auth {
+  id: ID!
+  name: String! @deprecated(reason: "Use fullName")
+  fullName: String!
+  posts(first: Int = 10, after: String): [Post!]!
+  role: Role
+}
+
+type Post implements Node {
+  id: ID!
+  title: String!
+  author: User!
+  publishedAt: DateTime
+}
+
+union SearchResult = | User | Post
+
+enum Role {
+  ADMIN
+  EDITOR
+  GUEST @deprecated
+}
+
+input CreatePostInput {
+  title: String!
+  tags: [String!] = []
+}
+
+type Query {
+  node(id: ID!): Node
+  search(term: String!, filter: SearchFilter = {kinds: [USER], limit: 5}): [SearchResult!]!
+}
+
+type Mutation {
+  createPost(input: CreatePostInput!): Post! @auth(requires: EDITOR)
+}
+
+extend type Query {
+  me: User
+}
+`
+
+func TestGraphQLExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &GraphQLExplorer{}
+	require.True(t, e.CanHandle("schema.graphql", nil))
+	require.True(t, e.CanHandle("schema.graphqls", nil))
+	require.True(t, e.CanHandle("queries.GQL", nil))
+	require.False(t, e.CanHandle("schema.json", nil))
+}
+
+func TestGraphQLExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &GraphQLExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "schema.graphql", Content: []byte(testGraphQLSchema)})
+	require.NoError(t, err)
+	require.Equal(t, "graphql", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "GraphQL document: schema.graphql\n")
+	require.Contains(t, s, "Declarations: 4 types, 1 interfaces, 1 unions, 1 enums, 1 inputs, 1 scalars, 1 directives\n")
+	require.Contains(t, s, "Root types: query Query, mutation Mutation\n")
+	require.Contains(t, s, "Types:\n"+
+		"  - User implements Node & Timestamped (5 fields, line 19)\n"+
+		"  - Post implements Node (4 fields, line 27)\n")
+	require.Contains(t, s, "Interfaces:\n  - Node (1 fields, line 14)\n")
+	require.Contains(t, s, "Unions:\n  - SearchResult = User | Post (line 34)\n")
+	require.Contains(t, s, "Enums:\n  - Role (3 values, line 36)\n")
+	require.Contains(t, s, "Inputs:\n  - CreatePostInput (2 fields, line 42)\n")
+	require.Contains(t, s, "Scalars:\n  - DateTime (line 12)\n")
+	require.Contains(t, s, "Queries:\n"+
+		"  - node(id: ID!): Node\n"+
+		"  - search(term: String!, filter: SearchFilter = {kinds: [USER], limit: 5}): [SearchResult!]!\n"+
+		"  - me: User\n")
+	require.Contains(t, s, "Mutations:\n  - createPost(input: CreatePostInput!): Post!\n")
+	require.NotContains(t, s, "Subscriptions:")
+	require.Contains(t, s, "Directives:\n  - @auth(requires: Role = ADMIN) repeatable on OBJECT | FIELD_DEFINITION\n")
+	require.Contains(t, s, "Extensions:\n  - extend type Query (1 fields, line 56)\n")
+
+	// Only root operation fields are spelled out; deprecations stay hidden.
+	require.NotContains(t, s, "posts(first: Int = 10")
+	require.NotContains(t, s, "Use fullName")
+}
+
+func TestGraphQLExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &GraphQLExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "schema.graphql", Content: []byte(testGraphQLSchema)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "  - User.posts(first: Int = 10, after: String): [Post!]!\n")
+	require.Contains(t, s, "  - CreatePostInput.tags: [String!]\n")
+	// Fields added by extend type follow those of the root types.
+	require.Contains(t, s, "  - Mutation.createPost(input: CreatePostInput!): Post!\n  - Query.me: User\n")
+	require.Contains(t, s, "Enum values:\n  - Role.ADMIN\n  - Role.EDITOR\n  - Role.GUEST\n")
+	require.Contains(t, s, "Deprecated:\n  - User.name: Use fullName\n  - Role.GUEST\n")
+	require.Contains(t, s, "Directive usage:\n  - @auth: 2\n  - @deprecated: 2\n")
+	require.Contains(t, s, "Type dependencies:\n"+
+		"  - User -> Node, Post, Role, Timestamped\n"+
+		"  - Post -> DateTime, Node, User\n"+
+		"  - SearchResult -> Post, User\n"+
+		"  - Query -> Node, SearchFilter, SearchResult, User\n"+
+		"  - Mutation -> CreatePostInput, Post\n")
+}
+
+func TestGraphQLExplorer_Explore_Operations(t *testing.T) {
+	t.Parallel()
+
+	content := `query GetUser($id: ID!) @cached {
+  node(id: $id) { ...UserFields }
+}
+
+fragment UserFields on User {
+  name
+  posts(first: 3) { title }
+}
+
+subscription { postAdded { title } }
+{ me { name } }
+`
+	result, err := (&GraphQLExplorer{}).Explore(context.Background(), ExploreInput{Path: "queries.graphql", Content: []byte(content)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Operations: 4\n")
+	require.NotContains(t, s, "Declarations:")
+	require.NotContains(t, s, "Root types:")
+	require.Contains(t, s, "Operation definitions:\n"+
+		"  - query GetUser\n"+
+		"  - fragment UserFields on User\n"+
+		"  - subscription (anonymous)\n"+
+		"  - query (anonymous)\n")
+}
+
+func TestGraphQLExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		content string
+		failed  string
+	}{
+		{content: "type Query {\n  user(id: ID!): User\n", failed: "line 3, column 1: unexpected end of input"},
+		{content: "type Query {\n  user(id ID!): User\n}\n", failed: "line 2, column 11: expected \":\", got \"ID\""},
+		{content: "type Query {\n  \"unterminated\n}\n", failed: "line 2, column 3: unterminated string"},
+		{content: "input Filter { q: String }\nresolver Foo\n", failed: "line 2, column 1: unexpected \"resolver\" at top level"},
+	} {
+		result, err := (&GraphQLExplorer{}).Explore(context.Background(), ExploreInput{Path: "schema.graphql", Content: []byte(tt.content)})
+		require.NoError(t, err)
+		require.Regexp(t, degradedBlockPattern, result.Summary)
+		require.Contains(t, result.Summary, "Failed: GraphQL parsing at "+tt.failed+"\n")
+	}
+}
+
+func TestGraphQLExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "schema.graphql", Content: []byte(testGraphQLSchema)})
+		require.NoError(t, err)
+		require.Equal(t, "graphql", result.ExplorerUsed)
+		require.Contains(t, result.Summary, "### Mutations\n- createPost(input: CreatePostInput!): Post!\n")
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Type dependencies"))
+	}
+}
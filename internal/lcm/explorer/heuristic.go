@@ -19,6 +19,10 @@ type EnrichedSymbol struct {
 	Kind       string
 	Line       int
 	Visibility string
+	// Signature is the parameter list and return type, when captured.
+	Signature string
+	// Qualifiers lists the export form, async flag and decorators.
+	Qualifiers []string
 }
 
 // EnrichedAnalysis augments tree-sitter analysis with heuristic signals.
@@ -435,6 +439,8 @@ func inferSymbolVisibility(lang string, symbols []treesitter.SymbolInfo) []Enric
 			Kind:       s.Kind,
 			Line:       s.Line,
 			Visibility: inferVisibility(lang, s),
+			Signature:  symbolSignature(lang, s),
+			Qualifiers: symbolQualifiers(lang, s),
 		})
 	}
 	return out
@@ -447,12 +453,7 @@ func inferVisibility(lang string, symbol treesitter.SymbolInfo) string {
 	}
 
 	hasModifier := func(want string) bool {
-		for _, m := range symbol.Modifiers {
-			if strings.EqualFold(strings.TrimSpace(m), want) {
-				return true
-			}
-		}
-		return false
+		return symbolHasModifier(symbol, want)
 	}
 
 	switch lang {
@@ -470,7 +471,9 @@ func inferVisibility(lang string, symbol treesitter.SymbolInfo) string {
 			return "private"
 		}
 		return "public"
-	case "java", "kotlin", "scala", "csharp":
+	case "kotlin", "swift":
+		return declVisibility(lang, symbol.Modifiers)
+	case "java", "scala", "csharp":
 		if hasModifier("public") {
 			return "public"
 		}
@@ -487,6 +490,12 @@ func inferVisibility(lang string, symbol treesitter.SymbolInfo) string {
 		}
 		return "private"
 	case "javascript", "typescript", "tsx", "jsx":
+		if hasModifier("private") || strings.HasPrefix(name, "#") {
+			return "private"
+		}
+		if hasModifier("protected") {
+			return "protected"
+		}
 		if hasModifier("export") || hasModifier("public") {
 			return "public"
 		}
@@ -501,3 +510,70 @@ func inferVisibility(lang string, symbol treesitter.SymbolInfo) string {
 		return "public"
 	}
 }
+
+func symbolHasModifier(symbol treesitter.SymbolInfo, want string) bool {
+	for _, m := range symbol.Modifiers {
+		if strings.EqualFold(strings.TrimSpace(m), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// symbolSignatureMaxRunes caps a rendered parameter list and return type.
+const symbolSignatureMaxRunes = 120
+
+// signatureLanguage reports whether signatures and qualifiers of lang are
+// rendered next to its symbols.
+func signatureLanguage(lang string) bool {
+	switch lang {
+	case "python", "javascript", "typescript", "tsx", "jsx":
+		return true
+	}
+	return false
+}
+
+// symbolSignature renders the captured parameter list and return type with
+// whitespace collapsed, using the return type syntax of lang.
+func symbolSignature(lang string, symbol treesitter.SymbolInfo) string {
+	if !signatureLanguage(lang) || symbol.Params == "" {
+		return ""
+	}
+	sig := strings.Join(strings.Fields(symbol.Params), " ")
+	if ret := strings.Join(strings.Fields(symbol.ReturnType), " "); ret != "" {
+		if lang == "python" {
+			sig += " -> " + ret
+		} else {
+			sig += ": " + ret
+		}
+	}
+	if runes := []rune(sig); len(runes) > symbolSignatureMaxRunes {
+		sig = string(runes[:symbolSignatureMaxRunes-3]) + "..."
+	}
+	return sig
+}
+
+// symbolQualifiers lists how a symbol is exported, whether it is async and
+// the names of its decorators, without decorator arguments.
+func symbolQualifiers(lang string, symbol treesitter.SymbolInfo) []string {
+	if !signatureLanguage(lang) {
+		return nil
+	}
+	var out []string
+	switch {
+	case symbolHasModifier(symbol, "default"):
+		out = append(out, "export default")
+	case symbolHasModifier(symbol, "export"):
+		out = append(out, "export")
+	}
+	if symbolHasModifier(symbol, "async") {
+		out = append(out, "async")
+	}
+	for _, d := range symbol.Decorators {
+		name, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(d), "@"), "(")
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, "@"+name)
+		}
+	}
+	return out
+}
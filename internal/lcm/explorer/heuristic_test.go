@@ -124,6 +124,74 @@ func main() {}
 	require.Equal(t, "private", enriched.Symbols[1].Visibility)
 }
 
+func TestEnrichAnalysis_SignaturesAndQualifiers(t *testing.T) {
+	t.Parallel()
+
+	analysis := &treesitter.FileAnalysis{
+		Language: "typescript",
+		Symbols: []treesitter.SymbolInfo{
+			{Name: "App", Kind: "class", Line: 1, Modifiers: []string{"export", "default"}},
+			{Name: "load", Kind: "function", Line: 3, Params: "(id: string,\n    opts?: Options)", ReturnType: "Promise<void>", Modifiers: []string{"export", "async"}},
+			{Name: "reset", Kind: "method", Line: 7, Params: "()", Modifiers: []string{"private"}},
+			{Name: "#cache", Kind: "method", Line: 9, Params: "()"},
+			{Name: "guard", Kind: "method", Line: 11, Params: "()", Modifiers: []string{"protected"}},
+		},
+	}
+
+	enriched := EnrichAnalysis(analysis, nil)
+	require.Len(t, enriched.Symbols, 5)
+	require.Equal(t, "public", enriched.Symbols[0].Visibility)
+	require.Equal(t, []string{"export default"}, enriched.Symbols[0].Qualifiers)
+	require.Empty(t, enriched.Symbols[0].Signature)
+	require.Equal(t, []string{"export", "async"}, enriched.Symbols[1].Qualifiers)
+	require.Equal(t, "(id: string, opts?: Options): Promise<void>", enriched.Symbols[1].Signature)
+	require.Equal(t, "private", enriched.Symbols[2].Visibility)
+	require.Equal(t, "private", enriched.Symbols[3].Visibility)
+	require.Equal(t, "protected", enriched.Symbols[4].Visibility)
+
+	py := EnrichAnalysis(&treesitter.FileAnalysis{
+		Language: "python",
+		Symbols: []treesitter.SymbolInfo{
+			{Name: "fetch", Kind: "function", Line: 4, Params: "(url: str)", ReturnType: "bytes", Modifiers: []string{"async"}, Decorators: []string{"retry(times=3)", "app.get(\"/x\")"}},
+		},
+	}, nil)
+	require.Equal(t, "(url: str) -> bytes", py.Symbols[0].Signature)
+	require.Equal(t, []string{"async", "@retry", "@app.get"}, py.Symbols[0].Qualifiers)
+
+	goSyms := EnrichAnalysis(&treesitter.FileAnalysis{
+		Language: "go",
+		Symbols:  []treesitter.SymbolInfo{{Name: "Run", Kind: "function", Params: "(ctx context.Context)", ReturnType: "error"}},
+	}, nil)
+	require.Empty(t, goSyms.Symbols[0].Signature)
+	require.Empty(t, goSyms.Symbols[0].Qualifiers)
+}
+
+func TestEnrichAnalysis_SwiftKotlinVisibility(t *testing.T) {
+	t.Parallel()
+
+	kt := EnrichAnalysis(&treesitter.FileAnalysis{
+		Language: "kotlin",
+		Symbols: []treesitter.SymbolInfo{
+			{Name: "Repo", Kind: "class"},
+			{Name: "clear", Kind: "function", Modifiers: []string{"internal"}},
+		},
+	}, nil)
+	require.Equal(t, "public", kt.Symbols[0].Visibility)
+	require.Equal(t, "internal", kt.Symbols[1].Visibility)
+
+	swift := EnrichAnalysis(&treesitter.FileAnalysis{
+		Language: "swift",
+		Symbols: []treesitter.SymbolInfo{
+			{Name: "Store", Kind: "class"},
+			{Name: "count", Kind: "property", Modifiers: []string{"public", "private(set)"}},
+			{Name: "reset", Kind: "function", Modifiers: []string{"fileprivate"}},
+		},
+	}, nil)
+	require.Equal(t, "internal", swift.Symbols[0].Visibility)
+	require.Equal(t, "public", swift.Symbols[1].Visibility)
+	require.Equal(t, "private", swift.Symbols[2].Visibility)
+}
+
 func TestClassifyImportCategoriesFocused(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,487 @@
+package explorer
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// HTMLExplorer explores HTML files: title, meta tags, heading hierarchy,
+// script/style/link references, forms and embedded JSON-LD.
+type HTMLExplorer struct {
+	formatterProfile OutputProfile
+}
+
+const (
+	// htmlMaxDetails caps the lines listed per section.
+	htmlMaxDetails = 200
+	// htmlTextMaxRunes caps titles, headings and meta values.
+	htmlTextMaxRunes = 100
+)
+
+// htmlCountedElements are the elements listed under "Element counts".
+var htmlCountedElements = []string{"div", "span", "p", "a", "img", "script", "link", "style", "form", "input", "button"}
+
+// htmlDocument is what one pass of the tokenizer collects.
+type htmlDocument struct {
+	title    string
+	lang     string
+	doctype  string
+	meta     []string
+	headings []htmlHeading
+	scripts  []string
+	styles   []string
+	links    []string
+	forms    []*htmlForm
+	jsonLD   []string
+	counts   map[string]int
+
+	hasRoot       bool
+	inlineScripts int
+	inlineStyles  int
+	anchors       []string
+	imagesNoAlt   []int
+	labelled      map[string]bool
+	fields        []htmlField
+}
+
+type htmlHeading struct {
+	level int
+	text  string
+	line  int
+}
+
+type htmlForm struct {
+	method string
+	action string
+	id     string
+	line   int
+	fields []string
+}
+
+// htmlField is a form control, kept to check it has a label.
+type htmlField struct {
+	name     string
+	id       string
+	line     int
+	labelled bool
+}
+
+func (e *HTMLExplorer) CanHandle(path string, content []byte) bool {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if ext == "html" || ext == "htm" || ext == "xhtml" {
+		return true
+	}
+	if ext == "php" || ext == "phtml" {
+		// PHP templates embed HTML but go to PHPExplorer.
+		return false
+	}
+	// Check if content looks like HTML
+	contentLower := strings.ToLower(string(content))
+	return strings.Contains(contentLower, "<!doctype html") ||
+		strings.Contains(contentLower, "<html")
+}
+
+func (e *HTMLExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("HTML file too large: %s (%d bytes)", filepath.Base(input.Path), len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "html", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	doc := parseHTMLDocument(input.Content)
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "HTML file: %s\n", filepath.Base(input.Path))
+	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
+	if doc.title != "" {
+		fmt.Fprintf(&summary, "Title: %s\n", doc.title)
+	}
+	if doc.lang != "" {
+		fmt.Fprintf(&summary, "Language: %s\n", doc.lang)
+	}
+	if doc.doctype != "" {
+		fmt.Fprintf(&summary, "Doctype: %s\n", doc.doctype)
+	}
+
+	writeHTMLSection(&summary, "Meta tags", doc.meta)
+
+	// Heading hierarchy, in the same shape as MarkdownExplorer's.
+	var hCounts [6]int
+	headings := make([]string, 0, len(doc.headings))
+	for _, h := range doc.headings {
+		hCounts[h.level-1]++
+		headings = append(headings, fmt.Sprintf("H%d %s (line %d)", h.level, h.text, h.line))
+	}
+	summary.WriteString("\nHeading hierarchy:\n")
+	for i, n := range hCounts {
+		fmt.Fprintf(&summary, "  H%d: %d\n", i+1, n)
+	}
+	fmt.Fprintf(&summary, "  Total: %d\n", len(doc.headings))
+	writeHTMLSection(&summary, "Headings", headings)
+
+	scripts := doc.scripts
+	if doc.inlineScripts > 0 {
+		scripts = append(slices.Clip(scripts), fmt.Sprintf("inline scripts: %d", doc.inlineScripts))
+	}
+	writeHTMLSection(&summary, "Scripts", scripts)
+	styles := doc.styles
+	if doc.inlineStyles > 0 {
+		styles = append(slices.Clip(styles), fmt.Sprintf("inline style blocks: %d", doc.inlineStyles))
+	}
+	writeHTMLSection(&summary, "Stylesheets", styles)
+	writeHTMLSection(&summary, "Link references", doc.links)
+
+	forms := make([]string, 0, len(doc.forms))
+	for _, f := range doc.forms {
+		line := fmt.Sprintf("%s %s", f.method, cmp.Or(f.action, "(same page)"))
+		details := []string{fmt.Sprintf("line %d", f.line)}
+		if f.id != "" {
+			details = append([]string{"id " + f.id}, details...)
+		}
+		line += " (" + strings.Join(details, ", ") + ")"
+		if len(f.fields) > 0 {
+			line += ": " + strings.Join(f.fields, ", ")
+		}
+		forms = append(forms, line)
+	}
+	writeHTMLSection(&summary, "Forms", forms)
+	writeHTMLSection(&summary, "JSON-LD", doc.jsonLD)
+
+	if len(doc.counts) > 0 {
+		summary.WriteString("\nElement counts:\n")
+		for _, e := range byCount(doc.counts) {
+			fmt.Fprintf(&summary, "  - <%s>: %d\n", e.Key, e.Count)
+		}
+	}
+
+	// EXCEED MODE: link targets and accessibility checks.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeHTMLSection(&summary, "Anchor targets", htmlAnchorTargets(doc.anchors))
+		writeHTMLSection(&summary, "Accessibility", htmlAccessibility(doc))
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "html",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+func writeHTMLSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), htmlMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+}
+
+// parseHTMLDocument walks the token stream once, tracking line numbers
+// from the raw bytes consumed.
+func parseHTMLDocument(content []byte) *htmlDocument {
+	doc := &htmlDocument{counts: make(map[string]int), labelled: make(map[string]bool)}
+	z := html.NewTokenizer(bytes.NewReader(content))
+	line := 1
+	var form *htmlForm
+	var heading *htmlHeading
+	var text strings.Builder
+	inTitle, inJSONLD, labelDepth := false, false, 0
+	titleSeen := false
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or a read error on the in-memory reader; the
+			// tokenizer itself never rejects malformed markup.
+			break
+		}
+		tokenLine := line
+		line += bytes.Count(z.Raw(), []byte("\n"))
+		tok := z.Token()
+		attrs := make(map[string]string, len(tok.Attr))
+		for _, a := range tok.Attr {
+			attrs[a.Key] = strings.TrimSpace(a.Val)
+		}
+
+		switch tt {
+		case html.DoctypeToken:
+			doc.doctype = tok.Data
+		case html.TextToken:
+			switch {
+			case inTitle || heading != nil:
+				text.WriteString(tok.Data)
+			case inJSONLD:
+				doc.jsonLD = append(doc.jsonLD, describeJSONLD(tok.Data, tokenLine))
+				inJSONLD = false
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name := tok.Data
+			if slices.Contains(htmlCountedElements, name) {
+				doc.counts[name]++
+			}
+			switch name {
+			case "html":
+				doc.hasRoot = true
+				doc.lang = attrs["lang"]
+			case "title":
+				if !titleSeen {
+					inTitle = true
+					text.Reset()
+				}
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				heading = &htmlHeading{level: int(name[1] - '0'), line: tokenLine}
+				text.Reset()
+			case "meta":
+				if m := describeHTMLMeta(attrs); m != "" {
+					doc.meta = append(doc.meta, m)
+				}
+			case "script":
+				switch {
+				case attrs["type"] == "application/ld+json":
+					inJSONLD = true
+				case attrs["src"] != "":
+					doc.scripts = append(doc.scripts, describeHTMLScript(attrs))
+				default:
+					doc.inlineScripts++
+				}
+			case "style":
+				doc.inlineStyles++
+			case "link":
+				rel := strings.ToLower(attrs["rel"])
+				switch {
+				case attrs["href"] == "":
+				case strings.Contains(rel, "stylesheet"):
+					doc.styles = append(doc.styles, attrs["href"])
+				default:
+					doc.links = append(doc.links, fmt.Sprintf("%s: %s", cmp.Or(rel, "link"), attrs["href"]))
+				}
+			case "a":
+				if href, ok := attrs["href"]; ok {
+					doc.anchors = append(doc.anchors, href)
+				}
+			case "img":
+				if _, ok := attrs["alt"]; !ok {
+					doc.imagesNoAlt = append(doc.imagesNoAlt, tokenLine)
+				}
+			case "form":
+				form = &htmlForm{
+					method: strings.ToUpper(cmp.Or(attrs["method"], "get")),
+					action: attrs["action"],
+					id:     cmp.Or(attrs["id"], attrs["name"]),
+					line:   tokenLine,
+				}
+				doc.forms = append(doc.forms, form)
+			case "label":
+				if attrs["for"] != "" {
+					doc.labelled[attrs["for"]] = true
+				}
+				if tt == html.StartTagToken {
+					labelDepth++
+				}
+			case "input", "select", "textarea", "button":
+				field := describeHTMLField(name, attrs)
+				if form != nil && field != "" {
+					form.fields = append(form.fields, field)
+				}
+				typ := strings.ToLower(attrs["type"])
+				if name != "button" && typ != "hidden" && typ != "submit" && typ != "button" && typ != "reset" && typ != "image" {
+					doc.fields = append(doc.fields, htmlField{
+						name:     cmp.Or(attrs["name"], attrs["id"], name),
+						id:       attrs["id"],
+						line:     tokenLine,
+						labelled: labelDepth > 0 || attrs["aria-label"] != "" || attrs["aria-labelledby"] != "" || attrs["title"] != "",
+					})
+				}
+			}
+		case html.EndTagToken:
+			switch tok.Data {
+			case "title":
+				if inTitle {
+					doc.title = truncateRunes(strings.Join(strings.Fields(text.String()), " "), htmlTextMaxRunes)
+					inTitle, titleSeen = false, true
+				}
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				if heading != nil {
+					heading.text = truncateRunes(strings.Join(strings.Fields(text.String()), " "), htmlTextMaxRunes)
+					doc.headings = append(doc.headings, *heading)
+					heading = nil
+				}
+			case "script":
+				inJSONLD = false
+			case "form":
+				form = nil
+			case "label":
+				labelDepth = max(0, labelDepth-1)
+			}
+		}
+	}
+	return doc
+}
+
+// describeHTMLMeta renders a meta tag as "name: content".
+func describeHTMLMeta(attrs map[string]string) string {
+	if charset := attrs["charset"]; charset != "" {
+		return "charset: " + charset
+	}
+	key := cmp.Or(attrs["name"], attrs["property"], attrs["itemprop"])
+	if key == "" {
+		if equiv := attrs["http-equiv"]; equiv != "" {
+			key = "http-equiv " + strings.ToLower(equiv)
+		}
+	}
+	if key == "" {
+		return ""
+	}
+	return key + ": " + truncateRunes(strings.Join(strings.Fields(attrs["content"]), " "), htmlTextMaxRunes)
+}
+
+// describeHTMLScript renders an external script with its loading
+// attributes.
+func describeHTMLScript(attrs map[string]string) string {
+	var flags []string
+	if typ := attrs["type"]; typ != "" && typ != "text/javascript" {
+		flags = append(flags, typ)
+	}
+	for _, flag := range []string{"async", "defer", "nomodule", "integrity", "crossorigin"} {
+		if _, ok := attrs[flag]; ok {
+			flags = append(flags, flag)
+		}
+	}
+	if len(flags) == 0 {
+		return attrs["src"]
+	}
+	return attrs["src"] + " (" + strings.Join(flags, ", ") + ")"
+}
+
+// describeHTMLField renders a named form control as "name (type,
+// required)", or "" for unnamed controls other than submit buttons.
+func describeHTMLField(tag string, attrs map[string]string) string {
+	typ := strings.ToLower(attrs["type"])
+	switch tag {
+	case "select", "textarea":
+		typ = tag
+	case "button":
+		typ = cmp.Or(typ, "submit")
+	case "input":
+		typ = cmp.Or(typ, "text")
+	}
+	name := attrs["name"]
+	if name == "" {
+		if typ != "submit" {
+			return ""
+		}
+		name = "submit"
+	}
+	if _, ok := attrs["required"]; ok {
+		return name + " (" + typ + ", required)"
+	}
+	return name + " (" + typ + ")"
+}
+
+// describeJSONLD names the @type (and name) of each top-level JSON-LD node.
+func describeJSONLD(raw string, line int) string {
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return fmt.Sprintf("invalid JSON (line %d): %v", line, err)
+	}
+	var nodes []any
+	switch t := v.(type) {
+	case []any:
+		nodes = t
+	case map[string]any:
+		if graph, ok := t["@graph"].([]any); ok {
+			nodes = graph
+		} else {
+			nodes = []any{t}
+		}
+	}
+	var parts []string
+	for _, n := range nodes {
+		m, ok := n.(map[string]any)
+		if !ok {
+			continue
+		}
+		part := "(untyped)"
+		if typ, ok := m["@type"]; ok {
+			part = fmt.Sprint(typ)
+		}
+		if name, ok := m["name"].(string); ok && name != "" {
+			part += " " + truncateRunes(name, htmlTextMaxRunes)
+		}
+		parts = append(parts, part)
+	}
+	return fmt.Sprintf("%s (line %d)", cmp.Or(strings.Join(parts, "; "), "(empty)"), line)
+}
+
+// htmlAnchorTargets counts anchors by kind and lists external hosts.
+func htmlAnchorTargets(anchors []string) []string {
+	kinds := make(map[string]int)
+	hosts := make(map[string]int)
+	for _, href := range anchors {
+		u, err := url.Parse(href)
+		switch {
+		case err != nil:
+			kinds["invalid"]++
+		case strings.HasPrefix(href, "#"):
+			kinds["fragment"]++
+		case u.Scheme == "mailto" || u.Scheme == "tel" || u.Scheme == "javascript":
+			kinds[u.Scheme]++
+		case u.Host != "":
+			kinds["external"]++
+			hosts[u.Host]++
+		default:
+			kinds["internal"]++
+		}
+	}
+	var lines []string
+	for _, kind := range sortedKeys(kinds) {
+		lines = append(lines, fmt.Sprintf("%s: %d", kind, kinds[kind]))
+	}
+	for _, h := range byCount(hosts) {
+		lines = append(lines, fmt.Sprintf("host %s: %d", h.Key, h.Count))
+	}
+	return lines
+}
+
+// htmlAccessibility lists common accessibility gaps.
+func htmlAccessibility(doc *htmlDocument) []string {
+	var lines []string
+	if doc.hasRoot && doc.lang == "" {
+		lines = append(lines, "<html> has no lang attribute")
+	}
+	if len(doc.imagesNoAlt) > 0 {
+		nums := make([]string, 0, len(doc.imagesNoAlt))
+		for _, n := range doc.imagesNoAlt {
+			nums = append(nums, fmt.Sprint(n))
+		}
+		lines = append(lines, fmt.Sprintf("images without alt text: %d (lines %s)", len(doc.imagesNoAlt), strings.Join(nums, ", ")))
+	}
+	for _, f := range doc.fields {
+		if !f.labelled && !doc.labelled[f.id] {
+			lines = append(lines, fmt.Sprintf("form control %s has no label (line %d)", f.name, f.line))
+		}
+	}
+	h1s, prev := 0, 0
+	for _, h := range doc.headings {
+		if h.level == 1 {
+			h1s++
+		}
+		if prev > 0 && h.level > prev+1 {
+			lines = append(lines, fmt.Sprintf("heading level skips from H%d to H%d (line %d)", prev, h.level, h.line))
+		}
+		prev = h.level
+	}
+	if h1s > 1 {
+		lines = append(lines, fmt.Sprintf("%d H1 headings", h1s))
+	}
+	return lines
+}
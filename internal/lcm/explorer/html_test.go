@@ -0,0 +1,138 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testHTMLPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="description" content="Acme widgets for
+    every workshop">
+  <meta property="og:title" content="Acme">
+  <title>Acme | Widgets</title>
+  <link rel="stylesheet" href="/css/site.css">
+  <link rel="icon" href="/favicon.ico">
+  <link rel="canonical" href="https://acme.example/">
+  <style>body { margin: 0 }</style>
+  <script src="/js/app.js" type="module" defer></script>
+  <script>window.dataLayer = [];</script>
+  <script type="application/ld+json">
+  {"@context": "https://schema.org", "@type": "Organization", "name": "Acme Inc"}
+  </script>
+</head>
+<body>
+  <h1>Widgets</h1>
+  <img src="/hero.png">
+  <h3>Featured</h3>
+  <h2>Contact <em>us</em></h2>
+  <form id="contact" method="post" action="/contact">
+    <label for="email">Email</label>
+    <input id="email" name="email" type="email" required>
+    <input name="phone" type="tel">
+    <textarea name="message"></textarea>
+    <input type="hidden" name="csrf" value="x">
+    <button>Send</button>
+  </form>
+  <a href="/about">About</a>
+  <a href="#top">Top</a>
+  <a href="https://github.com/acme">GitHub</a>
+  <a href="mailto:hi@acme.example">Mail</a>
+</body>
+</html>
+`
+
+func TestHTMLExplorer_Explore_Structure(t *testing.T) {
+	t.Parallel()
+
+	e := &HTMLExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "index.html", Content: []byte(testHTMLPage)})
+	require.NoError(t, err)
+	require.Equal(t, "html", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Title: Acme | Widgets\nLanguage: en\nDoctype: html\n")
+	require.Contains(t, s, "Meta tags:\n"+
+		"  - charset: utf-8\n"+
+		"  - viewport: width=device-width, initial-scale=1\n"+
+		"  - description: Acme widgets for every workshop\n"+
+		"  - og:title: Acme\n")
+	require.Contains(t, s, "Heading hierarchy:\n  H1: 1\n  H2: 1\n  H3: 1\n  H4: 0\n  H5: 0\n  H6: 0\n  Total: 3\n")
+	require.Contains(t, s, "Headings:\n  - H1 Widgets (line 21)\n  - H3 Featured (line 23)\n  - H2 Contact us (line 24)\n")
+	require.Contains(t, s, "Scripts:\n  - /js/app.js (module, defer)\n  - inline scripts: 1\n")
+	require.Contains(t, s, "Stylesheets:\n  - /css/site.css\n  - inline style blocks: 1\n")
+	require.Contains(t, s, "Link references:\n  - icon: /favicon.ico\n  - canonical: https://acme.example/\n")
+	require.Contains(t, s, "Forms:\n  - POST /contact (id contact, line 25): email (email, required), phone (tel), message (textarea), csrf (hidden), submit (submit)\n")
+	require.Contains(t, s, "JSON-LD:\n  - Organization Acme Inc (line 16)\n")
+	require.True(t, strings.HasSuffix(s, "Element counts:\n"+
+		"  - <a>: 4\n"+
+		"  - <input>: 3\n"+
+		"  - <link>: 3\n"+
+		"  - <script>: 3\n"+
+		"  - <button>: 1\n"+
+		"  - <form>: 1\n"+
+		"  - <img>: 1\n"+
+		"  - <style>: 1\n"), s)
+
+	// Anchors are counted, not followed.
+	require.NotContains(t, s, "github.com")
+}
+
+func TestHTMLExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &HTMLExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "index.html", Content: []byte(testHTMLPage)})
+	require.NoError(t, err)
+
+	// The four anchors split by target, then external ones by host.
+	s := result.Summary
+	require.Contains(t, s, "Anchor targets:\n"+
+		"  - external: 1\n"+
+		"  - fragment: 1\n"+
+		"  - internal: 1\n"+
+		"  - mailto: 1\n"+
+		"  - host github.com: 1\n")
+	require.Contains(t, s, "Accessibility:\n"+
+		"  - images without alt text: 1 (lines 22)\n"+
+		"  - form control phone has no label (line 28)\n"+
+		"  - form control message has no label (line 29)\n"+
+		"  - heading level skips from H1 to H3 (line 23)\n")
+	require.NotContains(t, s, "control email")
+}
+
+func TestHTMLExplorer_Explore_Fragment(t *testing.T) {
+	t.Parallel()
+
+	content := `<div><h2>Card</h2><script type="application/ld+json">{"@graph": [{"@type": "Person", "name": "Ada"}, {"@type": ["Thing", "Product"]}]}</script>
+<script type="application/ld+json">{broken</script>
+<label>Search <input name="q"></label></div>`
+	result, err := (&HTMLExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), ExploreInput{Path: "card.htm", Content: []byte(content)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.NotContains(t, s, "Title:")
+	require.Contains(t, s, "JSON-LD:\n  - Person Ada; [Thing Product] (line 1)\n  - invalid JSON (line 2): invalid character 'b' looking for beginning of object key string\n")
+	// A fragment without <html> is not flagged for its missing lang, and
+	// the wrapping label counts for the input.
+	require.NotContains(t, s, "Accessibility:")
+}
+
+func TestHTMLExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "index.html", Content: []byte(testHTMLPage)})
+		require.NoError(t, err)
+		require.Equal(t, "html", result.ExplorerUsed)
+		require.Contains(t, result.Summary, "### Forms")
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Accessibility"))
+	}
+}
@@ -12,7 +12,8 @@ import (
 )
 
 // ImageExplorer explores image files with pure Go parsing for common formats
-// and optional external tool fallback (identify, exiftool).
+// (dimensions, color depth and EXIF metadata) and optional external tool
+// fallback (identify, exiftool).
 type ImageExplorer struct {
 	formatterProfile OutputProfile
 }
@@ -102,11 +103,19 @@ func (e *ImageExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 	if info.animated {
 		summary.WriteString("Animated: yes\n")
 	}
+	if info.frames > 1 {
+		fmt.Fprintf(&summary, "Frames: %d\n", info.frames)
+	}
 
-	// Enhancement mode: attempt exiftool for richer metadata.
+	// EXCEED MODE: EXIF metadata, parsed natively with exiftool as the
+	// fallback for formats we do not read.
 	if e.formatterProfile == OutputProfileEnhancement {
-		exif := exiftoolMetadata(ctx, input.Content)
-		if exif != "" {
+		if lines := info.exif.lines(); len(lines) > 0 {
+			summary.WriteString("\nEXIF metadata:\n")
+			for _, line := range lines {
+				fmt.Fprintf(&summary, "  %s\n", line)
+			}
+		} else if exif := exiftoolMetadata(ctx, input.Content); exif != "" {
 			summary.WriteString("\nEXIF metadata:\n")
 			summary.WriteString(exif)
 		}
@@ -127,6 +136,8 @@ type imageInfo struct {
 	bitDepth  int
 	colorType string
 	animated  bool
+	frames    int
+	exif      exifInfo
 }
 
 // parseImageInfo attempts pure Go parsing of common image formats.
@@ -140,6 +151,10 @@ func parseImageInfo(content []byte, format string) imageInfo {
 		return parseGIF(content)
 	case "BMP":
 		return parseBMP(content)
+	case "WebP":
+		return parseWebP(content)
+	case "TIFF":
+		return parseTIFF(content)
 	default:
 		return imageInfo{}
 	}
@@ -175,14 +190,22 @@ func parsePNG(content []byte) imageInfo {
 
 	// Scan for acTL chunk (APNG animation control).
 	animated := scanPNGChunk(content, "acTL")
-
-	return imageInfo{
+	info := imageInfo{
 		width:     width,
 		height:    height,
 		bitDepth:  bitDepth,
 		colorType: colorType,
 		animated:  animated,
 	}
+	if animated {
+		if acTL := pngChunkData(content, "acTL"); len(acTL) >= 4 {
+			info.frames = int(binary.BigEndian.Uint32(acTL[:4]))
+		}
+	}
+	if exif := pngChunkData(content, "eXIf"); exif != nil {
+		info.exif = parseExif(exif)
+	}
+	return info
 }
 
 // pngColorType returns a human-readable string for the PNG color type byte.
@@ -203,6 +226,29 @@ func pngColorType(ct byte) string {
 	}
 }
 
+// pngChunkData returns the data of the first chunk of the given name, or
+// nil when there is none or it is truncated.
+func pngChunkData(content []byte, name string) []byte {
+	offset := 8 // Skip PNG signature.
+	for offset+8 <= len(content) {
+		chunkLen := int64(binary.BigEndian.Uint32(content[offset : offset+4]))
+		start := int64(offset) + 8
+		if string(content[offset+4:offset+8]) == name {
+			if start+chunkLen > int64(len(content)) {
+				return nil
+			}
+			return content[start : start+chunkLen]
+		}
+		// Skip chunk: length(4) + type(4) + data(chunkLen) + CRC(4).
+		next := start + chunkLen + 4
+		if next > int64(len(content)) {
+			break
+		}
+		offset = int(next)
+	}
+	return nil
+}
+
 // scanPNGChunk scans for a named chunk in PNG data. It walks the chunk
 // chain starting after the 8-byte signature.
 func scanPNGChunk(content []byte, name string) bool {
@@ -232,7 +278,9 @@ func scanPNGChunk(content []byte, name string) bool {
 	return false
 }
 
-// parseJPEG extracts dimensions from SOF markers in the first 64 KB.
+// parseJPEG extracts dimensions, sample precision and components from the
+// SOF marker, and EXIF metadata from the APP1 segment before it, in the
+// first 64 KB.
 func parseJPEG(content []byte) imageInfo {
 	if len(content) < 2 {
 		return imageInfo{}
@@ -242,9 +290,10 @@ func parseJPEG(content []byte) imageInfo {
 		return imageInfo{}
 	}
 
-	// Scan for SOF markers (0xFFC0-0xFFC3) in the first 64 KB.
+	// Scan for SOF markers in the first 64 KB.
 	limit := min(len(content), 65536)
 
+	var info imageInfo
 	offset := 2
 	for offset+2 <= limit {
 		// Find next marker.
@@ -258,18 +307,22 @@ func parseJPEG(content []byte) imageInfo {
 			offset++
 			continue
 		}
-		// SOF0-SOF3 markers: 0xC0-0xC3.
-		if marker >= 0xC0 && marker <= 0xC3 {
-			// SOF segment: length(2) + precision(1) + height(2) + width(2).
-			if offset+9 > limit {
+		// Start of scan: the headers are over.
+		if marker == 0xDA {
+			break
+		}
+		// SOF markers: 0xC0-0xCF except DHT (C4), JPG (C8) and DAC (CC).
+		if marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC {
+			// SOF segment: length(2) + precision(1) + height(2) + width(2) +
+			// components(1).
+			if offset+10 > limit {
 				break
 			}
-			height := binary.BigEndian.Uint16(content[offset+5 : offset+7])
-			width := binary.BigEndian.Uint16(content[offset+7 : offset+9])
-			return imageInfo{
-				width:  uint32(width),
-				height: uint32(height),
-			}
+			info.bitDepth = int(content[offset+4])
+			info.height = uint32(binary.BigEndian.Uint16(content[offset+5 : offset+7]))
+			info.width = uint32(binary.BigEndian.Uint16(content[offset+7 : offset+9]))
+			info.colorType = jpegColorType(content[offset+9])
+			return info
 		}
 		// Skip to next marker using segment length.
 		if offset+4 > limit {
@@ -279,15 +332,37 @@ func parseJPEG(content []byte) imageInfo {
 		if segLen < 2 {
 			break
 		}
+		// APP1 carries EXIF as a TIFF structure after "Exif\0\0".
+		if marker == 0xE1 && offset+2+segLen <= len(content) {
+			if seg := content[offset+4 : offset+2+segLen]; bytes.HasPrefix(seg, []byte("Exif\x00\x00")) {
+				info.exif = parseExif(seg)
+			}
+		}
 		offset += 2 + segLen
 	}
-	return imageInfo{}
+	return info
+}
+
+// jpegColorType names the color model of a JPEG by its component count.
+func jpegColorType(components byte) string {
+	switch components {
+	case 1:
+		return "grayscale"
+	case 3:
+		return "YCbCr"
+	case 4:
+		return "CMYK"
+	default:
+		return fmt.Sprintf("%d components", components)
+	}
 }
 
-// parseGIF extracts logical screen dimensions from the GIF header.
+// parseGIF extracts logical screen dimensions and color resolution from
+// the GIF header, and counts the frames of animated GIFs.
 func parseGIF(content []byte) imageInfo {
 	// GIF header: signature (6 bytes) + logical screen descriptor.
-	// Logical screen descriptor: width (2 LE) + height (2 LE) at offset 6.
+	// Logical screen descriptor: width (2 LE) + height (2 LE) at offset 6,
+	// then packed fields, background color index and aspect ratio.
 	if len(content) < 10 {
 		return imageInfo{}
 	}
@@ -297,10 +372,185 @@ func parseGIF(content []byte) imageInfo {
 	}
 	width := binary.LittleEndian.Uint16(content[6:8])
 	height := binary.LittleEndian.Uint16(content[8:10])
-	return imageInfo{
+	info := imageInfo{
 		width:  uint32(width),
 		height: uint32(height),
 	}
+	if len(content) < 13 {
+		return info
+	}
+	packed := content[10]
+	info.bitDepth = int((packed>>4)&0x07) + 1
+	info.colorType = "indexed (palette)"
+	offset := 13
+	if packed&0x80 != 0 {
+		offset += 3 << ((packed & 0x07) + 1)
+	}
+	info.frames = countGIFFrames(content, offset)
+	info.animated = info.frames > 1
+	return info
+}
+
+// countGIFFrames counts the image descriptors in the GIF blocks starting
+// at offset, stopping at the trailer or at truncated data.
+func countGIFFrames(content []byte, offset int) int {
+	frames := 0
+	for offset < len(content) {
+		switch content[offset] {
+		case 0x21: // Extension: label, then data sub-blocks.
+			offset = skipGIFSubBlocks(content, offset+2)
+		case 0x2C: // Image descriptor: 10 bytes, local color table, LZW minimum code size, data sub-blocks.
+			if offset+10 > len(content) {
+				return frames
+			}
+			frames++
+			packed := content[offset+9]
+			offset += 10
+			if packed&0x80 != 0 {
+				offset += 3 << ((packed & 0x07) + 1)
+			}
+			offset = skipGIFSubBlocks(content, offset+1)
+		default: // Trailer (0x3B) or garbage.
+			return frames
+		}
+	}
+	return frames
+}
+
+// skipGIFSubBlocks returns the offset after the sub-block chain at offset.
+func skipGIFSubBlocks(content []byte, offset int) int {
+	for offset < len(content) {
+		size := int(content[offset])
+		offset++
+		if size == 0 {
+			return offset
+		}
+		offset += size
+	}
+	return len(content)
+}
+
+// parseWebP extracts dimensions, alpha, animation and EXIF metadata from
+// the chunks of a WebP RIFF container.
+func parseWebP(content []byte) imageInfo {
+	if len(content) < 12 || string(content[:4]) != "RIFF" || string(content[8:12]) != "WEBP" {
+		return imageInfo{}
+	}
+	var info imageInfo
+	alpha := false
+	offset := 12
+	for offset+8 <= len(content) {
+		fourCC := string(content[offset : offset+4])
+		size := int64(binary.LittleEndian.Uint32(content[offset+4 : offset+8]))
+		start := int64(offset) + 8
+		end := min(start+size, int64(len(content)))
+		data := content[start:end]
+		switch fourCC {
+		case "VP8X": // Extended: flags, then 24-bit canvas width-1 and height-1.
+			if len(data) >= 10 {
+				alpha = data[0]&0x10 != 0
+				info.animated = data[0]&0x02 != 0
+				info.width = uint24LE(data[4:7]) + 1
+				info.height = uint24LE(data[7:10]) + 1
+			}
+		case "VP8 ": // Lossy: frame tag, start code, 14-bit width and height.
+			if len(data) >= 10 && info.width == 0 && bytes.Equal(data[3:6], []byte{0x9D, 0x01, 0x2A}) {
+				info.width = uint32(binary.LittleEndian.Uint16(data[6:8]) & 0x3FFF)
+				info.height = uint32(binary.LittleEndian.Uint16(data[8:10]) & 0x3FFF)
+			}
+		case "VP8L": // Lossless: signature, 14-bit width-1 and height-1, alpha hint.
+			if len(data) >= 5 && info.width == 0 && data[0] == 0x2F {
+				bits := binary.LittleEndian.Uint32(data[1:5])
+				info.width = bits&0x3FFF + 1
+				info.height = (bits>>14)&0x3FFF + 1
+				alpha = alpha || bits>>28&1 != 0
+			}
+		case "ANMF":
+			info.frames++
+		case "EXIF":
+			info.exif = parseExif(data)
+		}
+		// Chunks are padded to an even size.
+		next := start + size + size&1
+		if next > int64(len(content)) {
+			break
+		}
+		offset = int(next)
+	}
+	if info.width > 0 {
+		// WebP always decodes to 8 bits per channel.
+		info.bitDepth = 8
+		info.colorType = "RGB"
+		if alpha {
+			info.colorType = "RGBA"
+		}
+	}
+	return info
+}
+
+// uint24LE decodes a 24-bit little-endian integer.
+func uint24LE(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+// parseTIFF extracts dimensions, bit depth and photometric interpretation
+// from IFD0 of a TIFF file (camera raw formats included), and its EXIF
+// metadata.
+func parseTIFF(content []byte) imageInfo {
+	r, off, ok := newTIFFReader(content)
+	if !ok {
+		return imageInfo{}
+	}
+	ifd0 := r.readIFD(off)
+	var info imageInfo
+	if e, ok := ifd0[tiffTagImageWidth]; ok {
+		info.width, _ = r.uint(e)
+	}
+	if e, ok := ifd0[tiffTagImageLength]; ok {
+		info.height, _ = r.uint(e)
+	}
+	if e, ok := ifd0[tiffTagBitsPerSample]; ok {
+		if v, ok := r.uint(e); ok {
+			info.bitDepth = int(v)
+		}
+	}
+	samples := uint32(1)
+	if e, ok := ifd0[tiffTagSamplesPerPixel]; ok {
+		samples, _ = r.uint(e)
+	}
+	if e, ok := ifd0[tiffTagPhotometric]; ok {
+		if v, ok := r.uint(e); ok {
+			info.colorType = tiffColorType(v, samples)
+		}
+	}
+	info.exif = r.exif(ifd0)
+	return info
+}
+
+// tiffColorType names a TIFF photometric interpretation, noting an extra
+// alpha sample for RGB.
+func tiffColorType(photometric, samples uint32) string {
+	switch photometric {
+	case 0, 1:
+		return "grayscale"
+	case 2:
+		if samples > 3 {
+			return "RGBA"
+		}
+		return "RGB"
+	case 3:
+		return "indexed (palette)"
+	case 5:
+		return "CMYK"
+	case 6:
+		return "YCbCr"
+	case 32803:
+		return "color filter array (raw)"
+	case 34892:
+		return "linear raw"
+	default:
+		return fmt.Sprintf("unknown (%d)", photometric)
+	}
 }
 
 // parseBMP extracts dimensions and bit depth from the BMP info header.
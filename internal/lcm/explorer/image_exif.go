@@ -0,0 +1,252 @@
+package explorer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// exifInfo is the EXIF metadata worth summarizing. GPS coordinates are
+// deliberately not decoded; only their presence is reported.
+type exifInfo struct {
+	make             string
+	model            string
+	software         string
+	dateTime         string
+	dateTimeOriginal string
+	orientation      int
+	gps              bool
+}
+
+// TIFF tags read from IFD0 and the EXIF sub-IFD.
+const (
+	tiffTagImageWidth       = 0x0100
+	tiffTagImageLength      = 0x0101
+	tiffTagBitsPerSample    = 0x0102
+	tiffTagPhotometric      = 0x0106
+	tiffTagMake             = 0x010F
+	tiffTagModel            = 0x0110
+	tiffTagOrientation      = 0x0112
+	tiffTagSamplesPerPixel  = 0x0115
+	tiffTagSoftware         = 0x0131
+	tiffTagDateTime         = 0x0132
+	tiffTagExifIFD          = 0x8769
+	tiffTagGPSIFD           = 0x8825
+	exifTagDateTimeOriginal = 0x9003
+)
+
+// maxTIFFEntries bounds the entries read from one IFD of a corrupt file.
+const maxTIFFEntries = 1024
+
+// tiffEntry is one IFD entry with its value bytes resolved.
+type tiffEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// tiffReader reads IFDs out of a TIFF structure, as found in TIFF files
+// and in the EXIF blocks of JPEG, PNG and WebP.
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// newTIFFReader checks the TIFF header of data and returns a reader with
+// the offset of IFD0.
+func newTIFFReader(data []byte) (tiffReader, uint32, bool) {
+	if len(data) < 8 {
+		return tiffReader{}, 0, false
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return tiffReader{}, 0, false
+	}
+	if order.Uint16(data[2:4]) != 42 {
+		return tiffReader{}, 0, false
+	}
+	return tiffReader{data: data, order: order}, order.Uint32(data[4:8]), true
+}
+
+// tiffTypeSize returns the size in bytes of one value of a TIFF field type.
+func tiffTypeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 6, 7: // BYTE, ASCII, SBYTE, UNDEFINED
+		return 1
+	case 3, 8: // SHORT, SSHORT
+		return 2
+	case 4, 9, 11: // LONG, SLONG, FLOAT
+		return 4
+	case 5, 10, 12: // RATIONAL, SRATIONAL, DOUBLE
+		return 8
+	default:
+		return 0
+	}
+}
+
+// readIFD returns the entries of the IFD at off by tag. Entries whose
+// value lies outside the data are skipped.
+func (r tiffReader) readIFD(off uint32) map[uint16]tiffEntry {
+	if off == 0 || int64(off)+2 > int64(len(r.data)) {
+		return nil
+	}
+	n := int(r.order.Uint16(r.data[off : off+2]))
+	n = min(n, maxTIFFEntries)
+	entries := make(map[uint16]tiffEntry, n)
+	for i := range n {
+		start := int(off) + 2 + i*12
+		if start+12 > len(r.data) {
+			break
+		}
+		raw := r.data[start : start+12]
+		tag := r.order.Uint16(raw[0:2])
+		typ := r.order.Uint16(raw[2:4])
+		count := r.order.Uint32(raw[4:8])
+		size := int64(tiffTypeSize(typ)) * int64(count)
+		if size == 0 {
+			continue
+		}
+		var value []byte
+		if size <= 4 {
+			value = raw[8 : 8+size]
+		} else {
+			valueOff := int64(r.order.Uint32(raw[8:12]))
+			if valueOff+size > int64(len(r.data)) {
+				continue
+			}
+			value = r.data[valueOff : valueOff+size]
+		}
+		entries[tag] = tiffEntry{typ: typ, count: count, value: value}
+	}
+	return entries
+}
+
+// uint returns the first value of a BYTE, SHORT or LONG entry.
+func (r tiffReader) uint(e tiffEntry) (uint32, bool) {
+	switch e.typ {
+	case 1:
+		return uint32(e.value[0]), true
+	case 3:
+		return uint32(r.order.Uint16(e.value)), true
+	case 4:
+		return r.order.Uint32(e.value), true
+	default:
+		return 0, false
+	}
+}
+
+// ascii returns the text of an ASCII entry, up to its first NUL.
+func (r tiffReader) ascii(e tiffEntry) string {
+	if e.typ != 2 {
+		return ""
+	}
+	value := e.value
+	if i := bytes.IndexByte(value, 0); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(string(value))
+}
+
+// parseExif reads the EXIF metadata of a TIFF structure. data may carry
+// the "Exif\0\0" prefix of JPEG APP1 segments.
+func parseExif(data []byte) exifInfo {
+	data = bytes.TrimPrefix(data, []byte("Exif\x00\x00"))
+	r, off, ok := newTIFFReader(data)
+	if !ok {
+		return exifInfo{}
+	}
+	return r.exif(r.readIFD(off))
+}
+
+// exif reads the EXIF metadata from IFD0 and the sub-IFDs it points to.
+func (r tiffReader) exif(ifd0 map[uint16]tiffEntry) exifInfo {
+	var x exifInfo
+	x.make = r.ascii(ifd0[tiffTagMake])
+	x.model = r.ascii(ifd0[tiffTagModel])
+	x.software = r.ascii(ifd0[tiffTagSoftware])
+	x.dateTime = r.ascii(ifd0[tiffTagDateTime])
+	if e, ok := ifd0[tiffTagOrientation]; ok {
+		if v, ok := r.uint(e); ok {
+			x.orientation = int(v)
+		}
+	}
+	if e, ok := ifd0[tiffTagExifIFD]; ok {
+		if off, ok := r.uint(e); ok {
+			x.dateTimeOriginal = r.ascii(r.readIFD(off)[exifTagDateTimeOriginal])
+		}
+	}
+	if e, ok := ifd0[tiffTagGPSIFD]; ok {
+		if off, ok := r.uint(e); ok {
+			x.gps = len(r.readIFD(off)) > 0
+		}
+	}
+	return x
+}
+
+// lines returns the summary lines of the metadata, none when it is empty.
+func (x exifInfo) lines() []string {
+	var lines []string
+	camera := x.model
+	if x.make != "" && !strings.HasPrefix(strings.ToLower(x.model), strings.ToLower(x.make)) {
+		camera = strings.TrimSpace(x.make + " " + x.model)
+	}
+	if camera != "" {
+		lines = append(lines, "Camera: "+camera)
+	}
+	if x.dateTimeOriginal != "" {
+		lines = append(lines, "Taken: "+exifDate(x.dateTimeOriginal))
+	}
+	if x.dateTime != "" && x.dateTime != x.dateTimeOriginal {
+		lines = append(lines, "Modified: "+exifDate(x.dateTime))
+	}
+	if x.software != "" {
+		lines = append(lines, "Software: "+x.software)
+	}
+	if o := exifOrientation(x.orientation); o != "" {
+		lines = append(lines, "Orientation: "+o)
+	}
+	if x.gps {
+		lines = append(lines, "GPS: present (coordinates not shown)")
+	}
+	return lines
+}
+
+// exifDate rewrites the "YYYY:MM:DD HH:MM:SS" EXIF timestamp format with
+// dashes in the date.
+func exifDate(s string) string {
+	if len(s) >= 10 && s[4] == ':' && s[7] == ':' {
+		return s[:4] + "-" + s[5:7] + "-" + s[8:]
+	}
+	return s
+}
+
+// exifOrientation describes an EXIF orientation value other than the
+// default "top-left".
+func exifOrientation(v int) string {
+	switch v {
+	case 0, 1:
+		return ""
+	case 2:
+		return "mirrored horizontally"
+	case 3:
+		return "rotated 180°"
+	case 4:
+		return "mirrored vertically"
+	case 5:
+		return "mirrored horizontally, rotated 270° CW"
+	case 6:
+		return "rotated 90° CW"
+	case 7:
+		return "mirrored horizontally, rotated 90° CW"
+	case 8:
+		return "rotated 270° CW"
+	default:
+		return fmt.Sprintf("unknown (%d)", v)
+	}
+}
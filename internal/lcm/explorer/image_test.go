@@ -532,3 +532,213 @@ func TestImageExplorer_SVGNotClaimed(t *testing.T) {
 	svgContent := []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`)
 	require.False(t, explorer.CanHandle("icon", svgContent))
 }
+
+// buildExifTIFF builds a little-endian TIFF structure whose IFD0 holds the
+// given camera make and model, points to an EXIF IFD with the capture time
+// and, when gps is set, to a GPS IFD with one entry.
+func buildExifTIFF(make_, model, taken string, gps bool) []byte {
+	le := binary.LittleEndian
+	type entry struct {
+		tag, typ uint16
+		count    uint32
+		value    []byte // Stored after the IFDs when longer than 4 bytes.
+	}
+	ascii := func(s string) []byte { return append([]byte(s), 0) }
+	u32 := func(v uint32) []byte { b := make([]byte, 4); le.PutUint32(b, v); return b }
+
+	// Layout: header (8), IFD0 (4 entries), EXIF IFD (1 entry), GPS IFD
+	// (1 entry), then the out-of-line values.
+	ifdSize := func(n int) uint32 { return uint32(2 + 12*n + 4) }
+	ifd0Off := uint32(8)
+	exifOff := ifd0Off + ifdSize(4)
+	gpsOff := exifOff + ifdSize(1)
+	dataOff := gpsOff + ifdSize(1)
+
+	gpsPointer := uint32(0)
+	if gps {
+		gpsPointer = gpsOff
+	}
+	ifds := [][]entry{
+		{
+			{tag: tiffTagMake, typ: 2, count: uint32(len(make_) + 1), value: ascii(make_)},
+			{tag: tiffTagModel, typ: 2, count: uint32(len(model) + 1), value: ascii(model)},
+			{tag: tiffTagExifIFD, typ: 4, count: 1, value: u32(exifOff)},
+			{tag: tiffTagGPSIFD, typ: 4, count: 1, value: u32(gpsPointer)},
+		},
+		{{tag: exifTagDateTimeOriginal, typ: 2, count: uint32(len(taken) + 1), value: ascii(taken)}},
+		{{tag: 0x0001, typ: 2, count: 2, value: ascii("N")}},
+	}
+
+	buf := []byte{'I', 'I', 42, 0}
+	buf = append(buf, u32(ifd0Off)...)
+	var data []byte
+	for _, ifd := range ifds {
+		buf = le.AppendUint16(buf, uint16(len(ifd)))
+		for _, e := range ifd {
+			buf = le.AppendUint16(buf, e.tag)
+			buf = le.AppendUint16(buf, e.typ)
+			buf = le.AppendUint32(buf, e.count)
+			if len(e.value) <= 4 {
+				buf = append(buf, append(e.value, make([]byte, 4-len(e.value))...)...)
+				continue
+			}
+			buf = append(buf, u32(dataOff+uint32(len(data)))...)
+			data = append(data, e.value...)
+		}
+		buf = append(buf, 0, 0, 0, 0) // No next IFD.
+	}
+	return append(buf, data...)
+}
+
+func TestParseExif(t *testing.T) {
+	t.Parallel()
+
+	x := parseExif(append([]byte("Exif\x00\x00"), buildExifTIFF("Canon", "Canon EOS R5", "2024:05:06 07:08:09", true)...))
+	require.Equal(t, []string{
+		"Camera: Canon EOS R5",
+		"Taken: 2024-05-06 07:08:09",
+		"GPS: present (coordinates not shown)",
+	}, x.lines())
+
+	x = parseExif(buildExifTIFF("FUJIFILM", "X-T5", "2024:05:06 07:08:09", false))
+	require.Equal(t, "Camera: FUJIFILM X-T5", x.lines()[0])
+	require.False(t, x.gps)
+
+	require.Empty(t, parseExif([]byte("Exif\x00\x00II")).lines())
+}
+
+func TestImageExplorer_JPEGExif(t *testing.T) {
+	t.Parallel()
+
+	tiff := buildExifTIFF("Canon", "Canon EOS R5", "2024:05:06 07:08:09", true)
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	content := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte((len(app1) + 2) >> 8), byte(len(app1) + 2)}
+	content = append(content, app1...)
+	// Progressive SOF2 with a single (grayscale) component.
+	content = append(content, 0xFF, 0xC2, 0x00, 0x0B, 0x08, 0x01, 0xE0, 0x02, 0x80, 0x01, 1, 0x11, 0)
+
+	info := parseJPEG(content)
+	require.Equal(t, uint32(640), info.width)
+	require.Equal(t, uint32(480), info.height)
+	require.Equal(t, 8, info.bitDepth)
+	require.Equal(t, "grayscale", info.colorType)
+
+	explorer := &ImageExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := explorer.Explore(context.Background(), ExploreInput{Path: "photo.jpg", Content: content})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "EXIF metadata:\n  Camera: Canon EOS R5\n  Taken: 2024-05-06 07:08:09\n  GPS: present")
+
+	// EXIF is enhancement output only.
+	result, err = (&ImageExplorer{formatterProfile: OutputProfileParity}).Explore(context.Background(), ExploreInput{Path: "photo.jpg", Content: content})
+	require.NoError(t, err)
+	require.NotContains(t, result.Summary, "Camera:")
+}
+
+func TestImageExplorer_AnimatedGIF(t *testing.T) {
+	t.Parallel()
+
+	content := buildGIF(16, 16)
+	content = append(content, 0xF2, 0, 0)           // Global color table of 8 entries, 8-bit resolution.
+	content = append(content, make([]byte, 3*8)...) // Color table.
+	frame := []byte{0x2C, 0, 0, 0, 0, 16, 0, 16, 0, 0, 0x02, 0x01, 0x00, 0x00}
+	content = append(content, 0x21, 0xF9, 0x04, 0, 0, 0, 0, 0x00) // Graphic control extension.
+	content = append(content, frame...)
+	content = append(content, frame...)
+	content = append(content, 0x3B)
+
+	info := parseGIF(content)
+	require.Equal(t, 8, info.bitDepth)
+	require.Equal(t, "indexed (palette)", info.colorType)
+	require.Equal(t, 2, info.frames)
+	require.True(t, info.animated)
+}
+
+func TestImageExplorer_WebP(t *testing.T) {
+	t.Parallel()
+
+	chunk := func(fourCC string, data []byte) []byte {
+		b := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+		b = append(b, data...)
+		if len(data)%2 == 1 {
+			b = append(b, 0)
+		}
+		return b
+	}
+	riff := func(chunks ...[]byte) []byte {
+		var body []byte
+		for _, c := range chunks {
+			body = append(body, c...)
+		}
+		b := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)+4))...)
+		return append(append(b, "WEBP"...), body...)
+	}
+
+	t.Run("lossy", func(t *testing.T) {
+		t.Parallel()
+		info := parseWebP(riff(chunk("VP8 ", []byte{0, 0, 0, 0x9D, 0x01, 0x2A, 0x20, 0x03, 0x58, 0x02})))
+		require.Equal(t, uint32(800), info.width)
+		require.Equal(t, uint32(600), info.height)
+		require.Equal(t, "RGB", info.colorType)
+	})
+
+	t.Run("lossless with alpha", func(t *testing.T) {
+		t.Parallel()
+		bits := uint32(99) | uint32(49)<<14 | 1<<28
+		info := parseWebP(riff(chunk("VP8L", binary.LittleEndian.AppendUint32([]byte{0x2F}, bits))))
+		require.Equal(t, uint32(100), info.width)
+		require.Equal(t, uint32(50), info.height)
+		require.Equal(t, "RGBA", info.colorType)
+	})
+
+	t.Run("extended animated with EXIF", func(t *testing.T) {
+		t.Parallel()
+		vp8x := []byte{0x1A, 0, 0, 0, 0x3F, 0x01, 0, 0xEF, 0, 0} // Alpha, EXIF and animation flags; 320x240.
+		content := riff(
+			chunk("VP8X", vp8x),
+			chunk("ANMF", make([]byte, 16)),
+			chunk("ANMF", make([]byte, 16)),
+			chunk("EXIF", buildExifTIFF("Google", "Pixel 8", "2024:01:02 03:04:05", false)),
+		)
+		explorer := &ImageExplorer{formatterProfile: OutputProfileEnhancement}
+		result, err := explorer.Explore(context.Background(), ExploreInput{Path: "anim.webp", Content: content})
+		require.NoError(t, err)
+		require.Contains(t, result.Summary, "Format: WebP\n")
+		require.Contains(t, result.Summary, "Dimensions: 320x240\n")
+		require.Contains(t, result.Summary, "Color type: RGBA\n")
+		require.Contains(t, result.Summary, "Animated: yes\nFrames: 2\n")
+		require.Contains(t, result.Summary, "Camera: Google Pixel 8")
+	})
+}
+
+func TestImageExplorer_TIFF(t *testing.T) {
+	t.Parallel()
+
+	le := binary.LittleEndian
+	content := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	content = le.AppendUint16(content, 5)
+	for _, e := range []struct {
+		tag, typ uint16
+		value    uint32
+	}{
+		{tiffTagImageWidth, 4, 6000},
+		{tiffTagImageLength, 3, 4000},
+		{tiffTagBitsPerSample, 3, 16},
+		{tiffTagPhotometric, 3, 2},
+		{tiffTagSamplesPerPixel, 3, 4},
+	} {
+		content = le.AppendUint16(content, e.tag)
+		content = le.AppendUint16(content, e.typ)
+		content = le.AppendUint32(content, 1)
+		content = le.AppendUint32(content, e.value)
+	}
+	content = append(content, 0, 0, 0, 0)
+
+	info := parseTIFF(content)
+	require.Equal(t, uint32(6000), info.width)
+	require.Equal(t, uint32(4000), info.height)
+	require.Equal(t, 16, info.bitDepth)
+	require.Equal(t, "RGBA", info.colorType)
+
+	// Corrupt offsets are ignored rather than read out of bounds.
+	require.Zero(t, parseTIFF([]byte{'M', 'M', 0, 42, 0xFF, 0xFF, 0xFF, 0xFF}).width)
+}
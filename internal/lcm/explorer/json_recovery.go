@@ -0,0 +1,150 @@
+package explorer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// jsonRecovery is truncated JSON made decodable by cutting an incomplete
+// trailing value and closing the strings and containers left open.
+type jsonRecovery struct {
+	value any
+	// kept is the number of input bytes in the repaired document.
+	kept int
+	// suffix is what was appended to close the document.
+	suffix string
+}
+
+// recoverTruncatedJSON repairs content that ends inside an object or
+// array. It first closes everything open at the end of the input, which
+// keeps a truncated string or number, and otherwise cuts back to the end
+// of the last complete element. ok is false when content is not truncated
+// JSON with a container at the top.
+func recoverTruncatedJSON(content []byte) (jsonRecovery, bool) {
+	trimmed := bytes.TrimLeft(content, " \t\r\n")
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return jsonRecovery{}, false
+	}
+
+	var (
+		stack    []byte
+		inString bool
+		escaped  bool
+		// cut and cutStack are the last offset at which the document can
+		// end once the containers open there are closed.
+		cut      = -1
+		cutStack []byte
+	)
+	mark := func(at int) {
+		cut, cutStack = at, bytes.Clone(stack)
+	}
+	for i, c := range content {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+			mark(i + 1)
+		case '}', ']':
+			if len(stack) == 0 {
+				return jsonRecovery{}, false
+			}
+			stack = stack[:len(stack)-1]
+			mark(i + 1)
+		case ',':
+			mark(i)
+		}
+	}
+	if len(stack) == 0 && !inString {
+		// Complete, or broken by something other than truncation.
+		return jsonRecovery{}, false
+	}
+
+	attempts := []struct {
+		doc   []byte
+		stack []byte
+	}{{doc: bytes.TrimRight(content, " \t\r\n"), stack: stack}}
+	if inString {
+		attempts[0].doc = append(bytes.Clone(content), '"')
+	}
+	if cut >= 0 {
+		attempts = append(attempts, struct {
+			doc   []byte
+			stack []byte
+		}{doc: content[:cut], stack: cutStack})
+	}
+	for _, a := range attempts {
+		closers := jsonClosers(a.stack)
+		doc := append(bytes.Clone(a.doc), closers...)
+		var value any
+		if json.Unmarshal(doc, &value) == nil {
+			kept := min(len(a.doc), len(content))
+			return jsonRecovery{value: value, kept: kept, suffix: string(doc[kept:])}, true
+		}
+	}
+	return jsonRecovery{}, false
+}
+
+// jsonClosers returns the brackets that close the open containers of
+// stack, innermost first.
+func jsonClosers(stack []byte) []byte {
+	out := make([]byte, 0, len(stack))
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == '{' {
+			out = append(out, '}')
+		} else {
+			out = append(out, ']')
+		}
+	}
+	return out
+}
+
+// isJSONTruncation reports whether err is a decoding error at the end of
+// content, the way cut-off tool output fails.
+func isJSONTruncation(content []byte, err error) bool {
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr) && syntaxErr.Offset >= int64(len(content))
+}
+
+// recoveredJSONResult summarizes the structure recovered from truncated
+// JSON. The degraded block still reports the decoding failure, and the
+// provenance line flags that the structure is a repair, not the file.
+func recoveredJSONResult(path string, content []byte, err error, r jsonRecovery) ExploreResult {
+	size := len(content)
+	d := jsonDegradation(path, content, err)
+	d.Progress = fmt.Sprintf("recovered the structure of the first %d bytes by appending %q", r.kept, r.suffix)
+	d.Examined = int64(r.kept)
+	d.NextSteps = []string{
+		"Treat the last keys and items as possibly incomplete",
+		"Re-run the command that produced the file without truncating its output",
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "JSON file (recovered from truncation): %s\n", filepath.Base(path))
+	fmt.Fprintf(&summary, "Size: %d bytes\n", size)
+	summary.WriteString("Provenance: recovered from truncation\n")
+	fmt.Fprintf(&summary, "Recovered: %d of %d bytes (%.1f%%), %d bytes repaired\n",
+		r.kept, size, 100*float64(r.kept)/float64(max(size, 1)), len(r.suffix)+size-r.kept)
+	d.write(&summary)
+
+	summary.WriteString("\nStructure:\n")
+	describeJSONValue(&summary, r.value, 0, 3)
+
+	result := summary.String()
+	return ExploreResult{Summary: result, ExplorerUsed: "json", TokenEstimate: estimateTokens(result)}
+}
@@ -0,0 +1,89 @@
+package explorer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverTruncatedJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    any
+		kept    int
+		suffix  string
+	}{
+		{
+			name:    "dangling key",
+			content: `{"key": "value", "incomplete":`,
+			want:    map[string]any{"key": "value"},
+			kept:    15,
+			suffix:  "}",
+		},
+		{
+			name:    "string cut mid-value",
+			content: `{"items": [{"id": 1, "name": "wid`,
+			want:    map[string]any{"items": []any{map[string]any{"id": float64(1), "name": "wid"}}},
+			kept:    33,
+			suffix:  `"}]}`,
+		},
+		{
+			name:    "partial literal",
+			content: "[1, 2, tru",
+			want:    []any{float64(1), float64(2)},
+			kept:    5,
+			suffix:  "]",
+		},
+		{
+			name:    "trailing whitespace after number",
+			content: "{\"n\": 12\n",
+			want:    map[string]any{"n": float64(12)},
+			kept:    8,
+			suffix:  "}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, ok := recoverTruncatedJSON([]byte(tt.content))
+			require.True(t, ok)
+			require.Equal(t, tt.want, r.value)
+			require.Equal(t, tt.kept, r.kept)
+			require.Equal(t, tt.suffix, r.suffix)
+		})
+	}
+
+	for _, content := range []string{`"just a string`, `{"a": 1}`, `{"a": 1}}`, ``} {
+		_, ok := recoverTruncatedJSON([]byte(content))
+		require.False(t, ok, content)
+	}
+}
+
+func TestJSONExplorer_RecoversTruncation(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"name": "web", "tags": ["a", "b"], "config": {"port": 80, "host": "loc`)
+	result, err := (&JSONExplorer{}).Explore(context.Background(), ExploreInput{Path: "out.json", Content: content})
+	require.NoError(t, err)
+	require.Equal(t, "json", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "JSON file (recovered from truncation): out.json\n"+
+		"Size: 71 bytes\n"+
+		"Provenance: recovered from truncation\n"+
+		"Recovered: 71 of 71 bytes (100.0%), 3 bytes repaired\n")
+	require.Regexp(t, degradedBlockPattern, s)
+	require.Contains(t, s, "  Progress: recovered the structure of the first 71 bytes by appending \"\\\"}}\"\n")
+	require.Contains(t, s, "Structure:\nconfig: object (2 keys)\n  host: \"loc\"\n  port: 80 (number)\n")
+	require.NotContains(t, s, "Content (sampled)")
+
+	// Errors before the end of the input are not truncation.
+	result, err = (&JSONExplorer{}).Explore(context.Background(), ExploreInput{Path: "bad.json", Content: []byte(`{"a": ], "b": 1`)})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "JSON file (parse error): bad.json\n")
+	require.NotContains(t, result.Summary, "Provenance")
+}
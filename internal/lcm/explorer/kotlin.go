@@ -0,0 +1,213 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer/stdlib"
+)
+
+// KotlinExplorer explores Kotlin source and script files without
+// tree-sitter: package, imports, classes, interfaces, objects, functions
+// and properties with their visibility. It is a family-tier explorer, so
+// TreeSitterExplorer wins when built in.
+type KotlinExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// kotlinDeclRe matches a declaration line: annotations, modifiers, the
+// introducing keyword and the rest of the line. "fun" is also a modifier,
+// of fun interfaces.
+var kotlinDeclRe = regexp.MustCompile(`^\s*((?:@[\w.:]+(?:\([^)]*\))?\s+)*)((?:(?:public|private|protected|internal|open|final|abstract|sealed|data|enum|annotation|inner|value|inline|companion|override|lateinit|const|suspend|operator|infix|tailrec|external|expect|actual|fun)\s+)*)(class|interface|object|fun|val|var|typealias|constructor)\b\s*`)
+
+var (
+	kotlinPackageRe = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)`)
+	kotlinImportRe  = regexp.MustCompile(`(?m)^\s*import\s+([\w.]+)`)
+	kotlinNameRe    = regexp.MustCompile("^(?:<[^>]*>\\s*)?((?:[\\w<>?, *]+\\.)?`?\\w+`?)")
+	kotlinReturnRe  = regexp.MustCompile(`^\s*:\s*([^{=]+?)\s*(?:\bwhere\b.*)?(?:[{=].*)?$`)
+)
+
+func (e *KotlinExplorer) CanHandle(path string, content []byte) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".kt" || ext == ".kts" {
+		// Gradle build scripts go to BuildManifestExplorer first.
+		return true
+	}
+	return ext == "" && detectShebang(content) == "kotlin"
+}
+
+func (e *KotlinExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Kotlin file too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "kotlin", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	orig := string(input.Content)
+	code, err := blankCodeLiterals(orig, kotlinLiterals)
+	var decls []codeDecl
+	if err == nil {
+		decls, err = scanDecls(code, orig, matchKotlinDecl)
+	}
+	if err != nil {
+		return degradedTextResult("Kotlin file: "+name, "kotlin", input.Content, codeDegradation("kotlin", input.Content, decls, err)), nil
+	}
+	var pkg string
+	if m := kotlinPackageRe.FindStringSubmatch(code); m != nil {
+		pkg = m[1]
+	}
+	return codeExploration{
+		header:      "Kotlin file: " + name,
+		lang:        "kotlin",
+		pkg:         pkg,
+		imports:     kotlinImports(code),
+		decls:       decls,
+		explorer:    "kotlin",
+		returnArrow: ": ",
+	}.write(e.formatterProfile), nil
+}
+
+// kotlinImports returns the imported packages and names of code, without
+// aliases and wildcards.
+func kotlinImports(code string) []codeImport {
+	var out []codeImport
+	seen := map[string]bool{}
+	for _, m := range kotlinImportRe.FindAllStringSubmatch(code, -1) {
+		path := strings.TrimSuffix(m[1], ".")
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		category := "third_party"
+		if stdlib.IsKotlinStdlib(path) {
+			category = "stdlib"
+		}
+		out = append(out, codeImport{path: path, category: category})
+	}
+	return out
+}
+
+// matchKotlinDecl recognizes a Kotlin declaration line.
+func matchKotlinDecl(code, orig string, start, end int) (codeDecl, bool) {
+	line := code[start:end]
+	m := kotlinDeclRe.FindStringSubmatchIndex(line)
+	if m == nil {
+		return codeDecl{}, false
+	}
+	keyword := line[m[6]:m[7]]
+	restAt := start + m[1]
+	rest := code[restAt:end]
+
+	var d codeDecl
+	d.attributes = declAttributes(line[m[2]:m[3]])
+	d.modifiers = strings.Fields(line[m[4]:m[5]])
+	hasModifier := func(want string) bool { return slices.Contains(d.modifiers, want) }
+
+	if keyword == "constructor" {
+		d.name, d.kind = "constructor", "constructor"
+		if i := strings.IndexByte(rest, '('); i >= 0 {
+			d.params, _ = balancedSpan(code, orig, restAt+i, '(', ')')
+		}
+		return d, true
+	}
+	if keyword == "object" && hasModifier("companion") {
+		n := kotlinNameRe.FindString(rest)
+		if n == "" || strings.HasPrefix(strings.TrimSpace(rest), ":") {
+			n = "Companion"
+		}
+		d.name, d.kind, d.isType = n, "object", true
+		return d, true
+	}
+
+	sm := kotlinNameRe.FindStringSubmatchIndex(rest)
+	if sm == nil {
+		// Destructuring declarations and object expressions.
+		return codeDecl{}, false
+	}
+	qualified := rest[sm[2]:sm[3]]
+	// Extension members are named without their receiver type.
+	d.name = strings.Trim(qualified[strings.LastIndexByte(qualified, '.')+1:], "`")
+	afterAt := restAt + sm[1]
+	after := code[afterAt:end]
+
+	switch keyword {
+	case "fun":
+		d.kind = "function"
+		i := strings.IndexByte(after, '(')
+		if i < 0 {
+			return d, true
+		}
+		var next int
+		d.params, next = balancedSpan(code, orig, afterAt+i, '(', ')')
+		tail, _, _ := strings.Cut(orig[next:], "\n")
+		if r := kotlinReturnRe.FindStringSubmatch(tail); r != nil {
+			d.returnType = strings.TrimSpace(r[1])
+		}
+	case "val", "var":
+		d.kind = "variable"
+	case "typealias":
+		d.kind = "typealias"
+	default:
+		d.isType = true
+		switch {
+		case keyword == "interface":
+			d.kind = "interface"
+		case keyword == "object":
+			d.kind = "object"
+		case hasModifier("enum"):
+			d.kind = "enum"
+		case hasModifier("annotation"):
+			d.kind = "annotation"
+		default:
+			d.kind = "class"
+		}
+		d.supertypes = kotlinSupertypes(code, orig, afterAt, end)
+	}
+	return d, true
+}
+
+// kotlinSupertypes returns the supertype list of the class header at
+// code[at:end], which starts after the class name, without constructor
+// arguments.
+func kotlinSupertypes(code, orig string, at, end int) []string {
+	rest := code[at:end]
+	if strings.HasPrefix(strings.TrimSpace(rest), "<") {
+		if i := strings.IndexByte(rest, '>'); i >= 0 {
+			at += i + 1
+		}
+	}
+	rest = code[at:end]
+	// Skip the primary constructor, which may span lines and comes before
+	// the supertypes' constructor calls.
+	if i := strings.IndexByte(rest, '('); i >= 0 && !strings.Contains(rest[:i], ":") {
+		_, next := balancedSpan(code, orig, at+i, '(', ')')
+		rest, _, _ = strings.Cut(code[next:], "\n")
+	}
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, ":") {
+		return nil
+	}
+	rest = rest[1:]
+	if i := strings.IndexByte(rest, '{'); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, " where "); i >= 0 {
+		rest = rest[:i]
+	}
+	var out []string
+	for _, s := range splitTopLevel(rest) {
+		// Drop constructor calls and delegation: Base(x) by impl.
+		s, _, _ = strings.Cut(s, " by ")
+		if i := strings.IndexByte(s, '('); i >= 0 {
+			s = s[:i]
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
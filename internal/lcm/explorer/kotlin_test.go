@@ -0,0 +1,151 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testKotlin = `package com.example.users
+
+import kotlinx.coroutines.flow.Flow
+import kotlin.collections.List
+import java.time.Instant
+import io.ktor.client.HttpClient
+import com.example.core.Result as CoreResult
+
+/** Loads users. */
+interface UserRepository {
+    suspend fun find(id: String): User?
+    val size: Int
+}
+
+data class User(val id: String, val name: String) : Comparable<User> {
+    override fun compareTo(other: User): Int = id.compareTo(other.id)
+
+    companion object {
+        const val MAX = 10
+        fun empty() = User("", "")
+    }
+}
+
+sealed class Result<out T> {
+    data class Ok<T>(val value: T) : Result<T>()
+    object Missing : Result<Nothing>()
+}
+
+class RemoteRepository @Inject constructor(
+    private val client: HttpClient,
+) : UserRepository, AutoCloseable by client {
+    private val cache = mutableMapOf<String, User>()
+    override val size: Int get() = cache.size
+
+    override suspend fun find(id: String): User? {
+        val local = "}"
+        fun helper() = 1
+        return cache[id]
+    }
+
+    internal fun clear() { cache.clear() }
+
+    protected open fun refresh() {}
+}
+
+enum class Mode { FAST, SLOW }
+
+fun <T> List<T>.second(): T = this[1]
+
+private const val TIMEOUT = 30
+
+@JvmStatic
+fun main(args: Array<String>) {
+    println('{')
+}
+
+typealias Handler = (String) -> Unit
+fun interface Callback { fun call() }
+`
+
+func TestKotlinExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &KotlinExplorer{}
+	require.True(t, e.CanHandle("src/main/kotlin/App.kt", nil))
+	require.True(t, e.CanHandle("scripts/release.main.kts", nil))
+	require.True(t, e.CanHandle("release", []byte("#!/usr/bin/env kotlin\nprintln(1)\n")))
+	require.False(t, e.CanHandle("App.java", nil))
+}
+
+func TestKotlinExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &KotlinExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "Sample.kt", Content: []byte(testKotlin)})
+	require.NoError(t, err)
+	require.Equal(t, "kotlin", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Kotlin file: Sample.kt\nLanguage: kotlin\nPackage: com.example.users\n"+
+		"Declarations: 23 (19 public, 1 protected, 1 internal, 2 private)\n")
+	require.Contains(t, s, "Imports:\n"+
+		"  - kotlin.collections.List (stdlib)\n"+
+		"  - kotlinx.coroutines.flow.Flow (third_party)\n")
+	require.Contains(t, s, "  - com.example.core.Result (third_party)\n")
+	require.Contains(t, s, "  - object User.Companion (public, line 18)\n  - property User.Companion.MAX (public, line 19)\n")
+	require.Contains(t, s, "  - class Result.Ok (public, line 25)\n  - object Result.Missing (public, line 26)\n")
+	// Primary constructor parameters are not members.
+	require.Contains(t, s, "  - class RemoteRepository (public, line 29)\n  - property RemoteRepository.cache (private, line 32)\n")
+	require.Contains(t, s, "  - method RemoteRepository.clear (internal, line 41)\n")
+	require.Contains(t, s, "  - method RemoteRepository.refresh (protected, line 43)\n")
+	require.Contains(t, s, "  - enum Mode (public, line 46)\n")
+	require.Contains(t, s, "  - function second (public, line 48)\n")
+	require.Contains(t, s, "  - variable TIMEOUT (private, line 50)\n")
+	require.True(t, strings.HasSuffix(s, "  - interface Callback (public, line 58)"))
+	require.NotContains(t, s, "helper")
+	require.NotContains(t, s, "RemoteRepository.client")
+}
+
+func TestKotlinExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &KotlinExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "Sample.kt", Content: []byte(testKotlin)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "  - method RemoteRepository.find (public, override, suspend, line 35): (id: String): User?\n")
+	require.Contains(t, s, "  - class User (public, data, line 15)\n")
+	require.Contains(t, s, "  - function main (public, @JvmStatic, line 53): (args: Array<String>)\n")
+	require.Contains(t, s, "Inheritance:\n"+
+		"  - User: Comparable<User>\n"+
+		"  - Result.Ok: Result<T>\n"+
+		"  - Result.Missing: Result<Nothing>\n"+
+		"  - RemoteRepository: UserRepository, AutoCloseable")
+}
+
+func TestKotlinExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&KotlinExplorer{}).Explore(context.Background(), ExploreInput{
+		Path:    "A.kt",
+		Content: []byte("class A {\n    fun f() {\n        println('}')\n}\n"),
+	})
+	require.NoError(t, err)
+	require.Regexp(t, degradedBlockPattern, result.Summary)
+	require.Contains(t, result.Summary, "Failed: kotlin scanning at line 1, column 9: unclosed brace\n")
+	require.Contains(t, result.Summary, "Progress: found 2 declarations before the error\n")
+}
+
+func TestKotlinExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "Sample.kt", Content: []byte(testKotlin)})
+		require.NoError(t, err)
+		require.Equal(t, "kotlin", result.ExplorerUsed)
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Inheritance"))
+	}
+}
@@ -0,0 +1,565 @@
+package explorer
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KubernetesExplorer explores Kubernetes manifests: YAML files, possibly
+// holding several documents, whose documents declare apiVersion and kind.
+// It summarizes workloads, images, replicas, ports, referenced ConfigMaps
+// and Secrets, and namespaces instead of dumping the YAML structure.
+type KubernetesExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// kubernetesMaxDetails caps the lines listed per section.
+const kubernetesMaxDetails = 200
+
+// kubernetesKindPattern and kubernetesAPIVersionPattern detect top-level
+// manifest keys without decoding the file.
+var (
+	kubernetesKindPattern       = regexp.MustCompile(`(?m)^kind:[ \t]*\S`)
+	kubernetesAPIVersionPattern = regexp.MustCompile(`(?m)^apiVersion:[ \t]*\S`)
+)
+
+// kubernetesPodSpecPaths locates the pod spec of each workload kind.
+var kubernetesPodSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+type kubernetesResource struct {
+	apiVersion string
+	kind       string
+	name       string
+	namespace  string
+	line       int
+	doc        map[string]any
+}
+
+// id identifies the resource as "Kind namespace/name".
+func (r *kubernetesResource) id() string {
+	if r.namespace == "" {
+		return r.kind + " " + r.name
+	}
+	return r.kind + " " + r.namespace + "/" + r.name
+}
+
+// podSpec returns the pod spec of a workload, or nil for other kinds.
+func (r *kubernetesResource) podSpec() map[string]any {
+	path, ok := kubernetesPodSpecPaths[r.kind]
+	if !ok {
+		return nil
+	}
+	return yamlMapAt(r.doc, path...)
+}
+
+// kubernetesRef is a ConfigMap or Secret referenced by a workload.
+type kubernetesRef struct {
+	kind      string // ConfigMap or Secret
+	name      string
+	namespace string
+}
+
+func (e *KubernetesExplorer) CanHandle(path string, content []byte) bool {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if ext != "yaml" && ext != "yml" {
+		return false
+	}
+	return kubernetesKindPattern.Match(content) && kubernetesAPIVersionPattern.Match(content)
+}
+
+func (e *KubernetesExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Kubernetes manifest too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "kubernetes", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	resources, docs, others, err := decodeKubernetesManifest(input.Content)
+	if err != nil {
+		return degradedTextResult("Kubernetes manifest: "+name, "kubernetes", input.Content, yamlDegradation(input.Content, err)), nil
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Kubernetes manifest: %s\n", name)
+	fmt.Fprintf(&summary, "Documents: %d (%d resources", docs, len(resources))
+	if others > 0 {
+		fmt.Fprintf(&summary, ", %d non-Kubernetes", others)
+	}
+	summary.WriteString(")\n")
+
+	kindCounts := make(map[string]int)
+	namespaces := make(map[string]bool)
+	unscoped := 0
+	for _, r := range resources {
+		kindCounts[r.kind]++
+		switch {
+		case r.kind == "Namespace":
+			namespaces[r.name] = true
+		case r.namespace != "":
+			namespaces[r.namespace] = true
+		default:
+			unscoped++
+		}
+	}
+	kinds := sortedKeys(kindCounts)
+	kindParts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		kindParts = append(kindParts, fmt.Sprintf("%s %d", kind, kindCounts[kind]))
+	}
+	if len(kindParts) > 0 {
+		fmt.Fprintf(&summary, "Kinds: %s\n", strings.Join(kindParts, ", "))
+	}
+	if len(namespaces) > 0 || unscoped > 0 {
+		ns := sortedKeys(namespaces)
+		if unscoped > 0 {
+			ns = append(ns, fmt.Sprintf("(unset: %d resources)", unscoped))
+		}
+		fmt.Fprintf(&summary, "Namespaces: %s\n", strings.Join(ns, ", "))
+	}
+
+	var lines []string
+	for _, r := range resources {
+		lines = append(lines, fmt.Sprintf("%s (%s, line %d)", r.id(), r.apiVersion, r.line))
+	}
+	writeKubernetesSection(&summary, "Resources", lines)
+
+	writeKubernetesWorkloads(&summary, resources)
+	writeKubernetesImages(&summary, resources)
+	writeKubernetesPorts(&summary, resources)
+	refs, users := kubernetesReferences(resources)
+	writeKubernetesReferences(&summary, refs, users)
+
+	// EXCEED MODE: container resources, service-to-workload selection,
+	// ingress routes and references to objects defined elsewhere.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeKubernetesResourceLimits(&summary, resources)
+		writeKubernetesSelectors(&summary, resources)
+		writeKubernetesIngressRoutes(&summary, resources)
+		writeKubernetesUnresolved(&summary, resources, refs)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "kubernetes",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// decodeKubernetesManifest decodes every YAML document and returns the
+// resources they declare, expanding List kinds. docs counts non-empty
+// documents and others those that are not Kubernetes objects.
+func decodeKubernetesManifest(content []byte) (resources []*kubernetesResource, docs, others int, err error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				return resources, docs, others, nil
+			}
+			return nil, 0, 0, err
+		}
+		if len(node.Content) == 0 || node.Content[0].Kind == yaml.ScalarNode && node.Content[0].Tag == "!!null" {
+			continue
+		}
+		docs++
+		var doc map[string]any
+		if node.Decode(&doc) != nil {
+			others++
+			continue
+		}
+		r := newKubernetesResource(doc, node.Content[0].Line)
+		if r == nil {
+			others++
+			continue
+		}
+		if strings.HasSuffix(r.kind, "List") {
+			for _, item := range yamlList(doc, "items") {
+				if m, ok := item.(map[string]any); ok {
+					if ir := newKubernetesResource(m, r.line); ir != nil {
+						resources = append(resources, ir)
+					}
+				}
+			}
+			continue
+		}
+		resources = append(resources, r)
+	}
+}
+
+func newKubernetesResource(doc map[string]any, line int) *kubernetesResource {
+	r := &kubernetesResource{
+		apiVersion: yamlString(doc, "apiVersion"),
+		kind:       yamlString(doc, "kind"),
+		name:       yamlString(doc, "metadata", "name"),
+		namespace:  yamlString(doc, "metadata", "namespace"),
+		line:       line,
+		doc:        doc,
+	}
+	if r.apiVersion == "" || r.kind == "" {
+		return nil
+	}
+	if r.name == "" {
+		r.name = cmp.Or(yamlString(doc, "metadata", "generateName"), "(unnamed)")
+	}
+	return r
+}
+
+// kubernetesContainers returns the init and regular containers of a pod
+// spec, init containers first.
+func kubernetesContainers(spec map[string]any) []map[string]any {
+	var out []map[string]any
+	for _, key := range []string{"initContainers", "containers"} {
+		for _, c := range yamlList(spec, key) {
+			if m, ok := c.(map[string]any); ok {
+				out = append(out, m)
+			}
+		}
+	}
+	return out
+}
+
+func writeKubernetesWorkloads(summary *strings.Builder, resources []*kubernetesResource) {
+	var lines []string
+	for _, r := range resources {
+		spec := r.podSpec()
+		if spec == nil {
+			continue
+		}
+		var parts []string
+		switch r.kind {
+		case "Deployment", "StatefulSet", "ReplicaSet", "ReplicationController":
+			if replicas, ok := yamlAt(r.doc, "spec", "replicas"); ok {
+				parts = append(parts, fmt.Sprintf("replicas %v", replicas))
+			} else {
+				parts = append(parts, "replicas 1 (default)")
+			}
+		case "DaemonSet":
+			parts = append(parts, "one per node")
+		case "CronJob":
+			parts = append(parts, fmt.Sprintf("schedule %q", yamlString(r.doc, "spec", "schedule")))
+		}
+		var containers, inits []string
+		for _, c := range yamlList(spec, "initContainers") {
+			if m, ok := c.(map[string]any); ok {
+				inits = append(inits, yamlString(m, "name"))
+			}
+		}
+		for _, c := range yamlList(spec, "containers") {
+			if m, ok := c.(map[string]any); ok {
+				containers = append(containers, yamlString(m, "name"))
+			}
+		}
+		if len(containers) > 0 {
+			parts = append(parts, "containers "+strings.Join(containers, ", "))
+		}
+		if len(inits) > 0 {
+			parts = append(parts, "init "+strings.Join(inits, ", "))
+		}
+		if sa := yamlString(spec, "serviceAccountName"); sa != "" {
+			parts = append(parts, "service account "+sa)
+		}
+		lines = append(lines, r.id()+": "+strings.Join(parts, "; "))
+	}
+	writeKubernetesSection(summary, "Workloads", lines)
+}
+
+func writeKubernetesImages(summary *strings.Builder, resources []*kubernetesResource) {
+	var lines []string
+	for _, r := range resources {
+		for _, c := range kubernetesContainers(r.podSpec()) {
+			if image := yamlString(c, "image"); image != "" {
+				lines = append(lines, fmt.Sprintf("%s (%s, container %s)", image, r.id(), yamlString(c, "name")))
+			}
+		}
+	}
+	writeKubernetesSection(summary, "Images", lines)
+}
+
+func writeKubernetesPorts(summary *strings.Builder, resources []*kubernetesResource) {
+	var lines []string
+	for _, r := range resources {
+		for _, c := range kubernetesContainers(r.podSpec()) {
+			for _, p := range yamlList(c, "ports") {
+				m, ok := p.(map[string]any)
+				if !ok {
+					continue
+				}
+				line := fmt.Sprintf("%s %s: %v/%s", r.id(), yamlString(c, "name"), m["containerPort"], cmp.Or(yamlString(m, "protocol"), "TCP"))
+				if name := yamlString(m, "name"); name != "" {
+					line += " (" + name + ")"
+				}
+				lines = append(lines, line)
+			}
+		}
+		if r.kind != "Service" {
+			continue
+		}
+		typ := cmp.Or(yamlString(r.doc, "spec", "type"), "ClusterIP")
+		for _, p := range yamlList(r.doc, "spec", "ports") {
+			m, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			target := m["targetPort"]
+			if target == nil {
+				target = m["port"]
+			}
+			line := fmt.Sprintf("%s (%s): %v -> %v/%s", r.id(), typ, m["port"], target, cmp.Or(yamlString(m, "protocol"), "TCP"))
+			if nodePort, ok := m["nodePort"]; ok {
+				line += fmt.Sprintf(", node port %v", nodePort)
+			}
+			lines = append(lines, line)
+		}
+	}
+	writeKubernetesSection(summary, "Ports", lines)
+}
+
+// kubernetesReferences maps each ConfigMap and Secret referenced by a
+// workload to the workloads referencing it, in first-seen order.
+func kubernetesReferences(resources []*kubernetesResource) ([]kubernetesRef, map[kubernetesRef][]string) {
+	var order []kubernetesRef
+	users := make(map[kubernetesRef][]string)
+	add := func(r *kubernetesResource, kind, name string) {
+		if name == "" {
+			return
+		}
+		ref := kubernetesRef{kind: kind, name: name, namespace: r.namespace}
+		if _, ok := users[ref]; !ok {
+			order = append(order, ref)
+		}
+		if !slices.Contains(users[ref], r.id()) {
+			users[ref] = append(users[ref], r.id())
+		}
+	}
+	for _, r := range resources {
+		spec := r.podSpec()
+		if spec == nil {
+			continue
+		}
+		for _, c := range kubernetesContainers(spec) {
+			for _, from := range yamlList(c, "envFrom") {
+				if m, ok := from.(map[string]any); ok {
+					add(r, "ConfigMap", yamlString(m, "configMapRef", "name"))
+					add(r, "Secret", yamlString(m, "secretRef", "name"))
+				}
+			}
+			for _, env := range yamlList(c, "env") {
+				if m, ok := env.(map[string]any); ok {
+					add(r, "ConfigMap", yamlString(m, "valueFrom", "configMapKeyRef", "name"))
+					add(r, "Secret", yamlString(m, "valueFrom", "secretKeyRef", "name"))
+				}
+			}
+		}
+		for _, v := range yamlList(spec, "volumes") {
+			m, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			add(r, "ConfigMap", yamlString(m, "configMap", "name"))
+			add(r, "Secret", yamlString(m, "secret", "secretName"))
+			for _, src := range yamlList(m, "projected", "sources") {
+				if s, ok := src.(map[string]any); ok {
+					add(r, "ConfigMap", yamlString(s, "configMap", "name"))
+					add(r, "Secret", yamlString(s, "secret", "name"))
+				}
+			}
+		}
+		for _, s := range yamlList(spec, "imagePullSecrets") {
+			if m, ok := s.(map[string]any); ok {
+				add(r, "Secret", yamlString(m, "name"))
+			}
+		}
+	}
+	return order, users
+}
+
+func writeKubernetesReferences(summary *strings.Builder, refs []kubernetesRef, users map[kubernetesRef][]string) {
+	var lines []string
+	for _, ref := range refs {
+		lines = append(lines, fmt.Sprintf("%s %s (used by %s)", ref.kind, ref.name, strings.Join(users[ref], ", ")))
+	}
+	writeKubernetesSection(summary, "Config references", lines)
+}
+
+func writeKubernetesResourceLimits(summary *strings.Builder, resources []*kubernetesResource) {
+	var lines []string
+	for _, r := range resources {
+		for _, c := range kubernetesContainers(r.podSpec()) {
+			var parts []string
+			for _, key := range []string{"requests", "limits"} {
+				values := yamlMapAt(c, "resources", key)
+				if len(values) == 0 {
+					parts = append(parts, "no "+key)
+					continue
+				}
+				var kv []string
+				for _, name := range sortedKeys(values) {
+					kv = append(kv, fmt.Sprintf("%s=%v", name, values[name]))
+				}
+				parts = append(parts, key+" "+strings.Join(kv, " "))
+			}
+			lines = append(lines, fmt.Sprintf("%s %s: %s", r.id(), yamlString(c, "name"), strings.Join(parts, "; ")))
+		}
+	}
+	writeKubernetesSection(summary, "Resource limits", lines)
+}
+
+func writeKubernetesSelectors(summary *strings.Builder, resources []*kubernetesResource) {
+	var lines []string
+	for _, svc := range resources {
+		if svc.kind != "Service" {
+			continue
+		}
+		selector := yamlMapAt(svc.doc, "spec", "selector")
+		if len(selector) == 0 {
+			continue
+		}
+		var matched []string
+		for _, r := range resources {
+			if r.podSpec() == nil || r.namespace != svc.namespace {
+				continue
+			}
+			labels := yamlMapAt(r.doc, "metadata", "labels")
+			if r.kind != "Pod" {
+				labels = yamlMapAt(r.doc, kubernetesTemplatePath(r.kind)...)
+			}
+			if kubernetesSelects(selector, labels) {
+				matched = append(matched, r.id())
+			}
+		}
+		if len(matched) == 0 {
+			matched = []string{"(no workload in this file)"}
+		}
+		lines = append(lines, svc.id()+" -> "+strings.Join(matched, ", "))
+	}
+	writeKubernetesSection(summary, "Service selectors", lines)
+}
+
+// kubernetesTemplatePath returns the path to a workload's pod template
+// labels.
+func kubernetesTemplatePath(kind string) []string {
+	path := slices.Clone(kubernetesPodSpecPaths[kind])
+	return append(path[:len(path)-1], "metadata", "labels")
+}
+
+func kubernetesSelects(selector, labels map[string]any) bool {
+	for k, v := range selector {
+		if fmt.Sprint(labels[k]) != fmt.Sprint(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeKubernetesIngressRoutes(summary *strings.Builder, resources []*kubernetesResource) {
+	var lines []string
+	for _, r := range resources {
+		if r.kind != "Ingress" {
+			continue
+		}
+		for _, rule := range yamlList(r.doc, "spec", "rules") {
+			m, ok := rule.(map[string]any)
+			if !ok {
+				continue
+			}
+			host := cmp.Or(yamlString(m, "host"), "*")
+			for _, p := range yamlList(m, "http", "paths") {
+				pm, ok := p.(map[string]any)
+				if !ok {
+					continue
+				}
+				backend := yamlString(pm, "backend", "service", "name")
+				port, _ := yamlAt(pm, "backend", "service", "port", "number")
+				if port == nil {
+					port, _ = yamlAt(pm, "backend", "service", "port", "name")
+				}
+				lines = append(lines, fmt.Sprintf("%s: %s%s -> %s:%v", r.id(), host, cmp.Or(yamlString(pm, "path"), "/"), backend, port))
+			}
+		}
+	}
+	writeKubernetesSection(summary, "Ingress routes", lines)
+}
+
+func writeKubernetesUnresolved(summary *strings.Builder, resources []*kubernetesResource, refs []kubernetesRef) {
+	defined := make(map[kubernetesRef]bool)
+	for _, r := range resources {
+		if r.kind == "ConfigMap" || r.kind == "Secret" {
+			defined[kubernetesRef{kind: r.kind, name: r.name, namespace: r.namespace}] = true
+		}
+	}
+	var lines []string
+	for _, ref := range refs {
+		if !defined[ref] {
+			lines = append(lines, ref.kind+" "+ref.name)
+		}
+	}
+	writeKubernetesSection(summary, "Unresolved references", lines)
+}
+
+func writeKubernetesSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), kubernetesMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+}
+
+// yamlAt returns the value at the key path of a decoded YAML mapping.
+func yamlAt(m map[string]any, path ...string) (any, bool) {
+	var cur any = m
+	for _, key := range path {
+		next, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = next[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func yamlMapAt(m map[string]any, path ...string) map[string]any {
+	v, _ := yamlAt(m, path...)
+	out, _ := v.(map[string]any)
+	return out
+}
+
+func yamlList(m map[string]any, path ...string) []any {
+	v, _ := yamlAt(m, path...)
+	out, _ := v.([]any)
+	return out
+}
+
+func yamlString(m map[string]any, path ...string) string {
+	v, ok := yamlAt(m, path...)
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,235 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testKubernetesManifest = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      imagePullSecrets:
+        - name: registry
+      initContainers:
+        - name: migrate
+          image: shop/migrate:1.2
+      containers:
+        - name: app
+          image: shop/web:1.2
+          ports:
+            - containerPort: 8080
+              name: http
+          envFrom:
+            - configMapRef:
+                name: web-config
+          env:
+            - name: DB_PASSWORD
+              valueFrom:
+                secretKeyRef:
+                  name: db
+                  key: password
+          resources:
+            requests:
+              cpu: 100m
+              memory: 128Mi
+            limits:
+              memory: 256Mi
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: shop
+spec:
+  selector:
+    app: web
+  ports:
+    - port: 80
+      targetPort: http
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: shop
+data:
+  LOG_LEVEL: info
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+  namespace: shop
+spec:
+  schedule: "0 3 * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              image: shop/tools:latest
+          volumes:
+            - name: config
+              configMap:
+                name: web-config
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  namespace: shop
+spec:
+  rules:
+    - host: shop.example.com
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: web
+                port:
+                  number: 80
+`
+
+func TestKubernetesExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &KubernetesExplorer{}
+	require.True(t, e.CanHandle("deploy.yaml", []byte(testKubernetesManifest)))
+	require.True(t, e.CanHandle("pod.YML", []byte("apiVersion: v1\nkind: Pod\n")))
+	require.False(t, e.CanHandle("config.yaml", []byte("name: app\nversion: 2\n")))
+	require.False(t, e.CanHandle("values.yaml", []byte("image:\n  kind: web\n  apiVersion: v1\n")))
+	require.False(t, e.CanHandle("pod.json", []byte(`{"apiVersion":"v1","kind":"Pod"}`)))
+}
+
+func TestKubernetesExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &KubernetesExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "deploy.yaml", Content: []byte(testKubernetesManifest)})
+	require.NoError(t, err)
+	require.Equal(t, "kubernetes", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Kubernetes manifest: deploy.yaml\n")
+	require.Contains(t, s, "Documents: 6 (6 resources)\n")
+	require.Contains(t, s, "Kinds: ConfigMap 1, CronJob 1, Deployment 1, Ingress 1, Namespace 1, Service 1\n")
+	require.Contains(t, s, "Namespaces: shop\n")
+	require.Contains(t, s, "Resources:\n"+
+		"  - Namespace shop (v1, line 1)\n"+
+		"  - Deployment shop/web (apps/v1, line 6)\n")
+	require.Contains(t, s, "Workloads:\n"+
+		"  - Deployment shop/web: replicas 3; containers app; init migrate\n"+
+		"  - CronJob shop/cleanup: schedule \"0 3 * * *\"; containers cleanup\n")
+	require.Contains(t, s, "Images:\n"+
+		"  - shop/migrate:1.2 (Deployment shop/web, container migrate)\n"+
+		"  - shop/web:1.2 (Deployment shop/web, container app)\n"+
+		"  - shop/tools:latest (CronJob shop/cleanup, container cleanup)\n")
+	require.Contains(t, s, "Ports:\n"+
+		"  - Deployment shop/web app: 8080/TCP (http)\n"+
+		"  - Service shop/web (ClusterIP): 80 -> http/TCP\n")
+	require.Contains(t, s, "Config references:\n"+
+		"  - ConfigMap web-config (used by Deployment shop/web, CronJob shop/cleanup)\n"+
+		"  - Secret db (used by Deployment shop/web)\n"+
+		"  - Secret registry (used by Deployment shop/web)\n")
+
+	// Container resources and ingress rules are not described, only the
+	// objects themselves.
+	require.NotContains(t, s, "cpu=100m")
+	require.NotContains(t, s, "shop.example.com")
+}
+
+func TestKubernetesExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &KubernetesExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "deploy.yaml", Content: []byte(testKubernetesManifest)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	// Every container listed under Images gets a resources entry, init
+	// containers first.
+	require.Contains(t, s, "Resource limits:\n"+
+		"  - Deployment shop/web migrate: no requests; no limits\n"+
+		"  - Deployment shop/web app: requests cpu=100m memory=128Mi; limits memory=256Mi\n"+
+		"  - CronJob shop/cleanup cleanup: no requests; no limits\n")
+	require.Contains(t, s, "Service selectors:\n  - Service shop/web -> Deployment shop/web\n")
+	require.Contains(t, s, "Ingress routes:\n  - Ingress shop/web: shop.example.com/ -> web:80\n")
+	// The ConfigMap is defined in the same file; the secrets are not.
+	require.Contains(t, s, "Unresolved references:\n  - Secret db\n  - Secret registry\n")
+	require.NotContains(t, s, "  - ConfigMap web-config\n")
+}
+
+func TestKubernetesExplorer_Explore_List(t *testing.T) {
+	t.Parallel()
+
+	content := `apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: Pod
+    metadata:
+      name: debug
+    spec:
+      containers:
+        - name: shell
+          image: busybox
+  - apiVersion: v1
+    kind: Secret
+    metadata:
+      name: token
+`
+	result, err := (&KubernetesExplorer{}).Explore(context.Background(), ExploreInput{Path: "list.yaml", Content: []byte(content)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Documents: 1 (2 resources)\n")
+	require.Contains(t, s, "Namespaces: (unset: 2 resources)\n")
+	require.Contains(t, s, "Workloads:\n  - Pod debug: containers shell\n")
+	require.Contains(t, s, "Images:\n  - busybox (Pod debug, container shell)\n")
+}
+
+func TestKubernetesExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	content := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: [broken\n"
+	result, err := (&KubernetesExplorer{}).Explore(context.Background(), ExploreInput{Path: "pod.yaml", Content: []byte(content)})
+	require.NoError(t, err)
+	require.Equal(t, "kubernetes", result.ExplorerUsed)
+	require.Regexp(t, degradedBlockPattern, result.Summary)
+}
+
+func TestKubernetesExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "deploy.yaml", Content: []byte(testKubernetesManifest)})
+		require.NoError(t, err)
+		require.Equal(t, "kubernetes", result.ExplorerUsed)
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Unresolved references"))
+	}
+
+	result, err := NewRegistry().Explore(context.Background(), ExploreInput{Path: "config.yaml", Content: []byte("name: app\n")})
+	require.NoError(t, err)
+	require.Equal(t, "yaml", result.ExplorerUsed)
+}
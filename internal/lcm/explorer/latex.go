@@ -18,6 +18,8 @@ type LatexExplorer struct {
 type LatexSection struct {
 	Level int
 	Title string
+	// Line is the 1-based line of the sectioning command.
+	Line int
 }
 
 // LatexEnv represents a LaTeX environment found in the document.
@@ -58,6 +60,7 @@ func (e *LatexExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 
 	content := string(input.Content)
 	var summary strings.Builder
+	facts := &Facts{}
 	fmt.Fprintf(&summary, "LaTeX file: %s\n", filepath.Base(input.Path))
 
 	// Extract sections
@@ -69,9 +72,24 @@ func (e *LatexExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 	// Extract bibliography metadata
 	biblio := extractLatexBibliography(content)
 
+	sectionCounts := countSectionsByLevel(sections)
+	for level, command := range latexSectionCommands {
+		facts.count(command, sectionCounts[level+1])
+	}
+	for _, sec := range sections {
+		facts.Sections = append(facts.Sections, CodeSection{
+			Name:      sec.Title,
+			Type:      latexSectionCommands[sec.Level-1],
+			StartLine: sec.Line,
+		})
+	}
+	for _, env := range envs {
+		facts.count("env_"+strings.ToLower(env.Name), env.Count)
+	}
+	facts.count("citations", biblio.CiteCount)
+
 	// Section structure
 	if len(sections) > 0 {
-		sectionCounts := countSectionsByLevel(sections)
 		summary.WriteString("\nSection structure:\n")
 		if sectionCounts[1] > 0 {
 			fmt.Fprintf(&summary, "  - \\section: %d\n", sectionCounts[1])
@@ -123,6 +141,7 @@ func (e *LatexExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 
 	// Packages
 	pkgs := extractLatexPackages(content)
+	facts.Imports = pkgs
 	if len(pkgs) > 0 {
 		summary.WriteString("\nPackages:\n")
 		for _, pkg := range pkgs[:min(20, len(pkgs))] {
@@ -199,9 +218,13 @@ func (e *LatexExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 		Summary:       result,
 		ExplorerUsed:  "latex",
 		TokenEstimate: estimateTokens(result),
+		Facts:         facts,
 	}, nil
 }
 
+// latexSectionCommands are the sectioning commands by level, from 1.
+var latexSectionCommands = []string{"section", "subsection", "subsubsection", "paragraph", "subparagraph"}
+
 // extractLatexSections extracts all sections from LaTeX content.
 func extractLatexSections(content string) []LatexSection {
 	// Match all section commands with their titles in document order
@@ -210,15 +233,14 @@ func extractLatexSections(content string) []LatexSection {
 	re := regexp.MustCompile(`\\(section|subsection|subsubsection|paragraph|subparagraph)\*?\s*\{([^}]*)\}`)
 
 	var sections []LatexSection
-	matches := re.FindAllStringSubmatch(content, -1)
+	matches := re.FindAllStringSubmatchIndex(content, -1)
 
-	for _, match := range matches {
-		if len(match) != 3 {
-			continue
-		}
-
-		command := match[1]
-		title := strings.TrimSpace(match[2])
+	line, counted := 1, 0
+	for _, m := range matches {
+		line += strings.Count(content[counted:m[0]], "\n")
+		counted = m[0]
+		command := content[m[2]:m[3]]
+		title := strings.TrimSpace(content[m[4]:m[5]])
 
 		// Determine level from command type
 		level := 1
@@ -238,6 +260,7 @@ func extractLatexSections(content string) []LatexSection {
 		sections = append(sections, LatexSection{
 			Level: level,
 			Title: title,
+			Line:  line,
 		})
 	}
 
@@ -266,15 +289,12 @@ func extractLatexEnvironments(content string) []LatexEnv {
 		envCount[envName]++
 	}
 
-	// Convert to sorted list
+	// Convert to a list sorted by count (descending) then by name
 	var envs []LatexEnv
-	for name, count := range envCount {
-		envs = append(envs, LatexEnv{Name: name, Count: count})
+	for _, e := range byCount(envCount) {
+		envs = append(envs, LatexEnv{Name: e.Key, Count: e.Count})
 	}
 
-	// Sort by count (descending) then by name
-	sortEnvs(envs)
-
 	// Filter out environments that are typically not of interest
 	filteredEnvs := make([]LatexEnv, 0, len(envs))
 	for _, env := range envs {
@@ -294,19 +314,6 @@ func extractLatexEnvironments(content string) []LatexEnv {
 	return filteredEnvs
 }
 
-// sortEnvs sorts environments by count (descending), then by name.
-func sortEnvs(envs []LatexEnv) {
-	// Simple insertion sort for small slices
-	for i := 1; i < len(envs); i++ {
-		for j := i; j > 0; j-- {
-			if envs[j].Count > envs[j-1].Count ||
-				(envs[j].Count == envs[j-1].Count && envs[j].Name < envs[j-1].Name) {
-				envs[j], envs[j-1] = envs[j-1], envs[j]
-			}
-		}
-	}
-}
-
 // extractLatexBibliography extracts bibliography-related metadata.
 func extractLatexBibliography(content string) LatexBiblio {
 	result := LatexBiblio{}
@@ -0,0 +1,202 @@
+package explorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Reasons recorded in ExplorerSkip.
+const (
+	SkipTimeout     = "timeout"
+	SkipInputLimit  = "input_limit"
+	SkipCircuitOpen = "circuit_open"
+	SkipError       = "error"
+)
+
+// ExplorerSkip records an explorer that matched a file but did not produce
+// the result, so the next explorer in the chain was tried.
+type ExplorerSkip struct {
+	// Explorer is the canonical explorer name, e.g. "JSONExplorer".
+	Explorer string
+	// Reason is one of SkipTimeout, SkipInputLimit, SkipCircuitOpen or
+	// SkipError.
+	Reason string
+	// Detail describes the limit hit or the error.
+	Detail string
+}
+
+// ExplorerLimits bounds a single explorer call. Zero fields are unlimited.
+type ExplorerLimits struct {
+	// Timeout is the deadline of one Explore call. An explorer that
+	// ignores its context keeps running in the background, but the
+	// registry moves on.
+	Timeout time.Duration
+	// MaxInputBytes is a memory hint: inputs larger than this skip the
+	// explorer, as if it had not matched.
+	MaxInputBytes int64
+}
+
+// WithExplorerLimits bounds every explorer by defaults, or by the entry of
+// overrides keyed by canonical explorer name (see RegisterBefore). The
+// final FallbackExplorer is never limited, so Explore always answers.
+func WithExplorerLimits(defaults ExplorerLimits, overrides map[string]ExplorerLimits) RegistryOption {
+	return func(r *Registry) {
+		r.limits = &explorerLimits{defaults: defaults, overrides: overrides}
+	}
+}
+
+// WithCircuitBreaker skips an explorer for cooldown once it has timed out
+// failures times in a row. After the cooldown one call is let through; a
+// further timeout reopens the circuit. Only timeouts count, since an error
+// is usually about the file rather than the explorer.
+func WithCircuitBreaker(failures int, cooldown time.Duration) RegistryOption {
+	return func(r *Registry) {
+		if failures > 0 {
+			r.breaker = &circuitBreaker{
+				threshold: failures,
+				cooldown:  cooldown,
+				state:     make(map[string]*circuitState),
+				now:       time.Now,
+			}
+		}
+	}
+}
+
+type explorerLimits struct {
+	defaults  ExplorerLimits
+	overrides map[string]ExplorerLimits
+}
+
+func (l *explorerLimits) forExplorer(name string) ExplorerLimits {
+	if l == nil {
+		return ExplorerLimits{}
+	}
+	if o, ok := l.overrides[name]; ok {
+		return o
+	}
+	return l.defaults
+}
+
+type circuitState struct {
+	failures  int
+	openUntil time.Time
+}
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	state map[string]*circuitState
+}
+
+// open reports whether the circuit of name is open.
+func (b *circuitBreaker) open(name string) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.state[name]
+	return ok && b.now().Before(s.openUntil)
+}
+
+// done records the outcome of a call to name.
+func (b *circuitBreaker) done(name string, timedOut bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !timedOut {
+		delete(b.state, name)
+		return
+	}
+	s, ok := b.state[name]
+	if !ok {
+		s = &circuitState{}
+		b.state[name] = s
+	}
+	s.failures++
+	if s.failures >= b.threshold {
+		s.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// runExplorer runs e under its limits. A non-nil skip means the result
+// must not be used and the next explorer should be tried.
+func (r *Registry) runExplorer(ctx context.Context, e Explorer, input ExploreInput) (ExploreResult, *ExplorerSkip) {
+	if _, final := e.(*FallbackExplorer); final || (r.limits == nil && r.breaker == nil) {
+		result, err := e.Explore(ctx, input)
+		if err != nil {
+			return result, &ExplorerSkip{Explorer: explorerIdent(e), Reason: SkipError, Detail: err.Error()}
+		}
+		return result, nil
+	}
+
+	name := explorerIdent(e)
+	limits := r.limits.forExplorer(name)
+	if limits.MaxInputBytes > 0 && int64(len(input.Content)) > limits.MaxInputBytes {
+		return ExploreResult{}, &ExplorerSkip{
+			Explorer: name,
+			Reason:   SkipInputLimit,
+			Detail:   fmt.Sprintf("%d bytes exceeds %d", len(input.Content), limits.MaxInputBytes),
+		}
+	}
+	if r.breaker.open(name) {
+		return ExploreResult{}, &ExplorerSkip{Explorer: name, Reason: SkipCircuitOpen}
+	}
+
+	result, err := exploreWithTimeout(ctx, e, input, limits.Timeout)
+	// A canceled or expired caller context is not the explorer's fault.
+	timedOut := errors.Is(err, errExplorerTimeout) && ctx.Err() == nil
+	r.breaker.done(name, timedOut)
+	switch {
+	case timedOut:
+		return ExploreResult{}, &ExplorerSkip{Explorer: name, Reason: SkipTimeout, Detail: limits.Timeout.String()}
+	case err != nil:
+		return result, &ExplorerSkip{Explorer: name, Reason: SkipError, Detail: err.Error()}
+	}
+	return result, nil
+}
+
+var errExplorerTimeout = errors.New("explorer timed out")
+
+// exploreWithTimeout runs e.Explore with a deadline of timeout, when set,
+// and returns errExplorerTimeout once it passes, even if the explorer has
+// not returned yet.
+func exploreWithTimeout(ctx context.Context, e Explorer, input ExploreInput, timeout time.Duration) (ExploreResult, error) {
+	if timeout <= 0 {
+		return e.Explore(ctx, input)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result ExploreResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := e.Explore(ctx, input)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return o.result, errExplorerTimeout
+		}
+		return o.result, o.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return ExploreResult{}, errExplorerTimeout
+		}
+		return ExploreResult{}, ctx.Err()
+	}
+}
@@ -0,0 +1,114 @@
+package explorer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// hangingExplorer claims .slow files and blocks until released, ignoring
+// its context like a hostile parser would.
+type hangingExplorer struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (h *hangingExplorer) CanHandle(path string, _ []byte) bool {
+	return strings.HasSuffix(path, ".slow")
+}
+
+func (h *hangingExplorer) Explore(context.Context, ExploreInput) (ExploreResult, error) {
+	h.calls.Add(1)
+	<-h.release
+	return ExploreResult{Summary: "slow", ExplorerUsed: "slow"}, nil
+}
+
+type failingExplorer struct{}
+
+func (failingExplorer) CanHandle(path string, _ []byte) bool {
+	return strings.HasSuffix(path, ".bad")
+}
+
+func (failingExplorer) Explore(context.Context, ExploreInput) (ExploreResult, error) {
+	return ExploreResult{}, errors.New("corrupt header")
+}
+
+func TestExplorerLimits_TimeoutFallsThrough(t *testing.T) {
+	t.Parallel()
+
+	slow := &hangingExplorer{release: make(chan struct{})}
+	t.Cleanup(func() { close(slow.release) })
+
+	r := NewRegistry(WithExplorerLimits(ExplorerLimits{Timeout: 20 * time.Millisecond}, nil))
+	require.NoError(t, r.RegisterExplorer("SlowExplorer", slow))
+
+	result, err := r.Explore(context.Background(), ExploreInput{Path: "data.slow", Content: []byte("some text\n")})
+	require.NoError(t, err)
+	require.Equal(t, "text", result.ExplorerUsed)
+	require.Equal(t, []ExplorerSkip{{Explorer: "SlowExplorer", Reason: SkipTimeout, Detail: "20ms"}}, result.Skipped)
+}
+
+func TestExplorerLimits_InputLimitAndErrors(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(WithExplorerLimits(ExplorerLimits{}, map[string]ExplorerLimits{
+		"JSONExplorer": {MaxInputBytes: 8},
+	}))
+	require.NoError(t, r.RegisterExplorer("BadExplorer", failingExplorer{}))
+
+	result, err := r.Explore(context.Background(), ExploreInput{Path: "a.json", Content: []byte(`{"key": "value"}`)})
+	require.NoError(t, err)
+	require.NotEqual(t, "json", result.ExplorerUsed)
+	require.Equal(t, SkipInputLimit, result.Skipped[0].Reason)
+	require.Equal(t, "JSONExplorer", result.Skipped[0].Explorer)
+
+	result, err = r.Explore(context.Background(), ExploreInput{Path: "b.json", Content: []byte(`{}`)})
+	require.NoError(t, err)
+	require.Equal(t, "json", result.ExplorerUsed)
+	require.Empty(t, result.Skipped)
+
+	result, err = r.Explore(context.Background(), ExploreInput{Path: "x.bad", Content: []byte("text\n")})
+	require.NoError(t, err)
+	require.Equal(t, []ExplorerSkip{{Explorer: "BadExplorer", Reason: SkipError, Detail: "corrupt header"}}, result.Skipped)
+}
+
+func TestExplorerLimits_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	slow := &hangingExplorer{release: make(chan struct{})}
+	t.Cleanup(func() { close(slow.release) })
+
+	r := NewRegistry(
+		WithExplorerLimits(ExplorerLimits{Timeout: 10 * time.Millisecond}, nil),
+		WithCircuitBreaker(2, time.Minute),
+	)
+	require.NoError(t, r.RegisterExplorer("SlowExplorer", slow))
+	now := time.Now()
+	r.breaker.now = func() time.Time { return now }
+
+	input := ExploreInput{Path: "data.slow", Content: []byte("text\n")}
+	for range 2 {
+		result, err := r.Explore(context.Background(), input)
+		require.NoError(t, err)
+		require.Equal(t, SkipTimeout, result.Skipped[0].Reason)
+	}
+
+	// Open: skipped without being called.
+	result, err := r.Explore(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, SkipCircuitOpen, result.Skipped[0].Reason)
+	require.Equal(t, int32(2), slow.calls.Load())
+
+	// After the cooldown one call is let through and reopens the circuit.
+	now = now.Add(2 * time.Minute)
+	result, err = r.Explore(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, SkipTimeout, result.Skipped[0].Reason)
+	require.Equal(t, int32(3), slow.calls.Load())
+	require.True(t, r.breaker.open("SlowExplorer"))
+}
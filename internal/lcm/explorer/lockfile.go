@@ -0,0 +1,718 @@
+package explorer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LockfileExplorer explores dependency lockfiles (npm package-lock.json and
+// npm-shrinkwrap.json, go.sum, Cargo.lock and poetry.lock): the ecosystem,
+// package counts split into top-level and transitive dependencies, and
+// packages locked at more than one version, instead of the raw text.
+type LockfileExplorer struct {
+	formatterProfile OutputProfile
+}
+
+const (
+	// lockfileMaxDetails caps the lines listed per section.
+	lockfileMaxDetails = 100
+	// lockfileMaxDependents caps the most depended on packages listed in
+	// enhancement output.
+	lockfileMaxDependents = 10
+)
+
+// lockedPackage is one resolved package of a lockfile.
+type lockedPackage struct {
+	name    string
+	version string
+	// source is where the package comes from: registry, git, path or url.
+	source   string
+	dev      bool
+	topLevel bool
+	deps     []string // names of the packages it depends on
+	// license is the SPDX expression the lockfile records, which only npm
+	// lockfiles do.
+	license string
+}
+
+// lockfile is the ecosystem-independent view of one lockfile.
+type lockfile struct {
+	ecosystem string // npm, go, cargo or poetry
+	format    string
+	packages  []lockedPackage
+	// members are the workspace packages the lockfile was written for;
+	// they are not counted as dependencies.
+	members []string
+	// split reports whether the lockfile tells top-level dependencies from
+	// transitive ones; go.sum does not.
+	split bool
+	// graph reports whether packages list their dependencies.
+	graph bool
+	// notes are ecosystem-specific summary lines.
+	notes []string
+}
+
+// lockfileSyntaxError is a parse error on a 1-based line.
+type lockfileSyntaxError struct {
+	line int
+	msg  string
+}
+
+func (e *lockfileSyntaxError) Error() string { return e.msg }
+
+func (e *LockfileExplorer) CanHandle(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Base(path)) {
+	case "package-lock.json", "npm-shrinkwrap.json", "go.sum", "cargo.lock", "poetry.lock":
+		return true
+	}
+	return false
+}
+
+func (e *LockfileExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Lockfile too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "lockfile", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	lf, err := parseLockfile(name, input.Content)
+	if err != nil {
+		var d degradedExploration
+		switch strings.ToLower(name) {
+		case "package-lock.json", "npm-shrinkwrap.json":
+			d = jsonDegradation(input.Path, input.Content, err)
+		case "go.sum":
+			d = lockfileDegradation(input.Content, "go.sum", err, "Run go mod tidy to rewrite go.sum")
+		case "cargo.lock":
+			d = lockfileDegradation(input.Content, "Cargo.lock", err, "Run cargo generate-lockfile to rewrite the lockfile")
+		default:
+			d = lockfileDegradation(input.Content, "poetry.lock", err, "Run poetry lock to rewrite the lockfile")
+		}
+		return degradedTextResult("Lockfile: "+name, "lockfile", input.Content, d), nil
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Lockfile: %s (%s", name, lf.ecosystem)
+	if lf.format != "" {
+		fmt.Fprintf(&summary, ", %s", lf.format)
+	}
+	summary.WriteString(")\n")
+
+	topLevel, transitive := 0, 0
+	for _, p := range lf.packages {
+		if p.topLevel {
+			topLevel++
+		} else {
+			transitive++
+		}
+	}
+	fmt.Fprintf(&summary, "Packages: %d", len(lf.packages))
+	if lf.split {
+		fmt.Fprintf(&summary, " (%d top-level, %d transitive)", topLevel, transitive)
+	}
+	summary.WriteString("\n")
+	if len(lf.members) > 0 {
+		fmt.Fprintf(&summary, "Workspace members: %s\n", strings.Join(lf.members, ", "))
+	}
+	for _, note := range lf.notes {
+		summary.WriteString(note + "\n")
+	}
+	duplicates := lockfileDuplicates(lf.packages)
+	fmt.Fprintf(&summary, "Duplicated packages: %d\n", len(duplicates))
+
+	var lines []string
+	if lf.split {
+		for _, p := range lf.packages {
+			if p.topLevel {
+				lines = append(lines, p.name+" "+p.version)
+			}
+		}
+		writeLockfileSection(&summary, "Top-level dependencies", lines)
+	}
+	writeLockfileSection(&summary, "Duplicate versions", duplicates)
+
+	// EXCEED MODE: where packages come from, the ones outside the
+	// registry, dev-only packages, recorded licenses and the most depended
+	// on packages.
+	if e.formatterProfile == OutputProfileEnhancement {
+		sources := make(map[string]int)
+		licenses := make(map[string]int)
+		dev := 0
+		lines = nil
+		for _, p := range lf.packages {
+			sources[p.source]++
+			if p.license != "" {
+				licenses[p.license]++
+			}
+			if p.dev {
+				dev++
+			}
+			if p.source != "registry" {
+				lines = append(lines, fmt.Sprintf("%s %s (%s)", p.name, p.version, p.source))
+			}
+		}
+		if len(sources) > 1 || len(lines) > 0 {
+			summary.WriteString("\nSources:\n")
+			writeCounts(&summary, sources, " packages")
+		}
+		writeLockfileSection(&summary, "Packages outside the registry", lines)
+		if dev > 0 {
+			fmt.Fprintf(&summary, "\nDev-only packages: %d of %d\n", dev, len(lf.packages))
+		}
+		if len(licenses) > 0 {
+			summary.WriteString("\nLicenses:\n")
+			writeCounts(&summary, licenses, " packages")
+		}
+		if lf.graph {
+			writeLockfileSection(&summary, "Most depended on", lockfileDependents(lf.packages))
+		}
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "lockfile",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// parseLockfile parses the lockfile named name by its format.
+func parseLockfile(name string, content []byte) (*lockfile, error) {
+	switch strings.ToLower(name) {
+	case "package-lock.json", "npm-shrinkwrap.json":
+		return parseNPMLock(content)
+	case "go.sum":
+		return parseGoSum(content)
+	case "cargo.lock":
+		return parseTOMLLock(content, "cargo")
+	default:
+		return parseTOMLLock(content, "poetry")
+	}
+}
+
+// LockfilePackage is a package resolved by a dependency lockfile.
+type LockfilePackage struct {
+	Name    string
+	Version string
+	// Source is where the package comes from: registry, git, path or url.
+	Source string
+	// License is the SPDX expression recorded in the lockfile, empty for
+	// the formats that do not record one.
+	License  string
+	Dev      bool
+	TopLevel bool
+}
+
+// IsLockfile reports whether the file at path is a lockfile
+// ParseLockfile reads.
+func IsLockfile(path string) bool {
+	return (&LockfileExplorer{}).CanHandle(path, nil)
+}
+
+// ParseLockfile returns the ecosystem (npm, go, cargo or poetry) and the
+// packages of the lockfile at path, as the LockfileExplorer reads them.
+func ParseLockfile(path string, content []byte) (string, []LockfilePackage, error) {
+	if !IsLockfile(path) {
+		return "", nil, fmt.Errorf("not a supported lockfile: %s", filepath.Base(path))
+	}
+	lf, err := parseLockfile(filepath.Base(path), content)
+	if err != nil {
+		return "", nil, err
+	}
+	packages := make([]LockfilePackage, 0, len(lf.packages))
+	for _, p := range lf.packages {
+		packages = append(packages, LockfilePackage{
+			Name:     p.name,
+			Version:  p.version,
+			Source:   p.source,
+			License:  p.license,
+			Dev:      p.dev,
+			TopLevel: p.topLevel,
+		})
+	}
+	return lf.ecosystem, packages, nil
+}
+
+func writeLockfileSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), lockfileMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+	if len(lines) > lockfileMaxDetails {
+		fmt.Fprintf(summary, "  - ... and %d more\n", len(lines)-lockfileMaxDetails)
+	}
+}
+
+// lockfileDuplicates lists the packages locked at more than one version,
+// by name, with their versions in order of appearance.
+func lockfileDuplicates(packages []lockedPackage) []string {
+	versions := make(map[string][]string)
+	for _, p := range packages {
+		if !slices.Contains(versions[p.name], p.version) {
+			versions[p.name] = append(versions[p.name], p.version)
+		}
+	}
+	var lines []string
+	for _, name := range sortedKeys(versions) {
+		if v := versions[name]; len(v) > 1 {
+			lines = append(lines, fmt.Sprintf("%s: %s", name, strings.Join(v, ", ")))
+		}
+	}
+	return lines
+}
+
+// lockfileDependents lists the packages with the most dependents.
+func lockfileDependents(packages []lockedPackage) []string {
+	dependents := make(map[string]int)
+	for _, p := range packages {
+		for _, dep := range p.deps {
+			dependents[dep]++
+		}
+	}
+	var lines []string
+	for _, e := range byCount(dependents) {
+		if len(lines) == lockfileMaxDependents || e.Count < 2 {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s (%d dependents)", e.Key, e.Count))
+	}
+	return lines
+}
+
+func lockfileDegradation(content []byte, format string, err error, rewrite string) degradedExploration {
+	line := 0
+	var syntaxErr *lockfileSyntaxError
+	if errors.As(err, &syntaxErr) {
+		line = syntaxErr.line
+	}
+	return degradedExploration{
+		Failed:   fmt.Sprintf("%s parsing at line %d: %v", format, line, err),
+		Progress: fmt.Sprintf("parsed %d of %d lines", max(line-1, 0), strings.Count(string(content), "\n")+1),
+		Examined: lineEndOffset(content, line),
+		Size:     int64(len(content)),
+		NextSteps: []string{
+			"Check for merge conflict markers or a truncated file",
+			rewrite,
+		},
+	}
+}
+
+// --- npm ---
+
+type npmLock struct {
+	LockfileVersion int                       `json:"lockfileVersion"`
+	Packages        map[string]npmLockPackage `json:"packages"`
+	Dependencies    map[string]npmLockDep     `json:"dependencies"`
+}
+
+// npmLockPackage is an entry of the "packages" map (lockfile v2 and v3).
+type npmLockPackage struct {
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Link                 bool              `json:"link"`
+	Dev                  bool              `json:"dev"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	License              json.RawMessage   `json:"license"`
+}
+
+// npmLockDep is an entry of the nested "dependencies" map (lockfile v1).
+type npmLockDep struct {
+	Version      string                `json:"version"`
+	Resolved     string                `json:"resolved"`
+	Dev          bool                  `json:"dev"`
+	Requires     map[string]string     `json:"requires"`
+	Dependencies map[string]npmLockDep `json:"dependencies"`
+}
+
+func parseNPMLock(content []byte) (*lockfile, error) {
+	var raw npmLock
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	lf := &lockfile{
+		ecosystem: "npm",
+		format:    fmt.Sprintf("lockfile v%d", max(raw.LockfileVersion, 1)),
+		split:     true,
+		graph:     true,
+	}
+	if len(raw.Packages) > 0 {
+		root := raw.Packages[""]
+		declared := make(map[string]bool)
+		for _, deps := range []map[string]string{root.Dependencies, root.DevDependencies, root.OptionalDependencies, root.PeerDependencies} {
+			for name := range deps {
+				declared[name] = true
+			}
+		}
+		for _, key := range sortedKeys(raw.Packages) {
+			entry := raw.Packages[key]
+			idx := strings.LastIndex(key, "node_modules/")
+			if key == "" {
+				continue
+			}
+			if idx < 0 {
+				// Workspace packages live outside node_modules.
+				lf.members = append(lf.members, key)
+				continue
+			}
+			name := key[idx+len("node_modules/"):]
+			p := lockedPackage{
+				name:     name,
+				version:  entry.Version,
+				source:   npmSource(entry.Resolved, entry.Link),
+				dev:      entry.Dev,
+				topLevel: idx == 0 && declared[name],
+				license:  npmLicense(entry.License),
+			}
+			if entry.Link {
+				p.version = "link"
+			}
+			for _, deps := range []map[string]string{entry.Dependencies, entry.OptionalDependencies, entry.PeerDependencies} {
+				p.deps = append(p.deps, sortedKeys(deps)...)
+			}
+			lf.packages = append(lf.packages, p)
+		}
+		return lf, nil
+	}
+
+	// Lockfile v1 only nests packages; the ones no other package requires
+	// are the top-level dependencies.
+	required := make(map[string]bool)
+	var walk func(deps map[string]npmLockDep, nested bool)
+	walk = func(deps map[string]npmLockDep, nested bool) {
+		for _, name := range sortedKeys(deps) {
+			entry := deps[name]
+			p := lockedPackage{
+				name:    name,
+				version: entry.Version,
+				source:  npmSource(entry.Resolved, strings.HasPrefix(entry.Version, "file:")),
+				dev:     entry.Dev,
+				deps:    sortedKeys(entry.Requires),
+			}
+			p.topLevel = !nested
+			for _, req := range p.deps {
+				required[req] = true
+			}
+			lf.packages = append(lf.packages, p)
+			walk(entry.Dependencies, true)
+		}
+	}
+	walk(raw.Dependencies, false)
+	for i := range lf.packages {
+		if lf.packages[i].topLevel && required[lf.packages[i].name] {
+			lf.packages[i].topLevel = false
+		}
+	}
+	return lf, nil
+}
+
+// npmLicense reads a license field, an SPDX expression or, in old
+// packages, an object with a type.
+func npmLicense(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(raw, &obj)
+	return obj.Type
+}
+
+func npmSource(resolved string, link bool) string {
+	switch {
+	case link || strings.HasPrefix(resolved, "file:"):
+		return "path"
+	case strings.HasPrefix(resolved, "git"):
+		return "git"
+	default:
+		return "registry"
+	}
+}
+
+// --- go.sum ---
+
+func parseGoSum(content []byte) (*lockfile, error) {
+	lf := &lockfile{ecosystem: "go"}
+	modOnly := 0
+	seen := make(map[string]bool)
+	var modules []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxFullLoadSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "h1:") {
+			return nil, &lockfileSyntaxError{line: line, msg: "expected <module> <version> h1:<hash>"}
+		}
+		key := fields[0] + " " + fields[1]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		modules = append(modules, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for _, key := range modules {
+		mod, version, _ := strings.Cut(key, " ")
+		if v, ok := strings.CutSuffix(version, "/go.mod"); ok {
+			// Only the go.mod of this version was needed, for the module
+			// graph; its code is not part of the build.
+			if !seen[mod+" "+v] {
+				modOnly++
+			}
+			continue
+		}
+		lf.packages = append(lf.packages, lockedPackage{name: mod, version: version, source: "registry"})
+	}
+	lf.notes = append(lf.notes,
+		fmt.Sprintf("Module versions with go.mod only: %d", modOnly),
+		"Top-level and transitive modules are not recorded in go.sum; see go.mod",
+	)
+	return lf, nil
+}
+
+// --- Cargo.lock and poetry.lock ---
+
+// tomlLockTable is one [[package]] of a TOML lockfile, with its
+// [package.*] subtables.
+type tomlLockTable struct {
+	values map[string]string
+	deps   []string
+	source map[string]string
+}
+
+// parseTOMLLock reads the [[package]] tables of Cargo.lock or poetry.lock.
+// Both are machine-written, so only the subset of TOML they use is read:
+// one key per line, strings, arrays of strings and inline tables.
+func parseTOMLLock(content []byte, ecosystem string) (*lockfile, error) {
+	var tables []*tomlLockTable
+	var cur *tomlLockTable
+	section := ""
+	metadata := make(map[string]string)
+	lines := strings.Split(string(content), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "<<<<<<<") || strings.HasPrefix(line, ">>>>>>>") {
+			return nil, &lockfileSyntaxError{line: i + 1, msg: "merge conflict marker"}
+		}
+		if strings.HasPrefix(line, "[") && !strings.Contains(line, "=") {
+			header := strings.Trim(line, "[] ")
+			if strings.HasPrefix(line, "[[") {
+				if header == "package" {
+					cur = &tomlLockTable{values: make(map[string]string), source: make(map[string]string)}
+					tables = append(tables, cur)
+				}
+				section = header
+				continue
+			}
+			section = header
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, &lockfileSyntaxError{line: i + 1, msg: fmt.Sprintf("expected key = value, got %q", line)}
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		value = strings.TrimSpace(value)
+		start := i
+		for !tomlBalanced(value) {
+			i++
+			if i >= len(lines) {
+				return nil, &lockfileSyntaxError{line: start + 1, msg: fmt.Sprintf("unterminated value of %s", key)}
+			}
+			value += " " + strings.TrimSpace(lines[i])
+		}
+
+		switch {
+		case section == "" || section == "metadata":
+			metadata[key] = tomlString(value)
+		case cur == nil:
+		case section == "package":
+			cur.values[key] = value
+		case section == "package.dependencies":
+			cur.deps = append(cur.deps, key)
+		case section == "package.source":
+			cur.source[key] = tomlString(value)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, &lockfileSyntaxError{line: len(lines), msg: "no [[package]] tables"}
+	}
+
+	lf := &lockfile{ecosystem: ecosystem, graph: true, split: true}
+	if ecosystem == "cargo" {
+		fillCargoLock(lf, tables, metadata)
+	} else {
+		fillPoetryLock(lf, tables, metadata)
+	}
+	return lf, nil
+}
+
+func fillCargoLock(lf *lockfile, tables []*tomlLockTable, metadata map[string]string) {
+	if v := metadata["version"]; v != "" {
+		lf.format = "lock format " + v
+	}
+	local := make(map[string]bool)
+	var members []*tomlLockTable
+	for _, t := range tables {
+		// Workspace members and path dependencies have no source.
+		if t.values["source"] == "" {
+			local[tomlString(t.values["name"])] = true
+			members = append(members, t)
+		}
+	}
+	direct := make(map[string]bool)
+	for _, t := range members {
+		lf.members = append(lf.members, tomlString(t.values["name"]))
+		for _, dep := range tomlStrings(t.values["dependencies"]) {
+			direct[strings.Fields(dep)[0]] = true
+		}
+	}
+	for _, t := range tables {
+		name := tomlString(t.values["name"])
+		if t.values["source"] == "" {
+			continue
+		}
+		source := "registry"
+		if strings.HasPrefix(tomlString(t.values["source"]), "git+") {
+			source = "git"
+		}
+		var deps []string
+		for _, dep := range tomlStrings(t.values["dependencies"]) {
+			deps = append(deps, strings.Fields(dep)[0])
+		}
+		lf.packages = append(lf.packages, lockedPackage{
+			name:     name,
+			version:  tomlString(t.values["version"]),
+			source:   source,
+			topLevel: direct[name] && !local[name],
+			deps:     deps,
+		})
+	}
+	slices.Sort(lf.members)
+}
+
+func fillPoetryLock(lf *lockfile, tables []*tomlLockTable, metadata map[string]string) {
+	if v := metadata["lock-version"]; v != "" {
+		lf.format = "lock-version " + v
+	}
+	required := make(map[string]bool)
+	for _, t := range tables {
+		for _, dep := range t.deps {
+			required[poetryName(dep)] = true
+		}
+	}
+	for _, t := range tables {
+		name := poetryName(tomlString(t.values["name"]))
+		p := lockedPackage{
+			name:     name,
+			version:  tomlString(t.values["version"]),
+			source:   "registry",
+			topLevel: !required[name],
+		}
+		switch t.source["type"] {
+		case "git":
+			p.source = "git"
+		case "directory", "file":
+			p.source = "path"
+		case "url":
+			p.source = "url"
+		}
+		if category := tomlString(t.values["category"]); category != "" {
+			p.dev = category == "dev"
+		} else if groups := tomlStrings(t.values["groups"]); len(groups) > 0 {
+			p.dev = !slices.Contains(groups, "main")
+		}
+		for _, dep := range t.deps {
+			p.deps = append(p.deps, poetryName(dep))
+		}
+		lf.packages = append(lf.packages, p)
+	}
+	lf.notes = append(lf.notes, "Top-level: packages no other locked package depends on")
+}
+
+// poetryName normalizes a Python distribution name (PEP 503).
+func poetryName(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(name), "_", "-"), ".", "-")
+}
+
+// tomlBalanced reports whether value closes every bracket and brace it
+// opens outside strings.
+func tomlBalanced(value string) bool {
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth <= 0 && quote == 0
+}
+
+// tomlString returns the content of a basic or literal string value, or
+// the value as is.
+func tomlString(value string) string {
+	if len(value) >= 2 && value[0] == '"' {
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// tomlStrings returns the strings of an array value.
+func tomlStrings(value string) []string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil
+	}
+	var items []string
+	for item := range strings.SplitSeq(value[1:len(value)-1], ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, tomlString(item))
+		}
+	}
+	return items
+}
@@ -0,0 +1,337 @@
+package explorer
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPackageLock = `{
+  "name": "web",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "requires": true,
+  "packages": {
+    "": {
+      "name": "web",
+      "workspaces": ["packages/ui"],
+      "dependencies": {"express": "^4.18.0", "lodash": "^4.17.21"},
+      "devDependencies": {"typescript": "^5.4.0"}
+    },
+    "packages/ui": {"name": "ui", "version": "0.1.0"},
+    "node_modules/ui": {"resolved": "packages/ui", "link": true},
+    "node_modules/express": {
+      "version": "4.18.2",
+      "resolved": "https://registry.npmjs.org/express/-/express-4.18.2.tgz",
+      "license": "MIT",
+      "dependencies": {"debug": "2.6.9", "qs": "6.11.0"}
+    },
+    "node_modules/debug": {
+      "version": "2.6.9",
+      "resolved": "https://registry.npmjs.org/debug/-/debug-2.6.9.tgz",
+      "license": "MIT",
+      "dependencies": {"ms": "2.0.0"}
+    },
+    "node_modules/ms": {"version": "2.0.0", "resolved": "https://registry.npmjs.org/ms/-/ms-2.0.0.tgz"},
+    "node_modules/qs": {
+      "version": "6.11.0",
+      "resolved": "https://registry.npmjs.org/qs/-/qs-6.11.0.tgz",
+      "license": {"type": "BSD-3-Clause"},
+      "dependencies": {"ms": "2.1.3"}
+    },
+    "node_modules/qs/node_modules/ms": {"version": "2.1.3", "resolved": "https://registry.npmjs.org/ms/-/ms-2.1.3.tgz"},
+    "node_modules/lodash": {"version": "4.17.21", "resolved": "git+ssh://git@github.com/lodash/lodash.git#abc"},
+    "node_modules/typescript": {"version": "5.4.5", "resolved": "https://registry.npmjs.org/typescript/-/typescript-5.4.5.tgz", "dev": true}
+  }
+}
+`
+
+const testGoSum = `github.com/google/uuid v1.6.0 h1:NIvaJDMOsjHA8n1jAhLSgzrAzy1Hgr+hNrb57e+94F0=
+github.com/google/uuid v1.6.0/go.mod h1:TIyPZe4MgqvfeYDBFedMoGGpEw/LqOeaOT+nhxU+yHo=
+github.com/stretchr/testify v1.8.4 h1:CcVxjf3Q8PM0mHUKJCdn+eZZtm5yQwehR5yeSVQQcUk=
+github.com/stretchr/testify v1.8.4/go.mod h1:sz/lmYIOXD/1dqDmKjjqLyZ2RngseejIcXlSw2iwfAo=
+github.com/stretchr/testify v1.9.0 h1:HtqpIVDClZ4nwg75+f6Lvsy/wHu+3BoSGCbBAcpTsTg=
+github.com/stretchr/testify v1.9.0/go.mod h1:r2ic/lqez/lEtzL7wO/rwa5dbSLXVDPFyf8C91i36aY=
+gopkg.in/yaml.v3 v3.0.0/go.mod h1:K4uyk7z7BCEPqu6E+C64Yfv1cQ7kz7rIZviUmN+EgEM=
+`
+
+const testCargoLock = `# This file is automatically @generated by Cargo.
+# It is not intended for manual editing.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "serde",
+ "syn 2.0.48",
+ "tokio",
+]
+
+[[package]]
+name = "proc-macro2"
+version = "1.0.78"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "e2422ad645d89c99f8f3e6b88a9fdeca7fabeac836b1002371c4367c8f984aae"
+
+[[package]]
+name = "serde"
+version = "1.0.196"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "serde_derive",
+]
+
+[[package]]
+name = "serde_derive"
+version = "1.0.196"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "proc-macro2",
+ "syn 2.0.48",
+]
+
+[[package]]
+name = "syn"
+version = "1.0.109"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "proc-macro2",
+]
+
+[[package]]
+name = "syn"
+version = "2.0.48"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "proc-macro2",
+]
+
+[[package]]
+name = "tokio"
+version = "1.36.0"
+source = "git+https://github.com/tokio-rs/tokio?branch=master#1a2b3c"
+dependencies = [
+ "syn 1.0.109",
+]
+`
+
+const testPoetryLock = `# This file is automatically @generated by Poetry 1.8.2 and should not be changed by hand.
+
+[[package]]
+name = "certifi"
+version = "2024.2.2"
+description = "Python package for providing Mozilla's CA Bundle."
+optional = false
+python-versions = ">=3.6"
+files = [
+    {file = "certifi-2024.2.2-py3-none-any.whl", hash = "sha256:dc38"},
+    {file = "certifi-2024.2.2.tar.gz", hash = "sha256:0569"},
+]
+
+[[package]]
+name = "pytest"
+version = "8.0.2"
+description = "pytest: simple powerful testing with Python"
+optional = false
+python-versions = ">=3.8"
+groups = ["dev"]
+
+[package.dependencies]
+colorama = {version = "*", markers = "sys_platform == \"win32\""}
+
+[[package]]
+name = "colorama"
+version = "0.4.6"
+description = "Cross-platform colored terminal text."
+optional = false
+python-versions = "!=3.0.*,!=3.1.*,>=2.7"
+groups = ["dev"]
+
+[[package]]
+name = "Requests"
+version = "2.31.0"
+description = "Python HTTP for Humans."
+optional = false
+python-versions = ">=3.7"
+groups = ["main"]
+
+[package.dependencies]
+certifi = ">=2017.4.17"
+
+[package.extras]
+socks = ["PySocks (>=1.5.6,!=1.5.7)"]
+
+[package.source]
+type = "git"
+url = "https://github.com/psf/requests.git"
+reference = "main"
+resolved_reference = "abc123"
+
+[metadata]
+lock-version = "2.0"
+python-versions = "^3.11"
+content-hash = "deadbeef"
+`
+
+func TestLockfileExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &LockfileExplorer{}
+	for _, path := range []string{"package-lock.json", "web/npm-shrinkwrap.json", "go.sum", "Cargo.lock", "poetry.lock"} {
+		require.True(t, e.CanHandle(path, nil), path)
+	}
+	for _, path := range []string{"package.json", "go.mod", "Cargo.toml", "yarn.lock"} {
+		require.False(t, e.CanHandle(path, nil), path)
+	}
+}
+
+func TestLockfileExplorer_NPM(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&LockfileExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), ExploreInput{Path: "package-lock.json", Content: []byte(testPackageLock)})
+	require.NoError(t, err)
+	require.Equal(t, "lockfile", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Lockfile: package-lock.json (npm, lockfile v3)\n")
+	require.Contains(t, s, "Packages: 8 (3 top-level, 5 transitive)\n")
+	require.Contains(t, s, "Workspace members: packages/ui\n")
+	require.Contains(t, s, "Duplicated packages: 1\n")
+	require.Contains(t, s, "Top-level dependencies:\n  - express 4.18.2\n  - lodash 4.17.21\n  - typescript 5.4.5\n")
+	require.Contains(t, s, "Duplicate versions:\n  - ms: 2.0.0, 2.1.3\n")
+	require.Contains(t, s, "  - lodash 4.17.21 (git)\n")
+	require.Contains(t, s, "  - ui link (path)\n")
+	require.Contains(t, s, "Dev-only packages: 1 of 8\n")
+	require.Contains(t, s, "Most depended on:\n  - ms (2 dependents)\n")
+	require.Contains(t, s, "Licenses:\n  - MIT: 2 packages\n  - BSD-3-Clause: 1 packages\n")
+}
+
+func TestParseLockfile(t *testing.T) {
+	t.Parallel()
+
+	ecosystem, packages, err := ParseLockfile("web/package-lock.json", []byte(testPackageLock))
+	require.NoError(t, err)
+	require.Equal(t, "npm", ecosystem)
+	require.Len(t, packages, 8)
+	i := slices.IndexFunc(packages, func(p LockfilePackage) bool { return p.Name == "express" })
+	require.Equal(t, LockfilePackage{Name: "express", Version: "4.18.2", Source: "registry", License: "MIT", TopLevel: true}, packages[i])
+
+	ecosystem, packages, err = ParseLockfile("go.sum", []byte(testGoSum))
+	require.NoError(t, err)
+	require.Equal(t, "go", ecosystem)
+	require.NotEmpty(t, packages)
+
+	_, _, err = ParseLockfile("yarn.lock", nil)
+	require.Error(t, err)
+}
+
+func TestLockfileExplorer_NPMv1(t *testing.T) {
+	t.Parallel()
+
+	content := `{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "express": {"version": "4.18.2", "requires": {"qs": "6.11.0"}},
+    "qs": {"version": "6.11.0", "requires": {"side-channel": "^1.0.4"}, "dependencies": {"side-channel": {"version": "1.0.4"}}}
+  }
+}`
+	result, err := (&LockfileExplorer{}).Explore(context.Background(), ExploreInput{Path: "package-lock.json", Content: []byte(content)})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Lockfile: package-lock.json (npm, lockfile v1)\n")
+	require.Contains(t, result.Summary, "Packages: 3 (1 top-level, 2 transitive)\n")
+	require.Contains(t, result.Summary, "Top-level dependencies:\n  - express 4.18.2\n")
+}
+
+func TestLockfileExplorer_GoSum(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&LockfileExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), ExploreInput{Path: "go.sum", Content: []byte(testGoSum)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Lockfile: go.sum (go)\n")
+	require.Contains(t, s, "Packages: 3\n")
+	require.Contains(t, s, "Module versions with go.mod only: 1\n")
+	require.Contains(t, s, "Duplicate versions:\n  - github.com/stretchr/testify: v1.8.4, v1.9.0\n")
+	require.NotContains(t, s, "Top-level dependencies")
+	require.NotContains(t, s, "Sources:")
+}
+
+func TestLockfileExplorer_Cargo(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&LockfileExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), ExploreInput{Path: "Cargo.lock", Content: []byte(testCargoLock)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Lockfile: Cargo.lock (cargo, lock format 3)\n")
+	require.Contains(t, s, "Packages: 6 (4 top-level, 2 transitive)\n")
+	require.Contains(t, s, "Workspace members: app\n")
+	require.Contains(t, s, "Top-level dependencies:\n  - serde 1.0.196\n  - syn 1.0.109\n  - syn 2.0.48\n  - tokio 1.36.0\n")
+	require.Contains(t, s, "Duplicate versions:\n  - syn: 1.0.109, 2.0.48\n")
+	require.Contains(t, s, "  - tokio 1.36.0 (git)\n")
+	require.Contains(t, s, "  - proc-macro2 (3 dependents)\n")
+}
+
+func TestLockfileExplorer_Poetry(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&LockfileExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), ExploreInput{Path: "poetry.lock", Content: []byte(testPoetryLock)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Lockfile: poetry.lock (poetry, lock-version 2.0)\n")
+	require.Contains(t, s, "Packages: 4 (2 top-level, 2 transitive)\n")
+	require.Contains(t, s, "Top-level dependencies:\n  - pytest 8.0.2\n  - requests 2.31.0\n")
+	require.Contains(t, s, "  - requests 2.31.0 (git)\n")
+	require.Contains(t, s, "Dev-only packages: 2 of 4\n")
+	require.Contains(t, s, "Duplicated packages: 0\n")
+}
+
+func TestLockfileExplorer_Degraded(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		path    string
+		content string
+		failed  string
+	}{
+		{path: "go.sum", content: "github.com/a/b v1.0.0 h1:x=\ngithub.com/a/b v1.0.0\n", failed: "go.sum parsing at line 2: expected <module> <version> h1:<hash>"},
+		{path: "Cargo.lock", content: "[[package]]\nname = \"a\"\ndependencies = [\n \"b\",\n", failed: "Cargo.lock parsing at line 3: unterminated value of dependencies"},
+		{path: "poetry.lock", content: "[[package]]\n<<<<<<< HEAD\nname = \"a\"\n", failed: "poetry.lock parsing at line 2: merge conflict marker"},
+		{path: "Cargo.lock", content: "version = 3\n", failed: "Cargo.lock parsing at line 2: no [[package]] tables"},
+		{path: "package-lock.json", content: `{"packages": {`, failed: "JSON decoding"},
+	} {
+		result, err := (&LockfileExplorer{}).Explore(context.Background(), ExploreInput{Path: tt.path, Content: []byte(tt.content)})
+		require.NoError(t, err)
+		require.Regexp(t, degradedBlockPattern, result.Summary)
+		require.Contains(t, result.Summary, "Failed: "+tt.failed)
+	}
+}
+
+func TestLockfileExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		for _, tt := range []struct {
+			path, content, enhanced string
+		}{
+			{"package-lock.json", testPackageLock, "### Most depended on"},
+			{"go.sum", testGoSum, ""},
+			{"Cargo.lock", testCargoLock, "### Packages outside the registry"},
+			{"poetry.lock", testPoetryLock, "Dev-only packages"},
+		} {
+			result, err := registry.Explore(context.Background(), ExploreInput{Path: tt.path, Content: []byte(tt.content)})
+			require.NoError(t, err)
+			require.Equal(t, "lockfile", result.ExplorerUsed, tt.path)
+			if tt.enhanced != "" {
+				require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, tt.enhanced), tt.path)
+			}
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package explorer
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +16,9 @@ import (
 // and sampling error/warning messages.
 type LogsExplorer struct {
 	formatterProfile OutputProfile
+	// samples is how many error and warning lines are sampled; zero
+	// samples maxSampleSize.
+	samples int
 }
 
 // logLevels captures common log level patterns, ordered by severity (highest first).
@@ -194,6 +199,21 @@ func (e *LogsExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 	totalLines := len(lines)
 	fmt.Fprintf(&summary, "Total lines: %d\n", totalLines)
 
+	// Stack traces and other continuation lines count with the line they
+	// follow, so levels and samples are per event.
+	events := groupLogEvents(lines)
+	headers := eventHeaders(events)
+	continuations, multiline := 0, 0
+	for _, ev := range events {
+		if len(ev.lines) > 1 {
+			continuations += len(ev.lines) - 1
+			multiline++
+		}
+	}
+	if multiline > 0 {
+		fmt.Fprintf(&summary, "Multi-line events: %d (%d continuation lines)\n", multiline, continuations)
+	}
+
 	// Count levels and detect timestamp patterns in parallel.
 	var wg sync.WaitGroup
 
@@ -202,7 +222,7 @@ func (e *LogsExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 
 	// Count log levels.
 	wg.Go(func() {
-		countLogLevels(lines, levelCounts)
+		countLogLevels(headers, levelCounts)
 	})
 
 	// Count timestamp patterns.
@@ -212,6 +232,20 @@ func (e *LogsExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 
 	wg.Wait()
 
+	facts := &Facts{}
+	facts.count("total_lines", totalLines)
+	facts.count("events", len(events))
+	facts.count("multiline_events", multiline)
+	for level, count := range levelCounts {
+		facts.count("level_"+strings.ToLower(level), count)
+	}
+	timeline := buildLogTimeline(lines, tsPatternCounts)
+	if timeline != nil {
+		facts.count("timestamped_lines", timeline.stamped)
+		facts.count("duration_seconds", int(timeline.last.Sub(timeline.first).Seconds()))
+		facts.count("error_spikes", timeline.spikes)
+	}
+
 	// Write level distribution.
 	if len(levelCounts) > 0 {
 		summary.WriteString("\nLevel distribution:\n")
@@ -219,7 +253,7 @@ func (e *LogsExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 		orderedLevels := orderedLevelNames(levelCounts)
 		for _, level := range orderedLevels {
 			count := levelCounts[level]
-			percentage := float64(count) * 100 / float64(totalLines)
+			percentage := float64(count) * 100 / float64(totalLines-continuations)
 			fmt.Fprintf(&summary, "  %s: %d (%.1f%%)\n", level, count, percentage)
 		}
 	} else {
@@ -239,7 +273,7 @@ func (e *LogsExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 	}
 
 	// Sample errors and warnings.
-	samples := sampleErrorsAndWarnings(lines)
+	samples := sampleErrorsAndWarnings(events, cmp.Or(e.samples, maxSampleSize))
 	if len(samples) > 0 {
 		summary.WriteString("\nSample errors/warnings:\n")
 		for i, sample := range samples {
@@ -247,9 +281,14 @@ func (e *LogsExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 		}
 	}
 
+	// EXCEED MODE: Time range and event rate
+	if e.formatterProfile == OutputProfileEnhancement && timeline != nil {
+		timeline.write(&summary, totalLines)
+	}
+
 	// EXCEED MODE: Repeated error-signature aggregation
 	if e.formatterProfile == OutputProfileEnhancement {
-		signatures := aggregateErrorSignatures(lines)
+		signatures := aggregateErrorSignatures(headers)
 		if len(signatures) > 0 {
 			summary.WriteString("\nRepeated error signatures:\n")
 			for i, sig := range signatures {
@@ -272,6 +311,7 @@ func (e *LogsExplorer) Explore(ctx context.Context, input ExploreInput) (Explore
 		Summary:       result,
 		ExplorerUsed:  "logs",
 		TokenEstimate: estimateTokens(result),
+		Facts:         facts,
 	}, nil
 }
 
@@ -315,21 +355,19 @@ func countTimestampPatterns(lines []string, counts map[string]int) {
 	}
 }
 
-// sampleErrorsAndWarnings deterministically samples error and warning lines.
-func sampleErrorsAndWarnings(lines []string) []string {
+// sampleErrorsAndWarnings deterministically samples up to n error and
+// warning events, each with its frame count and cause.
+func sampleErrorsAndWarnings(events []logEvent, n int) []string {
 	var errorLines []string
 	var warnLines []string
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+	for _, ev := range events {
+		line := ev.header()
 
 		// Check for error patterns.
 		for _, pattern := range logLevels[0].patterns {
 			if pattern.MatchString(line) {
-				errorLines = append(errorLines, truncateSample(line, maxSampleLineLength))
+				errorLines = append(errorLines, truncateSample(ev.summary(), maxSampleLineLength))
 				break
 			}
 		}
@@ -337,16 +375,16 @@ func sampleErrorsAndWarnings(lines []string) []string {
 		// Check for warning patterns.
 		for _, pattern := range logLevels[1].patterns {
 			if pattern.MatchString(line) {
-				warnLines = append(warnLines, truncateSample(line, maxSampleLineLength))
+				warnLines = append(warnLines, truncateSample(ev.summary(), maxSampleLineLength))
 				break
 			}
 		}
 	}
 
-	// Deterministically select up to maxSampleSize samples for each level.
-	samples := make([]string, 0, maxSampleSize)
-	samples = append(samples, deterministicallySample(errorLines, maxSampleSize/2)...)
-	samples = append(samples, deterministicallySample(warnLines, maxSampleSize-len(samples))...)
+	// Deterministically select up to n samples, half of them errors.
+	samples := make([]string, 0, n)
+	samples = append(samples, deterministicallySample(errorLines, n/2)...)
+	samples = append(samples, deterministicallySample(warnLines, n-len(samples))...)
 
 	return samples
 }
@@ -418,38 +456,29 @@ func orderedLevelNames(counts map[string]int) []string {
 		severity[level.name] = i
 	}
 
-	var names []string
-	for name := range counts {
-		names = append(names, name)
-	}
-
-	// Sort by severity order.
-	sort.Slice(names, func(i, j int) bool {
-		sevi, oki := severity[names[i]]
-		sevj, okj := severity[names[j]]
-		if !oki || !okj {
-			return names[i] < names[j]
+	// Sort by severity order; unknown levels follow alphabetically.
+	names := sortedKeys(counts)
+	slices.SortStableFunc(names, func(a, b string) int {
+		sevA, okA := severity[a]
+		sevB, okB := severity[b]
+		if !okA {
+			sevA = len(logLevels)
+		}
+		if !okB {
+			sevB = len(logLevels)
 		}
-		return sevi < sevj
+		return cmp.Compare(sevA, sevB)
 	})
-
 	return names
 }
 
 // sortedTimestampPatternNames returns timestamp pattern names sorted by count (descending).
 func sortedTimestampPatternNames(counts map[string]int) []string {
-	var names []string
-	for name := range counts {
-		names = append(names, name)
+	entries := byCount(counts)
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Key
 	}
-
-	sort.Slice(names, func(i, j int) bool {
-		if counts[names[i]] != counts[names[j]] {
-			return counts[names[i]] > counts[names[j]]
-		}
-		return names[i] < names[j]
-	})
-
 	return names
 }
 
@@ -497,23 +526,14 @@ func aggregateErrorSignatures(lines []string) []errorSignature {
 
 	// Build sorted list by count (descending)
 	signatures := make([]errorSignature, 0, len(sigCounts))
-	for sig, count := range sigCounts {
-		if count >= 2 { // Only include repeated errors (2+ occurrences)
+	for _, e := range byCount(sigCounts) {
+		if e.Count >= 2 { // Only include repeated errors (2+ occurrences)
 			signatures = append(signatures, errorSignature{
-				signature: sig,
-				count:     count,
+				signature: e.Key,
+				count:     e.Count,
 			})
 		}
 	}
-
-	// Sort by count (descending)
-	sort.Slice(signatures, func(i, j int) bool {
-		if signatures[i].count != signatures[j].count {
-			return signatures[i].count > signatures[j].count
-		}
-		return signatures[i].signature < signatures[j].signature
-	})
-
 	return signatures
 }
 
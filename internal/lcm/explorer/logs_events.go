@@ -0,0 +1,128 @@
+package explorer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// continuationPattern matches unindented lines that belong to the event
+	// above them: Java frames, causes, suppressed exceptions and elided
+	// frames.
+	continuationPattern = regexp.MustCompile(`^(?:at\s+\S|Caused by:|Suppressed:|\.\.\. \d+ (?:more|common frames omitted))`)
+	// javaFrame matches a Java or JavaScript stack frame.
+	javaFrame = regexp.MustCompile(`^\s*at\s+\S`)
+	// exceptionLine matches an exception's message, such as
+	// "java.io.IOException: disk full" or "TypeError: x is undefined".
+	// Followed by a frame, it belongs to the event above it.
+	exceptionLine = regexp.MustCompile(`^(?:(?:[\w$]+\.)+[\w$]+|\w*(?:Error|Exception))(?::|$)`)
+	// goroutineHeader starts a goroutine's stack in a Go panic.
+	goroutineHeader = regexp.MustCompile(`^goroutine \d+ \[.*\]:$`)
+	// stackFrame matches a frame of a Java/JavaScript, Python or Go stack
+	// trace. Go frames are counted by their file:line half.
+	stackFrame = regexp.MustCompile(`^\s*at\s+\S|^\s*File "[^"]*", line \d+|^\s+\S+:\d+(?:\s+\+0x[0-9a-f]+)?$`)
+)
+
+// pythonTraceback starts a Python traceback.
+const pythonTraceback = "Traceback (most recent call last):"
+
+// logEvent is one log entry: a line and the continuation lines, such as
+// stack frames, that follow it.
+type logEvent struct {
+	// start is the index of the event's first line.
+	start  int
+	lines  []string
+	frames int
+	// cause is the innermost "Caused by:" line, or the exception line
+	// ending a Python traceback.
+	cause string
+}
+
+// header is the event's first line.
+func (ev logEvent) header() string {
+	return strings.TrimSpace(ev.lines[0])
+}
+
+// summary is the event's first line with its frame count and cause.
+func (ev logEvent) summary() string {
+	switch {
+	case ev.frames > 0 && ev.cause != "":
+		return fmt.Sprintf("%s (%d frames, caused by %s)", ev.header(), ev.frames, ev.cause)
+	case ev.frames > 0:
+		return fmt.Sprintf("%s (%d frames)", ev.header(), ev.frames)
+	case len(ev.lines) > 1:
+		return fmt.Sprintf("%s (+%d lines)", ev.header(), len(ev.lines)-1)
+	default:
+		return ev.header()
+	}
+}
+
+// groupLogEvents groups lines into events, attaching stack traces and other
+// continuation lines to the line they follow: indented lines, Java
+// exceptions with their "at" and "Caused by" lines, Python tracebacks and
+// the goroutine stacks of Go panics.
+// Blank lines are not events.
+func groupLogEvents(lines []string) []logEvent {
+	var events []logEvent
+	// inTraceback is set in a Python traceback until its exception line;
+	// inGoroutine is set in a goroutine's stack until a blank line.
+	var inTraceback, inGoroutine bool
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			inGoroutine = false
+			continue
+		}
+		if len(events) == 0 {
+			events = append(events, logEvent{start: i, lines: []string{line}})
+			inTraceback = trimmed == pythonTraceback
+			continue
+		}
+
+		ev := &events[len(events)-1]
+		indented := line[0] == ' ' || line[0] == '\t'
+		continues := indented || inGoroutine || continuationPattern.MatchString(trimmed) || trimmed == pythonTraceback ||
+			(exceptionLine.MatchString(trimmed) && i+1 < len(lines) && javaFrame.MatchString(lines[i+1]))
+		if goroutineHeader.MatchString(trimmed) && isPanicHeader(ev.header()) {
+			continues, inGoroutine = true, true
+		}
+		if !continues && inTraceback {
+			// The exception line ends the traceback.
+			ev.lines = append(ev.lines, line)
+			ev.cause = trimmed
+			inTraceback = false
+			continue
+		}
+		if !continues {
+			events = append(events, logEvent{start: i, lines: []string{line}})
+			inTraceback = trimmed == pythonTraceback
+			continue
+		}
+
+		ev.lines = append(ev.lines, line)
+		switch {
+		case stackFrame.MatchString(line):
+			ev.frames++
+		case trimmed == pythonTraceback:
+			inTraceback = true
+		case strings.HasPrefix(trimmed, "Caused by:"):
+			ev.cause = strings.TrimSpace(strings.TrimPrefix(trimmed, "Caused by:"))
+		}
+	}
+	return events
+}
+
+// isPanicHeader reports whether line starts a Go panic or fatal error.
+func isPanicHeader(line string) bool {
+	return strings.HasPrefix(line, "panic: ") || strings.HasPrefix(line, "fatal error: ")
+}
+
+// eventHeaders returns the first line of each event.
+func eventHeaders(events []logEvent) []string {
+	headers := make([]string, len(events))
+	for i, ev := range events {
+		headers[i] = ev.header()
+	}
+	return headers
+}
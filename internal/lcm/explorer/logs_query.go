@@ -0,0 +1,294 @@
+package explorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Log query group keys.
+const (
+	LogGroupLevel     = "level"
+	LogGroupSignature = "signature"
+	LogGroupMinute    = "minute"
+	LogGroupHour      = "hour"
+)
+
+// noTimestampGroup is the time group of entries without a parseable
+// timestamp.
+const noTimestampGroup = "(no timestamp)"
+
+// logQueryTimeLayouts are the layouts ParseLogQueryTime accepts.
+var logQueryTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// LogQuery selects the entries of a log. Zero fields match every entry.
+type LogQuery struct {
+	// Levels are the severities to keep: ERROR, WARN, INFO, DEBUG or TRACE,
+	// matched case-insensitively and with or without brackets.
+	Levels []string
+	// Since and Until bound the entries' timestamps, inclusively. Bounding
+	// the time drops entries without a parseable timestamp.
+	Since, Until time.Time
+	// Pattern matches anywhere in an entry, its stack trace included.
+	Pattern *regexp.Regexp
+	// GroupBy counts the matching entries by one of the LogGroup keys.
+	GroupBy string
+	// Limit caps the entries returned; Matched still counts them all.
+	Limit int
+}
+
+// LogEntry is one log event: a line and its continuation lines, such as a
+// stack trace.
+type LogEntry struct {
+	// Line is the 1-based line number of the entry's first line.
+	Line      int       `json:"line"`
+	Time      time.Time `json:"time,omitzero"`
+	Timestamp string    `json:"timestamp,omitempty"`
+	// Level is the entry's severity, as counted by the logs explorer.
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message"`
+	// Text is the whole entry, continuation lines included.
+	Text   string `json:"text"`
+	Frames int    `json:"frames,omitempty"`
+	Cause  string `json:"cause,omitempty"`
+}
+
+// LogGroup is the count of matching entries sharing a group key.
+type LogGroup struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+	// FirstLine is the line number of the group's first entry.
+	FirstLine int `json:"first_line"`
+}
+
+// LogQueryResult is the outcome of a LogQuery.
+type LogQueryResult struct {
+	Total   int        `json:"total"`
+	Matched int        `json:"matched"`
+	Entries []LogEntry `json:"entries"`
+	Groups  []LogGroup `json:"groups,omitempty"`
+}
+
+// ParseLogEntries groups a log into entries, attaching stack traces to the
+// line they follow, and parses the timestamps of the log's most common
+// timestamp format.
+func ParseLogEntries(content []byte) []LogEntry {
+	lines := strings.Split(string(content), "\n")
+	tsCounts := make(map[string]int)
+	countTimestampPatterns(lines, tsCounts)
+	pattern := parseableTimestampPattern(tsCounts)
+
+	events := groupLogEvents(lines)
+	entries := make([]LogEntry, 0, len(events))
+	for _, ev := range events {
+		header := ev.header()
+		timestamp, _, message := parseLogLine(header)
+		entry := LogEntry{
+			Line:      ev.start + 1,
+			Timestamp: timestamp,
+			Level:     logLevelOf(header),
+			Message:   message,
+			Text:      strings.Join(ev.lines, "\n"),
+			Frames:    ev.frames,
+			Cause:     ev.cause,
+		}
+		if pattern != "" {
+			if at, _, ok := parseLogTimestamp(pattern, header); ok {
+				entry.Time = at
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// QueryLogs runs q over the entries of a log.
+func QueryLogs(content []byte, q LogQuery) LogQueryResult {
+	entries := ParseLogEntries(content)
+	levels := make([]string, 0, len(q.Levels))
+	for _, level := range q.Levels {
+		levels = append(levels, normalizeLevel(level))
+	}
+
+	result := LogQueryResult{Total: len(entries)}
+	groups := make(map[string]*LogGroup)
+	for _, entry := range entries {
+		if len(levels) > 0 && !slices.Contains(levels, entry.Level) {
+			continue
+		}
+		if !q.Since.IsZero() || !q.Until.IsZero() {
+			if entry.Time.IsZero() || !withinLogQueryRange(entry.Time, q.Since, q.Until) {
+				continue
+			}
+		}
+		if q.Pattern != nil && !q.Pattern.MatchString(entry.Text) {
+			continue
+		}
+
+		result.Matched++
+		if q.Limit <= 0 || len(result.Entries) < q.Limit {
+			result.Entries = append(result.Entries, entry)
+		}
+		if q.GroupBy == "" {
+			continue
+		}
+		key := logGroupKey(entry, q.GroupBy)
+		if g, ok := groups[key]; ok {
+			g.Count++
+			continue
+		}
+		groups[key] = &LogGroup{Key: key, Count: 1, FirstLine: entry.Line}
+	}
+
+	for _, g := range groups {
+		result.Groups = append(result.Groups, *g)
+	}
+	slices.SortFunc(result.Groups, func(a, b LogGroup) int {
+		// Time groups read in time order, the others most frequent first.
+		if q.GroupBy != LogGroupMinute && q.GroupBy != LogGroupHour && a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return a.FirstLine - b.FirstLine
+	})
+	return result
+}
+
+// ValidLogGroupBy reports whether key is a LogQuery.GroupBy key.
+func ValidLogGroupBy(key string) bool {
+	switch key {
+	case "", LogGroupLevel, LogGroupSignature, LogGroupMinute, LogGroupHour:
+		return true
+	}
+	return false
+}
+
+// ParseLogQueryTime parses a LogQuery bound written as RFC 3339 or as a UTC
+// date with an optional time of day, such as "2024-01-15 10:30".
+func ParseLogQueryTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range logQueryTimeLayouts {
+		if at, err := time.Parse(layout, s); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: want RFC 3339 or YYYY-MM-DD[ HH:MM[:SS]]", s)
+}
+
+// withinLogQueryRange reports whether at lies within [since, until]. Syslog
+// timestamps have no year, so they are compared ignoring the bounds' year.
+func withinLogQueryRange(at, since, until time.Time) bool {
+	if at.Year() == 0 {
+		since, until = withoutYear(since), withoutYear(until)
+	}
+	if !since.IsZero() && at.Before(since) {
+		return false
+	}
+	return until.IsZero() || !at.After(until)
+}
+
+// withoutYear moves t to year 0, leaving the zero time as it is.
+func withoutYear(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.AddDate(-t.Year(), 0, 0)
+}
+
+// logLevelOf returns the severity of line, the first level group whose
+// patterns match it, or "" when none does.
+func logLevelOf(line string) string {
+	for _, level := range logLevels {
+		for _, pattern := range level.patterns {
+			if pattern.MatchString(line) {
+				return level.name
+			}
+		}
+	}
+	return ""
+}
+
+// logGroupKey returns the key entry is counted under.
+func logGroupKey(entry LogEntry, groupBy string) string {
+	switch groupBy {
+	case LogGroupLevel:
+		if entry.Level == "" {
+			return "(none)"
+		}
+		return entry.Level
+	case LogGroupSignature:
+		return normalizeForSignature(strings.TrimSpace(strings.SplitN(entry.Text, "\n", 2)[0]))
+	case LogGroupMinute, LogGroupHour:
+		if entry.Time.IsZero() {
+			return noTimestampGroup
+		}
+		width := time.Minute
+		if groupBy == LogGroupHour {
+			width = time.Hour
+		}
+		return bucketLabel(entry.Time.Truncate(width))
+	}
+	return ""
+}
+
+// Format renders the result as text: the match count, then the groups or
+// the entries.
+func (r LogQueryResult) Format() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Matched %d of %d entries\n", r.Matched, r.Total)
+	if len(r.Groups) > 0 {
+		for _, g := range r.Groups {
+			fmt.Fprintf(&sb, "  %s: %d (first at line %d)\n", g.Key, g.Count, g.FirstLine)
+		}
+		return sb.String()
+	}
+	for _, entry := range r.Entries {
+		fmt.Fprintf(&sb, "%d: %s\n", entry.Line, entry.Text)
+	}
+	if n := r.Matched - len(r.Entries); n > 0 {
+		fmt.Fprintf(&sb, "%s\n", overflowMarker(OutputProfileEnhancement, n, false))
+	}
+	return sb.String()
+}
+
+// ExportCSV exports the groups as key,count,first_line rows, or else the
+// entries as line,timestamp,level,message rows.
+func (r LogQueryResult) ExportCSV() string {
+	var sb strings.Builder
+	if len(r.Groups) > 0 {
+		sb.WriteString("key,count,first_line\n")
+		for _, g := range r.Groups {
+			fmt.Fprintf(&sb, "%s,%d,%d\n", escapeCSV(g.Key), g.Count, g.FirstLine)
+		}
+		return sb.String()
+	}
+	sb.WriteString("line,timestamp,level,message\n")
+	for _, entry := range r.Entries {
+		message := entry.Message
+		if entry.Cause != "" {
+			message += " (caused by " + entry.Cause + ")"
+		}
+		fmt.Fprintf(&sb, "%d,%s,%s,%s\n", entry.Line, escapeCSV(entry.Timestamp), entry.Level, escapeCSV(message))
+	}
+	return sb.String()
+}
+
+// ExportJSON exports the result as indented JSON.
+func (r LogQueryResult) ExportJSON() (string, error) {
+	if r.Entries == nil {
+		r.Entries = []LogEntry{}
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal log query result: %w", err)
+	}
+	return string(data), nil
+}
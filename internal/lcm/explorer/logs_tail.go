@@ -0,0 +1,154 @@
+package explorer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxTailSignatures caps the signatures a LogTail remembers; once full,
+// signatures not yet seen are still reported but no longer remembered.
+const maxTailSignatures = 1000
+
+// LogTail analyzes a growing log one appended chunk at a time. It remembers
+// the error and warning signatures of earlier chunks, so each chunk
+// reports only the signatures it introduces. A LogTail is not safe for
+// concurrent use.
+type LogTail struct {
+	seen map[string]int
+}
+
+// LogSignature is an error or warning signature of a log chunk: the
+// header of its events with timestamps, IDs, numbers and paths removed.
+type LogSignature struct {
+	Signature string
+	Level     string
+	// Count is the occurrences in the chunk, Total those since the tail
+	// started.
+	Count int
+	Total int
+	// Sample is the first event of the chunk with the signature.
+	Sample string
+}
+
+// LogChunk is the analysis of one appended chunk of a log.
+type LogChunk struct {
+	Lines  int
+	Events int
+	Levels map[string]int
+	// New are the signatures first seen in this chunk, Recurring those
+	// seen in an earlier one; both most frequent first.
+	New       []LogSignature
+	Recurring []LogSignature
+}
+
+// NewLogTail creates a LogTail that has seen nothing yet.
+func NewLogTail() *LogTail {
+	return &LogTail{seen: make(map[string]int)}
+}
+
+// Add analyzes content appended to the log. Content should end at a line
+// boundary; an event whose stack trace spans two chunks counts in both.
+func (t *LogTail) Add(content []byte) LogChunk {
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+	events := groupLogEvents(lines)
+	chunk := LogChunk{
+		Lines:  len(lines),
+		Events: len(events),
+		Levels: make(map[string]int),
+	}
+	countLogLevels(eventHeaders(events), chunk.Levels)
+
+	var order []string
+	sigs := make(map[string]*LogSignature)
+	for _, ev := range events {
+		header := ev.header()
+		level := logLevelOf(header)
+		if level != "ERROR" && level != "WARN" {
+			continue
+		}
+		key := normalizeForSignature(header)
+		if sig, ok := sigs[key]; ok {
+			sig.Count++
+			continue
+		}
+		order = append(order, key)
+		sigs[key] = &LogSignature{
+			Signature: key,
+			Level:     level,
+			Count:     1,
+			Sample:    truncateSample(ev.summary(), maxSampleLineLength),
+		}
+	}
+
+	for _, key := range order {
+		sig := sigs[key]
+		before, seen := t.seen[key]
+		sig.Total = before + sig.Count
+		if seen || len(t.seen) < maxTailSignatures {
+			t.seen[key] = sig.Total
+		}
+		if seen {
+			chunk.Recurring = append(chunk.Recurring, *sig)
+		} else {
+			chunk.New = append(chunk.New, *sig)
+		}
+	}
+	byFrequency := func(a, b LogSignature) int { return b.Count - a.Count }
+	slices.SortStableFunc(chunk.New, byFrequency)
+	slices.SortStableFunc(chunk.Recurring, byFrequency)
+	return chunk
+}
+
+// Format renders the chunk as text: its level distribution, then the new
+// signatures with a sample event each, then the recurring ones.
+func (c LogChunk) Format() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "New lines: %d (%d events)\n", c.Lines, c.Events)
+	if len(c.Levels) > 0 {
+		levels := orderedLevelNames(c.Levels)
+		parts := make([]string, len(levels))
+		for i, level := range levels {
+			parts[i] = fmt.Sprintf("%s %d", level, c.Levels[level])
+		}
+		fmt.Fprintf(&sb, "Levels: %s\n", strings.Join(parts, ", "))
+	}
+
+	if len(c.New) == 0 {
+		sb.WriteString("\nNo new error signatures.\n")
+	} else {
+		sb.WriteString("\nNew error signatures:\n")
+		writeLogSignatures(&sb, c.New, true)
+	}
+	if len(c.Recurring) > 0 {
+		sb.WriteString("\nRecurring signatures:\n")
+		writeLogSignatures(&sb, c.Recurring, false)
+	}
+	return sb.String()
+}
+
+// writeLogSignatures lists up to maxSignatures signatures, with their
+// sample event when withSample is set.
+func writeLogSignatures(sb *strings.Builder, sigs []LogSignature, withSample bool) {
+	for i, sig := range sigs {
+		if i >= maxSignatures {
+			fmt.Fprintf(sb, "  %s\n", overflowMarker(OutputProfileEnhancement, len(sigs)-maxSignatures, false))
+			return
+		}
+		display := sig.Signature
+		if len(display) > maxSignatureLength {
+			display = display[:maxSignatureLength] + "..."
+		}
+		if sig.Total > sig.Count {
+			fmt.Fprintf(sb, "  [%s] %s: %d new (%d total)\n", sig.Level, display, sig.Count, sig.Total)
+		} else {
+			fmt.Fprintf(sb, "  [%s] %s: %d\n", sig.Level, display, sig.Count)
+		}
+		if withSample {
+			fmt.Fprintf(sb, "    e.g. %s\n", sig.Sample)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package explorer
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/x/exp/golden"
 	"github.com/stretchr/testify/require"
@@ -966,6 +968,179 @@ Jan 15 10:30:57 hostname service[1234]: [WARN] Syslog formatted warning message
 `)
 }
 
+func TestGroupLogEvents(t *testing.T) {
+	t.Parallel()
+
+	lines := strings.Split(`2024-01-15 10:30:45 [ERROR] Request failed
+java.lang.IllegalStateException: handler crashed
+	at com.example.Handler.handle(Handler.java:42)
+	at com.example.Server.run(Server.java:17)
+Caused by: java.io.IOException: disk full
+	at com.example.Store.write(Store.java:88)
+	... 2 more
+2024-01-15 10:30:46 [INFO] Retrying
+
+2024-01-15 10:30:47 [ERROR] Job crashed
+Traceback (most recent call last):
+  File "job.py", line 12, in <module>
+    main()
+  File "job.py", line 8, in main
+    raise ValueError("bad input")
+ValueError: bad input
+2024-01-15 10:30:48 [INFO] Next job
+panic: runtime error: index out of range [3] with length 3
+
+goroutine 1 [running]:
+main.main()
+	/src/main.go:12 +0x1d
+exit status 2`, "\n")
+
+	events := groupLogEvents(lines)
+	require.Equal(t, []string{
+		"2024-01-15 10:30:45 [ERROR] Request failed (3 frames, caused by java.io.IOException: disk full)",
+		"2024-01-15 10:30:46 [INFO] Retrying",
+		"2024-01-15 10:30:47 [ERROR] Job crashed (2 frames, caused by ValueError: bad input)",
+		"2024-01-15 10:30:48 [INFO] Next job",
+		"panic: runtime error: index out of range [3] with length 3 (1 frames)",
+	}, func() []string {
+		var summaries []string
+		for _, ev := range events {
+			summaries = append(summaries, ev.summary())
+		}
+		return summaries
+	}())
+	require.Len(t, events[0].lines, 7)
+	require.Len(t, events[2].lines, 7)
+	require.Len(t, events[4].lines, 5, "the goroutine stack runs to the next blank line")
+
+	// A log line followed by frames starts its own event.
+	events = groupLogEvents([]string{"[INFO] ok", "[ERROR] failed", "\tat com.example.Main.main(Main.java:3)"})
+	require.Len(t, events, 2)
+	require.Equal(t, "[ERROR] failed (1 frames)", events[1].summary())
+
+	// Indented lines without frames are counted as lines.
+	events = groupLogEvents([]string{"WARN config:", "  key: value", "  other: value"})
+	require.Len(t, events, 1)
+	require.Equal(t, "WARN config: (+2 lines)", events[0].summary())
+}
+
+func TestLogsExplorer_MultilineEvents(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	for i := range 3 {
+		lines = append(lines,
+			fmt.Sprintf("2024-01-15 10:30:4%d [ERROR] Request failed", i),
+			"java.lang.RuntimeException: FAILED to render",
+			"\tat com.example.View.render(View.java:10)",
+			"\tat com.example.Server.run(Server.java:17)",
+		)
+	}
+	lines = append(lines, "2024-01-15 10:30:50 [INFO] Done")
+
+	explorer := &LogsExplorer{formatterProfile: OutputProfileParity}
+	result, err := explorer.Explore(context.Background(), ExploreInput{Path: "app.log", Content: []byte(strings.Join(lines, "\n"))})
+	require.NoError(t, err)
+
+	require.Contains(t, result.Summary, "Total lines: 13\n")
+	require.Contains(t, result.Summary, "Multi-line events: 3 (9 continuation lines)")
+	require.Contains(t, result.Summary, "ERROR: 3 (75.0%)")
+	require.Contains(t, result.Summary, "INFO: 1 (25.0%)")
+	require.Contains(t, result.Summary, "1. 2024-01-15 10:30:40 [ERROR] Request failed (2 frames)")
+	require.NotContains(t, result.Summary, "RuntimeException", "continuation lines are not sampled on their own")
+	require.Equal(t, int64(4), result.Facts.Counts["events"])
+	require.Equal(t, int64(3), result.Facts.Counts["multiline_events"])
+}
+
+func TestLogsExplorer_Timeline(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	for minute := range 10 {
+		for second := range 4 {
+			lines = append(lines, fmt.Sprintf("2024-01-15T10:%02d:%02dZ [INFO] request served", minute, second*10))
+		}
+	}
+	for second := range 6 {
+		lines = append(lines, fmt.Sprintf("2024-01-15T10:04:%02dZ [ERROR] upstream timeout", 45+second))
+	}
+	lines = append(lines, "2024-01-15T10:07:05Z [ERROR] disk nearly full")
+	// A quiet hour before the last line.
+	lines = append(lines, "2024-01-15T11:10:00Z [INFO] shutting down")
+	content := []byte(strings.Join(lines, "\n"))
+
+	explorer := &LogsExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := explorer.Explore(context.Background(), ExploreInput{Path: "app.log", Content: content})
+	require.NoError(t, err)
+
+	require.Contains(t, result.Summary, "First: 2024-01-15T10:00:00Z")
+	require.Contains(t, result.Summary, "Last: 2024-01-15T11:10:00Z")
+	require.Contains(t, result.Summary, "Duration: 1:10:00")
+	require.Contains(t, result.Summary, "Timestamped lines: 48 of 48 (RFC3339)")
+	require.Contains(t, result.Summary, "Longest gap: 1:00:30 after 2024-01-15 10:09")
+	require.Contains(t, result.Summary, "Event rate (5m buckets):")
+	require.Contains(t, result.Summary, "2024-01-15 10:00: 26 lines (5.2/min), 6 errors (error spike)")
+	require.Contains(t, result.Summary, "2024-01-15 10:05: 21 lines (4.2/min), 1 errors\n")
+	require.Contains(t, result.Summary, "2024-01-15 11:10: 1 lines (0.2/min)\n")
+	require.Contains(t, result.Summary, "Error spikes: 1")
+	require.Equal(t, int64(48), result.Facts.Counts["timestamped_lines"])
+	require.Equal(t, int64(4200), result.Facts.Counts["duration_seconds"])
+	require.Equal(t, int64(1), result.Facts.Counts["error_spikes"])
+
+	// The parity profile keeps the facts but not the timeline.
+	parity := &LogsExplorer{formatterProfile: OutputProfileParity}
+	result, err = parity.Explore(context.Background(), ExploreInput{Path: "app.log", Content: content})
+	require.NoError(t, err)
+	require.NotContains(t, result.Summary, "Time range:")
+	require.Equal(t, int64(1), result.Facts.Counts["error_spikes"])
+}
+
+func TestParseLogTimestamp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		line    string
+		want    string
+	}{
+		{"RFC3339", "2024-01-15T10:30:45.123+02:00 [INFO] ok", "2024-01-15T08:30:45Z"},
+		{"ISO8601", "2024-01-15 10:30:45.123 [INFO] ok", "2024-01-15T10:30:45Z"},
+		{"ISO8601", "2024-01-15T10:30:45+0000 [INFO] ok", "2024-01-15T10:30:45Z"},
+		{"CommonLog", `127.0.0.1 - - [15/Jan/2024:10:30:45 +0000] "GET /"`, "2024-01-15T10:30:45Z"},
+		{"Syslog", "Jan  5 10:30:45 host sshd[1]: accepted", "0000-01-05T10:30:45Z"},
+		{"CompactDateTime", "20240115103045 job done", "2024-01-15T10:30:45Z"},
+		{"UnixTime", "1705314645 job done", "2024-01-15T10:30:45Z"},
+	}
+	for _, tt := range tests {
+		at, _, ok := parseLogTimestamp(tt.pattern, tt.line)
+		require.True(t, ok, tt.line)
+		require.Equal(t, tt.want, at.UTC().Format(time.RFC3339), tt.line)
+	}
+
+	_, _, ok := parseLogTimestamp("CompactDate", "20240115 job done")
+	require.False(t, ok, "dates without a time are not parsed")
+	_, _, ok = parseLogTimestamp("ISO8601", "2024-13-45 10:30:45 bad date")
+	require.False(t, ok)
+}
+
+func TestBuildLogTimeline_WidensBuckets(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	for hour := range 48 {
+		lines = append(lines, fmt.Sprintf("2024-01-%02d %02d:15:00 [INFO] tick", 15+hour/24, hour%24))
+	}
+	counts := map[string]int{}
+	countTimestampPatterns(lines, counts)
+	tl := buildLogTimeline(lines, counts)
+	require.NotNil(t, tl)
+	require.Equal(t, time.Hour, tl.width)
+	require.Len(t, tl.buckets, 48)
+	require.Zero(t, tl.spikes)
+
+	require.Nil(t, buildLogTimeline([]string{"no timestamps here"}, map[string]int{}))
+}
+
 // TestLogsExplorer_GoldenEnhancement tests golden file output for enhancement profile.
 func TestLogsExplorer_GoldenEnhancement(t *testing.T) {
 	t.Parallel()
@@ -997,3 +1172,126 @@ func TestLogsExplorer_GoldenParity(t *testing.T) {
 
 	golden.RequireEqual(t, []byte(result.Summary))
 }
+
+func TestQueryLogs(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(strings.Join([]string{
+		"2024-01-15 10:00:01 INFO server started on :8080",
+		"2024-01-15 10:00:30 WARN slow query took 1200ms",
+		"2024-01-15 10:01:05 ERROR request 1017 failed",
+		"java.lang.IllegalStateException: pool closed",
+		"\tat com.example.Pool.get(Pool.java:42)",
+		"\tat com.example.Handler.serve(Handler.java:7)",
+		"2024-01-15 10:02:10 ERROR request 1023 failed",
+		"2024-01-15 11:15:00 INFO shutting down",
+	}, "\n"))
+
+	entries := ParseLogEntries(content)
+	require.Len(t, entries, 5)
+	require.Equal(t, 3, entries[2].Line)
+	require.Equal(t, "ERROR", entries[2].Level)
+	require.Equal(t, 2, entries[2].Frames)
+	require.Equal(t, time.Date(2024, 1, 15, 10, 1, 5, 0, time.UTC), entries[2].Time)
+
+	t.Run("level", func(t *testing.T) {
+		t.Parallel()
+		result := QueryLogs(content, LogQuery{Levels: []string{"[error]"}})
+		require.Equal(t, 5, result.Total)
+		require.Equal(t, 2, result.Matched)
+		require.Equal(t, []int{3, 7}, []int{result.Entries[0].Line, result.Entries[1].Line})
+	})
+
+	t.Run("time range and limit", func(t *testing.T) {
+		t.Parallel()
+		since, err := ParseLogQueryTime("2024-01-15 10:00:30")
+		require.NoError(t, err)
+		until, err := ParseLogQueryTime("2024-01-15T11:00:00Z")
+		require.NoError(t, err)
+		result := QueryLogs(content, LogQuery{Since: since, Until: until, Limit: 2})
+		require.Equal(t, 3, result.Matched)
+		require.Len(t, result.Entries, 2)
+		require.Contains(t, result.Format(), "... and 1 more")
+	})
+
+	t.Run("pattern searches stack traces", func(t *testing.T) {
+		t.Parallel()
+		result := QueryLogs(content, LogQuery{Pattern: regexp.MustCompile(`Pool\.get`)})
+		require.Equal(t, 1, result.Matched)
+		require.Contains(t, result.Entries[0].Text, "pool closed")
+	})
+
+	t.Run("group by", func(t *testing.T) {
+		t.Parallel()
+		result := QueryLogs(content, LogQuery{GroupBy: LogGroupSignature, Levels: []string{"ERROR"}})
+		require.Len(t, result.Groups, 1)
+		require.Equal(t, 2, result.Groups[0].Count)
+
+		result = QueryLogs(content, LogQuery{GroupBy: LogGroupHour})
+		require.Equal(t, []LogGroup{
+			{Key: "2024-01-15 10:00", Count: 4, FirstLine: 1},
+			{Key: "2024-01-15 11:00", Count: 1, FirstLine: 8},
+		}, result.Groups)
+		require.Equal(t, "key,count,first_line\n2024-01-15 10:00,4,1\n2024-01-15 11:00,1,8\n", result.ExportCSV())
+	})
+
+	t.Run("export", func(t *testing.T) {
+		t.Parallel()
+		result := QueryLogs(content, LogQuery{Levels: []string{"WARN"}})
+		require.Equal(t, "line,timestamp,level,message\n2,2024-01-15 10:00:30,WARN,slow query took 1200ms\n", result.ExportCSV())
+
+		out, err := result.ExportJSON()
+		require.NoError(t, err)
+		require.Contains(t, out, `"matched": 1`)
+		require.Contains(t, out, `"time": "2024-01-15T10:00:30Z"`)
+
+		out, err = QueryLogs(content, LogQuery{Levels: []string{"TRACE"}}).ExportJSON()
+		require.NoError(t, err)
+		require.Contains(t, out, `"entries": []`)
+	})
+}
+
+func TestQueryLogs_SyslogIgnoresYear(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("Jan 15 10:00:00 host app: INFO up\nJan 15 12:00:00 host app: ERROR down\n")
+	since, err := ParseLogQueryTime("2024-01-15 11:00")
+	require.NoError(t, err)
+	result := QueryLogs(content, LogQuery{Since: since})
+	require.Equal(t, 1, result.Matched)
+	require.Equal(t, 2, result.Entries[0].Line)
+
+	_, err = ParseLogQueryTime("yesterday")
+	require.Error(t, err)
+}
+
+func TestLogTail(t *testing.T) {
+	t.Parallel()
+
+	tail := NewLogTail()
+	first := tail.Add([]byte("[INFO] up\n[ERROR] request 1234 failed\n[ERROR] request 5678 failed\njava.lang.IllegalStateException: boom\n\tat com.x.Y.run(Y.java:1)\n"))
+	require.Equal(t, 5, first.Lines)
+	require.Equal(t, map[string]int{"INFO": 1, "ERROR": 2}, first.Levels)
+	require.Len(t, first.New, 1)
+	require.Equal(t, 2, first.New[0].Count)
+	require.Equal(t, "ERROR", first.New[0].Level)
+	require.Contains(t, first.New[0].Sample, "request 1234 failed")
+	require.Empty(t, first.Recurring)
+
+	second := tail.Add([]byte("[ERROR] request 9999 failed\n[WARN] disk 91% full\n"))
+	require.Len(t, second.New, 1)
+	require.Equal(t, "WARN", second.New[0].Level)
+	require.Len(t, second.Recurring, 1)
+	require.Equal(t, 1, second.Recurring[0].Count)
+	require.Equal(t, 3, second.Recurring[0].Total)
+
+	out := second.Format()
+	require.Contains(t, out, "New lines: 2 (2 events)")
+	require.Contains(t, out, "Levels: ERROR 1, WARN 1")
+	require.Contains(t, out, "e.g. [WARN] disk 91% full")
+	require.Contains(t, out, "1 new (3 total)")
+
+	empty := tail.Add(nil)
+	require.Zero(t, empty.Lines)
+	require.Contains(t, empty.Format(), "No new error signatures.")
+}
@@ -0,0 +1,249 @@
+package explorer
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxRateBuckets caps the rate buckets a log spans; longer logs get
+	// wider buckets.
+	maxRateBuckets = 60
+	// errorSpikeFactor is how many times the mean errors per bucket a
+	// bucket needs to be flagged as an error spike.
+	errorSpikeFactor = 3
+	// minSpikeErrors is the fewest errors an error spike has.
+	minSpikeErrors = 3
+)
+
+// rateBucketWidths are the bucket widths tried, narrowest first.
+var rateBucketWidths = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// timestampLayouts are the layouts parsing the matches of each timestamp
+// pattern. Parsing accepts fractional seconds the layouts leave out.
+// CompactDate has no time of day and UnixTime is parsed as a number.
+var timestampLayouts = map[string][]string{
+	"RFC3339":         {time.RFC3339},
+	"ISO8601":         {"2006-01-02T15:04:05Z07:00", "2006-01-02T15:04:05Z0700", "2006-01-02T15:04:05"},
+	"CommonLog":       {"02/Jan/2006:15:04:05"},
+	"Syslog":          {"Jan 2 15:04:05"},
+	"CompactDateTime": {"20060102150405"},
+}
+
+// logTimeline is when the events of a log happened: its range, the
+// longest quiet gap and the event rate over time.
+type logTimeline struct {
+	pattern string
+	// first and last are the earliest and latest timestamps, firstRaw and
+	// lastRaw as written in the log.
+	first, last       time.Time
+	firstRaw, lastRaw string
+	stamped           int
+	// gapAfter is where the longest gap between consecutive events starts.
+	gap      time.Duration
+	gapAfter time.Time
+	width    time.Duration
+	buckets  []rateBucket
+	spikes   int
+}
+
+// rateBucket is the events and errors of one span of the log.
+type rateBucket struct {
+	start  time.Time
+	events int
+	errors int
+	spike  bool
+}
+
+// buildLogTimeline parses the timestamps of the most common parseable
+// pattern in tsPatternCounts and buckets the lines by time. It returns nil
+// when no line has a parseable timestamp.
+func buildLogTimeline(lines []string, tsPatternCounts map[string]int) *logTimeline {
+	pattern := parseableTimestampPattern(tsPatternCounts)
+	if pattern == "" {
+		return nil
+	}
+
+	type event struct {
+		at    time.Time
+		raw   string
+		error bool
+	}
+	var events []event
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		at, raw, ok := parseLogTimestamp(pattern, line)
+		if !ok {
+			continue
+		}
+		isError := slices.ContainsFunc(logLevels[0].patterns, func(p *regexp.Regexp) bool {
+			return p.MatchString(line)
+		})
+		events = append(events, event{at: at, raw: raw, error: isError})
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(events, func(a, b event) int { return a.at.Compare(b.at) })
+
+	tl := &logTimeline{
+		pattern:  pattern,
+		first:    events[0].at,
+		last:     events[len(events)-1].at,
+		firstRaw: events[0].raw,
+		lastRaw:  events[len(events)-1].raw,
+		stamped:  len(events),
+	}
+	for i := 1; i < len(events); i++ {
+		if gap := events[i].at.Sub(events[i-1].at); gap > tl.gap {
+			tl.gap, tl.gapAfter = gap, events[i-1].at
+		}
+	}
+
+	tl.width = rateBucketWidths[len(rateBucketWidths)-1]
+	for _, width := range rateBucketWidths {
+		if tl.last.Truncate(width).Sub(tl.first.Truncate(width))/width < maxRateBuckets {
+			tl.width = width
+			break
+		}
+	}
+	var errors int
+	for _, ev := range events {
+		start := ev.at.Truncate(tl.width)
+		if n := len(tl.buckets); n == 0 || !tl.buckets[n-1].start.Equal(start) {
+			tl.buckets = append(tl.buckets, rateBucket{start: start})
+		}
+		b := &tl.buckets[len(tl.buckets)-1]
+		b.events++
+		if ev.error {
+			b.errors++
+			errors++
+		}
+	}
+
+	// The mean counts the empty buckets too, so that errors bunched in a
+	// quiet log still stand out.
+	span := int(tl.last.Truncate(tl.width).Sub(tl.first.Truncate(tl.width))/tl.width) + 1
+	mean := float64(errors) / float64(span)
+	for i := range tl.buckets {
+		b := &tl.buckets[i]
+		if b.errors >= minSpikeErrors && float64(b.errors) >= errorSpikeFactor*mean {
+			b.spike = true
+			tl.spikes++
+		}
+	}
+	return tl
+}
+
+// parseableTimestampPattern returns the most common timestamp pattern in
+// counts that parseLogTimestamp can parse, or "" when there is none.
+func parseableTimestampPattern(counts map[string]int) string {
+	for _, name := range sortedTimestampPatternNames(counts) {
+		if _, ok := timestampLayouts[name]; ok || name == "UnixTime" {
+			return name
+		}
+	}
+	return ""
+}
+
+// parseLogTimestamp parses the first timestamp of the named pattern in
+// line, returning it with its text.
+func parseLogTimestamp(pattern, line string) (time.Time, string, bool) {
+	var re *regexp.Regexp
+	for _, ts := range timestampPatterns {
+		if ts.name == pattern {
+			re = ts.pattern
+			break
+		}
+	}
+	if re == nil {
+		return time.Time{}, "", false
+	}
+	m := re.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, "", false
+	}
+
+	if pattern == "UnixTime" {
+		secs, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return time.Time{}, "", false
+		}
+		return time.Unix(secs, 0).UTC(), m[1], true
+	}
+	raw := m[0]
+	value := raw
+	switch pattern {
+	case "ISO8601":
+		value = strings.Replace(value, " ", "T", 1)
+	case "Syslog":
+		value = strings.Join(strings.Fields(value), " ")
+	}
+	for _, layout := range timestampLayouts[pattern] {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at, raw, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// bucketLabel formats the start of a bucket. Syslog timestamps have no
+// year.
+func bucketLabel(t time.Time) string {
+	if t.Year() == 0 {
+		return t.Format("Jan 02 15:04")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// write writes the time range and the event rate by bucket.
+func (tl *logTimeline) write(sb *strings.Builder, totalLines int) {
+	duration := tl.last.Sub(tl.first)
+	sb.WriteString("\nTime range:\n")
+	fmt.Fprintf(sb, "  First: %s\n", tl.firstRaw)
+	fmt.Fprintf(sb, "  Last: %s\n", tl.lastRaw)
+	fmt.Fprintf(sb, "  Duration: %s\n", formatDuration(duration.Seconds()))
+	fmt.Fprintf(sb, "  Timestamped lines: %d of %d (%s)\n", tl.stamped, totalLines, tl.pattern)
+	fmt.Fprintf(sb, "  Average rate: %.1f lines/min\n", float64(tl.stamped)/max(duration.Minutes(), 1))
+	if tl.gap >= 2*tl.width {
+		fmt.Fprintf(sb, "  Longest gap: %s after %s\n", formatDuration(tl.gap.Seconds()), bucketLabel(tl.gapAfter))
+	}
+	fmt.Fprintf(sb, "  Error spikes: %d\n", tl.spikes)
+
+	minutes := tl.width.Minutes()
+	fmt.Fprintf(sb, "\nEvent rate (%s buckets):\n", strings.TrimSuffix(strings.TrimSuffix(tl.width.String(), "0s"), "0m"))
+	for i, b := range tl.buckets {
+		if i >= maxRateBuckets {
+			fmt.Fprintf(sb, "  %s\n", overflowMarker(OutputProfileEnhancement, len(tl.buckets)-maxRateBuckets, false))
+			break
+		}
+		fmt.Fprintf(sb, "  %s: %d lines", bucketLabel(b.start), b.events)
+		if minutes > 1 {
+			fmt.Fprintf(sb, " (%.1f/min)", float64(b.events)/minutes)
+		}
+		if b.errors > 0 {
+			fmt.Fprintf(sb, ", %d errors", b.errors)
+		}
+		if b.spike {
+			sb.WriteString(" (error spike)")
+		}
+		sb.WriteString("\n")
+	}
+}
@@ -0,0 +1,814 @@
+package explorer
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// BuildManifestExplorer explores build manifests (npm package.json, Maven
+// pom.xml and Gradle build scripts): declared dependencies with versions,
+// scripts or tasks, and plugins, instead of the generic JSON/XML structure.
+type BuildManifestExplorer struct {
+	formatterProfile OutputProfile
+}
+
+const (
+	// manifestMaxDetails caps the lines listed per section.
+	manifestMaxDetails = 200
+	// manifestScriptMaxRunes caps a script command in the summary.
+	manifestScriptMaxRunes = 100
+)
+
+// buildManifest is the tool-independent view of one manifest.
+type buildManifest struct {
+	tool       string // npm, maven or gradle
+	details    []string
+	deps       []manifestDependency
+	managed    []manifestDependency
+	scripts    []string
+	plugins    []string
+	modules    []string
+	repos      []string
+	properties []string
+	calls      []string
+	// scriptsTitle names the scripts section: npm scripts, Gradle tasks or
+	// Maven plugin goals.
+	scriptsTitle string
+}
+
+type manifestDependency struct {
+	name    string
+	version string
+	scope   string
+}
+
+func (d manifestDependency) String() string {
+	if d.version == "" {
+		return fmt.Sprintf("%s (%s)", d.name, d.scope)
+	}
+	return fmt.Sprintf("%s %s (%s)", d.name, d.version, d.scope)
+}
+
+// manifestHighlights maps dependency names, Maven/Gradle coordinate prefixes
+// and plugin ids to the framework they indicate.
+var manifestHighlights = []struct {
+	prefix string
+	label  string
+}{
+	{"react", "React"},
+	{"next", "Next.js"},
+	{"vue", "Vue"},
+	{"@angular/core", "Angular"},
+	{"svelte", "Svelte"},
+	{"express", "Express"},
+	{"@nestjs/core", "NestJS"},
+	{"typescript", "TypeScript"},
+	{"jest", "Jest"},
+	{"vitest", "Vitest"},
+	{"org.springframework.boot", "Spring Boot"},
+	{"io.quarkus", "Quarkus"},
+	{"io.micronaut", "Micronaut"},
+	{"io.ktor", "Ktor"},
+	{"org.jetbrains.kotlin", "Kotlin"},
+	{"org.junit", "JUnit"},
+	{"junit:", "JUnit"},
+	{"org.projectlombok", "Lombok"},
+	{"com.android", "Android"},
+}
+
+func (e *BuildManifestExplorer) CanHandle(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Base(path)) {
+	case "package.json", "pom.xml", "build.gradle", "build.gradle.kts":
+		return true
+	}
+	return false
+}
+
+func (e *BuildManifestExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Build manifest too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "manifest", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	var m *buildManifest
+	var d degradedExploration
+	var err error
+	switch strings.ToLower(name) {
+	case "package.json":
+		if m, err = parsePackageJSON(input.Content); err != nil {
+			d = jsonDegradation(input.Path, input.Content, err)
+		}
+	case "pom.xml":
+		if m, err = parsePOM(input.Content); err != nil {
+			d = pomDegradation(input.Content, err)
+		}
+	default:
+		if m, err = parseGradleBuild(input.Content); err != nil {
+			d = gradleDegradation(input.Content, err)
+		}
+	}
+	if err != nil {
+		return degradedTextResult("Build manifest: "+name, "manifest", input.Content, d), nil
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Build manifest: %s (%s)\n", name, m.tool)
+	for _, line := range m.details {
+		summary.WriteString(line + "\n")
+	}
+	scopes := make(map[string]int)
+	var scopeOrder []string
+	for _, dep := range m.deps {
+		if scopes[dep.scope] == 0 {
+			scopeOrder = append(scopeOrder, dep.scope)
+		}
+		scopes[dep.scope]++
+	}
+	if len(m.deps) > 0 {
+		parts := make([]string, 0, len(scopeOrder))
+		for _, scope := range scopeOrder {
+			parts = append(parts, fmt.Sprintf("%d %s", scopes[scope], scope))
+		}
+		fmt.Fprintf(&summary, "Declared dependencies: %d (%s)\n", len(m.deps), strings.Join(parts, ", "))
+	}
+	if highlights := manifestHighlightLabels(m); len(highlights) > 0 {
+		fmt.Fprintf(&summary, "Highlights: %s\n", strings.Join(highlights, ", "))
+	}
+
+	lines := make([]string, 0, len(m.deps))
+	for _, dep := range m.deps {
+		lines = append(lines, dep.String())
+	}
+	writeManifestSection(&summary, "Dependencies", lines)
+	writeManifestSection(&summary, m.scriptsTitle, m.scripts)
+	writeManifestSection(&summary, "Plugins", m.plugins)
+	writeManifestSection(&summary, "Modules", m.modules)
+
+	// EXCEED MODE: managed versions, repositories, properties, script
+	// chains and dependencies whose resolved version can drift.
+	if e.formatterProfile == OutputProfileEnhancement {
+		lines = nil
+		for _, dep := range m.managed {
+			lines = append(lines, dep.String())
+		}
+		writeManifestSection(&summary, "Managed dependencies", lines)
+		writeManifestSection(&summary, "Repositories", m.repos)
+		writeManifestSection(&summary, "Properties", m.properties)
+		writeManifestSection(&summary, "Script calls", m.calls)
+		writeManifestSection(&summary, "Unpinned dependencies", manifestUnpinned(m))
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "manifest",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// manifestHighlightLabels returns the frameworks indicated by dependencies
+// and plugins, in table order.
+func manifestHighlightLabels(m *buildManifest) []string {
+	names := make([]string, 0, len(m.deps)+len(m.plugins))
+	for _, dep := range m.deps {
+		names = append(names, dep.name)
+	}
+	names = append(names, m.plugins...)
+	var labels []string
+	for _, h := range manifestHighlights {
+		if slices.Contains(labels, h.label) {
+			continue
+		}
+		for _, name := range names {
+			// npm names match exactly; coordinates and plugin ids by prefix.
+			if name == h.prefix || (m.tool != "npm" && strings.HasPrefix(name, h.prefix)) {
+				labels = append(labels, h.label)
+				break
+			}
+		}
+	}
+	return labels
+}
+
+// manifestUnpinned lists dependencies whose version is open-ended, a
+// snapshot, or resolved outside the registry.
+func manifestUnpinned(m *buildManifest) []string {
+	var lines []string
+	for _, dep := range m.deps {
+		v := strings.TrimSpace(dep.version)
+		lower := strings.ToLower(v)
+		reason := ""
+		switch m.tool {
+		case "npm":
+			switch {
+			case v == "" || v == "*" || lower == "latest" || lower == "x":
+				reason = "any version"
+			case strings.HasPrefix(v, ">") && !strings.Contains(v, "<"):
+				reason = "no upper bound"
+			case strings.HasPrefix(lower, "git") || strings.HasPrefix(lower, "http"):
+				reason = "fetched from a URL"
+			case strings.HasPrefix(lower, "file:") || strings.HasPrefix(lower, "link:"):
+				reason = "local path"
+			}
+		default:
+			switch {
+			case strings.HasSuffix(lower, "-snapshot"):
+				reason = "snapshot"
+			case strings.Contains(v, "+") || strings.HasPrefix(lower, "latest") || lower == "release":
+				reason = "dynamic version"
+			case strings.HasPrefix(v, "[") || strings.HasPrefix(v, "("):
+				reason = "version range"
+			case strings.Contains(v, "${"):
+				reason = "unresolved property"
+			}
+		}
+		if reason != "" {
+			lines = append(lines, fmt.Sprintf("%s %s (%s)", dep.name, v, reason))
+		}
+	}
+	return lines
+}
+
+func writeManifestSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), manifestMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+}
+
+// --- npm ---
+
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Private              bool              `json:"private"`
+	Type                 string            `json:"type"`
+	PackageManager       string            `json:"packageManager"`
+	Main                 string            `json:"main"`
+	Module               string            `json:"module"`
+	Types                string            `json:"types"`
+	Bin                  json.RawMessage   `json:"bin"`
+	Engines              map[string]string `json:"engines"`
+	Workspaces           json.RawMessage   `json:"workspaces"`
+	Scripts              map[string]string `json:"scripts"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	Overrides            map[string]any    `json:"overrides"`
+}
+
+// npmRunPattern matches a script invoking another script.
+var npmRunPattern = regexp.MustCompile(`\b(?:npm run|pnpm(?: run)?|yarn(?: run)?|bun run)\s+([\w:.-]+)`)
+
+func parsePackageJSON(content []byte) (*buildManifest, error) {
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+	m := &buildManifest{tool: "npm", scriptsTitle: "Scripts"}
+
+	project := cmp.Or(pkg.Name, "(unnamed)")
+	if pkg.Version != "" {
+		project += "@" + pkg.Version
+	}
+	if pkg.Private {
+		project += " (private)"
+	}
+	m.details = append(m.details, "Package: "+project)
+	if pkg.Type != "" {
+		m.details = append(m.details, "Module type: "+pkg.Type)
+	}
+	if pkg.PackageManager != "" {
+		m.details = append(m.details, "Package manager: "+pkg.PackageManager)
+	}
+	if len(pkg.Engines) > 0 {
+		var engines []string
+		for _, name := range sortedKeys(pkg.Engines) {
+			engines = append(engines, name+" "+pkg.Engines[name])
+		}
+		m.details = append(m.details, "Engines: "+strings.Join(engines, ", "))
+	}
+	var entries []string
+	for _, entry := range []struct{ key, value string }{{"main", pkg.Main}, {"module", pkg.Module}, {"types", pkg.Types}} {
+		if entry.value != "" {
+			entries = append(entries, entry.key+" "+entry.value)
+		}
+	}
+	var bin string
+	var bins map[string]string
+	if json.Unmarshal(pkg.Bin, &bin) == nil && bin != "" {
+		entries = append(entries, "bin "+bin)
+	} else if json.Unmarshal(pkg.Bin, &bins) == nil {
+		for _, name := range sortedKeys(bins) {
+			entries = append(entries, "bin "+name+" -> "+bins[name])
+		}
+	}
+	if len(entries) > 0 {
+		m.details = append(m.details, "Entry points: "+strings.Join(entries, ", "))
+	}
+
+	for _, group := range []struct {
+		scope string
+		deps  map[string]string
+	}{
+		{"runtime", pkg.Dependencies},
+		{"dev", pkg.DevDependencies},
+		{"peer", pkg.PeerDependencies},
+		{"optional", pkg.OptionalDependencies},
+	} {
+		for _, name := range sortedKeys(group.deps) {
+			m.deps = append(m.deps, manifestDependency{name: name, version: group.deps[name], scope: group.scope})
+		}
+	}
+	for _, name := range sortedKeys(pkg.Overrides) {
+		m.managed = append(m.managed, manifestDependency{name: name, version: fmt.Sprint(pkg.Overrides[name]), scope: "override"})
+	}
+
+	for _, name := range sortedKeys(pkg.Scripts) {
+		cmd := strings.Join(strings.Fields(pkg.Scripts[name]), " ")
+		m.scripts = append(m.scripts, name+": "+truncateRunes(cmd, manifestScriptMaxRunes))
+		var targets []string
+		for _, match := range npmRunPattern.FindAllStringSubmatch(cmd, -1) {
+			if _, ok := pkg.Scripts[match[1]]; ok && !slices.Contains(targets, match[1]) {
+				targets = append(targets, match[1])
+			}
+		}
+		for _, hook := range []string{"pre" + name, "post" + name} {
+			if _, ok := pkg.Scripts[hook]; ok {
+				targets = append(targets, hook+" (hook)")
+			}
+		}
+		if len(targets) > 0 {
+			m.calls = append(m.calls, name+" -> "+strings.Join(targets, ", "))
+		}
+	}
+
+	var workspaces []string
+	var wsObject struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(pkg.Workspaces, &workspaces) != nil && json.Unmarshal(pkg.Workspaces, &wsObject) == nil {
+		workspaces = wsObject.Packages
+	}
+	m.modules = workspaces
+	return m, nil
+}
+
+// --- Maven ---
+
+type pomProject struct {
+	GroupID    string        `xml:"groupId"`
+	ArtifactID string        `xml:"artifactId"`
+	Version    string        `xml:"version"`
+	Packaging  string        `xml:"packaging"`
+	Parent     pomCoordinate `xml:"parent"`
+	Properties pomProperties `xml:"properties"`
+	Modules    []string      `xml:"modules>module"`
+	Deps       []pomDep      `xml:"dependencies>dependency"`
+	Managed    []pomDep      `xml:"dependencyManagement>dependencies>dependency"`
+	Plugins    []pomPlugin   `xml:"build>plugins>plugin"`
+	Repos      []struct {
+		ID  string `xml:"id"`
+		URL string `xml:"url"`
+	} `xml:"repositories>repository"`
+	Profiles []struct {
+		ID string `xml:"id"`
+	} `xml:"profiles>profile"`
+}
+
+type pomCoordinate struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+type pomDep struct {
+	pomCoordinate
+	Scope    string `xml:"scope"`
+	Type     string `xml:"type"`
+	Optional bool   `xml:"optional"`
+}
+
+type pomPlugin struct {
+	pomCoordinate
+	Executions []struct {
+		ID    string   `xml:"id"`
+		Phase string   `xml:"phase"`
+		Goals []string `xml:"goals>goal"`
+	} `xml:"executions>execution"`
+}
+
+// pomProperties keeps <properties> children in document order.
+type pomProperties [][2]string
+
+func (p *pomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			*p = append(*p, [2]string{t.Name.Local, strings.TrimSpace(value)})
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// pomPropertyPattern matches a ${property} reference.
+var pomPropertyPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+func parsePOM(content []byte) (*buildManifest, error) {
+	var pom pomProject
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return nil, err
+	}
+	m := &buildManifest{tool: "maven", scriptsTitle: "Plugin goals"}
+
+	props := map[string]string{
+		"project.groupId":        cmp.Or(pom.GroupID, pom.Parent.GroupID),
+		"project.artifactId":     pom.ArtifactID,
+		"project.version":        cmp.Or(pom.Version, pom.Parent.Version),
+		"project.parent.version": pom.Parent.Version,
+	}
+	for _, kv := range pom.Properties {
+		props[kv[0]] = kv[1]
+		m.properties = append(m.properties, kv[0]+" = "+kv[1])
+	}
+	resolve := func(s string) string {
+		// References may nest; a few passes resolve every defined one.
+		for range 5 {
+			next := pomPropertyPattern.ReplaceAllStringFunc(s, func(ref string) string {
+				if v, ok := props[ref[2:len(ref)-1]]; ok {
+					return v
+				}
+				return ref
+			})
+			if next == s {
+				break
+			}
+			s = next
+		}
+		return s
+	}
+
+	coord := func(c pomCoordinate) string {
+		return resolve(c.GroupID) + ":" + resolve(c.ArtifactID)
+	}
+	project := coord(pomCoordinate{GroupID: props["project.groupId"], ArtifactID: pom.ArtifactID})
+	if v := props["project.version"]; v != "" {
+		project += ":" + resolve(v)
+	}
+	m.details = append(m.details, fmt.Sprintf("Project: %s (%s)", project, cmp.Or(pom.Packaging, "jar")))
+	if pom.Parent.ArtifactID != "" {
+		m.details = append(m.details, "Parent: "+coord(pom.Parent)+":"+resolve(pom.Parent.Version))
+	}
+	if len(pom.Profiles) > 0 {
+		var ids []string
+		for _, p := range pom.Profiles {
+			ids = append(ids, p.ID)
+		}
+		m.details = append(m.details, "Profiles: "+strings.Join(ids, ", "))
+	}
+
+	managed := make(map[string]bool)
+	for _, dep := range pom.Managed {
+		scope := cmp.Or(dep.Scope, "compile")
+		if dep.Scope == "import" {
+			scope = "import BOM"
+		}
+		m.managed = append(m.managed, manifestDependency{name: coord(dep.pomCoordinate), version: resolve(dep.Version), scope: scope})
+		managed[coord(dep.pomCoordinate)] = true
+	}
+	for _, dep := range pom.Deps {
+		scope := cmp.Or(dep.Scope, "compile")
+		if dep.Optional {
+			scope += ", optional"
+		}
+		name := coord(dep.pomCoordinate)
+		version := resolve(dep.Version)
+		if version == "" && managed[name] {
+			scope += ", managed"
+		}
+		m.deps = append(m.deps, manifestDependency{name: name, version: version, scope: scope})
+	}
+
+	for _, p := range pom.Plugins {
+		if p.GroupID == "" {
+			p.GroupID = "org.apache.maven.plugins"
+		}
+		plugin := coord(p.pomCoordinate)
+		if v := resolve(p.Version); v != "" {
+			plugin += " " + v
+		}
+		m.plugins = append(m.plugins, plugin)
+		for _, exec := range p.Executions {
+			if len(exec.Goals) == 0 {
+				continue
+			}
+			line := resolve(p.ArtifactID) + ": " + strings.Join(exec.Goals, ", ")
+			if exec.Phase != "" {
+				line += " (phase " + exec.Phase + ")"
+			}
+			m.scripts = append(m.scripts, line)
+		}
+	}
+	m.modules = pom.Modules
+	for _, r := range pom.Repos {
+		m.repos = append(m.repos, fmt.Sprintf("%s %s", r.ID, r.URL))
+	}
+	return m, nil
+}
+
+// pomDegradation describes a pom.xml that failed to decode.
+func pomDegradation(content []byte, err error) degradedExploration {
+	size := int64(len(content))
+	d := degradedExploration{
+		Failed:   "POM decoding: " + err.Error(),
+		Progress: "no project element decoded",
+		Examined: size,
+		Size:     size,
+		NextSteps: []string{
+			"Run mvn validate to locate the malformed element",
+			"Read the raw content around the error with the view tool",
+		},
+	}
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		d.Examined = lineEndOffset(content, syntaxErr.Line)
+		d.Progress = fmt.Sprintf("well-formed up to line %d", syntaxErr.Line)
+	}
+	return d
+}
+
+// --- Gradle ---
+
+var (
+	gradleDependencyPattern = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*(?:(platform|enforcedPlatform)\s*\(\s*)?["']([^"'$]+(?:\$\{?[\w.]+\}?[^"']*)?)["']`)
+	gradleMapDepPattern     = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*group\s*[:=]\s*["']([^"']+)["']\s*,\s*name\s*[:=]\s*["']([^"']+)["'](?:\s*,\s*version\s*[:=]\s*["']([^"']+)["'])?`)
+	gradleProjectDepPattern = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*project\s*\(\s*(?:path\s*[:=]\s*)?["']([^"']+)["']`)
+	gradleCatalogDepPattern = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*(?:(platform|enforcedPlatform)\s*\(\s*)?(libs\.[\w.]+)`)
+	gradleKotlinDepPattern  = regexp.MustCompile(`^\s*(\w+)\s*\(\s*kotlin\(\s*"([\w-]+)"(?:\s*,\s*"([^"]+)")?`)
+	gradlePluginIDPattern   = regexp.MustCompile(`^\s*id\s*\(?\s*["']([^"']+)["']\s*\)?(?:\s+version\s*\(?\s*["']([^"']+)["']\s*\)?)?(\s+apply\s*\(?\s*false)?`)
+	gradlePluginKtPattern   = regexp.MustCompile(`^\s*kotlin\(\s*"([\w.-]+)"\s*\)(?:\s+version\s+"([^"]+)")?`)
+	gradlePluginAliasRe     = regexp.MustCompile(`^\s*alias\(\s*(libs\.plugins\.[\w.]+)\s*\)`)
+	gradlePluginBareRe      = regexp.MustCompile("^\\s*`?([a-z][\\w-]*)`?\\s*$")
+	gradleApplyPluginRe     = regexp.MustCompile(`apply\s*\(?\s*plugin\s*[:=]\s*["']([^"']+)["']`)
+	gradleTaskRegisterRe    = regexp.MustCompile(`tasks\.(register|named|create)(?:<[^>]+>)?\(\s*["']([\w-]+)["']`)
+	gradleTaskDeclRe        = regexp.MustCompile(`^\s*task(?:\s+|\s*\(\s*)["']?(\w+)`)
+	gradleTaskBlockRe       = regexp.MustCompile(`^\s*tasks\.(\w+)\s*\{`)
+	gradleRepoRe            = regexp.MustCompile(`\b(mavenCentral|mavenLocal|google|gradlePluginPortal|jcenter)\(\)`)
+	gradleRepoURLRe         = regexp.MustCompile(`url\s*[=(]?\s*(?:uri\(\s*)?["']([^"']+)["']`)
+	gradleAssignRe          = regexp.MustCompile(`^\s*(group|version|description)\s*=\s*["']([^"']+)["']`)
+	gradleJavaRe            = regexp.MustCompile(`(?:JavaLanguageVersion\.of\(\s*(\d+)\s*\)|jvmToolchain\(\s*(\d+)\s*\)|sourceCompatibility\s*=\s*(?:JavaVersion\.VERSION_)?['"]?([\d._]+))`)
+	gradleBlockNameRe       = regexp.MustCompile(`([\w.]+)\s*(?:\([^()]*\))?\s*$`)
+)
+
+// gradleSyntaxError is a brace or string error on a 1-based line.
+type gradleSyntaxError struct {
+	line int
+	msg  string
+}
+
+func (e *gradleSyntaxError) Error() string { return e.msg }
+
+// stripGradleComments blanks out comments, keeping strings and line breaks,
+// and checks that braces balance outside strings.
+func stripGradleComments(content string) (string, error) {
+	var out strings.Builder
+	line, depth := 1, 0
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(content) {
+				out.WriteByte(c)
+				i++
+				c = content[i]
+			} else if c == quote {
+				quote = 0
+			} else if c == '\n' {
+				return "", &gradleSyntaxError{line: line, msg: "unterminated string"}
+			}
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			if i < len(content) {
+				line++
+				out.WriteByte('\n')
+			}
+			continue
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				return "", &gradleSyntaxError{line: line, msg: "unterminated block comment"}
+			}
+			comment := content[i : i+2+end+2]
+			nl := strings.Count(comment, "\n")
+			line += nl
+			out.WriteString(strings.Repeat("\n", nl))
+			i += len(comment) - 1
+			continue
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth < 0 {
+				return "", &gradleSyntaxError{line: line, msg: "unmatched closing brace"}
+			}
+		}
+		if c == '\n' {
+			line++
+		}
+		out.WriteByte(c)
+	}
+	if depth > 0 {
+		return "", &gradleSyntaxError{line: line, msg: fmt.Sprintf("%d unclosed braces at end of file", depth)}
+	}
+	return out.String(), nil
+}
+
+func parseGradleBuild(content []byte) (*buildManifest, error) {
+	text, err := stripGradleComments(strings.ReplaceAll(string(content), "\r\n", "\n"))
+	if err != nil {
+		return nil, err
+	}
+	m := &buildManifest{tool: "gradle", scriptsTitle: "Tasks"}
+	var blocks []string
+	inBlock := func(name string) bool { return slices.Contains(blocks, name) }
+	var group, version, java string
+
+	for raw := range strings.SplitSeq(text, "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+		case len(blocks) == 0 && gradleAssignRe.MatchString(line):
+			match := gradleAssignRe.FindStringSubmatch(line)
+			switch match[1] {
+			case "group":
+				group = match[2]
+			case "version":
+				version = match[2]
+			}
+		case inBlock("plugins"):
+			m.plugins = appendGradlePlugin(m.plugins, line)
+		case inBlock("repositories"):
+			if match := gradleRepoRe.FindStringSubmatch(line); match != nil {
+				m.repos = append(m.repos, match[1])
+			} else if match := gradleRepoURLRe.FindStringSubmatch(line); match != nil {
+				m.repos = append(m.repos, match[1])
+			}
+		case inBlock("dependencies"):
+			if dep, ok := parseGradleDependency(line); ok {
+				if inBlock("buildscript") {
+					dep.scope = "buildscript " + dep.scope
+				}
+				m.deps = append(m.deps, dep)
+			}
+		}
+
+		if match := gradleApplyPluginRe.FindStringSubmatch(line); match != nil {
+			m.plugins = append(m.plugins, match[1])
+		}
+		if match := gradleTaskRegisterRe.FindStringSubmatch(line); match != nil {
+			kind := "registered"
+			if match[1] == "named" {
+				kind = "configured"
+			}
+			m.scripts = append(m.scripts, fmt.Sprintf("%s (%s)", match[2], kind))
+		} else if match := gradleTaskBlockRe.FindStringSubmatch(line); match != nil && match[1] != "configureEach" {
+			m.scripts = append(m.scripts, match[1]+" (configured)")
+		} else if match := gradleTaskDeclRe.FindStringSubmatch(line); match != nil && len(blocks) == 0 {
+			m.scripts = append(m.scripts, match[1]+" (registered)")
+		}
+		if match := gradleJavaRe.FindStringSubmatch(line); match != nil && java == "" {
+			java = cmp.Or(match[1], match[2], match[3])
+		}
+
+		// Track the enclosing named blocks for the next lines.
+		for i := 0; i < len(line); i++ {
+			switch line[i] {
+			case '{':
+				name := ""
+				if match := gradleBlockNameRe.FindStringSubmatch(line[:i]); match != nil {
+					name = match[1]
+				}
+				blocks = append(blocks, name)
+			case '}':
+				if len(blocks) > 0 {
+					blocks = blocks[:len(blocks)-1]
+				}
+			}
+		}
+	}
+
+	if group != "" || version != "" {
+		project := cmp.Or(group, "(no group)")
+		if version != "" {
+			project += " version " + version
+		}
+		m.details = append(m.details, "Project: "+project)
+	}
+	if java != "" {
+		m.details = append(m.details, "Java: "+java)
+	}
+	return m, nil
+}
+
+// appendGradlePlugin appends the plugin declared on a plugins block line.
+func appendGradlePlugin(plugins []string, line string) []string {
+	var id, version string
+	applied := true
+	if match := gradlePluginIDPattern.FindStringSubmatch(line); match != nil {
+		id, version, applied = match[1], match[2], match[3] == ""
+	} else if match := gradlePluginKtPattern.FindStringSubmatch(line); match != nil {
+		id, version = "org.jetbrains.kotlin."+match[1], match[2]
+	} else if match := gradlePluginAliasRe.FindStringSubmatch(line); match != nil {
+		id = match[1]
+	} else if match := gradlePluginBareRe.FindStringSubmatch(line); match != nil {
+		id = match[1]
+	} else {
+		return plugins
+	}
+	if version != "" {
+		id += " " + version
+	}
+	if !applied {
+		id += " (not applied)"
+	}
+	return append(plugins, id)
+}
+
+// parseGradleDependency parses one dependencies block line.
+func parseGradleDependency(line string) (manifestDependency, bool) {
+	if match := gradleKotlinDepPattern.FindStringSubmatch(line); match != nil {
+		return manifestDependency{name: "org.jetbrains.kotlin:kotlin-" + match[2], version: match[3], scope: match[1]}, true
+	}
+	if match := gradleProjectDepPattern.FindStringSubmatch(line); match != nil {
+		return manifestDependency{name: "project " + match[2], scope: match[1]}, true
+	}
+	if match := gradleMapDepPattern.FindStringSubmatch(line); match != nil {
+		return manifestDependency{name: match[2] + ":" + match[3], version: match[4], scope: match[1]}, true
+	}
+	if match := gradleDependencyPattern.FindStringSubmatch(line); match != nil && strings.Contains(match[3], ":") {
+		parts := strings.SplitN(match[3], ":", 3)
+		dep := manifestDependency{name: parts[0] + ":" + parts[1], scope: match[1]}
+		if len(parts) == 3 {
+			dep.version = parts[2]
+		}
+		if match[2] != "" {
+			dep.scope += " " + match[2]
+		}
+		return dep, true
+	}
+	if match := gradleCatalogDepPattern.FindStringSubmatch(line); match != nil {
+		dep := manifestDependency{name: match[3], scope: match[1]}
+		if match[2] != "" {
+			dep.scope += " " + match[2]
+		}
+		return dep, true
+	}
+	return manifestDependency{}, false
+}
+
+// gradleDegradation describes a build script whose braces or strings do
+// not balance.
+func gradleDegradation(content []byte, err error) degradedExploration {
+	line := 0
+	var syntaxErr *gradleSyntaxError
+	if errors.As(err, &syntaxErr) {
+		line = syntaxErr.line
+	}
+	return degradedExploration{
+		Failed:   fmt.Sprintf("Gradle script scanning at line %d: %v", line, err),
+		Progress: fmt.Sprintf("scanned %d of %d lines", line, strings.Count(string(content), "\n")+1),
+		Examined: lineEndOffset(content, line),
+		Size:     int64(len(content)),
+		NextSteps: []string{
+			"Run gradle help to have Gradle report the script error",
+			"Read the raw content around the error with the view tool",
+		},
+	}
+}
@@ -0,0 +1,337 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPackageJSON = `{
+  "name": "@acme/web",
+  "version": "2.3.0",
+  "private": true,
+  "type": "module",
+  "packageManager": "pnpm@9.1.0",
+  "engines": {"node": ">=20"},
+  "main": "dist/index.js",
+  "workspaces": ["packages/*", "apps/*"],
+  "scripts": {
+    "build": "tsc -p . && vite build",
+    "prebuild": "rimraf dist",
+    "test": "vitest run",
+    "ci": "pnpm run build && pnpm run test"
+  },
+  "dependencies": {
+    "react": "^18.2.0",
+    "next": "14.1.0",
+    "left-pad": "*",
+    "utils": "file:../utils"
+  },
+  "devDependencies": {
+    "typescript": "~5.4.0",
+    "vitest": "^1.5.0"
+  },
+  "peerDependencies": {"react-dom": ">=18"},
+  "overrides": {"semver": "7.5.4"}
+}
+`
+
+const testPOM = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>3.2.5</version>
+  </parent>
+  <groupId>com.acme</groupId>
+  <artifactId>orders</artifactId>
+  <version>1.4.0-SNAPSHOT</version>
+  <packaging>jar</packaging>
+  <properties>
+    <java.version>21</java.version>
+    <mapstruct.version>1.5.5.Final</mapstruct.version>
+  </properties>
+  <modules>
+    <module>orders-api</module>
+  </modules>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.testcontainers</groupId>
+        <artifactId>testcontainers-bom</artifactId>
+        <version>1.19.7</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-web</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.mapstruct</groupId>
+      <artifactId>mapstruct</artifactId>
+      <version>${mapstruct.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.acme</groupId>
+      <artifactId>shared</artifactId>
+      <version>[1.0,2.0)</version>
+    </dependency>
+    <dependency>
+      <groupId>org.testcontainers</groupId>
+      <artifactId>postgresql</artifactId>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.springframework.boot</groupId>
+        <artifactId>spring-boot-maven-plugin</artifactId>
+      </plugin>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.2.5</version>
+        <executions>
+          <execution>
+            <id>it</id>
+            <phase>integration-test</phase>
+            <goals><goal>test</goal></goals>
+          </execution>
+        </executions>
+      </plugin>
+    </plugins>
+  </build>
+  <repositories>
+    <repository>
+      <id>acme</id>
+      <url>https://repo.acme.example/maven</url>
+    </repository>
+  </repositories>
+</project>
+`
+
+const testGradleBuild = `plugins {
+    id("org.springframework.boot") version "3.2.5"
+    kotlin("jvm") version "1.9.23"
+    alias(libs.plugins.detekt)
+    ` + "`java-library`" + `
+}
+
+group = "com.acme"
+version = "0.1.0"
+
+java {
+    toolchain { languageVersion = JavaLanguageVersion.of(21) }
+}
+
+repositories {
+    mavenCentral()
+    maven { url = uri("https://repo.acme.example/maven") } // internal
+}
+
+/* Dependencies { are documented elsewhere */
+dependencies {
+    implementation(platform("org.springframework.boot:spring-boot-dependencies:3.2.5"))
+    implementation("org.springframework.boot:spring-boot-starter-web")
+    implementation(kotlin("reflect"))
+    implementation(project(":core"))
+    implementation(libs.jackson.kotlin)
+    runtimeOnly("com.acme:metrics:1.+")
+    testImplementation("org.junit.jupiter:junit-jupiter:5.10.2")
+}
+
+tasks.register<Copy>("bundle") {
+    from("build/libs")
+}
+
+tasks.test {
+    useJUnitPlatform()
+}
+`
+
+func TestBuildManifestExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &BuildManifestExplorer{}
+	require.True(t, e.CanHandle("web/package.json", nil))
+	require.True(t, e.CanHandle("pom.xml", nil))
+	require.True(t, e.CanHandle("build.gradle", nil))
+	require.True(t, e.CanHandle("app/build.gradle.kts", nil))
+	require.False(t, e.CanHandle("package-lock.json", nil))
+	require.False(t, e.CanHandle("settings.gradle.kts", nil))
+	require.False(t, e.CanHandle("config.xml", nil))
+}
+
+func TestBuildManifestExplorer_Explore_NPM(t *testing.T) {
+	t.Parallel()
+
+	e := &BuildManifestExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "package.json", Content: []byte(testPackageJSON)})
+	require.NoError(t, err)
+	require.Equal(t, "manifest", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Build manifest: package.json (npm)\n")
+	require.Contains(t, s, "Package: @acme/web@2.3.0 (private)\n")
+	require.Contains(t, s, "Package manager: pnpm@9.1.0\n")
+	require.Contains(t, s, "Engines: node >=20\n")
+	require.Contains(t, s, "Declared dependencies: 7 (4 runtime, 2 dev, 1 peer)\n")
+	require.Contains(t, s, "Highlights: React, Next.js, TypeScript, Vitest\n")
+	require.Contains(t, s, "Dependencies:\n"+
+		"  - left-pad * (runtime)\n"+
+		"  - next 14.1.0 (runtime)\n"+
+		"  - react ^18.2.0 (runtime)\n"+
+		"  - utils file:../utils (runtime)\n"+
+		"  - typescript ~5.4.0 (dev)\n"+
+		"  - vitest ^1.5.0 (dev)\n"+
+		"  - react-dom >=18 (peer)\n")
+	require.Contains(t, s, "Scripts:\n"+
+		"  - build: tsc -p . && vite build\n"+
+		"  - ci: pnpm run build && pnpm run test\n"+
+		"  - prebuild: rimraf dist\n"+
+		"  - test: vitest run\n")
+	require.Contains(t, s, "Modules:\n  - packages/*\n  - apps/*\n")
+
+	// Overrides pin transitive packages and are not declared dependencies.
+	require.NotContains(t, s, "semver")
+}
+
+func TestBuildManifestExplorer_Explore_NPMEnhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &BuildManifestExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "package.json", Content: []byte(testPackageJSON)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Managed dependencies:\n  - semver 7.5.4 (override)\n")
+	require.Contains(t, s, "Script calls:\n  - build -> prebuild (hook)\n  - ci -> build, test\n")
+	require.Contains(t, s, "Unpinned dependencies:\n"+
+		"  - left-pad * (any version)\n"+
+		"  - utils file:../utils (local path)\n"+
+		"  - react-dom >=18 (no upper bound)\n")
+}
+
+func TestBuildManifestExplorer_Explore_Maven(t *testing.T) {
+	t.Parallel()
+
+	e := &BuildManifestExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "pom.xml", Content: []byte(testPOM)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Build manifest: pom.xml (maven)\n")
+	require.Contains(t, s, "Project: com.acme:orders:1.4.0-SNAPSHOT (jar)\n")
+	require.Contains(t, s, "Parent: org.springframework.boot:spring-boot-starter-parent:3.2.5\n")
+	require.Contains(t, s, "Declared dependencies: 4 (3 compile, 1 test)\n")
+	require.Contains(t, s, "Highlights: Spring Boot\n")
+	require.Contains(t, s, "Dependencies:\n"+
+		"  - org.springframework.boot:spring-boot-starter-web (compile)\n"+
+		"  - org.mapstruct:mapstruct 1.5.5.Final (compile)\n"+
+		"  - com.acme:shared [1.0,2.0) (compile)\n"+
+		"  - org.testcontainers:postgresql (test)\n")
+	require.Contains(t, s, "Plugin goals:\n  - maven-surefire-plugin: test (phase integration-test)\n")
+	require.Contains(t, s, "Plugins:\n"+
+		"  - org.springframework.boot:spring-boot-maven-plugin\n"+
+		"  - org.apache.maven.plugins:maven-surefire-plugin 3.2.5\n")
+	require.Contains(t, s, "Modules:\n  - orders-api\n")
+	require.Contains(t, s, "Managed dependencies:\n  - org.testcontainers:testcontainers-bom 1.19.7 (import BOM)\n")
+	require.Contains(t, s, "Repositories:\n  - acme https://repo.acme.example/maven\n")
+	require.Contains(t, s, "Properties:\n  - java.version = 21\n  - mapstruct.version = 1.5.5.Final\n")
+	require.Contains(t, s, "Unpinned dependencies:\n  - com.acme:shared [1.0,2.0) (version range)\n")
+}
+
+func TestBuildManifestExplorer_Explore_Gradle(t *testing.T) {
+	t.Parallel()
+
+	e := &BuildManifestExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "build.gradle.kts", Content: []byte(testGradleBuild)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Build manifest: build.gradle.kts (gradle)\n")
+	require.Contains(t, s, "Project: com.acme version 0.1.0\nJava: 21\n")
+	require.Contains(t, s, "Highlights: Spring Boot, Kotlin, JUnit\n")
+	require.Contains(t, s, "Dependencies:\n"+
+		"  - org.springframework.boot:spring-boot-dependencies 3.2.5 (implementation platform)\n"+
+		"  - org.springframework.boot:spring-boot-starter-web (implementation)\n"+
+		"  - org.jetbrains.kotlin:kotlin-reflect (implementation)\n"+
+		"  - project :core (implementation)\n"+
+		"  - libs.jackson.kotlin (implementation)\n"+
+		"  - com.acme:metrics 1.+ (runtimeOnly)\n"+
+		"  - org.junit.jupiter:junit-jupiter 5.10.2 (testImplementation)\n")
+	require.Contains(t, s, "Tasks:\n  - bundle (registered)\n  - test (configured)\n")
+	require.Contains(t, s, "Plugins:\n"+
+		"  - org.springframework.boot 3.2.5\n"+
+		"  - org.jetbrains.kotlin.jvm 1.9.23\n"+
+		"  - libs.plugins.detekt\n"+
+		"  - java-library\n")
+	require.Contains(t, s, "Repositories:\n  - mavenCentral\n  - https://repo.acme.example/maven\n")
+	require.Contains(t, s, "Unpinned dependencies:\n  - com.acme:metrics 1.+ (dynamic version)\n")
+}
+
+func TestBuildManifestExplorer_Explore_GroovyGradle(t *testing.T) {
+	t.Parallel()
+
+	content := `apply plugin: 'java'
+
+dependencies {
+    compileOnly group: 'org.projectlombok', name: 'lombok', version: '1.18.32'
+    testImplementation 'junit:junit:4.13.2'
+}
+
+task integrationTest(type: Test) {
+    description = 'Runs integration tests.'
+}
+`
+	result, err := (&BuildManifestExplorer{}).Explore(context.Background(), ExploreInput{Path: "build.gradle", Content: []byte(content)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Highlights: JUnit, Lombok\n")
+	require.Contains(t, s, "  - org.projectlombok:lombok 1.18.32 (compileOnly)\n  - junit:junit 4.13.2 (testImplementation)\n")
+	require.Contains(t, s, "Tasks:\n  - integrationTest (registered)\n")
+	require.Contains(t, s, "Plugins:\n  - java\n")
+}
+
+func TestBuildManifestExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		path    string
+		content string
+		failed  string
+	}{
+		{path: "build.gradle", content: "dependencies {\n  implementation 'a:b:1'\n", failed: "Gradle script scanning at line 3: 1 unclosed braces at end of file"},
+		{path: "build.gradle", content: "}\n", failed: "Gradle script scanning at line 1: unmatched closing brace"},
+		{path: "build.gradle.kts", content: "version = \"1.0\n", failed: "Gradle script scanning at line 1: unterminated string"},
+		{path: "pom.xml", content: "<project>\n<dependencies>\n</project>\n", failed: "POM decoding: XML syntax error on line 3"},
+		{path: "package.json", content: `{"name": `, failed: "JSON decoding"},
+	} {
+		result, err := (&BuildManifestExplorer{}).Explore(context.Background(), ExploreInput{Path: tt.path, Content: []byte(tt.content)})
+		require.NoError(t, err)
+		require.Regexp(t, degradedBlockPattern, result.Summary)
+		require.Contains(t, result.Summary, "Failed: "+tt.failed)
+	}
+}
+
+func TestBuildManifestExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		for path, content := range map[string]string{"package.json": testPackageJSON, "pom.xml": testPOM, "build.gradle.kts": testGradleBuild} {
+			result, err := registry.Explore(context.Background(), ExploreInput{Path: path, Content: []byte(content)})
+			require.NoError(t, err)
+			require.Equal(t, "manifest", result.ExplorerUsed)
+			require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Unpinned dependencies"))
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -32,15 +31,18 @@ func (e *MarkdownExplorer) Explore(ctx context.Context, input ExploreInput) (Exp
 		Summary:       result.summary,
 		ExplorerUsed:  "markdown",
 		TokenEstimate: estimateTokens(result.summary),
+		Facts:         result.facts,
 	}, nil
 }
 
 type markdownAnalysis struct {
 	summary string
+	facts   *Facts
 }
 
 func (e *MarkdownExplorer) analyzeMarkdown(content, filename string) markdownAnalysis {
 	var sb strings.Builder
+	facts := &Facts{}
 	fmt.Fprintf(&sb, "Markdown file: %s\n", filename)
 	fmt.Fprintf(&sb, "Size: %d bytes\n", len(content))
 
@@ -59,12 +61,16 @@ func (e *MarkdownExplorer) analyzeMarkdown(content, filename string) markdownAna
 	fmt.Fprintf(&sb, "Frontmatter: %v\n", hasFrontmatter)
 	if hasFrontmatter {
 		fmt.Fprintf(&sb, "Frontmatter keys: %d\n", keyCount)
+		facts.count("frontmatter_keys", keyCount)
 	}
 
 	// Content to analyze for headings, code blocks, links (exclude frontmatter)
 	var contentToAnalyze string
+	// lineOffset maps lines of contentToAnalyze back to the file.
+	lineOffset := 0
 	if hasFrontmatter && frontmatterEndOffset > 0 {
 		contentToAnalyze = content[frontmatterEndOffset:]
+		lineOffset = strings.Count(content[:frontmatterEndOffset], "\n")
 	} else {
 		contentToAnalyze = content
 	}
@@ -76,8 +82,17 @@ func (e *MarkdownExplorer) analyzeMarkdown(content, filename string) markdownAna
 		if h.level >= 1 && h.level <= 6 {
 			hCounts[h.level-1]++
 		}
+		facts.Sections = append(facts.Sections, CodeSection{
+			Name:      h.text,
+			Type:      fmt.Sprintf("h%d", h.level),
+			StartLine: h.line + lineOffset,
+		})
 	}
 	totalHeadings := len(headings)
+	for i, n := range hCounts {
+		facts.count(fmt.Sprintf("h%d", i+1), n)
+	}
+	facts.count("heading_total", totalHeadings)
 	sb.WriteString("\nHeading hierarchy:\n")
 	fmt.Fprintf(&sb, "  H1: %d\n", hCounts[0])
 	fmt.Fprintf(&sb, "  H2: %d\n", hCounts[1])
@@ -89,6 +104,7 @@ func (e *MarkdownExplorer) analyzeMarkdown(content, filename string) markdownAna
 
 	// Fenced code block language histogram
 	codeBlocks := e.extractFencedCodeBlocks(contentToAnalyze)
+	facts.count("code_blocks", len(codeBlocks))
 	langHist := make(map[string]int)
 	for _, cb := range codeBlocks {
 		lang := cb.lang
@@ -99,12 +115,7 @@ func (e *MarkdownExplorer) analyzeMarkdown(content, filename string) markdownAna
 	}
 	if len(langHist) > 0 {
 		sb.WriteString("\nFenced code blocks:\n")
-		langs := make([]string, 0, len(langHist))
-		for lang := range langHist {
-			langs = append(langs, lang)
-		}
-		sort.Strings(langs)
-		for _, lang := range langs {
+		for _, lang := range sortedKeys(langHist) {
 			fmt.Fprintf(&sb, "  %s: %d\n", lang, langHist[lang])
 		}
 	}
@@ -120,8 +131,12 @@ func (e *MarkdownExplorer) analyzeMarkdown(content, filename string) markdownAna
 	fmt.Fprintf(&sb, "  Reference-style links: %d\n", refLinks)
 	fmt.Fprintf(&sb, "  Autolinks (http/https URLs): %d\n", autolinks)
 	fmt.Fprintf(&sb, "  Reference definitions: %d\n", refDefs)
+	facts.count("inline_links", inlineLinks)
+	facts.count("reference_links", refLinks)
+	facts.count("autolinks", autolinks)
+	facts.count("reference_definitions", refDefs)
 
-	return markdownAnalysis{summary: sb.String()}
+	return markdownAnalysis{summary: sb.String(), facts: facts}
 }
 
 func (e *MarkdownExplorer) extractFrontmatter(content string) (found bool, frontmatter []byte) {
@@ -0,0 +1,233 @@
+package explorer
+
+import (
+	"cmp"
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DefaultMetrics collects the explorations of registries built by the LCM
+// runtime for the lifetime of the process.
+var DefaultMetrics = NewMetrics()
+
+// ExplorerStats are the counters of one explorer.
+type ExplorerStats struct {
+	Explorer     string
+	Explorations int64
+	// Fallbacks counts explorations that only a generic explorer (text,
+	// binary or fallback) handled.
+	Fallbacks int64
+	// ParseFailures counts explorations that ended in a degraded block.
+	ParseFailures int64
+	Duration      time.Duration
+	Bytes         int64
+}
+
+// AvgDuration returns the mean duration of an exploration, or 0 when none
+// were recorded.
+func (s ExplorerStats) AvgDuration() time.Duration {
+	if s.Explorations == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Explorations)
+}
+
+func (s *ExplorerStats) add(o ExplorerStats) {
+	s.Explorations += o.Explorations
+	s.Fallbacks += o.Fallbacks
+	s.ParseFailures += o.ParseFailures
+	s.Duration += o.Duration
+	s.Bytes += o.Bytes
+}
+
+// CoverageGap counts the files of one extension that only the final
+// FallbackExplorer handled.
+type CoverageGap struct {
+	// Extension is the lowercased extension without its dot, "(none)"
+	// for files without one and "(other)" past maxCoverageGaps.
+	Extension string
+	Count     int64
+}
+
+// maxCoverageGaps bounds the extensions counted apart, and so the series
+// exported through OTel.
+const maxCoverageGaps = 64
+
+// Metrics counts explorations per explorer in process. Totals cover every
+// recorded exploration; pending holds what was recorded since the last
+// TakePending, so a caller can persist deltas.
+type Metrics struct {
+	mu      sync.Mutex
+	totals  map[string]*ExplorerStats
+	pending map[string]*ExplorerStats
+	gaps    map[string]int64
+}
+
+// NewMetrics returns empty counters.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		totals:  make(map[string]*ExplorerStats),
+		pending: make(map[string]*ExplorerStats),
+		gaps:    make(map[string]int64),
+	}
+}
+
+// WithMetrics records every static exploration of the registry into m.
+func WithMetrics(m *Metrics) RegistryOption {
+	return func(r *Registry) {
+		r.metrics = m
+	}
+}
+
+// record counts one static exploration result of path. It is a no-op on
+// a nil Metrics.
+func (m *Metrics) record(result ExploreResult, path string, size int64, d time.Duration) {
+	if m == nil {
+		return
+	}
+	name := strings.TrimSpace(result.ExplorerUsed)
+	if name == "" {
+		name = "unknown"
+	}
+	delta := ExplorerStats{Explorer: name, Explorations: 1, Duration: d, Bytes: size}
+	if result.SpecificityTier == SpecificityGeneric {
+		delta.Fallbacks = 1
+	}
+	if strings.Contains(result.Summary, "\nDegraded exploration:\n") {
+		delta.ParseFailures = 1
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, counters := range []map[string]*ExplorerStats{m.totals, m.pending} {
+		s, ok := counters[name]
+		if !ok {
+			s = &ExplorerStats{Explorer: name}
+			counters[name] = s
+		}
+		s.add(delta)
+	}
+	if fallbackReason(result) == FallbackFinal {
+		ext := gapExtension(path)
+		if _, ok := m.gaps[ext]; !ok && len(m.gaps) >= maxCoverageGaps {
+			ext = "(other)"
+		}
+		m.gaps[ext]++
+	}
+}
+
+func gapExtension(path string) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if ext == "" {
+		return "(none)"
+	}
+	return ext
+}
+
+// CoverageGaps returns the fallback_final hits by extension, most frequent
+// first: the file types no explorer recognized.
+func (m *Metrics) CoverageGaps() []CoverageGap {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]CoverageGap, 0, len(m.gaps))
+	for ext, n := range m.gaps {
+		out = append(out, CoverageGap{Extension: ext, Count: n})
+	}
+	slices.SortFunc(out, func(a, b CoverageGap) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Extension, b.Extension)
+	})
+	return out
+}
+
+// Snapshot returns the totals, most explored first.
+func (m *Metrics) Snapshot() []ExplorerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedStats(m.totals)
+}
+
+// TakePending returns the counters recorded since the previous call and
+// resets them.
+func (m *Metrics) TakePending() []ExplorerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := sortedStats(m.pending)
+	clear(m.pending)
+	return out
+}
+
+func sortedStats(counters map[string]*ExplorerStats) []ExplorerStats {
+	out := make([]ExplorerStats, 0, len(counters))
+	for _, s := range counters {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b ExplorerStats) int {
+		if c := cmp.Compare(b.Explorations, a.Explorations); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Explorer, b.Explorer)
+	})
+	return out
+}
+
+// RegisterOTel exports the totals through observable counters on meter,
+// one series per explorer under the "explorer" attribute. Nothing leaves
+// the process unless the meter's provider has an exporter.
+func (m *Metrics) RegisterOTel(meter metric.Meter) (metric.Registration, error) {
+	explorations, err := meter.Int64ObservableCounter("crush.explorer.explorations",
+		metric.WithDescription("Files explored, by explorer"))
+	if err != nil {
+		return nil, err
+	}
+	fallbacks, err := meter.Int64ObservableCounter("crush.explorer.fallbacks",
+		metric.WithDescription("Explorations handled only by a generic explorer"))
+	if err != nil {
+		return nil, err
+	}
+	failures, err := meter.Int64ObservableCounter("crush.explorer.parse_failures",
+		metric.WithDescription("Explorations that ended degraded"))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64ObservableCounter("crush.explorer.duration",
+		metric.WithDescription("Time spent exploring"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	processed, err := meter.Int64ObservableCounter("crush.explorer.bytes",
+		metric.WithDescription("Bytes explored"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackFinal, err := meter.Int64ObservableCounter("crush.explorer.fallback_final",
+		metric.WithDescription("Files only the final fallback explorer handled, by extension"))
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, s := range m.Snapshot() {
+			attrs := metric.WithAttributes(attribute.String("explorer", s.Explorer))
+			o.ObserveInt64(explorations, s.Explorations, attrs)
+			o.ObserveInt64(fallbacks, s.Fallbacks, attrs)
+			o.ObserveInt64(failures, s.ParseFailures, attrs)
+			o.ObserveFloat64(duration, s.Duration.Seconds(), attrs)
+			o.ObserveInt64(processed, s.Bytes, attrs)
+		}
+		for _, g := range m.CoverageGaps() {
+			o.ObserveInt64(fallbackFinal, g.Count, metric.WithAttributes(attribute.String("extension", g.Extension)))
+		}
+		return nil
+	}, explorations, fallbacks, failures, duration, processed, fallbackFinal)
+}
@@ -0,0 +1,72 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestMetrics_Registry(t *testing.T) {
+	t.Parallel()
+
+	m := NewMetrics()
+	registry := NewRegistry(WithMetrics(m))
+	ctx := context.Background()
+
+	for _, input := range []ExploreInput{
+		{Path: "a.json", Content: []byte(`{"a": 1}`)},
+		{Path: "b.json", Content: []byte(`{"a": ], "b": 1`)},
+		{Path: "notes.txt", Content: []byte("plain text\n")},
+	} {
+		_, err := registry.Explore(ctx, input)
+		require.NoError(t, err)
+	}
+	large := []byte("alpha beta\n")
+	_, err := registry.ExploreStream(ctx, "huge.txt", bytes.NewReader(large), int64(len(large)))
+	require.NoError(t, err)
+
+	stats := m.Snapshot()
+	require.Len(t, stats, 2)
+	require.Equal(t, "json", stats[0].Explorer)
+	require.Equal(t, int64(2), stats[0].Explorations)
+	require.Equal(t, int64(1), stats[0].ParseFailures)
+	require.Equal(t, int64(0), stats[0].Fallbacks)
+	require.Equal(t, int64(8+15), stats[0].Bytes)
+	require.Equal(t, "text", stats[1].Explorer)
+	require.Equal(t, int64(2), stats[1].Explorations)
+	require.Equal(t, int64(2), stats[1].Fallbacks)
+	require.Equal(t, int64(0), stats[1].ParseFailures)
+	require.Equal(t, int64(11+11), stats[1].Bytes)
+	require.Equal(t, stats[1].Duration/2, stats[1].AvgDuration())
+
+	require.Equal(t, stats, m.TakePending())
+	require.Empty(t, m.TakePending())
+	_, err = registry.Explore(ctx, ExploreInput{Path: "c.json", Content: []byte(`[]`)})
+	require.NoError(t, err)
+	pending := m.TakePending()
+	require.Len(t, pending, 1)
+	require.Equal(t, int64(1), pending[0].Explorations)
+	require.Equal(t, int64(3), m.Snapshot()[0].Explorations)
+}
+
+func TestMetrics_NilAndZero(t *testing.T) {
+	t.Parallel()
+
+	var m *Metrics
+	m.record(ExploreResult{ExplorerUsed: "json"}, "a.json", 1, 0)
+	require.Zero(t, ExplorerStats{}.AvgDuration())
+
+	_, err := NewRegistry().Explore(context.Background(), ExploreInput{Path: "a.json", Content: []byte(`{}`)})
+	require.NoError(t, err)
+}
+
+func TestMetrics_RegisterOTel(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewMetrics().RegisterOTel(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+	require.NoError(t, reg.Unregister())
+}
@@ -0,0 +1,367 @@
+package explorer
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer/stdlib"
+)
+
+// NotebookExplorer explores Jupyter notebooks (nbformat 4, and the
+// worksheet layout of nbformat 3).
+type NotebookExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// notebookMaxOutline caps the number of markdown headings listed.
+const notebookMaxOutline = 40
+
+// notebookText is a multiline notebook string, stored either as one
+// string or as a list of lines.
+type notebookText string
+
+func (t *notebookText) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = notebookText(s)
+		return nil
+	}
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return err
+	}
+	*t = notebookText(strings.Join(lines, ""))
+	return nil
+}
+
+type notebookOutput struct {
+	OutputType string                     `json:"output_type"`
+	Text       notebookText               `json:"text"`
+	Data       map[string]json.RawMessage `json:"data"`
+	EName      string                     `json:"ename"`
+	EValue     string                     `json:"evalue"`
+}
+
+type notebookCell struct {
+	CellType       string           `json:"cell_type"`
+	Level          int              `json:"level"` // nbformat 3 heading cells
+	Source         notebookText     `json:"source"`
+	Input          notebookText     `json:"input"` // nbformat 3 code cells
+	Outputs        []notebookOutput `json:"outputs"`
+	ExecutionCount *int             `json:"execution_count"`
+}
+
+type notebook struct {
+	NBFormat      int `json:"nbformat"`
+	NBFormatMinor int `json:"nbformat_minor"`
+	Metadata      struct {
+		Kernelspec struct {
+			Name        string `json:"name"`
+			DisplayName string `json:"display_name"`
+			Language    string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+	Cells      []notebookCell `json:"cells"`
+	Worksheets []struct {
+		Cells []notebookCell `json:"cells"`
+	} `json:"worksheets"`
+}
+
+var (
+	notebookPythonImport = regexp.MustCompile(`(?m)^\s*(?:from\s+([\w.]+)\s+import\b|import\s+([\w.]+(?:\s*,\s*[\w.]+)*))`)
+	notebookRImport      = regexp.MustCompile(`(?m)\b(?:library|require)\(\s*["']?([\w.]+)`)
+	notebookJuliaImport  = regexp.MustCompile(`(?m)^\s*(?:using|import)\s+([\w.]+(?:\s*,\s*[\w.]+)*)`)
+	notebookPipInstall   = regexp.MustCompile(`(?m)^\s*[%!]\s*(?:pip|conda|mamba)\s+install\s+(.+)$`)
+	notebookHeading      = regexp.MustCompile(`(?m)^(#{1,6})\s+(.+?)\s*#*\s*$`)
+)
+
+func (e *NotebookExplorer) CanHandle(path string, content []byte) bool {
+	return strings.EqualFold(filepath.Ext(path), ".ipynb")
+}
+
+func (e *NotebookExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Jupyter notebook too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "notebook", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	var nb notebook
+	if err := json.Unmarshal(input.Content, &nb); err != nil {
+		d := jsonDegradation(input.Path, input.Content, err)
+		d.NextSteps = append(d.NextSteps, "Open and save the notebook in Jupyter to repair its JSON")
+		return degradedTextResult("Jupyter notebook: "+name, "notebook", input.Content, d), nil
+	}
+	cells := nb.Cells
+	for _, ws := range nb.Worksheets {
+		cells = append(cells, ws.Cells...)
+	}
+	language := notebookLanguage(nb)
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Jupyter notebook: %s\n", name)
+	if nb.NBFormat > 0 {
+		fmt.Fprintf(&summary, "Format: nbformat %d.%d\n", nb.NBFormat, nb.NBFormatMinor)
+	}
+	if ks := nb.Metadata.Kernelspec; ks.Name != "" || ks.DisplayName != "" {
+		fmt.Fprintf(&summary, "Kernel: %s\n", cmp.Or(ks.DisplayName, ks.Name))
+	}
+	if language != "" {
+		fmt.Fprintf(&summary, "Language: %s\n", strings.TrimSpace(language+" "+nb.Metadata.LanguageInfo.Version))
+	}
+	fmt.Fprintf(&summary, "Cells: %d\n", len(cells))
+
+	cellTypes := make(map[string]int)
+	for _, cell := range cells {
+		cellTypes[cmp.Or(cell.CellType, "unknown")]++
+	}
+	if len(cellTypes) > 0 {
+		summary.WriteString("\nCell types:\n")
+		writeCounts(&summary, cellTypes, "")
+	}
+
+	imports := notebookImports(cells, language)
+	if len(imports) > 0 {
+		summary.WriteString("\nImports:\n")
+		for _, imp := range sortedKeys(imports) {
+			fmt.Fprintf(&summary, "  - %s\n", imp)
+		}
+	}
+
+	writeNotebookOutline(&summary, cells)
+	e.writeNotebookOutputs(&summary, cells)
+
+	// EXCEED MODE: execution state, dependency and error details.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeNotebookExecution(&summary, cells)
+		writeNotebookInstalls(&summary, cells)
+		if language == "python" && len(imports) > 0 {
+			std := 0
+			for _, imp := range sortedKeys(imports) {
+				if stdlib.IsPythonStdlib(imp) {
+					std++
+				}
+			}
+			summary.WriteString("\nImport categories:\n")
+			fmt.Fprintf(&summary, "  - Standard library: %d\n", std)
+			fmt.Fprintf(&summary, "  - Third-party: %d\n", len(imports)-std)
+		}
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "notebook",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// notebookLanguage returns the lowercase kernel language.
+func notebookLanguage(nb notebook) string {
+	lang := cmp.Or(nb.Metadata.LanguageInfo.Name, nb.Metadata.Kernelspec.Language)
+	if lang == "" && strings.HasPrefix(nb.Metadata.Kernelspec.Name, "python") {
+		lang = "python"
+	}
+	return strings.ToLower(lang)
+}
+
+// cellSource returns the source of a cell in either nbformat layout.
+func cellSource(cell notebookCell) string {
+	return cmp.Or(string(cell.Source), string(cell.Input))
+}
+
+// notebookImports aggregates the top-level modules imported by code cells.
+func notebookImports(cells []notebookCell, language string) map[string]bool {
+	imports := make(map[string]bool)
+	add := func(list string) {
+		for _, mod := range strings.Split(list, ",") {
+			mod = strings.TrimSpace(mod)
+			if head, _, _ := strings.Cut(mod, "."); head != "" {
+				imports[head] = true
+			}
+		}
+	}
+	for _, cell := range cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		src := cellSource(cell)
+		switch language {
+		case "r":
+			for _, m := range notebookRImport.FindAllStringSubmatch(src, -1) {
+				imports[m[1]] = true
+			}
+		case "julia":
+			for _, m := range notebookJuliaImport.FindAllStringSubmatch(src, -1) {
+				add(m[1])
+			}
+		default:
+			for _, m := range notebookPythonImport.FindAllStringSubmatch(src, -1) {
+				add(m[1] + m[2])
+			}
+		}
+	}
+	return imports
+}
+
+// writeNotebookOutline lists the markdown headings, numbered to keep their
+// order through formatting.
+func writeNotebookOutline(summary *strings.Builder, cells []notebookCell) {
+	var titles []string
+	var depths []int
+	prev := -1
+	add := func(level int, title string) {
+		if len(titles) == notebookMaxOutline {
+			return
+		}
+		// Skipped heading levels nest one step below the previous one.
+		depth := max(0, min(level-1, prev+1))
+		titles = append(titles, title)
+		depths = append(depths, depth)
+		prev = depth
+	}
+	for _, cell := range cells {
+		switch cell.CellType {
+		case "markdown":
+			for _, m := range notebookHeading.FindAllStringSubmatch(cellSource(cell), -1) {
+				add(len(m[1]), m[2])
+			}
+		case "heading":
+			if title := strings.TrimSpace(cellSource(cell)); title != "" {
+				add(cell.Level, title)
+			}
+		}
+	}
+	if len(titles) == 0 {
+		return
+	}
+	summary.WriteString("\nOutline:\n")
+	for i, number := range outlineNumbers(depths) {
+		fmt.Fprintf(summary, "  %s %s\n", number, titles[i])
+	}
+}
+
+// outputSize returns the bytes held by an output: its text stream plus
+// every rich representation.
+func outputSize(out notebookOutput) int {
+	size := len(out.Text)
+	for _, mime := range sortedKeys(out.Data) {
+		size += len(out.Data[mime])
+	}
+	return size
+}
+
+// writeNotebookOutputs summarizes the stored outputs.
+func (e *NotebookExplorer) writeNotebookOutputs(summary *strings.Builder, cells []notebookCell) {
+	withOutputs, total := 0, 0
+	types := make(map[string]int)
+	mimes := make(map[string]int)
+	largestCell, largestSize := 0, 0
+	for i, cell := range cells {
+		if len(cell.Outputs) == 0 {
+			continue
+		}
+		withOutputs++
+		cellSize := 0
+		for _, out := range cell.Outputs {
+			types[out.OutputType]++
+			for _, mime := range sortedKeys(out.Data) {
+				mimes[mime]++
+			}
+			cellSize += outputSize(out)
+		}
+		total += cellSize
+		if cellSize > largestSize {
+			largestCell, largestSize = i+1, cellSize
+		}
+	}
+	if withOutputs == 0 {
+		return
+	}
+
+	summary.WriteString("\nOutputs:\n")
+	fmt.Fprintf(summary, "  - Cells with outputs: %d\n", withOutputs)
+	fmt.Fprintf(summary, "  - Total output size: %d bytes\n", total)
+	fmt.Fprintf(summary, "  - Largest output: cell %d (%d bytes)\n", largestCell, largestSize)
+
+	summary.WriteString("\nOutput types:\n")
+	writeCounts(summary, types, "")
+	if e.formatterProfile == OutputProfileEnhancement && len(mimes) > 0 {
+		summary.WriteString("\nRich output formats:\n")
+		writeCounts(summary, mimes, "")
+	}
+}
+
+// writeNotebookExecution reports how many code cells ran, whether they
+// ran in order, and the errors stored in outputs.
+func writeNotebookExecution(summary *strings.Builder, cells []notebookCell) {
+	code, executed, last := 0, 0, 0
+	inOrder := true
+	var errs []string
+	for i, cell := range cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		code++
+		if cell.ExecutionCount != nil {
+			executed++
+			if *cell.ExecutionCount < last {
+				inOrder = false
+			}
+			last = *cell.ExecutionCount
+		}
+		for _, out := range cell.Outputs {
+			if out.OutputType == "error" {
+				errs = append(errs, fmt.Sprintf("cell %d: %s: %s", i+1, out.EName, truncateSample(out.EValue, 120)))
+			}
+		}
+	}
+	if code == 0 {
+		return
+	}
+	summary.WriteString("\nExecution:\n")
+	fmt.Fprintf(summary, "  - Executed code cells: %d of %d\n", executed, code)
+	if executed > 1 {
+		fmt.Fprintf(summary, "  - Executed in order: %t\n", inOrder)
+	}
+	if len(errs) > 0 {
+		summary.WriteString("\nErrors:\n")
+		for _, e := range errs {
+			fmt.Fprintf(summary, "  - %s\n", e)
+		}
+	}
+}
+
+// writeNotebookInstalls lists packages installed from cells with pip,
+// conda or mamba magics.
+func writeNotebookInstalls(summary *strings.Builder, cells []notebookCell) {
+	pkgs := make(map[string]bool)
+	for _, cell := range cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		for _, m := range notebookPipInstall.FindAllStringSubmatch(cellSource(cell), -1) {
+			for _, field := range strings.Fields(m[1]) {
+				if !strings.HasPrefix(field, "-") {
+					pkgs[field] = true
+				}
+			}
+		}
+	}
+	if len(pkgs) == 0 {
+		return
+	}
+	summary.WriteString("\nInstalled in notebook:\n")
+	for _, pkg := range sortedKeys(pkgs) {
+		fmt.Fprintf(summary, "  - %s\n", pkg)
+	}
+}
@@ -0,0 +1,130 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testNotebook = `{
+ "nbformat": 4,
+ "nbformat_minor": 5,
+ "metadata": {
+  "kernelspec": {"name": "python3", "display_name": "Python 3", "language": "python"},
+  "language_info": {"name": "python", "version": "3.11.4"}
+ },
+ "cells": [
+  {"cell_type": "markdown", "source": ["# Sales analysis\n", "Quarterly numbers.\n"]},
+  {"cell_type": "code", "execution_count": 1, "source": ["%pip install -q seaborn\n", "import os, json\n", "import pandas as pd\n", "from sklearn.model_selection import train_test_split\n"], "outputs": []},
+  {"cell_type": "markdown", "source": "## Load data\n### Sources"},
+  {"cell_type": "code", "execution_count": 3, "source": "df = pd.read_csv('sales.csv')\ndf.head()", "outputs": [
+   {"output_type": "execute_result", "data": {"text/html": "<table></table>", "text/plain": "   a  b"}},
+   {"output_type": "stream", "name": "stdout", "text": ["loaded 120 rows\n"]}
+  ]},
+  {"cell_type": "code", "execution_count": 2, "source": "1/0", "outputs": [
+   {"output_type": "error", "ename": "ZeroDivisionError", "evalue": "division by zero", "traceback": []}
+  ]},
+  {"cell_type": "raw", "source": ""}
+ ]
+}`
+
+func TestNotebookExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &NotebookExplorer{}
+	require.True(t, e.CanHandle("analysis.ipynb", nil))
+	require.True(t, e.CanHandle("ANALYSIS.IPYNB", nil))
+	require.False(t, e.CanHandle("analysis.json", nil))
+}
+
+func TestNotebookExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &NotebookExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "sales.ipynb", Content: []byte(testNotebook)})
+	require.NoError(t, err)
+	require.Equal(t, "notebook", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Jupyter notebook: sales.ipynb\n")
+	require.Contains(t, s, "Format: nbformat 4.5\n")
+	require.Contains(t, s, "Kernel: Python 3\n")
+	require.Contains(t, s, "Language: python 3.11.4\n")
+	require.Contains(t, s, "Cells: 6\n")
+	require.Contains(t, s, "Imports:\n  - json\n  - os\n  - pandas\n  - sklearn\n")
+	require.Contains(t, s, "Outline:\n  1 Sales analysis\n  1.1 Load data\n  1.1.1 Sources\n")
+
+	// The pip install cell is a code cell, but not an import.
+	require.NotContains(t, s, "seaborn")
+}
+
+func TestNotebookExplorer_Explore_CellsAndOutputs(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		e := &NotebookExplorer{formatterProfile: profile}
+		result, err := e.Explore(context.Background(), ExploreInput{Path: "sales.ipynb", Content: []byte(testNotebook)})
+		require.NoError(t, err)
+
+		s := result.Summary
+		require.Contains(t, s, "Cell types:\n  - code: 3\n  - markdown: 2\n  - raw: 1\n", profile)
+		require.Contains(t, s, "Outputs:\n"+
+			"  - Cells with outputs: 2\n"+
+			"  - Total output size: 42 bytes\n"+
+			"  - Largest output: cell 4 (42 bytes)\n", profile)
+		require.Contains(t, s, "Output types:\n  - error: 1\n  - execute_result: 1\n  - stream: 1\n", profile)
+
+		// Execution order and the error raised are only summarized for
+		// enhancement.
+		if profile == OutputProfileParity {
+			require.NotContains(t, s, "Executed code cells:")
+			require.NotContains(t, s, "ZeroDivisionError")
+			continue
+		}
+		require.Contains(t, s, "Rich output formats:\n  - text/html: 1\n  - text/plain: 1\n")
+		require.Contains(t, s, "Execution:\n  - Executed code cells: 3 of 3\n  - Executed in order: false\n")
+		require.Contains(t, s, "Errors:\n  - cell 5: ZeroDivisionError: division by zero\n")
+	}
+}
+
+func TestNotebookExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &NotebookExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "sales.ipynb", Content: []byte(testNotebook)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Installed in notebook:\n  - seaborn\n")
+	require.Contains(t, s, "Import categories:\n  - Standard library: 2\n  - Third-party: 2\n")
+}
+
+func TestNotebookExplorer_Explore_NBFormat3(t *testing.T) {
+	t.Parallel()
+
+	content := `{"nbformat": 3, "nbformat_minor": 0, "metadata": {},
+ "worksheets": [{"cells": [
+  {"cell_type": "heading", "level": 1, "source": ["Legacy"]},
+  {"cell_type": "code", "language": "python", "input": ["import numpy\n"], "outputs": []}
+ ]}]}`
+	result, err := (&NotebookExplorer{}).Explore(context.Background(), ExploreInput{Path: "old.ipynb", Content: []byte(content)})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Cells: 2\n")
+	require.Contains(t, result.Summary, "Imports:\n  - numpy\n")
+	require.Contains(t, result.Summary, "Outline:\n  1 Legacy\n")
+}
+
+func TestNotebookExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "sales.ipynb", Content: []byte(testNotebook)})
+		require.NoError(t, err)
+		require.Equal(t, "notebook", result.ExplorerUsed)
+		require.Contains(t, result.Summary, "### Outline\n- 1 Sales analysis\n- 1.1 Load data\n- 1.1.1 Sources\n")
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Execution"))
+	}
+}
@@ -0,0 +1,100 @@
+package explorer
+
+import (
+	"context"
+	"time"
+)
+
+// FallbackReason explains why a generic explorer handled a file.
+type FallbackReason string
+
+const (
+	// FallbackNone is the reason of files a specialized or family
+	// explorer handled.
+	FallbackNone FallbackReason = ""
+	// FallbackGeneric means no specific explorer recognized the file, so
+	// the generic text or binary explorer handled it.
+	FallbackGeneric FallbackReason = "generic"
+	// FallbackSkipped means explorers that recognized the file were
+	// skipped on a timeout, input limit, open circuit or error.
+	FallbackSkipped FallbackReason = "skipped"
+	// FallbackFinal means only the final FallbackExplorer handled the
+	// file: nothing, not even the text or binary explorer, recognized it.
+	// These hits point at gaps in coverage.
+	FallbackFinal FallbackReason = "fallback_final"
+)
+
+// fallbackReason returns why result came from a generic explorer.
+func fallbackReason(result ExploreResult) FallbackReason {
+	switch {
+	case result.ExplorerUsed == "fallback":
+		return FallbackFinal
+	case result.SpecificityTier != SpecificityGeneric:
+		return FallbackNone
+	case len(result.Skipped) > 0:
+		return FallbackSkipped
+	default:
+		return FallbackGeneric
+	}
+}
+
+// ExploreEvent describes a finished exploration.
+type ExploreEvent struct {
+	Path string
+	// Explorer is the explorer chosen, as in ExploreResult.ExplorerUsed;
+	// empty when the exploration failed before one was.
+	Explorer string
+	Tier     SpecificityTier
+	// Duration covers the whole call, enhancement included.
+	Duration      time.Duration
+	TokenEstimate int
+	Fallback      FallbackReason
+	// Stream is set for ExploreStream calls.
+	Stream bool
+	Err    error
+}
+
+// Observer is told about each Explore and ExploreStream call of a
+// Registry, so a host can emit its own telemetry without wrapping every
+// call. Methods are called synchronously on the exploring goroutine, so
+// they must be cheap and safe for concurrent use.
+type Observer interface {
+	// ExploreStarted is called before path is explored.
+	ExploreStarted(ctx context.Context, path string)
+	// ExploreFinished is called once the exploration of ExploreStarted
+	// returns, whether it succeeded or not.
+	ExploreFinished(ctx context.Context, event ExploreEvent)
+}
+
+// WithObserver reports the registry's explorations to o.
+func WithObserver(o Observer) RegistryOption {
+	return func(r *Registry) {
+		r.observer = o
+	}
+}
+
+// observe runs explore between the observer's started and finished
+// calls. Without an observer it only runs explore.
+func (r *Registry) observe(ctx context.Context, path string, stream bool, explore func() (ExploreResult, error)) (ExploreResult, error) {
+	if r.observer == nil {
+		return explore()
+	}
+
+	r.observer.ExploreStarted(ctx, path)
+	start := time.Now()
+	result, err := explore()
+	event := ExploreEvent{
+		Path:     path,
+		Duration: time.Since(start),
+		Stream:   stream,
+		Err:      err,
+	}
+	if err == nil {
+		event.Explorer = result.ExplorerUsed
+		event.Tier = result.SpecificityTier
+		event.TokenEstimate = result.TokenEstimate
+		event.Fallback = fallbackReason(result)
+	}
+	r.observer.ExploreFinished(ctx, event)
+	return result, err
+}
@@ -0,0 +1,98 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	mu      sync.Mutex
+	started []string
+	events  []ExploreEvent
+}
+
+func (o *recordingObserver) ExploreStarted(_ context.Context, path string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.started = append(o.started, path)
+}
+
+func (o *recordingObserver) ExploreFinished(_ context.Context, event ExploreEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func TestRegistry_Observer(t *testing.T) {
+	t.Parallel()
+
+	obs := &recordingObserver{}
+	m := NewMetrics()
+	registry := NewRegistry(WithObserver(obs), WithMetrics(m))
+	ctx := context.Background()
+
+	unknown := []byte{0x00, 0x01, 0x02, 0x03, 0xfe}
+	for _, input := range []ExploreInput{
+		{Path: "a.json", Content: []byte(`{"a": 1}`)},
+		{Path: "notes.txt", Content: []byte("plain text\n")},
+		{Path: "blob.XYZ", Content: unknown},
+		{Path: "other.xyz", Content: unknown},
+		{Path: "noext", Content: unknown},
+	} {
+		_, err := registry.Explore(ctx, input)
+		require.NoError(t, err)
+	}
+	_, err := registry.ExploreStream(ctx, "big.json", bytes.NewReader([]byte(`{}`)), 2)
+	require.ErrorIs(t, err, ErrStreamUnsupported)
+
+	require.Equal(t, []string{"a.json", "notes.txt", "blob.XYZ", "other.xyz", "noext", "big.json"}, obs.started)
+	require.Len(t, obs.events, 6)
+
+	json := obs.events[0]
+	require.Equal(t, "json", json.Explorer)
+	require.Equal(t, SpecificityFamily, json.Tier)
+	require.Equal(t, FallbackNone, json.Fallback)
+	require.Positive(t, json.TokenEstimate)
+	require.Positive(t, json.Duration)
+
+	require.Equal(t, "text", obs.events[1].Explorer)
+	require.Equal(t, FallbackGeneric, obs.events[1].Fallback)
+	require.Equal(t, "fallback", obs.events[2].Explorer)
+	require.Equal(t, FallbackFinal, obs.events[2].Fallback)
+
+	stream := obs.events[5]
+	require.True(t, stream.Stream)
+	require.ErrorIs(t, stream.Err, ErrStreamUnsupported)
+	require.Empty(t, stream.Explorer)
+
+	require.Equal(t, []CoverageGap{{Extension: "xyz", Count: 2}, {Extension: "(none)", Count: 1}}, m.CoverageGaps())
+}
+
+func TestFallbackReason(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, FallbackNone, fallbackReason(ExploreResult{ExplorerUsed: "json", SpecificityTier: SpecificitySpecialized}))
+	require.Equal(t, FallbackGeneric, fallbackReason(ExploreResult{ExplorerUsed: "binary", SpecificityTier: SpecificityGeneric}))
+	require.Equal(t, FallbackSkipped, fallbackReason(ExploreResult{
+		ExplorerUsed:    "text",
+		SpecificityTier: SpecificityGeneric,
+		Skipped:         []ExplorerSkip{{Explorer: "JSONExplorer", Reason: SkipTimeout}},
+	}))
+	require.Equal(t, FallbackFinal, fallbackReason(ExploreResult{ExplorerUsed: "fallback", SpecificityTier: SpecificityGeneric}))
+}
+
+func TestMetrics_CoverageGapsBounded(t *testing.T) {
+	t.Parallel()
+
+	m := NewMetrics()
+	for i := range maxCoverageGaps + 3 {
+		m.record(ExploreResult{ExplorerUsed: "fallback", SpecificityTier: SpecificityGeneric}, "f."+string(rune('a'+i%26))+string(rune('a'+i/26)), 1, 0)
+	}
+	gaps := m.CoverageGaps()
+	require.Len(t, gaps, maxCoverageGaps+1)
+	require.Equal(t, CoverageGap{Extension: "(other)", Count: 3}, gaps[0])
+}
@@ -77,7 +77,9 @@ func (e *OfficeExplorer) Explore(ctx context.Context, input ExploreInput) (Explo
 	// Parse as ZIP.
 	r, err := zip.NewReader(bytes.NewReader(input.Content), int64(len(input.Content)))
 	if err != nil {
-		summary.WriteString("Error: unable to parse as ZIP archive\n")
+		d := zipDegradation(int64(len(input.Content)), err)
+		d.NextSteps = append(d.NextSteps, "Open the document in its editor and re-save it")
+		d.write(&summary)
 		result := summary.String()
 		return ExploreResult{
 			Summary:       result,
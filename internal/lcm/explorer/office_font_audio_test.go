@@ -133,7 +133,7 @@ func TestOfficeExplorer_CorruptFile(t *testing.T) {
 		Content: []byte("not a real zip file"),
 	})
 	require.NoError(t, err)
-	require.Contains(t, result.Summary, "Error: unable to parse as ZIP")
+	require.Contains(t, result.Summary, "Failed: ZIP central directory read")
 }
 
 func TestOfficeExplorer_ThroughRegistry(t *testing.T) {
@@ -0,0 +1,90 @@
+package explorer
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Summaries must be byte-for-byte reproducible: they are persisted, hashed
+// and compared against parity fixtures. Go map iteration order is random,
+// so explorers never range over a map while emitting output or picking a
+// first match. They go through the helpers below instead.
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// keysLongestFirst returns the keys of m ordered by descending length, then
+// alphabetically. Use it for first-match lookups where one key may be a
+// prefix or suffix of another, so the most specific key wins.
+func keysLongestFirst[V any](m map[string]V) []string {
+	keys := sortedKeys(m)
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	return keys
+}
+
+// countEntry is a key of a histogram with its count.
+type countEntry struct {
+	Key   string
+	Count int
+}
+
+// byCount returns the entries of a histogram ordered by descending count,
+// ties broken alphabetically.
+func byCount(counts map[string]int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for _, k := range sortedKeys(counts) {
+		entries = append(entries, countEntry{Key: k, Count: counts[k]})
+	}
+	slices.SortStableFunc(entries, func(a, b countEntry) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return entries
+}
+
+// writeCounts writes one "  - <key>: <count><suffix>" line per histogram
+// entry, most frequent first.
+func writeCounts(sb *strings.Builder, counts map[string]int, suffix string) {
+	for _, e := range byCount(counts) {
+		fmt.Fprintf(sb, "  - %s: %d%s\n", e.Key, e.Count, suffix)
+	}
+}
+
+// outlineNumbers returns hierarchical numbers ("1", "1.2") for outline
+// entries at the given depths, zero-padded per level so the entries keep
+// their document order when the formatter sorts a section.
+func outlineNumbers(depths []int) []string {
+	levels := 0
+	for _, d := range depths {
+		levels = max(levels, d+1)
+	}
+	counts := make([]int, levels)
+	widths := make([]int, levels)
+	step := func(depth int) {
+		counts[depth]++
+		clear(counts[depth+1:])
+	}
+	for _, d := range depths {
+		step(d)
+		widths[d] = max(widths[d], len(strconv.Itoa(counts[d])))
+	}
+
+	clear(counts)
+	numbers := make([]string, len(depths))
+	for i, d := range depths {
+		step(d)
+		parts := make([]string, d+1)
+		for level := range parts {
+			parts[level] = fmt.Sprintf("%0*d", widths[level], counts[level])
+		}
+		numbers[i] = strings.Join(parts, ".")
+	}
+	return numbers
+}
@@ -200,58 +200,18 @@ func (l *ParityFixtureLoader) LoadAllFixtures() (map[string][]byte, error) {
 
 	fixtures := make(map[string][]byte)
 
-	// Load language fixtures
-	for name, path := range index.Language {
-		content, err := LoadFixtureFile(l.cfg, path)
-		if err != nil {
-			return nil, err
-		}
-		fixtures[name] = content
-	}
-
-	// Load format fixtures
-	for name, path := range index.Format {
-		content, err := LoadFixtureFile(l.cfg, path)
-		if err != nil {
-			return nil, err
-		}
-		fixtures[name] = content
-	}
-
-	// Load other fixture categories
-	if len(index.Shell) > 0 {
-		for name, path := range index.Shell {
-			content, err := LoadFixtureFile(l.cfg, path)
-			if err != nil {
-				return nil, err
-			}
-			fixtures[name] = content
-		}
-	}
-
-	if len(index.Markdown) > 0 {
-		for name, path := range index.Markdown {
-			content, err := LoadFixtureFile(l.cfg, path)
-			if err != nil {
-				return nil, err
-			}
-			fixtures[name] = content
-		}
-	}
-
-	if len(index.Binary) > 0 {
-		for name, path := range index.Binary {
-			content, err := LoadFixtureFile(l.cfg, path)
-			if err != nil {
-				return nil, err
-			}
-			fixtures[name] = content
-		}
-	}
-
-	if len(index.Negative) > 0 {
-		for name, path := range index.Negative {
-			content, err := LoadFixtureFile(l.cfg, path)
+	// Categories are loaded in a fixed order, and each in key order, so
+	// the first missing fixture reported is always the same.
+	for _, category := range []map[string]string{
+		index.Language,
+		index.Format,
+		index.Shell,
+		index.Markdown,
+		index.Binary,
+		index.Negative,
+	} {
+		for _, name := range sortedKeys(category) {
+			content, err := LoadFixtureFile(l.cfg, category[name])
 			if err != nil {
 				return nil, err
 			}
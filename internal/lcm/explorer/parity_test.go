@@ -455,10 +455,10 @@ line 16
 line 17
 `
 
-	parityList := formatSummary(listOverflowRaw, OutputProfileParity)
-	parityRaw := formatSummary(rawOverflowRaw, OutputProfileParity)
-	enhList := formatSummary(listOverflowRaw, OutputProfileEnhancement)
-	enhRaw := formatSummary(rawOverflowRaw, OutputProfileEnhancement)
+	parityList := formatSummary(listOverflowRaw, OutputProfileParity, OutputLimits{})
+	parityRaw := formatSummary(rawOverflowRaw, OutputProfileParity, OutputLimits{})
+	enhList := formatSummary(listOverflowRaw, OutputProfileEnhancement, OutputLimits{})
+	enhRaw := formatSummary(rawOverflowRaw, OutputProfileEnhancement, OutputLimits{})
 
 	if err := verifyParityMarkerClasses(parityList); err != nil {
 		return fmt.Errorf("parity list marker class check failed: %w", err)
@@ -1526,3 +1526,39 @@ func TestParityFixtureDispatchBinaryAndNegative(t *testing.T) {
 		})
 	}
 }
+
+// TestFacts_MatchParityCounts checks that the counts of the B4 fixtures
+// agree with what the parity gate extracts from the summaries.
+func TestFacts_MatchParityCounts(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewDefaultParityFixtureConfig(".")
+	index, err := NewParityFixtureLoader(cfg).LoadIndex()
+	require.NoError(t, err)
+	fixtures := map[string]string{
+		"latex":       index.Format["latex"],
+		"logs":        index.Format["logs"],
+		"sqlite_seed": index.Format["sqlite_seed"],
+		"markdown":    index.Markdown["readme"],
+	}
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		for key, name := range fixtures {
+			raw, err := LoadFixtureFile(cfg, name)
+			require.NoError(t, err)
+			input, _, err := buildB4GateInputAndSpec(key, name, raw, profile)
+			require.NoError(t, err)
+
+			result, err := registry.Explore(context.Background(), input)
+			require.NoError(t, err)
+			require.NotNil(t, result.Facts, key)
+			for field, want := range extractB4ActualCounts(key, result.Summary) {
+				if field == "unique_index" {
+					continue
+				}
+				require.Equal(t, want, float64(result.Facts.Counts[field]), "%s %s (%s)", key, field, profile)
+			}
+		}
+	}
+}
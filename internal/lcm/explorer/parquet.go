@@ -0,0 +1,644 @@
+package explorer
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ParquetExplorer explores columnar data files: Parquet, Arrow IPC
+// (Feather v2) and Feather v1. Only the footer metadata is read (schema,
+// row groups or record batches, column statistics and compression codecs),
+// so ExploreStream summarizes files of any size with a few small reads.
+type ParquetExplorer struct {
+	formatterProfile OutputProfile
+}
+
+var parquetMagic = []byte("PAR1")
+
+const (
+	// parquetMaxFooter caps the footer read from disk.
+	parquetMaxFooter = 64 << 20
+	// parquetMaxStatLength is the maximum length of a displayed min/max.
+	parquetMaxStatLength = 40
+	// parquetRowGroupsShown is the number of row groups listed in
+	// enhancement output.
+	parquetRowGroupsShown = 10
+)
+
+var columnarExtensions = map[string]bool{
+	".parquet": true, ".pq": true,
+	".arrow": true, ".feather": true, ".ipc": true,
+}
+
+// Parquet enums, indexed by value.
+var (
+	parquetPhysicalTypes = []string{
+		"BOOLEAN", "INT32", "INT64", "INT96", "FLOAT", "DOUBLE",
+		"BYTE_ARRAY", "FIXED_LEN_BYTE_ARRAY",
+	}
+	parquetRepetitions    = []string{"required", "optional", "repeated"}
+	parquetConvertedTypes = []string{
+		"UTF8", "MAP", "MAP_KEY_VALUE", "LIST", "ENUM", "DECIMAL", "DATE",
+		"TIME_MILLIS", "TIME_MICROS", "TIMESTAMP_MILLIS", "TIMESTAMP_MICROS",
+		"UINT_8", "UINT_16", "UINT_32", "UINT_64", "INT_8", "INT_16",
+		"INT_32", "INT_64", "JSON", "BSON", "INTERVAL",
+	}
+	parquetCodecs = []string{
+		"UNCOMPRESSED", "SNAPPY", "GZIP", "LZO", "BROTLI", "LZ4", "ZSTD", "LZ4_RAW",
+	}
+	parquetEncodings = []string{
+		"PLAIN", "GROUP_VAR_INT", "PLAIN_DICTIONARY", "RLE", "BIT_PACKED",
+		"DELTA_BINARY_PACKED", "DELTA_LENGTH_BYTE_ARRAY", "DELTA_BYTE_ARRAY",
+		"RLE_DICTIONARY", "BYTE_STREAM_SPLIT",
+	}
+)
+
+// parquetLogicalTypes names the LogicalType union members, in field id
+// order.
+var parquetLogicalTypes = []struct {
+	id   int16
+	name string
+}{
+	{1, "STRING"}, {2, "MAP"}, {3, "LIST"}, {4, "ENUM"}, {5, "DECIMAL"},
+	{6, "DATE"}, {7, "TIME"}, {8, "TIMESTAMP"}, {10, "INTEGER"},
+	{11, "UNKNOWN"}, {12, "JSON"}, {13, "BSON"}, {14, "UUID"},
+	{15, "FLOAT16"}, {16, "VARIANT"}, {17, "GEOMETRY"}, {18, "GEOGRAPHY"},
+}
+
+// parquetNode is one schema element, in depth-first order.
+type parquetNode struct {
+	name       string
+	depth      int
+	group      bool
+	physical   int64
+	annotation string // logical or converted type, "" if none
+	repetition string
+	timeUnit   string // for TIMESTAMP columns: "ms", "us" or "ns"
+}
+
+// parquetColumnStats aggregates the statistics of one column across row
+// groups.
+type parquetColumnStats struct {
+	path      string
+	node      *parquetNode
+	nulls     int64
+	hasNulls  bool
+	min, max  []byte
+	distinct  int64
+	encodings map[string]bool
+}
+
+// parquetRowGroup is the size of one row group.
+type parquetRowGroup struct {
+	rows         int64
+	uncompressed int64
+	compressed   int64
+}
+
+// parquetMeta is what a Parquet footer describes.
+type parquetMeta struct {
+	version      int64
+	createdBy    string
+	rows         int64
+	footerSize   int64
+	nodes        []parquetNode
+	rowGroups    []parquetRowGroup
+	columns      []*parquetColumnStats
+	codecs       map[string]int
+	metadataKeys []string
+}
+
+func (e *ParquetExplorer) CanHandle(path string, content []byte) bool {
+	if columnarExtensions[strings.ToLower(filepath.Ext(path))] {
+		return true
+	}
+	return bytes.HasPrefix(content, parquetMagic) || bytes.HasPrefix(content, arrowMagic) || bytes.HasPrefix(content, featherMagic)
+}
+
+func (e *ParquetExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	return e.explore(input.Path, bytes.NewReader(input.Content), int64(len(input.Content)))
+}
+
+// ExploreStream summarizes the file at path read from r, which holds size
+// bytes. Only the trailer, the footer and, for Arrow files, the record
+// batch headers are read.
+func (e *ParquetExplorer) ExploreStream(ctx context.Context, path string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	return e.explore(path, r, size)
+}
+
+func (e *ParquetExplorer) explore(path string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	head := make([]byte, min(size, 8))
+	if _, err := r.ReadAt(head, 0); err != nil && !errors.Is(err, io.EOF) {
+		return ExploreResult{}, err
+	}
+
+	var summary strings.Builder
+	if bytes.HasPrefix(head, arrowMagic) || bytes.HasPrefix(head, featherMagic) {
+		e.exploreArrow(&summary, path, r, size)
+	} else {
+		e.exploreParquet(&summary, path, r, size)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "parquet",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+func (e *ParquetExplorer) exploreParquet(summary *strings.Builder, path string, r io.ReaderAt, size int64) {
+	fmt.Fprintf(summary, "Parquet file: %s\n", filepath.Base(path))
+	fmt.Fprintf(summary, "Size: %s\n", formatSize(uint64(size)))
+
+	meta, err := readParquetFooter(r, size)
+	if err != nil {
+		degradedExploration{
+			Failed:   "Parquet footer: " + err.Error(),
+			Progress: "no metadata read",
+			Examined: min(size, 8),
+			Size:     size,
+			NextSteps: []string{
+				"A file still being written has no footer yet; retry once the writer closes it",
+				"Inspect it with `parquet-tools meta` or pyarrow.parquet.read_metadata",
+			},
+		}.write(summary)
+		return
+	}
+
+	fmt.Fprintf(summary, "Format version: %d\n", meta.version)
+	if meta.createdBy != "" {
+		fmt.Fprintf(summary, "Created by: %s\n", meta.createdBy)
+	}
+	fmt.Fprintf(summary, "Rows: %d\n", meta.rows)
+	fmt.Fprintf(summary, "Row groups: %d\n", len(meta.rowGroups))
+	fmt.Fprintf(summary, "Columns: %d\n", len(meta.columns))
+
+	if len(meta.nodes) > 0 {
+		summary.WriteString("\nSchema:\n")
+		depths := make([]int, len(meta.nodes))
+		for i, n := range meta.nodes {
+			depths[i] = n.depth
+		}
+		for i, num := range outlineNumbers(depths) {
+			n := meta.nodes[i]
+			typ := "group"
+			if !n.group {
+				typ = nameOr(parquetPhysicalTypes, int(n.physical))
+			}
+			if n.annotation != "" {
+				typ += " (" + n.annotation + ")"
+			}
+			fmt.Fprintf(summary, "  %s %s: %s, %s\n", num, n.name, typ, n.repetition)
+		}
+	}
+
+	if len(meta.codecs) > 0 {
+		summary.WriteString("\nCompression codecs:\n")
+		writeCounts(summary, meta.codecs, " column chunks")
+	}
+
+	if len(meta.columns) > 0 && len(meta.rowGroups) > 0 {
+		summary.WriteString("\nColumn statistics:\n")
+		for _, col := range meta.columns {
+			var parts []string
+			if col.min != nil || col.max != nil {
+				parts = append(parts, "min "+parquetStatValue(col.node, col.min), "max "+parquetStatValue(col.node, col.max))
+			}
+			if col.hasNulls {
+				parts = append(parts, fmt.Sprintf("nulls %d", col.nulls))
+			}
+			if col.distinct > 0 {
+				parts = append(parts, fmt.Sprintf("distinct %d", col.distinct))
+			}
+			if len(parts) == 0 {
+				parts = append(parts, "no statistics")
+			}
+			fmt.Fprintf(summary, "  - %s: %s\n", col.path, strings.Join(parts, ", "))
+		}
+	}
+
+	// EXCEED MODE: physical layout and writer metadata.
+	if e.formatterProfile != OutputProfileEnhancement {
+		return
+	}
+	if len(meta.rowGroups) > 0 {
+		var compressed, uncompressed int64
+		for _, rg := range meta.rowGroups {
+			compressed += rg.compressed
+			uncompressed += rg.uncompressed
+		}
+		summary.WriteString("\nStorage:\n")
+		fmt.Fprintf(summary, "  - Footer: %s\n", formatSize(uint64(meta.footerSize)))
+		fmt.Fprintf(summary, "  - Uncompressed data: %s\n", formatSize(uint64(uncompressed)))
+		if compressed > 0 {
+			fmt.Fprintf(summary, "  - Compression ratio: %.1fx\n", float64(uncompressed)/float64(compressed))
+		}
+
+		summary.WriteString("\nRow group sizes:\n")
+		numbers := outlineNumbers(make([]int, len(meta.rowGroups)))
+		for i, rg := range meta.rowGroups[:min(len(meta.rowGroups), parquetRowGroupsShown)] {
+			fmt.Fprintf(summary, "  %s. %d rows, %s\n", numbers[i], rg.rows, formatSize(uint64(cmp.Or(rg.compressed, rg.uncompressed))))
+		}
+	}
+	encodings := make(map[string]int)
+	for _, col := range meta.columns {
+		for _, enc := range parquetEncodings {
+			if col.encodings[enc] {
+				encodings[enc]++
+			}
+		}
+	}
+	if len(encodings) > 0 {
+		summary.WriteString("\nEncodings:\n")
+		writeCounts(summary, encodings, " columns")
+	}
+	if len(meta.metadataKeys) > 0 {
+		summary.WriteString("\nMetadata keys:\n")
+		for _, key := range meta.metadataKeys {
+			fmt.Fprintf(summary, "  - %s\n", key)
+		}
+	}
+}
+
+func (e *ParquetExplorer) exploreArrow(summary *strings.Builder, path string, r io.ReaderAt, size int64) {
+	fmt.Fprintf(summary, "Arrow file: %s\n", filepath.Base(path))
+	fmt.Fprintf(summary, "Size: %s\n", formatSize(uint64(size)))
+
+	meta, err := readArrowFooter(r, size)
+	if err != nil {
+		degradedExploration{
+			Failed:   "Arrow footer: " + err.Error(),
+			Progress: "no metadata read",
+			Examined: min(size, 8),
+			Size:     size,
+			NextSteps: []string{
+				"Open it with pyarrow.ipc.open_file or pyarrow.feather.read_table",
+			},
+		}.write(summary)
+		return
+	}
+
+	fmt.Fprintf(summary, "Format: %s\n", meta.format)
+	if meta.version > 0 {
+		fmt.Fprintf(summary, "Format version: %d\n", meta.version)
+	}
+	if meta.batchesRead < meta.batches {
+		fmt.Fprintf(summary, "Rows: at least %d (first %d of %d record batches)\n", meta.rows, meta.batchesRead, meta.batches)
+	} else {
+		fmt.Fprintf(summary, "Rows: %d\n", meta.rows)
+	}
+	if meta.format != "Feather v1" {
+		fmt.Fprintf(summary, "Record batches: %d\n", meta.batches)
+	}
+	columns := 0
+	for _, f := range meta.fields {
+		if f.depth == 0 {
+			columns++
+		}
+	}
+	fmt.Fprintf(summary, "Columns: %d\n", columns)
+
+	if len(meta.fields) > 0 {
+		summary.WriteString("\nSchema:\n")
+		depths := make([]int, len(meta.fields))
+		for i, f := range meta.fields {
+			depths[i] = f.depth
+		}
+		for i, num := range outlineNumbers(depths) {
+			f := meta.fields[i]
+			nullable := "not null"
+			if f.nullable {
+				nullable = "nullable"
+			}
+			fmt.Fprintf(summary, "  %s %s: %s, %s\n", num, f.name, f.typ, nullable)
+		}
+	}
+
+	if len(meta.codecs) > 0 {
+		summary.WriteString("\nCompression codecs:\n")
+		writeCounts(summary, meta.codecs, " record batches")
+	}
+
+	// EXCEED MODE: dictionaries and writer metadata.
+	if e.formatterProfile != OutputProfileEnhancement {
+		return
+	}
+	if meta.dictionaries > 0 {
+		fmt.Fprintf(summary, "\nDictionary batches: %d\n", meta.dictionaries)
+	}
+	if len(meta.metadataKeys) > 0 {
+		summary.WriteString("\nMetadata keys:\n")
+		for _, key := range meta.metadataKeys {
+			fmt.Fprintf(summary, "  - %s\n", key)
+		}
+	}
+}
+
+// readParquetFooter reads and decodes the FileMetaData footer:
+// PAR1 ... <footer> <int32 footer length> PAR1.
+func readParquetFooter(r io.ReaderAt, size int64) (*parquetMeta, error) {
+	if size < 12 {
+		return nil, errors.New("file too short for a Parquet footer")
+	}
+	trailer := make([]byte, 8)
+	if _, err := r.ReadAt(trailer, size-8); err != nil {
+		return nil, err
+	}
+	switch string(trailer[4:]) {
+	case "PAR1":
+	case "PARE":
+		return nil, errors.New("footer is encrypted")
+	default:
+		return nil, errors.New("missing PAR1 trailer")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(trailer))
+	if footerLen <= 0 || footerLen > size-12 || footerLen > parquetMaxFooter {
+		return nil, fmt.Errorf("invalid footer length %d", footerLen)
+	}
+	buf := make([]byte, footerLen)
+	if _, err := r.ReadAt(buf, size-8-footerLen); err != nil {
+		return nil, err
+	}
+	fileMeta, err := (&thriftReader{buf: buf}).readStruct()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &parquetMeta{footerSize: footerLen, codecs: make(map[string]int)}
+	meta.version, _ = fileMeta.int(1)
+	meta.rows, _ = fileMeta.int(3)
+	meta.createdBy = fileMeta.string(6)
+	for _, kv := range fileMeta.structs(5) {
+		meta.metadataKeys = append(meta.metadataKeys, kv.string(1))
+	}
+
+	meta.nodes = parquetSchema(fileMeta.structs(2))
+	leaves := parquetLeafPaths(meta.nodes)
+	byPath := make(map[string]*parquetColumnStats, len(leaves))
+	for _, leaf := range leaves {
+		col := &parquetColumnStats{path: leaf.path, node: leaf.node, encodings: make(map[string]bool)}
+		meta.columns = append(meta.columns, col)
+		byPath[leaf.path] = col
+	}
+
+	for _, rg := range fileMeta.structs(4) {
+		group := parquetRowGroup{}
+		group.rows, _ = rg.int(3)
+		group.uncompressed, _ = rg.int(2)
+		// total_compressed_size is optional; sum the chunks without it.
+		var hasCompressed bool
+		group.compressed, hasCompressed = rg.int(6)
+		for _, chunk := range rg.structs(1) {
+			cm := chunk.structField(3)
+			if cm == nil {
+				continue
+			}
+			codec, _ := cm.int(4)
+			meta.codecs[nameOr(parquetCodecs, int(codec))]++
+			if !hasCompressed {
+				n, _ := cm.int(7)
+				group.compressed += n
+			}
+
+			var parts []string
+			for _, p := range cm.list(3) {
+				if b, ok := p.([]byte); ok {
+					parts = append(parts, string(b))
+				}
+			}
+			col := byPath[strings.Join(parts, ".")]
+			if col == nil {
+				continue
+			}
+			for _, enc := range cm.list(2) {
+				if v, ok := enc.(int64); ok {
+					col.encodings[nameOr(parquetEncodings, int(v))] = true
+				}
+			}
+			col.merge(cm.structField(12))
+		}
+		meta.rowGroups = append(meta.rowGroups, group)
+	}
+	return meta, nil
+}
+
+// parquetSchema flattens the schema element list, written depth-first with
+// child counts, skipping the root. An explicit stack keeps a corrupt
+// footer from recursing without limit.
+func parquetSchema(elems []thriftStruct) []parquetNode {
+	if len(elems) == 0 {
+		return nil
+	}
+	rootChildren, _ := elems[0].int(5)
+	remaining := []int64{rootChildren}
+	var nodes []parquetNode
+	for _, el := range elems[1:] {
+		for len(remaining) > 0 && remaining[len(remaining)-1] <= 0 {
+			remaining = remaining[:len(remaining)-1]
+		}
+		if len(remaining) == 0 {
+			break
+		}
+		remaining[len(remaining)-1]--
+
+		children, _ := el.int(5)
+		physical, hasType := el.int(1)
+		repetition, _ := el.int(3)
+		node := parquetNode{
+			name:       el.string(4),
+			depth:      len(remaining) - 1,
+			group:      !hasType || children > 0,
+			physical:   physical,
+			repetition: nameOr(parquetRepetitions, int(repetition)),
+		}
+		node.annotation, node.timeUnit = parquetAnnotation(el)
+		nodes = append(nodes, node)
+		if children > 0 {
+			remaining = append(remaining, children)
+		}
+	}
+	return nodes
+}
+
+// parquetAnnotation returns the logical type of a schema element, falling
+// back to its legacy converted type, and the time unit of timestamps.
+func parquetAnnotation(el thriftStruct) (string, string) {
+	if logical := el.structField(10); logical != nil {
+		for _, lt := range parquetLogicalTypes {
+			if _, ok := logical[lt.id]; !ok {
+				continue
+			}
+			switch lt.name {
+			case "DECIMAL":
+				d := logical.structField(lt.id)
+				precision, _ := d.int(2)
+				scale, _ := d.int(1)
+				return fmt.Sprintf("DECIMAL(%d, %d)", precision, scale), ""
+			case "TIMESTAMP":
+				unit := logical.structField(lt.id).structField(2)
+				for id, name := range []string{"", "ms", "us", "ns"} {
+					if _, ok := unit[int16(id)]; ok && name != "" {
+						return "TIMESTAMP", name
+					}
+				}
+			}
+			return lt.name, ""
+		}
+	}
+	converted, ok := el.int(6)
+	if !ok {
+		return "", ""
+	}
+	name := nameOr(parquetConvertedTypes, int(converted))
+	switch name {
+	case "DECIMAL":
+		precision, _ := el.int(8)
+		scale, _ := el.int(7)
+		return fmt.Sprintf("DECIMAL(%d, %d)", precision, scale), ""
+	case "TIMESTAMP_MILLIS":
+		return name, "ms"
+	case "TIMESTAMP_MICROS":
+		return name, "us"
+	}
+	return name, ""
+}
+
+// parquetLeaf is a leaf column with its dotted path.
+type parquetLeaf struct {
+	path string
+	node *parquetNode
+}
+
+// parquetLeafPaths returns the leaf columns of a flattened schema.
+func parquetLeafPaths(nodes []parquetNode) []parquetLeaf {
+	var leaves []parquetLeaf
+	var stack []string
+	for i := range nodes {
+		n := &nodes[i]
+		stack = append(stack[:min(len(stack), n.depth)], n.name)
+		if !n.group {
+			leaves = append(leaves, parquetLeaf{path: strings.Join(stack, "."), node: n})
+		}
+	}
+	return leaves
+}
+
+// merge folds one column chunk's Statistics struct into the column.
+func (c *parquetColumnStats) merge(stats thriftStruct) {
+	if stats == nil {
+		return
+	}
+	if n, ok := stats.int(3); ok {
+		c.nulls += n
+		c.hasNulls = true
+	}
+	if n, ok := stats.int(4); ok {
+		c.distinct = max(c.distinct, n)
+	}
+	// min_value/max_value (6/5) supersede the deprecated min/max (2/1).
+	minV, maxV := stats.bytes(6), stats.bytes(5)
+	if minV == nil && maxV == nil {
+		minV, maxV = stats.bytes(2), stats.bytes(1)
+	}
+	if minV != nil && (c.min == nil || parquetCompare(c.node.physical, minV, c.min) < 0) {
+		c.min = minV
+	}
+	if maxV != nil && (c.max == nil || parquetCompare(c.node.physical, maxV, c.max) > 0) {
+		c.max = maxV
+	}
+}
+
+// parquetCompare orders two plain-encoded statistics values.
+func parquetCompare(physical int64, a, b []byte) int {
+	if x, ok := parquetInt(physical, a); ok {
+		if y, ok := parquetInt(physical, b); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+	if x, ok := parquetFloat(physical, a); ok {
+		if y, ok := parquetFloat(physical, b); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+	return bytes.Compare(a, b)
+}
+
+// parquetInt decodes a plain-encoded INT32 or INT64 statistics value.
+func parquetInt(physical int64, b []byte) (int64, bool) {
+	switch {
+	case physical == 1 && len(b) == 4:
+		return int64(int32(binary.LittleEndian.Uint32(b))), true
+	case physical == 2 && len(b) == 8:
+		return int64(binary.LittleEndian.Uint64(b)), true
+	}
+	return 0, false
+}
+
+// parquetFloat decodes a plain-encoded FLOAT or DOUBLE statistics value.
+func parquetFloat(physical int64, b []byte) (float64, bool) {
+	switch {
+	case physical == 4 && len(b) == 4:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), true
+	case physical == 5 && len(b) == 8:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), true
+	}
+	return 0, false
+}
+
+// parquetStatValue renders a plain-encoded statistics value for display.
+func parquetStatValue(node *parquetNode, b []byte) string {
+	if b == nil {
+		return "?"
+	}
+	switch node.physical {
+	case 0:
+		return strconv.FormatBool(len(b) > 0 && b[0] != 0)
+	case 1, 2:
+		n, ok := parquetInt(node.physical, b)
+		switch {
+		case !ok:
+		case node.annotation == "DATE":
+			return time.Unix(n*86400, 0).UTC().Format("2006-01-02")
+		case node.timeUnit != "":
+			return parquetTimestamp(n, node.timeUnit)
+		default:
+			return strconv.FormatInt(n, 10)
+		}
+	case 4, 5:
+		if n, ok := parquetFloat(node.physical, b); ok {
+			return strconv.FormatFloat(n, 'g', -1, 64)
+		}
+	case 6, 7:
+		if utf8.Valid(b) && !bytes.ContainsFunc(b, func(r rune) bool { return r < ' ' }) {
+			return strconv.Quote(truncateSample(string(b), parquetMaxStatLength))
+		}
+	}
+	return "0x" + truncateSample(hex.EncodeToString(b), parquetMaxStatLength)
+}
+
+// parquetTimestamp formats an epoch offset in unit as RFC 3339.
+func parquetTimestamp(v int64, unit string) string {
+	var t time.Time
+	switch unit {
+	case "ms":
+		t = time.UnixMilli(v)
+	case "us":
+		t = time.UnixMicro(v)
+	default:
+		t = time.Unix(0, v)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,449 @@
+package explorer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Arrow IPC files (Feather v2) end with a FlatBuffers footer holding the
+// schema and the location of every record batch; Feather v1 files end with
+// a FlatBuffers table describing the columns. Both are read without
+// touching the column data.
+
+var (
+	arrowMagic   = []byte("ARROW1")
+	featherMagic = []byte("FEA1")
+)
+
+const (
+	// arrowMaxFooter caps the footer read from disk.
+	arrowMaxFooter = 64 << 20
+	// arrowMaxBatches caps the record batch headers read to count rows.
+	arrowMaxBatches = 10_000
+	// arrowMaxMessage caps one record batch header.
+	arrowMaxMessage = 1 << 20
+	// arrowMaxDepth bounds nested field traversal.
+	arrowMaxDepth = 32
+)
+
+// arrowTypeNames names the Arrow Type union members by tag.
+var arrowTypeNames = []string{
+	"none", "null", "int", "float", "binary", "utf8", "bool", "decimal",
+	"date", "time", "timestamp", "interval", "list", "struct", "union",
+	"fixed_size_binary", "fixed_size_list", "map", "duration",
+	"large_binary", "large_utf8", "large_list", "run_end_encoded",
+	"binary_view", "utf8_view", "list_view", "large_list_view",
+}
+
+// arrowTimeUnits names the Arrow TimeUnit enum.
+var arrowTimeUnits = []string{"s", "ms", "us", "ns"}
+
+// featherV1Types names the Feather v1 column type enum.
+var featherV1Types = []string{
+	"bool", "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32",
+	"uint64", "float32", "float64", "utf8", "binary", "category",
+	"timestamp", "date", "time", "large_utf8", "large_binary",
+}
+
+// arrowBodyCodecs names the Arrow BodyCompression codecs.
+var arrowBodyCodecs = []string{"LZ4_FRAME", "ZSTD"}
+
+// arrowField is one field of an Arrow schema, in depth-first order.
+type arrowField struct {
+	name     string
+	typ      string
+	nullable bool
+	depth    int
+}
+
+// arrowMeta is what an Arrow IPC or Feather v1 footer describes.
+type arrowMeta struct {
+	format       string
+	version      int
+	fields       []arrowField
+	rows         int64
+	batches      int
+	batchesRead  int
+	dictionaries int
+	codecs       map[string]int
+	metadataKeys []string
+}
+
+// readArrowFooter reads the footer of an Arrow IPC file or a Feather v1
+// file from r.
+func readArrowFooter(r io.ReaderAt, size int64) (*arrowMeta, error) {
+	head := make([]byte, min(size, 8))
+	if _, err := r.ReadAt(head, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if bytes.HasPrefix(head, featherMagic) {
+		return readFeatherV1(r, size)
+	}
+
+	// File layout: ARROW1 <pad> ... <footer> <int32 footer length> ARROW1
+	if size < 16 {
+		return nil, errors.New("file too short for an Arrow footer")
+	}
+	trailer := make([]byte, 10)
+	if _, err := r.ReadAt(trailer, size-10); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(trailer[4:], arrowMagic) {
+		return nil, errors.New("missing ARROW1 trailer; not an Arrow IPC file (streams have no footer)")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(trailer))
+	if footerLen <= 0 || footerLen > size-18 || footerLen > arrowMaxFooter {
+		return nil, fmt.Errorf("invalid footer length %d", footerLen)
+	}
+	buf := make([]byte, footerLen)
+	if _, err := r.ReadAt(buf, size-10-footerLen); err != nil {
+		return nil, err
+	}
+	footer, ok := fbRoot(buf)
+	if !ok {
+		return nil, errors.New("corrupt footer")
+	}
+
+	meta := &arrowMeta{format: "Arrow IPC", codecs: make(map[string]int)}
+	meta.version = int(footer.int16(0)) + 1
+	if schema, ok := footer.table(1); ok {
+		meta.fields = arrowFields(schema, 0)
+		meta.metadataKeys = fbMetadataKeys(schema, 2)
+	}
+	_, meta.dictionaries = footer.vector(2)
+
+	start, n := footer.vector(3)
+	meta.batches = n
+	for i := range min(n, arrowMaxBatches) {
+		// Block: int64 offset, int32 metadata length, pad, int64 body length.
+		block := start + i*24
+		if block+24 > len(buf) {
+			break
+		}
+		offset := int64(binary.LittleEndian.Uint64(buf[block:]))
+		length := int64(int32(binary.LittleEndian.Uint32(buf[block+8:])))
+		rows, codec, err := readArrowBatchHeader(r, size, offset, length)
+		if err != nil {
+			break
+		}
+		meta.batchesRead++
+		meta.rows += rows
+		meta.codecs[codec]++
+	}
+	return meta, nil
+}
+
+// readArrowBatchHeader reads the row count and compression codec from the
+// record batch message at offset.
+func readArrowBatchHeader(r io.ReaderAt, size, offset, length int64) (int64, string, error) {
+	if offset < 0 || length < 8 || length > arrowMaxMessage || offset+length > size {
+		return 0, "", errors.New("invalid record batch block")
+	}
+	buf := make([]byte, length)
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return 0, "", err
+	}
+	// Messages start with a 0xFFFFFFFF continuation marker since format
+	// 0.15; older files have the length alone.
+	skip := 4
+	if binary.LittleEndian.Uint32(buf) == 0xFFFFFFFF {
+		skip = 8
+	}
+	msg, ok := fbRoot(buf[skip:])
+	if !ok || msg.uint8(1) != 3 {
+		return 0, "", errors.New("not a record batch message")
+	}
+	batch, ok := msg.table(2)
+	if !ok {
+		return 0, "", errors.New("record batch without header")
+	}
+	codec := "none"
+	if compression, ok := batch.table(3); ok {
+		codec = nameOr(arrowBodyCodecs, int(compression.uint8(0)))
+	}
+	return batch.int64(0), codec, nil
+}
+
+// arrowFields flattens the field vector of a Schema (slot 1) or of a
+// Field's children (slot 5) into depth-first order.
+func arrowFields(t fbTable, depth int) []arrowField {
+	slot := 1
+	if depth > 0 {
+		slot = 5
+	}
+	start, n := t.vector(slot)
+	var fields []arrowField
+	for i := range n {
+		f, ok := t.tableAt(start, i)
+		if !ok {
+			break
+		}
+		field := arrowField{name: f.string(0), nullable: f.bool(1), depth: depth}
+		typ, _ := f.table(3)
+		field.typ = arrowTypeName(f.uint8(2), typ)
+		if _, ok := f.table(4); ok {
+			field.typ += " (dictionary)"
+		}
+		fields = append(fields, field)
+		if depth < arrowMaxDepth {
+			fields = append(fields, arrowFields(f, depth+1)...)
+		}
+	}
+	return fields
+}
+
+// arrowTypeName describes an Arrow Type union member with its parameters.
+func arrowTypeName(tag uint8, t fbTable) string {
+	name := nameOr(arrowTypeNames, int(tag))
+	switch tag {
+	case 2: // Int
+		if t.bool(1) {
+			return fmt.Sprintf("int%d", t.int32(0))
+		}
+		return fmt.Sprintf("uint%d", t.int32(0))
+	case 3: // FloatingPoint
+		return nameOr([]string{"float16", "float32", "float64"}, int(t.int16(0)))
+	case 7: // Decimal
+		return fmt.Sprintf("decimal(%d, %d)", t.int32(0), t.int32(1))
+	case 8: // Date; the unit defaults to milliseconds.
+		if _, ok := t.field(0); ok && t.int16(0) == 0 {
+			return "date32"
+		}
+		return "date64"
+	case 9: // Time
+		return fmt.Sprintf("time[%s]", arrowTimeUnit(t, 1))
+	case 10: // Timestamp
+		if tz := t.string(1); tz != "" {
+			return fmt.Sprintf("timestamp[%s, %s]", nameOr(arrowTimeUnits, int(t.int16(0))), tz)
+		}
+		return fmt.Sprintf("timestamp[%s]", nameOr(arrowTimeUnits, int(t.int16(0))))
+	case 15: // FixedSizeBinary
+		return fmt.Sprintf("fixed_size_binary[%d]", t.int32(0))
+	case 16: // FixedSizeList
+		return fmt.Sprintf("fixed_size_list[%d]", t.int32(0))
+	case 18: // Duration
+		return fmt.Sprintf("duration[%s]", arrowTimeUnit(t, 1))
+	}
+	return name
+}
+
+// arrowTimeUnit names the unit in slot 0 of a Time or Duration type, which
+// falls back to def (milliseconds for both) when omitted.
+func arrowTimeUnit(t fbTable, def int) string {
+	if _, ok := t.field(0); ok {
+		return nameOr(arrowTimeUnits, int(t.int16(0)))
+	}
+	return nameOr(arrowTimeUnits, def)
+}
+
+// readFeatherV1 reads the metadata table at the end of a Feather v1 file:
+// <FEA1> ... <metadata> <int32 metadata length> <FEA1>.
+func readFeatherV1(r io.ReaderAt, size int64) (*arrowMeta, error) {
+	if size < 16 {
+		return nil, errors.New("file too short for Feather metadata")
+	}
+	trailer := make([]byte, 8)
+	if _, err := r.ReadAt(trailer, size-8); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(trailer[4:], featherMagic) {
+		return nil, errors.New("missing FEA1 trailer")
+	}
+	metaLen := int64(binary.LittleEndian.Uint32(trailer))
+	if metaLen <= 0 || metaLen > size-12 || metaLen > arrowMaxFooter {
+		return nil, fmt.Errorf("invalid metadata length %d", metaLen)
+	}
+	buf := make([]byte, metaLen)
+	if _, err := r.ReadAt(buf, size-8-metaLen); err != nil {
+		return nil, err
+	}
+	table, ok := fbRoot(buf)
+	if !ok {
+		return nil, errors.New("corrupt metadata")
+	}
+
+	meta := &arrowMeta{format: "Feather v1", version: int(table.int32(3)), rows: table.int64(1), codecs: make(map[string]int)}
+	start, n := table.vector(2)
+	for i := range n {
+		col, ok := table.tableAt(start, i)
+		if !ok {
+			break
+		}
+		values, _ := col.table(1)
+		// Feather v1 has no nullability in its schema; any column may
+		// hold nulls.
+		meta.fields = append(meta.fields, arrowField{
+			name:     col.string(0),
+			typ:      nameOr(featherV1Types, int(values.uint8(0))),
+			nullable: true,
+		})
+	}
+	return meta, nil
+}
+
+// fbMetadataKeys returns the keys of a KeyValue vector field.
+func fbMetadataKeys(t fbTable, slot int) []string {
+	start, n := t.vector(slot)
+	keys := make([]string, 0, n)
+	for i := range n {
+		kv, ok := t.tableAt(start, i)
+		if !ok {
+			break
+		}
+		keys = append(keys, kv.string(0))
+	}
+	return keys
+}
+
+// nameOr returns names[i], or a numbered placeholder for values the
+// table does not know.
+func nameOr(names []string, i int) string {
+	if i >= 0 && i < len(names) {
+		return names[i]
+	}
+	return fmt.Sprintf("unknown(%d)", i)
+}
+
+// fbTable is a FlatBuffers table in buf. Accessors return zero values for
+// absent fields and for offsets outside buf, so a corrupt footer yields a
+// partial summary rather than a panic.
+type fbTable struct {
+	buf []byte
+	pos int
+}
+
+// fbRoot returns the root table of a FlatBuffers buffer.
+func fbRoot(buf []byte) (fbTable, bool) {
+	off, ok := fbUint32(buf, 0)
+	t := fbTable{buf: buf, pos: off}
+	_, valid := fbUint32(buf, t.pos)
+	return t, ok && valid
+}
+
+func fbUint32(buf []byte, at int) (int, bool) {
+	if at < 0 || at+4 > len(buf) {
+		return 0, false
+	}
+	return int(binary.LittleEndian.Uint32(buf[at:])), true
+}
+
+// field returns the absolute position of the field in vtable slot.
+func (t fbTable) field(slot int) (int, bool) {
+	soff, ok := fbUint32(t.buf, t.pos)
+	if !ok {
+		return 0, false
+	}
+	vt := t.pos - int(int32(uint32(soff)))
+	if vt < 0 || vt+4 > len(t.buf) {
+		return 0, false
+	}
+	entry := 4 + 2*slot
+	if entry+2 > int(binary.LittleEndian.Uint16(t.buf[vt:])) || vt+entry+2 > len(t.buf) {
+		return 0, false
+	}
+	off := int(binary.LittleEndian.Uint16(t.buf[vt+entry:]))
+	if off == 0 {
+		return 0, false
+	}
+	return t.pos + off, true
+}
+
+// scalar returns the size bytes of a scalar field, or nil.
+func (t fbTable) scalar(slot, size int) []byte {
+	p, ok := t.field(slot)
+	if !ok || p+size > len(t.buf) {
+		return nil
+	}
+	return t.buf[p : p+size]
+}
+
+func (t fbTable) uint8(slot int) uint8 {
+	if b := t.scalar(slot, 1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (t fbTable) bool(slot int) bool { return t.uint8(slot) != 0 }
+
+func (t fbTable) int16(slot int) int16 {
+	if b := t.scalar(slot, 2); b != nil {
+		return int16(binary.LittleEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (t fbTable) int32(slot int) int32 {
+	if b := t.scalar(slot, 4); b != nil {
+		return int32(binary.LittleEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (t fbTable) int64(slot int) int64 {
+	if b := t.scalar(slot, 8); b != nil {
+		return int64(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+// indirect follows the offset stored in a reference field.
+func (t fbTable) indirect(slot int) (int, bool) {
+	p, ok := t.field(slot)
+	if !ok {
+		return 0, false
+	}
+	off, ok := fbUint32(t.buf, p)
+	return p + off, ok
+}
+
+func (t fbTable) table(slot int) (fbTable, bool) {
+	p, ok := t.indirect(slot)
+	if !ok {
+		return fbTable{}, false
+	}
+	_, ok = fbUint32(t.buf, p)
+	return fbTable{buf: t.buf, pos: p}, ok
+}
+
+func (t fbTable) string(slot int) string {
+	p, ok := t.indirect(slot)
+	if !ok {
+		return ""
+	}
+	n, ok := fbUint32(t.buf, p)
+	if !ok || p+4+n > len(t.buf) {
+		return ""
+	}
+	return strings.ToValidUTF8(string(t.buf[p+4:p+4+n]), "?")
+}
+
+// vector returns the position of the first element of a vector field and
+// its length, bounded by the bytes left in the buffer.
+func (t fbTable) vector(slot int) (int, int) {
+	p, ok := t.indirect(slot)
+	if !ok {
+		return 0, 0
+	}
+	n, ok := fbUint32(t.buf, p)
+	if !ok {
+		return 0, 0
+	}
+	return p + 4, min(n, len(t.buf)-p-4)
+}
+
+// tableAt returns element i of a vector of tables starting at start.
+func (t fbTable) tableAt(start, i int) (fbTable, bool) {
+	elem := start + 4*i
+	off, ok := fbUint32(t.buf, elem)
+	if !ok {
+		return fbTable{}, false
+	}
+	p := elem + off
+	_, ok = fbUint32(t.buf, p)
+	return fbTable{buf: t.buf, pos: p}, ok
+}
@@ -0,0 +1,424 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test fixtures are encoded by hand: thriftFields for Parquet footers and
+// fbFields for Arrow footers, so no Parquet or Arrow library is needed.
+
+// thriftFields is a Thrift struct to encode; values are int32, int64,
+// string, bool, thriftFields or thriftList.
+type thriftFields []struct {
+	id int16
+	v  any
+}
+
+type thriftList []any
+
+func thriftType(v any) byte {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return 1
+		}
+		return 2
+	case int32:
+		return 5
+	case int64:
+		return 6
+	case string:
+		return 8
+	case thriftList:
+		return 9
+	case thriftFields:
+		return 12
+	}
+	panic("unsupported thrift value")
+}
+
+func appendZigzag(buf []byte, v int64) []byte {
+	return binary.AppendUvarint(buf, uint64(v<<1)^uint64(v>>63))
+}
+
+func appendThriftValue(buf []byte, v any) []byte {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return append(buf, 1)
+		}
+		return append(buf, 2)
+	case int32:
+		return appendZigzag(buf, int64(v))
+	case int64:
+		return appendZigzag(buf, v)
+	case string:
+		return append(binary.AppendUvarint(buf, uint64(len(v))), v...)
+	case thriftList:
+		elem := byte(12)
+		if len(v) > 0 {
+			elem = thriftType(v[0]) &^ 2 // booleans list as type 1
+		}
+		if len(v) < 15 {
+			buf = append(buf, byte(len(v))<<4|elem)
+		} else {
+			buf = binary.AppendUvarint(append(buf, 0xf0|elem), uint64(len(v)))
+		}
+		for _, item := range v {
+			buf = appendThriftValue(buf, item)
+		}
+		return buf
+	case thriftFields:
+		var last int16
+		for _, f := range v {
+			typ := thriftType(f.v)
+			if delta := f.id - last; delta > 0 && delta <= 15 {
+				buf = append(buf, byte(delta)<<4|typ)
+			} else {
+				buf = appendZigzag(append(buf, typ), int64(f.id))
+			}
+			last = f.id
+			if _, isBool := f.v.(bool); !isBool {
+				buf = appendThriftValue(buf, f.v)
+			}
+		}
+		return append(buf, 0)
+	}
+	panic("unsupported thrift value")
+}
+
+func le32(v int32) string { return string(binary.LittleEndian.AppendUint32(nil, uint32(v))) }
+func le64(v int64) string { return string(binary.LittleEndian.AppendUint64(nil, uint64(v))) }
+
+func parquetChunk(typ int32, path []string, codec int32, stats thriftFields) thriftFields {
+	var pathList thriftList
+	for _, p := range path {
+		pathList = append(pathList, p)
+	}
+	return thriftFields{
+		{2, int64(4)},
+		{3, thriftFields{
+			{1, typ},
+			{2, thriftList{int32(0), int32(3), int32(8)}},
+			{3, pathList},
+			{4, codec},
+			{5, int64(2)},
+			{6, int64(100)},
+			{7, int64(40)},
+			{9, int64(4)},
+			{12, stats},
+		}},
+	}
+}
+
+// makeParquet returns a Parquet file with a nested schema and two row
+// groups.
+func makeParquet() []byte {
+	rowGroup := func(rows int64, minID, maxID int64, minName, maxName string, nulls int64) thriftFields {
+		return thriftFields{
+			{1, thriftList{
+				parquetChunk(2, []string{"id"}, 1, thriftFields{{3, int64(0)}, {5, le64(maxID)}, {6, le64(minID)}}),
+				parquetChunk(6, []string{"name"}, 6, thriftFields{{3, nulls}, {5, maxName}, {6, minName}}),
+				parquetChunk(1, []string{"address", "zip"}, 1, thriftFields{{5, le32(19800)}, {6, le32(19700)}}),
+			}},
+			{2, int64(300)},
+			{3, rows},
+		}
+	}
+	footer := appendThriftValue(nil, thriftFields{
+		{1, int32(2)},
+		{2, thriftList{
+			thriftFields{{4, "schema"}, {5, int32(3)}},
+			thriftFields{{1, int32(2)}, {3, int32(0)}, {4, "id"}},
+			thriftFields{{1, int32(6)}, {3, int32(1)}, {4, "name"}, {6, int32(0)}, {10, thriftFields{{1, thriftFields{}}}}},
+			thriftFields{{3, int32(1)}, {4, "address"}, {5, int32(1)}},
+			thriftFields{{1, int32(1)}, {3, int32(1)}, {4, "zip"}, {10, thriftFields{{6, thriftFields{}}}}},
+		}},
+		{3, int64(5)},
+		{4, thriftList{
+			rowGroup(3, 1, 3, "alice", "carol", 0),
+			rowGroup(2, 4, 42, "bob", "dave", 1),
+		}},
+		{5, thriftList{thriftFields{{1, "ARROW:schema"}, {2, "..."}}}},
+		{6, "parquet-cpp-arrow version 15.0.0"},
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("PAR1")
+	buf.Write(make([]byte, 64)) // column data, never read
+	buf.Write(footer)
+	buf.WriteString(le32(int32(len(footer))))
+	buf.WriteString("PAR1")
+	return buf.Bytes()
+}
+
+// fbFields is a FlatBuffers table to encode, indexed by slot; values are
+// nil (absent), uint8, bool, int16, int32, int64, string, fbFields,
+// []fbFields (vector of tables) or fbStructs (vector of inline structs).
+type fbFields []any
+
+type fbStructs []byte
+
+// fbBuilder lays tables out front to back, writing referenced objects
+// after the table that refers to them so every offset points forward.
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *fbBuilder) patch(at int, target int) {
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(target-at))
+}
+
+func (b *fbBuilder) table(fields fbFields) int {
+	// Inline layout: soffset, then each field aligned to its size.
+	offsets := make([]int, len(fields))
+	size := 4
+	for i, v := range fields {
+		n := 0
+		switch v.(type) {
+		case uint8, bool:
+			n = 1
+		case int16:
+			n = 2
+		case int32, string, fbFields, []fbFields, fbStructs:
+			n = 4
+		case int64:
+			n = 8
+		}
+		if n == 0 {
+			continue
+		}
+		size = (size + n - 1) / n * n
+		offsets[i] = size
+		size += n
+	}
+
+	b.align(2)
+	vtable := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(4+2*len(fields)))
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(size))
+	for _, off := range offsets {
+		b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(off))
+	}
+	b.align(8)
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(pos-vtable))
+
+	for i, v := range fields {
+		at := pos + offsets[i]
+		switch v := v.(type) {
+		case uint8:
+			b.buf[at] = v
+		case bool:
+			if v {
+				b.buf[at] = 1
+			}
+		case int16:
+			binary.LittleEndian.PutUint16(b.buf[at:], uint16(v))
+		case int32:
+			binary.LittleEndian.PutUint32(b.buf[at:], uint32(v))
+		case int64:
+			binary.LittleEndian.PutUint64(b.buf[at:], uint64(v))
+		}
+	}
+	for i, v := range fields {
+		at := pos + offsets[i]
+		switch v := v.(type) {
+		case string:
+			b.align(4)
+			b.patch(at, len(b.buf))
+			b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(v)))
+			b.buf = append(append(b.buf, v...), 0)
+		case fbFields:
+			b.patch(at, b.table(v))
+		case []fbFields:
+			b.align(4)
+			b.patch(at, len(b.buf))
+			b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(v)))
+			slots := len(b.buf)
+			b.buf = append(b.buf, make([]byte, 4*len(v))...)
+			for j, elem := range v {
+				b.patch(slots+4*j, b.table(elem))
+			}
+		case fbStructs:
+			for (len(b.buf)+4)%8 != 0 {
+				b.buf = append(b.buf, 0)
+			}
+			b.patch(at, len(b.buf))
+			b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(v)/24))
+			b.buf = append(b.buf, v...)
+		}
+	}
+	return pos
+}
+
+// buildFlatBuffer returns a buffer whose root is fields.
+func buildFlatBuffer(fields fbFields) []byte {
+	b := &fbBuilder{buf: make([]byte, 4)}
+	b.patch(0, b.table(fields))
+	return b.buf
+}
+
+// makeArrowIPC returns an Arrow IPC file with one ZSTD-compressed record
+// batch of 3 rows.
+func makeArrowIPC() []byte {
+	message := buildFlatBuffer(fbFields{
+		int16(4), uint8(3),
+		fbFields{int64(3), nil, nil, fbFields{uint8(1)}},
+		int64(0),
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("ARROW1\x00\x00")
+	offset := int64(buf.Len())
+	buf.WriteString(le32(-1))
+	buf.WriteString(le32(int32(len(message))))
+	buf.Write(message)
+	for buf.Len()%8 != 0 {
+		buf.WriteByte(0)
+	}
+	metaLen := int64(buf.Len()) - offset
+
+	block := le64(offset) + le32(int32(metaLen)) + le32(0) + le64(0)
+	footer := buildFlatBuffer(fbFields{
+		int16(4),
+		fbFields{
+			nil,
+			[]fbFields{
+				{"id", false, uint8(2), fbFields{int32(64), true}},
+				{"name", true, uint8(5), fbFields{}},
+				{"tags", true, uint8(12), fbFields{}, nil, []fbFields{
+					{"item", true, uint8(5), fbFields{}},
+				}},
+				{"ts", true, uint8(10), fbFields{int16(2), "UTC"}},
+			},
+			[]fbFields{{"pandas", "{}"}},
+		},
+		nil,
+		fbStructs(block),
+	})
+	buf.Write(footer)
+	buf.WriteString(le32(int32(len(footer))))
+	buf.WriteString("ARROW1")
+	return buf.Bytes()
+}
+
+func TestParquetExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &ParquetExplorer{}
+	require.True(t, e.CanHandle("data.parquet", nil))
+	require.True(t, e.CanHandle("data.feather", nil))
+	require.True(t, e.CanHandle("data.arrow", nil))
+	require.True(t, e.CanHandle("part-0000", []byte("PAR1\x15\x04")))
+	require.True(t, e.CanHandle("table", []byte("ARROW1\x00\x00")))
+	require.False(t, e.CanHandle("data.csv", []byte("a,b\n")))
+}
+
+func TestParquetExplorer_Parquet(t *testing.T) {
+	t.Parallel()
+
+	e := &ParquetExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "users.parquet", Content: makeParquet()})
+	require.NoError(t, err)
+	require.Equal(t, "parquet", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Parquet file: users.parquet\n")
+	require.Contains(t, s, "Format version: 2\n")
+	require.Contains(t, s, "Created by: parquet-cpp-arrow version 15.0.0\n")
+	require.Contains(t, s, "Rows: 5\nRow groups: 2\nColumns: 3\n")
+	require.Contains(t, s, "Schema:\n"+
+		"  1 id: INT64, required\n"+
+		"  2 name: BYTE_ARRAY (STRING), optional\n"+
+		"  3 address: group, optional\n"+
+		"  3.1 zip: INT32 (DATE), optional\n")
+	require.Contains(t, s, "Compression codecs:\n  - SNAPPY: 4 column chunks\n  - ZSTD: 2 column chunks\n")
+	// Statistics are merged across both row groups and close the summary.
+	require.True(t, strings.HasSuffix(s, "Column statistics:\n"+
+		"  - id: min 1, max 42, nulls 0\n"+
+		`  - name: min "alice", max "dave", nulls 1`+"\n"+
+		"  - address.zip: min 2023-12-09, max 2024-03-18\n"), s)
+}
+
+func TestParquetExplorer_Parquet_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &ParquetExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "users.parquet", Content: makeParquet()})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Size: 521 bytes\n")
+	require.Contains(t, s, "Storage:\n"+
+		"  - Footer: 445 bytes\n"+
+		"  - Uncompressed data: 600 bytes\n"+
+		"  - Compression ratio: 2.5x\n")
+	require.Contains(t, s, "Row group sizes:\n  1. 3 rows, 120 bytes\n  2. 2 rows, 120 bytes\n")
+	require.Contains(t, s, "Encodings:\n  - PLAIN: 3 columns\n  - RLE: 3 columns\n  - RLE_DICTIONARY: 3 columns\n")
+	require.Contains(t, s, "Metadata keys:\n  - ARROW:schema\n")
+}
+
+func TestParquetExplorer_Arrow(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		e := &ParquetExplorer{formatterProfile: profile}
+		result, err := e.Explore(context.Background(), ExploreInput{Path: "events.feather", Content: makeArrowIPC()})
+		require.NoError(t, err)
+
+		s := result.Summary
+		require.Contains(t, s, "Arrow file: events.feather\n")
+		require.Contains(t, s, "Format: Arrow IPC\nFormat version: 5\n")
+		require.Contains(t, s, "Rows: 3\nRecord batches: 1\nColumns: 4\n")
+		require.Contains(t, s, "Schema:\n"+
+			"  1 id: int64, not null\n"+
+			"  2 name: utf8, nullable\n"+
+			"  3 tags: list, nullable\n"+
+			"  3.1 item: utf8, nullable\n"+
+			"  4 ts: timestamp[us, UTC], nullable\n")
+		require.Contains(t, s, "Compression codecs:\n  - ZSTD: 1 record batches\n")
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(s, "Metadata keys:\n  - pandas\n"))
+	}
+}
+
+func TestParquetExplorer_Degraded(t *testing.T) {
+	t.Parallel()
+
+	// A file whose writer never closed it has the header but no footer.
+	content := append([]byte("PAR1"), make([]byte, 32)...)
+	result, err := (&ParquetExplorer{}).Explore(context.Background(), ExploreInput{Path: "partial.parquet", Content: content})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Degraded exploration:\n  Failed: Parquet footer: missing PAR1 trailer\n")
+
+	result, err = (&ParquetExplorer{}).Explore(context.Background(), ExploreInput{Path: "stream.arrow", Content: []byte("ARROW1\x00\x00\xff\xff\xff\xff\x10\x00\x00\x00")})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Failed: Arrow footer: missing ARROW1 trailer; not an Arrow IPC file (streams have no footer)\n")
+}
+
+func TestParquetExplorer_Stream(t *testing.T) {
+	t.Parallel()
+
+	content := makeParquet()
+	registry := NewRegistry(WithOutputProfile(OutputProfileParity))
+	streamed, err := registry.ExploreStream(context.Background(), "users.parquet", bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	loaded, err := registry.Explore(context.Background(), ExploreInput{Path: "users.parquet", Content: content})
+	require.NoError(t, err)
+	require.Equal(t, "parquet", streamed.ExplorerUsed)
+	require.Equal(t, loaded.Summary, streamed.Summary)
+	require.Equal(t, "data_format_native", KindValue(&ParquetExplorer{}))
+}
@@ -0,0 +1,236 @@
+package explorer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// thriftStruct is a Thrift struct decoded without a schema: field values
+// keyed by field id. Values are bool, int64, float64, []byte, []any or
+// thriftStruct.
+type thriftStruct map[int16]any
+
+// thriftMaxDepth bounds struct and list nesting, so a corrupt footer cannot
+// recurse without limit.
+const thriftMaxDepth = 64
+
+var errThriftTruncated = errors.New("thrift: truncated input")
+
+// thriftReader decodes the Thrift compact protocol Parquet footers are
+// written in.
+type thriftReader struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+func (r *thriftReader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errThriftTruncated
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errThriftTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+// varint reads a zigzag-encoded integer.
+func (r *thriftReader) varint() (int64, error) {
+	v, err := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+// readStruct decodes a struct up to and including its stop field.
+func (r *thriftReader) readStruct() (thriftStruct, error) {
+	if r.depth++; r.depth > thriftMaxDepth {
+		return nil, fmt.Errorf("thrift: nesting deeper than %d", thriftMaxDepth)
+	}
+	defer func() { r.depth-- }()
+
+	s := make(thriftStruct)
+	var last int16
+	for {
+		header, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return s, nil
+		}
+		typ := header & 0x0f
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		last = id
+
+		// Struct fields carry booleans in the type nibble.
+		switch typ {
+		case 1:
+			s[id] = true
+		case 2:
+			s[id] = false
+		default:
+			if s[id], err = r.value(typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// value decodes a value of the given compact type.
+func (r *thriftReader) value(typ byte) (any, error) {
+	switch typ {
+	case 1, 2:
+		// Booleans inside collections take a byte each.
+		b, err := r.byte()
+		return b == 1, err
+	case 3:
+		b, err := r.byte()
+		return int64(int8(b)), err
+	case 4, 5, 6:
+		return r.varint()
+	case 7:
+		if r.pos+8 > len(r.buf) {
+			return nil, errThriftTruncated
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf[r.pos:]))
+		r.pos += 8
+		return v, nil
+	case 8:
+		n, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(r.buf)-r.pos) {
+			return nil, errThriftTruncated
+		}
+		b := r.buf[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return b, nil
+	case 9, 10:
+		return r.readList()
+	case 11:
+		return r.readMap()
+	case 12:
+		return r.readStruct()
+	default:
+		return nil, fmt.Errorf("thrift: unknown type %d", typ)
+	}
+}
+
+func (r *thriftReader) readList() ([]any, error) {
+	header, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	size := uint64(header >> 4)
+	if size == 15 {
+		if size, err = r.uvarint(); err != nil {
+			return nil, err
+		}
+	}
+	// Every element takes at least one byte.
+	if size > uint64(len(r.buf)-r.pos) {
+		return nil, errThriftTruncated
+	}
+	if r.depth++; r.depth > thriftMaxDepth {
+		return nil, fmt.Errorf("thrift: nesting deeper than %d", thriftMaxDepth)
+	}
+	defer func() { r.depth-- }()
+
+	items := make([]any, 0, size)
+	for range size {
+		v, err := r.value(header & 0x0f)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+// readMap decodes a map as a list of alternating keys and values; Parquet
+// footers do not use maps, but they must still be skipped correctly.
+func (r *thriftReader) readMap() ([]any, error) {
+	size, err := r.uvarint()
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	if size > uint64(len(r.buf)-r.pos) {
+		return nil, errThriftTruncated
+	}
+	types, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]any, 0, 2*size)
+	for range size {
+		k, err := r.value(types >> 4)
+		if err != nil {
+			return nil, err
+		}
+		v, err := r.value(types & 0x0f)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, k, v)
+	}
+	return items, nil
+}
+
+func (s thriftStruct) int(id int16) (int64, bool) {
+	v, ok := s[id].(int64)
+	return v, ok
+}
+
+func (s thriftStruct) bytes(id int16) []byte {
+	v, _ := s[id].([]byte)
+	return v
+}
+
+func (s thriftStruct) string(id int16) string {
+	return string(s.bytes(id))
+}
+
+func (s thriftStruct) bool(id int16) bool {
+	v, _ := s[id].(bool)
+	return v
+}
+
+func (s thriftStruct) structField(id int16) thriftStruct {
+	v, _ := s[id].(thriftStruct)
+	return v
+}
+
+// structs returns the struct elements of a list field.
+func (s thriftStruct) structs(id int16) []thriftStruct {
+	list, _ := s[id].([]any)
+	out := make([]thriftStruct, 0, len(list))
+	for _, item := range list {
+		if st, ok := item.(thriftStruct); ok {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// list returns the elements of a list field.
+func (s thriftStruct) list(id int16) []any {
+	v, _ := s[id].([]any)
+	return v
+}
@@ -10,7 +10,9 @@ import (
 	"time"
 )
 
-// PDFExplorer explores PDF files using pdfinfo and pdftotext.
+// PDFExplorer explores PDF files. Page count, document info, the outline
+// and per-page text samples are read natively; pdfinfo and pdftotext are
+// preferred for metadata and text when installed.
 type PDFExplorer struct {
 	formatterProfile OutputProfile
 }
@@ -49,9 +51,14 @@ func (e *PDFExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 	fmt.Fprintf(&summary, "PDF document: %s\n", name)
 	fmt.Fprintf(&summary, "Size: %d bytes\n", len(input.Content))
 
+	doc := parsePDFDocument(input.Content)
+	if v := doc.version(); v != "" {
+		fmt.Fprintf(&summary, "PDF version: %s\n", v)
+	}
+
 	// Write content to a temp file for external tool invocation.
 	err := withTempFile("crush-pdf-*.pdf", input.Content, func(tempPath string) error {
-		return e.explorePDF(ctx, &summary, tempPath)
+		return e.explorePDF(ctx, &summary, tempPath, doc)
 	})
 	if err != nil {
 		summary.WriteString("\nError: " + err.Error())
@@ -65,21 +72,104 @@ func (e *PDFExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreR
 	}, nil
 }
 
-// explorePDF runs pdfinfo and pdftotext against the temp file and writes
-// the extracted information into the summary builder.
-func (e *PDFExplorer) explorePDF(ctx context.Context, summary *strings.Builder, path string) error {
+// explorePDF writes metadata, the outline and page text into the summary
+// builder, using pdfinfo and pdftotext against the temp file when they are
+// installed and the native parse of doc otherwise.
+func (e *PDFExplorer) explorePDF(ctx context.Context, summary *strings.Builder, path string, doc *pdfDocument) error {
 	// Try pdfinfo for metadata (non-fatal if missing).
-	e.extractMetadata(ctx, summary, path)
+	if !e.extractMetadata(ctx, summary, path) {
+		writePDFDocumentInfo(summary, doc)
+	}
+	writePDFOutline(summary, doc)
 
 	// Try pdftotext for text content.
-	return e.extractText(ctx, summary, path)
+	ok, err := e.extractText(ctx, summary, path)
+	if err == nil && !ok {
+		writePDFPageSamples(summary, doc)
+	}
+	return err
+}
+
+// writePDFDocumentInfo writes the page count and the Info dictionary
+// entries found by the native parser.
+func writePDFDocumentInfo(summary *strings.Builder, doc *pdfDocument) {
+	pages := doc.pageCount()
+	var info [][2]string
+	if !doc.encrypted() {
+		info = doc.info()
+	}
+	if pages == 0 && len(info) == 0 {
+		return
+	}
+	summary.WriteString("\nDocument info:\n")
+	if pages > 0 {
+		fmt.Fprintf(summary, "  Pages: %d\n", pages)
+	}
+	for _, field := range info {
+		fmt.Fprintf(summary, "  %s: %s\n", field[0], field[1])
+	}
+}
+
+// writePDFOutline writes the bookmark tree, numbered so it keeps its
+// order through formatting.
+func writePDFOutline(summary *strings.Builder, doc *pdfDocument) {
+	if doc.encrypted() {
+		return
+	}
+	items := doc.outline()
+	if len(items) == 0 {
+		return
+	}
+	depths := make([]int, len(items))
+	for i, item := range items {
+		depths[i] = item.depth
+	}
+	summary.WriteString("\nOutline:\n")
+	for i, number := range outlineNumbers(depths) {
+		fmt.Fprintf(summary, "  %s %s\n", number, items[i].title)
+	}
+}
+
+// writePDFPageSamples writes a text sample for each of the first pages,
+// extracted natively from their content streams.
+func writePDFPageSamples(summary *strings.Builder, doc *pdfDocument) {
+	if doc.encrypted() {
+		return
+	}
+	var samples []string
+	for _, page := range doc.pages() {
+		if len(samples) == pdfMaxSampledPages {
+			break
+		}
+		samples = append(samples, doc.pageText(page))
+	}
+	writePageSamples(summary, samples)
+}
+
+// writePageSamples writes the non-empty samples under "Page samples:".
+func writePageSamples(summary *strings.Builder, samples []string) {
+	header := false
+	for i, text := range samples {
+		text = strings.Join(strings.Fields(text), " ")
+		if len(text) < pdfMinTextChars {
+			continue
+		}
+		if !header {
+			summary.WriteString("\nPage samples:\n")
+			header = true
+		}
+		if r := []rune(text); len(r) > pdfPageSampleChars {
+			text = string(r[:pdfPageSampleChars]) + "..."
+		}
+		fmt.Fprintf(summary, "  Page %d: %s\n", i+1, text)
+	}
 }
 
 // extractMetadata runs pdfinfo and parses key-value lines into the summary.
-// Missing pdfinfo tool is non-fatal.
-func (e *PDFExplorer) extractMetadata(ctx context.Context, summary *strings.Builder, path string) {
+// It reports whether pdfinfo succeeded; a missing tool is non-fatal.
+func (e *PDFExplorer) extractMetadata(ctx context.Context, summary *strings.Builder, path string) bool {
 	if _, err := exec.LookPath("pdfinfo"); err != nil {
-		return
+		return false
 	}
 
 	tctx, cancel := context.WithTimeout(ctx, pdfToolTimeout)
@@ -87,7 +177,7 @@ func (e *PDFExplorer) extractMetadata(ctx context.Context, summary *strings.Buil
 
 	out, err := exec.CommandContext(tctx, "pdfinfo", path).Output()
 	if err != nil {
-		return
+		return false
 	}
 
 	lines := strings.Split(string(out), "\n")
@@ -106,14 +196,17 @@ func (e *PDFExplorer) extractMetadata(ctx context.Context, summary *strings.Buil
 			}
 		}
 	}
+	return true
 }
 
-// extractText runs pdftotext -layout and captures stdout. It truncates long
-// output and detects encrypted or image-only PDFs.
-func (e *PDFExplorer) extractText(ctx context.Context, summary *strings.Builder, path string) error {
+// extractText runs pdftotext -layout and captures stdout. Multi-page
+// output becomes per-page samples; single-page output is truncated into a
+// text sample. It detects encrypted or image-only PDFs and reports whether
+// pdftotext produced a result.
+func (e *PDFExplorer) extractText(ctx context.Context, summary *strings.Builder, path string) (bool, error) {
 	if _, err := exec.LookPath("pdftotext"); err != nil {
-		// No pdftotext available; degrade gracefully to file-size only.
-		return nil
+		// No pdftotext available; the caller falls back to native text.
+		return false, nil
 	}
 
 	tctx, cancel := context.WithTimeout(ctx, pdfToolTimeout)
@@ -132,10 +225,10 @@ func (e *PDFExplorer) extractText(ctx context.Context, summary *strings.Builder,
 		if strings.Contains(stderrStr, "ncrypt") ||
 			strings.Contains(stderrStr, "password") {
 			summary.WriteString("\nEncrypted PDF")
-			return nil
+			return true, nil
 		}
 		// Other errors are non-fatal; degrade to file-size only.
-		return nil
+		return false, nil
 	}
 
 	text := strings.TrimSpace(stdout.String())
@@ -143,7 +236,13 @@ func (e *PDFExplorer) extractText(ctx context.Context, summary *strings.Builder,
 	// Detect image-only / scanned PDFs.
 	if len(text) < pdfMinTextChars {
 		summary.WriteString("\nImage-only or scanned PDF")
-		return nil
+		return true, nil
+	}
+
+	// pdftotext separates pages with form feeds.
+	if pages := strings.Split(strings.TrimSuffix(text, "\f"), "\f"); len(pages) > 1 {
+		writePageSamples(summary, pages[:min(len(pages), pdfMaxSampledPages)])
+		return true, nil
 	}
 
 	// Truncate to limit: head + tail.
@@ -156,5 +255,5 @@ func (e *PDFExplorer) extractText(ctx context.Context, summary *strings.Builder,
 	summary.WriteString("\nText content:\n")
 	summary.WriteString(text)
 
-	return nil
+	return true, nil
 }
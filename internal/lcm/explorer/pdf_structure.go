@@ -0,0 +1,591 @@
+package explorer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// pdfMaxStreamBytes caps the decoded size of a single PDF stream.
+	pdfMaxStreamBytes = 4 * 1024 * 1024
+	// pdfMaxOutlineItems caps the number of outline entries collected.
+	pdfMaxOutlineItems = 32
+	// pdfMaxOutlineDepth caps how deep the outline is followed.
+	pdfMaxOutlineDepth = 4
+	// pdfMaxSampledPages is the number of pages a text sample is kept for.
+	pdfMaxSampledPages = 8
+	// pdfPageSampleChars is the length of each per-page text sample.
+	pdfPageSampleChars = 200
+)
+
+var (
+	pdfObjectPattern  = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfRefPattern     = regexp.MustCompile(`^(\d+)\s+\d+\s+R\b`)
+	pdfVersionPattern = regexp.MustCompile(`^%PDF-(\d\.\d)`)
+	pdfPageTypeRegexp = regexp.MustCompile(`/Type\s*/Page(?:[^A-Za-z]|$)`)
+)
+
+// pdfDocument is a tolerant, read-only view of the objects in a PDF. It
+// understands enough of the file structure (indirect objects, object
+// streams, Flate-encoded streams) to read the document catalog without
+// external tools; anything it cannot parse is skipped.
+type pdfDocument struct {
+	content []byte
+	objects map[int][]byte
+}
+
+// pdfOutlineItem is one bookmark in the document outline.
+type pdfOutlineItem struct {
+	title string
+	depth int
+}
+
+// parsePDFDocument indexes the indirect objects of content, including
+// those packed into object streams.
+func parsePDFDocument(content []byte) *pdfDocument {
+	doc := &pdfDocument{content: content, objects: make(map[int][]byte)}
+	matches := pdfObjectPattern.FindAllSubmatchIndex(content, -1)
+	for _, m := range matches {
+		if m[0] > 0 && !isPDFWhitespace(content[m[0]-1]) {
+			continue
+		}
+		num, err := strconv.Atoi(string(content[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		body := content[m[1]:]
+		if end := bytes.Index(body, []byte("endobj")); end >= 0 {
+			body = body[:end]
+		}
+		doc.objects[num] = bytes.TrimSpace(body)
+	}
+
+	for _, num := range sortedKeys(doc.objects) {
+		body := doc.objects[num]
+		if name, _ := pdfName(pdfDictValue(body, "Type")); name == "ObjStm" {
+			doc.unpackObjectStream(body)
+		}
+	}
+	return doc
+}
+
+// unpackObjectStream adds the objects stored in an object stream.
+func (d *pdfDocument) unpackObjectStream(body []byte) {
+	count, _ := strconv.Atoi(string(pdfDictValue(body, "N")))
+	first, _ := strconv.Atoi(string(pdfDictValue(body, "First")))
+	data := pdfStreamData(body)
+	if count <= 0 || first <= 0 || first > len(data) {
+		return
+	}
+
+	header := strings.Fields(string(data[:first]))
+	type entry struct{ num, off int }
+	entries := make([]entry, 0, count)
+	for i := 0; i+1 < len(header) && len(entries) < count; i += 2 {
+		num, err1 := strconv.Atoi(header[i])
+		off, err2 := strconv.Atoi(header[i+1])
+		if err1 != nil || err2 != nil || first+off > len(data) {
+			return
+		}
+		entries = append(entries, entry{num, first + off})
+	}
+	for i, e := range entries {
+		end := len(data)
+		if i+1 < len(entries) {
+			end = entries[i+1].off
+		}
+		if _, ok := d.objects[e.num]; !ok && e.off <= end {
+			d.objects[e.num] = bytes.TrimSpace(data[e.off:end])
+		}
+	}
+}
+
+// version returns the header version, such as "1.7".
+func (d *pdfDocument) version() string {
+	if m := pdfVersionPattern.FindSubmatch(d.content); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// trailerRef returns the object number of a trailer entry such as Root or
+// Info. The last occurrence wins, as with incremental updates.
+func (d *pdfDocument) trailerRef(key string) (int, bool) {
+	pattern := regexp.MustCompile(`/` + key + `\s+(\d+)\s+\d+\s+R`)
+	all := pattern.FindAllSubmatch(d.content, -1)
+	if len(all) == 0 {
+		return 0, false
+	}
+	num, err := strconv.Atoi(string(all[len(all)-1][1]))
+	return num, err == nil
+}
+
+// encrypted reports whether the document declares an encryption
+// dictionary, in which case its strings and streams are unreadable.
+func (d *pdfDocument) encrypted() bool {
+	return bytes.Contains(d.content, []byte("/Encrypt"))
+}
+
+// resolve returns the object a value refers to, or the value itself.
+func (d *pdfDocument) resolve(value []byte) []byte {
+	for range 8 {
+		m := pdfRefPattern.FindSubmatch(value)
+		if m == nil {
+			return value
+		}
+		num, _ := strconv.Atoi(string(m[1]))
+		value = d.objects[num]
+	}
+	return value
+}
+
+// catalog returns the document catalog dictionary.
+func (d *pdfDocument) catalog() []byte {
+	if num, ok := d.trailerRef("Root"); ok {
+		return d.objects[num]
+	}
+	for _, num := range sortedKeys(d.objects) {
+		if name, _ := pdfName(pdfDictValue(d.objects[num], "Type")); name == "Catalog" {
+			return d.objects[num]
+		}
+	}
+	return nil
+}
+
+// info returns the title, author and producer from the Info dictionary,
+// in that order, leaving missing entries out.
+func (d *pdfDocument) info() [][2]string {
+	num, ok := d.trailerRef("Info")
+	if !ok {
+		return nil
+	}
+	dict := d.objects[num]
+	var fields [][2]string
+	for _, key := range []string{"Title", "Author", "Producer"} {
+		if v := pdfString(d.resolve(pdfDictValue(dict, key))); v != "" {
+			fields = append(fields, [2]string{key, v})
+		}
+	}
+	return fields
+}
+
+// pages returns the page dictionaries in document order. When the page
+// tree cannot be walked it falls back to every object typed as a page.
+func (d *pdfDocument) pages() [][]byte {
+	var pages [][]byte
+	visited := make(map[string]bool)
+	var walk func(node []byte, depth int)
+	walk = func(node []byte, depth int) {
+		if node == nil || depth > 32 || visited[string(node)] {
+			return
+		}
+		visited[string(node)] = true
+		kids := pdfDictValue(node, "Kids")
+		if len(kids) == 0 {
+			pages = append(pages, node)
+			return
+		}
+		for _, ref := range pdfRefs(kids) {
+			walk(d.objects[ref], depth+1)
+		}
+	}
+	if root := d.resolve(pdfDictValue(d.catalog(), "Pages")); len(root) > 0 {
+		walk(root, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	for _, num := range sortedKeys(d.objects) {
+		if pdfPageTypeRegexp.Match(d.objects[num]) {
+			pages = append(pages, d.objects[num])
+		}
+	}
+	return pages
+}
+
+// pageCount returns the page count declared by the page tree root, or the
+// number of page objects found.
+func (d *pdfDocument) pageCount() int {
+	root := d.resolve(pdfDictValue(d.catalog(), "Pages"))
+	if n, err := strconv.Atoi(string(d.resolve(pdfDictValue(root, "Count")))); err == nil && n > 0 {
+		return n
+	}
+	return len(d.pages())
+}
+
+// outline returns the bookmarks in document order.
+func (d *pdfDocument) outline() []pdfOutlineItem {
+	root := d.resolve(pdfDictValue(d.catalog(), "Outlines"))
+	first, ok := pdfRef(pdfDictValue(root, "First"))
+	if !ok {
+		return nil
+	}
+
+	var items []pdfOutlineItem
+	visited := make(map[int]bool)
+	var walk func(num, depth int)
+	walk = func(num, depth int) {
+		for !visited[num] && len(items) < pdfMaxOutlineItems {
+			visited[num] = true
+			node := d.objects[num]
+			if node == nil {
+				return
+			}
+			if title := pdfString(d.resolve(pdfDictValue(node, "Title"))); title != "" {
+				items = append(items, pdfOutlineItem{title: title, depth: depth})
+			}
+			if child, ok := pdfRef(pdfDictValue(node, "First")); ok && depth+1 < pdfMaxOutlineDepth {
+				walk(child, depth+1)
+			}
+			next, ok := pdfRef(pdfDictValue(node, "Next"))
+			if !ok {
+				return
+			}
+			num = next
+		}
+	}
+	walk(first, 0)
+	return items
+}
+
+// pageText extracts the text shown by a page's content streams.
+func (d *pdfDocument) pageText(page []byte) string {
+	contents := pdfDictValue(page, "Contents")
+	refs := pdfRefs(contents)
+	if len(refs) == 0 {
+		if resolved := d.resolve(contents); len(resolved) > 0 && !bytes.Equal(resolved, contents) {
+			refs = pdfRefs(resolved)
+		}
+	}
+	var sb strings.Builder
+	for _, ref := range refs {
+		sb.WriteString(pdfContentText(pdfStreamData(d.objects[ref])))
+		sb.WriteByte(' ')
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// pdfContentText collects the strings shown by text operators in a content
+// stream, inserting spaces where the stream moves to a new position.
+func pdfContentText(stream []byte) string {
+	var sb strings.Builder
+	inArray := false
+	for i := 0; i < len(stream); i++ {
+		c := stream[i]
+		switch {
+		case c == '(':
+			s, end := pdfLiteralString(stream[i:])
+			sb.WriteString(s)
+			i += end - 1
+		case c == '[':
+			inArray = true
+		case c == ']':
+			inArray = false
+		case c == '%':
+			for i < len(stream) && stream[i] != '\n' && stream[i] != '\r' {
+				i++
+			}
+		case inArray && (c == '-' || c == '.' || (c >= '0' && c <= '9')):
+			j := i
+			for j < len(stream) && (stream[j] == '-' || stream[j] == '.' || (stream[j] >= '0' && stream[j] <= '9')) {
+				j++
+			}
+			// Large negative kerning in a TJ array is a word gap.
+			if n, err := strconv.ParseFloat(string(stream[i:j]), 64); err == nil && n < -150 {
+				sb.WriteByte(' ')
+			}
+			i = j - 1
+		case isPDFRegular(c):
+			j := i
+			for j < len(stream) && isPDFRegular(stream[j]) {
+				j++
+			}
+			switch string(stream[i:j]) {
+			case "Td", "TD", "T*", "Tm", "ET", "'", `"`:
+				sb.WriteByte(' ')
+			case "ID":
+				// Skip inline image data.
+				if end := bytes.Index(stream[j:], []byte("EI")); end >= 0 {
+					j += end + 2
+				} else {
+					j = len(stream)
+				}
+			}
+			i = j - 1
+		}
+	}
+	return sb.String()
+}
+
+// pdfStreamData returns the decoded stream of an object, or nil when the
+// object has no stream or uses a filter other than FlateDecode.
+func pdfStreamData(body []byte) []byte {
+	start := bytes.Index(body, []byte("stream"))
+	if start < 0 {
+		return nil
+	}
+	dict := body[:start]
+	data := body[start+len("stream"):]
+	data = bytes.TrimPrefix(data, []byte("\r"))
+	data = bytes.TrimPrefix(data, []byte("\n"))
+	if end := bytes.LastIndex(data, []byte("endstream")); end >= 0 {
+		data = data[:end]
+	}
+
+	filter := pdfDictValue(dict, "Filter")
+	switch {
+	case len(filter) == 0:
+		return data
+	case bytes.Contains(filter, []byte("FlateDecode")) && bytes.Count(filter, []byte("/")) == 1:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil
+		}
+		defer zr.Close()
+		decoded, _ := io.ReadAll(io.LimitReader(zr, pdfMaxStreamBytes))
+		return decoded
+	default:
+		return nil
+	}
+}
+
+// pdfDictValue returns the raw value of /key in a dictionary: a string,
+// name, number, reference, array or nested dictionary. Nested
+// dictionaries are searched too, so keys should be specific to the
+// dictionary being read.
+func pdfDictValue(dict []byte, key string) []byte {
+	needle := []byte("/" + key)
+	for off := 0; off < len(dict); {
+		idx := bytes.Index(dict[off:], needle)
+		if idx < 0 {
+			return nil
+		}
+		pos := off + idx + len(needle)
+		off = pos
+		if pos < len(dict) && isPDFRegular(dict[pos]) {
+			continue
+		}
+		for pos < len(dict) && isPDFWhitespace(dict[pos]) {
+			pos++
+		}
+		return pdfValueAt(dict[pos:])
+	}
+	return nil
+}
+
+// pdfValueAt returns the value token at the start of b.
+func pdfValueAt(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	switch {
+	case b[0] == '(':
+		_, end := pdfLiteralString(b)
+		return b[:end]
+	case bytes.HasPrefix(b, []byte("<<")):
+		return b[:pdfBalanced(b, "<<", ">>")]
+	case b[0] == '<':
+		if end := bytes.IndexByte(b, '>'); end >= 0 {
+			return b[:end+1]
+		}
+		return b
+	case b[0] == '[':
+		return b[:pdfBalanced(b, "[", "]")]
+	case pdfRefPattern.Match(b):
+		return pdfRefPattern.Find(b)
+	case b[0] == '/':
+		end := 1
+		for end < len(b) && isPDFRegular(b[end]) {
+			end++
+		}
+		return b[:end]
+	default:
+		end := 0
+		for end < len(b) && isPDFRegular(b[end]) {
+			end++
+		}
+		return b[:end]
+	}
+}
+
+// pdfBalanced returns the length of the balanced open/close group that
+// starts b, skipping string literals.
+func pdfBalanced(b []byte, open, close string) int {
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '(':
+			_, end := pdfLiteralString(b[i:])
+			i += end - 1
+		case bytes.HasPrefix(b[i:], []byte(open)):
+			depth++
+			i += len(open) - 1
+		case bytes.HasPrefix(b[i:], []byte(close)):
+			depth--
+			i += len(close) - 1
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(b)
+}
+
+// pdfLiteralString decodes the literal string starting b and returns it
+// with the number of bytes consumed.
+func pdfLiteralString(b []byte) (string, int) {
+	var out []byte
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(out), i + 1
+			}
+		case '\\':
+			if i+1 >= len(b) {
+				return string(out), len(b)
+			}
+			i++
+			switch e := b[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation.
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					j := i
+					for j < len(b) && j < i+3 && b[j] >= '0' && b[j] <= '7' {
+						n = n*8 + int(b[j]-'0')
+						j++
+					}
+					out = append(out, byte(n))
+					i = j - 1
+					continue
+				}
+				out = append(out, e)
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return string(out), len(b)
+}
+
+// pdfString decodes a literal or hex string value into text, handling the
+// UTF-16BE encoding PDF uses for non-Latin text.
+func pdfString(value []byte) string {
+	var raw []byte
+	switch {
+	case len(value) == 0:
+		return ""
+	case value[0] == '(':
+		s, _ := pdfLiteralString(value)
+		raw = []byte(s)
+	case value[0] == '<' && !bytes.HasPrefix(value, []byte("<<")):
+		hex := strings.Join(strings.Fields(strings.Trim(string(value), "<>")), "")
+		if len(hex)%2 == 1 {
+			hex += "0"
+		}
+		for i := 0; i+1 < len(hex); i += 2 {
+			n, err := strconv.ParseUint(hex[i:i+2], 16, 8)
+			if err != nil {
+				return ""
+			}
+			raw = append(raw, byte(n))
+		}
+	default:
+		return ""
+	}
+
+	if len(raw) >= 2 && raw[0] == 0xfe && raw[1] == 0xff {
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		return strings.TrimSpace(string(utf16.Decode(units)))
+	}
+	return strings.TrimSpace(strings.ToValidUTF8(string(raw), ""))
+}
+
+// pdfName returns the name in a /Name value.
+func pdfName(value []byte) (string, bool) {
+	if len(value) < 2 || value[0] != '/' {
+		return "", false
+	}
+	return string(value[1:]), true
+}
+
+// pdfRef returns the object number of an indirect reference value.
+func pdfRef(value []byte) (int, bool) {
+	m := pdfRefPattern.FindSubmatch(value)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(string(m[1]))
+	return n, err == nil
+}
+
+// pdfRefs returns the object numbers referenced by a reference or an array
+// of references.
+func pdfRefs(value []byte) []int {
+	if n, ok := pdfRef(value); ok {
+		return []int{n}
+	}
+	if len(value) == 0 || value[0] != '[' {
+		return nil
+	}
+	var refs []int
+	fields := strings.Fields(strings.Trim(string(value), "[]"))
+	for i := 0; i+2 < len(fields); i++ {
+		if fields[i+2] != "R" {
+			continue
+		}
+		if n, err := strconv.Atoi(fields[i]); err == nil {
+			refs = append(refs, n)
+			i += 2
+		}
+	}
+	return refs
+}
+
+// isPDFWhitespace reports whether c is PDF whitespace.
+func isPDFWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+// isPDFRegular reports whether c is a regular (non-delimiter,
+// non-whitespace) PDF character.
+func isPDFRegular(c byte) bool {
+	if isPDFWhitespace(c) {
+		return false
+	}
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return false
+	}
+	return true
+}
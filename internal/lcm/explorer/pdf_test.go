@@ -1,6 +1,8 @@
 package explorer
 
 import (
+	"bytes"
+	"compress/zlib"
 	"context"
 	"fmt"
 	"os"
@@ -306,6 +308,10 @@ func TestHelperProcess(t *testing.T) {
 		fmt.Fprintf(os.Stdout, "%s", strings.Repeat("A", 3000))
 		os.Exit(0)
 
+	case "pdftotext-pages":
+		fmt.Fprintf(os.Stdout, "First page introduces the report.\fSecond page lists the results.\f")
+		os.Exit(0)
+
 	case "pdfinfo":
 		fmt.Fprintf(os.Stdout, "Title:          Test Document\nAuthor:         Test Author\nPages:          1\nPage size:      612 x 792 pts (letter)\n")
 		os.Exit(0)
@@ -400,3 +406,79 @@ func TestPDFExplorer_LookPathUsed(t *testing.T) {
 	require.NotContains(t, result.Summary, "Metadata")
 	require.NotContains(t, result.Summary, "Text content")
 }
+
+// makeStructuredPDF builds a two-page PDF with document info, a nested
+// outline kept in an object stream, and a Flate-encoded second page.
+func makeStructuredPDF(t *testing.T) []byte {
+	t.Helper()
+
+	var page2 bytes.Buffer
+	zw := zlib.NewWriter(&page2)
+	_, err := zw.Write([]byte("BT /F1 12 Tf 72 720 Td [(Res) 20 (ults) -250 (table)] TJ ET"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	items := []string{
+		"<< /Title (Introduction) /Parent 5 0 R /Next 7 0 R >>",
+		"<< /Title (Methods) /Parent 5 0 R /Prev 6 0 R /First 8 0 R /Last 8 0 R >>",
+		"<< /Title <FEFF00530061006D0070006C0069006E0067> /Parent 7 0 R >>",
+	}
+	var header, objects strings.Builder
+	for i, item := range items {
+		fmt.Fprintf(&header, "%d %d ", 6+i, objects.Len())
+		objects.WriteString(item + "\n")
+	}
+	outline, body := header.String(), header.String()+objects.String()
+
+	var b strings.Builder
+	b.WriteString("%PDF-1.7\n")
+	b.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R /Outlines 5 0 R >>\nendobj\n")
+	b.WriteString("2 0 obj\n<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>\nendobj\n")
+	b.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 10 0 R >>\nendobj\n")
+	b.WriteString("4 0 obj\n<< /Type /Page /Parent 2 0 R /Contents [11 0 R] >>\nendobj\n")
+	b.WriteString("5 0 obj\n<< /Type /Outlines /First 6 0 R /Last 7 0 R /Count 3 >>\nendobj\n")
+	fmt.Fprintf(&b, "9 0 obj\n<< /Type /ObjStm /N 3 /First %d /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(outline), len(body), body)
+	page1 := "BT /F1 12 Tf 72 720 Td (Quarterly report) Tj 0 -14 Td (for the \\(northern\\) region) Tj ET"
+	fmt.Fprintf(&b, "10 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(page1), page1)
+	fmt.Fprintf(&b, "11 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n", page2.Len(), page2.String())
+	b.WriteString("12 0 obj\n<< /Title (Quarterly Report) /Author (Finance Team) /Producer (xrush test) >>\nendobj\n")
+	b.WriteString("trailer\n<< /Root 1 0 R /Info 12 0 R /Size 13 >>\n%%EOF\n")
+	return []byte(b.String())
+}
+
+func TestPDFExplorer_Explore_NativeStructure(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	result, err := (&PDFExplorer{}).Explore(context.Background(), ExploreInput{
+		Path:    "report.pdf",
+		Content: makeStructuredPDF(t),
+	})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "PDF version: 1.7")
+	require.Contains(t, result.Summary, "Document info:\n  Pages: 2\n  Title: Quarterly Report\n  Author: Finance Team\n  Producer: xrush test\n")
+	require.Contains(t, result.Summary, "Outline:\n  1 Introduction\n  2 Methods\n  2.1 Sampling\n")
+	require.Contains(t, result.Summary, "Page samples:\n  Page 1: Quarterly report for the (northern) region\n  Page 2: Results table\n")
+	require.NotContains(t, result.Summary, "Metadata")
+}
+
+func TestPDFExplorer_Explore_PdftotextPages(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Skip("Cannot find test executable")
+	}
+
+	tmpDir := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\nexec %q -test.run=TestHelperProcess -- pdftotext-pages \"$@\"\n", self)
+	require.NoError(t, os.WriteFile(tmpDir+"/pdftotext", []byte(script), 0o755))
+
+	t.Setenv("PATH", tmpDir)
+	t.Setenv("GO_WANT_HELPER_PROCESS", "1")
+
+	result, err := (&PDFExplorer{}).Explore(context.Background(), ExploreInput{
+		Path:    "report.pdf",
+		Content: makeStructuredPDF(t),
+	})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "Page samples:\n  Page 1: First page introduces the report.\n  Page 2: Second page lists the results.\n")
+	require.NotContains(t, result.Summary, "Text content")
+}
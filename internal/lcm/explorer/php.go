@@ -0,0 +1,205 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer/stdlib"
+)
+
+// PHPExplorer explores PHP source files without tree-sitter: namespace,
+// use imports, classes, interfaces, traits, enums, functions, methods,
+// properties and constants with their visibility. It is a family-tier
+// explorer, so TreeSitterExplorer wins when built in.
+type PHPExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// phpDeclRe matches a declaration line: attributes, modifiers, the
+// introducing keyword and the rest of the line.
+var phpDeclRe = regexp.MustCompile(`^\s*((?:#\[(?:[^\[\]]|\[[^\]]*\])*\]\s*)*)((?:(?:public|protected|private|static|abstract|final|readonly)\s+)*)(namespace|use|class|interface|trait|enum|function|const|case)\b\s*`)
+
+var (
+	// phpPropertyRe matches a property: attributes, modifiers, which a
+	// property needs at least one of, an optional type and the name.
+	phpPropertyRe  = regexp.MustCompile(`^\s*((?:#\[(?:[^\[\]]|\[[^\]]*\])*\]\s*)*)((?:(?:public|protected|private|static|readonly|var)\s+)+)(?:[?\w\\|&()]+\s+)?\$(\w+)`)
+	phpNamespaceRe = regexp.MustCompile(`(?m)^\s*namespace\s+([\w\\]+)`)
+	phpNameRe      = regexp.MustCompile(`^&?\s*(\w+)`)
+	phpConstRe     = regexp.MustCompile(`^(?:[?\w\\|]+\s+)?(\w+)\s*=`)
+	phpReturnRe    = regexp.MustCompile(`^\s*:\s*([^{;]+?)\s*(?:[{;].*)?$`)
+	phpExtendsRe   = regexp.MustCompile(`\bextends\s+([\w\\,\s]+?)\s*(?:\bimplements\b|\{|$)`)
+	phpImplementRe = regexp.MustCompile(`\bimplements\s+([\w\\,\s]+?)\s*(?:\{|$)`)
+)
+
+func (e *PHPExplorer) CanHandle(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".php", ".phtml":
+		return true
+	case "":
+		return detectShebang(content) == "php"
+	}
+	return false
+}
+
+func (e *PHPExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("PHP file too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "php", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	orig := string(input.Content)
+	code, err := blankCodeLiterals(orig, phpLiterals)
+	var decls []codeDecl
+	if err == nil {
+		decls, err = scanDecls(code, orig, matchPHPDecl)
+	}
+	if err != nil {
+		return degradedTextResult("PHP file: "+name, "php", input.Content, codeDegradation("php", input.Content, decls, err)), nil
+	}
+	var namespace string
+	if m := phpNamespaceRe.FindStringSubmatch(code); m != nil {
+		namespace = m[1]
+	}
+	imports, decls := phpUses(decls, namespace)
+	return codeExploration{
+		header:      "PHP file: " + name,
+		lang:        "php",
+		pkg:         namespace,
+		pkgLabel:    "Namespace",
+		imports:     imports,
+		decls:       decls,
+		explorer:    "php",
+		returnArrow: ": ",
+	}.write(e.formatterProfile), nil
+}
+
+// matchPHPDecl recognizes a PHP declaration line. A use clause is matched
+// as a declaration of kind "use" named by the clause, for phpUses: at the
+// top level it imports, in a class body it uses traits.
+func matchPHPDecl(code, orig string, start, end int) (codeDecl, bool) {
+	line := code[start:end]
+	var d codeDecl
+	m := phpDeclRe.FindStringSubmatchIndex(line)
+	if m == nil {
+		p := phpPropertyRe.FindStringSubmatch(line)
+		if p == nil {
+			return codeDecl{}, false
+		}
+		d.attributes = bracketAttributes(p[1])
+		d.modifiers = strings.Fields(p[2])
+		d.name, d.kind = p[3], "variable"
+		return d, true
+	}
+	keyword := line[m[6]:m[7]]
+	restAt := start + m[1]
+	rest := code[restAt:end]
+	d.attributes = bracketAttributes(line[m[2]:m[3]])
+	d.modifiers = strings.Fields(line[m[4]:m[5]])
+
+	switch keyword {
+	case "namespace":
+		return codeDecl{kind: "namespace"}, true
+	case "use":
+		clause, _, _ := strings.Cut(rest, ";")
+		// A brace after a backslash opens a group use, otherwise a trait
+		// conflict resolution block.
+		if i := strings.IndexByte(clause, '{'); i > 0 && clause[i-1] != '\\' {
+			clause = clause[:i]
+		}
+		return codeDecl{name: strings.TrimSpace(clause), kind: "use"}, true
+	case "const":
+		c := phpConstRe.FindStringSubmatch(rest)
+		if c == nil {
+			return codeDecl{}, false
+		}
+		d.name, d.kind = c[1], "constant"
+		return d, true
+	case "case":
+		n := phpNameRe.FindStringSubmatch(rest)
+		if n == nil {
+			return codeDecl{}, false
+		}
+		d.name, d.kind = n[1], "case"
+		return d, true
+	}
+
+	n := phpNameRe.FindStringSubmatchIndex(rest)
+	if n == nil {
+		// Closures and anonymous classes.
+		return codeDecl{}, false
+	}
+	d.name = rest[n[2]:n[3]]
+	afterAt := restAt + n[1]
+	after := code[afterAt:end]
+	if keyword == "function" {
+		d.kind = "function"
+		i := strings.IndexByte(after, '(')
+		if i < 0 {
+			return d, true
+		}
+		var next int
+		d.params, next = balancedSpan(code, orig, afterAt+i, '(', ')')
+		tail, _, _ := strings.Cut(orig[next:], "\n")
+		if r := phpReturnRe.FindStringSubmatch(tail); r != nil {
+			d.returnType = strings.TrimSpace(r[1])
+		}
+		return d, true
+	}
+	d.kind, d.isType = keyword, true
+	for _, re := range []*regexp.Regexp{phpExtendsRe, phpImplementRe} {
+		if s := re.FindStringSubmatch(after); s != nil {
+			d.supertypes = append(d.supertypes, splitTopLevel(s[1])...)
+		}
+	}
+	return d, true
+}
+
+// phpUses splits the use clauses out of decls: top-level ones are the
+// file's imports, with group uses expanded and aliases dropped, and those
+// in a class body add the traits to the class's supertypes. Imports under
+// the root of the file's own namespace are local.
+func phpUses(decls []codeDecl, namespace string) ([]codeImport, []codeDecl) {
+	var imports []codeImport
+	seen := map[string]bool{}
+	root, _, _ := strings.Cut(namespace, `\`)
+	for _, d := range decls {
+		if d.kind != "use" {
+			continue
+		}
+		if d.owner >= 0 {
+			decls[d.owner].supertypes = append(decls[d.owner].supertypes, splitTopLevel(d.name)...)
+			continue
+		}
+		clause := strings.TrimPrefix(strings.TrimPrefix(d.name, "function "), "const ")
+		var paths []string
+		if prefix, group, ok := strings.Cut(clause, "{"); ok {
+			for _, p := range splitTopLevel(strings.TrimSuffix(strings.TrimSpace(group), "}")) {
+				paths = append(paths, prefix+p)
+			}
+		} else {
+			paths = splitTopLevel(clause)
+		}
+		for _, p := range paths {
+			p, _, _ = strings.Cut(p, " as ")
+			p = strings.TrimPrefix(strings.TrimSpace(p), `\`)
+			if p == "" || seen[p] {
+				continue
+			}
+			seen[p] = true
+			category := "third_party"
+			switch first, _, _ := strings.Cut(p, `\`); {
+			case !strings.Contains(p, `\`) && stdlib.IsPHPStdlib(p):
+				category = "stdlib"
+			case root != "" && first == root:
+				category = "local"
+			}
+			imports = append(imports, codeImport{path: p, category: category})
+		}
+	}
+	return imports, slices.DeleteFunc(decls, func(d codeDecl) bool { return d.kind == "use" })
+}
@@ -0,0 +1,159 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPHP = `<html><body>{ not code }</body></html>
+<?php
+
+declare(strict_types=1);
+
+namespace App\Models;
+
+use DateTimeImmutable;
+use Illuminate\Database\Eloquent\Model;
+use App\Contracts\{Auditable, HasOwner as Owned};
+use function Illuminate\Support\collect;
+
+# A hash comment with a { brace
+interface Named
+{
+    public function name(): string;
+}
+
+trait Timestamps
+{
+    protected ?DateTimeImmutable $createdAt = null;
+}
+
+#[Entity(table: 'users')]
+final class User extends Model implements Named, Auditable
+{
+    use Timestamps, SoftDeletes;
+
+    public const STATUS = 'active';
+    private static int $count = 0;
+    public readonly string $email;
+
+    public function __construct(
+        private string $first,
+        string $email,
+    ) {
+        $this->email = "{$email}";
+    }
+
+    #[Route('/users', methods: ['GET'])]
+    public function name(): string
+    {
+        $label = <<<EOT
+        } not a brace {
+        EOT;
+        return $this->first;
+    }
+
+    protected static function boot(): void {}
+
+    private function secret() { return '}'; }
+}
+
+enum Status: string
+{
+    case Active = 'active';
+    case Banned = 'banned';
+}
+
+function helper(int $a, int $b = 2): int
+{
+    return $a + $b;
+}
+?>
+<p>Trailing { html</p>
+`
+
+func TestPHPExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &PHPExplorer{}
+	require.True(t, e.CanHandle("app/Models/User.php", nil))
+	require.True(t, e.CanHandle("views/index.phtml", nil))
+	require.True(t, e.CanHandle("artisan", []byte("#!/usr/bin/env php\n<?php\n")))
+	require.False(t, e.CanHandle("composer.json", nil))
+}
+
+func TestPHPExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &PHPExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "User.php", Content: []byte(testPHP)})
+	require.NoError(t, err)
+	require.Equal(t, "php", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "PHP file: User.php\nLanguage: php\nNamespace: App\\Models\n"+
+		"Declarations: 16 (12 public, 2 protected, 2 private)\n")
+	require.Contains(t, s, "Imports:\n"+
+		"  - DateTimeImmutable (stdlib)\n"+
+		"  - Illuminate\\Database\\Eloquent\\Model (third_party)\n"+
+		"  - Illuminate\\Support\\collect (third_party)\n"+
+		"  - App\\Contracts\\Auditable (local)\n"+
+		"  - App\\Contracts\\HasOwner (local)\n")
+	require.Contains(t, s, "  - interface Named (public, line 14)\n  - method Named.name (public, line 16)\n")
+	require.Contains(t, s, "  - trait Timestamps (public, line 19)\n  - property Timestamps.createdAt (protected, line 21)\n")
+	require.Contains(t, s, "  - class User (public, line 25)\n"+
+		"  - constant User.STATUS (public, line 29)\n"+
+		"  - property User.count (private, line 30)\n"+
+		"  - property User.email (public, line 31)\n"+
+		"  - method User.__construct (public, line 33)\n"+
+		"  - method User.name (public, line 41)\n")
+	require.Contains(t, s, "  - method User.boot (protected, line 49)\n  - method User.secret (private, line 51)\n")
+	require.Contains(t, s, "  - enum Status (public, line 54)\n  - case Status.Active (public, line 56)\n")
+	require.True(t, strings.HasSuffix(s, "  - function helper (public, line 60)"))
+	// Promoted constructor parameters are not listed as members.
+	require.NotContains(t, s, "User.first")
+}
+
+func TestPHPExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &PHPExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "User.php", Content: []byte(testPHP)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "  - class User (public, final, #[Entity], line 25)\n")
+	require.Contains(t, s, "  - method User.name (public, #[Route], line 41): (): string\n")
+	require.Contains(t, s, "  - method User.boot (protected, static, line 49): (): void\n")
+	require.Contains(t, s, "  - property User.email (public, readonly, line 31)\n")
+	require.Contains(t, s, "  - function helper (public, line 60): (int $a, int $b = 2): int")
+	require.Contains(t, s, "Inheritance:\n"+
+		"  - User: Model, Named, Auditable, Timestamps, SoftDeletes")
+}
+
+func TestPHPExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	result, err := (&PHPExplorer{}).Explore(context.Background(), ExploreInput{
+		Path:    "a.php",
+		Content: []byte("<?php\nclass A {\n    function f() { return \"}; }\n}\n"),
+	})
+	require.NoError(t, err)
+	require.Regexp(t, degradedBlockPattern, result.Summary)
+	require.Contains(t, result.Summary, "Failed: php scanning at line 3, column 27: unterminated string literal\n")
+}
+
+func TestPHPExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "User.php", Content: []byte(testPHP)})
+		require.NoError(t, err)
+		require.Equal(t, "php", result.ExplorerUsed)
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Inheritance"))
+	}
+}
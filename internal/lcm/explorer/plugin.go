@@ -0,0 +1,149 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// pluginKind is the runtime path kind of registered explorers that do not
+// declare one.
+const pluginKind = "plugin_format"
+
+// RegisterOption configures an explorer added with RegisterExplorer.
+type RegisterOption func(*pluginExplorer)
+
+// RegisterBefore places the explorer directly before the named one, e.g.
+// "BinaryExplorer" so a proprietary binary format wins over the generic
+// binary summary.
+func RegisterBefore(name string) RegisterOption {
+	return func(p *pluginExplorer) { p.before = name }
+}
+
+// RegisterAfter places the explorer directly after the named one.
+func RegisterAfter(name string) RegisterOption {
+	return func(p *pluginExplorer) { p.after = name }
+}
+
+// RegisterSpecificity sets the specificity tier the explorer is dispatched
+// in. The default is SpecificityFamily.
+func RegisterSpecificity(tier SpecificityTier) RegisterOption {
+	return func(p *pluginExplorer) { p.tier = tier }
+}
+
+// RegisterKind sets the runtime path kind reported by DiscoverRuntimePaths.
+// The default is "plugin_format".
+func RegisterKind(kind string) RegisterOption {
+	return func(p *pluginExplorer) { p.kind = kind }
+}
+
+// RegisterGate consults enabled before each dispatch; while it returns
+// false the explorer never handles a file and is left out of
+// DiscoverRuntimePaths. Use it to tie the explorer to a config setting.
+func RegisterGate(enabled func() bool) RegisterOption {
+	return func(p *pluginExplorer) { p.enabled = enabled }
+}
+
+// pluginExplorer wraps an explorer added with RegisterExplorer with its
+// name, dispatch tier and gate.
+type pluginExplorer struct {
+	Explorer
+	name          string
+	kind          string
+	tier          SpecificityTier
+	before, after string
+	enabled       func() bool
+}
+
+func (p *pluginExplorer) active() bool {
+	return p.enabled == nil || p.enabled()
+}
+
+func (p *pluginExplorer) CanHandle(path string, content []byte) bool {
+	return p.active() && p.Explorer.CanHandle(path, content)
+}
+
+// Explore names the result after the registration when the wrapped
+// explorer leaves ExplorerUsed empty.
+func (p *pluginExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	result, err := p.Explorer.Explore(ctx, input)
+	if err == nil && strings.TrimSpace(result.ExplorerUsed) == "" {
+		result.ExplorerUsed = p.name
+	}
+	return result, err
+}
+
+func (p *pluginExplorer) specificityTier() SpecificityTier {
+	return p.tier
+}
+
+// ExploreStream delegates to the wrapped explorer when it streams.
+func (p *pluginExplorer) ExploreStream(ctx context.Context, path string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	se, ok := p.Explorer.(StreamExplorer)
+	if !ok {
+		return ExploreResult{}, ErrStreamUnsupported
+	}
+	return se.ExploreStream(ctx, path, r, size)
+}
+
+// RegisterExplorer adds e to the registry under name, so callers can
+// support formats of their own without changing this package. Without
+// RegisterBefore or RegisterAfter the explorer goes before TextExplorer,
+// so it is tried ahead of the generic text and fallback explorers. The
+// name must be unique and is what DiscoverRuntimePaths reports; runtime
+// inventory paths do not list it, so in the persistence matrix its results
+// are not persisted until the inventory does.
+//
+// RegisterExplorer must not be called concurrently with exploration.
+func (r *Registry) RegisterExplorer(name string, e Explorer, opts ...RegisterOption) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("register explorer: empty name")
+	}
+	if e == nil {
+		return fmt.Errorf("register explorer %q: nil explorer", name)
+	}
+	if r.explorerIndex(name) >= 0 {
+		return fmt.Errorf("register explorer %q: name already registered", name)
+	}
+
+	p := &pluginExplorer{Explorer: e, name: name, kind: pluginKind, tier: SpecificityFamily}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.before != "" && p.after != "" {
+		return fmt.Errorf("register explorer %q: both before and after set", name)
+	}
+
+	var at int
+	switch {
+	case p.before != "":
+		at = r.explorerIndex(p.before)
+		if at < 0 {
+			return fmt.Errorf("register explorer %q: unknown explorer %q", name, p.before)
+		}
+	case p.after != "":
+		at = r.explorerIndex(p.after)
+		if at < 0 {
+			return fmt.Errorf("register explorer %q: unknown explorer %q", name, p.after)
+		}
+		at++
+	default:
+		at = r.explorerIndex("TextExplorer")
+		if at < 0 {
+			at = len(r.explorers)
+		}
+	}
+	r.explorers = slices.Insert(r.explorers, at, Explorer(p))
+	return nil
+}
+
+// explorerIndex returns the position of the explorer with the given
+// canonical name, or -1.
+func (r *Registry) explorerIndex(name string) int {
+	return slices.IndexFunc(r.explorers, func(e Explorer) bool {
+		return explorerIdent(e) == name
+	})
+}
@@ -0,0 +1,109 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// magicExplorer handles files that start with a fixed magic number.
+type magicExplorer struct{}
+
+func (magicExplorer) CanHandle(_ string, content []byte) bool {
+	return bytes.HasPrefix(content, []byte("ACME\x00"))
+}
+
+func (magicExplorer) Explore(_ context.Context, input ExploreInput) (ExploreResult, error) {
+	return ExploreResult{Summary: "ACME container: " + input.Path}, nil
+}
+
+func TestRegisterExplorer_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	require.NoError(t, r.RegisterExplorer("AcmeExplorer", magicExplorer{}, RegisterBefore("BinaryExplorer")))
+
+	content := append([]byte("ACME\x00"), bytes.Repeat([]byte{0xff, 0x00}, 64)...)
+	result, err := r.Explore(context.Background(), ExploreInput{Path: "data.acme", Content: content})
+	require.NoError(t, err)
+	require.Equal(t, "AcmeExplorer", result.ExplorerUsed)
+	require.Contains(t, result.Summary, "ACME container")
+	require.Equal(t, SpecificityFamily, result.SpecificityTier)
+
+	// Files it does not recognize keep their built-in explorer.
+	result, err = r.Explore(context.Background(), ExploreInput{Path: "a.json", Content: []byte(`{}`)})
+	require.NoError(t, err)
+	require.Equal(t, "json", result.ExplorerUsed)
+
+	// Streaming is refused for explorers that need the whole content.
+	_, err = r.ExploreStream(context.Background(), "data.acme", bytes.NewReader(content), int64(len(content)))
+	require.ErrorIs(t, err, ErrStreamUnsupported)
+}
+
+func TestRegisterExplorer_Ordering(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	require.NoError(t, r.RegisterExplorer("Default", magicExplorer{}))
+	require.NoError(t, r.RegisterExplorer("AfterJSON", magicExplorer{}, RegisterAfter("JSONExplorer")))
+
+	require.Equal(t, r.explorerIndex("TextExplorer")-1, r.explorerIndex("Default"))
+	require.Equal(t, r.explorerIndex("JSONExplorer")+1, r.explorerIndex("AfterJSON"))
+
+	for _, tc := range []struct {
+		name string
+		opts []RegisterOption
+	}{
+		{"", nil},
+		{"Default", nil},
+		{"JSONExplorer", nil},
+		{"Orphan", []RegisterOption{RegisterBefore("NoSuchExplorer")}},
+		{"Both", []RegisterOption{RegisterBefore("JSONExplorer"), RegisterAfter("JSONExplorer")}},
+	} {
+		require.Error(t, r.RegisterExplorer(tc.name, magicExplorer{}, tc.opts...), tc.name)
+	}
+	require.Error(t, r.RegisterExplorer("Nil", nil))
+}
+
+func TestRegisterExplorer_GateAndDiscovery(t *testing.T) {
+	t.Parallel()
+
+	enabled := false
+	r := NewRegistry()
+	require.NoError(t, r.RegisterExplorer("AcmeExplorer", magicExplorer{},
+		RegisterBefore("BinaryExplorer"),
+		RegisterKind("acme_format_native"),
+		RegisterGate(func() bool { return enabled }),
+	))
+
+	discovered := func() map[string]DiscoveredPath {
+		out := make(map[string]DiscoveredPath)
+		for i, p := range DiscoverRuntimePaths(r, OutputProfileParity) {
+			require.Equal(t, i+1, p.Position)
+			out[p.ExplorerName] = p
+		}
+		return out
+	}
+
+	content := []byte("ACME\x00 plain enough to be text")
+	result, err := r.Explore(context.Background(), ExploreInput{Path: "data.acme", Content: content})
+	require.NoError(t, err)
+	require.NotEqual(t, "AcmeExplorer", result.ExplorerUsed)
+	require.NotContains(t, discovered(), "AcmeExplorer")
+
+	enabled = true
+	result, err = r.Explore(context.Background(), ExploreInput{Path: "data.acme", Content: content})
+	require.NoError(t, err)
+	require.Equal(t, "AcmeExplorer", result.ExplorerUsed)
+	paths := discovered()
+	require.Equal(t, "acme_format_native", paths["AcmeExplorer"].Kind)
+	require.Equal(t, paths["BinaryExplorer"].Position-1, paths["AcmeExplorer"].Position)
+
+	// Registered explorers are not in the runtime inventory, so their
+	// results are never persisted by the matrix.
+	matrix, err := LoadRuntimePersistenceMatrix(OutputProfileEnhancement)
+	require.NoError(t, err)
+	require.False(t, matrix.PolicyForExplorer("AcmeExplorer").Persist)
+}
@@ -0,0 +1,969 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ProtoExplorer explores Protocol Buffers schemas (.proto): syntax,
+// package, imports, messages, enums, services and their RPC methods.
+type ProtoExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// protoMaxDetails caps the fields and enum values listed in enhancement
+// output.
+const protoMaxDetails = 200
+
+// protoSymbols are the punctuation characters that form tokens on their
+// own.
+const protoSymbols = "{}[]()<>;=,:"
+
+// protoScalarTypes are the built-in field types.
+var protoScalarTypes = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true,
+	"uint64": true, "sint32": true, "sint64": true, "fixed32": true, "fixed64": true,
+	"sfixed32": true, "sfixed64": true, "bool": true, "string": true, "bytes": true,
+}
+
+// protoHTTPVerbs are the google.api.http rule fields that bind a method.
+var protoHTTPVerbs = map[string]bool{"get": true, "put": true, "post": true, "delete": true, "patch": true}
+
+// protoThirdPartyPrefixes are import paths of widely shared schemas that
+// are not part of the protobuf distribution.
+var protoThirdPartyPrefixes = []string{
+	"google/api/", "google/rpc/", "google/type/", "google/longrunning/",
+	"validate/", "buf/validate/", "gogoproto/", "protoc-gen-openapiv2/",
+}
+
+type protoImport struct {
+	path     string
+	modifier string // public, weak or ""
+}
+
+type protoField struct {
+	label  string // repeated, optional, required or ""
+	typ    string
+	name   string
+	number string
+	oneof  string
+}
+
+type protoMessage struct {
+	// name is qualified within the file, e.g. Invoice.LineItem.
+	name   string
+	line   int
+	fields []protoField
+	oneofs int
+}
+
+type protoEnum struct {
+	name   string
+	line   int
+	values []string
+}
+
+type protoMethod struct {
+	name                       string
+	input, output              string
+	clientStream, serverStream bool
+	// http is the google.api.http binding, e.g. "GET /v1/{name}".
+	http string
+}
+
+type protoService struct {
+	name    string
+	line    int
+	methods []protoMethod
+}
+
+type protoExtend struct {
+	target string
+	fields int
+}
+
+// protoFile is the parsed content of a schema.
+type protoFile struct {
+	syntax   string
+	pkg      string
+	imports  []protoImport
+	options  []string
+	messages []*protoMessage
+	enums    []*protoEnum
+	services []*protoService
+	extends  []protoExtend
+}
+
+type protoToken struct {
+	text   string
+	offset int
+	quoted bool
+}
+
+// protoSyntaxError is a tokenizer or parser error at a byte offset.
+type protoSyntaxError struct {
+	offset int
+	msg    string
+}
+
+func (e *protoSyntaxError) Error() string { return e.msg }
+
+func (e *ProtoExplorer) CanHandle(path string, content []byte) bool {
+	return strings.EqualFold(filepath.Ext(path), ".proto")
+}
+
+func (e *ProtoExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Protobuf schema too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "proto", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	p := &protoParser{content: input.Content}
+	toks, err := tokenizeProto(input.Content)
+	if err == nil {
+		p.toks = toks
+		err = p.parse()
+	}
+	if err != nil {
+		return degradedTextResult("Protobuf schema: "+name, "proto", input.Content, protoDegradation(input.Content, &p.file, err)), nil
+	}
+	file := &p.file
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Protobuf schema: %s\n", name)
+	if file.syntax != "" {
+		fmt.Fprintf(&summary, "Syntax: %s\n", file.syntax)
+	} else {
+		summary.WriteString("Syntax: proto2 (not declared)\n")
+	}
+	if file.pkg != "" {
+		fmt.Fprintf(&summary, "Package: %s\n", file.pkg)
+	}
+	methods := 0
+	for _, svc := range file.services {
+		methods += len(svc.methods)
+	}
+	fmt.Fprintf(&summary, "Declarations: %d messages, %d enums, %d services, %d RPC methods\n",
+		len(file.messages), len(file.enums), len(file.services), methods)
+
+	if len(file.imports) > 0 {
+		summary.WriteString("\nImports:\n")
+		for _, imp := range file.imports {
+			note := protoImportCategory(imp.path)
+			if imp.modifier != "" {
+				note += ", " + imp.modifier
+			}
+			fmt.Fprintf(&summary, "  - %s (%s)\n", imp.path, note)
+		}
+	}
+	if len(file.options) > 0 {
+		summary.WriteString("\nOptions:\n")
+		for _, opt := range file.options {
+			fmt.Fprintf(&summary, "  - %s\n", opt)
+		}
+	}
+	if len(file.services) > 0 {
+		summary.WriteString("\nServices:\n")
+		for _, svc := range file.services {
+			fmt.Fprintf(&summary, "  - %s (%d methods, line %d)\n", svc.name, len(svc.methods), svc.line)
+		}
+		summary.WriteString("\nRPC methods:\n")
+		for _, svc := range file.services {
+			for _, m := range svc.methods {
+				fmt.Fprintf(&summary, "  - %s.%s(%s) returns (%s)\n", svc.name, m.name,
+					protoStreamType(m.input, m.clientStream), protoStreamType(m.output, m.serverStream))
+			}
+		}
+	}
+	if len(file.messages) > 0 {
+		summary.WriteString("\nMessages:\n")
+		for _, msg := range file.messages {
+			oneofs := ""
+			if msg.oneofs > 0 {
+				oneofs = fmt.Sprintf(", %d oneofs", msg.oneofs)
+			}
+			fmt.Fprintf(&summary, "  - %s (%d fields%s, line %d)\n", msg.name, len(msg.fields), oneofs, msg.line)
+		}
+	}
+	if len(file.enums) > 0 {
+		summary.WriteString("\nEnums:\n")
+		for _, enum := range file.enums {
+			fmt.Fprintf(&summary, "  - %s (%d values, line %d)\n", enum.name, len(enum.values), enum.line)
+		}
+	}
+	if len(file.extends) > 0 {
+		summary.WriteString("\nExtensions:\n")
+		for _, ext := range file.extends {
+			fmt.Fprintf(&summary, "  - %s (%d fields)\n", ext.target, ext.fields)
+		}
+	}
+
+	// EXCEED MODE: field layouts, enum values, HTTP bindings and the type
+	// dependency graph.
+	if e.formatterProfile == OutputProfileEnhancement {
+		writeProtoFields(&summary, file)
+		writeProtoEnumValues(&summary, file)
+		writeProtoHTTPBindings(&summary, file)
+		writeProtoDependencies(&summary, file)
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "proto",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// protoDegradation describes a schema that failed to tokenize or parse.
+func protoDegradation(content []byte, file *protoFile, err error) degradedExploration {
+	size := int64(len(content))
+	offset := len(content)
+	var syntaxErr *protoSyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.offset
+	}
+	line, col := lineColumn(content, int64(offset))
+	return degradedExploration{
+		Failed: fmt.Sprintf("protobuf parsing at line %d, column %d: %v", line, col, err),
+		Progress: fmt.Sprintf("parsed %d messages, %d enums and %d services before the error",
+			len(file.messages), len(file.enums), len(file.services)),
+		Examined: int64(offset),
+		Size:     size,
+		NextSteps: []string{
+			"Run protoc or buf lint on the file to locate the syntax error",
+			"Read the raw content around the error with the view tool",
+		},
+	}
+}
+
+// protoImportCategory classifies an import path like the code explorers
+// classify imports: well-known types are the protobuf standard library.
+func protoImportCategory(path string) string {
+	switch {
+	case strings.HasPrefix(path, "google/protobuf/"):
+		return "stdlib"
+	case slices.ContainsFunc(protoThirdPartyPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(path, prefix)
+	}):
+		return "third_party"
+	}
+	return "local"
+}
+
+func protoStreamType(typ string, stream bool) string {
+	if stream {
+		return "stream " + typ
+	}
+	return typ
+}
+
+func writeProtoFields(summary *strings.Builder, file *protoFile) {
+	var lines []string
+	for _, msg := range file.messages {
+		for _, f := range msg.fields {
+			typ := strings.TrimSpace(f.label + " " + f.typ)
+			line := fmt.Sprintf("%s.%s: %s = %s", msg.name, f.name, typ, f.number)
+			if f.oneof != "" {
+				line += " (oneof " + f.oneof + ")"
+			}
+			lines = append(lines, line)
+		}
+	}
+	writeProtoSection(summary, "Fields", lines)
+}
+
+func writeProtoEnumValues(summary *strings.Builder, file *protoFile) {
+	var lines []string
+	for _, enum := range file.enums {
+		for _, value := range enum.values {
+			lines = append(lines, enum.name+"."+value)
+		}
+	}
+	writeProtoSection(summary, "Enum values", lines)
+}
+
+func writeProtoHTTPBindings(summary *strings.Builder, file *protoFile) {
+	var lines []string
+	for _, svc := range file.services {
+		for _, m := range svc.methods {
+			if m.http != "" {
+				lines = append(lines, fmt.Sprintf("%s.%s: %s", svc.name, m.name, m.http))
+			}
+		}
+	}
+	writeProtoSection(summary, "HTTP bindings", lines)
+}
+
+// writeProtoDependencies lists the types each message and service refers
+// to, resolved against the declarations of the file. Unresolved names are
+// kept as written; they come from imports.
+func writeProtoDependencies(summary *strings.Builder, file *protoFile) {
+	declared := make(map[string]bool)
+	for _, msg := range file.messages {
+		declared[msg.name] = true
+	}
+	for _, enum := range file.enums {
+		declared[enum.name] = true
+	}
+	resolve := func(scope, typ string) string {
+		if qualified, ok := strings.CutPrefix(typ, "."); ok {
+			if local, ok := strings.CutPrefix(qualified, file.pkg+"."); ok && file.pkg != "" && declared[local] {
+				return local
+			}
+			return qualified
+		}
+		// Like protoc, look in the enclosing scopes from the innermost out.
+		for {
+			if candidate := strings.TrimPrefix(scope+"."+typ, "."); declared[candidate] {
+				return candidate
+			}
+			if scope == "" {
+				return typ
+			}
+			scope = scope[:max(strings.LastIndexByte(scope, '.'), 0)]
+		}
+	}
+
+	var lines []string
+	add := func(from string, deps []string) {
+		slices.Sort(deps)
+		deps = slices.Compact(deps)
+		if len(deps) > 0 {
+			lines = append(lines, from+" -> "+strings.Join(deps, ", "))
+		}
+	}
+	for _, msg := range file.messages {
+		var deps []string
+		for _, f := range msg.fields {
+			for _, typ := range protoFieldTypes(f.typ) {
+				if !protoScalarTypes[typ] && typ != "group" {
+					deps = append(deps, resolve(msg.name, typ))
+				}
+			}
+		}
+		add(msg.name, deps)
+	}
+	for _, svc := range file.services {
+		var deps []string
+		for _, m := range svc.methods {
+			deps = append(deps, resolve("", m.input), resolve("", m.output))
+		}
+		add(svc.name, deps)
+	}
+	writeProtoSection(summary, "Type dependencies", lines)
+}
+
+// protoFieldTypes returns the type names of a field, splitting map<K, V>.
+func protoFieldTypes(typ string) []string {
+	if inner, ok := strings.CutPrefix(typ, "map<"); ok {
+		key, value, _ := strings.Cut(strings.TrimSuffix(inner, ">"), ", ")
+		return []string{key, value}
+	}
+	return []string{typ}
+}
+
+func writeProtoSection(summary *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n%s:\n", title)
+	for _, line := range lines[:min(len(lines), protoMaxDetails)] {
+		fmt.Fprintf(summary, "  - %s\n", line)
+	}
+}
+
+// tokenizeProto splits a schema into identifiers, numbers, string literals
+// and punctuation, dropping comments.
+func tokenizeProto(content []byte) ([]protoToken, error) {
+	var toks []protoToken
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case bytes.HasPrefix(content[i:], []byte("//")):
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case bytes.HasPrefix(content[i:], []byte("/*")):
+			end := bytes.Index(content[i+2:], []byte("*/"))
+			if end < 0 {
+				return toks, &protoSyntaxError{offset: i, msg: "unterminated block comment"}
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			start := i
+			var text strings.Builder
+			for i++; i < len(content) && content[i] != c; i++ {
+				if content[i] == '\n' {
+					break
+				}
+				if content[i] == '\\' && i+1 < len(content) {
+					i++
+				}
+				text.WriteByte(content[i])
+			}
+			if i >= len(content) || content[i] != c {
+				return toks, &protoSyntaxError{offset: start, msg: "unterminated string literal"}
+			}
+			i++
+			toks = append(toks, protoToken{text: text.String(), offset: start, quoted: true})
+		case strings.IndexByte(protoSymbols, c) >= 0:
+			toks = append(toks, protoToken{text: string(c), offset: i})
+			i++
+		default:
+			start := i
+			for i < len(content) && !bytes.ContainsAny(content[i:i+1], " \t\n\r\f\v\"'"+protoSymbols) &&
+				!bytes.HasPrefix(content[i:], []byte("//")) && !bytes.HasPrefix(content[i:], []byte("/*")) {
+				i++
+			}
+			toks = append(toks, protoToken{text: string(content[start:i]), offset: start})
+		}
+	}
+	return toks, nil
+}
+
+// protoParser is a recursive-descent parser for the declarations of a
+// schema. Option values, reserved ranges and field options are skipped
+// rather than validated.
+type protoParser struct {
+	content []byte
+	toks    []protoToken
+	pos     int
+	file    protoFile
+}
+
+func (p *protoParser) parse() error {
+	for p.pos < len(p.toks) {
+		tok := p.toks[p.pos]
+		if tok.quoted {
+			return p.errorAt(tok, "unexpected string %q", tok.text)
+		}
+		p.pos++
+		var err error
+		switch tok.text {
+		case ";":
+		case "syntax", "edition":
+			err = p.parseSyntax(tok.text)
+		case "package":
+			if p.file.pkg, err = p.ident(); err == nil {
+				err = p.expect(";")
+			}
+		case "import":
+			err = p.parseImport()
+		case "option":
+			var toks []protoToken
+			if toks, err = p.statement(); err == nil {
+				p.file.options = append(p.file.options, protoOptionString(toks))
+			}
+		case "message":
+			err = p.parseMessage("", tok)
+		case "enum":
+			err = p.parseEnum("", tok)
+		case "service":
+			err = p.parseService(tok)
+		case "extend":
+			err = p.parseExtend()
+		default:
+			return p.errorAt(tok, "unexpected %q at top level", tok.text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *protoParser) parseSyntax(keyword string) error {
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	value, err := p.str()
+	if err != nil {
+		return err
+	}
+	if keyword == "edition" {
+		value = "edition " + value
+	}
+	p.file.syntax = value
+	return p.expect(";")
+}
+
+func (p *protoParser) parseImport() error {
+	var imp protoImport
+	if p.is("public") || p.is("weak") {
+		imp.modifier = p.toks[p.pos].text
+		p.pos++
+	}
+	path, err := p.str()
+	if err != nil {
+		return err
+	}
+	imp.path = path
+	p.file.imports = append(p.file.imports, imp)
+	return p.expect(";")
+}
+
+func (p *protoParser) parseMessage(prefix string, keyword protoToken) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	msg := &protoMessage{name: prefix + name, line: p.line(keyword)}
+	p.file.messages = append(p.file.messages, msg)
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	return p.parseMessageBody(msg)
+}
+
+// parseMessageBody parses message members up to and including the closing
+// brace. Extend blocks share it to collect their fields.
+func (p *protoParser) parseMessageBody(msg *protoMessage) error {
+	for {
+		if p.pos >= len(p.toks) {
+			return p.errorf("unterminated message %s", msg.name)
+		}
+		tok := p.toks[p.pos]
+		var err error
+		switch {
+		case p.is("}"):
+			p.pos++
+			return nil
+		case p.is(";"):
+			p.pos++
+		case p.is("message"):
+			p.pos++
+			err = p.parseMessage(msg.name+".", tok)
+		case p.is("enum"):
+			p.pos++
+			err = p.parseEnum(msg.name+".", tok)
+		case p.is("extend"):
+			p.pos++
+			err = p.parseExtend()
+		case p.is("oneof"):
+			p.pos++
+			err = p.parseOneof(msg)
+		case p.is("option"), p.is("reserved"), p.is("extensions"):
+			p.pos++
+			_, err = p.statement()
+		default:
+			err = p.parseField(msg, "")
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *protoParser) parseOneof(msg *protoMessage) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	msg.oneofs++
+	for {
+		var err error
+		switch {
+		case p.pos >= len(p.toks):
+			return p.errorf("unterminated oneof %s", name)
+		case p.is("}"):
+			p.pos++
+			return nil
+		case p.is(";"):
+			p.pos++
+		case p.is("option"):
+			p.pos++
+			_, err = p.statement()
+		default:
+			err = p.parseField(msg, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *protoParser) parseField(msg *protoMessage, oneof string) error {
+	start := p.toks[p.pos]
+	f := protoField{oneof: oneof}
+	if p.is("repeated") || p.is("optional") || p.is("required") {
+		f.label = p.toks[p.pos].text
+		p.pos++
+	}
+	typ, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if typ == "map" && p.is("<") {
+		p.pos++
+		key, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+		value, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(">"); err != nil {
+			return err
+		}
+		typ = "map<" + key + ", " + value + ">"
+	}
+	f.typ = typ
+	if f.name, err = p.ident(); err != nil {
+		return err
+	}
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	if f.number, err = p.ident(); err != nil {
+		return err
+	}
+	if p.is("[") {
+		if err := p.skipGroup("[", "]"); err != nil {
+			return err
+		}
+	}
+	msg.fields = append(msg.fields, f)
+
+	// Proto2 groups declare a nested message inline.
+	if typ == "group" && p.is("{") {
+		p.pos++
+		group := &protoMessage{name: msg.name + "." + f.name, line: p.line(start)}
+		p.file.messages = append(p.file.messages, group)
+		return p.parseMessageBody(group)
+	}
+	return p.expect(";")
+}
+
+func (p *protoParser) parseEnum(prefix string, keyword protoToken) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	enum := &protoEnum{name: prefix + name, line: p.line(keyword)}
+	p.file.enums = append(p.file.enums, enum)
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		switch {
+		case p.pos >= len(p.toks):
+			return p.errorf("unterminated enum %s", enum.name)
+		case p.is("}"):
+			p.pos++
+			return nil
+		case p.is(";"):
+			p.pos++
+		case p.is("option"), p.is("reserved"):
+			p.pos++
+			if _, err := p.statement(); err != nil {
+				return err
+			}
+		default:
+			value, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			number, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if p.is("[") {
+				if err := p.skipGroup("[", "]"); err != nil {
+					return err
+				}
+			}
+			enum.values = append(enum.values, value+" = "+number)
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *protoParser) parseService(keyword protoToken) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	svc := &protoService{name: name, line: p.line(keyword)}
+	p.file.services = append(p.file.services, svc)
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		var err error
+		switch {
+		case p.pos >= len(p.toks):
+			return p.errorf("unterminated service %s", name)
+		case p.is("}"):
+			p.pos++
+			return nil
+		case p.is(";"):
+			p.pos++
+		case p.is("option"):
+			p.pos++
+			_, err = p.statement()
+		case p.is("rpc"):
+			p.pos++
+			err = p.parseRPC(svc)
+		default:
+			return p.errorAt(p.toks[p.pos], "unexpected %q in service %s", p.toks[p.pos].text, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *protoParser) parseRPC(svc *protoService) error {
+	var m protoMethod
+	var err error
+	if m.name, err = p.ident(); err != nil {
+		return err
+	}
+	if m.input, m.clientStream, err = p.rpcType(); err != nil {
+		return err
+	}
+	if err := p.expect("returns"); err != nil {
+		return err
+	}
+	if m.output, m.serverStream, err = p.rpcType(); err != nil {
+		return err
+	}
+	if p.is("{") {
+		p.pos++
+		for !p.is("}") {
+			switch {
+			case p.pos >= len(p.toks):
+				return p.errorf("unterminated rpc %s", m.name)
+			case p.is(";"):
+				p.pos++
+			case p.is("option"):
+				p.pos++
+				toks, err := p.statement()
+				if err != nil {
+					return err
+				}
+				if m.http == "" {
+					m.http = protoHTTPRule(toks)
+				}
+			default:
+				return p.errorAt(p.toks[p.pos], "unexpected %q in rpc %s", p.toks[p.pos].text, m.name)
+			}
+		}
+		p.pos++
+	} else if err := p.expect(";"); err != nil {
+		return err
+	}
+	svc.methods = append(svc.methods, m)
+	return nil
+}
+
+// rpcType parses a parenthesized request or response type.
+func (p *protoParser) rpcType() (string, bool, error) {
+	if err := p.expect("("); err != nil {
+		return "", false, err
+	}
+	stream := false
+	if p.is("stream") && p.pos+1 < len(p.toks) && p.toks[p.pos+1].text != ")" {
+		stream = true
+		p.pos++
+	}
+	typ, err := p.ident()
+	if err != nil {
+		return "", false, err
+	}
+	return typ, stream, p.expect(")")
+}
+
+func (p *protoParser) parseExtend() error {
+	target, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	fields := &protoMessage{name: target}
+	if err := p.parseMessageBody(fields); err != nil {
+		return err
+	}
+	p.file.extends = append(p.file.extends, protoExtend{target: target, fields: len(fields.fields)})
+	return nil
+}
+
+// statement consumes tokens up to and including the next semicolon
+// outside brackets and returns them without it.
+func (p *protoParser) statement() ([]protoToken, error) {
+	start := p.pos
+	depth := 0
+	for ; p.pos < len(p.toks); p.pos++ {
+		tok := p.toks[p.pos]
+		if tok.quoted {
+			continue
+		}
+		switch tok.text {
+		case "{", "[", "(":
+			depth++
+		case "}", "]", ")":
+			depth--
+		case ";":
+			if depth == 0 {
+				p.pos++
+				return p.toks[start : p.pos-1], nil
+			}
+		}
+	}
+	return nil, p.errorf("unterminated statement")
+}
+
+// skipGroup consumes a bracketed group, such as field options.
+func (p *protoParser) skipGroup(open, close string) error {
+	depth := 0
+	for ; p.pos < len(p.toks); p.pos++ {
+		switch {
+		case p.is(open):
+			depth++
+		case p.is(close):
+			depth--
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+		}
+	}
+	return p.errorf("unterminated %s", open)
+}
+
+// is reports whether the current token is the unquoted text.
+func (p *protoParser) is(text string) bool {
+	return p.pos < len(p.toks) && !p.toks[p.pos].quoted && p.toks[p.pos].text == text
+}
+
+func (p *protoParser) expect(text string) error {
+	if !p.is(text) {
+		return p.unexpected("expected %q", text)
+	}
+	p.pos++
+	return nil
+}
+
+// ident consumes a name, number or other bare word.
+func (p *protoParser) ident() (string, error) {
+	if p.pos >= len(p.toks) {
+		return "", p.errorf("unexpected end of input")
+	}
+	tok := p.toks[p.pos]
+	if tok.quoted || (len(tok.text) == 1 && strings.Contains(protoSymbols, tok.text)) {
+		return "", p.unexpected("expected a name")
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+func (p *protoParser) str() (string, error) {
+	if p.pos >= len(p.toks) || !p.toks[p.pos].quoted {
+		return "", p.unexpected("expected a string")
+	}
+	p.pos++
+	return p.toks[p.pos-1].text, nil
+}
+
+func (p *protoParser) unexpected(format string, args ...any) error {
+	if p.pos >= len(p.toks) {
+		return p.errorf(format+", got end of input", args...)
+	}
+	tok := p.toks[p.pos]
+	return p.errorAt(tok, format+", got %q", append(args, tok.text)...)
+}
+
+// errorf reports an error at the current token, or at the end of input.
+func (p *protoParser) errorf(format string, args ...any) error {
+	if p.pos < len(p.toks) {
+		return p.errorAt(p.toks[p.pos], format, args...)
+	}
+	return &protoSyntaxError{offset: len(p.content), msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *protoParser) errorAt(tok protoToken, format string, args ...any) error {
+	return &protoSyntaxError{offset: tok.offset, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *protoParser) line(tok protoToken) int {
+	line, _ := lineColumn(p.content, int64(tok.offset))
+	return line
+}
+
+// protoOptionString renders an option statement as "name = value".
+func protoOptionString(toks []protoToken) string {
+	var name, value []string
+	target := &name
+	for _, tok := range toks {
+		switch {
+		case tok.quoted:
+			*target = append(*target, strconv.Quote(tok.text))
+		case tok.text == "=" && target == &name:
+			target = &value
+		default:
+			*target = append(*target, tok.text)
+		}
+	}
+	return strings.Join(name, "") + " = " + strings.Join(value, " ")
+}
+
+// protoHTTPRule returns the binding of a google.api.http method option,
+// e.g. "GET /v1/{name=shelves/*}", or "" for other options. Additional
+// bindings are ignored.
+func protoHTTPRule(toks []protoToken) string {
+	eq := slices.IndexFunc(toks, func(tok protoToken) bool { return !tok.quoted && tok.text == "=" })
+	if eq < 0 {
+		return ""
+	}
+	var name strings.Builder
+	for _, tok := range toks[:eq] {
+		name.WriteString(tok.text)
+	}
+	value := toks[eq+1:]
+	if verb, ok := strings.CutPrefix(name.String(), "(google.api.http)."); ok {
+		if protoHTTPVerbs[verb] && len(value) == 1 && value[0].quoted {
+			return strings.ToUpper(verb) + " " + value[0].text
+		}
+		return ""
+	}
+	if name.String() != "(google.api.http)" {
+		return ""
+	}
+	depth := 0
+	for i, tok := range value {
+		switch {
+		case tok.quoted:
+		case tok.text == "{":
+			depth++
+		case tok.text == "}":
+			depth--
+		case depth == 1 && protoHTTPVerbs[tok.text] && i+2 < len(value) && value[i+1].text == ":" && value[i+2].quoted:
+			return strings.ToUpper(tok.text) + " " + value[i+2].text
+		}
+	}
+	return ""
+}
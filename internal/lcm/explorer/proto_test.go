@@ -0,0 +1,213 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testProto = `// Billing API.
+syntax = "proto3";
+
+package acme.billing.v1;
+
+import "google/protobuf/timestamp.proto";
+import "google/api/annotations.proto";
+import public "acme/common/v1/money.proto";
+
+option go_package = "github.com/acme/billing/v1;billingv1";
+
+/* Invoices are immutable once issued. */
+service InvoiceService {
+  option (acme.auth.scope) = "billing";
+
+  rpc GetInvoice(GetInvoiceRequest) returns (Invoice) {
+    option (google.api.http) = {
+      get: "/v1/{name=invoices/*}"
+    };
+  }
+  rpc WatchInvoices(WatchInvoicesRequest) returns (stream Invoice);
+  rpc UploadLines(stream Invoice.LineItem) returns (Invoice) {
+    option (google.api.http).post = "/v1/invoices:upload";
+  }
+}
+
+message Invoice {
+  message LineItem {
+    string sku = 1;
+    acme.common.v1.Money price = 2;
+  }
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_OPEN = 1;
+    STATUS_PAID = 2 [deprecated = true];
+  }
+
+  string name = 1;
+  repeated LineItem items = 2;
+  Status status = 3;
+  google.protobuf.Timestamp issued = 4;
+  map<string, string> labels = 5 [json_name = "labels"];
+  oneof payer {
+    string account = 6;
+    string card = 7;
+  }
+  reserved 8, 10 to 12;
+}
+
+message GetInvoiceRequest {
+  string name = 1;
+}
+
+message WatchInvoicesRequest {
+  string filter = 1;
+}
+
+enum Region {
+  REGION_UNSPECIFIED = 0;
+  REGION_EU = 1;
+}
+`
+
+func TestProtoExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &ProtoExplorer{}
+	require.True(t, e.CanHandle("api.proto", nil))
+	require.True(t, e.CanHandle("API.PROTO", nil))
+	require.False(t, e.CanHandle("api.protobuf.json", nil))
+}
+
+func TestProtoExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &ProtoExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "billing.proto", Content: []byte(testProto)})
+	require.NoError(t, err)
+	require.Equal(t, "proto", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Protobuf schema: billing.proto\n")
+	require.Contains(t, s, "Syntax: proto3\n")
+	require.Contains(t, s, "Package: acme.billing.v1\n")
+	require.Contains(t, s, "Declarations: 4 messages, 2 enums, 1 services, 3 RPC methods\n")
+	require.Contains(t, s, "Imports:\n"+
+		"  - google/protobuf/timestamp.proto (stdlib)\n"+
+		"  - google/api/annotations.proto (third_party)\n"+
+		"  - acme/common/v1/money.proto (local, public)\n")
+	require.Contains(t, s, "Options:\n  - go_package = \"github.com/acme/billing/v1;billingv1\"\n")
+	require.Contains(t, s, "Services:\n  - InvoiceService (3 methods, line 13)\n")
+	require.Contains(t, s, "RPC methods:\n"+
+		"  - InvoiceService.GetInvoice(GetInvoiceRequest) returns (Invoice)\n"+
+		"  - InvoiceService.WatchInvoices(WatchInvoicesRequest) returns (stream Invoice)\n"+
+		"  - InvoiceService.UploadLines(stream Invoice.LineItem) returns (Invoice)\n")
+	require.Contains(t, s, "Messages:\n"+
+		"  - Invoice (7 fields, 1 oneofs, line 27)\n"+
+		"  - Invoice.LineItem (2 fields, line 28)\n"+
+		"  - GetInvoiceRequest (1 fields, line 50)\n")
+	require.Contains(t, s, "Enums:\n  - Invoice.Status (3 values, line 32)\n  - Region (2 values, line 58)\n")
+
+	// Messages and enums are only counted; their members, like the
+	// google.api.http options, are not listed.
+	require.NotContains(t, s, "Invoice.items")
+	require.NotContains(t, s, "STATUS_PAID")
+	require.NotContains(t, s, "/v1/{name=invoices/*}")
+}
+
+func TestProtoExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &ProtoExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "billing.proto", Content: []byte(testProto)})
+	require.NoError(t, err)
+
+	// Every field and value counted under Messages and Enums is listed,
+	// nested types included.
+	s := result.Summary
+	require.Contains(t, s, "Fields:\n"+
+		"  - Invoice.name: string = 1\n"+
+		"  - Invoice.items: repeated LineItem = 2\n"+
+		"  - Invoice.status: Status = 3\n"+
+		"  - Invoice.issued: google.protobuf.Timestamp = 4\n"+
+		"  - Invoice.labels: map<string, string> = 5\n"+
+		"  - Invoice.account: string = 6 (oneof payer)\n"+
+		"  - Invoice.card: string = 7 (oneof payer)\n"+
+		"  - Invoice.LineItem.sku: string = 1\n"+
+		"  - Invoice.LineItem.price: acme.common.v1.Money = 2\n"+
+		"  - GetInvoiceRequest.name: string = 1\n"+
+		"  - WatchInvoicesRequest.filter: string = 1\n")
+	require.Contains(t, s, "Enum values:\n"+
+		"  - Invoice.Status.STATUS_UNSPECIFIED = 0\n"+
+		"  - Invoice.Status.STATUS_OPEN = 1\n"+
+		"  - Invoice.Status.STATUS_PAID = 2\n"+
+		"  - Region.REGION_UNSPECIFIED = 0\n"+
+		"  - Region.REGION_EU = 1\n")
+	require.Contains(t, s, "HTTP bindings:\n"+
+		"  - InvoiceService.GetInvoice: GET /v1/{name=invoices/*}\n"+
+		"  - InvoiceService.UploadLines: POST /v1/invoices:upload\n")
+	require.Contains(t, s, "Type dependencies:\n"+
+		"  - Invoice -> Invoice.LineItem, Invoice.Status, google.protobuf.Timestamp\n"+
+		"  - Invoice.LineItem -> acme.common.v1.Money\n"+
+		"  - InvoiceService -> GetInvoiceRequest, Invoice, Invoice.LineItem, WatchInvoicesRequest\n")
+}
+
+func TestProtoExplorer_Explore_Proto2(t *testing.T) {
+	t.Parallel()
+
+	content := `package legacy;
+message Search {
+  required string query = 1;
+  repeated group Result = 2 {
+    required string url = 3;
+  }
+  extensions 100 to 199;
+}
+extend Search {
+  optional int32 rank = 100;
+}
+service Lookup { rpc Find (.legacy.Search) returns (Search.Result); }
+`
+	result, err := (&ProtoExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), ExploreInput{Path: "legacy.proto", Content: []byte(content)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "Syntax: proto2 (not declared)\n")
+	require.Contains(t, s, "  - Search (2 fields, line 2)\n  - Search.Result (1 fields, line 4)\n")
+	require.Contains(t, s, "Extensions:\n  - Search (1 fields)\n")
+	require.Contains(t, s, "  - Search.query: required string = 1\n")
+	require.Contains(t, s, "  - Lookup -> Search, Search.Result\n")
+}
+
+func TestProtoExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		content string
+		failed  string
+	}{
+		{content: "syntax = \"proto3\";\nmessage User {\n  string id = 1;\n", failed: "line 4, column 1: unterminated message User"},
+		{content: "message User {\n  string id 1;\n}\n", failed: "line 2, column 13: expected \"=\", got \"1\""},
+		{content: "syntax = \"proto3;\n", failed: "line 1, column 10: unterminated string literal"},
+		{content: "/* license\nmessage User {}\n", failed: "line 1, column 1: unterminated block comment"},
+	} {
+		result, err := (&ProtoExplorer{}).Explore(context.Background(), ExploreInput{Path: "user.proto", Content: []byte(tt.content)})
+		require.NoError(t, err)
+		require.Regexp(t, degradedBlockPattern, result.Summary)
+		require.Contains(t, result.Summary, "Failed: protobuf parsing at "+tt.failed+"\n")
+	}
+}
+
+func TestProtoExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "billing.proto", Content: []byte(testProto)})
+		require.NoError(t, err)
+		require.Equal(t, "proto", result.ExplorerUsed)
+		require.Contains(t, result.Summary, "### RPC methods\n- InvoiceService.GetInvoice(GetInvoiceRequest) returns (Invoice)\n")
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### HTTP bindings"))
+	}
+}
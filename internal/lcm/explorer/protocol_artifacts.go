@@ -194,8 +194,8 @@ func validateB1ThresholdSet(scope string, set B1ThresholdSet) error {
 		"import_category_accuracy": set.ImportCategoryAccuracy,
 		"visibility_accuracy":      set.VisibilityAccuracy,
 	}
-	for name, val := range fields {
-		if val <= 0 || val > 1 {
+	for _, name := range sortedKeys(fields) {
+		if val := fields[name]; val <= 0 || val > 1 {
 			return fmt.Errorf("b1 scoring protocol: %s.%s must be in (0,1], got %v", scope, name, val)
 		}
 	}
@@ -219,7 +219,8 @@ func (b1 *B1ScoringProtocol) ValidateRequiredFields() error {
 	if len(b1.VisibilityCapabilities) == 0 {
 		return fmt.Errorf("b1 scoring protocol: visibility_capabilities must not be empty")
 	}
-	for lang, cap := range b1.VisibilityCapabilities {
+	for _, lang := range sortedKeys(b1.VisibilityCapabilities) {
+		cap := b1.VisibilityCapabilities[lang]
 		norm := strings.ToLower(strings.TrimSpace(cap))
 		if norm != "full" && norm != "export-only" && norm != "none" {
 			return fmt.Errorf("b1 scoring protocol: visibility_capabilities[%s] invalid capability %q", lang, cap)
@@ -0,0 +1,234 @@
+package explorer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrRemoteDisabled is returned by Registry.Explore for a remote URI
+	// when remote fetching is not configured or the registry uses the
+	// parity output profile.
+	ErrRemoteDisabled = errors.New("remote exploration is disabled")
+	// ErrRemoteTooLarge is returned when a remote artifact exceeds the
+	// configured size cap.
+	ErrRemoteTooLarge = errors.New("remote artifact exceeds size cap")
+	// ErrUnsupportedScheme is returned for a URI scheme without a fetcher.
+	ErrUnsupportedScheme = errors.New("no fetcher for URI scheme")
+)
+
+// Fetcher retrieves the content of a remote artifact. Implementations must
+// not return more than maxBytes bytes; ErrRemoteTooLarge signals a larger
+// artifact.
+type Fetcher interface {
+	Fetch(ctx context.Context, u *url.URL, maxBytes int64) ([]byte, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface.
+type FetcherFunc func(ctx context.Context, u *url.URL, maxBytes int64) ([]byte, error)
+
+// Fetch calls f.
+func (f FetcherFunc) Fetch(ctx context.Context, u *url.URL, maxBytes int64) ([]byte, error) {
+	return f(ctx, u, maxBytes)
+}
+
+// RemoteOptions configures exploration of remote URIs.
+type RemoteOptions struct {
+	// Fetchers maps a URI scheme ("s3", "https", "file") to its fetcher.
+	Fetchers map[string]Fetcher
+	// MaxBytes caps the size of a fetched artifact. Defaults to
+	// MaxFullLoadSize.
+	MaxBytes int64
+	// CacheDir, when set, stores fetched artifacts on disk keyed by URI.
+	CacheDir string
+	// CacheTTL is how long a cached artifact stays fresh. Zero disables
+	// the cache.
+	CacheTTL time.Duration
+}
+
+// WithRemoteFetch lets Explore accept remote URIs as ExploreInput.Path
+// when no content is given.
+func WithRemoteFetch(opts RemoteOptions) RegistryOption {
+	return func(r *Registry) {
+		if opts.MaxBytes <= 0 {
+			opts.MaxBytes = MaxFullLoadSize
+		}
+		r.remote = &opts
+	}
+}
+
+// DefaultFetchers returns fetchers for file, https and s3 URIs. headers
+// maps a host name to headers sent with every HTTPS request to it.
+func DefaultFetchers(headers map[string]map[string]string) map[string]Fetcher {
+	return map[string]Fetcher{
+		"file":  FileFetcher{},
+		"https": &HTTPFetcher{Headers: headers},
+		"s3":    &S3Fetcher{},
+	}
+}
+
+// IsRemoteURI reports whether path is a URI with a scheme the explorer
+// knows how to fetch.
+func IsRemoteURI(path string) bool {
+	scheme, _, ok := strings.Cut(path, "://")
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(scheme) {
+	case "s3", "https", "file":
+		return true
+	}
+	return false
+}
+
+// resolveRemote fetches the content of a remote input and rewrites its
+// path to the URI's path so extension-based detection works.
+func (r *Registry) resolveRemote(ctx context.Context, input ExploreInput) (ExploreInput, error) {
+	if r.remote == nil || r.formatterProfile == OutputProfileParity {
+		return input, ErrRemoteDisabled
+	}
+	u, err := url.Parse(input.Path)
+	if err != nil {
+		return input, fmt.Errorf("parsing remote URI: %w", err)
+	}
+	fetcher, ok := r.remote.Fetchers[strings.ToLower(u.Scheme)]
+	if !ok || fetcher == nil {
+		return input, fmt.Errorf("%w: %s", ErrUnsupportedScheme, u.Scheme)
+	}
+
+	content, err := r.remote.fetchCached(ctx, fetcher, u)
+	if err != nil {
+		return input, err
+	}
+	input.Content = content
+	input.Path = path.Base(u.Path)
+	return input, nil
+}
+
+// fetchCached returns a fresh cached copy of u, or fetches and caches it.
+func (o *RemoteOptions) fetchCached(ctx context.Context, fetcher Fetcher, u *url.URL) ([]byte, error) {
+	cachePath := ""
+	if o.CacheDir != "" && o.CacheTTL > 0 && u.Scheme != "file" {
+		sum := sha256.Sum256([]byte(u.String()))
+		cachePath = filepath.Join(o.CacheDir, hex.EncodeToString(sum[:]))
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < o.CacheTTL {
+			if content, err := os.ReadFile(cachePath); err == nil {
+				return content, nil
+			}
+		}
+	}
+
+	content, err := fetcher.Fetch(ctx, u, o.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > o.MaxBytes {
+		return nil, ErrRemoteTooLarge
+	}
+	if cachePath != "" {
+		// The cache is an optimization; a failed write only costs a
+		// refetch next time.
+		_ = writeCacheFile(cachePath, content)
+	}
+	return content, nil
+}
+
+// writeCacheFile writes content to path atomically.
+func writeCacheFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fetch-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readCapped reads at most maxBytes from r, returning ErrRemoteTooLarge
+// when there is more.
+func readCapped(r io.Reader, maxBytes int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > maxBytes {
+		return nil, ErrRemoteTooLarge
+	}
+	return content, nil
+}
+
+// FileFetcher reads file:// URIs from the local filesystem.
+type FileFetcher struct{}
+
+// Fetch reads the file named by u.
+func (FileFetcher) Fetch(_ context.Context, u *url.URL, maxBytes int64) ([]byte, error) {
+	f, err := os.Open(filepath.FromSlash(u.Path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > maxBytes {
+		return nil, ErrRemoteTooLarge
+	}
+	return readCapped(f, maxBytes)
+}
+
+// HTTPFetcher fetches https:// URIs.
+type HTTPFetcher struct {
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+	// Headers maps a host name to headers sent with requests to it, such
+	// as Authorization.
+	Headers map[string]map[string]string
+}
+
+// Fetch issues a GET for u.
+func (f *HTTPFetcher) Fetch(ctx context.Context, u *url.URL, maxBytes int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range f.Headers[u.Hostname()] {
+		req.Header.Set(name, value)
+	}
+	return doCapped(f.Client, req, maxBytes)
+}
+
+// doCapped sends req and reads a successful response of at most maxBytes.
+func doCapped(client *http.Client, req *http.Request, maxBytes int64) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", req.URL.Redacted(), resp.Status)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, ErrRemoteTooLarge
+	}
+	return readCapped(resp.Body, maxBytes)
+}
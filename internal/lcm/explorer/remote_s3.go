@@ -0,0 +1,71 @@
+package explorer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, sent with signed GETs.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Fetcher fetches s3://bucket/key URIs with credentials from the
+// standard AWS chain (environment, shared config, SSO, instance role).
+type S3Fetcher struct {
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+	// Endpoint overrides the virtual-hosted S3 endpoint; the bucket is
+	// then addressed path-style. Used for S3-compatible stores.
+	Endpoint string
+	// Region defaults to the region of the loaded AWS config, then
+	// us-east-1.
+	Region string
+}
+
+// Fetch signs and issues a GET for the object named by u.
+func (f *S3Fetcher) Fetch(ctx context.Context, u *url.URL, maxBytes int64) ([]byte, error) {
+	bucket, key := u.Host, strings.TrimPrefix(u.Path, "/")
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("invalid S3 URI %q: want s3://bucket/key", u.String())
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+	region := cmp.Or(f.Region, cfg.Region, "us-east-1")
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving AWS credentials: %w", err)
+	}
+
+	target := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, escapeS3Key(key))
+	if f.Endpoint != "" {
+		target = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(f.Endpoint, "/"), bucket, escapeS3Key(key))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-amz-content-sha256", emptyPayloadHash)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, emptyPayloadHash, "s3", region, time.Now()); err != nil {
+		return nil, fmt.Errorf("signing S3 request: %w", err)
+	}
+	return doCapped(f.Client, req, maxBytes)
+}
+
+// escapeS3Key escapes each segment of an object key for use in a URL path.
+func escapeS3Key(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,113 @@
+package explorer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRemoteURI(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsRemoteURI("s3://bucket/key.json"))
+	require.True(t, IsRemoteURI("https://example.com/a.csv"))
+	require.True(t, IsRemoteURI("FILE:///tmp/a.txt"))
+	require.False(t, IsRemoteURI("http://example.com/a.csv"))
+	require.False(t, IsRemoteURI("/tmp/a.txt"))
+	require.False(t, IsRemoteURI("C:\\data\\a.txt"))
+}
+
+func TestRegistry_Explore_FileURI(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"name":"demo","port":8080}`), 0o600))
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+
+	registry := NewRegistry(WithRemoteFetch(RemoteOptions{Fetchers: DefaultFetchers(nil)}))
+	result, err := registry.Explore(t.Context(), ExploreInput{Path: uri})
+	require.NoError(t, err)
+	require.Equal(t, "json", result.ExplorerUsed)
+	require.Contains(t, result.Summary, "config.json")
+}
+
+func TestRegistry_Explore_HTTPSWithHeadersAndCache(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("id,name\n1,a\n2,b\n"))
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	fetchers := map[string]Fetcher{"https": &HTTPFetcher{
+		Client:  srv.Client(),
+		Headers: map[string]map[string]string{u.Hostname(): {"Authorization": "Bearer secret"}},
+	}}
+	registry := NewRegistry(WithRemoteFetch(RemoteOptions{
+		Fetchers: fetchers,
+		CacheDir: t.TempDir(),
+		CacheTTL: time.Hour,
+	}))
+
+	for range 2 {
+		result, err := registry.Explore(t.Context(), ExploreInput{Path: srv.URL + "/exports/rows.csv"})
+		require.NoError(t, err)
+		require.Equal(t, "csv", result.ExplorerUsed)
+	}
+	require.Equal(t, int32(1), hits.Load(), "second explore should be served from the cache")
+}
+
+func TestRegistry_Explore_RemoteSizeCap(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+	t.Cleanup(srv.Close)
+
+	registry := NewRegistry(WithRemoteFetch(RemoteOptions{
+		Fetchers: map[string]Fetcher{"https": &HTTPFetcher{Client: srv.Client()}},
+		MaxBytes: 1024,
+	}))
+	_, err := registry.Explore(t.Context(), ExploreInput{Path: srv.URL + "/big.txt"})
+	require.ErrorIs(t, err, ErrRemoteTooLarge)
+}
+
+func TestRegistry_Explore_RemoteDisabled(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	fetchers := map[string]Fetcher{"s3": FetcherFunc(func(context.Context, *url.URL, int64) ([]byte, error) {
+		called = true
+		return []byte("{}"), nil
+	})}
+
+	_, err := NewRegistry().Explore(t.Context(), ExploreInput{Path: "s3://bucket/a.json"})
+	require.ErrorIs(t, err, ErrRemoteDisabled)
+
+	parity := NewRegistry(WithOutputProfile(OutputProfileParity), WithRemoteFetch(RemoteOptions{Fetchers: fetchers}))
+	_, err = parity.Explore(t.Context(), ExploreInput{Path: "s3://bucket/a.json"})
+	require.ErrorIs(t, err, ErrRemoteDisabled)
+	require.False(t, called)
+
+	enabled := NewRegistry(WithRemoteFetch(RemoteOptions{Fetchers: fetchers}))
+	_, err = enabled.Explore(t.Context(), ExploreInput{Path: "https://example.com/a.json"})
+	require.ErrorIs(t, err, ErrUnsupportedScheme)
+}
@@ -3,7 +3,10 @@ package explorer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 )
 
 var errNilRuntimeAdapter = errors.New("explorer runtime adapter is nil")
@@ -13,12 +16,17 @@ var errNilRuntimeAdapter = errors.New("explorer runtime adapter is nil")
 type RuntimeAdapter struct {
 	registry          *Registry
 	persistenceMatrix *RuntimePersistenceMatrix
+	rejectUnsafe      bool
 }
 
 type runtimeAdapterConfig struct {
 	parser            any
 	outputProfile     OutputProfile
 	persistenceMatrix *RuntimePersistenceMatrix
+	remote            *RemoteOptions
+	metrics           *Metrics
+	rejectUnsafe      bool
+	registryOpts      []RegistryOption
 }
 
 // RuntimeAdapterOption configures RuntimeAdapter behavior.
@@ -45,6 +53,111 @@ func WithRuntimePersistenceMatrix(matrix *RuntimePersistenceMatrix) RuntimeAdapt
 	}
 }
 
+// WithRuntimeRemoteFetch lets Explore accept remote URIs as paths. A nil
+// opts leaves remote fetching disabled.
+func WithRuntimeRemoteFetch(opts *RemoteOptions) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		cfg.remote = opts
+	}
+}
+
+// WithRuntimeMetrics counts the adapter's explorations into m. A nil m
+// leaves them uncounted.
+func WithRuntimeMetrics(m *Metrics) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		cfg.metrics = m
+	}
+}
+
+// WithRuntimeObserver reports the adapter's explorations to o, as
+// WithObserver does for a Registry. A nil o observes nothing.
+func WithRuntimeObserver(o Observer) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		if o != nil {
+			cfg.registryOpts = append(cfg.registryOpts, WithObserver(o))
+		}
+	}
+}
+
+// WithRuntimeExplorerLimits bounds the adapter's explorers, as
+// WithExplorerLimits does for a Registry.
+func WithRuntimeExplorerLimits(defaults ExplorerLimits, overrides map[string]ExplorerLimits) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		cfg.registryOpts = append(cfg.registryOpts, WithExplorerLimits(defaults, overrides))
+	}
+}
+
+// WithRuntimeCircuitBreaker skips explorers that keep timing out, as
+// WithCircuitBreaker does for a Registry.
+func WithRuntimeCircuitBreaker(failures int, cooldown time.Duration) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		cfg.registryOpts = append(cfg.registryOpts, WithCircuitBreaker(failures, cooldown))
+	}
+}
+
+// WithRuntimeExploreCache reuses static results of identical content from
+// c (see WithExploreCache). A nil cache disables caching.
+func WithRuntimeExploreCache(c ExploreCache) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		if c != nil {
+			cfg.registryOpts = append(cfg.registryOpts, WithExploreCache(c))
+		}
+	}
+}
+
+// WithRuntimeTokenCounter counts token estimates with counter for model,
+// as WithTokenCounter does for a Registry. A nil counter keeps the
+// heuristic.
+func WithRuntimeTokenCounter(counter TokenCounter, model string) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		if counter != nil {
+			cfg.registryOpts = append(cfg.registryOpts, WithTokenCounter(counter, model))
+		}
+	}
+}
+
+// WithRuntimeSQLiteSampling bounds the row counts and sample rows of
+// SQLite databases, as WithSQLiteSampling does for a Registry. A nil s
+// keeps the defaults.
+func WithRuntimeSQLiteSampling(s *SQLiteSampling) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		if s != nil {
+			cfg.registryOpts = append(cfg.registryOpts, WithSQLiteSampling(*s))
+		}
+	}
+}
+
+// WithRuntimeArchiveNesting bounds the archives opened inside archives, as
+// WithArchiveNesting does for a Registry. A nil n keeps the defaults.
+func WithRuntimeArchiveNesting(n *ArchiveNesting) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		if n != nil {
+			cfg.registryOpts = append(cfg.registryOpts, WithArchiveNesting(*n))
+		}
+	}
+}
+
+// WithRuntimeOutputLimits sets the limits of summaries formatted under
+// profile, as WithOutputLimits does for a Registry. A nil l keeps the
+// defaults.
+func WithRuntimeOutputLimits(profile OutputProfile, l *OutputLimits) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		if l != nil {
+			cfg.registryOpts = append(cfg.registryOpts, WithOutputLimits(profile, *l))
+		}
+	}
+}
+
+// WithRuntimeRejectUnsafeArchives makes Explore fail with
+// ErrUnsafeArchive, instead of returning a summary, for archives with
+// entries that would be unsafe to extract: path traversal, absolute paths,
+// links escaping the root or archive bomb expansion.
+func WithRuntimeRejectUnsafeArchives(reject bool) RuntimeAdapterOption {
+	return func(cfg *runtimeAdapterConfig) {
+		cfg.rejectUnsafe = reject
+	}
+}
+
 // NewRuntimeAdapter creates a runtime adapter with an explorer registry.
 // When a parser is configured, tree-sitter exploration is enabled.
 func NewRuntimeAdapter(opts ...RuntimeAdapterOption) *RuntimeAdapter {
@@ -59,6 +172,13 @@ func NewRuntimeAdapter(opts ...RuntimeAdapterOption) *RuntimeAdapter {
 	if cfg.parser != nil {
 		registryOpts = append(registryOpts, WithTreeSitter(cfg.parser))
 	}
+	if cfg.remote != nil {
+		registryOpts = append(registryOpts, WithRemoteFetch(*cfg.remote))
+	}
+	if cfg.metrics != nil {
+		registryOpts = append(registryOpts, WithMetrics(cfg.metrics))
+	}
+	registryOpts = append(registryOpts, cfg.registryOpts...)
 
 	matrix := cfg.persistenceMatrix
 	if matrix == nil {
@@ -71,6 +191,7 @@ func NewRuntimeAdapter(opts ...RuntimeAdapterOption) *RuntimeAdapter {
 	return &RuntimeAdapter{
 		registry:          NewRegistry(registryOpts...),
 		persistenceMatrix: matrix,
+		rejectUnsafe:      cfg.rejectUnsafe,
 	}
 }
 
@@ -93,6 +214,15 @@ func (a *RuntimeAdapter) Explore(
 	if err != nil {
 		return "", "", false, err
 	}
+	if a.rejectUnsafe && result.Facts != nil {
+		if n := result.Facts.Counts["unsafe_entries"]; n > 0 {
+			return "", "", false, fmt.Errorf("%w: %d found in %s", ErrUnsafeArchive, n, path)
+		}
+	}
+
+	for _, skip := range result.Skipped {
+		slog.Debug("Explorer skipped", "path", path, "explorer", skip.Explorer, "reason", skip.Reason, "detail", skip.Detail)
+	}
 
 	explorerUsed := strings.TrimSpace(result.ExplorerUsed)
 	policy := RuntimePersistencePolicy{Persist: true}
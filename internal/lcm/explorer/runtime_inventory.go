@@ -15,6 +15,10 @@ const (
 	RuntimeInventoryPath = "testdata/parity_volt/runtime_ingestion_paths.v1.json"
 )
 
+// RuntimePathToolOutputCreate is the inventory ID of the path that explores
+// large tool outputs in the LCM message decorator.
+const RuntimePathToolOutputCreate = "lcm.tool_output.create"
+
 // RuntimeIngestionPath describes a single runtime ingestion/retrieval path.
 type RuntimeIngestionPath struct {
 	ID                          string         `json:"id"`
@@ -211,6 +215,8 @@ func runtimeExplorerKeyFromInventoryExplorer(explorer string) string {
 		return "json"
 	case "CSVExplorer":
 		return "csv"
+	case "ParquetExplorer":
+		return "parquet"
 	case "YAMLExplorer":
 		return "yaml"
 	case "TOMLExplorer":
@@ -258,6 +264,8 @@ func runtimeExplorerKeyToInventoryExplorer(explorerUsed string) string {
 		return "JSONExplorer"
 	case "csv":
 		return "CSVExplorer"
+	case "parquet":
+		return "ParquetExplorer"
 	case "yaml":
 		return "YAMLExplorer"
 	case "toml":
@@ -313,11 +321,15 @@ type OrderingDriftEntry struct {
 // DiscoverRuntimePaths enumerates runtime ingestion paths from a registry.
 func DiscoverRuntimePaths(registry *Registry, profile OutputProfile) []DiscoveredPath {
 	paths := make([]DiscoveredPath, 0, len(registry.explorers))
-	for i, explorer := range registry.explorers {
+	for _, explorer := range registry.explorers {
+		// Registered explorers whose gate is closed are not on the path.
+		if p, ok := explorer.(*pluginExplorer); ok && !p.active() {
+			continue
+		}
 		path := DiscoveredPath{
 			ExplorerName: explorerName(explorer),
 			Kind:         KindValue(explorer),
-			Position:     i + 1,
+			Position:     len(paths) + 1,
 		}
 		paths = append(paths, path)
 	}
@@ -346,6 +358,8 @@ func DiscoverRuntimePaths(registry *Registry, profile OutputProfile) []Discovere
 // explorerIdent returns the canonical identifier for an explorer.
 func explorerIdent(explorer Explorer) string {
 	switch e := explorer.(type) {
+	case *pluginExplorer:
+		return e.name
 	case *ArchiveExplorer:
 		return "ArchiveExplorer"
 	case *PDFExplorer:
@@ -360,6 +374,8 @@ func explorerIdent(explorer Explorer) string {
 		return "JSONExplorer"
 	case *CSVExplorer:
 		return "CSVExplorer"
+	case *ParquetExplorer:
+		return "ParquetExplorer"
 	case *YAMLExplorer:
 		return "YAMLExplorer"
 	case *TOMLExplorer:
@@ -581,6 +597,8 @@ func GenerateRuntimeInventory(registry *Registry, profile OutputProfile) (*Runti
 // KindValue returns a path kind value for an explorer.
 func KindValue(explorer Explorer) string {
 	switch e := explorer.(type) {
+	case *pluginExplorer:
+		return e.kind
 	case *BinaryExplorer:
 		return "native_binary"
 	case *ArchiveExplorer:
@@ -591,7 +609,7 @@ func KindValue(explorer Explorer) string {
 		return "image_format_native"
 	case *ExecutableExplorer:
 		return "executable_format_native"
-	case *JSONExplorer, *CSVExplorer, *YAMLExplorer, *TOMLExplorer, *INIExplorer, *XMLExplorer, *HTMLExplorer, *MarkdownExplorer, *LatexExplorer, *SQLiteExplorer, *LogsExplorer:
+	case *JSONExplorer, *CSVExplorer, *ParquetExplorer, *YAMLExplorer, *TOMLExplorer, *INIExplorer, *XMLExplorer, *HTMLExplorer, *MarkdownExplorer, *LatexExplorer, *SQLiteExplorer, *LogsExplorer, *GitObjectExplorer:
 		return "data_format_native"
 	case explorerWithKind:
 		return "code_format_enhanced"
@@ -685,10 +703,44 @@ func validateB3RuntimeInventoryPaths(paths []RuntimeIngestionPath, profile strin
 		}
 	}
 
-	for id, present := range requiredIDs {
-		if !present {
+	for _, id := range sortedKeys(requiredIDs) {
+		if !requiredIDs[id] {
 			return fmt.Errorf("runtime inventory missing required path id: %s", id)
 		}
 	}
 	return nil
 }
+
+// ValidateRuntimePathProfiles checks output profile overrides keyed by
+// runtime path ID against inventory: each ID must name an ingestion path and
+// each profile must be a known OutputProfile. Callers should treat an error
+// as parity for every path, so a typo never loosens parity gating.
+func ValidateRuntimePathProfiles(inventory *RuntimeInventory, profiles map[string]OutputProfile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+	if inventory == nil {
+		return fmt.Errorf("runtime inventory is nil")
+	}
+
+	kinds := make(map[string]string, len(inventory.Paths))
+	for _, path := range inventory.Paths {
+		kinds[strings.TrimSpace(path.ID)] = strings.ToLower(strings.TrimSpace(path.PathKind))
+	}
+	for _, id := range sortedKeys(profiles) {
+		kind, ok := kinds[id]
+		if !ok {
+			return fmt.Errorf("path profile %q: unknown runtime path", id)
+		}
+		if kind != "ingestion" {
+			return fmt.Errorf("path profile %q: %s path has no output profile", id, kind)
+		}
+		switch profiles[id] {
+		case OutputProfileParity, OutputProfileEnhancement, OutputProfileCompact,
+			OutputProfileStandard, OutputProfileVerbose:
+		default:
+			return fmt.Errorf("path profile %q: unknown output profile %q", id, profiles[id])
+		}
+	}
+	return nil
+}
@@ -622,3 +622,27 @@ func TestRuntimeInventory_PreconditionFields(t *testing.T) {
 		require.NotEmptyf(t, path.ConfigGates, "path[%d] config_gates should not be empty", i)
 	}
 }
+
+func TestValidateRuntimePathProfiles(t *testing.T) {
+	t.Parallel()
+
+	inventory, err := LoadRuntimeInventory()
+	require.NoError(t, err)
+
+	require.NoError(t, ValidateRuntimePathProfiles(inventory, nil))
+	require.NoError(t, ValidateRuntimePathProfiles(inventory, map[string]OutputProfile{
+		RuntimePathToolOutputCreate: OutputProfileParity,
+		"volt.prompt.file.persist":  OutputProfileEnhancement,
+	}))
+
+	for name, profiles := range map[string]map[string]OutputProfile{
+		"unknown path":    {"lcm.tool_output.update": OutputProfileParity},
+		"retrieval path":  {"lcm.describe.readback": OutputProfileEnhancement},
+		"unknown profile": {RuntimePathToolOutputCreate: "fancy"},
+	} {
+		require.Error(t, ValidateRuntimePathProfiles(inventory, profiles), name)
+	}
+	require.Error(t, ValidateRuntimePathProfiles(nil, map[string]OutputProfile{
+		RuntimePathToolOutputCreate: OutputProfileParity,
+	}))
+}
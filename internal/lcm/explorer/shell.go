@@ -39,20 +39,27 @@ func (e *ShellExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 	// Source/dot commands
 	sourceRe := regexp.MustCompile(`(?m)^(?:source|\.)\s+(.+)`)
 	sources := sourceRe.FindAllStringSubmatch(content, -1)
+	facts := &Facts{}
 	if len(sources) > 0 {
 		summary.WriteString("\nSources:\n")
 		for _, match := range sources {
 			fmt.Fprintf(&summary, "  - %s\n", match[1])
+			facts.Imports = append(facts.Imports, match[1])
 		}
 	}
 
 	// Functions
 	funcRe := regexp.MustCompile(`(?m)^(?:function\s+)?(\w+)\s*\(\s*\)\s*\{`)
-	functions := funcRe.FindAllStringSubmatch(content, -1)
+	functions := funcRe.FindAllStringSubmatchIndex(content, -1)
 	if len(functions) > 0 {
 		summary.WriteString("\nFunctions:\n")
-		for _, match := range functions {
-			fmt.Fprintf(&summary, "  - %s\n", match[1])
+		line, counted := 1, 0
+		for _, m := range functions {
+			name := content[m[2]:m[3]]
+			fmt.Fprintf(&summary, "  - %s\n", name)
+			line += strings.Count(content[counted:m[0]], "\n")
+			counted = m[0]
+			facts.Symbols = append(facts.Symbols, SymbolInfo{Name: name, Kind: "function", StartLine: line})
 		}
 	}
 
@@ -67,6 +74,7 @@ func (e *ShellExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 			if !seen[v] {
 				fmt.Fprintf(&summary, "  - %s\n", v)
 				seen[v] = true
+				facts.Symbols = append(facts.Symbols, SymbolInfo{Name: v, Kind: "variable"})
 			}
 		}
 	}
@@ -76,5 +84,6 @@ func (e *ShellExplorer) Explore(ctx context.Context, input ExploreInput) (Explor
 		Summary:       result,
 		ExplorerUsed:  "shell",
 		TokenEstimate: estimateTokens(result),
+		Facts:         facts,
 	}, nil
 }
@@ -184,7 +184,7 @@ Imports:
   - time
   - sync
 `
-		verbose := formatSummary(raw, OutputProfileVerbose)
+		verbose := formatSummary(raw, OutputProfileVerbose, OutputLimits{})
 		for _, item := range []string{"fmt", "os", "strings", "context", "io", "net", "http", "encoding", "json", "time", "sync"} {
 			require.Contains(t, verbose, item, "verbose should include all items, missing %q", item)
 		}
@@ -206,7 +206,7 @@ Imports:
   - encoding
   - json
 `
-		compact := formatSummary(raw, normalizeProfile(OutputProfileCompact))
+		compact := formatSummary(raw, normalizeProfile(OutputProfileCompact), OutputLimits{})
 		require.Contains(t, compact, "(+1 more)", "compact should use parity-style markers")
 	})
 
@@ -224,7 +224,7 @@ Imports:
   - encoding
   - json
 `
-		standard := formatSummary(raw, normalizeProfile(OutputProfileStandard))
+		standard := formatSummary(raw, normalizeProfile(OutputProfileStandard), OutputLimits{})
 		require.Contains(t, standard, "... and 1 more", "standard should use enhancement-style markers")
 	})
 
@@ -235,7 +235,7 @@ Imports:
 			lines[i] = "line content here"
 		}
 		raw := "Text file: notes.txt\nContent:\n" + strings.Join(lines, "\n")
-		verbose := formatSummary(raw, OutputProfileVerbose)
+		verbose := formatSummary(raw, OutputProfileVerbose, OutputLimits{})
 		lineCount := strings.Count(verbose, "- line content here")
 		require.Equal(t, 30, lineCount, "verbose should include all content lines")
 	})
@@ -1,12 +1,15 @@
 package explorer
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -14,14 +17,51 @@ import (
 // SQLiteExplorer explores SQLite database files.
 type SQLiteExplorer struct {
 	formatterProfile OutputProfile
+	sampling         SQLiteSampling
 }
 
 const (
 	sqliteMagicHeader = "SQLite format 3\000"
 	maxSampleRows     = 3
 	maxCellLength     = 100
+	maxSampledTables  = 8
 )
 
+// SQLiteSampling bounds the data shape the SQLite explorer reports under
+// the enhancement profile: per-table row counts and a few sample rows of
+// each table. Zero fields take their defaults.
+type SQLiteSampling struct {
+	// SampleRows is how many rows are sampled per table. Defaults to 3;
+	// negative disables sampling.
+	SampleRows int
+	// MaxTables is how many tables, in name order, are counted and
+	// sampled. Defaults to 8.
+	MaxTables int
+	// MaxCellLength truncates longer sampled values. Defaults to 100.
+	MaxCellLength int
+	// SkipRowCounts omits the row counts, which scan every table.
+	SkipRowCounts bool
+}
+
+func (s SQLiteSampling) withDefaults() SQLiteSampling {
+	s.SampleRows = cmp.Or(s.SampleRows, maxSampleRows)
+	if s.MaxTables <= 0 {
+		s.MaxTables = maxSampledTables
+	}
+	if s.MaxCellLength <= 0 {
+		s.MaxCellLength = maxCellLength
+	}
+	return s
+}
+
+// WithSQLiteSampling bounds the row counts and sample rows the SQLite
+// explorer reports under the enhancement profile.
+func WithSQLiteSampling(s SQLiteSampling) RegistryOption {
+	return func(r *Registry) {
+		r.sqliteSampling = s
+	}
+}
+
 func (e *SQLiteExplorer) CanHandle(path string, content []byte) bool {
 	// Check by extension first
 	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
@@ -46,11 +86,25 @@ func (e *SQLiteExplorer) Explore(ctx context.Context, input ExploreInput) (Explo
 	// Use withTempFile because the SQLite library requires a file path, not
 	// bytes. The helper creates the file, writes content, closes it, then
 	// calls our callback with the path.
+	facts := &Facts{}
 	err := withTempFile("crush-sqlite-*.db", input.Content, func(tempPath string) error {
-		return e.exploreDB(ctx, &summary, tempPath)
+		return e.exploreDB(ctx, &summary, facts, tempPath)
 	})
 	if err != nil {
-		summary.WriteString("\nError: " + err.Error())
+		progress := "database not recognized, no schema read"
+		if strings.Contains(summary.String(), "SQLite version:") {
+			progress = "database opened, schema inventory incomplete"
+		}
+		degradedExploration{
+			Failed:   err.Error(),
+			Progress: progress,
+			Examined: int64(len(input.Content)),
+			Size:     int64(len(input.Content)),
+			NextSteps: []string{
+				"Run sqlite3 <file> \"PRAGMA integrity_check\" to locate the damage",
+				"A database copied while open may be missing its -wal file; copy it alongside",
+			},
+		}.write(&summary)
 	}
 
 	result := summary.String()
@@ -58,12 +112,13 @@ func (e *SQLiteExplorer) Explore(ctx context.Context, input ExploreInput) (Explo
 		Summary:       result,
 		ExplorerUsed:  "sqlite",
 		TokenEstimate: estimateTokens(result),
+		Facts:         facts,
 	}, nil
 }
 
-// exploreDB opens the SQLite database at path and writes its schema summary
-// into the provided builder.
-func (e *SQLiteExplorer) exploreDB(ctx context.Context, summary *strings.Builder, path string) error {
+// exploreDB opens the SQLite database at path, writes its schema summary
+// into the provided builder and records the schema objects in facts.
+func (e *SQLiteExplorer) exploreDB(ctx context.Context, summary *strings.Builder, facts *Facts, path string) error {
 	// Open database in read-only mode.
 	dsn := fmt.Sprintf("file:%s?mode=ro", url.QueryEscape(path))
 	db, err := sql.Open("sqlite", dsn)
@@ -86,6 +141,10 @@ func (e *SQLiteExplorer) exploreDB(ctx context.Context, summary *strings.Builder
 	}
 
 	fmt.Fprintf(summary, "Tables: %d\n", len(tables))
+	facts.count("tables", len(tables))
+	for _, table := range tables {
+		facts.Symbols = append(facts.Symbols, SymbolInfo{Name: table, Kind: "table"})
+	}
 	if len(tables) > 0 {
 		summary.WriteString("\nTable inventory:\n")
 		for _, table := range tables {
@@ -100,6 +159,10 @@ func (e *SQLiteExplorer) exploreDB(ctx context.Context, summary *strings.Builder
 	}
 
 	fmt.Fprintf(summary, "\nIndexes: %d\n", len(indexes))
+	facts.count("indexes", len(indexes))
+	for _, idx := range indexes {
+		facts.Symbols = append(facts.Symbols, SymbolInfo{Name: idx.Name, Kind: "index"})
+	}
 	if len(indexes) > 0 {
 		summary.WriteString("\nIndex inventory:\n")
 		for _, idx := range indexes {
@@ -128,25 +191,33 @@ func (e *SQLiteExplorer) exploreDB(ctx context.Context, summary *strings.Builder
 	}
 
 	// PARITY MODE: Sample row summaries.
-	sampleRows, err := e.getSampleRows(ctx, db, tables)
-	if err == nil {
-		summary.WriteString("\nSample row summaries:\n")
-		for _, table := range tables {
-			if rows, ok := sampleRows[table]; ok && len(rows) > 0 {
-				fmt.Fprintf(summary, "  %s:\n", table)
-				for _, row := range rows {
-					fmt.Fprintf(summary, "    %s\n", row)
+	if e.formatterProfile != OutputProfileEnhancement {
+		sampleRows, err := e.getSampleRows(ctx, db, tables)
+		if err == nil {
+			summary.WriteString("\nSample row summaries:\n")
+			for _, table := range tables {
+				if rows, ok := sampleRows[table]; ok && len(rows) > 0 {
+					fmt.Fprintf(summary, "  %s:\n", table)
+					for _, row := range rows {
+						fmt.Fprintf(summary, "    %s\n", row)
+					}
 				}
 			}
 		}
 	}
 
-	// EXCEED MODE: Views, triggers, and constraints.
+	// EXCEED MODE: Row counts, sample rows, views, triggers, and constraints.
 	if e.formatterProfile == OutputProfileEnhancement {
+		e.writeDataShape(ctx, summary, db, tables)
+
 		// Get view inventory.
 		views, err := e.getViews(ctx, db)
 		if err == nil {
 			fmt.Fprintf(summary, "\nViews: %d\n", len(views))
+			facts.count("views", len(views))
+			for _, view := range views {
+				facts.Symbols = append(facts.Symbols, SymbolInfo{Name: view.Name, Kind: "view"})
+			}
 			if len(views) > 0 {
 				summary.WriteString("\nView inventory:\n")
 				for _, view := range views {
@@ -160,6 +231,10 @@ func (e *SQLiteExplorer) exploreDB(ctx context.Context, summary *strings.Builder
 		triggers, err := e.getTriggers(ctx, db)
 		if err == nil {
 			fmt.Fprintf(summary, "\nTriggers: %d\n", len(triggers))
+			facts.count("triggers", len(triggers))
+			for _, trig := range triggers {
+				facts.Symbols = append(facts.Symbols, SymbolInfo{Name: trig.Name, Kind: "trigger"})
+			}
 			if len(triggers) > 0 {
 				summary.WriteString("\nTrigger inventory:\n")
 				for _, trig := range triggers {
@@ -177,6 +252,7 @@ func (e *SQLiteExplorer) exploreDB(ctx context.Context, summary *strings.Builder
 			for _, tblConstraints := range constraints {
 				constraintCount += len(tblConstraints)
 			}
+			facts.count("constraints", constraintCount)
 			if constraintCount > 0 {
 				fmt.Fprintf(summary, "\nConstraints: %d\n", constraintCount)
 				summary.WriteString("\nConstraint details:\n")
@@ -365,40 +441,224 @@ func (e *SQLiteExplorer) getSampleRowsForTable(ctx context.Context, db *sql.DB,
 			continue
 		}
 
-		var cells []string
-		for i, col := range columns {
-			var val string
-			if values[i] == nil {
-				val = "NULL"
-			} else {
-				// Handle different types.
-				switch v := values[i].(type) {
-				case []byte:
-					// Check if it's BLOB or text.
-					if looksLikeBLOB(v) {
-						val = fmt.Sprintf("<BLOB %d bytes>", len(v))
-					} else {
-						val = string(v)
-						if len(val) > maxCellLength {
-							val = val[:maxCellLength] + "..."
-						}
-					}
-				default:
-					val = fmt.Sprintf("%v", v)
-					if len(val) > maxCellLength {
-						val = val[:maxCellLength] + "..."
+		tableRows = append(tableRows, formatSampleRow(columns, values, maxCellLength))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tableRows, nil
+}
+
+// formatSampleRow renders one sampled row as "{ col: value, ... }",
+// truncating values longer than maxLen.
+func formatSampleRow(columns []string, values []any, maxLen int) string {
+	var cells []string
+	for i, col := range columns {
+		var val string
+		if values[i] == nil {
+			val = "NULL"
+		} else {
+			// Handle different types.
+			switch v := values[i].(type) {
+			case []byte:
+				// Check if it's BLOB or text.
+				if looksLikeBLOB(v) {
+					val = fmt.Sprintf("<BLOB %d bytes>", len(v))
+				} else {
+					val = string(v)
+					if len(val) > maxLen {
+						val = val[:maxLen] + "..."
 					}
 				}
+			default:
+				val = fmt.Sprintf("%v", v)
+				if len(val) > maxLen {
+					val = val[:maxLen] + "..."
+				}
+			}
+		}
+		cells = append(cells, fmt.Sprintf("%s: %s", col, val))
+	}
+	return "{ " + strings.Join(cells, ", ") + " }"
+}
+
+// writeDataShape writes the row count of each table and a deterministic
+// sample of its first rows, bounded by the explorer's sampling limits.
+func (e *SQLiteExplorer) writeDataShape(ctx context.Context, summary *strings.Builder, db *sql.DB, tables []string) {
+	s := e.sampling.withDefaults()
+	sampled := tables
+	if len(sampled) > s.MaxTables {
+		sampled = sampled[:s.MaxTables]
+	}
+	if len(sampled) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	if !s.SkipRowCounts {
+		if len(sampled) < len(tables) {
+			fmt.Fprintf(summary, "\nRow counts (first %d of %d tables):\n", len(sampled), len(tables))
+		} else {
+			summary.WriteString("\nRow counts:\n")
+		}
+		for _, table := range sampled {
+			var count int
+			err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdentifier(table))).Scan(&count)
+			if err != nil {
+				fmt.Fprintf(summary, "  - %s: (error counting rows)\n", table)
+				continue
 			}
-			cells = append(cells, fmt.Sprintf("%s: %s", col, val))
+			counts[table] = count
+			fmt.Fprintf(summary, "  - %s: %d rows\n", table, count)
 		}
-		tableRows = append(tableRows, "{ "+strings.Join(cells, ", ")+" }")
+	}
+
+	if s.SampleRows < 0 {
+		return
+	}
+	for _, table := range sampled {
+		if count, ok := counts[table]; ok && count == 0 {
+			continue
+		}
+		sample, err := e.sampleTable(ctx, db, table, s)
+		if err != nil || len(sample.rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(summary, "\nSample rows of %s (by %s):\n", table, sample.order)
+		fmt.Fprintf(summary, "  - column types: %s\n", strings.Join(sample.types, ", "))
+		for i, row := range sample.rows {
+			fmt.Fprintf(summary, "  - row %d: %s\n", i+1, row)
+		}
+	}
+}
+
+// tableSample is a deterministic sample of a table's rows.
+type tableSample struct {
+	order string   // what the rows are ordered by
+	types []string // "column type[|type...]" as observed in the rows
+	rows  []string
+}
+
+// sampleTable reads the first rows of a table in a stable order: by rowid,
+// by primary key for WITHOUT ROWID tables, or by every column otherwise.
+func (e *SQLiteExplorer) sampleTable(ctx context.Context, db *sql.DB, table string, s SQLiteSampling) (tableSample, error) {
+	query := func(orderBy string) (*sql.Rows, error) {
+		return db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s ORDER BY %s LIMIT %d",
+			quoteIdentifier(table), orderBy, s.SampleRows))
+	}
+
+	sample := tableSample{order: "rowid"}
+	rows, err := query("rowid")
+	if err != nil {
+		orderBy, order, perr := e.fallbackOrder(ctx, db, table)
+		if perr != nil {
+			return tableSample{}, perr
+		}
+		sample.order = order
+		if rows, err = query(orderBy); err != nil {
+			return tableSample{}, err
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return tableSample{}, err
+	}
+	observed := make([][]string, len(columns))
+	for rows.Next() {
+		values := make([]any, len(columns))
+		valuesPtr := make([]any, len(columns))
+		for i := range values {
+			valuesPtr[i] = &values[i]
+		}
+		if err := rows.Scan(valuesPtr...); err != nil {
+			continue
+		}
+		for i, v := range values {
+			if t := sqliteValueType(v); !slices.Contains(observed[i], t) {
+				observed[i] = append(observed[i], t)
+			}
+		}
+		sample.rows = append(sample.rows, formatSampleRow(columns, values, s.MaxCellLength))
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return tableSample{}, err
 	}
+	for i, col := range columns {
+		sample.types = append(sample.types, col+" "+strings.Join(observed[i], "|"))
+	}
+	return sample, nil
+}
 
-	return tableRows, nil
+// fallbackOrder returns the ORDER BY clause of a table without a usable
+// rowid, and a description of it: its primary key columns, or all of its
+// columns by position when it has none.
+func (e *SQLiteExplorer) fallbackOrder(ctx context.Context, db *sql.DB, table string) (orderBy, order string, err error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", quoteIdentifier(table)))
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+
+	pk := make(map[int]string)
+	columns := 0
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			dataType  string
+			notnull   int
+			dfltValue sql.NullString
+			pkIndex   int
+		)
+		if err := rows.Scan(&cid, &name, &dataType, &notnull, &dfltValue, &pkIndex); err != nil {
+			continue
+		}
+		columns++
+		if pkIndex > 0 {
+			pk[pkIndex] = quoteIdentifier(name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", err
+	}
+
+	if len(pk) > 0 {
+		keys := make([]string, 0, len(pk))
+		for i := 1; i <= len(pk); i++ {
+			keys = append(keys, pk[i])
+		}
+		return strings.Join(keys, ", "), "primary key", nil
+	}
+	if columns == 0 {
+		return "", "", fmt.Errorf("table %s has no columns", table)
+	}
+	positions := make([]string, columns)
+	for i := range positions {
+		positions[i] = fmt.Sprint(i + 1)
+	}
+	return strings.Join(positions, ", "), "all columns", nil
+}
+
+// sqliteValueType returns the storage class of a scanned SQLite value.
+func sqliteValueType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case int64:
+		return "integer"
+	case float64:
+		return "real"
+	case []byte:
+		return "blob"
+	case string, time.Time:
+		// The driver parses text in date-typed columns into time.Time.
+		return "text"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
 }
 
 // getViews gets view definitions for exceed mode.
@@ -257,7 +257,7 @@ func TestSQLiteExplorer_Explore(t *testing.T) {
 
 		summary := result.Summary
 		require.Contains(t, summary, "SQLite database: corrupted.db")
-		require.Contains(t, summary, "Error: invalid SQLite database file")
+		require.Contains(t, summary, "Failed: invalid SQLite database file")
 	})
 
 	t.Run("non-sqlite content with extension", func(t *testing.T) {
@@ -273,7 +273,7 @@ func TestSQLiteExplorer_Explore(t *testing.T) {
 
 		summary := result.Summary
 		require.Contains(t, summary, "SQLite database: test.db")
-		require.Contains(t, summary, "Error:")
+		require.Contains(t, summary, "Degraded exploration:")
 	})
 
 	t.Run("database with primary key constraints", func(t *testing.T) {
@@ -482,3 +482,51 @@ func TestSQLiteExplorer_GoldenParity(t *testing.T) {
 	// Compare against golden file
 	golden.RequireEqual(t, []byte(normalized))
 }
+
+func TestSQLiteExplorer_DataShape(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "shape.db")
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s", url.QueryEscape(dbPath)))
+	require.NoError(t, err)
+	_, err = db.ExecContext(context.Background(), `
+		CREATE TABLE events (id INTEGER PRIMARY KEY, kind TEXT, payload BLOB, score REAL);
+		CREATE TABLE tags (name TEXT PRIMARY KEY, uses INTEGER) WITHOUT ROWID;
+		CREATE TABLE empty (x INTEGER);
+		INSERT INTO events VALUES (3, 'login', NULL, 1.5), (1, 'signup', x'00ff', NULL), (2, 'logout', NULL, 2);
+		INSERT INTO tags VALUES ('zeta', 1), ('alpha', 7), ('mid', 2);
+	`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	content, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+
+	explore := func(s SQLiteSampling) string {
+		e := &SQLiteExplorer{formatterProfile: OutputProfileEnhancement, sampling: s}
+		result, err := e.Explore(context.Background(), ExploreInput{Path: "shape.db", Content: content})
+		require.NoError(t, err)
+		return result.Summary
+	}
+
+	s := explore(SQLiteSampling{SampleRows: 2})
+	require.Contains(t, s, "Row counts:\n  - empty: 0 rows\n  - events: 3 rows\n  - tags: 3 rows\n")
+	require.Contains(t, s, "Sample rows of events (by rowid):\n"+
+		"  - column types: id integer, kind text, payload blob|null, score null|real\n"+
+		"  - row 1: { id: 1, kind: signup, payload: <BLOB 2 bytes>, score: NULL }\n"+
+		"  - row 2: { id: 2, kind: logout, payload: NULL, score: 2 }\n")
+	require.Contains(t, s, "Sample rows of tags (by primary key):\n"+
+		"  - column types: name text, uses integer\n"+
+		"  - row 1: { name: alpha, uses: 7 }\n"+
+		"  - row 2: { name: mid, uses: 2 }\n")
+	require.NotContains(t, s, "Sample rows of empty")
+	require.NotContains(t, s, "Sample row summaries:")
+
+	s = explore(SQLiteSampling{SampleRows: -1, MaxTables: 1, SkipRowCounts: true})
+	require.NotContains(t, s, "Row counts")
+	require.NotContains(t, s, "Sample rows of")
+
+	s = explore(SQLiteSampling{MaxTables: 2, MaxCellLength: 3})
+	require.Contains(t, s, "Row counts (first 2 of 3 tables):\n")
+	require.Contains(t, s, "{ id: 1, kind: sig..., payload: <BLOB 2 bytes>, score: NULL }")
+	require.NotContains(t, s, "Sample rows of tags")
+}
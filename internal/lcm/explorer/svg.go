@@ -0,0 +1,109 @@
+package explorer
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// svgShapes are the SVG elements that draw geometry.
+var svgShapes = map[string]bool{
+	"path":     true,
+	"rect":     true,
+	"circle":   true,
+	"ellipse":  true,
+	"line":     true,
+	"polyline": true,
+	"polygon":  true,
+}
+
+// svgStats summarizes an SVG document for XMLExplorer: its declared
+// canvas and what it is drawn with.
+type svgStats struct {
+	viewBox  string
+	width    string
+	height   string
+	elements int
+	shapes   map[string]int
+	groups   int
+	text     int
+	images   int
+	scripts  int
+	defs     int // gradients, patterns, filters, masks and clip paths
+}
+
+// newSVGStats returns stats for a document whose root element is root, or
+// nil when the root is not <svg>.
+func newSVGStats(root xml.StartElement) *svgStats {
+	if root.Name.Local != "svg" {
+		return nil
+	}
+	s := &svgStats{shapes: make(map[string]int)}
+	for _, attr := range root.Attr {
+		switch attr.Name.Local {
+		case "viewBox":
+			s.viewBox = strings.Join(strings.Fields(strings.ReplaceAll(attr.Value, ",", " ")), " ")
+		case "width":
+			s.width = attr.Value
+		case "height":
+			s.height = attr.Value
+		}
+	}
+	return s
+}
+
+// add counts one element below the root.
+func (s *svgStats) add(el xml.StartElement) {
+	s.elements++
+	name := el.Name.Local
+	switch {
+	case svgShapes[name]:
+		s.shapes[name]++
+	case name == "g" || name == "symbol" || name == "use":
+		s.groups++
+	case name == "text" || name == "tspan" || name == "textPath":
+		s.text++
+	case name == "image":
+		s.images++
+	case name == "script":
+		s.scripts++
+	case strings.HasSuffix(name, "Gradient") || name == "pattern" || name == "filter" || name == "mask" || name == "clipPath":
+		s.defs++
+	}
+}
+
+// write appends the SVG section to summary.
+func (s *svgStats) write(summary *strings.Builder) {
+	summary.WriteString("\nSVG:\n")
+	if s.viewBox != "" {
+		fmt.Fprintf(summary, "  - viewBox: %s\n", s.viewBox)
+	}
+	if s.width != "" || s.height != "" {
+		fmt.Fprintf(summary, "  - Size: %s x %s\n", cmp.Or(s.width, "-"), cmp.Or(s.height, "-"))
+	}
+	fmt.Fprintf(summary, "  - Elements: %d\n", s.elements)
+	if len(s.shapes) > 0 {
+		var shapes []string
+		total := 0
+		for _, name := range sortedKeys(s.shapes) {
+			shapes = append(shapes, fmt.Sprintf("%s ×%d", name, s.shapes[name]))
+			total += s.shapes[name]
+		}
+		fmt.Fprintf(summary, "  - Shapes: %d (%s)\n", total, strings.Join(shapes, ", "))
+	}
+	for _, c := range []struct {
+		label string
+		n     int
+	}{
+		{"Groups and reuses", s.groups},
+		{"Text elements", s.text},
+		{"Paint servers and effects", s.defs},
+		{"Embedded images", s.images},
+		{"Scripts", s.scripts},
+	} {
+		if c.n > 0 {
+			fmt.Fprintf(summary, "  - %s: %d\n", c.label, c.n)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package explorer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestXMLExplorer_SVG(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0,0 24 24" width="24">
+  <defs><linearGradient id="g"/></defs>
+  <g><path d="M0 0"/><path d="M1 1"/><circle r="2"/></g>
+  <text>hi</text>
+  <script>alert(1)</script>
+</svg>`)
+
+	e := &XMLExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "icon.svg", Content: content})
+	require.NoError(t, err)
+	require.Equal(t, "xml", result.ExplorerUsed)
+	require.Contains(t, result.Summary, "SVG:\n"+
+		"  - viewBox: 0 0 24 24\n"+
+		"  - Size: 24 x -\n"+
+		"  - Elements: 8\n"+
+		"  - Shapes: 3 (circle ×1, path ×2)\n"+
+		"  - Groups and reuses: 1\n"+
+		"  - Text elements: 1\n"+
+		"  - Paint servers and effects: 1\n"+
+		"  - Scripts: 1\n")
+
+	// Parity output and plain XML documents have no SVG section.
+	result, err = (&XMLExplorer{formatterProfile: OutputProfileParity}).Explore(context.Background(), ExploreInput{Path: "icon.svg", Content: content})
+	require.NoError(t, err)
+	require.NotContains(t, result.Summary, "SVG:")
+	result, err = e.Explore(context.Background(), ExploreInput{Path: "a.xml", Content: []byte(`<root><svg/></root>`)})
+	require.NoError(t, err)
+	require.NotContains(t, result.Summary, "SVG:")
+}
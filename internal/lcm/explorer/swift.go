@@ -0,0 +1,215 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/lcm/explorer/stdlib"
+)
+
+// SwiftExplorer explores Swift source files without tree-sitter: imports,
+// types, extensions, functions and properties with their access level.
+// It is a family-tier explorer, so TreeSitterExplorer wins when built in.
+type SwiftExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// swiftDeclRe matches a declaration line: attributes, modifiers, the
+// introducing keyword and the rest of the line. "class" is also a
+// modifier; backtracking sorts out "class func" from "class Foo".
+var swiftDeclRe = regexp.MustCompile(`^\s*((?:@[\w.]+(?:\([^)]*\))?\s+)*)((?:(?:(?:public|open|internal|fileprivate|private)(?:\(set\))?|static|class|final|override|mutating|nonmutating|convenience|required|lazy|weak|unowned|dynamic|indirect|nonisolated|distributed|optional|prefix|postfix|infix)\s+)*)(class|struct|enum|protocol|extension|actor|func|init|subscript|typealias|associatedtype|var|let)\b[?!]?\s*`)
+
+var (
+	swiftNameRe      = regexp.MustCompile("^(`?\\w+`?|[^\\s(<]+)")
+	swiftExtensionRe = regexp.MustCompile(`^[\w.]+`)
+	// swiftImportRe captures the module of plain and kind imports, such
+	// as "import struct Foundation.Date".
+	swiftImportRe = regexp.MustCompile(`(?m)^\s*(?:@\w+\s+)*import\s+(?:(?:typealias|struct|class|enum|protocol|let|var|func)\s+)?(\w+)`)
+	swiftReturnRe = regexp.MustCompile(`^\s*(?:async\s*)?(?:(?:re)?throws(?:\([^)]*\))?\s*)?->\s*([^{=]+?)\s*(?:\bwhere\b.*)?(?:\{.*)?$`)
+	swiftAsyncRe  = regexp.MustCompile(`^\s*async\b`)
+)
+
+// swiftTypeKinds maps type keywords to symbol kinds.
+var swiftTypeKinds = map[string]string{
+	"class": "class", "struct": "struct", "enum": "enum", "protocol": "protocol",
+	"extension": "extension", "actor": "actor",
+}
+
+func (e *SwiftExplorer) CanHandle(path string, content []byte) bool {
+	if strings.EqualFold(filepath.Ext(path), ".swift") {
+		return true
+	}
+	return filepath.Ext(path) == "" && detectShebang(content) == "swift"
+}
+
+func (e *SwiftExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	name := filepath.Base(input.Path)
+	if len(input.Content) > MaxFullLoadSize {
+		summary := fmt.Sprintf("Swift file too large: %s (%d bytes)", name, len(input.Content))
+		return ExploreResult{Summary: summary, ExplorerUsed: "swift", TokenEstimate: estimateTokens(summary)}, nil
+	}
+
+	orig := string(input.Content)
+	code, err := blankCodeLiterals(orig, swiftLiterals)
+	var decls []codeDecl
+	if err == nil {
+		decls, err = scanDecls(code, orig, matchSwiftDecl)
+	}
+	if err == nil {
+		inheritSwiftAccess(decls)
+	}
+	if err != nil {
+		return degradedTextResult("Swift file: "+name, "swift", input.Content, codeDegradation("swift", input.Content, decls, err)), nil
+	}
+	return codeExploration{
+		header:      "Swift file: " + name,
+		lang:        "swift",
+		imports:     swiftImports(code),
+		decls:       decls,
+		explorer:    "swift",
+		returnArrow: " -> ",
+	}.write(e.formatterProfile), nil
+}
+
+// swiftImports returns the imported modules of code. A Swift module is
+// either part of the SDK or a package dependency.
+func swiftImports(code string) []codeImport {
+	var out []codeImport
+	seen := map[string]bool{}
+	for _, m := range swiftImportRe.FindAllStringSubmatch(code, -1) {
+		if seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		category := "third_party"
+		if stdlib.IsSwiftStdlib(m[1]) {
+			category = "stdlib"
+		}
+		out = append(out, codeImport{path: m[1], category: category})
+	}
+	return out
+}
+
+// matchSwiftDecl recognizes a Swift declaration line.
+func matchSwiftDecl(code, orig string, start, end int) (codeDecl, bool) {
+	line := code[start:end]
+	m := swiftDeclRe.FindStringSubmatchIndex(line)
+	if m == nil {
+		return codeDecl{}, false
+	}
+	keyword := line[m[6]:m[7]]
+	restAt := start + m[1]
+	rest := code[restAt:end]
+
+	var d codeDecl
+	d.attributes = declAttributes(line[m[2]:m[3]])
+	d.modifiers = strings.Fields(line[m[4]:m[5]])
+
+	switch keyword {
+	case "init", "subscript":
+		d.name, d.kind = keyword, "initializer"
+		if keyword == "subscript" {
+			d.kind = "subscript"
+		}
+		if i := strings.IndexByte(rest, '('); i >= 0 {
+			d.params, _ = balancedSpan(code, orig, restAt+i, '(', ')')
+		}
+		return d, true
+	case "extension":
+		n := swiftExtensionRe.FindString(rest)
+		if n == "" {
+			return codeDecl{}, false
+		}
+		d.name, d.kind, d.isType = n, "extension", true
+		d.supertypes = swiftSupertypes(rest[len(n):])
+		return d, true
+	}
+
+	n := swiftNameRe.FindString(rest)
+	if n == "" || strings.HasPrefix(n, "(") {
+		// Tuple destructuring and the like.
+		return codeDecl{}, false
+	}
+	d.name = strings.Trim(n, "`")
+	after := rest[len(n):]
+	switch keyword {
+	case "func":
+		d.kind = "function"
+		i := strings.IndexByte(after, '(')
+		if i < 0 {
+			return d, true
+		}
+		var next int
+		d.params, next = balancedSpan(code, orig, restAt+len(n)+i, '(', ')')
+		tail, _, _ := strings.Cut(orig[next:], "\n")
+		if swiftAsyncRe.MatchString(tail) {
+			d.modifiers = append(d.modifiers, "async")
+		}
+		if r := swiftReturnRe.FindStringSubmatch(tail); r != nil {
+			d.returnType = strings.TrimSpace(r[1])
+		}
+	case "var", "let":
+		d.kind = "variable"
+	case "typealias", "associatedtype":
+		d.kind = keyword
+	default:
+		d.kind, d.isType = swiftTypeKinds[keyword], true
+		d.supertypes = swiftSupertypes(after)
+	}
+	return d, true
+}
+
+// swiftSupertypes returns the inheritance clause of a type header rest,
+// which starts after the type name.
+func swiftSupertypes(rest string) []string {
+	if strings.HasPrefix(strings.TrimSpace(rest), "<") {
+		if i := strings.IndexByte(rest, '>'); i >= 0 {
+			rest = rest[i+1:]
+		}
+	}
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, ":") {
+		return nil
+	}
+	rest = rest[1:]
+	if i := strings.IndexByte(rest, '{'); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, " where "); i >= 0 {
+		rest = rest[:i]
+	}
+	return splitTopLevel(rest)
+}
+
+// swiftAccessModifiers are the Swift access levels.
+var swiftAccessModifiers = []string{"open", "public", "internal", "fileprivate", "private"}
+
+// inheritSwiftAccess gives members without an access level of their own
+// that of their protocol, or of their extension when it declares one.
+func inheritSwiftAccess(decls []codeDecl) {
+	access := func(d codeDecl) string {
+		for _, m := range d.modifiers {
+			if slices.Contains(swiftAccessModifiers, m) {
+				return m
+			}
+		}
+		return ""
+	}
+	for i := range decls {
+		d := &decls[i]
+		if d.owner < 0 || access(*d) != "" {
+			continue
+		}
+		owner := decls[d.owner]
+		if owner.kind != "protocol" && owner.kind != "extension" {
+			continue
+		}
+		if a := access(owner); a != "" {
+			d.modifiers = append(d.modifiers, a)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package explorer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSwift = `import Foundation
+import SwiftUI
+@testable import Alamofire
+import struct Vapor.Request
+
+/// A user store.
+public protocol UserStore: AnyObject, Sendable {
+    func user(id: String) async throws -> User?
+    var count: Int { get }
+}
+
+public final class RemoteStore: UserStore, @unchecked Sendable {
+    public private(set) var count: Int = 0
+    private let session: URLSession
+    static let shared = RemoteStore(session: .shared)
+
+    public init(session: URLSession) {
+        self.session = session
+        let local = "{ not a brace"
+    }
+
+    public func user(id: String) async throws -> User? {
+        func helper() {}
+        return nil
+    }
+
+    fileprivate func reset() { count = 0 }
+
+    struct Cache<Key: Hashable> where Key: Sendable {
+        var items: [Key: User] = [:]
+        mutating func clear() { items.removeAll() }
+    }
+}
+
+extension RemoteStore: CustomStringConvertible {
+    public var description: String {
+        "RemoteStore(\(count))"
+    }
+}
+
+enum Mode: String, CaseIterable {
+    case fast, slow
+    var label: String { rawValue }
+}
+
+@MainActor
+func configure(
+    store: RemoteStore,
+    mode: Mode = .fast
+) -> Bool {
+    true
+}
+
+/* nested /* comment */ still comment { */
+actor Counter {
+    private var value = 0
+    func increment() -> Int { value += 1; return value }
+}
+typealias Handler = (String) -> Void
+`
+
+func TestSwiftExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &SwiftExplorer{}
+	require.True(t, e.CanHandle("Sources/App/Store.swift", nil))
+	require.True(t, e.CanHandle("deploy", []byte("#!/usr/bin/env swift\nprint(1)\n")))
+	require.False(t, e.CanHandle("Package.resolved", nil))
+	require.False(t, e.CanHandle("notes.txt", []byte("#!/usr/bin/env swift\n")))
+}
+
+func TestSwiftExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &SwiftExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "Sample.swift", Content: []byte(testSwift)})
+	require.NoError(t, err)
+	require.Equal(t, "swift", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "Swift file: Sample.swift\nLanguage: swift\nDeclarations: 22 (8 public, 11 internal, 3 private)\n")
+	require.Contains(t, s, "Imports:\n"+
+		"  - Foundation (stdlib)\n"+
+		"  - SwiftUI (stdlib)\n"+
+		"  - Alamofire (third_party)\n"+
+		"  - Vapor (third_party)\n")
+	// Protocol requirements take the protocol's access level.
+	require.Contains(t, s, "  - protocol UserStore (public, line 7)\n  - method UserStore.user (public, line 8)\n")
+	require.Contains(t, s, "  - property RemoteStore.count (public, line 13)\n")
+	require.Contains(t, s, "  - property RemoteStore.session (private, line 14)\n")
+	require.Contains(t, s, "  - initializer RemoteStore.init (public, line 17)\n")
+	require.Contains(t, s, "  - method RemoteStore.reset (private, line 27)\n")
+	require.Contains(t, s, "  - method RemoteStore.Cache.clear (internal, line 31)\n")
+	require.Contains(t, s, "  - extension RemoteStore (internal, line 35)\n")
+	require.Contains(t, s, "  - function configure (internal, line 47)\n")
+	require.Contains(t, s, "  - actor Counter (internal, line 55)\n")
+	// Local functions and literals are not declarations.
+	require.NotContains(t, s, "helper")
+	require.NotContains(t, s, "local")
+	require.NotContains(t, s, "Inheritance:")
+}
+
+func TestSwiftExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	e := &SwiftExplorer{formatterProfile: OutputProfileEnhancement}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "Sample.swift", Content: []byte(testSwift)})
+	require.NoError(t, err)
+
+	s := result.Summary
+	require.Contains(t, s, "  - method RemoteStore.user (public, async, line 22): (id: String) -> User?\n")
+	require.Contains(t, s, "  - property RemoteStore.shared (internal, static, line 15)\n")
+	require.Contains(t, s, "  - function configure (internal, @MainActor, line 47): (store: RemoteStore, mode: Mode = .fast) -> Bool\n")
+	require.Contains(t, s, "Inheritance:\n"+
+		"  - UserStore: AnyObject, Sendable\n"+
+		"  - RemoteStore: UserStore, @unchecked Sendable\n"+
+		"  - RemoteStore: CustomStringConvertible\n"+
+		"  - Mode: String, CaseIterable")
+}
+
+func TestSwiftExplorer_Explore_Degraded(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		content string
+		failed  string
+	}{
+		{content: "struct A {\n  func f() {\n}\n", failed: "line 1, column 10: unclosed brace"},
+		{content: "func f() {}\n}\n", failed: "line 2, column 1: unmatched closing brace"},
+		{content: "let s = \"open\n", failed: "line 1, column 9: unterminated string literal"},
+		{content: "/* a /* b */\nstruct A {}\n", failed: "line 1, column 1: unterminated block comment"},
+	} {
+		result, err := (&SwiftExplorer{}).Explore(context.Background(), ExploreInput{Path: "A.swift", Content: []byte(tt.content)})
+		require.NoError(t, err)
+		require.Regexp(t, degradedBlockPattern, result.Summary)
+		require.Contains(t, result.Summary, "Failed: swift scanning at "+tt.failed+"\n")
+	}
+}
+
+func TestSwiftExplorer_ThroughRegistry(t *testing.T) {
+	t.Parallel()
+
+	for _, profile := range []OutputProfile{OutputProfileParity, OutputProfileEnhancement} {
+		registry := NewRegistry(WithOutputProfile(profile))
+		result, err := registry.Explore(context.Background(), ExploreInput{Path: "Sample.swift", Content: []byte(testSwift)})
+		require.NoError(t, err)
+		require.Equal(t, "swift", result.ExplorerUsed)
+		require.Equal(t, profile == OutputProfileEnhancement, strings.Contains(result.Summary, "### Inheritance"))
+	}
+}
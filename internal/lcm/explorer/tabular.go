@@ -0,0 +1,271 @@
+package explorer
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TabularExplorer explores delimiter-separated tables (.csv, .tsv, .psv):
+// column names, inferred types, null ratios and a sampled preview.
+type TabularExplorer struct {
+	formatterProfile OutputProfile
+}
+
+// CSVExplorer is the name the runtime inventory and the parity matrix know
+// TabularExplorer by.
+type CSVExplorer = TabularExplorer
+
+const (
+	// tabularPreviewRows is the number of data rows in the sampled preview.
+	tabularPreviewRows = 3
+	// tabularMaxDistinct caps the distinct values tracked per column.
+	tabularMaxDistinct = 1000
+	// tabularMaxCellLength is the maximum length of a previewed cell.
+	tabularMaxCellLength = 40
+)
+
+// tabularDelimiters maps extensions to their field delimiter.
+var tabularDelimiters = map[string]rune{
+	".csv": ',',
+	".tsv": '\t',
+	".psv": '|',
+}
+
+// tabularNulls are the cell values counted as missing, lowercased.
+var tabularNulls = map[string]bool{
+	"": true, "na": true, "n/a": true, "null": true, "none": true, "nil": true,
+}
+
+// tabularDateLayouts are the layouts a cell must match to infer a date.
+var tabularDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"01/02/2006",
+}
+
+// tabularColumn accumulates statistics for one column.
+type tabularColumn struct {
+	name     string
+	kind     string // inferred type; "" until a non-null value is seen
+	nulls    int
+	distinct map[string]struct{}
+	numeric  bool
+	min, max float64
+}
+
+// tabularRow is a data row with its 1-based position.
+type tabularRow struct {
+	index  int
+	fields []string
+	hash   uint32
+}
+
+func (e *TabularExplorer) CanHandle(path string, content []byte) bool {
+	_, ok := tabularDelimiters[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+func (e *TabularExplorer) Explore(ctx context.Context, input ExploreInput) (ExploreResult, error) {
+	ext := strings.ToLower(filepath.Ext(input.Path))
+	header := fmt.Sprintf("%s file: %s", strings.ToUpper(strings.TrimPrefix(ext, ".")), filepath.Base(input.Path))
+
+	reader := csv.NewReader(strings.NewReader(string(input.Content)))
+	reader.Comma = tabularDelimiters[ext]
+	// Ragged rows are counted rather than rejected.
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var (
+		columns []*tabularColumn
+		preview []tabularRow
+		rows    int
+		ragged  int
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return degradedTextResult(header+" (parse error)", "csv", input.Content, degradedExploration{
+				Failed:   "CSV parsing: " + err.Error(),
+				Progress: fmt.Sprintf("%d rows read before the error", rows),
+				Examined: reader.InputOffset(),
+				Size:     int64(len(input.Content)),
+				NextSteps: []string{
+					"Check the reported line for an unbalanced quote or a row with a different field count",
+					"Read the raw lines around the error with the view tool",
+				},
+			}), nil
+		}
+		rows++
+		if rows == 1 {
+			for _, name := range record {
+				columns = append(columns, &tabularColumn{name: name, distinct: make(map[string]struct{})})
+			}
+			continue
+		}
+		if len(record) != len(columns) {
+			ragged++
+		}
+		for i, col := range columns {
+			value := ""
+			if i < len(record) {
+				value = record[i]
+			}
+			col.add(value)
+		}
+		preview = samplePreviewRow(preview, rows-1, record)
+	}
+
+	var summary strings.Builder
+	summary.WriteString(header + "\n")
+	fmt.Fprintf(&summary, "Rows: %d\n", rows)
+	if len(columns) == 0 {
+		result := summary.String()
+		return ExploreResult{Summary: result, ExplorerUsed: "csv", TokenEstimate: estimateTokens(result)}, nil
+	}
+	dataRows := rows - 1
+	fmt.Fprintf(&summary, "Data rows: %d\n", dataRows)
+	fmt.Fprintf(&summary, "Columns: %d\n", len(columns))
+	if ragged > 0 {
+		fmt.Fprintf(&summary, "Ragged rows: %d\n", ragged)
+	}
+
+	summary.WriteString("\nColumn headers:\n")
+	numbers := outlineNumbers(make([]int, len(columns)))
+	for i, col := range columns {
+		fmt.Fprintf(&summary, "  %s. %s (%s, %d%% null)\n", numbers[i], col.name, cmp.Or(col.kind, "empty"), percent(col.nulls, dataRows))
+	}
+
+	if len(preview) > 0 {
+		summary.WriteString("\nSample rows:\n")
+		slices.SortFunc(preview, func(a, b tabularRow) int { return a.index - b.index })
+		width := len(strconv.Itoa(dataRows))
+		for _, row := range preview {
+			cells := make([]string, len(row.fields))
+			for i, cell := range row.fields {
+				cells[i] = truncateSample(cell, tabularMaxCellLength)
+			}
+			fmt.Fprintf(&summary, "  Row %0*d: %s\n", width, row.index, strings.Join(cells, " | "))
+		}
+	}
+
+	// EXCEED MODE: value ranges and cardinality per column.
+	if e.formatterProfile == OutputProfileEnhancement && dataRows > 0 {
+		summary.WriteString("\nColumn statistics:\n")
+		for i, col := range columns {
+			distinct := strconv.Itoa(len(col.distinct))
+			if len(col.distinct) >= tabularMaxDistinct {
+				distinct += "+"
+			}
+			fmt.Fprintf(&summary, "  %s. %s: %s distinct", numbers[i], col.name, distinct)
+			if col.numeric {
+				fmt.Fprintf(&summary, ", min %s, max %s", formatNumber(col.min), formatNumber(col.max))
+			}
+			summary.WriteString("\n")
+		}
+	}
+
+	result := summary.String()
+	return ExploreResult{
+		Summary:       result,
+		ExplorerUsed:  "csv",
+		TokenEstimate: estimateTokens(result),
+	}, nil
+}
+
+// add records one cell of the column.
+func (c *tabularColumn) add(value string) {
+	value = strings.TrimSpace(value)
+	if tabularNulls[strings.ToLower(value)] {
+		c.nulls++
+		return
+	}
+	if len(c.distinct) < tabularMaxDistinct {
+		c.distinct[value] = struct{}{}
+	}
+
+	kind := tabularValueType(value)
+	switch {
+	case c.kind == "":
+		c.kind = kind
+	case c.kind == kind:
+	case (c.kind == "integer" && kind == "float") || (c.kind == "float" && kind == "integer"):
+		c.kind = "float"
+	default:
+		c.kind = "string"
+	}
+
+	if kind != "integer" && kind != "float" {
+		return
+	}
+	n, _ := strconv.ParseFloat(value, 64)
+	if !c.numeric {
+		c.numeric, c.min, c.max = true, n, n
+		return
+	}
+	c.min, c.max = min(c.min, n), max(c.max, n)
+}
+
+// tabularValueType infers the type of a non-null cell.
+func tabularValueType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "integer"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "float"
+	}
+	if _, err := strconv.ParseBool(value); err == nil && len(value) > 1 {
+		return "boolean"
+	}
+	for _, layout := range tabularDateLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return "date"
+		}
+	}
+	return "string"
+}
+
+// samplePreviewRow keeps the tabularPreviewRows rows with the lowest hash,
+// the streaming form of deterministicallySample. record is copied only
+// when kept, as the reader reuses it.
+func samplePreviewRow(kept []tabularRow, index int, record []string) []tabularRow {
+	hash := fnv1aHash(strings.Join(record, "\x00"))
+	if len(kept) < tabularPreviewRows {
+		return append(kept, tabularRow{index: index, fields: slices.Clone(record), hash: hash})
+	}
+	worst := 0
+	for i, k := range kept {
+		if k.hash > kept[worst].hash {
+			worst = i
+		}
+	}
+	if hash < kept[worst].hash {
+		kept[worst] = tabularRow{index: index, fields: slices.Clone(record), hash: hash}
+	}
+	return kept
+}
+
+// percent returns n as a rounded percentage of total.
+func percent(n, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return (n*100 + total/2) / total
+}
+
+// formatNumber prints a float without trailing zeros.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,111 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testTable = `id,name,score,active,joined,notes
+1,Alice,9.5,true,2024-01-02,
+2,Bob,7,false,2024-02-03,NA
+3,Charlie,8.25,true,2024-03-04,vip
+4,Dana,,false,2024-04-05,
+`
+
+func TestTabularExplorer_CanHandle(t *testing.T) {
+	t.Parallel()
+
+	e := &TabularExplorer{}
+	require.True(t, e.CanHandle("data.csv", nil))
+	require.True(t, e.CanHandle("data.TSV", nil))
+	require.True(t, e.CanHandle("data.psv", nil))
+	require.False(t, e.CanHandle("data.txt", nil))
+}
+
+func TestTabularExplorer_Explore(t *testing.T) {
+	t.Parallel()
+
+	e := &TabularExplorer{formatterProfile: OutputProfileParity}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "people.csv", Content: []byte(testTable)})
+	require.NoError(t, err)
+	require.Equal(t, "csv", result.ExplorerUsed)
+
+	s := result.Summary
+	require.Contains(t, s, "CSV file: people.csv\n")
+	require.Contains(t, s, "Rows: 5\nData rows: 4\nColumns: 6\n")
+	require.Contains(t, s, "Column headers:\n"+
+		"  1. id (integer, 0% null)\n"+
+		"  2. name (string, 0% null)\n"+
+		"  3. score (float, 25% null)\n"+
+		"  4. active (boolean, 0% null)\n"+
+		"  5. joined (date, 0% null)\n"+
+		"  6. notes (string, 75% null)\n")
+	require.Equal(t, 3, strings.Count(s, "  Row "))
+	require.True(t, strings.HasSuffix(s, "Sample rows:\n"+
+		"  Row 1: 1 | Alice | 9.5 | true | 2024-01-02 | \n"+
+		"  Row 2: 2 | Bob | 7 | false | 2024-02-03 | NA\n"+
+		"  Row 4: 4 | Dana |  | false | 2024-04-05 | \n"), s)
+	require.NotContains(t, s, "Ragged rows:")
+}
+
+func TestTabularExplorer_Explore_Enhancement(t *testing.T) {
+	t.Parallel()
+
+	input := ExploreInput{Path: "people.csv", Content: []byte(testTable)}
+	parity, err := (&TabularExplorer{formatterProfile: OutputProfileParity}).Explore(context.Background(), input)
+	require.NoError(t, err)
+	result, err := (&TabularExplorer{formatterProfile: OutputProfileEnhancement}).Explore(context.Background(), input)
+	require.NoError(t, err)
+
+	// Statistics follow the sample rows, one entry per column header.
+	stats, ok := strings.CutPrefix(result.Summary, parity.Summary)
+	require.True(t, ok, result.Summary)
+	require.Equal(t, "\nColumn statistics:\n"+
+		"  1. id: 4 distinct, min 1, max 4\n"+
+		"  2. name: 4 distinct\n"+
+		"  3. score: 3 distinct, min 7, max 9.5\n"+
+		"  4. active: 2 distinct\n"+
+		"  5. joined: 4 distinct\n"+
+		"  6. notes: 1 distinct\n", stats)
+}
+
+func TestTabularExplorer_Delimiters(t *testing.T) {
+	t.Parallel()
+
+	e := &TabularExplorer{}
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "t.tsv", Content: []byte("a\tb\n1\tx\n2\n")})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "TSV file: t.tsv\n")
+	require.Contains(t, result.Summary, "Ragged rows: 1\n")
+	require.Contains(t, result.Summary, "  2. b (string, 50% null)\n")
+
+	result, err = e.Explore(context.Background(), ExploreInput{Path: "t.psv", Content: []byte("a|b\n1|2\n")})
+	require.NoError(t, err)
+	require.Contains(t, result.Summary, "PSV file: t.psv\n")
+	require.Contains(t, result.Summary, "  2. b (integer, 0% null)\n")
+}
+
+func TestTabularExplorer_SampleIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	var content strings.Builder
+	content.WriteString("n,label\n")
+	for i := range 500 {
+		fmt.Fprintf(&content, "%d,row-%d\n", i, i)
+	}
+
+	e := &TabularExplorer{}
+	first, err := e.Explore(context.Background(), ExploreInput{Path: "big.csv", Content: []byte(content.String())})
+	require.NoError(t, err)
+	for range 3 {
+		again, err := e.Explore(context.Background(), ExploreInput{Path: "big.csv", Content: []byte(content.String())})
+		require.NoError(t, err)
+		require.Equal(t, first.Summary, again.Summary)
+	}
+	require.Contains(t, first.Summary, "Data rows: 500\n")
+	require.Equal(t, 3, strings.Count(first.Summary, "  Row "))
+}
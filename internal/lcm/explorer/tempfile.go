@@ -1,11 +1,21 @@
 package explorer
 
-import "os"
+import (
+	"bytes"
+	"io"
+	"os"
+)
 
 // withTempFile creates a temporary file with the given prefix and content,
 // closes the file, calls fn with its path, and removes the file on return.
 // The file is always cleaned up, even when fn returns an error.
 func withTempFile(prefix string, content []byte, fn func(path string) error) error {
+	return withTempFileFrom(prefix, bytes.NewReader(content), fn)
+}
+
+// withTempFileFrom is withTempFile with the content copied from r, so large
+// content never has to be held in memory.
+func withTempFileFrom(prefix string, r io.Reader, fn func(path string) error) error {
 	f, err := os.CreateTemp("", prefix)
 	if err != nil {
 		return err
@@ -13,7 +23,7 @@ func withTempFile(prefix string, content []byte, fn func(path string) error) err
 	path := f.Name()
 	defer os.Remove(path)
 
-	if _, err := f.Write(content); err != nil {
+	if _, err := io.Copy(f, r); err != nil {
 		f.Close()
 		return err
 	}
@@ -0,0 +1,156 @@
+package explorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// textSampleTokens is the token budget of the blocks sampled from a
+	// text file too large to load, about what sampleContent keeps.
+	textSampleTokens = 3000
+	// textMiddleSamples is the number of strata between the head and the
+	// tail block, each sampled at its center.
+	textMiddleSamples = 4
+	// textScanChunk is the read size of the streaming line and word count.
+	textScanChunk = 256 * 1024
+)
+
+// textSample is a block of a large text file, cut at line boundaries.
+type textSample struct {
+	offset int64
+	text   string
+	// line is the 1-based line the block starts on.
+	line int
+}
+
+// textSampling is the outcome of sampling a large text file: streaming
+// counts over the whole file and the sampled blocks in file order.
+type textSampling struct {
+	size    int64
+	lines   int
+	words   int
+	samples []textSample
+}
+
+// textSampleOffsets returns the start and length of the head block, the
+// stratified middle blocks and the tail block of a size-byte file. The
+// selection depends only on size, so it is the same on every run.
+func textSampleOffsets(size int64) [][2]int64 {
+	budget := int64(textSampleTokens * 4)
+	block := budget / (textMiddleSamples + 2)
+	if size <= budget {
+		return [][2]int64{{0, size}}
+	}
+	out := [][2]int64{{0, block}}
+	// The middle strata split what lies between the head and tail blocks.
+	span := size - 2*block
+	for i := range int64(textMiddleSamples) {
+		center := block + span*(2*i+1)/(2*textMiddleSamples)
+		out = append(out, [2]int64{center - block/2, block})
+	}
+	return append(out, [2]int64{size - block, block})
+}
+
+// sampleText reads the size bytes of r once to count lines and words and
+// keeps the blocks at textSampleOffsets, trimmed to whole lines.
+func sampleText(ctx context.Context, r io.ReaderAt, size int64) (textSampling, error) {
+	plan := textSampleOffsets(size)
+	s := textSampling{size: size, lines: 1}
+	for _, p := range plan {
+		buf := make([]byte, p[1])
+		n, err := r.ReadAt(buf, p[0])
+		if err != nil && !errors.Is(err, io.EOF) {
+			return s, err
+		}
+		s.samples = append(s.samples, textSample{offset: p[0], text: string(buf[:n])})
+	}
+
+	// Stream the whole file for the counts and the line of each block.
+	next := 0
+	inWord := false
+	buf := make([]byte, textScanChunk)
+	for off := int64(0); off < size; off += textScanChunk {
+		if err := ctx.Err(); err != nil {
+			return s, err
+		}
+		n, err := r.ReadAt(buf[:min(textScanChunk, size-off)], off)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return s, err
+		}
+		for i, c := range buf[:n] {
+			for next < len(s.samples) && s.samples[next].offset == off+int64(i) {
+				s.samples[next].line = s.lines
+				next++
+			}
+			if c == '\n' {
+				s.lines++
+			}
+			space := c == ' ' || c == '\n' || c == '\t' || c == '\r' || c == '\v' || c == '\f'
+			if !space && !inWord {
+				s.words++
+			}
+			inWord = !space
+		}
+	}
+
+	// Cut blocks after the head to start on a line, and blocks before the
+	// tail to end on one, so no sample shows a partial line.
+	for i := range s.samples {
+		b := &s.samples[i]
+		if i > 0 {
+			if nl := strings.IndexByte(b.text, '\n'); nl >= 0 && nl < len(b.text)-1 {
+				b.offset += int64(nl + 1)
+				b.text = b.text[nl+1:]
+				b.line++
+			}
+		}
+		if i < len(s.samples)-1 {
+			if nl := strings.LastIndexByte(b.text, '\n'); nl > 0 {
+				b.text = b.text[:nl+1]
+			}
+		}
+	}
+	return s, nil
+}
+
+// write renders the counts and the blocks, each preceded by a marker with
+// its byte range and lines.
+func (s textSampling) write(sb *strings.Builder) {
+	fmt.Fprintf(sb, "Lines: %d\n", s.lines)
+	fmt.Fprintf(sb, "Words: %d\n", s.words)
+	fmt.Fprintf(sb, "Size: %d bytes\n", s.size)
+	fmt.Fprintf(sb, "Sampling: head, %d stratified middle and tail blocks within %d tokens\n", textMiddleSamples, textSampleTokens)
+	sb.WriteString("Content (sampled):\n")
+	for i, b := range s.samples {
+		end := b.offset + int64(len(b.text))
+		lastLine := b.line + strings.Count(strings.TrimSuffix(b.text, "\n"), "\n")
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(sb, "...[SAMPLED bytes %d-%d, lines %d-%d]...\n", b.offset, end, b.line, lastLine)
+		sb.WriteString(strings.TrimSuffix(b.text, "\n"))
+	}
+}
+
+// sampledTextResult summarizes a text file too large to load from r.
+func sampledTextResult(ctx context.Context, header, explorerUsed string, r io.ReaderAt, size int64) (ExploreResult, error) {
+	s, err := sampleText(ctx, r, size)
+	if err != nil {
+		return ExploreResult{}, err
+	}
+	var summary strings.Builder
+	summary.WriteString(header + "\n")
+	s.write(&summary)
+
+	facts := &Facts{}
+	facts.count("lines", s.lines)
+	facts.count("words", s.words)
+	facts.count("bytes", int(size))
+
+	result := summary.String()
+	return ExploreResult{Summary: result, ExplorerUsed: explorerUsed, TokenEstimate: estimateTokens(result), Facts: facts}, nil
+}
@@ -0,0 +1,87 @@
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextSampleOffsets(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, [][2]int64{{0, 500}}, textSampleOffsets(500))
+	require.Equal(t, [][2]int64{
+		{0, 2000},
+		{13000, 2000}, {37000, 2000}, {61000, 2000}, {85000, 2000},
+		{98000, 2000},
+	}, textSampleOffsets(100000))
+}
+
+func TestSampleText(t *testing.T) {
+	t.Parallel()
+
+	var content bytes.Buffer
+	for i := 1; content.Len() < 100000; i++ {
+		fmt.Fprintf(&content, "line %d has five words\n", i)
+	}
+	data := content.Bytes()
+	lines := bytes.Count(data, []byte("\n"))
+
+	s, err := sampleText(context.Background(), bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Equal(t, lines+1, s.lines)
+	require.Equal(t, 5*lines, s.words)
+	require.Len(t, s.samples, textMiddleSamples+2)
+	for _, b := range s.samples {
+		// Every block starts on the line it reports, at a line start.
+		require.True(t, strings.HasPrefix(b.text, fmt.Sprintf("line %d has", b.line)), b.text[:20])
+		require.Equal(t, string(data[b.offset:b.offset+int64(len(b.text))]), b.text)
+	}
+	require.True(t, strings.HasSuffix(s.samples[len(s.samples)-1].text, fmt.Sprintf("line %d has five words\n", lines)))
+
+	var sb strings.Builder
+	s.write(&sb)
+	out := sb.String()
+	require.Contains(t, out, fmt.Sprintf("Lines: %d\nWords: %d\nSize: %d bytes\n", lines+1, 5*lines, len(data)))
+	require.Contains(t, out, "Content (sampled):\n...[SAMPLED bytes 0-1992, lines 1-87]...\nline 1 has five words\n")
+	require.Equal(t, textMiddleSamples+2, strings.Count(out, "...[SAMPLED bytes "))
+
+	again, err := sampleText(context.Background(), bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Equal(t, s, again)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sampleText(ctx, bytes.NewReader(data), int64(len(data)))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTextExplorer_ExploreStream(t *testing.T) {
+	t.Parallel()
+
+	e := &TextExplorer{}
+	small := []byte("alpha\nbeta\n")
+	streamed, err := e.ExploreStream(context.Background(), "notes.txt", bytes.NewReader(small), int64(len(small)))
+	require.NoError(t, err)
+	loaded, err := e.Explore(context.Background(), ExploreInput{Path: "notes.txt", Content: small})
+	require.NoError(t, err)
+	require.Equal(t, loaded, streamed)
+
+	large := bytes.Repeat([]byte("0123456789 abcdefghi\n"), MaxFullLoadSize/20)
+	result, err := e.Explore(context.Background(), ExploreInput{Path: "huge.txt", Content: large})
+	require.NoError(t, err)
+	require.Equal(t, "text", result.ExplorerUsed)
+	require.Contains(t, result.Summary, fmt.Sprintf("Text file (sampled): huge.txt\nLines: %d\nWords: %d\n", MaxFullLoadSize/20+1, MaxFullLoadSize/10))
+	require.Contains(t, result.Summary, "Sampling: head, 4 stratified middle and tail blocks within 3000 tokens\n")
+	require.Less(t, result.TokenEstimate, 4000)
+
+	registry := NewRegistry()
+	formatted, err := registry.ExploreStream(context.Background(), "huge.txt", bytes.NewReader(large), int64(len(large)))
+	require.NoError(t, err)
+	require.Equal(t, "text", formatted.ExplorerUsed)
+	require.Contains(t, formatted.Summary, "...[SAMPLED bytes 0-1995, lines 1-95]...")
+}
@@ -0,0 +1,40 @@
+package explorer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// TokenCounter counts the tokens of text for a model. It has the method
+// set of repomap.TokenCounter, so the repo map's tokenizer-backed counters
+// can be passed as is.
+type TokenCounter interface {
+	Count(ctx context.Context, model string, text string) (int, error)
+}
+
+// WithTokenCounter counts ExploreResult.TokenEstimate with counter for
+// model instead of the character heuristic, as parity runs need exact
+// counts. The heuristic remains the fallback when counting fails. A nil
+// counter keeps the heuristic.
+func WithTokenCounter(counter TokenCounter, model string) RegistryOption {
+	return func(r *Registry) {
+		r.tokenCounter = counter
+		r.tokenModel = model
+	}
+}
+
+// countTokens sets the token estimate of result from the registry's
+// counter. Results are left as they are without one.
+func (r *Registry) countTokens(ctx context.Context, result ExploreResult) ExploreResult {
+	if r.tokenCounter == nil || result.Summary == "" {
+		return result
+	}
+	n, err := r.tokenCounter.Count(ctx, r.tokenModel, result.Summary)
+	if err != nil {
+		slog.Debug("Token counter failed, using heuristic estimate", "model", r.tokenModel, "error", err)
+		result.TokenEstimate = estimateTokens(result.Summary)
+		return result
+	}
+	result.TokenEstimate = n
+	return result
+}
@@ -0,0 +1,42 @@
+package explorer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTokenCounter struct {
+	err   error
+	model string
+}
+
+func (c *fakeTokenCounter) Count(_ context.Context, model string, text string) (int, error) {
+	c.model = model
+	if c.err != nil {
+		return 0, c.err
+	}
+	return len(text), nil
+}
+
+func TestWithTokenCounter(t *testing.T) {
+	t.Parallel()
+
+	input := ExploreInput{Path: "data.json", Content: []byte(`{"name": "crush", "tags": ["a", "b"]}`)}
+	heuristic, err := NewRegistry().Explore(context.Background(), input)
+	require.NoError(t, err)
+
+	counter := &fakeTokenCounter{}
+	exact, err := NewRegistry(WithTokenCounter(counter, "gpt-4o")).Explore(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, heuristic.Summary, exact.Summary)
+	require.Equal(t, len(exact.Summary), exact.TokenEstimate)
+	require.Equal(t, "gpt-4o", counter.model)
+
+	failing := &fakeTokenCounter{err: errors.New("unknown model")}
+	fallback, err := NewRegistry(WithTokenCounter(failing, "x")).Explore(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, heuristic.TokenEstimate, fallback.TokenEstimate)
+}
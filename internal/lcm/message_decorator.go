@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/charmbracelet/crush/internal/db"
 	"github.com/charmbracelet/crush/internal/lcm/explorer"
 	"github.com/charmbracelet/crush/internal/message"
+	"go.opentelemetry.io/otel"
 )
 
 // Compile-time check that messageDecorator implements message.Service.
@@ -36,6 +39,7 @@ type messageDecorator struct {
 	sqlDB           *sql.DB
 	cfg             MessageDecoratorConfig
 	runtimeAdapter  *explorer.RuntimeAdapter
+	metrics         *explorer.Metrics
 	initSessions    sync.Map // sessionID -> struct{} (tracks lazily initialized sessions)
 }
 
@@ -45,6 +49,55 @@ type MessageDecoratorConfig struct {
 	LargeToolOutputTokenThreshold int
 	Parser                        any
 	ExplorerOutputProfile         explorer.OutputProfile
+	// ExplorerPathProfiles overrides ExplorerOutputProfile per runtime
+	// ingestion path ID. Overrides that fail validation against the runtime
+	// inventory select the parity profile.
+	ExplorerPathProfiles map[string]explorer.OutputProfile
+	// RemoteFetch, when non-nil, lets exploration fetch remote URIs.
+	RemoteFetch *explorer.RemoteOptions
+	// ExplorerMetrics counts explorations; nil uses explorer.DefaultMetrics,
+	// which is also exported through the global OTel meter provider.
+	ExplorerMetrics *explorer.Metrics
+	// ExploreCache, when non-nil, reuses the static exploration of
+	// identical tool outputs.
+	ExploreCache explorer.ExploreCache
+	// SQLiteSampling, when non-nil, bounds the row counts and sample rows
+	// of explored SQLite databases.
+	SQLiteSampling *explorer.SQLiteSampling
+	// ArchiveNesting, when non-nil, bounds the archives opened inside
+	// explored archives.
+	ArchiveNesting *explorer.ArchiveNesting
+	// OutputLimits, when non-nil, cap the summaries formatted under each
+	// output profile.
+	OutputLimits map[explorer.OutputProfile]*explorer.OutputLimits
+	// RejectUnsafeArchives drops the exploration of archives with entries
+	// that would be unsafe to extract instead of persisting their summary.
+	RejectUnsafeArchives bool
+}
+
+// Limits on a single explorer while exploring large tool output. An
+// explorer that times out is skipped for the next one in the chain, and
+// one that keeps timing out is skipped outright for the cooldown.
+const (
+	explorerTimeout         = 30 * time.Second
+	explorerBreakerFailures = 3
+	explorerBreakerCooldown = 5 * time.Minute
+)
+
+// registerExplorerOTel exports explorer.DefaultMetrics once per process.
+var registerExplorerOTel = sync.OnceFunc(func() {
+	meter := otel.Meter("github.com/charmbracelet/crush/internal/lcm/explorer")
+	if _, err := explorer.DefaultMetrics.RegisterOTel(meter); err != nil {
+		slog.Warn("Failed to register explorer metrics with OTel", "error", err)
+	}
+})
+
+func (c MessageDecoratorConfig) explorerMetrics() *explorer.Metrics {
+	if c.ExplorerMetrics != nil {
+		return c.ExplorerMetrics
+	}
+	registerExplorerOTel()
+	return explorer.DefaultMetrics
 }
 
 func (c MessageDecoratorConfig) threshold() int64 {
@@ -56,10 +109,23 @@ func (c MessageDecoratorConfig) threshold() int64 {
 
 // NewMessageDecorator wraps svc with LCM-aware behaviour.
 func NewMessageDecorator(svc message.Service, mgr Manager, queries *db.Queries, sqlDB *sql.DB, cfg MessageDecoratorConfig) message.Service {
-	runtimeAdapter := explorer.NewRuntimeAdapter(
+	metrics := cfg.explorerMetrics()
+	opts := []explorer.RuntimeAdapterOption{
 		explorer.WithRuntimeTreeSitter(cfg.Parser),
 		explorer.WithRuntimeOutputProfile(decoratorOutputProfile(cfg)),
-	)
+		explorer.WithRuntimeRemoteFetch(cfg.RemoteFetch),
+		explorer.WithRuntimeMetrics(metrics),
+		explorer.WithRuntimeExplorerLimits(explorer.ExplorerLimits{Timeout: explorerTimeout}, nil),
+		explorer.WithRuntimeCircuitBreaker(explorerBreakerFailures, explorerBreakerCooldown),
+		explorer.WithRuntimeExploreCache(cfg.ExploreCache),
+		explorer.WithRuntimeSQLiteSampling(cfg.SQLiteSampling),
+		explorer.WithRuntimeArchiveNesting(cfg.ArchiveNesting),
+		explorer.WithRuntimeRejectUnsafeArchives(cfg.RejectUnsafeArchives),
+	}
+	for profile, limits := range cfg.OutputLimits {
+		opts = append(opts, explorer.WithRuntimeOutputLimits(profile, limits))
+	}
+	runtimeAdapter := explorer.NewRuntimeAdapter(opts...)
 
 	return &messageDecorator{
 		Service:        svc,
@@ -69,10 +135,24 @@ func NewMessageDecorator(svc message.Service, mgr Manager, queries *db.Queries,
 		sqlDB:          sqlDB,
 		cfg:            cfg,
 		runtimeAdapter: runtimeAdapter,
+		metrics:        metrics,
 	}
 }
 
 func decoratorOutputProfile(cfg MessageDecoratorConfig) explorer.OutputProfile {
+	if len(cfg.ExplorerPathProfiles) > 0 {
+		inventory, err := explorer.LoadRuntimeInventory()
+		if err == nil {
+			err = explorer.ValidateRuntimePathProfiles(inventory, cfg.ExplorerPathProfiles)
+		}
+		if err != nil {
+			slog.Warn("Invalid explorer path profiles, using parity profile", "error", err)
+			return explorer.OutputProfileParity
+		}
+		if profile, ok := cfg.ExplorerPathProfiles[explorer.RuntimePathToolOutputCreate]; ok {
+			return profile
+		}
+	}
 	if cfg.ExplorerOutputProfile == "" {
 		return explorer.OutputProfileEnhancement
 	}
@@ -537,6 +617,15 @@ func (s *messageDecorator) persistLargeOutputExploration(ctx context.Context, se
 		explorationPath,
 		[]byte(content),
 	)
+	s.flushExplorerMetrics(ctx)
+	if errors.Is(err, explorer.ErrUnsafeArchive) {
+		slog.Warn("LCM rejected unsafe archive in large tool output",
+			"session_id", sessionID,
+			"file_id", fileID,
+			"error", err,
+		)
+		return
+	}
 	if err != nil {
 		slog.Warn("LCM exploration failed for large tool output",
 			"session_id", sessionID,
@@ -566,3 +655,26 @@ func (s *messageDecorator) persistLargeOutputExploration(ctx context.Context, se
 		)
 	}
 }
+
+// flushExplorerMetrics adds the explorations counted since the last flush
+// to the explorer_metrics table read by the stats command.
+func (s *messageDecorator) flushExplorerMetrics(ctx context.Context) {
+	if s.metrics == nil {
+		return
+	}
+	now := time.Now().Unix()
+	for _, m := range s.metrics.TakePending() {
+		err := s.querier.AddExplorerMetrics(ctx, db.AddExplorerMetricsParams{
+			Explorer:      m.Explorer,
+			Explorations:  m.Explorations,
+			Fallbacks:     m.Fallbacks,
+			ParseFailures: m.ParseFailures,
+			DurationUs:    m.Duration.Microseconds(),
+			Bytes:         m.Bytes,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			slog.Warn("Failed to persist explorer metrics", "explorer", m.Explorer, "error", err)
+		}
+	}
+}
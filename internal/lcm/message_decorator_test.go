@@ -619,6 +619,32 @@ func main() {}
 	require.NotZero(t, files[0].TokenCount)
 }
 
+func TestDecoratorOutputProfile_PathOverrides(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, explorer.OutputProfileEnhancement, decoratorOutputProfile(MessageDecoratorConfig{}))
+	require.Equal(t, explorer.OutputProfileParity, decoratorOutputProfile(MessageDecoratorConfig{
+		ExplorerOutputProfile: explorer.OutputProfileEnhancement,
+		ExplorerPathProfiles: map[string]explorer.OutputProfile{
+			explorer.RuntimePathToolOutputCreate: explorer.OutputProfileParity,
+			"volt.prompt.file.persist":           explorer.OutputProfileEnhancement,
+		},
+	}))
+	// Overrides for other paths leave the tool output path on the default.
+	require.Equal(t, explorer.OutputProfileEnhancement, decoratorOutputProfile(MessageDecoratorConfig{
+		ExplorerPathProfiles: map[string]explorer.OutputProfile{
+			"volt.prompt.file.persist": explorer.OutputProfileParity,
+		},
+	}))
+	// Invalid overrides fail closed to parity.
+	require.Equal(t, explorer.OutputProfileParity, decoratorOutputProfile(MessageDecoratorConfig{
+		ExplorerOutputProfile: explorer.OutputProfileEnhancement,
+		ExplorerPathProfiles: map[string]explorer.OutputProfile{
+			"lcm.tool_output.typo": explorer.OutputProfileEnhancement,
+		},
+	}))
+}
+
 func TestMessageDecorator_List_BootstrappedLegacySessionPreservesBoundary(t *testing.T) {
 	t.Parallel()
 
@@ -666,3 +692,34 @@ func TestMessageDecorator_List_BootstrappedLegacySessionPreservesBoundary(t *tes
 		msgs[3].ID,
 	})
 }
+
+func TestMessageDecorator_Create_FlushesExplorerMetrics(t *testing.T) {
+	t.Parallel()
+
+	queries, sqlDB := setupTestDB(t)
+	ctx := context.Background()
+	sessionID := "sess-msgdecorator-metrics"
+	createTestSession(t, queries, sessionID)
+
+	metrics := explorer.NewMetrics()
+	svc := NewMessageDecorator(message.NewService(queries), NewManager(queries, sqlDB), queries, sqlDB, MessageDecoratorConfig{
+		LargeToolOutputTokenThreshold: 5,
+		ExplorerMetrics:               metrics,
+	})
+
+	for i := range 2 {
+		_, err := svc.Create(ctx, sessionID, message.CreateMessageParams{
+			Role:  message.Tool,
+			Parts: []message.ContentPart{message.ToolResult{ToolCallID: fmt.Sprintf("tc-metrics-%d", i), Name: "test", Content: strings.Repeat("x", 80)}},
+		})
+		require.NoError(t, err)
+	}
+
+	rows, err := queries.ListExplorerMetrics(ctx)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int64(2), rows[0].Explorations)
+	require.Equal(t, int64(160), rows[0].Bytes)
+	require.Empty(t, metrics.TakePending())
+	require.Equal(t, rows[0].Explorer, metrics.Snapshot()[0].Explorer)
+}
@@ -0,0 +1,232 @@
+// Package licenses scans a repository for license compliance: the project
+// license, the license headers of source files, and the licenses of the
+// dependencies its lockfiles resolve, read from the lockfile when it
+// records them and from the installed packages otherwise.
+package licenses
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Categories of licenses, from the least to the most restrictive.
+const (
+	Permissive      = "permissive"
+	WeakCopyleft    = "weak copyleft"
+	StrongCopyleft  = "strong copyleft"
+	NetworkCopyleft = "network copyleft"
+	SourceAvailable = "source-available"
+	Proprietary     = "proprietary"
+	Unknown         = "unknown"
+)
+
+// categoryRank orders the categories by restrictiveness.
+var categoryRank = map[string]int{
+	Permissive:      0,
+	WeakCopyleft:    1,
+	StrongCopyleft:  2,
+	NetworkCopyleft: 3,
+	SourceAvailable: 4,
+	Proprietary:     5,
+	Unknown:         6,
+}
+
+// spdxRe matches an SPDX-License-Identifier tag, capturing the expression.
+var spdxRe = regexp.MustCompile(`(?m)SPDX-License-Identifier:\s*([A-Za-z0-9.+()\- ]+?)\s*(?:\*/|-->|#}|$)`)
+
+// copyrightRe matches a copyright notice, capturing what follows it.
+var copyrightRe = regexp.MustCompile(`(?i)\bcopyright\b(?:\s*\(c\)|\s*©)?\s*(.+)`)
+
+// signature recognizes a license by phrases of its text or of the headers
+// it asks for, in normalized form: lower case words separated by single
+// spaces.
+type signature struct {
+	id  string
+	all []string
+}
+
+// signatures are tried in order; the first whose phrases all appear wins,
+// so more specific licenses come before the ones whose phrases they share.
+var signatures = []signature{
+	{"AGPL-3.0", []string{"gnu affero general public license"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license"}},
+	{"LGPL-2.0", []string{"gnu library general public license"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"GPL", []string{"gnu general public license"}},
+	{"SSPL-1.0", []string{"server side public license"}},
+	{"BUSL-1.1", []string{"business source license"}},
+	{"Elastic-2.0", []string{"elastic license 2.0"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"EPL-2.0", []string{"eclipse public license", "2.0"}},
+	{"EPL-1.0", []string{"eclipse public license"}},
+	{"CDDL-1.0", []string{"common development and distribution license"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"Apache-2.0", []string{"apache software license"}},
+	{"Unlicense", []string{"free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+	{"ISC", []string{"permission to use copy modify and or distribute this software for any purpose"}},
+	{"MIT", []string{"permission is hereby granted free of charge"}},
+	{"MIT", []string{"mit license"}},
+	{"MIT-style", []string{"mit style license"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"BSD-style", []string{"bsd style license"}},
+	{"BSD-style", []string{"bsd license"}},
+	{"Proprietary", []string{"proprietary and confidential"}},
+	{"Proprietary", []string{"all rights reserved", "confidential"}},
+}
+
+// Detect returns the license of a license text or source file header: the
+// SPDX-License-Identifier when one is tagged, otherwise the license whose
+// phrases the text contains. It returns "" when no license is recognized.
+func Detect(text string) string {
+	if m := spdxRe.FindStringSubmatch(text); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	norm := normalize(text)
+	for _, sig := range signatures {
+		if containsAll(norm, sig.all) {
+			return sig.id
+		}
+	}
+	return ""
+}
+
+// Copyright returns the first copyright notice of text, without the
+// "Copyright" word, or "".
+func Copyright(text string) string {
+	for line := range strings.Lines(text) {
+		if m := copyrightRe.FindStringSubmatch(line); m != nil {
+			notice := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(m[1]), "*/->#"))
+			if notice == "" || strings.HasPrefix(strings.ToLower(notice), "notice") {
+				continue
+			}
+			if len(notice) > 100 {
+				notice = notice[:100] + "..."
+			}
+			return notice
+		}
+	}
+	return ""
+}
+
+// Category classifies a license or SPDX expression. Alternatives joined by
+// OR take the least restrictive category, since the licensee may choose;
+// licenses joined by AND take the most restrictive.
+func Category(expr string) string {
+	expr = strings.TrimSpace(strings.Trim(strings.TrimSpace(expr), "()"))
+	if expr == "" {
+		return Unknown
+	}
+	if alts := splitExpr(expr, " OR "); len(alts) > 1 {
+		best := Unknown
+		for _, alt := range alts {
+			if c := Category(alt); categoryRank[c] < categoryRank[best] {
+				best = c
+			}
+		}
+		return best
+	}
+	if parts := splitExpr(expr, " AND "); len(parts) > 1 {
+		worst := Permissive
+		for _, part := range parts {
+			if c := Category(part); categoryRank[c] > categoryRank[worst] {
+				worst = c
+			}
+		}
+		return worst
+	}
+	id, _, _ := strings.Cut(expr, " WITH ")
+	id = strings.TrimSuffix(strings.TrimSpace(id), "+")
+	upper := strings.ToUpper(id)
+	switch {
+	case strings.HasPrefix(upper, "AGPL"), strings.HasPrefix(upper, "SSPL"):
+		return NetworkCopyleft
+	case strings.HasPrefix(upper, "LGPL"), strings.HasPrefix(upper, "MPL"),
+		strings.HasPrefix(upper, "EPL"), strings.HasPrefix(upper, "CDDL"),
+		strings.HasPrefix(upper, "EUPL"), strings.HasPrefix(upper, "OSL"):
+		return WeakCopyleft
+	case strings.HasPrefix(upper, "GPL"):
+		return StrongCopyleft
+	case strings.HasPrefix(upper, "BUSL"), strings.HasPrefix(upper, "ELASTIC"),
+		strings.HasPrefix(upper, "POLYFORM"), strings.HasPrefix(upper, "COMMONS-CLAUSE"):
+		return SourceAvailable
+	case upper == "PROPRIETARY", upper == "UNLICENSED", strings.HasPrefix(upper, "SEE LICENSE"):
+		// npm uses UNLICENSED for packages not licensed for use at all.
+		return Proprietary
+	case strings.HasPrefix(upper, "MIT"), strings.HasPrefix(upper, "BSD"),
+		strings.HasPrefix(upper, "APACHE"), strings.HasPrefix(upper, "ISC"),
+		strings.HasPrefix(upper, "0BSD"), upper == "UNLICENSE", strings.HasPrefix(upper, "CC0"),
+		strings.HasPrefix(upper, "ZLIB"), strings.HasPrefix(upper, "PYTHON"),
+		strings.HasPrefix(upper, "PSF"), strings.HasPrefix(upper, "BLUEOAK"),
+		strings.HasPrefix(upper, "BSL-1.0"), strings.HasPrefix(upper, "WTFPL"),
+		strings.HasPrefix(upper, "CC-BY-4"), strings.HasPrefix(upper, "CC-BY-3"):
+		return Permissive
+	}
+	return Unknown
+}
+
+// NeedsReview reports whether a license calls for a closer look before
+// shipping: copyleft, source-available, proprietary or unknown.
+func NeedsReview(expr string) bool {
+	c := Category(expr)
+	return c != Permissive && c != WeakCopyleft
+}
+
+// splitExpr splits an SPDX expression on op outside parentheses.
+func splitExpr(expr, op string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 && strings.HasPrefix(strings.ToUpper(expr[i:]), op) {
+			parts = append(parts, expr[start:i])
+			start = i + len(op)
+			i += len(op) - 1
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+// normalize lower-cases text and reduces it to words separated by single
+// spaces, dropping comment markers and punctuation other than dots inside
+// version numbers.
+func normalize(text string) string {
+	var sb strings.Builder
+	space := true
+	for i, r := range text {
+		keep := unicode.IsLetter(r) || unicode.IsDigit(r) ||
+			r == '.' && i > 0 && i+1 < len(text) && isDigit(text[i-1]) && isDigit(text[i+1])
+		if !keep {
+			if !space {
+				sb.WriteByte(' ')
+				space = true
+			}
+			continue
+		}
+		sb.WriteRune(unicode.ToLower(r))
+		space = false
+	}
+	return sb.String()
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func containsAll(s string, phrases []string) bool {
+	for _, p := range phrases {
+		if !strings.Contains(s, p) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+const mitText = `MIT License
+
+Copyright (c) 2024 Example Corp
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.
+`
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	for text, want := range map[string]string{
+		"// SPDX-License-Identifier: Apache-2.0 OR MIT\npackage x": "Apache-2.0 OR MIT",
+		"/* SPDX-License-Identifier: GPL-2.0-only */":              "GPL-2.0-only",
+		mitText: "MIT",
+		"# Licensed under the Apache License, Version 2.0 (the \"License\");":                 "Apache-2.0",
+		"// Use of this source code is governed by a BSD-style\n// license that can be found": "BSD-style",
+		"GNU GENERAL PUBLIC LICENSE\n   Version 3, 29 June 2007":                              "GPL-3.0",
+		"GNU LESSER GENERAL PUBLIC LICENSE\n   Version 3, 29 June 2007":                       "LGPL-3.0",
+		"GNU AFFERO GENERAL PUBLIC LICENSE":                                                   "AGPL-3.0",
+		"Mozilla Public License Version 2.0":                                                  "MPL-2.0",
+		"Permission to use, copy, modify, and/or distribute this software for any purpose":    "ISC",
+		"Redistribution and use in source and binary forms ... Neither the name of":           "BSD-3-Clause",
+		"package main\n\nfunc main() {}\n":                                                    "",
+	} {
+		require.Equal(t, want, Detect(text), text)
+	}
+	require.Equal(t, "2024 Example Corp", Copyright(mitText))
+}
+
+func TestCategory(t *testing.T) {
+	t.Parallel()
+
+	for expr, want := range map[string]string{
+		"MIT":                                  Permissive,
+		"(MIT OR Apache-2.0)":                  Permissive,
+		"GPL-3.0-or-later":                     StrongCopyleft,
+		"GPL-2.0 OR MIT":                       Permissive,
+		"MIT AND LGPL-2.1":                     WeakCopyleft,
+		"AGPL-3.0-only":                        NetworkCopyleft,
+		"BUSL-1.1":                             SourceAvailable,
+		"UNLICENSED":                           Proprietary,
+		"GPL-2.0 WITH Classpath-exception-2.0": StrongCopyleft,
+		"":                                     Unknown,
+		"LicenseRef-Custom":                    Unknown,
+	} {
+		require.Equal(t, want, Category(expr), expr)
+	}
+	require.True(t, NeedsReview(""))
+	require.False(t, NeedsReview("MPL-2.0"))
+}
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, root, "LICENSE", mitText)
+	writeFile(t, root, "main.go", "// SPDX-License-Identifier: MIT\n// Copyright 2024 Example Corp\npackage main\n")
+	writeFile(t, root, "vendored/lib.c", "/*\n * Licensed under the GNU General Public License, version 2\n */\n")
+	writeFile(t, root, "util.go", "package main\n")
+	writeFile(t, root, "web/package-lock.json", `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"left": "^1.0.0", "right": "^2.0.0"}},
+    "node_modules/left": {"version": "1.0.0", "resolved": "https://registry.npmjs.org/left/-/left-1.0.0.tgz", "license": "MIT"},
+    "node_modules/right": {"version": "2.0.0", "resolved": "https://registry.npmjs.org/right/-/right-2.0.0.tgz"},
+    "node_modules/lost": {"version": "0.1.0", "resolved": "https://registry.npmjs.org/lost/-/lost-0.1.0.tgz"}
+  }
+}`)
+	writeFile(t, root, "web/node_modules/right/package.json", `{"name": "right", "license": "AGPL-3.0-only"}`)
+
+	r, err := Scan(t.Context(), root)
+	require.NoError(t, err)
+	require.Equal(t, "MIT", r.Project)
+	require.Equal(t, "LICENSE", r.ProjectFile)
+	require.Equal(t, []FileLicense{
+		{Path: "main.go", License: "MIT", Copyright: "2024 Example Corp"},
+		{Path: "util.go"},
+		{Path: "vendored/lib.c", License: "GPL-2.0"},
+	}, r.Files)
+	require.Equal(t, []Dependency{
+		{Ecosystem: "npm", Lockfile: "web/package-lock.json", Name: "left", Version: "1.0.0", License: "MIT", Origin: OriginLockfile, TopLevel: true},
+		{Ecosystem: "npm", Lockfile: "web/package-lock.json", Name: "lost", Version: "0.1.0"},
+		{Ecosystem: "npm", Lockfile: "web/package-lock.json", Name: "right", Version: "2.0.0", License: "AGPL-3.0-only", Origin: OriginMetadata, TopLevel: true},
+	}, r.Dependencies)
+
+	s := r.Summary(10)
+	require.Contains(t, s, "Project license: MIT (LICENSE, permissive)\n")
+	require.Contains(t, s, "\nSource files: 3, 2 with a license header\n")
+	require.Contains(t, s, "\nFiles licensed differently from the project: 1\n  - vendored/lib.c: GPL-2.0\n")
+	require.Contains(t, s, "\nFiles without a license header: 1\n  - util.go\n")
+	require.Contains(t, s, "\nDependencies: 3 in 1 lockfiles\n")
+	require.Contains(t, s, "  - npm lost 0.1.0: license not found (unknown; web/package-lock.json)\n")
+	require.Contains(t, s, "  - npm right 2.0.0: AGPL-3.0-only (network copyleft, from the package metadata, direct; web/package-lock.json)\n")
+	require.NotContains(t, s, "npm left 1.0.0")
+
+	require.Equal(t, "- npm left 1.0.0: MIT (permissive, from the lockfile, direct; web/package-lock.json)\n", r.Lookup("LEFT"))
+	require.Contains(t, r.Lookup("ri"), "with names containing it")
+	require.Contains(t, r.Lookup("nothing"), "No dependency named")
+	require.Equal(t, "1 source files matching \"vendored\":\n- vendored/lib.c: GPL-2.0\n", r.FileHeaders("vendored", 10))
+}
+
+func TestResolveGoModuleCache(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	writeFile(t, cache, "github.com/!burnt!sushi/toml@v1.3.2/COPYING", mitText)
+
+	root := t.TempDir()
+	writeFile(t, root, "go.sum", "github.com/BurntSushi/toml v1.3.2 h1:o7IhLm0Msx3BaB+n3Ag7L8EVlByGKpq==\n"+
+		"github.com/BurntSushi/toml v1.3.2/go.mod h1:CxXYINrC8qIiEnFrOxCa7Jy5BFHlXnUU2pbicEuybxQ=\n")
+
+	r, err := Scan(t.Context(), root)
+	require.NoError(t, err)
+	require.Len(t, r.Dependencies, 1)
+	require.Equal(t, "MIT", r.Dependencies[0].License)
+	require.Equal(t, OriginFile, r.Dependencies[0].Origin)
+}
@@ -0,0 +1,184 @@
+package licenses
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// noHeader labels files without a license header in the counts.
+const noHeader = "no header"
+
+// Summary renders the compliance summary of the report, listing at most
+// limit entries per section: the project license, the file headers by
+// license, files without one or with one differing from the project's,
+// and the dependencies by license and category, with the ones that need
+// review.
+func (r *Report) Summary(limit int) string {
+	var sb strings.Builder
+	switch {
+	case r.ProjectFile == "":
+		sb.WriteString("Project license: none found at the repository root\n")
+	case r.Project == "":
+		fmt.Fprintf(&sb, "Project license: unrecognized (%s)\n", r.ProjectFile)
+	default:
+		fmt.Fprintf(&sb, "Project license: %s (%s, %s)\n", r.Project, r.ProjectFile, Category(r.Project))
+	}
+
+	headers := make(map[string]int)
+	var missing, differing []string
+	for _, f := range r.Files {
+		if f.License == "" {
+			headers[noHeader]++
+			missing = append(missing, f.Path)
+			continue
+		}
+		headers[f.License]++
+		if r.Project != "" && !sameLicense(f.License, r.Project) {
+			differing = append(differing, fmt.Sprintf("%s: %s", f.Path, f.License))
+		}
+	}
+	fmt.Fprintf(&sb, "\nSource files: %d, %d with a license header\n", len(r.Files), len(r.Files)-len(missing))
+	writeCounts(&sb, headers, "files")
+	writeList(&sb, "Files licensed differently from the project", differing, limit)
+	if len(missing) < len(r.Files) {
+		// When no file has a header, listing them all tells nothing.
+		writeList(&sb, "Files without a license header", missing, limit)
+	}
+
+	if len(r.Dependencies) == 0 {
+		sb.WriteString("\nDependencies: no supported lockfiles found\n")
+		return sb.String()
+	}
+	lockfiles := make(map[string]bool)
+	byLicense := make(map[string]int)
+	byCategory := make(map[string]int)
+	var review []string
+	for _, d := range r.Dependencies {
+		lockfiles[d.Lockfile] = true
+		byLicense[cmp.Or(d.License, Unknown)]++
+		byCategory[Category(d.License)]++
+		if NeedsReview(d.License) {
+			review = append(review, d.line())
+		}
+	}
+	fmt.Fprintf(&sb, "\nDependencies: %d in %d lockfiles\n", len(r.Dependencies), len(lockfiles))
+	writeCounts(&sb, byCategory, "packages")
+	sb.WriteString("\nDependency licenses:\n")
+	writeCounts(&sb, byLicense, "packages")
+	writeList(&sb, "Needs review (copyleft beyond file level, source-available, proprietary or unknown)", review, limit)
+	return sb.String()
+}
+
+// Lookup renders the dependencies named name, matched case-insensitively,
+// or, when none is, the ones whose name contains it.
+func (r *Report) Lookup(name string) string {
+	var exact, partial []Dependency
+	for _, d := range r.Dependencies {
+		switch {
+		case strings.EqualFold(d.Name, name):
+			exact = append(exact, d)
+		case strings.Contains(strings.ToLower(d.Name), strings.ToLower(name)):
+			partial = append(partial, d)
+		}
+	}
+	deps := exact
+	if len(deps) == 0 {
+		deps = partial
+	}
+	if len(deps) == 0 {
+		return fmt.Sprintf("No dependency named %q in the lockfiles of the repository.\n", name)
+	}
+	var sb strings.Builder
+	if len(exact) == 0 {
+		fmt.Fprintf(&sb, "No dependency named %q; %d with names containing it:\n", name, len(deps))
+	}
+	for _, d := range deps {
+		fmt.Fprintf(&sb, "- %s\n", d.line())
+	}
+	return sb.String()
+}
+
+// FileHeaders renders the license headers of the source files whose path
+// contains pathPart, listing at most limit.
+func (r *Report) FileHeaders(pathPart string, limit int) string {
+	var lines []string
+	for _, f := range r.Files {
+		if !strings.Contains(f.Path, pathPart) {
+			continue
+		}
+		line := f.Path + ": " + cmp.Or(f.License, noHeader)
+		if f.Copyright != "" {
+			line += " (Copyright " + f.Copyright + ")"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("No source files matching %q.\n", pathPart)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d source files matching %q:\n", len(lines), pathPart)
+	for i, line := range lines {
+		if i == limit {
+			fmt.Fprintf(&sb, "- ... and %d more\n", len(lines)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "- %s\n", line)
+	}
+	return sb.String()
+}
+
+// line describes the dependency on one line.
+func (d Dependency) line() string {
+	license := cmp.Or(d.License, "license not found")
+	var notes []string
+	notes = append(notes, Category(d.License))
+	if d.Origin != "" {
+		notes = append(notes, "from the "+d.Origin)
+	}
+	if d.Dev {
+		notes = append(notes, "dev")
+	}
+	if d.TopLevel {
+		notes = append(notes, "direct")
+	}
+	return fmt.Sprintf("%s %s %s: %s (%s; %s)", d.Ecosystem, d.Name, d.Version, license, strings.Join(notes, ", "), d.Lockfile)
+}
+
+// sameLicense reports whether a file header and the project license name
+// the same license, counting "MIT-style" headers as MIT and so on.
+func sameLicense(header, project string) bool {
+	if strings.EqualFold(header, project) {
+		return true
+	}
+	family, ok := strings.CutSuffix(header, "-style")
+	return ok && strings.HasPrefix(strings.ToUpper(project), strings.ToUpper(family))
+}
+
+func writeCounts(sb *strings.Builder, counts map[string]int, unit string) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+	for _, k := range keys {
+		fmt.Fprintf(sb, "  - %s: %d %s\n", k, counts[k], unit)
+	}
+}
+
+func writeList(sb *strings.Builder, title string, lines []string, limit int) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n%s: %d\n", title, len(lines))
+	for i, line := range lines {
+		if i == limit {
+			fmt.Fprintf(sb, "  - ... and %d more\n", len(lines)-i)
+			break
+		}
+		fmt.Fprintf(sb, "  - %s\n", line)
+	}
+}
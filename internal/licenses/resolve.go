@@ -0,0 +1,248 @@
+package licenses
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// resolver finds the licenses of installed packages that their lockfile
+// does not record: in node_modules, the Go vendor directory and module
+// cache, the Cargo registry and Python virtual environments.
+type resolver struct {
+	goModCache  string
+	cargoSrc    []string
+	sitePackage map[string][]string
+}
+
+func newResolver() *resolver {
+	r := &resolver{sitePackage: make(map[string][]string)}
+	home, _ := os.UserHomeDir()
+	r.goModCache = os.Getenv("GOMODCACHE")
+	if r.goModCache == "" {
+		gopath, _, _ := strings.Cut(os.Getenv("GOPATH"), string(os.PathListSeparator))
+		if gopath == "" && home != "" {
+			gopath = filepath.Join(home, "go")
+		}
+		if gopath != "" {
+			r.goModCache = filepath.Join(gopath, "pkg", "mod")
+		}
+	}
+	cargoHome := os.Getenv("CARGO_HOME")
+	if cargoHome == "" && home != "" {
+		cargoHome = filepath.Join(home, ".cargo")
+	}
+	if cargoHome != "" {
+		r.cargoSrc, _ = filepath.Glob(filepath.Join(cargoHome, "registry", "src", "*"))
+	}
+	return r
+}
+
+// resolve returns the license of a package installed for the lockfile in
+// dir and where it was read, or empty strings.
+func (r *resolver) resolve(ecosystem, dir, name, version string) (string, string) {
+	switch ecosystem {
+	case "npm":
+		pkgDir := filepath.Join(dir, "node_modules", filepath.FromSlash(name))
+		if l := npmPackageLicense(filepath.Join(pkgDir, "package.json")); l != "" {
+			return l, OriginMetadata
+		}
+		return licenseFileIn(pkgDir)
+	case "go":
+		if l, origin := licenseFileIn(filepath.Join(dir, "vendor", filepath.FromSlash(name))); l != "" {
+			return l, origin
+		}
+		if r.goModCache != "" {
+			return licenseFileIn(filepath.Join(r.goModCache, escapeModulePath(name)+"@"+escapeModulePath(version)))
+		}
+	case "cargo":
+		for _, src := range r.cargoSrc {
+			crateDir := filepath.Join(src, name+"-"+version)
+			if l := cargoLicense(filepath.Join(crateDir, "Cargo.toml")); l != "" {
+				return l, OriginMetadata
+			}
+			if l, origin := licenseFileIn(crateDir); l != "" {
+				return l, origin
+			}
+		}
+	case "poetry":
+		for _, site := range r.sitePackages(dir) {
+			distInfo := filepath.Join(site, pythonDistName(name)+"-"+version+".dist-info")
+			if l := pythonLicense(filepath.Join(distInfo, "METADATA")); l != "" {
+				return l, OriginMetadata
+			}
+			if l, origin := licenseFileIn(filepath.Join(distInfo, "licenses")); l != "" {
+				return l, origin
+			}
+			if l, origin := licenseFileIn(distInfo); l != "" {
+				return l, origin
+			}
+		}
+	}
+	return "", ""
+}
+
+// sitePackages returns the site-packages directories of the virtual
+// environments next to a lockfile in dir.
+func (r *resolver) sitePackages(dir string) []string {
+	if sites, ok := r.sitePackage[dir]; ok {
+		return sites
+	}
+	var sites []string
+	for _, venv := range []string{".venv", "venv"} {
+		found, _ := filepath.Glob(filepath.Join(dir, venv, "lib", "python*", "site-packages"))
+		sites = append(sites, found...)
+		sites = append(sites, filepath.Join(dir, venv, "Lib", "site-packages"))
+	}
+	r.sitePackage[dir] = sites
+	return sites
+}
+
+// licenseFileIn identifies the license file in dir.
+func licenseFileIn(dir string) (string, string) {
+	name := findLicenseFile(dir)
+	if name == "" {
+		return "", ""
+	}
+	if l := Detect(readHead(filepath.Join(dir, name), licenseTextBytes)); l != "" {
+		return l, OriginFile
+	}
+	return "", ""
+}
+
+// npmPackageLicense reads the license of a package.json: the license
+// field, or the licenses list of old packages.
+func npmPackageLicense(p string) string {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		License  json.RawMessage `json:"license"`
+		Licenses []struct {
+			Type string `json:"type"`
+		} `json:"licenses"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(pkg.License, &s) == nil && s != "" {
+		return s
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(pkg.License, &obj) == nil && obj.Type != "" {
+		return obj.Type
+	}
+	var types []string
+	for _, l := range pkg.Licenses {
+		if l.Type != "" {
+			types = append(types, l.Type)
+		}
+	}
+	return strings.Join(types, " OR ")
+}
+
+// cargoLicense reads the license key of a Cargo.toml's package table.
+func cargoLicense(p string) string {
+	f, err := os.Open(p)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	inPackage := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[package]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if inPackage && ok && strings.TrimSpace(key) == "license" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// pythonLicense reads the license of a dist-info METADATA file: the
+// License-Expression field, a short License field, or the license
+// classifiers.
+func pythonLicense(p string) string {
+	f, err := os.Open(p)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	var license string
+	var classifiers []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			// The headers end at the first blank line.
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "License-Expression":
+			return value
+		case "License":
+			if len(value) <= 40 && !strings.EqualFold(value, "UNKNOWN") {
+				license = value
+			}
+		case "Classifier":
+			if rest, ok := strings.CutPrefix(value, "License :: "); ok {
+				parts := strings.Split(rest, " :: ")
+				classifiers = append(classifiers, parts[len(parts)-1])
+			}
+		}
+	}
+	if len(classifiers) > 0 {
+		var ids []string
+		for _, c := range classifiers {
+			if id := Detect(c); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			return strings.Join(ids, " OR ")
+		}
+	}
+	if license != "" {
+		if id := Detect(license); id != "" {
+			return id
+		}
+	}
+	return license
+}
+
+// escapeModulePath escapes a module path or version as the Go module
+// cache does: upper-case letters become "!" and the lower-case letter.
+func escapeModulePath(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return filepath.FromSlash(sb.String())
+}
+
+// pythonDistName normalizes a project name as wheels name their
+// dist-info directories.
+func pythonDistName(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,218 @@
+package licenses
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+)
+
+// headerBytes is how much of a source file is read for its license header.
+const headerBytes = 4096
+
+// licenseTextBytes is how much of a license file is read to identify it.
+const licenseTextBytes = 16 << 10
+
+// maxLockfileSize bounds the size of a lockfile read.
+const maxLockfileSize = 16 << 20
+
+// licenseFileNames are the names, matched case-insensitively and without
+// extension, of the files holding a license text.
+var licenseFileNames = []string{"license", "licence", "copying", "unlicense", "license-mit", "license-apache"}
+
+// sourceExtensions are the extensions of the files checked for license
+// headers.
+var sourceExtensions = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".ts": true, ".tsx": true, ".vue": true, ".svelte": true,
+	".py": true, ".rb": true, ".php": true, ".pl": true, ".lua": true,
+	".java": true, ".kt": true, ".kts": true, ".scala": true, ".groovy": true,
+	".cs": true, ".fs": true, ".swift": true, ".rs": true, ".dart": true,
+	".ex": true, ".exs": true, ".erl": true, ".hs": true, ".ml": true, ".clj": true,
+	".c": true, ".cc": true, ".cpp": true, ".h": true, ".hpp": true, ".m": true, ".mm": true,
+	".sh": true, ".bash": true, ".zsh": true, ".ps1": true, ".proto": true, ".sql": true,
+}
+
+// FileLicense is the license header of a source file.
+type FileLicense struct {
+	Path string `json:"path"`
+	// License is the SPDX identifier or expression, or "" for a file
+	// without a recognized license header.
+	License   string `json:"license,omitempty"`
+	Copyright string `json:"copyright,omitempty"`
+}
+
+// Dependency is a package resolved by a lockfile of the repository.
+type Dependency struct {
+	Ecosystem string `json:"ecosystem"`
+	// Lockfile is the repository relative path of the lockfile.
+	Lockfile string `json:"lockfile"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	// License is the SPDX expression, or "" when it could not be found.
+	License string `json:"license,omitempty"`
+	// Origin is where the license was read: the lockfile, the installed
+	// package's metadata or its license file.
+	Origin   string `json:"origin,omitempty"`
+	Dev      bool   `json:"dev,omitempty"`
+	TopLevel bool   `json:"top_level,omitempty"`
+}
+
+// Origins of a dependency license.
+const (
+	OriginLockfile = "lockfile"
+	OriginMetadata = "package metadata"
+	OriginFile     = "license file"
+)
+
+// Report is the license compliance scan of a repository.
+type Report struct {
+	// Project is the license of the repository, from the license file at
+	// its root, and ProjectFile that file's name.
+	Project     string `json:"project,omitempty"`
+	ProjectFile string `json:"project_file,omitempty"`
+	// Files are the source files checked for headers, in path order.
+	Files []FileLicense `json:"files"`
+	// Dependencies are in lockfile, name and version order.
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// Scan checks the source files below root for license headers and
+// resolves the licenses of the packages its lockfiles lock, skipping the
+// directories the repository ignores.
+func Scan(ctx context.Context, root string) (*Report, error) {
+	files, err := walk(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{}
+	r.ProjectFile, r.Project = projectLicense(root)
+	resolver := newResolver()
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		abs := filepath.Join(root, filepath.FromSlash(rel))
+		switch {
+		case explorer.IsLockfile(rel):
+			r.Dependencies = append(r.Dependencies, lockfileDependencies(abs, rel, resolver)...)
+		case sourceExtensions[strings.ToLower(path.Ext(rel))]:
+			head := readHead(abs, headerBytes)
+			r.Files = append(r.Files, FileLicense{Path: rel, License: Detect(head), Copyright: Copyright(head)})
+		}
+	}
+	slices.SortFunc(r.Files, func(a, b FileLicense) int { return strings.Compare(a.Path, b.Path) })
+	slices.SortStableFunc(r.Dependencies, func(a, b Dependency) int {
+		if c := strings.Compare(a.Lockfile, b.Lockfile); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Version, b.Version)
+	})
+	return r, nil
+}
+
+// projectLicense identifies the license file at the root of the
+// repository.
+func projectLicense(root string) (string, string) {
+	name := findLicenseFile(root)
+	if name == "" {
+		return "", ""
+	}
+	return name, Detect(readHead(filepath.Join(root, name), licenseTextBytes))
+}
+
+// findLicenseFile returns the name of the license file in dir, or "".
+func findLicenseFile(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, want := range licenseFileNames {
+		for _, e := range entries {
+			base := strings.ToLower(e.Name())
+			base = strings.TrimSuffix(base, path.Ext(base))
+			if !e.IsDir() && base == want {
+				return e.Name()
+			}
+		}
+	}
+	return ""
+}
+
+// lockfileDependencies lists the packages of the lockfile at abs with
+// their licenses.
+func lockfileDependencies(abs, rel string, res *resolver) []Dependency {
+	content := readHead(abs, maxLockfileSize)
+	ecosystem, packages, err := explorer.ParseLockfile(rel, []byte(content))
+	if err != nil {
+		return nil
+	}
+	dir := filepath.Dir(abs)
+	deps := make([]Dependency, 0, len(packages))
+	for _, p := range packages {
+		d := Dependency{
+			Ecosystem: ecosystem,
+			Lockfile:  rel,
+			Name:      p.Name,
+			Version:   p.Version,
+			License:   p.License,
+			Dev:       p.Dev,
+			TopLevel:  p.TopLevel,
+		}
+		if d.License != "" {
+			d.Origin = OriginLockfile
+		} else {
+			d.License, d.Origin = res.resolve(ecosystem, dir, p.Name, p.Version)
+		}
+		deps = append(deps, d)
+	}
+	return deps
+}
+
+// readHead returns up to n bytes from the start of the file at p.
+func readHead(p string, n int64) string {
+	f, err := os.Open(p)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	data, _ := io.ReadAll(io.LimitReader(f, n))
+	return string(data)
+}
+
+func walk(ctx context.Context, root string) ([]string, error) {
+	walker := fsext.NewFastGlobWalker(root)
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if p != root && walker.ShouldSkipDir(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// The walker leaves lockfiles out as noise; here they are the point.
+		if walker.ShouldSkip(p) && !explorer.IsLockfile(p) {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, p); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files, err
+}
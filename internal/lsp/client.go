@@ -375,6 +375,7 @@ func (c *Client) OpenFile(ctx context.Context, filepath string) error {
 	if !c.HandlesFile(filepath) {
 		return nil
 	}
+	c.markUsed() // XRUSH: idle shutdown
 
 	uri := string(protocol.URIFromPath(filepath))
 
@@ -406,6 +407,7 @@ func (c *Client) NotifyChange(ctx context.Context, filepath string) error {
 	if c == nil {
 		return nil
 	}
+	c.markUsed() // XRUSH: idle shutdown
 	uri := string(protocol.URIFromPath(filepath))
 
 	content, err := os.ReadFile(filepath)
@@ -663,6 +665,7 @@ func (c *Client) waitForDiagnosticsToSettle(ctx context.Context, deadline <-chan
 
 // FindReferences finds all references to the symbol at the given position.
 func (c *Client) FindReferences(ctx context.Context, filepath string, line, character int, includeDeclaration bool) ([]protocol.Location, error) {
+	c.markUsed() // XRUSH: idle shutdown
 	if err := c.OpenFileOnDemand(ctx, filepath); err != nil {
 		return nil, err
 	}
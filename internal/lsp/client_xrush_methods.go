@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
@@ -21,6 +22,20 @@ type xrushClientFields struct {
 	cachedCaps    *protocol.ServerCapabilities
 	cachedCapsErr error
 	capsOnce      sync.Once
+
+	// lastUsed is the UnixNano time of the last request, read by
+	// Manager.StopIdle.
+	lastUsed atomic.Int64
+}
+
+// markUsed records that the client served a request now.
+func (c *Client) markUsed() {
+	c.lastUsed.Store(time.Now().UnixNano())
+}
+
+// LastUsed returns when the client last served a request.
+func (c *Client) LastUsed() time.Time {
+	return time.Unix(0, c.lastUsed.Load())
 }
 
 // errServerNotReady is returned when an LSP method is called while the server
@@ -34,6 +49,7 @@ func errCallNotConfigured(name string) error {
 }
 
 func (c *Client) requireCallLSP() (func(ctx context.Context, method string, params any, result any) error, error) {
+	c.markUsed()
 	if c.callLSP == nil {
 		return nil, errCallNotConfigured(c.name)
 	}
@@ -219,7 +219,12 @@ func (s *Manager) startServer(ctx context.Context, name, filepath string, server
 
 	// this is the slowest bit, so we do it last.
 	// [XRUSH: begin: use handlesWithPatterns for match pattern support]
-	if !handlesWithPatterns(server, cfg.MatchPatterns, filepath, s.cfg.WorkingDir()) {
+	if filepath == "" {
+		// Eager start: there is no file to match, only the root markers.
+		if !hasRootMarkers(s.cfg.WorkingDir(), server.RootMarkers) {
+			return
+		}
+	} else if !handlesWithPatterns(server, cfg.MatchPatterns, filepath, s.cfg.WorkingDir()) {
 		// [XRUSH: end]
 		// nothing to do
 		return
@@ -257,6 +262,7 @@ func (s *Manager) startServer(ctx context.Context, name, filepath string, server
 		}
 	}
 	s.clients.Set(name, client)
+	client.markUsed() // XRUSH: idle shutdown counts from startup
 	defer func() {
 		s.callback(name, client)
 	}()
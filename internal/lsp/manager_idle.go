@@ -0,0 +1,90 @@
+package lsp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// [XRUSH: begin: eager startup and idle shutdown]
+
+// StartEager starts the named servers without waiting for a file they
+// handle, so the first request does not pay for their startup. Unknown
+// and disabled names are skipped.
+func (s *Manager) StartEager(ctx context.Context, names []string) {
+	var wg sync.WaitGroup
+	for _, name := range names {
+		actual := resolveServerName(s.manager, name)
+		server, ok := s.manager.GetServer(actual)
+		if !ok {
+			slog.Warn("Eager LSP not found", "name", name)
+			continue
+		}
+		wg.Go(func() {
+			s.startServer(ctx, actual, "", server)
+		})
+	}
+	wg.Wait()
+}
+
+// StopIdle stops the ready clients that have not served a request for
+// longer than idle. They show as stopped and start again on the next file
+// they handle. It returns the names it stopped.
+func (s *Manager) StopIdle(ctx context.Context, idle time.Duration) []string {
+	if idle <= 0 {
+		return nil
+	}
+	cutoff := s.now().Add(-idle)
+	var (
+		mu      sync.Mutex
+		stopped []string
+		wg      sync.WaitGroup
+	)
+	for name, client := range s.clients.Seq2() {
+		if client.GetServerState() != StateReady || client.LastUsed().After(cutoff) {
+			continue
+		}
+		wg.Go(func() {
+			defer func() { s.callback(name, client) }()
+			client.SetServerState(StateStopped)
+			s.clients.Del(name)
+			if err := client.Close(ctx); err != nil &&
+				!errors.Is(err, io.EOF) &&
+				!errors.Is(err, context.Canceled) &&
+				!errors.Is(err, jsonrpc2.ErrClosed) &&
+				err.Error() != "signal: killed" {
+				slog.Warn("Failed to stop idle LSP client", "name", name, "error", err)
+			}
+			slog.Debug("Stopped idle LSP client", "name", name, "idle", idle)
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return stopped
+}
+
+// WatchIdle runs StopIdle periodically until ctx is done.
+func (s *Manager) WatchIdle(ctx context.Context, idle time.Duration) {
+	if idle <= 0 {
+		return
+	}
+	ticker := time.NewTicker(max(idle/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.StopIdle(ctx, idle)
+		}
+	}
+}
+
+// [XRUSH: end]
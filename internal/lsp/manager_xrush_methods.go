@@ -333,6 +333,7 @@ func (s *Manager) startCrashRecovery(ctx context.Context, name string, cfg confi
 		recoveryMgr := s
 		go func() {
 			cr := NewCrashRecovery(recoveryName, recoveryBackoff, func(recoveryCtx context.Context) error {
+				watched, _ := recoveryMgr.clients.Get(recoveryName)
 				for {
 					if current, ok := recoveryMgr.clients.Get(recoveryName); !ok || current.GetServerState() != StateReady {
 						break
@@ -344,6 +345,11 @@ func (s *Manager) startCrashRecovery(ctx context.Context, name string, cfg confi
 					}
 				}
 
+				// A deliberate stop (StopAll, StopIdle) is not a crash.
+				if watched != nil && watched.GetServerState() == StateStopped {
+					return nil
+				}
+
 				slog.Warn("LSP server crashed, attempting recovery", "name", recoveryName)
 
 				newClient, err := New(
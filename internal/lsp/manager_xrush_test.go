@@ -388,3 +388,36 @@ func TestCrashRecoveryNonCrashErrorPropagates(t *testing.T) {
 	require.Equal(t, 1, cr.Attempts())
 	require.False(t, cr.LastCrashed())
 }
+
+func TestStopIdleKeepsActiveClients(t *testing.T) {
+	t.Parallel()
+
+	recent := &Client{name: "recent"}
+	recent.serverState.Store(StateReady)
+	recent.markUsed()
+
+	starting := &Client{name: "starting"}
+	starting.serverState.Store(StateStarting)
+
+	mgr := &Manager{
+		clients:  csync.NewMap[string, *Client](),
+		now:      time.Now,
+		callback: func(string, *Client) {},
+	}
+	mgr.clients.Set("recent", recent)
+	mgr.clients.Set("starting", starting)
+
+	require.Empty(t, mgr.StopIdle(context.Background(), time.Minute))
+	require.Empty(t, mgr.StopIdle(context.Background(), 0))
+	require.Equal(t, 2, mgr.clients.Len())
+	require.Equal(t, StateReady, recent.GetServerState())
+}
+
+func TestClientLastUsed(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{name: "test"}
+	before := time.Now()
+	c.markUsed()
+	require.False(t, c.LastUsed().Before(before))
+}
@@ -0,0 +1,87 @@
+package message
+
+// ContextSourceKind identifies where a piece of turn context came from.
+type ContextSourceKind string
+
+const (
+	ContextSourceRepoMap      ContextSourceKind = "repo_map"
+	ContextSourcePinnedNotes  ContextSourceKind = "pinned_notes"
+	ContextSourceSummary      ContextSourceKind = "lcm_summary"
+	ContextSourceObservations ContextSourceKind = "observations"
+	ContextSourceContextFile  ContextSourceKind = "context_file"
+	ContextSourceFile         ContextSourceKind = "file"
+)
+
+// ContextSource is one source present in the context of a turn. Name is
+// the file path, summary ID or context file name; it is empty for sources
+// that occur once per turn, such as the repo map.
+type ContextSource struct {
+	Kind ContextSourceKind `json:"kind"`
+	Name string            `json:"name,omitempty"`
+}
+
+// CitationStatus records how much of a cited file the model could see.
+type CitationStatus string
+
+const (
+	// CitationRead means the file content was in context: it was read,
+	// edited or attached.
+	CitationRead CitationStatus = "read"
+	// CitationMentioned means only the file name was in context, e.g. as
+	// a repo map entry.
+	CitationMentioned CitationStatus = "mentioned"
+	// CitationAbsent means the file never appeared in context.
+	CitationAbsent CitationStatus = "absent"
+)
+
+// FileCitation is a file the reply refers to.
+type FileCitation struct {
+	Path   string         `json:"path"`
+	Status CitationStatus `json:"status"`
+	// Missing is set when the file does not exist in the working
+	// directory.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// ContextAttribution lists the context sources present for the step that
+// produced an assistant message and checks the files its reply cites. It
+// is display metadata and is never sent to the model.
+type ContextAttribution struct {
+	Sources   []ContextSource `json:"sources,omitempty"`
+	Citations []FileCitation  `json:"citations,omitempty"`
+}
+
+func (ContextAttribution) isPart() {}
+
+// Unverified returns the citations of files whose content was not in
+// context.
+func (a ContextAttribution) Unverified() []FileCitation {
+	var out []FileCitation
+	for _, c := range a.Citations {
+		if c.Status != CitationRead {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ContextAttribution returns the message's context attribution, or nil.
+func (m *Message) ContextAttribution() *ContextAttribution {
+	for _, part := range m.Parts {
+		if c, ok := part.(ContextAttribution); ok {
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetContextAttribution replaces the message's context attribution.
+func (m *Message) SetContextAttribution(a ContextAttribution) {
+	for i, part := range m.Parts {
+		if _, ok := part.(ContextAttribution); ok {
+			m.Parts[i] = a
+			return
+		}
+	}
+	m.Parts = append(m.Parts, a)
+}
@@ -0,0 +1,47 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestContextAttribution_PersistedOnUpdate verifies that the context
+// attribution survives a round trip through the store and stays out of the
+// messages sent to the model.
+func TestContextAttribution_PersistedOnUpdate(t *testing.T) {
+	t.Parallel()
+
+	svc, sessionID := newTestService(t, WithDebounce(0))
+
+	msg, err := svc.Create(t.Context(), sessionID, CreateMessageParams{
+		Role: Assistant,
+	})
+	require.NoError(t, err)
+	require.Nil(t, msg.ContextAttribution())
+
+	attribution := ContextAttribution{
+		Sources: []ContextSource{
+			{Kind: ContextSourceRepoMap},
+			{Kind: ContextSourceFile, Name: "main.go"},
+		},
+		Citations: []FileCitation{
+			{Path: "main.go", Status: CitationRead},
+			{Path: "util.go", Status: CitationAbsent, Missing: true},
+		},
+	}
+	msg.AppendContent("See main.go and util.go")
+	msg.SetContextAttribution(ContextAttribution{})
+	msg.SetContextAttribution(attribution)
+	msg.AddFinish(FinishReasonEndTurn, "", "")
+	require.NoError(t, svc.Update(t.Context(), msg))
+
+	got, err := svc.Get(t.Context(), msg.ID)
+	require.NoError(t, err)
+	require.Equal(t, &attribution, got.ContextAttribution())
+	require.Equal(t, []FileCitation{{Path: "util.go", Status: CitationAbsent, Missing: true}}, got.ContextAttribution().Unverified())
+
+	ai := got.ToAIMessage()
+	require.Len(t, ai, 1)
+	require.Len(t, ai[0].Content, 1, "attribution must not reach the model")
+}
@@ -0,0 +1,88 @@
+package message
+
+// ResponseCandidate is one sampled final answer of a turn.
+type ResponseCandidate struct {
+	Text        string   `json:"text"`
+	Model       string   `json:"model"`
+	Provider    string   `json:"provider,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	// Score is the judge's 0-10 rating; 0 when it gave none.
+	Score float64 `json:"score,omitempty"`
+	// Cost is what generating the candidate cost. The turn's own answer
+	// is accounted on the message and has none.
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// ResponseCandidates holds the final answers sampled for a turn. The
+// message text is the chosen candidate's; the others stay here so the
+// user can read them or switch to one. It is display metadata and is
+// never sent to the model.
+type ResponseCandidates struct {
+	Candidates []ResponseCandidate `json:"candidates"`
+	Chosen     int                 `json:"chosen"`
+	// Judge is the model that ranked the candidates and Reason its
+	// explanation. Both are empty when judging failed and the turn's own
+	// answer was kept.
+	Judge     string  `json:"judge,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+	JudgeCost float64 `json:"judge_cost,omitempty"`
+}
+
+func (ResponseCandidates) isPart() {}
+
+// Cost returns what sampling added to the turn: the extra candidates and
+// the judge.
+func (c ResponseCandidates) Cost() float64 {
+	total := c.JudgeCost
+	for _, cand := range c.Candidates {
+		total += cand.Cost
+	}
+	return total
+}
+
+// ResponseCandidates returns the message's sampled candidates, or nil.
+func (m *Message) ResponseCandidates() *ResponseCandidates {
+	for _, part := range m.Parts {
+		if c, ok := part.(ResponseCandidates); ok {
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetResponseCandidates replaces the message's sampled candidates and sets
+// its text to the chosen one.
+func (m *Message) SetResponseCandidates(c ResponseCandidates) {
+	for i, part := range m.Parts {
+		if _, ok := part.(ResponseCandidates); ok {
+			m.Parts[i] = c
+			m.setText(c.Candidates[c.Chosen].Text)
+			return
+		}
+	}
+	m.Parts = append(m.Parts, c)
+	m.setText(c.Candidates[c.Chosen].Text)
+}
+
+// ChooseCandidate makes candidate i the message text. It reports false
+// when the message has no candidate i.
+func (m *Message) ChooseCandidate(i int) bool {
+	c := m.ResponseCandidates()
+	if c == nil || i < 0 || i >= len(c.Candidates) {
+		return false
+	}
+	c.Chosen = i
+	m.SetResponseCandidates(*c)
+	return true
+}
+
+// setText replaces the text content of the message.
+func (m *Message) setText(text string) {
+	for i, part := range m.Parts {
+		if _, ok := part.(TextContent); ok {
+			m.Parts[i] = TextContent{Text: text}
+			return
+		}
+	}
+	m.Parts = append(m.Parts, TextContent{Text: text})
+}
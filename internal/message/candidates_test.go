@@ -0,0 +1,54 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestResponseCandidates_PersistedOnUpdate verifies that sampled candidates
+// survive a round trip through the store, that choosing one replaces the
+// message text and that they stay out of the messages sent to the model.
+func TestResponseCandidates_PersistedOnUpdate(t *testing.T) {
+	t.Parallel()
+
+	svc, sessionID := newTestService(t, WithDebounce(0))
+
+	msg, err := svc.Create(t.Context(), sessionID, CreateMessageParams{
+		Role: Assistant,
+	})
+	require.NoError(t, err)
+	require.Nil(t, msg.ResponseCandidates())
+	require.False(t, msg.ChooseCandidate(0))
+
+	hot := 0.9
+	candidates := ResponseCandidates{
+		Candidates: []ResponseCandidate{
+			{Text: "first answer", Model: "large", Score: 6},
+			{Text: "second answer", Model: "large", Temperature: &hot, Score: 9, Cost: 0.02},
+		},
+		Chosen:    1,
+		Judge:     "small",
+		Reason:    "more complete",
+		JudgeCost: 0.001,
+	}
+	msg.AppendContent("first answer")
+	msg.SetResponseCandidates(candidates)
+	msg.AddFinish(FinishReasonEndTurn, "", "")
+	require.NoError(t, svc.Update(t.Context(), msg))
+
+	got, err := svc.Get(t.Context(), msg.ID)
+	require.NoError(t, err)
+	require.Equal(t, &candidates, got.ResponseCandidates())
+	require.Equal(t, "second answer", got.Content().Text)
+	require.InDelta(t, 0.021, got.ResponseCandidates().Cost(), 1e-9)
+
+	ai := got.ToAIMessage()
+	require.Len(t, ai, 1)
+	require.Len(t, ai[0].Content, 1, "candidates must not reach the model")
+
+	require.True(t, got.ChooseCandidate(0))
+	require.Equal(t, "first answer", got.Content().Text)
+	require.Equal(t, 0, got.ResponseCandidates().Chosen)
+	require.False(t, got.ChooseCandidate(2))
+}
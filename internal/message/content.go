@@ -42,6 +42,10 @@ const (
 	FinishReasonUnknown FinishReason = "unknown"
 )
 
+// InterruptedFinishMessage is the finish message of assistant responses
+// recovered after the process exited mid-stream.
+const InterruptedFinishMessage = "Interrupted"
+
 type ContentPart interface {
 	isPart()
 }
@@ -54,6 +58,9 @@ type ReasoningContent struct {
 	ResponsesData    *openai.ResponsesReasoningMetadata `json:"responses_data"`
 	StartedAt        int64                              `json:"started_at,omitempty"`
 	FinishedAt       int64                              `json:"finished_at,omitempty"`
+	// XRUSH: Tokens is the reasoning share of the step's output tokens, as
+	// reported by the provider or estimated from the text.
+	Tokens int64 `json:"tokens,omitempty"`
 }
 
 func (tc ReasoningContent) String() string {
@@ -144,6 +151,31 @@ type Message struct {
 	SentToLLMAt      int64 `json:"sent_to_llm_at,omitempty"`
 	FirstTokenAt     int64 `json:"first_token_at,omitempty"`
 	CompletedAt      int64 `json:"completed_at,omitempty"`
+
+	// PromptTokens, CompletionTokens and Cost record the provider-reported
+	// usage of the LLM step that produced this message. They are zero for
+	// user and tool messages and when the provider reported no usage.
+	PromptTokens     int64   `json:"prompt_tokens,omitempty"`
+	CompletionTokens int64   `json:"completion_tokens,omitempty"`
+	Cost             float64 `json:"cost,omitempty"`
+
+	// RoutingReason explains why the message was produced by a model other
+	// than the session's large model, e.g. a trivial turn routed to the
+	// small model. It is empty for regular turns.
+	RoutingReason string `json:"routing_reason,omitempty"`
+}
+
+// SetUsage records the token usage and cost of the LLM step that produced
+// the message.
+func (m *Message) SetUsage(promptTokens, completionTokens int64, cost float64) {
+	m.PromptTokens = promptTokens
+	m.CompletionTokens = completionTokens
+	m.Cost = cost
+}
+
+// HasUsage reports whether usage has been recorded for the message.
+func (m *Message) HasUsage() bool {
+	return m.PromptTokens != 0 || m.CompletionTokens != 0 || m.Cost != 0
 }
 
 func (m *Message) Content() TextContent {
@@ -334,6 +366,48 @@ func (m *Message) FinishThinking() {
 	}
 }
 
+// XRUSH: SetReasoningTokens records the reasoning tokens of the step,
+// adding an empty reasoning part when the model reasoned without
+// streaming any of it.
+func (m *Message) SetReasoningTokens(tokens int64) {
+	for i, part := range m.Parts {
+		if c, ok := part.(ReasoningContent); ok {
+			c.Tokens = tokens
+			m.Parts[i] = c
+			return
+		}
+	}
+	if tokens > 0 {
+		m.Parts = append(m.Parts, ReasoningContent{Tokens: tokens})
+	}
+}
+
+// XRUSH: ReasoningTokens returns the recorded reasoning tokens.
+func (m *Message) ReasoningTokens() int64 {
+	return m.ReasoningContent().Tokens
+}
+
+// XRUSH: StripReasoning drops the reasoning text and provider signatures
+// of the message, keeping its token count and timing. It reports whether
+// anything was dropped. Stripped reasoning is not sent back to the model.
+func (m *Message) StripReasoning() bool {
+	for i, part := range m.Parts {
+		if c, ok := part.(ReasoningContent); ok {
+			stripped := ReasoningContent{
+				StartedAt:  c.StartedAt,
+				FinishedAt: c.FinishedAt,
+				Tokens:     c.Tokens,
+			}
+			if c == stripped {
+				return false
+			}
+			m.Parts[i] = stripped
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Message) ThinkingDuration() time.Duration {
 	reasoning := m.ReasoningContent()
 	if reasoning.StartedAt == 0 {
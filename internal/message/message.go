@@ -28,6 +28,7 @@ type CreateMessageParams struct {
 	Provider         string
 	IsSummaryMessage bool
 	SubmittedAt      int64
+	RoutingReason    string
 }
 
 // Service is the public interface to the message store.
@@ -67,6 +68,12 @@ type Service interface {
 	// message known to the service. Intended for shutdown and
 	// session-switch paths.
 	FlushAll(ctx context.Context) error
+
+	// RecoverInterrupted finishes assistant messages that a previous
+	// process left mid-stream, keeping their partial content. Only
+	// messages last written before the given time are touched. It
+	// returns the recovered messages.
+	RecoverInterrupted(ctx context.Context, before time.Time) ([]Message, error)
 }
 
 // pendingState holds the in-memory coalescing buffer for a single
@@ -185,6 +192,7 @@ func (s *service) Create(ctx context.Context, sessionID string, params CreateMes
 		Provider:         sql.NullString{String: params.Provider, Valid: params.Provider != ""},
 		IsSummaryMessage: isSummary,
 		SubmittedAt:      params.SubmittedAt,
+		RoutingReason:    params.RoutingReason,
 	})
 	if err != nil {
 		return Message{}, err
@@ -406,12 +414,15 @@ func (s *service) write(ctx context.Context, msg Message) error {
 		finishedAt.Valid = true
 	}
 	if err := s.q.UpdateMessage(ctx, db.UpdateMessageParams{
-		ID:           msg.ID,
-		Parts:        string(parts),
-		FinishedAt:   finishedAt,
-		SentToLlmAt:  msg.SentToLLMAt,
-		FirstTokenAt: msg.FirstTokenAt,
-		CompletedAt:  msg.CompletedAt,
+		ID:               msg.ID,
+		Parts:            string(parts),
+		FinishedAt:       finishedAt,
+		SentToLlmAt:      msg.SentToLLMAt,
+		FirstTokenAt:     msg.FirstTokenAt,
+		CompletedAt:      msg.CompletedAt,
+		PromptTokens:     msg.PromptTokens,
+		CompletionTokens: msg.CompletionTokens,
+		Cost:             msg.Cost,
 	}); err != nil {
 		return err
 	}
@@ -464,6 +475,10 @@ func partTypeOf(part ContentPart) partType {
 		return toolResultType
 	case Finish:
 		return finishType
+	case ContextAttribution: // XRUSH: context attribution
+		return contextAttributionType
+	case ResponseCandidates: // XRUSH: multi-candidate sampling
+		return responseCandidatesType
 	default:
 		return "unknown"
 	}
@@ -557,6 +572,34 @@ func (s *service) ListAllUserMessages(ctx context.Context) ([]Message, error) {
 	return messages, nil
 }
 
+func (s *service) RecoverInterrupted(ctx context.Context, before time.Time) ([]Message, error) {
+	dbMessages, err := s.q.ListUnfinishedAssistantMessages(ctx, before.Unix())
+	if err != nil {
+		return nil, err
+	}
+	recovered := make([]Message, 0, len(dbMessages))
+	for _, dbMessage := range dbMessages {
+		msg, err := s.fromDBItem(dbMessage)
+		if err != nil {
+			return recovered, err
+		}
+		for _, tc := range msg.ToolCalls() {
+			if !tc.Finished {
+				// Partial tool input is not valid JSON.
+				tc.Finished = true
+				tc.Input = "{}"
+				msg.AddToolCall(tc)
+			}
+		}
+		msg.AddFinish(FinishReasonError, InterruptedFinishMessage, "Crush exited before this response finished. The partial response was recovered.")
+		if err := s.Update(ctx, msg); err != nil {
+			return recovered, err
+		}
+		recovered = append(recovered, msg)
+	}
+	return recovered, nil
+}
+
 func (s *service) fromDBItem(item db.Message) (Message, error) {
 	parts, err := unmarshalParts([]byte(item.Parts))
 	if err != nil {
@@ -577,6 +620,10 @@ func (s *service) fromDBItem(item db.Message) (Message, error) {
 		SentToLLMAt:      item.SentToLlmAt,
 		FirstTokenAt:     item.FirstTokenAt,
 		CompletedAt:      item.CompletedAt,
+		PromptTokens:     item.PromptTokens,
+		CompletionTokens: item.CompletionTokens,
+		Cost:             item.Cost,
+		RoutingReason:    item.RoutingReason,
 	}, nil
 }
 
@@ -590,6 +637,9 @@ const (
 	toolCallType   partType = "tool_call"
 	toolResultType partType = "tool_result"
 	finishType     partType = "finish"
+
+	contextAttributionType partType = "context_attribution" // XRUSH: context attribution
+	responseCandidatesType partType = "response_candidates" // XRUSH: multi-candidate sampling
 )
 
 type partWrapper struct {
@@ -618,6 +668,10 @@ func marshalParts(parts []ContentPart) ([]byte, error) {
 			typ = toolResultType
 		case Finish:
 			typ = finishType
+		case ContextAttribution: // XRUSH: context attribution
+			typ = contextAttributionType
+		case ResponseCandidates: // XRUSH: multi-candidate sampling
+			typ = responseCandidatesType
 		default:
 			return nil, fmt.Errorf("unknown part type: %T", part)
 		}
@@ -692,6 +746,18 @@ func unmarshalParts(data []byte) ([]ContentPart, error) {
 				return nil, err
 			}
 			parts = append(parts, part)
+		case contextAttributionType: // XRUSH: context attribution
+			part := ContextAttribution{}
+			if err := json.Unmarshal(wrapper.Data, &part); err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
+		case responseCandidatesType: // XRUSH: multi-candidate sampling
+			part := ResponseCandidates{}
+			if err := json.Unmarshal(wrapper.Data, &part); err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
 		default:
 			return nil, fmt.Errorf("unknown part type: %s", wrapper.Type)
 		}
@@ -811,3 +811,21 @@ func TestMessagePartsBestEffort(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "hello", got.Content().Text)
 }
+
+func TestCreate_PersistsRoutingReason(t *testing.T) {
+	t.Parallel()
+
+	svc, sessionID := newTestService(t, WithDebounce(0))
+	msg, err := svc.Create(t.Context(), sessionID, CreateMessageParams{
+		Role:          Assistant,
+		Model:         "small",
+		RoutingReason: "short prompt",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "short prompt", msg.RoutingReason)
+
+	msgs, err := svc.List(t.Context(), sessionID)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, "short prompt", msgs[0].RoutingReason)
+}
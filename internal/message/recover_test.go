@@ -0,0 +1,53 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecoverInterrupted verifies that assistant messages left unfinished
+// by a previous process are finished with their partial content intact.
+func TestRecoverInterrupted(t *testing.T) {
+	t.Parallel()
+
+	svc, sessionID := newTestService(t, WithDebounce(0))
+
+	partial, err := svc.Create(t.Context(), sessionID, CreateMessageParams{Role: Assistant})
+	require.NoError(t, err)
+	partial.AppendContent("Half an ans")
+	partial.AddToolCall(ToolCall{ID: "tc1", Name: "bash", Input: `{"comm`})
+	require.NoError(t, svc.Update(t.Context(), partial))
+
+	done, err := svc.Create(t.Context(), sessionID, CreateMessageParams{Role: Assistant})
+	require.NoError(t, err)
+	done.AppendContent("Complete")
+	done.AddFinish(FinishReasonEndTurn, "", "")
+	require.NoError(t, svc.Update(t.Context(), done))
+
+	// Messages written after the cutoff are left alone.
+	recovered, err := svc.RecoverInterrupted(t.Context(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, recovered)
+
+	recovered, err = svc.RecoverInterrupted(t.Context(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, recovered, 1)
+	require.Equal(t, partial.ID, recovered[0].ID)
+
+	got, err := svc.Get(t.Context(), partial.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Half an ans", got.Content().Text)
+	require.NotNil(t, got.FinishPart())
+	require.Equal(t, FinishReasonError, got.FinishPart().Reason)
+	require.Equal(t, InterruptedFinishMessage, got.FinishPart().Message)
+	require.Len(t, got.ToolCalls(), 1)
+	require.True(t, got.ToolCalls()[0].Finished)
+	require.Equal(t, "{}", got.ToolCalls()[0].Input)
+
+	// Recovery is idempotent.
+	recovered, err = svc.RecoverInterrupted(t.Context(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, recovered)
+}
@@ -0,0 +1,82 @@
+package message
+
+import (
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUsage_PersistedOnUpdate verifies that per-message token usage and
+// cost survive a round trip through the store.
+func TestUsage_PersistedOnUpdate(t *testing.T) {
+	t.Parallel()
+
+	svc, sessionID := newTestService(t, WithDebounce(0))
+
+	msg, err := svc.Create(t.Context(), sessionID, CreateMessageParams{
+		Role: Assistant,
+	})
+	require.NoError(t, err)
+	require.False(t, msg.HasUsage(), "new messages should have no usage")
+
+	msg.AppendContent("Hello")
+	msg.SetUsage(1200, 340, 0.0125)
+	msg.AddFinish(FinishReasonEndTurn, "", "")
+	require.NoError(t, svc.Update(t.Context(), msg))
+
+	got, err := svc.Get(t.Context(), msg.ID)
+	require.NoError(t, err)
+	require.True(t, got.HasUsage())
+	require.Equal(t, int64(1200), got.PromptTokens)
+	require.Equal(t, int64(340), got.CompletionTokens)
+	require.InDelta(t, 0.0125, got.Cost, 1e-9)
+}
+
+// TestUsage_ReasoningTokensAndStrip verifies that reasoning tokens are
+// stored on the reasoning part and survive stripping its text.
+func TestUsage_ReasoningTokensAndStrip(t *testing.T) {
+	t.Parallel()
+
+	svc, sessionID := newTestService(t, WithDebounce(0))
+
+	msg, err := svc.Create(t.Context(), sessionID, CreateMessageParams{
+		Role: Assistant,
+	})
+	require.NoError(t, err)
+
+	msg.AppendReasoningContent("Let me think")
+	msg.AppendReasoningSignature("sig")
+	msg.FinishThinking()
+	msg.AppendContent("Hello")
+	msg.SetReasoningTokens(42)
+	require.NoError(t, svc.Update(t.Context(), msg))
+
+	got, err := svc.Get(t.Context(), msg.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), got.ReasoningTokens())
+	require.Equal(t, "Let me think", got.ReasoningContent().Thinking)
+
+	require.True(t, got.StripReasoning())
+	require.False(t, got.StripReasoning(), "stripping twice changes nothing")
+	reasoning := got.ReasoningContent()
+	require.Empty(t, reasoning.Thinking)
+	require.Empty(t, reasoning.Signature)
+	require.NotZero(t, reasoning.FinishedAt)
+	require.Equal(t, int64(42), reasoning.Tokens)
+	require.Equal(t, "Hello", got.Content().Text)
+
+	// Stripped reasoning is not sent back to the model.
+	for _, m := range got.ToAIMessage() {
+		for _, part := range m.Content {
+			require.NotEqual(t, fantasy.ContentTypeReasoning, part.GetType())
+		}
+	}
+
+	// Models that reason without streaming it still get a count.
+	hidden := Message{Role: Assistant}
+	hidden.SetReasoningTokens(0)
+	require.Empty(t, hidden.Parts)
+	hidden.SetReasoningTokens(7)
+	require.Equal(t, int64(7), hidden.ReasoningTokens())
+}
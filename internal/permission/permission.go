@@ -103,6 +103,7 @@ type permissionService struct {
 	autoApproveSessionsMu sync.RWMutex
 	skip                  atomic.Bool
 	allowedTools          []string
+	sessionPolicies       *csync.Map[string, sessionPolicy] // XRUSH: headless permission policies
 
 	// used to make sure we only process one request at a time
 	requestMu       sync.Mutex
@@ -209,6 +210,11 @@ func (s *permissionService) Request(ctx context.Context, opts CreatePermissionRe
 		ToolCallID: opts.ToolCallID,
 	})
 
+	// XRUSH: sessions with a permission policy are decided by it.
+	if granted, ok, err := s.decideByPolicy(opts); ok {
+		return granted, err
+	}
+
 	s.autoApproveSessionsMu.RLock()
 	autoApprove := s.autoApproveSessions[opts.SessionID]
 	s.autoApproveSessionsMu.RUnlock()
@@ -304,6 +310,7 @@ func NewPermissionService(workingDir string, skip bool, allowedTools []string) S
 		autoApproveSessions: make(map[string]bool),
 		allowedTools:        allowedTools,
 		pendingRequests:     csync.NewMap[string, chan bool](),
+		sessionPolicies:     csync.NewMap[string, sessionPolicy](), // XRUSH: headless permission policies
 	}
 	svc.skip.Store(skip)
 	return svc
@@ -0,0 +1,332 @@
+package permission
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/charmbracelet/crush/internal/pubsub"
+)
+
+// PolicyDecision is the outcome a policy assigns to a permission request.
+type PolicyDecision string
+
+const (
+	// PolicyAllow grants the request without prompting.
+	PolicyAllow PolicyDecision = "allow"
+	// PolicyDeny refuses the request; the tool reports a permission denial
+	// to the model.
+	PolicyDeny PolicyDecision = "deny"
+	// PolicyAsk marks requests that need a human. Nobody can answer in a
+	// headless run, so the request fails with ErrPolicyAsk.
+	PolicyAsk PolicyDecision = "ask"
+)
+
+// ErrPolicyAsk is returned for requests a policy sends to a human while
+// nobody is there to answer.
+var ErrPolicyAsk = errors.New("permission policy requires interactive approval")
+
+// PolicyRule matches permission requests. Empty fields match anything; tool,
+// action and command accept * and ? wildcards, path accepts doublestar
+// globs relative to the working directory.
+type PolicyRule struct {
+	Tool     string         `json:"tool,omitempty"`
+	Action   string         `json:"action,omitempty"`
+	Path     string         `json:"path,omitempty"`
+	Command  string         `json:"command,omitempty"`
+	Decision PolicyDecision `json:"decision"`
+	Reason   string         `json:"reason,omitempty"`
+}
+
+// Policy decides permission requests in non-interactive runs. Rules are
+// evaluated in order and the first match wins; requests no rule matches
+// get Default, which is ask when unset.
+type Policy struct {
+	Default PolicyDecision `json:"default,omitempty"`
+	Rules   []PolicyRule   `json:"rules"`
+}
+
+// PolicyEvaluation is the decision for one request and the rule that made
+// it. Rule is 1-based; 0 means the policy default applied.
+type PolicyEvaluation struct {
+	Decision PolicyDecision
+	Rule     int
+	Reason   string
+}
+
+// LoadPolicy reads and validates a policy file.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read permission policy: %w", err)
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// ParsePolicy decodes a policy and validates it. Unknown fields are
+// rejected so a misspelled matcher cannot silently widen a rule.
+func ParsePolicy(data []byte) (*Policy, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var policy Policy
+	if err := dec.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("parse permission policy: %w", err)
+	}
+	if err := errors.Join(policy.Validate()...); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate reports every problem in the policy: unknown decisions, bad
+// globs and rules that can never match because an earlier rule matches
+// every request.
+func (p *Policy) Validate() []error {
+	var errs []error
+	if p.Default != "" && !p.Default.valid() {
+		errs = append(errs, fmt.Errorf("default: unknown decision %q", p.Default))
+	}
+	catchAll := 0
+	for i, rule := range p.Rules {
+		n := i + 1
+		if !rule.Decision.valid() {
+			errs = append(errs, fmt.Errorf("rule %d: unknown decision %q", n, rule.Decision))
+		}
+		if rule.Path != "" && !doublestar.ValidatePattern(filepath.ToSlash(rule.Path)) {
+			errs = append(errs, fmt.Errorf("rule %d: invalid path glob %q", n, rule.Path))
+		}
+		if catchAll > 0 {
+			errs = append(errs, fmt.Errorf("rule %d: unreachable, rule %d matches every request", n, catchAll))
+		}
+		if catchAll == 0 && rule.matchesAll() {
+			catchAll = n
+		}
+	}
+	return errs
+}
+
+// Evaluate decides a request. workingDir anchors relative path globs.
+func (p *Policy) Evaluate(workingDir string, opts CreatePermissionRequest) PolicyEvaluation {
+	command := requestCommand(opts.Params)
+	for i, rule := range p.Rules {
+		if rule.matches(workingDir, opts, command) {
+			return PolicyEvaluation{Decision: rule.Decision, Rule: i + 1, Reason: rule.Reason}
+		}
+	}
+	if p.Default == "" {
+		return PolicyEvaluation{Decision: PolicyAsk, Reason: "no rule matched"}
+	}
+	return PolicyEvaluation{Decision: p.Default, Reason: "no rule matched"}
+}
+
+func (d PolicyDecision) valid() bool {
+	return d == PolicyAllow || d == PolicyDeny || d == PolicyAsk
+}
+
+func (r PolicyRule) matchesAll() bool {
+	return wildcardAll(r.Tool) && wildcardAll(r.Action) && wildcardAll(r.Command) && r.Path == ""
+}
+
+func (r PolicyRule) matches(workingDir string, opts CreatePermissionRequest, command string) bool {
+	if r.Tool != "" && !wildcardMatch(r.Tool, opts.ToolName) {
+		return false
+	}
+	if r.Action != "" && !wildcardMatch(r.Action, opts.Action) {
+		return false
+	}
+	if r.Command != "" && !wildcardMatch(r.Command, command) {
+		return false
+	}
+	if r.Path != "" && !pathMatch(r.Path, workingDir, opts.Path) {
+		return false
+	}
+	return true
+}
+
+// requestCommand extracts the command of shell tool requests from their
+// params, or "" for other tools.
+func requestCommand(params any) string {
+	if params == nil {
+		return ""
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	var withCommand struct {
+		Command string `json:"command"`
+	}
+	_ = json.Unmarshal(data, &withCommand)
+	return withCommand.Command
+}
+
+// pathMatch matches relative globs against the path relative to workingDir
+// and absolute globs against the absolute path. Paths outside workingDir
+// never match a relative glob.
+func pathMatch(pattern, workingDir, path string) bool {
+	pattern = filepath.ToSlash(pattern)
+	if path == "" {
+		return false
+	}
+	abs := path
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(workingDir, path)
+	}
+	if strings.HasPrefix(pattern, "/") {
+		ok, _ := doublestar.Match(pattern, filepath.ToSlash(abs))
+		return ok
+	}
+	rel, err := filepath.Rel(workingDir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	ok, _ := doublestar.Match(pattern, filepath.ToSlash(rel))
+	return ok
+}
+
+func wildcardAll(pattern string) bool {
+	return strings.Trim(pattern, "*") == ""
+}
+
+// wildcardMatch reports whether s matches pattern, where * matches any run
+// of characters (slashes and spaces included) and ? matches one character.
+func wildcardMatch(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	pi, si := 0, 0
+	star, mark := -1, 0
+	for si < len(str) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			pi = star + 1
+			mark++
+			si = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// AuditEntry records one decision made by a policy.
+type AuditEntry struct {
+	Time       time.Time      `json:"time"`
+	SessionID  string         `json:"session_id"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+	Tool       string         `json:"tool"`
+	Action     string         `json:"action,omitempty"`
+	Path       string         `json:"path,omitempty"`
+	Command    string         `json:"command,omitempty"`
+	Decision   PolicyDecision `json:"decision"`
+	Rule       int            `json:"rule"`
+	Reason     string         `json:"reason,omitempty"`
+}
+
+// AuditLog writes policy decisions as JSON lines. Every decision is also
+// logged with slog; a nil AuditLog only logs.
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog returns an audit log appending to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record logs the entry and appends it to the audit log.
+func (a *AuditLog) Record(entry AuditEntry) {
+	slog.Info("Permission policy decision",
+		"session_id", entry.SessionID,
+		"tool", entry.Tool,
+		"action", entry.Action,
+		"path", entry.Path,
+		"decision", entry.Decision,
+		"rule", entry.Rule,
+	)
+	if a == nil || a.w == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(append(data, '\n')); err != nil {
+		slog.Warn("Failed to write permission audit entry", "error", err)
+	}
+}
+
+// PolicyService is implemented by permission services that can answer a
+// session's requests from a policy instead of prompting.
+type PolicyService interface {
+	// ApplyPolicy decides every later request of the session with policy
+	// and records each decision to audit.
+	ApplyPolicy(sessionID string, policy *Policy, audit *AuditLog)
+}
+
+// sessionPolicy is a policy applied to one session.
+type sessionPolicy struct {
+	policy *Policy
+	audit  *AuditLog
+}
+
+func (s *permissionService) ApplyPolicy(sessionID string, policy *Policy, audit *AuditLog) {
+	s.sessionPolicies.Set(sessionID, sessionPolicy{policy: policy, audit: audit})
+}
+
+// decideByPolicy answers the request from the session's policy. ok is false
+// when no policy applies to the session.
+func (s *permissionService) decideByPolicy(opts CreatePermissionRequest) (granted, ok bool, err error) {
+	applied, ok := s.sessionPolicies.Get(opts.SessionID)
+	if !ok {
+		return false, false, nil
+	}
+	eval := applied.policy.Evaluate(s.workingDir, opts)
+	applied.audit.Record(AuditEntry{
+		Time:       time.Now().UTC(),
+		SessionID:  opts.SessionID,
+		ToolCallID: opts.ToolCallID,
+		Tool:       opts.ToolName,
+		Action:     opts.Action,
+		Path:       opts.Path,
+		Command:    requestCommand(opts.Params),
+		Decision:   eval.Decision,
+		Rule:       eval.Rule,
+		Reason:     eval.Reason,
+	})
+
+	granted = eval.Decision == PolicyAllow
+	s.notificationBroker.Publish(pubsub.CreatedEvent, PermissionNotification{
+		ToolCallID: opts.ToolCallID,
+		Granted:    granted,
+		Denied:     !granted,
+	})
+	if eval.Decision == PolicyAsk {
+		return false, true, fmt.Errorf("%w: %s %s", ErrPolicyAsk, opts.ToolName, cmp.Or(opts.Path, opts.Action))
+	}
+	return granted, true, nil
+}
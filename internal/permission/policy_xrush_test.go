@@ -0,0 +1,157 @@
+package permission
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `{
+  "default": "deny",
+  "rules": [
+    {"tool": "bash", "command": "rm *", "decision": "deny", "reason": "no deletes"},
+    {"tool": "bash", "command": "go test *", "decision": "allow"},
+    {"tool": "edit", "path": "internal/**", "decision": "allow"},
+    {"tool": "mcp_*", "decision": "ask"}
+  ]
+}`
+
+type testBashParams struct {
+	Command string `json:"command"`
+}
+
+func TestParsePolicy_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy string
+		errs   []string
+	}{
+		{
+			name:   "unknown field",
+			policy: `{"rules": [{"tools": "bash", "decision": "allow"}]}`,
+			errs:   []string{`unknown field "tools"`},
+		},
+		{
+			name:   "unknown decisions",
+			policy: `{"default": "maybe", "rules": [{"tool": "bash", "decision": "yes"}]}`,
+			errs:   []string{`default: unknown decision "maybe"`, `rule 1: unknown decision "yes"`},
+		},
+		{
+			name:   "bad glob",
+			policy: `{"rules": [{"path": "src/[", "decision": "allow"}]}`,
+			errs:   []string{`rule 1: invalid path glob "src/["`},
+		},
+		{
+			name:   "unreachable rule",
+			policy: `{"rules": [{"tool": "*", "decision": "deny"}, {"tool": "view", "decision": "allow"}]}`,
+			errs:   []string{"rule 2: unreachable, rule 1 matches every request"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParsePolicy([]byte(tt.policy))
+			require.Error(t, err)
+			for _, want := range tt.errs {
+				require.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	wd := filepath.FromSlash("/work/repo")
+
+	tests := []struct {
+		name string
+		req  CreatePermissionRequest
+		want PolicyDecision
+		rule int
+	}{
+		{"first match wins", CreatePermissionRequest{ToolName: "bash", Params: testBashParams{Command: "rm -rf build/"}}, PolicyDeny, 1},
+		{"command wildcard spans slashes", CreatePermissionRequest{ToolName: "bash", Params: testBashParams{Command: "go test ./internal/..."}}, PolicyAllow, 2},
+		{"relative path glob", CreatePermissionRequest{ToolName: "edit", Path: filepath.Join(wd, "internal", "app", "app.go")}, PolicyAllow, 3},
+		{"path outside glob", CreatePermissionRequest{ToolName: "edit", Path: filepath.Join(wd, "go.mod")}, PolicyDeny, 0},
+		{"path outside working dir", CreatePermissionRequest{ToolName: "edit", Path: filepath.FromSlash("/work/internal/x.go")}, PolicyDeny, 0},
+		{"tool wildcard", CreatePermissionRequest{ToolName: "mcp_github_create_issue"}, PolicyAsk, 4},
+		{"default", CreatePermissionRequest{ToolName: "fetch"}, PolicyDeny, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			eval := policy.Evaluate(wd, tt.req)
+			require.Equal(t, tt.want, eval.Decision)
+			require.Equal(t, tt.rule, eval.Rule)
+		})
+	}
+
+	eval := (&Policy{}).Evaluate(wd, CreatePermissionRequest{ToolName: "view"})
+	require.Equal(t, PolicyAsk, eval.Decision, "an unset default asks")
+}
+
+func TestWildcardMatch(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, wildcardMatch("go test *", "go test ./..."))
+	require.True(t, wildcardMatch("*", ""))
+	require.True(t, wildcardMatch("git ?tatus", "git status"))
+	require.True(t, wildcardMatch("*build*", "make build -j4"))
+	require.False(t, wildcardMatch("go test *", "go vet ./..."))
+	require.False(t, wildcardMatch("ls", "ls -la"))
+}
+
+func TestPermissionService_Policy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	svc := NewPermissionService("/work/repo", false, nil)
+	svc.(PolicyService).ApplyPolicy("headless", policy, NewAuditLog(&buf))
+
+	granted, err := svc.Request(t.Context(), CreatePermissionRequest{
+		SessionID: "headless", ToolCallID: "1", ToolName: "bash", Action: "execute",
+		Params: testBashParams{Command: "go test ./..."},
+	})
+	require.NoError(t, err)
+	require.True(t, granted)
+
+	granted, err = svc.Request(t.Context(), CreatePermissionRequest{
+		SessionID: "headless", ToolCallID: "2", ToolName: "bash", Action: "execute",
+		Params: testBashParams{Command: "rm -rf /"},
+	})
+	require.NoError(t, err)
+	require.False(t, granted)
+
+	granted, err = svc.Request(t.Context(), CreatePermissionRequest{
+		SessionID: "headless", ToolCallID: "3", ToolName: "mcp_github", Action: "call",
+	})
+	require.ErrorIs(t, err, ErrPolicyAsk)
+	require.False(t, granted)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	var entries []AuditEntry
+	for _, line := range lines {
+		var entry AuditEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	require.Equal(t, PolicyAllow, entries[0].Decision)
+	require.Equal(t, "go test ./...", entries[0].Command)
+	require.Equal(t, 2, entries[0].Rule)
+	require.Equal(t, PolicyDeny, entries[1].Decision)
+	require.Equal(t, "no deletes", entries[1].Reason)
+	require.Equal(t, PolicyAsk, entries[2].Decision)
+	require.Equal(t, "3", entries[2].ToolCallID)
+}
@@ -36,6 +36,9 @@ type AgentEvent struct {
 	SessionTitle string `json:"session_title,omitempty"`
 	Progress     string `json:"progress,omitempty"`
 	Done         bool   `json:"done,omitempty"`
+
+	// DurationMs is how long the turn ran, for agent finished events.
+	DurationMs int64 `json:"duration_ms,omitempty"`
 }
 
 // MarshalJSON implements the [json.Marshaler] interface.
@@ -0,0 +1,58 @@
+package proto
+
+// ToolEventType identifies a stage in a tool call's lifecycle.
+type ToolEventType string
+
+const (
+	// ToolEventStarted indicates the model finished emitting a tool call
+	// and the tool is about to run.
+	ToolEventStarted ToolEventType = "started"
+	// ToolEventFinished indicates the tool returned a result.
+	ToolEventFinished ToolEventType = "finished"
+)
+
+// ToolEvent is the wire representation of a tool lifecycle transition.
+type ToolEvent struct {
+	Type       ToolEventType `json:"type"`
+	SessionID  string        `json:"session_id"`
+	ToolCallID string        `json:"tool_call_id"`
+	ToolName   string        `json:"tool_name"`
+	IsError    bool          `json:"is_error,omitempty"`
+}
+
+// LCMEventType identifies an LCM storage transition.
+type LCMEventType string
+
+const (
+	LCMCompactionStarted   LCMEventType = "compaction_started"
+	LCMCompactionCompleted LCMEventType = "compaction_completed"
+	LCMCompactionFailed    LCMEventType = "compaction_failed"
+)
+
+// LCMEvent is the wire representation of lcm.CompactionEvent.
+type LCMEvent struct {
+	Type      LCMEventType `json:"type"`
+	SessionID string       `json:"session_id"`
+	Blocking  bool         `json:"blocking,omitempty"`
+	Rounds    int          `json:"rounds,omitempty"`
+	Success   bool         `json:"success,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// RepoMapEventType identifies a repo-map refresh transition.
+type RepoMapEventType string
+
+const (
+	RepoMapRefreshCompleted RepoMapEventType = "refresh_completed"
+	RepoMapRefreshFailed    RepoMapEventType = "refresh_failed"
+)
+
+// RepoMapEvent is the wire representation of repomap.RefreshEvent.
+type RepoMapEvent struct {
+	Type       RepoMapEventType `json:"type"`
+	SessionID  string           `json:"session_id"`
+	Tokens     int              `json:"tokens,omitempty"`
+	Forced     bool             `json:"forced,omitempty"`
+	DurationMs int64            `json:"duration_ms,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
@@ -15,6 +15,7 @@ const (
 	MCPStateStarting
 	MCPStateConnected
 	MCPStateError
+	MCPStateIdle
 )
 
 // MarshalText implements the [encoding.TextMarshaler] interface.
@@ -33,6 +34,8 @@ func (s *MCPState) UnmarshalText(data []byte) error {
 		*s = MCPStateConnected
 	case "error":
 		*s = MCPStateError
+	case "idle":
+		*s = MCPStateIdle
 	default:
 		return fmt.Errorf("unknown mcp state: %s", data)
 	}
@@ -50,6 +53,8 @@ func (s MCPState) String() string {
 		return "connected"
 	case MCPStateError:
 		return "error"
+	case MCPStateIdle:
+		return "idle"
 	default:
 		return "unknown"
 	}
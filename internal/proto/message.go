@@ -86,6 +86,7 @@ type ReasoningContent struct {
 	Signature  string `json:"signature"`
 	StartedAt  int64  `json:"started_at,omitempty"`
 	FinishedAt int64  `json:"finished_at,omitempty"`
+	Tokens     int64  `json:"tokens,omitempty"`
 }
 
 // String returns the thinking content as a string.
@@ -3,6 +3,7 @@ package pubsub
 import (
 	"context"
 	"encoding/json"
+	"slices"
 )
 
 const (
@@ -11,6 +12,12 @@ const (
 	DeletedEvent EventType = "deleted"
 )
 
+// SchemaVersion is the version of the wire schema used for [Payload]
+// envelopes. Bump it whenever an existing payload changes shape in a way
+// external subscribers cannot ignore; adding new payload types or new
+// optional fields does not require a bump.
+const SchemaVersion = 1
+
 // PayloadType identifies the type of event payload for discriminated
 // deserialization over JSON.
 type PayloadType = string
@@ -26,11 +33,40 @@ const (
 	PayloadTypeAgentEvent             PayloadType = "agent_event"
 	PayloadTypeConfigChanged          PayloadType = "config_changed"
 	PayloadTypeSkillsEvent            PayloadType = "skills_event"
+	PayloadTypeToolEvent              PayloadType = "tool_event"
+	PayloadTypeLCMEvent               PayloadType = "lcm_event"
+	PayloadTypeRepoMapEvent           PayloadType = "repomap_event"
 )
 
-// Payload wraps a discriminated JSON payload with a type tag.
+// PayloadTypes lists every known payload type in a stable order. External
+// subscribers can use it to validate event filters.
+var PayloadTypes = []PayloadType{
+	PayloadTypeLSPEvent,
+	PayloadTypeMCPEvent,
+	PayloadTypePermissionRequest,
+	PayloadTypePermissionNotification,
+	PayloadTypeMessage,
+	PayloadTypeSession,
+	PayloadTypeFile,
+	PayloadTypeAgentEvent,
+	PayloadTypeConfigChanged,
+	PayloadTypeSkillsEvent,
+	PayloadTypeToolEvent,
+	PayloadTypeLCMEvent,
+	PayloadTypeRepoMapEvent,
+}
+
+// IsKnownPayloadType reports whether t is one of [PayloadTypes].
+func IsKnownPayloadType(t PayloadType) bool {
+	return slices.Contains(PayloadTypes, t)
+}
+
+// Payload wraps a discriminated JSON payload with a type tag. Version
+// carries the [SchemaVersion] the payload was encoded with; it is zero
+// for envelopes produced by older servers.
 type Payload struct {
 	Type    PayloadType     `json:"type"`
+	Version int             `json:"version,omitempty"`
 	Payload json.RawMessage `json:"payload"`
 }
 
@@ -0,0 +1,315 @@
+package remote
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/crush/internal/config"
+)
+
+// devcontainerFiles are where a project keeps its devcontainer
+// configuration, in lookup order.
+var devcontainerFiles = []string{
+	filepath.Join(".devcontainer", "devcontainer.json"),
+	".devcontainer.json",
+}
+
+// devcontainerConfig is the part of a devcontainer.json that crush uses.
+// Paths are relative to the directory holding the file.
+type devcontainerConfig struct {
+	Image      string `json:"image"`
+	DockerFile string `json:"dockerFile"`
+	Build      struct {
+		Dockerfile string            `json:"dockerfile"`
+		Context    string            `json:"context"`
+		Args       map[string]string `json:"args"`
+	} `json:"build"`
+	DockerComposeFile any               `json:"dockerComposeFile"`
+	WorkspaceFolder   string            `json:"workspaceFolder"`
+	ContainerUser     string            `json:"containerUser"`
+	ContainerEnv      map[string]string `json:"containerEnv"`
+	RunArgs           []string          `json:"runArgs"`
+}
+
+// devcontainer starts the container of a devcontainer target on first use.
+// The container is named after the working directory and left running
+// when crush exits, so later sessions reuse it and its toolchain caches.
+type devcontainer struct {
+	name       string
+	image      string
+	dockerfile string // build the image from this file when set
+	context    string
+	buildArgs  map[string]string
+	user       string
+	env        map[string]string
+	runArgs    []string
+	localRoot  string
+	root       string
+
+	mu      sync.Mutex
+	started bool
+}
+
+// newDevcontainer configures the devcontainer of the project at localRoot
+// from its devcontainer.json, with opts.Image, opts.Container and
+// opts.Path taking precedence.
+func newDevcontainer(opts *config.RemoteOptions, localRoot string) (*devcontainer, error) {
+	cfg, dir, err := loadDevcontainerConfig(localRoot)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil && opts.Image == "" {
+		return nil, fmt.Errorf("remote type devcontainer requires an image or a .devcontainer/devcontainer.json in %s", localRoot)
+	}
+	if cfg == nil {
+		cfg = &devcontainerConfig{}
+	}
+
+	base := filepath.Base(localRoot)
+	root := opts.Path
+	if root == "" {
+		root = cmp.Or(expandDevcontainerVars(cfg.WorkspaceFolder, localRoot, ""), "/workspaces/"+base)
+	}
+	if !path.IsAbs(root) {
+		return nil, fmt.Errorf("remote path must be absolute: %s", root)
+	}
+	root = path.Clean(root)
+
+	d := &devcontainer{
+		name:      cmp.Or(opts.Container, containerName(localRoot)),
+		image:     opts.Image,
+		buildArgs: cfg.Build.Args,
+		user:      cfg.ContainerUser,
+		runArgs:   cfg.RunArgs,
+		localRoot: localRoot,
+		root:      root,
+	}
+	if d.image == "" {
+		dockerfile := cmp.Or(cfg.Build.Dockerfile, cfg.DockerFile)
+		switch {
+		case cfg.Image != "":
+			d.image = cfg.Image
+		case dockerfile != "":
+			d.image = d.name
+			d.dockerfile = filepath.Join(dir, filepath.FromSlash(dockerfile))
+			d.context = filepath.Join(dir, filepath.FromSlash(cmp.Or(cfg.Build.Context, ".")))
+		case cfg.DockerComposeFile != nil:
+			return nil, errors.New("docker compose devcontainers are not supported; set an image")
+		default:
+			return nil, errors.New("devcontainer.json has no image or dockerfile")
+		}
+	}
+	if len(cfg.ContainerEnv) > 0 {
+		d.env = make(map[string]string, len(cfg.ContainerEnv))
+		for k, v := range cfg.ContainerEnv {
+			d.env[k] = expandDevcontainerVars(v, localRoot, root)
+		}
+	}
+	return d, nil
+}
+
+// loadDevcontainerConfig reads the devcontainer.json of the project at
+// localRoot, returning nil when there is none.
+func loadDevcontainerConfig(localRoot string) (*devcontainerConfig, string, error) {
+	for _, name := range devcontainerFiles {
+		file := filepath.Join(localRoot, name)
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		var cfg devcontainerConfig
+		if err := json.Unmarshal(stripJSONC(data), &cfg); err != nil {
+			return nil, "", fmt.Errorf("parse %s: %w", file, err)
+		}
+		return &cfg, filepath.Dir(file), nil
+	}
+	return nil, "", nil
+}
+
+// containerName names the devcontainer of the project at localRoot after
+// its directory, with a hash of its path to tell apart projects of the
+// same name.
+func containerName(localRoot string) string {
+	sum := sha256.Sum256([]byte(localRoot))
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, filepath.Base(localRoot))
+	return "crush-" + strings.Trim(base, "-_.") + "-" + hex.EncodeToString(sum[:4])
+}
+
+// ensure starts the container unless it already runs, building its image
+// first when it comes from a Dockerfile. A stopped container is restarted
+// as it is; remove it to apply configuration changes.
+func (d *devcontainer) ensure(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started {
+		return nil
+	}
+	out, err := exec.CommandContext(ctx, "docker", "inspect", "-f", "{{.State.Running}}", d.name).Output()
+	switch {
+	case err == nil && strings.TrimSpace(string(out)) == "true":
+	case err == nil:
+		if err := docker(ctx, "start", d.name); err != nil {
+			return fmt.Errorf("start devcontainer %s: %w", d.name, err)
+		}
+	default:
+		if d.dockerfile != "" {
+			if err := docker(ctx, d.buildArgv()...); err != nil {
+				return fmt.Errorf("build devcontainer image: %w", err)
+			}
+		}
+		if err := docker(ctx, d.runArgv()...); err != nil {
+			return fmt.Errorf("create devcontainer %s: %w", d.name, err)
+		}
+	}
+	d.started = true
+	return nil
+}
+
+func (d *devcontainer) buildArgv() []string {
+	args := []string{"build", "-t", d.image, "-f", d.dockerfile}
+	for _, k := range slices.Sorted(maps.Keys(d.buildArgs)) {
+		args = append(args, "--build-arg", k+"="+d.buildArgs[k])
+	}
+	return append(args, d.context)
+}
+
+// runArgv creates the container with the working directory mounted at the
+// project root. Without a containerUser it runs as the local user, so the
+// files it writes to the mount stay owned by them; HOME then points at a
+// writable directory, as the image may not know the user.
+func (d *devcontainer) runArgv() []string {
+	args := []string{
+		"run", "-d", "--init", "--name", d.name,
+		"-v", d.localRoot + ":" + d.root,
+		"-w", d.root,
+	}
+	env := maps.Clone(d.env)
+	switch {
+	case d.user != "":
+		args = append(args, "--user", d.user)
+	case os.Getuid() > 0:
+		args = append(args, "--user", strconv.Itoa(os.Getuid())+":"+strconv.Itoa(os.Getgid()))
+		if _, ok := env["HOME"]; !ok {
+			if env == nil {
+				env = make(map[string]string, 1)
+			}
+			env["HOME"] = "/tmp"
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		args = append(args, "-e", k+"="+env[k])
+	}
+	args = append(args, d.runArgs...)
+	// The image's entrypoint is replaced by one that idles until the
+	// container is stopped; commands arrive through docker exec.
+	return append(args, "--entrypoint", "sh", d.image, "-c", "trap 'exit 0' TERM; while sleep 3600; do :; done")
+}
+
+// docker runs a docker command, reporting its stderr with the error.
+func docker(ctx context.Context, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+var devcontainerVar = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// expandDevcontainerVars substitutes the local workspace, container
+// workspace and local environment variables of devcontainer.json. Others
+// are left as they are.
+func expandDevcontainerVars(s, localRoot, root string) string {
+	return devcontainerVar.ReplaceAllStringFunc(s, func(m string) string {
+		name := m[2 : len(m)-1]
+		switch {
+		case name == "localWorkspaceFolder":
+			return localRoot
+		case name == "localWorkspaceFolderBasename":
+			return filepath.Base(localRoot)
+		case name == "containerWorkspaceFolder" && root != "":
+			return root
+		case name == "containerWorkspaceFolderBasename" && root != "":
+			return path.Base(root)
+		case strings.HasPrefix(name, "localEnv:"):
+			name, def, _ := strings.Cut(strings.TrimPrefix(name, "localEnv:"), ":")
+			return cmp.Or(os.Getenv(name), def)
+		}
+		return m
+	})
+}
+
+// stripJSONC turns the JSON with comments and trailing commas that
+// devcontainer.json allows into plain JSON.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket.
+			if trimmed := bytes.TrimRight(out, " \t\r\n"); len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				out = trimmed[:len(trimmed)-1]
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,214 @@
+// Package remote runs commands on a remote execution target, a host
+// reached over SSH, a container reached through docker exec or the
+// project's own devcontainer, and maps paths between the local working
+// directory and the project root on the target.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/shell"
+)
+
+// Target is a configured remote execution target. A nil *Target stands
+// for local execution: its path methods return their input unchanged and
+// Push does nothing.
+type Target struct {
+	kind      string
+	dest      string
+	args      []string
+	localRoot string
+	root      string
+	sync      bool
+	// dev starts the container of a devcontainer target.
+	dev *devcontainer
+}
+
+// New returns the target configured by opts, or nil when opts is nil.
+// localRoot is the local working directory the target's project root
+// corresponds to.
+func New(opts *config.RemoteOptions, localRoot string) (*Target, error) {
+	if opts == nil {
+		return nil, nil
+	}
+	t := &Target{
+		kind:      opts.Type,
+		args:      opts.Args,
+		localRoot: filepath.Clean(localRoot),
+		sync:      opts.Sync,
+	}
+	switch opts.Type {
+	case config.RemoteTypeSSH:
+		t.dest = opts.Host
+	case config.RemoteTypeDocker:
+		t.dest = opts.Container
+	case config.RemoteTypeDevcontainer:
+		dev, err := newDevcontainer(opts, t.localRoot)
+		if err != nil {
+			return nil, err
+		}
+		t.dest, t.root, t.dev = dev.name, dev.root, dev
+		return t, nil
+	default:
+		return nil, fmt.Errorf("unknown remote type %q: expected %q, %q or %q", opts.Type, config.RemoteTypeSSH, config.RemoteTypeDocker, config.RemoteTypeDevcontainer)
+	}
+	if t.dest == "" {
+		if t.kind == config.RemoteTypeSSH {
+			return nil, errors.New("remote type ssh requires a host")
+		}
+		return nil, errors.New("remote type docker requires a container")
+	}
+	t.root = filepath.ToSlash(t.localRoot)
+	if opts.Path != "" {
+		if !path.IsAbs(opts.Path) {
+			return nil, fmt.Errorf("remote path must be absolute: %s", opts.Path)
+		}
+		t.root = path.Clean(opts.Path)
+	}
+	return t, nil
+}
+
+// String describes the target, such as "ssh dev@devbox:/srv/app".
+func (t *Target) String() string {
+	if t == nil {
+		return "local"
+	}
+	return t.kind + " " + t.dest + ":" + t.root
+}
+
+// Sync reports whether written files are copied to the target.
+func (t *Target) Sync() bool {
+	return t != nil && t.sync
+}
+
+// RemotePath maps a local path under the working directory to its path on
+// the target. Other paths are returned unchanged, as they are taken to be
+// paths on the target already.
+func (t *Target) RemotePath(p string) string {
+	if remotePath, ok := t.remotePath(p); ok {
+		return remotePath
+	}
+	return p
+}
+
+func (t *Target) remotePath(p string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(t.localRoot, filepath.Clean(p))
+	if err != nil || rel != "." && !filepath.IsLocal(rel) {
+		return "", false
+	}
+	return path.Join(t.root, filepath.ToSlash(rel)), true
+}
+
+// LocalPath maps a path under the project root on the target to the
+// corresponding local path, so that paths seen in remote command output can
+// be edited. Other paths are returned unchanged.
+func (t *Target) LocalPath(p string) string {
+	if t == nil || t.root == filepath.ToSlash(t.localRoot) || !path.IsAbs(filepath.ToSlash(p)) {
+		return p
+	}
+	rel, ok := strings.CutPrefix(path.Clean(filepath.ToSlash(p)), t.root)
+	if !ok || rel != "" && !strings.HasPrefix(rel, "/") && t.root != "/" {
+		return p
+	}
+	return filepath.Join(t.localRoot, filepath.FromSlash(strings.TrimPrefix(rel, "/")))
+}
+
+// Exec runs command with bash in dir on the target, streaming its output.
+// It satisfies [shell.Remote]. A non-zero exit is reported as an
+// *exec.ExitError; ssh itself exits 255 when the host cannot be reached.
+// A devcontainer is started by the first command.
+func (t *Target) Exec(ctx context.Context, dir, command string, stdout, stderr io.Writer) error {
+	if err := t.start(ctx); err != nil {
+		return err
+	}
+	cmd := t.command(ctx, dir, command, false)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// Push copies the local file at p to its path on the target when Sync is
+// set. Files outside the working directory are not copied.
+func (t *Target) Push(ctx context.Context, p string) error {
+	if !t.Sync() {
+		return nil
+	}
+	remotePath, ok := t.remotePath(p)
+	if !ok {
+		return nil
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	if err := t.start(ctx); err != nil {
+		return err
+	}
+	script := "mkdir -p " + quote(path.Dir(remotePath)) + " && cat > " + quote(remotePath)
+	cmd := t.command(ctx, "/", script, true)
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("copy %s to %s: %w: %s", p, t.dest, err, msg)
+		}
+		return fmt.Errorf("copy %s to %s: %w", p, t.dest, err)
+	}
+	return nil
+}
+
+// start starts the container of a devcontainer target.
+func (t *Target) start(ctx context.Context) error {
+	if t.dev == nil {
+		return nil
+	}
+	return t.dev.ensure(ctx)
+}
+
+// command builds the ssh or docker exec invocation running script with
+// bash in dir on the target, the dialect the shell tool parses commands in. The crush environment markers are exported so
+// remote tools can tell they run under an agent, as they can locally.
+func (t *Target) command(ctx context.Context, dir, script string, stdin bool) *exec.Cmd {
+	env := shell.CrushEnvMarkers()
+	switch t.kind {
+	case config.RemoteTypeDocker, config.RemoteTypeDevcontainer:
+		args := []string{"exec", "-w", dir}
+		if stdin {
+			args = append(args, "-i")
+		}
+		for _, e := range env {
+			args = append(args, "-e", e)
+		}
+		args = append(args, t.args...)
+		args = append(args, t.dest, "bash", "-c", script)
+		return exec.CommandContext(ctx, "docker", args...)
+	default:
+		// ssh joins its arguments into a line for the remote login shell,
+		// so the script is quoted as a whole. BatchMode keeps a
+		// password prompt from hanging the command; user arguments come
+		// first, so they can override it.
+		wrapped := "cd " + quote(dir) + " || exit 1\nexport " + strings.Join(env, " ") + "\n" + script
+		args := append([]string{}, t.args...)
+		args = append(args, "-T", "-o", "BatchMode=yes", "--", t.dest, "bash -c "+quote(wrapped))
+		return exec.CommandContext(ctx, "ssh", args...)
+	}
+}
+
+// quote quotes s as a single sh word.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
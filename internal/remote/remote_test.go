@@ -0,0 +1,223 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/shell"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	target, err := New(nil, "/home/me/app")
+	require.NoError(t, err)
+	require.Nil(t, target)
+	require.Equal(t, "local", target.String())
+	require.False(t, target.Sync())
+
+	for _, opts := range []config.RemoteOptions{
+		{Type: "telnet", Host: "box"},
+		{Type: config.RemoteTypeSSH},
+		{Type: config.RemoteTypeDocker, Host: "box"},
+		{Type: config.RemoteTypeSSH, Host: "box", Path: "srv/app"},
+	} {
+		_, err := New(&opts, "/home/me/app")
+		require.Error(t, err, opts)
+	}
+
+	target, err = New(&config.RemoteOptions{Type: config.RemoteTypeSSH, Host: "dev@box", Path: "/srv/app/", Sync: true}, "/home/me/app")
+	require.NoError(t, err)
+	require.Equal(t, "ssh dev@box:/srv/app", target.String())
+	require.True(t, target.Sync())
+}
+
+func TestPathMapping(t *testing.T) {
+	t.Parallel()
+
+	local := filepath.Join(t.TempDir(), "app")
+	target, err := New(&config.RemoteOptions{Type: config.RemoteTypeSSH, Host: "box", Path: "/srv/app"}, local)
+	require.NoError(t, err)
+
+	require.Equal(t, "/srv/app", target.RemotePath(local))
+	require.Equal(t, "/srv/app/cmd/main.go", target.RemotePath(filepath.Join(local, "cmd", "main.go")))
+	require.Equal(t, "/var/log", target.RemotePath("/var/log"))
+	require.Equal(t, filepath.Join(local, "cmd", "main.go"), target.LocalPath("/srv/app/cmd/main.go"))
+	require.Equal(t, local, target.LocalPath("/srv/app"))
+	require.Equal(t, "/srv/application/x", target.LocalPath("/srv/application/x"))
+	require.Equal(t, "cmd/main.go", target.LocalPath("cmd/main.go"))
+
+	var none *Target
+	require.Equal(t, "/srv/app/x", none.LocalPath("/srv/app/x"))
+	require.Equal(t, local, none.RemotePath(local))
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	ssh, err := New(&config.RemoteOptions{Type: config.RemoteTypeSSH, Host: "box", Args: []string{"-p", "2222"}}, "/app")
+	require.NoError(t, err)
+	cmd := ssh.command(t.Context(), "/app/it's", "go test ./...", false)
+	require.Equal(t, "ssh", filepath.Base(cmd.Path))
+	require.Equal(t, []string{"-p", "2222", "-T", "-o", "BatchMode=yes", "--", "box"}, cmd.Args[1:8])
+	require.Equal(t, `bash -c 'cd '\''/app/it'\''\'\'''\''s'\'' || exit 1
+export CRUSH=1 AGENT=crush AI_AGENT=crush
+go test ./...'`, cmd.Args[8])
+
+	docker, err := New(&config.RemoteOptions{Type: config.RemoteTypeDocker, Container: "dev", Path: "/src"}, "/app")
+	require.NoError(t, err)
+	cmd = docker.command(t.Context(), "/src", "make", true)
+	require.Equal(t, []string{"exec", "-w", "/src", "-i", "-e", "CRUSH=1", "-e", "AGENT=crush", "-e", "AI_AGENT=crush", "dev", "bash", "-c", "make"}, cmd.Args[1:])
+}
+
+// fakeSSH puts an ssh on PATH that runs its command locally, the way the
+// remote login shell would.
+func fakeSSH(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh is a shell script")
+	}
+	bin := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\nexec sh -c \"$last\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "ssh"), []byte(script), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestExecAndPush(t *testing.T) {
+	fakeSSH(t)
+
+	local, remoteRoot := t.TempDir(), t.TempDir()
+	target, err := New(&config.RemoteOptions{Type: config.RemoteTypeSSH, Host: "box", Path: filepath.ToSlash(remoteRoot), Sync: true}, local)
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	err = target.Exec(t.Context(), target.RemotePath(local), `pwd; echo "$CRUSH"; echo oops >&2`, &stdout, &stderr)
+	require.NoError(t, err)
+	wd, err := filepath.EvalSymlinks(remoteRoot)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	got, err := filepath.EvalSymlinks(lines[0])
+	require.NoError(t, err)
+	require.Equal(t, wd, got)
+	require.Equal(t, "1", lines[1])
+	require.Equal(t, "oops\n", stderr.String())
+
+	err = target.Exec(t.Context(), target.RemotePath(local), "exit 3", &stdout, &stderr)
+	require.Equal(t, 3, shell.ExitCode(err))
+
+	file := filepath.Join(local, "pkg", "it's.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("package pkg\n"), 0o644))
+	require.NoError(t, target.Push(t.Context(), file))
+	data, err := os.ReadFile(filepath.Join(remoteRoot, "pkg", "it's.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package pkg\n", string(data))
+
+	// Files outside the working directory are left alone.
+	require.NoError(t, target.Push(t.Context(), filepath.Join(t.TempDir(), "other.go")))
+}
+
+func TestStripJSONC(t *testing.T) {
+	t.Parallel()
+
+	in := `{
+	// The image.
+	"image": "golang:1.25", /* inline */
+	"url": "http://example.com/*x*/",
+	"runArgs": ["--cap-add=SYS_PTRACE",],
+}`
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(stripJSONC([]byte(in)), &got))
+	require.Equal(t, map[string]any{
+		"image":   "golang:1.25",
+		"url":     "http://example.com/*x*/",
+		"runArgs": []any{"--cap-add=SYS_PTRACE"},
+	}, got)
+}
+
+func TestNewDevcontainer(t *testing.T) {
+	t.Parallel()
+
+	local := filepath.Join(t.TempDir(), "My App")
+	_, err := New(&config.RemoteOptions{Type: config.RemoteTypeDevcontainer}, local)
+	require.Error(t, err, "no devcontainer.json and no image")
+
+	target, err := New(&config.RemoteOptions{Type: config.RemoteTypeDevcontainer, Image: "golang:1.25"}, local)
+	require.NoError(t, err)
+	require.Regexp(t, `^devcontainer crush-my-app-[0-9a-f]{8}:/workspaces/My App$`, target.String())
+	require.Equal(t, "/workspaces/My App/main.go", target.RemotePath(filepath.Join(local, "main.go")))
+
+	writeDevcontainer(t, local, `{"dockerComposeFile": "compose.yml", "service": "app"}`)
+	_, err = New(&config.RemoteOptions{Type: config.RemoteTypeDevcontainer}, local)
+	require.ErrorContains(t, err, "compose")
+}
+
+func writeDevcontainer(t *testing.T, local, content string) {
+	t.Helper()
+	dir := filepath.Join(local, ".devcontainer")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "devcontainer.json"), []byte(content), 0o644))
+}
+
+// fakeDocker puts a docker on PATH that logs its invocations, knows of no
+// container until one is run, and runs exec'd commands locally.
+func fakeDocker(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker is a shell script")
+	}
+	bin := t.TempDir()
+	log := filepath.Join(bin, "log")
+	script := `#!/bin/sh
+echo "$*" >> "` + log + `"
+case "$1" in
+inspect) [ -f "` + bin + `/running" ] && echo true && exit 0; exit 1 ;;
+run) touch "` + bin + `/running" ;;
+exec) for last; do :; done; exec sh -c "$last" ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "docker"), []byte(script), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestDevcontainerStartsOnFirstCommand(t *testing.T) {
+	log := fakeDocker(t)
+
+	local := t.TempDir()
+	writeDevcontainer(t, local, `{
+	// Built from the project's Dockerfile.
+	"build": {"dockerfile": "Dockerfile", "context": "..", "args": {"GO": "1.25"}},
+	"workspaceFolder": "/src/${localWorkspaceFolderBasename}",
+	"containerUser": "dev",
+	"containerEnv": {"WORKSPACE": "${containerWorkspaceFolder}"},
+	"runArgs": ["--cap-add=SYS_PTRACE"],
+}`)
+	target, err := New(&config.RemoteOptions{Type: config.RemoteTypeDevcontainer, Container: "app-dev"}, local)
+	require.NoError(t, err)
+	root := "/src/" + filepath.Base(local)
+	require.Equal(t, "devcontainer app-dev:"+root, target.String())
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, target.Exec(t.Context(), root, "echo hi", &stdout, &stderr))
+	require.NoError(t, target.Exec(t.Context(), root, "echo again", &stdout, &stderr))
+	require.Equal(t, "hi\nagain\n", stdout.String())
+
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"inspect -f {{.State.Running}} app-dev",
+		"build -t app-dev -f " + filepath.Join(local, ".devcontainer", "Dockerfile") + " --build-arg GO=1.25 " + local,
+		"run -d --init --name app-dev -v " + local + ":" + root + " -w " + root + " --user dev -e WORKSPACE=" + root +
+			" --cap-add=SYS_PTRACE --entrypoint sh app-dev -c trap 'exit 0' TERM; while sleep 3600; do :; done",
+		"exec -w " + root + " -e CRUSH=1 -e AGENT=crush -e AI_AGENT=crush app-dev bash -c echo hi",
+		"exec -w " + root + " -e CRUSH=1 -e AGENT=crush -e AI_AGENT=crush app-dev bash -c echo again",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+}
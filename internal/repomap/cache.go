@@ -20,11 +20,20 @@ type sessionStorageValue struct {
 type renderCacheEntry struct {
 	mapString  string
 	tokenCount int
+	lastUsed   uint64
 }
 
+// maxRenderCacheEntries caps the maps a RenderCache keeps per session.
+// Every distinct set of chat files and mentions renders under its own key,
+// so without a cap a long session accumulates them indefinitely.
+const maxRenderCacheEntries = 16
+
+// RenderCache holds rendered maps by render key, evicting the least
+// recently used beyond maxRenderCacheEntries.
 type RenderCache struct {
-	mu      sync.RWMutex
+	mu      sync.Mutex
 	entries map[string]renderCacheEntry
+	clock   uint64
 }
 
 type SessionRenderCacheSet struct {
@@ -76,13 +85,23 @@ func (s *SessionRenderCacheSet) ClearAll() {
 	s.sessions = make(map[string]*RenderCache)
 }
 
+// Size returns the number of sessions with render caches.
+func (s *SessionRenderCacheSet) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessions)
+}
+
 func (c *RenderCache) Get(key string) (string, int, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	entry, ok := c.entries[key]
 	if !ok {
 		return "", 0, false
 	}
+	c.clock++
+	entry.lastUsed = c.clock
+	c.entries[key] = entry
 	return entry.mapString, entry.tokenCount, true
 }
 
@@ -92,7 +111,24 @@ func (c *RenderCache) Set(key, mapString string, tokenCount int) {
 	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.entries[key] = renderCacheEntry{mapString: mapString, tokenCount: tokenCount}
+	c.clock++
+	c.entries[key] = renderCacheEntry{mapString: mapString, tokenCount: tokenCount, lastUsed: c.clock}
+	for len(c.entries) > maxRenderCacheEntries {
+		var oldest string
+		for k, e := range c.entries {
+			if oldest == "" || e.lastUsed < c.entries[oldest].lastUsed {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// Len returns the number of cached maps.
+func (c *RenderCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
 }
 
 func (c *RenderCache) Delete(key string) {
@@ -733,3 +733,23 @@ func TestCacheZeroValueHandling(t *testing.T) {
 	require.Empty(t, s)
 	require.Zero(t, tok)
 }
+
+// TestRenderCacheEvictsLeastRecentlyUsed verifies the per-session entry cap.
+func TestRenderCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	cache := NewRenderCache()
+	for i := range maxRenderCacheEntries {
+		cache.Set(string(rune('a'+i)), "map", i)
+	}
+	// Touch the oldest entry so the next one in line is evicted instead.
+	_, _, ok := cache.Get("a")
+	require.True(t, ok)
+
+	cache.Set("new", "map", 99)
+	require.Equal(t, maxRenderCacheEntries, cache.Len())
+	_, _, ok = cache.Get("a")
+	require.True(t, ok)
+	_, _, ok = cache.Get("b")
+	require.False(t, ok)
+}
@@ -0,0 +1,22 @@
+package repomap
+
+import "time"
+
+// RefreshEventType identifies the outcome of a repo-map refresh.
+type RefreshEventType string
+
+const (
+	RefreshCompleted RefreshEventType = "completed"
+	RefreshFailed    RefreshEventType = "failed"
+)
+
+// RefreshEvent is published after every synchronous or asynchronous
+// repo-map refresh so observers can track map freshness.
+type RefreshEvent struct {
+	Type      RefreshEventType
+	SessionID string
+	Tokens    int
+	Forced    bool
+	Duration  time.Duration
+	Error     string
+}
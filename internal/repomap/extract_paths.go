@@ -66,6 +66,14 @@ func normalizeRepoRelPath(rootDir, path string) (string, error) {
 }
 
 func normalizeFileUniverse(rootDir string, fileUniverse []string) ([]string, error) {
+	return normalizePaths(fileUniverse, func(path string) (string, error) {
+		return normalizeRepoRelPath(rootDir, path)
+	})
+}
+
+// normalizePaths maps fileUniverse through toRel, sorted and without
+// duplicates.
+func normalizePaths(fileUniverse []string, toRel func(string) (string, error)) ([]string, error) {
 	if len(fileUniverse) == 0 {
 		return nil, nil
 	}
@@ -73,7 +81,7 @@ func normalizeFileUniverse(rootDir string, fileUniverse []string) ([]string, err
 	seen := make(map[string]struct{}, len(fileUniverse))
 	normalized := make([]string, 0, len(fileUniverse))
 	for _, path := range fileUniverse {
-		rel, err := normalizeRepoRelPath(rootDir, path)
+		rel, err := toRel(path)
 		if err != nil {
 			return nil, err
 		}
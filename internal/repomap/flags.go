@@ -0,0 +1,55 @@
+package repomap
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/featureflags"
+)
+
+// maxFlagPreludeFlags is the most flags the feature flag prelude lists;
+// feature_flags covers the rest.
+const maxFlagPreludeFlags = 50
+
+// flagPrelude caches the feature flag section of the map, enabled with
+// feature_flags.repo_map. It is rebuilt only when a source file is added,
+// removed or modified.
+type flagPrelude struct {
+	opts *config.FeatureFlagOptions
+
+	mu      sync.Mutex
+	stamp   uint64
+	section string
+}
+
+// Section returns the feature flag prelude for the source files among
+// files, or "" when it is disabled or no flags are checked.
+func (p *flagPrelude) Section(ctx context.Context, rootDir string, files []string) string {
+	if !p.opts.AnnotateRepoMap() {
+		return ""
+	}
+	sources := featureflags.SourceFiles(files)
+	if len(sources) == 0 {
+		return ""
+	}
+	stamp := featureflags.Stamp(rootDir, sources)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if stamp == p.stamp {
+		return p.section
+	}
+	patterns, err := featureflags.Patterns(p.opts.CustomPatterns())
+	if err != nil {
+		slog.Debug("Invalid feature flag pattern", "error", err)
+		return ""
+	}
+	ix, err := featureflags.Scan(ctx, rootDir, sources, patterns)
+	if err != nil {
+		return ""
+	}
+	p.stamp, p.section = stamp, ix.Prelude(maxFlagPreludeFlags)
+	return p.section
+}
@@ -0,0 +1,29 @@
+package repomap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagPreludeSection(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	src := filepath.Join(root, "checkout.go")
+	require.NoError(t, os.WriteFile(src, []byte(`if unleash.IsEnabled("new-checkout") {}`), 0o644))
+	files := []string{"README.md", "checkout.go"}
+
+	var disabled flagPrelude
+	require.Empty(t, disabled.Section(t.Context(), root, files))
+
+	p := flagPrelude{opts: &config.FeatureFlagOptions{RepoMap: true, Patterns: []string{`features\.On\("([^"]+)"\)`}}}
+	require.Empty(t, p.Section(t.Context(), root, []string{"README.md"}))
+	require.Equal(t, "Feature flags (1):\n  new-checkout: checkout.go\n", p.Section(t.Context(), root, files))
+
+	require.NoError(t, os.WriteFile(src, []byte(`if features.On("dark-mode") && unleash.IsEnabled("new-checkout") {}`), 0o644))
+	require.Equal(t, "Feature flags (2):\n  dark-mode: checkout.go\n  new-checkout: checkout.go\n", p.Section(t.Context(), root, files))
+}
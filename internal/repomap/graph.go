@@ -4,8 +4,10 @@
 package repomap
 
 import (
+	"maps"
 	"math"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"unicode"
@@ -35,6 +37,27 @@ type ImportEdge struct {
 // BuildGraphOptions configures graph construction.
 type BuildGraphOptions struct {
 	Imports []ImportEdge
+	// LanguageWeights and GlobWeights scale the edges into matching files,
+	// and with them the rank of the files' definitions (see fileWeight).
+	LanguageWeights map[string]float64
+	GlobWeights     map[string]float64
+}
+
+// fileWeight returns the rank multiplier of relPath: the weight of its
+// language times that of every glob it matches. Negative weights are
+// ignored.
+func (o BuildGraphOptions) fileWeight(relPath string) float64 {
+	weight := 1.0
+	if w, ok := o.LanguageWeights[treesitter.GetQueryKey(treesitter.MapPath(relPath))]; ok && w >= 0 {
+		weight *= w
+	}
+	// Sorted, so the product does not depend on map order.
+	for _, glob := range slices.Sorted(maps.Keys(o.GlobWeights)) {
+		if w := o.GlobWeights[glob]; w >= 0 && matchesAnyGlob(relPath, []string{glob}) {
+			weight *= w
+		}
+	}
+	return weight
 }
 
 // FileGraph is a directed multigraph over repository files.
@@ -137,15 +160,20 @@ func buildGraph(tags []treesitter.Tag, chatFiles []string, mentionedIdents []str
 
 	var edges []GraphEdge
 
-	// 3) Self-edges for orphan definitions.
+	// 3) Self-edges for orphan definitions. HTTP routes are entry points
+	// that nothing references by name, so they weigh as much as a reference.
 	for ident, defFiles := range defsByIdent {
 		if _, referenced := refsByIdent[ident]; !referenced {
+			weight := 0.1
+			if isRouteTagName(ident) {
+				weight = 1
+			}
 			for relPath := range defFiles {
 				edges = append(edges, GraphEdge{
 					From:     relPath,
 					To:       relPath,
 					Ident:    ident,
-					Weight:   0.1,
+					Weight:   weight,
 					RefCount: 0,
 				})
 			}
@@ -226,6 +254,19 @@ func buildGraph(tags []treesitter.Tag, chatFiles []string, mentionedIdents []str
 		}
 	}
 
+	// Rank multipliers scale the edges into each weighted file.
+	if len(opts) > 0 && (len(opts[0].LanguageWeights) > 0 || len(opts[0].GlobWeights) > 0) {
+		weights := make(map[string]float64, len(nodeList))
+		for i := range edges {
+			w, ok := weights[edges[i].To]
+			if !ok {
+				w = opts[0].fileWeight(edges[i].To)
+				weights[edges[i].To] = w
+			}
+			edges[i].Weight *= w
+		}
+	}
+
 	sort.Slice(edges, func(i, j int) bool {
 		if edges[i].From != edges[j].From {
 			return edges[i].From < edges[j].From
@@ -245,6 +286,19 @@ func buildGraph(tags []treesitter.Tag, chatFiles []string, mentionedIdents []str
 	return &FileGraph{Nodes: nodeList, Edges: edges}
 }
 
+// normalizeLanguageWeights keys weights by tree-sitter query key, so that
+// "Go" configures the weight of "go" and "tsx" that of "typescript".
+func normalizeLanguageWeights(weights map[string]float64) map[string]float64 {
+	if len(weights) == 0 {
+		return nil
+	}
+	normalized := make(map[string]float64, len(weights))
+	for _, lang := range slices.Sorted(maps.Keys(weights)) {
+		normalized[treesitter.GetQueryKey(lang)] = weights[lang]
+	}
+	return normalized
+}
+
 func normalizeGraphRelPath(path string) string {
 	if path = strings.TrimSpace(path); path == "" {
 		return ""
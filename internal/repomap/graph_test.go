@@ -113,6 +113,20 @@ func TestBuildGraphAddsOrphanSelfEdgesWhenOtherRefsExist(t *testing.T) {
 	require.InDelta(t, 1.0, cUsed.Weight, 1e-9)
 }
 
+func TestBuildGraphWeighsRouteSelfEdgesAsReferences(t *testing.T) {
+	t.Parallel()
+
+	tags := []treesitter.Tag{
+		{RelPath: "api/routes.go", Name: "POST /api/users", Kind: "def", NodeType: "route"},
+		{RelPath: "api/routes.go", Name: "Used", Kind: "ref"},
+		{RelPath: "b.go", Name: "Used", Kind: "def"},
+	}
+
+	g := buildGraph(tags, nil, nil)
+	route := findEdge(t, g, "api/routes.go", "api/routes.go", "POST /api/users")
+	require.InDelta(t, 1.0, route.Weight, 1e-9)
+}
+
 func TestBuildGraphPathNormalization(t *testing.T) {
 	t.Parallel()
 
@@ -191,6 +205,29 @@ func TestBuildGraphDeterministicAcrossRuns(t *testing.T) {
 	}
 }
 
+func TestBuildGraphAppliesRankWeights(t *testing.T) {
+	t.Parallel()
+
+	tags := []treesitter.Tag{
+		{RelPath: "svc/handle.go", Name: "Handle", Kind: "def"},
+		{RelPath: "svc/handle_test.go", Name: "Handle", Kind: "def"},
+		{RelPath: "main.py", Name: "Handle", Kind: "ref"},
+	}
+	plain := buildGraph(tags, nil, nil)
+	base := findEdge(t, plain, "main.py", "svc/handle.go", "Handle").Weight
+	require.Equal(t, base, findEdge(t, plain, "main.py", "svc/handle_test.go", "Handle").Weight)
+
+	g := buildGraph(tags, nil, nil, BuildGraphOptions{
+		LanguageWeights: normalizeLanguageWeights(map[string]float64{"Go": 2, "python": 0}),
+		GlobWeights:     map[string]float64{"**/*_test.go": 0.25, "docs/**": 3},
+	})
+	require.InDelta(t, 2*base, findEdge(t, g, "main.py", "svc/handle.go", "Handle").Weight, 1e-9)
+	require.InDelta(t, 0.5*base, findEdge(t, g, "main.py", "svc/handle_test.go", "Handle").Weight, 1e-9)
+
+	ranked := Rank(g, nil)
+	require.Equal(t, "svc/handle.go", ranked[0].File)
+}
+
 func findEdge(t *testing.T, g *FileGraph, from, to, ident string) GraphEdge {
 	t.Helper()
 	for _, e := range g.Edges {
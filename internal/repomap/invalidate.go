@@ -0,0 +1,194 @@
+//go:build treesitter
+// +build treesitter
+
+package repomap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+)
+
+// WithFileWatcher re-indexes files as they change on disk, watching the
+// repository, or each root of a workspace, with filesystem notifications
+// (see InvalidateFiles). The watch starts with PreIndex and stops on Close.
+func WithFileWatcher() ServiceOption {
+	return func(s *Service) {
+		for _, root := range s.workspace().roots {
+			s.fileWatchers = append(s.fileWatchers, NewFileWatcher(FileWatcherConfig{
+				RootDir: root.Dir,
+				Skip:    s.skipPath(root),
+				OnChange: func(ctx context.Context, paths []string) {
+					if err := s.InvalidateFiles(ctx, paths); err != nil && !errors.Is(err, errServiceClosed) {
+						slog.Warn("Repomap watcher failed to re-index files", "files", len(paths), "error", err)
+					}
+				},
+			}))
+		}
+	}
+}
+
+// InvalidateFiles re-indexes files that changed on disk, given absolute or
+// relative to the repository root. Their tags and imports are extracted
+// again, removed files and directories leave the index and new files join
+// it, while every other file keeps its cached tags: the next Generate only
+// re-ranks. The cached maps of every session are dropped. Paths outside
+// the repository or ignored by it are skipped.
+func (s *Service) InvalidateFiles(ctx context.Context, paths []string) error {
+	if err := s.checkContextsDone(ctx); err != nil {
+		return err
+	}
+
+	ws := s.workspace()
+	var changed []string
+	for _, p := range paths {
+		if rel, err := ws.rel(p); err == nil {
+			changed = append(changed, rel)
+		}
+	}
+	changed = normalizeUniqueStrings(changed)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	s.mu.RLock()
+	indexed := s.allFiles
+	s.mu.RUnlock()
+
+	skips := make(map[string]func(string, bool) bool, len(ws.roots))
+	var updated, removed []string
+	for _, rel := range changed {
+		root, sub, _ := ws.split(rel)
+		skip, ok := skips[root.Name]
+		if !ok {
+			skip = s.skipPath(root)
+			skips[root.Name] = skip
+		}
+		st, err := os.Stat(ws.abs(rel))
+		switch {
+		case err == nil && st.Mode().IsRegular():
+			if ignored(skip, root.Dir, sub) {
+				// A file that became ignored leaves the index.
+				removed = append(removed, rel)
+			} else {
+				updated = append(updated, rel)
+			}
+		case errors.Is(err, fs.ErrNotExist):
+			// A removed directory takes its indexed files along.
+			removed = append(removed, rel)
+			prefix := rel + "/"
+			for _, f := range indexed {
+				if strings.HasPrefix(f, prefix) {
+					removed = append(removed, f)
+				}
+			}
+		}
+	}
+	removed = normalizeUniqueStrings(removed)
+	if len(updated) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	if s.db != nil && s.rawDB != nil {
+		if err := s.reindexFiles(ctx, updated, removed); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	// Before PreIndex has walked the repository there is no universe to
+	// patch; Generate walks it whole.
+	if s.allFiles != nil {
+		files := slices.DeleteFunc(slices.Clone(s.allFiles), func(f string) bool {
+			_, found := slices.BinarySearch(removed, f)
+			return found
+		})
+		for _, f := range updated {
+			if i, found := slices.BinarySearch(files, f); !found {
+				files = slices.Insert(files, i, f)
+			}
+		}
+		s.allFiles = files
+	}
+	s.mu.Unlock()
+
+	s.sessionCaches.ClearAll()
+	s.renderCaches.ClearAll()
+	slog.Debug("Repomap re-indexed changed files", "updated", len(updated), "removed", len(removed))
+	return nil
+}
+
+// reindexFiles extracts the tags and imports of updated again and drops
+// those of removed, in one transaction.
+func (s *Service) reindexFiles(ctx context.Context, updated, removed []string) error {
+	ws := s.workspace()
+	repoKey := ws.key()
+	if repoKey == "" {
+		return fmt.Errorf("repo key is empty")
+	}
+	parser := s.ensureParser()
+	if parser == nil {
+		return fmt.Errorf("tree-sitter parser is not available")
+	}
+
+	results := make([]fileParseResult, 0, len(updated)+len(removed))
+	for _, rel := range updated {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		results = append(results, s.parseFile(ctx, parser, ws, rel, true, nil))
+	}
+	for _, rel := range removed {
+		results = append(results, fileParseResult{relPath: rel, deleted: true})
+	}
+
+	tx, err := s.rawDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin repo-map re-index transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	qtx := s.db.WithTx(tx)
+	for _, r := range results {
+		s.writeParseResult(ctx, tx, qtx, repoKey, r)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit repo-map re-index transaction: %w", err)
+	}
+	return nil
+}
+
+// skipPath returns whether an absolute path under root is left out of the
+// index, following the same rules as walkAllFiles for one path at a time.
+func (s *Service) skipPath(root Root) func(path string, isDir bool) bool {
+	walker := fsext.NewFastGlobWalker(root.Dir)
+	return func(p string, isDir bool) bool {
+		if isDir {
+			return walker.ShouldSkipDir(p)
+		}
+		if walker.ShouldSkip(p) {
+			return true
+		}
+		rel, err := filepath.Rel(root.Dir, p)
+		return err == nil && s.excluded(root, filepath.ToSlash(rel))
+	}
+}
+
+// ignored reports whether rel, relative to rootDir, or any directory above
+// it, is skipped.
+func ignored(skip func(string, bool) bool, rootDir, rel string) bool {
+	for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+		if skip(filepath.Join(rootDir, filepath.FromSlash(dir)), true) {
+			return true
+		}
+	}
+	return skip(filepath.Join(rootDir, filepath.FromSlash(rel)), false)
+}
@@ -0,0 +1,169 @@
+//go:build treesitter
+// +build treesitter
+
+package repomap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/stretchr/testify/require"
+)
+
+func newInvalidateTestService(t *testing.T, opts ...ServiceOption) (*Service, *db.Queries, string) {
+	t.Helper()
+	root := t.TempDir()
+	writeGoFile(t, root, "a.go", "package p\n\nfunc Alpha() {}\n")
+	writeGoFile(t, root, "pkg/b.go", "package pkg\n\nimport \"fmt\"\n\nfunc Beta() { fmt.Println() }\n")
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	q := db.New(conn)
+
+	cfg := &config.Config{Options: &config.Options{RepoMap: &config.RepoMapOptions{}}}
+	svc := NewService(cfg, q, conn, root, t.Context(), opts...)
+	t.Cleanup(func() { _ = svc.Close() })
+
+	svc.PreIndex()
+	files := svc.AllFiles(t.Context())
+	require.Equal(t, []string{"a.go", "pkg/b.go"}, files)
+	_, _, err = svc.extractTags(t.Context(), root, files, false)
+	require.NoError(t, err)
+	return svc, q, root
+}
+
+func writeGoFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func indexedDefs(t *testing.T, q *db.Queries, repoKey string) map[string][]string {
+	t.Helper()
+	rows, err := q.ListRepoMapTags(context.Background(), repoKey)
+	require.NoError(t, err)
+	defs := map[string][]string{}
+	for _, r := range rows {
+		// Package clauses are defs too; only the functions matter here.
+		if r.Kind == "def" && unicode.IsUpper([]rune(r.Name)[0]) {
+			defs[r.RelPath] = append(defs[r.RelPath], r.Name)
+		}
+	}
+	return defs
+}
+
+func TestInvalidateFiles(t *testing.T) {
+	t.Parallel()
+
+	svc, q, root := newInvalidateTestService(t)
+	ctx := t.Context()
+	cacheRows := func() map[string]int64 {
+		rows, err := q.GetRepoMapFileCache(ctx, repoKeyForRoot(root))
+		require.NoError(t, err)
+		m := map[string]int64{}
+		for _, r := range rows {
+			m[r.RelPath] = r.Mtime
+		}
+		return m
+	}
+	before := cacheRows()
+	svc.sessionCaches.Store("sess", "map", 10)
+
+	// Same-second edits keep the mtime; the named file is parsed anyway.
+	writeGoFile(t, root, "a.go", "package p\n\nfunc Gamma() {}\n")
+	writeGoFile(t, root, "c.go", "package p\n\nfunc Delta() {}\n")
+	writeGoFile(t, root, "node_modules/d.go", "package dep\n\nfunc Ignored() {}\n")
+	require.NoError(t, svc.InvalidateFiles(ctx, []string{
+		filepath.Join(root, "a.go"), "c.go", "node_modules/d.go", "../outside.go",
+	}))
+
+	require.Equal(t, map[string][]string{
+		"a.go":     {"Gamma"},
+		"c.go":     {"Delta"},
+		"pkg/b.go": {"Beta"},
+	}, indexedDefs(t, q, repoKeyForRoot(root)))
+	require.Equal(t, before["pkg/b.go"], cacheRows()["pkg/b.go"], "unchanged files keep their cache")
+	require.Equal(t, []string{"a.go", "c.go", "pkg/b.go"}, svc.AllFiles(ctx))
+	require.Empty(t, svc.LastGoodMap("sess"))
+
+	// Removing a directory drops its files and their imports.
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "pkg")))
+	require.NoError(t, svc.InvalidateFiles(ctx, []string{"pkg"}))
+	require.Equal(t, map[string][]string{"a.go": {"Gamma"}, "c.go": {"Delta"}}, indexedDefs(t, q, repoKeyForRoot(root)))
+	require.Equal(t, []string{"a.go", "c.go"}, svc.AllFiles(ctx))
+	var imports int
+	require.NoError(t, svc.rawDB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM repo_map_imports WHERE repo_key = ?", repoKeyForRoot(root)).Scan(&imports))
+	require.Zero(t, imports)
+
+	require.NoError(t, svc.InvalidateFiles(ctx, nil))
+}
+
+func TestFileWatcherReindexesChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	svc, q, root := newInvalidateTestService(t, WithFileWatcher())
+
+	writeGoFile(t, root, "pkg/b.go", "package pkg\n\nfunc Beta2() {}\n")
+	writeGoFile(t, root, "lib/e.go", "package lib\n\nfunc Epsilon() {}\n")
+	require.Eventually(t, func() bool {
+		defs := indexedDefs(t, q, repoKeyForRoot(root))
+		return len(defs["pkg/b.go"]) == 1 && defs["pkg/b.go"][0] == "Beta2" && len(defs["lib/e.go"]) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	require.Contains(t, svc.AllFiles(t.Context()), "lib/e.go")
+}
+
+func TestInvalidateFilesMultiRoot(t *testing.T) {
+	t.Parallel()
+
+	workspaceDir, shared := t.TempDir(), t.TempDir()
+	writeGoFile(t, workspaceDir, "services/api/main.go", "package main\n\nfunc Serve() {}\n")
+	writeGoFile(t, workspaceDir, "services/api/gen/types.go", "package gen\n\nfunc Generated() {}\n")
+	writeGoFile(t, shared, "util.go", "package shared\n\nfunc Helper() {}\n")
+
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	q := db.New(conn)
+
+	cfg := &config.Config{Options: &config.Options{RepoMap: &config.RepoMapOptions{}}}
+	svc, err := NewServiceMultiRoot(cfg, q, conn, workspaceDir, []Root{
+		{Dir: "services/api", ExcludeGlobs: []string{"gen/**"}},
+		{Name: "lib", Dir: shared},
+	}, t.Context())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = svc.Close() })
+
+	svc.PreIndex()
+	files := svc.AllFiles(t.Context())
+	require.Equal(t, []string{"api/main.go", "lib/util.go"}, files)
+	_, _, err = svc.extractTags(t.Context(), workspaceDir, files, false)
+	require.NoError(t, err)
+	repoKey := svc.workspace().key()
+	require.NotEqual(t, repoKeyForRoot(workspaceDir), repoKey)
+	require.Equal(t, map[string][]string{
+		"api/main.go": {"Serve"},
+		"lib/util.go": {"Helper"},
+	}, indexedDefs(t, q, repoKey))
+
+	writeGoFile(t, shared, "util.go", "package shared\n\nfunc Helper2() {}\n")
+	require.NoError(t, svc.InvalidateFiles(t.Context(), []string{
+		filepath.Join(shared, "util.go"),
+		filepath.Join(workspaceDir, "services", "api", "gen", "types.go"),
+	}))
+	require.Equal(t, map[string][]string{
+		"api/main.go": {"Serve"},
+		"lib/util.go": {"Helper2"},
+	}, indexedDefs(t, q, repoKey))
+
+	_, err = NewServiceMultiRoot(cfg, q, conn, workspaceDir, []Root{{Dir: "a/lib"}, {Dir: "b/lib"}}, t.Context())
+	require.Error(t, err, "duplicate root names")
+}
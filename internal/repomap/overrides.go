@@ -0,0 +1,140 @@
+package repomap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ErrUnavailable is returned by override and ranking operations when no
+// repo-map service is running, e.g. in builds without tree-sitter.
+var ErrUnavailable = errors.New("repomap: service unavailable")
+
+// OverrideKind is the kind of a per-session repo-map override.
+type OverrideKind string
+
+const (
+	// OverridePin keeps matching entries at the front of the map so they
+	// survive budget trimming.
+	OverridePin OverrideKind = "pin"
+	// OverrideBlacklist removes matching entries from the map.
+	OverrideBlacklist OverrideKind = "blacklist"
+)
+
+// Override pins or blacklists repo-map entries for one session.
+//
+// Target is a repo-relative path or doublestar glob ("gen/**/*.pb.go"),
+// optionally followed by "#Symbol" to match a single ranked definition.
+// A bare "#Symbol" matches the definition in any file. A trailing slash
+// matches everything below a directory.
+type Override struct {
+	Kind   OverrideKind
+	Target string
+}
+
+// ParseOverride validates and normalizes a user-supplied override target.
+func ParseOverride(kind OverrideKind, target string) (Override, error) {
+	if kind != OverridePin && kind != OverrideBlacklist {
+		return Override{}, fmt.Errorf("unknown repo map override kind %q", kind)
+	}
+	pattern, ident, _ := strings.Cut(strings.TrimSpace(target), "#")
+	ident = strings.TrimSpace(ident)
+	dir := strings.HasSuffix(pattern, "/")
+	pattern = normalizeGraphRelPath(pattern)
+	if dir && pattern != "" {
+		pattern += "/**"
+	}
+	if pattern == "" && ident == "" {
+		return Override{}, fmt.Errorf("repo map override target is empty")
+	}
+	if pattern != "" && !doublestar.ValidatePattern(pattern) {
+		return Override{}, fmt.Errorf("invalid repo map override pattern %q", pattern)
+	}
+	if ident != "" {
+		pattern += "#" + ident
+	}
+	return Override{Kind: kind, Target: pattern}, nil
+}
+
+// matches reports whether the override applies to entry e.
+func (o Override) matches(e StageEntry) bool {
+	pattern, ident, _ := strings.Cut(o.Target, "#")
+	if ident != "" && e.Ident != ident {
+		return false
+	}
+	if pattern == "" {
+		return true
+	}
+	ok, err := doublestar.Match(pattern, e.File)
+	return err == nil && ok
+}
+
+// literalFile returns the file named by a pin that uses neither a glob nor a
+// symbol, or "" otherwise.
+func (o Override) literalFile() string {
+	if strings.Contains(o.Target, "#") || strings.ContainsAny(o.Target, "*?[{") {
+		return ""
+	}
+	return o.Target
+}
+
+// ApplyOverrides applies session overrides to assembled stage entries ahead
+// of FitToBudget. Blacklisted entries are dropped; pinned entries move to the
+// front in their original order, so the prefix-based budget fit keeps them
+// first. A pin wins over a blacklist that matches the same entry. Literal
+// file pins that matched nothing are appended to the pinned prefix as bare
+// filenames.
+func ApplyOverrides(entries []StageEntry, overrides []Override) []StageEntry {
+	if len(overrides) == 0 {
+		return entries
+	}
+
+	var pins, blacklist []Override
+	for _, o := range overrides {
+		switch o.Kind {
+		case OverridePin:
+			pins = append(pins, o)
+		case OverrideBlacklist:
+			blacklist = append(blacklist, o)
+		}
+	}
+
+	matchesAny := func(list []Override, e StageEntry) bool {
+		for _, o := range list {
+			if o.matches(e) {
+				return true
+			}
+		}
+		return false
+	}
+
+	pinned := make([]StageEntry, 0, len(pins))
+	rest := make([]StageEntry, 0, len(entries))
+	pinnedFiles := make(map[string]struct{})
+	for _, e := range entries {
+		switch {
+		case matchesAny(pins, e):
+			pinned = append(pinned, e)
+			pinnedFiles[e.File] = struct{}{}
+		case matchesAny(blacklist, e):
+		default:
+			rest = append(rest, e)
+		}
+	}
+
+	for _, o := range pins {
+		file := o.literalFile()
+		if file == "" {
+			continue
+		}
+		if _, ok := pinnedFiles[file]; ok {
+			continue
+		}
+		pinnedFiles[file] = struct{}{}
+		pinned = append(pinned, StageEntry{Stage: stageRemainingFiles, File: file})
+	}
+
+	return append(pinned, rest...)
+}
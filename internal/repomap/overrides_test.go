@@ -0,0 +1,78 @@
+package repomap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOverride(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		target string
+		want   string
+	}{
+		{target: " ./internal/app/app.go ", want: "internal/app/app.go"},
+		{target: "gen/", want: "gen/**"},
+		{target: "internal/**/*.pb.go", want: "internal/**/*.pb.go"},
+		{target: "internal/app/app.go#New", want: "internal/app/app.go#New"},
+		{target: "#Coordinator", want: "#Coordinator"},
+	} {
+		o, err := ParseOverride(OverridePin, tt.target)
+		require.NoError(t, err, tt.target)
+		require.Equal(t, tt.want, o.Target)
+	}
+
+	_, err := ParseOverride(OverridePin, "  ")
+	require.Error(t, err)
+	_, err = ParseOverride(OverrideBlacklist, "gen/[")
+	require.Error(t, err)
+	_, err = ParseOverride("hide", "a.go")
+	require.Error(t, err)
+}
+
+func TestApplyOverrides(t *testing.T) {
+	t.Parallel()
+
+	entries := []StageEntry{
+		{Stage: stageSpecialPrelude, File: "README.md"},
+		{Stage: stageRankedDefs, File: "a.go", Ident: "A"},
+		{Stage: stageRankedDefs, File: "gen/api.pb.go", Ident: "Request"},
+		{Stage: stageRankedDefs, File: "b.go", Ident: "B"},
+		{Stage: stageRankedDefs, File: "b.go", Ident: "Helper"},
+		{Stage: stageGraphNodes, File: "gen/types.pb.go"},
+		{Stage: stageRemainingFiles, File: "d.go"},
+	}
+
+	got := ApplyOverrides(entries, []Override{
+		{Kind: OverrideBlacklist, Target: "gen/**"},
+		{Kind: OverridePin, Target: "#Helper"},
+		{Kind: OverridePin, Target: "d.go"},
+		{Kind: OverridePin, Target: "gen/types.pb.go"},
+		{Kind: OverridePin, Target: "docs/design.md"},
+		{Kind: OverrideBlacklist, Target: "README.md"},
+	})
+	require.Equal(t, []StageEntry{
+		{Stage: stageRankedDefs, File: "b.go", Ident: "Helper"},
+		{Stage: stageGraphNodes, File: "gen/types.pb.go"},
+		{Stage: stageRemainingFiles, File: "d.go"},
+		{Stage: stageRemainingFiles, File: "docs/design.md"},
+		{Stage: stageRankedDefs, File: "a.go", Ident: "A"},
+		{Stage: stageRankedDefs, File: "b.go", Ident: "B"},
+	}, got)
+
+	require.Equal(t, entries, ApplyOverrides(entries, nil))
+}
+
+func TestApplyOverridesSymbolBlacklist(t *testing.T) {
+	t.Parallel()
+
+	entries := []StageEntry{
+		{Stage: stageRankedDefs, File: "a.go", Ident: "String"},
+		{Stage: stageRankedDefs, File: "a.go", Ident: "Parse"},
+		{Stage: stageRankedDefs, File: "b.go", Ident: "String"},
+	}
+	got := ApplyOverrides(entries, []Override{{Kind: OverrideBlacklist, Target: "a.go#String"}})
+	require.Equal(t, entries[1:], got)
+}
@@ -0,0 +1,119 @@
+//go:build treesitter
+// +build treesitter
+
+package repomap
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPersistSessionArtifactsSkipsUnchanged verifies that identical
+// rankings are written once, changed rankings replace the stored rows and
+// Reset forgets the persisted hash.
+func TestPersistSessionArtifactsSkipsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn, err := db.Connect(ctx, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	q := db.New(conn)
+
+	const sessionID = "sess-persist"
+	_, err = q.CreateSession(ctx, db.CreateSessionParams{ID: sessionID, Title: "persist"})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	svc := NewService(nil, q, conn, dir, ctx)
+	defer svc.Close()
+	repoKey := repoKeyForRoot(dir)
+	require.NotEmpty(t, repoKey)
+	artifactsKey := repoKey + "\x00" + sessionID
+
+	listRankings := func() []db.RepoMapSessionRanking {
+		rows, err := q.ListSessionRankings(ctx, db.ListSessionRankingsParams{RepoKey: repoKey, SessionID: sessionID})
+		require.NoError(t, err)
+		return rows
+	}
+
+	ranked := []RankedFile{{Path: "a.go", Rank: 0.6}, {Path: "b.go", Rank: 0.4}}
+	svc.persistSessionArtifacts(ctx, sessionID, repoKey, ranked, []string{"c.go"})
+	require.Len(t, listRankings(), 2)
+	first, ok := svc.persistedArtifacts.Load(artifactsKey)
+	require.True(t, ok)
+
+	// Remove a row behind the service's back: an unchanged generation must
+	// not rewrite it.
+	_, err = conn.ExecContext(ctx, "DELETE FROM repo_map_session_rankings WHERE rel_path = 'b.go'")
+	require.NoError(t, err)
+	svc.persistSessionArtifacts(ctx, sessionID, repoKey, ranked, []string{"c.go"})
+	require.Len(t, listRankings(), 1)
+
+	// A changed generation replaces every row.
+	ranked = []RankedFile{{Path: "a.go", Rank: 0.3}, {Path: "b.go", Rank: 0.5}, {Path: "d.go", Rank: 0.2}}
+	svc.persistSessionArtifacts(ctx, sessionID, repoKey, ranked, nil)
+	require.Len(t, listRankings(), 3)
+	readOnly, err := q.ListSessionReadOnlyPaths(ctx, db.ListSessionReadOnlyPathsParams{RepoKey: repoKey, SessionID: sessionID})
+	require.NoError(t, err)
+	require.Empty(t, readOnly)
+	second, ok := svc.persistedArtifacts.Load(artifactsKey)
+	require.True(t, ok)
+	require.NotEqual(t, first.([sha256.Size]byte), second.([sha256.Size]byte))
+
+	require.NoError(t, svc.Reset(ctx, sessionID))
+	_, ok = svc.persistedArtifacts.Load(artifactsKey)
+	require.False(t, ok)
+	require.Empty(t, listRankings())
+}
+
+func TestTopFilesAndFileRank(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn, err := db.Connect(ctx, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	q := db.New(conn)
+
+	const sessionID = "sess-rank"
+	_, err = q.CreateSession(ctx, db.CreateSessionParams{ID: sessionID, Title: "rank"})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	svc := NewService(nil, q, conn, dir, ctx)
+	defer svc.Close()
+
+	top, err := svc.TopFiles(ctx, sessionID, 2)
+	require.NoError(t, err)
+	require.Empty(t, top, "no map generated yet")
+
+	svc.persistSessionArtifacts(ctx, sessionID, repoKeyForRoot(dir), []RankedFile{
+		{Path: "c.go", Rank: 0.2}, {Path: "a.go", Rank: 0.4}, {Path: "b.go", Rank: 0.4},
+	}, nil)
+
+	top, err = svc.TopFiles(ctx, sessionID, 2)
+	require.NoError(t, err)
+	require.Equal(t, []RankedFile{{Path: "a.go", Rank: 0.4}, {Path: "b.go", Rank: 0.4}}, top)
+	all, err := svc.TopFiles(ctx, sessionID, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	rank, ok, err := svc.FileRank(ctx, sessionID, "./c.go")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.InDelta(t, 0.2, rank, 1e-9)
+	_, ok, err = svc.FileRank(ctx, sessionID, "missing.go")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = svc.TopFiles(ctx, "", 1)
+	require.Error(t, err)
+	var unavailable *Service
+	_, _, err = unavailable.FileRank(ctx, sessionID, "a.go")
+	require.ErrorIs(t, err, ErrUnavailable)
+}
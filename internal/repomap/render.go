@@ -27,6 +27,19 @@ func RenderRepoMap(
 	tags map[string][]treesitter.Tag,
 	parser treesitter.Parser,
 	rootDir string,
+) (string, error) {
+	return renderRepoMap(ctx, entries, tags, parser, func(file string) string {
+		return filepath.Join(rootDir, filepath.FromSlash(file))
+	})
+}
+
+// renderRepoMap is RenderRepoMap reading each file from absPath(file).
+func renderRepoMap(
+	ctx context.Context,
+	entries []StageEntry,
+	tags map[string][]treesitter.Tag,
+	parser treesitter.Parser,
+	absPath func(file string) string,
 ) (string, error) {
 	if len(entries) == 0 {
 		return "", nil
@@ -70,7 +83,7 @@ func RenderRepoMap(
 		}
 
 		if hasStage1 {
-			rendered := renderStage1File(ctx, g.file, g.entries, tags, parser, rootDir, contentCache)
+			rendered := renderStage1File(ctx, g.file, g.entries, tags, parser, absPath, contentCache)
 			out.WriteString(rendered)
 			// Release cached content after rendering.
 			delete(contentCache, g.file)
@@ -92,7 +105,7 @@ func renderStage1File(
 	fileEntries []StageEntry,
 	tags map[string][]treesitter.Tag,
 	parser treesitter.Parser,
-	rootDir string,
+	absPath func(file string) string,
 	contentCache map[string][]byte,
 ) string {
 	// Collect only stage-1 entries for LOI computation.
@@ -113,8 +126,7 @@ func renderStage1File(
 	// Read file content (cached).
 	content, ok := contentCache[file]
 	if !ok {
-		absPath := filepath.Join(rootDir, filepath.FromSlash(file))
-		data, err := os.ReadFile(absPath)
+		data, err := os.ReadFile(absPath(file))
 		if err != nil {
 			// File cannot be read: fall back to flat format.
 			return renderFlatDefs(file, stage1Entries)
@@ -5,7 +5,9 @@ package repomap
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -14,6 +16,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,6 +27,8 @@ import (
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/db"
 	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/techdebt"
 	"github.com/charmbracelet/crush/internal/treesitter"
 	"golang.org/x/sync/singleflight"
 )
@@ -100,6 +105,13 @@ func WithProximityScorer() ServiceOption {
 	}
 }
 
+// WithRefreshPublisher publishes a RefreshEvent after every refresh.
+func WithRefreshPublisher(pub pubsub.Publisher[RefreshEvent]) ServiceOption {
+	return func(s *Service) {
+		s.refreshPub = pub
+	}
+}
+
 // Service handles repo-map generation and lifecycle.
 type Service struct {
 	parser           treesitter.Parser
@@ -107,18 +119,21 @@ type Service struct {
 	db               *db.Queries
 	rawDB            *sql.DB
 	rootDir          string
-	cfg              *config.RepoMapOptions
-	lifecycleCtx     context.Context
-	serviceCtx       context.Context
-	cancel           context.CancelFunc
-	closed           chan struct{}
+	// roots are the named roots of a multi-root workspace; nil when the
+	// service indexes rootDir alone.
+	roots        []Root
+	cfg          *config.RepoMapOptions
+	lifecycleCtx context.Context
+	serviceCtx   context.Context
+	cancel       context.CancelFunc
+	closed       chan struct{}
 
 	wg sync.WaitGroup
 
 	mu                   sync.RWMutex
 	sessionCaches        *SessionCacheSet
 	renderCaches         *SessionRenderCacheSet
-	injectedBySessionRun map[string]map[RunInjectionKey]struct{}
+	injectedBySessionRun map[string][]RunInjectionKey // oldest run first
 	allFiles             []string
 	preIndexDone         chan struct{}
 	preIndexRunning      bool
@@ -129,18 +144,61 @@ type Service struct {
 
 	// Optional features (fork).
 	diffWatcher      *DiffWatcher
+	fileWatchers     []*FileWatcher
 	proximityEnabled bool
+	refreshPub       pubsub.Publisher[RefreshEvent]
+
+	// queryStamps hashes the repository's own tags queries by language.
+	// They are mixed into the file cache mtimes so that editing a query
+	// re-parses the files it applies to. Set with parser.
+	queryStamps map[string]int64
+
+	// schema caches the database schema prelude (see schema.go).
+	schema schemaPrelude
+	// flags caches the feature flag prelude (see flags.go).
+	flags flagPrelude
+	// techDebt is the TODO/FIXME inventory refreshed by PreIndex; nil
+	// without a data directory.
+	techDebt *techdebt.Inventory
 
 	disabledSessions sync.Map // one-way disable latch per session
 
+	// persistedArtifacts holds the hash of the rankings last persisted per
+	// repo key and session, so unchanged generations skip the rewrite.
+	persistedArtifacts sync.Map
+
+	// sessionActivity holds when each session with in-memory state was
+	// last active, so idle sessions can be evicted (see session_gc.go).
+	// Guarded by mu.
+	sessionActivity map[string]time.Time
+	lastSweep       time.Time
+	now             func() time.Time
+
 	closeOnce sync.Once
 }
 
 // NewService creates a new repo-map service scaffold.
 func NewService(cfg *config.Config, q *db.Queries, rawDB *sql.DB, rootDir string, lifecycleCtx context.Context, opts ...ServiceOption) *Service {
+	return newService(cfg, q, rawDB, rootDir, nil, lifecycleCtx, opts...)
+}
+
+// NewServiceMultiRoot creates a repo-map service over the roots of a
+// workspace at rootDir, such as the projects of a monorepo. Files of every
+// root are ranked in one graph and named "root/rel/path" in the map.
+func NewServiceMultiRoot(cfg *config.Config, q *db.Queries, rawDB *sql.DB, rootDir string, roots []Root, lifecycleCtx context.Context, opts ...ServiceOption) (*Service, error) {
+	resolved, err := resolveRoots(rootDir, roots)
+	if err != nil {
+		return nil, err
+	}
+	return newService(cfg, q, rawDB, rootDir, resolved, lifecycleCtx, opts...), nil
+}
+
+func newService(cfg *config.Config, q *db.Queries, rawDB *sql.DB, rootDir string, roots []Root, lifecycleCtx context.Context, opts ...ServiceOption) *Service {
 	var repoCfg *config.RepoMapOptions
+	var flagOpts *config.FeatureFlagOptions
 	if cfg != nil && cfg.Options != nil {
 		repoCfg = cfg.Options.RepoMap
+		flagOpts = cfg.Options.FeatureFlags
 	}
 
 	baseCtx := lifecycleCtx
@@ -157,6 +215,7 @@ func NewService(cfg *config.Config, q *db.Queries, rawDB *sql.DB, rootDir string
 		db:                   q,
 		rawDB:                rawDB,
 		rootDir:              rootDir,
+		roots:                roots,
 		cfg:                  repoCfg,
 		lifecycleCtx:         lifecycleCtx,
 		serviceCtx:           serviceCtx,
@@ -164,8 +223,15 @@ func NewService(cfg *config.Config, q *db.Queries, rawDB *sql.DB, rootDir string
 		closed:               make(chan struct{}),
 		sessionCaches:        NewSessionCacheSet(),
 		renderCaches:         NewSessionRenderCacheSet(),
-		injectedBySessionRun: make(map[string]map[RunInjectionKey]struct{}),
+		injectedBySessionRun: make(map[string][]RunInjectionKey),
 		preIndexDone:         preIndexDone,
+		sessionActivity:      make(map[string]time.Time),
+		now:                  time.Now,
+		flags:                flagPrelude{opts: flagOpts},
+	}
+
+	if cfg != nil && cfg.Options != nil && cfg.Options.DataDirectory != "" {
+		svc.techDebt = techdebt.New(rootDir, cfg.Options.DataDirectory)
 	}
 
 	for _, opt := range opts {
@@ -175,6 +241,20 @@ func NewService(cfg *config.Config, q *db.Queries, rawDB *sql.DB, rootDir string
 	return svc
 }
 
+// workspace returns the roots the service indexes.
+func (s *Service) workspace() workspace {
+	return s.workspaceAt(s.rootDir)
+}
+
+// workspaceAt returns the roots the service indexes, with rootDir as the
+// repository of a single-root service.
+func (s *Service) workspaceAt(rootDir string) workspace {
+	if len(s.roots) > 0 {
+		return workspace{dir: s.rootDir, roots: s.roots}
+	}
+	return singleRoot(rootDir)
+}
+
 // Generate produces a repo map.
 func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int, error) {
 	if err := s.checkContextsDone(ctx); err != nil {
@@ -188,6 +268,7 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 	}
 
 	mode := s.effectiveRefreshMode(opts)
+	s.touchSession(sessionID)
 
 	lastMap, lastTok := s.sessionCaches.Load(sessionID)
 	cacheKey := buildRenderCacheKey(mode, opts)
@@ -297,6 +378,10 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 		}
 		return fallback(err)
 	}
+	if opts.ParityMode {
+		// Aider has no route tags.
+		tags = slices.DeleteFunc(tags, func(tag treesitter.Tag) bool { return tag.NodeType == routeNodeType })
+	}
 	slog.Info("Repomap Generate: extractTags completed",
 		"session_id", sessionID,
 		"tag_count", len(tags),
@@ -309,11 +394,17 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 		tagsByFile[tag.RelPath] = append(tagsByFile[tag.RelPath], tag)
 	}
 
-	graph := buildGraph(tags, opts.ChatFiles, opts.MentionedIdents, BuildGraphOptions{Imports: importEdges})
+	// Rank weights are a fork setting; parity mode ranks as Aider does.
+	graphOpts := BuildGraphOptions{Imports: importEdges}
+	if s.cfg != nil && !opts.ParityMode {
+		graphOpts.LanguageWeights = normalizeLanguageWeights(s.cfg.LanguageWeights)
+		graphOpts.GlobWeights = s.cfg.GlobWeights
+	}
+	graph := buildGraph(tags, opts.ChatFiles, opts.MentionedIdents, graphOpts)
 	personalization := BuildPersonalization(fileUniverse, opts.ChatFiles, opts.MentionedFnames, opts.MentionedIdents)
 
 	if opts.WithBlameInfo && personalization != nil {
-		blameInfo, _ := GetBlameInfo(ctx, s.rootDir, fileUniverse)
+		blameInfo := s.blameInfo(ctx, fileUniverse)
 		personalization = BlendBlamePersonalization(
 			personalization,
 			blameInfo,
@@ -347,6 +438,7 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 		opts.ChatFiles,
 		opts.ParityMode,
 	)
+	entries = ApplyOverrides(entries, s.SessionOverrides(ctx, sessionID))
 
 	// Parity mode requires tokenizer-backed counting; fail hard if unavailable.
 	if opts.ParityMode && opts.TokenCounter == nil {
@@ -363,6 +455,18 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 		Model:        opts.Model,
 		LanguageHint: "default",
 	}
+	// The database schema and feature flag preludes take their share of
+	// the budget first. In a multi-root workspace they cover the roots
+	// inside the workspace directory.
+	var schemaSection, flagSection string
+	var schemaTokens, flagTokens int
+	if !opts.ParityMode {
+		localFiles := s.workspace().local(fileUniverse)
+		schemaSection, schemaTokens = fitPrelude(s.schema.Section(s.rootDir, localFiles), budgetProfile.TokenBudget)
+		budgetProfile.TokenBudget -= schemaTokens
+		flagSection, flagTokens = fitPrelude(s.flags.Section(ctx, s.rootDir, localFiles), budgetProfile.TokenBudget)
+		budgetProfile.TokenBudget -= flagTokens
+	}
 	originalBudget := budgetProfile.TokenBudget
 	budgetProfile.TokenBudget = max(budgetProfile.TokenBudget/scopeExpansionFactor, 1)
 
@@ -383,9 +487,9 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 	counter := opts.TokenCounter
 	model := opts.Model
 	parser := s.ensureParser()
-	rootDir := s.rootDir
+	absPath := s.workspace().abs
 
-	mapText, renderErr := RenderRepoMap(ctx, fit.Entries, tagsByFile, parser, rootDir)
+	mapText, renderErr := renderRepoMap(ctx, fit.Entries, tagsByFile, parser, absPath)
 	if renderErr != nil {
 		if errors.Is(renderErr, context.DeadlineExceeded) && opts.ParityMode {
 			slog.Warn("Disabling repo map for session — render timed out",
@@ -413,7 +517,7 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 		for lo < hi {
 			mid := (lo + hi + 1) / 2
 			candidate := fit.Entries[:mid]
-			text, trimRenderErr := RenderRepoMap(ctx, candidate, tagsByFile, parser, rootDir)
+			text, trimRenderErr := renderRepoMap(ctx, candidate, tagsByFile, parser, absPath)
 			if trimRenderErr != nil {
 				break // Context cancelled.
 			}
@@ -430,7 +534,7 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 				"budget", originalBudget)
 		}
 		fit.Entries = fit.Entries[:lo]
-		mapText, _ = RenderRepoMap(ctx, fit.Entries, tagsByFile, parser, rootDir)
+		mapText, _ = renderRepoMap(ctx, fit.Entries, tagsByFile, parser, absPath)
 		_, tokenCount = fitsWithinBudget(mapText)
 	}
 
@@ -448,12 +552,21 @@ func (s *Service) Generate(ctx context.Context, opts GenerateOpts) (string, int,
 		}
 	}
 
+	if flagSection != "" {
+		mapText = flagSection + "\n" + mapText
+		tokenCount += flagTokens
+	}
+	if schemaSection != "" {
+		mapText = schemaSection + "\n" + mapText
+		tokenCount += schemaTokens
+	}
+
 	s.sessionCaches.Store(sessionID, mapText, tokenCount)
 	if cacheKey != "" {
 		renderCache.Set(cacheKey, mapText, tokenCount)
 	}
 
-	repoKey := repoKeyForRoot(s.rootDir)
+	repoKey := s.workspace().key()
 	readOnly := append(append([]string(nil), opts.ChatFiles...), opts.MentionedFnames...)
 	s.persistSessionArtifacts(ctx, sessionID, repoKey, rankedFiles, readOnly)
 
@@ -516,7 +629,7 @@ func (s *Service) SessionReadOnlyFiles(ctx context.Context, sessionID string) []
 	if sessionID == "" {
 		return nil
 	}
-	repoKey := repoKeyForRoot(s.rootDir)
+	repoKey := s.workspace().key()
 	if repoKey == "" {
 		return nil
 	}
@@ -533,6 +646,89 @@ func (s *Service) SessionReadOnlyFiles(ctx context.Context, sessionID string) []
 	return normalizeUniqueGraphPaths(paths)
 }
 
+// SessionOverrides returns the persisted pin and blacklist overrides for a
+// session, oldest first.
+func (s *Service) SessionOverrides(ctx context.Context, sessionID string) []Override {
+	if s == nil || s.isClosed() || s.db == nil {
+		return nil
+	}
+	sessionID = strings.TrimSpace(sessionID)
+	repoKey := s.workspace().key()
+	if sessionID == "" || repoKey == "" {
+		return nil
+	}
+	rows, err := s.db.ListSessionOverrides(ctx, db.ListSessionOverridesParams{
+		RepoKey:   repoKey,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil
+	}
+	overrides := make([]Override, 0, len(rows))
+	for _, r := range rows {
+		overrides = append(overrides, Override{Kind: OverrideKind(r.Kind), Target: r.Target})
+	}
+	return overrides
+}
+
+// SetOverride persists a pin or blacklist override for a session and drops
+// the session's cached maps so the next Generate applies it. Setting a target
+// that is already overridden replaces its kind.
+func (s *Service) SetOverride(ctx context.Context, sessionID string, o Override) error {
+	repoKey, err := s.overrideRepoKey(sessionID)
+	if err != nil {
+		return err
+	}
+	if file := o.literalFile(); file != "" && o.Kind == OverridePin {
+		if _, err := os.Stat(s.workspace().abs(file)); err != nil {
+			return fmt.Errorf("pin repo map entry %q: %w", file, err)
+		}
+	}
+	if err := s.db.UpsertSessionOverride(ctx, db.UpsertSessionOverrideParams{
+		RepoKey:   repoKey,
+		SessionID: sessionID,
+		Target:    o.Target,
+		Kind:      string(o.Kind),
+		CreatedAt: time.Now().UnixMilli(),
+	}); err != nil {
+		return err
+	}
+	s.sessionCaches.Clear(sessionID)
+	s.renderCaches.Clear(sessionID)
+	return nil
+}
+
+// ClearOverrides removes one override target for a session, or all of them
+// when target is empty.
+func (s *Service) ClearOverrides(ctx context.Context, sessionID, target string) error {
+	repoKey, err := s.overrideRepoKey(sessionID)
+	if err != nil {
+		return err
+	}
+	if target == "" {
+		err = s.db.DeleteSessionOverrides(ctx, db.DeleteSessionOverridesParams{RepoKey: repoKey, SessionID: sessionID})
+	} else {
+		err = s.db.DeleteSessionOverride(ctx, db.DeleteSessionOverrideParams{RepoKey: repoKey, SessionID: sessionID, Target: target})
+	}
+	if err != nil {
+		return err
+	}
+	s.sessionCaches.Clear(sessionID)
+	s.renderCaches.Clear(sessionID)
+	return nil
+}
+
+func (s *Service) overrideRepoKey(sessionID string) (string, error) {
+	if s == nil || s.isClosed() || s.db == nil {
+		return "", ErrUnavailable
+	}
+	repoKey := s.workspace().key()
+	if strings.TrimSpace(sessionID) == "" || repoKey == "" {
+		return "", errors.New("repo map overrides require a session and repository")
+	}
+	return repoKey, nil
+}
+
 // ShouldInject reports whether map should be injected for this run.
 func (s *Service) ShouldInject(sessionID string, runKey RunInjectionKey) bool {
 	if sessionID == "" || runKey.RootUserMessageID == "" {
@@ -544,13 +740,10 @@ func (s *Service) ShouldInject(sessionID string, runKey RunInjectionKey) bool {
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.touchSessionLocked(sessionID)
 
-	runs, ok := s.injectedBySessionRun[sessionID]
-	if !ok {
-		runs = make(map[RunInjectionKey]struct{})
-		s.injectedBySessionRun[sessionID] = runs
-	}
-	if _, exists := runs[runKey]; exists {
+	runs := s.injectedBySessionRun[sessionID]
+	if slices.Contains(runs, runKey) {
 		return false
 	}
 	// Only mark as injected if a cached map exists. If the map hasn't
@@ -560,7 +753,13 @@ func (s *Service) ShouldInject(sessionID string, runKey RunInjectionKey) bool {
 	if s.LastTokenCount(sessionID) <= 0 {
 		return false
 	}
-	runs[runKey] = struct{}{}
+	// Only the latest runs can still be preparing steps; older records
+	// would otherwise pile up for as long as the session lives.
+	runs = append(runs, runKey)
+	if len(runs) > maxRunsPerSession {
+		runs = slices.Delete(runs, 0, len(runs)-maxRunsPerSession)
+	}
+	s.injectedBySessionRun[sessionID] = runs
 	return true
 }
 
@@ -574,13 +773,27 @@ func (s *Service) ClearInjection(sessionID string, runKey RunInjectionKey) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if runs, ok := s.injectedBySessionRun[sessionID]; ok {
-		delete(runs, runKey)
+		runs = slices.DeleteFunc(runs, func(k RunInjectionKey) bool { return k == runKey })
 		if len(runs) == 0 {
 			delete(s.injectedBySessionRun, sessionID)
+		} else {
+			s.injectedBySessionRun[sessionID] = runs
 		}
 	}
 }
 
+// ForgetInjections drops the injection records of every run of a session.
+// Used when another process takes the session over: its runs are no longer
+// this process's to track, and a later run here must re-evaluate injection.
+func (s *Service) ForgetInjections(sessionID string) {
+	if s == nil || sessionID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.injectedBySessionRun, sessionID)
+}
+
 // RefreshAsync schedules async refresh.
 func (s *Service) RefreshAsync(sessionID string, opts GenerateOpts) {
 	if s == nil || s.isClosed() {
@@ -636,7 +849,9 @@ func (s *Service) Refresh(ctx context.Context, sessionID string, opts GenerateOp
 		s.renderCaches.Clear(opts.SessionID)
 	}
 
+	start := time.Now()
 	m, tok, err := s.Generate(ctx, opts)
+	s.publishRefresh(opts, tok, time.Since(start), err)
 	if err != nil {
 		return "", 0, err
 	}
@@ -656,6 +871,24 @@ func (s *Service) Refresh(ctx context.Context, sessionID string, opts GenerateOp
 	return m, tok, nil
 }
 
+func (s *Service) publishRefresh(opts GenerateOpts, tokens int, d time.Duration, err error) {
+	if s.refreshPub == nil || opts.SessionID == "" {
+		return
+	}
+	ev := RefreshEvent{
+		Type:      RefreshCompleted,
+		SessionID: opts.SessionID,
+		Tokens:    tokens,
+		Forced:    opts.ForceRefresh,
+		Duration:  d,
+	}
+	if err != nil {
+		ev.Type = RefreshFailed
+		ev.Error = err.Error()
+	}
+	s.refreshPub.Publish(pubsub.CreatedEvent, ev)
+}
+
 // Reset clears cached repo-map state for a session.
 func (s *Service) Reset(ctx context.Context, sessionID string) error {
 	if err := s.checkContextsDone(ctx); err != nil {
@@ -666,10 +899,11 @@ func (s *Service) Reset(ctx context.Context, sessionID string) error {
 	s.renderCaches.Clear(sessionID)
 	s.disabledSessions.Delete(sessionID)
 
-	repoKey := repoKeyForRoot(s.rootDir)
+	repoKey := s.workspace().key()
 	if repoKey != "" && s.db != nil {
 		_ = s.db.DeleteSessionRankings(ctx, db.DeleteSessionRankingsParams{RepoKey: repoKey, SessionID: sessionID})
 		_ = s.db.DeleteSessionReadOnlyPaths(ctx, db.DeleteSessionReadOnlyPathsParams{RepoKey: repoKey, SessionID: sessionID})
+		s.persistedArtifacts.Delete(repoKey + "\x00" + sessionID)
 	}
 
 	s.mu.Lock()
@@ -684,7 +918,7 @@ func (s *Service) FileScores(ctx context.Context, sessionID string) map[string]f
 	if s == nil || s.db == nil || s.isClosed() {
 		return nil
 	}
-	repoKey := repoKeyForRoot(s.rootDir)
+	repoKey := s.workspace().key()
 	if repoKey == "" {
 		return nil
 	}
@@ -702,6 +936,68 @@ func (s *Service) FileScores(ctx context.Context, sessionID string) map[string]f
 	return scores
 }
 
+// TopFiles returns the n highest ranked files of the session's last
+// generated map, best first, or all of them when n <= 0. Only file ranks
+// are persisted, so the results carry no Defs. A session without a map
+// yet has no ranked files.
+func (s *Service) TopFiles(ctx context.Context, sessionID string, n int) ([]RankedFile, error) {
+	files, err := s.sessionRankedFiles(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && len(files) > n {
+		files = files[:n]
+	}
+	return files, nil
+}
+
+// FileRank returns the rank of a repo-relative path in the session's last
+// generated map, and whether the map ranked it at all.
+func (s *Service) FileRank(ctx context.Context, sessionID, path string) (float64, bool, error) {
+	files, err := s.sessionRankedFiles(ctx, sessionID)
+	if err != nil {
+		return 0, false, err
+	}
+	rel := normalizeGraphRelPath(path)
+	for _, f := range files {
+		if f.Path == rel {
+			return f.Rank, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// sessionRankedFiles reads the file ranking persisted by the session's last
+// Generate, ordered by rank then path.
+func (s *Service) sessionRankedFiles(ctx context.Context, sessionID string) ([]RankedFile, error) {
+	if s == nil || s.isClosed() || s.db == nil {
+		return nil, ErrUnavailable
+	}
+	sessionID = strings.TrimSpace(sessionID)
+	repoKey := s.workspace().key()
+	if sessionID == "" || repoKey == "" {
+		return nil, errors.New("repo map rankings require a session and repository")
+	}
+	rows, err := s.db.ListSessionRankings(ctx, db.ListSessionRankingsParams{
+		RepoKey:   repoKey,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list repo map rankings: %w", err)
+	}
+	files := make([]RankedFile, 0, len(rows))
+	for _, r := range rows {
+		files = append(files, RankedFile{Path: r.RelPath, Rank: r.Rank})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Rank != files[j].Rank {
+			return files[i].Rank > files[j].Rank
+		}
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
 // PreIndex starts background pre-index work.
 func (s *Service) PreIndex() {
 	if s == nil || s.isClosed() {
@@ -711,6 +1007,11 @@ func (s *Service) PreIndex() {
 	if s.diffWatcher != nil {
 		s.diffWatcher.Start(s.serviceCtx)
 	}
+	for _, fw := range s.fileWatchers {
+		if err := fw.Start(s.serviceCtx); err != nil {
+			slog.Warn("Repomap file watcher failed to start", "error", err)
+		}
+	}
 
 	s.mu.Lock()
 	if s.preIndexRunning {
@@ -730,7 +1031,7 @@ func (s *Service) PreIndex() {
 			s.mu.Unlock()
 		}()
 
-		_, _, _ = s.preIndexFlight.Do(repoKeyForRoot(s.rootDir), func() (any, error) {
+		_, _, _ = s.preIndexFlight.Do(s.workspace().key(), func() (any, error) {
 			if s.onPreIndexRun != nil {
 				s.onPreIndexRun()
 			}
@@ -738,6 +1039,11 @@ func (s *Service) PreIndex() {
 			s.mu.Lock()
 			s.allFiles = files
 			s.mu.Unlock()
+			if s.techDebt != nil {
+				if err := s.techDebt.Refresh(s.serviceCtx, s.workspace().local(files)); err != nil {
+					slog.Debug("Tech-debt inventory refresh failed", "error", err)
+				}
+			}
 			return nil, nil
 		})
 	})
@@ -754,6 +1060,9 @@ func (s *Service) Close() error {
 		if s.diffWatcher != nil {
 			s.diffWatcher.Stop()
 		}
+		for _, fw := range s.fileWatchers {
+			fw.Stop()
+		}
 		s.cancel()
 		close(s.closed)
 		s.wg.Wait()
@@ -817,42 +1126,60 @@ func (s *Service) checkContextsDone(ctx context.Context) error {
 // gitTrackedFiles returns git-tracked files (cached index) for parity
 // mode. .crushignore is NOT applied: parity mode mirrors Aider's
 // behaviour where only ExcludeGlobs filter the git-tracked universe.
+// Roots outside a git repository are left out; the error is returned when
+// no root is tracked.
 func (s *Service) gitTrackedFiles(ctx context.Context) ([]string, error) {
-	cmd := exec.CommandContext(ctx, "git", "ls-files", "-z", "--cached")
-	cmd.Dir = s.rootDir
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, err
-	}
-	out = bytes.TrimSuffix(out, []byte{0}) // Trailing NUL from -z.
-	if len(out) == 0 {
-		return nil, nil
-	}
+	ws := s.workspace()
 	var files []string
-	for _, entry := range bytes.Split(out, []byte{0}) {
-		rel := filepath.ToSlash(string(entry))
-		if rel == "" {
+	var firstErr error
+	for _, root := range ws.roots {
+		cmd := exec.CommandContext(ctx, "git", "ls-files", "-z", "--cached")
+		cmd.Dir = root.Dir
+		out, err := cmd.Output()
+		if err != nil {
+			firstErr = cmp.Or(firstErr, err)
 			continue
 		}
-		if s.cfg != nil && matchesAnyGlob(rel, s.cfg.ExcludeGlobs) {
+		out = bytes.TrimSuffix(out, []byte{0}) // Trailing NUL from -z.
+		if len(out) == 0 {
 			continue
 		}
-		files = append(files, rel)
+		for _, entry := range bytes.Split(out, []byte{0}) {
+			rel := filepath.ToSlash(string(entry))
+			if rel == "" || s.excluded(root, rel) {
+				continue
+			}
+			files = append(files, ws.qualify(root, rel))
+		}
+	}
+	if files == nil && firstErr != nil {
+		return nil, firstErr
 	}
 	sort.Strings(files)
 	return files, nil
 }
 
 func (s *Service) walkAllFiles(ctx context.Context) []string {
-	root := strings.TrimSpace(s.rootDir)
-	if root == "" {
+	if strings.TrimSpace(s.rootDir) == "" && len(s.roots) == 0 {
 		return nil
 	}
 
-	walker := fsext.NewFastGlobWalker(root)
+	ws := s.workspace()
 	files := make([]string, 0, 256)
+	for _, root := range ws.roots {
+		files = append(files, s.walkRoot(ctx, ws, root)...)
+	}
+	sort.Strings(files)
+	return files
+}
 
-	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+// walkRoot returns the map paths of the files of root, leaving out the
+// directories of roots nested in it.
+func (s *Service) walkRoot(ctx context.Context, ws workspace, root Root) []string {
+	walker := fsext.NewFastGlobWalker(root.Dir)
+	var files []string
+
+	_ = filepath.WalkDir(root.Dir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -864,7 +1191,7 @@ func (s *Service) walkAllFiles(ctx context.Context) []string {
 		}
 
 		if d.IsDir() {
-			if walker.ShouldSkipDir(path) {
+			if walker.ShouldSkipDir(path) || ws.nested(root, path) {
 				return filepath.SkipDir
 			}
 			return nil
@@ -879,27 +1206,50 @@ func (s *Service) walkAllFiles(ctx context.Context) []string {
 			return nil
 		}
 
-		rel, relErr := filepath.Rel(root, path)
+		rel, relErr := filepath.Rel(root.Dir, path)
 		if relErr != nil {
 			return nil
 		}
-		files = append(files, filepath.ToSlash(rel))
+		// Apply ExcludeGlobs filtering via doublestar.Match.
+		if rel = filepath.ToSlash(rel); !s.excluded(root, rel) {
+			files = append(files, ws.qualify(root, rel))
+		}
 		return nil
 	})
+	return files
+}
+
+// excluded reports whether rel, relative to root, matches the repo map's
+// exclude globs or the root's own.
+func (s *Service) excluded(root Root, rel string) bool {
+	return (s.cfg != nil && matchesAnyGlob(rel, s.cfg.ExcludeGlobs)) || matchesAnyGlob(rel, root.ExcludeGlobs)
+}
 
-	// Apply ExcludeGlobs filtering via doublestar.Match.
-	if s.cfg != nil && len(s.cfg.ExcludeGlobs) > 0 {
-		filtered := make([]string, 0, len(files))
-		for _, f := range files {
-			if !matchesAnyGlob(f, s.cfg.ExcludeGlobs) {
-				filtered = append(filtered, f)
+// blameInfo returns the blame info of files, keyed by map path, asking the
+// git repository of each root.
+func (s *Service) blameInfo(ctx context.Context, files []string) map[string]*BlameInfo {
+	ws := s.workspace()
+	if !ws.multi() {
+		info, _ := GetBlameInfo(ctx, s.rootDir, files)
+		return info
+	}
+	byRoot := make(map[string][]string, len(ws.roots))
+	for _, f := range files {
+		if root, sub, ok := ws.split(f); ok {
+			byRoot[root.Name] = append(byRoot[root.Name], sub)
+		}
+	}
+	var result map[string]*BlameInfo
+	for _, root := range ws.roots {
+		info, _ := GetBlameInfo(ctx, root.Dir, byRoot[root.Name])
+		for sub, bi := range info {
+			if result == nil {
+				result = make(map[string]*BlameInfo)
 			}
+			result[ws.qualify(root, sub)] = bi
 		}
-		files = filtered
 	}
-
-	sort.Strings(files)
-	return files
+	return result
 }
 
 // matchesAnyGlob reports whether the given path matches any of the
@@ -1017,7 +1367,7 @@ func (s *Service) buildRefreshFlightKey(sessionID string, opts GenerateOpts) str
 	}
 	repoKey := repoKeyForRoot("")
 	if s != nil {
-		repoKey = repoKeyForRoot(s.rootDir)
+		repoKey = s.workspace().key()
 	}
 	return strings.Join([]string{repoKey, sessionID, cacheKey}, "|")
 }
@@ -1088,15 +1438,50 @@ func (s *Service) persistSessionArtifacts(ctx context.Context, sessionID, repoKe
 		return
 	}
 
+	rankings := make([]db.UpsertSessionRankingParams, 0, len(ranked))
+	for _, file := range ranked {
+		rel := normalizeGraphRelPath(file.Path)
+		if rel == "" {
+			continue
+		}
+		rankings = append(rankings, db.UpsertSessionRankingParams{
+			RepoKey:   repoKey,
+			SessionID: sessionID,
+			RelPath:   rel,
+			Rank:      file.Rank,
+		})
+	}
+	readOnly := normalizeUniqueGraphPaths(readOnlyPaths)
+	readOnlyRows := make([]db.UpsertSessionReadOnlyPathParams, 0, len(readOnly))
+	for _, p := range readOnly {
+		readOnlyRows = append(readOnlyRows, db.UpsertSessionReadOnlyPathParams{
+			RepoKey:   repoKey,
+			SessionID: sessionID,
+			RelPath:   p,
+		})
+	}
+
+	// Rankings rarely change between turns; skip the rewrite when this
+	// generation matches the last one persisted for the session.
+	artifactsKey := repoKey + "\x00" + sessionID
+	hash := sessionArtifactsHash(rankings, readOnly)
+	if prev, ok := s.persistedArtifacts.Load(artifactsKey); ok && prev.([sha256.Size]byte) == hash {
+		slog.Debug("Repomap persistSessionArtifacts: rankings unchanged, skipped",
+			"session_id", sessionID,
+			"ranked_count", len(rankings),
+		)
+		return
+	}
+
 	slog.Info("Repomap persistSessionArtifacts: persisting",
 		"session_id", sessionID,
-		"ranked_count", len(ranked),
-		"read_only_count", len(readOnlyPaths),
+		"ranked_count", len(rankings),
+		"read_only_count", len(readOnly),
 		"repo_key", repoKey,
 	)
 
 	// Wrap delete + upsert in a transaction so rankings are never
-	// partially written if the loop fails partway through.
+	// partially written if a batch fails partway through.
 	tx, err := s.rawDB.BeginTx(ctx, nil)
 	if err != nil {
 		slog.Warn("Repomap persistSessionArtifacts: failed to begin transaction",
@@ -1129,42 +1514,19 @@ func (s *Service) persistSessionArtifacts(ctx context.Context, sessionID, repoKe
 		)
 		return
 	}
-
-	upserted := 0
-	for _, file := range ranked {
-		rel := normalizeGraphRelPath(file.Path)
-		if rel == "" {
-			continue
-		}
-		if err := qtx.UpsertSessionRanking(ctx, db.UpsertSessionRankingParams{
-			RepoKey:   repoKey,
-			SessionID: sessionID,
-			RelPath:   rel,
-			Rank:      file.Rank,
-		}); err != nil {
-			slog.Warn("Repomap persistSessionArtifacts: failed to upsert ranking",
-				"session_id", sessionID,
-				"path", rel,
-				"error", err,
-			)
-			return
-		}
-		upserted++
+	if err := qtx.UpsertSessionRankings(ctx, rankings); err != nil {
+		slog.Warn("Repomap persistSessionArtifacts: failed to upsert rankings",
+			"session_id", sessionID,
+			"error", err,
+		)
+		return
 	}
-
-	for _, p := range normalizeUniqueGraphPaths(readOnlyPaths) {
-		if err := qtx.UpsertSessionReadOnlyPath(ctx, db.UpsertSessionReadOnlyPathParams{
-			RepoKey:   repoKey,
-			SessionID: sessionID,
-			RelPath:   p,
-		}); err != nil {
-			slog.Warn("Repomap persistSessionArtifacts: failed to upsert read-only path",
-				"session_id", sessionID,
-				"path", p,
-				"error", err,
-			)
-			return
-		}
+	if err := qtx.UpsertSessionReadOnlyPaths(ctx, readOnlyRows); err != nil {
+		slog.Warn("Repomap persistSessionArtifacts: failed to upsert read-only paths",
+			"session_id", sessionID,
+			"error", err,
+		)
+		return
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -1175,11 +1537,27 @@ func (s *Service) persistSessionArtifacts(ctx context.Context, sessionID, repoKe
 		return
 	}
 	committed = true
+	s.persistedArtifacts.Store(artifactsKey, hash)
 
 	slog.Info("Repomap persistSessionArtifacts: completed",
 		"session_id", sessionID,
-		"ranked_upserted", upserted,
+		"ranked_upserted", len(rankings),
 		"ranked_total", len(ranked),
-		"read_only_total", len(readOnlyPaths),
+		"read_only_total", len(readOnly),
 	)
 }
+
+// sessionArtifactsHash fingerprints one generation of session rankings and
+// read-only paths.
+func sessionArtifactsHash(rankings []db.UpsertSessionRankingParams, readOnly []string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, r := range rankings {
+		fmt.Fprintf(h, "r\x00%s\x00%v\n", r.RelPath, r.Rank)
+	}
+	for _, p := range readOnly {
+		fmt.Fprintf(h, "o\x00%s\n", p)
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
@@ -0,0 +1,177 @@
+package repomap
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// routeNodeType is the node type of the tags naming HTTP routes.
+const routeNodeType = "route"
+
+// Route is an HTTP endpoint registered in source code.
+type Route struct {
+	// Method is the HTTP method, ANY for routes serving every method, or
+	// RESOURCES/RESOURCE for Rails resource routes.
+	Method string
+	Path   string
+	Line   int
+}
+
+// Name is the route's tag name, such as "POST /api/users".
+func (r Route) Name() string {
+	return r.Method + " " + r.Path
+}
+
+var (
+	// goMuxRoute matches net/http registrations, including the method and
+	// host of Go 1.22 patterns: mux.HandleFunc("POST /api/users", h).
+	goMuxRoute = regexp.MustCompile(`\bHandle(?:Func)?\(\s*"(?:([A-Z]+)\s+)?[^/"\s]*(/[^"]*)"`)
+	// goMuxMethods matches the methods a gorilla/mux route is restricted
+	// to: r.HandleFunc("/users", h).Methods("GET", "POST").
+	goMuxMethods = regexp.MustCompile(`\.Methods\(([^)]*)\)`)
+	// quotedMethod matches a quoted HTTP method.
+	quotedMethod = regexp.MustCompile(`['"]([A-Za-z]+)['"]`)
+	// goRouterRoute matches gin, echo and gorilla-style registrations:
+	// r.GET("/users", h), e.POST("/users", h), g.Any("/x", h).
+	goRouterRoute = regexp.MustCompile(`\.(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|Any)\(\s*"([^"]*)"`)
+	// goTitleRoute matches chi and fiber registrations: r.Get("/users", h).
+	// The path must start with a slash, as Get and friends are common
+	// method names.
+	goTitleRoute = regexp.MustCompile(`\.(Get|Post|Put|Patch|Delete|Head|Options|All)\(\s*"(/[^"]*)"`)
+	// jsRoute matches Express, Fastify, Koa and Hono registrations on
+	// app- and router-like receivers: app.get('/users', h).
+	jsRoute = regexp.MustCompile("\\b(?:app|api|server|fastify|router|routes|r|\\w+Router|\\w+App)\\.(get|post|put|patch|delete|head|options|all)\\(\\s*['\"`](/[^'\"`]*)['\"`]")
+	// pyRoute matches Flask route decorators with their methods:
+	// @app.route("/users", methods=["GET", "POST"]).
+	pyRoute = regexp.MustCompile(`^\s*@\w+(?:\.\w+)*\.route\(\s*['"]([^'"]*)['"](.*)`)
+	// pyMethodRoute matches FastAPI and Flask 2 method decorators:
+	// @router.post("/users").
+	pyMethodRoute = regexp.MustCompile(`^\s*@\w+(?:\.\w+)*\.(get|post|put|patch|delete|head|options)\(\s*['"]([^'"]*)['"]`)
+	// railsRoute matches verb routes in a Rails routes file:
+	// post "users/:id/confirm", to: "users#confirm".
+	railsRoute = regexp.MustCompile(`^\s*(get|post|put|patch|delete|match)\s*\(?\s*['"]([^'"]*)['"]`)
+	// railsResources matches resource routes: resources :users.
+	railsResources = regexp.MustCompile(`^\s*(resources?)\s*\(?\s*:(\w+)`)
+	// railsRoot matches the root route: root "pages#home".
+	railsRoot = regexp.MustCompile(`^\s*root\b`)
+)
+
+// ExtractRoutes returns the HTTP routes registered in a source file by the
+// web frameworks it recognizes: Go net/http, gin, echo, chi and fiber;
+// Express, Fastify, Koa and Hono; Flask and FastAPI; and Rails routes
+// files. Paths are as written: prefixes from router groups, blueprints and
+// namespaces are not applied.
+func ExtractRoutes(relPath string, content []byte) []Route {
+	var match func(line string) []Route
+	switch ext := strings.ToLower(path.Ext(relPath)); ext {
+	case ".go":
+		match = goRoutes
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts":
+		match = jsRoutes
+	case ".py":
+		match = pyRoutes
+	case ".rb":
+		if !strings.HasSuffix(relPath, "routes.rb") {
+			return nil
+		}
+		match = railsRoutes
+	default:
+		return nil
+	}
+
+	var routes []Route
+	for i, line := range strings.Split(string(content), "\n") {
+		for _, route := range match(line) {
+			route.Line = i + 1
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func goRoutes(line string) []Route {
+	var routes []Route
+	for _, m := range goMuxRoute.FindAllStringSubmatch(line, -1) {
+		methods := goMuxMethods.FindStringSubmatch(line)
+		if m[1] != "" || methods == nil {
+			routes = append(routes, newRoute(m[1], m[2]))
+			continue
+		}
+		for _, method := range quotedMethod.FindAllStringSubmatch(methods[1], -1) {
+			routes = append(routes, newRoute(method[1], m[2]))
+		}
+	}
+	for _, m := range goRouterRoute.FindAllStringSubmatch(line, -1) {
+		routes = append(routes, newRoute(m[1], m[2]))
+	}
+	for _, m := range goTitleRoute.FindAllStringSubmatch(line, -1) {
+		routes = append(routes, newRoute(m[1], m[2]))
+	}
+	return routes
+}
+
+func jsRoutes(line string) []Route {
+	var routes []Route
+	for _, m := range jsRoute.FindAllStringSubmatch(line, -1) {
+		routes = append(routes, newRoute(m[1], m[2]))
+	}
+	return routes
+}
+
+func pyRoutes(line string) []Route {
+	if m := pyMethodRoute.FindStringSubmatch(line); m != nil {
+		return []Route{newRoute(m[1], m[2])}
+	}
+	m := pyRoute.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	_, methods, ok := strings.Cut(m[2], "methods")
+	if !ok {
+		return []Route{newRoute("GET", m[1])}
+	}
+	methods, _, _ = strings.Cut(methods, "]")
+	var routes []Route
+	for _, method := range quotedMethod.FindAllStringSubmatch(methods, -1) {
+		routes = append(routes, newRoute(method[1], m[1]))
+	}
+	return routes
+}
+
+func railsRoutes(line string) []Route {
+	if m := railsRoute.FindStringSubmatch(line); m != nil {
+		method := m[1]
+		if method == "match" {
+			method = "ANY"
+		}
+		return []Route{newRoute(method, m[2])}
+	}
+	if m := railsResources.FindStringSubmatch(line); m != nil {
+		return []Route{newRoute(m[1], m[2])}
+	}
+	if railsRoot.MatchString(line) {
+		return []Route{newRoute("GET", "/")}
+	}
+	return nil
+}
+
+// newRoute normalizes a route's method and path.
+func newRoute(method, routePath string) Route {
+	method = strings.ToUpper(method)
+	switch method {
+	case "", "ALL":
+		method = "ANY"
+	}
+	if !strings.HasPrefix(routePath, "/") {
+		routePath = "/" + routePath
+	}
+	return Route{Method: method, Path: routePath}
+}
+
+// isRouteTagName reports whether a tag name is an HTTP route's, as made by
+// Route.Name.
+func isRouteTagName(name string) bool {
+	method, routePath, ok := strings.Cut(name, " ")
+	return ok && strings.HasPrefix(routePath, "/") && method != "" && strings.ToUpper(method) == method
+}
@@ -0,0 +1,97 @@
+package repomap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []string
+	}{
+		{
+			name: "net/http and gorilla",
+			path: "cmd/server/main.go",
+			content: `mux.HandleFunc("POST /api/users", createUser)
+mux.Handle("example.com/static/", fs)
+http.HandleFunc("/health", health)
+r.HandleFunc("/api/items", items).Methods("GET", "PUT")
+w.Header().Get("Content-Type")`,
+			want: []string{"1 POST /api/users", "2 ANY /static/", "3 ANY /health", "4 GET /api/items", "4 PUT /api/items"},
+		},
+		{
+			name: "gin, echo, chi and fiber",
+			path: "internal/api/routes.go",
+			content: `v1.GET("/users/:id", getUser)
+e.POST("/login", login)
+g.Any("proxy", proxy)
+r.Get("/posts/{id}", getPost)
+app.Delete("/posts/:id", deletePost)
+cache.Get(key)`,
+			want: []string{"1 GET /users/:id", "2 POST /login", "3 ANY /proxy", "4 GET /posts/{id}", "5 DELETE /posts/:id"},
+		},
+		{
+			name: "express and fastify",
+			path: "src/server.ts",
+			content: "app.get('/api/users', listUsers);\n" +
+				"usersRouter.post(\"/\", createUser)\n" +
+				"fastify.put(`/api/users/:id`, updateUser)\n" +
+				"router.all('/api/*', auth)\n" +
+				"axios.get('/api/users')",
+			want: []string{"1 GET /api/users", "2 POST /", "3 PUT /api/users/:id", "4 ANY /api/*"},
+		},
+		{
+			name: "flask and fastapi",
+			path: "app/views.py",
+			content: `@app.route("/users", methods=["GET", "POST"])
+def users(): ...
+@bp.route('/about')
+@router.delete("/users/{user_id}")
+@app.get("/items")`,
+			want: []string{"1 GET /users", "1 POST /users", "3 GET /about", "4 DELETE /users/{user_id}", "5 GET /items"},
+		},
+		{
+			name: "rails",
+			path: "config/routes.rb",
+			content: `Rails.application.routes.draw do
+  root "pages#home"
+  resources :users
+  resource :profile
+  post "users/:id/confirm", to: "users#confirm"
+  match "/search" => "search#index", via: [:get, :post]
+end`,
+			want: []string{"2 GET /", "3 RESOURCES /users", "4 RESOURCE /profile", "5 POST /users/:id/confirm", "6 ANY /search"},
+		},
+		{
+			name:    "ruby outside routes files",
+			path:    "app/models/user.rb",
+			content: `get "/users"`,
+		},
+		{
+			name:    "unsupported language",
+			path:    "README.md",
+			content: `app.get('/users', h)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, route := range ExtractRoutes(tt.path, []byte(tt.content)) {
+				require.True(t, isRouteTagName(route.Name()), route.Name())
+				got = append(got, fmt.Sprintf("%d %s", route.Line, route.Name()))
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	require.False(t, isRouteTagName("NewServer"))
+	require.False(t, isRouteTagName("get /users"))
+}
@@ -121,6 +121,24 @@ func TestClearInjectionIsScopedToRunKey(t *testing.T) {
 	require.False(t, svc.ShouldInject("session-1", second))
 }
 
+func TestForgetInjectionsIsScopedToSession(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil, nil, nil, ".", context.Background())
+	runKey := RunInjectionKey{RootUserMessageID: "root-user-msg", QueueGeneration: 0}
+	// Injection needs a cached map for the session.
+	svc.sessionCaches.Store("session-1", "map", 10)
+	svc.sessionCaches.Store("session-2", "map", 10)
+
+	require.True(t, svc.ShouldInject("session-1", runKey))
+	require.True(t, svc.ShouldInject("session-2", runKey))
+	require.False(t, svc.ShouldInject("session-1", runKey))
+
+	svc.ForgetInjections("session-1")
+	require.True(t, svc.ShouldInject("session-1", runKey))
+	require.False(t, svc.ShouldInject("session-2", runKey))
+}
+
 func TestClearInjectionNoopOnEmptyInputs(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,56 @@
+package repomap
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/charmbracelet/crush/internal/dbschema"
+)
+
+// maxSchemaPreludeTables is the most tables the schema prelude lists;
+// schema_lookup covers the rest.
+const maxSchemaPreludeTables = 50
+
+// schemaPrelude caches the database schema section of the map. It is
+// rebuilt only when a migration is added, removed or modified.
+type schemaPrelude struct {
+	mu      sync.Mutex
+	stamp   uint64
+	section string
+}
+
+// Section returns the schema prelude for the migrations among files, or ""
+// when the repository has none.
+func (p *schemaPrelude) Section(rootDir string, files []string) string {
+	migrations := dbschema.Migrations(files)
+	if len(migrations) == 0 {
+		return ""
+	}
+	stamp := dbschema.Stamp(rootDir, migrations)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if stamp == p.stamp {
+		return p.section
+	}
+	schema, err := dbschema.Parse(rootDir, migrations)
+	if err != nil {
+		slog.Debug("Failed to parse database migrations", "error", err)
+		return ""
+	}
+	p.stamp, p.section = stamp, schema.Prelude(maxSchemaPreludeTables)
+	return p.section
+}
+
+// fitPrelude returns section if it takes at most a quarter of budget, and
+// its estimated token count.
+func fitPrelude(section string, budget int) (string, int) {
+	if section == "" {
+		return "", 0
+	}
+	tokens := EstimateTokens(section, "default")
+	if tokens > budget/4 {
+		return "", 0
+	}
+	return section, tokens
+}
@@ -0,0 +1,36 @@
+package repomap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaPreludeSection(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "db", "migrations")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	migration := filepath.Join(dir, "001_users.up.sql")
+	require.NoError(t, os.WriteFile(migration, []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);"), 0o644))
+	files := []string{"main.go", "db/migrations/001_users.up.sql"}
+
+	var p schemaPrelude
+	require.Empty(t, p.Section(root, []string{"main.go"}))
+	section := p.Section(root, files)
+	require.Contains(t, section, "  users(id PK, email)\n")
+
+	require.NoError(t, os.WriteFile(migration, []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, login_name TEXT);"), 0o644))
+	require.Contains(t, p.Section(root, files), "  users(id PK, login_name)\n")
+
+	fitted, tokens := fitPrelude(section, 1000)
+	require.Equal(t, section, fitted)
+	require.Positive(t, tokens)
+	fitted, tokens = fitPrelude(strings.Repeat(section, 100), 1000)
+	require.Empty(t, fitted)
+	require.Zero(t, tokens)
+}
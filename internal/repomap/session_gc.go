@@ -0,0 +1,86 @@
+//go:build treesitter
+// +build treesitter
+
+package repomap
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Bounds on the in-memory state kept per session. A long-lived process
+// sees many sessions come and go; without these, their caches and
+// injection records would stay until the process exits.
+const (
+	// sessionIdleTTL is how long a session's state outlives its last
+	// activity.
+	sessionIdleTTL = 2 * time.Hour
+	// maxTrackedSessions caps the sessions holding state; the least
+	// recently active are evicted first.
+	maxTrackedSessions = 64
+	// sessionSweepInterval is how often idle sessions are looked for.
+	sessionSweepInterval = 5 * time.Minute
+	// maxRunsPerSession caps the injection records kept per session.
+	maxRunsPerSession = 8
+)
+
+// touchSession marks a session active and evicts idle sessions.
+func (s *Service) touchSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.touchSessionLocked(sessionID)
+}
+
+// touchSessionLocked is touchSession with s.mu held.
+func (s *Service) touchSessionLocked(sessionID string) {
+	now := s.now()
+	s.sessionActivity[sessionID] = now
+	if now.Sub(s.lastSweep) < sessionSweepInterval && len(s.sessionActivity) <= maxTrackedSessions {
+		return
+	}
+	s.lastSweep = now
+
+	for id, last := range s.sessionActivity {
+		if now.Sub(last) > sessionIdleTTL {
+			s.forgetSessionLocked(id)
+			slog.Debug("Repomap: evicted idle session state", "session_id", id)
+		}
+	}
+	for len(s.sessionActivity) > maxTrackedSessions {
+		oldest, oldestAt := "", now
+		for id, last := range s.sessionActivity {
+			if id != sessionID && !last.After(oldestAt) {
+				oldest, oldestAt = id, last
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		s.forgetSessionLocked(oldest)
+		slog.Debug("Repomap: evicted least recently active session state", "session_id", oldest)
+	}
+}
+
+// ForgetSession drops every piece of in-memory state held for a session:
+// its cached maps, render caches, injection records and disable latch.
+// Persisted rankings are kept; use Reset to drop those too. Used when a
+// session is deleted.
+func (s *Service) ForgetSession(sessionID string) {
+	if s == nil || sessionID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forgetSessionLocked(sessionID)
+}
+
+func (s *Service) forgetSessionLocked(sessionID string) {
+	s.sessionCaches.Clear(sessionID)
+	s.renderCaches.Clear(sessionID)
+	s.disabledSessions.Delete(sessionID)
+	if repoKey := s.workspace().key(); repoKey != "" {
+		s.persistedArtifacts.Delete(repoKey + "\x00" + sessionID)
+	}
+	delete(s.injectedBySessionRun, sessionID)
+	delete(s.sessionActivity, sessionID)
+}
@@ -0,0 +1,87 @@
+//go:build treesitter
+// +build treesitter
+
+package repomap
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// seedSession gives a session the in-memory state a generation leaves
+// behind.
+func seedSession(svc *Service, sessionID string) {
+	svc.sessionCaches.Store(sessionID, "map", 10)
+	svc.renderCaches.GetOrCreate(sessionID).Set("key", "map", 10)
+	svc.disableForSession(sessionID)
+	svc.touchSession(sessionID)
+}
+
+func TestForgetSessionDropsState(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil, nil, nil, ".", context.Background())
+	seedSession(svc, "s1")
+	require.True(t, svc.ShouldInject("s1", RunInjectionKey{RootUserMessageID: "m1"}))
+
+	svc.ForgetSession("s1")
+	require.Zero(t, svc.LastTokenCount("s1"))
+	require.Nil(t, svc.renderCaches.Get("s1"))
+	require.False(t, svc.isDisabledForSession("s1"))
+	require.NotContains(t, svc.injectedBySessionRun, "s1")
+	require.NotContains(t, svc.sessionActivity, "s1")
+}
+
+func TestIdleSessionsAreEvicted(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	svc := NewService(nil, nil, nil, ".", context.Background())
+	svc.now = func() time.Time { return now }
+	seedSession(svc, "idle")
+	seedSession(svc, "busy")
+
+	now = now.Add(sessionIdleTTL / 2)
+	svc.touchSession("busy")
+	now = now.Add(sessionIdleTTL/2 + sessionSweepInterval)
+	svc.touchSession("busy")
+
+	require.Zero(t, svc.LastTokenCount("idle"))
+	require.Equal(t, 10, svc.LastTokenCount("busy"))
+	require.Equal(t, 1, svc.renderCaches.Size())
+}
+
+func TestLeastRecentlyActiveSessionsAreEvicted(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	svc := NewService(nil, nil, nil, ".", context.Background())
+	svc.now = func() time.Time { return now }
+	for i := range maxTrackedSessions + 3 {
+		now = now.Add(time.Second)
+		seedSession(svc, fmt.Sprintf("s%d", i))
+	}
+
+	require.Len(t, svc.sessionActivity, maxTrackedSessions)
+	require.Equal(t, maxTrackedSessions, svc.sessionCaches.Size())
+	require.Zero(t, svc.LastTokenCount("s0"))
+	require.Equal(t, 10, svc.LastTokenCount(fmt.Sprintf("s%d", maxTrackedSessions+2)))
+}
+
+func TestInjectionRecordsAreBoundedPerSession(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil, nil, nil, ".", context.Background())
+	seedSession(svc, "s1")
+	for i := range maxRunsPerSession + 4 {
+		require.True(t, svc.ShouldInject("s1", RunInjectionKey{RootUserMessageID: "m", QueueGeneration: int64(i)}))
+	}
+
+	require.Len(t, svc.injectedBySessionRun["s1"], maxRunsPerSession)
+	latest := RunInjectionKey{RootUserMessageID: "m", QueueGeneration: maxRunsPerSession + 3}
+	require.False(t, svc.ShouldInject("s1", latest))
+}
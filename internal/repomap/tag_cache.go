@@ -0,0 +1,174 @@
+//go:build treesitter
+// +build treesitter
+
+package repomap
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/treesitter"
+	"github.com/charmbracelet/crush/internal/version"
+)
+
+// tagCacheFormat versions what a cached parse holds. Bump it when
+// extraction changes what a file yields, such as a new kind of tag.
+const tagCacheFormat = 1
+
+const (
+	// tagCacheVersionsPerFile is how many versions of each file of the
+	// universe the tag cache keeps, so switching branches back and forth
+	// stays a cache hit.
+	tagCacheVersionsPerFile = 4
+	// minTagCacheEntries is the least number of parses kept per
+	// repository, however small.
+	minTagCacheEntries = 1000
+)
+
+// cachedTag is a tag as stored, JSON encoded, in the tag cache. The path
+// is the cache key's, so tags do not repeat it.
+type cachedTag struct {
+	Name     string `json:"n"`
+	Kind     string `json:"k"`
+	Line     int    `json:"l"`
+	Language string `json:"g,omitempty"`
+	NodeType string `json:"t,omitempty"`
+}
+
+// cachedImport is an import as stored in the tag cache.
+type cachedImport struct {
+	Path     string   `json:"p"`
+	Names    []string `json:"n,omitempty"`
+	Category string   `json:"c,omitempty"`
+}
+
+// contentHash identifies a file version in the tag cache.
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// parserVersion identifies the extraction a cached parse came from: the
+// crush build, which pins the grammars and tags queries, the cache format,
+// and the stamp of the repository's own tags query for the file's
+// language. Needs the parser, for the stamps.
+func (s *Service) parserVersion(relPath string) string {
+	stamp := s.queryStamps[treesitter.GetQueryKey(treesitter.MapPath(relPath))]
+	return version.Version + "/" + strconv.Itoa(tagCacheFormat) + "/" + strconv.FormatUint(uint64(stamp), 16)
+}
+
+// loadTagCache returns the parse of a file version stored by an earlier
+// extraction, in this process or an earlier one. Misses and read failures
+// report false: the file is parsed instead.
+func (s *Service) loadTagCache(ctx context.Context, repoKey, relPath, hash, parserVersion string) (fileParseResult, bool) {
+	if s.db == nil {
+		return fileParseResult{}, false
+	}
+	row, err := s.db.GetRepoMapTagCacheEntry(ctx, db.GetRepoMapTagCacheEntryParams{
+		RepoKey:       repoKey,
+		RelPath:       relPath,
+		ContentHash:   hash,
+		ParserVersion: parserVersion,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Debug("Failed to read repo-map tag cache", "path", relPath, "error", err)
+		}
+		return fileParseResult{}, false
+	}
+
+	var tags []cachedTag
+	var imports []cachedImport
+	if err := errors.Join(
+		json.Unmarshal([]byte(row.Tags), &tags),
+		json.Unmarshal([]byte(row.Imports), &imports),
+	); err != nil {
+		slog.Debug("Ignoring undecodable repo-map tag cache entry", "path", relPath, "error", err)
+		return fileParseResult{}, false
+	}
+
+	interner := newStringInterner(len(tags) + 8)
+	r := fileParseResult{
+		relPath:       relPath,
+		language:      row.Language,
+		tags:          make([]treesitter.Tag, 0, len(tags)),
+		contentHash:   hash,
+		parserVersion: parserVersion,
+		cacheHit:      true,
+	}
+	for _, t := range tags {
+		r.tags = append(r.tags, treesitter.Tag{
+			RelPath:  relPath,
+			Name:     interner.Intern(t.Name),
+			Kind:     interner.Intern(t.Kind),
+			Line:     t.Line,
+			Language: interner.Intern(t.Language),
+			NodeType: interner.Intern(t.NodeType),
+		})
+	}
+	for _, imp := range imports {
+		r.imports = append(r.imports, treesitter.ImportInfo{Path: imp.Path, Names: imp.Names, Category: imp.Category})
+	}
+	return r, true
+}
+
+// storeTagCache records the parse of r in qtx's transaction, or marks a cache hit as
+// recently used, so the next extraction, even after a restart, reuses it.
+func (s *Service) storeTagCache(ctx context.Context, qtx *db.Queries, repoKey string, r fileParseResult) error {
+	now := time.Now().Unix()
+	if r.cacheHit {
+		return qtx.TouchRepoMapTagCacheEntry(ctx, db.TouchRepoMapTagCacheEntryParams{
+			AccessedAt:    now,
+			RepoKey:       repoKey,
+			RelPath:       r.relPath,
+			ContentHash:   r.contentHash,
+			ParserVersion: r.parserVersion,
+		})
+	}
+
+	tags := make([]cachedTag, len(r.tags))
+	for i, t := range r.tags {
+		tags[i] = cachedTag{Name: t.Name, Kind: t.Kind, Line: t.Line, Language: t.Language, NodeType: t.NodeType}
+	}
+	imports := make([]cachedImport, len(r.imports))
+	for i, imp := range r.imports {
+		imports[i] = cachedImport{Path: imp.Path, Names: imp.Names, Category: imp.Category}
+	}
+	rawTags, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("encode cached tags: %w", err)
+	}
+	rawImports, err := json.Marshal(imports)
+	if err != nil {
+		return fmt.Errorf("encode cached imports: %w", err)
+	}
+	return qtx.UpsertRepoMapTagCacheEntry(ctx, db.UpsertRepoMapTagCacheEntryParams{
+		RepoKey:       repoKey,
+		RelPath:       r.relPath,
+		ContentHash:   r.contentHash,
+		ParserVersion: r.parserVersion,
+		Language:      r.language,
+		Tags:          string(rawTags),
+		Imports:       string(rawImports),
+		AccessedAt:    now,
+	})
+}
+
+// pruneTagCache keeps the most recently used parses of a repository whose
+// universe has files files.
+func pruneTagCache(ctx context.Context, qtx *db.Queries, repoKey string, files int) error {
+	keep := max(files*tagCacheVersionsPerFile, minTagCacheEntries)
+	if err := qtx.PruneRepoMapTagCache(ctx, db.PruneRepoMapTagCacheParams{RepoKey: repoKey, Offset: int64(keep)}); err != nil {
+		return fmt.Errorf("prune repo-map tag cache: %w", err)
+	}
+	return nil
+}
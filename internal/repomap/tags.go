@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -61,6 +62,12 @@ type fileParseResult struct {
 	skipped  bool
 	deleted  bool
 	err      error
+
+	// contentHash and parserVersion key the parse in the tag cache;
+	// cacheHit is set when it came from there.
+	contentHash   string
+	parserVersion string
+	cacheHit      bool
 }
 
 // preloadFileCache fetches the entire file cache for a repo key in a
@@ -79,11 +86,12 @@ func (s *Service) preloadFileCache(ctx context.Context, repoKey string) (map[str
 }
 
 // parseFile performs all filesystem and tree-sitter work for a single
-// file without touching the database. It replicates the language
+// file without writing to the database; unchanged content is read back
+// from the tag cache instead of parsed. It replicates the language
 // detection logic from the original upsertPathTags (lines 156-159)
 // inline because resolveLanguage does not exist as a standalone method.
-func (s *Service) parseFile(ctx context.Context, parser treesitter.Parser, rootDir, relPath string, forceRefresh bool, cache map[string]fileCacheEntry) fileParseResult {
-	absPath := filepath.Join(rootDir, filepath.FromSlash(relPath))
+func (s *Service) parseFile(ctx context.Context, parser treesitter.Parser, ws workspace, relPath string, forceRefresh bool, cache map[string]fileCacheEntry) fileParseResult {
+	absPath := ws.abs(relPath)
 	st, err := os.Stat(absPath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -95,7 +103,9 @@ func (s *Service) parseFile(ctx context.Context, parser treesitter.Parser, rootD
 		return fileParseResult{relPath: relPath, skipped: true}
 	}
 
-	mtime := st.ModTime().UnixNano()
+	// The cached mtime carries the stamp of the language's custom tags
+	// query, so editing the query re-parses the file.
+	mtime := st.ModTime().UnixNano() ^ s.queryStamps[treesitter.GetQueryKey(treesitter.MapPath(relPath))]
 	if !forceRefresh {
 		if cached, ok := cache[relPath]; ok && cached.mtime == mtime {
 			return fileParseResult{relPath: relPath, skipped: true}
@@ -107,6 +117,16 @@ func (s *Service) parseFile(ctx context.Context, parser treesitter.Parser, rootD
 		return fileParseResult{relPath: relPath, err: fmt.Errorf("read %q: %w", relPath, err)}
 	}
 
+	// A new mtime does not mean new content: after a restart with a
+	// fresh checkout, or a branch switch and back, the tag cache still
+	// holds this version's parse.
+	hash := contentHash(content)
+	parserVersion := s.parserVersion(relPath)
+	if cached, ok := s.loadTagCache(ctx, ws.key(), relPath, hash, parserVersion); ok {
+		cached.mtime = mtime
+		return cached
+	}
+
 	analysis, err := parser.Analyze(ctx, relPath, content)
 	if err != nil {
 		return fileParseResult{relPath: relPath, err: fmt.Errorf("analyze %q: %w", relPath, err)}
@@ -155,14 +175,27 @@ func (s *Service) parseFile(ctx context.Context, parser treesitter.Parser, rootD
 			})
 		}
 	}
+	// HTTP routes are definitions too, named "METHOD /path".
+	for _, route := range ExtractRoutes(relPath, content) {
+		tags = append(tags, treesitter.Tag{
+			RelPath:  internedRelPath,
+			Name:     route.Name(),
+			Kind:     "def",
+			Line:     route.Line,
+			Language: internedLanguage,
+			NodeType: routeNodeType,
+		})
+	}
 	sortTagsDeterministic(tags)
 
 	return fileParseResult{
-		relPath:  relPath,
-		mtime:    mtime,
-		language: language,
-		tags:     tags,
-		imports:  imports,
+		relPath:       relPath,
+		mtime:         mtime,
+		language:      language,
+		tags:          tags,
+		imports:       imports,
+		contentHash:   hash,
+		parserVersion: parserVersion,
 	}
 }
 
@@ -219,13 +252,67 @@ func (s *Service) writePathImports(ctx context.Context, tx *sql.Tx, repoKey stri
 	return nil
 }
 
+// writeParseResult persists one parse result inside tx: the tags and
+// imports of a parsed file, or the removal of a deleted one. Failures are
+// logged and skip the file.
+func (s *Service) writeParseResult(ctx context.Context, tx *sql.Tx, qtx *db.Queries, repoKey string, r fileParseResult) {
+	if r.skipped || r.err != nil {
+		if r.err != nil {
+			slog.Warn("Skipping file due to parse error",
+				"path", r.relPath,
+				"error", r.err)
+		}
+		return
+	}
+
+	if r.deleted {
+		if delErr := qtx.DeleteRepoMapFileCache(ctx, db.DeleteRepoMapFileCacheParams{
+			RepoKey: repoKey,
+			RelPath: r.relPath,
+		}); delErr != nil {
+			slog.Warn("Failed to delete file cache for deleted path",
+				"path", r.relPath,
+				"error", delErr)
+		}
+		// Imports are not tied to the file cache; with none left to
+		// insert this only drops the file's edges.
+		if err := s.writePathImports(ctx, tx, repoKey, r); err != nil {
+			slog.Warn("Failed to delete imports for deleted path",
+				"path", r.relPath,
+				"error", err)
+		}
+		return
+	}
+
+	if err := s.writePathTags(ctx, qtx, repoKey, r); err != nil {
+		slog.Warn("Failed to write tags for path",
+			"path", r.relPath,
+			"error", err)
+		return
+	}
+	if err := s.writePathImports(ctx, tx, repoKey, r); err != nil {
+		slog.Warn("Failed to write imports for path",
+			"path", r.relPath,
+			"error", err)
+		return
+	}
+	if r.contentHash != "" {
+		if err := s.storeTagCache(ctx, qtx, repoKey, r); err != nil {
+			slog.Warn("Failed to cache tags for path",
+				"path", r.relPath,
+				"error", err)
+		}
+	}
+}
+
 // extractTags derives defs/refs from the file universe, normalizes all paths,
 // persists incremental updates in repo_map tables, and returns a deterministic
 // tag slice for downstream graph construction.
 //
 // The pipeline is split into three phases:
 //   - Phase 0: Pre-load file cache (serial, non-transactional).
-//   - Phase 1: Concurrent parse (no DB, errgroup worker pool).
+//   - Phase 1: Concurrent parse (tag cache reads only, errgroup worker
+//     pool).
 //   - Phase 2: Sequential DB writes (inside transaction).
 func (s *Service) extractTags(ctx context.Context, rootDir string, fileUniverse []string, forceRefresh bool) ([]treesitter.Tag, []ImportEdge, error) {
 	if s == nil {
@@ -238,11 +325,12 @@ func (s *Service) extractTags(ctx context.Context, rootDir string, fileUniverse
 		return nil, nil, fmt.Errorf("repo-map database is not configured")
 	}
 
-	normalizedFiles, err := normalizeFileUniverse(rootDir, fileUniverse)
+	ws := s.workspaceAt(rootDir)
+	normalizedFiles, err := ws.normalize(fileUniverse)
 	if err != nil {
 		return nil, nil, err
 	}
-	repoKey := repoKeyForRoot(rootDir)
+	repoKey := ws.key()
 	if repoKey == "" {
 		return nil, nil, fmt.Errorf("repo key is empty")
 	}
@@ -259,7 +347,7 @@ func (s *Service) extractTags(ctx context.Context, rootDir string, fileUniverse
 		return nil, nil, err
 	}
 
-	// ── Phase 1: Concurrent parse (no DB) ──
+	// ── Phase 1: Concurrent parse (tag cache reads only) ──
 	// Resolve pool size: config → runtime.NumCPU() → 1.
 	// CRITICAL: SetLimit(0) causes deadlock — always clamp to >= 1.
 	poolSize := 0
@@ -292,7 +380,7 @@ func (s *Service) extractTags(ctx context.Context, rootDir string, fileUniverse
 			if err := gCtx.Err(); err != nil {
 				return err
 			}
-			results[i] = s.parseFile(gCtx, parser, rootDir, relPath, forceRefresh, cache)
+			results[i] = s.parseFile(gCtx, parser, ws, relPath, forceRefresh, cache)
 			return nil
 		})
 	}
@@ -323,39 +411,16 @@ func (s *Service) extractTags(ctx context.Context, rootDir string, fileUniverse
 		return nil, nil, err
 	}
 
+	parsed := 0
 	for _, r := range results {
-		if r.skipped || r.err != nil {
-			if r.err != nil {
-				slog.Warn("Skipping file due to parse error",
-					"path", r.relPath,
-					"error", r.err)
-			}
-			continue
-		}
-
-		if r.deleted {
-			if delErr := qtx.DeleteRepoMapFileCache(ctx, db.DeleteRepoMapFileCacheParams{
-				RepoKey: repoKey,
-				RelPath: r.relPath,
-			}); delErr != nil {
-				slog.Warn("Failed to delete file cache for deleted path",
-					"path", r.relPath,
-					"error", delErr)
-			}
-			continue
+		s.writeParseResult(ctx, tx, qtx, repoKey, r)
+		if r.contentHash != "" && !r.cacheHit {
+			parsed++
 		}
-
-		if err := s.writePathTags(ctx, qtx, repoKey, r); err != nil {
-			slog.Warn("Failed to write tags for path",
-				"path", r.relPath,
-				"error", err)
-			continue
-		}
-		if err := s.writePathImports(ctx, tx, repoKey, r); err != nil {
-			slog.Warn("Failed to write imports for path",
-				"path", r.relPath,
-				"error", err)
-			continue
+	}
+	if parsed > 0 {
+		if err := pruneTagCache(ctx, qtx, repoKey, len(normalizedFiles)); err != nil {
+			slog.Warn("Failed to prune repo-map tag cache", "error", err)
 		}
 	}
 
@@ -467,7 +532,27 @@ func (s *Service) ensureParser() treesitter.Parser {
 		if factory == nil {
 			factory = treesitter.NewParserWithConfig
 		}
-		s.parser = factory(treesitter.ParserConfig{PoolSize: poolSize})
+		var queries map[string][]byte
+		if s.rootDir != "" {
+			var err error
+			queries, err = treesitter.LoadCustomTagsQueries(filepath.Join(s.rootDir, treesitter.CustomQueryDir))
+			if err != nil {
+				slog.Warn("Failed to load custom tags queries", "error", err)
+			}
+		}
+		s.queryStamps = tagsQueryStamps(queries)
+		s.parser = factory(treesitter.ParserConfig{PoolSize: poolSize, TagsQueries: queries})
 	}
 	return s.parser
 }
+
+// tagsQueryStamps hashes each custom tags query for the file cache.
+func tagsQueryStamps(queries map[string][]byte) map[string]int64 {
+	stamps := make(map[string]int64, len(queries))
+	for lang, query := range queries {
+		h := fnv.New64a()
+		_, _ = h.Write(query)
+		stamps[lang] = int64(h.Sum64())
+	}
+	return stamps
+}
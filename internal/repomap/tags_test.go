@@ -201,6 +201,134 @@ func TestEnsureParserRespectsConfiguredPoolSize(t *testing.T) {
 	require.Equal(t, 7, factory.lastConfig.PoolSize)
 }
 
+func TestTagsExtractAddsRoutes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+
+	conn, err := db.Connect(ctx, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	q := db.New(conn)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {\n\tr.POST(\"/api/users\", createUser)\n}\n"), 0o644))
+
+	svc := NewService(nil, q, conn, root, context.Background())
+	svc.parser = &fakeParser{analyses: map[string]*treesitter.FileAnalysis{
+		"main.go": {Language: "go", Tags: []treesitter.Tag{{Name: "main", Kind: "def", Line: 3, Language: "go", NodeType: "function"}}},
+	}}
+	tags, _, err := svc.extractTags(ctx, root, []string{"main.go"}, false)
+	require.NoError(t, err)
+
+	require.Len(t, tags, 2)
+	require.Equal(t, "POST /api/users", tags[1].Name)
+	require.Equal(t, "def", tags[1].Kind)
+	require.Equal(t, routeNodeType, tags[1].NodeType)
+	require.Equal(t, 4, tags[1].Line)
+}
+
+func TestEnsureParserLoadsCustomTagsQueries(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, filepath.FromSlash(treesitter.CustomQueryDir))
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go-tags.scm"), []byte("; extends\n"), 0o644))
+
+	factory := &fakeParserFactory{}
+	svc := &Service{rootDir: root, newParserWithCfg: factory.NewParserWithConfig}
+
+	_ = svc.ensureParser()
+	require.Equal(t, map[string][]byte{"go": []byte("; extends\n")}, factory.lastConfig.TagsQueries)
+	require.NotZero(t, svc.queryStamps["go"])
+}
+
+func TestTagsExtractReparsesOnQueryChange(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+
+	conn, err := db.Connect(ctx, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	q := db.New(conn)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "x.go"), []byte("package main\n"), 0o644))
+
+	svc := NewService(nil, q, conn, root, context.Background())
+	fp := &fakeParser{analyses: map[string]*treesitter.FileAnalysis{
+		"x.go": {Language: "go", Tags: []treesitter.Tag{{Name: "Run", Kind: "def", Line: 1, Language: "go", NodeType: "function"}}},
+	}}
+	svc.parser = fp
+	_, _, err = svc.extractTags(ctx, root, []string{"x.go"}, false)
+	require.NoError(t, err)
+
+	fp.analyses["x.go"] = &treesitter.FileAnalysis{Language: "go", Tags: []treesitter.Tag{{Name: "/health", Kind: "def", Line: 1, Language: "go", NodeType: "route"}}}
+	_, _, err = svc.extractTags(ctx, root, []string{"x.go"}, false)
+	require.NoError(t, err)
+	stored, err := q.ListRepoMapTags(ctx, repoKeyForRoot(root))
+	require.NoError(t, err)
+	require.Equal(t, "Run", stored[0].Name, "an unchanged file keeps its tags")
+
+	svc.queryStamps = map[string]int64{"go": 42}
+	_, _, err = svc.extractTags(ctx, root, []string{"x.go"}, false)
+	require.NoError(t, err)
+	stored, err = q.ListRepoMapTags(ctx, repoKeyForRoot(root))
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "/health", stored[0].Name, "a changed tags query re-parses the file")
+}
+
+func TestTagsExtractReusesTagCacheAcrossRestarts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+
+	conn, err := db.Connect(ctx, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	q := db.New(conn)
+
+	path := filepath.Join(root, "x.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n\nfunc Run() {}\n"), 0o644))
+
+	svc := NewService(nil, q, conn, root, context.Background())
+	svc.parser = &fakeParser{analyses: map[string]*treesitter.FileAnalysis{
+		"x.go": {Language: "go", Tags: []treesitter.Tag{{Name: "Run", Kind: "def", Line: 3, Language: "go", NodeType: "function"}}},
+	}}
+	_, _, err = svc.extractTags(ctx, root, []string{"x.go"}, false)
+	require.NoError(t, err)
+
+	// A fresh checkout after a restart: new mtime, same content, and a
+	// parser that would now see something else.
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, later, later))
+	restarted := NewService(nil, q, conn, root, context.Background())
+	fp := &fakeParser{analyses: map[string]*treesitter.FileAnalysis{
+		"x.go": {Language: "go", Tags: []treesitter.Tag{{Name: "Walk", Kind: "def", Line: 3, Language: "go", NodeType: "function"}}},
+	}}
+	restarted.parser = fp
+	tags, _, err := restarted.extractTags(ctx, root, []string{"x.go"}, false)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.Equal(t, "Run", tags[0].Name, "unchanged content is read from the tag cache")
+	require.Equal(t, "function", tags[0].NodeType)
+
+	require.NoError(t, os.WriteFile(path, []byte("package main\n\nfunc Walk() {}\n"), 0o644))
+	tags, _, err = restarted.extractTags(ctx, root, []string{"x.go"}, true)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.Equal(t, "Walk", tags[0].Name, "changed content is parsed")
+
+	var entries int
+	require.NoError(t, conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM repo_map_tag_cache WHERE repo_key = ?", repoKeyForRoot(root)).Scan(&entries))
+	require.Equal(t, 2, entries, "both versions stay cached")
+}
+
 func TestStringInternerDeduplicatesBackingStorage(t *testing.T) {
 	t.Parallel()
 
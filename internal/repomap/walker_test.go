@@ -256,3 +256,28 @@ func TestWalkAllFilesRelativePaths(t *testing.T) {
 			"path should be relative: %q", f)
 	}
 }
+
+func TestWalkAllFilesMultiRoot(t *testing.T) {
+	t.Parallel()
+
+	workspaceDir := t.TempDir()
+	writeFile(t, filepath.Join(workspaceDir, "README.md"), "# ws")
+	writeFile(t, filepath.Join(workspaceDir, "app", "main.go"), "package main")
+	writeFile(t, filepath.Join(workspaceDir, "app", "main_test.go"), "package main")
+	writeFile(t, filepath.Join(workspaceDir, "app", "plugins", "auth", "auth.go"), "package auth")
+	writeFile(t, filepath.Join(workspaceDir, "app", "plugins", "auth", "auth.pb.go"), "package auth")
+
+	roots, err := resolveRoots(workspaceDir, []Root{
+		{Dir: "app"},
+		{Name: "auth", Dir: "app/plugins/auth", ExcludeGlobs: []string{"*.pb.go"}},
+	})
+	require.NoError(t, err)
+	svc := &Service{
+		rootDir: workspaceDir,
+		roots:   roots,
+		cfg:     &config.RepoMapOptions{ExcludeGlobs: []string{"**/*_test.go"}},
+	}
+
+	// The nested root's files are listed under its own name only.
+	require.Equal(t, []string{"app/main.go", "auth/auth.go"}, svc.walkAllFiles(context.Background()))
+}
@@ -0,0 +1,202 @@
+package repomap
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultWatchDebounce is how long a FileWatcher waits for events to stop
+// before reporting a batch, so a save or checkout re-indexes once.
+const defaultWatchDebounce = 500 * time.Millisecond
+
+// FileWatcher reports the files of a repository changed on disk, in
+// debounced batches, using filesystem notifications. Directories are
+// watched recursively, including ones created later.
+type FileWatcher struct {
+	rootDir  string
+	debounce time.Duration
+	onChange func(ctx context.Context, paths []string)
+	skip     func(path string, isDir bool) bool
+
+	mu      sync.Mutex
+	running bool
+	watcher *fsnotify.Watcher
+	cancel  context.CancelFunc
+	done    chan struct{}
+	pending map[string]struct{}
+}
+
+// FileWatcherConfig configures a FileWatcher.
+type FileWatcherConfig struct {
+	RootDir string
+	// Debounce is the quiet period before a batch is reported; 500ms when
+	// zero.
+	Debounce time.Duration
+	// OnChange receives the absolute paths of a batch of changed, created
+	// and removed files, sorted.
+	OnChange func(ctx context.Context, paths []string)
+	// Skip reports whether an absolute path is ignored; ignored
+	// directories are not watched. Nothing is skipped when nil.
+	Skip func(path string, isDir bool) bool
+}
+
+// NewFileWatcher creates a FileWatcher. It does nothing until Start.
+func NewFileWatcher(cfg FileWatcherConfig) *FileWatcher {
+	debounce := cfg.Debounce
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+	skip := cfg.Skip
+	if skip == nil {
+		skip = func(string, bool) bool { return false }
+	}
+	return &FileWatcher{
+		rootDir:  cfg.RootDir,
+		debounce: debounce,
+		onChange: cfg.OnChange,
+		skip:     skip,
+	}
+}
+
+// Start watches the repository until ctx is done or Stop is called. It is
+// safe to call Start multiple times; only one watch runs at a time.
+func (fw *FileWatcher) Start(ctx context.Context) error {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if fw.running {
+		return nil
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	fw.watcher = w
+	fw.pending = make(map[string]struct{})
+	fw.addTree(fw.rootDir, false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	fw.cancel = cancel
+	fw.done = make(chan struct{})
+	fw.running = true
+	go fw.run(ctx, w)
+	return nil
+}
+
+// Stop halts the watch and waits for it to finish. Pending changes are
+// dropped.
+func (fw *FileWatcher) Stop() {
+	fw.mu.Lock()
+	if !fw.running {
+		fw.mu.Unlock()
+		return
+	}
+	fw.cancel()
+	fw.running = false
+	done := fw.done
+	fw.mu.Unlock()
+
+	<-done
+}
+
+// addTree watches dir and its subdirectories. With report set, the files
+// found are added to the pending batch: a directory created or moved into
+// the repository arrives as one event, not one per file. Called with mu
+// held.
+func (fw *FileWatcher) addTree(dir string, report bool) {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if report && !fw.skip(path, false) {
+				fw.pending[path] = struct{}{}
+			}
+			return nil
+		}
+		if path != fw.rootDir && fw.skip(path, true) {
+			return filepath.SkipDir
+		}
+		if err := fw.watcher.Add(path); err != nil {
+			slog.Debug("FileWatcher failed to watch directory", "path", path, "error", err)
+		}
+		return nil
+	})
+}
+
+func (fw *FileWatcher) run(ctx context.Context, w *fsnotify.Watcher) {
+	defer close(fw.done)
+	defer w.Close()
+
+	timer := time.NewTimer(fw.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if fw.handle(event) {
+				timer.Reset(fw.debounce)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			slog.Debug("FileWatcher error", "error", err)
+		case <-timer.C:
+			fw.flush(ctx)
+		}
+	}
+}
+
+// handle records the file of event and reports whether it is pending.
+func (fw *FileWatcher) handle(event fsnotify.Event) bool {
+	if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+		return false
+	}
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if event.Has(fsnotify.Create) {
+		if st, err := os.Stat(event.Name); err == nil && st.IsDir() {
+			n := len(fw.pending)
+			if !fw.skip(event.Name, true) {
+				fw.addTree(event.Name, true)
+			}
+			return len(fw.pending) > n
+		}
+	}
+	if fw.skip(event.Name, false) {
+		return false
+	}
+	fw.pending[event.Name] = struct{}{}
+	return true
+}
+
+func (fw *FileWatcher) flush(ctx context.Context) {
+	fw.mu.Lock()
+	paths := make([]string, 0, len(fw.pending))
+	for p := range fw.pending {
+		paths = append(paths, p)
+	}
+	clear(fw.pending)
+	fw.mu.Unlock()
+
+	if len(paths) == 0 || fw.onChange == nil {
+		return
+	}
+	slices.Sort(paths)
+	fw.onChange(ctx, paths)
+}
@@ -0,0 +1,67 @@
+package repomap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcher(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "node_modules"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "old.go"), []byte("package src"), 0o644))
+
+	var mu sync.Mutex
+	var batches [][]string
+	fw := NewFileWatcher(FileWatcherConfig{
+		RootDir:  root,
+		Debounce: 50 * time.Millisecond,
+		OnChange: func(_ context.Context, paths []string) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches = append(batches, paths)
+		},
+		Skip: func(path string, _ bool) bool {
+			return strings.Contains(path, "node_modules")
+		},
+	})
+	require.NoError(t, fw.Start(t.Context()))
+	t.Cleanup(fw.Stop)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "old.go"), []byte("package src // edited"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "sub", "new.go"), []byte("package sub"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "node_modules", "dep.js"), []byte("x"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(root, "src", "old.go")))
+
+	changed := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		var all []string
+		for _, b := range batches {
+			all = append(all, b...)
+		}
+		slices.Sort(all)
+		return slices.Compact(all)
+	}
+	require.Eventually(t, func() bool {
+		return slices.Contains(changed(), filepath.Join(root, "src", "sub", "new.go")) &&
+			slices.Contains(changed(), filepath.Join(root, "src", "old.go"))
+	}, 5*time.Second, 20*time.Millisecond)
+	for _, p := range changed() {
+		require.NotContains(t, p, "node_modules")
+	}
+
+	fw.Stop()
+	fw.Stop()
+}
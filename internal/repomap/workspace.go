@@ -0,0 +1,183 @@
+package repomap
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Root is one project of a multi-root workspace (see NewServiceMultiRoot).
+type Root struct {
+	// Name prefixes the paths of the root's files in the map, as
+	// "name/rel/path". The base name of Dir when empty.
+	Name string
+	// Dir is the root's directory, absolute or relative to the workspace
+	// directory.
+	Dir string
+	// ExcludeGlobs are excluded from this root only, on top of the repo
+	// map's own exclude globs, and match paths relative to Dir.
+	ExcludeGlobs []string
+}
+
+// workspace maps the paths of the map to files on disk. A single-root
+// workspace has one unnamed root and paths relative to it; with named
+// roots every path starts with the name of its root, and the files of all
+// roots are ranked in one graph.
+type workspace struct {
+	// dir resolves relative paths that do not start with a root name.
+	dir   string
+	roots []Root
+}
+
+// singleRoot returns the workspace of the repository at dir.
+func singleRoot(dir string) workspace {
+	return workspace{dir: dir, roots: []Root{{Dir: dir}}}
+}
+
+// resolveRoots validates roots and resolves their directories against dir
+// and their default names.
+func resolveRoots(dir string, roots []Root) ([]Root, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no repo map roots")
+	}
+	resolved := make([]Root, 0, len(roots))
+	seen := make(map[string]string, len(roots))
+	for _, r := range roots {
+		rootDir := strings.TrimSpace(r.Dir)
+		if rootDir == "" {
+			return nil, fmt.Errorf("repo map root %q has no directory", r.Name)
+		}
+		if !filepath.IsAbs(rootDir) {
+			rootDir = filepath.Join(dir, rootDir)
+		}
+		rootDir = filepath.Clean(rootDir)
+		name := cmp.Or(strings.TrimSpace(r.Name), filepath.Base(rootDir))
+		if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("repo map root %q: invalid name %q", r.Dir, name)
+		}
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("repo map roots %q and %q are both named %q", other, r.Dir, name)
+		}
+		seen[name] = r.Dir
+		resolved = append(resolved, Root{Name: name, Dir: rootDir, ExcludeGlobs: r.ExcludeGlobs})
+	}
+	return resolved, nil
+}
+
+// multi reports whether paths carry a root name.
+func (w workspace) multi() bool {
+	return len(w.roots) > 0 && w.roots[0].Name != ""
+}
+
+// key identifies the workspace in the database. A single root keeps the
+// key of its directory, so its cached tags survive.
+func (w workspace) key() string {
+	if !w.multi() {
+		return repoKeyForRoot(w.dir)
+	}
+	var b strings.Builder
+	for _, r := range w.roots {
+		fmt.Fprintf(&b, "%s=%s\n", r.Name, filepath.ToSlash(r.Dir))
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// split returns the root of a map path and the path relative to it.
+func (w workspace) split(rel string) (Root, string, bool) {
+	if !w.multi() {
+		return w.roots[0], rel, true
+	}
+	name, sub, _ := strings.Cut(rel, "/")
+	for _, r := range w.roots {
+		if r.Name == name {
+			return r, sub, sub != ""
+		}
+	}
+	return Root{}, "", false
+}
+
+// qualify returns the map path of sub, relative to root.
+func (w workspace) qualify(root Root, sub string) string {
+	if root.Name == "" {
+		return sub
+	}
+	return root.Name + "/" + sub
+}
+
+// abs returns the file on disk of a map path.
+func (w workspace) abs(rel string) string {
+	if root, sub, ok := w.split(rel); ok {
+		return filepath.Join(root.Dir, filepath.FromSlash(sub))
+	}
+	return filepath.Join(w.dir, filepath.FromSlash(rel))
+}
+
+// rel returns the map path of p, given absolute, relative to the workspace
+// directory, or as a map path. Paths outside every root are an error.
+func (w workspace) rel(p string) (string, error) {
+	if !w.multi() {
+		return normalizeRepoRelPath(w.dir, p)
+	}
+	if strings.TrimSpace(p) == "" {
+		return "", fmt.Errorf("path is empty")
+	}
+	if !filepath.IsAbs(p) {
+		if _, _, ok := w.split(path.Clean(filepath.ToSlash(p))); ok {
+			p = w.abs(path.Clean(filepath.ToSlash(p)))
+		} else {
+			p = filepath.Join(w.dir, p)
+		}
+	}
+	// The innermost root wins when roots nest.
+	best, bestSub := -1, ""
+	for i, r := range w.roots {
+		sub, err := normalizeRepoRelPath(r.Dir, p)
+		if err != nil {
+			continue
+		}
+		if best < 0 || len(r.Dir) > len(w.roots[best].Dir) {
+			best, bestSub = i, sub
+		}
+	}
+	if best < 0 {
+		return "", fmt.Errorf("path %q is outside the workspace roots", p)
+	}
+	return w.qualify(w.roots[best], bestSub), nil
+}
+
+// normalize returns the sorted, unique map paths of files.
+func (w workspace) normalize(files []string) ([]string, error) {
+	return normalizePaths(files, w.rel)
+}
+
+// local returns the paths of files relative to the workspace directory,
+// leaving out those of roots outside it. Single-root paths are returned as
+// they are.
+func (w workspace) local(files []string) []string {
+	if !w.multi() {
+		return files
+	}
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		if rel, err := normalizeRepoRelPath(w.dir, w.abs(f)); err == nil {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+// nested reports whether dir is the directory of a root other than root,
+// whose files that root leaves to it.
+func (w workspace) nested(root Root, dir string) bool {
+	for _, r := range w.roots {
+		if r.Dir == dir && r.Name != root.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package repomap
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRoots(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	other := t.TempDir()
+	roots, err := resolveRoots(dir, []Root{
+		{Dir: "services/api/"},
+		{Name: "shared", Dir: other, ExcludeGlobs: []string{"gen/**"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Root{
+		{Name: "api", Dir: filepath.Join(dir, "services", "api")},
+		{Name: "shared", Dir: other, ExcludeGlobs: []string{"gen/**"}},
+	}, roots)
+
+	for _, bad := range [][]Root{
+		nil,
+		{{Name: "x"}},
+		{{Name: "a/b", Dir: "x"}},
+		{{Dir: "a/lib"}, {Dir: "b/lib"}},
+	} {
+		_, err := resolveRoots(dir, bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestWorkspacePaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	other := t.TempDir()
+	roots, err := resolveRoots(dir, []Root{
+		{Dir: "app"},
+		{Name: "auth", Dir: "app/plugins/auth"},
+		{Name: "shared", Dir: other},
+	})
+	require.NoError(t, err)
+	ws := workspace{dir: dir, roots: roots}
+
+	for in, want := range map[string]string{
+		filepath.Join(dir, "app", "main.go"):                    "app/main.go",
+		"app/./cmd/run.go":                                      "app/cmd/run.go",
+		"shared/util.go":                                        "shared/util.go",
+		filepath.Join(other, "util.go"):                         "shared/util.go",
+		filepath.Join(dir, "app", "plugins", "auth", "auth.go"): "auth/auth.go",
+		"app/plugins/auth/auth.go":                              "auth/auth.go",
+	} {
+		got, err := ws.rel(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, bad := range []string{"", "README.md", filepath.Join(t.TempDir(), "x.go")} {
+		_, err := ws.rel(bad)
+		require.Error(t, err, bad)
+	}
+
+	require.Equal(t, filepath.Join(other, "pkg", "util.go"), ws.abs("shared/pkg/util.go"))
+	require.Equal(t, filepath.Join(dir, "app", "plugins", "auth", "auth.go"), ws.abs("auth/auth.go"))
+	require.Equal(t, []string{"app/main.go", "app/plugins/auth/auth.go"}, ws.local([]string{"app/main.go", "auth/auth.go", "shared/util.go"}))
+
+	// A single root keeps plain paths and the key of its directory.
+	single := singleRoot(dir)
+	require.Equal(t, repoKeyForRoot(dir), single.key())
+	require.NotEqual(t, single.key(), ws.key())
+	got, err := single.rel(filepath.Join(dir, "app", "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "app/main.go", got)
+	require.Equal(t, filepath.Join(dir, "app", "main.go"), single.abs("app/main.go"))
+}
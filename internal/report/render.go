@@ -0,0 +1,133 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+//go:embed report.html
+var htmlTemplate string
+
+// maxRows caps the repository and tool tables so reports stay readable.
+const maxRows = 10
+
+// Markdown renders the report as a markdown document.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Crush usage report\n\n")
+	fmt.Fprintf(&b, "%s to %s. Generated %s from local data only.\n\n",
+		r.Since.Format("2006-01-02"), r.GeneratedAt.Format("2006-01-02"), r.GeneratedAt.Format("2006-01-02 15:04"))
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Sessions | Messages | Input tokens | Output tokens | Cost |\n")
+	b.WriteString("| ---: | ---: | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n\n",
+		formatInt(r.Sessions), formatInt(r.Messages), formatInt(r.PromptTokens), formatInt(r.CompletionTokens), formatCost(r.Cost))
+
+	if len(r.Models) > 0 {
+		b.WriteString("## Models\n\n")
+		b.WriteString("| Model | Provider | Messages | Input tokens | Output tokens | Cost |\n")
+		b.WriteString("| --- | --- | ---: | ---: | ---: | ---: |\n")
+		for _, m := range r.Models {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				escapeCell(m.Model), escapeCell(m.Provider), formatInt(m.Messages), formatInt(m.PromptTokens), formatInt(m.CompletionTokens), formatCost(m.Cost))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Repositories) > 0 {
+		b.WriteString("## Top repositories\n\n")
+		b.WriteString("| Repository | Sessions | Tokens | Cost |\n")
+		b.WriteString("| --- | ---: | ---: | ---: |\n")
+		for _, repo := range r.TopRepositories() {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				escapeCell(repo.Path), formatInt(repo.Sessions), formatInt(repo.Tokens), formatCost(repo.Cost))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Tools) > 0 {
+		b.WriteString("## Tool mix\n\n")
+		b.WriteString("| Tool | Calls | Share |\n")
+		b.WriteString("| --- | ---: | ---: |\n")
+		for _, t := range r.TopTools() {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", escapeCell(t.Name), formatInt(t.Calls), r.ToolShare(t))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// HTML renders the report as a self-contained HTML page without external
+// assets.
+func (r *Report) HTML() (string, error) {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"int":  formatInt,
+		"cost": formatCost,
+		"date": func(r *Report) string {
+			return r.Since.Format("2006-01-02") + " to " + r.GeneratedAt.Format("2006-01-02")
+		},
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// TopRepositories returns the repositories with the highest spend.
+func (r *Report) TopRepositories() []RepositoryUsage {
+	return r.Repositories[:min(len(r.Repositories), maxRows)]
+}
+
+// TopTools returns the most called tools.
+func (r *Report) TopTools() []ToolUsage {
+	return r.Tools[:min(len(r.Tools), maxRows)]
+}
+
+// ToolShare returns t's share of all tool calls as a percentage.
+func (r *Report) ToolShare(t ToolUsage) string {
+	var total int64
+	for _, tool := range r.Tools {
+		total += tool.Calls
+	}
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(t.Calls)*100/float64(total))
+}
+
+// formatInt formats n with thousands separators.
+func formatInt(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if neg {
+		return "-" + b.String()
+	}
+	return b.String()
+}
+
+// formatCost formats a dollar amount.
+func formatCost(c float64) string {
+	return fmt.Sprintf("$%.2f", c)
+}
+
+// escapeCell keeps s from breaking a markdown table row.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,157 @@
+// Package report builds shareable usage reports from local crush
+// databases. Everything is computed from the databases on disk; nothing
+// is sent over the network.
+package report
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+)
+
+// Source is the database of one repository.
+type Source struct {
+	// Path is the repository's working directory, as shown in the report.
+	Path    string
+	Querier db.Querier
+}
+
+// Report is the usage of every source since a point in time.
+type Report struct {
+	GeneratedAt      time.Time         `json:"generated_at"`
+	Since            time.Time         `json:"since"`
+	Sessions         int64             `json:"sessions"`
+	Messages         int64             `json:"messages"`
+	PromptTokens     int64             `json:"prompt_tokens"`
+	CompletionTokens int64             `json:"completion_tokens"`
+	Cost             float64           `json:"cost"`
+	Models           []ModelUsage      `json:"models"`
+	Repositories     []RepositoryUsage `json:"repositories"`
+	Tools            []ToolUsage       `json:"tools"`
+}
+
+// ModelUsage is the usage of a single model.
+type ModelUsage struct {
+	Model            string  `json:"model"`
+	Provider         string  `json:"provider"`
+	Messages         int64   `json:"messages"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// RepositoryUsage is the usage within a single repository.
+type RepositoryUsage struct {
+	Path     string  `json:"path"`
+	Sessions int64   `json:"sessions"`
+	Tokens   int64   `json:"tokens"`
+	Cost     float64 `json:"cost"`
+}
+
+// ToolUsage is how often a tool was called.
+type ToolUsage struct {
+	Name  string `json:"name"`
+	Calls int64  `json:"calls"`
+}
+
+// Tokens returns the total number of tokens used.
+func (r *Report) Tokens() int64 {
+	return r.PromptTokens + r.CompletionTokens
+}
+
+// Build gathers the usage of every source since the given time.
+// Repositories without any session in the period are left out.
+func Build(ctx context.Context, sources []Source, since time.Time) (*Report, error) {
+	r := &Report{GeneratedAt: time.Now(), Since: since}
+	models := map[[2]string]*ModelUsage{}
+	tools := map[string]int64{}
+
+	for _, src := range sources {
+		totals, err := src.Querier.GetReportTotals(ctx, since.Unix())
+		if err != nil {
+			return nil, fmt.Errorf("%s: totals: %w", src.Path, err)
+		}
+		if totals.SessionCount == 0 {
+			continue
+		}
+		r.Sessions += totals.SessionCount
+		r.Messages += totals.MessageCount
+		r.PromptTokens += totals.PromptTokens
+		r.CompletionTokens += totals.CompletionTokens
+		r.Cost += totals.Cost
+		r.Repositories = append(r.Repositories, RepositoryUsage{
+			Path:     src.Path,
+			Sessions: totals.SessionCount,
+			Tokens:   totals.PromptTokens + totals.CompletionTokens,
+			Cost:     totals.Cost,
+		})
+
+		byModel, err := src.Querier.GetReportUsageByModel(ctx, since.Unix())
+		if err != nil {
+			return nil, fmt.Errorf("%s: models: %w", src.Path, err)
+		}
+		for _, row := range byModel {
+			key := [2]string{row.Model, row.Provider}
+			m, ok := models[key]
+			if !ok {
+				m = &ModelUsage{Model: row.Model, Provider: row.Provider}
+				models[key] = m
+			}
+			m.Messages += row.MessageCount
+			m.PromptTokens += row.PromptTokens
+			m.CompletionTokens += row.CompletionTokens
+			m.Cost += row.Cost
+		}
+
+		byTool, err := src.Querier.GetReportToolUsage(ctx, since.Unix())
+		if err != nil {
+			return nil, fmt.Errorf("%s: tools: %w", src.Path, err)
+		}
+		for _, row := range byTool {
+			tools[row.ToolName] += row.CallCount
+		}
+	}
+
+	for _, m := range models {
+		r.Models = append(r.Models, *m)
+	}
+	slices.SortFunc(r.Models, func(a, b ModelUsage) int {
+		return cmp.Or(cmp.Compare(b.Cost, a.Cost), cmp.Compare(b.Messages, a.Messages), strings.Compare(a.Model, b.Model))
+	})
+	slices.SortFunc(r.Repositories, func(a, b RepositoryUsage) int {
+		return cmp.Or(cmp.Compare(b.Cost, a.Cost), cmp.Compare(b.Tokens, a.Tokens), strings.Compare(a.Path, b.Path))
+	})
+	for name, calls := range tools {
+		r.Tools = append(r.Tools, ToolUsage{Name: name, Calls: calls})
+	}
+	slices.SortFunc(r.Tools, func(a, b ToolUsage) int {
+		return cmp.Or(cmp.Compare(b.Calls, a.Calls), strings.Compare(a.Name, b.Name))
+	})
+	return r, nil
+}
+
+// ParseSince parses a look-back period such as "30d", "2w" or "12h".
+// Days and weeks are accepted in addition to [time.ParseDuration] units.
+func ParseSince(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("invalid period %q", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period %q: use a value like 30d, 2w or 12h", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		" 1d": 24 * time.Hour,
+	} {
+		got, err := ParseSince(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "d", "-3d", "0w", "soon"} {
+		_, err := ParseSince(in)
+		require.Error(t, err, in)
+	}
+}
+
+// newTestSource creates a database with a single session that used the
+// given model and called the given tools.
+func newTestSource(t *testing.T, path, model string, cost float64, tools ...string) Source {
+	t.Helper()
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	q := db.New(conn)
+	sessions := session.NewService(q, conn)
+	sess, err := sessions.Create(t.Context(), "test")
+	require.NoError(t, err)
+	sess.PromptTokens = 1000
+	sess.CompletionTokens = 200
+	sess.Cost = cost
+	_, err = sessions.Save(t.Context(), sess)
+	require.NoError(t, err)
+
+	var parts []message.ContentPart
+	for i, name := range tools {
+		parts = append(parts, message.ToolCall{ID: string(rune('a' + i)), Name: name, Finished: true})
+	}
+	messages := message.NewService(q, message.WithDebounce(0))
+	_, err = messages.Create(t.Context(), sess.ID, message.CreateMessageParams{
+		Role:     message.Assistant,
+		Parts:    parts,
+		Model:    model,
+		Provider: "test",
+	})
+	require.NoError(t, err)
+
+	return Source{Path: path, Querier: q}
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	sources := []Source{
+		newTestSource(t, "~/cheap", "small", 0.25, "view", "bash"),
+		newTestSource(t, "~/pricey", "large", 1.5, "view", "edit", "view"),
+		newTestSource(t, "~/free", "small", 0),
+	}
+	// Only sessions created since the cut-off are counted, so a cut-off
+	// in the future yields an empty report.
+	empty, err := Build(t.Context(), sources, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, empty.Sessions)
+	require.Empty(t, empty.Repositories)
+
+	r, err := Build(t.Context(), sources, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), r.Sessions)
+	require.Equal(t, int64(3600), r.Tokens())
+	require.InDelta(t, 1.75, r.Cost, 1e-9)
+
+	require.Len(t, r.Repositories, 3)
+	require.Equal(t, "~/pricey", r.Repositories[0].Path)
+	require.Equal(t, ModelUsage{Model: "small", Provider: "test", Messages: 2}, r.Models[0])
+	require.Equal(t, []ToolUsage{
+		{Name: "view", Calls: 3},
+		{Name: "bash", Calls: 1},
+		{Name: "edit", Calls: 1},
+	}, r.Tools)
+
+	md := r.Markdown()
+	require.Contains(t, md, "## Models")
+	require.Contains(t, md, "| ~/pricey |")
+	require.Contains(t, md, "| view |")
+	require.Contains(t, md, "$1.75")
+
+	html, err := r.HTML()
+	require.NoError(t, err)
+	require.Contains(t, html, "<table")
+	require.Contains(t, html, "~/pricey")
+	require.NotContains(t, html, "http")
+}
@@ -0,0 +1,211 @@
+package rewind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/diff"
+	"github.com/charmbracelet/crush/internal/lcm/explorer"
+)
+
+// FileChange is the change to one file between two workspace states.
+type FileChange struct {
+	Path      string
+	Status    string // added, deleted, modified
+	Additions int
+	Deletions int
+}
+
+// WorkspaceDiff is the difference between two workspace states.
+type WorkspaceDiff struct {
+	// From and To describe the compared states.
+	From string
+	To   string
+	// Files lists the changed files, sorted by path.
+	Files []FileChange
+	// Patch is the unified diff of all changed files.
+	Patch string
+	// Summary is the DiffExplorer summary of Patch.
+	Summary string
+}
+
+// Differ compares the working tree state recorded for sessions. A
+// session's state is the content of every file it touched, as recorded by
+// the file history: its first version is the state at session start and
+// its latest version the state when the session ended.
+type Differ interface {
+	// DiffSessions compares the state left by fromSessionID with the state
+	// left by toSessionID. For a file only one session touched, the other
+	// side is the content that session found it in.
+	DiffSessions(ctx context.Context, fromSessionID, toSessionID string) (*WorkspaceDiff, error)
+	// DiffSinceStart compares the files a session touched as they were
+	// when it started with their current content on disk.
+	DiffSinceStart(ctx context.Context, sessionID string) (*WorkspaceDiff, error)
+}
+
+// ErrNoSessionFiles is returned when a session has no recorded file
+// history to diff.
+var ErrNoSessionFiles = errors.New("session has no recorded file changes")
+
+type differ struct {
+	q          db.Querier
+	workingDir string
+	registry   *explorer.Registry
+}
+
+// NewDiffer creates a Differ that reads file history from q and resolves
+// relative paths against workingDir.
+func NewDiffer(q db.Querier, workingDir string) Differ {
+	return &differ{q: q, workingDir: workingDir, registry: explorer.NewRegistry()}
+}
+
+// sessionFiles holds the first and latest recorded content per path.
+type sessionFiles struct {
+	first  map[string]string
+	latest map[string]string
+}
+
+func (d *differ) loadSession(ctx context.Context, sessionID string) (sessionFiles, error) {
+	rows, err := d.q.ListFilesBySession(ctx, sessionID)
+	if err != nil {
+		return sessionFiles{}, fmt.Errorf("listing files for session %s: %w", sessionID, err)
+	}
+	files := sessionFiles{first: make(map[string]string), latest: make(map[string]string)}
+	// Rows are ordered by version, so the last one seen per path is latest.
+	for _, row := range rows {
+		if _, ok := files.first[row.Path]; !ok {
+			files.first[row.Path] = row.Content
+		}
+		files.latest[row.Path] = row.Content
+	}
+	return files, nil
+}
+
+func (d *differ) DiffSessions(ctx context.Context, fromSessionID, toSessionID string) (*WorkspaceDiff, error) {
+	from, err := d.loadSession(ctx, fromSessionID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := d.loadSession(ctx, toSessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(from.latest) == 0 && len(to.latest) == 0 {
+		return nil, ErrNoSessionFiles
+	}
+
+	before := make(map[string]string)
+	after := make(map[string]string)
+	for path, content := range from.latest {
+		before[path] = content
+		if _, ok := to.latest[path]; !ok {
+			after[path] = content
+		}
+	}
+	for path, content := range to.latest {
+		after[path] = content
+		if _, ok := from.latest[path]; !ok {
+			before[path] = to.first[path]
+		}
+	}
+	return d.build(ctx, "session "+fromSessionID, "session "+toSessionID, before, after)
+}
+
+func (d *differ) DiffSinceStart(ctx context.Context, sessionID string) (*WorkspaceDiff, error) {
+	files, err := d.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(files.first) == 0 {
+		return nil, ErrNoSessionFiles
+	}
+
+	// Files gone from disk are left out of now and so show as deleted.
+	now := make(map[string]string, len(files.first))
+	for path := range files.first {
+		content, err := os.ReadFile(d.absPath(path))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		default:
+			now[path] = string(content)
+		}
+	}
+	return d.build(ctx, "start of session "+sessionID, "working tree", files.first, now)
+}
+
+// build diffs every path in before or after. A path missing from after is
+// treated as deleted, and one missing from before as added.
+func (d *differ) build(ctx context.Context, fromLabel, toLabel string, before, after map[string]string) (*WorkspaceDiff, error) {
+	paths := make([]string, 0, len(before)+len(after))
+	for path := range before {
+		paths = append(paths, path)
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	result := &WorkspaceDiff{From: fromLabel, To: toLabel}
+	var patch strings.Builder
+	for _, path := range paths {
+		old, hadOld := before[path]
+		cur, hasCur := after[path]
+		if hadOld && hasCur && old == cur {
+			continue
+		}
+		rel := d.relPath(path)
+		unified, additions, deletions := diff.GenerateDiff(old, cur, rel)
+		status := "modified"
+		switch {
+		case !hadOld || (old == "" && cur != ""):
+			status = "added"
+		case !hasCur:
+			status = "deleted"
+		}
+		result.Files = append(result.Files, FileChange{Path: path, Status: status, Additions: additions, Deletions: deletions})
+		patch.WriteString(unified)
+	}
+	result.Patch = patch.String()
+	if result.Patch == "" {
+		return result, nil
+	}
+
+	summary, err := d.registry.Explore(ctx, explorer.ExploreInput{Path: "workspace.diff", Content: []byte(result.Patch)})
+	if err != nil {
+		return nil, fmt.Errorf("summarizing diff: %w", err)
+	}
+	result.Summary = summary.Summary
+	return result, nil
+}
+
+// absPath resolves a history path against the working directory.
+func (d *differ) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(d.workingDir, path)
+}
+
+// relPath returns path relative to the working directory when it is
+// inside it.
+func (d *differ) relPath(path string) string {
+	if d.workingDir == "" || !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(d.workingDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
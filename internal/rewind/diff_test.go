@@ -0,0 +1,77 @@
+package rewind
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffer_DiffSessions(t *testing.T) {
+	t.Parallel()
+
+	q := new(mockQuerier)
+	q.On("ListFilesBySession", mock.Anything, "sess-a").Return([]db.File{
+		{Path: "/work/main.go", Content: "package main\n", Version: 0},
+		{Path: "/work/main.go", Content: "package main\n\nfunc main() {}\n", Version: 1},
+		{Path: "/work/only_a.txt", Content: "a\n", Version: 0},
+	}, nil)
+	q.On("ListFilesBySession", mock.Anything, "sess-b").Return([]db.File{
+		{Path: "/work/main.go", Content: "package main\n", Version: 0},
+		{Path: "/work/main.go", Content: "package main\n\nfunc main() { run() }\n", Version: 1},
+		{Path: "/work/new.go", Content: "", Version: 0},
+		{Path: "/work/new.go", Content: "package main\n", Version: 1},
+	}, nil)
+
+	d := NewDiffer(q, "/work")
+	result, err := d.DiffSessions(context.Background(), "sess-a", "sess-b")
+	require.NoError(t, err)
+	require.Equal(t, []FileChange{
+		{Path: "/work/main.go", Status: "modified", Additions: 1, Deletions: 1},
+		{Path: "/work/new.go", Status: "added", Additions: 1},
+	}, result.Files)
+	require.Contains(t, result.Patch, "--- a/main.go\n+++ b/main.go\n")
+	require.Contains(t, result.Summary, "Files changed: 2")
+	require.Contains(t, result.Summary, "main.go (modified, +1 -1, 1 hunk)")
+	q.AssertExpectations(t)
+}
+
+func TestDiffer_DiffSinceStart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kept.txt"), []byte("one\ntwo\nthree\n"), 0o644))
+
+	q := new(mockQuerier)
+	q.On("ListFilesBySession", mock.Anything, "sess-1").Return([]db.File{
+		{Path: filepath.Join(dir, "kept.txt"), Content: "one\nthree\n", Version: 0},
+		{Path: filepath.Join(dir, "kept.txt"), Content: "one\ntwo\n", Version: 1},
+		{Path: "gone.txt", Content: "bye\n", Version: 0},
+		{Path: "same.txt", Content: "x\n", Version: 0},
+	}, nil)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "same.txt"), []byte("x\n"), 0o644))
+
+	result, err := NewDiffer(q, dir).DiffSinceStart(context.Background(), "sess-1")
+	require.NoError(t, err)
+	require.Equal(t, "working tree", result.To)
+	require.Equal(t, []FileChange{
+		{Path: filepath.Join(dir, "kept.txt"), Status: "modified", Additions: 1},
+		{Path: "gone.txt", Status: "deleted", Deletions: 1},
+	}, result.Files)
+	require.Contains(t, result.Patch, "+++ b/kept.txt\n")
+	require.Contains(t, result.Summary, "Lines removed: 1")
+}
+
+func TestDiffer_NoFiles(t *testing.T) {
+	t.Parallel()
+
+	q := new(mockQuerier)
+	q.On("ListFilesBySession", mock.Anything, "empty").Return([]db.File{}, nil)
+
+	_, err := NewDiffer(q, t.TempDir()).DiffSinceStart(context.Background(), "empty")
+	require.ErrorIs(t, err, ErrNoSessionFiles)
+}
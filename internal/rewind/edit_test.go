@@ -33,6 +33,10 @@ func (m *editMockQuerier) ListMessagesInSeqRange(ctx context.Context, arg db.Lis
 }
 
 // Stub out remaining Querier methods so the mock compiles.
+func (m *editMockQuerier) AddExplorerMetrics(ctx context.Context, arg db.AddExplorerMetricsParams) error {
+	return nil
+}
+
 func (m *editMockQuerier) AddSnapshotFile(ctx context.Context, arg db.AddSnapshotFileParams) error {
 	return nil
 }
@@ -103,6 +107,14 @@ func (m *editMockQuerier) DeleteSessionMessages(ctx context.Context, id string)
 	return nil
 }
 
+func (m *editMockQuerier) DeleteSessionOverride(ctx context.Context, arg db.DeleteSessionOverrideParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) DeleteSessionOverrides(ctx context.Context, arg db.DeleteSessionOverridesParams) error {
+	return nil
+}
+
 func (m *editMockQuerier) DeleteSessionRankings(ctx context.Context, arg db.DeleteSessionRankingsParams) error {
 	return nil
 }
@@ -207,6 +219,18 @@ func (m *editMockQuerier) GetRepoMapFileCacheByPath(ctx context.Context, arg db.
 	return db.RepoMapFileCache{}, nil
 }
 
+func (m *editMockQuerier) GetReportToolUsage(ctx context.Context, createdAt int64) ([]db.GetReportToolUsageRow, error) {
+	return nil, nil
+}
+
+func (m *editMockQuerier) GetReportTotals(ctx context.Context, createdAt int64) (db.GetReportTotalsRow, error) {
+	return db.GetReportTotalsRow{}, nil
+}
+
+func (m *editMockQuerier) GetReportUsageByModel(ctx context.Context, createdAt int64) ([]db.GetReportUsageByModelRow, error) {
+	return nil, nil
+}
+
 func (m *editMockQuerier) GetSessionByID(ctx context.Context, id string) (db.Session, error) {
 	return db.Session{}, nil
 }
@@ -279,6 +303,10 @@ func (m *editMockQuerier) InsertRepoMapTag(ctx context.Context, arg db.InsertRep
 	return nil
 }
 
+func (m *editMockQuerier) ListUnfinishedAssistantMessages(ctx context.Context, updatedAt int64) ([]db.Message, error) {
+	return nil, nil
+}
+
 func (m *editMockQuerier) ListAllUserMessages(ctx context.Context) ([]db.Message, error) {
 	return nil, nil
 }
@@ -291,6 +319,46 @@ func (m *editMockQuerier) ListContentReplacementsByState(ctx context.Context, ar
 	return nil, nil
 }
 
+func (m *editMockQuerier) ImportMessage(ctx context.Context, arg db.ImportMessageParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) ImportSession(ctx context.Context, arg db.ImportSessionParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) GetExplorerCacheEntry(ctx context.Context, arg db.GetExplorerCacheEntryParams) (string, error) {
+	return "", nil
+}
+
+func (m *editMockQuerier) PruneExplorerCache(ctx context.Context, limit int64) error {
+	return nil
+}
+
+func (m *editMockQuerier) UpsertExplorerCacheEntry(ctx context.Context, arg db.UpsertExplorerCacheEntryParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) ClaimSessionLock(ctx context.Context, arg db.ClaimSessionLockParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *editMockQuerier) GetSessionLock(ctx context.Context, sessionID string) (db.SessionLock, error) {
+	return db.SessionLock{}, nil
+}
+
+func (m *editMockQuerier) ReleaseSessionLock(ctx context.Context, arg db.ReleaseSessionLockParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) RenewSessionLock(ctx context.Context, arg db.RenewSessionLockParams) (int64, error) {
+	return 0, nil
+}
+
+func (m *editMockQuerier) ListExplorerMetrics(ctx context.Context) ([]db.ExplorerMetric, error) {
+	return nil, nil
+}
+
 func (m *editMockQuerier) ListFilesByPath(ctx context.Context, id string) ([]db.File, error) {
 	return nil, nil
 }
@@ -347,6 +415,10 @@ func (m *editMockQuerier) ListRepoMapTags(ctx context.Context, id string) ([]db.
 	return nil, nil
 }
 
+func (m *editMockQuerier) ListSessionOverrides(ctx context.Context, arg db.ListSessionOverridesParams) ([]db.RepoMapSessionOverride, error) {
+	return nil, nil
+}
+
 func (m *editMockQuerier) ListSessionRankings(ctx context.Context, arg db.ListSessionRankingsParams) ([]db.RepoMapSessionRanking, error) {
 	return nil, nil
 }
@@ -444,6 +516,26 @@ func (m *editMockQuerier) UpsertRepoMapFileCache(ctx context.Context, arg db.Ups
 	return nil
 }
 
+func (m *editMockQuerier) GetRepoMapTagCacheEntry(ctx context.Context, arg db.GetRepoMapTagCacheEntryParams) (db.GetRepoMapTagCacheEntryRow, error) {
+	return db.GetRepoMapTagCacheEntryRow{}, nil
+}
+
+func (m *editMockQuerier) TouchRepoMapTagCacheEntry(ctx context.Context, arg db.TouchRepoMapTagCacheEntryParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) UpsertRepoMapTagCacheEntry(ctx context.Context, arg db.UpsertRepoMapTagCacheEntryParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) PruneRepoMapTagCache(ctx context.Context, arg db.PruneRepoMapTagCacheParams) error {
+	return nil
+}
+
+func (m *editMockQuerier) UpsertSessionOverride(ctx context.Context, arg db.UpsertSessionOverrideParams) error {
+	return nil
+}
+
 func (m *editMockQuerier) UpsertSessionRanking(ctx context.Context, arg db.UpsertSessionRankingParams) error {
 	return nil
 }
@@ -15,6 +15,11 @@ type mockQuerier struct {
 
 var _ db.Querier = (*mockQuerier)(nil)
 
+func (m *mockQuerier) AddExplorerMetrics(ctx context.Context, arg db.AddExplorerMetricsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
 func (m *mockQuerier) AddSnapshotFile(ctx context.Context, arg db.AddSnapshotFileParams) error {
 	args := m.Called(ctx, arg)
 	return args.Error(0)
@@ -154,6 +159,16 @@ func (m *mockQuerier) DeleteSessionMessages(ctx context.Context, sessionID strin
 	return args.Error(0)
 }
 
+func (m *mockQuerier) DeleteSessionOverride(ctx context.Context, arg db.DeleteSessionOverrideParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) DeleteSessionOverrides(ctx context.Context, arg db.DeleteSessionOverridesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
 func (m *mockQuerier) DeleteSessionRankings(ctx context.Context, arg db.DeleteSessionRankingsParams) error {
 	args := m.Called(ctx, arg)
 	return args.Error(0)
@@ -387,6 +402,33 @@ func (m *mockQuerier) GetRepoMapFileCacheByPath(ctx context.Context, arg db.GetR
 	return zero, args.Error(1)
 }
 
+func (m *mockQuerier) GetReportToolUsage(ctx context.Context, createdAt int64) ([]db.GetReportToolUsageRow, error) {
+	args := m.Called(ctx, createdAt)
+	var zero []db.GetReportToolUsageRow
+	if v := args.Get(0); v != nil {
+		return v.([]db.GetReportToolUsageRow), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
+func (m *mockQuerier) GetReportTotals(ctx context.Context, createdAt int64) (db.GetReportTotalsRow, error) {
+	args := m.Called(ctx, createdAt)
+	var zero db.GetReportTotalsRow
+	if v := args.Get(0); v != nil {
+		return v.(db.GetReportTotalsRow), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
+func (m *mockQuerier) GetReportUsageByModel(ctx context.Context, createdAt int64) ([]db.GetReportUsageByModelRow, error) {
+	args := m.Called(ctx, createdAt)
+	var zero []db.GetReportUsageByModelRow
+	if v := args.Get(0); v != nil {
+		return v.([]db.GetReportUsageByModelRow), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
 func (m *mockQuerier) GetSessionByID(ctx context.Context, id string) (db.Session, error) {
 	args := m.Called(ctx, id)
 	var zero db.Session
@@ -517,6 +559,15 @@ func (m *mockQuerier) InsertRepoMapTag(ctx context.Context, arg db.InsertRepoMap
 	return args.Error(0)
 }
 
+func (m *mockQuerier) ListUnfinishedAssistantMessages(ctx context.Context, updatedAt int64) ([]db.Message, error) {
+	args := m.Called(ctx, updatedAt)
+	var zero []db.Message
+	if v := args.Get(0); v != nil {
+		return v.([]db.Message), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
 func (m *mockQuerier) ListAllUserMessages(ctx context.Context) ([]db.Message, error) {
 	args := m.Called(ctx)
 	var zero []db.Message
@@ -544,6 +595,72 @@ func (m *mockQuerier) ListContentReplacementsByState(ctx context.Context, arg db
 	return zero, args.Error(1)
 }
 
+func (m *mockQuerier) ImportMessage(ctx context.Context, arg db.ImportMessageParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) ImportSession(ctx context.Context, arg db.ImportSessionParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) GetExplorerCacheEntry(ctx context.Context, arg db.GetExplorerCacheEntryParams) (string, error) {
+	args := m.Called(ctx, arg)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockQuerier) PruneExplorerCache(ctx context.Context, limit int64) error {
+	args := m.Called(ctx, limit)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) UpsertExplorerCacheEntry(ctx context.Context, arg db.UpsertExplorerCacheEntryParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) ClaimSessionLock(ctx context.Context, arg db.ClaimSessionLockParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	var zero int64
+	if v := args.Get(0); v != nil {
+		return v.(int64), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
+func (m *mockQuerier) GetSessionLock(ctx context.Context, sessionID string) (db.SessionLock, error) {
+	args := m.Called(ctx, sessionID)
+	var zero db.SessionLock
+	if v := args.Get(0); v != nil {
+		return v.(db.SessionLock), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
+func (m *mockQuerier) ReleaseSessionLock(ctx context.Context, arg db.ReleaseSessionLockParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) RenewSessionLock(ctx context.Context, arg db.RenewSessionLockParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	var zero int64
+	if v := args.Get(0); v != nil {
+		return v.(int64), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
+func (m *mockQuerier) ListExplorerMetrics(ctx context.Context) ([]db.ExplorerMetric, error) {
+	args := m.Called(ctx)
+	var zero []db.ExplorerMetric
+	if v := args.Get(0); v != nil {
+		return v.([]db.ExplorerMetric), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
 func (m *mockQuerier) ListFilesByPath(ctx context.Context, path string) ([]db.File, error) {
 	args := m.Called(ctx, path)
 	var zero []db.File
@@ -679,6 +796,15 @@ func (m *mockQuerier) ListRepoMapTags(ctx context.Context, repoKey string) ([]db
 	return zero, args.Error(1)
 }
 
+func (m *mockQuerier) ListSessionOverrides(ctx context.Context, arg db.ListSessionOverridesParams) ([]db.RepoMapSessionOverride, error) {
+	args := m.Called(ctx, arg)
+	var zero []db.RepoMapSessionOverride
+	if v := args.Get(0); v != nil {
+		return v.([]db.RepoMapSessionOverride), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
 func (m *mockQuerier) ListSessionRankings(ctx context.Context, arg db.ListSessionRankingsParams) ([]db.RepoMapSessionRanking, error) {
 	args := m.Called(ctx, arg)
 	var zero []db.RepoMapSessionRanking
@@ -843,6 +969,35 @@ func (m *mockQuerier) UpsertRepoMapFileCache(ctx context.Context, arg db.UpsertR
 	return args.Error(0)
 }
 
+func (m *mockQuerier) GetRepoMapTagCacheEntry(ctx context.Context, arg db.GetRepoMapTagCacheEntryParams) (db.GetRepoMapTagCacheEntryRow, error) {
+	args := m.Called(ctx, arg)
+	var zero db.GetRepoMapTagCacheEntryRow
+	if v := args.Get(0); v != nil {
+		return v.(db.GetRepoMapTagCacheEntryRow), args.Error(1)
+	}
+	return zero, args.Error(1)
+}
+
+func (m *mockQuerier) TouchRepoMapTagCacheEntry(ctx context.Context, arg db.TouchRepoMapTagCacheEntryParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) UpsertRepoMapTagCacheEntry(ctx context.Context, arg db.UpsertRepoMapTagCacheEntryParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) PruneRepoMapTagCache(ctx context.Context, arg db.PruneRepoMapTagCacheParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQuerier) UpsertSessionOverride(ctx context.Context, arg db.UpsertSessionOverrideParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
 func (m *mockQuerier) UpsertSessionRanking(ctx context.Context, arg db.UpsertSessionRankingParams) error {
 	args := m.Called(ctx, arg)
 	return args.Error(0)
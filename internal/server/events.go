@@ -4,16 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/charmbracelet/crush/internal/agent/notify"
 	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/app"
 	"github.com/charmbracelet/crush/internal/backend"
 	"github.com/charmbracelet/crush/internal/history"
+	"github.com/charmbracelet/crush/internal/lcm"
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/permission"
 	"github.com/charmbracelet/crush/internal/proto"
 	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/repomap"
 	"github.com/charmbracelet/crush/internal/session"
 	"github.com/charmbracelet/crush/internal/skills"
 )
@@ -85,12 +88,19 @@ func wrapEvent(ev any) *pubsub.Payload {
 			Payload: fileToProto(e.Payload),
 		})
 	case pubsub.Event[notify.Notification]:
+		if e.Payload.IsToolEvent() {
+			return envelope(pubsub.PayloadTypeToolEvent, pubsub.Event[proto.ToolEvent]{
+				Type:    e.Type,
+				Payload: toolEventToProto(e.Payload),
+			})
+		}
 		return envelope(pubsub.PayloadTypeAgentEvent, pubsub.Event[proto.AgentEvent]{
 			Type: e.Type,
 			Payload: proto.AgentEvent{
 				SessionID:    e.Payload.SessionID,
 				SessionTitle: e.Payload.SessionTitle,
 				Type:         proto.AgentEventType(e.Payload.Type),
+				DurationMs:   e.Payload.Duration.Milliseconds(),
 			},
 		})
 	case pubsub.Event[proto.ConfigChanged]:
@@ -100,6 +110,16 @@ func wrapEvent(ev any) *pubsub.Payload {
 			Type:    e.Type,
 			Payload: skillsEventToProto(e.Payload),
 		})
+	case pubsub.Event[lcm.CompactionEvent]:
+		return envelope(pubsub.PayloadTypeLCMEvent, pubsub.Event[proto.LCMEvent]{
+			Type:    e.Type,
+			Payload: compactionEventToProto(e.Payload),
+		})
+	case pubsub.Event[repomap.RefreshEvent]:
+		return envelope(pubsub.PayloadTypeRepoMapEvent, pubsub.Event[proto.RepoMapEvent]{
+			Type:    e.Type,
+			Payload: refreshEventToProto(e.Payload),
+		})
 	default:
 		slog.Warn("Unrecognized event type for SSE wrapping", "type", fmt.Sprintf("%T", ev))
 		return nil
@@ -115,10 +135,60 @@ func envelope(payloadType pubsub.PayloadType, inner any) *pubsub.Payload {
 	}
 	return &pubsub.Payload{
 		Type:    payloadType,
+		Version: pubsub.SchemaVersion,
 		Payload: raw,
 	}
 }
 
+func toolEventToProto(n notify.Notification) proto.ToolEvent {
+	t := proto.ToolEventStarted
+	if n.Type == notify.TypeToolFinished {
+		t = proto.ToolEventFinished
+	}
+	return proto.ToolEvent{
+		Type:       t,
+		SessionID:  n.SessionID,
+		ToolCallID: n.ToolCallID,
+		ToolName:   n.ToolName,
+		IsError:    n.IsError,
+	}
+}
+
+func compactionEventToProto(e lcm.CompactionEvent) proto.LCMEvent {
+	var t proto.LCMEventType
+	switch e.Type {
+	case lcm.CompactionStarted:
+		t = proto.LCMCompactionStarted
+	case lcm.CompactionCompleted:
+		t = proto.LCMCompactionCompleted
+	default:
+		t = proto.LCMCompactionFailed
+	}
+	return proto.LCMEvent{
+		Type:      t,
+		SessionID: e.SessionID,
+		Blocking:  e.Blocking,
+		Rounds:    e.Rounds,
+		Success:   e.Success,
+		Error:     e.Error,
+	}
+}
+
+func refreshEventToProto(e repomap.RefreshEvent) proto.RepoMapEvent {
+	t := proto.RepoMapRefreshCompleted
+	if e.Type == repomap.RefreshFailed {
+		t = proto.RepoMapRefreshFailed
+	}
+	return proto.RepoMapEvent{
+		Type:       t,
+		SessionID:  e.SessionID,
+		Tokens:     e.Tokens,
+		Forced:     e.Forced,
+		DurationMs: e.Duration.Milliseconds(),
+		Error:      e.Error,
+	}
+}
+
 func mcpEventTypeToProto(t mcp.EventType) proto.MCPEventType {
 	switch t {
 	case mcp.EventStateChanged:
@@ -221,6 +291,7 @@ func messageToProto(m message.Message) proto.Message {
 				Signature:  v.Signature,
 				StartedAt:  v.StartedAt,
 				FinishedAt: v.FinishedAt,
+				Tokens:     v.Tokens,
 			})
 		case message.ToolCall:
 			msg.Parts = append(msg.Parts, proto.ToolCall{
@@ -284,3 +355,40 @@ func messagesToProto(msgs []message.Message) []proto.Message {
 	}
 	return out
 }
+
+// eventTypeFilter restricts an SSE stream to a set of payload types. A
+// nil filter allows every type.
+type eventTypeFilter map[pubsub.PayloadType]struct{}
+
+// parseEventTypeFilter parses a comma-separated list of payload types.
+// An empty string yields a nil filter. Unknown types are rejected so
+// subscribers notice typos instead of silently receiving nothing.
+func parseEventTypeFilter(raw string) (eventTypeFilter, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	filter := make(eventTypeFilter)
+	for t := range strings.SplitSeq(raw, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !pubsub.IsKnownPayloadType(t) {
+			return nil, fmt.Errorf("unknown event type %q", t)
+		}
+		filter[t] = struct{}{}
+	}
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+func (f eventTypeFilter) allows(t pubsub.PayloadType) bool {
+	if f == nil {
+		return true
+	}
+	_, ok := f[t]
+	return ok
+}
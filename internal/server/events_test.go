@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/crush/internal/agent/notify"
+	"github.com/charmbracelet/crush/internal/lcm"
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/proto"
 	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/repomap"
 	"github.com/charmbracelet/crush/internal/skills"
 	"github.com/stretchr/testify/require"
 )
@@ -83,3 +87,115 @@ func TestSkillsEventToProto_RoundTrip(t *testing.T) {
 	require.Equal(t, proto.SkillStateError, decoded.Payload.States[1].State)
 	require.Equal(t, "bad frontmatter", decoded.Payload.States[1].Error)
 }
+
+// TestToolNotificationWrapsAsToolEvent verifies that tool lifecycle
+// notifications are routed to the tool_event payload type rather than
+// the turn-level agent_event type.
+func TestToolNotificationWrapsAsToolEvent(t *testing.T) {
+	t.Parallel()
+
+	env := wrapEvent(pubsub.Event[notify.Notification]{
+		Type: pubsub.CreatedEvent,
+		Payload: notify.Notification{
+			SessionID:  "s1",
+			Type:       notify.TypeToolFinished,
+			ToolCallID: "call-1",
+			ToolName:   "bash",
+			IsError:    true,
+		},
+	})
+	require.NotNil(t, env)
+	require.Equal(t, pubsub.PayloadTypeToolEvent, env.Type)
+	require.Equal(t, pubsub.SchemaVersion, env.Version)
+
+	var decoded pubsub.Event[proto.ToolEvent]
+	require.NoError(t, json.Unmarshal(env.Payload, &decoded))
+	require.Equal(t, proto.ToolEvent{
+		Type:       proto.ToolEventFinished,
+		SessionID:  "s1",
+		ToolCallID: "call-1",
+		ToolName:   "bash",
+		IsError:    true,
+	}, decoded.Payload)
+}
+
+func TestAgentNotificationKeepsDuration(t *testing.T) {
+	t.Parallel()
+
+	env := wrapEvent(pubsub.Event[notify.Notification]{
+		Type: pubsub.CreatedEvent,
+		Payload: notify.Notification{
+			SessionID:    "s1",
+			SessionTitle: "Title",
+			Type:         notify.TypeAgentFinished,
+			Duration:     90 * time.Second,
+		},
+	})
+	require.NotNil(t, env)
+	require.Equal(t, pubsub.PayloadTypeAgentEvent, env.Type)
+
+	var decoded pubsub.Event[proto.AgentEvent]
+	require.NoError(t, json.Unmarshal(env.Payload, &decoded))
+	require.Equal(t, int64(90_000), decoded.Payload.DurationMs)
+}
+
+func TestCompactionEventWrapsAsLCMEvent(t *testing.T) {
+	t.Parallel()
+
+	env := wrapEvent(pubsub.Event[lcm.CompactionEvent]{
+		Type: pubsub.CreatedEvent,
+		Payload: lcm.CompactionEvent{
+			Type:      lcm.CompactionCompleted,
+			SessionID: "s1",
+			Rounds:    2,
+			Success:   true,
+		},
+	})
+	require.NotNil(t, env)
+	require.Equal(t, pubsub.PayloadTypeLCMEvent, env.Type)
+
+	var decoded pubsub.Event[proto.LCMEvent]
+	require.NoError(t, json.Unmarshal(env.Payload, &decoded))
+	require.Equal(t, proto.LCMCompactionCompleted, decoded.Payload.Type)
+	require.Equal(t, 2, decoded.Payload.Rounds)
+	require.True(t, decoded.Payload.Success)
+}
+
+func TestRefreshEventWrapsAsRepoMapEvent(t *testing.T) {
+	t.Parallel()
+
+	env := wrapEvent(pubsub.Event[repomap.RefreshEvent]{
+		Type: pubsub.CreatedEvent,
+		Payload: repomap.RefreshEvent{
+			Type:      repomap.RefreshFailed,
+			SessionID: "s1",
+			Duration:  1500 * time.Millisecond,
+			Error:     "boom",
+		},
+	})
+	require.NotNil(t, env)
+	require.Equal(t, pubsub.PayloadTypeRepoMapEvent, env.Type)
+
+	var decoded pubsub.Event[proto.RepoMapEvent]
+	require.NoError(t, json.Unmarshal(env.Payload, &decoded))
+	require.Equal(t, proto.RepoMapRefreshFailed, decoded.Payload.Type)
+	require.Equal(t, int64(1500), decoded.Payload.DurationMs)
+	require.Equal(t, "boom", decoded.Payload.Error)
+}
+
+func TestParseEventTypeFilter(t *testing.T) {
+	t.Parallel()
+
+	f, err := parseEventTypeFilter("")
+	require.NoError(t, err)
+	require.True(t, f.allows(pubsub.PayloadTypeMessage))
+
+	f, err = parseEventTypeFilter(" tool_event , lcm_event ")
+	require.NoError(t, err)
+	require.True(t, f.allows(pubsub.PayloadTypeToolEvent))
+	require.True(t, f.allows(pubsub.PayloadTypeLCMEvent))
+	require.False(t, f.allows(pubsub.PayloadTypeMessage))
+
+	_, err = parseEventTypeFilter("tool_event,nope")
+	require.Error(t, err)
+}
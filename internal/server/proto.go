@@ -22,8 +22,14 @@ type controllerV1 struct {
 //	@Summary		Health check
 //	@Tags			system
 //	@Success		200
+//	@Failure		503	{object}	proto.Error
 //	@Router			/health [get]
-func (c *controllerV1) handleGetHealth(w http.ResponseWriter, _ *http.Request) {
+func (c *controllerV1) handleGetHealth(w http.ResponseWriter, r *http.Request) {
+	if err := c.backend.CheckDatabases(r.Context()); err != nil {
+		c.server.logError(r, "Database health check failed", "error", err)
+		jsonError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -245,12 +251,18 @@ func (c *controllerV1) handleGetWorkspaceProviders(w http.ResponseWriter, r *htt
 }
 
 // handleGetWorkspaceEvents streams workspace events as Server-Sent Events.
+// External subscribers can restrict the stream to a subset of payload
+// types with a comma-separated types query parameter, e.g.
+// ?types=tool_event,lcm_event.
 //
 //	@Summary		Stream workspace events (SSE)
 //	@Tags			workspaces
 //	@Produce		text/event-stream
-//	@Param			id	path	string	true	"Workspace ID"
+//	@Param			id			path	string	true	"Workspace ID"
+//	@Param			client_id	query	string	true	"Client ID (UUID)"
+//	@Param			types		query	string	false	"Comma-separated payload types to receive"
 //	@Success		200
+//	@Failure		400	{object}	proto.Error
 //	@Failure		404	{object}	proto.Error
 //	@Failure		500	{object}	proto.Error
 //	@Router			/workspaces/{id}/events [get]
@@ -261,6 +273,12 @@ func (c *controllerV1) handleGetWorkspaceEvents(w http.ResponseWriter, r *http.R
 	if !ok {
 		return
 	}
+	filter, err := parseEventTypeFilter(r.URL.Query().Get("types"))
+	if err != nil {
+		c.server.logError(r, "Invalid event type filter", "error", err)
+		jsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err := c.backend.AttachClient(id, clientID); err != nil {
 		c.handleError(w, r, err)
 		return
@@ -292,7 +310,7 @@ func (c *controllerV1) handleGetWorkspaceEvents(w http.ResponseWriter, r *http.R
 				return
 			}
 			wrapped := wrapEvent(ev.Payload)
-			if wrapped == nil {
+			if wrapped == nil || !filter.allows(wrapped.Type) {
 				continue
 			}
 			data, err := json.Marshal(wrapped)
@@ -52,6 +52,11 @@ func (s *stubCoordinator) StructuredSubagentFactory() agent.StructuredSubagentFa
 func (s *stubCoordinator) ResolveLCMModel(context.Context, config.SelectedModel, config.ProviderConfig) (agent.Model, error) {
 	return agent.Model{}, nil
 }
+func (s *stubCoordinator) AutoDowngradeEnabled(string) bool { return false } // XRUSH: auto downgrade
+func (s *stubCoordinator) SetAutoDowngrade(string, bool)    {}
+func (s *stubCoordinator) EstimateTurn(context.Context, string, string, ...message.Attachment) (agent.TurnEstimate, error) {
+	return agent.TurnEstimate{}, agent.ErrEstimateUnavailable
+}
 
 // stubSessions is a minimal session.Service that returns a fixed list
 // (and supports Get by ID). All other methods return zero values; the
@@ -0,0 +1,317 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/google/uuid"
+)
+
+// ErrSessionLocked is returned when another crush process holds the lock
+// of a session.
+var ErrSessionLocked = errors.New("session is in use by another crush process")
+
+// ErrSessionTakenOver is returned by a run that stopped because another
+// crush process took over its session.
+var ErrSessionTakenOver = errors.New("session was taken over by another crush process")
+
+const (
+	// lockHeartbeat is how often a held session lock is renewed.
+	lockHeartbeat = 10 * time.Second
+	// lockStaleAfter is how long a lock may go without a heartbeat before
+	// another process may claim it, e.g. after its owner crashed.
+	lockStaleAfter = 3 * lockHeartbeat
+)
+
+// LockedError wraps ErrSessionLocked with the process holding the lock.
+type LockedError struct {
+	SessionID  string
+	PID        int64
+	AcquiredAt time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s (pid %d, since %s)", ErrSessionLocked, e.PID, e.AcquiredAt.Format(time.Kitchen))
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrSessionLocked
+}
+
+type takeoverKey struct{}
+
+// WithTakeover returns a context asking Locks.Acquire to take the session
+// lock over from any other process holding it.
+func WithTakeover(ctx context.Context) context.Context {
+	return context.WithValue(ctx, takeoverKey{}, true)
+}
+
+// TakeoverRequested reports whether ctx carries a takeover request.
+func TakeoverRequested(ctx context.Context) bool {
+	v, _ := ctx.Value(takeoverKey{}).(bool)
+	return v
+}
+
+// Locks hands out advisory per-session locks stored in the database, so
+// that two crush processes sharing a data directory do not write to the
+// same session at once. A process keeps the locks it holds alive with a
+// heartbeat; a lock whose heartbeat stops is free to claim once stale.
+//
+// A nil *Locks hands out no-op locks.
+type Locks struct {
+	q     db.Querier
+	owner string
+	pid   int64
+	now   func() time.Time
+
+	// mu guards the fields below. It is never held across a database
+	// round-trip, so a slow database does not stall every session.
+	mu     sync.Mutex
+	held   map[string]*heldLock
+	onLost []func(sessionID string)
+	// releasing holds, per session, a channel closed once the lock row of
+	// a released lock is deleted; releases counts the deletions, so that
+	// a claim racing with one is retried.
+	releasing map[string]chan struct{}
+	releases  uint64
+}
+
+// heldLock is a session lock this process holds, shared by every Lock
+// acquired for the session until the last one is released.
+type heldLock struct {
+	refs int
+	lost atomic.Bool
+	stop chan struct{}
+}
+
+// NewLocks returns the session locks of this process.
+func NewLocks(q db.Querier) *Locks {
+	return &Locks{
+		q:     q,
+		owner: uuid.NewString(),
+		pid:   int64(os.Getpid()),
+		now:   time.Now,
+		held:  make(map[string]*heldLock),
+
+		releasing: make(map[string]chan struct{}),
+	}
+}
+
+// OnLost registers fn to be called when another process takes over a
+// session lock this process holds.
+func (l *Locks) OnLost(fn func(sessionID string)) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onLost = append(l.onLost, fn)
+}
+
+// Acquire takes the lock of a session, or shares it when this process
+// already holds it. When another live process holds it, Acquire returns a
+// *LockedError unless takeover is set.
+func (l *Locks) Acquire(ctx context.Context, sessionID string, takeover bool) (*Lock, error) {
+	if l == nil {
+		return &Lock{}, nil
+	}
+	for {
+		l.mu.Lock()
+		if h, ok := l.held[sessionID]; ok {
+			h.refs++
+			l.mu.Unlock()
+			return &Lock{locks: l, sessionID: sessionID, held: h}, nil
+		}
+		done, releasing := l.releasing[sessionID]
+		releases := l.releases
+		l.mu.Unlock()
+
+		if releasing {
+			select {
+			case <-done:
+				continue
+			case <-ctx.Done():
+				return nil, fmt.Errorf("failed to lock session: %w", ctx.Err())
+			}
+		}
+
+		staleBefore := l.now().Add(-lockStaleAfter).Unix()
+		if takeover {
+			staleBefore = math.MaxInt64
+		}
+		claimed, err := l.claim(ctx, sessionID, staleBefore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock session: %w", err)
+		}
+		if !claimed {
+			return nil, l.lockedError(ctx, sessionID)
+		}
+
+		l.mu.Lock()
+		if h, ok := l.held[sessionID]; ok {
+			// Another Acquire claimed it meanwhile.
+			h.refs++
+			l.mu.Unlock()
+			return &Lock{locks: l, sessionID: sessionID, held: h}, nil
+		}
+		if l.releases != releases || l.releasing[sessionID] != nil {
+			// A release may have deleted the row just claimed; claiming
+			// again is harmless since this process owns it.
+			l.mu.Unlock()
+			continue
+		}
+		h := &heldLock{refs: 1, stop: make(chan struct{})}
+		l.held[sessionID] = h
+		l.mu.Unlock()
+		go l.heartbeat(sessionID, h)
+		return &Lock{locks: l, sessionID: sessionID, held: h}, nil
+	}
+}
+
+func (l *Locks) claim(ctx context.Context, sessionID string, staleBefore int64) (bool, error) {
+	now := l.now().Unix()
+	n, err := l.q.ClaimSessionLock(ctx, db.ClaimSessionLockParams{
+		SessionID:   sessionID,
+		Owner:       l.owner,
+		Pid:         l.pid,
+		AcquiredAt:  now,
+		HeartbeatAt: now,
+		StaleBefore: staleBefore,
+	})
+	return n > 0, err
+}
+
+func (l *Locks) lockedError(ctx context.Context, sessionID string) error {
+	row, err := l.q.GetSessionLock(ctx, sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Debug("Failed to read session lock owner", "session_id", sessionID, "error", err)
+	}
+	return &LockedError{
+		SessionID:  sessionID,
+		PID:        row.Pid,
+		AcquiredAt: time.Unix(row.AcquiredAt, 0),
+	}
+}
+
+// heartbeat renews the lock of a session until it is released, and
+// reports it lost once another process has taken it over.
+func (l *Locks) heartbeat(sessionID string, h *heldLock) {
+	ticker := time.NewTicker(lockHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			if !l.renew(sessionID, h) {
+				return
+			}
+		}
+	}
+}
+
+// renew renews a held lock. It returns false once the lock is released or
+// lost.
+func (l *Locks) renew(sessionID string, h *heldLock) bool {
+	l.mu.Lock()
+	held := l.held[sessionID] == h
+	l.mu.Unlock()
+	if !held {
+		return false
+	}
+
+	n, err := l.q.RenewSessionLock(context.Background(), db.RenewSessionLockParams{
+		HeartbeatAt: l.now().Unix(),
+		SessionID:   sessionID,
+		Owner:       l.owner,
+	})
+	if err != nil {
+		// A busy database is no reason to give the session up; the
+		// next heartbeat tries again well before the lock goes stale.
+		slog.Warn("Failed to renew session lock", "session_id", sessionID, "error", err)
+		return true
+	}
+	if n > 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	if l.held[sessionID] != h {
+		// Released while renewing, which deleted the row.
+		l.mu.Unlock()
+		return false
+	}
+	delete(l.held, sessionID)
+	h.lost.Store(true)
+	callbacks := append([]func(string){}, l.onLost...)
+	l.mu.Unlock()
+
+	slog.Warn("Session lock taken over by another process", "session_id", sessionID)
+	for _, fn := range callbacks {
+		fn(sessionID)
+	}
+	return false
+}
+
+func (l *Locks) release(sessionID string, h *heldLock) {
+	l.mu.Lock()
+	h.refs--
+	if h.refs > 0 {
+		l.mu.Unlock()
+		return
+	}
+	close(h.stop)
+	if l.held[sessionID] != h {
+		l.mu.Unlock()
+		return
+	}
+	delete(l.held, sessionID)
+	done := make(chan struct{})
+	l.releasing[sessionID] = done
+	l.mu.Unlock()
+
+	if err := l.q.ReleaseSessionLock(context.Background(), db.ReleaseSessionLockParams{
+		SessionID: sessionID,
+		Owner:     l.owner,
+	}); err != nil {
+		slog.Warn("Failed to release session lock", "session_id", sessionID, "error", err)
+	}
+
+	l.mu.Lock()
+	delete(l.releasing, sessionID)
+	l.releases++
+	l.mu.Unlock()
+	close(done)
+}
+
+// Lock is one hold on a session lock, returned by Locks.Acquire.
+type Lock struct {
+	locks     *Locks
+	sessionID string
+	held      *heldLock
+	once      sync.Once
+}
+
+// Release gives up this hold on the lock. The lock itself is released
+// with its last hold.
+func (k *Lock) Release() {
+	if k.held == nil {
+		return
+	}
+	k.once.Do(func() { k.locks.release(k.sessionID, k.held) })
+}
+
+// Lost reports whether another process took the lock over while it was
+// held.
+func (k *Lock) Lost() bool {
+	return k.held != nil && k.held.lost.Load()
+}
@@ -0,0 +1,196 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLockTestDB returns the queries of a fresh database holding one
+// session, and that session's ID.
+func newLockTestDB(t *testing.T) (*db.Queries, string) {
+	t.Helper()
+
+	dataDir := t.TempDir()
+	t.Cleanup(func() {
+		require.NoError(t, db.Release(dataDir))
+		db.ResetPool()
+	})
+	conn, err := db.Connect(t.Context(), dataDir)
+	require.NoError(t, err)
+
+	q := db.New(conn)
+	sess, err := NewService(q, conn).Create(t.Context(), "locked")
+	require.NoError(t, err)
+	return q, sess.ID
+}
+
+func TestLocks_SecondProcessIsRefused(t *testing.T) {
+	q, sessionID := newLockTestDB(t)
+	first, second := NewLocks(q), NewLocks(q)
+
+	lock, err := first.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+
+	_, err = second.Acquire(t.Context(), sessionID, false)
+	require.ErrorIs(t, err, ErrSessionLocked)
+	var locked *LockedError
+	require.ErrorAs(t, err, &locked)
+	require.Equal(t, first.pid, locked.PID)
+
+	// The lock is free again once released.
+	lock.Release()
+	lock.Release()
+	other, err := second.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+	other.Release()
+}
+
+func TestLocks_SharedWithinProcess(t *testing.T) {
+	q, sessionID := newLockTestDB(t)
+	locks, other := NewLocks(q), NewLocks(q)
+
+	a, err := locks.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+	b, err := locks.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+
+	// Releasing one hold keeps the lock.
+	a.Release()
+	_, err = other.Acquire(t.Context(), sessionID, false)
+	require.ErrorIs(t, err, ErrSessionLocked)
+
+	b.Release()
+	_, err = other.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+}
+
+func TestLocks_StaleLockIsClaimed(t *testing.T) {
+	q, sessionID := newLockTestDB(t)
+	crashed, locks := NewLocks(q), NewLocks(q)
+
+	_, err := crashed.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+
+	locks.now = func() time.Time { return time.Now().Add(lockStaleAfter + time.Second) }
+	lock, err := locks.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+	lock.Release()
+}
+
+func TestLocks_Takeover(t *testing.T) {
+	q, sessionID := newLockTestDB(t)
+	first, second := NewLocks(q), NewLocks(q)
+
+	var lostSession string
+	first.OnLost(func(id string) { lostSession = id })
+
+	lock, err := first.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+	taken, err := second.Acquire(t.Context(), sessionID, TakeoverRequested(WithTakeover(t.Context())))
+	require.NoError(t, err)
+
+	// The first process learns of the takeover on its next heartbeat.
+	require.False(t, first.renew(sessionID, lock.held))
+	require.True(t, lock.Lost())
+	require.Equal(t, sessionID, lostSession)
+
+	// Releasing the lost lock leaves the new owner's lock alone.
+	lock.Release()
+	_, err = first.Acquire(t.Context(), sessionID, false)
+	require.ErrorIs(t, err, ErrSessionLocked)
+	require.True(t, second.renew(sessionID, taken.held))
+	taken.Release()
+}
+
+// blockingQuerier holds the ClaimSessionLock calls of one session until
+// unblocked.
+type blockingQuerier struct {
+	db.Querier
+	sessionID string
+	claiming  chan struct{}
+	unblock   chan struct{}
+	once      sync.Once
+}
+
+func (q *blockingQuerier) ClaimSessionLock(ctx context.Context, arg db.ClaimSessionLockParams) (int64, error) {
+	if arg.SessionID == q.sessionID {
+		q.once.Do(func() { close(q.claiming) })
+		<-q.unblock
+	}
+	return q.Querier.ClaimSessionLock(ctx, arg)
+}
+
+func TestLocks_ClaimDoesNotBlockHeldLocks(t *testing.T) {
+	q, sessionID := newLockTestDB(t)
+	slow, err := NewService(q, nil).Create(t.Context(), "slow")
+	require.NoError(t, err)
+
+	blocking := &blockingQuerier{Querier: q, sessionID: slow.ID, claiming: make(chan struct{}), unblock: make(chan struct{})}
+	locks := NewLocks(blocking)
+	lock, err := locks.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() {
+		other, err := locks.Acquire(t.Context(), slow.ID, false)
+		if err == nil {
+			other.Release()
+		}
+		errc <- err
+	}()
+	<-blocking.claiming
+
+	// Sharing, renewing and releasing the held lock go on while the
+	// claim waits on the database.
+	shared, err := locks.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+	require.True(t, locks.renew(sessionID, lock.held))
+	shared.Release()
+	lock.Release()
+
+	close(blocking.unblock)
+	require.NoError(t, <-errc)
+}
+
+func TestLocks_ConcurrentAcquireRelease(t *testing.T) {
+	q, sessionID := newLockTestDB(t)
+	locks, other := NewLocks(q), NewLocks(q)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 20 {
+				lock, err := locks.Acquire(t.Context(), sessionID, false)
+				if !assert.NoError(t, err) {
+					return
+				}
+				// While any hold is out, the row belongs to this process.
+				assert.True(t, locks.renew(sessionID, lock.held))
+				lock.Release()
+			}
+		})
+	}
+	wg.Wait()
+
+	require.Empty(t, locks.held)
+	lock, err := other.Acquire(t.Context(), sessionID, false)
+	require.NoError(t, err)
+	lock.Release()
+}
+
+func TestLocks_Nil(t *testing.T) {
+	t.Parallel()
+
+	var locks *Locks
+	locks.OnLost(func(string) {})
+	lock, err := locks.Acquire(t.Context(), "session", false)
+	require.NoError(t, err)
+	require.False(t, lock.Lost())
+	lock.Release()
+}
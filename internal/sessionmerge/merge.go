@@ -0,0 +1,517 @@
+// Package sessionmerge copies messages of one session into another, for
+// instance to carry an investigation into the session implementing its
+// findings. Merging is planned first so the result can be previewed, then
+// applied in one transaction.
+package sessionmerge
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/lcm"
+	"github.com/google/uuid"
+)
+
+// ErrSameSession is returned when a session is merged into itself.
+var ErrSameSession = errors.New("cannot merge a session into itself")
+
+// lcmFileRef matches LCM large file IDs (see lcm.GenerateFileID).
+var lcmFileRef = regexp.MustCompile(lcm.FileIDPrefix + `[0-9a-f]{16}`)
+
+// Options selects what to merge.
+type Options struct {
+	SourceID string
+	TargetID string
+	// MessageIDs are the source messages to merge, by ID or unique ID
+	// prefix. Empty merges the whole source session.
+	MessageIDs []string
+}
+
+// FileRemap is an LCM large file the merged messages reference, copied
+// into the target under a new ID because large files are only readable
+// from the session that stored them.
+type FileRemap struct {
+	From string
+	To   string
+	Path string
+	// Existing reports whether the target already holds the same content
+	// under To, in which case nothing is copied.
+	Existing bool
+}
+
+// Plan is a merge ready to apply. Its messages are copies with new IDs,
+// sequenced after the target's last message, with LCM file references
+// remapped and duplicate pinned notes removed.
+type Plan struct {
+	Source db.Session
+	Target db.Session
+
+	Messages []db.Message
+	// Widened is the number of messages added to the selection so every
+	// tool call keeps its result.
+	Widened int
+
+	// PinnedNotes are the source's pinned notes kept in the merged
+	// messages; DuplicateNotes those dropped because the target already
+	// pins them.
+	PinnedNotes    []string
+	DuplicateNotes []string
+
+	Files      []FileRemap
+	largeFiles []db.LcmLargeFile
+	// MissingFiles are referenced file IDs the source does not hold; the
+	// references are left as they are.
+	MissingFiles []string
+}
+
+// Preview plans merging the selected messages of the source session into
+// the target session, without changing either.
+func Preview(ctx context.Context, q db.Querier, opts Options) (*Plan, error) {
+	if opts.SourceID == opts.TargetID {
+		return nil, ErrSameSession
+	}
+	source, err := q.GetSessionByID(ctx, opts.SourceID)
+	if err != nil {
+		return nil, fmt.Errorf("loading source session: %w", err)
+	}
+	target, err := q.GetSessionByID(ctx, opts.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("loading target session: %w", err)
+	}
+	sourceMsgs, err := q.ListMessagesBySessionSeq(ctx, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("loading source messages: %w", err)
+	}
+	targetMsgs, err := q.ListMessagesBySessionSeq(ctx, target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("loading target messages: %w", err)
+	}
+
+	selected, widened, err := selectMessages(sourceMsgs, opts.MessageIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("session %s has no messages to merge", source.ID)
+	}
+
+	p := &Plan{Source: source, Target: target, Widened: widened}
+	if err := p.remapFiles(ctx, q, selected); err != nil {
+		return nil, err
+	}
+	replacer := p.fileReplacer()
+
+	pinned := make(map[string]bool)
+	for _, note := range pinnedNotes(targetMsgs) {
+		pinned[note] = true
+	}
+
+	var nextSeq int64
+	if len(targetMsgs) > 0 {
+		nextSeq = targetMsgs[len(targetMsgs)-1].Seq + 1
+	}
+	now := time.Now().Unix()
+	for _, m := range selected {
+		parts := replacer.Replace(m.Parts)
+		if m.Role == "user" {
+			parts, err = p.dedupeNotes(parts, pinned)
+			if err != nil {
+				return nil, fmt.Errorf("message %s: %w", m.ID, err)
+			}
+		}
+		m.ID = uuid.New().String()
+		m.SessionID = target.ID
+		m.Parts = parts
+		m.Seq = nextSeq
+		m.CreatedAt = now
+		m.UpdatedAt = now
+		nextSeq++
+		p.Messages = append(p.Messages, m)
+	}
+	return p, nil
+}
+
+// selectMessages returns the messages matching ids, or all of msgs when
+// ids is empty. A selected tool call pulls in the message with its result
+// and a selected result the message with its call; widened counts the
+// messages added that way.
+func selectMessages(msgs []db.Message, ids []string) (selected []db.Message, widened int, err error) {
+	if len(ids) == 0 {
+		return msgs, 0, nil
+	}
+	keep := make(map[string]bool)
+	for _, id := range ids {
+		var match string
+		for _, m := range msgs {
+			if !strings.HasPrefix(m.ID, id) {
+				continue
+			}
+			if match != "" {
+				return nil, 0, fmt.Errorf("message ID %q is ambiguous", id)
+			}
+			match = m.ID
+		}
+		if match == "" {
+			return nil, 0, fmt.Errorf("message %q not found in the source session", id)
+		}
+		keep[match] = true
+	}
+
+	// Tool calls and results are linked by call ID; a provider rejects a
+	// call without its result, and the other way around.
+	calls := make(map[string][]string)
+	for _, m := range msgs {
+		for _, id := range toolCallIDs(m.Parts) {
+			calls[id] = append(calls[id], m.ID)
+		}
+	}
+	for _, m := range msgs {
+		if !keep[m.ID] {
+			continue
+		}
+		for _, id := range toolCallIDs(m.Parts) {
+			for _, linked := range calls[id] {
+				if !keep[linked] {
+					keep[linked] = true
+					widened++
+				}
+			}
+		}
+	}
+
+	for _, m := range msgs {
+		if keep[m.ID] {
+			selected = append(selected, m)
+		}
+	}
+	return selected, widened, nil
+}
+
+// storedPart is a part as stored in the parts column of messages.
+type storedPart struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// toolCallIDs returns the IDs of the tool calls and tool results in the
+// stored parts.
+func toolCallIDs(raw string) []string {
+	var parts []storedPart
+	if err := json.Unmarshal([]byte(raw), &parts); err != nil {
+		return nil
+	}
+	var ids []string
+	for _, part := range parts {
+		var data struct {
+			ID         string `json:"id"`
+			ToolCallID string `json:"tool_call_id"`
+		}
+		switch part.Type {
+		case "tool_call":
+			if json.Unmarshal(part.Data, &data) == nil && data.ID != "" {
+				ids = append(ids, data.ID)
+			}
+		case "tool_result":
+			if json.Unmarshal(part.Data, &data) == nil && data.ToolCallID != "" {
+				ids = append(ids, data.ToolCallID)
+			}
+		}
+	}
+	return ids
+}
+
+// remapFiles plans copying the LCM large files referenced by msgs into
+// the target. The new ID derives from the content and the target, so
+// content the target already stored keeps its existing ID.
+func (p *Plan) remapFiles(ctx context.Context, q db.Querier, msgs []db.Message) error {
+	var ids []string
+	for _, m := range msgs {
+		for _, id := range lcmFileRef.FindAllString(m.Parts, -1) {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	for _, id := range ids {
+		file, err := q.GetLcmLargeFile(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && file.SessionID != p.Source.ID) {
+			p.MissingFiles = append(p.MissingFiles, id)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("loading LCM file %s: %w", id, err)
+		}
+
+		seed := file.Content.String
+		if !file.Content.Valid {
+			seed = id
+		}
+		remap := FileRemap{From: id, To: lcm.GenerateFileID(p.Target.ID, seed), Path: file.OriginalPath}
+		existing, err := q.GetLcmLargeFile(ctx, remap.To)
+		switch {
+		case err == nil && existing.SessionID == p.Target.ID:
+			remap.Existing = true
+		case err == nil || errors.Is(err, sql.ErrNoRows):
+			file.FileID = remap.To
+			file.SessionID = p.Target.ID
+			p.largeFiles = append(p.largeFiles, file)
+		default:
+			return fmt.Errorf("checking LCM file %s: %w", remap.To, err)
+		}
+		p.Files = append(p.Files, remap)
+	}
+	return nil
+}
+
+// fileReplacer rewrites the remapped file IDs.
+func (p *Plan) fileReplacer() *strings.Replacer {
+	pairs := make([]string, 0, 2*len(p.Files))
+	for _, f := range p.Files {
+		pairs = append(pairs, f.From, f.To)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// dedupeNotes removes from the <pinned_notes> blocks of the stored parts
+// the notes in pinned, dropping blocks left empty, and adds the notes it
+// keeps to pinned.
+func (p *Plan) dedupeNotes(raw string, pinned map[string]bool) (string, error) {
+	var parts []storedPart
+	if err := json.Unmarshal([]byte(raw), &parts); err != nil {
+		return "", fmt.Errorf("decoding parts: %w", err)
+	}
+	changed := false
+	for i, part := range parts {
+		if part.Type != "text" {
+			continue
+		}
+		var data map[string]json.RawMessage
+		var text string
+		if json.Unmarshal(part.Data, &data) != nil || json.Unmarshal(data["text"], &text) != nil {
+			continue
+		}
+		deduped, ok := p.dedupeBlock(text, pinned)
+		if !ok {
+			continue
+		}
+		data["text"], _ = json.Marshal(deduped)
+		parts[i].Data, _ = json.Marshal(data)
+		changed = true
+	}
+	if !changed {
+		return raw, nil
+	}
+	out, err := json.Marshal(parts)
+	if err != nil {
+		return "", fmt.Errorf("encoding parts: %w", err)
+	}
+	return string(out), nil
+}
+
+// dedupeBlock rewrites the <pinned_notes> block of text. It reports false
+// when text has no block.
+func (p *Plan) dedupeBlock(text string, pinned map[string]bool) (string, bool) {
+	const open, closing = "<pinned_notes>", "</pinned_notes>"
+	start := strings.Index(text, open)
+	if start < 0 {
+		return text, false
+	}
+	end := strings.Index(text[start:], closing)
+	if end < 0 {
+		return text, false
+	}
+	end += start
+
+	var kept []string
+	for line := range strings.SplitSeq(text[start+len(open):end], "\n") {
+		note := strings.TrimPrefix(strings.TrimSpace(line), "- ")
+		if note == "" {
+			continue
+		}
+		if pinned[note] {
+			p.DuplicateNotes = append(p.DuplicateNotes, note)
+			continue
+		}
+		pinned[note] = true
+		p.PinnedNotes = append(p.PinnedNotes, note)
+		kept = append(kept, strings.TrimSpace(line))
+	}
+
+	after := text[end+len(closing):]
+	if len(kept) == 0 {
+		return text[:start] + strings.TrimPrefix(after, "\n"), true
+	}
+	return text[:start] + open + "\n" + strings.Join(kept, "\n") + "\n" + closing + after, true
+}
+
+// pinnedNotes returns the notes of the <pinned_notes> blocks in the user
+// messages of msgs.
+func pinnedNotes(msgs []db.Message) []string {
+	var notes []string
+	for _, m := range msgs {
+		if m.Role != "user" || !strings.Contains(m.Parts, "pinned_notes") {
+			continue
+		}
+		var parts []storedPart
+		if err := json.Unmarshal([]byte(m.Parts), &parts); err != nil {
+			continue
+		}
+		for _, part := range parts {
+			var data struct {
+				Text string `json:"text"`
+			}
+			if part.Type != "text" || json.Unmarshal(part.Data, &data) != nil {
+				continue
+			}
+			_, rest, ok := strings.Cut(data.Text, "<pinned_notes>")
+			if !ok {
+				continue
+			}
+			body, _, ok := strings.Cut(rest, "</pinned_notes>")
+			if !ok {
+				continue
+			}
+			for line := range strings.SplitSeq(body, "\n") {
+				if note := strings.TrimPrefix(strings.TrimSpace(line), "- "); note != "" {
+					notes = append(notes, note)
+				}
+			}
+		}
+	}
+	return notes
+}
+
+// Apply writes the merge into the database in one transaction: the
+// copied LCM large files, the messages with their parts, and the LCM
+// context items that put the messages in the target's context.
+func Apply(ctx context.Context, conn *sql.DB, q *db.Queries, p *Plan) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+	qtx := q.WithTx(tx)
+
+	for _, f := range p.largeFiles {
+		if err := qtx.InsertLcmLargeFile(ctx, db.InsertLcmLargeFileParams{
+			FileID:             f.FileID,
+			SessionID:          f.SessionID,
+			OriginalPath:       f.OriginalPath,
+			Content:            f.Content,
+			TokenCount:         f.TokenCount,
+			ExplorationSummary: f.ExplorationSummary,
+			ExplorerUsed:       f.ExplorerUsed,
+		}); err != nil {
+			return fmt.Errorf("copying LCM file %s: %w", f.FileID, err)
+		}
+	}
+
+	for _, m := range p.Messages {
+		if err := qtx.ImportMessage(ctx, db.ImportMessageParams{
+			ID:               m.ID,
+			SessionID:        m.SessionID,
+			Role:             m.Role,
+			Parts:            m.Parts,
+			Model:            m.Model,
+			Provider:         m.Provider,
+			IsSummaryMessage: m.IsSummaryMessage,
+			Seq:              m.Seq,
+			TokenCount:       m.TokenCount,
+			CreatedAt:        m.CreatedAt,
+			UpdatedAt:        m.UpdatedAt,
+			FinishedAt:       m.FinishedAt,
+			SubmittedAt:      m.SubmittedAt,
+			SentToLlmAt:      m.SentToLlmAt,
+			FirstTokenAt:     m.FirstTokenAt,
+			CompletedAt:      m.CompletedAt,
+			PromptTokens:     m.PromptTokens,
+			CompletionTokens: m.CompletionTokens,
+			Cost:             m.Cost,
+			RoutingReason:    m.RoutingReason,
+		}); err != nil {
+			return fmt.Errorf("merging message %s: %w", m.ID, err)
+		}
+		if err := insertParts(ctx, qtx, m); err != nil {
+			return err
+		}
+		if err := qtx.AppendLcmContextItem(ctx, db.AppendLcmContextItemParams{
+			SessionID:   m.SessionID,
+			SessionID_2: m.SessionID,
+			ItemType:    "message",
+			MessageID:   sql.NullString{String: m.ID, Valid: true},
+			TokenCount:  m.TokenCount,
+		}); err != nil {
+			return fmt.Errorf("adding message %s to the LCM context: %w", m.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// insertParts writes the message_parts rows of m, as the message service
+// does: one row per part, binary parts left out.
+func insertParts(ctx context.Context, q *db.Queries, m db.Message) error {
+	var parts []storedPart
+	if err := json.Unmarshal([]byte(m.Parts), &parts); err != nil {
+		return fmt.Errorf("decoding parts of message %s: %w", m.ID, err)
+	}
+	for i, part := range parts {
+		if part.Type == "binary" {
+			continue
+		}
+		if _, err := q.InsertMessagePart(ctx, db.InsertMessagePartParams{
+			PartID:      uuid.New().String(),
+			MessageID:   m.ID,
+			SessionID:   m.SessionID,
+			PartType:    part.Type,
+			PartIndex:   int64(i),
+			ContentJson: string(part.Data),
+		}); err != nil {
+			return fmt.Errorf("merging part %d of message %s: %w", i, m.ID, err)
+		}
+	}
+	return nil
+}
+
+// Excerpt returns the first line of the text of m, leaving out template
+// blocks, or the tools it calls or answers, for previews.
+func Excerpt(m db.Message) string {
+	var parts []storedPart
+	if err := json.Unmarshal([]byte(m.Parts), &parts); err != nil {
+		return ""
+	}
+	var tools []string
+	for _, part := range parts {
+		var data struct {
+			Text string `json:"text"`
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(part.Data, &data) != nil {
+			continue
+		}
+		switch part.Type {
+		case "text":
+			text := data.Text
+			for _, tag := range []string{"pinned_notes", "working_set"} {
+				if before, rest, ok := strings.Cut(text, "<"+tag+">"); ok {
+					if _, after, ok := strings.Cut(rest, "</"+tag+">"); ok {
+						text = before + after
+					}
+				}
+			}
+			if line, _, _ := strings.Cut(strings.TrimSpace(text), "\n"); line != "" {
+				return line
+			}
+		case "tool_call", "tool_result":
+			tools = append(tools, data.Name)
+		}
+	}
+	return strings.Join(tools, ", ")
+}
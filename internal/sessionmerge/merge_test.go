@@ -0,0 +1,145 @@
+package sessionmerge
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/stretchr/testify/require"
+)
+
+func openDB(t *testing.T) (*db.Queries, *sql.DB) {
+	t.Helper()
+	conn, err := db.Connect(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return db.New(conn), conn
+}
+
+func createSession(t *testing.T, q *db.Queries, conn *sql.DB, title string, params ...message.CreateMessageParams) (session.Session, []message.Message) {
+	t.Helper()
+	sess, err := session.NewService(q, conn).Create(t.Context(), title)
+	require.NoError(t, err)
+	msgs := make([]message.Message, 0, len(params))
+	for _, p := range params {
+		msg, err := message.NewService(q).Create(t.Context(), sess.ID, p)
+		require.NoError(t, err)
+		msgs = append(msgs, msg)
+	}
+	return sess, msgs
+}
+
+func text(role message.MessageRole, s string) message.CreateMessageParams {
+	return message.CreateMessageParams{Role: role, Parts: []message.ContentPart{message.TextContent{Text: s}}}
+}
+
+func TestMergeSession(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	q, conn := openDB(t)
+
+	target, _ := createSession(t, q, conn, "Implement caching",
+		text(message.User, "<pinned_notes>\n- Keep the API stable\n</pinned_notes>\nAdd a cache"),
+		text(message.Assistant, "Added it"),
+	)
+	source, _ := createSession(t, q, conn, "Investigate latency",
+		text(message.User, "<pinned_notes>\n- Keep the API stable\n- Measure before changing\n</pinned_notes>\nWhy is it slow?"),
+		text(message.Assistant, "The profile is stored as file_0123456789abcdef"),
+	)
+	require.NoError(t, q.InsertLcmLargeFile(ctx, db.InsertLcmLargeFileParams{
+		FileID:       "file_0123456789abcdef",
+		SessionID:    source.ID,
+		OriginalPath: "cpu.pprof.txt",
+		Content:      sql.NullString{String: "hot path: json encoding", Valid: true},
+		TokenCount:   6,
+	}))
+
+	plan, err := Preview(ctx, q, Options{SourceID: source.ID, TargetID: target.ID})
+	require.NoError(t, err)
+	require.Len(t, plan.Messages, 2)
+	require.Equal(t, "Why is it slow?", Excerpt(plan.Messages[0]))
+	require.Equal(t, []string{"Measure before changing"}, plan.PinnedNotes)
+	require.Equal(t, []string{"Keep the API stable"}, plan.DuplicateNotes)
+	require.Len(t, plan.Files, 1)
+	remap := plan.Files[0]
+	require.Equal(t, "file_0123456789abcdef", remap.From)
+	require.NotEqual(t, remap.From, remap.To)
+	require.False(t, remap.Existing)
+
+	// Previewing changes nothing.
+	msgs, err := message.NewService(q).List(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+
+	require.NoError(t, Apply(ctx, conn, q, plan))
+
+	msgs, err = message.NewService(q).List(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, msgs, 4)
+	require.Equal(t, "<pinned_notes>\n- Measure before changing\n</pinned_notes>\nWhy is it slow?", msgs[2].Content().Text)
+	require.Equal(t, "The profile is stored as "+remap.To, msgs[3].Content().Text)
+
+	file, err := q.GetLcmLargeFile(ctx, remap.To)
+	require.NoError(t, err)
+	require.Equal(t, target.ID, file.SessionID)
+	require.Equal(t, "hot path: json encoding", file.Content.String)
+
+	items, err := q.ListLcmContextItems(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 2, "merged messages join the LCM context")
+	parts, err := q.GetMessagePartsByMessageID(ctx, msgs[3].ID)
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	require.Contains(t, parts[0].ContentJson, remap.To)
+
+	sourceMsgs, err := message.NewService(q).List(ctx, source.ID)
+	require.NoError(t, err)
+	require.Len(t, sourceMsgs, 2, "the source session is left as it was")
+
+	// Merging the same content again reuses the copied file.
+	again, err := Preview(ctx, q, Options{SourceID: source.ID, TargetID: target.ID})
+	require.NoError(t, err)
+	require.True(t, again.Files[0].Existing)
+	require.Equal(t, []string{"Keep the API stable", "Measure before changing"}, again.DuplicateNotes)
+}
+
+func TestMergeSelectedMessages(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	q, conn := openDB(t)
+
+	target, _ := createSession(t, q, conn, "Target", text(message.User, "Start"))
+	source, msgs := createSession(t, q, conn, "Source",
+		text(message.User, "Check the config"),
+		message.CreateMessageParams{Role: message.Assistant, Parts: []message.ContentPart{
+			message.ToolCall{ID: "call_1", Name: "view", Input: `{"file_path":"crush.json"}`, Finished: true},
+		}},
+		message.CreateMessageParams{Role: message.Tool, Parts: []message.ContentPart{
+			message.ToolResult{ToolCallID: "call_1", Name: "view", Content: "{}"},
+		}},
+		text(message.Assistant, "The config is empty"),
+	)
+
+	// Selecting the tool result pulls in its call.
+	plan, err := Preview(ctx, q, Options{SourceID: source.ID, TargetID: target.ID, MessageIDs: []string{msgs[2].ID[:12], msgs[3].ID}})
+	require.NoError(t, err)
+	require.Len(t, plan.Messages, 3)
+	require.Equal(t, 1, plan.Widened)
+	require.Equal(t, []string{"assistant", "tool", "assistant"}, []string{plan.Messages[0].Role, plan.Messages[1].Role, plan.Messages[2].Role})
+	require.Equal(t, "view", Excerpt(plan.Messages[0]))
+	require.Equal(t, int64(1), plan.Messages[0].Seq, "merged messages follow the target's")
+
+	require.NoError(t, Apply(ctx, conn, q, plan))
+	merged, err := message.NewService(q).List(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, merged, 4)
+	require.Equal(t, "call_1", merged[1].ToolCalls()[0].ID)
+	require.Equal(t, "call_1", merged[2].ToolResults()[0].ToolCallID)
+
+	_, err = Preview(ctx, q, Options{SourceID: source.ID, TargetID: target.ID, MessageIDs: []string{"not-a-message"}})
+	require.ErrorContains(t, err, "not found in the source session")
+	_, err = Preview(ctx, q, Options{SourceID: source.ID, TargetID: source.ID})
+	require.ErrorIs(t, err, ErrSameSession)
+}
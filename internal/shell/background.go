@@ -89,6 +89,12 @@ func GetBackgroundShellManager() *BackgroundShellManager {
 
 // Start creates and starts a new background shell with the given command.
 func (m *BackgroundShellManager) Start(ctx context.Context, workingDir string, blockFuncs []BlockFunc, command string, description string) (*BackgroundShell, error) {
+	return m.StartRemote(ctx, nil, workingDir, blockFuncs, command, description)
+}
+
+// StartRemote is like Start, but runs the command through remote when it
+// is non-nil; workingDir is then a directory on the remote side.
+func (m *BackgroundShellManager) StartRemote(ctx context.Context, remote Remote, workingDir string, blockFuncs []BlockFunc, command string, description string) (*BackgroundShell, error) {
 	// Check job limit
 	if m.shells.Len() >= MaxBackgroundJobs {
 		return nil, fmt.Errorf("maximum number of background jobs (%d) reached. Please terminate or wait for some jobs to complete", MaxBackgroundJobs)
@@ -99,6 +105,7 @@ func (m *BackgroundShellManager) Start(ctx context.Context, workingDir string, b
 	shell := NewShell(&Options{
 		WorkingDir: workingDir,
 		BlockFuncs: blockFuncs,
+		Remote:     remote,
 	})
 
 	shellCtx, cancel := context.WithCancel(ctx)
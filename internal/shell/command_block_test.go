@@ -1,6 +1,8 @@
 package shell
 
 import (
+	"context"
+	"io"
 	"strings"
 	"testing"
 
@@ -374,3 +376,63 @@ func TestSplitArgsFlags(t *testing.T) {
 		})
 	}
 }
+
+// recordingRemote records the commands handed to it.
+type recordingRemote struct {
+	dir      string
+	commands []string
+}
+
+func (r *recordingRemote) Exec(_ context.Context, dir, command string, stdout, _ io.Writer) error {
+	r.dir = dir
+	r.commands = append(r.commands, command)
+	_, _ = io.WriteString(stdout, "remote output")
+	return nil
+}
+
+func TestRemoteCommandBlocking(t *testing.T) {
+	t.Parallel()
+
+	remote := &recordingRemote{}
+	shell := NewShell(&Options{
+		WorkingDir: "/srv/app",
+		BlockFuncs: []BlockFunc{CommandsBlocker([]string{"curl"}), ArgumentsBlocker("go", []string{"test"}, []string{"-exec"})},
+		Remote:     remote,
+	})
+
+	for _, command := range []string{
+		"curl https://example.com",
+		"echo ok && 'curl' x",
+		"for f in *; do \"curl\" $f; done",
+		"go test -exec=./x ./...",
+		"(cd sub; curl x)",
+		"echo $(curl x) | wc -c",
+		"f() { curl x; }; f",
+		"command curl x",
+		"exec -a fetch curl x",
+		"eval 'curl x'",
+		"eval \"$CMD\"",
+		"bash -c 'echo; curl x'",
+		"sh -c \"eval 'curl x'\"",
+		"$FETCH x",
+		"\"$(which curl)\" x",
+	} {
+		_, _, err := shell.Exec(t.Context(), command)
+		require.ErrorContains(t, err, "not allowed for security reasons", command)
+	}
+	require.Empty(t, remote.commands)
+
+	for _, command := range []string{
+		"go test ./... | tee out.txt",
+		"[[ -n $HOME ]] && echo ${HOME/\\//x}",
+		"command -v curl",
+		"FOO=1 go test \"$PKG\"",
+		"bash -c 'go test ./...'",
+	} {
+		stdout, _, err := shell.Exec(t.Context(), command)
+		require.NoError(t, err, command)
+		require.Equal(t, "remote output", stdout)
+	}
+	require.Equal(t, "/srv/app", remote.dir)
+	require.Equal(t, "go test ./... | tee out.txt", remote.commands[0])
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"mvdan.cc/sh/moreinterp/coreutils"
@@ -156,3 +157,151 @@ func blockHandler(blockFuncs []BlockFunc) func(next interp.ExecHandlerFunc) inte
 		}
 	}
 }
+
+// checkBlocked applies blockFuncs to the simple commands of a parsed script
+// before it is handed to a [Remote], where the handlers above cannot see
+// it. Commands nested in subshells, pipelines, substitutions and function
+// bodies are checked like the others; the scripts given to eval and to
+// bash -c or sh -c are parsed and checked in turn; and the command, exec
+// and builtin prefixes are looked through, as the interpreter would.
+// Arguments are checked up to the first word the remote shell expands. A
+// command whose name or eval script is expanded cannot be checked, so it
+// is refused.
+func checkBlocked(file *syntax.File, blockFuncs []BlockFunc) error {
+	if len(blockFuncs) == 0 {
+		return nil
+	}
+	return checkBlockedNodes(file, blockFuncs, 0)
+}
+
+// maxBlockCheckDepth bounds the eval and bash -c scripts checked inside
+// one another.
+const maxBlockCheckDepth = 8
+
+func checkBlockedNodes(node syntax.Node, blockFuncs []BlockFunc, depth int) error {
+	if depth > maxBlockCheckDepth {
+		return fmt.Errorf("command is not allowed for security reasons: scripts nested more than %d deep", maxBlockCheckDepth)
+	}
+	var err error
+	syntax.Walk(node, func(node syntax.Node) bool {
+		if err != nil {
+			return false
+		}
+		call, ok := node.(*syntax.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		err = checkBlockedCall(call, blockFuncs, depth)
+		// Substitutions in the words are walked into as well.
+		return err == nil
+	})
+	return err
+}
+
+func checkBlockedCall(call *syntax.CallExpr, blockFuncs []BlockFunc, depth int) error {
+	var args []string
+	for _, word := range call.Args {
+		lit, ok := literalWord(word)
+		if !ok {
+			break
+		}
+		args = append(args, lit)
+	}
+	words := len(args)
+
+	args, lookup := unwrapBuiltins(args)
+	if lookup {
+		return nil
+	}
+	if len(args) == 0 {
+		if words < len(call.Args) {
+			return fmt.Errorf("command is not allowed for security reasons: its name is expanded and cannot be checked before running remotely")
+		}
+		return nil
+	}
+	for _, blockFunc := range blockFuncs {
+		if blockFunc(args) {
+			return fmt.Errorf("command is not allowed for security reasons: %q", args[0])
+		}
+	}
+
+	var script string
+	switch {
+	case args[0] == "eval":
+		if words < len(call.Args) {
+			return fmt.Errorf("command is not allowed for security reasons: eval of expanded words cannot be checked before running remotely")
+		}
+		script = strings.Join(args[1:], " ")
+	case (args[0] == "bash" || args[0] == "sh") && slices.Contains(args[1:], "-c"):
+		i := slices.Index(args, "-c")
+		if i+1 >= len(args) {
+			if words < len(call.Args) {
+				return fmt.Errorf("command is not allowed for security reasons: %s -c of an expanded script cannot be checked before running remotely", args[0])
+			}
+			return nil
+		}
+		script = args[i+1]
+	default:
+		return nil
+	}
+	file, err := syntax.NewParser().Parse(strings.NewReader(script), "")
+	if err != nil {
+		return fmt.Errorf("could not parse command: %w", err)
+	}
+	return checkBlockedNodes(file, blockFuncs, depth+1)
+}
+
+// unwrapBuiltins drops the command, exec and builtin prefixes, with their
+// options, that run the rest of the arguments as a command. lookup is set
+// for command -v and -V, which only look the command up.
+func unwrapBuiltins(args []string) (rest []string, lookup bool) {
+	for len(args) > 0 {
+		switch args[0] {
+		case "command", "exec", "builtin":
+		default:
+			return args, false
+		}
+		name := args[0]
+		args = args[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			opt := args[0]
+			args = args[1:]
+			if opt == "--" {
+				break
+			}
+			if name == "command" && (opt == "-v" || opt == "-V") {
+				return nil, true
+			}
+			if name == "exec" && opt == "-a" && len(args) > 0 {
+				// The name to pass as argv[0].
+				args = args[1:]
+			}
+		}
+	}
+	return args, false
+}
+
+// literalWord returns the value of a word made only of literal and quoted
+// literal parts.
+func literalWord(word *syntax.Word) (string, bool) {
+	var sb strings.Builder
+	for _, part := range word.Parts {
+		switch part := part.(type) {
+		case *syntax.Lit:
+			sb.WriteString(part.Value)
+		case *syntax.SglQuoted:
+			sb.WriteString(part.Value)
+		case *syntax.DblQuoted:
+			for _, inner := range part.Parts {
+				lit, ok := inner.(*syntax.Lit)
+				if !ok {
+					return "", false
+				}
+				sb.WriteString(lit.Value)
+			}
+		default:
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
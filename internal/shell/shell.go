@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"slices"
 	"strings"
 	"sync"
@@ -61,6 +62,14 @@ func (noopLogger) InfoPersist(msg string, keysAndValues ...any) {}
 // BlockFunc is a function that determines if a command should be blocked
 type BlockFunc func(args []string) bool
 
+// Remote runs commands outside the local interpreter, such as on another
+// host or inside a development environment. command is bash source, which
+// has been checked against the block list; dir is the working directory
+// on that side.
+type Remote interface {
+	Exec(ctx context.Context, dir, command string, stdout, stderr io.Writer) error
+}
+
 // Shell provides cross-platform shell execution with optional state persistence
 type Shell struct {
 	env        []string
@@ -68,6 +77,7 @@ type Shell struct {
 	mu         sync.Mutex
 	logger     Logger
 	blockFuncs []BlockFunc
+	remote     Remote
 }
 
 // Options for creating a new shell
@@ -76,6 +86,10 @@ type Options struct {
 	Env        []string
 	Logger     Logger
 	BlockFuncs []BlockFunc
+	// Remote, when set, runs commands instead of the local interpreter.
+	// WorkingDir is then a directory on the remote side, and Env is not
+	// passed on.
+	Remote Remote
 }
 
 // NewShell creates a new shell instance with the given options
@@ -107,6 +121,7 @@ func NewShell(opts *Options) *Shell {
 		env:        env,
 		logger:     logger,
 		blockFuncs: opts.BlockFuncs,
+		remote:     opts.Remote,
 	}
 }
 
@@ -268,6 +283,13 @@ func (s *Shell) execCommon(ctx context.Context, command string, stdout, stderr i
 		return fmt.Errorf("could not parse command: %w", err)
 	}
 
+	if s.remote != nil {
+		if err := checkBlocked(line, s.blockFuncs); err != nil {
+			return err
+		}
+		return s.remote.Exec(ctx, s.cwd, command, stdout, stderr)
+	}
+
 	runner, err = s.newInterp(nil, stdout, stderr)
 	if err != nil {
 		return fmt.Errorf("could not run command: %w", err)
@@ -303,5 +325,9 @@ func ExitCode(err error) int {
 	if exitErr, ok := errors.AsType[interp.ExitStatus](err); ok {
 		return int(exitErr)
 	}
+	// Remote commands run as processes of their own.
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
 	return 1
 }
@@ -0,0 +1,374 @@
+// Package staging holds proposed file edits for review before they are
+// written to disk. Each staged file is split into hunks that the user
+// accepts or rejects individually; committing writes only the accepted
+// hunks through the atomic write path.
+package staging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aymanbagabas/go-udiff"
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/history"
+)
+
+// hunkContextLines is the number of unchanged lines shown around a hunk.
+const hunkContextLines = 3
+
+// Decision is the review state of a single hunk.
+type Decision string
+
+const (
+	DecisionPending  Decision = "pending"
+	DecisionAccepted Decision = "accepted"
+	DecisionRejected Decision = "rejected"
+)
+
+// Hunk is one contiguous change within a staged file.
+type Hunk struct {
+	// Index is the 0-based position of the hunk within its file.
+	Index int
+	// Diff is the hunk rendered as a unified diff, including context.
+	Diff string
+	// Additions and Removals count the changed lines.
+	Additions int
+	Removals  int
+	// Decision is the user's review decision.
+	Decision Decision
+
+	edit udiff.Edit
+}
+
+// Change is a staged edit to a single file.
+type Change struct {
+	SessionID string
+	Path      string
+	// Original is the file content when the first edit was staged. It is
+	// empty for files that do not exist yet.
+	Original string
+	// Proposed is the content after applying every staged edit.
+	Proposed string
+	// Created reports whether the file did not exist when first staged.
+	Created   bool
+	Hunks     []Hunk
+	UpdatedAt time.Time
+
+	// revision counts the edits staged on top of each other, so a commit
+	// can tell whether the change was re-staged while it was writing.
+	revision int
+}
+
+// Pending reports whether any hunk still awaits a decision.
+func (c Change) Pending() bool {
+	return slices.ContainsFunc(c.Hunks, func(h Hunk) bool { return h.Decision == DecisionPending })
+}
+
+// CommitResult describes what was written for one file on commit.
+type CommitResult struct {
+	Path     string
+	Accepted int
+	Rejected int
+	// Written is false when every hunk was rejected and the file was left
+	// untouched.
+	Written bool
+}
+
+// Service stages proposed edits and commits the accepted hunks.
+type Service interface {
+	// Stage records proposed as the new content of path. Staging the same
+	// path again stacks on top of the earlier edit; the original content
+	// is kept and the hunks are recomputed with their decisions reset.
+	Stage(sessionID, path, original, proposed string, created bool) (Change, error)
+
+	// Content returns the staged content of path, if any, so subsequent
+	// edits apply on top of it.
+	Content(sessionID, path string) (string, bool)
+
+	// List returns the staged changes for the session sorted by path.
+	List(sessionID string) []Change
+
+	// Decide sets the decision for one hunk of a staged file.
+	Decide(sessionID, path string, hunk int, decision Decision) error
+
+	// Commit writes the accepted hunks of every staged file in the
+	// session and clears them. Pending hunks are treated as rejected. A
+	// file that cannot be written stays staged, along with the files not
+	// yet reached, and the error is returned with the results so far.
+	// Files keep their mode, and a file staged for creation is refused
+	// if its path exists by then. An edit staged while Commit runs stays
+	// staged.
+	Commit(ctx context.Context, sessionID string) ([]CommitResult, error)
+
+	// Discard drops every staged change for the session.
+	Discard(sessionID string)
+}
+
+type service struct {
+	mu      sync.Mutex
+	changes map[string]map[string]*Change
+	files   history.Service
+}
+
+// NewService creates a staging service. When files is non-nil, committed
+// content is recorded as a new history version.
+func NewService(files history.Service) Service {
+	return &service{
+		changes: make(map[string]map[string]*Change),
+		files:   files,
+	}
+}
+
+func (s *service) Stage(sessionID, path, original, proposed string, created bool) (Change, error) {
+	if sessionID == "" || path == "" {
+		return Change{}, fmt.Errorf("session ID and path are required to stage an edit")
+	}
+	path = filepath.Clean(path)
+	original, _ = fsext.ToUnixLineEndings(original)
+	proposed, _ = fsext.ToUnixLineEndings(proposed)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, ok := s.changes[sessionID]
+	if !ok {
+		files = make(map[string]*Change)
+		s.changes[sessionID] = files
+	}
+	change, ok := files[path]
+	if !ok {
+		change = &Change{SessionID: sessionID, Path: path, Original: original, Created: created}
+		files[path] = change
+	}
+	change.Proposed = proposed
+	change.UpdatedAt = time.Now()
+	change.revision++
+	hunks, err := computeHunks(path, change.Original, proposed)
+	if err != nil {
+		return Change{}, err
+	}
+	change.Hunks = hunks
+	if len(hunks) == 0 {
+		delete(files, path)
+	}
+	return *change, nil
+}
+
+func (s *service) Content(sessionID, path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	change, ok := s.changes[sessionID][filepath.Clean(path)]
+	if !ok {
+		return "", false
+	}
+	return change.Proposed, true
+}
+
+func (s *service) List(sessionID string) []Change {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := s.changes[sessionID]
+	out := make([]Change, 0, len(files))
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		c := *files[path]
+		c.Hunks = slices.Clone(c.Hunks)
+		out = append(out, c)
+	}
+	return out
+}
+
+func (s *service) Decide(sessionID, path string, hunk int, decision Decision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	change, ok := s.changes[sessionID][filepath.Clean(path)]
+	if !ok {
+		return fmt.Errorf("no staged edit for %s", path)
+	}
+	if hunk < 0 || hunk >= len(change.Hunks) {
+		return fmt.Errorf("hunk %d out of range for %s", hunk, path)
+	}
+	change.Hunks[hunk].Decision = decision
+	return nil
+}
+
+func (s *service) Commit(ctx context.Context, sessionID string) ([]CommitResult, error) {
+	// Snapshot the changes so Stage and Decide can run while the files
+	// are written.
+	s.mu.Lock()
+	files := make(map[string]Change, len(s.changes[sessionID]))
+	for path, change := range s.changes[sessionID] {
+		snapshot := *change
+		snapshot.Hunks = slices.Clone(change.Hunks)
+		files[path] = snapshot
+	}
+	s.mu.Unlock()
+
+	var results []CommitResult
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		result, content, err := s.commitFile(ctx, files[path])
+		if err != nil {
+			return results, err
+		}
+		s.unstage(files[path], content, result.Written)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// unstage drops the staged change of a committed file. A change that was
+// re-staged while it was being committed is kept, rebased onto the
+// content that was written so only the newer edit remains to review.
+func (s *service) unstage(committed Change, written string, wasWritten bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := s.changes[committed.SessionID]
+	change, ok := files[committed.Path]
+	if !ok {
+		return
+	}
+	if change.revision != committed.revision {
+		if !wasWritten {
+			return
+		}
+		hunks, err := computeHunks(change.Path, written, change.Proposed)
+		if err != nil {
+			slog.Error("Error rebasing staged edit", "file", change.Path, "error", err)
+			return
+		}
+		change.Original = written
+		change.Created = false
+		change.Hunks = hunks
+		if len(hunks) > 0 {
+			return
+		}
+	}
+	delete(files, committed.Path)
+	if len(files) == 0 {
+		delete(s.changes, committed.SessionID)
+	}
+}
+
+// commitFile writes the accepted hunks of change and returns the content
+// written, with Unix line endings.
+func (s *service) commitFile(ctx context.Context, change Change) (CommitResult, string, error) {
+	result := CommitResult{Path: change.Path}
+	var accepted []udiff.Edit
+	for _, h := range change.Hunks {
+		if h.Decision == DecisionAccepted {
+			accepted = append(accepted, h.edit)
+			result.Accepted++
+			continue
+		}
+		result.Rejected++
+	}
+	if len(accepted) == 0 {
+		return result, "", nil
+	}
+
+	content, err := udiff.Apply(change.Original, accepted)
+	if err != nil {
+		return result, "", fmt.Errorf("applying accepted hunks to %s: %w", change.Path, err)
+	}
+
+	// Refuse to clobber a file that changed on disk since it was staged.
+	data := content
+	perm := os.FileMode(0o644)
+	if !change.Created {
+		info, err := os.Stat(change.Path)
+		if err != nil {
+			return result, "", fmt.Errorf("reading %s: %w", change.Path, err)
+		}
+		perm = info.Mode().Perm()
+		current, err := os.ReadFile(change.Path)
+		if err != nil {
+			return result, "", fmt.Errorf("reading %s: %w", change.Path, err)
+		}
+		if normalized, _ := fsext.ToUnixLineEndings(string(current)); normalized != change.Original {
+			return result, "", fmt.Errorf("%s changed on disk since the edit was staged", change.Path)
+		}
+		if strings.Contains(string(current), "\r\n") {
+			data, _ = fsext.ToWindowsLineEndings(content)
+		}
+	} else if _, err := os.Lstat(change.Path); err == nil {
+		return result, "", fmt.Errorf("%s was created on disk since the edit was staged", change.Path)
+	} else if err := os.MkdirAll(filepath.Dir(change.Path), 0o755); err != nil {
+		return result, "", fmt.Errorf("creating parent directories for %s: %w", change.Path, err)
+	}
+
+	if err := fsext.WriteFileAtomic(change.Path, []byte(data), perm); err != nil {
+		return result, "", fmt.Errorf("writing %s: %w", change.Path, err)
+	}
+	result.Written = true
+
+	if s.files != nil {
+		if _, err := s.files.GetByPathAndSession(ctx, change.Path, change.SessionID); err != nil {
+			if _, err := s.files.Create(ctx, change.SessionID, change.Path, change.Original); err != nil {
+				slog.Error("Error creating file history", "file", change.Path, "error", err)
+			}
+		}
+		if _, err := s.files.CreateVersion(ctx, change.SessionID, change.Path, data); err != nil {
+			slog.Error("Error creating file history version", "file", change.Path, "error", err)
+		}
+	}
+	return result, content, nil
+}
+
+func (s *service) Discard(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.changes, sessionID)
+}
+
+// computeHunks splits the difference between original and proposed into
+// line-aligned hunks.
+func computeHunks(path, original, proposed string) ([]Hunk, error) {
+	edits := udiff.Lines(original, proposed)
+	name := strings.TrimPrefix(filepath.ToSlash(path), "/")
+	hunks := make([]Hunk, 0, len(edits))
+	for i, edit := range edits {
+		unified, err := udiff.ToUnified("a/"+name, "b/"+name, original, []udiff.Edit{edit}, hunkContextLines)
+		if err != nil {
+			return nil, fmt.Errorf("rendering hunk %d of %s: %w", i, path, err)
+		}
+		h := Hunk{Index: i, Decision: DecisionPending, edit: edit}
+		var body []string
+		for line := range strings.SplitSeq(unified, "\n") {
+			switch {
+			case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+				continue
+			case strings.HasPrefix(line, "+"):
+				h.Additions++
+			case strings.HasPrefix(line, "-"):
+				h.Removals++
+			}
+			body = append(body, line)
+		}
+		h.Diff = strings.TrimRight(strings.Join(body, "\n"), "\n")
+		hunks = append(hunks, h)
+	}
+	return hunks, nil
+}
+
+// Summary renders a short description of the staged change for tool
+// responses.
+func (c Change) Summary() string {
+	adds, dels := 0, 0
+	for _, h := range c.Hunks {
+		adds += h.Additions
+		dels += h.Removals
+	}
+	verb := "edit"
+	if c.Created {
+		verb = "creation"
+	}
+	return fmt.Sprintf("Staged %s of %s for review: %d hunk(s), +%d/-%d lines. The file on disk is unchanged until the user accepts the hunks.",
+		verb, c.Path, len(c.Hunks), adds, dels)
+}
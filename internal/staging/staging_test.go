@@ -0,0 +1,232 @@
+package staging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const original = "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\ntwelve\n"
+
+func TestStageSplitsHunks(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil)
+	proposed := "ONE\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\nTWELVE\n"
+	change, err := svc.Stage("s1", "/tmp/file.txt", original, proposed, false)
+	require.NoError(t, err)
+	require.Len(t, change.Hunks, 2)
+	require.True(t, change.Pending())
+	require.Equal(t, 1, change.Hunks[0].Additions)
+	require.Equal(t, 1, change.Hunks[0].Removals)
+	require.Contains(t, change.Hunks[1].Diff, "+TWELVE")
+
+	content, ok := svc.Content("s1", "/tmp/file.txt")
+	require.True(t, ok)
+	require.Equal(t, proposed, content)
+}
+
+func TestStageStacksEdits(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil)
+	first := "ONE\n" + original[len("one\n"):]
+	_, err := svc.Stage("s1", "f.txt", original, first, false)
+	require.NoError(t, err)
+
+	second := first[:len(first)-len("twelve\n")] + "TWELVE\n"
+	change, err := svc.Stage("s1", "f.txt", first, second, false)
+	require.NoError(t, err)
+	require.Equal(t, original, change.Original, "the first original is kept")
+	require.Len(t, change.Hunks, 2)
+
+	// Staging the original content again drops the change.
+	_, err = svc.Stage("s1", "f.txt", second, original, false)
+	require.NoError(t, err)
+	require.Empty(t, svc.List("s1"))
+}
+
+func TestCommitAcceptedHunks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o644))
+
+	svc := NewService(nil)
+	proposed := "ONE\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\nTWELVE\n"
+	_, err := svc.Stage("s1", path, original, proposed, false)
+	require.NoError(t, err)
+	require.NoError(t, svc.Decide("s1", path, 1, DecisionAccepted))
+	require.Error(t, svc.Decide("s1", path, 5, DecisionAccepted))
+
+	results, err := svc.Commit(t.Context(), "s1")
+	require.NoError(t, err)
+	require.Equal(t, []CommitResult{{Path: path, Accepted: 1, Rejected: 1, Written: true}}, results)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\nTWELVE\n", string(data))
+	require.Empty(t, svc.List("s1"))
+}
+
+func TestCommitRejectsAll(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o644))
+
+	svc := NewService(nil)
+	_, err := svc.Stage("s1", path, original, "changed\n", false)
+	require.NoError(t, err)
+
+	results, err := svc.Commit(t.Context(), "s1")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.False(t, results[0].Written)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, original, string(data))
+}
+
+func TestCommitDetectsConflict(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o644))
+
+	svc := NewService(nil)
+	_, err := svc.Stage("s1", path, original, "changed\n", false)
+	require.NoError(t, err)
+	require.NoError(t, svc.Decide("s1", path, 0, DecisionAccepted))
+	require.NoError(t, os.WriteFile(path, []byte("edited elsewhere\n"), 0o644))
+
+	_, err = svc.Commit(t.Context(), "s1")
+	require.ErrorContains(t, err, "changed on disk")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "edited elsewhere\n", string(data))
+}
+
+func TestCommitCreatesFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "new.txt")
+
+	svc := NewService(nil)
+	change, err := svc.Stage("s1", path, "", "hello\n", true)
+	require.NoError(t, err)
+	require.Contains(t, change.Summary(), "Staged creation")
+	require.NoError(t, svc.Decide("s1", path, 0, DecisionAccepted))
+
+	_, err = svc.Commit(t.Context(), "s1")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", string(data))
+}
+
+func TestCommitKeepsUnwrittenFilesStaged(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(first, []byte(original), 0o644))
+	// A file staged for creation fails when something took its path.
+	second := filepath.Join(dir, "b")
+	require.NoError(t, os.Mkdir(second, 0o755))
+	third := filepath.Join(dir, "c.txt")
+
+	svc := NewService(nil)
+	for _, stage := range []struct {
+		path     string
+		original string
+		created  bool
+	}{{first, original, false}, {second, "", true}, {third, "", true}} {
+		_, err := svc.Stage("s1", stage.path, stage.original, "changed\n", stage.created)
+		require.NoError(t, err)
+		require.NoError(t, svc.Decide("s1", stage.path, 0, DecisionAccepted))
+	}
+
+	results, err := svc.Commit(t.Context(), "s1")
+	require.ErrorContains(t, err, second+" was created on disk")
+	require.Equal(t, []CommitResult{{Path: first, Accepted: 1, Written: true}}, results)
+
+	data, err := os.ReadFile(first)
+	require.NoError(t, err)
+	require.Equal(t, "changed\n", string(data))
+	require.NoFileExists(t, third)
+
+	staged := svc.List("s1")
+	require.Len(t, staged, 2)
+	require.Equal(t, second, staged[0].Path)
+	require.Equal(t, third, staged[1].Path)
+}
+
+func TestCommitKeepsFileMode(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "run.sh")
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o755))
+
+	svc := NewService(nil)
+	_, err := svc.Stage("s1", path, original, "changed\n", false)
+	require.NoError(t, err)
+	require.NoError(t, svc.Decide("s1", path, 0, DecisionAccepted))
+	_, err = svc.Commit(t.Context(), "s1")
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+}
+
+func TestCommitDetectsCreatedConflict(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "new.txt")
+
+	svc := NewService(nil)
+	_, err := svc.Stage("s1", path, "", "hello\n", true)
+	require.NoError(t, err)
+	require.NoError(t, svc.Decide("s1", path, 0, DecisionAccepted))
+	require.NoError(t, os.WriteFile(path, []byte("created elsewhere\n"), 0o644))
+
+	_, err = svc.Commit(t.Context(), "s1")
+	require.ErrorContains(t, err, "created on disk")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "created elsewhere\n", string(data))
+	require.Len(t, svc.List("s1"), 1)
+}
+
+func TestUnstageKeepsRestagedChange(t *testing.T) {
+	t.Parallel()
+
+	s := NewService(nil).(*service)
+	first := "ONE\n" + original[len("one\n"):]
+	_, err := s.Stage("s1", "f.txt", original, first, false)
+	require.NoError(t, err)
+	committed := *s.changes["s1"]["f.txt"]
+
+	// Another edit is staged while the first one is being written.
+	second := first[:len(first)-len("twelve\n")] + "TWELVE\n"
+	_, err = s.Stage("s1", "f.txt", first, second, false)
+	require.NoError(t, err)
+
+	s.unstage(committed, first, true)
+	staged := s.List("s1")
+	require.Len(t, staged, 1)
+	require.Equal(t, first, staged[0].Original, "the kept edit is rebased onto the written content")
+	require.Len(t, staged[0].Hunks, 1)
+	require.Contains(t, staged[0].Hunks[0].Diff, "+TWELVE")
+
+	// Once nothing was re-staged, the change is dropped.
+	s.unstage(*s.changes["s1"]["f.txt"], second, true)
+	require.Empty(t, s.List("s1"))
+}
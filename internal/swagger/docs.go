@@ -83,6 +83,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK"
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Error"
+                        }
                     }
                 }
             }
@@ -3477,13 +3483,15 @@ const docTemplate = `{
                 0,
                 1,
                 2,
-                3
+                3,
+                4
             ],
             "x-enum-varnames": [
                 "MCPStateDisabled",
                 "MCPStateStarting",
                 "MCPStateConnected",
-                "MCPStateError"
+                "MCPStateError",
+                "MCPStateIdle"
             ]
         },
         "proto.MessageRole": {
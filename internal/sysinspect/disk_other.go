@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package sysinspect
+
+func diskUsage(string) (DiskUsage, error) {
+	return DiskUsage{}, ErrUnsupported
+}
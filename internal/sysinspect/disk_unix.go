@@ -0,0 +1,15 @@
+//go:build linux || darwin || freebsd
+
+package sysinspect
+
+import "golang.org/x/sys/unix"
+
+func diskUsage(path string) (DiskUsage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return DiskUsage{}, err
+	}
+	// Free is what an unprivileged user may still write, as df reports.
+	bsize := uint64(st.Bsize)
+	return DiskUsage{Path: path, Total: uint64(st.Blocks) * bsize, Free: uint64(st.Bavail) * bsize}, nil
+}
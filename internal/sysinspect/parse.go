@@ -0,0 +1,274 @@
+package sysinspect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The parsers below read the output of the platform tools. They are not
+// build constrained so each is tested on every platform.
+
+// parsePS parses the output of
+// ps -axww -o pid=,ppid=,user=,%cpu=,rss=,args=.
+func parsePS(out []byte) []Process {
+	var procs []Process
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		cpu, _ := strconv.ParseFloat(fields[3], 64)
+		rss, _ := strconv.ParseUint(fields[4], 10, 64)
+		p := Process{PID: pid, PPID: ppid, User: fields[2], CPU: cpu, RSS: rss * 1024}
+		if len(fields) > 5 {
+			p.Command = strings.Join(fields[5:], " ")
+			p.Name = filepath.Base(fields[5])
+		}
+		procs = append(procs, p)
+	}
+	return procs
+}
+
+// parseLsof parses the output of lsof -nP -i -F pcPnT: a p line per
+// process, then f, P, n and T lines per socket.
+func parseLsof(out []byte) []Port {
+	var (
+		ports []Port
+		pid   int
+		name  string
+		cur   *Port
+	)
+	flush := func() {
+		if cur != nil && cur.Port > 0 {
+			ports = append(ports, *cur)
+		}
+		cur = nil
+	}
+	for line := range strings.SplitSeq(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		value := line[1:]
+		switch line[0] {
+		case 'p':
+			flush()
+			pid, _ = strconv.Atoi(value)
+			name = ""
+		case 'c':
+			name = value
+		case 'f':
+			flush()
+			cur = &Port{PID: pid, Process: name}
+		case 'P':
+			if cur != nil {
+				cur.Protocol = strings.ToLower(value)
+			}
+		case 'n':
+			if cur == nil {
+				continue
+			}
+			local, remote, _ := strings.Cut(value, "->")
+			cur.Local = local
+			cur.Port = portOf(local)
+			cur.Remote = remote
+			if strings.HasPrefix(local, "[") {
+				cur.Protocol += "6"
+			}
+		case 'T':
+			if state, ok := strings.CutPrefix(value, "ST="); ok && cur != nil {
+				cur.State = state
+			}
+		}
+	}
+	flush()
+	return ports
+}
+
+// parseNetstat parses the output of Windows netstat -ano.
+func parseNetstat(out []byte) []Port {
+	var ports []Port
+	for line := range strings.SplitSeq(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		proto := strings.ToLower(fields[0])
+		if proto != "tcp" && proto != "udp" {
+			continue
+		}
+		p := Port{Protocol: proto, Local: fields[1], Port: portOf(fields[1])}
+		if strings.HasPrefix(p.Local, "[") {
+			p.Protocol += "6"
+		}
+		if remote := fields[2]; remote != "*:*" && !strings.HasSuffix(remote, ":0") {
+			p.Remote = remote
+		}
+		if proto == "tcp" && len(fields) >= 5 {
+			p.State = fields[3]
+			if p.State == "LISTENING" {
+				p.State = "LISTEN"
+			}
+		}
+		p.PID, _ = strconv.Atoi(fields[len(fields)-1])
+		ports = append(ports, p)
+	}
+	return ports
+}
+
+// tcpStates are the TCP states of /proc/net/tcp, by their hex code.
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED", "02": "SYN_SENT", "03": "SYN_RECV", "04": "FIN_WAIT1",
+	"05": "FIN_WAIT2", "06": "TIME_WAIT", "07": "CLOSE", "08": "CLOSE_WAIT",
+	"09": "LAST_ACK", "0A": "LISTEN", "0B": "CLOSING",
+}
+
+// procNetSocket is a socket of /proc/net/{tcp,udp}[6] with the inode that
+// links it to its owner's file descriptors.
+type procNetSocket struct {
+	Port
+	inode string
+}
+
+// parseProcNet parses a /proc/net/{tcp,udp}[6] table for protocol proto.
+func parseProcNet(data []byte, proto string) []procNetSocket {
+	var socks []procNetSocket
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 10 {
+			continue
+		}
+		local, port := decodeProcNetAddr(fields[1])
+		if local == "" {
+			continue
+		}
+		s := procNetSocket{Port: Port{Protocol: proto, Local: local, Port: port}, inode: fields[9]}
+		if remote, rport := decodeProcNetAddr(fields[2]); rport != 0 {
+			s.Remote = remote
+		}
+		if strings.HasPrefix(proto, "tcp") {
+			s.State = tcpStates[fields[3]]
+		}
+		socks = append(socks, s)
+	}
+	return socks
+}
+
+// decodeProcNetAddr decodes an address of /proc/net: a hex IP, in host
+// byte order per 32-bit word, a colon and a hex port.
+func decodeProcNetAddr(s string) (string, int) {
+	ipHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", 0
+	}
+	raw, err := hex.DecodeString(ipHex)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", 0
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", 0
+	}
+	ip := make(net.IP, len(raw))
+	for w := 0; w < len(raw); w += 4 {
+		for b := range 4 {
+			ip[w+b] = raw[w+3-b]
+		}
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(port))), int(port)
+}
+
+// portOf returns the port of an address such as 127.0.0.1:8080, [::]:443
+// or *:53, or 0.
+func portOf(addr string) int {
+	i := strings.LastIndexByte(addr, ':')
+	if i < 0 {
+		return 0
+	}
+	port, _ := strconv.Atoi(addr[i+1:])
+	return port
+}
+
+// parseKeyValues parses "Key: value unit" lines, as in /proc/meminfo and
+// vm_stat, into the first number of each value.
+func parseKeyValues(data []byte) map[string]uint64 {
+	values := make(map[string]uint64)
+	for line := range strings.SplitSeq(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		if n, err := strconv.ParseUint(strings.TrimSuffix(fields[0], "."), 10, 64); err == nil {
+			values[strings.TrimSpace(key)] = n
+		}
+	}
+	return values
+}
+
+// parseLoad parses a load average such as "0.52 0.58 0.59 1/467 12345" or
+// sysctl's "{ 1.23 1.45 1.67 }".
+func parseLoad(s string) []float64 {
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(s), "{}"))
+	if len(fields) < 3 {
+		return nil
+	}
+	load := make([]float64, 3)
+	for i := range load {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil
+		}
+		load[i] = v
+	}
+	return load
+}
+
+// winProcess is a process as listed by the PowerShell script of
+// listProcesses on Windows.
+type winProcess struct {
+	PID     int    `json:"pid"`
+	PPID    int    `json:"ppid"`
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	RSS     uint64 `json:"rss"`
+	// CPUTime is the kernel and user time in 100ns units, Started the
+	// Unix time the process started at.
+	CPUTime uint64 `json:"cpu"`
+	Started int64  `json:"started"`
+}
+
+// parseWinProcesses parses the JSON array of winProcess printed on Windows,
+// with CPU usage averaged up to now.
+func parseWinProcesses(out []byte, now time.Time) ([]Process, error) {
+	var raw []winProcess
+	if err := json.Unmarshal(bytes.TrimSpace(out), &raw); err != nil {
+		return nil, fmt.Errorf("parse process list: %w", err)
+	}
+	procs := make([]Process, 0, len(raw))
+	for _, w := range raw {
+		p := Process{PID: w.PID, PPID: w.PPID, Name: w.Name, Command: w.Command, RSS: w.RSS}
+		if elapsed := now.Sub(time.Unix(w.Started, 0)).Seconds(); w.Started > 0 && elapsed > 0 {
+			p.CPU = float64(w.CPUTime) / 1e7 / elapsed * 100
+		}
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
@@ -0,0 +1,74 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package sysinspect
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func listProcesses(ctx context.Context) ([]Process, error) {
+	out, err := run(ctx, "ps", "-axww", "-o", "pid=,ppid=,user=,%cpu=,rss=,args=")
+	if err != nil {
+		return nil, err
+	}
+	return parsePS(out), nil
+}
+
+func listPorts(ctx context.Context) ([]Port, error) {
+	out, err := run(ctx, "lsof", "-nP", "-i", "-F", "pcfPnT")
+	if err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case errors.Is(err, exec.ErrNotFound):
+			return nil, ErrUnsupported
+		case errors.As(err, &exitErr):
+			// lsof exits 1 when some sockets could not be read, and when
+			// there are none at all.
+		default:
+			return nil, err
+		}
+	}
+	return parseLsof(out), nil
+}
+
+// bootTimePattern finds the seconds of sysctl kern.boottime, printed as
+// "{ sec = 1700000000, usec = 0 } Tue Nov 14 22:13:20 2023".
+var bootTimePattern = regexp.MustCompile(`sec = (\d+)`)
+
+// pageSizePattern finds the page size of the vm_stat header.
+var pageSizePattern = regexp.MustCompile(`page size of (\d+) bytes`)
+
+func readSystem(ctx context.Context, s *Snapshot) error {
+	if out, err := run(ctx, "sysctl", "-n", "hw.memsize"); err == nil {
+		s.MemTotal, _ = strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	} else if out, err := run(ctx, "sysctl", "-n", "hw.physmem"); err == nil {
+		s.MemTotal, _ = strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	}
+	// vm_stat is macOS only; available memory is the free, inactive and
+	// speculative pages.
+	if out, err := run(ctx, "vm_stat"); err == nil {
+		pages := parseKeyValues(out)
+		pageSize := uint64(4096)
+		if m := pageSizePattern.FindSubmatch(out); m != nil {
+			pageSize, _ = strconv.ParseUint(string(m[1]), 10, 64)
+		}
+		s.MemAvailable = (pages["Pages free"] + pages["Pages inactive"] + pages["Pages speculative"]) * pageSize
+	}
+	if out, err := run(ctx, "sysctl", "-n", "vm.loadavg"); err == nil {
+		s.Load = parseLoad(string(out))
+	}
+	if out, err := run(ctx, "sysctl", "-n", "kern.boottime"); err == nil {
+		if m := bootTimePattern.FindSubmatch(out); m != nil {
+			if secs, err := strconv.ParseInt(string(m[1]), 10, 64); err == nil {
+				s.Uptime = time.Since(time.Unix(secs, 0)).Truncate(time.Second)
+			}
+		}
+	}
+	return nil
+}
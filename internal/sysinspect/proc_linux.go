@@ -0,0 +1,198 @@
+package sysinspect
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of the CPU times of /proc/<pid>/stat;
+// it is 100 on every architecture Linux runs on.
+const clockTicks = 100
+
+func listProcesses(ctx context.Context) ([]Process, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	uptime := readUptime()
+	users := make(map[string]string)
+	var procs []Process
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		// A process may exit while it is read; it is then left out.
+		if p, ok := readProcess(pid, uptime, users); ok {
+			procs = append(procs, p)
+		}
+	}
+	return procs, nil
+}
+
+// readProcess reads /proc/<pid>. users caches user names by UID.
+func readProcess(pid int, uptime time.Duration, users map[string]string) (Process, bool) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return Process{}, false
+	}
+	// The name is parenthesized and may itself hold spaces and
+	// parentheses: the fields start after the last ')'.
+	open, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return Process{}, false
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return Process{}, false
+	}
+	p := Process{PID: pid, Name: string(stat[open+1 : end])}
+	p.PPID, _ = strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	start, _ := strconv.ParseUint(fields[19], 10, 64)
+	rss, _ := strconv.ParseUint(fields[21], 10, 64)
+	p.RSS = rss * uint64(os.Getpagesize())
+	if elapsed := uptime.Seconds() - float64(start)/clockTicks; elapsed > 0 {
+		p.CPU = float64(utime+stime) / clockTicks / elapsed * 100
+	}
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		p.Command = strings.TrimSpace(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '})))
+	}
+	if status, err := os.ReadFile(filepath.Join(dir, "status")); err == nil {
+		p.User = lookupUser(status, users)
+	}
+	return p, true
+}
+
+// lookupUser returns the name of the real user of a /proc/<pid>/status,
+// or its UID when it has no name.
+func lookupUser(status []byte, users map[string]string) string {
+	for line := range strings.SplitSeq(string(status), "\n") {
+		rest, ok := strings.CutPrefix(line, "Uid:")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return ""
+		}
+		uid := fields[0]
+		name, ok := users[uid]
+		if !ok {
+			name = uid
+			if u, err := user.LookupId(uid); err == nil {
+				name = u.Username
+			}
+			users[uid] = name
+		}
+		return name
+	}
+	return ""
+}
+
+func readUptime() time.Duration {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0
+	}
+	secs, _ := strconv.ParseFloat(fields[0], 64)
+	return time.Duration(secs * float64(time.Second))
+}
+
+func listPorts(ctx context.Context) ([]Port, error) {
+	var socks []procNetSocket
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		data, err := os.ReadFile(filepath.Join("/proc/net", proto))
+		if err != nil {
+			// IPv6 may be disabled.
+			continue
+		}
+		socks = append(socks, parseProcNet(data, proto)...)
+	}
+	if len(socks) == 0 {
+		return nil, nil
+	}
+
+	owners, err := socketOwners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ports := make([]Port, len(socks))
+	for i, s := range socks {
+		ports[i] = s.Port
+		if o, ok := owners[s.inode]; ok {
+			ports[i].PID, ports[i].Process = o.PID, o.Name
+		}
+	}
+	return ports, nil
+}
+
+// socketOwners maps socket inodes to the processes holding them, from the
+// file descriptors of the processes crush may read.
+func socketOwners(ctx context.Context) (map[string]Process, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[string]Process)
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", e.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		var name string
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			inode, ok := strings.CutPrefix(target, "socket:[")
+			if !ok {
+				continue
+			}
+			if name == "" {
+				comm, _ := os.ReadFile(filepath.Join("/proc", e.Name(), "comm"))
+				name = strings.TrimSpace(string(comm))
+			}
+			owners[strings.TrimSuffix(inode, "]")] = Process{PID: pid, Name: name}
+		}
+	}
+	return owners, nil
+}
+
+func readSystem(_ context.Context, s *Snapshot) error {
+	if data, err := os.ReadFile("/proc/meminfo"); err == nil {
+		mem := parseKeyValues(data)
+		s.MemTotal = mem["MemTotal"] * 1024
+		s.MemAvailable = mem["MemAvailable"] * 1024
+	}
+	if data, err := os.ReadFile("/proc/loadavg"); err == nil {
+		s.Load = parseLoad(string(data))
+	}
+	s.Uptime = readUptime().Truncate(time.Second)
+	return nil
+}
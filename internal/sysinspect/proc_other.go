@@ -0,0 +1,17 @@
+//go:build !linux && !windows && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package sysinspect
+
+import "context"
+
+func listProcesses(context.Context) ([]Process, error) {
+	return nil, ErrUnsupported
+}
+
+func listPorts(context.Context) ([]Port, error) {
+	return nil, ErrUnsupported
+}
+
+func readSystem(context.Context, *Snapshot) error {
+	return ErrUnsupported
+}
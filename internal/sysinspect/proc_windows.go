@@ -0,0 +1,78 @@
+package sysinspect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// processScript lists the processes as a JSON array of winProcess.
+const processScript = `ConvertTo-Json -Compress -InputObject @(Get-CimInstance Win32_Process | ForEach-Object {
+	[pscustomobject]@{
+		pid = $_.ProcessId; ppid = $_.ParentProcessId; name = $_.Name; command = $_.CommandLine
+		rss = $_.WorkingSetSize; cpu = $_.KernelModeTime + $_.UserModeTime
+		started = if ($_.CreationDate) { ([DateTimeOffset]$_.CreationDate).ToUnixTimeSeconds() } else { 0 }
+	}
+})`
+
+// systemScript reports memory in KiB and the boot time.
+const systemScript = `Get-CimInstance Win32_OperatingSystem | ForEach-Object {
+	[pscustomobject]@{
+		total = $_.TotalVisibleMemorySize; free = $_.FreePhysicalMemory
+		boot = ([DateTimeOffset]$_.LastBootUpTime).ToUnixTimeSeconds()
+	}
+} | ConvertTo-Json -Compress`
+
+func listProcesses(ctx context.Context) ([]Process, error) {
+	out, err := powershell(ctx, processScript)
+	if err != nil {
+		return nil, err
+	}
+	return parseWinProcesses(out, time.Now())
+}
+
+func listPorts(ctx context.Context) ([]Port, error) {
+	out, err := run(ctx, "netstat", "-ano")
+	if err != nil {
+		return nil, err
+	}
+	ports := parseNetstat(out)
+	// netstat names no processes; a failed listing only leaves them out.
+	if procs, err := listProcesses(ctx); err == nil {
+		names := make(map[int]string, len(procs))
+		for _, p := range procs {
+			names[p.PID] = p.Name
+		}
+		for i := range ports {
+			ports[i].Process = names[ports[i].PID]
+		}
+	}
+	return ports, nil
+}
+
+func readSystem(ctx context.Context, s *Snapshot) error {
+	out, err := powershell(ctx, systemScript)
+	if err != nil {
+		return err
+	}
+	var sys struct {
+		Total uint64 `json:"total"`
+		Free  uint64 `json:"free"`
+		Boot  int64  `json:"boot"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &sys); err != nil {
+		return fmt.Errorf("parse system info: %w", err)
+	}
+	s.MemTotal = sys.Total * 1024
+	s.MemAvailable = sys.Free * 1024
+	if sys.Boot > 0 {
+		s.Uptime = time.Since(time.Unix(sys.Boot, 0)).Truncate(time.Second)
+	}
+	return nil
+}
+
+func powershell(ctx context.Context, script string) ([]byte, error) {
+	return run(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+}
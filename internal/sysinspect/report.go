@@ -0,0 +1,96 @@
+package sysinspect
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// maxCommandWidth truncates command lines in process tables.
+const maxCommandWidth = 160
+
+// FormatProcesses renders processes as a table.
+func FormatProcesses(procs []Process) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PID\tPPID\tUSER\t%CPU\tRSS\tCOMMAND")
+	for _, p := range procs {
+		command := p.Command
+		if command == "" {
+			command = "[" + p.Name + "]"
+		}
+		if len(command) > maxCommandWidth {
+			command = command[:maxCommandWidth] + "..."
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%.1f\t%s\t%s\n", p.PID, p.PPID, p.User, p.CPU, humanize.IBytes(p.RSS), command)
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+// FormatPorts renders sockets as a table.
+func FormatPorts(ports []Port) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROTO\tLOCAL\tREMOTE\tSTATE\tPID\tPROCESS")
+	for _, p := range ports {
+		pid := "-"
+		if p.PID > 0 {
+			pid = fmt.Sprint(p.PID)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Protocol, p.Local, dash(p.Remote), dash(p.State), pid, dash(p.Process))
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+// Format renders the snapshot as text.
+func (s Snapshot) Format() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Host: %s (%s/%s), %d CPUs\n", dash(s.Hostname), s.OS, s.Arch, s.CPUs)
+	if s.Uptime > 0 {
+		fmt.Fprintf(&sb, "Uptime: %s\n", s.Uptime.Round(time.Minute))
+	}
+	if len(s.Load) == 3 {
+		fmt.Fprintf(&sb, "Load average: %.2f %.2f %.2f\n", s.Load[0], s.Load[1], s.Load[2])
+	}
+	if s.MemTotal > 0 {
+		fmt.Fprintf(&sb, "Memory: %s total", humanize.IBytes(s.MemTotal))
+		if s.MemAvailable > 0 {
+			fmt.Fprintf(&sb, ", %s available (%.0f%% used)", humanize.IBytes(s.MemAvailable), usedPercent(s.MemTotal, s.MemAvailable))
+		}
+		sb.WriteString("\n")
+	}
+	if d := s.Disk; d != nil && d.Total > 0 {
+		fmt.Fprintf(&sb, "Disk (%s): %s total, %s free (%.0f%% used)\n", d.Path, humanize.IBytes(d.Total), humanize.IBytes(d.Free), usedPercent(d.Total, d.Free))
+	}
+	if s.Processes > 0 {
+		fmt.Fprintf(&sb, "Processes: %d\n", s.Processes)
+	}
+	if len(s.TopCPU) > 0 {
+		sb.WriteString("\nTop CPU:\n")
+		sb.WriteString(FormatProcesses(s.TopCPU))
+	}
+	if len(s.TopMemory) > 0 {
+		sb.WriteString("\nTop memory:\n")
+		sb.WriteString(FormatProcesses(s.TopMemory))
+	}
+	return sb.String()
+}
+
+func usedPercent(total, free uint64) float64 {
+	if total == 0 || free > total {
+		return 0
+	}
+	return float64(total-free) / float64(total) * 100
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package sysinspect
+
+import (
+	"context"
+	"os/exec"
+)
+
+// run runs a command for its standard output.
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
@@ -0,0 +1,229 @@
+// Package sysinspect inspects the machine crush runs on: its processes,
+// the ports they use and a snapshot of its resources. Linux reads /proc;
+// macOS and the BSDs run ps, lsof and sysctl; Windows runs PowerShell and
+// netstat. Command lines are redacted of secrets before they are returned.
+package sysinspect
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned on platforms an inspection is not implemented
+// for.
+var ErrUnsupported = errors.New("sysinspect: not supported on " + runtime.GOOS)
+
+// commandTimeout bounds the external commands an inspection runs.
+const commandTimeout = 10 * time.Second
+
+// Process is a running process.
+type Process struct {
+	PID  int    `json:"pid"`
+	PPID int    `json:"ppid"`
+	User string `json:"user,omitempty"`
+	Name string `json:"name"`
+	// Command is the redacted command line, empty when it is not readable,
+	// such as for kernel threads.
+	Command string `json:"command,omitempty"`
+	// CPU is the percentage of a core used over the process lifetime, as
+	// ps reports it.
+	CPU float64 `json:"cpu_percent"`
+	// RSS is the resident memory in bytes.
+	RSS uint64 `json:"rss_bytes"`
+}
+
+// Port is a socket bound to a local port.
+type Port struct {
+	// Protocol is tcp, tcp6, udp or udp6.
+	Protocol string `json:"protocol"`
+	Local    string `json:"local"`
+	Port     int    `json:"port"`
+	Remote   string `json:"remote,omitempty"`
+	// State is the TCP state, such as LISTEN or ESTABLISHED; empty for
+	// UDP sockets.
+	State string `json:"state,omitempty"`
+	// PID and Process are the owner, when it is visible to crush.
+	PID     int    `json:"pid,omitempty"`
+	Process string `json:"process,omitempty"`
+}
+
+// Listening reports whether the socket accepts connections or datagrams,
+// rather than being one end of a connection.
+func (p Port) Listening() bool {
+	if strings.HasPrefix(p.Protocol, "udp") {
+		return p.Remote == ""
+	}
+	return p.State == "LISTEN"
+}
+
+// DiskUsage is the space of the filesystem holding Path.
+type DiskUsage struct {
+	Path  string `json:"path"`
+	Total uint64 `json:"total_bytes"`
+	Free  uint64 `json:"free_bytes"`
+}
+
+// Snapshot is the state of the machine's resources at one moment. Fields
+// a platform does not report are zero.
+type Snapshot struct {
+	Time     time.Time `json:"time"`
+	OS       string    `json:"os"`
+	Arch     string    `json:"arch"`
+	Hostname string    `json:"hostname,omitempty"`
+	CPUs     int       `json:"cpus"`
+	// Load is the 1, 5 and 15 minute load average; nil on Windows.
+	Load         []float64     `json:"load,omitempty"`
+	MemTotal     uint64        `json:"mem_total_bytes,omitempty"`
+	MemAvailable uint64        `json:"mem_available_bytes,omitempty"`
+	Uptime       time.Duration `json:"uptime,omitempty"`
+	Processes    int           `json:"processes"`
+	Disk         *DiskUsage    `json:"disk,omitempty"`
+	// TopCPU and TopMemory are the processes using the most CPU and
+	// resident memory.
+	TopCPU    []Process `json:"top_cpu,omitempty"`
+	TopMemory []Process `json:"top_memory,omitempty"`
+}
+
+// topProcesses is how many processes a snapshot lists per resource.
+const topProcesses = 5
+
+// Processes lists the running processes, by PID.
+func Processes(ctx context.Context) ([]Process, error) {
+	procs, err := listProcesses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	redactor := commandRedactor()
+	for i := range procs {
+		procs[i].Command = redactor.redact(procs[i].Command)
+	}
+	slices.SortFunc(procs, func(a, b Process) int { return cmp.Compare(a.PID, b.PID) })
+	return procs, nil
+}
+
+// Ports lists the sockets bound to local ports, by port. Owners are only
+// known for the processes crush may inspect, usually those of its user.
+func Ports(ctx context.Context) ([]Port, error) {
+	ports, err := listPorts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(ports, func(a, b Port) int {
+		return cmp.Or(
+			cmp.Compare(a.Port, b.Port),
+			cmp.Compare(a.Protocol, b.Protocol),
+			cmp.Compare(a.Local, b.Local),
+			cmp.Compare(a.Remote, b.Remote),
+		)
+	})
+	return ports, nil
+}
+
+// TakeSnapshot reports the machine's resources, with the usage of the
+// filesystem holding dir. Parts a platform cannot report are left out
+// rather than failing the snapshot.
+func TakeSnapshot(ctx context.Context, dir string) (Snapshot, error) {
+	s := Snapshot{
+		Time: time.Now(),
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+		CPUs: runtime.NumCPU(),
+	}
+	s.Hostname, _ = os.Hostname()
+	if err := readSystem(ctx, &s); err != nil && !errors.Is(err, ErrUnsupported) {
+		return Snapshot{}, err
+	}
+	if dir != "" {
+		if disk, err := diskUsage(dir); err == nil {
+			s.Disk = &disk
+		}
+	}
+
+	procs, err := Processes(ctx)
+	if err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			return s, nil
+		}
+		return Snapshot{}, err
+	}
+	s.Processes = len(procs)
+	s.TopCPU = TopProcesses(procs, SortByCPU, topProcesses)
+	s.TopMemory = TopProcesses(procs, SortByMemory, topProcesses)
+	return s, nil
+}
+
+// SortKey orders a process list.
+type SortKey string
+
+const (
+	SortByCPU    SortKey = "cpu"
+	SortByMemory SortKey = "memory"
+	SortByPID    SortKey = "pid"
+)
+
+// TopProcesses returns the first n processes of procs ordered by key,
+// highest usage first, or all of them when n <= 0. procs is not modified.
+func TopProcesses(procs []Process, key SortKey, n int) []Process {
+	sorted := slices.Clone(procs)
+	slices.SortStableFunc(sorted, func(a, b Process) int {
+		switch key {
+		case SortByCPU:
+			return cmp.Or(cmp.Compare(b.CPU, a.CPU), cmp.Compare(b.RSS, a.RSS))
+		case SortByMemory:
+			return cmp.Or(cmp.Compare(b.RSS, a.RSS), cmp.Compare(b.CPU, a.CPU))
+		}
+		return cmp.Compare(a.PID, b.PID)
+	})
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// FilterProcesses returns the processes whose name or command line
+// contains query, case-insensitively.
+func FilterProcesses(procs []Process, query string) []Process {
+	query = strings.ToLower(query)
+	var out []Process
+	for _, p := range procs {
+		if strings.Contains(strings.ToLower(p.Name), query) || strings.Contains(strings.ToLower(p.Command), query) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ProcessTree returns the process pid and its descendants, or nothing
+// when pid is not running.
+func ProcessTree(procs []Process, pid int) []Process {
+	in := map[int]bool{pid: true}
+	// A child may be listed before its parent, so repeat until no process
+	// is added.
+	for changed := true; changed; {
+		changed = false
+		for _, p := range procs {
+			if !in[p.PID] && in[p.PPID] {
+				in[p.PID] = true
+				changed = true
+			}
+		}
+	}
+	var out []Process
+	found := false
+	for _, p := range procs {
+		if in[p.PID] {
+			out = append(out, p)
+			found = found || p.PID == pid
+		}
+	}
+	if !found {
+		return nil
+	}
+	return out
+}
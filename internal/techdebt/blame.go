@@ -0,0 +1,113 @@
+package techdebt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// blame is the commit a line was last changed in.
+type blame struct {
+	author string
+	date   time.Time
+}
+
+// blameItems fills in the author and date of the items of the changed
+// files from git blame. Outside a git repository, and for files or lines
+// git does not know, they are left empty.
+func blameItems(ctx context.Context, root string, changed []string, entries map[string]*fileEntry) {
+	if len(changed) == 0 {
+		return
+	}
+	if err := exec.CommandContext(ctx, "git", "-C", root, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return
+	}
+
+	var mu sync.Mutex
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(8)
+	for _, rel := range changed {
+		items := entries[rel].Items
+		g.Go(func() error {
+			lines := make([]int, len(items))
+			for i, it := range items {
+				lines[i] = it.Line
+			}
+			blamed, err := blameLines(gCtx, root, rel, lines)
+			if err != nil {
+				slog.Debug("Tech-debt blame skipped", "path", rel, "error", err)
+				return nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for i := range items {
+				if b, ok := blamed[items[i].Line]; ok {
+					items[i].Author, items[i].Date = b.author, b.date
+				}
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// blameLines runs git blame on the given lines of the file at rel.
+func blameLines(ctx context.Context, root, rel string, lines []int) (map[int]blame, error) {
+	args := []string{"-C", root, "blame", "--line-porcelain"}
+	for _, l := range lines {
+		args = append(args, "-L", fmt.Sprintf("%d,%d", l, l))
+	}
+	args = append(args, "--", rel)
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git blame: %w", err)
+	}
+	return parseBlame(out), nil
+}
+
+// parseBlame parses `git blame --line-porcelain` output into the blame
+// of each final line. Lines that are not committed yet are left out.
+func parseBlame(out []byte) map[int]blame {
+	result := make(map[int]blame)
+	var (
+		line        int
+		uncommitted bool
+		cur         blame
+	)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 64*1024), maxSourceSize)
+	for sc.Scan() {
+		text := sc.Text()
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			// The line's content ends its entry.
+			if line > 0 && !uncommitted {
+				result[line] = cur
+			}
+			line, cur = 0, blame{}
+		case strings.HasPrefix(text, "author "):
+			cur.author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64); err == nil {
+				cur.date = time.Unix(sec, 0).UTC()
+			}
+		case line == 0:
+			// The header: <sha> <original line> <final line> [<count>].
+			fields := strings.Fields(text)
+			if len(fields) >= 3 {
+				line, _ = strconv.Atoi(fields[2])
+				uncommitted = strings.Trim(fields[0], "0") == ""
+			}
+		}
+	}
+	return result
+}
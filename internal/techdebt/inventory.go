@@ -0,0 +1,284 @@
+// Package techdebt keeps an inventory of the TODO, FIXME, HACK and XXX
+// comments of a repository, with the author and date of each from git
+// blame. The inventory is cached in the data directory and refreshed
+// incrementally: only files whose size or modification time changed are
+// re-read and re-blamed.
+package techdebt
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+)
+
+// cacheFile is the name of the inventory cache in the data directory.
+const cacheFile = "techdebt.json"
+
+// cacheVersion is bumped when the cached entries would be read or
+// recognized differently.
+const cacheVersion = 1
+
+// maxSourceSize bounds the size of a scanned file; larger files are
+// skipped.
+const maxSourceSize = 1 << 20
+
+// maxItemText bounds the comment text kept for an item.
+const maxItemText = 200
+
+// Tags are the markers recognized, in the order they are reported.
+var Tags = []string{"FIXME", "HACK", "XXX", "TODO"}
+
+// markerRe matches a marker opening a comment, with an optional owner in
+// parentheses, as in "// TODO(alice): text" or "# FIXME text".
+var markerRe = regexp.MustCompile(`(?:^|\s|[^\w:/])(?://+|#+|/\*+|<!--|--|;+|\*)\s*@?(TODO|FIXME|HACK|XXX)\b(?:\(([^)]*)\))?[:\s-]*(.*)`)
+
+// sourceExtensions are the extensions of the files scanned. Files named
+// in sourceNames are scanned as well.
+var sourceExtensions = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".ts": true, ".tsx": true, ".vue": true, ".svelte": true,
+	".py": true, ".rb": true, ".php": true, ".pl": true, ".lua": true,
+	".java": true, ".kt": true, ".kts": true, ".scala": true, ".groovy": true,
+	".cs": true, ".fs": true, ".swift": true, ".rs": true, ".dart": true,
+	".ex": true, ".exs": true, ".erl": true, ".hs": true, ".ml": true, ".clj": true,
+	".c": true, ".cc": true, ".cpp": true, ".h": true, ".hpp": true, ".m": true, ".mm": true,
+	".sh": true, ".bash": true, ".zsh": true, ".fish": true, ".ps1": true,
+	".sql": true, ".proto": true, ".graphql": true, ".tf": true, ".nix": true,
+	".yaml": true, ".yml": true, ".toml": true, ".ini": true,
+	".css": true, ".scss": true, ".less": true, ".html": true,
+}
+
+var sourceNames = map[string]bool{
+	"Makefile": true, "Dockerfile": true, "Justfile": true, "Rakefile": true,
+}
+
+// Item is a tech-debt comment.
+type Item struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Tag  string `json:"tag"`
+	// Owner is the name in parentheses after the tag, if any.
+	Owner string `json:"owner,omitempty"`
+	Text  string `json:"text,omitempty"`
+	// Author and Date come from git blame; they are empty for lines that
+	// are not committed or outside a git repository.
+	Author string    `json:"author,omitempty"`
+	Date   time.Time `json:"date,omitzero"`
+}
+
+// fileEntry is the cached scan of one file.
+type fileEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	Items   []Item `json:"items,omitempty"`
+}
+
+type cache struct {
+	Version int                   `json:"version"`
+	Files   map[string]*fileEntry `json:"files"`
+}
+
+// Inventory is the tech-debt inventory of a repository. It is safe for
+// concurrent use.
+type Inventory struct {
+	mu     sync.Mutex
+	root   string
+	path   string
+	loaded bool
+	files  map[string]*fileEntry
+}
+
+// New returns the inventory of the repository at root, cached in dataDir.
+// An empty dataDir keeps it in memory only.
+func New(root, dataDir string) *Inventory {
+	inv := &Inventory{root: root, files: map[string]*fileEntry{}}
+	if dataDir != "" {
+		inv.path = filepath.Join(dataDir, cacheFile)
+	}
+	return inv
+}
+
+// Load returns the inventory of the repository at root, cached in
+// dataDir, brought up to date.
+func Load(ctx context.Context, root, dataDir string) (*Inventory, error) {
+	inv := New(root, dataDir)
+	if err := inv.Update(ctx); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// Update refreshes the inventory with the files below the root, skipping
+// the paths the repository ignores.
+func (inv *Inventory) Update(ctx context.Context) error {
+	files, err := walk(ctx, inv.root)
+	if err != nil {
+		return err
+	}
+	return inv.Refresh(ctx, files)
+}
+
+// Refresh brings the inventory up to date with files, the slash-separated,
+// repository relative paths of the repository. Files that are no longer
+// listed are dropped; unchanged files are not re-read.
+func (inv *Inventory) Refresh(ctx context.Context, files []string) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.load()
+
+	next := make(map[string]*fileEntry, len(inv.files))
+	var changed []string
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !isSource(rel) {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(inv.root, filepath.FromSlash(rel)))
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxSourceSize {
+			continue
+		}
+		if e := inv.files[rel]; e != nil && e.Size == info.Size() && e.ModTime == info.ModTime().UnixNano() {
+			next[rel] = e
+			continue
+		}
+		items, err := scanFile(inv.root, rel)
+		if err != nil {
+			continue
+		}
+		next[rel] = &fileEntry{Size: info.Size(), ModTime: info.ModTime().UnixNano(), Items: items}
+		if len(items) > 0 {
+			changed = append(changed, rel)
+		}
+	}
+
+	dirty := len(next) != len(inv.files)
+	for rel, e := range next {
+		if inv.files[rel] != e {
+			dirty = true
+			break
+		}
+	}
+	blameItems(ctx, inv.root, changed, next)
+	inv.files = next
+	if dirty {
+		inv.save()
+	}
+	return ctx.Err()
+}
+
+// isSource reports whether the file at rel is scanned.
+func isSource(rel string) bool {
+	return sourceExtensions[strings.ToLower(path.Ext(rel))] || sourceNames[path.Base(rel)]
+}
+
+// scanFile returns the tech-debt comments of the file at rel.
+func scanFile(root, rel string) ([]Item, error) {
+	f, err := os.Open(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var items []Item
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), maxSourceSize)
+	for line := 1; sc.Scan(); line++ {
+		m := markerRe.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue
+		}
+		items = append(items, Item{
+			Path:  rel,
+			Line:  line,
+			Tag:   m[1],
+			Owner: strings.TrimSpace(m[2]),
+			Text:  truncate(commentText(m[3]), maxItemText),
+		})
+	}
+	return items, sc.Err()
+}
+
+// commentText strips the closing delimiter of a block comment.
+func commentText(s string) string {
+	s = strings.TrimSpace(s)
+	for _, closer := range []string{"*/", "-->", "-}"} {
+		s = strings.TrimSpace(strings.TrimSuffix(s, closer))
+	}
+	return s
+}
+
+func (inv *Inventory) load() {
+	if inv.loaded || inv.path == "" {
+		return
+	}
+	inv.loaded = true
+	data, err := os.ReadFile(inv.path)
+	if err != nil {
+		return
+	}
+	var c cache
+	if json.Unmarshal(data, &c) != nil || c.Version != cacheVersion || c.Files == nil {
+		return
+	}
+	inv.files = c.Files
+}
+
+func (inv *Inventory) save() {
+	if inv.path == "" {
+		return
+	}
+	data, err := json.Marshal(cache{Version: cacheVersion, Files: inv.files})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(inv.path), 0o700); err != nil {
+		return
+	}
+	_ = fsext.WriteFileAtomic(inv.path, data, 0o600)
+}
+
+// walk returns the files below root the repository does not ignore.
+func walk(ctx context.Context, root string) ([]string, error) {
+	walker := fsext.NewFastGlobWalker(root)
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if p != root && walker.ShouldSkipDir(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if walker.ShouldSkip(p) {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, p); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files, err
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "") + "..."
+}
@@ -0,0 +1,154 @@
+package techdebt
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_DATE=2024-01-02T00:00:00Z", "GIT_COMMITTER_DATE=2024-01-02T00:00:00Z")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestScanMarkers(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, root, "main.go", `package main
+
+// TODO(alice): drop the retry once the API is fixed
+func main() {
+	x := "TODO not a comment"
+	i-- // FIXME off by one
+	/* HACK: bypass the cache */
+	// see http://example.com/TODO
+	// This is not a TODO either.
+}
+`)
+	writeFile(t, root, "deploy.sh", "#!/bin/sh\n# XXX hardcoded region\n")
+	writeFile(t, root, "notes.txt", "TODO: ignored, not source\n")
+
+	items, err := scanFile(root, "main.go")
+	require.NoError(t, err)
+	require.Equal(t, []Item{
+		{Path: "main.go", Line: 3, Tag: "TODO", Owner: "alice", Text: "drop the retry once the API is fixed"},
+		{Path: "main.go", Line: 6, Tag: "FIXME", Text: "off by one"},
+		{Path: "main.go", Line: 7, Tag: "HACK", Text: "bypass the cache"},
+	}, items)
+
+	inv, err := Load(t.Context(), root, "")
+	require.NoError(t, err)
+	require.Equal(t, 4, inv.Total())
+	require.Equal(t, "XXX", inv.Items(Filter{Path: "deploy.sh"}, time.Now())[0].Tag)
+}
+
+func TestRefreshIsIncremental(t *testing.T) {
+	t.Parallel()
+
+	root, dataDir := t.TempDir(), t.TempDir()
+	writeFile(t, root, "a.go", "// TODO one\n")
+	writeFile(t, root, "b.go", "// TODO two\n")
+
+	inv, err := Load(t.Context(), root, dataDir)
+	require.NoError(t, err)
+	require.Equal(t, 2, inv.Total())
+	require.FileExists(t, filepath.Join(dataDir, cacheFile))
+
+	// Files whose size and modification time are unchanged are not
+	// re-read.
+	a := filepath.Join(root, "a.go")
+	info, err := os.Stat(a)
+	require.NoError(t, err)
+	writeFile(t, root, "a.go", "// TODO uno\n")
+	require.NoError(t, os.Chtimes(a, info.ModTime(), info.ModTime()))
+	inv = New(root, dataDir)
+	require.NoError(t, inv.Update(t.Context()))
+	require.Len(t, inv.Items(Filter{Text: "one"}, time.Now()), 1)
+
+	// A fresh inventory starts from the cache, rescans the changed file
+	// and drops the removed one.
+	writeFile(t, root, "b.go", "// FIXME two\n// TODO three\n")
+	require.NoError(t, os.Chtimes(filepath.Join(root, "b.go"), time.Now().Add(time.Hour), time.Now().Add(time.Hour)))
+	require.NoError(t, os.Remove(filepath.Join(root, "a.go")))
+	inv = New(root, dataDir)
+	require.NoError(t, inv.Refresh(t.Context(), []string{"b.go"}))
+	items := inv.Items(Filter{}, time.Now())
+	require.Len(t, items, 2)
+	require.Equal(t, "FIXME", items[0].Tag)
+	require.Equal(t, "three", items[1].Text)
+}
+
+func TestBlameAndFilters(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git(t, root, "init", "-q")
+	git(t, root, "config", "user.email", "alice@example.com")
+	git(t, root, "config", "user.name", "Alice")
+	writeFile(t, root, "internal/old.go", "package internal\n\n// FIXME legacy parser\n")
+	git(t, root, "add", ".")
+	git(t, root, "commit", "-q", "-m", "initial")
+	writeFile(t, root, "internal/old.go", "package internal\n\n// FIXME legacy parser\n// TODO(bob): new work\n")
+
+	inv, err := Load(t.Context(), root, "")
+	require.NoError(t, err)
+	now := time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	items := inv.Items(Filter{}, now)
+	require.Len(t, items, 2)
+	require.Equal(t, "Alice", items[0].Author)
+	require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), items[0].Date)
+	require.Empty(t, items[1].Author, "uncommitted lines have no blame")
+
+	require.Len(t, inv.Items(Filter{OlderThan: 365 * 24 * time.Hour}, now), 1)
+	require.Len(t, inv.Items(Filter{Tags: []string{"todo"}}, now), 1)
+	require.Len(t, inv.Items(Filter{Author: "bob"}, now), 1)
+	require.Len(t, inv.Items(Filter{Path: "internal"}, now), 2)
+	require.Empty(t, inv.Items(Filter{Text: "lexer"}, now))
+
+	require.Equal(t, `2 of 2 tech-debt comments (FIXME 1, TODO 1):
+  internal/old.go:3 FIXME [Alice, 18 months]: legacy parser
+  internal/old.go:4 TODO(bob): new work
+`, Report(items, inv.Total(), 0, now))
+	require.Contains(t, Report(items, inv.Total(), 1, now), "  ... and 1 more; narrow with filters\n")
+}
+
+func TestParseBlameSkipsUncommitted(t *testing.T) {
+	t.Parallel()
+
+	out := []byte(`0123456789abcdef0123456789abcdef01234567 1 3 1
+author Alice
+author-mail <alice@example.com>
+author-time 1704153600
+filename a.go
+	// FIXME legacy parser
+0000000000000000000000000000000000000000 4 4 1
+author Not Committed Yet
+author-time 1751414400
+filename a.go
+	// TODO new work
+`)
+	require.Equal(t, map[int]blame{
+		3: {author: "Alice", date: time.Unix(1704153600, 0).UTC()},
+	}, parseBlame(out))
+}
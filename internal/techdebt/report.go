@@ -0,0 +1,179 @@
+package techdebt
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sort orders of [Filter].
+const (
+	SortPath = "path"
+	SortAge  = "age"
+)
+
+// Filter selects inventory items. Zero fields select everything.
+type Filter struct {
+	// Tags keeps the items with one of the tags, case-insensitively.
+	Tags []string
+	// Path keeps the items of files under a directory or matching a
+	// substring of their path.
+	Path string
+	// Author keeps the items whose blamed author or owner contains it,
+	// case-insensitively.
+	Author string
+	// Text keeps the items whose comment contains it, case-insensitively.
+	Text string
+	// OlderThan keeps the items last changed longer ago than it. Items
+	// without a blame date are left out.
+	OlderThan time.Duration
+	// Sort orders the items by path and line (the default) or oldest
+	// first.
+	Sort string
+}
+
+// Items returns the items matching f as of now.
+func (inv *Inventory) Items(f Filter, now time.Time) []Item {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	var items []Item
+	for _, e := range inv.files {
+		for _, it := range e.Items {
+			if f.matches(it, now) {
+				items = append(items, it)
+			}
+		}
+	}
+	slices.SortFunc(items, func(a, b Item) int {
+		if f.Sort == SortAge {
+			if c := compareDates(a.Date, b.Date); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(strings.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line))
+	})
+	return items
+}
+
+// Total returns the number of items in the inventory.
+func (inv *Inventory) Total() int {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	n := 0
+	for _, e := range inv.files {
+		n += len(e.Items)
+	}
+	return n
+}
+
+func (f Filter) matches(it Item, now time.Time) bool {
+	if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, func(t string) bool { return strings.EqualFold(t, it.Tag) }) {
+		return false
+	}
+	if f.Path != "" {
+		dir := strings.TrimSuffix(f.Path, "/") + "/"
+		if !strings.HasPrefix(it.Path, dir) && !strings.Contains(it.Path, f.Path) {
+			return false
+		}
+	}
+	if f.Author != "" && !containsFold(it.Author, f.Author) && !containsFold(it.Owner, f.Author) {
+		return false
+	}
+	if f.Text != "" && !containsFold(it.Text, f.Text) {
+		return false
+	}
+	if f.OlderThan > 0 && (it.Date.IsZero() || now.Sub(it.Date) < f.OlderThan) {
+		return false
+	}
+	return true
+}
+
+// compareDates orders older dates first and unknown dates last.
+func compareDates(a, b time.Time) int {
+	switch {
+	case a.Equal(b):
+		return 0
+	case a.IsZero():
+		return 1
+	case b.IsZero():
+		return -1
+	}
+	return a.Compare(b)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// Report renders items, at most limit of them when limit > 0, out of the
+// total in the inventory, as of now.
+func Report(items []Item, total, limit int, now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d of %d tech-debt comments", len(items), total)
+	if counts := tagCounts(items); counts != "" {
+		fmt.Fprintf(&sb, " (%s)", counts)
+	}
+	sb.WriteString(":\n")
+	shown := items
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+	for _, it := range shown {
+		tag := it.Tag
+		if it.Owner != "" {
+			tag += "(" + it.Owner + ")"
+		}
+		fmt.Fprintf(&sb, "  %s:%d %s", it.Path, it.Line, tag)
+		if it.Author != "" {
+			fmt.Fprintf(&sb, " [%s, %s]", it.Author, FormatAge(now.Sub(it.Date)))
+		}
+		if it.Text != "" {
+			sb.WriteString(": " + it.Text)
+		}
+		sb.WriteByte('\n')
+	}
+	if more := len(items) - len(shown); more > 0 {
+		fmt.Fprintf(&sb, "  ... and %d more; narrow with filters\n", more)
+	}
+	return sb.String()
+}
+
+// tagCounts returns the number of items per tag, as "FIXME 2, TODO 5".
+func tagCounts(items []Item) string {
+	counts := make(map[string]int)
+	for _, it := range items {
+		counts[it.Tag]++
+	}
+	var parts []string
+	for _, tag := range Tags {
+		if n := counts[tag]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", tag, n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatAge renders an age in the largest whole unit: days, months or
+// years.
+func FormatAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 60:
+		return plural(days, "day")
+	case days < 730:
+		return plural(days/30, "month")
+	}
+	return plural(days/365, "year")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
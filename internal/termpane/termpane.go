@@ -0,0 +1,145 @@
+// Package termpane opens content in a split of the surrounding terminal
+// multiplexer (tmux or iTerm2) so verbose tool output, diffs and expanded
+// context can be read next to the chat instead of inline.
+package termpane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Multiplexer identifies the terminal that hosts the pane.
+type Multiplexer string
+
+const (
+	MultiplexerNone   Multiplexer = ""
+	MultiplexerTmux   Multiplexer = "tmux"
+	MultiplexerITerm2 Multiplexer = "iterm2"
+)
+
+// DefaultPager is used to display pane content when none is configured.
+const DefaultPager = "less -R"
+
+// ErrUnsupported is returned when no supported multiplexer is available.
+var ErrUnsupported = errors.New("no supported terminal multiplexer detected (tmux or iTerm2)")
+
+// Environ looks up environment variables.
+type Environ interface {
+	LookupEnv(key string) (string, bool)
+}
+
+// Detect returns the multiplexer hosting the current terminal. tmux wins
+// over iTerm2 because a tmux session inside iTerm2 should split the tmux
+// window rather than the iTerm2 tab.
+func Detect(env Environ) Multiplexer {
+	if v, ok := env.LookupEnv("TMUX"); ok && v != "" {
+		return MultiplexerTmux
+	}
+	if v, _ := env.LookupEnv("TERM_PROGRAM"); v == "iTerm.app" {
+		return MultiplexerITerm2
+	}
+	return MultiplexerNone
+}
+
+// Resolve maps a configured multiplexer name to the one to use. "auto" or
+// an empty name falls back to detection; "disabled" turns panes off.
+func Resolve(name string, env Environ) (Multiplexer, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return Detect(env), nil
+	case "disabled", "off", "none":
+		return MultiplexerNone, nil
+	case string(MultiplexerTmux):
+		return MultiplexerTmux, nil
+	case string(MultiplexerITerm2):
+		return MultiplexerITerm2, nil
+	}
+	return MultiplexerNone, fmt.Errorf("unknown multiplexer %q", name)
+}
+
+// Options controls how a pane is opened.
+type Options struct {
+	// Pager is the command used to display the content, e.g. "less -R".
+	Pager string
+	// Vertical stacks the pane below the current one instead of beside it.
+	Vertical bool
+}
+
+// Open writes content to a temporary file and shows it in a new split of
+// the given multiplexer. The file is removed when the pager exits.
+func Open(ctx context.Context, mux Multiplexer, title, content string, opts Options) error {
+	if mux == MultiplexerNone {
+		return ErrUnsupported
+	}
+
+	f, err := os.CreateTemp("", "crush-pane-*.txt")
+	if err != nil {
+		return fmt.Errorf("creating pane file: %w", err)
+	}
+	if title != "" {
+		content = title + "\n\n" + content
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("writing pane file: %w", err)
+	}
+	f.Close()
+
+	name, args := command(mux, f.Name(), opts)
+	if out, err := exec.CommandContext(ctx, name, args...).CombinedOutput(); err != nil {
+		os.Remove(f.Name())
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("opening %s pane: %w: %s", mux, err, msg)
+		}
+		return fmt.Errorf("opening %s pane: %w", mux, err)
+	}
+	return nil
+}
+
+// command builds the multiplexer invocation that runs the pager on path.
+func command(mux Multiplexer, path string, opts Options) (string, []string) {
+	pager := opts.Pager
+	if pager == "" {
+		pager = DefaultPager
+	}
+	script := fmt.Sprintf("%s %s; rm -f %s", pager, shellQuote(path), shellQuote(path))
+
+	switch mux {
+	case MultiplexerITerm2:
+		split := "vertically"
+		if opts.Vertical {
+			split = "horizontally"
+		}
+		cmd := "/bin/sh -c " + shellQuote(script)
+		return "osascript", []string{
+			"-e", `tell application "iTerm2"`,
+			"-e", "tell current session of current window",
+			"-e", fmt.Sprintf("split %s with default profile command %s", split, appleScriptQuote(cmd)),
+			"-e", "end tell",
+			"-e", "end tell",
+		}
+	default:
+		// tmux's -h splits side by side; -d keeps focus in crush.
+		split := "-h"
+		if opts.Vertical {
+			split = "-v"
+		}
+		return "tmux", []string{"split-window", split, "-d", script}
+	}
+}
+
+// shellQuote quotes s for POSIX sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// appleScriptQuote quotes s as an AppleScript string literal.
+func appleScriptQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
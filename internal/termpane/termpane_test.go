@@ -0,0 +1,59 @@
+package termpane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type env map[string]string
+
+func (e env) LookupEnv(key string) (string, bool) {
+	v, ok := e[key]
+	return v, ok
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, MultiplexerTmux, Detect(env{"TMUX": "/tmp/tmux-1000/default,1,0", "TERM_PROGRAM": "iTerm.app"}))
+	require.Equal(t, MultiplexerITerm2, Detect(env{"TERM_PROGRAM": "iTerm.app"}))
+	require.Equal(t, MultiplexerNone, Detect(env{"TMUX": ""}))
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	mux, err := Resolve("auto", env{"TMUX": "x"})
+	require.NoError(t, err)
+	require.Equal(t, MultiplexerTmux, mux)
+
+	mux, err = Resolve("disabled", env{"TMUX": "x"})
+	require.NoError(t, err)
+	require.Equal(t, MultiplexerNone, mux)
+
+	mux, err = Resolve("iTerm2", env{})
+	require.NoError(t, err)
+	require.Equal(t, MultiplexerITerm2, mux)
+
+	_, err = Resolve("screen", env{})
+	require.Error(t, err)
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	name, args := command(MultiplexerTmux, "/tmp/it's.txt", Options{})
+	require.Equal(t, "tmux", name)
+	require.Equal(t, []string{"split-window", "-h", "-d", `less -R '/tmp/it'\''s.txt'; rm -f '/tmp/it'\''s.txt'`}, args)
+
+	name, args = command(MultiplexerITerm2, "/tmp/out.txt", Options{Pager: "bat", Vertical: true})
+	require.Equal(t, "osascript", name)
+	require.Contains(t, args, `split horizontally with default profile command "/bin/sh -c 'bat '\\''/tmp/out.txt'\\''; rm -f '\\''/tmp/out.txt'\\'''"`)
+}
+
+func TestOpenUnsupported(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, Open(t.Context(), MultiplexerNone, "", "x", Options{}), ErrUnsupported)
+}
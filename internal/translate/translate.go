@@ -0,0 +1,150 @@
+// Package translate translates assistant markdown into another language
+// while leaving fenced code blocks untouched.
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/ext"
+)
+
+// systemPrompt instructs the model to translate prose only. %s is the
+// target language.
+const systemPrompt = `You are a translator. Translate the user's markdown into %s.
+Keep the markdown structure intact. Do not translate inline code, file paths,
+identifiers, URLs or command lines. Leave every placeholder of the form
+<<CODE_n>> exactly as it is, on its own line. Reply with the translation only.`
+
+// ErrUnavailable is returned when no model is available to translate with.
+var ErrUnavailable = errors.New("translate: no model available")
+
+// segment is a run of prose or a fenced code block.
+type segment struct {
+	text string
+	code bool
+}
+
+// Markdown translates the prose of src into language using complete,
+// keeping fenced code blocks byte-for-byte. Code blocks are swapped for
+// placeholders so the prose is translated in a single request; if the
+// model drops or reorders a placeholder, each prose run is translated on
+// its own instead.
+func Markdown(ctx context.Context, complete ext.TextCompleter, language, src string) (string, error) {
+	if complete == nil {
+		return "", ErrUnavailable
+	}
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return "", errors.New("translate: no target language configured")
+	}
+	segs := split(src)
+	prompt := fmt.Sprintf(systemPrompt, language)
+
+	var masked strings.Builder
+	var codes []string
+	for _, s := range segs {
+		if s.code {
+			fmt.Fprintf(&masked, "\n<<CODE_%d>>\n", len(codes))
+			codes = append(codes, s.text)
+			continue
+		}
+		masked.WriteString(s.text)
+	}
+	if len(codes) == 0 {
+		return complete(ctx, prompt, src)
+	}
+
+	out, err := complete(ctx, prompt, masked.String())
+	if err != nil {
+		return "", err
+	}
+	if restored, ok := restore(out, codes); ok {
+		return restored, nil
+	}
+
+	var b strings.Builder
+	for _, s := range segs {
+		if s.code || strings.TrimSpace(s.text) == "" {
+			b.WriteString(s.text)
+			continue
+		}
+		t, err := complete(ctx, prompt, s.text)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(t)
+		if !strings.HasSuffix(t, "\n") && strings.HasSuffix(s.text, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// restore substitutes the code blocks back for their placeholders. It
+// fails if any placeholder is missing, duplicated or out of order.
+func restore(out string, codes []string) (string, bool) {
+	pos := 0
+	for i := range codes {
+		ph := fmt.Sprintf("<<CODE_%d>>", i)
+		if strings.Count(out, ph) != 1 {
+			return "", false
+		}
+		idx := strings.Index(out, ph)
+		if idx < pos {
+			return "", false
+		}
+		pos = idx
+	}
+	for i, code := range codes {
+		out = strings.Replace(out, fmt.Sprintf("<<CODE_%d>>", i), strings.TrimSuffix(code, "\n"), 1)
+	}
+	return out, true
+}
+
+// split breaks src into prose and fenced code block segments. An
+// unclosed fence runs to the end of src.
+func split(src string) []segment {
+	var segs []segment
+	var cur strings.Builder
+	var marker string
+	flush := func(code bool) {
+		if cur.Len() > 0 {
+			segs = append(segs, segment{text: cur.String(), code: code})
+			cur.Reset()
+		}
+	}
+	lines := strings.SplitAfter(src, "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if marker == "" {
+			if m := fence(trimmed); m != "" {
+				flush(false)
+				marker = m
+			}
+			cur.WriteString(line)
+			continue
+		}
+		cur.WriteString(line)
+		if strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == "" {
+			flush(true)
+			marker = ""
+		}
+	}
+	flush(marker != "")
+	return segs
+}
+
+// fence returns the fence marker that line opens, or "".
+func fence(line string) string {
+	for _, ch := range []string{"`", "~"} {
+		if !strings.HasPrefix(line, strings.Repeat(ch, 3)) {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, ch))
+		return line[:n]
+	}
+	return ""
+}
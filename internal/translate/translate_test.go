@@ -0,0 +1,70 @@
+package translate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	src := "intro\n```go\nfmt.Println(1)\n```\noutro\n~~~~\nraw\n"
+	segs := split(src)
+	require.Equal(t, []segment{
+		{text: "intro\n"},
+		{text: "```go\nfmt.Println(1)\n```\n", code: true},
+		{text: "outro\n"},
+		{text: "~~~~\nraw\n", code: true},
+	}, segs)
+}
+
+func TestMarkdown_KeepsCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	src := "Hello\n```sh\necho hi\n```\nBye\n"
+	calls := 0
+	complete := func(_ context.Context, prompt, input string) (string, error) {
+		calls++
+		require.Contains(t, prompt, "French")
+		require.NotContains(t, input, "echo hi")
+		out := strings.ReplaceAll(input, "Hello", "Bonjour")
+		return strings.ReplaceAll(out, "Bye", "Salut"), nil
+	}
+
+	out, err := Markdown(t.Context(), complete, "French", src)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+	require.Contains(t, out, "Bonjour")
+	require.Contains(t, out, "```sh\necho hi\n```")
+	require.Contains(t, out, "Salut")
+}
+
+func TestMarkdown_FallsBackWhenPlaceholderDropped(t *testing.T) {
+	t.Parallel()
+
+	src := "Hello\n```sh\necho hi\n```\nBye\n"
+	complete := func(_ context.Context, _, input string) (string, error) {
+		if strings.Contains(input, "<<CODE_0>>") {
+			return "Bonjour\nSalut\n", nil
+		}
+		return strings.ToUpper(input), nil
+	}
+
+	out, err := Markdown(t.Context(), complete, "French", src)
+	require.NoError(t, err)
+	require.Equal(t, "HELLO\n```sh\necho hi\n```\nBYE\n", out)
+}
+
+func TestMarkdown_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Markdown(t.Context(), nil, "French", "hi")
+	require.ErrorIs(t, err, ErrUnavailable)
+
+	echo := func(_ context.Context, _, input string) (string, error) { return input, nil }
+	_, err = Markdown(t.Context(), echo, " ", "hi")
+	require.Error(t, err)
+}
@@ -542,6 +542,89 @@ func TestSymbolInfoTypeScriptClassWithModifiers(t *testing.T) {
 	}
 }
 
+func TestSymbolInfoPythonAsyncAndDecoratedClass(t *testing.T) {
+	t.Parallel()
+
+	pyLang := tree_sitter.NewLanguage(tree_sitter_python.Language())
+	p := tree_sitter.NewParser()
+	t.Cleanup(p.Close)
+	require.NoError(t, p.SetLanguage(pyLang))
+
+	src := []byte(`@dataclass
+class Job:
+    async def run(self, retries: int = 3) -> None:
+        pass
+
+async def main():
+    pass
+`)
+	tree := p.Parse(src, nil)
+	t.Cleanup(tree.Close)
+
+	loader := NewQueryLoader()
+	loader.RegisterLanguage("python", pyLang)
+	t.Cleanup(func() { require.NoError(t, loader.Close()) })
+
+	_, symbols, err := loader.ExtractTags("python", "job.py", tree.RootNode(), src)
+	require.NoError(t, err)
+
+	symMap := make(map[string]*SymbolInfo)
+	for i := range symbols {
+		symMap[symbols[i].Name] = &symbols[i]
+	}
+
+	job, ok := symMap["Job"]
+	require.True(t, ok, "expected Job symbol")
+	require.Equal(t, []string{"dataclass"}, job.Decorators)
+
+	run, ok := symMap["run"]
+	require.True(t, ok, "expected run symbol")
+	require.Equal(t, "Job", run.Parent, "decorated class methods keep their parent")
+	require.Contains(t, run.Modifiers, "async")
+
+	mainFn, ok := symMap["main"]
+	require.True(t, ok, "expected main symbol")
+	require.Contains(t, mainFn.Modifiers, "async")
+}
+
+func TestSymbolInfoTypeScriptDefaultAndNamedExports(t *testing.T) {
+	t.Parallel()
+
+	tsLang := tree_sitter.NewLanguage(tree_sitter_typescript.LanguageTypescript())
+	p := tree_sitter.NewParser()
+	t.Cleanup(p.Close)
+	require.NoError(t, p.SetLanguage(tsLang))
+
+	src := []byte(`export default class App {}
+
+export async function load(id: string): Promise<void> {}
+
+export interface Options {}
+
+function helper() {}
+`)
+	tree := p.Parse(src, nil)
+	t.Cleanup(tree.Close)
+
+	loader := NewQueryLoader()
+	loader.RegisterLanguage("typescript", tsLang)
+	t.Cleanup(func() { require.NoError(t, loader.Close()) })
+
+	_, symbols, err := loader.ExtractTags("typescript", "app.ts", tree.RootNode(), src)
+	require.NoError(t, err)
+
+	symMap := make(map[string]*SymbolInfo)
+	for i := range symbols {
+		symMap[symbols[i].Name] = &symbols[i]
+	}
+
+	require.Equal(t, []string{"export", "default"}, symMap["App"].Modifiers)
+	require.ElementsMatch(t, []string{"export", "async"}, symMap["load"].Modifiers)
+	require.Equal(t, "Promise<void>", symMap["load"].ReturnType)
+	require.Equal(t, []string{"export"}, symMap["Options"].Modifiers)
+	require.Empty(t, symMap["helper"].Modifiers)
+}
+
 func TestExtractScalaImports(t *testing.T) {
 	t.Parallel()
 
@@ -117,6 +117,9 @@ type ParserConfig struct {
 	// PoolSize controls the parser pool capacity.
 	// Zero or negative values fall back to runtime defaults.
 	PoolSize int
+	// TagsQueries holds a repository's own tags queries by query key, as
+	// read by LoadCustomTagsQueries.
+	TagsQueries map[string][]byte
 }
 
 // NewParserPool creates a parser pool using runtime defaults.
@@ -305,7 +308,7 @@ func NewParserWithConfig(cfg ParserConfig) Parser {
 		languages:   languages,
 		langSet:     langSet,
 		treeCache:   NewCache(0, 0),
-		queryLoader: NewQueryLoader(),
+		queryLoader: NewQueryLoaderWithTags(cfg.TagsQueries),
 		treeLangs:   map[string]*tree_sitter.Language{},
 	}
 	pr.initLanguages()
@@ -504,7 +507,7 @@ func (p *parser) HasTags(lang string) bool {
 	if !p.SupportsLanguage(lang) {
 		return false
 	}
-	return HasTagsQuery(lang)
+	return p.queryLoader.HasTags(lang)
 }
 
 // Close closes parser resources.
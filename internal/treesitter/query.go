@@ -3,27 +3,108 @@
 package treesitter
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 
 	tree_sitter "github.com/tree-sitter/go-tree-sitter"
 )
 
+// CustomQueryDir is where a repository keeps its own tags queries,
+// relative to its root. "<lang>-tags.scm" in it replaces the embedded tags
+// query for lang, or extends it when its first line is "; extends".
+const CustomQueryDir = ".crush/queries"
+
+// extendsDirective marks a custom tags query that adds to the embedded one,
+// as in Neovim's query files.
+var extendsDirective = regexp.MustCompile(`^;+\s*extends\s*$`)
+
 // QueryLoader manages loading and caching language queries.
 type QueryLoader struct {
 	mu         sync.RWMutex
 	languages  map[string]*tree_sitter.Language
 	queries    map[string]*tree_sitter.Query
 	captureMap map[string][]string
+
+	// customTags holds the repository's tags queries by query key. It is
+	// not modified after construction.
+	customTags map[string][]byte
 }
 
 // NewQueryLoader creates a new query loader.
 func NewQueryLoader() *QueryLoader {
+	return NewQueryLoaderWithTags(nil)
+}
+
+// NewQueryLoaderWithTags creates a query loader using the custom tags
+// queries in customTags, keyed by query key, in place of or in addition to
+// the embedded ones.
+func NewQueryLoaderWithTags(customTags map[string][]byte) *QueryLoader {
 	return &QueryLoader{
 		languages:  make(map[string]*tree_sitter.Language),
 		queries:    make(map[string]*tree_sitter.Query),
 		captureMap: make(map[string][]string),
+		customTags: customTags,
+	}
+}
+
+// LoadCustomTagsQueries reads the "<lang>-tags.scm" files in dir, keyed by
+// query key. A missing dir holds no queries.
+func LoadCustomTagsQueries(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read tags queries: %w", err)
+	}
+
+	queries := make(map[string][]byte)
+	for _, entry := range entries {
+		lang, ok := strings.CutSuffix(entry.Name(), "-tags.scm")
+		if !ok || entry.IsDir() || GetQueryKey(lang) == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read tags query %q: %w", entry.Name(), err)
+		}
+		queries[GetQueryKey(lang)] = data
+	}
+	return queries, nil
+}
+
+// extendsEmbedded reports whether a custom tags query adds to the embedded
+// query rather than replacing it.
+func extendsEmbedded(query []byte) bool {
+	for line := range strings.Lines(string(query)) {
+		if line = strings.TrimSpace(line); line != "" {
+			return extendsDirective.MatchString(line)
+		}
+	}
+	return false
+}
+
+// tagsQuerySource returns the tags query for queryKey and whether the
+// repository customized it.
+func (q *QueryLoader) tagsQuerySource(queryKey string) (string, bool, error) {
+	embedded, err := LoadTagsQuery(queryKey)
+	custom, ok := q.customTags[queryKey]
+	switch {
+	case !ok && err != nil:
+		return "", false, err
+	case !ok:
+		return string(embedded), false, nil
+	case err == nil && extendsEmbedded(custom):
+		return string(embedded) + "\n" + string(custom), true, nil
+	default:
+		return string(custom), true, nil
 	}
 }
 
@@ -61,12 +142,19 @@ func (q *QueryLoader) LoadQuery(lang string) (*tree_sitter.Query, error) {
 		return nil, fmt.Errorf("language %q not registered", queryKey)
 	}
 
-	querySource, err := LoadTagsQuery(queryKey)
+	querySource, custom, err := q.tagsQuerySource(queryKey)
 	if err != nil {
 		return nil, fmt.Errorf("load tags query %q: %w", queryKey, err)
 	}
 
-	compiled, qErr := tree_sitter.NewQuery(language, string(querySource))
+	compiled, qErr := tree_sitter.NewQuery(language, querySource)
+	if qErr != nil && custom {
+		// A broken repository query should not cost the language its tags.
+		slog.Warn("Custom tags query does not compile, using the embedded one", "language", queryKey, "error", qErr)
+		if embedded, err := LoadTagsQuery(queryKey); err == nil {
+			compiled, qErr = tree_sitter.NewQuery(language, string(embedded))
+		}
+	}
 	if qErr != nil {
 		return nil, fmt.Errorf("compile tags query %q: %w", queryKey, qErr)
 	}
@@ -88,7 +176,8 @@ func (q *QueryLoader) LoadQuery(lang string) (*tree_sitter.Query, error) {
 
 // HasTags reports whether tags are available for the language.
 func (q *QueryLoader) HasTags(lang string) bool {
-	return HasTags(GetQueryKey(lang))
+	queryKey := GetQueryKey(lang)
+	return HasTags(queryKey) || len(q.customTags[queryKey]) > 0
 }
 
 // Languages returns the loaded language set.
@@ -385,6 +474,17 @@ func enrichPythonSymbols(symbols []SymbolInfo, root *tree_sitter.Node, content [
 		return ""
 	}
 
+	// markAsync records the async keyword of an "async def".
+	markAsync := func(def *tree_sitter.Node, sym *SymbolInfo) {
+		for i := range int(def.ChildCount()) {
+			child := def.Child(uint(i))
+			if child != nil && !child.IsNamed() && child.Kind() == "async" {
+				sym.Modifiers = append(sym.Modifiers, "async")
+				return
+			}
+		}
+	}
+
 	var walk func(n *tree_sitter.Node, parentClass string)
 	walk = func(n *tree_sitter.Node, parentClass string) {
 		if n == nil {
@@ -409,9 +509,6 @@ func enrichPythonSymbols(symbols []SymbolInfo, root *tree_sitter.Node, content [
 
 		case "decorated_definition":
 			var decorators []string
-			var funcName string
-			var funcLine int
-			var funcBody *tree_sitter.Node
 			for i := range int(n.ChildCount()) {
 				child := n.Child(uint(i))
 				if child == nil {
@@ -424,23 +521,33 @@ func enrichPythonSymbols(symbols []SymbolInfo, root *tree_sitter.Node, content [
 				}
 			}
 			defNode := n.ChildByFieldName("definition")
-			if defNode != nil && defNode.Kind() == "function_definition" {
-				if nameNode := defNode.ChildByFieldName("name"); nameNode != nil {
-					funcName = strings.TrimSpace(nameNode.Utf8Text(content))
-					funcLine = int(defNode.StartPosition().Row) + 1
-					funcBody = defNode.ChildByFieldName("body")
-				}
+			if defNode == nil {
+				return
 			}
-			if funcName != "" {
-				if sym, ok := symMap[symKey{funcName, funcLine}]; ok {
+			nameNode := defNode.ChildByFieldName("name")
+			if nameNode == nil {
+				return
+			}
+			name := strings.TrimSpace(nameNode.Utf8Text(content))
+			line := int(defNode.StartPosition().Row) + 1
+			switch defNode.Kind() {
+			case "function_definition":
+				if sym, ok := symMap[symKey{name, line}]; ok {
 					if parentClass != "" {
 						sym.Parent = parentClass
 					}
 					sym.Decorators = append(sym.Decorators, decorators...)
+					markAsync(defNode, sym)
 					if sym.DocComment == "" {
-						sym.DocComment = extractDoc(funcBody)
+						sym.DocComment = extractDoc(defNode.ChildByFieldName("body"))
 					}
 				}
+			case "class_definition":
+				// Decorated classes still need their methods walked.
+				if sym, ok := symMap[symKey{name, line}]; ok {
+					sym.Decorators = append(sym.Decorators, decorators...)
+				}
+				walk(defNode, parentClass)
 			}
 			return
 
@@ -453,6 +560,7 @@ func enrichPythonSymbols(symbols []SymbolInfo, root *tree_sitter.Node, content [
 					if parentClass != "" {
 						sym.Parent = parentClass
 					}
+					markAsync(n, sym)
 					if sym.DocComment == "" {
 						sym.DocComment = extractDoc(n.ChildByFieldName("body"))
 					}
@@ -490,31 +598,35 @@ func enrichTSSymbols(symbols []SymbolInfo, root *tree_sitter.Node, content []byt
 
 		switch n.Kind() {
 		case "export_statement":
+			// "export default" marks the declaration with both modifiers so
+			// default and named exports can be told apart.
+			isDefault := false
+			for i := range int(n.ChildCount()) {
+				child := n.Child(uint(i))
+				if child != nil && !child.IsNamed() && child.Utf8Text(content) == "default" {
+					isDefault = true
+				}
+			}
 			for i := range int(n.ChildCount()) {
 				child := n.Child(uint(i))
 				if child == nil || !child.IsNamed() {
 					continue
 				}
 				switch child.Kind() {
-				case "class_declaration", "abstract_class_declaration":
+				case "class_declaration", "abstract_class_declaration", "function_declaration",
+					"interface_declaration", "type_alias_declaration", "enum_declaration":
 					nameNode := child.ChildByFieldName("name")
 					if nameNode != nil {
 						name := strings.TrimSpace(nameNode.Utf8Text(content))
 						line := int(child.StartPosition().Row) + 1
 						if sym, ok := symMap[symKey{name, line}]; ok {
 							sym.Modifiers = append(sym.Modifiers, "export")
+							if isDefault {
+								sym.Modifiers = append(sym.Modifiers, "default")
+							}
 						}
 					}
 					walk(child)
-				case "function_declaration":
-					nameNode := child.ChildByFieldName("name")
-					if nameNode != nil {
-						name := strings.TrimSpace(nameNode.Utf8Text(content))
-						line := int(child.StartPosition().Row) + 1
-						if sym, ok := symMap[symKey{name, line}]; ok {
-							sym.Modifiers = append(sym.Modifiers, "export")
-						}
-					}
 				}
 			}
 			return
@@ -247,3 +247,68 @@ func TestQueryLoaderCachesCompiledQuery(t *testing.T) {
 	require.NoError(t, err)
 	require.Same(t, q1, q2)
 }
+
+func TestQueryLoaderCustomTagsQuery(t *testing.T) {
+	t.Parallel()
+
+	goLang := tree_sitter.NewLanguage(tree_sitter_go.Language())
+	p := tree_sitter.NewParser()
+	t.Cleanup(p.Close)
+	require.NoError(t, p.SetLanguage(goLang))
+
+	src := []byte(`package main
+
+func Run() {
+	http.HandleFunc("/health", health)
+}
+`)
+	tree := p.Parse(src, nil)
+	t.Cleanup(tree.Close)
+
+	routes := `(call_expression
+  function: (selector_expression field: (field_identifier) @_method)
+  arguments: (argument_list . (interpreted_string_literal) @name.definition.route)
+  (#eq? @_method "HandleFunc"))
+`
+	defs := func(custom string) []string {
+		loader := NewQueryLoaderWithTags(map[string][]byte{"go": []byte(custom)})
+		loader.RegisterLanguage("go", goLang)
+		t.Cleanup(func() { require.NoError(t, loader.Close()) })
+		tags, _, err := loader.ExtractTags("go", "main.go", tree.RootNode(), src)
+		require.NoError(t, err)
+		var names []string
+		for _, tag := range tags {
+			if tag.Kind == "def" {
+				names = append(names, tag.NodeType+" "+tag.Name)
+			}
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"module main", "function Run", `route "/health"`}, defs(";; extends\n"+routes))
+	require.Equal(t, []string{`route "/health"`}, defs(routes), "a query without the extends directive replaces the embedded one")
+	require.Equal(t, []string{"module main", "function Run"}, defs("; extends\n(call_expression"), "a broken query falls back to the embedded one")
+}
+
+func TestLoadCustomTagsQueries(t *testing.T) {
+	t.Parallel()
+
+	queries, err := LoadCustomTagsQueries(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	require.Empty(t, queries)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go-tags.scm"), []byte("; extends\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Python-tags.scm"), []byte("(function_definition)"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a query"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go-imports.scm"), []byte("(import_spec)"), 0o644))
+
+	queries, err = LoadCustomTagsQueries(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"go":     []byte("; extends\n"),
+		"python": []byte("(function_definition)"),
+	}, queries)
+	require.True(t, extendsEmbedded(queries["go"]))
+	require.False(t, extendsEmbedded(queries["python"]))
+}
@@ -2,6 +2,7 @@
 package anim
 
 import (
+	"cmp"
 	"fmt"
 	"image/color"
 	"math/rand/v2"
@@ -64,6 +65,16 @@ var (
 // are received only by spinner components that sent them.
 var lastID atomic.Int64
 
+// reducedMotion disables animation for every spinner; see SetReducedMotion.
+var reducedMotion atomic.Bool
+
+// SetReducedMotion turns off spinner animation globally. While set,
+// spinners never schedule frames and render as a static text label,
+// which keeps screen readers from re-announcing every frame.
+func SetReducedMotion(v bool) {
+	reducedMotion.Store(v)
+}
+
 func nextID() int {
 	return int(lastID.Add(1))
 }
@@ -110,6 +121,7 @@ type Anim struct {
 	width            int
 	cyclingCharWidth int
 	label            *csync.Slice[string]
+	labelText        string
 	labelWidth       int
 	labelColor       color.Color
 	birthSteps       []int
@@ -147,6 +159,7 @@ func New(opts Settings) *Anim {
 	}
 	a.cyclingCharWidth = opts.Size
 	a.labelColor = opts.LabelColor
+	a.labelText = opts.Label
 
 	// Check cache first
 	cacheKey := settingsHash(opts)
@@ -280,6 +293,7 @@ func New(opts Settings) *Anim {
 
 // SetLabel updates the label text and re-renders it.
 func (a *Anim) SetLabel(newLabel string) {
+	a.labelText = newLabel
 	a.labelWidth = lipgloss.Width(newLabel)
 
 	// Update total width
@@ -339,12 +353,15 @@ func (a *Anim) Width() (w int) {
 
 // Start starts the animation.
 func (a *Anim) Start() tea.Cmd {
+	if reducedMotion.Load() {
+		return nil
+	}
 	return a.Step()
 }
 
 // Animate advances the animation to the next step.
 func (a *Anim) Animate(msg StepMsg) tea.Cmd {
-	if msg.ID != a.id {
+	if msg.ID != a.id || reducedMotion.Load() {
 		return nil
 	}
 
@@ -368,6 +385,10 @@ func (a *Anim) Animate(msg StepMsg) tea.Cmd {
 
 // Render renders the current state of the animation.
 func (a *Anim) Render() string {
+	if reducedMotion.Load() {
+		label := cmp.Or(a.labelText, "Working")
+		return lipgloss.NewStyle().Foreground(a.labelColor).Render(label + "...")
+	}
 	var b strings.Builder
 	step := int(a.step.Load())
 	frames := int(a.framesSinceStart.Load())
@@ -9,6 +9,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/ui/anim"
 	"github.com/charmbracelet/crush/internal/ui/common"
@@ -132,6 +133,22 @@ type AssistantMessageItem struct {
 	thinkingViewMode  thinkingViewMode
 	thinkingBoxHeight int // Tracks the rendered thinking box height for click detection.
 
+	// XRUSH: content display toggles. codeUnfolded shows long fenced code
+	// blocks in full; rawView shows the markdown source unrendered;
+	// showUsage annotates the footer with the step's tokens and cost.
+	codeUnfolded bool
+	rawView      bool
+	showUsage    bool
+	// hideThinking leaves the thinking block out, per the reasoning
+	// display setting.
+	hideThinking bool
+
+	// XRUSH: translation of the content into the configured language,
+	// shown below the original or beside it when sideBySide is set.
+	translation    string
+	sideBySide     bool
+	translationSec assistantSection
+
 	// Per-section render caches. Splitting these out means content
 	// streaming does not invalidate the (often expensive) thinking
 	// render, and vice versa.
@@ -243,6 +260,19 @@ func (a *AssistantMessageItem) RawRender(width int) string {
 	if spinner != "" {
 		parts = append(parts, spinner)
 	}
+	if attribution := a.renderAttribution(cappedWidth); attribution != "" { // XRUSH: context attribution
+		parts = append(parts, attribution)
+	}
+	if candidates := a.renderCandidates(cappedWidth); candidates != "" { // XRUSH: multi-candidate sampling
+		parts = append(parts, candidates)
+	}
+	if usage := a.renderUsage(); usage != "" {
+		if endTimestamp != "" {
+			endTimestamp += " " + usage
+		} else {
+			endTimestamp = usage
+		}
+	}
 	if endTimestamp != "" {
 		parts = append(parts, endTimestamp)
 	}
@@ -341,9 +371,65 @@ func (a *AssistantMessageItem) compositionKey() uint64 {
 		finishedFlag = 1
 		reason = string(a.message.FinishReason())
 	}
+	var usage string
+	if a.showUsage && a.message.HasUsage() {
+		usage = fmt.Sprintf("%d/%d/%d/%g", a.message.PromptTokens, a.message.CompletionTokens, a.message.ReasoningTokens(), a.message.Cost)
+	}
+	var sideBySide, hideThinking byte
+	if a.sideBySide {
+		sideBySide = 1
+	}
+	if a.hideThinking {
+		hideThinking = 1
+	}
 	// Length-prefixed framing keeps the finished flag and the reason
 	// string from blending into one another.
-	return fnvFields([]byte{finishedFlag}, []byte(reason))
+	return fnvFields([]byte{finishedFlag, sideBySide, hideThinking}, []byte(reason), []byte(usage), []byte(a.translation), []byte(a.attributionKey()), []byte(a.candidatesKey()))
+}
+
+// SetShowUsage toggles the token and cost annotation in the footer.
+func (a *AssistantMessageItem) SetShowUsage(show bool) {
+	if a.showUsage == show {
+		return
+	}
+	a.showUsage = show
+	a.Bump()
+}
+
+// SetThinkingDisplay applies a reasoning display mode: collapsed is the
+// default, expanded opens the thinking block in full and hidden leaves it
+// out.
+func (a *AssistantMessageItem) SetThinkingDisplay(mode string) {
+	hide := mode == config.ReasoningDisplayHidden
+	viewMode := a.thinkingViewMode
+	if mode == config.ReasoningDisplayExpanded && viewMode == thinkingCollapsed {
+		viewMode = thinkingFullExpanded
+	}
+	if hide == a.hideThinking && viewMode == a.thinkingViewMode {
+		return
+	}
+	a.hideThinking = hide
+	a.thinkingViewMode = viewMode
+	a.Bump()
+}
+
+// renderUsage renders the token and cost annotation for a finished
+// message, or "" when it is hidden or no usage was recorded.
+func (a *AssistantMessageItem) renderUsage() string {
+	if !a.showUsage || !a.message.IsFinished() || !a.message.HasUsage() {
+		return ""
+	}
+	var reasoning string
+	if tokens := a.message.ReasoningTokens(); tokens > 0 {
+		reasoning = fmt.Sprintf(" (%s reasoning)", common.FormatTokenCount(tokens))
+	}
+	return a.sty.Messages.AssistantTimestamp.Render(fmt.Sprintf(
+		"· ↑%s ↓%s%s · $%.4f",
+		common.FormatTokenCount(a.message.PromptTokens),
+		common.FormatTokenCount(a.message.CompletionTokens),
+		reasoning,
+		a.message.Cost,
+	))
 }
 
 // renderMessageContent renders the message content including thinking, main
@@ -354,6 +440,9 @@ func (a *AssistantMessageItem) renderMessageContent(width int) (string, int) {
 	var messageParts []string
 	thinking := strings.TrimSpace(a.message.ReasoningContent().Thinking)
 	content := strings.TrimSpace(a.message.Content().Text)
+	if a.hideThinking {
+		thinking = ""
+	}
 
 	if thinking != "" {
 		messageParts = append(messageParts, a.cachedThinking(width))
@@ -363,7 +452,7 @@ func (a *AssistantMessageItem) renderMessageContent(width int) (string, int) {
 		if thinking != "" {
 			messageParts = append(messageParts, "")
 		}
-		messageParts = append(messageParts, a.cachedContent(width))
+		messageParts = append(messageParts, a.renderContentSection(width))
 	}
 
 	if a.message.IsFinished() {
@@ -409,9 +498,17 @@ func (a *AssistantMessageItem) thinkingKey() (uint64, uint64) {
 }
 
 // contentKey returns the (srcHash, extra) cache key components for the
-// main content section.
+// main content section. extra folds in the code folding and raw view
+// toggles.
 func (a *AssistantMessageItem) contentKey() (uint64, uint64) {
-	return fnv64(a.message.Content().Text), 0
+	var flags byte
+	if a.codeUnfolded {
+		flags |= 1
+	}
+	if a.rawView {
+		flags |= 2
+	}
+	return fnv64(a.message.Content().Text), uint64(flags)
 }
 
 // errorKey returns the (srcHash, extra) cache key components for the
@@ -539,6 +636,14 @@ func (a *AssistantMessageItem) renderThinking(thinking string, width int) string
 // detection has the slightest doubt — see
 // findSafeMarkdownBoundary.
 func (a *AssistantMessageItem) renderMarkdown(content string, width int) string {
+	if a.rawView {
+		return ansi.Wrap(content, width, "")
+	}
+	foldAt := codeFoldLines
+	if a.codeUnfolded {
+		foldAt = 0
+	}
+	content, _ = prepareMarkdown(content, foldAt)
 	renderer := common.MarkdownRenderer(a.sty, width)
 	return a.streamingContent.Render(content, width, renderer)
 }
@@ -616,6 +721,7 @@ func (a *AssistantMessageItem) clearCache() {
 	a.thinkingSec.reset()
 	a.contentSec.reset()
 	a.errorSec.reset()
+	a.translationSec.reset()
 	a.streamingContent.Reset()
 }
 
@@ -632,6 +738,9 @@ func (a *AssistantMessageItem) clearCache() {
 // there is nothing to expand, and mutating the view mode would
 // thrash the thinking-section cache key for no visible benefit.
 func (a *AssistantMessageItem) ToggleExpanded() bool {
+	if a.hideThinking {
+		return false
+	}
 	if strings.TrimSpace(a.message.ReasoningContent().Thinking) == "" {
 		return a.thinkingViewMode != thinkingCollapsed
 	}
@@ -690,9 +799,23 @@ func (a *AssistantMessageItem) HandleMouseClick(btn ansi.MouseButton, x, y int)
 
 // HandleKeyEvent implements KeyEventHandler.
 func (a *AssistantMessageItem) HandleKeyEvent(key tea.KeyMsg) (bool, tea.Cmd) {
-	if k := key.String(); k == "c" || k == "y" {
+	switch key.String() {
+	case "c", "y":
 		text := a.message.Content().Text
 		return true, common.CopyToClipboard(text, "Message copied to clipboard")
+	case "z":
+		a.codeUnfolded = !a.codeUnfolded
+		a.Bump()
+		return true, nil
+	case "r":
+		a.rawView = !a.rawView
+		a.Bump()
+		return true, nil
 	}
 	return false, nil
 }
+
+// PaneContent implements PaneContentProvider.
+func (a *AssistantMessageItem) PaneContent() string {
+	return a.message.Content().Text
+}
@@ -0,0 +1,49 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssistantMessageItemContextAttribution(t *testing.T) {
+	t.Parallel()
+
+	sty := styles.CharmtonePantera()
+	msg := &message.Message{
+		ID:   "attribution",
+		Role: message.Assistant,
+		Parts: []message.ContentPart{
+			message.TextContent{Text: "Fixed main.go and util.go"},
+			message.Finish{Reason: message.FinishReasonEndTurn, Time: time.Now().Unix()},
+		},
+	}
+	item := NewAssistantMessageItem(&sty, msg).(*AssistantMessageItem)
+	require.NotContains(t, ansi.Strip(item.Render(100)), "Context:")
+
+	msg.SetContextAttribution(message.ContextAttribution{
+		Sources: []message.ContextSource{
+			{Kind: message.ContextSourceRepoMap},
+			{Kind: message.ContextSourceSummary, Name: "a"},
+			{Kind: message.ContextSourceSummary, Name: "b"},
+			{Kind: message.ContextSourceFile, Name: "main.go"},
+		},
+		Citations: []message.FileCitation{
+			{Path: "main.go", Status: message.CitationRead},
+			{Path: "util.go", Status: message.CitationAbsent},
+			{Path: "pkg/gone.go", Status: message.CitationMentioned, Missing: true},
+		},
+	})
+	item.SetMessage(msg)
+
+	out := ansi.Strip(item.Render(100))
+	require.Contains(t, out, "Context: repo map · 2 LCM summaries · 1 file")
+	require.Contains(t, out, "UNVERIFIED")
+	require.Contains(t, out, "util.go (not in context)")
+	require.Contains(t, out, "pkg/gone.go (name only, not on disk)")
+	require.NotContains(t, out, "main.go (")
+}
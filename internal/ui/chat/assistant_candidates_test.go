@@ -0,0 +1,50 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssistantMessageItemCandidates(t *testing.T) {
+	t.Parallel()
+
+	sty := styles.CharmtonePantera()
+	msg := &message.Message{
+		ID:   "candidates",
+		Role: message.Assistant,
+		Parts: []message.ContentPart{
+			message.TextContent{Text: "first answer"},
+			message.Finish{Reason: message.FinishReasonEndTurn, Time: time.Now().Unix()},
+		},
+	}
+	item := NewAssistantMessageItem(&sty, msg).(*AssistantMessageItem)
+	require.NotContains(t, ansi.Strip(item.Render(100)), "Candidates:")
+
+	msg.SetResponseCandidates(message.ResponseCandidates{
+		Candidates: []message.ResponseCandidate{
+			{Text: "first answer"},
+			{Text: "second answer", Cost: 0.02},
+			{Text: "third answer", Cost: 0.01},
+		},
+		Chosen:    1,
+		Judge:     "small",
+		Reason:    "covers the error path",
+		JudgeCost: 0.001,
+	})
+	item.SetMessage(msg)
+
+	out := ansi.Strip(item.Render(100))
+	require.Contains(t, out, "second answer")
+	require.NotContains(t, out, "first answer")
+	require.Contains(t, out, "Candidates: 3 sampled · #2 chosen by small · +$0.0310")
+	require.Contains(t, out, "covers the error path")
+
+	msg.ChooseCandidate(2)
+	item.SetMessage(msg)
+	require.Contains(t, ansi.Strip(item.Render(100)), "#3 chosen by small")
+}
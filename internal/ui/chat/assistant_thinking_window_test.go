@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/ui/styles"
 	"github.com/charmbracelet/x/ansi"
@@ -498,3 +499,28 @@ func TestThinkingWindow_BoxHeightTracksWindow(t *testing.T) {
 		"full expansion box height must reflect the full thinking render; got %d",
 		fullHeight)
 }
+
+// TestAssistantMessageItemThinkingDisplay covers the reasoning display
+// modes: expanded opens the thinking block, hidden leaves it out and
+// ignores toggles.
+func TestAssistantMessageItemThinkingDisplay(t *testing.T) {
+	t.Parallel()
+
+	sty := styles.CharmtonePantera()
+	msg := thinkingMessageWithLines("display", 3)
+	msg.Parts = append(msg.Parts, message.TextContent{Text: "the reply"})
+	item := NewAssistantMessageItem(&sty, msg).(*AssistantMessageItem)
+	require.Equal(t, thinkingCollapsed, item.thinkingViewMode)
+
+	requireBump(t, "SetThinkingDisplay expanded", item, func() {
+		item.SetThinkingDisplay(config.ReasoningDisplayExpanded)
+	})
+	require.Equal(t, thinkingFullExpanded, item.thinkingViewMode)
+	require.Contains(t, ansi.Strip(item.Render(80)), "ln3")
+
+	item.SetThinkingDisplay(config.ReasoningDisplayHidden)
+	out := ansi.Strip(item.Render(80))
+	require.NotContains(t, out, "ln1")
+	require.Contains(t, out, "the reply")
+	require.False(t, item.ToggleExpanded())
+}
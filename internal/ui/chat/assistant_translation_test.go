@@ -0,0 +1,48 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssistantMessageItemTranslation(t *testing.T) {
+	t.Parallel()
+
+	sty := styles.CharmtonePantera()
+	msg := &message.Message{
+		ID:    "translated",
+		Role:  message.Assistant,
+		Parts: []message.ContentPart{message.TextContent{Text: "Hello there"}},
+	}
+	item := NewAssistantMessageItem(&sty, msg).(*AssistantMessageItem)
+	require.Equal(t, "Hello there", item.Text())
+
+	requireBump(t, "SetTranslation", item, func() {
+		item.SetTranslation("Bonjour", false)
+	})
+	out := ansi.Strip(item.Render(80))
+	require.Contains(t, out, "Translation")
+	require.Contains(t, out, "Bonjour")
+
+	item.SetTranslation("Bonjour", true)
+	out = ansi.Strip(item.Render(100))
+	require.NotContains(t, out, "Translation")
+	var sideBySide bool
+	for line := range splitLines(out) {
+		if strings.Contains(line, "Hello there") && strings.Contains(line, "Bonjour") {
+			sideBySide = true
+		}
+	}
+	require.True(t, sideBySide, "original and translation should share a line")
+
+	requireBump(t, "clear translation", item, func() {
+		item.SetTranslation("", false)
+	})
+	require.False(t, item.HasTranslation())
+	require.NotContains(t, ansi.Strip(item.Render(80)), "Bonjour")
+}
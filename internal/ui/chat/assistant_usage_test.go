@@ -0,0 +1,44 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssistantMessageItemUsageAnnotation(t *testing.T) {
+	t.Parallel()
+
+	sty := styles.CharmtonePantera()
+	msg := &message.Message{
+		ID:   "usage",
+		Role: message.Assistant,
+		Parts: []message.ContentPart{
+			message.TextContent{Text: "done"},
+			message.Finish{Reason: message.FinishReasonEndTurn, Time: time.Now().Unix()},
+		},
+	}
+	msg.SetUsage(1200, 340, 0.0125)
+	item := NewAssistantMessageItem(&sty, msg).(*AssistantMessageItem)
+
+	require.NotContains(t, ansi.Strip(item.Render(80)), "↑1.2K")
+
+	requireBump(t, "SetShowUsage", item, func() {
+		item.SetShowUsage(true)
+	})
+	require.Contains(t, ansi.Strip(item.Render(80)), "↑1.2K ↓340 · $0.0125")
+
+	// Reasoning tokens are counted apart from the reply.
+	msg.SetReasoningTokens(120)
+	item.SetMessage(msg)
+	require.Contains(t, ansi.Strip(item.Render(80)), "↑1.2K ↓340 (120 reasoning) · $0.0125")
+
+	// Messages without recorded usage stay unannotated.
+	msg.SetUsage(0, 0, 0)
+	item.SetMessage(msg)
+	require.NotContains(t, ansi.Strip(item.Render(80)), "↑")
+}
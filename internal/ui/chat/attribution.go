@@ -0,0 +1,95 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/message"
+)
+
+// contextSourceLabels name the context source kinds in the attribution
+// footer, in display order. Kinds that occur at most once per turn are not
+// counted.
+var contextSourceLabels = []struct {
+	kind             message.ContextSourceKind
+	singular, plural string
+	counted          bool
+}{
+	{message.ContextSourceRepoMap, "repo map", "", false},
+	{message.ContextSourcePinnedNotes, "pinned notes", "", false},
+	{message.ContextSourceObservations, "observations", "", false},
+	{message.ContextSourceSummary, "LCM summary", "LCM summaries", true},
+	{message.ContextSourceContextFile, "context file", "context files", true},
+	{message.ContextSourceFile, "file", "files", true},
+}
+
+// renderAttribution renders the context sources of a finished message and
+// warns about cited files whose content was not in context. It returns ""
+// when the message has no attribution.
+func (a *AssistantMessageItem) renderAttribution(width int) string {
+	attribution := a.message.ContextAttribution()
+	if attribution == nil || !a.message.IsFinished() {
+		return ""
+	}
+
+	counts := make(map[message.ContextSourceKind]int)
+	for _, src := range attribution.Sources {
+		counts[src.Kind]++
+	}
+	var sources []string
+	for _, label := range contextSourceLabels {
+		switch n := counts[label.kind]; {
+		case n == 0:
+		case !label.counted:
+			sources = append(sources, label.singular)
+		case n == 1:
+			sources = append(sources, "1 "+label.singular)
+		case n > 1:
+			sources = append(sources, fmt.Sprintf("%d %s", n, label.plural))
+		}
+	}
+	if len(sources) == 0 {
+		sources = append(sources, "conversation only")
+	}
+	lines := []string{a.sty.Messages.AssistantTimestamp.Width(width).Render("Context: " + strings.Join(sources, " · "))}
+
+	if unverified := attribution.Unverified(); len(unverified) > 0 {
+		cited := make([]string, len(unverified))
+		for i, c := range unverified {
+			cited[i] = c.Path + " (" + citationNote(c) + ")"
+		}
+		tag := a.sty.Tool.WarnTag.Render("UNVERIFIED")
+		msg := a.sty.Tool.WarnMessage.Width(max(width-lipgloss.Width(tag)-1, 1)).Render(strings.Join(cited, ", "))
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, tag, " ", msg))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// citationNote explains why a citation is unverified.
+func citationNote(c message.FileCitation) string {
+	note := "not in context"
+	if c.Status == message.CitationMentioned {
+		note = "name only"
+	}
+	if c.Missing {
+		note += ", not on disk"
+	}
+	return note
+}
+
+// attributionKey fingerprints the attribution for the render caches.
+func (a *AssistantMessageItem) attributionKey() string {
+	attribution := a.message.ContextAttribution()
+	if attribution == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, src := range attribution.Sources {
+		fmt.Fprintf(&b, "%s:%s|", src.Kind, src.Name)
+	}
+	for _, c := range attribution.Citations {
+		fmt.Fprintf(&b, "|%s:%s:%t", c.Path, c.Status, c.Missing)
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderCandidates notes that the reply was picked among sampled
+// candidates. It returns "" when the message has none.
+func (a *AssistantMessageItem) renderCandidates(width int) string {
+	candidates := a.message.ResponseCandidates()
+	if candidates == nil || !a.message.IsFinished() {
+		return ""
+	}
+	parts := []string{fmt.Sprintf("Candidates: %d sampled", len(candidates.Candidates))}
+	if candidates.Judge != "" {
+		parts = append(parts, fmt.Sprintf("#%d chosen by %s", candidates.Chosen+1, candidates.Judge))
+	} else {
+		parts = append(parts, fmt.Sprintf("#%d kept", candidates.Chosen+1))
+	}
+	if cost := candidates.Cost(); cost > 0 {
+		parts = append(parts, fmt.Sprintf("+$%.4f", cost))
+	}
+	line := strings.Join(parts, " · ")
+	if candidates.Reason != "" {
+		line += "\n" + candidates.Reason
+	}
+	return a.sty.Messages.AssistantTimestamp.Width(width).Render(line)
+}
+
+// candidatesKey fingerprints the sampled candidates for the render caches.
+func (a *AssistantMessageItem) candidatesKey() string {
+	candidates := a.message.ResponseCandidates()
+	if candidates == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d:%s:%s", len(candidates.Candidates), candidates.Chosen, candidates.Judge, candidates.Reason)
+}
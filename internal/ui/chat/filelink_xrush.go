@@ -0,0 +1,22 @@
+package chat
+
+import (
+	"sync/atomic"
+
+	"github.com/charmbracelet/crush/internal/ui/filelink"
+)
+
+// fileLinker turns file:line references in tool output into editor links;
+// see SetFileLinker.
+var fileLinker atomic.Pointer[filelink.Linker]
+
+// SetFileLinker sets the linker applied to plain tool output, such as
+// explorer summaries, diagnostics and stack traces. nil turns links off.
+func SetFileLinker(l *filelink.Linker) {
+	fileLinker.Store(l)
+}
+
+// FileLinker returns the linker set with SetFileLinker, or nil.
+func FileLinker() *filelink.Linker {
+	return fileLinker.Load()
+}
@@ -0,0 +1,135 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// codeFoldLines is the number of lines a fenced code block may span in
+// the chat before it is folded.
+const codeFoldLines = 30
+
+// fenceOpen describes an open fenced code block.
+type fenceOpen struct {
+	indent string
+	marker string // the run of ` or ~ characters
+	info   string
+	line   int
+}
+
+// prepareMarkdown rewrites closed fenced code blocks for display. Blocks
+// without an info string get a language detected from their content so
+// glamour can highlight them, and blocks longer than foldAt lines are cut
+// to foldAt lines followed by a note saying how many were hidden. foldAt
+// <= 0 disables folding. Unclosed blocks (still streaming) are left alone
+// so the stable-prefix render cache is not disturbed. It returns the
+// rewritten source and the number of folded blocks.
+func prepareMarkdown(src string, foldAt int) (string, int) {
+	if !strings.Contains(src, "```") && !strings.Contains(src, "~~~") {
+		return src, 0
+	}
+
+	lines := strings.Split(src, "\n")
+	out := make([]string, 0, len(lines))
+	folded := 0
+	var open *fenceOpen
+	var body []string
+
+	for i, line := range lines {
+		if open == nil {
+			if f, ok := parseFenceOpen(line); ok {
+				f.line = i
+				open = &f
+				body = body[:0]
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+
+		if !isFenceClose(line, open.marker) {
+			body = append(body, line)
+			continue
+		}
+
+		info := open.info
+		if info == "" {
+			info = detectLanguage(strings.Join(body, "\n"))
+		}
+		out = append(out, open.indent+open.marker+info)
+		if foldAt > 0 && len(body) > foldAt {
+			out = append(out, body[:foldAt]...)
+			out = append(out, line)
+			out = append(out, "", fmt.Sprintf("%s*… %s folded*", open.indent, pluralLines(len(body)-foldAt)))
+			folded++
+		} else {
+			out = append(out, body...)
+			out = append(out, line)
+		}
+		open = nil
+	}
+
+	if open != nil {
+		// Unclosed: emit the block verbatim.
+		out = append(out, lines[open.line])
+		out = append(out, body...)
+	}
+	return strings.Join(out, "\n"), folded
+}
+
+// parseFenceOpen reports whether line opens a fenced code block.
+func parseFenceOpen(line string) (fenceOpen, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(trimmed)]
+	if len(indent) > 3 || len(trimmed) < 3 {
+		return fenceOpen{}, false
+	}
+	ch := trimmed[0]
+	if ch != '`' && ch != '~' {
+		return fenceOpen{}, false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == ch {
+		n++
+	}
+	if n < 3 {
+		return fenceOpen{}, false
+	}
+	info := strings.TrimSpace(trimmed[n:])
+	if ch == '`' && strings.Contains(info, "`") {
+		return fenceOpen{}, false
+	}
+	return fenceOpen{indent: indent, marker: trimmed[:n], info: info}, true
+}
+
+// isFenceClose reports whether line closes a block opened with marker.
+func isFenceClose(line, marker string) bool {
+	trimmed := strings.TrimSpace(line)
+	if len(trimmed) < len(marker) || len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return false
+	}
+	return strings.Trim(trimmed, marker[:1]) == ""
+}
+
+// detectLanguage guesses the language of an unlabeled code block, returning
+// an empty string when chroma has no confident match.
+func detectLanguage(code string) string {
+	l := lexers.Analyse(code)
+	if l == nil {
+		return ""
+	}
+	cfg := l.Config()
+	if len(cfg.Aliases) > 0 {
+		return cfg.Aliases[0]
+	}
+	return strings.ToLower(cfg.Name)
+}
+
+func pluralLines(n int) string {
+	if n == 1 {
+		return "1 more line"
+	}
+	return fmt.Sprintf("%d more lines", n)
+}
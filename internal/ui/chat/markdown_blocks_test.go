@@ -0,0 +1,87 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareMarkdownFoldsLongBlocks(t *testing.T) {
+	t.Parallel()
+
+	var code []string
+	for i := range 10 {
+		code = append(code, fmt.Sprintf("line %d", i))
+	}
+	src := "Intro\n\n```text\n" + strings.Join(code, "\n") + "\n```\n\nOutro"
+
+	got, folded := prepareMarkdown(src, 4)
+	require.Equal(t, 1, folded)
+	require.Equal(t, "Intro\n\n```text\nline 0\nline 1\nline 2\nline 3\n```\n\n*… 6 more lines folded*\n\nOutro", got)
+
+	got, folded = prepareMarkdown(src, 0)
+	require.Zero(t, folded)
+	require.Equal(t, src, got)
+}
+
+func TestPrepareMarkdownDetectsLanguage(t *testing.T) {
+	t.Parallel()
+
+	src := "```\n#!/bin/bash\necho hi\n```"
+	got, _ := prepareMarkdown(src, 0)
+	require.True(t, strings.HasPrefix(got, "```bash\n"), got)
+
+	// Labeled blocks are untouched.
+	src = "~~~python\nprint(1)\n~~~"
+	got, _ = prepareMarkdown(src, 0)
+	require.Equal(t, src, got)
+}
+
+func TestPrepareMarkdownLeavesUnclosedBlocks(t *testing.T) {
+	t.Parallel()
+
+	src := "Text\n```\nline 1\nline 2\nline 3"
+	got, folded := prepareMarkdown(src, 1)
+	require.Zero(t, folded)
+	require.Equal(t, src, got)
+}
+
+func TestPrepareMarkdownNestedFences(t *testing.T) {
+	t.Parallel()
+
+	src := "````markdown\n```go\nx := 1\n```\n````"
+	got, folded := prepareMarkdown(src, 2)
+	require.Equal(t, 1, folded)
+	require.Equal(t, "````markdown\n```go\nx := 1\n````\n\n*… 1 more line folded*", got)
+}
+
+func TestAssistantMessageItemFoldAndRawToggles(t *testing.T) {
+	t.Parallel()
+
+	var code []string
+	for i := range codeFoldLines + 5 {
+		code = append(code, fmt.Sprintf("value_%d = %d", i, i))
+	}
+	text := "# Title\n\n```text\n" + strings.Join(code, "\n") + "\n```"
+	sty := styles.CharmtonePantera()
+	item := NewAssistantMessageItem(&sty, thinkingMessage("fold", "", text)).(*AssistantMessageItem)
+
+	folded := ansi.Strip(item.RawRender(80))
+	require.Contains(t, folded, "5 more lines folded")
+	require.NotContains(t, folded, fmt.Sprintf("value_%d", codeFoldLines+4))
+
+	handled, _ := item.HandleKeyEvent(tea.KeyPressMsg{Code: 'z', Text: "z"})
+	require.True(t, handled)
+	unfolded := ansi.Strip(item.RawRender(80))
+	require.NotContains(t, unfolded, "more lines folded")
+	require.Contains(t, unfolded, fmt.Sprintf("value_%d", codeFoldLines+4))
+
+	handled, _ = item.HandleKeyEvent(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	require.True(t, handled)
+	require.Contains(t, ansi.Strip(item.RawRender(80)), "# Title\n\n```text")
+}
@@ -48,6 +48,12 @@ type KeyEventHandler interface {
 	HandleKeyEvent(key tea.KeyMsg) (bool, tea.Cmd)
 }
 
+// PaneContentProvider is implemented by items whose full content can be
+// opened in an external terminal pane.
+type PaneContentProvider interface {
+	PaneContent() string
+}
+
 // MessageItem represents a [message.Message] item that can be displayed in the
 // UI and be part of a [list.List] identifiable by a unique ID.
 type MessageItem interface {
@@ -370,6 +376,10 @@ func (a *AssistantInfoItem) renderContent(width int) string {
 
 	infoMsg := a.sty.Messages.AssistantInfoDuration.Render(durationStr)
 	assistant := fmt.Sprintf("%s %s %s %s", icon, modelFormatted, provider, infoMsg)
+	if a.message.RoutingReason != "" {
+		// XRUSH: the turn was automatically routed to the small model.
+		assistant += " " + a.sty.Messages.AssistantInfoDuration.Render("· auto-downgraded: "+a.message.RoutingReason)
+	}
 	return common.Section(a.sty, assistant, width)
 }
 
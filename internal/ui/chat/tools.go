@@ -500,6 +500,11 @@ func (t *baseToolMessageItem) HandleKeyEvent(key tea.KeyMsg) (bool, tea.Cmd) {
 	return false, nil
 }
 
+// PaneContent implements PaneContentProvider.
+func (t *baseToolMessageItem) PaneContent() string {
+	return t.formatToolForCopy()
+}
+
 // pendingTool renders a tool that is still in progress with an animation.
 func pendingTool(sty *styles.Styles, name string, anim *anim.Anim, nested bool) string {
 	icon := sty.Tool.IconPending.Render()
@@ -638,6 +643,7 @@ func toolOutputPlainContent(sty *styles.Styles, content string, width int, expan
 		if lipgloss.Width(ln) > width {
 			ln = ansi.Truncate(ln, width, "…")
 		}
+		ln = fileLinker.Load().Linkify(ln) // XRUSH: editor links
 		out = append(out, sty.Tool.ContentLine.Width(width).Render(ln))
 	}
 
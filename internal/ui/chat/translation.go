@@ -0,0 +1,68 @@
+package chat
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/ui/common"
+)
+
+const (
+	// translationGutter is the gap between the original and the
+	// translation in side-by-side mode.
+	translationGutter = 3
+	// minSideBySideWidth is the narrowest width at which side-by-side
+	// translations are shown; below it they fall back to inline.
+	minSideBySideWidth = 60
+)
+
+// SetTranslation shows text as the translation of the message content.
+// An empty text removes the translation.
+func (a *AssistantMessageItem) SetTranslation(text string, sideBySide bool) {
+	if a.translation == text && a.sideBySide == sideBySide {
+		return
+	}
+	a.translation = text
+	a.sideBySide = sideBySide
+	a.Bump()
+}
+
+// HasTranslation reports whether a translation is shown.
+func (a *AssistantMessageItem) HasTranslation() bool {
+	return a.translation != ""
+}
+
+// Text returns the raw markdown content of the message.
+func (a *AssistantMessageItem) Text() string {
+	return a.message.Content().Text
+}
+
+// renderContentSection renders the main content together with its
+// translation, if any.
+func (a *AssistantMessageItem) renderContentSection(width int) string {
+	if a.translation == "" {
+		return a.cachedContent(width)
+	}
+	if a.sideBySide && width >= minSideBySideWidth {
+		col := (width - translationGutter) / 2
+		left := lipgloss.NewStyle().Width(col).Render(a.cachedContent(col))
+		return lipgloss.JoinHorizontal(lipgloss.Top, left, strings.Repeat(" ", translationGutter), a.cachedTranslation(col))
+	}
+	label := a.sty.Messages.AssistantTimestamp.Render("Translation")
+	return a.cachedContent(width) + "\n\n" + label + "\n" + a.cachedTranslation(width)
+}
+
+// cachedTranslation returns the rendered translation section.
+func (a *AssistantMessageItem) cachedTranslation(width int) string {
+	srcHash := fnv64(a.translation)
+	if a.translationSec.hit(width, srcHash, 0) {
+		return a.translationSec.out
+	}
+	out, err := common.MarkdownRenderer(a.sty, width).Render(a.translation)
+	if err != nil {
+		out = a.translation
+	}
+	out = strings.TrimSuffix(out, "\n")
+	a.translationSec.store(width, srcHash, 0, out, 0)
+	return out
+}
@@ -37,6 +37,16 @@ func Button(t *styles.Styles, opts ButtonOpts) string {
 		opts.UnderlineIndex = -1
 	}
 
+	// In accessibility mode the selection is also shown in text, taking
+	// one cell of padding on each side so the layout doesn't shift.
+	if t.Accessible && opts.Selected {
+		text = "[" + text + "]"
+		opts.Padding--
+		if opts.UnderlineIndex != -1 {
+			opts.UnderlineIndex++
+		}
+	}
+
 	text = style.Padding(0, opts.Padding).Render(text)
 
 	if opts.UnderlineIndex != -1 {
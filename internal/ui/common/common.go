@@ -33,9 +33,13 @@ func (c *Common) Config() *config.Config {
 
 // DefaultCommon returns the default common UI configurations. When the
 // workspace has a large model selected, the theme is chosen based on its
-// provider; otherwise the default theme is used.
+// provider; otherwise the default theme is used. Accessibility mode always
+// uses the high-contrast theme.
 func DefaultCommon(ws workspace.Workspace) *Common {
 	s := styles.ThemeForProvider(largeModelProviderID(ws))
+	if accessibilityEnabled(ws) {
+		s = styles.HighContrast()
+	}
 	return &Common{
 		Workspace: ws,
 		Styles:    &s,
@@ -55,6 +59,24 @@ func largeModelProviderID(ws workspace.Workspace) string {
 	return cfg.Models[config.SelectedModelTypeLarge].Provider
 }
 
+// accessibilityEnabled reports whether accessibility mode is turned on,
+// either in the TUI options or through the ACCESSIBLE environment variable.
+func accessibilityEnabled(ws workspace.Workspace) bool {
+	if os.Getenv("ACCESSIBLE") != "" {
+		return true
+	}
+	if ws == nil {
+		return false
+	}
+	cfg := ws.Config()
+	return cfg != nil && cfg.Options != nil && cfg.Options.TUI != nil && cfg.Options.TUI.Accessibility
+}
+
+// Accessible reports whether the UI runs in accessibility mode.
+func (c *Common) Accessible() bool {
+	return c.Styles != nil && c.Styles.Accessible
+}
+
 // IsHyper reports whether the currently selected large model is provided
 // by Hyper.
 func (c *Common) IsHyper() bool {
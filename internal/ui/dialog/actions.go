@@ -112,6 +112,12 @@ type (
 	// ActionToggleOperationalMemory toggles the LCM operational memory
 	// feature on/off.
 	ActionToggleOperationalMemory struct{}
+	// ActionToggleAutoDowngrade toggles routing trivial turns of the
+	// session to the small model.
+	ActionToggleAutoDowngrade struct {
+		SessionID string
+		Enable    bool
+	}
 )
 
 // Messages for API key input dialog.
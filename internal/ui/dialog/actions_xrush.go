@@ -1,6 +1,11 @@
 package dialog
 
-import "github.com/charmbracelet/crush/internal/rewind"
+import (
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/repomap"
+	"github.com/charmbracelet/crush/internal/rewind"
+	"github.com/charmbracelet/crush/internal/staging"
+)
 
 type (
 	// ActionRewind is a message to rewind a session to a specific sequence.
@@ -26,4 +31,49 @@ type (
 		Seq       int
 		MessageID string
 	}
+	// ActionReviewStagedEdits is a message to open the staged edit review
+	// dialog for a session.
+	ActionReviewStagedEdits struct {
+		SessionID string
+	}
+	// ActionApplyStagedEdits is a message to write the accepted hunks of
+	// the reviewed changes.
+	ActionApplyStagedEdits struct {
+		SessionID string
+		Changes   []staging.Change
+	}
+	// ActionRepoMapOverride is a message to pin or blacklist a repo map
+	// entry for a session. An empty Target prompts for one.
+	ActionRepoMapOverride struct {
+		SessionID string
+		Kind      repomap.OverrideKind
+		Target    string
+	}
+	// ActionClearRepoMapOverrides is a message to drop all repo map pins
+	// and blacklist entries of a session.
+	ActionClearRepoMapOverrides struct {
+		SessionID string
+	}
+	// ActionTakeOverSession is a message to take a session over from
+	// another crush process and resend the prompt it refused.
+	ActionTakeOverSession struct {
+		SessionID   string
+		Prompt      string
+		Attachments []message.Attachment
+	}
+	// ActionEstimateTurn is a message to estimate the tokens and cost of
+	// sending the editor content, without sending it.
+	ActionEstimateTurn struct{}
+	// ActionSendEstimatedTurn is a message to send a turn held back by the
+	// cost confirmation threshold.
+	ActionSendEstimatedTurn struct {
+		Prompt      string
+		Attachments []message.Attachment
+	}
+	// ActionCancelEstimatedTurn is a message to return a turn held back by
+	// the cost confirmation threshold to the editor.
+	ActionCancelEstimatedTurn struct {
+		Prompt      string
+		Attachments []message.Attachment
+	}
 )
@@ -218,6 +218,9 @@ func (a *Arguments) HandleMsg(msg tea.Msg) Action {
 				case ActionRunMCPPrompt:
 					action.Args = args
 					return action
+				case ActionRepoMapOverride: // XRUSH: repo map overrides
+					action.Target = args[RepoMapTargetArgument]
+					return action
 				}
 			}
 			a.focusInput(a.focused + 1)
@@ -11,6 +11,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/charmbracelet/crush/internal/commands"
 	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/repomap"
 	"github.com/charmbracelet/crush/internal/ui/common"
 	"github.com/charmbracelet/crush/internal/ui/list"
 	"github.com/charmbracelet/crush/internal/ui/styles"
@@ -440,7 +441,19 @@ func (c *Commands) defaultCommands() []*CommandItem {
 	if c.hasSession {
 		commands = append(commands, NewCommandItem(c.com.Styles, "summarize", "Summarize Session", "", ActionSummarize{SessionID: c.sessionID}))
 		commands = append(commands, NewCommandItem(c.com.Styles, "refresh_repomap", "Refresh Repository Map", "", ActionRefreshRepoMap{SessionID: c.sessionID}))
+		commands = append(commands, NewCommandItem(c.com.Styles, "pin_repomap_entry", "Pin Repository Map Entry", "", ActionRepoMapOverride{SessionID: c.sessionID, Kind: repomap.OverridePin}))
+		commands = append(commands, NewCommandItem(c.com.Styles, "blacklist_repomap_entry", "Blacklist Repository Map Entry", "", ActionRepoMapOverride{SessionID: c.sessionID, Kind: repomap.OverrideBlacklist}))
+		commands = append(commands, NewCommandItem(c.com.Styles, "clear_repomap_overrides", "Clear Repository Map Pins and Blacklist", "", ActionClearRepoMapOverrides{SessionID: c.sessionID}))
+		if c.com.Config().Options.ReviewEdits {
+			commands = append(commands, NewCommandItem(c.com.Styles, "review_edits", "Review Staged Edits", "", ActionReviewStagedEdits{SessionID: c.sessionID}))
+		}
+		if c.com.Workspace.AgentAutoDowngradeEnabled(c.sessionID) {
+			commands = append(commands, NewCommandItem(c.com.Styles, "toggle_auto_downgrade", "Disable Auto Model Downgrade", "", ActionToggleAutoDowngrade{SessionID: c.sessionID}))
+		} else {
+			commands = append(commands, NewCommandItem(c.com.Styles, "toggle_auto_downgrade", "Enable Auto Model Downgrade", "", ActionToggleAutoDowngrade{SessionID: c.sessionID, Enable: true}))
+		}
 	}
+	commands = append(commands, NewCommandItem(c.com.Styles, "estimate_turn", "Estimate Turn Cost", "", ActionEstimateTurn{}))
 
 	// Add reasoning toggle for models that support it
 	cfg := c.com.Config()
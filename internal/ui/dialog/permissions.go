@@ -16,6 +16,7 @@ import (
 	"github.com/charmbracelet/crush/internal/stringext"
 	"github.com/charmbracelet/crush/internal/ui/common"
 	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/crush/internal/ui/util"
 	uv "github.com/charmbracelet/ultraviolet"
 )
 
@@ -240,9 +241,11 @@ func (p *Permissions) HandleMsg(msg tea.Msg) Action {
 			return p.respond(PermissionDeny)
 		case key.Matches(msg, p.keyMap.Right), key.Matches(msg, p.keyMap.Tab):
 			p.selectedOption = (p.selectedOption + 1) % 3
+			return p.announceSelection()
 		case key.Matches(msg, p.keyMap.Left):
 			// Add 2 instead of subtracting 1 to avoid negative modulo.
 			p.selectedOption = (p.selectedOption + 2) % 3
+			return p.announceSelection()
 		case key.Matches(msg, p.keyMap.Select):
 			return p.selectCurrentOption()
 		case key.Matches(msg, p.keyMap.Allow):
@@ -302,6 +305,18 @@ func (p *Permissions) HandleMsg(msg tea.Msg) Action {
 	return nil
 }
 
+// permissionOptionLabels are the button labels, indexed by selectedOption.
+var permissionOptionLabels = [...]string{"Allow", "Allow for Session", "Deny"}
+
+// announceSelection reports the newly selected option in the status bar
+// in accessibility mode, so screen readers speak the change.
+func (p *Permissions) announceSelection() Action {
+	if !p.com.Accessible() {
+		return nil
+	}
+	return ActionCmd{util.ReportInfo("Selected: " + permissionOptionLabels[p.selectedOption])}
+}
+
 func (p *Permissions) selectCurrentOption() tea.Msg {
 	switch p.selectedOption {
 	case 0:
@@ -737,9 +752,9 @@ func (p *Permissions) renderContentPanel(content string, width int) string {
 
 func (p *Permissions) renderButtons(contentWidth int) string {
 	buttons := []common.ButtonOpts{
-		{Text: "Allow", UnderlineIndex: 0, Selected: p.selectedOption == 0},
-		{Text: "Allow for Session", UnderlineIndex: 10, Selected: p.selectedOption == 1},
-		{Text: "Deny", UnderlineIndex: 0, Selected: p.selectedOption == 2},
+		{Text: permissionOptionLabels[0], UnderlineIndex: 0, Selected: p.selectedOption == 0},
+		{Text: permissionOptionLabels[1], UnderlineIndex: 10, Selected: p.selectedOption == 1},
+		{Text: permissionOptionLabels[2], UnderlineIndex: 0, Selected: p.selectedOption == 2},
 	}
 
 	content := common.ButtonGroup(p.com.Styles, buttons, "  ")
@@ -0,0 +1,28 @@
+package dialog
+
+import (
+	"github.com/charmbracelet/crush/internal/commands"
+	"github.com/charmbracelet/crush/internal/repomap"
+	"github.com/charmbracelet/crush/internal/ui/common"
+)
+
+// RepoMapTargetArgument is the argument ID holding the repo map override
+// target.
+const RepoMapTargetArgument = "target"
+
+// NewRepoMapOverrideArguments returns an arguments dialog prompting for the
+// path, glob or symbol to pin or blacklist.
+func NewRepoMapOverrideArguments(com *common.Common, action ActionRepoMapOverride) *Arguments {
+	title := "Pin Repository Map Entry"
+	description := "Pinned entries are kept at the top of the map for this session."
+	if action.Kind == repomap.OverrideBlacklist {
+		title = "Blacklist Repository Map Entry"
+		description = "Blacklisted entries are left out of the map for this session."
+	}
+	return NewArguments(com, title, description, []commands.Argument{{
+		ID:          RepoMapTargetArgument,
+		Title:       "Target",
+		Description: "File, directory/, glob (gen/**/*.pb.go), path#Symbol or #Symbol",
+		Required:    true,
+	}}, action)
+}
@@ -0,0 +1,268 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/staging"
+	"github.com/charmbracelet/crush/internal/ui/common"
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// ReviewEditsID is the identifier for the staged edit review dialog.
+	ReviewEditsID = "review-edits"
+
+	reviewEditsDialogMaxWidth = 100
+	reviewEditsDialogHeight   = 30
+)
+
+// ReviewEdits is a dialog that walks through the staged edits of a session
+// hunk by hunk so the user can accept or reject each one before anything
+// is written to disk.
+type ReviewEdits struct {
+	com       *common.Common
+	help      help.Model
+	sessionID string
+	changes   []staging.Change
+	file      int
+	hunk      int
+
+	keyMap struct {
+		Next,
+		Previous,
+		UpDown,
+		Accept,
+		Reject,
+		AcceptAll,
+		RejectAll,
+		Apply,
+		Close key.Binding
+	}
+}
+
+var _ Dialog = (*ReviewEdits)(nil)
+
+// NewReviewEdits creates a review dialog for the given staged changes.
+func NewReviewEdits(com *common.Common, sessionID string, changes []staging.Change) *ReviewEdits {
+	r := &ReviewEdits{
+		com:       com,
+		sessionID: sessionID,
+		changes:   changes,
+	}
+
+	help := help.New()
+	help.Styles = com.Styles.DialogHelpStyles()
+	r.help = help
+
+	r.keyMap.Next = key.NewBinding(
+		key.WithKeys("down", "j", "tab"),
+		key.WithHelp("↓", "next hunk"),
+	)
+	r.keyMap.Previous = key.NewBinding(
+		key.WithKeys("up", "k", "shift+tab"),
+		key.WithHelp("↑", "previous hunk"),
+	)
+	r.keyMap.UpDown = key.NewBinding(
+		key.WithKeys("up", "down"),
+		key.WithHelp("↑/↓", "hunks"),
+	)
+	r.keyMap.Accept = key.NewBinding(
+		key.WithKeys("a", "y"),
+		key.WithHelp("a", "accept"),
+	)
+	r.keyMap.Reject = key.NewBinding(
+		key.WithKeys("r", "n"),
+		key.WithHelp("r", "reject"),
+	)
+	r.keyMap.AcceptAll = key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "accept all"),
+	)
+	r.keyMap.RejectAll = key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "reject all"),
+	)
+	r.keyMap.Apply = key.NewBinding(
+		key.WithKeys("enter", "ctrl+y"),
+		key.WithHelp("enter", "write accepted"),
+	)
+	r.keyMap.Close = CloseKey
+	return r
+}
+
+// ID implements [Dialog].
+func (*ReviewEdits) ID() string {
+	return ReviewEditsID
+}
+
+// HandleMsg implements [Dialog].
+func (r *ReviewEdits) HandleMsg(msg tea.Msg) Action {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return nil
+	}
+	switch {
+	case key.Matches(keyMsg, r.keyMap.Close):
+		return ActionClose{}
+	case key.Matches(keyMsg, r.keyMap.Apply):
+		return ActionApplyStagedEdits{SessionID: r.sessionID, Changes: r.changes}
+	case key.Matches(keyMsg, r.keyMap.Next):
+		r.move(1)
+	case key.Matches(keyMsg, r.keyMap.Previous):
+		r.move(-1)
+	case key.Matches(keyMsg, r.keyMap.Accept):
+		r.decide(staging.DecisionAccepted)
+		r.move(1)
+	case key.Matches(keyMsg, r.keyMap.Reject):
+		r.decide(staging.DecisionRejected)
+		r.move(1)
+	case key.Matches(keyMsg, r.keyMap.AcceptAll):
+		r.decideAll(staging.DecisionAccepted)
+	case key.Matches(keyMsg, r.keyMap.RejectAll):
+		r.decideAll(staging.DecisionRejected)
+	}
+	return nil
+}
+
+// move selects the next or previous hunk, crossing file boundaries.
+func (r *ReviewEdits) move(delta int) {
+	if len(r.changes) == 0 {
+		return
+	}
+	next := r.hunk + delta
+	switch {
+	case next >= len(r.changes[r.file].Hunks):
+		if r.file < len(r.changes)-1 {
+			r.file++
+			r.hunk = 0
+		}
+	case next < 0:
+		if r.file > 0 {
+			r.file--
+			r.hunk = max(0, len(r.changes[r.file].Hunks)-1)
+		}
+	default:
+		r.hunk = next
+	}
+}
+
+func (r *ReviewEdits) decide(d staging.Decision) {
+	if r.file < len(r.changes) && r.hunk < len(r.changes[r.file].Hunks) {
+		r.changes[r.file].Hunks[r.hunk].Decision = d
+	}
+}
+
+func (r *ReviewEdits) decideAll(d staging.Decision) {
+	for i := range r.changes {
+		for j := range r.changes[i].Hunks {
+			r.changes[i].Hunks[j].Decision = d
+		}
+	}
+}
+
+// Draw implements [Dialog].
+func (r *ReviewEdits) Draw(scr uv.Screen, area uv.Rectangle) *tea.Cursor {
+	t := r.com.Styles
+	width := max(0, min(reviewEditsDialogMaxWidth, area.Dx()))
+	height := max(0, min(reviewEditsDialogHeight, area.Dy()))
+	innerWidth := width - t.Dialog.View.GetHorizontalFrameSize()
+	r.help.SetWidth(innerWidth)
+
+	rc := NewRenderContext(t, width)
+	rc.Title = "Review Staged Edits"
+	rc.TitleInfo = t.Dialog.SecondaryText.Render(r.progress())
+
+	if len(r.changes) == 0 {
+		rc.AddPart(t.Dialog.SecondaryText.Render("No staged edits."))
+	} else {
+		change := r.changes[r.file]
+		h := change.Hunks[r.hunk]
+		header := fmt.Sprintf("%s  hunk %d/%d  %s",
+			fsext.PrettyPath(change.Path), r.hunk+1, len(change.Hunks), r.decisionLabel(h.Decision))
+		rc.AddPart(ansi.Truncate(header, innerWidth, "…"))
+
+		bodyHeight := max(1, height-t.Dialog.View.GetVerticalFrameSize()-
+			t.Dialog.Title.GetVerticalFrameSize()-titleContentHeight-
+			t.Dialog.HelpView.GetVerticalFrameSize()-4)
+		rc.AddPart(r.renderHunk(h.Diff, innerWidth, bodyHeight))
+	}
+	rc.Help = r.help.View(r)
+
+	DrawCenter(scr, area, rc.Render())
+	return nil
+}
+
+// progress renders how many hunks have been decided.
+func (r *ReviewEdits) progress() string {
+	total, decided := 0, 0
+	for _, c := range r.changes {
+		for _, h := range c.Hunks {
+			total++
+			if h.Decision != staging.DecisionPending {
+				decided++
+			}
+		}
+	}
+	return fmt.Sprintf("%d/%d reviewed", decided, total)
+}
+
+func (r *ReviewEdits) decisionLabel(d staging.Decision) string {
+	t := r.com.Styles
+	switch d {
+	case staging.DecisionAccepted:
+		return t.Diff.InsertLine.Code.Render(" accepted ")
+	case staging.DecisionRejected:
+		return t.Diff.DeleteLine.Code.Render(" rejected ")
+	default:
+		return t.Dialog.SecondaryText.Render("pending")
+	}
+}
+
+// renderHunk colors the unified diff lines of a hunk and fits them into
+// the given box.
+func (r *ReviewEdits) renderHunk(diff string, width, height int) string {
+	t := r.com.Styles
+	lines := strings.Split(diff, "\n")
+	if len(lines) > height {
+		lines = append(lines[:height-1], fmt.Sprintf("… %d more lines", len(lines)-height+1))
+	}
+	for i, line := range lines {
+		line = ansi.Truncate(strings.ReplaceAll(line, "\t", "    "), width, "…")
+		style := t.Diff.EqualLine.Code
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			style = t.Diff.DividerLine.Code
+		case strings.HasPrefix(line, "+"):
+			style = t.Diff.InsertLine.Code
+		case strings.HasPrefix(line, "-"):
+			style = t.Diff.DeleteLine.Code
+		}
+		lines[i] = style.Width(width).Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ShortHelp implements [help.KeyMap].
+func (r *ReviewEdits) ShortHelp() []key.Binding {
+	return []key.Binding{
+		r.keyMap.UpDown,
+		r.keyMap.Accept,
+		r.keyMap.Reject,
+		r.keyMap.Apply,
+		r.keyMap.Close,
+	}
+}
+
+// FullHelp implements [help.KeyMap].
+func (r *ReviewEdits) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{r.keyMap.Next, r.keyMap.Previous, r.keyMap.Accept, r.keyMap.Reject},
+		{r.keyMap.AcceptAll, r.keyMap.RejectAll, r.keyMap.Apply, r.keyMap.Close},
+	}
+}
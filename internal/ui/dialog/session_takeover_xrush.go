@@ -0,0 +1,133 @@
+package dialog
+
+import (
+	"fmt"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/ui/common"
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// SessionTakeoverID is the identifier for the session takeover dialog.
+const SessionTakeoverID = "session_takeover"
+
+// SessionTakeover asks whether to take a session over from the other crush
+// process holding its lock, and resend the prompt that was refused.
+type SessionTakeover struct {
+	com        *common.Common
+	action     ActionTakeOverSession
+	owner      *session.LockedError
+	selectedNo bool
+	keyMap     struct {
+		LeftRight,
+		EnterSpace,
+		Yes,
+		No,
+		Tab,
+		Close key.Binding
+	}
+}
+
+var _ Dialog = (*SessionTakeover)(nil)
+
+// NewSessionTakeover creates a takeover dialog for the prompt in action.
+// owner describes the process holding the lock and may be nil.
+func NewSessionTakeover(com *common.Common, action ActionTakeOverSession, owner *session.LockedError) *SessionTakeover {
+	d := &SessionTakeover{
+		com:        com,
+		action:     action,
+		owner:      owner,
+		selectedNo: true,
+	}
+	d.keyMap.LeftRight = key.NewBinding(
+		key.WithKeys("left", "right"),
+		key.WithHelp("←/→", "switch options"),
+	)
+	d.keyMap.EnterSpace = key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter/space", "confirm"),
+	)
+	d.keyMap.Yes = key.NewBinding(
+		key.WithKeys("y", "Y"),
+		key.WithHelp("y/Y", "take over"),
+	)
+	d.keyMap.No = key.NewBinding(
+		key.WithKeys("n", "N"),
+		key.WithHelp("n/N", "no"),
+	)
+	d.keyMap.Tab = key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "switch options"),
+	)
+	d.keyMap.Close = CloseKey
+	return d
+}
+
+// ID implements [Model].
+func (*SessionTakeover) ID() string {
+	return SessionTakeoverID
+}
+
+// HandleMsg implements [Model].
+func (d *SessionTakeover) HandleMsg(msg tea.Msg) Action {
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keyMap.LeftRight, d.keyMap.Tab):
+			d.selectedNo = !d.selectedNo
+		case key.Matches(msg, d.keyMap.EnterSpace):
+			if !d.selectedNo {
+				return d.action
+			}
+			return ActionClose{}
+		case key.Matches(msg, d.keyMap.Yes):
+			return d.action
+		case key.Matches(msg, d.keyMap.No, d.keyMap.Close):
+			return ActionClose{}
+		}
+	}
+	return nil
+}
+
+// Draw implements [Dialog].
+func (d *SessionTakeover) Draw(scr uv.Screen, area uv.Rectangle) *tea.Cursor {
+	owner := "another crush process"
+	if d.owner != nil && d.owner.PID != 0 {
+		owner = fmt.Sprintf("crush (pid %d)", d.owner.PID)
+	}
+	baseStyle := d.com.Styles.Dialog.Quit.Content
+	buttons := common.ButtonGroup(d.com.Styles, []common.ButtonOpts{
+		{Text: "Take over", Selected: !d.selectedNo, Padding: 2},
+		{Text: "Cancel", Selected: d.selectedNo, Padding: 2},
+	}, " ")
+	content := baseStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Center,
+			"This session is in use by "+owner+".",
+			"Take it over? The other process stops its turn.",
+			"",
+			buttons,
+		),
+	)
+	DrawCenter(scr, area, d.com.Styles.Dialog.Quit.Frame.Render(content))
+	return nil
+}
+
+// ShortHelp implements [help.KeyMap].
+func (d *SessionTakeover) ShortHelp() []key.Binding {
+	return []key.Binding{
+		d.keyMap.LeftRight,
+		d.keyMap.EnterSpace,
+	}
+}
+
+// FullHelp implements [help.KeyMap].
+func (d *SessionTakeover) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{d.keyMap.LeftRight, d.keyMap.EnterSpace, d.keyMap.Yes, d.keyMap.No},
+		{d.keyMap.Tab, d.keyMap.Close},
+	}
+}
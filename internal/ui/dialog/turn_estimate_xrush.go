@@ -0,0 +1,204 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/agent"
+	"github.com/charmbracelet/crush/internal/ui/common"
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// TurnEstimateID is the identifier for the turn estimate dialog.
+const TurnEstimateID = "turn_estimate"
+
+// TurnEstimate shows the prompt tokens by component and the projected cost
+// of a turn. When the turn was held back by the confirmation threshold, it
+// asks whether to send it.
+type TurnEstimate struct {
+	com        *common.Common
+	est        agent.TurnEstimate
+	threshold  float64
+	send       *ActionSendEstimatedTurn
+	selectedNo bool
+	keyMap     struct {
+		LeftRight,
+		EnterSpace,
+		Yes,
+		No,
+		Tab,
+		Close key.Binding
+	}
+}
+
+var _ Dialog = (*TurnEstimate)(nil)
+
+// NewTurnEstimate creates a dialog showing est. When send is not nil, the
+// dialog asks whether to send the turn, which exceeds threshold.
+func NewTurnEstimate(com *common.Common, est agent.TurnEstimate, threshold float64, send *ActionSendEstimatedTurn) *TurnEstimate {
+	d := &TurnEstimate{
+		com:        com,
+		est:        est,
+		threshold:  threshold,
+		send:       send,
+		selectedNo: true,
+	}
+	d.keyMap.LeftRight = key.NewBinding(
+		key.WithKeys("left", "right"),
+		key.WithHelp("←/→", "switch options"),
+	)
+	d.keyMap.EnterSpace = key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter/space", "confirm"),
+	)
+	d.keyMap.Yes = key.NewBinding(
+		key.WithKeys("y", "Y"),
+		key.WithHelp("y/Y", "send"),
+	)
+	d.keyMap.No = key.NewBinding(
+		key.WithKeys("n", "N"),
+		key.WithHelp("n/N", "cancel"),
+	)
+	d.keyMap.Tab = key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "switch options"),
+	)
+	d.keyMap.Close = CloseKey
+	return d
+}
+
+// ID implements [Model].
+func (*TurnEstimate) ID() string {
+	return TurnEstimateID
+}
+
+// HandleMsg implements [Model].
+func (d *TurnEstimate) HandleMsg(msg tea.Msg) Action {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return nil
+	}
+	if d.send == nil {
+		if key.Matches(keyMsg, d.keyMap.EnterSpace, d.keyMap.Close) {
+			return ActionClose{}
+		}
+		return nil
+	}
+	switch {
+	case key.Matches(keyMsg, d.keyMap.LeftRight, d.keyMap.Tab):
+		d.selectedNo = !d.selectedNo
+	case key.Matches(keyMsg, d.keyMap.EnterSpace):
+		if !d.selectedNo {
+			return *d.send
+		}
+		return d.cancel()
+	case key.Matches(keyMsg, d.keyMap.Yes):
+		return *d.send
+	case key.Matches(keyMsg, d.keyMap.No, d.keyMap.Close):
+		return d.cancel()
+	}
+	return nil
+}
+
+// cancel returns the held back turn to the editor.
+func (d *TurnEstimate) cancel() Action {
+	return ActionCancelEstimatedTurn{Prompt: d.send.Prompt, Attachments: d.send.Attachments}
+}
+
+// Draw implements [Dialog].
+func (d *TurnEstimate) Draw(scr uv.Screen, area uv.Rectangle) *tea.Cursor {
+	lines := []string{"Estimated turn on " + d.est.Model, ""}
+	lines = append(lines, d.breakdown()...)
+	lines = append(lines, "")
+	if d.est.Exact {
+		lines = append(lines, "Counted with the model's tokenizer, before caching discounts.")
+	} else {
+		lines = append(lines, "Estimated from the text length, before caching discounts.")
+	}
+
+	baseStyle := d.com.Styles.Dialog.Quit.Content
+	var content string
+	if d.send == nil {
+		content = baseStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	} else {
+		buttons := common.ButtonGroup(d.com.Styles, []common.ButtonOpts{
+			{Text: "Send", Selected: !d.selectedNo, Padding: 2},
+			{Text: "Cancel", Selected: d.selectedNo, Padding: 2},
+		}, " ")
+		content = baseStyle.Render(lipgloss.JoinVertical(
+			lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Left, lines...),
+			"",
+			fmt.Sprintf("This is above your $%.2f per turn threshold. Send it?", d.threshold),
+			"",
+			buttons,
+		))
+	}
+	DrawCenter(scr, area, d.com.Styles.Dialog.Quit.Frame.Render(content))
+	return nil
+}
+
+// breakdown returns the table of components, totals and costs.
+func (d *TurnEstimate) breakdown() []string {
+	type row struct{ label, tokens, cost string }
+	var rows []row
+	for _, c := range d.est.Components {
+		rows = append(rows, row{"  " + c.Name, common.FormatTokenCount(int64(c.Tokens)), ""})
+	}
+	rows = append(rows,
+		row{"Input", common.FormatTokenCount(int64(d.est.InputTokens())), d.cost(d.est.InputCost())},
+		row{"Output (projected)", "~" + common.FormatTokenCount(int64(d.est.OutputTokens)), d.cost(d.est.OutputCost())},
+		row{"Total", "", d.cost(d.est.Cost())},
+	)
+
+	var labelW, tokensW int
+	for _, r := range rows {
+		labelW = max(labelW, lipgloss.Width(r.label))
+		tokensW = max(tokensW, lipgloss.Width(r.tokens))
+	}
+	lines := make([]string, 0, len(rows))
+	for _, r := range rows {
+		line := fmt.Sprintf("%-*s  %*s", labelW, r.label, tokensW, r.tokens)
+		if r.cost != "" {
+			line += "  " + r.cost
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return lines
+}
+
+// cost formats a projected cost.
+func (d *TurnEstimate) cost(usd float64) string {
+	switch {
+	case d.est.FlatRate:
+		return "flat rate"
+	case !d.est.Priced():
+		return "price unknown"
+	}
+	return fmt.Sprintf("$%.4f", usd)
+}
+
+// ShortHelp implements [help.KeyMap].
+func (d *TurnEstimate) ShortHelp() []key.Binding {
+	if d.send == nil {
+		return []key.Binding{d.keyMap.Close}
+	}
+	return []key.Binding{
+		d.keyMap.LeftRight,
+		d.keyMap.EnterSpace,
+	}
+}
+
+// FullHelp implements [help.KeyMap].
+func (d *TurnEstimate) FullHelp() [][]key.Binding {
+	if d.send == nil {
+		return [][]key.Binding{{d.keyMap.Close}}
+	}
+	return [][]key.Binding{
+		{d.keyMap.LeftRight, d.keyMap.EnterSpace, d.keyMap.Yes, d.keyMap.No},
+		{d.keyMap.Tab, d.keyMap.Close},
+	}
+}
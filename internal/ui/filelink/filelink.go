@@ -0,0 +1,241 @@
+// Package filelink finds file:line references in tool output, such as
+// explorer summaries, diagnostics and stack traces, and turns them into
+// links that open the file in an editor: OSC 8 hyperlinks for URL
+// templates, or a command run on double click for command templates.
+package filelink
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Presets are the editor URL templates selectable by name.
+var Presets = map[string]string{
+	"vscode":          "vscode://file{path}:{line}:{col}",
+	"vscode-insiders": "vscode-insiders://file{path}:{line}:{col}",
+	"vscodium":        "vscodium://file{path}:{line}:{col}",
+	"cursor":          "cursor://file{path}:{line}:{col}",
+	"windsurf":        "windsurf://file{path}:{line}:{col}",
+	"zed":             "zed://file{path}:{line}:{col}",
+	"idea":            "idea://open?file={path}&line={line}&column={col}",
+	"sublime":         "subl://open?url=file://{path}&line={line}&column={col}",
+	"textmate":        "txmt://open?url=file://{path}&line={line}&column={col}",
+	"file":            "file://{path}",
+}
+
+// Ref is a reference to a position in a file.
+type Ref struct {
+	// Path is the path as written in the text.
+	Path string
+	Line int
+	// Col is the 1-based column, or 0 when the reference has none.
+	Col int
+	// Start and End are the byte offsets of the reference in the text.
+	Start, End int
+}
+
+var (
+	// pathLineRE matches path:line[:col], as printed by compilers,
+	// linters, grep and most stack traces. The path needs an extension so
+	// that times and host:port pairs are not taken for references.
+	pathLineRE = regexp.MustCompile(`((?:[A-Za-z]:)?[\w.~/\\@+-]*[\w-]\.[A-Za-z]\w*):(\d+)(?::(\d+))?`)
+	// pythonRE matches the frames of Python tracebacks.
+	pythonRE = regexp.MustCompile(`File "([^"]+)", line (\d+)`)
+)
+
+// Find returns the references in s, ordered by position. Only the path of
+// a Python traceback frame is covered by its reference.
+func Find(s string) []Ref {
+	var refs []Ref
+	for _, m := range pathLineRE.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > 0 && isPathByte(s[m[0]-1]) {
+			continue
+		}
+		ref := Ref{Path: s[m[2]:m[3]], Start: m[0], End: m[1]}
+		ref.Line, _ = strconv.Atoi(s[m[4]:m[5]])
+		if m[6] >= 0 {
+			ref.Col, _ = strconv.Atoi(s[m[6]:m[7]])
+		}
+		refs = append(refs, ref)
+	}
+	for _, m := range pythonRE.FindAllStringSubmatchIndex(s, -1) {
+		ref := Ref{Path: s[m[2]:m[3]], Start: m[2], End: m[3]}
+		ref.Line, _ = strconv.Atoi(s[m[4]:m[5]])
+		refs = append(refs, ref)
+	}
+	slices.SortStableFunc(refs, func(a, b Ref) int { return a.Start - b.Start })
+	return refs
+}
+
+// At returns the reference in s covering display column col.
+func At(s string, col int) (Ref, bool) {
+	for _, ref := range Find(s) {
+		start := ansi.StringWidth(s[:ref.Start])
+		end := start + ansi.StringWidth(s[ref.Start:ref.End])
+		if col >= start && col < end {
+			return ref, true
+		}
+	}
+	return Ref{}, false
+}
+
+// isPathByte reports whether b can be part of a path, in which case a
+// match starting right after it is only a suffix of a longer token.
+func isPathByte(b byte) bool {
+	return b == '/' || b == '\\' || b == '.' || b == '-' || b == '_' ||
+		b >= '0' && b <= '9' || b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z'
+}
+
+// maxStatCache bounds the remembered existence checks.
+const maxStatCache = 4096
+
+// Linker renders references to existing files as editor links.
+type Linker struct {
+	url     string
+	command []string
+	root    string
+
+	mu     sync.Mutex
+	exists map[string]bool
+}
+
+// New returns a linker for spec, which is a preset name, a URL template or
+// a command template, resolving relative paths against root. Templates
+// use the {path}, {line} and {col} placeholders; a template without "://"
+// is a command, split on spaces and run without a shell. It returns nil
+// when spec is empty.
+func New(spec, root string) *Linker {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil
+	}
+	l := &Linker{root: root, exists: make(map[string]bool)}
+	switch {
+	case Presets[spec] != "":
+		l.url = Presets[spec]
+	case strings.Contains(spec, "://"):
+		l.url = spec
+	default:
+		l.command = strings.Fields(spec)
+	}
+	return l
+}
+
+// HasCommand reports whether references open through a command rather
+// than a hyperlink.
+func (l *Linker) HasCommand() bool {
+	return l != nil && len(l.command) > 0
+}
+
+// Resolve returns the absolute path of a reference to an existing regular
+// file, or "" when there is none.
+func (l *Linker) Resolve(ref Ref) string {
+	path := ref.Path
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(l.root, path)
+	}
+	path = filepath.Clean(path)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ok, seen := l.exists[path]
+	if !seen {
+		info, err := os.Stat(path)
+		ok = err == nil && info.Mode().IsRegular()
+		if len(l.exists) >= maxStatCache {
+			clear(l.exists)
+		}
+		l.exists[path] = ok
+	}
+	if !ok {
+		return ""
+	}
+	return path
+}
+
+// URL returns the editor URL of a reference, or "" when the linker uses a
+// command or the file does not exist.
+func (l *Linker) URL(ref Ref) string {
+	if l == nil || l.url == "" {
+		return ""
+	}
+	path := l.Resolve(ref)
+	if path == "" {
+		return ""
+	}
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed // Windows drive paths.
+	}
+	return expand(l.url, (&url.URL{Path: slashed}).EscapedPath(), ref)
+}
+
+// Command returns the command line that opens a reference, or nil when
+// the linker uses URLs or the file does not exist.
+func (l *Linker) Command(ref Ref) []string {
+	if !l.HasCommand() {
+		return nil
+	}
+	path := l.Resolve(ref)
+	if path == "" {
+		return nil
+	}
+	args := make([]string, len(l.command))
+	for i, arg := range l.command {
+		args[i] = expand(arg, path, ref)
+	}
+	return args
+}
+
+// expand fills the placeholders of a template.
+func expand(template, path string, ref Ref) string {
+	return strings.NewReplacer(
+		"{path}", path,
+		"{line}", strconv.Itoa(max(ref.Line, 1)),
+		"{col}", strconv.Itoa(max(ref.Col, 1)),
+	).Replace(template)
+}
+
+// Linkify wraps the references in s to existing files in OSC 8
+// hyperlinks. It returns s unchanged for a nil linker or one that uses a
+// command. s must be plain text, without escape sequences.
+func (l *Linker) Linkify(s string) string {
+	if l == nil || l.url == "" {
+		return s
+	}
+	refs := Find(s)
+	if len(refs) == 0 {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		u := l.URL(ref)
+		if u == "" || ref.Start < last {
+			continue
+		}
+		b.WriteString(s[last:ref.Start])
+		b.WriteString(ansi.SetHyperlink(u))
+		b.WriteString(s[ref.Start:ref.End])
+		b.WriteString(ansi.ResetHyperlink())
+		last = ref.End
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
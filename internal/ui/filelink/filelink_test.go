@@ -0,0 +1,107 @@
+package filelink
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want []Ref
+	}{
+		{
+			name: "compiler diagnostic",
+			text: "internal/app/app.go:42:7: undefined: foo",
+			want: []Ref{{Path: "internal/app/app.go", Line: 42, Col: 7, Start: 0, End: 24}},
+		},
+		{
+			name: "go stack frame",
+			text: "\t/src/main.go:12 +0x1d",
+			want: []Ref{{Path: "/src/main.go", Line: 12, Start: 1, End: 16}},
+		},
+		{
+			name: "node stack frame",
+			text: "    at run (lib/index.js:10:5)",
+			want: []Ref{{Path: "lib/index.js", Line: 10, Col: 5, Start: 12, End: 29}},
+		},
+		{
+			name: "python traceback",
+			text: `  File "app/views.py", line 88, in index`,
+			want: []Ref{{Path: "app/views.py", Line: 88, Start: 8, End: 20}},
+		},
+		{
+			name: "times and ports are not references",
+			text: "started 12:30:01 on localhost:8080 and v1.2:3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, Find(tt.text))
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	t.Parallel()
+
+	line := " ok main.go:3 and util.go:9"
+	ref, ok := At(line, 5)
+	require.True(t, ok)
+	require.Equal(t, "main.go", ref.Path)
+	ref, ok = At(line, 20)
+	require.True(t, ok)
+	require.Equal(t, "util.go", ref.Path)
+	_, ok = At(line, 15)
+	require.False(t, ok)
+}
+
+func TestLinker(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), nil, 0o644))
+	abs := filepath.Join(root, "main.go")
+
+	t.Run("preset", func(t *testing.T) {
+		t.Parallel()
+		l := New("vscode", root)
+		require.False(t, l.HasCommand())
+		require.Equal(t, "vscode://file"+filepath.ToSlash(abs)+":3:1", l.URL(Ref{Path: "main.go", Line: 3}))
+		require.Empty(t, l.URL(Ref{Path: "missing.go", Line: 3}))
+	})
+
+	t.Run("linkify", func(t *testing.T) {
+		t.Parallel()
+		l := New("idea://open?file={path}&line={line}", root)
+		text := "main.go:3: bad, missing.go:4: gone"
+		linked := l.Linkify(text)
+		require.Equal(t, text, ansi.Strip(linked))
+		require.Contains(t, linked, ansi.SetHyperlink("idea://open?file="+filepath.ToSlash(abs)+"&line=3")+"main.go:3"+ansi.ResetHyperlink())
+		require.NotContains(t, linked, "missing.go&")
+	})
+
+	t.Run("command", func(t *testing.T) {
+		t.Parallel()
+		l := New("code -g {path}:{line}:{col}", root)
+		require.True(t, l.HasCommand())
+		require.Equal(t, "main.go:3", l.Linkify("main.go:3"))
+		require.Equal(t, []string{"code", "-g", abs + ":3:2"}, l.Command(Ref{Path: "main.go", Line: 3, Col: 2}))
+	})
+
+	t.Run("off", func(t *testing.T) {
+		t.Parallel()
+		var l *Linker = New("", root)
+		require.Nil(t, l)
+		require.Equal(t, "main.go:3", l.Linkify("main.go:3"))
+		require.False(t, l.HasCommand())
+	})
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/crush/internal/ui/chat"
+	"github.com/charmbracelet/crush/internal/ui/list"
+)
+
+// linearItem renders a chat item for screen readers: the decorative left
+// border is dropped and the content follows a plain role prefix line.
+type linearItem struct {
+	list.Item
+	prefix string
+}
+
+// Render implements [list.Item].
+func (l linearItem) Render(width int) string {
+	body := ""
+	if raw, ok := l.Item.(list.RawRenderable); ok {
+		body = raw.RawRender(width)
+	} else {
+		body = l.Item.Render(width)
+	}
+	return l.prefix + "\n" + body
+}
+
+// linearizeItem is a [list.RenderCallback] that swaps items for their
+// linearized form in accessibility mode.
+func (m *Chat) linearizeItem(_, _ int, item list.Item) list.Item {
+	if !m.accessible {
+		return nil
+	}
+	prefix := accessiblePrefix(item)
+	if prefix == "" {
+		return nil
+	}
+	return linearItem{Item: item, prefix: prefix}
+}
+
+// accessiblePrefix returns the semantic prefix announcing item's role,
+// and for tools their name and state.
+func accessiblePrefix(item list.Item) string {
+	switch item := item.(type) {
+	case *chat.UserMessageItem:
+		return "USER:"
+	case *chat.AssistantMessageItem:
+		return "ASSISTANT:"
+	case *chat.AssistantInfoItem:
+		return "INFO:"
+	case *chat.ProcessingItem:
+		return "STATUS:"
+	case chat.ToolMessageItem:
+		return fmt.Sprintf("TOOL %s (%s):", item.ToolCall().Name, toolStatusText(item.Status()))
+	}
+	return ""
+}
+
+// toolStatusText spells out a tool status, which is otherwise shown only
+// as a colored icon.
+func toolStatusText(status chat.ToolStatus) string {
+	switch status {
+	case chat.ToolStatusAwaitingPermission:
+		return "awaiting permission"
+	case chat.ToolStatusRunning:
+		return "running"
+	case chat.ToolStatusSuccess:
+		return "done"
+	case chat.ToolStatusError:
+		return "failed"
+	case chat.ToolStatusCanceled:
+		return "canceled"
+	}
+	return "unknown"
+}
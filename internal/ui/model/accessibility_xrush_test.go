@@ -0,0 +1,56 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/chat"
+	"github.com/charmbracelet/crush/internal/ui/common"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatLinearizesItemsInAccessibilityMode(t *testing.T) {
+	t.Parallel()
+
+	sty := styles.HighContrast()
+	com := &common.Common{Styles: &sty}
+	require.True(t, com.Accessible())
+
+	user := &message.Message{
+		ID:    "u1",
+		Role:  message.User,
+		Parts: []message.ContentPart{message.TextContent{Text: "list files"}},
+	}
+	assistant := &message.Message{
+		ID:    "a1",
+		Role:  message.Assistant,
+		Parts: []message.ContentPart{message.TextContent{Text: "Here they are"}},
+	}
+	ch := NewChat(com)
+	ch.SetSize(80, 40)
+	ch.SetMessages(
+		chat.NewUserMessageItem(&sty, user, nil),
+		chat.NewAssistantMessageItem(&sty, assistant),
+	)
+
+	lines := strings.Split(ansi.Strip(ch.list.Render()), "\n")
+	var prefixes []string
+	for _, line := range lines {
+		if strings.HasSuffix(line, ":") && strings.ToUpper(line) == line {
+			prefixes = append(prefixes, line)
+		}
+	}
+	require.Equal(t, []string{"USER:", "ASSISTANT:"}, prefixes)
+	require.NotContains(t, ansi.Strip(ch.list.Render()), "│")
+}
+
+func TestToolStatusTextCoversAllStates(t *testing.T) {
+	t.Parallel()
+
+	for status := chat.ToolStatusAwaitingPermission; status <= chat.ToolStatusCanceled; status++ {
+		require.NotEqual(t, "unknown", toolStatusText(status))
+	}
+}
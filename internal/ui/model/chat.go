@@ -67,6 +67,18 @@ type Chat struct {
 	// XRUSH: SessionID is the current session ID, used for message options.
 	sessionID string
 
+	// XRUSH: showUsage annotates assistant messages with token usage and
+	// cost. Applied to items as they are added.
+	showUsage bool
+
+	// XRUSH: thinkingDisplay is the reasoning display mode applied to
+	// assistant messages as they are added.
+	thinkingDisplay string
+
+	// XRUSH: accessible linearizes items for screen readers; see
+	// linearizeItem.
+	accessible bool
+
 	// follow is a flag to indicate whether the view should auto-scroll to
 	// bottom on new messages.
 	follow bool
@@ -110,6 +122,9 @@ func NewChat(com *common.Common) *Chat {
 	l.SetGap(1)
 	l.RegisterRenderCallback(c.applyHighlightRange)
 	l.RegisterRenderCallback(list.FocusedRenderCallback(l))
+	// XRUSH: must run last so it wraps the focus-styled item.
+	c.accessible = com.Accessible()
+	l.RegisterRenderCallback(c.linearizeItem)
 	c.list = l
 	c.mouseDownItem = -1
 	c.mouseDragItem = -1
@@ -253,6 +268,8 @@ func (m *Chat) SetMessages(msgs ...chat.MessageItem) {
 		}
 		items[i] = msg
 	}
+	m.applyShowUsage(msgs...)
+	m.applyThinkingDisplay(msgs...)
 	m.list.SetItems(items...)
 	m.ScrollToBottom()
 }
@@ -271,6 +288,8 @@ func (m *Chat) AppendMessages(msgs ...chat.MessageItem) {
 		}
 		items[i] = msg
 	}
+	m.applyShowUsage(msgs...)
+	m.applyThinkingDisplay(msgs...)
 	m.list.AppendItems(items...)
 }
 
@@ -692,6 +711,11 @@ func (m *Chat) HandleMouseDown(x, y int) (bool, tea.Cmd) {
 			}
 		})
 	case 2:
+		// XRUSH: double click on a file reference opens it in the editor.
+		if cmd := m.openFileReferenceAt(itemIdx, x, itemY); cmd != nil {
+			m.clickCount = 0
+			return true, cmd
+		}
 		// Double click - select word (no delayed action)
 		m.selectWord(itemIdx, x, itemY)
 	case 3:
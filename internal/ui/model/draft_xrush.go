@@ -0,0 +1,124 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+const (
+	// draftFile is the prompt draft's file name in the data directory.
+	draftFile = "draft.md"
+	// draftSaveInterval is how often the editor is checked for changes
+	// that need to be written to the draft file.
+	draftSaveInterval = 2 * time.Second
+	// recoveryBannerTTL keeps the recovery notice up long enough to read.
+	recoveryBannerTTL = 10 * time.Second
+)
+
+// draftTickMsg triggers a periodic draft save.
+type draftTickMsg struct{}
+
+// draftPath returns the path of the prompt draft file, or "" when there
+// is no data directory.
+func (m *UI) draftPath() string {
+	cfg := m.com.Config()
+	if cfg == nil || cfg.Options == nil || cfg.Options.DataDirectory == "" {
+		return ""
+	}
+	return filepath.Join(cfg.Options.DataDirectory, draftFile)
+}
+
+// restoreAfterCrash puts a persisted prompt draft back into the empty
+// editor, announces any responses recovered at startup, and starts the
+// periodic draft autosave.
+func (m *UI) restoreAfterCrash() tea.Cmd {
+	var notes []string
+	if path := m.draftPath(); path != "" && m.textarea.Value() == "" {
+		if text := loadDraft(path); text != "" {
+			m.textarea.SetValue(text)
+			m.savedDraft = text
+			notes = append(notes, "restored your unsent prompt")
+		}
+	}
+	switch n := len(m.com.Workspace.RecoveredSessions()); {
+	case n == 1:
+		notes = append(notes, "kept the partial response of an interrupted session")
+	case n > 1:
+		notes = append(notes, fmt.Sprintf("kept the partial responses of %d interrupted sessions", n))
+	}
+
+	cmds := []tea.Cmd{scheduleDraftSave()}
+	if len(notes) > 0 {
+		cmds = append(cmds, util.CmdHandler(util.InfoMsg{
+			Type: util.InfoTypeInfo,
+			Msg:  "Recovered: " + strings.Join(notes, "; "),
+			TTL:  recoveryBannerTTL,
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// scheduleDraftSave schedules the next draft save.
+func scheduleDraftSave() tea.Cmd {
+	return tea.Tick(draftSaveInterval, func(time.Time) tea.Msg {
+		return draftTickMsg{}
+	})
+}
+
+// handleDraftTick writes the editor contents to the draft file if they
+// changed since the last save, then schedules the next save.
+func (m *UI) handleDraftTick() tea.Cmd {
+	text := m.textarea.Value()
+	path := m.draftPath()
+	if text == m.savedDraft || path == "" {
+		return scheduleDraftSave()
+	}
+	m.savedDraft = text
+	return tea.Batch(scheduleDraftSave(), func() tea.Msg {
+		if err := saveDraft(path, text); err != nil {
+			slog.Warn("Failed to save prompt draft", "path", path, "error", err)
+		}
+		return nil
+	})
+}
+
+// clearDraft removes the draft file once its prompt has been sent.
+func (m *UI) clearDraft() {
+	m.savedDraft = ""
+	if path := m.draftPath(); path != "" {
+		if err := saveDraft(path, ""); err != nil {
+			slog.Warn("Failed to clear prompt draft", "path", path, "error", err)
+		}
+	}
+}
+
+// loadDraft returns the draft stored at path, or "" if there is none.
+func loadDraft(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// saveDraft writes text to path atomically. Blank drafts remove the file.
+func saveDraft(path, text string) error {
+	if strings.TrimSpace(text) == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return fsext.WriteFileAtomic(path, []byte(text), 0o600)
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), draftFile)
+	require.Empty(t, loadDraft(path), "missing draft should load empty")
+
+	require.NoError(t, saveDraft(path, "a long prompt\nwith lines"))
+	require.Equal(t, "a long prompt\nwith lines", loadDraft(path))
+
+	// Blank drafts remove the file, and removing twice is fine.
+	require.NoError(t, saveDraft(path, "  \n"))
+	_, err := os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, saveDraft(path, ""))
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/charmbracelet/crush/internal/ui/chat"
+	"github.com/charmbracelet/crush/internal/ui/filelink"
+	"github.com/charmbracelet/crush/internal/ui/list"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// openFileReferenceAt returns a command that opens the file reference
+// under a double click with the editor command template, or nil when
+// there is no reference there or editor links use URLs.
+func (m *Chat) openFileReferenceAt(itemIdx, x, itemY int) tea.Cmd {
+	linker := chat.FileLinker()
+	if !linker.HasCommand() {
+		return nil
+	}
+	item := m.list.ItemAt(itemIdx)
+	if item == nil {
+		return nil
+	}
+	var rendered string
+	if rr, ok := item.(list.RawRenderable); ok {
+		rendered = rr.RawRender(m.list.Width())
+	} else {
+		rendered = item.Render(m.list.Width())
+	}
+	lines := strings.Split(rendered, "\n")
+	if itemY < 0 || itemY >= len(lines) {
+		return nil
+	}
+
+	line := ansi.Strip(lines[itemY])
+	ref, ok := filelink.At(line, max(x-chat.MessageLeftPaddingTotal, 0))
+	if !ok {
+		return nil
+	}
+	args := linker.Command(ref)
+	if args == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // The command template comes from the user's config.
+		if err := cmd.Start(); err != nil {
+			return util.NewErrorMsg(fmt.Errorf("open %s: %w", ref.Path, err))
+		}
+		go func() { _ = cmd.Wait() }()
+		return util.NewInfoMsg(fmt.Sprintf("Opened %s:%d", filepath.Base(ref.Path), ref.Line))
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// SetInitialPrompt fills the editor with a prompt to review before it is
+// sent. It is used for the default prompt of a session template, and must
+// be called before the program starts.
+func (m *UI) SetInitialPrompt(prompt string) {
+	m.initialPrompt = prompt
+}
+
+// fillInitialPrompt returns a command that puts the initial prompt in the
+// editor, or nil when there is none.
+func (m *UI) fillInitialPrompt() tea.Cmd {
+	if m.initialPrompt == "" {
+		return nil
+	}
+	return util.CmdHandler(openEditorMsg{Text: m.initialPrompt})
+}
@@ -21,6 +21,8 @@ type KeyMap struct {
 		// History navigation
 		HistoryPrev key.Binding
 		HistoryNext key.Binding
+
+		Voice key.Binding // XRUSH: push-to-talk voice input
 	}
 
 	Chat struct {
@@ -48,6 +50,11 @@ type KeyMap struct {
 		ClearHighlight key.Binding
 		Expand         key.Binding
 		MessageOptions key.Binding // XRUSH: message options keybinding
+		OpenPane       key.Binding // XRUSH: open item in a tmux/iTerm2 pane
+		FoldCode       key.Binding // XRUSH: fold/unfold long code blocks
+		RawView        key.Binding // XRUSH: toggle raw markdown view
+		ToggleUsage    key.Binding // XRUSH: toggle per-message usage annotations
+		Translate      key.Binding // XRUSH: translate the selected assistant message
 	}
 
 	Initialize struct {
@@ -151,6 +158,10 @@ func DefaultKeyMap() KeyMap {
 		key.WithKeys("r"),
 		key.WithHelp("ctrl+r+r", "delete all attachments"),
 	)
+	km.Editor.Voice = key.NewBinding(
+		key.WithKeys("alt+v"),
+		key.WithHelp("alt+v", "voice input"),
+	)
 	km.Editor.HistoryPrev = key.NewBinding(
 		key.WithKeys("up"),
 	)
@@ -256,6 +267,29 @@ func DefaultKeyMap() KeyMap {
 		key.WithKeys("o"),
 		key.WithHelp("o", "message options"),
 	)
+	// XRUSH: open the selected item in a tmux pane or iTerm2 split.
+	km.Chat.OpenPane = key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "open in pane"),
+	)
+	// XRUSH: markdown display toggles, handled by the assistant item.
+	km.Chat.FoldCode = key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "fold/unfold code"),
+	)
+	km.Chat.RawView = key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "raw markdown"),
+	)
+	km.Chat.ToggleUsage = key.NewBinding(
+		key.WithKeys("$"),
+		key.WithHelp("$", "token usage"),
+	)
+	// XRUSH: translate the selected assistant message.
+	km.Chat.Translate = key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "translate"),
+	)
 	km.Initialize.Yes = key.NewBinding(
 		key.WithKeys("y", "Y"),
 		key.WithHelp("y", "yes"),
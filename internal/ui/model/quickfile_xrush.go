@@ -0,0 +1,26 @@
+package model
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// SetInitialAttachment attaches a file to the first prompt. It is used
+// when crush is started with a file argument, and must be called before
+// the program starts.
+func (m *UI) SetInitialAttachment(att message.Attachment) {
+	m.initialAttachment = &att
+	m.readyPlaceholder = "Ask about " + att.FileName + "…"
+	m.textarea.Placeholder = m.readyPlaceholder
+}
+
+// attachInitialFile returns a command that adds the initial attachment to
+// the editor, or nil when there is none.
+func (m *UI) attachInitialFile() tea.Cmd {
+	if m.initialAttachment == nil {
+		return nil
+	}
+	return util.CmdHandler(*m.initialAttachment)
+}
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/crush/internal/repomap"
+	"github.com/charmbracelet/crush/internal/ui/dialog"
 	"github.com/charmbracelet/crush/internal/ui/util"
 )
 
@@ -40,3 +42,54 @@ func (m *UI) handleRepoMapRefreshResult(msg RepoMapRefreshResultMsg) tea.Cmd {
 		return util.InfoMsg{Type: util.InfoTypeSuccess, Msg: "Repo map refreshed"}
 	}
 }
+
+// RepoMapOverrideResultMsg carries the result of pinning, blacklisting or
+// clearing repo map entries from the command palette. Kind and Target are
+// empty when all overrides were cleared.
+type RepoMapOverrideResultMsg struct {
+	SessionID string
+	Kind      repomap.OverrideKind
+	Target    string
+	Err       error
+}
+
+// executeRepoMapOverride persists a pin or blacklist entry and refreshes the
+// session's repo map.
+func (m *UI) executeRepoMapOverride(action dialog.ActionRepoMapOverride) tea.Cmd {
+	return func() tea.Msg {
+		err := m.com.Workspace.RepoMapSetOverride(context.Background(), action.SessionID, action.Kind, action.Target)
+		return RepoMapOverrideResultMsg{
+			SessionID: action.SessionID,
+			Kind:      action.Kind,
+			Target:    action.Target,
+			Err:       err,
+		}
+	}
+}
+
+// executeRepoMapClearOverrides drops all pin and blacklist entries of the
+// session and refreshes its repo map.
+func (m *UI) executeRepoMapClearOverrides(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.com.Workspace.RepoMapClearOverrides(context.Background(), sessionID)
+		return RepoMapOverrideResultMsg{SessionID: sessionID, Err: err}
+	}
+}
+
+// handleRepoMapOverrideResult reports the outcome of a repo map override
+// change.
+func (m *UI) handleRepoMapOverrideResult(msg RepoMapOverrideResultMsg) tea.Cmd {
+	if msg.Err != nil {
+		slog.Error("Repo map override failed", "session_id", msg.SessionID, "target", msg.Target, "error", msg.Err)
+		return func() tea.Msg {
+			return util.InfoMsg{Type: util.InfoTypeError, Msg: fmt.Sprintf("Repo map override failed: %v", msg.Err)}
+		}
+	}
+	switch msg.Kind {
+	case repomap.OverridePin:
+		return util.ReportInfo(fmt.Sprintf("Pinned %s in the repo map", msg.Target))
+	case repomap.OverrideBlacklist:
+		return util.ReportInfo(fmt.Sprintf("Blacklisted %s from the repo map", msg.Target))
+	}
+	return util.ReportInfo("Repo map pins and blacklist cleared")
+}
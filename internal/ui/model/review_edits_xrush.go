@@ -0,0 +1,84 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/staging"
+	"github.com/charmbracelet/crush/internal/ui/dialog"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// stagedEditsLoadedMsg carries the staged changes to show in the review
+// dialog.
+type stagedEditsLoadedMsg struct {
+	SessionID string
+	Changes   []staging.Change
+}
+
+// stagedEditsAppliedMsg carries the result of writing reviewed hunks.
+type stagedEditsAppliedMsg struct {
+	Results []staging.CommitResult
+	Err     error
+}
+
+// loadStagedEdits fetches the staged changes for the session.
+func (m *UI) loadStagedEdits(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		svc := m.com.Workspace.StagingService()
+		if svc == nil {
+			return util.InfoMsg{Type: util.InfoTypeWarn, Msg: "Edit review is not available"}
+		}
+		return stagedEditsLoadedMsg{SessionID: sessionID, Changes: svc.List(sessionID)}
+	}
+}
+
+// handleStagedEditsLoaded opens the review dialog, or reports that there
+// is nothing to review.
+func (m *UI) handleStagedEditsLoaded(msg stagedEditsLoadedMsg) tea.Cmd {
+	if len(msg.Changes) == 0 {
+		return util.ReportInfo("No staged edits to review")
+	}
+	m.dialog.OpenDialog(dialog.NewReviewEdits(m.com, msg.SessionID, msg.Changes))
+	return nil
+}
+
+// applyStagedEdits records the hunk decisions made in the review dialog
+// and writes the accepted hunks.
+func (m *UI) applyStagedEdits(sessionID string, changes []staging.Change) tea.Cmd {
+	return func() tea.Msg {
+		svc := m.com.Workspace.StagingService()
+		if svc == nil {
+			return util.InfoMsg{Type: util.InfoTypeWarn, Msg: "Edit review is not available"}
+		}
+		for _, c := range changes {
+			for _, h := range c.Hunks {
+				if err := svc.Decide(sessionID, c.Path, h.Index, h.Decision); err != nil {
+					return stagedEditsAppliedMsg{Err: err}
+				}
+			}
+		}
+		results, err := svc.Commit(context.Background(), sessionID)
+		return stagedEditsAppliedMsg{Results: results, Err: err}
+	}
+}
+
+// handleStagedEditsApplied reports the outcome of writing reviewed hunks.
+func (m *UI) handleStagedEditsApplied(msg stagedEditsAppliedMsg) tea.Cmd {
+	if msg.Err != nil {
+		slog.Error("Failed to apply staged edits", "error", msg.Err)
+		return util.ReportError(fmt.Errorf("failed to apply staged edits: %w", msg.Err))
+	}
+	written, accepted, rejected := 0, 0, 0
+	for _, r := range msg.Results {
+		if r.Written {
+			written++
+		}
+		accepted += r.Accepted
+		rejected += r.Rejected
+	}
+	return util.ReportInfo(fmt.Sprintf("Wrote %d file(s): %d hunk(s) accepted, %d rejected", written, accepted, rejected))
+}
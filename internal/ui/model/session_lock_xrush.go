@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/ui/dialog"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// sessionLockedMsg reports a prompt refused because another crush process
+// holds the session lock.
+type sessionLockedMsg struct {
+	action dialog.ActionTakeOverSession
+	owner  *session.LockedError
+}
+
+// agentRunResult turns the error of an agent run into the message the UI
+// shows for it. A prompt refused by a session lock becomes a takeover
+// prompt.
+func agentRunResult(err error, sessionID, content string, attachments []message.Attachment) tea.Msg {
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return nil
+	case errors.Is(err, session.ErrSessionLocked):
+		var owner *session.LockedError
+		errors.As(err, &owner)
+		return sessionLockedMsg{
+			action: dialog.ActionTakeOverSession{SessionID: sessionID, Prompt: content, Attachments: attachments},
+			owner:  owner,
+		}
+	}
+	return util.InfoMsg{
+		Type: util.InfoTypeError,
+		Msg:  err.Error(),
+	}
+}
+
+// handleSessionLocked asks whether to take the session over.
+func (m *UI) handleSessionLocked(msg sessionLockedMsg) tea.Cmd {
+	if m.dialog.ContainsDialog(dialog.SessionTakeoverID) {
+		m.dialog.CloseDialog(dialog.SessionTakeoverID)
+	}
+	m.dialog.OpenDialog(dialog.NewSessionTakeover(m.com, msg.action, msg.owner))
+	return nil
+}
+
+// takeOverSession resends a refused prompt, taking the session lock over
+// from the process holding it.
+func (m *UI) takeOverSession(action dialog.ActionTakeOverSession) tea.Cmd {
+	return func() tea.Msg {
+		ctx := session.WithTakeover(context.Background())
+		err := m.com.Workspace.AgentRun(ctx, action.SessionID, action.Prompt, action.Attachments...)
+		return agentRunResult(err, action.SessionID, action.Prompt, action.Attachments)
+	}
+}
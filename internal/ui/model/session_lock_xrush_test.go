@@ -0,0 +1,31 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/ui/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentRunResult(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, agentRunResult(nil, "s", "hi", nil))
+	require.Nil(t, agentRunResult(context.Canceled, "s", "hi", nil))
+
+	info, ok := agentRunResult(errors.New("boom"), "s", "hi", nil).(util.InfoMsg)
+	require.True(t, ok)
+	require.Equal(t, util.InfoTypeError, info.Type)
+	require.Equal(t, "boom", info.Msg)
+
+	locked := &session.LockedError{SessionID: "s", PID: 42}
+	msg, ok := agentRunResult(fmt.Errorf("run: %w", locked), "s", "hi", nil).(sessionLockedMsg)
+	require.True(t, ok)
+	require.Equal(t, "s", msg.action.SessionID)
+	require.Equal(t, "hi", msg.action.Prompt)
+	require.Same(t, locked, msg.owner)
+}
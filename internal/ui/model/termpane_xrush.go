@@ -0,0 +1,66 @@
+package model
+
+import (
+	"context"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/termpane"
+	"github.com/charmbracelet/crush/internal/ui/chat"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// SelectedPaneContent returns the content of the selected item if it can
+// be opened in an external pane.
+func (m *Chat) SelectedPaneContent() (string, bool) {
+	item, ok := m.list.SelectedItem().(chat.PaneContentProvider)
+	if !ok {
+		return "", false
+	}
+	content := item.PaneContent()
+	return content, content != ""
+}
+
+// outputPane resolves the multiplexer and pane options from config.
+func (m *UI) outputPane() (termpane.Multiplexer, termpane.Options, error) {
+	var opts termpane.Options
+	var name string
+	if cfg := m.com.Config(); cfg != nil && cfg.Options != nil && cfg.Options.OutputPane != nil {
+		name = cfg.Options.OutputPane.Multiplexer
+		opts.Pager = cfg.Options.OutputPane.Pager
+		opts.Vertical = cfg.Options.OutputPane.Vertical
+	}
+	mux, err := termpane.Resolve(name, m.caps.Env)
+	return mux, opts, err
+}
+
+// openSelectedInPane opens the selected chat item in a tmux pane or iTerm2
+// split so verbose output does not crowd the chat.
+func (m *UI) openSelectedInPane() tea.Cmd {
+	content, ok := m.chat.SelectedPaneContent()
+	if !ok {
+		return util.ReportWarn("Nothing to open for the selected item")
+	}
+	mux, opts, err := m.outputPane()
+	if err != nil {
+		return util.ReportError(err)
+	}
+	if mux == termpane.MultiplexerNone {
+		return util.ReportWarn("Output panes need tmux or iTerm2")
+	}
+	return func() tea.Msg {
+		if err := termpane.Open(context.Background(), mux, "", content, opts); err != nil {
+			return util.InfoMsg{Type: util.InfoTypeError, Msg: err.Error()}
+		}
+		return util.NewInfoMsg("Opened in " + string(mux) + " pane")
+	}
+}
+
+// canOpenPane reports whether the selected item can be opened in a pane.
+func (m *UI) canOpenPane() bool {
+	if _, ok := m.chat.SelectedPaneContent(); !ok {
+		return false
+	}
+	mux, _, err := m.outputPane()
+	return err == nil && mux != termpane.MultiplexerNone
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"context"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/ui/chat"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// translatedMsg carries the translation of an assistant message.
+type translatedMsg struct {
+	id   string
+	text string
+	err  error
+}
+
+// translationOptions returns the translation configuration, or nil when no
+// target language is configured.
+func (m *UI) translationOptions() *config.TranslationOptions {
+	opts := m.com.Config().Options.Translation
+	if opts == nil || strings.TrimSpace(opts.Language) == "" {
+		return nil
+	}
+	return opts
+}
+
+// toggleTranslation translates the selected assistant message, or hides
+// its translation if one is already shown.
+func (m *UI) toggleTranslation() tea.Cmd {
+	item := m.chat.SelectedAssistantItem()
+	if item == nil {
+		return nil
+	}
+	if item.HasTranslation() {
+		item.SetTranslation("", false)
+		return nil
+	}
+	if m.translationOptions() == nil {
+		return util.ReportWarn("Set options.translation.language to translate messages")
+	}
+	return m.translate(item)
+}
+
+// translate returns a command translating item's content with the small
+// model.
+func (m *UI) translate(item *chat.AssistantMessageItem) tea.Cmd {
+	id, text := item.ID(), item.Text()
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	ws := m.com.Workspace
+	return tea.Sequence(
+		util.ReportInfo("Translating…"),
+		func() tea.Msg {
+			out, err := ws.TranslateText(context.Background(), text)
+			return translatedMsg{id: id, text: out, err: err}
+		},
+	)
+}
+
+// autoTranslate translates a finished assistant reply when automatic
+// translation is enabled.
+func (m *UI) autoTranslate(id string) tea.Cmd {
+	opts := m.translationOptions()
+	if opts == nil || !opts.Auto {
+		return nil
+	}
+	item, ok := m.chat.MessageItem(id).(*chat.AssistantMessageItem)
+	if !ok || item.HasTranslation() {
+		return nil
+	}
+	return m.translate(item)
+}
+
+// handleTranslated attaches a finished translation to its message.
+func (m *UI) handleTranslated(msg translatedMsg) tea.Cmd {
+	if msg.err != nil {
+		return util.ReportError(msg.err)
+	}
+	item, ok := m.chat.MessageItem(msg.id).(*chat.AssistantMessageItem)
+	if !ok {
+		return nil
+	}
+	item.SetTranslation(msg.text, m.com.Config().Options.Translation.SideBySide())
+	return nil
+}
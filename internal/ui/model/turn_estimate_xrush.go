@@ -0,0 +1,107 @@
+package model
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/agent"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/dialog"
+	"github.com/charmbracelet/crush/internal/ui/util"
+)
+
+// turnEstimatedMsg carries the estimate of a turn. send is set when the
+// turn is held back until its cost is checked.
+type turnEstimatedMsg struct {
+	est  agent.TurnEstimate
+	err  error
+	send *dialog.ActionSendEstimatedTurn
+}
+
+// costThreshold returns the projected cost above which a turn needs
+// confirmation, or 0 when turns are sent without one.
+func (m *UI) costThreshold() float64 {
+	opts := m.com.Config().Options
+	if opts == nil {
+		return 0
+	}
+	return opts.CostEstimate.Threshold()
+}
+
+// estimateDraft estimates sending the editor content, without sending it.
+func (m *UI) estimateDraft() tea.Cmd {
+	content := strings.TrimSpace(m.textarea.Value())
+	return m.estimateTurn(content, slices.Clone(m.attachments.List()), nil)
+}
+
+// estimateTurn returns a command estimating a turn of the current session.
+func (m *UI) estimateTurn(content string, attachments []message.Attachment, send *dialog.ActionSendEstimatedTurn) tea.Cmd {
+	var sessionID string
+	if m.hasSession() {
+		sessionID = m.session.ID
+	}
+	return func() tea.Msg {
+		est, err := m.com.Workspace.AgentEstimateTurn(context.Background(), sessionID, content, attachments...)
+		return turnEstimatedMsg{est: est, err: err, send: send}
+	}
+}
+
+// holdForCostCheck holds a turn back while its cost is estimated, when a
+// confirmation threshold is configured. It returns nil when the turn can
+// be sent right away.
+func (m *UI) holdForCostCheck(content string, attachments []message.Attachment) tea.Cmd {
+	if m.costApproved || m.costThreshold() <= 0 {
+		return nil
+	}
+	return m.estimateTurn(content, attachments, &dialog.ActionSendEstimatedTurn{Prompt: content, Attachments: attachments})
+}
+
+// handleTurnEstimated shows an estimate, or sends the turn it was made for
+// unless its cost exceeds the threshold, in which case it asks first. A
+// turn whose cost cannot be estimated is sent.
+func (m *UI) handleTurnEstimated(msg turnEstimatedMsg) tea.Cmd {
+	threshold := m.costThreshold()
+	if msg.send == nil {
+		if msg.err != nil {
+			return util.ReportError(msg.err)
+		}
+		m.dialog.OpenDialog(dialog.NewTurnEstimate(m.com, msg.est, threshold, nil))
+		return nil
+	}
+	if msg.err != nil {
+		slog.Warn("Failed to estimate turn cost, sending without confirmation", "error", msg.err)
+		return m.sendEstimatedTurn(*msg.send)
+	}
+	if msg.est.Cost() <= threshold {
+		return m.sendEstimatedTurn(*msg.send)
+	}
+	if m.dialog.ContainsDialog(dialog.TurnEstimateID) {
+		m.dialog.CloseDialog(dialog.TurnEstimateID)
+	}
+	m.dialog.OpenDialog(dialog.NewTurnEstimate(m.com, msg.est, threshold, msg.send))
+	return nil
+}
+
+// sendEstimatedTurn sends a turn that passed the cost check.
+func (m *UI) sendEstimatedTurn(action dialog.ActionSendEstimatedTurn) tea.Cmd {
+	m.costApproved = true
+	defer func() { m.costApproved = false }()
+	return m.sendMessage(action.Prompt, action.Attachments...)
+}
+
+// restoreEstimatedTurn puts a turn the user chose not to send back in the
+// editor, unless something new was typed meanwhile.
+func (m *UI) restoreEstimatedTurn(action dialog.ActionCancelEstimatedTurn) tea.Cmd {
+	for _, att := range action.Attachments {
+		m.attachments.Update(att)
+	}
+	cmds := []tea.Cmd{util.ReportInfo("Turn not sent")}
+	if m.textarea.Value() == "" {
+		cmds = append(cmds, util.CmdHandler(openEditorMsg{Text: action.Prompt}))
+	}
+	return tea.Batch(cmds...)
+}
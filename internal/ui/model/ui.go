@@ -49,12 +49,14 @@ import (
 	"github.com/charmbracelet/crush/internal/ui/common"
 	"github.com/charmbracelet/crush/internal/ui/completions"
 	"github.com/charmbracelet/crush/internal/ui/dialog"
+	"github.com/charmbracelet/crush/internal/ui/filelink"
 	fimage "github.com/charmbracelet/crush/internal/ui/image"
 	"github.com/charmbracelet/crush/internal/ui/logo"
 	"github.com/charmbracelet/crush/internal/ui/notification"
 	"github.com/charmbracelet/crush/internal/ui/styles"
 	"github.com/charmbracelet/crush/internal/ui/util"
 	"github.com/charmbracelet/crush/internal/version"
+	"github.com/charmbracelet/crush/internal/voice"
 	"github.com/charmbracelet/crush/internal/workspace"
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/ultraviolet/layout"
@@ -177,6 +179,14 @@ type UI struct {
 	initialSessionID string
 	// continueLastSession is set to continue the most recent session on startup.
 	continueLastSession bool
+	// initialAttachment is the file crush was started with, if any.
+	initialAttachment *message.Attachment // XRUSH: crush <file>
+	// initialPrompt is the draft the editor starts with, if any.
+	initialPrompt string // XRUSH: crush new --template
+
+	// costApproved lets sendMessage skip the cost confirmation threshold
+	// for a turn that passed it.
+	costApproved bool // XRUSH: pre-send cost estimate
 
 	lastUserMessageTime int64
 
@@ -287,6 +297,13 @@ type UI struct {
 	lcmCompactingStart time.Time
 	lcmSpinner         spinner.Model
 
+	// XRUSH: push-to-talk voice input state
+	voiceState     voiceState
+	voiceRecording *voice.Recording
+
+	// XRUSH: last editor contents written to the draft file
+	savedDraft string
+
 	// mouse highlighting related state
 	lastClickTime time.Time
 
@@ -382,6 +399,12 @@ func New(com *common.Common, initialSessionID string, continueLast bool) *UI {
 
 	// Initialize compact mode from config
 	ui.forceCompactMode = com.Config().Options.TUI.CompactMode
+	ui.chat.SetShowUsage(com.Config().Options.TUI.ShowMessageUsage)
+	ui.chat.SetThinkingDisplay(com.Config().Options.Reasoning.DisplayMode())
+	// XRUSH: accessibility mode stops spinner animation.
+	anim.SetReducedMotion(com.Accessible())
+	// XRUSH: link file:line references in tool output to the editor.
+	chat.SetFileLinker(filelink.New(com.Config().Options.TUI.EditorLinks, com.Workspace.WorkingDir()))
 
 	// set onboarding state defaults
 	ui.onboarding.yesInitializeSelected = true
@@ -426,6 +449,16 @@ func (m *UI) Init() tea.Cmd {
 	if m.com.IsHyper() {
 		cmds = append(cmds, m.fetchHyperCredits())
 	}
+	// XRUSH: restore the prompt draft and start autosaving it.
+	cmds = append(cmds, m.restoreAfterCrash())
+	// XRUSH: attach the file crush was started with.
+	if cmd := m.attachInitialFile(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	// XRUSH: fill in the default prompt of the session's template.
+	if cmd := m.fillInitialPrompt(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -515,6 +548,16 @@ func selectNotificationBackend(caps common.Capabilities, cfg *config.Config) not
 	return notification.NoopBackend{}
 }
 
+// notificationOptions returns the notification policy from config, or nil
+// when none is configured.
+func (m *UI) notificationOptions() *config.NotificationOptions {
+	cfg := m.com.Config()
+	if cfg == nil || cfg.Options == nil {
+		return nil
+	}
+	return cfg.Options.Notifications
+}
+
 func (m *UI) updateNotificationBackend() {
 	cfg := m.com.Config()
 	m.notifyBackend = selectNotificationBackend(m.caps, cfg)
@@ -522,12 +565,16 @@ func (m *UI) updateNotificationBackend() {
 
 // shouldSendNotification returns true if notifications should be sent based on
 // current state. Focus reporting must be supported, window must not be
-// focused, and notifications must not be disabled in config.
+// focused, and notifications must be enabled in config.
 func (m *UI) shouldSendNotification() bool {
 	cfg := m.com.Config()
 	if cfg != nil && cfg.Options != nil && cfg.Options.NotificationStyle == "disabled" {
 		return false
 	}
+	// XRUSH: desktop notifications are opt-in.
+	if opts := m.notificationOptions(); opts == nil || !opts.Enabled {
+		return false
+	}
 	return m.caps.ReportFocusEvents && !m.notifyWindowFocused
 }
 
@@ -759,11 +806,13 @@ func (m *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cmd := m.openPermissionsDialog(msg.Payload); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
-		if cmd := m.sendNotification(notification.Notification{
-			Title:   "Crush is waiting...",
-			Message: fmt.Sprintf("Permission required to execute \"%s\"", msg.Payload.ToolName),
-		}); cmd != nil {
-			cmds = append(cmds, cmd)
+		if opts := m.notificationOptions(); opts == nil || !opts.SkipPermissionPrompts {
+			if cmd := m.sendNotification(notification.Notification{
+				Title:   "Crush is waiting...",
+				Message: fmt.Sprintf("Permission required to execute \"%s\"", msg.Payload.ToolName),
+			}); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
 		}
 	case pubsub.Event[permission.PermissionNotification]:
 		m.handlePermissionNotification(msg.Payload)
@@ -1300,6 +1349,10 @@ func (m *UI) updateSessionMessage(msg message.Message) tea.Cmd {
 			newInfoItem := chat.NewAssistantInfoItem(m.com.Styles, &msg, m.com.Config(), time.Unix(m.lastUserMessageTime, 0))
 			m.chat.AppendMessages(newInfoItem)
 		}
+		// XRUSH: translate finished replies when auto translation is on.
+		if cmd := m.autoTranslate(msg.ID); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 		if !m.agentProcessing && m.com.Workspace.AgentIsBusy() {
 			processingItem := chat.NewProcessingItem(m.com.Styles)
 			m.agentProcessing = true
@@ -1533,6 +1586,14 @@ func (m *UI) handleDialogMsg(msg tea.Msg) tea.Cmd {
 	case dialog.ActionRefreshRepoMap:
 		cmds = append(cmds, m.executeRepoMapRefresh(msg.SessionID))
 		m.dialog.CloseDialog(dialog.CommandsID)
+	case dialog.ActionToggleAutoDowngrade:
+		m.com.Workspace.AgentSetAutoDowngrade(msg.SessionID, msg.Enable)
+		if msg.Enable {
+			cmds = append(cmds, util.ReportInfo("Trivial turns will use the small model"))
+		} else {
+			cmds = append(cmds, util.ReportInfo("Auto model downgrade disabled for this session"))
+		}
+		m.dialog.CloseDialog(dialog.CommandsID)
 	case dialog.ActionToggleHelp:
 		m.status.ToggleHelp()
 		m.dialog.CloseDialog(dialog.CommandsID)
@@ -1908,7 +1969,8 @@ func (m *UI) handleSelectModel(msg dialog.ActionSelectModel) tea.Cmd {
 	if err := m.com.Workspace.UpdatePreferredModel(config.ScopeGlobal, msg.ModelType, msg.Model); err != nil {
 		cmds = append(cmds, util.ReportError(err))
 	} else {
-		if msg.ModelType == config.SelectedModelTypeLarge {
+		// XRUSH: accessibility mode keeps the high-contrast theme.
+		if msg.ModelType == config.SelectedModelTypeLarge && !m.com.Accessible() {
 			// Swap the theme live based on the newly selected large
 			// model's provider.
 			m.applyTheme(styles.ThemeForProvider(providerID))
@@ -2201,6 +2263,9 @@ func (m *UI) handleKeyPressMsg(msg tea.KeyPressMsg) tea.Cmd {
 					break
 				}
 				cmds = append(cmds, m.openEditor(m.textarea.Value()))
+			// XRUSH: push-to-talk voice input.
+			case key.Matches(msg, m.keyMap.Editor.Voice):
+				cmds = append(cmds, m.toggleVoice())
 			case key.Matches(msg, m.keyMap.Editor.Newline):
 				prevHeight := m.textarea.Height()
 				m.textarea.InsertRune('\n')
@@ -2315,6 +2380,17 @@ func (m *UI) handleKeyPressMsg(msg tea.KeyPressMsg) tea.Cmd {
 				if cmd := m.handleXrushKeyPress(msg); cmd != nil {
 					cmds = append(cmds, cmd)
 				}
+			// XRUSH: open the selected item in a tmux/iTerm2 pane.
+			case key.Matches(msg, m.keyMap.Chat.OpenPane):
+				cmds = append(cmds, m.openSelectedInPane())
+			// XRUSH: toggle per-message token and cost annotations.
+			case key.Matches(msg, m.keyMap.Chat.ToggleUsage):
+				m.chat.SetShowUsage(!m.chat.ShowUsage())
+			// XRUSH: translate the selected assistant message.
+			case key.Matches(msg, m.keyMap.Chat.Translate):
+				if cmd := m.toggleTranslation(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
 			case key.Matches(msg, m.keyMap.Chat.Up):
 				if cmd := m.chat.ScrollByAndAnimate(-1); cmd != nil {
 					cmds = append(cmds, cmd)
@@ -2616,6 +2692,9 @@ func (m *UI) ShortHelp() []key.Binding {
 			if _, _, ok := m.chat.SelectedMessageItem(); ok && m.com.Workspace.RewindService() != nil {
 				binds = append(binds, k.Chat.MessageOptions)
 			}
+			if m.canOpenPane() {
+				binds = append(binds, k.Chat.OpenPane)
+			}
 			if m.pillsExpanded && hasIncompleteTodos(m.session.Todos) && m.promptQueue > 0 {
 				binds = append(binds, k.Chat.PillLeft)
 			}
@@ -2701,6 +2780,9 @@ func (m *UI) FullHelp() [][]key.Binding {
 				k.Editor.MentionFile,
 				k.Editor.OpenEditor,
 			}
+			if m.voiceOptions() != nil {
+				editorBinds = append(editorBinds, k.Editor.Voice)
+			}
 			if m.currentModelSupportsImages() {
 				editorBinds = append(editorBinds, k.Editor.AddImage, k.Editor.PasteImage)
 			}
@@ -2733,6 +2815,7 @@ func (m *UI) FullHelp() [][]key.Binding {
 				[]key.Binding{
 					k.Chat.Copy,
 					k.Chat.ClearHighlight,
+					k.Chat.ToggleUsage,
 				},
 			)
 			if m.pillsExpanded && hasIncompleteTodos(m.session.Todos) && m.promptQueue > 0 {
@@ -2741,6 +2824,12 @@ func (m *UI) FullHelp() [][]key.Binding {
 			if _, _, ok := m.chat.SelectedMessageItem(); ok && m.com.Workspace.RewindService() != nil {
 				binds = append(binds, []key.Binding{k.Chat.MessageOptions})
 			}
+			if m.canOpenPane() {
+				binds = append(binds, []key.Binding{k.Chat.OpenPane})
+			}
+			if m.chat.SelectedIsAssistant() {
+				binds = append(binds, []key.Binding{k.Chat.FoldCode, k.Chat.RawView, k.Chat.Translate})
+			}
 		}
 	default:
 		if m.session == nil {
@@ -2759,6 +2848,9 @@ func (m *UI) FullHelp() [][]key.Binding {
 				k.Editor.MentionFile,
 				k.Editor.OpenEditor,
 			}
+			if m.voiceOptions() != nil {
+				editorBinds = append(editorBinds, k.Editor.Voice)
+			}
 			if m.currentModelSupportsImages() {
 				editorBinds = append(editorBinds, k.Editor.AddImage, k.Editor.PasteImage)
 			}
@@ -3462,6 +3554,11 @@ func (m *UI) sendMessage(content string, attachments ...message.Attachment) tea.
 	if !m.com.Workspace.AgentIsReady() {
 		return util.ReportError(fmt.Errorf("coder agent is not initialized"))
 	}
+	if cmd := m.holdForCostCheck(content, attachments); cmd != nil { // XRUSH: pre-send cost estimate
+		return cmd
+	}
+
+	m.clearDraft() // XRUSH: the draft is no longer unsent
 
 	var cmds []tea.Cmd
 	if !m.hasSession() {
@@ -3492,17 +3589,7 @@ func (m *UI) sendMessage(content string, attachments ...message.Attachment) tea.
 	sessionID := m.session.ID
 	cmds = append(cmds, func() tea.Msg {
 		err := m.com.Workspace.AgentRun(context.Background(), sessionID, content, attachments...)
-		if err != nil {
-			isCancelErr := errors.Is(err, context.Canceled)
-			if isCancelErr {
-				return nil
-			}
-			return util.InfoMsg{
-				Type: util.InfoTypeError,
-				Msg:  fmt.Sprintf("%v", err),
-			}
-		}
-		return nil
+		return agentRunResult(err, sessionID, content, attachments) // XRUSH: session takeover
 	})
 	return tea.Batch(cmds...)
 }
@@ -3765,10 +3852,13 @@ func (m *UI) handleAgentNotification(n notify.Notification) tea.Cmd {
 				return processingHideMsg{}
 			}))
 		}
-		cmds = append(cmds, m.sendNotification(notification.Notification{
-			Title:   "Crush is waiting...",
-			Message: fmt.Sprintf("Agent's turn completed in \"%s\"", n.SessionTitle),
-		}))
+		// XRUSH: only long-running turns are worth interrupting the user for.
+		if n.Duration >= m.notificationOptions().MinTurnDuration() {
+			cmds = append(cmds, m.sendNotification(notification.Notification{
+				Title:   "Crush is waiting...",
+				Message: fmt.Sprintf("Agent's turn completed in \"%s\" after %s", n.SessionTitle, n.Duration.Round(time.Second)),
+			}))
+		}
 		if m.com.IsHyper() {
 			cmds = append(cmds, m.fetchHyperCredits())
 		}
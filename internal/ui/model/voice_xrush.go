@@ -0,0 +1,127 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/ui/util"
+	"github.com/charmbracelet/crush/internal/voice"
+)
+
+// voiceState tracks the push-to-talk lifecycle.
+type voiceState int
+
+const (
+	voiceIdle voiceState = iota
+	voiceStarting
+	voiceRecording
+	voiceTranscribing
+)
+
+// voiceStartedMsg is sent once the recorder is running.
+type voiceStartedMsg struct {
+	rec *voice.Recording
+	err error
+}
+
+// voiceTranscribedMsg carries the transcript of a finished recording.
+type voiceTranscribedMsg struct {
+	text string
+	err  error
+}
+
+// voiceOptions returns the voice configuration, or nil when voice input is
+// disabled.
+func (m *UI) voiceOptions() *config.VoiceOptions {
+	opts := m.com.Config().Options.Voice
+	if opts == nil || !opts.Enabled {
+		return nil
+	}
+	return opts
+}
+
+// toggleVoice starts recording on the first push-to-talk press and stops
+// and transcribes on the second.
+func (m *UI) toggleVoice() tea.Cmd {
+	opts := m.voiceOptions()
+	if opts == nil {
+		return util.ReportWarn("Voice input is disabled; set options.voice.enabled to use it")
+	}
+	switch m.voiceState {
+	case voiceIdle:
+		m.voiceState = voiceStarting
+		return func() tea.Msg {
+			rec, err := voice.Start(opts)
+			return voiceStartedMsg{rec: rec, err: err}
+		}
+	case voiceRecording:
+		rec := m.voiceRecording
+		m.voiceRecording = nil
+		m.voiceState = voiceTranscribing
+		return tea.Batch(
+			util.ReportInfo("Transcribing…"),
+			func() tea.Msg {
+				path, err := rec.Stop()
+				if err != nil {
+					return voiceTranscribedMsg{err: err}
+				}
+				defer os.Remove(path)
+				text, err := voice.Transcribe(context.Background(), opts, path)
+				return voiceTranscribedMsg{text: text, err: err}
+			},
+		)
+	}
+	return nil
+}
+
+// handleVoiceStarted records the running recorder and tells the user how
+// to stop it.
+func (m *UI) handleVoiceStarted(msg voiceStartedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.voiceState = voiceIdle
+		return util.ReportError(msg.err)
+	}
+	m.voiceState = voiceRecording
+	m.voiceRecording = msg.rec
+	return util.CmdHandler(util.InfoMsg{
+		Type: util.InfoTypeInfo,
+		Msg:  "Recording… press " + m.keyMap.Editor.Voice.Help().Key + " to stop",
+		TTL:  voice.MaxDuration,
+	})
+}
+
+// handleVoiceTranscribed inserts the transcript at the editor cursor.
+func (m *UI) handleVoiceTranscribed(msg voiceTranscribedMsg) tea.Cmd {
+	m.voiceState = voiceIdle
+	if msg.err != nil {
+		if errors.Is(msg.err, voice.ErrNoAudio) {
+			return util.ReportWarn("No audio was recorded")
+		}
+		slog.Error("Voice transcription failed", "error", msg.err)
+		return util.ReportError(msg.err)
+	}
+	if msg.text == "" {
+		return util.ReportWarn("No speech detected")
+	}
+
+	prevHeight := m.textarea.Height()
+	text := msg.text
+	if v := m.textarea.Value(); v != "" && !isSpace(v[len(v)-1]) {
+		text = " " + text
+	}
+	m.textarea.InsertString(text)
+	return tea.Batch(
+		m.updateTextareaWithPrevHeight(msg, prevHeight),
+		util.CmdHandler(util.InfoMsg{Type: util.InfoTypeSuccess, Msg: "Transcript inserted", TTL: 2 * time.Second}),
+	)
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\t'
+}
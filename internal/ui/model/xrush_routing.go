@@ -16,7 +16,7 @@ import (
 
 // handleXrushRoutingUpdate handles fork-only message routing in the main Update
 // loop. This includes rewind results, compaction events, edit message results,
-// and delayed click handling.
+// voice input, and delayed click handling.
 func (m *UI) handleXrushRoutingUpdate(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case rewindResultMsg:
@@ -54,6 +54,33 @@ func (m *UI) handleXrushRoutingUpdate(msg tea.Msg) tea.Cmd {
 
 	case RepoMapRefreshResultMsg:
 		return m.handleRepoMapRefreshResult(msg)
+
+	case RepoMapOverrideResultMsg:
+		return m.handleRepoMapOverrideResult(msg)
+
+	case stagedEditsLoadedMsg:
+		return m.handleStagedEditsLoaded(msg)
+
+	case stagedEditsAppliedMsg:
+		return m.handleStagedEditsApplied(msg)
+
+	case voiceStartedMsg:
+		return m.handleVoiceStarted(msg)
+
+	case voiceTranscribedMsg:
+		return m.handleVoiceTranscribed(msg)
+
+	case translatedMsg:
+		return m.handleTranslated(msg)
+
+	case draftTickMsg:
+		return m.handleDraftTick()
+
+	case sessionLockedMsg:
+		return m.handleSessionLocked(msg)
+
+	case turnEstimatedMsg:
+		return m.handleTurnEstimated(msg)
 	}
 
 	return nil
@@ -85,6 +112,73 @@ func (m *Chat) SelectedMessageItem() (id string, seq int, ok bool) {
 	return "", 0, false
 }
 
+// XRUSH: SelectedIsAssistant reports whether the selected item is an
+// assistant message.
+func (m *Chat) SelectedIsAssistant() bool {
+	_, ok := m.list.SelectedItem().(*chat.AssistantMessageItem)
+	return ok
+}
+
+// XRUSH: SelectedAssistantItem returns the selected item if it is an
+// assistant message, or nil otherwise.
+func (m *Chat) SelectedAssistantItem() *chat.AssistantMessageItem {
+	if item, ok := m.list.SelectedItem().(*chat.AssistantMessageItem); ok {
+		return item
+	}
+	return nil
+}
+
+// XRUSH: SetShowUsage shows or hides the token and cost annotations on
+// assistant messages, including those already in the chat.
+func (m *Chat) SetShowUsage(show bool) {
+	m.showUsage = show
+	for i := range m.list.Len() {
+		if item, ok := m.list.ItemAt(i).(*chat.AssistantMessageItem); ok {
+			item.SetShowUsage(show)
+		}
+	}
+}
+
+// XRUSH: ShowUsage reports whether usage annotations are shown.
+func (m *Chat) ShowUsage() bool {
+	return m.showUsage
+}
+
+// applyShowUsage propagates the usage annotation setting to new items.
+func (m *Chat) applyShowUsage(msgs ...chat.MessageItem) {
+	if !m.showUsage {
+		return
+	}
+	for _, msg := range msgs {
+		if item, ok := msg.(*chat.AssistantMessageItem); ok {
+			item.SetShowUsage(true)
+		}
+	}
+}
+
+// XRUSH: SetThinkingDisplay sets how thinking blocks of assistant
+// messages are shown: collapsed, expanded or hidden.
+func (m *Chat) SetThinkingDisplay(mode string) {
+	m.thinkingDisplay = mode
+	for i := range m.list.Len() {
+		if item, ok := m.list.ItemAt(i).(*chat.AssistantMessageItem); ok {
+			item.SetThinkingDisplay(mode)
+		}
+	}
+}
+
+// applyThinkingDisplay propagates the reasoning display mode to new items.
+func (m *Chat) applyThinkingDisplay(msgs ...chat.MessageItem) {
+	if m.thinkingDisplay == "" {
+		return
+	}
+	for _, msg := range msgs {
+		if item, ok := msg.(*chat.AssistantMessageItem); ok {
+			item.SetThinkingDisplay(m.thinkingDisplay)
+		}
+	}
+}
+
 // XRUSH: dispatchForkMessageOptions handles fork-specific message options
 // dispatch on single-click of user messages.
 func (m *Chat) dispatchXrushMessageOptions(selectedItem list.Item) (bool, tea.Cmd) {
@@ -103,7 +197,11 @@ func (m *Chat) dispatchXrushMessageOptions(selectedItem list.Item) (bool, tea.Cm
 
 func isXrushDialogAction(action dialog.Action) bool {
 	switch action.(type) {
-	case dialog.ActionOpenMessageOptions, dialog.ActionRewind, dialog.ActionFork, dialog.ActionEditMessage:
+	case dialog.ActionOpenMessageOptions, dialog.ActionRewind, dialog.ActionFork, dialog.ActionEditMessage,
+		dialog.ActionReviewStagedEdits, dialog.ActionApplyStagedEdits,
+		dialog.ActionRepoMapOverride, dialog.ActionClearRepoMapOverrides,
+		dialog.ActionTakeOverSession,
+		dialog.ActionEstimateTurn, dialog.ActionSendEstimatedTurn, dialog.ActionCancelEstimatedTurn:
 		return true
 	}
 	return false
@@ -119,7 +217,8 @@ func initXrushChatCallbacks(ch *Chat, com *common.Common) {
 }
 
 // handleXrushDialogMsg handles fork-only dialog action routing. This includes
-// message options, rewind, fork, and edit message actions.
+// message options, rewind, fork, edit message, staged edit review and repo
+// map override, session takeover and turn estimate actions.
 func (m *UI) handleXrushDialogMsg(action tea.Msg) tea.Cmd {
 	switch msg := action.(type) {
 	case dialog.ActionOpenMessageOptions:
@@ -165,6 +264,42 @@ func (m *UI) handleXrushDialogMsg(action tea.Msg) tea.Cmd {
 	case dialog.ActionEditMessage:
 		m.dialog.CloseFrontDialog()
 		return m.executeEditMessage(msg.SessionID, msg.Seq, msg.MessageID)
+
+	case dialog.ActionReviewStagedEdits:
+		m.dialog.CloseDialog(dialog.CommandsID)
+		return m.loadStagedEdits(msg.SessionID)
+
+	case dialog.ActionApplyStagedEdits:
+		m.dialog.CloseDialog(dialog.ReviewEditsID)
+		return m.applyStagedEdits(msg.SessionID, msg.Changes)
+
+	case dialog.ActionRepoMapOverride:
+		m.dialog.CloseFrontDialog()
+		if msg.Target == "" {
+			m.dialog.OpenDialog(dialog.NewRepoMapOverrideArguments(m.com, msg))
+			return nil
+		}
+		return m.executeRepoMapOverride(msg)
+
+	case dialog.ActionClearRepoMapOverrides:
+		m.dialog.CloseDialog(dialog.CommandsID)
+		return m.executeRepoMapClearOverrides(msg.SessionID)
+
+	case dialog.ActionTakeOverSession:
+		m.dialog.CloseDialog(dialog.SessionTakeoverID)
+		return m.takeOverSession(msg)
+
+	case dialog.ActionEstimateTurn:
+		m.dialog.CloseDialog(dialog.CommandsID)
+		return m.estimateDraft()
+
+	case dialog.ActionSendEstimatedTurn:
+		m.dialog.CloseDialog(dialog.TurnEstimateID)
+		return m.sendEstimatedTurn(msg)
+
+	case dialog.ActionCancelEstimatedTurn:
+		m.dialog.CloseDialog(dialog.TurnEstimateID)
+		return m.restoreEstimatedTurn(msg)
 	}
 
 	return nil
@@ -532,6 +532,11 @@ type Styles struct {
 		HelpText           lipgloss.Style // Help action text style
 		Area               lipgloss.Style // Pills area container
 	}
+
+	// Accessible marks the high-contrast accessibility theme. Components
+	// add textual state cues (such as bracketed selections) when set, so
+	// state is not conveyed by color alone.
+	Accessible bool
 }
 
 // ChromaTheme converts the current markdown chroma styles to a chroma
@@ -87,3 +87,43 @@ func HypercrushObsidiana() Styles {
 		successMostSubtle: charmtone.Guac,
 	})
 }
+
+// HighContrast returns the accessibility theme: bright foregrounds on the
+// darkest background, with subtle text raised to full legibility.
+func HighContrast() Styles {
+	s := quickStyle(quickStyleOpts{
+		primary:   charmtone.Malibu,
+		secondary: charmtone.Butter,
+		accent:    charmtone.Julep,
+		keyword:   charmtone.Citron,
+
+		fgBase:       charmtone.Butter,
+		fgMoreSubtle: charmtone.Salt,
+		fgSubtle:     charmtone.Salt,
+		fgMostSubtle: charmtone.Ash,
+
+		onPrimary: charmtone.Pepper,
+
+		bgBase:         charmtone.Pepper,
+		bgLeastVisible: charmtone.Pepper,
+		bgLessVisible:  charmtone.Iron,
+		bgMostVisible:  charmtone.Oyster,
+
+		separator: charmtone.Ash,
+
+		destructive:       charmtone.Salmon,
+		error:             charmtone.Salmon,
+		warningSubtle:     charmtone.Zest,
+		warning:           charmtone.Zest,
+		denied:            charmtone.Salmon,
+		busy:              charmtone.Citron,
+		info:              charmtone.Malibu,
+		infoMoreSubtle:    charmtone.Malibu,
+		infoMostSubtle:    charmtone.Malibu,
+		success:           charmtone.Julep,
+		successMoreSubtle: charmtone.Julep,
+		successMostSubtle: charmtone.Julep,
+	})
+	s.Accessible = true
+	return s
+}
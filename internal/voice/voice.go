@@ -0,0 +1,211 @@
+// Package voice implements push-to-talk voice input. Audio is captured by
+// an external recorder (sox or arecord by default) and transcribed by the
+// whisper.cpp command line tool, so no audio libraries are linked into
+// crush. Both commands can be replaced through configuration.
+package voice
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"mvdan.cc/sh/v3/shell"
+)
+
+// MaxDuration caps a single recording so a forgotten push-to-talk session
+// does not keep the microphone open indefinitely.
+const MaxDuration = 5 * time.Minute
+
+// stopTimeout is how long a recorder is given to finalize its output after
+// being interrupted before it is killed.
+const stopTimeout = 3 * time.Second
+
+// ErrNoAudio is returned when a recording produced no audio data.
+var ErrNoAudio = errors.New("no audio was recorded")
+
+// Recording is an in-progress microphone capture.
+type Recording struct {
+	path   string
+	cancel context.CancelFunc
+	done   chan error
+	stderr *bytes.Buffer
+}
+
+// Start begins recording from the default microphone into a temporary WAV
+// file. The recording stops on [Recording.Stop] or after [MaxDuration].
+func Start(opts *config.VoiceOptions) (*Recording, error) {
+	tmp, err := os.CreateTemp("", "crush-voice-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("creating recording file: %w", err)
+	}
+	path := tmp.Name()
+	_ = tmp.Close()
+
+	tmpl, err := recordCommand(opts)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	args, err := expand(tmpl, map[string]string{"output": path})
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("parsing record command: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), MaxDuration)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	// Recorders finalize the WAV header when interrupted; a hard kill would
+	// leave a truncated file behind.
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = stopTimeout
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		cancel()
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("starting recorder %q: %w", args[0], err)
+	}
+
+	r := &Recording{path: path, cancel: cancel, done: make(chan error, 1), stderr: stderr}
+	go func() { r.done <- cmd.Wait() }()
+	return r, nil
+}
+
+// Stop ends the recording and returns the path of the captured audio. The
+// caller owns the file and should remove it once it has been transcribed.
+func (r *Recording) Stop() (string, error) {
+	r.cancel()
+	waitErr := <-r.done
+
+	// Recorders usually exit with a non-zero status when interrupted, so
+	// the exit status only matters when nothing was captured.
+	info, err := os.Stat(r.path)
+	if err != nil || info.Size() == 0 {
+		_ = os.Remove(r.path)
+		if msg := strings.TrimSpace(r.stderr.String()); waitErr != nil && msg != "" {
+			return "", fmt.Errorf("%w: %s", ErrNoAudio, msg)
+		}
+		return "", ErrNoAudio
+	}
+	return r.path, nil
+}
+
+// Transcribe runs the transcribe command on the audio file at path and
+// returns the cleaned-up transcript.
+func Transcribe(ctx context.Context, opts *config.VoiceOptions, path string) (string, error) {
+	tmpl, err := transcribeCommand(opts)
+	if err != nil {
+		return "", err
+	}
+	args, err := expand(tmpl, map[string]string{
+		"input":    path,
+		"model":    opts.Model,
+		"language": cmp.Or(opts.Language, "auto"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("parsing transcribe command: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("transcribing audio: %w: %s", err, lastLine(msg))
+		}
+		return "", fmt.Errorf("transcribing audio: %w", err)
+	}
+	return CleanTranscript(stdout.String()), nil
+}
+
+var (
+	// timestampRe matches whisper.cpp segment timestamps such as
+	// "[00:00:00.000 --> 00:00:02.500]".
+	timestampRe = regexp.MustCompile(`\[\d{2}:\d{2}:\d{2}[.,]\d{3} --> \d{2}:\d{2}:\d{2}[.,]\d{3}\]`)
+	// annotationRe matches non-speech annotations such as "[BLANK_AUDIO]"
+	// or "(music)".
+	annotationRe = regexp.MustCompile(`\[[A-Z_ ]+\]|\([a-z ]+\)`)
+)
+
+// CleanTranscript strips timestamps and non-speech annotations from
+// transcriber output and joins the remaining text into a single line.
+func CleanTranscript(out string) string {
+	out = timestampRe.ReplaceAllString(out, " ")
+	out = annotationRe.ReplaceAllString(out, " ")
+	return strings.Join(strings.Fields(out), " ")
+}
+
+// recordCommand returns the configured record command or the first
+// available default recorder.
+func recordCommand(opts *config.VoiceOptions) (string, error) {
+	if opts.RecordCommand != "" {
+		return opts.RecordCommand, nil
+	}
+	for _, c := range []struct{ bin, cmd string }{
+		{"sox", "sox -q -d -r 16000 -c 1 -b 16 {output}"},
+		{"arecord", "arecord -q -f S16_LE -r 16000 -c 1 {output}"},
+	} {
+		if _, err := exec.LookPath(c.bin); err == nil {
+			return c.cmd, nil
+		}
+	}
+	return "", errors.New("no audio recorder found: install sox or arecord, or set options.voice.record_command")
+}
+
+// transcribeCommand returns the configured transcribe command or a
+// whisper.cpp invocation using the configured model.
+func transcribeCommand(opts *config.VoiceOptions) (string, error) {
+	if opts.TranscribeCommand != "" {
+		return opts.TranscribeCommand, nil
+	}
+	if opts.Model == "" {
+		return "", errors.New("options.voice.model must point to a whisper.cpp model when no transcribe_command is set")
+	}
+	for _, bin := range []string{"whisper-cli", "whisper-cpp"} {
+		if _, err := exec.LookPath(bin); err == nil {
+			return bin + " -m {model} -l {language} -f {input} -nt -np", nil
+		}
+	}
+	return "", errors.New("whisper.cpp not found: install whisper-cli or set options.voice.transcribe_command")
+}
+
+// expand splits a command template into arguments, expanding environment
+// variables, and substitutes the {name} placeholders. Substitution happens after splitting so paths with
+// spaces stay a single argument.
+func expand(tmpl string, vars map[string]string) ([]string, error) {
+	fields, err := shell.Fields(tmpl, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("empty command")
+	}
+	for i, f := range fields {
+		for k, v := range vars {
+			f = strings.ReplaceAll(f, "{"+k+"}", v)
+		}
+		fields[i] = f
+	}
+	return fields, nil
+}
+
+func lastLine(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
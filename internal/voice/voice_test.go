@@ -0,0 +1,86 @@
+package voice
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanTranscript(t *testing.T) {
+	t.Parallel()
+
+	out := "\n[00:00:00.000 --> 00:00:02.000]   Fix the failing\n" +
+		"[00:00:02.000 --> 00:00:04.000]  test in the parser. [BLANK_AUDIO]\n(music)\n"
+	require.Equal(t, "Fix the failing test in the parser.", CleanTranscript(out))
+	require.Empty(t, CleanTranscript(" [BLANK_AUDIO] \n"))
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	args, err := expand(`whisper-cli -m {model} -f "{input}" -nt`, map[string]string{
+		"model": "/models/base.bin",
+		"input": "/tmp/with space.wav",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"whisper-cli", "-m", "/models/base.bin", "-f", "/tmp/with space.wav", "-nt"}, args)
+
+	_, err = expand("  ", nil)
+	require.Error(t, err)
+}
+
+func TestTranscribeCommandRequiresModel(t *testing.T) {
+	t.Parallel()
+
+	_, err := transcribeCommand(&config.VoiceOptions{})
+	require.ErrorContains(t, err, "options.voice.model")
+
+	cmd, err := transcribeCommand(&config.VoiceOptions{TranscribeCommand: "my-stt {input}"})
+	require.NoError(t, err)
+	require.Equal(t, "my-stt {input}", cmd)
+}
+
+func TestRecordAndTranscribe(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	opts := &config.VoiceOptions{
+		RecordCommand:     `sh -c 'printf RIFF > "{output}"; exec sleep 30'`,
+		TranscribeCommand: `sh -c 'cat "{input}"; echo " [BLANK_AUDIO] hello"'`,
+	}
+	rec, err := Start(opts)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		info, err := os.Stat(rec.path)
+		return err == nil && info.Size() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	path, err := rec.Stop()
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(path) })
+
+	text, err := Transcribe(t.Context(), opts, path)
+	require.NoError(t, err)
+	require.Equal(t, "RIFF hello", text)
+}
+
+func TestStopWithoutAudio(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	rec, err := Start(&config.VoiceOptions{RecordCommand: "sleep 30"})
+	require.NoError(t, err)
+
+	_, err = rec.Stop()
+	require.ErrorIs(t, err, ErrNoAudio)
+	_, statErr := os.Stat(rec.path)
+	require.True(t, os.IsNotExist(statErr))
+}
@@ -1,17 +1,57 @@
 package workspace
 
 import (
+	"context"
 	"log/slog"
 
+	"github.com/charmbracelet/crush/internal/agent"
 	"github.com/charmbracelet/crush/internal/extensions"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/repomap"
 	"github.com/charmbracelet/crush/internal/rewind"
 	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/staging"
+	"github.com/charmbracelet/crush/internal/translate"
 )
 
 func (w *AppWorkspace) RewindService() rewind.Service {
 	return w.app.RewindService
 }
 
+func (w *AppWorkspace) StagingService() staging.Service {
+	return w.app.Staging
+}
+
+func (w *AppWorkspace) TranslateText(ctx context.Context, text string) (string, error) {
+	var language string
+	if opts := w.store.Config().Options; opts != nil && opts.Translation != nil {
+		language = opts.Translation.Language
+	}
+	return translate.Markdown(ctx, w.app.Completer, language, text)
+}
+
+func (w *AppWorkspace) RecoveredSessions() []string {
+	return w.app.RecoveredSessions
+}
+
+func (w *AppWorkspace) RepoMapSetOverride(ctx context.Context, sessionID string, kind repomap.OverrideKind, target string) error {
+	o, err := repomap.ParseOverride(kind, target)
+	if err != nil {
+		return err
+	}
+	if err := extensions.TheRepomapExtension.SetOverride(ctx, sessionID, o); err != nil {
+		return err
+	}
+	return w.RepoMapRefresh(ctx, sessionID)
+}
+
+func (w *AppWorkspace) RepoMapClearOverrides(ctx context.Context, sessionID string) error {
+	if err := extensions.TheRepomapExtension.ClearOverrides(ctx, sessionID, ""); err != nil {
+		return err
+	}
+	return w.RepoMapRefresh(ctx, sessionID)
+}
+
 func (w *AppWorkspace) SetOperationalMemoryEnabled(enabled bool) error {
 	mgr := extensions.TheLCMExtension.Manager()
 	if mgr == nil {
@@ -29,3 +69,31 @@ func (w *AppWorkspace) SetOperationalMemoryEnabled(enabled bool) error {
 	}
 	return nil
 }
+
+func (w *AppWorkspace) AgentAutoDowngradeEnabled(sessionID string) bool {
+	if w.app.AgentCoordinator == nil {
+		return false
+	}
+	return w.app.AgentCoordinator.AutoDowngradeEnabled(sessionID)
+}
+
+func (w *AppWorkspace) AgentSetAutoDowngrade(sessionID string, enabled bool) {
+	if w.app.AgentCoordinator != nil {
+		w.app.AgentCoordinator.SetAutoDowngrade(sessionID, enabled)
+	}
+}
+
+func (w *AppWorkspace) AgentEstimateTurn(ctx context.Context, sessionID, prompt string, attachments ...message.Attachment) (agent.TurnEstimate, error) {
+	if w.app.AgentCoordinator == nil {
+		return agent.TurnEstimate{}, agent.ErrEstimateUnavailable
+	}
+	est, err := w.app.AgentCoordinator.EstimateTurn(ctx, sessionID, prompt, attachments...)
+	if err != nil {
+		return agent.TurnEstimate{}, err
+	}
+	// The map is injected into the first step of every turn.
+	if _, tokens := extensions.TheRepomapExtension.LoadCachedMap(sessionID); tokens > 0 {
+		est.Add(agent.EstimateRepoMap, tokens)
+	}
+	return est, nil
+}
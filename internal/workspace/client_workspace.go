@@ -15,6 +15,7 @@ import (
 	"github.com/charmbracelet/crush/internal/client"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/history"
+	"github.com/charmbracelet/crush/internal/lcm"
 	"github.com/charmbracelet/crush/internal/log"
 	"github.com/charmbracelet/crush/internal/lsp"
 	"github.com/charmbracelet/crush/internal/message"
@@ -22,6 +23,7 @@ import (
 	"github.com/charmbracelet/crush/internal/permission"
 	"github.com/charmbracelet/crush/internal/proto"
 	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/repomap"
 	"github.com/charmbracelet/crush/internal/session"
 	"github.com/charmbracelet/crush/internal/skills"
 	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
@@ -715,6 +717,43 @@ func (w *ClientWorkspace) translateEvent(ev any) tea.Msg {
 				SessionID:    e.Payload.SessionID,
 				SessionTitle: e.Payload.SessionTitle,
 				Type:         notify.Type(e.Payload.Type),
+				Duration:     time.Duration(e.Payload.DurationMs) * time.Millisecond,
+			},
+		}
+	case pubsub.Event[proto.ToolEvent]:
+		t := notify.TypeToolStarted
+		if e.Payload.Type == proto.ToolEventFinished {
+			t = notify.TypeToolFinished
+		}
+		return pubsub.Event[notify.Notification]{
+			Type: e.Type,
+			Payload: notify.Notification{
+				SessionID:  e.Payload.SessionID,
+				Type:       t,
+				ToolCallID: e.Payload.ToolCallID,
+				ToolName:   e.Payload.ToolName,
+				IsError:    e.Payload.IsError,
+			},
+		}
+	case pubsub.Event[proto.LCMEvent]:
+		return pubsub.Event[lcm.CompactionEvent]{
+			Type:    e.Type,
+			Payload: protoToCompactionEvent(e.Payload),
+		}
+	case pubsub.Event[proto.RepoMapEvent]:
+		t := repomap.RefreshCompleted
+		if e.Payload.Type == proto.RepoMapRefreshFailed {
+			t = repomap.RefreshFailed
+		}
+		return pubsub.Event[repomap.RefreshEvent]{
+			Type: e.Type,
+			Payload: repomap.RefreshEvent{
+				Type:      t,
+				SessionID: e.Payload.SessionID,
+				Tokens:    e.Payload.Tokens,
+				Forced:    e.Payload.Forced,
+				Duration:  time.Duration(e.Payload.DurationMs) * time.Millisecond,
+				Error:     e.Payload.Error,
 			},
 		}
 	case pubsub.Event[proto.SkillsEvent]:
@@ -732,6 +771,26 @@ func (w *ClientWorkspace) translateEvent(ev any) tea.Msg {
 	}
 }
 
+func protoToCompactionEvent(e proto.LCMEvent) lcm.CompactionEvent {
+	var t lcm.CompactionEventType
+	switch e.Type {
+	case proto.LCMCompactionStarted:
+		t = lcm.CompactionStarted
+	case proto.LCMCompactionCompleted:
+		t = lcm.CompactionCompleted
+	default:
+		t = lcm.CompactionFailed
+	}
+	return lcm.CompactionEvent{
+		Type:      t,
+		SessionID: e.SessionID,
+		Blocking:  e.Blocking,
+		Rounds:    e.Rounds,
+		Success:   e.Success,
+		Error:     e.Error,
+	}
+}
+
 func protoToMCPEventType(t proto.MCPEventType) mcp.EventType {
 	switch t {
 	case proto.MCPEventStateChanged:
@@ -818,6 +877,7 @@ func protoToMessage(m proto.Message) message.Message {
 				Signature:  v.Signature,
 				StartedAt:  v.StartedAt,
 				FinishedAt: v.FinishedAt,
+				Tokens:     v.Tokens,
 			})
 		case proto.ToolCall:
 			msg.Parts = append(msg.Parts, message.ToolCall{
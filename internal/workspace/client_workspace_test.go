@@ -7,7 +7,9 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/crush/internal/agent/notify"
 	"github.com/charmbracelet/crush/internal/client"
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/permission"
@@ -156,6 +158,31 @@ func TestProtoToSkillStates(t *testing.T) {
 	require.EqualError(t, got[1].Err, "bad frontmatter")
 }
 
+// TestTranslateEvent_AgentDuration verifies that the turn duration of an
+// agent event survives the trip over the wire, so minimum turn duration
+// notification settings work in client/server mode.
+func TestTranslateEvent_AgentDuration(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(pubsub.Event[proto.AgentEvent]{
+		Type: pubsub.CreatedEvent,
+		Payload: proto.AgentEvent{
+			Type:       proto.AgentEventType(notify.TypeAgentFinished),
+			SessionID:  "s1",
+			DurationMs: 90_000,
+		},
+	})
+	require.NoError(t, err)
+	var ev pubsub.Event[proto.AgentEvent]
+	require.NoError(t, json.Unmarshal(data, &ev))
+
+	w := NewClientWorkspace(nil, proto.Workspace{})
+	out, ok := w.translateEvent(ev).(pubsub.Event[notify.Notification])
+	require.True(t, ok)
+	require.Equal(t, notify.TypeAgentFinished, out.Payload.Type)
+	require.Equal(t, 90*time.Second, out.Payload.Duration)
+}
+
 // TestTranslateEvent_Skills verifies that an incoming proto.SkillsEvent
 // is converted into pubsub.Event[skills.Event] and that the
 // client-process skill cache is updated as a side effect, so callers
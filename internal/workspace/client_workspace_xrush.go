@@ -1,11 +1,50 @@
 package workspace
 
-import "github.com/charmbracelet/crush/internal/rewind" // XRUSH: rewind service
+import (
+	"context"
+
+	"github.com/charmbracelet/crush/internal/agent"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/repomap"
+	"github.com/charmbracelet/crush/internal/rewind" // XRUSH: rewind service
+	"github.com/charmbracelet/crush/internal/staging"
+	"github.com/charmbracelet/crush/internal/translate"
+)
 
 func (w *ClientWorkspace) RewindService() rewind.Service {
 	return nil
 }
 
+func (w *ClientWorkspace) StagingService() staging.Service {
+	return nil
+}
+
+func (w *ClientWorkspace) TranslateText(_ context.Context, _ string) (string, error) {
+	return "", translate.ErrUnavailable
+}
+
+func (w *ClientWorkspace) RecoveredSessions() []string {
+	return nil
+}
+
+func (w *ClientWorkspace) RepoMapSetOverride(_ context.Context, _ string, _ repomap.OverrideKind, _ string) error {
+	return repomap.ErrUnavailable
+}
+
+func (w *ClientWorkspace) RepoMapClearOverrides(_ context.Context, _ string) error {
+	return repomap.ErrUnavailable
+}
+
 func (w *ClientWorkspace) SetOperationalMemoryEnabled(_ bool) error {
 	return nil
 }
+
+func (w *ClientWorkspace) AgentAutoDowngradeEnabled(_ string) bool {
+	return false
+}
+
+func (w *ClientWorkspace) AgentSetAutoDowngrade(_ string, _ bool) {}
+
+func (w *ClientWorkspace) AgentEstimateTurn(_ context.Context, _, _ string, _ ...message.Attachment) (agent.TurnEstimate, error) {
+	return agent.TurnEstimate{}, agent.ErrEstimateUnavailable
+}
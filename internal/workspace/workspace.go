@@ -10,6 +10,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/crush/internal/agent"
 	mcptools "github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/history"
@@ -17,9 +18,11 @@ import (
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/oauth"
 	"github.com/charmbracelet/crush/internal/permission"
-	"github.com/charmbracelet/crush/internal/rewind" // XRUSH: rewind service
+	"github.com/charmbracelet/crush/internal/repomap" // XRUSH: repo map overrides
+	"github.com/charmbracelet/crush/internal/rewind"  // XRUSH: rewind service
 	"github.com/charmbracelet/crush/internal/session"
 	"github.com/charmbracelet/crush/internal/skills"
+	"github.com/charmbracelet/crush/internal/staging"
 )
 
 // LSPClientInfo holds information about an LSP client's state. This is
@@ -166,11 +169,45 @@ type Workspace interface {
 	// XRUSH: repomap command palette bridge
 	RepoMapRefresh(ctx context.Context, sessionID string) error
 
+	// RepoMapSetOverride pins or blacklists a repo-map path, glob or symbol
+	// for the session; RepoMapClearOverrides drops all of them.
+	// XRUSH: repo map overrides
+	RepoMapSetOverride(ctx context.Context, sessionID string, kind repomap.OverrideKind, target string) error
+	RepoMapClearOverrides(ctx context.Context, sessionID string) error
+
+	// StagingService returns the staging area holding edits awaiting
+	// per-hunk review, or nil if review is not available.
+	// XRUSH: staged edit review
+	StagingService() staging.Service
+
 	// SetOperationalMemoryEnabled enables or disables the LCM operational
 	// memory store at runtime. When enabling for the first time, it creates
 	// the OperationalMemory store and wires it into the LCM manager.
 	SetOperationalMemoryEnabled(enabled bool) error
 
+	// TranslateText translates assistant markdown into the configured
+	// translation language, leaving code blocks untouched.
+	// XRUSH: conversation translation
+	TranslateText(ctx context.Context, text string) (string, error)
+
+	// RecoveredSessions returns the sessions whose interrupted responses
+	// were recovered at startup.
+	// XRUSH: crash recovery
+	RecoveredSessions() []string
+
+	// AgentAutoDowngradeEnabled reports whether trivial turns of the
+	// session are routed to the small model; AgentSetAutoDowngrade
+	// overrides it for the session.
+	// XRUSH: automatic model downgrade
+	AgentAutoDowngradeEnabled(sessionID string) bool
+	AgentSetAutoDowngrade(sessionID string, enabled bool)
+
+	// AgentEstimateTurn returns the input tokens by component, repository
+	// map included, and the projected cost of sending prompt to the
+	// session, without sending it.
+	// XRUSH: pre-send cost estimate
+	AgentEstimateTurn(ctx context.Context, sessionID, prompt string, attachments ...message.Attachment) (agent.TurnEstimate, error)
+
 	// Events
 	Subscribe(program *tea.Program)
 	Shutdown()